@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/bootstrap"
+	"pixelpunk/internal/services/backup"
+	vectorService "pixelpunk/internal/services/vector"
+	"pixelpunk/pkg/cache"
+)
+
+// runCommand 执行命令行子命令，返回进程退出码
+func runCommand(name string, args []string) int {
+	switch name {
+	case "vector-reindex":
+		return runVectorReindex(args)
+	case "vector-export":
+		return runVectorExport(args)
+	case "vector-import":
+		return runVectorImport(args)
+	case "backup":
+		return runBackup(args)
+	case "backup-restore":
+		return runBackupRestore(args)
+	case "export":
+		return runExport(args)
+	case "import":
+		return runImport(args)
+	case "help", "-h", "--help":
+		printUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", name)
+		printUsage()
+		return 2
+	}
+}
+
+func printUsage() {
+	fmt.Println("用法: pixelpunk [命令] [参数]")
+	fmt.Println()
+	fmt.Println("不带命令时启动HTTP服务。可用命令:")
+	fmt.Println("  vector-reindex   删除并重建向量集合，重新生成全部向量（切换向量模型/提供者后使用），需先停止所有服务实例")
+	fmt.Println("    -batch int     每批向量化数量 (默认 20)")
+	fmt.Println("    -interval int  批次间隔毫秒，用于限速 (默认 500)")
+	fmt.Println("  vector-export    导出全部向量到文件（gzip 压缩的 JSON Lines）")
+	fmt.Println("    -o string      输出文件路径 (默认 vectors.jsonl.gz)")
+	fmt.Println("  vector-import    从导出文件恢复向量，无需重新向量化")
+	fmt.Println("    -i string      导出文件路径")
+	fmt.Println("  backup           立即备份数据库与配置文件，默认上传到备份设置中的目标渠道")
+	fmt.Println("    -o string      写入本地文件而不上传")
+	fmt.Println("    -storage       同时打包本地存储文件 (默认使用备份设置)")
+	fmt.Println("  backup-restore   从备份恢复数据库，会覆盖当前数据，建议先停止服务")
+	fmt.Println("    -i string      本地备份文件路径")
+	fmt.Println("    -key string    目标渠道中的备份文件，与 -i 二选一")
+	fmt.Println("    -storage       同时恢复本地存储文件")
+	fmt.Println("    -config string 将备份中的配置文件写入该路径，核对后再替换现有配置")
+	fmt.Println("  export           导出整个实例（数据库、配置、可选本地文件），用于迁移到新服务器或其他数据库类型")
+	fmt.Println("    -o string      输出文件路径 (默认 pixelpunk-export.tar.gz)")
+	fmt.Println("    -storage       同时打包本地存储文件")
+	fmt.Println("  import           将导出文件导入当前实例，覆盖当前数据")
+	fmt.Println("    -i string      导出文件路径")
+	fmt.Println("    -list          只列出导出文件与当前实例的存储渠道，不导入")
+	fmt.Println("    -map old=new   将导出文件中的渠道ID映射为当前实例的渠道ID，可重复指定")
+	fmt.Println("    -storage       同时恢复本地存储文件")
+	fmt.Println("    -config string 将导出文件中的配置写入该路径")
+}
+
+// initCommandApp 初始化应用依赖（数据库、配置、向量引擎等），不启动HTTP服务
+func initCommandApp() bool {
+	app := bootstrap.NewApp(Version)
+	if err := app.Initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "应用初始化失败: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// initVectorCommand 只初始化数据库、缓存与向量引擎，不启动定时任务与队列消费
+func initVectorCommand() bool {
+	if err := bootstrap.InitDatabaseOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		return false
+	}
+	if err := bootstrap.InitVectorEngineOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return false
+	}
+	return true
+}
+
+// runVectorExport 导出全部向量到文件
+func runVectorExport(args []string) int {
+	fs := flag.NewFlagSet("vector-export", flag.ContinueOnError)
+	output := fs.String("o", "vectors.jsonl.gz", "输出文件路径")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !initVectorCommand() {
+		return 1
+	}
+	defer bootstrap.CloseVectorEngineOnly()
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	count, err := vectorService.ExportVectors(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "向量导出失败: %v\n", err)
+		return 1
+	}
+	fmt.Printf("向量导出完成: %d 条 -> %s\n", count, *output)
+	return 0
+}
+
+// runVectorImport 从导出文件恢复向量
+func runVectorImport(args []string) int {
+	fs := flag.NewFlagSet("vector-import", flag.ContinueOnError)
+	input := fs.String("i", "", "导出文件路径")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "请通过 -i 指定导出文件路径")
+		return 2
+	}
+	if !initVectorCommand() {
+		return 1
+	}
+	defer bootstrap.CloseVectorEngineOnly()
+
+	f, err := os.Open(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开导出文件失败: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	count, err := vectorService.ImportVectors(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "向量导入失败（已导入 %d 条）: %v\n", count, err)
+		return 1
+	}
+	fmt.Printf("向量导入完成: %d 条\n", count)
+	return 0
+}
+
+// runVectorReindex 同步执行向量重建并定期输出进度
+func runVectorReindex(args []string) int {
+	fs := flag.NewFlagSet("vector-reindex", flag.ContinueOnError)
+	batchSize := fs.Int("batch", 20, "每批向量化数量")
+	intervalMs := fs.Int("interval", 500, "批次间隔毫秒")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := bootstrap.InitDatabaseOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		return 1
+	}
+	// 重建会删除集合，运行中的服务仍会写入向量，因此只允许在服务全部停止时执行
+	active, err := bootstrap.ActiveServerInstances()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if active > 0 {
+		fmt.Fprintf(os.Stderr, "检测到 %d 个正在运行的服务实例，请先停止服务，或在后台管理中发起向量重建\n", active)
+		return 1
+	}
+	if err := bootstrap.InitVectorEngineOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer bootstrap.CloseVectorEngineOnly()
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p := vectorService.GetVectorReindexProgress()
+				fmt.Printf("进度: %d/%d (成功 %d, 失败 %d)\n", p.Processed, p.Total, p.Succeeded, p.Failed)
+			}
+		}
+	}()
+
+	progress, err := vectorService.RunVectorReindex(*batchSize, *intervalMs)
+	close(stop)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "向量重建失败: %v\n", err)
+		return 1
+	}
+
+	if progress.Status != vectorService.ReindexStatusCompleted {
+		fmt.Fprintf(os.Stderr, "向量重建失败: %s\n", progress.Error)
+		return 1
+	}
+
+	fmt.Printf("向量重建完成: 模型=%s 维度=%d 总数=%d 成功=%d 失败=%d\n",
+		progress.Model, progress.Dimension, progress.Total, progress.Succeeded, progress.Failed)
+	return 0
+}
+
+// runBackup 执行一次备份，指定 -o 时写入本地文件
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := fs.String("o", "", "写入本地文件而不上传")
+	includeStorage := fs.Bool("storage", false, "同时打包本地存储文件")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !initCommandApp() {
+		return 1
+	}
+	backup.SetAppVersion(Version)
+
+	if *output == "" {
+		info, err := backup.Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "备份失败: %v\n", err)
+			return 1
+		}
+		fmt.Printf("备份完成: %s (%d 字节)\n", info.Key, info.Size)
+		return 0
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	if _, err := backup.WriteArchive(f, *includeStorage || backup.GetConfig().IncludeStorage); err != nil {
+		fmt.Fprintf(os.Stderr, "备份失败: %v\n", err)
+		return 1
+	}
+	fmt.Printf("备份完成: %s\n", *output)
+	return 0
+}
+
+// runBackupRestore 从本地文件或目标渠道恢复备份，只初始化数据库，避免后台任务在恢复期间写入
+func runBackupRestore(args []string) int {
+	fs := flag.NewFlagSet("backup-restore", flag.ContinueOnError)
+	input := fs.String("i", "", "本地备份文件路径")
+	key := fs.String("key", "", "目标渠道中的备份文件")
+	restoreStorage := fs.Bool("storage", false, "同时恢复本地存储文件")
+	configPath := fs.String("config", "", "配置文件写入路径")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*input == "") == (*key == "") {
+		fmt.Fprintln(os.Stderr, "请通过 -i 或 -key 指定一个备份文件")
+		return 2
+	}
+	if err := bootstrap.InitDatabaseOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		return 1
+	}
+
+	var src io.ReadCloser
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "打开备份文件失败: %v\n", err)
+			return 1
+		}
+		src = f
+	} else {
+		rc, err := backup.Open(context.Background(), *key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "读取备份失败: %v\n", err)
+			return 1
+		}
+		src = rc
+	}
+	defer src.Close()
+
+	result, err := backup.Restore(src, backup.RestoreOptions{Storage: *restoreStorage, ConfigPath: *configPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "恢复失败，数据库已回滚: %v\n", err)
+		return 1
+	}
+	if err := cache.ClearNamespaceCache(); err != nil {
+		fmt.Fprintf(os.Stderr, "清理缓存失败，请手动清理或等待缓存过期: %v\n", err)
+	}
+
+	fmt.Printf("恢复完成: 备份时间 %s，版本 %s，数据表 %d 个，存储文件 %d 个\n",
+		result.Manifest.CreatedAt.Format("2006-01-02 15:04:05"), result.Manifest.AppVersion, len(result.Tables), result.StorageFiles)
+	if len(result.Skipped) > 0 {
+		fmt.Printf("当前版本不存在以下数据表，已跳过: %v\n", result.Skipped)
+	}
+	if result.Config {
+		fmt.Printf("配置文件已写入 %s，请核对后替换现有配置\n", *configPath)
+	}
+	fmt.Println("请重启服务使恢复的数据生效")
+	return 0
+}
+
+// runExport 导出实例到本地文件
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "pixelpunk-export.tar.gz", "输出文件路径")
+	includeStorage := fs.Bool("storage", false, "同时打包本地存储文件")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := bootstrap.InitDatabaseOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		return 1
+	}
+	backup.SetAppVersion(Version)
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	manifest, err := backup.WriteArchive(f, *includeStorage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "导出失败: %v\n", err)
+		return 1
+	}
+	fmt.Printf("导出完成: %s（数据表 %d 个，存储渠道 %d 个）\n", *output, len(manifest.Tables), len(manifest.Channels))
+	return 0
+}
+
+// runImport 将导出文件导入当前实例，可将旧存储渠道映射到当前实例已有的渠道
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	input := fs.String("i", "", "导出文件路径")
+	listOnly := fs.Bool("list", false, "只列出存储渠道")
+	restoreStorage := fs.Bool("storage", false, "同时恢复本地存储文件")
+	configPath := fs.String("config", "", "配置文件写入路径")
+	channelMap := map[string]string{}
+	fs.Func("map", "渠道ID映射 old=new", func(v string) error {
+		for _, pair := range strings.Split(v, ",") {
+			oldID, newID, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || oldID == "" || newID == "" {
+				return fmt.Errorf("格式应为 旧渠道ID=新渠道ID: %s", pair)
+			}
+			channelMap[oldID] = newID
+		}
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "请通过 -i 指定导出文件路径")
+		return 2
+	}
+	if err := bootstrap.InitDatabaseOnly(); err != nil {
+		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		return 1
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开导出文件失败: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	if *listOnly {
+		manifest, err := backup.ReadManifest(f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "读取导出文件失败: %v\n", err)
+			return 1
+		}
+		current, err := backup.ListCurrentChannels()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "获取当前存储渠道失败: %v\n", err)
+			return 1
+		}
+		fmt.Printf("导出文件: 版本 %s，数据库 %s，导出时间 %s\n", manifest.AppVersion, manifest.DBType, manifest.CreatedAt.Format("2006-01-02 15:04:05"))
+		printChannels("导出文件中的存储渠道:", manifest.Channels)
+		printChannels("当前实例的存储渠道:", current)
+		return 0
+	}
+
+	result, err := backup.Restore(f, backup.RestoreOptions{Storage: *restoreStorage, ConfigPath: *configPath, ChannelMap: channelMap})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "导入失败，数据库已回滚: %v\n", err)
+		return 1
+	}
+	if err := cache.ClearNamespaceCache(); err != nil {
+		fmt.Fprintf(os.Stderr, "清理缓存失败，请手动清理或等待缓存过期: %v\n", err)
+	}
+
+	fmt.Printf("导入完成: 源版本 %s（%s），数据表 %d 个，存储文件 %d 个，渠道映射 %d 个\n",
+		result.Manifest.AppVersion, result.Manifest.DBType, len(result.Tables), result.StorageFiles, len(channelMap))
+	if len(result.Skipped) > 0 {
+		fmt.Printf("当前版本不存在以下数据表，已跳过: %v\n", result.Skipped)
+	}
+	fmt.Println("请重启服务使导入的数据生效")
+	return 0
+}
+
+func printChannels(title string, channels []backup.ChannelInfo) {
+	fmt.Println(title)
+	for _, ch := range channels {
+		mark := ""
+		if ch.IsDefault {
+			mark = " [默认]"
+		}
+		fmt.Printf("  %s  %-8s %s%s（文件 %d）\n", ch.ID, ch.Type, ch.Name, mark, ch.Files)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"pixelpunk/internal/bootstrap"
 	"pixelpunk/pkg/logger"
 	"syscall"
-	"time"
 )
 
 // Version 应用版本号，可通过 ldflags 在编译时注入
@@ -15,6 +14,11 @@ import (
 var Version = "1.2.3"
 
 func main() {
+	// 带子命令时执行命令行工具，不启动HTTP服务
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	app := bootstrap.NewApp(Version)
 
 	if err := app.Initialize(); err != nil {
@@ -24,7 +28,7 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go handleSignals(cancel)
+	go handleSignals(app, cancel)
 
 	go func() {
 		if err := app.Start(); err != nil {
@@ -34,7 +38,7 @@ func main() {
 
 	<-ctx.Done()
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), bootstrap.ShutdownTimeout())
 	defer shutdownCancel()
 
 	if err := app.Shutdown(shutdownCtx); err != nil {
@@ -42,7 +46,8 @@ func main() {
 	}
 }
 
-func handleSignals(cancel context.CancelFunc) {
+// handleSignals SIGHUP 重新加载配置，其余信号安全退出
+func handleSignals(app *bootstrap.App, cancel context.CancelFunc) {
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan,
 		syscall.SIGINT,
@@ -51,7 +56,14 @@ func handleSignals(cancel context.CancelFunc) {
 		syscall.SIGHUP,
 	)
 
-	<-signalChan
-	logger.Info("正在安全退出程序...")
-	cancel()
+	for sig := range signalChan {
+		if sig == syscall.SIGHUP {
+			logger.Info("收到 SIGHUP，重新加载配置...")
+			app.Reload()
+			continue
+		}
+		logger.Info("正在安全退出程序...")
+		cancel()
+		return
+	}
 }
@@ -4,20 +4,33 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"pixelpunk/internal/controllers/websocket"
 	"pixelpunk/internal/cron"
 	middlewareInternal "pixelpunk/internal/middleware"
+	qqueue "pixelpunk/internal/queue"
 	"pixelpunk/internal/routes"
+	ai "pixelpunk/internal/services/ai"
+	"pixelpunk/internal/services/apikey"
+	"pixelpunk/internal/services/errorreport"
+	fileSvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/setting"
 	"pixelpunk/internal/services/storage"
+	"pixelpunk/internal/services/telegram"
+	vectorSvc "pixelpunk/internal/services/vector"
 	"pixelpunk/pkg/cache"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/config"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/email"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/health"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 	"pixelpunk/pkg/vector"
 
 	"github.com/gin-gonic/gin"
@@ -25,11 +38,12 @@ import (
 )
 
 type App struct {
-	Version string
-	Engine  *gin.Engine
-	Server  *http.Server
-	ctx     context.Context
-	cancel  context.CancelFunc
+	Version    string
+	Engine     *gin.Engine
+	Server     *http.Server
+	httpServer *http.Server // 启用内置 HTTPS 时的 HTTP 监听，用于证书验证与重定向
+	ctx        context.Context
+	cancel     context.CancelFunc
 }
 
 func NewApp(version string) *App {
@@ -42,15 +56,12 @@ func NewApp(version string) *App {
 }
 
 func (app *App) Initialize() error {
-	loc, err := time.LoadLocation("Asia/Shanghai")
-	if err != nil {
-		return fmt.Errorf("设置时区失败: %v", err)
+	if err := initBase(); err != nil {
+		return err
 	}
-	time.Local = loc
-
-	logger.InitWithConfig(&logger.Config{LogLevel: gormLogger.Info, Colorful: true})
-	config.InitConfig()
+	app.initTracing()
 	database.InitDB()
+	bootstrapAdmin()
 
 	installManager := common.GetInstallManager()
 	if installManager.IsInstallMode() {
@@ -62,11 +73,14 @@ func (app *App) Initialize() error {
 
 	cache.InitCache()
 	RunMigrations()
+	applyBootstrapSettings()
 	storage.CheckAndInitDefaultChannel()
 	email.Init()
 	websocket.InitWebSocketManager()
 	InitAllServices(app.Version)
 	cron.InitCronManager()
+	qqueue.StartJobs()
+	health.MarkStageDone(health.StageServices)
 
 	if err := app.initializeHTTPServer(); err != nil {
 		return fmt.Errorf("HTTP服务器初始化失败: %v", err)
@@ -75,6 +89,148 @@ func (app *App) Initialize() error {
 	return nil
 }
 
+// initBase 设置时区、日志与配置
+func initBase() error {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return fmt.Errorf("设置时区失败: %v", err)
+	}
+	time.Local = loc
+
+	logger.InitWithConfig(&logger.Config{LogLevel: gormLogger.Info, Colorful: true})
+	config.InitConfig()
+	applyLogLevel()
+	return nil
+}
+
+/* InitDatabaseOnly 只连接数据库与缓存，不初始化业务服务和定时任务，用于备份恢复等需要独占数据的命令 */
+func InitDatabaseOnly() error {
+	if err := initBase(); err != nil {
+		return err
+	}
+	database.InitDB()
+	if database.GetDB() == nil {
+		return fmt.Errorf("数据库未配置或连接失败，请先配置数据库")
+	}
+	cache.InitCache()
+	return nil
+}
+
+/* InitVectorEngineOnly 在 InitDatabaseOnly 之后初始化设置与向量引擎（含内置 Qdrant），
+ * 不启动向量队列消费、定时任务与HTTP服务，用于向量重建、导出与导入命令 */
+func InitVectorEngineOnly() error {
+	setting.InitSettingService()
+	if !setting.GetBoolDirectFromDB("vector", "vector_enabled", false) {
+		return fmt.Errorf("向量功能未启用")
+	}
+	startBuiltinQdrant()
+	if err := vector.InitConfiguredVectorEngine(); err != nil {
+		return fmt.Errorf("向量引擎初始化失败: %v", err)
+	}
+	if vector.GetGlobalVectorEngine() == nil {
+		return fmt.Errorf("向量引擎初始化失败")
+	}
+	return nil
+}
+
+/* CloseVectorEngineOnly 关闭 InitVectorEngineOnly 打开的向量引擎、内置 Qdrant、数据库与缓存 */
+func CloseVectorEngineOnly() {
+	if engine := vector.GetGlobalVectorEngine(); engine != nil {
+		if err := engine.Close(); err != nil {
+			logger.Error("关闭向量引擎失败: %v", err)
+		}
+	}
+	vector.StopBuiltinQdrant()
+	if err := database.Close(); err != nil {
+		logger.Error("关闭数据库连接失败: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		logger.Error("关闭缓存连接失败: %v", err)
+	}
+}
+
+func applyLogLevel() {
+	if err := logger.SetLevel(config.GetConfig().App.LogLevel); err != nil {
+		logger.Warn("日志级别配置无效: %v，保持当前级别", err)
+	}
+	logger.SetAccessLogFormat(config.GetConfig().App.AccessLog)
+}
+
+/* Reload 重新加载配置文件并应用信任代理、日志级别、链路追踪等可热更新的配置，上传限制在读取时即生效 */
+func (app *App) Reload() {
+	old := config.GetConfig()
+	if err := config.ReloadConfig(); err != nil {
+		logger.Warn("重新加载配置失败，继续使用当前配置: %v", err)
+		return
+	}
+	cfg := config.GetConfig()
+
+	applyLogLevel()
+	if app.Engine != nil {
+		app.applyTrustedProxies()
+	}
+	if !reflect.DeepEqual(old.Tracing, cfg.Tracing) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		tracing.Shutdown(ctx)
+		cancel()
+		app.initTracing()
+	}
+
+	// 以下配置在启动时建立连接或监听，修改后需重启
+	var restartRequired []string
+	if old.App.Port != cfg.App.Port || old.App.Mode != cfg.App.Mode || old.App.Namespace != cfg.App.Namespace {
+		restartRequired = append(restartRequired, "app")
+	}
+	if !reflect.DeepEqual(old.Database, cfg.Database) {
+		restartRequired = append(restartRequired, "database")
+	}
+	if !reflect.DeepEqual(old.Redis, cfg.Redis) {
+		restartRequired = append(restartRequired, "redis")
+	}
+	if old.Vector != cfg.Vector {
+		restartRequired = append(restartRequired, "vector")
+	}
+	if !reflect.DeepEqual(old.TLS, cfg.TLS) {
+		restartRequired = append(restartRequired, "tls")
+	}
+
+	logger.Info("配置已重新加载")
+	if len(restartRequired) > 0 {
+		logger.Warn("以下配置已修改但需重启后生效: %s", strings.Join(restartRequired, ", "))
+	}
+}
+
+/* initTracing 按配置开启链路追踪，导出失败只记录日志不影响服务 */
+func (app *App) initTracing() {
+	cfg := config.GetConfig().Tracing
+	if !cfg.Enabled {
+		return
+	}
+
+	headers := make(map[string]string, len(cfg.Headers))
+	for _, h := range cfg.Headers {
+		if k, v, ok := strings.Cut(h, "="); ok {
+			headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+
+	tracing.ErrorHandler = func(err error) {
+		logger.Warn("链路追踪数据导出失败: %v", err)
+	}
+	err := tracing.Init(tracing.Config{
+		Endpoint:    cfg.Endpoint,
+		ServiceName: cfg.ServiceName,
+		Version:     app.Version,
+		SampleRatio: cfg.SampleRatio,
+		Headers:     headers,
+	})
+	if err != nil {
+		logger.Warn("链路追踪初始化失败: %v", err)
+		return
+	}
+	logger.Info("链路追踪已开启: endpoint=%s, 采样比例=%.2f", cfg.Endpoint, cfg.SampleRatio)
+}
+
 func (app *App) initializeHTTPServer() error {
 	gin.SetMode(config.GetConfig().App.Mode)
 	app.Engine = gin.New()
@@ -84,23 +240,57 @@ func (app *App) initializeHTTPServer() error {
 }
 
 func (app *App) configureMiddleware() {
+	// 客户端 IP 由 resolveClientIP 按信任代理计算，gin 只读取其写入的请求头
+	app.Engine.TrustedPlatform = clientIPHeader
+	if err := app.Engine.SetTrustedProxies(nil); err != nil {
+		logger.Warn("重置 gin 信任代理失败: %v", err)
+	}
+	app.Engine.Use(resolveClientIP())
 	app.Engine.Use(middlewareInternal.CORSMiddleware())
 	app.Engine.Use(gin.Recovery())
+	app.Engine.Use(middlewareInternal.Tracing())
 	app.Engine.Use(errors.ErrorHandler())
+	app.Engine.Use(middlewareInternal.AccessLog())
+	errorreport.SetRelease(app.Version)
+	errors.SetReporter(middlewareInternal.ReportError)
+	app.Engine.Use(middlewareInternal.RequestMetrics())
+
+	app.applyTrustedProxies()
+}
 
-	// 配置信任的代理 IP，支持从配置文件读取
-	// 默认值：本地回环地址（IPv4 和 IPv6）
+// applyTrustedProxies 配置信任的代理 IP，支持从配置文件读取，热重载时原子替换
+// 默认值：本地回环地址（IPv4 和 IPv6）
+func (app *App) applyTrustedProxies() {
 	trustedProxies := config.GetConfig().App.TrustedProxies
 	if len(trustedProxies) == 0 {
-		trustedProxies = []string{"127.0.0.1", "::1"}
+		trustedProxies = defaultTrustedProxies
 	}
-	if err := app.Engine.SetTrustedProxies(trustedProxies); err != nil {
+	cidrs, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
 		logger.Warn("设置信任代理失败: %v，将使用默认配置", err)
-		app.Engine.SetTrustedProxies([]string{"127.0.0.1", "::1"})
+		cidrs, _ = parseTrustedProxies(defaultTrustedProxies)
 	}
+	trustedProxyCIDRs.Store(&cidrs)
+}
+
+// shutdownReserve 关闭时限中为放回任务与关闭连接预留的时间上限
+const shutdownReserve = 2 * time.Second
+
+/* ShutdownTimeout 优雅关闭的总时限，由 app.shutdown_timeout 配置，默认 10 秒 */
+func ShutdownTimeout() time.Duration {
+	if seconds := config.GetConfig().App.ShutdownTimeout; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 10 * time.Second
 }
 
 func (app *App) Start() error {
+	app.startPresence()
+
+	if config.GetConfig().TLS.Enabled {
+		return app.startTLS()
+	}
+
 	appCfg := config.GetConfig().App
 	app.Server = &http.Server{
 		Addr:    fmt.Sprintf(":%d", appCfg.Port),
@@ -115,21 +305,68 @@ func (app *App) Start() error {
 	return nil
 }
 
+/* Shutdown 优雅关闭：就绪探针先返回 503，停止接收新请求并等待进行中的请求与上传后处理结束，
+ * 随后停止 AI、向量与通用任务队列，时限内未完成的任务放回队列，最后关闭内置 Qdrant、数据库与缓存 */
 func (app *App) Shutdown(ctx context.Context) error {
-	if app.Server != nil {
-		if err := app.Server.Shutdown(ctx); err != nil {
-			logger.Error("HTTP服务器关闭失败: %v", err)
+	health.MarkShuttingDown()
+
+	// 预留一部分时限用于放回任务与关闭连接
+	drainCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		reserve := time.Until(deadline) / 5
+		if reserve > shutdownReserve {
+			reserve = shutdownReserve
+		}
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithDeadline(ctx, deadline.Add(-reserve))
+		defer cancel()
+	}
+
+	for _, server := range []*http.Server{app.Server, app.httpServer} {
+		if server == nil {
+			continue
+		}
+		if err := server.Shutdown(drainCtx); err != nil {
+			logger.Warn("HTTP服务器未在关闭时限内处理完请求，强制关闭: %v", err)
+			server.Close()
 		}
 	}
 
+	if !fileSvc.WaitUploadTasks(drainCtx) {
+		logger.Warn("上传后处理任务未在关闭时限内完成")
+	}
+	fileSvc.ShutdownUploadService()
+
 	app.cancel()
+	clearPresence()
 	cron.Stop()
+	telegram.Stop()
+	websocket.StopWebSocketManager()
+
+	var queues sync.WaitGroup
+	queues.Add(3)
+	go func() {
+		defer queues.Done()
+		ai.Shutdown(drainCtx)
+	}()
+	go func() {
+		defer queues.Done()
+		vectorSvc.ShutdownVectorQueue(drainCtx)
+	}()
+	go func() {
+		defer queues.Done()
+		qqueue.StopJobs(drainCtx)
+	}()
+	queues.Wait()
 
 	if vectorEngine := vector.GetGlobalVectorEngine(); vectorEngine != nil {
 		if err := vectorEngine.Close(); err != nil {
 			logger.Error("关闭向量引擎失败: %v", err)
 		}
 	}
+	vector.StopBuiltinQdrant()
+
+	apikey.FlushUsage()
 
 	if err := database.Close(); err != nil {
 		logger.Error("关闭数据库连接失败: %v", err)
@@ -139,5 +376,7 @@ func (app *App) Shutdown(ctx context.Context) error {
 		logger.Error("关闭缓存连接失败: %v", err)
 	}
 
+	tracing.Shutdown(ctx)
+
 	return nil
 }
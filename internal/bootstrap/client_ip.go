@@ -0,0 +1,99 @@
+package bootstrap
+
+import (
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIPHeader 由 resolveClientIP 写入的客户端 IP 请求头，作为 gin 的 TrustedPlatform 供 c.ClientIP() 读取；
+// 客户端自带的同名请求头会被覆盖
+const clientIPHeader = "X-PixelPunk-Client-IP"
+
+// defaultTrustedProxies 未配置信任代理时使用本地回环地址
+var defaultTrustedProxies = []string{"127.0.0.1", "::1"}
+
+// trustedProxyCIDRs 当前信任的代理网段，热重载时整体替换，请求中只读
+var trustedProxyCIDRs atomic.Pointer[[]*net.IPNet]
+
+// parseTrustedProxies 解析 IP 或 CIDR 列表，规则与 gin.Engine.SetTrustedProxies 一致
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	cidrs := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: proxy}
+			}
+			if ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, cidr, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	cidrs := trustedProxyCIDRs.Load()
+	if cidrs == nil || ip == nil {
+		return false
+	}
+	for _, cidr := range *cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIPFromHeader 从右向左跳过信任的代理，返回第一个不受信任的地址
+func clientIPFromHeader(header string) (string, bool) {
+	if header == "" {
+		return "", false
+	}
+	items := strings.Split(header, ",")
+	for i := len(items) - 1; i >= 0; i-- {
+		ipStr := strings.TrimSpace(items[i])
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			break
+		}
+		if i == 0 || !isTrustedProxy(ip) {
+			return ipStr, true
+		}
+	}
+	return "", false
+}
+
+/* resolveClientIP 按当前信任代理计算客户端 IP 并写入 clientIPHeader；
+ * 信任代理列表可在运行中替换，不修改 gin.Engine 的字段，避免与处理中的请求并发读写 */
+func resolveClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Header.Del(clientIPHeader)
+
+		remoteIP := c.RemoteIP()
+		clientIP := remoteIP
+		if isTrustedProxy(net.ParseIP(remoteIP)) {
+			for _, name := range []string{"X-Forwarded-For", "X-Real-IP"} {
+				if ip, ok := clientIPFromHeader(c.GetHeader(name)); ok {
+					clientIP = ip
+					break
+				}
+			}
+		}
+		if clientIP != "" {
+			c.Request.Header.Set(clientIPHeader, clientIP)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,119 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/config"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+/* bootstrapAdmin 数据库已就绪但缺少管理员时，使用 bootstrap 配置创建超级管理员并跳过安装向导 */
+func bootstrapAdmin() {
+	cfg := config.GetConfig().Bootstrap
+	username := strings.TrimSpace(cfg.AdminUsername)
+	if username == "" || cfg.AdminPassword == "" {
+		return
+	}
+
+	installManager := common.GetInstallManager()
+	db := database.GetDB()
+	if !installManager.IsInstallMode() || db == nil || !db.Migrator().HasTable(&models.User{}) {
+		return
+	}
+
+	var count int64
+	db.Model(&models.User{}).Where("username = ?", username).Count(&count)
+	if count > 0 {
+		logger.Warn("引导配置的管理员用户名 %s 已被普通用户占用，请通过安装向导完成初始化", username)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(cfg.AdminPassword)
+	if err != nil {
+		logger.Error("引导创建管理员失败，密码加密失败: %v", err)
+		return
+	}
+
+	email := strings.TrimSpace(cfg.AdminEmail)
+	if email == "" {
+		email = fmt.Sprintf("%s@pixelpunk.local", username)
+	}
+	admin := models.User{
+		Username: username,
+		Password: hashedPassword,
+		Email:    email,
+		Role:     common.UserRoleSuperAdmin,
+		Status:   common.UserStatusNormal,
+	}
+	if err := db.Create(&admin).Error; err != nil {
+		logger.Error("引导创建管理员失败: %v", err)
+		return
+	}
+
+	installManager.SetInstallMode(false)
+	installManager.SetSystemInstalled(true)
+	logger.Info("已根据引导配置创建管理员账户 %s", username)
+}
+
+/* applyBootstrapSettings 将 bootstrap 配置中非空的项写入系统设置，每次启动都会覆盖，便于容器环境统一由环境变量管理 */
+func applyBootstrapSettings() {
+	cfg := config.GetConfig().Bootstrap
+
+	var overrides []setting.SettingOverride
+	addString := func(group, key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			overrides = append(overrides, setting.SettingOverride{Group: group, Key: key, Type: "string", Value: value})
+		}
+	}
+	addBool := func(group, key string, value *bool) {
+		if value != nil {
+			overrides = append(overrides, setting.SettingOverride{Group: group, Key: key, Type: "boolean", Value: *value})
+		}
+	}
+
+	addString("security", "jwt_secret", cfg.JWTSecret)
+	addString("website", "site_base_url", cfg.SiteBaseURL)
+
+	addString("mail", "smtp_host", cfg.SMTPHost)
+	if cfg.SMTPPort > 0 {
+		overrides = append(overrides, setting.SettingOverride{Group: "mail", Key: "smtp_port", Type: "number", Value: cfg.SMTPPort})
+	}
+	addString("mail", "smtp_encryption", cfg.SMTPEncryption)
+	addString("mail", "smtp_username", cfg.SMTPUsername)
+	addString("mail", "smtp_password", cfg.SMTPPassword)
+	addString("mail", "smtp_from_address", cfg.SMTPFromAddress)
+	addString("mail", "smtp_from_name", cfg.SMTPFromName)
+
+	addBool("ai", "ai_enabled", cfg.AIEnabled)
+	addString("ai", "ai_api_key", cfg.AIAPIKey)
+	addString("ai", "ai_model", cfg.AIModel)
+	addString("ai", "ai_proxy", cfg.AIProxy)
+
+	addBool("vector", "vector_enabled", cfg.VectorEnabled)
+	addString("vector", "vector_provider", cfg.VectorProvider)
+	addString("vector", "vector_api_key", cfg.VectorAPIKey)
+	addString("vector", "vector_base_url", cfg.VectorBaseURL)
+	addString("vector", "vector_model", cfg.VectorModel)
+	addString("vector", "qdrant_url", cfg.QdrantURL)
+	addString("vector", "qdrant_api_key", cfg.QdrantAPIKey)
+
+	if len(overrides) == 0 {
+		return
+	}
+
+	updated, err := setting.ApplySettingOverrides(overrides)
+	if err != nil {
+		logger.Error("应用引导配置失败: %v", err)
+		return
+	}
+	if len(updated) > 0 {
+		// 只记录键名，避免密钥写入日志
+		logger.Info("已根据引导配置更新系统设置: %s", strings.Join(updated, ", "))
+	}
+}
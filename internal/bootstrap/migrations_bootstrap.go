@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"pixelpunk/migrations"
 	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/health"
 	"pixelpunk/pkg/logger"
 )
 
@@ -25,4 +26,5 @@ func RunMigrations() {
 		logger.Warn("部分迁移可能执行失败: %v", err)
 		return
 	}
+	health.MarkStageDone(health.StageMigrations)
 }
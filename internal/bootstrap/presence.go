@@ -0,0 +1,98 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// presenceKey 运行中服务实例的有序集合，成员为实例标识，分值为最近一次心跳的毫秒时间戳
+	presenceKey      = "server:instances"
+	presenceInterval = 10 * time.Second
+	presenceTTL      = 30 * time.Second
+	// presenceFile 本机服务的心跳文件，未启用 Redis 时命令行工具据此判断服务是否在运行
+	presenceFile = "temp/server.heartbeat"
+)
+
+/* startPresence 服务运行期间定期登记心跳，供向量重建等命令判断是否有服务实例在运行；
+ * 启用 Redis 时登记到共享的有序集合，同时写入本机心跳文件 */
+func (app *App) startPresence() {
+	beat := func() {
+		now := time.Now()
+		if client := cache.GetRedisClient(); client != nil && cache.IsRedisEnabled() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			key := cache.GetNamespace() + ":" + presenceKey
+			pipe := client.TxPipeline()
+			pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: cache.InstanceID()})
+			pipe.PExpire(ctx, key, presenceTTL*2)
+			if _, err := pipe.Exec(ctx); err != nil {
+				logger.Warn("登记服务实例心跳失败: %v", err)
+			}
+			cancel()
+		}
+		if err := os.MkdirAll(filepath.Dir(presenceFile), 0755); err == nil {
+			_ = os.WriteFile(presenceFile, []byte(cache.InstanceID()+" "+strconv.FormatInt(now.Unix(), 10)), 0644)
+		}
+	}
+
+	beat()
+	go func() {
+		ticker := time.NewTicker(presenceInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				beat()
+			case <-app.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// clearPresence 关闭时注销本实例的心跳
+func clearPresence() {
+	if client := cache.GetRedisClient(); client != nil && cache.IsRedisEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		client.ZRem(ctx, cache.GetNamespace()+":"+presenceKey, cache.InstanceID())
+		cancel()
+	}
+	os.Remove(presenceFile)
+}
+
+/* ActiveServerInstances 统计心跳未过期的服务实例数：启用 Redis 时统计所有实例，否则只检查本机心跳文件 */
+func ActiveServerInstances() (int, error) {
+	now := time.Now()
+	count := 0
+
+	if client := cache.GetRedisClient(); client != nil && cache.IsRedisEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		key := cache.GetNamespace() + ":" + presenceKey
+		stale := strconv.FormatInt(now.Add(-presenceTTL).UnixMilli(), 10)
+		if err := client.ZRemRangeByScore(ctx, key, "-inf", "("+stale).Err(); err != nil {
+			return 0, fmt.Errorf("查询运行中的服务实例失败: %v", err)
+		}
+		n, err := client.ZCard(ctx, key).Result()
+		if err != nil {
+			return 0, fmt.Errorf("查询运行中的服务实例失败: %v", err)
+		}
+		count = int(n)
+	}
+
+	if count == 0 {
+		if info, err := os.Stat(presenceFile); err == nil && now.Sub(info.ModTime()) < presenceTTL {
+			count = 1
+		}
+	}
+	return count, nil
+}
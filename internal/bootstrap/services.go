@@ -5,8 +5,11 @@ import (
 	"time"
 
 	ai "pixelpunk/internal/services/ai"
+	"pixelpunk/internal/services/backup"
+	fileSvc "pixelpunk/internal/services/file"
 	"pixelpunk/internal/services/message"
 	"pixelpunk/internal/services/setting"
+	"pixelpunk/internal/services/telegram"
 	"pixelpunk/internal/services/user"
 	vectorSvc "pixelpunk/internal/services/vector"
 	"pixelpunk/pkg/logger"
@@ -17,13 +20,18 @@ func InitAllServices(appVersion string) {
 	user.InitUserService()
 	setting.InitSettingService()
 	syncVersionToDatabase(appVersion)
+	backup.SetAppVersion(appVersion)
 	initMessageService()
+	fileSvc.InitUploadService()
+	startBuiltinQdrant()
 	initVectorEngine()
 	ai.RegisterAISettingHooks()
 	vectorSvc.RegisterVectorConfigHooks()
 	if err := ai.InitGlobalTaggingQueue(); err != nil {
 		logger.Warn("AI打标队列初始化警告: %v", err)
 	}
+	telegram.RegisterTelegramSettingHooks()
+	go telegram.Start()
 }
 
 /* syncVersionToDatabase 同步应用版本号到数据库 */
@@ -43,6 +51,19 @@ func syncVersionToDatabase(appVersion string) {
 	}
 }
 
+/* startBuiltinQdrant 安装时选择了内置Qdrant时由应用托管其进程 */
+func startBuiltinQdrant() {
+	if !setting.GetBoolDirectFromDB("vector", "qdrant_builtin", false) {
+		return
+	}
+
+	httpPort := setting.GetIntDirectFromDB("vector", "qdrant_builtin_http_port", 6333)
+	grpcPort := setting.GetIntDirectFromDB("vector", "qdrant_builtin_grpc_port", 6334)
+	if err := vector.StartBuiltinQdrant(httpPort, grpcPort); err != nil {
+		logger.Error("启动内置Qdrant失败: %v", err)
+	}
+}
+
 func initVectorEngine() {
 	vectorEnabled := setting.GetBoolDirectFromDB("vector", "vector_enabled", false)
 	if !vectorEnabled {
@@ -50,15 +71,7 @@ func initVectorEngine() {
 		return
 	}
 
-	qdrantURL := setting.GetStringDirectFromDB("vector", "qdrant_url", "")
-	if qdrantURL == "" {
-		logger.Warn("向量功能已启用，但未配置 qdrant_url，跳过初始化")
-		return
-	}
-
-	qdrantTimeout := setting.GetIntDirectFromDB("vector", "qdrant_timeout", 30)
-
-	if err := vector.InitQdrantVectorEngine(qdrantURL, qdrantTimeout); err != nil {
+	if err := vector.InitConfiguredVectorEngine(); err != nil {
 		logger.Error("向量引擎初始化失败: %v", err)
 		return
 	}
@@ -80,6 +93,8 @@ func initVectorEngine() {
 			if n, err := queueSvc.EnqueueAllPending(1000); err == nil && n > 0 {
 				logger.Info("启动时自动扫描入队 %d 个待处理向量任务", n)
 			}
+			vectorSvc.CheckVectorCollectionMigration()
+			vectorSvc.CheckVectorPayloadSync()
 		}()
 	}
 }
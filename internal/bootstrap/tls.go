@@ -0,0 +1,107 @@
+package bootstrap
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/pkg/config"
+	"pixelpunk/pkg/logger"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const letsEncryptStagingURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+// startTLS 启用内置 HTTPS：HTTPS 端口由 autocert 自动申请与续期证书，
+// HTTP 端口响应 HTTP-01 验证，探针请求直接处理，其余请求重定向到 HTTPS
+func (app *App) startTLS() error {
+	cfg := config.GetConfig().TLS
+
+	var domains []string
+	for _, d := range cfg.Domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("启用内置 HTTPS 需要配置 tls.domains")
+	}
+	if err := os.MkdirAll(cfg.CacheDir, 0700); err != nil {
+		return fmt.Errorf("创建证书缓存目录失败: %v", err)
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cfg.CacheDir),
+		Email:      cfg.Email,
+	}
+	if cfg.Staging {
+		manager.Client = &acme.Client{DirectoryURL: letsEncryptStagingURL}
+	}
+
+	app.Server = &http.Server{
+		Addr:      fmt.Sprintf(":%d", cfg.Port),
+		Handler:   app.Engine,
+		TLSConfig: manager.TLSConfig(),
+	}
+
+	if cfg.HTTPPort > 0 {
+		app.httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
+			Handler:           manager.HTTPHandler(httpsRedirectHandler(app.Engine, cfg.Port)),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info("🔀 启动HTTP服务器（证书验证与HTTPS重定向），地址: %s", app.httpServer.Addr)
+			if err := app.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("HTTP服务器启动失败，证书将只能通过 TLS-ALPN-01 验证: %v", err)
+			}
+		}()
+	} else {
+		logger.Warn("未监听HTTP端口，证书只能通过 TLS-ALPN-01 验证，请确保 %d 端口可从公网访问", cfg.Port)
+	}
+
+	if cfg.Staging {
+		logger.Warn("正在使用 Let's Encrypt 测试环境，签发的证书不受浏览器信任")
+	}
+	logger.Info("🔒 启动HTTPS服务器，地址: %s，域名: %s", app.Server.Addr, strings.Join(domains, ", "))
+	if err := app.Server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("HTTPS服务器启动失败: %v", err)
+	}
+	return nil
+}
+
+// httpsRedirectHandler 探针请求直接交给 handler，便于容器健康检查继续使用 HTTP，其余请求重定向到 HTTPS
+func httpsRedirectHandler(handler http.Handler, httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if httpsPort != 443 {
+			host += ":" + strconv.Itoa(httpsPort)
+		}
+
+		status := http.StatusMovedPermanently
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			// 保留请求方法与请求体
+			status = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+}
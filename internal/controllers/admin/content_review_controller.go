@@ -80,6 +80,11 @@ func GetReviewQueue(c *gin.Context) {
 		keyword = k
 	}
 
+	assigned := c.Query("params[assigned]")
+	if assigned == "" {
+		assigned = c.Query("assigned")
+	}
+
 	var reviewFiles []models.File
 	db := database.GetDB().Model(&models.File{}).Preload("AIInfo").Preload("User").Where("status = ?", "pending_review")
 
@@ -87,6 +92,9 @@ func GetReviewQueue(c *gin.Context) {
 		db = db.Where("original_name LIKE ? OR display_name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
 	}
 
+	// 认领筛选：mine 我认领的，unclaimed 无人认领的，others 他人认领的
+	db = review.FilterReviewQueueByAssignee(db, assigned, middleware.GetCurrentUserID(c))
+
 	var total int64
 	countErr := db.Count(&total).Error
 	if countErr != nil {
@@ -103,6 +111,12 @@ func GetReviewQueue(c *gin.Context) {
 
 	signer := utils.GetURLSigner()
 
+	fileIDs := make([]string, 0, len(reviewFiles))
+	for _, file := range reviewFiles {
+		fileIDs = append(fileIDs, file.ID)
+	}
+	claims := review.GetReviewClaims(fileIDs)
+
 	var responseFiles []map[string]interface{}
 	for _, file := range reviewFiles {
 		var fullURL, fullThumbURL string
@@ -111,6 +125,17 @@ func GetReviewQueue(c *gin.Context) {
 			fullThumbURL = signer.SignThumbURL(file.ID, utils.SIGNATURE_DURATION)
 		}
 
+		var claimInfo map[string]interface{}
+		if claim, ok := claims[file.ID]; ok {
+			claimInfo = map[string]interface{}{
+				"assigned_to": claim.AssignedTo,
+				"expires_at":  claim.ExpiresAt,
+			}
+			if claim.Assignee != nil {
+				claimInfo["assignee_name"] = claim.Assignee.Username
+			}
+		}
+
 		var aiInfo map[string]interface{}
 		if file.AIInfo != nil {
 			var tags []string
@@ -179,6 +204,7 @@ func GetReviewQueue(c *gin.Context) {
 			"user_id":    file.UserID,
 			"uploader":   uploaderInfo, // 新增：上传者信息
 			"ai_info":    aiInfo,       // AI信息
+			"claim":      claimInfo,    // 认领信息，无人认领时为空
 		})
 	}
 
@@ -205,6 +231,14 @@ func GetReviewStats(c *gin.Context) {
 		return
 	}
 
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	performance, err := review.GetReviewStats(days)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	stats["performance"] = performance
+
 	errors.ResponseSuccess(c, stats, "获取审核统计成功")
 }
 
@@ -236,6 +270,11 @@ func ReviewFile(c *gin.Context) {
 		errors.HandleError(c, errors.Wrap(reviewErr, errors.CodeInternal, "审核操作失败"))
 		return
 	}
+	middleware.SetAuditChange(c, "review."+req.Action, "file", req.FileID, nil, map[string]interface{}{
+		"action":      req.Action,
+		"reason":      req.Reason,
+		"hard_delete": req.HardDelete,
+	})
 
 	deleteType := "软删除"
 	if req.HardDelete {
@@ -351,35 +390,7 @@ func GetReviewLogs(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-
-	query := db.Model(&models.ReviewLog{}).
-		Preload("File").
-		Preload("Auditor").
-		Preload("Uploader")
-
-	if req.Action != "" {
-		query = query.Where("action = ?", req.Action)
-	}
-
-	if req.AuditorID > 0 {
-		query = query.Where("auditor_id = ?", req.AuditorID)
-	}
-
-	if req.Keyword != "" {
-		keyword := "%" + req.Keyword + "%"
-		query = query.Where(
-			"reason LIKE ? OR EXISTS (SELECT 1 FROM user WHERE user.id = review_log.auditor_id AND user.username LIKE ?) OR EXISTS (SELECT 1 FROM user WHERE user.id = review_log.uploader_id AND user.username LIKE ?) OR EXISTS (SELECT 1 FROM file WHERE file.id = review_log.file_id AND (file.original_name LIKE ? OR file.display_name LIKE ?))",
-			keyword, keyword, keyword, keyword, keyword,
-		)
-	}
-
-	if req.DateFrom != "" {
-		query = query.Where("DATE(created_at) >= ?", req.DateFrom)
-	}
-	if req.DateTo != "" {
-		query = query.Where("DATE(created_at) <= ?", req.DateTo)
-	}
+	query := buildReviewLogQuery(req)
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -399,6 +410,7 @@ func GetReviewLogs(c *gin.Context) {
 		logData := map[string]interface{}{
 			"id":             log.ID,
 			"file_id":        log.FileID,
+			"auditor_type":   log.AuditorType,
 			"action":         log.Action,
 			"delete_type":    log.DeleteType,
 			"reason":         log.Reason,
@@ -491,6 +503,7 @@ func HardDeleteReviewedFile(c *gin.Context) {
 		errors.HandleError(c, errors.Wrap(err, errors.CodeInternal, "硬删除失败"))
 		return
 	}
+	middleware.SetAuditChange(c, "review.hard_delete", "file", fileID, nil, nil)
 	errors.ResponseSuccess(c, nil, "文件已彻底删除")
 }
 
@@ -603,3 +616,36 @@ func BatchRestoreReviewedFiles(c *gin.Context) {
 
 	errors.ResponseSuccess(c, response, "批量恢复操作完成")
 }
+
+// buildReviewLogQuery 按列表筛选条件构建审核记录查询，列表和导出共用
+func buildReviewLogQuery(req *ReviewLogQueryDTO) *gorm.DB {
+	query := database.GetDB().Model(&models.ReviewLog{}).
+		Preload("File").
+		Preload("Auditor").
+		Preload("Uploader")
+
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+
+	if req.AuditorID > 0 {
+		query = query.Where("auditor_id = ?", req.AuditorID)
+	}
+
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		query = query.Where(
+			"reason LIKE ? OR EXISTS (SELECT 1 FROM user WHERE user.id = review_log.auditor_id AND user.username LIKE ?) OR EXISTS (SELECT 1 FROM user WHERE user.id = review_log.uploader_id AND user.username LIKE ?) OR EXISTS (SELECT 1 FROM file WHERE file.id = review_log.file_id AND (file.original_name LIKE ? OR file.display_name LIKE ?))",
+			keyword, keyword, keyword, keyword, keyword,
+		)
+	}
+
+	if req.DateFrom != "" {
+		query = query.Where("DATE(created_at) >= ?", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		query = query.Where("DATE(created_at) <= ?", req.DateTo)
+	}
+
+	return query
+}
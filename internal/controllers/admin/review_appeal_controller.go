@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/review"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewAppealQueryDTO struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Size   int    `form:"size,default=20" binding:"min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=pending approved denied"`
+}
+
+func (dto *ReviewAppealQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Status.oneof": "状态只能是pending、approved或denied",
+	}
+}
+
+type HandleReviewAppealDTO struct {
+	Response string `json:"response" binding:"max=1000"`
+}
+
+func (dto *HandleReviewAppealDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Response.max": "处理意见不能超过1000个字符",
+	}
+}
+
+func GetReviewAppeals(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewAppealQueryDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	appeals, total, err := review.GetReviewAppeals(req.Status, req.Page, req.Size)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result := map[string]interface{}{
+		"data": appeals,
+		"pagination": map[string]interface{}{
+			"page":       req.Page,
+			"page_size":  req.Size,
+			"total":      int(total),
+			"total_page": (int(total) + req.Size - 1) / req.Size,
+		},
+	}
+
+	errors.ResponseSuccess(c, result, "获取申诉列表成功")
+}
+
+func ApproveReviewAppeal(c *gin.Context) {
+	handleReviewAppeal(c, true)
+}
+
+func DenyReviewAppeal(c *gin.Context) {
+	handleReviewAppeal(c, false)
+}
+
+func handleReviewAppeal(c *gin.Context, approve bool) {
+	appealID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "申诉ID无效"))
+		return
+	}
+
+	req, err := common.ValidateRequest[HandleReviewAppealDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	handlerID := middleware.GetCurrentUserID(c)
+	if approve {
+		err = review.ApproveReviewAppeal(uint(appealID), handlerID, req.Response)
+	} else {
+		err = review.DenyReviewAppeal(uint(appealID), handlerID, req.Response)
+	}
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeInternal, "处理申诉失败"))
+		return
+	}
+
+	message := "申诉已驳回"
+	if approve {
+		message = "申诉已通过，文件已恢复"
+	}
+	errors.ResponseSuccess(c, nil, message)
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/review"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewBulkFilterDTO struct {
+	UploaderID   uint     `json:"uploader_id"`
+	NSFWScoreMin *float64 `json:"nsfw_score_min" binding:"omitempty,min=0,max=1"`
+	NSFWScoreMax *float64 `json:"nsfw_score_max" binding:"omitempty,min=0,max=1"`
+	Keyword      string   `json:"keyword" binding:"max=100"`
+	DateFrom     string   `json:"date_from" binding:"omitempty,datetime=2006-01-02"`
+	DateTo       string   `json:"date_to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (dto *ReviewBulkFilterDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"NSFWScoreMin.min":  "NSFW分数范围必须在0-1之间",
+		"NSFWScoreMin.max":  "NSFW分数范围必须在0-1之间",
+		"NSFWScoreMax.min":  "NSFW分数范围必须在0-1之间",
+		"NSFWScoreMax.max":  "NSFW分数范围必须在0-1之间",
+		"Keyword.max":       "关键词不能超过100个字符",
+		"DateFrom.datetime": "开始日期格式应为YYYY-MM-DD",
+		"DateTo.datetime":   "结束日期格式应为YYYY-MM-DD",
+	}
+}
+
+func (dto *ReviewBulkFilterDTO) toFilter() review.ReviewBulkFilter {
+	return review.ReviewBulkFilter{
+		UploaderID:   dto.UploaderID,
+		NSFWScoreMin: dto.NSFWScoreMin,
+		NSFWScoreMax: dto.NSFWScoreMax,
+		Keyword:      dto.Keyword,
+		DateFrom:     dto.DateFrom,
+		DateTo:       dto.DateTo,
+	}
+}
+
+type CreateReviewBulkJobDTO struct {
+	ReviewBulkFilterDTO
+	Action     string `json:"action" binding:"required,oneof=approve reject"`
+	Reason     string `json:"reason" binding:"max=500"`
+	HardDelete bool   `json:"hard_delete"`
+}
+
+func (dto *CreateReviewBulkJobDTO) GetValidationMessages() map[string]string {
+	messages := dto.ReviewBulkFilterDTO.GetValidationMessages()
+	messages["Action.required"] = "审核操作不能为空"
+	messages["Action.oneof"] = "审核操作只能是approve或reject"
+	messages["Reason.max"] = "审核原因不能超过500个字符"
+	return messages
+}
+
+type ReviewBulkJobQueryDTO struct {
+	Page int `form:"page,default=1" binding:"min=1"`
+	Size int `form:"size,default=20" binding:"min=1,max=100"`
+}
+
+func (dto *ReviewBulkJobQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Size.max": "每页数量不能超过100",
+	}
+}
+
+func PreviewReviewBulkFilter(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewBulkFilterDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	count, err := review.CountReviewBulkMatches(req.toFilter())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"total": count}, "获取匹配文件数量成功")
+}
+
+func CreateReviewBulkJob(c *gin.Context) {
+	req, err := common.ValidateRequest[CreateReviewBulkJobDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	job, err := review.CreateReviewBulkJob(middleware.GetCurrentUserID(c), req.toFilter(), req.Action, req.Reason, req.HardDelete)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, job, "批量审核任务已创建")
+}
+
+func GetReviewBulkJobs(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewBulkJobQueryDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	jobs, total, err := review.GetReviewBulkJobs(req.Page, req.Size)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result := map[string]interface{}{
+		"data": jobs,
+		"pagination": map[string]interface{}{
+			"page":       req.Page,
+			"page_size":  req.Size,
+			"total":      int(total),
+			"total_page": (int(total) + req.Size - 1) / req.Size,
+		},
+	}
+
+	errors.ResponseSuccess(c, result, "获取批量审核任务成功")
+}
+
+func GetReviewBulkJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "任务ID无效"))
+		return
+	}
+
+	job, err := review.GetReviewBulkJob(uint(jobID))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, job, "获取批量审核任务成功")
+}
+
+func CancelReviewBulkJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "任务ID无效"))
+		return
+	}
+
+	if err := review.CancelReviewBulkJob(uint(jobID)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已请求取消批量审核任务")
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/review"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewClaimDTO struct {
+	FileIDs []string `json:"file_ids" binding:"omitempty,max=50"`
+	Count   int      `json:"count" binding:"omitempty,min=1,max=50"` // 未指定文件时从队列中认领的数量
+}
+
+func (dto *ReviewClaimDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.max": "单次最多认领50个文件",
+		"Count.min":   "认领数量必须大于0",
+		"Count.max":   "单次最多认领50个文件",
+	}
+}
+
+type ReviewReleaseDTO struct {
+	FileIDs []string `json:"file_ids" binding:"required,min=1,max=100"`
+	Force   bool     `json:"force"` // 强制释放他人的认领，仅管理员可用
+}
+
+func (dto *ReviewReleaseDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.required": "文件ID列表不能为空",
+		"FileIDs.min":      "文件ID列表不能为空",
+		"FileIDs.max":      "单次最多释放100个文件",
+	}
+}
+
+func ClaimReviewFiles(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewClaimDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	reviewerID := middleware.GetCurrentUserID(c)
+	claims, failed, err := review.ClaimReviewFiles(req.FileIDs, req.Count, reviewerID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"claims":      claims,
+		"failed":      failed,
+		"ttl_seconds": int(review.ReviewClaimTTL.Seconds()),
+	}, "认领审核文件成功")
+}
+
+func ReleaseReviewFiles(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewReleaseDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if req.Force && !middleware.IsCurrentUserAdmin(c) {
+		errors.HandleError(c, errors.New(errors.CodeForbidden, "只有管理员可以释放他人的认领"))
+		return
+	}
+
+	count, err := review.ReleaseReviewFiles(req.FileIDs, middleware.GetCurrentUserID(c), req.Force)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"released": count}, "释放认领成功")
+}
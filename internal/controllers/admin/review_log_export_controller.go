@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const reviewLogExportBatchSize = 500
+
+// ExportReviewLogs 按审核记录列表的筛选条件导出CSV，分批查询并边查边写
+func ExportReviewLogs(c *gin.Context) {
+	req, err := common.ValidateRequest[ReviewLogQueryDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("review_logs_%s.csv", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(filename))
+	c.Status(http.StatusOK)
+
+	// 写入BOM，避免Excel打开中文乱码
+	c.Writer.Write([]byte("\xEF\xBB\xBF"))
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"审核时间", "操作", "删除方式", "审核方式", "文件ID", "文件名", "上传者ID", "上传者", "审核员ID", "审核员", "NSFW分数", "NSFW阈值", "AI判定NSFW", "原因"})
+
+	var logs []models.ReviewLog
+	result := buildReviewLogQuery(req).Order("id ASC").FindInBatches(&logs, reviewLogExportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, log := range logs {
+			fileName := ""
+			if log.File != nil {
+				fileName = log.File.OriginalName
+			}
+			uploader := ""
+			if log.Uploader != nil {
+				uploader = log.Uploader.Username
+			}
+			auditor := ""
+			if log.AuditorType == models.ReviewAuditorAuto {
+				auditor = "自动审核"
+			} else if log.Auditor != nil {
+				auditor = log.Auditor.Username
+			}
+
+			w.Write([]string{
+				time.Time(log.CreatedAt).Format("2006-01-02 15:04:05"),
+				log.Action,
+				log.DeleteType,
+				log.AuditorType,
+				log.FileID,
+				fileName,
+				strconv.FormatUint(uint64(log.UploaderID), 10),
+				uploader,
+				strconv.FormatUint(uint64(log.AuditorID), 10),
+				auditor,
+				formatOptionalFloat(log.NSFWScore),
+				formatOptionalFloat(log.NSFWThreshold),
+				formatOptionalBool(log.IsNSFW),
+				log.Reason,
+			})
+		}
+		w.Flush()
+		return w.Error()
+	})
+	if result.Error != nil {
+		// 响应头已发送，只能记录日志
+		logger.ErrorContext(c.Request.Context(), "导出审核记录失败: %v", result.Error)
+	}
+	w.Flush()
+}
+
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
+
+func formatOptionalBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
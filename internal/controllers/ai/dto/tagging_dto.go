@@ -95,6 +95,30 @@ func (d *TriggerTaggingDTO) GetValidationMessages() map[string]string {
 	}
 }
 
+type DryRunAIConfigDTO struct {
+	FileIDs    []string `json:"file_ids" binding:"omitempty,max=20"`
+	SampleSize int      `json:"sample_size" binding:"omitempty,min=1,max=20"`
+}
+
+func (d *DryRunAIConfigDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.max":    "单次最多试运行20个文件",
+		"SampleSize.min": "样本数量至少为1",
+		"SampleSize.max": "样本数量最多为20",
+	}
+}
+
+type RequeueDeadLetterDTO struct {
+	IDs []uint `json:"ids" binding:"omitempty,max=1000"`
+	All bool   `json:"all"`
+}
+
+func (d *RequeueDeadLetterDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"IDs.max": "单次最多重新入队1000个任务",
+	}
+}
+
 func TaggingLogToResponse(log *models.FileTaggingLog) gin.H {
 	response := gin.H{
 		"id":          log.ID,
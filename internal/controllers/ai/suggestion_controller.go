@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/ai"
+	"pixelpunk/pkg/errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAISuggestions 获取当前用户待确认的AI建议
+func GetAISuggestions(c *gin.Context) {
+	type QueryParams struct {
+		FileID string `form:"file_id"`
+		Page   int    `form:"page"`
+		Limit  int    `form:"limit"`
+	}
+	var params QueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "参数错误"))
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+	result, err := ai.ListAISuggestions(userID, params.FileID, params.Page, params.Limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// AcceptAISuggestion 确认AI建议
+func AcceptAISuggestion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的建议ID"))
+		return
+	}
+	if err := ai.AcceptAISuggestion(middleware.GetCurrentUserID(c), uint(id)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, nil, "已应用AI建议")
+}
+
+// RejectAISuggestion 拒绝AI建议
+func RejectAISuggestion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的建议ID"))
+		return
+	}
+	if err := ai.RejectAISuggestion(middleware.GetCurrentUserID(c), uint(id)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, nil, "已忽略AI建议")
+}
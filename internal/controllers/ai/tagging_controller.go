@@ -9,6 +9,7 @@ import (
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/errors"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -232,6 +233,21 @@ func TriggerTagging(c *gin.Context) {
 	}, "触发打标任务成功")
 }
 
+// DryRunAIConfig 使用当前AI配置对样本文件试运行，不写入任何数据
+func DryRunAIConfig(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.DryRunAIConfigDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	result, svcErr := ai.DryRunAIConfig(req.FileIDs, req.SampleSize)
+	if svcErr != nil {
+		errors.HandleError(c, svcErr)
+		return
+	}
+	errors.ResponseSuccess(c, result, "AI配置试运行完成")
+}
+
 func GetTaggingLogs(c *gin.Context) {
 	type QueryParams struct {
 		FileID string `form:"file_id"` // 可选,过滤特定文件
@@ -267,6 +283,63 @@ func GetTaggingLogs(c *gin.Context) {
 	errors.ResponseSuccess(c, result, "获取成功")
 }
 
+// GetDeadLetterJobs 获取死信任务列表
+func GetDeadLetterJobs(c *gin.Context) {
+	type QueryParams struct {
+		FileID string `form:"file_id"`
+		Page   int    `form:"page"`
+		Limit  int    `form:"limit"`
+	}
+	var params QueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "参数错误"))
+		return
+	}
+	result, err := ai.ListDeadLetterJobs(params.FileID, params.Page, params.Limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// GetDeadLetterJobDetail 获取死信任务详情
+func GetDeadLetterJobDetail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的任务ID"))
+		return
+	}
+	result, svcErr := ai.GetDeadLetterJobDetail(uint(id))
+	if svcErr != nil {
+		errors.HandleError(c, svcErr)
+		return
+	}
+	if logs, ok := result["logs"].([]models.FileTaggingLog); ok {
+		logResponses := make([]gin.H, len(logs))
+		for i, log := range logs {
+			logResponses[i] = dto.TaggingLogToResponse(&log)
+		}
+		result["logs"] = logResponses
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// RequeueDeadLetterJobs 批量重新入队死信任务
+func RequeueDeadLetterJobs(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.RequeueDeadLetterDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	count, svcErr := ai.RequeueDeadLetterJobs(req.IDs, req.All)
+	if svcErr != nil {
+		errors.HandleError(c, svcErr)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{"requeued_count": count}, "死信任务已重新入队")
+}
+
 func GetTaggingDiagnosis(c *gin.Context) {
 	result := gin.H{
 		"queue_initialized": false,
@@ -1,13 +1,32 @@
 package dto
 
-import "pixelpunk/pkg/common"
+import (
+	"time"
+
+	"pixelpunk/pkg/common"
+)
 
 type AnnouncementCreateDTO struct {
-	Title    string `json:"title" binding:"required,max=255"`                         // 公告标题
-	Content  string `json:"content" binding:"required"`                               // 内容（Markdown格式）
-	Summary  string `json:"summary" binding:"max=500"`                                // 摘要
-	IsPinned bool   `json:"is_pinned"`                                                // 是否置顶
-	Status   string `json:"status" binding:"required,oneof=draft published archived"` // 状态
+	Title     string                  `json:"title" binding:"required,max=255"`                                   // 公告标题
+	Content   string                  `json:"content" binding:"required"`                                         // 内容（Markdown格式）
+	Summary   string                  `json:"summary" binding:"max=500"`                                          // 摘要
+	IsPinned  bool                    `json:"is_pinned"`                                                          // 是否置顶
+	Status    string                  `json:"status" binding:"required,oneof=draft scheduled published archived"` // 状态
+	PublishAt *time.Time              `json:"publish_at"`                                                         // 定时发布时间(RFC3339)，晚于当前时间时自动进入待发布状态
+	ExpireAt  *time.Time              `json:"expire_at"`                                                          // 自动下线时间(RFC3339)
+	Targets   *AnnouncementTargetsDTO `json:"targets"`                                                            // 投放对象，为空表示全部用户
+}
+
+/* AnnouncementTargetsDTO 定向公告的投放对象，三类对象取并集 */
+type AnnouncementTargetsDTO struct {
+	Roles    []int  `json:"roles" binding:"omitempty,dive,oneof=1 2 3 4"` // 角色
+	GroupIDs []uint `json:"group_ids"`                                    // 用户组ID
+	UserIDs  []uint `json:"user_ids"`                                     // 用户ID
+}
+
+/* IsEmpty 未指定任何投放对象 */
+func (t *AnnouncementTargetsDTO) IsEmpty() bool {
+	return t == nil || (len(t.Roles) == 0 && len(t.GroupIDs) == 0 && len(t.UserIDs) == 0)
 }
 
 func (d *AnnouncementCreateDTO) GetValidationMessages() map[string]string {
@@ -17,23 +36,30 @@ func (d *AnnouncementCreateDTO) GetValidationMessages() map[string]string {
 		"Content.required": "公告内容不能为空",
 		"Summary.max":      "摘要长度不能超过500个字符",
 		"Status.required":  "状态不能为空",
-		"Status.oneof":     "状态必须是draft、published或archived",
+		"Status.oneof":     "状态必须是draft、scheduled、published或archived",
+		"Roles.oneof":      "投放角色不合法",
 	}
 }
 
 type AnnouncementUpdateDTO struct {
-	Title    *string `json:"title" binding:"omitempty,max=255"`
-	Content  *string `json:"content"`
-	Summary  *string `json:"summary" binding:"omitempty,max=500"`
-	IsPinned *bool   `json:"is_pinned"`
-	Status   *string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Title          *string                 `json:"title" binding:"omitempty,max=255"`
+	Content        *string                 `json:"content"`
+	Summary        *string                 `json:"summary" binding:"omitempty,max=500"`
+	IsPinned       *bool                   `json:"is_pinned"`
+	Status         *string                 `json:"status" binding:"omitempty,oneof=draft scheduled published archived"`
+	PublishAt      *time.Time              `json:"publish_at"`
+	ExpireAt       *time.Time              `json:"expire_at"`
+	ClearPublishAt bool                    `json:"clear_publish_at"` // 清除定时发布时间
+	ClearExpireAt  bool                    `json:"clear_expire_at"`  // 清除自动下线时间
+	Targets        *AnnouncementTargetsDTO `json:"targets"`          // 投放对象，传入时整体替换，传空对象表示改为全部用户
 }
 
 func (d *AnnouncementUpdateDTO) GetValidationMessages() map[string]string {
 	return map[string]string{
 		"Title.max":    "公告标题长度不能超过255个字符",
 		"Summary.max":  "摘要长度不能超过500个字符",
-		"Status.oneof": "状态必须是draft、published或archived",
+		"Status.oneof": "状态必须是draft、scheduled、published或archived",
+		"Roles.oneof":  "投放角色不合法",
 	}
 }
 
@@ -46,16 +72,20 @@ type AnnouncementQueryDTO struct {
 }
 
 type AnnouncementResponseDTO struct {
-	ID        uint            `json:"id"`
-	Title     string          `json:"title"`
-	Content   string          `json:"content"`
-	Summary   string          `json:"summary"`
-	IsPinned  bool            `json:"is_pinned"`
-	Status    string          `json:"status"`
-	ViewCount int             `json:"view_count"`
-	CreatedBy uint            `json:"created_by"`
-	CreatedAt common.JSONTime `json:"created_at"`
-	UpdatedAt common.JSONTime `json:"updated_at"`
+	ID        uint                   `json:"id"`
+	Title     string                 `json:"title"`
+	Content   string                 `json:"content"`
+	Summary   string                 `json:"summary"`
+	IsPinned  bool                   `json:"is_pinned"`
+	Status    string                 `json:"status"`
+	ViewCount int                    `json:"view_count"`
+	CreatedBy uint                   `json:"created_by"`
+	PublishAt *common.JSONTime       `json:"publish_at"`
+	ExpireAt  *common.JSONTime       `json:"expire_at"`
+	Audience  string                 `json:"audience"`
+	Targets   AnnouncementTargetsDTO `json:"targets"`
+	CreatedAt common.JSONTime        `json:"created_at"`
+	UpdatedAt common.JSONTime        `json:"updated_at"`
 }
 
 type AnnouncementListResponseDTO struct {
@@ -71,26 +101,32 @@ type PaginationResponseDTO struct {
 }
 
 type AnnouncementSimpleDTO struct {
-	ID        uint            `json:"id"`
-	Title     string          `json:"title"`
-	Summary   string          `json:"summary"`
-	IsPinned  bool            `json:"is_pinned"`
-	ViewCount int             `json:"view_count"`
-	CreatedAt common.JSONTime `json:"created_at"`
+	ID        uint             `json:"id"`
+	Title     string           `json:"title"`
+	Summary   string           `json:"summary"`
+	IsPinned  bool             `json:"is_pinned"`
+	ViewCount int              `json:"view_count"`
+	PublishAt *common.JSONTime `json:"publish_at"`
+	ExpireAt  *common.JSONTime `json:"expire_at"`
+	IsRead    bool             `json:"is_read"` // 当前用户是否已读，匿名访问时恒为false
+	CreatedAt common.JSONTime  `json:"created_at"`
 }
 
 type AnnouncementDetailDTO struct {
-	ID        uint            `json:"id"`
-	Title     string          `json:"title"`
-	Content   string          `json:"content"`
-	Summary   string          `json:"summary"`
-	IsPinned  bool            `json:"is_pinned"`
-	ViewCount int             `json:"view_count"`
-	CreatedAt common.JSONTime `json:"created_at"`
+	ID        uint             `json:"id"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
+	Summary   string           `json:"summary"`
+	IsPinned  bool             `json:"is_pinned"`
+	ViewCount int              `json:"view_count"`
+	PublishAt *common.JSONTime `json:"publish_at"`
+	ExpireAt  *common.JSONTime `json:"expire_at"`
+	CreatedAt common.JSONTime  `json:"created_at"`
 }
 
 type PublicAnnouncementListDTO struct {
 	Announcements []AnnouncementSimpleDTO `json:"announcements"`
 	Total         int                     `json:"total"`
-	Config        map[string]interface{}  `json:"config"` // 公告系统配置
+	UnreadCount   int64                   `json:"unread_count"` // 当前用户的未读公告数，匿名访问时为0
+	Config        map[string]interface{}  `json:"config"`       // 公告系统配置
 }
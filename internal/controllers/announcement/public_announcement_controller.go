@@ -1,6 +1,7 @@
 package announcement
 
 import (
+	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/services/announcement"
 	"pixelpunk/pkg/errors"
 	"strconv"
@@ -10,7 +11,8 @@ import (
 
 func GetPublicAnnouncementListHandler(c *gin.Context) {
 	// 查询公告列表（配置由后端控制）
-	result, err := announcement.GetPublicAnnouncementList()
+	viewer := announcement.LoadViewer(middleware.GetCurrentUserID(c))
+	result, err := announcement.GetPublicAnnouncementList(viewer)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -27,7 +29,8 @@ func GetPublicAnnouncementDetailHandler(c *gin.Context) {
 		return
 	}
 
-	result, err := announcement.GetPublicAnnouncementDetail(uint(id))
+	viewer := announcement.LoadViewer(middleware.GetCurrentUserID(c))
+	result, err := announcement.GetPublicAnnouncementDetail(uint(id), viewer)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -35,3 +38,56 @@ func GetPublicAnnouncementDetailHandler(c *gin.Context) {
 
 	errors.ResponseSuccess(c, result, "获取公告详情成功")
 }
+
+func GetUnreadAnnouncementCountHandler(c *gin.Context) {
+	viewer := announcement.LoadViewer(middleware.GetCurrentUserID(c))
+	if viewer == nil {
+		errors.HandleError(c, errors.New(errors.CodeUnauthorized, "未授权"))
+		return
+	}
+
+	count, err := announcement.GetUnreadCount(viewer)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"unread_count": count}, "获取未读公告数成功")
+}
+
+func MarkAnnouncementReadHandler(c *gin.Context) {
+	viewer := announcement.LoadViewer(middleware.GetCurrentUserID(c))
+	if viewer == nil {
+		errors.HandleError(c, errors.New(errors.CodeUnauthorized, "未授权"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的公告ID"))
+		return
+	}
+
+	if err := announcement.MarkAnnouncementRead(viewer, uint(id)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已标记为已读")
+}
+
+func MarkAllAnnouncementsReadHandler(c *gin.Context) {
+	viewer := announcement.LoadViewer(middleware.GetCurrentUserID(c))
+	if viewer == nil {
+		errors.HandleError(c, errors.New(errors.CodeUnauthorized, "未授权"))
+		return
+	}
+
+	count, err := announcement.MarkAllAnnouncementsRead(viewer)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"marked": count}, "已全部标记为已读")
+}
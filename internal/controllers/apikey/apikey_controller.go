@@ -31,6 +31,10 @@ func CreateAPIKey(c *gin.Context) {
 		req.UploadCountLimit,
 		req.AllowedTypes,
 		req.FolderID,
+		req.RestrictToFolder,
+		req.Scopes,
+		req.RateLimit,
+		req.AllowedIPs,
 		req.ExpiresInDays,
 	)
 	if err != nil {
@@ -39,17 +43,21 @@ func CreateAPIKey(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"id":                 apiKeyModel.ID,
-		"name":               apiKeyModel.Name,
-		"key":                keyValue,
-		"status":             apiKeyModel.Status,
-		"storage_limit":      apiKeyModel.StorageLimit,
-		"single_file_limit":  apiKeyModel.SingleFileLimit,
-		"upload_count_limit": apiKeyModel.UploadCountLimit,
-		"allowed_types":      apikey.ParseAllowedTypes(apiKeyModel.AllowedTypes),
-		"folder_id":          apiKeyModel.FolderID,
-		"expires_at":         apiKeyModel.ExpiresAt,
-		"created_at":         apiKeyModel.CreatedAt,
+		"id":                    apiKeyModel.ID,
+		"name":                  apiKeyModel.Name,
+		"key":                   keyValue,
+		"status":                apiKeyModel.Status,
+		"storage_limit":         apiKeyModel.StorageLimit,
+		"single_file_limit":     apiKeyModel.SingleFileLimit,
+		"upload_count_limit":    apiKeyModel.UploadCountLimit,
+		"allowed_types":         apikey.ParseAllowedTypes(apiKeyModel.AllowedTypes),
+		"folder_id":             apiKeyModel.FolderID,
+		"restrict_to_folder":    apiKeyModel.RestrictToFolder,
+		"scopes":                apiKeyModel.ScopeList(),
+		"rate_limit_per_minute": apiKeyModel.RateLimitPerMinute,
+		"allowed_ips":           apikey.ParseAllowedIPs(apiKeyModel.AllowedIPs),
+		"expires_at":            apiKeyModel.ExpiresAt,
+		"created_at":            apiKeyModel.CreatedAt,
 	}
 
 	// 记录API密钥创建活动日志
@@ -100,24 +108,28 @@ func GetAPIKeyList(c *gin.Context) {
 		folderPath := apikey.GetFolderFullPath(userID, key.FolderID)
 
 		items = append(items, gin.H{
-			"id":                 key.ID,
-			"key":                key.KeyValue, // 添加API密钥本身
-			"name":               key.Name,
-			"status":             key.Status,
-			"status_text":        getStatusText(key.Status),
-			"is_active":          key.IsActive(),
-			"storage_limit":      key.StorageLimit,
-			"storage_used":       key.StorageUsed,
-			"upload_count_limit": key.UploadCountLimit,
-			"upload_count_used":  key.UploadCountUsed,
-			"single_file_limit":  key.SingleFileLimit,
-			"folder_id":          key.FolderID,
-			"folder_path":        folderPath,
-			"allowed_types":      apikey.ParseAllowedTypes(key.AllowedTypes),
-			"is_expired":         key.IsExpired(),
-			"expires_at":         key.ExpiresAt,
-			"last_used_at":       key.LastUsedAt,
-			"created_at":         key.CreatedAt,
+			"id":                    key.ID,
+			"key":                   key.KeyValue, // 添加API密钥本身
+			"name":                  key.Name,
+			"status":                key.Status,
+			"status_text":           getStatusText(key.Status),
+			"is_active":             key.IsActive(),
+			"storage_limit":         key.StorageLimit,
+			"storage_used":          key.StorageUsed,
+			"upload_count_limit":    key.UploadCountLimit,
+			"upload_count_used":     key.UploadCountUsed,
+			"single_file_limit":     key.SingleFileLimit,
+			"folder_id":             key.FolderID,
+			"restrict_to_folder":    key.RestrictToFolder,
+			"scopes":                key.ScopeList(),
+			"rate_limit_per_minute": key.RateLimitPerMinute,
+			"allowed_ips":           apikey.ParseAllowedIPs(key.AllowedIPs),
+			"folder_path":           folderPath,
+			"allowed_types":         apikey.ParseAllowedTypes(key.AllowedTypes),
+			"is_expired":            key.IsExpired(),
+			"expires_at":            key.ExpiresAt,
+			"last_used_at":          key.LastUsedAt,
+			"created_at":            key.CreatedAt,
 		})
 	}
 
@@ -152,24 +164,30 @@ func GetAPIKeyDetail(c *gin.Context) {
 	folderPath := apikey.GetFolderFullPath(userID, key.FolderID)
 
 	response := gin.H{
-		"id":                 key.ID,
-		"name":               key.Name,
-		"status":             key.Status,
-		"status_text":        getStatusText(key.Status),
-		"is_active":          key.IsActive(),
-		"storage_limit":      key.StorageLimit,
-		"storage_used":       key.StorageUsed,
-		"upload_count_limit": key.UploadCountLimit,
-		"upload_count_used":  key.UploadCountUsed,
-		"single_file_limit":  key.SingleFileLimit,
-		"folder_id":          key.FolderID,
-		"folder_path":        folderPath,
-		"allowed_types":      apikey.ParseAllowedTypes(key.AllowedTypes),
-		"is_expired":         key.IsExpired(),
-		"expires_at":         key.ExpiresAt,
-		"last_used_at":       key.LastUsedAt,
-		"created_at":         key.CreatedAt,
-		"updated_at":         key.UpdatedAt,
+		"id":                    key.ID,
+		"name":                  key.Name,
+		"status":                key.Status,
+		"status_text":           getStatusText(key.Status),
+		"is_active":             key.IsActive(),
+		"storage_limit":         key.StorageLimit,
+		"storage_used":          key.StorageUsed,
+		"upload_count_limit":    key.UploadCountLimit,
+		"upload_count_used":     key.UploadCountUsed,
+		"single_file_limit":     key.SingleFileLimit,
+		"folder_id":             key.FolderID,
+		"restrict_to_folder":    key.RestrictToFolder,
+		"scopes":                key.ScopeList(),
+		"rate_limit_per_minute": key.RateLimitPerMinute,
+		"allowed_ips":           apikey.ParseAllowedIPs(key.AllowedIPs),
+		"request_count":         key.RequestCount,
+		"last_used_ip":          key.LastUsedIP,
+		"folder_path":           folderPath,
+		"allowed_types":         apikey.ParseAllowedTypes(key.AllowedTypes),
+		"is_expired":            key.IsExpired(),
+		"expires_at":            key.ExpiresAt,
+		"last_used_at":          key.LastUsedAt,
+		"created_at":            key.CreatedAt,
+		"updated_at":            key.UpdatedAt,
 	}
 
 	errors.ResponseSuccess(c, response, "获取API密钥详情成功")
@@ -212,6 +230,18 @@ func UpdateAPIKey(c *gin.Context) {
 	if req.FolderID != "" {
 		updates["folder_id"] = req.FolderID
 	}
+	if req.RestrictToFolder != nil {
+		updates["restrict_to_folder"] = *req.RestrictToFolder
+	}
+	if req.Scopes != nil {
+		updates["scopes"] = req.Scopes
+	}
+	if req.RateLimit != nil {
+		updates["rate_limit_per_minute"] = *req.RateLimit
+	}
+	if req.AllowedIPs != nil {
+		updates["allowed_ips"] = req.AllowedIPs
+	}
 	if c.Request.Method == "PUT" || c.PostForm("expires_in_days") != "" || c.Request.Header.Get("Content-Type") == "application/json" {
 		updates["expires_in_days"] = req.ExpiresInDays
 	}
@@ -223,21 +253,25 @@ func UpdateAPIKey(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"id":                 updatedKey.ID,
-		"name":               updatedKey.Name,
-		"status":             updatedKey.Status,
-		"status_text":        getStatusText(updatedKey.Status),
-		"is_active":          updatedKey.IsActive(),
-		"storage_limit":      updatedKey.StorageLimit,
-		"storage_used":       updatedKey.StorageUsed,
-		"upload_count_limit": updatedKey.UploadCountLimit,
-		"upload_count_used":  updatedKey.UploadCountUsed,
-		"single_file_limit":  updatedKey.SingleFileLimit,
-		"folder_id":          updatedKey.FolderID,
-		"allowed_types":      apikey.ParseAllowedTypes(updatedKey.AllowedTypes),
-		"is_expired":         updatedKey.IsExpired(),
-		"expires_at":         updatedKey.ExpiresAt,
-		"updated_at":         updatedKey.UpdatedAt,
+		"id":                    updatedKey.ID,
+		"name":                  updatedKey.Name,
+		"status":                updatedKey.Status,
+		"status_text":           getStatusText(updatedKey.Status),
+		"is_active":             updatedKey.IsActive(),
+		"storage_limit":         updatedKey.StorageLimit,
+		"storage_used":          updatedKey.StorageUsed,
+		"upload_count_limit":    updatedKey.UploadCountLimit,
+		"upload_count_used":     updatedKey.UploadCountUsed,
+		"single_file_limit":     updatedKey.SingleFileLimit,
+		"folder_id":             updatedKey.FolderID,
+		"restrict_to_folder":    updatedKey.RestrictToFolder,
+		"scopes":                updatedKey.ScopeList(),
+		"rate_limit_per_minute": updatedKey.RateLimitPerMinute,
+		"allowed_ips":           apikey.ParseAllowedIPs(updatedKey.AllowedIPs),
+		"allowed_types":         apikey.ParseAllowedTypes(updatedKey.AllowedTypes),
+		"is_expired":            updatedKey.IsExpired(),
+		"expires_at":            updatedKey.ExpiresAt,
+		"updated_at":            updatedKey.UpdatedAt,
 	}
 
 	errors.ResponseSuccess(c, response, "更新API密钥成功")
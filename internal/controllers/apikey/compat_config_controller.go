@@ -0,0 +1,65 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/apikey"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadShareXConfig 下载按密钥生成的 ShareX 自定义上传器配置
+func DownloadShareXConfig(c *gin.Context) {
+	key, ok := getUploadKey(c)
+	if !ok {
+		return
+	}
+	sendConfigFile(c, key.Name+".sxcu", apikey.BuildShareXConfig(key))
+}
+
+// DownloadPicGoConfig 下载按密钥生成的 PicGo 配置片段
+func DownloadPicGoConfig(c *gin.Context) {
+	key, ok := getUploadKey(c)
+	if !ok {
+		return
+	}
+	sendConfigFile(c, key.Name+".picgo.json", apikey.BuildPicGoConfig(key))
+}
+
+// getUploadKey 获取当前用户拥有上传权限的密钥，失败时已写入错误响应
+func getUploadKey(c *gin.Context) (*models.APIKey, bool) {
+	userID := middleware.GetCurrentUserID(c)
+
+	keyID := c.Param("key_id")
+	if keyID == "" {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "API密钥ID不能为空"))
+		return nil, false
+	}
+
+	key, err := apikey.GetAPIKey(userID, keyID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return nil, false
+	}
+	if err := apikey.CheckScope(key, models.APIKeyScopeUpload); err != nil {
+		errors.HandleError(c, err)
+		return nil, false
+	}
+	return key, true
+}
+
+func sendConfigFile(c *gin.Context, filename string, config interface{}) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeInternal, "生成配置文件失败"))
+		return
+	}
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
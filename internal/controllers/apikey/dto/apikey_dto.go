@@ -7,6 +7,10 @@ type CreateAPIKeyDTO struct {
 	UploadCountLimit int      `json:"upload_count_limit" binding:"omitempty,min=0"`
 	AllowedTypes     []string `json:"allowed_types" binding:"omitempty"`
 	FolderID         string   `json:"folder_id" binding:"omitempty"`
+	RestrictToFolder bool     `json:"restrict_to_folder"`                                                   // 限制所有操作在 folder_id 目录内
+	Scopes           []string `json:"scopes" binding:"omitempty,dive,oneof=upload read delete share admin"` // 授权范围，为空时仅允许上传
+	RateLimit        int      `json:"rate_limit_per_minute" binding:"omitempty,min=0,max=100000"`           // 每分钟请求次数限制，0表示不限制
+	AllowedIPs       []string `json:"allowed_ips" binding:"omitempty,max=50"`                               // IP白名单，支持CIDR
 	ExpiresInDays    int      `json:"expires_in_days" binding:"omitempty,min=0"`
 }
 
@@ -19,6 +23,10 @@ func (d *CreateAPIKeyDTO) GetValidationMessages() map[string]string {
 		"SingleFileLimit.min":  "单文件大小限制不能为负数",
 		"UploadCountLimit.min": "上传次数限制不能为负数",
 		"ExpiresInDays.min":    "有效天数不能为负数",
+		"Scopes.oneof":         "授权范围无效，可选值: upload/read/delete/share/admin",
+		"RateLimit.min":        "请求频率限制不能为负数",
+		"RateLimit.max":        "请求频率限制不能超过每分钟100000次",
+		"AllowedIPs.max":       "IP白名单最多50条",
 	}
 }
 
@@ -29,6 +37,10 @@ type UpdateAPIKeyDTO struct {
 	UploadCountLimit int      `json:"upload_count_limit" binding:"omitempty,min=0"`
 	AllowedTypes     []string `json:"allowed_types" binding:"omitempty"`
 	FolderID         string   `json:"folder_id" binding:"omitempty"`
+	RestrictToFolder *bool    `json:"restrict_to_folder"`
+	Scopes           []string `json:"scopes" binding:"omitempty,dive,oneof=upload read delete share admin"`
+	RateLimit        *int     `json:"rate_limit_per_minute" binding:"omitempty,min=0,max=100000"`
+	AllowedIPs       []string `json:"allowed_ips" binding:"omitempty,max=50"`
 	ExpiresInDays    int      `json:"expires_in_days" binding:"omitempty,min=0"`
 	Status           int      `json:"status" binding:"omitempty,oneof=1 2"`
 }
@@ -42,6 +54,10 @@ func (d *UpdateAPIKeyDTO) GetValidationMessages() map[string]string {
 		"UploadCountLimit.min": "上传次数限制不能为负数",
 		"ExpiresInDays.min":    "有效天数不能为负数",
 		"Status.oneof":         "状态值无效，应为1(启用)或2(禁用)",
+		"Scopes.oneof":         "授权范围无效，可选值: upload/read/delete/share/admin",
+		"RateLimit.min":        "请求频率限制不能为负数",
+		"RateLimit.max":        "请求频率限制不能超过每分钟100000次",
+		"AllowedIPs.max":       "IP白名单最多50条",
 	}
 }
 
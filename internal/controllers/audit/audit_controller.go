@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/controllers/audit/dto"
+	auditService "pixelpunk/internal/services/audit"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* ListAuditLogs 搜索管理员审计日志 */
+func ListAuditLogs(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.AuditLogQueryDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	params := auditService.QueryParams{
+		ActorID:    req.ActorID,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		IP:         req.IP,
+		Keyword:    req.Keyword,
+		Success:    req.Success,
+		Page:       req.Page,
+		Size:       req.Size,
+	}
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Size <= 0 {
+		params.Size = 20
+	}
+	if req.StartTime != "" {
+		t, ok := parseQueryTime(req.StartTime, false)
+		if !ok {
+			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "开始时间格式不正确"))
+			return
+		}
+		params.StartTime = &t
+	}
+	if req.EndTime != "" {
+		t, ok := parseQueryTime(req.EndTime, true)
+		if !ok {
+			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "结束时间格式不正确"))
+			return
+		}
+		params.EndTime = &t
+	}
+
+	logs, total, err := auditService.List(params)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"items": logs,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        params.Page,
+			"size":        params.Size,
+			"total_pages": (total + int64(params.Size) - 1) / int64(params.Size),
+		},
+	}, "获取审计日志成功")
+}
+
+/* GetAuditLog 获取审计日志详情 */
+func GetAuditLog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的日志ID"))
+		return
+	}
+
+	log, err := auditService.Get(uint(id))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, log, "获取审计日志成功")
+}
+
+// parseQueryTime 支持精确到秒或只传日期，只传日期的结束时间取当天末尾
+func parseQueryTime(value string, endOfDay bool) (time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, true
+}
@@ -0,0 +1,28 @@
+package dto
+
+type AuditLogQueryDTO struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Size       int    `form:"size" binding:"omitempty,min=1,max=100"`
+	ActorID    uint   `form:"actor_id"`
+	Action     string `form:"action" binding:"omitempty,max=100"` // 按前缀匹配，如 user. 匹配全部用户操作
+	TargetType string `form:"target_type" binding:"omitempty,max=50"`
+	TargetID   string `form:"target_id" binding:"omitempty,max=100"`
+	IP         string `form:"ip" binding:"omitempty,max=45"`
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"`
+	Success    *bool  `form:"success"`
+	StartTime  string `form:"start_time"` // 格式 2006-01-02 15:04:05 或 2006-01-02
+	EndTime    string `form:"end_time"`
+}
+
+func (d *AuditLogQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":       "页码必须大于0",
+		"Size.min":       "每页数量必须大于0",
+		"Size.max":       "每页数量不能超过100",
+		"Action.max":     "操作标识过长",
+		"TargetType.max": "对象类型过长",
+		"TargetID.max":   "对象ID过长",
+		"IP.max":         "IP格式不正确",
+		"Keyword.max":    "关键词不能超过100个字符",
+	}
+}
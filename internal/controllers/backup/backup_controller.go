@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"io"
+	"path"
+
+	"pixelpunk/internal/controllers/backup/dto"
+	backupService "pixelpunk/internal/services/backup"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* GetBackupStatus 获取备份设置与最近一次运行状态 */
+func GetBackupStatus(c *gin.Context) {
+	errors.ResponseSuccess(c, gin.H{
+		"config": backupService.GetConfig(),
+		"status": backupService.GetStatus(),
+	}, "获取备份状态成功")
+}
+
+/* ListBackups 列出目标渠道中的备份 */
+func ListBackups(c *gin.Context) {
+	backups, err := backupService.List()
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, backups, "获取备份列表成功")
+}
+
+/* CreateBackup 立即在后台执行一次备份 */
+func CreateBackup(c *gin.Context) {
+	if err := backupService.Start(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, backupService.GetStatus(), "备份已开始")
+}
+
+/* DownloadBackup 下载备份文件 */
+func DownloadBackup(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.BackupKeyDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	rc, err := backupService.Open(c.Request.Context(), req.Key)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", `attachment; filename="`+path.Base(req.Key)+`"`)
+	c.Status(200)
+	if _, err := io.Copy(c.Writer, rc); err != nil {
+		logger.WarnContext(c.Request.Context(), "下载备份中断: %s, %v", req.Key, err)
+	}
+}
+
+/* DeleteBackup 删除备份文件 */
+func DeleteBackup(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.BackupKeyDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := backupService.Delete(req.Key); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, nil, "删除备份成功")
+}
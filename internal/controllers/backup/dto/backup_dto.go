@@ -0,0 +1,12 @@
+package dto
+
+type BackupKeyDTO struct {
+	Key string `form:"key" json:"key" binding:"required,max=512"`
+}
+
+func (d *BackupKeyDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Key.required": "请指定备份文件",
+		"Key.max":      "备份文件名过长",
+	}
+}
@@ -0,0 +1,80 @@
+package cron
+
+import (
+	"pixelpunk/internal/controllers/cron/dto"
+	cronMgr "pixelpunk/internal/cron"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* ListCronTasks 列出定时任务及其执行周期、启停状态与下次执行时间 */
+func ListCronTasks(c *gin.Context) {
+	errors.ResponseSuccess(c, cronMgr.ListTasks(), "获取成功")
+}
+
+/* UpdateCronTask 修改定时任务的执行周期或启停状态 */
+func UpdateCronTask(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UpdateCronTaskDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	info, err := cronMgr.UpdateTask(c.Param("name"), req.Schedule, req.Enabled)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, info, "更新成功")
+}
+
+/* RunCronTask 立即执行一次定时任务 */
+func RunCronTask(c *gin.Context) {
+	if err := cronMgr.RunTask(c.Param("name")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, nil, "任务已开始执行")
+}
+
+/* ListCronHistory 分页查询定时任务执行历史，可按任务名与状态筛选 */
+func ListCronHistory(c *gin.Context) {
+	type QueryParams struct {
+		Name   string `form:"name"`
+		Status string `form:"status"`
+		Page   int    `form:"page"`
+		Limit  int    `form:"limit"`
+	}
+	var params QueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "参数错误"))
+		return
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	runs, total, err := cronMgr.ListHistory(cronMgr.HistoryQuery{
+		TaskName: params.Name,
+		Status:   params.Status,
+		Offset:   (params.Page - 1) * params.Limit,
+		Limit:    params.Limit,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{
+		"runs": runs,
+		"pagination": gin.H{
+			"page":  params.Page,
+			"limit": params.Limit,
+			"total": total,
+		},
+	}, "获取成功")
+}
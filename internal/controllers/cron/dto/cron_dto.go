@@ -0,0 +1,13 @@
+package dto
+
+// UpdateCronTaskDTO 修改定时任务，字段为空表示不修改，schedule 传空字符串恢复默认周期
+type UpdateCronTaskDTO struct {
+	Schedule *string `json:"schedule" binding:"omitempty,max=100"`
+	Enabled  *bool   `json:"enabled"`
+}
+
+func (d *UpdateCronTaskDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Schedule.max": "执行周期长度不能超过100个字符",
+	}
+}
@@ -0,0 +1,231 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimeDebug "runtime/debug"
+	"runtime/metrics"
+	rpprof "runtime/pprof"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"pixelpunk/internal/controllers/debug/dto"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	startTime = time.Now()
+
+	// 当前的阻塞与锁竞争采样设置，runtime 未提供读取接口，在此记录
+	blockRate     atomic.Int64
+	mutexFraction atomic.Int64
+)
+
+/* PprofIndex pprof 首页，列出可用的 profile */
+func PprofIndex(c *gin.Context) {
+	pprof.Index(c.Writer, c.Request)
+}
+
+/* PprofCmdline 进程启动参数 */
+func PprofCmdline(c *gin.Context) {
+	pprof.Cmdline(c.Writer, c.Request)
+}
+
+/* PprofProfile CPU profile，seconds 参数指定采样时长，默认 30 秒 */
+func PprofProfile(c *gin.Context) {
+	pprof.Profile(c.Writer, c.Request)
+}
+
+/* PprofSymbol 符号查询，供 go tool pprof 使用 */
+func PprofSymbol(c *gin.Context) {
+	pprof.Symbol(c.Writer, c.Request)
+}
+
+/* PprofTrace 执行追踪，seconds 参数指定采样时长 */
+func PprofTrace(c *gin.Context) {
+	pprof.Trace(c.Writer, c.Request)
+}
+
+/* PprofLookup 按名称输出 heap、goroutine、allocs、block、mutex 等 profile */
+func PprofLookup(c *gin.Context) {
+	name := c.Param("name")
+	if rpprof.Lookup(name) == nil {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, "profile 不存在: "+name))
+		return
+	}
+	pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+}
+
+/* GoroutineDump 以文本形式输出所有 goroutine 的完整调用栈 */
+func GoroutineDump(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=goroutines-"+time.Now().Format("20060102-150405")+".txt")
+	c.Status(http.StatusOK)
+	rpprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+}
+
+// RuntimeStats 运行时与 GC 统计
+type RuntimeStats struct {
+	GoVersion     string          `json:"go_version"`
+	NumCPU        int             `json:"num_cpu"`
+	GOMAXPROCS    int             `json:"gomaxprocs"`
+	NumGoroutine  int             `json:"num_goroutine"`
+	NumCgoCall    int64           `json:"num_cgo_call"`
+	UptimeSeconds int64           `json:"uptime_seconds"`
+	Memory        MemoryStats     `json:"memory"`
+	GC            GCStats         `json:"gc"`
+	Profiling     ProfilingStatus `json:"profiling"`
+}
+
+// MemoryStats 内存统计，单位字节
+type MemoryStats struct {
+	Alloc          uint64 `json:"alloc"`
+	AllocFormatted string `json:"alloc_formatted"`
+	TotalAlloc     uint64 `json:"total_alloc"`
+	Sys            uint64 `json:"sys"`
+	SysFormatted   string `json:"sys_formatted"`
+	HeapAlloc      uint64 `json:"heap_alloc"`
+	HeapInuse      uint64 `json:"heap_inuse"`
+	HeapIdle       uint64 `json:"heap_idle"`
+	HeapReleased   uint64 `json:"heap_released"`
+	HeapObjects    uint64 `json:"heap_objects"`
+	StackInuse     uint64 `json:"stack_inuse"`
+	Mallocs        uint64 `json:"mallocs"`
+	Frees          uint64 `json:"frees"`
+}
+
+// GCStats 垃圾回收统计
+type GCStats struct {
+	NumGC          uint32             `json:"num_gc"`
+	NumForcedGC    uint32             `json:"num_forced_gc"`
+	LastGC         *common.JSONTime   `json:"last_gc"`
+	NextGC         uint64             `json:"next_gc"`
+	PauseTotalMs   float64            `json:"pause_total_ms"`
+	RecentPauseMs  []float64          `json:"recent_pause_ms"` // 最近的停顿时间，最新的在前
+	PausePercentMs map[string]float64 `json:"pause_percentiles_ms"`
+	CPUFraction    float64            `json:"gc_cpu_fraction"`
+	GOGC           int                `json:"gogc"`
+	MemoryLimit    int64              `json:"memory_limit"`
+}
+
+// ProfilingStatus 阻塞与锁竞争采样设置
+type ProfilingStatus struct {
+	BlockRate     int64 `json:"block_rate"`
+	MutexFraction int64 `json:"mutex_fraction"`
+}
+
+/* GetRuntimeStats 获取运行时、内存与 GC 统计 */
+func GetRuntimeStats(c *gin.Context) {
+	errors.ResponseSuccess(c, collectRuntimeStats(), "获取运行时统计成功")
+}
+
+/* ForceGC 立即执行 GC 并归还内存给操作系统，返回回收前后的统计 */
+func ForceGC(c *gin.Context) {
+	before := collectRuntimeStats()
+	start := time.Now()
+	runtimeDebug.FreeOSMemory()
+	duration := time.Since(start)
+	after := collectRuntimeStats()
+
+	errors.ResponseSuccess(c, gin.H{
+		"duration_ms": float64(duration.Microseconds()) / 1000,
+		"freed":       int64(before.Memory.HeapAlloc) - int64(after.Memory.HeapAlloc),
+		"before":      before.Memory,
+		"after":       after.Memory,
+	}, "GC 已执行")
+}
+
+/* UpdateProfilingRate 开启或关闭阻塞与锁竞争采样，采样会带来额外开销，排查完成后应关闭 */
+func UpdateProfilingRate(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.ProfilingRateDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if req.BlockRate != nil {
+		runtime.SetBlockProfileRate(*req.BlockRate)
+		blockRate.Store(int64(*req.BlockRate))
+	}
+	if req.MutexFraction != nil {
+		runtime.SetMutexProfileFraction(*req.MutexFraction)
+		mutexFraction.Store(int64(*req.MutexFraction))
+	}
+
+	errors.ResponseSuccess(c, ProfilingStatus{
+		BlockRate:     blockRate.Load(),
+		MutexFraction: mutexFraction.Load(),
+	}, "采样设置已更新")
+}
+
+func collectRuntimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	gc := runtimeDebug.GCStats{PauseQuantiles: make([]time.Duration, 5)}
+	runtimeDebug.ReadGCStats(&gc)
+
+	stats := RuntimeStats{
+		GoVersion:     runtime.Version(),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumGoroutine:  runtime.NumGoroutine(),
+		NumCgoCall:    runtime.NumCgoCall(),
+		UptimeSeconds: int64(time.Since(startTime).Seconds()),
+		Memory: MemoryStats{
+			Alloc:          mem.Alloc,
+			AllocFormatted: utils.FormatBytes(int64(mem.Alloc)),
+			TotalAlloc:     mem.TotalAlloc,
+			Sys:            mem.Sys,
+			SysFormatted:   utils.FormatBytes(int64(mem.Sys)),
+			HeapAlloc:      mem.HeapAlloc,
+			HeapInuse:      mem.HeapInuse,
+			HeapIdle:       mem.HeapIdle,
+			HeapReleased:   mem.HeapReleased,
+			HeapObjects:    mem.HeapObjects,
+			StackInuse:     mem.StackInuse,
+			Mallocs:        mem.Mallocs,
+			Frees:          mem.Frees,
+		},
+		GC: GCStats{
+			NumGC:        mem.NumGC,
+			NumForcedGC:  mem.NumForcedGC,
+			NextGC:       mem.NextGC,
+			PauseTotalMs: float64(mem.PauseTotalNs) / 1e6,
+			CPUFraction:  mem.GCCPUFraction,
+		},
+		Profiling: ProfilingStatus{
+			BlockRate:     blockRate.Load(),
+			MutexFraction: mutexFraction.Load(),
+		},
+	}
+
+	samples := []metrics.Sample{{Name: "/gc/gogc:percent"}, {Name: "/gc/gomemlimit:bytes"}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		stats.GC.GOGC = int(samples[0].Value.Uint64())
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		stats.GC.MemoryLimit = int64(samples[1].Value.Uint64())
+	}
+	if mem.LastGC > 0 {
+		last := common.JSONTime(time.Unix(0, int64(mem.LastGC)))
+		stats.GC.LastGC = &last
+	}
+	for i := 0; i < len(gc.Pause) && i < 10; i++ {
+		stats.GC.RecentPauseMs = append(stats.GC.RecentPauseMs, float64(gc.Pause[i].Microseconds())/1000)
+	}
+	stats.GC.PausePercentMs = make(map[string]float64, len(gc.PauseQuantiles))
+	if len(gc.Pause) > 0 {
+		for i, q := range gc.PauseQuantiles {
+			stats.GC.PausePercentMs["p"+strconv.Itoa(i*25)] = float64(q.Microseconds()) / 1000
+		}
+	}
+	return stats
+}
@@ -0,0 +1,13 @@
+package dto
+
+type ProfilingRateDTO struct {
+	BlockRate     *int `json:"block_rate" binding:"omitempty,min=0"`     // 阻塞采样间隔(纳秒)，0 关闭，1 记录全部
+	MutexFraction *int `json:"mutex_fraction" binding:"omitempty,min=0"` // 锁竞争采样比例 1/n，0 关闭
+}
+
+func (d *ProfilingRateDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"BlockRate.min":     "阻塞采样间隔不能小于0",
+		"MutexFraction.min": "锁竞争采样比例不能小于0",
+	}
+}
@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	feedService "pixelpunk/internal/services/feed"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/feed"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 订阅阅读器通常定时轮询，允许代理与客户端短时间缓存
+const feedCacheControl = "public, max-age=600"
+
+/* GetAuthorFeed 作者公开文件订阅源，格式为 rss、atom 或 json */
+func GetAuthorFeed(c *gin.Context) {
+	authorID, err := strconv.ParseUint(c.Param("author_id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的作者ID"))
+		return
+	}
+	serveFeed(c, func(feedURL string) (*feed.Feed, error) {
+		return feedService.AuthorFeed(uint(authorID), feedURL)
+	})
+}
+
+/* GetRecommendedFeed 全站推荐文件订阅源 */
+func GetRecommendedFeed(c *gin.Context) {
+	serveFeed(c, feedService.RecommendedFeed)
+}
+
+func serveFeed(c *gin.Context, build func(feedURL string) (*feed.Feed, error)) {
+	if !feedService.IsEnabled() {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, "订阅功能未开启"))
+		return
+	}
+	format, ok := feed.ParseFormat(c.Param("format"))
+	if !ok {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "不支持的订阅格式，可选 rss、atom、json"))
+		return
+	}
+
+	f, err := build(utils.GetBaseUrl() + c.Request.URL.Path)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", feedCacheControl)
+	if !f.Updated.IsZero() {
+		lastModified := f.Updated.UTC().Truncate(time.Second)
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	body, err := f.Encode(format)
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeInternal, "生成订阅源失败"))
+		return
+	}
+	c.Data(http.StatusOK, format.ContentType(), body)
+}
@@ -39,6 +39,11 @@ type AdminGetFileListParams struct {
 	MaxHeight     int     `form:"max_height" json:"max_height"`
 	UserID        uint    `form:"user_id" json:"user_id"`
 	IsRecommended *bool   `form:"is_recommended" json:"is_recommended"`
+
+	Status            string `form:"status" json:"status"`
+	Format            string `form:"format" json:"format"`
+	StorageProviderID string `form:"storage_provider_id" json:"storage_provider_id"` // 存储渠道ID
+	Facets            bool   `form:"facets" json:"facets"`                           // 是否返回分面统计
 }
 
 // AdminGetFileList 管理员获取文件列表
@@ -96,6 +101,10 @@ func AdminGetFileList(c *gin.Context) {
 		MaxHeight:     params.MaxHeight,
 		UserID:        params.UserID,
 		IsRecommended: params.IsRecommended,
+
+		Status:            params.Status,
+		Format:            params.Format,
+		StorageProviderID: params.StorageProviderID,
 	}
 
 	files, total, err := filesvc.AdminGetFileList(searchParams)
@@ -114,6 +123,15 @@ func AdminGetFileList(c *gin.Context) {
 		},
 	}
 
+	if params.Facets {
+		facets, err := filesvc.AdminGetFileFacets(searchParams)
+		if err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+		data["facets"] = facets
+	}
+
 	errors.ResponseSuccess(c, data, "获取管理员文件列表成功")
 }
 
@@ -239,6 +257,11 @@ func AdminDeleteFile(c *gin.Context) {
 
 	// 记录管理员删除活动日志
 	activity.LogAdminDelete(fileRecord.UserID, req.FileID, adminID)
+	middleware.SetAuditChange(c, "file.delete", "file", req.FileID, map[string]interface{}{
+		"user_id":       fileRecord.UserID,
+		"original_name": fileRecord.OriginalName,
+		"size":          fileRecord.Size,
+	}, nil)
 
 	// 异步发送管理员删除文件通知
 	go sendAdminDeleteNotification(fileRecord.UserID, fileRecord.ID, fileRecord.OriginalName)
@@ -0,0 +1,115 @@
+package file
+
+import (
+	"fmt"
+
+	"pixelpunk/internal/controllers/file/dto"
+	"pixelpunk/internal/middleware"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetTrashList 管理员查看所有用户回收站中的文件
+func AdminGetTrashList(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.AdminTrashListDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Size <= 0 {
+		req.Size = filesvc.DefaultPageSize
+	}
+
+	params := filesvc.AdminTrashSearchParams{
+		Page:              req.Page,
+		Size:              req.Size,
+		Keyword:           req.Keyword,
+		UserID:            req.UserID,
+		StorageProviderID: req.StorageProviderID,
+		Sort:              req.Sort,
+	}
+	if !req.StartDate.IsZero() {
+		params.TrashedFrom = &req.StartDate
+	}
+	if !req.EndDate.IsZero() {
+		end := req.EndDate.AddDate(0, 0, 1)
+		params.TrashedTo = &end
+	}
+
+	items, total, err := filesvc.AdminGetTrashList(params)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"items":          items,
+		"retention_days": filesvc.GetTrashRetentionDays(),
+		"pagination": gin.H{
+			"total":       total,
+			"page":        req.Page,
+			"size":        req.Size,
+			"total_pages": (total + int64(req.Size) - 1) / int64(req.Size),
+		},
+	}, "获取回收站文件成功")
+}
+
+// AdminRestoreTrashFiles 批量恢复回收站文件
+func AdminRestoreTrashFiles(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.AdminTrashBatchDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := filesvc.AdminRestoreTrashFiles(req.FileIDs)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if result.SuccessCount > 0 {
+		middleware.SetAuditChange(c, "file.trash_restore", "file", "", nil, gin.H{"file_ids": result.SuccessIDs})
+	}
+
+	errors.ResponseSuccess(c, result, fmt.Sprintf("恢复完成，成功 %d 个，失败 %d 个", result.SuccessCount, result.FailCount))
+}
+
+// AdminPurgeTrashFiles 批量彻底删除回收站文件
+func AdminPurgeTrashFiles(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.AdminTrashBatchDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := filesvc.AdminPurgeTrashFiles(req.FileIDs)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if result.SuccessCount > 0 {
+		middleware.SetAuditChange(c, "file.trash_purge", "file", "", gin.H{"file_ids": result.SuccessIDs}, nil)
+	}
+
+	errors.ResponseSuccess(c, result, fmt.Sprintf("彻底删除完成，成功 %d 个，失败 %d 个", result.SuccessCount, result.FailCount))
+}
+
+// AdminPurgeTrashFile 立即彻底删除单个回收站文件
+func AdminPurgeTrashFile(c *gin.Context) {
+	fileID := c.Param("id")
+	if err := filesvc.AdminPurgeTrashFile(fileID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	middleware.SetAuditChange(c, "file.trash_purge", "file", fileID, gin.H{"file_ids": []string{fileID}}, nil)
+	errors.ResponseSuccess(c, nil, "彻底删除成功")
+}
@@ -1,8 +1,8 @@
 package file
 
 import (
-	"net/http"
 	"pixelpunk/internal/cron"
+	filesvc "pixelpunk/internal/services/file"
 	"pixelpunk/pkg/errors"
 
 	"github.com/gin-gonic/gin"
@@ -12,24 +12,22 @@ import (
 func ManualCleanupChunkedUploads(c *gin.Context) {
 	cleanupJob := cron.NewChunkedUploadCleanupJob()
 
-	if err := cleanupJob.Execute(); err != nil {
+	result, err := cleanupJob.Execute()
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	errors.ResponseSuccess(c, nil, "分片上传清理任务执行完成")
+	errors.ResponseSuccess(c, result, "分片上传清理任务执行完成")
 }
 
+// GetChunkedUploadStats 获取未完成的分片上传会话统计
 func GetChunkedUploadStats(c *gin.Context) {
-	// 这里可以添加获取分片上传统计信息的逻辑
-	// 比如当前活跃会话数、过期会话数等
-	stats := gin.H{
-		"message": "分片上传统计功能即将推出",
+	stats, err := filesvc.GetUploadSessionStats()
+	if err != nil {
+		errors.HandleError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
-		"message": "获取统计信息成功",
-	})
+	errors.ResponseSuccess(c, stats, "获取统计信息成功")
 }
@@ -232,3 +232,25 @@ func (d *ReorderFilesRequest) GetValidationMessages() map[string]string {
 		"FileIDs.min":      "至少需要一个文件",
 	}
 }
+
+// DuplicateListRequest 近似重复文件列表请求DTO
+type DuplicateListRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending ignored resolved"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+func (d *DuplicateListRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Status.oneof": "状态只能是 pending、ignored 或 resolved",
+	}
+}
+
+// ResolveDuplicateRequest 处理近似重复文件请求DTO
+type ResolveDuplicateRequest struct {
+	DeleteFileID string `json:"delete_file_id"` // 要删除的文件ID，为空时仅标记为已处理
+}
+
+func (d *ResolveDuplicateRequest) GetValidationMessages() map[string]string {
+	return map[string]string{}
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// AdminTrashListDTO 管理员回收站查询参数
+type AdminTrashListDTO struct {
+	Page              int       `form:"page" binding:"omitempty,min=1"`
+	Size              int       `form:"size" binding:"omitempty,min=1,max=100"`
+	Keyword           string    `form:"keyword" binding:"max=100"`
+	UserID            uint      `form:"user_id"`
+	StorageProviderID string    `form:"storage_provider_id"`
+	StartDate         time.Time `form:"start_date" time_format:"2006-01-02" binding:"omitempty"`
+	EndDate           time.Time `form:"end_date" time_format:"2006-01-02" binding:"omitempty"`
+	Sort              string    `form:"sort" binding:"omitempty,oneof=newest oldest size"`
+}
+
+func (d *AdminTrashListDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":    "页码必须大于0",
+		"Size.min":    "每页数量必须大于0",
+		"Size.max":    "每页数量不能超过100",
+		"Keyword.max": "关键词不能超过100个字符",
+		"Sort.oneof":  "排序方式只能是 newest、oldest 或 size",
+	}
+}
+
+// AdminTrashBatchDTO 回收站批量恢复/彻底删除
+type AdminTrashBatchDTO struct {
+	FileIDs []string `json:"file_ids" binding:"required,min=1,max=100"`
+}
+
+func (d *AdminTrashBatchDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.required": "文件ID列表不能为空",
+		"FileIDs.min":      "至少需要一个文件",
+		"FileIDs.max":      "单次批量操作最多支持100个文件",
+	}
+}
@@ -0,0 +1,85 @@
+package file
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/file/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	vectorSvc "pixelpunk/internal/services/vector"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicateCandidates 获取当前用户的近似重复文件列表
+func GetDuplicateCandidates(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.DuplicateListRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := vectorSvc.ListVectorDuplicates(middleware.GetCurrentUserID(c), req.Status, req.Page, req.Limit)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// IgnoreDuplicateCandidate 忽略近似重复提示
+func IgnoreDuplicateCandidate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的记录ID"))
+		return
+	}
+
+	if err := vectorSvc.UpdateVectorDuplicateStatus(middleware.GetCurrentUserID(c), uint(id), models.DuplicateStatusIgnored); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, nil, "已忽略")
+}
+
+// ResolveDuplicateCandidate 处理近似重复文件，可选删除其中一个文件
+func ResolveDuplicateCandidate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的记录ID"))
+		return
+	}
+
+	req, err := common.ValidateRequest[dto.ResolveDuplicateRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID := middleware.GetCurrentUserID(c)
+	candidate, err := vectorSvc.GetVectorDuplicate(userID, uint(id))
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, err.Error()))
+		return
+	}
+
+	if req.DeleteFileID != "" {
+		if req.DeleteFileID != candidate.FileID && req.DeleteFileID != candidate.DuplicateFileID {
+			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "只能删除该重复组中的文件"))
+			return
+		}
+		if err := filesvc.DeleteFile(userID, req.DeleteFileID); err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+	}
+
+	if err := vectorSvc.UpdateVectorDuplicateStatus(userID, candidate.ID, models.DuplicateStatusResolved); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBUpdateFailed, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{"id": candidate.ID, "deleted_file_id": req.DeleteFileID}, "处理成功")
+}
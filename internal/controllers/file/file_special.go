@@ -262,7 +262,7 @@ func UserGetCategoryList(c *gin.Context) {
 
 	var categories []models.FileCategory
 	if err := db.Model(&models.FileCategory{}).Where("user_id = ?", userID).Order("sort_order ASC").Find(&categories).Error; err != nil {
-		logger.Error("获取分类列表失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "获取分类列表失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, "获取分类列表失败"))
 		return
 	}
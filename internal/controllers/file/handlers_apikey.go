@@ -0,0 +1,96 @@
+package file
+
+import (
+	"pixelpunk/internal/controllers/file/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/apikey"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListFilesForApiKey API密钥查询文件列表，限制目录的密钥默认查询限定目录
+func ListFilesForApiKey(c *gin.Context) {
+	key := middleware.GetCurrentAPIKey(c)
+
+	req, err := common.ValidateRequest[dto.FileListQueryDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+	sort := req.Sort
+	if sort == "" {
+		sort = "newest"
+	}
+
+	folderID := req.FolderID
+	if folderID == "" && key.IsFolderRestricted() {
+		folderID = key.FolderID
+	}
+
+	files, total, err := filesvc.GetFileList(key.UserID, folderID, page, size, sort, req.AccessLevel, req.Keyword, nil, nil, "", 0, 0, 0, 0)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"items": files,
+		"pagination": gin.H{
+			"total":        total,
+			"size":         size,
+			"current_page": page,
+			"last_page":    (total + int64(size) - 1) / int64(size),
+		},
+	}
+
+	errors.ResponseSuccess(c, data, "获取成功")
+}
+
+// GetFileForApiKey API密钥查询文件详情
+func GetFileForApiKey(c *gin.Context) {
+	key := middleware.GetCurrentAPIKey(c)
+
+	fileID := c.Param("file_id")
+	if err := apikey.CheckFileAccess(key, fileID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	fileInfo, err := filesvc.GetFileDetail(key.UserID, fileID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, fileInfo, "获取成功")
+}
+
+// DeleteFileForApiKey API密钥删除文件
+func DeleteFileForApiKey(c *gin.Context) {
+	key := middleware.GetCurrentAPIKey(c)
+
+	fileID := c.Param("file_id")
+	if err := apikey.CheckFileAccess(key, fileID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := filesvc.DeleteFile(key.UserID, fileID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"id": fileID}, "删除成功")
+}
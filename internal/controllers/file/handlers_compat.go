@@ -0,0 +1,133 @@
+package file
+
+import (
+	"html/template"
+	"net/http"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/apikey"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 删除链接由上传工具在浏览器中打开，先展示确认页，避免链接预取误删文件
+var compatDeletionTemplate = template.Must(template.New("compat_deletion").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>删除文件</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#f5f5f7;color:#222;display:flex;align-items:center;justify-content:center;min-height:100vh;margin:0}
+.card{background:#fff;border-radius:12px;box-shadow:0 2px 12px rgba(0,0,0,.08);padding:32px;max-width:360px;text-align:center}
+button{background:#e5484d;color:#fff;border:0;border-radius:8px;padding:10px 24px;font-size:15px;cursor:pointer}
+.muted{color:#888;font-size:13px;word-break:break-all}
+</style>
+</head>
+<body>
+<div class="card">
+{{if .Done}}<p>{{.Message}}</p>
+{{else}}<p>确定要删除这个文件吗？删除后可在回收站中找回。</p>
+<p class="muted">{{.FileID}}</p>
+<form method="post"><button type="submit">确认删除</button></form>
+{{end}}
+</div>
+</body>
+</html>`))
+
+type compatDeletionView struct {
+	FileID  string
+	Done    bool
+	Message string
+}
+
+// ShareXUpload ShareX 自定义上传器接口，返回 ShareX 可直接解析的精简结果
+func ShareXUpload(c *gin.Context) {
+	uploaded, ok := compatUpload(c)
+	if !ok {
+		return
+	}
+	key := middleware.GetCurrentAPIKey(c)
+	c.JSON(http.StatusOK, gin.H{
+		"id":            uploaded.ID,
+		"url":           uploaded.URL,
+		"thumbnail_url": uploaded.ThumbURL,
+		"deletion_url":  apikey.DeletionURL(key, uploaded.ID),
+	})
+}
+
+// PicGoUpload PicGo web-uploader 接口，jsonPath 配置为 url
+func PicGoUpload(c *gin.Context) {
+	uploaded, ok := compatUpload(c)
+	if !ok {
+		return
+	}
+	key := middleware.GetCurrentAPIKey(c)
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"url":           uploaded.URL,
+		"thumbnail_url": uploaded.ThumbURL,
+		"deletion_url":  apikey.DeletionURL(key, uploaded.ID),
+	})
+}
+
+// CompatDeletionPage 删除链接确认页
+func CompatDeletionPage(c *gin.Context) {
+	fileID := c.Param("file_id")
+	if _, err := apikey.VerifyDeletionToken(c.Param("key_id"), fileID, c.Param("token")); err != nil {
+		renderCompatDeletion(c, http.StatusForbidden, compatDeletionView{Done: true, Message: "删除链接无效或文件已删除"})
+		return
+	}
+	renderCompatDeletion(c, http.StatusOK, compatDeletionView{FileID: fileID})
+}
+
+// CompatDeleteFile 通过删除链接删除文件，文件移入回收站
+func CompatDeleteFile(c *gin.Context) {
+	fileID := c.Param("file_id")
+	key, err := apikey.VerifyDeletionToken(c.Param("key_id"), fileID, c.Param("token"))
+	if err != nil {
+		renderCompatDeletion(c, http.StatusForbidden, compatDeletionView{Done: true, Message: "删除链接无效或文件已删除"})
+		return
+	}
+	if err := filesvc.DeleteFile(key.UserID, fileID); err != nil {
+		logger.WarnContext(c.Request.Context(), "通过删除链接删除文件失败: keyID=%s, fileID=%s, error=%v", key.ID, fileID, err)
+		renderCompatDeletion(c, http.StatusInternalServerError, compatDeletionView{Done: true, Message: "删除失败，请稍后重试"})
+		return
+	}
+	renderCompatDeletion(c, http.StatusOK, compatDeletionView{Done: true, Message: "文件已删除"})
+}
+
+// compatUpload 按单文件上传处理兼容接口请求，失败时已写入错误响应
+func compatUpload(c *gin.Context) (*filesvc.ExternalAPIFileResponse, bool) {
+	key := middleware.GetCurrentAPIKey(c)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "未检测到上传文件"))
+		return nil, false
+	}
+
+	result, err := filesvc.UploadFileWithAPIKey(c, key, c.PostForm("folderId"), c.PostForm("filePath"), c.PostForm("access_level"), false, nil, file)
+	if err != nil {
+		errors.HandleError(c, err)
+		return nil, false
+	}
+	if result.UploadedSingle == nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, result.Message))
+		return nil, false
+	}
+	return result.UploadedSingle, true
+}
+
+func renderCompatDeletion(c *gin.Context, status int, view compatDeletionView) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	if err := compatDeletionTemplate.Execute(c.Writer, view); err != nil {
+		logger.ErrorContext(c.Request.Context(), "渲染删除确认页面失败: %v", err)
+	}
+}
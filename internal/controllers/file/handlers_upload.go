@@ -50,13 +50,13 @@ func Upload(c *gin.Context) {
 	if req.StorageDuration != "" {
 		storageConfig, err := setting.CreateStorageConfig()
 		if err != nil {
-			logger.Error("Upload: 创建存储配置失败: %v", err)
+			logger.ErrorContext(c.Request.Context(), "Upload: 创建存储配置失败: %v", err)
 			errors.HandleError(c, errors.Wrap(err, errors.CodeDBQueryFailed, "创建存储配置失败"))
 			return
 		}
 		// 验证存储时长（false表示已登录用户模式）
 		if err := storageConfig.ValidateStorageDuration(req.StorageDuration, false); err != nil {
-			logger.Error("Upload: 存储时长验证失败: %v", err)
+			logger.ErrorContext(c.Request.Context(), "Upload: 存储时长验证失败: %v", err)
 			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
 			return
 		}
@@ -183,26 +183,26 @@ func BatchUpload(c *gin.Context) {
 func GuestUpload(c *gin.Context) {
 	req, err := common.ValidateRequest[dto.GuestUploadDTO](c)
 	if err != nil {
-		logger.Error("GuestUpload: 请求验证失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 请求验证失败: %v", err)
 		errors.HandleError(c, err)
 		return
 	}
 
 	storageConfig, err := setting.CreateStorageConfig()
 	if err != nil {
-		logger.Error("GuestUpload: 创建存储配置失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 创建存储配置失败: %v", err)
 		errors.HandleError(c, errors.Wrap(err, errors.CodeDBQueryFailed, "创建存储配置失败"))
 		return
 	}
 	if err := storageConfig.ValidateStorageDuration(req.StorageDuration, true); err != nil {
-		logger.Error("GuestUpload: 存储时长验证失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 存储时长验证失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
 		return
 	}
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		logger.Error("GuestUpload: 获取文件失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 获取文件失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "文件上传失败: "+err.Error()))
 		return
 	}
@@ -211,7 +211,7 @@ func GuestUpload(c *gin.Context) {
 
 	guestFolderID, err := folder.GetOrCreateGuestFolder()
 	if err != nil {
-		logger.Error("GuestUpload: 获取游客文件夹失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 获取游客文件夹失败: %v", err)
 		errors.HandleError(c, err)
 		return
 	}
@@ -228,7 +228,7 @@ func GuestUpload(c *gin.Context) {
 
 	fileInfo, remainingCount, err := filesvc.GuestUploadWithWatermark(c, file, guestFolderID, req.AccessLevel, req.Optimize, req.StorageDuration, fingerprint, wmEnabled, wmConfig)
 	if err != nil {
-		logger.Error("GuestUpload: 服务层处理失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "GuestUpload: 服务层处理失败: %v", err)
 		errors.HandleError(c, err)
 		return
 	}
@@ -333,13 +333,13 @@ func InstantUpload(c *gin.Context) {
 	if req.StorageDuration != "" {
 		storageConfig, err := setting.CreateStorageConfig()
 		if err != nil {
-			logger.Error("InstantUpload: 创建存储配置失败: %v", err)
+			logger.ErrorContext(c.Request.Context(), "InstantUpload: 创建存储配置失败: %v", err)
 			errors.HandleError(c, errors.Wrap(err, errors.CodeDBQueryFailed, "创建存储配置失败"))
 			return
 		}
 		isGuest := userID == 0
 		if err := storageConfig.ValidateStorageDuration(req.StorageDuration, isGuest); err != nil {
-			logger.Error("InstantUpload: 存储时长验证失败: %v", err)
+			logger.ErrorContext(c.Request.Context(), "InstantUpload: 存储时长验证失败: %v", err)
 			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
 			return
 		}
@@ -5,6 +5,7 @@ type CreateFolderDTO struct {
 	ParentID    string `json:"parent_id"`
 	Permission  string `json:"permission" binding:"omitempty,oneof=private public"`
 	Description string `json:"description" binding:"omitempty,max=500"`
+	AIEnabled   *bool  `json:"ai_enabled"` // 为空时保持默认/不修改
 }
 
 func (d *CreateFolderDTO) GetValidationMessages() map[string]string {
@@ -23,6 +24,7 @@ type UpdateFolderDTO struct {
 	ParentID    string `json:"parent_id"`
 	Permission  string `json:"permission" binding:"omitempty,oneof=private public"`
 	Description string `json:"description" binding:"omitempty,max=500"`
+	AIEnabled   *bool  `json:"ai_enabled"` // 为空时保持默认/不修改
 }
 
 func (d *UpdateFolderDTO) GetValidationMessages() map[string]string {
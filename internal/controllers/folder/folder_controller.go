@@ -20,7 +20,7 @@ func CreateFolder(c *gin.Context) {
 		return
 	}
 
-	folderInfo, err := folder.CreateFolder(userID, req.Name, req.ParentID, req.Permission, req.Description)
+	folderInfo, err := folder.CreateFolder(userID, req.Name, req.ParentID, req.Permission, req.Description, req.AIEnabled)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -78,7 +78,7 @@ func UpdateFolder(c *gin.Context) {
 		return
 	}
 
-	folderInfo, err := folder.UpdateFolder(userID, req.FolderID, req.Name, req.ParentID, req.Permission, req.Description)
+	folderInfo, err := folder.UpdateFolder(userID, req.FolderID, req.Name, req.ParentID, req.Permission, req.Description, req.AIEnabled)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
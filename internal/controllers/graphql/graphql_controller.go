@@ -0,0 +1,74 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"pixelpunk/internal/middleware"
+	graphqlService "pixelpunk/internal/services/graphql"
+	"pixelpunk/pkg/errors"
+	gql "pixelpunk/pkg/graphql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体上限
+const maxRequestBytes = 1 << 20
+
+/* Query 执行 GraphQL 查询，支持 GET 查询参数、JSON 请求体与 application/graphql 请求体 */
+func Query(c *gin.Context) {
+	if !graphqlService.IsEnabled() {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, "GraphQL 接口未开启"))
+		return
+	}
+
+	userID := middleware.GetCurrentUserID(c)
+	if key := middleware.GetCurrentAPIKey(c); key != nil {
+		if key.IsFolderRestricted() {
+			errors.HandleError(c, errors.New(errors.CodeForbidden, "限定目录的API密钥不能使用 GraphQL 接口"))
+			return
+		}
+		userID = key.UserID
+	}
+
+	req, err := parseRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &gql.Result{Errors: []*gql.Error{{Message: err.Error()}}})
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		c.JSON(http.StatusBadRequest, &gql.Result{Errors: []*gql.Error{{Message: "缺少 query 参数"}}})
+		return
+	}
+
+	c.JSON(http.StatusOK, graphqlService.Execute(c.Request.Context(), userID, req))
+}
+
+func parseRequest(c *gin.Context) (graphqlService.Request, error) {
+	var req graphqlService.Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return req, errors.New(errors.CodeInvalidParameter, "variables 不是有效的 JSON 对象")
+			}
+		}
+		return req, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBytes))
+	if err != nil {
+		return req, errors.New(errors.CodeInvalidParameter, "请求体过大或读取失败")
+	}
+	if strings.HasPrefix(c.ContentType(), "application/graphql") {
+		req.Query = string(body)
+		return req, nil
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return req, errors.New(errors.CodeInvalidParameter, "请求体不是有效的 JSON")
+	}
+	return req, nil
+}
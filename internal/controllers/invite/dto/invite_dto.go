@@ -0,0 +1,57 @@
+package dto
+
+type GenerateInviteCodeDTO struct {
+	Count         int    `json:"count" binding:"omitempty,min=1,max=100"`
+	MaxUses       *int   `json:"max_uses" binding:"omitempty,min=0,max=10000"` // 仅管理员有效，默认1次，0表示不限次数
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=0,max=3650"`
+	Note          string `json:"note" binding:"omitempty,max=255"`
+}
+
+func (d *GenerateInviteCodeDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Count.min":         "生成数量至少为1",
+		"Count.max":         "单次最多生成100个邀请码",
+		"MaxUses.min":       "可使用次数不能为负数",
+		"MaxUses.max":       "可使用次数不能超过10000",
+		"ExpiresInDays.min": "有效天数不能为负数",
+		"ExpiresInDays.max": "有效天数不能超过3650天",
+		"Note.max":          "备注长度不能超过255个字符",
+	}
+}
+
+type InviteCodeListDTO struct {
+	Page   int `form:"page" binding:"omitempty,min=1"`
+	Size   int `form:"size" binding:"omitempty,min=1,max=100"`
+	Status int `form:"status" binding:"omitempty,oneof=1 2"`
+}
+
+func (d *InviteCodeListDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":     "页码必须大于0",
+		"Size.min":     "每页数量必须大于0",
+		"Size.max":     "每页数量不能超过100",
+		"Status.oneof": "状态值无效",
+	}
+}
+
+type UpdateInviteCodeStatusDTO struct {
+	Status int `json:"status" binding:"required,oneof=1 2"`
+}
+
+func (d *UpdateInviteCodeStatusDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Status.required": "状态不能为空",
+		"Status.oneof":    "状态值无效",
+	}
+}
+
+type CheckInviteCodeDTO struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+func (d *CheckInviteCodeDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Code.required": "请填写邀请码",
+		"Code.max":      "邀请码格式不正确",
+	}
+}
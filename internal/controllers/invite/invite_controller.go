@@ -0,0 +1,234 @@
+package invite
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/invite/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	inviteService "pixelpunk/internal/services/invite"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* CheckInviteCode 注册前校验邀请码是否可用（公开接口） */
+func CheckInviteCode(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.CheckInviteCodeDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if _, err := inviteService.CheckCode(req.Code); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"valid": true}, "邀请码可用")
+}
+
+/* GetMyInviteCodes 获取当前用户生成的邀请码及剩余配额 */
+func GetMyInviteCodes(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	listInviteCodes(c, userID, func(response gin.H) {
+		quota, used := inviteService.GetUserQuota(userID)
+		response["quota"] = gin.H{
+			"total": quota,
+			"used":  used,
+		}
+	})
+}
+
+/* GenerateMyInviteCodes 普通用户在配额内生成一次性邀请码 */
+func GenerateMyInviteCodes(c *gin.Context) {
+	generateInviteCodes(c, middleware.IsCurrentUserAdmin(c))
+}
+
+/* DisableMyInviteCode 停用自己生成的邀请码 */
+func DisableMyInviteCode(c *gin.Context) {
+	id, ok := parseInviteCodeID(c)
+	if !ok {
+		return
+	}
+
+	if err := inviteService.SetCodeStatus(id, middleware.GetCurrentUserID(c), models.InvitationCodeStatusDisabled); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "邀请码已停用")
+}
+
+/* AdminListInviteCodes 管理员获取全部邀请码 */
+func AdminListInviteCodes(c *gin.Context) {
+	listInviteCodes(c, 0, nil)
+}
+
+/* AdminGenerateInviteCodes 管理员生成邀请码，可设置使用次数和有效期 */
+func AdminGenerateInviteCodes(c *gin.Context) {
+	generateInviteCodes(c, true)
+}
+
+/* AdminUpdateInviteCodeStatus 管理员启用或停用邀请码 */
+func AdminUpdateInviteCodeStatus(c *gin.Context) {
+	id, ok := parseInviteCodeID(c)
+	if !ok {
+		return
+	}
+
+	req, err := common.ValidateRequest[dto.UpdateInviteCodeStatusDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := inviteService.SetCodeStatus(id, 0, req.Status); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "更新邀请码状态成功")
+}
+
+/* AdminDeleteInviteCode 管理员删除未被使用过的邀请码 */
+func AdminDeleteInviteCode(c *gin.Context) {
+	id, ok := parseInviteCodeID(c)
+	if !ok {
+		return
+	}
+
+	if err := inviteService.DeleteCode(id, 0); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "删除邀请码成功")
+}
+
+/* AdminGetInviteCodeUsers 查看通过某个邀请码注册的用户 */
+func AdminGetInviteCodeUsers(c *gin.Context) {
+	id, ok := parseInviteCodeID(c)
+	if !ok {
+		return
+	}
+
+	users, err := inviteService.GetCodeUsers(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(users))
+	for _, u := range users {
+		items = append(items, gin.H{
+			"id":         u.ID,
+			"username":   u.Username,
+			"created_at": u.CreatedAt,
+		})
+	}
+
+	errors.ResponseSuccess(c, items, "获取邀请注册用户成功")
+}
+
+func generateInviteCodes(c *gin.Context, isAdmin bool) {
+	req, err := common.ValidateRequest[dto.GenerateInviteCodeDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	maxUses := 1
+	if req.MaxUses != nil {
+		maxUses = *req.MaxUses
+	}
+
+	codes, err := inviteService.GenerateCodes(middleware.GetCurrentUserID(c), isAdmin, inviteService.GenerateOptions{
+		Count:         req.Count,
+		MaxUses:       maxUses,
+		ExpiresInDays: req.ExpiresInDays,
+		Note:          req.Note,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(codes))
+	for i := range codes {
+		items = append(items, formatInviteCode(&codes[i]))
+	}
+
+	errors.ResponseSuccess(c, items, "生成邀请码成功")
+}
+
+func listInviteCodes(c *gin.Context, creatorID uint, decorate func(gin.H)) {
+	req, err := common.ValidateRequest[dto.InviteCodeListDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	page, size := req.Page, req.Size
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+
+	codes, total, err := inviteService.ListCodes(creatorID, page, size, req.Status)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(codes))
+	for i := range codes {
+		items = append(items, formatInviteCode(&codes[i]))
+	}
+
+	response := gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        page,
+			"size":        size,
+			"total_pages": (total + int64(size) - 1) / int64(size),
+		},
+	}
+	if decorate != nil {
+		decorate(response)
+	}
+
+	errors.ResponseSuccess(c, response, "获取邀请码列表成功")
+}
+
+func formatInviteCode(code *models.InvitationCode) gin.H {
+	item := gin.H{
+		"id":         code.ID,
+		"code":       code.Code,
+		"creator_id": code.CreatorID,
+		"max_uses":   code.MaxUses,
+		"used_count": code.UsedCount,
+		"status":     code.Status,
+		"note":       code.Note,
+		"is_usable":  code.IsUsable(),
+		"expires_at": code.ExpiresAt,
+		"created_at": code.CreatedAt,
+	}
+	if code.Creator != nil {
+		item["creator_name"] = code.Creator.Username
+	}
+	return item
+}
+
+func parseInviteCodeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的邀请码ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,13 @@
+package dto
+
+type RequeueJobsDTO struct {
+	FileIDs []string `json:"file_ids" binding:"required,min=1,max=1000"`
+}
+
+func (d *RequeueJobsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.required": "请选择需要重新入队的任务",
+		"FileIDs.min":      "请选择需要重新入队的任务",
+		"FileIDs.max":      "单次最多重新入队1000个任务",
+	}
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"pixelpunk/internal/controllers/job/dto"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* ListJobKinds 后台任务概览：各任务类型的队列长度、重试策略与处理结果 */
+func ListJobKinds(c *gin.Context) {
+	errors.ResponseSuccess(c, qqueue.Stats(), "获取成功")
+}
+
+/* ListJobs 按状态分页列出指定类型的任务 */
+func ListJobs(c *gin.Context) {
+	type QueryParams struct {
+		State string `form:"state"`
+		Page  int    `form:"page"`
+		Limit int    `form:"limit"`
+	}
+	var params QueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "参数错误"))
+		return
+	}
+	if params.State == "" {
+		params.State = qqueue.StateQueued
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	jobs, total, err := qqueue.ListJobs(c.Param("kind"), params.State, (params.Page-1)*params.Limit, params.Limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{
+		"jobs": jobs,
+		"pagination": gin.H{
+			"page":  params.Page,
+			"limit": params.Limit,
+			"total": total,
+		},
+	}, "获取成功")
+}
+
+/* RequeueDeadJobs 将指定类型的死信任务重新入队 */
+func RequeueDeadJobs(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.RequeueJobsDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	count, err := qqueue.RequeueDead(c.Param("kind"), req.FileIDs)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{"requeued": count}, "重新入队成功")
+}
@@ -0,0 +1,87 @@
+package message
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/message/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/message"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateBroadcast(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.CreateBroadcastDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	broadcast, err := message.GetMessageService().CreateBroadcast(middleware.GetCurrentUserID(c), message.BroadcastRequest{
+		Title:      req.Title,
+		Content:    req.Content,
+		Priority:   req.Priority,
+		ActionURL:  req.ActionURL,
+		ActionText: req.ActionText,
+		Segment: message.BroadcastSegment{
+			Roles:            req.Roles,
+			GroupIDs:         req.GroupIDs,
+			UserIDs:          req.UserIDs,
+			ActiveWithinDays: req.ActiveWithinDays,
+		},
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	middleware.SetAuditChange(c, "message.broadcast", "message_broadcast", strconv.FormatUint(uint64(broadcast.ID), 10), nil, gin.H{
+		"title":            broadcast.Title,
+		"segment":          broadcast.Segment,
+		"total_recipients": broadcast.TotalRecipients,
+	})
+
+	errors.ResponseSuccess(c, broadcast, "群发消息已开始投递")
+}
+
+func GetBroadcasts(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.GetBroadcastsDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items, total, err := message.GetMessageService().ListBroadcasts(req.Page, req.PageSize)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"total":        total,
+			"size":         req.PageSize,
+			"current_page": req.Page,
+			"last_page":    (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+		},
+	}, "获取成功")
+}
+
+func GetBroadcast(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "群发ID无效"))
+		return
+	}
+
+	broadcast, err := message.GetMessageService().GetBroadcast(uint(id))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, broadcast, "获取成功")
+}
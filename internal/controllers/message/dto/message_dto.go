@@ -226,3 +226,68 @@ func MessageToResponse(msg *models.Message) gin.H {
 
 	return response
 }
+
+type ReviewAppealDTO struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+func (d *ReviewAppealDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Reason.required": "申诉理由不能为空",
+		"Reason.max":      "申诉理由不能超过1000个字符",
+	}
+}
+
+type GetReviewAppealsDTO struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"pageSize,default=20" binding:"min=1,max=100"`
+}
+
+func (d *GetReviewAppealsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":     "页码不能小于1",
+		"PageSize.min": "每页数量不能小于1",
+		"PageSize.max": "每页数量不能大于100",
+	}
+}
+
+type CreateBroadcastDTO struct {
+	Title            string `json:"title" binding:"required,max=200"`
+	Content          string `json:"content" binding:"required,max=5000"`
+	Priority         int    `json:"priority" binding:"omitempty,oneof=1 2 3"`
+	ActionURL        string `json:"action_url" binding:"omitempty,max=500"`
+	ActionText       string `json:"action_text" binding:"omitempty,max=100"`
+	Roles            []int  `json:"roles" binding:"omitempty,dive,oneof=1 2 3 4"` // 接收角色，均为空时发送给全部用户
+	GroupIDs         []uint `json:"group_ids"`
+	UserIDs          []uint `json:"user_ids" binding:"omitempty,max=1000"`
+	ActiveWithinDays int    `json:"active_within_days" binding:"omitempty,min=1,max=3650"` // 仅发送给最近N天内活跃的用户
+}
+
+func (d *CreateBroadcastDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Title.required":       "消息标题不能为空",
+		"Title.max":            "消息标题不能超过200个字符",
+		"Content.required":     "消息内容不能为空",
+		"Content.max":          "消息内容不能超过5000个字符",
+		"Priority.oneof":       "优先级必须是1、2或3",
+		"ActionURL.max":        "跳转链接不能超过500个字符",
+		"ActionText.max":       "按钮文字不能超过100个字符",
+		"Roles.oneof":          "接收角色无效",
+		"UserIDs.max":          "指定用户不能超过1000个",
+		"ActiveWithinDays.min": "活跃天数必须大于0",
+		"ActiveWithinDays.max": "活跃天数不能超过3650",
+	}
+}
+
+type GetBroadcastsDTO struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"pageSize,default=20" binding:"min=1,max=100"`
+}
+
+func (d *GetBroadcastsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":     "页码不能小于1",
+		"PageSize.min": "每页数量不能小于1",
+		"PageSize.max": "每页数量不能大于100",
+	}
+}
@@ -0,0 +1,64 @@
+package message
+
+import (
+	"pixelpunk/internal/controllers/message/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/review"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppealReviewMessage 对审核拒绝通知发起申诉
+func AppealReviewMessage(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ReviewAppealDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	reviewLogID, err := review.ResolveAppealReviewLog(userID, c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	appeal, err := review.CreateReviewAppeal(userID, reviewLogID, req.Reason)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, appeal, "申诉已提交，请等待管理员处理")
+}
+
+func GetMyReviewAppeals(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.GetReviewAppealsDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	appeals, total, err := review.GetUserReviewAppeals(userID, req.Page, req.PageSize)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"items": appeals,
+		"pagination": gin.H{
+			"total":        total,
+			"size":         req.PageSize,
+			"current_page": req.Page,
+			"last_page":    (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+		},
+	}
+
+	errors.ResponseSuccess(c, data, "获取成功")
+}
@@ -7,8 +7,10 @@ import (
 	"pixelpunk/internal/services/auth"
 	oauthService "pixelpunk/internal/services/oauth"
 	"pixelpunk/internal/services/setting"
+	userService "pixelpunk/internal/services/user"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -53,11 +55,13 @@ func handleOAuthLogin(c *gin.Context, code string, provider string, handler OAut
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username, int(user.Role), jwtSecret, expiresHours)
+	client := auth.ClientInfo{IP: utils.GetClientIP(c), UserAgent: c.Request.UserAgent()}
+	token, err := auth.IssueSessionToken(user, "oauth", client, jwtSecret, expiresHours)
 	if err != nil {
 		errors.HandleError(c, errors.New(errors.CodeInternal, "生成登录凭证失败"))
 		return
 	}
+	go userService.CheckLoginAnomaly(user, client)
 
 	userInfo := map[string]interface{}{
 		"id":       user.ID,
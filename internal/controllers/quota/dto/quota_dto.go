@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type GrantQuotaDTO struct {
+	UserID    uint       `json:"user_id" binding:"required,min=1"`
+	QuotaType string     `json:"quota_type" binding:"required,oneof=storage bandwidth upload"`
+	Amount    int64      `json:"amount" binding:"required,min=1"` // 存储/流量为字节，上传为次数
+	Reason    string     `json:"reason" binding:"required,max=255"`
+	ExpiresAt *time.Time `json:"expires_at"` // 到期时间(RFC3339)，为空表示永久有效
+}
+
+func (d *GrantQuotaDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"UserID.required":    "用户ID不能为空",
+		"UserID.min":         "用户ID无效",
+		"QuotaType.required": "配额类型不能为空",
+		"QuotaType.oneof":    "配额类型必须是storage、bandwidth或upload",
+		"Amount.required":    "追加量不能为空",
+		"Amount.min":         "追加量必须大于0",
+		"Reason.required":    "请填写调整原因",
+		"Reason.max":         "调整原因不能超过255个字符",
+	}
+}
+
+type RevokeQuotaDTO struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+func (d *RevokeQuotaDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Reason.max": "撤销原因不能超过255个字符",
+	}
+}
+
+type QuotaGrantListDTO struct {
+	UserID    uint   `form:"user_id"`
+	QuotaType string `form:"quota_type" binding:"omitempty,oneof=storage bandwidth upload"`
+	Status    string `form:"status" binding:"omitempty,oneof=active expired revoked"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Size      int    `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+func (d *QuotaGrantListDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"QuotaType.oneof": "配额类型必须是storage、bandwidth或upload",
+		"Status.oneof":    "状态必须是active、expired或revoked",
+		"Page.min":        "页码必须大于0",
+		"Size.min":        "每页数量必须大于0",
+		"Size.max":        "每页数量不能超过100",
+	}
+}
@@ -0,0 +1,149 @@
+package quota
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/quota/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	quotaService "pixelpunk/internal/services/quota"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* GetMyQuotaGrants 用户查看自己的配额调整记录及当前生效的追加配额 */
+func GetMyQuotaGrants(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	listQuotaGrants(c, userID, func(response gin.H) {
+		response["active_bonus"] = quotaService.GetActiveBonus(userID)
+	})
+}
+
+/* AdminListQuotaGrants 管理员查看配额调整历史，可按用户筛选 */
+func AdminListQuotaGrants(c *gin.Context) {
+	listQuotaGrants(c, 0, nil)
+}
+
+/* AdminGrantQuota 管理员为用户追加存储、流量或上传配额 */
+func AdminGrantQuota(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.GrantQuotaDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	grant, err := quotaService.Grant(middleware.GetCurrentUserID(c), quotaService.GrantInput{
+		UserID:    req.UserID,
+		QuotaType: req.QuotaType,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "quota.grant", "user", strconv.FormatUint(uint64(req.UserID), 10), nil, formatQuotaGrant(grant))
+
+	errors.ResponseSuccess(c, formatQuotaGrant(grant), "追加配额成功")
+}
+
+/* AdminRevokeQuotaGrant 管理员提前撤销追加配额 */
+func AdminRevokeQuotaGrant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的记录ID"))
+		return
+	}
+
+	req, err := common.ValidateRequest[dto.RevokeQuotaDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	grant, err := quotaService.Revoke(middleware.GetCurrentUserID(c), uint(id), req.Reason)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "quota.revoke", "user", strconv.FormatUint(uint64(grant.UserID), 10), formatQuotaGrant(grant), gin.H{"revoke_reason": req.Reason})
+
+	errors.ResponseSuccess(c, nil, "撤销配额成功")
+}
+
+func listQuotaGrants(c *gin.Context, userID uint, decorate func(gin.H)) {
+	req, err := common.ValidateRequest[dto.QuotaGrantListDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	page, size := req.Page, req.Size
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if userID == 0 {
+		userID = req.UserID
+	}
+
+	grants, total, err := quotaService.List(quotaService.ListParams{
+		UserID:    userID,
+		QuotaType: req.QuotaType,
+		Status:    req.Status,
+		Page:      page,
+		Size:      size,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(grants))
+	for i := range grants {
+		items = append(items, formatQuotaGrant(&grants[i]))
+	}
+
+	response := gin.H{
+		"items": items,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        page,
+			"size":        size,
+			"total_pages": (total + int64(size) - 1) / int64(size),
+		},
+	}
+	if decorate != nil {
+		decorate(response)
+	}
+
+	errors.ResponseSuccess(c, response, "获取配额记录成功")
+}
+
+func formatQuotaGrant(grant *models.UserQuotaGrant) gin.H {
+	item := gin.H{
+		"id":            grant.ID,
+		"user_id":       grant.UserID,
+		"quota_type":    grant.QuotaType,
+		"amount":        grant.Amount,
+		"reason":        grant.Reason,
+		"status":        grant.Status(),
+		"expires_at":    grant.ExpiresAt,
+		"granted_by":    grant.GrantedBy,
+		"revoked_at":    grant.RevokedAt,
+		"revoke_reason": grant.RevokeReason,
+		"created_at":    grant.CreatedAt,
+	}
+	if grant.User != nil {
+		item["username"] = grant.User.Username
+	}
+	if grant.Granter != nil {
+		item["granter_name"] = grant.Granter.Username
+	}
+	return item
+}
@@ -0,0 +1,396 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/middleware"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/s3compat"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LibraryBucket 每个密钥只有一个虚拟存储桶，对应密钥可访问的文件库
+const LibraryBucket = "library"
+
+const defaultMaxKeys = 1000
+
+/* ListBuckets 列出存储桶 */
+func ListBuckets(c *gin.Context) {
+	key := middleware.GetCurrentAPIKey(c)
+	writeXML(c, http.StatusOK, s3compat.ListAllMyBucketsResult{
+		Xmlns: s3compat.Namespace,
+		Owner: s3compat.Owner{ID: key.ID, DisplayName: key.Name},
+		Buckets: []s3compat.Bucket{
+			{Name: LibraryBucket, CreationDate: s3compat.FormatTime(time.Time(key.CreatedAt))},
+		},
+	})
+}
+
+/* GetBucket 存储桶级 GET：GetBucketLocation 或 ListObjects(V1/V2) */
+func GetBucket(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	query := c.Request.URL.Query()
+
+	switch {
+	case query.Has("location"):
+		writeXML(c, http.StatusOK, s3compat.LocationConstraint{Xmlns: s3compat.Namespace})
+	case query.Has("uploads"), query.Has("versions"), query.Has("acl"), query.Has("policy"), query.Has("lifecycle"):
+		middleware.AbortS3(c, s3compat.ErrNotImplemented)
+	default:
+		listObjects(c, query.Get("list-type") == "2")
+	}
+}
+
+/* HeadBucket 判断存储桶是否存在 */
+func HeadBucket(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+/* PutBucket 存储桶固定存在，创建请求直接返回成功 */
+func PutBucket(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	c.Header("Location", "/"+LibraryBucket)
+	c.Status(http.StatusOK)
+}
+
+/* GetObject 下载对象 */
+func GetObject(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	objectKey := objectKeyParam(c)
+	if objectKey == "" {
+		GetBucket(c)
+		return
+	}
+	serveObject(c, objectKey, true)
+}
+
+/* HeadObject 获取对象元数据 */
+func HeadObject(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	objectKey := objectKeyParam(c)
+	if objectKey == "" {
+		HeadBucket(c)
+		return
+	}
+	serveObject(c, objectKey, false)
+}
+
+/* PutObject 上传对象，以 / 结尾的键视为创建目录 */
+func PutObject(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	objectKey := objectKeyParam(c)
+	if objectKey == "" {
+		PutBucket(c)
+		return
+	}
+	query := c.Request.URL.Query()
+	if query.Has("uploadId") || query.Has("partNumber") || c.GetHeader("X-Amz-Copy-Source") != "" {
+		middleware.AbortS3(c, s3compat.ErrNotImplemented.WithMessage("Multipart upload and copy are not supported."))
+		return
+	}
+
+	key := middleware.GetCurrentAPIKey(c)
+	sr := middleware.GetS3Request(c)
+	body, size, err := sr.Body(c.Request)
+	if err != nil {
+		middleware.AbortS3(c, err)
+		return
+	}
+
+	if strings.HasSuffix(objectKey, "/") {
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			middleware.AbortS3(c, err)
+			return
+		}
+		if err := filesvc.CreateLibraryFolder(key, strings.TrimSuffix(objectKey, "/")); err != nil {
+			middleware.AbortS3(c, toS3Error(err))
+			return
+		}
+		c.Header("ETag", `"`+hex.EncodeToString(md5.New().Sum(nil))+`"`)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if !key.CheckUploadCountLimit() {
+		middleware.AbortS3(c, s3compat.ErrAccessDenied.WithMessage("Upload count limit reached."))
+		return
+	}
+	maxSize := filesvc.MaxLibraryFileSize(key)
+	if size > maxSize {
+		middleware.AbortS3(c, s3compat.ErrEntityTooLarge)
+		return
+	}
+
+	tmp, etag, err := spoolBody(body, size, maxSize)
+	if err != nil {
+		middleware.AbortS3(c, err)
+		return
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err := filesvc.PutLibraryObject(c, key, objectKey, tmp, c.GetHeader("Content-Type")); err != nil {
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+
+	c.Header("ETag", etag)
+	c.Status(http.StatusOK)
+}
+
+/* DeleteObject 删除对象，对象不存在时同样返回成功 */
+func DeleteObject(c *gin.Context) {
+	if !checkBucket(c) {
+		return
+	}
+	objectKey := objectKeyParam(c)
+	if objectKey == "" {
+		middleware.AbortS3(c, s3compat.ErrNotImplemented.WithMessage("Bucket deletion is not supported."))
+		return
+	}
+
+	key := middleware.GetCurrentAPIKey(c)
+	file, _, err := filesvc.FindLibraryObject(key, objectKey)
+	if err != nil {
+		if errors.IsCode(err, errors.CodeFileNotFound) {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+
+	if err := filesvc.DeleteFile(key.UserID, file.ID); err != nil {
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+/* NotImplemented 未支持的操作（分片上传、批量删除等） */
+func NotImplemented(c *gin.Context) {
+	middleware.AbortS3(c, s3compat.ErrNotImplemented)
+}
+
+func listObjects(c *gin.Context, v2 bool) {
+	query := c.Request.URL.Query()
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	encode := query.Get("encoding-type") == "url"
+
+	maxKeys := defaultMaxKeys
+	if v := query.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			middleware.AbortS3(c, s3compat.ErrInvalidArgument.WithMessage("Invalid max-keys."))
+			return
+		}
+		if n < maxKeys {
+			maxKeys = n
+		}
+	}
+
+	after := query.Get("marker")
+	if v2 {
+		after = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				middleware.AbortS3(c, s3compat.ErrInvalidArgument.WithMessage("The continuation token provided is incorrect."))
+				return
+			}
+			after = string(decoded)
+		}
+	}
+
+	key := middleware.GetCurrentAPIKey(c)
+	objects, err := filesvc.ListLibraryObjects(key, prefix)
+	if err != nil {
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+	entries := make([]s3compat.ListEntry, 0, len(objects))
+	for _, o := range objects {
+		entries = append(entries, s3compat.ListEntry{Key: o.Key, ETag: o.ETag, Size: o.Size, LastModified: o.LastModified})
+	}
+	page := s3compat.Paginate(entries, prefix, delimiter, after, maxKeys)
+
+	result := s3compat.ListBucketResult{
+		Xmlns:          s3compat.Namespace,
+		Name:           LibraryBucket,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+		MaxKeys:        maxKeys,
+		IsTruncated:    page.IsTruncated,
+		Contents:       page.Contents,
+		CommonPrefixes: page.CommonPrefixes,
+	}
+	if v2 {
+		result.KeyCount = len(page.Contents) + len(page.CommonPrefixes)
+		result.StartAfter = query.Get("start-after")
+		result.ContinuationToken = query.Get("continuation-token")
+		if page.IsTruncated {
+			result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(page.NextMarker))
+		}
+	} else {
+		result.Marker = after
+		if page.IsTruncated && delimiter != "" {
+			result.NextMarker = page.NextMarker
+		}
+	}
+
+	if encode {
+		result.EncodingType = "url"
+		result.Prefix = s3compat.EncodeKey(result.Prefix)
+		result.Delimiter = s3compat.EncodeKey(result.Delimiter)
+		result.Marker = s3compat.EncodeKey(result.Marker)
+		result.NextMarker = s3compat.EncodeKey(result.NextMarker)
+		result.StartAfter = s3compat.EncodeKey(result.StartAfter)
+		for i := range result.Contents {
+			result.Contents[i].Key = s3compat.EncodeKey(result.Contents[i].Key)
+		}
+		for i := range result.CommonPrefixes {
+			result.CommonPrefixes[i].Prefix = s3compat.EncodeKey(result.CommonPrefixes[i].Prefix)
+		}
+	}
+
+	writeXML(c, http.StatusOK, result)
+}
+
+func serveObject(c *gin.Context, objectKey string, withBody bool) {
+	key := middleware.GetCurrentAPIKey(c)
+	file, object, err := filesvc.FindLibraryObject(key, objectKey)
+	if err != nil {
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+
+	c.Header("ETag", object.ETag)
+	c.Header("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	c.Header("Content-Length", strconv.FormatInt(object.Size, 10))
+	contentType := object.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+
+	if match := c.GetHeader("If-None-Match"); match != "" && match == object.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if !withBody {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	reader, err := filesvc.OpenFileContent(c.Request.Context(), *file)
+	if err != nil {
+		middleware.AbortS3(c, toS3Error(err))
+		return
+	}
+	defer reader.Close()
+
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		logger.WarnContext(c.Request.Context(), "S3 下载对象中断: fileID=%s, error=%v", file.ID, err)
+	}
+}
+
+// spoolBody 将请求体写入临时文件并计算 MD5，超过大小限制或长度不符时返回对应 S3 错误
+func spoolBody(body io.Reader, size, maxSize int64) (*os.File, string, error) {
+	tmp, err := os.CreateTemp("", "pixelpunk-s3-*")
+	if err != nil {
+		return nil, "", s3compat.ErrInternal
+	}
+
+	h := md5.New()
+	written, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(body, maxSize+1))
+	if err == nil {
+		switch {
+		case written > maxSize:
+			err = s3compat.ErrEntityTooLarge
+		case size >= 0 && written != size:
+			err = s3compat.ErrIncompleteBody
+		default:
+			_, err = tmp.Seek(0, io.SeekStart)
+		}
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		if _, ok := err.(*s3compat.Error); !ok {
+			err = s3compat.ErrIncompleteBody
+		}
+		return nil, "", err
+	}
+
+	return tmp, `"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
+
+func checkBucket(c *gin.Context) bool {
+	if c.Param("bucket") != LibraryBucket {
+		middleware.AbortS3(c, s3compat.ErrNoSuchBucket)
+		return false
+	}
+	return true
+}
+
+// objectKeyParam 读取对象键，键为空时表示存储桶级请求
+func objectKeyParam(c *gin.Context) string {
+	return strings.TrimPrefix(c.Param("key"), "/")
+}
+
+// toS3Error 将业务错误转换为 S3 错误码
+func toS3Error(err error) *s3compat.Error {
+	if s3Err, ok := err.(*s3compat.Error); ok {
+		return s3Err
+	}
+	switch {
+	case errors.IsCode(err, errors.CodeFileNotFound), errors.IsCode(err, errors.CodeNotFound):
+		return s3compat.ErrNoSuchKey
+	case errors.IsCode(err, errors.CodeFileTooLarge), errors.IsCode(err, errors.CodeStorageLimitExceeded):
+		return s3compat.ErrEntityTooLarge.WithMessage(err.Error())
+	case errors.IsCode(err, errors.CodeForbidden), errors.IsCode(err, errors.CodeUploadLimitExceeded):
+		return s3compat.ErrAccessDenied.WithMessage(err.Error())
+	case errors.IsCode(err, errors.CodeInvalidParameter):
+		return s3compat.ErrInvalidArgument.WithMessage(err.Error())
+	}
+	logger.Error("S3 接口处理失败: %v", err)
+	return s3compat.ErrInternal
+}
+
+func writeXML(c *gin.Context, status int, v interface{}) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		middleware.AbortS3(c, s3compat.ErrInternal)
+		return
+	}
+	c.Data(status, "application/xml", append([]byte(xml.Header), body...))
+}
@@ -6,14 +6,41 @@ import (
 	"time"
 )
 
+// VectorSearchFilters 语义搜索的分面过滤条件，在向量存储中与相似度检索一起执行
+type VectorSearchFilters struct {
+	TagIDs   []uint   `json:"tag_ids" binding:"omitempty,max=10"`                   // 必须同时包含的标签ID
+	FolderID string   `json:"folder_id" binding:"omitempty,max=32"`                 // 所在文件夹ID
+	Formats  []string `json:"formats" binding:"omitempty,max=10,dive,min=1,max=10"` // 文件格式，任一匹配即可
+	DateFrom string   `json:"date_from" binding:"omitempty,datetime=2006-01-02"`    // 上传日期起（含）
+	DateTo   string   `json:"date_to" binding:"omitempty,datetime=2006-01-02"`      // 上传日期止（含）
+	NSFW     *bool    `json:"nsfw"`                                                 // 是否为敏感内容
+}
+
+// withFilterMessages 合并分面过滤条件的校验提示
+func withFilterMessages(messages map[string]string) map[string]string {
+	for k, v := range map[string]string{
+		"TagIDs.max":        "标签最多选择10个",
+		"FolderID.max":      "文件夹ID格式错误",
+		"Formats.max":       "文件格式最多选择10个",
+		"Formats.min":       "文件格式不能为空",
+		"DateFrom.datetime": "开始日期格式应为 YYYY-MM-DD",
+		"DateTo.datetime":   "结束日期格式应为 YYYY-MM-DD",
+	} {
+		messages[k] = v
+	}
+	return messages
+}
+
 type VectorSearchRequest struct {
-	Query     string  `json:"query" binding:"required,min=1,max=500"`    // 搜索查询文本
-	Limit     int     `json:"limit" binding:"omitempty,min=1,max=100"`   // 返回结果数量限制
-	Threshold float32 `json:"threshold" binding:"omitempty,min=0,max=1"` // 相似度阈值
+	Query     string  `json:"query" binding:"required,min=1,max=500"`       // 搜索查询文本
+	Limit     int     `json:"limit" binding:"omitempty,min=1,max=100"`      // 返回结果数量限制
+	Threshold float32 `json:"threshold" binding:"omitempty,min=0,max=1"`    // 相似度阈值
+	Mode      string  `json:"mode" binding:"omitempty,oneof=vector hybrid"` // 搜索模式：vector(默认)/hybrid(关键词+向量融合)
+	VectorSearchFilters
 }
 
 func (r *VectorSearchRequest) GetValidationMessages() map[string]string {
-	return map[string]string{
+	return withFilterMessages(map[string]string{
 		"Query.required": "搜索查询不能为空",
 		"Query.min":      "搜索查询至少需要1个字符",
 		"Query.max":      "搜索查询不能超过500个字符",
@@ -21,7 +48,8 @@ func (r *VectorSearchRequest) GetValidationMessages() map[string]string {
 		"Limit.max":      "结果数量限制不能超过100",
 		"Threshold.min":  "相似度阈值不能小于0",
 		"Threshold.max":  "相似度阈值不能大于1",
-	}
+		"Mode.oneof":     "搜索模式只能是 vector 或 hybrid",
+	})
 }
 
 type VectorSearchResult struct {
@@ -81,31 +109,37 @@ type VectorHealthResponse struct {
 }
 
 type UserVectorSearchRequest struct {
-	Query string `json:"query" binding:"required,min=1,max=500"` // 搜索查询文本
-	Page  int    `json:"page" binding:"omitempty,min=1"`         // 页码
+	Query string `json:"query" binding:"required,min=1,max=500"`       // 搜索查询文本
+	Page  int    `json:"page" binding:"omitempty,min=1"`               // 页码
+	Mode  string `json:"mode" binding:"omitempty,oneof=vector hybrid"` // 搜索模式：vector(默认)/hybrid(关键词+向量融合)
+	VectorSearchFilters
 }
 
 func (r *UserVectorSearchRequest) GetValidationMessages() map[string]string {
-	return map[string]string{
+	return withFilterMessages(map[string]string{
 		"Query.required": "搜索查询不能为空",
 		"Query.min":      "搜索查询至少需要1个字符",
 		"Query.max":      "搜索查询不能超过500个字符",
 		"Page.min":       "页码必须大于等于1",
-	}
+		"Mode.oneof":     "搜索模式只能是 vector 或 hybrid",
+	})
 }
 
 type GalleryVectorSearchRequest struct {
-	Query string `json:"query" binding:"required,min=1,max=500"` // 搜索查询文本
-	Page  int    `json:"page" binding:"omitempty,min=1"`         // 页码
+	Query string `json:"query" binding:"required,min=1,max=500"`       // 搜索查询文本
+	Page  int    `json:"page" binding:"omitempty,min=1"`               // 页码
+	Mode  string `json:"mode" binding:"omitempty,oneof=vector hybrid"` // 搜索模式：vector(默认)/hybrid(关键词+向量融合)
+	VectorSearchFilters
 }
 
 func (r *GalleryVectorSearchRequest) GetValidationMessages() map[string]string {
-	return map[string]string{
+	return withFilterMessages(map[string]string{
 		"Query.required": "搜索查询不能为空",
 		"Query.min":      "搜索查询至少需要1个字符",
 		"Query.max":      "搜索查询不能超过500个字符",
 		"Page.min":       "页码必须大于等于1",
-	}
+		"Mode.oneof":     "搜索模式只能是 vector 或 hybrid",
+	})
 }
 
 type VectorSearchFileResult struct {
@@ -137,3 +171,55 @@ func ConvertFileToInfo(file *models.File) *FileInfo {
 		Resolution:    file.Resolution,
 	}
 }
+
+type SearchFeedbackRequest struct {
+	Query      string  `json:"query" binding:"required,min=1,max=500"`       // 产生该结果的搜索查询
+	FileID     string  `json:"file_id" binding:"required,max=32"`            // 被评价的结果文件ID
+	Relevant   *bool   `json:"relevant" binding:"required"`                  // 是否相关
+	Similarity float64 `json:"similarity" binding:"omitempty,min=0,max=1"`   // 结果的相似度
+	Rank       int     `json:"rank" binding:"omitempty,min=1"`               // 结果在列表中的位置（从1开始）
+	Mode       string  `json:"mode" binding:"omitempty,oneof=vector hybrid"` // 搜索模式
+	Scope      string  `json:"scope" binding:"omitempty,oneof=user gallery"` // 搜索范围：user(我的文件)/gallery(画廊)
+}
+
+func (r *SearchFeedbackRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Query.required":    "搜索查询不能为空",
+		"Query.max":         "搜索查询不能超过500个字符",
+		"FileID.required":   "文件ID不能为空",
+		"FileID.max":        "文件ID格式错误",
+		"Relevant.required": "请指定结果是否相关",
+		"Similarity.min":    "相似度不能小于0",
+		"Similarity.max":    "相似度不能大于1",
+		"Rank.min":          "结果位置必须大于等于1",
+		"Mode.oneof":        "搜索模式只能是 vector 或 hybrid",
+		"Scope.oneof":       "搜索范围只能是 user 或 gallery",
+	}
+}
+
+type SearchFeedbackListRequest struct {
+	Relevant *bool  `form:"relevant"`                                     // 按是否相关过滤
+	Mode     string `form:"mode" binding:"omitempty,oneof=vector hybrid"` // 按搜索模式过滤
+	Page     int    `form:"page" binding:"omitempty,min=1"`               // 页码
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`      // 每页数量
+}
+
+func (r *SearchFeedbackListRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Mode.oneof": "搜索模式只能是 vector 或 hybrid",
+		"Page.min":   "页码必须大于等于1",
+		"Limit.min":  "每页数量不能小于1",
+		"Limit.max":  "每页数量不能超过100",
+	}
+}
+
+type SearchFeedbackStatsRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"` // 统计最近天数，默认30
+}
+
+func (r *SearchFeedbackStatsRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Days.min": "统计天数不能小于1",
+		"Days.max": "统计天数不能超过365",
+	}
+}
@@ -24,7 +24,7 @@ func RegenerateVectors(c *gin.Context) {
 		updated := db.Model(&models.FileVector{}).Where("file_id = ?", singleID).
 			Updates(map[string]interface{}{"status": common.VectorStatusReset, "model": currentModel})
 		if updated.Error != nil {
-			logger.Error("单文件更新向量状态失败: %v", updated.Error)
+			logger.ErrorContext(c.Request.Context(), "单文件更新向量状态失败: %v", updated.Error)
 			errors.HandleError(c, errors.New(errors.CodeDBUpdateFailed, "更新向量状态失败"))
 			return
 		}
@@ -56,13 +56,13 @@ func RegenerateVectors(c *gin.Context) {
 		Joins("JOIN file_ai_info ai ON i.id = ai.file_id").
 		Where("ai.description IS NOT NULL AND ai.description != ''").
 		Count(&totalCount).Error; err != nil {
-		logger.Error("统计文件总数失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "统计文件总数失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, "统计文件失败"))
 		return
 	}
 
 	if totalCount == 0 {
-		logger.Warn("没有找到需要生成向量的文件")
+		logger.WarnContext(c.Request.Context(), "没有找到需要生成向量的文件")
 		errors.ResponseSuccess(c, map[string]interface{}{
 			"total":   0,
 			"message": "没有找到需要生成向量的文件",
@@ -78,7 +78,7 @@ func RegenerateVectors(c *gin.Context) {
 		})
 
 	if result.Error != nil {
-		logger.Error("更新现有向量状态失败: %v", result.Error)
+		logger.ErrorContext(c.Request.Context(), "更新现有向量状态失败: %v", result.Error)
 		errors.HandleError(c, errors.New(errors.CodeDBUpdateFailed, "更新向量状态失败"))
 		return
 	}
@@ -91,7 +91,7 @@ func RegenerateVectors(c *gin.Context) {
 		Where("ai.description IS NOT NULL AND ai.description != ''").
 		Where("i.id NOT IN (SELECT file_id FROM file_vector)").
 		Find(&missingFiles).Error; err != nil {
-		logger.Error("查询缺失向量记录的文件失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "查询缺失向量记录的文件失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, "查询文件失败"))
 		return
 	}
@@ -111,7 +111,7 @@ func RegenerateVectors(c *gin.Context) {
 		}
 
 		if err := db.Create(resetVector).Error; err != nil {
-			logger.Warn("为文件 %s 创建重置向量记录失败: %v", file.ID, err)
+			logger.WarnContext(c.Request.Context(), "为文件 %s 创建重置向量记录失败: %v", file.ID, err)
 		} else {
 			createdCount++
 		}
@@ -148,7 +148,7 @@ func GetVectorTaskStatus(c *gin.Context) {
 
 func DebugVectorStatus(c *gin.Context) {
 
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	var stats = make(map[string]interface{})
 
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"pixelpunk/internal/controllers/search/dto"
+	"pixelpunk/internal/middleware"
+	vectorSvc "pixelpunk/internal/services/vector"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubmitSearchFeedback 用户标记搜索结果是否相关
+func SubmitSearchFeedback(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.SearchFeedbackRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	feedback, err := vectorSvc.SaveSearchFeedback(middleware.GetCurrentUserID(c), vectorSvc.SearchFeedbackInput{
+		Query:      req.Query,
+		FileID:     req.FileID,
+		Relevant:   *req.Relevant,
+		Similarity: req.Similarity,
+		Rank:       req.Rank,
+		Mode:       req.Mode,
+		Scope:      req.Scope,
+	})
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, feedback, "感谢反馈")
+}
+
+// GetSearchFeedbackList 管理员查看搜索评价记录
+func GetSearchFeedbackList(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.SearchFeedbackListRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := vectorSvc.ListSearchFeedback(req.Relevant, req.Mode, req.Page, req.Limit)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// GetSearchFeedbackStats 管理员查看评价统计及建议阈值
+func GetSearchFeedbackStats(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.SearchFeedbackStatsRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	stats, err := vectorSvc.GetSearchFeedbackStats(req.Days)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, stats, "获取成功")
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+// 搜索模式
+const (
+	searchModeVector = "vector"
+	searchModeHybrid = "hybrid"
+)
+
+const maxKeywordTerms = 5
+
+// likeEscaper 转义 LIKE 通配符，使关键词中的 % 与 _ 按字面匹配；使用 ! 作为转义符，避免反斜杠在各数据库中的语义差异
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// getHybridSearchConfig 获取混合搜索的融合参数（RRF: weight / (k + rank)）
+func getHybridSearchConfig() (vectorWeight, keywordWeight float64, k int) {
+	vectorWeight = setting.GetFloatDirectFromDB("vector", "hybrid_vector_weight", 1.0)
+	keywordWeight = setting.GetFloatDirectFromDB("vector", "hybrid_keyword_weight", 1.0)
+	k = setting.GetIntDirectFromDB("vector", "hybrid_rrf_k", 60)
+	if vectorWeight < 0 {
+		vectorWeight = 0
+	}
+	if keywordWeight < 0 {
+		keywordWeight = 0
+	}
+	if k <= 0 {
+		k = 60
+	}
+	return vectorWeight, keywordWeight, k
+}
+
+// keywordSearchFileIDs 关键词检索文件名、描述与标签名，scope 用于限定可见范围
+func keywordSearchFileIDs(query string, limit int, scope func(*gorm.DB) *gorm.DB) ([]string, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if len(terms) > maxKeywordTerms {
+		terms = terms[:maxKeywordTerms]
+	}
+
+	db := database.ReadDB()
+	conds := db.Where("1 = 0")
+	for _, term := range terms {
+		like := "%" + likeEscaper.Replace(term) + "%"
+		conds = conds.Or("display_name LIKE ? ESCAPE '!' OR original_name LIKE ? ESCAPE '!' OR description LIKE ? ESCAPE '!'", like, like, like).
+			Or("id IN (?)", db.Model(&models.FileGlobalTagRelation{}).
+				Select("file_global_tag_relation.file_id").
+				Joins("JOIN global_tag ON global_tag.id = file_global_tag_relation.tag_id").
+				Where("global_tag.name LIKE ? ESCAPE '!'", like))
+	}
+
+	var ids []string
+	q := db.Model(&models.File{}).Where("status <> ?", "pending_deletion").Where(conds)
+	if scope != nil {
+		q = scope(q)
+	}
+	if err := q.Order("created_at DESC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// fuseRankings 使用加权倒数排名融合（RRF）合并向量与关键词结果
+func fuseRankings(vectorResults []vector.VectorSearchResult, keywordIDs []string, vectorWeight, keywordWeight float64, k int) []vector.VectorSearchResult {
+	scores := make(map[string]float64, len(vectorResults)+len(keywordIDs))
+	merged := make(map[string]vector.VectorSearchResult, len(vectorResults)+len(keywordIDs))
+	order := make([]string, 0, len(vectorResults)+len(keywordIDs))
+
+	for i, r := range vectorResults {
+		if _, ok := merged[r.FileID]; ok {
+			continue
+		}
+		merged[r.FileID] = r
+		order = append(order, r.FileID)
+		scores[r.FileID] += vectorWeight / float64(k+i+1)
+	}
+	for i, id := range keywordIDs {
+		if _, ok := merged[id]; !ok {
+			merged[id] = vector.VectorSearchResult{FileID: id}
+			order = append(order, id)
+		}
+		scores[id] += keywordWeight / float64(k+i+1)
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	results := make([]vector.VectorSearchResult, 0, len(order))
+	for _, id := range order {
+		results = append(results, merged[id])
+	}
+	return results
+}
+
+// searchModeOrDefault 未指定模式时默认为纯向量搜索
+func searchModeOrDefault(mode string) string {
+	if mode == "" {
+		return searchModeVector
+	}
+	return mode
+}
+
+// applySearchMode 混合模式下追加关键词检索并融合排序，其余模式原样返回向量结果
+func applySearchMode(mode, query string, vectorResults []vector.VectorSearchResult, limit int, scope func(*gorm.DB) *gorm.DB) []vector.VectorSearchResult {
+	if mode != searchModeHybrid {
+		return vectorResults
+	}
+
+	keywordIDs, err := keywordSearchFileIDs(query, limit, scope)
+	if err != nil {
+		logger.Warn("混合搜索关键词检索失败，仅使用向量结果: %v", err)
+		return vectorResults
+	}
+
+	vectorWeight, keywordWeight, k := getHybridSearchConfig()
+	return fuseRankings(vectorResults, keywordIDs, vectorWeight, keywordWeight, k)
+}
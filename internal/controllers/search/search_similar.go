@@ -28,7 +28,7 @@ func SearchSimilarFiles(c *gin.Context) {
 
 	threshold, _, err := getVectorConfig()
 	if err != nil {
-		logger.Warn("获取向量配置失败，使用默认阈值: %v", err)
+		logger.WarnContext(c.Request.Context(), "获取向量配置失败，使用默认阈值: %v", err)
 		threshold = 0.7
 	}
 
@@ -38,21 +38,15 @@ func SearchSimilarFiles(c *gin.Context) {
 		return
 	}
 
-	userIDUint := uint(0)
-	if userID, exists := c.Get("user_id"); exists {
-		if uid, ok := userID.(uint); ok {
-			userIDUint = uid
-		}
-	}
-
-	searchResults, err := engine.SearchSimilarByFileID(fileID, limit+1, userIDUint, threshold)
+	filter := accessFilterFromContext(c)
+	searchResults, err := engine.SearchSimilarByFileIDWithFilter(fileID, limit+1, filter, threshold)
 	if err != nil {
-		logger.Error("相似文件搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "相似文件搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	results := make([]dto.VectorSearchResult, 0, limit)
 	for _, result := range searchResults {
@@ -61,10 +55,9 @@ func SearchSimilarFiles(c *gin.Context) {
 		}
 
 		var file models.File
-		if err := db.Where("id = ?", result.FileID).
+		if err := db.Scopes(filterScope(filter)).Where("id = ?", result.FileID).
 			Where("status <> ?", "pending_deletion").
 			First(&file).Error; err != nil {
-			logger.Warn("文件信息查询失败 [%s]: %v", result.FileID, err)
 			continue
 		}
 
@@ -138,7 +131,7 @@ func GallerySimilarFiles(c *gin.Context) {
 	limit := 15
 	threshold, _, err := getVectorConfig()
 	if err != nil {
-		logger.Warn("获取向量配置失败，使用默认阈值: %v", err)
+		logger.WarnContext(c.Request.Context(), "获取向量配置失败，使用默认阈值: %v", err)
 		threshold = 0.7
 	}
 
@@ -148,14 +141,14 @@ func GallerySimilarFiles(c *gin.Context) {
 		return
 	}
 
-	searchResults, err := engine.SearchSimilarByFileID(fileID, limit*3, 0, threshold)
+	searchResults, err := engine.SearchSimilarByFileIDWithFilter(fileID, limit*3, vector.VectorFilter{PublicOnly: true}, threshold)
 	if err != nil {
-		logger.Error("Gallery相似文件搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "Gallery相似文件搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.GetDB()
+	db := database.ReadDB()
 	results := make([]dto.VectorSearchResult, 0, limit)
 
 	for _, result := range searchResults {
@@ -248,7 +241,7 @@ func UserSimilarFiles(c *gin.Context) {
 	limit := 15
 	threshold, _, err := getVectorConfig()
 	if err != nil {
-		logger.Warn("获取向量配置失败，使用默认阈值: %v", err)
+		logger.WarnContext(c.Request.Context(), "获取向量配置失败，使用默认阈值: %v", err)
 		threshold = 0.7
 	}
 
@@ -260,12 +253,12 @@ func UserSimilarFiles(c *gin.Context) {
 
 	searchResults, err := engine.SearchSimilarByFileID(fileID, limit+1, userID, threshold)
 	if err != nil {
-		logger.Error("用户相似文件搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "用户相似文件搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.GetDB()
+	db := database.ReadDB()
 	results := make([]dto.VectorSearchResult, 0, limit)
 
 	for _, result := range searchResults {
@@ -357,7 +350,7 @@ func AdminSimilarFiles(c *gin.Context) {
 	limit := 15
 	threshold, _, err := getVectorConfig()
 	if err != nil {
-		logger.Warn("获取向量配置失败，使用默认阈值: %v", err)
+		logger.WarnContext(c.Request.Context(), "获取向量配置失败，使用默认阈值: %v", err)
 		threshold = 0.7
 	}
 
@@ -369,12 +362,12 @@ func AdminSimilarFiles(c *gin.Context) {
 
 	searchResults, err := engine.SearchSimilarByFileID(fileID, limit+1, 0, threshold)
 	if err != nil {
-		logger.Error("管理员相似文件搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "管理员相似文件搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	type AdminSearchResult struct {
 		dto.VectorSearchResult
@@ -392,7 +385,7 @@ func AdminSimilarFiles(c *gin.Context) {
 		if err := db.Where("id = ?", result.FileID).
 			Where("status <> ?", "pending_deletion").
 			First(&file).Error; err != nil {
-			logger.Warn("文件信息查询失败 [%s]: %v", result.FileID, err)
+			logger.WarnContext(c.Request.Context(), "文件信息查询失败 [%s]: %v", result.FileID, err)
 			continue
 		}
 
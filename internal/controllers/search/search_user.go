@@ -57,17 +57,25 @@ func UserVectorSearch(c *gin.Context) {
 		return
 	}
 
+	filter, err := applyFacetFilters(vector.VectorFilter{UserID: userID}, req.VectorSearchFilters)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
 	offset := (page - 1) * size
-	searchLimit := size * 2
+	searchLimit := recallLimit(engine, filter, size*2)
 
-	searchResults, err := engine.SearchFiles(req.Query, searchLimit, userID, threshold)
+	searchResults, err := engine.SearchFilesWithFilter(req.Query, searchLimit, filter, threshold)
 	if err != nil {
-		logger.Error("用户向量搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "用户向量搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.DB
+	searchResults = applySearchMode(req.Mode, req.Query, searchResults, searchLimit, filterScope(filter))
+
+	db := database.ReadDB()
 
 	var results []map[string]interface{}
 	totalResults := len(searchResults)
@@ -81,7 +89,7 @@ func UserVectorSearch(c *gin.Context) {
 		}
 
 		var file models.File
-		if err := db.Where("id = ? AND user_id = ?", result.FileID, userID).
+		if err := db.Scopes(filterScope(filter)).Where("id = ?", result.FileID).
 			Where("status <> ?", "pending_deletion").
 			First(&file).Error; err != nil {
 			continue
@@ -149,6 +157,7 @@ func UserVectorSearch(c *gin.Context) {
 		"search_info": gin.H{
 			"query":        req.Query,
 			"threshold":    threshold,
+			"mode":         searchModeOrDefault(req.Mode),
 			"process_time": processTime.String(),
 		},
 	}
@@ -161,7 +170,7 @@ func GalleryVectorSearch(c *gin.Context) {
 
 	req, err := common.ValidateRequest[dto.GalleryVectorSearchRequest](c)
 	if err != nil {
-		logger.Error("Gallery向量搜索参数验证失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "Gallery向量搜索参数验证失败: %v", err)
 		errors.HandleError(c, err)
 		return
 	}
@@ -174,7 +183,7 @@ func GalleryVectorSearch(c *gin.Context) {
 	threshold := getSearchThreshold()
 	_, maxResults, err := getVectorConfig()
 	if err != nil {
-		logger.Error("Gallery向量搜索获取配置失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "Gallery向量搜索获取配置失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("获取向量配置失败: %v", err)))
 		return
 	}
@@ -189,27 +198,35 @@ func GalleryVectorSearch(c *gin.Context) {
 
 	engine := vector.GetEngine()
 	if engine == nil {
-		logger.Error("Gallery向量搜索: 向量引擎为空")
+		logger.ErrorContext(c.Request.Context(), "Gallery向量搜索: 向量引擎为空")
 		errors.HandleError(c, errors.New(errors.CodeServiceUnavailable, "向量搜索服务不可用"))
 		return
 	}
 	if !engine.IsEnabled() {
-		logger.Error("Gallery向量搜索: 向量引擎未启用")
+		logger.ErrorContext(c.Request.Context(), "Gallery向量搜索: 向量引擎未启用")
 		errors.HandleError(c, errors.New(errors.CodeServiceUnavailable, "向量搜索服务不可用"))
 		return
 	}
 
+	filter, err := applyFacetFilters(vector.VectorFilter{PublicOnly: true}, req.VectorSearchFilters)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
 	offset := (page - 1) * size
-	searchLimit := size * 3
+	searchLimit := recallLimit(engine, filter, size*3)
 
-	searchResults, err := engine.SearchFiles(req.Query, searchLimit, 0, threshold)
+	searchResults, err := engine.SearchFilesWithFilter(req.Query, searchLimit, filter, threshold)
 	if err != nil {
-		logger.Error("Gallery向量搜索失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "Gallery向量搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
-	db := database.DB
+	searchResults = applySearchMode(req.Mode, req.Query, searchResults, searchLimit, filterScope(filter))
+
+	db := database.ReadDB()
 
 	var results []map[string]interface{}
 	var filteredResults []string
@@ -218,8 +235,7 @@ func GalleryVectorSearch(c *gin.Context) {
 	for _, result := range searchResults {
 
 		var file models.File
-		err := db.Where("id = ? AND access_level = ? AND is_recommended = ?",
-			result.FileID, "public", true).First(&file).Error
+		err := db.Scopes(filterScope(filter)).Where("id = ?", result.FileID).First(&file).Error
 		if err == nil {
 			filteredResults = append(filteredResults, result.FileID)
 			publicCount++
@@ -231,8 +247,7 @@ func GalleryVectorSearch(c *gin.Context) {
 
 	for _, result := range searchResults {
 		var file models.File
-		err := db.Where("id = ? AND access_level = ? AND is_recommended = ?",
-			result.FileID, "public", true).First(&file).Error
+		err := db.Scopes(filterScope(filter)).Where("id = ?", result.FileID).First(&file).Error
 		if err != nil {
 			continue
 		}
@@ -317,6 +332,7 @@ func GalleryVectorSearch(c *gin.Context) {
 		"search_info": gin.H{
 			"query":        req.Query,
 			"threshold":    threshold,
+			"mode":         searchModeOrDefault(req.Mode),
 			"process_time": processTime.String(),
 		},
 	}
@@ -3,17 +3,20 @@ package search
 import (
 	"fmt"
 	"pixelpunk/internal/controllers/search/dto"
+	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/logger"
 	"pixelpunk/pkg/vector"
+	"strings"
 	"time"
 
 	"pixelpunk/internal/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func getVectorConfig() (threshold float32, maxResults int, err error) {
@@ -30,6 +33,83 @@ func getSearchThreshold() float32 {
 	return float32(thresholdVal)
 }
 
+// accessFilterFromContext 登录用户只能搜索自己的文件，匿名请求只能搜索公开且推荐的文件
+func accessFilterFromContext(c *gin.Context) vector.VectorFilter {
+	userID := middleware.GetCurrentUserID(c)
+	return vector.VectorFilter{UserID: userID, PublicOnly: userID == 0}
+}
+
+// applyFacetFilters 将请求中的分面条件合并到过滤条件，日期按自然日闭区间处理
+func applyFacetFilters(filter vector.VectorFilter, facets dto.VectorSearchFilters) (vector.VectorFilter, error) {
+	filter.TagIDs = facets.TagIDs
+	filter.FolderID = facets.FolderID
+	filter.NSFW = facets.NSFW
+	for _, format := range facets.Formats {
+		filter.Formats = append(filter.Formats, strings.ToLower(strings.TrimPrefix(format, ".")))
+	}
+	if facets.DateFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", facets.DateFrom, time.Local)
+		if err != nil {
+			return filter, errors.New(errors.CodeInvalidParameter, "开始日期格式错误")
+		}
+		filter.CreatedFrom = from.Unix()
+	}
+	if facets.DateTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", facets.DateTo, time.Local)
+		if err != nil {
+			return filter, errors.New(errors.CodeInvalidParameter, "结束日期格式错误")
+		}
+		filter.CreatedTo = to.AddDate(0, 0, 1).Unix() - 1
+	}
+	if filter.CreatedFrom > 0 && filter.CreatedTo > 0 && filter.CreatedFrom > filter.CreatedTo {
+		return filter, errors.New(errors.CodeInvalidParameter, "开始日期不能晚于结束日期")
+	}
+	return filter, nil
+}
+
+// filterScope 与存储层过滤条件一致的数据库条件，防止元数据尚未同步时返回不符合条件的文件
+func filterScope(filter vector.VectorFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.UserID > 0 {
+			db = db.Where("user_id = ?", filter.UserID)
+		}
+		if filter.PublicOnly {
+			db = db.Where("access_level = ? AND is_recommended = ?", "public", true)
+		}
+		if len(filter.TagIDs) > 0 {
+			db = db.Where("id IN (?)", database.ReadDB().Model(&models.FileGlobalTagRelation{}).
+				Select("file_id").
+				Where("tag_id IN ?", filter.TagIDs).
+				Group("file_id").
+				Having("COUNT(DISTINCT tag_id) = ?", len(filter.TagIDs)))
+		}
+		if filter.FolderID != "" {
+			db = db.Where("folder_id = ?", filter.FolderID)
+		}
+		if len(filter.Formats) > 0 {
+			db = db.Where("LOWER(format) IN ?", filter.Formats)
+		}
+		if filter.CreatedFrom > 0 {
+			db = db.Where("created_at >= ?", time.Unix(filter.CreatedFrom, 0))
+		}
+		if filter.CreatedTo > 0 {
+			db = db.Where("created_at <= ?", time.Unix(filter.CreatedTo, 0))
+		}
+		if filter.NSFW != nil {
+			db = db.Where("nsfw = ?", *filter.NSFW)
+		}
+		return db
+	}
+}
+
+// recallLimit 存储层无法完整执行过滤时扩大召回数量，由数据库条件二次过滤
+func recallLimit(engine *vector.VectorEngine, filter vector.VectorFilter, limit int) int {
+	if engine.StoreFilterReady(filter) {
+		return limit
+	}
+	return limit * 3
+}
+
 func VectorSearch(c *gin.Context) {
 	startTime := time.Now()
 
@@ -57,31 +137,37 @@ func VectorSearch(c *gin.Context) {
 		return
 	}
 
-	searchResults, err := engine.SearchFiles(req.Query, req.Limit, 0, req.Threshold)
+	filter, err := applyFacetFilters(accessFilterFromContext(c), req.VectorSearchFilters)
 	if err != nil {
-		logger.Error("向量搜索失败: %v", err)
+		errors.HandleError(c, err)
+		return
+	}
+
+	searchResults, err := engine.SearchFilesWithFilter(req.Query, recallLimit(engine, filter, req.Limit), filter, req.Threshold)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "向量搜索失败: %v", err)
 		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("搜索失败: %v", err)))
 		return
 	}
 
+	searchResults = applySearchMode(req.Mode, req.Query, searchResults, req.Limit, filterScope(filter))
+	if req.Mode == searchModeHybrid && len(searchResults) > req.Limit {
+		searchResults = searchResults[:req.Limit]
+	}
+
 	results := make([]dto.VectorSearchResult, 0, len(searchResults))
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	for _, result := range searchResults {
-		if result.Similarity < req.Threshold {
+		// 混合模式下关键词命中的结果没有相似度，不按阈值过滤
+		if req.Mode != searchModeHybrid && result.Similarity < req.Threshold {
 			continue
 		}
 
 		var file models.File
-		if err := db.Where("id = ?", result.FileID).
+		if err := db.Scopes(filterScope(filter)).Where("id = ?", result.FileID).
 			Where("status <> ?", "pending_deletion").
 			First(&file).Error; err != nil {
-			results = append(results, dto.VectorSearchResult{
-				FileID:      result.FileID,
-				Similarity:  result.Similarity,
-				Description: result.Description,
-				FileInfo:    nil,
-			})
 			continue
 		}
 
@@ -116,7 +202,7 @@ func GetVectorStats(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	var stats dto.VectorStatsResponse
 
@@ -173,7 +259,7 @@ func GetVectorHealth(c *gin.Context) {
 	err := engine.HealthCheck()
 	if err != nil {
 		response.Message = fmt.Sprintf("健康检查失败: %v", err)
-		logger.Error("向量引擎健康检查失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "向量引擎健康检查失败: %v", err)
 	} else {
 		response.Status = "ok"
 		response.DatabaseOK = true
@@ -80,6 +80,10 @@ type OAuthProvidersDTO struct {
 	GithubEnabled  bool `json:"github_enabled"`
 	GoogleEnabled  bool `json:"google_enabled"`
 	LinuxdoEnabled bool `json:"linuxdo_enabled"`
+
+	SAMLEnabled    bool   `json:"saml_enabled"`     // SAML 单点登录是否可用
+	SAMLButtonText string `json:"saml_button_text"` // 登录页SSO按钮文字
+	SSOEnforced    bool   `json:"sso_enforced"`     // 是否强制SSO登录（隐藏账号密码登录与注册）
 }
 
 type GlobalSettingsResponseDTO struct {
@@ -172,6 +176,38 @@ func (d *EmailTestDTO) GetValidationMessages() map[string]string {
 	}
 }
 
+type LDAPTestDTO struct {
+	URL                string `json:"url" binding:"required"` // LDAP服务器地址
+	StartTLS           bool   `json:"start_tls"`              // 使用StartTLS
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`   // 跳过证书校验
+	BindDN             string `json:"bind_dn"`                // 服务账号DN，为空时匿名绑定
+	BindPassword       string `json:"bind_password"`          // 服务账号密码
+	BaseDN             string `json:"base_dn"`                // 用户搜索Base DN
+	TestAccount        string `json:"test_account"`           // 可选，测试登录的账号
+	TestPassword       string `json:"test_password"`          // 可选，测试登录的密码
+}
+
+func (d *LDAPTestDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"URL.required": "LDAP服务器地址不能为空",
+	}
+}
+
+type ErrorReportingTestDTO struct {
+	Provider     string `json:"provider" binding:"required,oneof=sentry webhook"` // 上报方式
+	SentryDSN    string `json:"sentry_dsn"`                                       // Sentry DSN
+	WebhookURL   string `json:"webhook_url"`                                      // 回调地址
+	WebhookToken string `json:"webhook_token"`                                    // 回调鉴权令牌
+	Environment  string `json:"environment"`                                      // 环境名称
+}
+
+func (d *ErrorReportingTestDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Provider.required": "上报方式不能为空",
+		"Provider.oneof":    "上报方式只能是 sentry 或 webhook",
+	}
+}
+
 type TestMailDTO struct {
 	Email           string `json:"email" binding:"required,email"`             // 测试接收邮箱
 	SmtpHost        string `json:"smtp_host" binding:"required"`               // SMTP服务器地址
@@ -198,18 +234,18 @@ func (d *TestMailDTO) GetValidationMessages() map[string]string {
 }
 
 type VectorTestDTO struct {
-	Provider string `json:"provider" binding:"required"`      // 向量化提供者
-	Model    string `json:"model" binding:"required"`         // 向量化模型
-	APIKey   string `json:"api_key" binding:"required"`       // API密钥
-	BaseURL  string `json:"base_url"`                         // API代理地址
-	Timeout  int    `json:"timeout" binding:"required,min=1"` // 超时时间(秒)
+	Provider string `json:"provider" binding:"required,oneof=openai ollama"` // 向量化提供者
+	Model    string `json:"model" binding:"required"`                        // 向量化模型
+	APIKey   string `json:"api_key"`                                         // API密钥（ollama无需）
+	BaseURL  string `json:"base_url"`                                        // API代理地址 / Ollama服务地址
+	Timeout  int    `json:"timeout" binding:"required,min=1"`                // 超时时间(秒)
 }
 
 func (d *VectorTestDTO) GetValidationMessages() map[string]string {
 	return map[string]string{
 		"Provider.required": "向量化提供者不能为空",
+		"Provider.oneof":    "向量化提供者只能是 openai 或 ollama",
 		"Model.required":    "向量化模型不能为空",
-		"APIKey.required":   "API密钥不能为空",
 		"Timeout.required":  "超时时间不能为空",
 		"Timeout.min":       "超时时间必须大于0",
 	}
@@ -218,6 +254,9 @@ func (d *VectorTestDTO) GetValidationMessages() map[string]string {
 type QdrantTestDTO struct {
 	QdrantURL     string `json:"qdrant_url" binding:"required"`           // Qdrant服务器地址
 	QdrantTimeout int    `json:"qdrant_timeout" binding:"required,min=1"` // 连接超时时间(秒)
+	QdrantAPIKey  string `json:"qdrant_api_key"`                          // API密钥，为空时不鉴权
+	QdrantCACert  string `json:"qdrant_ca_cert"`                          // CA证书(PEM内容或文件路径)
+	TLSSkipVerify bool   `json:"qdrant_tls_skip_verify"`                  // 跳过证书校验
 }
 
 func (d *QdrantTestDTO) GetValidationMessages() map[string]string {
@@ -5,6 +5,8 @@ import (
 	"pixelpunk/internal/controllers/setting/dto"
 	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/errorreport"
+	ldapService "pixelpunk/internal/services/ldap"
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/email"
@@ -72,11 +74,17 @@ func UpdateSetting(c *gin.Context) {
 		return
 	}
 
+	var before interface{}
+	if current, err := setting.GetSetting(req.Key); err == nil {
+		before = map[string]interface{}{req.Key: current.Value}
+	}
+
 	result, err := setting.UpdateSetting(req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "setting.update", "setting", req.Group+"."+req.Key, before, map[string]interface{}{req.Key: req.Value})
 
 	errors.ResponseSuccess(c, result, "更新设置成功")
 }
@@ -88,11 +96,17 @@ func DeleteSetting(c *gin.Context) {
 		return
 	}
 
+	var before interface{}
+	if current, err := setting.GetSetting(key); err == nil {
+		before = map[string]interface{}{key: current.Value}
+	}
+
 	err := setting.DeleteSetting(key)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "setting.delete", "setting", key, before, nil)
 
 	errors.ResponseSuccess(c, nil, "删除设置成功")
 }
@@ -176,12 +190,20 @@ func BatchUpsertSettings(c *gin.Context) {
 		}
 	}
 
+	before := settingsAuditSnapshot(req.Settings)
+
 	result, err := setting.BatchUpsertSettings(req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	after := make(map[string]interface{}, len(req.Settings))
+	for _, item := range req.Settings {
+		after[item.Group+"."+item.Key] = item.Value
+	}
+	middleware.SetAuditChange(c, "setting.update", "setting", "", before, after)
+
 	if containsSecuritySettings && len(result.Failed) == 0 {
 		userID := middleware.GetCurrentUserID(c)
 
@@ -306,40 +328,114 @@ func GetLegalDocuments(c *gin.Context) {
 	errors.ResponseSuccess(c, result, "获取法律文档成功")
 }
 
-func TestVectorSettings(c *gin.Context) {
-	req, err := common.ValidateRequest[dto.VectorTestDTO](c)
+func TestLDAPSettings(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.LDAPTestDTO](c)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	if req.APIKey == "" {
-		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "API密钥不能为空"))
+	// 未在请求中给出的搜索相关配置沿用已保存的设置
+	cfg := ldapService.GetConfig()
+	cfg.URL = req.URL
+	cfg.StartTLS = req.StartTLS
+	cfg.InsecureSkipVerify = req.InsecureSkipVerify
+	cfg.BindDN = req.BindDN
+	cfg.BindPassword = req.BindPassword
+	if req.BaseDN != "" {
+		cfg.BaseDN = req.BaseDN
+	}
+
+	if err := ldapService.TestConnection(cfg); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
 		return
 	}
 
-	if req.Provider != "openai" {
-		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "目前仅支持OpenAI提供者"))
+	if req.TestAccount == "" {
+		errors.ResponseSuccess(c, nil, "LDAP连接测试成功")
 		return
 	}
 
-	client := vector.NewOpenAIEmbeddingClientWithConfig(
-		req.APIKey,
-		req.BaseURL,
-		req.Model,
-		req.Timeout,
-	)
+	entry, err := ldapService.Authenticate(cfg, req.TestAccount, req.TestPassword)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"dn":       entry.DN,
+		"username": entry.Username,
+		"email":    entry.Email,
+		"groups":   entry.Groups,
+		"role":     entry.Role,
+	}, "LDAP登录测试成功")
+}
+
+/* TestErrorReporting 使用表单中的配置发送一条测试事件，未填写的字段沿用已保存的设置 */
+func TestErrorReporting(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.ErrorReportingTestDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	cfg := errorreport.GetConfig()
+	cfg.Provider = req.Provider
+	if req.SentryDSN != "" {
+		cfg.SentryDSN = req.SentryDSN
+	}
+	if req.WebhookURL != "" {
+		cfg.WebhookURL = req.WebhookURL
+	}
+	if req.WebhookToken != "" {
+		cfg.WebhookToken = req.WebhookToken
+	}
+	if req.Environment != "" {
+		cfg.Environment = req.Environment
+	}
 
-	if client == nil {
-		logger.Error("临时OpenAI客户端创建失败")
-		errors.HandleError(c, errors.New(errors.CodeInternal, "向量化客户端初始化失败，请检查配置参数"))
+	if err := errorreport.SendTest(cfg); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "测试事件发送失败: "+err.Error()))
 		return
 	}
 
+	errors.ResponseSuccess(c, nil, "测试事件已发送")
+}
+
+func TestVectorSettings(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.VectorTestDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	var client vector.EmbeddingProvider
+	if req.Provider == vector.EmbeddingProviderOllama {
+		client = vector.NewOllamaEmbeddingClientWithConfig(req.BaseURL, req.Model, req.Timeout)
+	} else {
+		if req.APIKey == "" {
+			errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "API密钥不能为空"))
+			return
+		}
+
+		openaiClient := vector.NewOpenAIEmbeddingClientWithConfig(
+			req.APIKey,
+			req.BaseURL,
+			req.Model,
+			req.Timeout,
+		)
+		if openaiClient == nil {
+			logger.ErrorContext(c.Request.Context(), "临时OpenAI客户端创建失败")
+			errors.HandleError(c, errors.New(errors.CodeInternal, "向量化客户端初始化失败，请检查配置参数"))
+			return
+		}
+		client = openaiClient
+	}
+
 	testText := "这是一个测试文本，用于验证向量化配置是否正确"
 	_, err = client.GenerateEmbedding(testText)
 	if err != nil {
-		logger.Error("向量化测试失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "向量化测试失败: %v", err)
 
 		var errorMessage string
 		var details string
@@ -416,14 +512,22 @@ func TestQdrantConnection(c *gin.Context) {
 		timeout = 30
 	}
 
-	startTime := time.Now()
-	client := vector.NewQdrantClient(req.QdrantURL, timeout)
+	client, err := vector.NewQdrantClientWithOptions(req.QdrantURL, timeout, vector.QdrantOptions{
+		APIKey:             req.QdrantAPIKey,
+		CACert:             req.QdrantCACert,
+		InsecureSkipVerify: req.TLSSkipVerify,
+	})
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
+		return
+	}
 
+	startTime := time.Now()
 	err = client.InitCollection()
 	responseTime := time.Since(startTime).Milliseconds()
 
 	if err != nil {
-		logger.Error("Qdrant连接测试失败: %v", err)
+		logger.ErrorContext(c.Request.Context(), "Qdrant连接测试失败: %v", err)
 
 		errorMessage := "Qdrant连接失败"
 		var details string
@@ -433,6 +537,10 @@ func TestQdrantConnection(c *gin.Context) {
 			details = "无法连接到Qdrant服务，请确保服务已启动且地址正确"
 		} else if strings.Contains(errorMsg, "timeout") || strings.Contains(errorMsg, "deadline exceeded") {
 			details = fmt.Sprintf("连接超时（%d秒），请检查网络或增加超时时间", timeout)
+		} else if strings.Contains(errorMsg, "401") || strings.Contains(errorMsg, "403") {
+			details = "Qdrant鉴权失败，请检查API密钥是否正确"
+		} else if strings.Contains(errorMsg, "x509") || strings.Contains(errorMsg, "tls") {
+			details = "TLS握手失败，请检查CA证书配置"
 		} else if strings.Contains(errorMsg, "404") {
 			details = "Qdrant服务未找到，请检查URL路径是否正确"
 		} else {
@@ -503,3 +611,22 @@ func TestProxy(c *gin.Context) {
 
 	errors.ResponseSuccess(c, result, "代理测试完成")
 }
+
+// settingsAuditSnapshot 记录批量修改前各设置项的值，键为 group.key
+func settingsAuditSnapshot(items []dto.SettingCreateDTO) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(items))
+	groups := make(map[string]map[string]interface{})
+	for _, item := range items {
+		values, ok := groups[item.Group]
+		if !ok {
+			if groupSettings, err := setting.GetSettingsByGroupAsMap(item.Group); err == nil {
+				values = groupSettings.Settings
+			}
+			groups[item.Group] = values
+		}
+		if value, exists := values[item.Key]; exists {
+			snapshot[item.Group+"."+item.Key] = value
+		}
+	}
+	return snapshot
+}
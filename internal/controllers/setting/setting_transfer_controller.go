@@ -0,0 +1,126 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSettingsImportSize 导入文件大小上限
+const maxSettingsImportSize = 5 << 20
+
+// ExportSettings 导出设置为 YAML 或 JSON 文件，可按分组导出，默认不包含敏感设置
+func ExportSettings(c *gin.Context) {
+	group := strings.TrimSpace(c.Query("group"))
+	format := strings.ToLower(c.DefaultQuery("format", "yaml"))
+	if format != "yaml" && format != "json" {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "导出格式只能是 yaml 或 json"))
+		return
+	}
+	includeSecrets := c.Query("include_secrets") == "true"
+
+	export, err := setting.ExportSettings(group, includeSecrets)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	var data []byte
+	contentType := "application/json; charset=utf-8"
+	if format == "json" {
+		data, err = json.MarshalIndent(export, "", "  ")
+	} else {
+		data, err = yaml.Marshal(export)
+		contentType = "application/yaml; charset=utf-8"
+	}
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeInternal, "生成导出文件失败"))
+		return
+	}
+
+	name := "settings"
+	if group != "" {
+		name += "_" + group
+	}
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102150405"), format)
+	middleware.SetAuditChange(c, "setting.export", "setting", group, nil, gin.H{"include_secrets": includeSecrets})
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// PreviewSettingsImport 校验导入文件并返回与当前设置的差异，不写入
+func PreviewSettingsImport(c *gin.Context) {
+	doc, err := readSettingsImport(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	preview, err := setting.PreviewSettingsImport(doc)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, preview, "导入预览生成成功")
+}
+
+// ImportSettings 导入设置，存在校验错误时不写入任何设置
+func ImportSettings(c *gin.Context) {
+	doc, err := readSettingsImport(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := setting.ImportSettings(doc)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if result.Applied > 0 {
+		changed := make(map[string]interface{}, len(result.Changes))
+		for _, ch := range result.Changes {
+			changed[ch.Group+"."+ch.Key] = ch.NewValue
+		}
+		middleware.SetAuditChange(c, "setting.import", "setting", "", nil, changed)
+	}
+	errors.ResponseSuccess(c, result, fmt.Sprintf("导入完成，写入 %d 项，失败 %d 项", result.Applied, len(result.Errors)))
+}
+
+// readSettingsImport 读取导入内容：multipart 表单的 file 字段，或直接以请求体提交 YAML/JSON
+func readSettingsImport(c *gin.Context) (*setting.SettingsExport, error) {
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New(errors.CodeInvalidParameter, "请上传导入文件")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeInvalidParameter, "读取导入文件失败")
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxSettingsImportSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInvalidParameter, "读取导入内容失败")
+	}
+	if len(data) > maxSettingsImportSize {
+		return nil, errors.New(errors.CodeInvalidParameter, "导入文件不能超过5MB")
+	}
+	return setting.ParseSettingsImport(data)
+}
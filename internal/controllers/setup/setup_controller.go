@@ -2,14 +2,12 @@ package setup
 
 import (
 	"fmt"
-	"net"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"pixelpunk/internal/controllers/setting/dto"
 	"pixelpunk/internal/controllers/websocket"
 	"pixelpunk/internal/cron"
 	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
 	ai "pixelpunk/internal/services/ai"
 	"pixelpunk/internal/services/auth"
 	"pixelpunk/internal/services/message"
@@ -24,8 +22,10 @@ import (
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/email"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/health"
+	"pixelpunk/pkg/logger"
 	"pixelpunk/pkg/utils"
-	"regexp"
+	"pixelpunk/pkg/vector"
 	"strings"
 	"time"
 
@@ -211,7 +211,11 @@ func (s *SetupController) Install(c *gin.Context) {
 		}
 	}
 
-	config.ReloadConfig()
+	// 预设模式下可能只有环境变量而没有配置文件，此时沿用已加载的配置
+	if err := config.ReloadConfig(); err != nil && !isPresetMode {
+		errors.HandleError(c, errors.New(errors.CodeInternal, "加载配置文件失败: "+err.Error()))
+		return
+	}
 
 	if err := database.ReconnectDatabase(); err != nil {
 		errors.HandleError(c, errors.New(errors.CodeInternal, "重新连接数据库失败: "+sanitizeDBError(err)))
@@ -248,7 +252,8 @@ func (s *SetupController) Install(c *gin.Context) {
 		}
 	}
 
-	token, err := auth.GenerateToken(adminUser.ID, adminUser.Username, int(adminUser.Role), jwtSecret, expiresHours)
+	client := auth.ClientInfo{IP: utils.GetClientIP(c), UserAgent: c.Request.UserAgent()}
+	token, err := auth.IssueSessionToken(adminUser, "setup", client, jwtSecret, expiresHours)
 	if err != nil {
 		installSuccess = true
 		errors.ResponseSuccess(c, gin.H{"message": userMessage}, userMessage)
@@ -378,11 +383,7 @@ redis:
   password: "%s"
   db: %d
 
-# 跨域(CORS)配置
-cors:
-  enabled: true
-  allowed_origins:
-    - "*"
+# 跨域(CORS)策略在后台设置分组 cors 中按路由分组配置
 `,
 			dbCfg.Host,
 			dbCfg.Port,
@@ -412,11 +413,7 @@ redis:
   password: "%s"
   db: %d
 
-# 跨域(CORS)配置
-cors:
-  enabled: true
-  allowed_origins:
-    - "*"
+# 跨域(CORS)策略在后台设置分组 cors 中按路由分组配置
 `,
 			dbCfg.Path,
 			redisHost,
@@ -520,11 +517,13 @@ func initializeSystemServices(vectorCfg VectorConfig) error {
 		qdrantTimeout = vectorCfg.QdrantTimeout
 	}
 
-	writeVectorConfigToDatabase(qdrantURL, qdrantTimeout)
+	writeVectorConfigToDatabase(qdrantURL, qdrantTimeout, vectorCfg)
 
 	if vectorCfg.UseBuiltin {
 		go func() {
-			startBuiltinQdrant(vectorCfg.HTTPPort, vectorCfg.GRPCPort)
+			if err := vector.StartBuiltinQdrant(vectorCfg.HTTPPort, vectorCfg.GRPCPort); err != nil {
+				logger.Error("启动内置Qdrant失败: %v", err)
+			}
 		}()
 		time.Sleep(2 * time.Second)
 	}
@@ -534,6 +533,8 @@ func initializeSystemServices(vectorCfg VectorConfig) error {
 	initAllServices()
 	websocket.InitWebSocketManager()
 	cron.InitCronManager()
+	qqueue.StartJobs()
+	health.MarkStageDone(health.StageServices)
 
 	return nil
 }
@@ -543,7 +544,11 @@ func runMigrations() {
 	if db == nil {
 		return
 	}
-	migrations.RegisterAllMigrations(db)
+	if err := migrations.RegisterAllMigrations(db); err != nil {
+		logger.Warn("部分迁移可能执行失败: %v", err)
+		return
+	}
+	health.MarkStageDone(health.StageMigrations)
 }
 
 func initAllServices() {
@@ -558,7 +563,7 @@ func initAllServices() {
 	vectorSvc.RegisterVectorConfigHooks()
 }
 
-func writeVectorConfigToDatabase(qdrantURL string, qdrantTimeout int) error {
+func writeVectorConfigToDatabase(qdrantURL string, qdrantTimeout int, vectorCfg VectorConfig) error {
 	db := database.GetDB()
 	if db == nil {
 		return fmt.Errorf("数据库连接未初始化")
@@ -597,6 +602,30 @@ func writeVectorConfigToDatabase(qdrantURL string, qdrantTimeout int) error {
 				Description: "Qdrant连接超时时间(秒)",
 				IsSystem:    true,
 			},
+			{
+				Key:         "qdrant_builtin",
+				Value:       vectorCfg.UseBuiltin,
+				Type:        "boolean",
+				Group:       "vector",
+				Description: "是否由应用托管内置Qdrant进程",
+				IsSystem:    true,
+			},
+			{
+				Key:         "qdrant_builtin_http_port",
+				Value:       vectorCfg.HTTPPort,
+				Type:        "number",
+				Group:       "vector",
+				Description: "内置Qdrant HTTP端口",
+				IsSystem:    true,
+			},
+			{
+				Key:         "qdrant_builtin_grpc_port",
+				Value:       vectorCfg.GRPCPort,
+				Type:        "number",
+				Group:       "vector",
+				Description: "内置Qdrant gRPC端口",
+				IsSystem:    true,
+			},
 		},
 	}
 
@@ -611,176 +640,3 @@ func writeVectorConfigToDatabase(qdrantURL string, qdrantTimeout int) error {
 
 	return nil
 }
-
-func isPortInUse(port int) bool {
-	addr := fmt.Sprintf("127.0.0.1:%d", port)
-	conn, err := net.DialTimeout("tcp", addr, 1*time.Second)
-	if err != nil {
-		return false
-	}
-	conn.Close()
-	return true
-}
-
-func updateQdrantConfig(configPath string, httpPort, grpcPort int) error {
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("读取Qdrant配置文件失败: %v", err)
-	}
-
-	content := string(data)
-
-	httpPortPattern := regexp.MustCompile(`(?m)^(\s*http_port:\s*)(\d+)`)
-	if httpPortPattern.MatchString(content) {
-		content = httpPortPattern.ReplaceAllString(content, fmt.Sprintf("${1}%d", httpPort))
-	} else {
-		servicePattern := regexp.MustCompile(`(?m)^(service:)`)
-		if servicePattern.MatchString(content) {
-			content = servicePattern.ReplaceAllString(content, fmt.Sprintf("${1}\n  http_port: %d", httpPort))
-		}
-	}
-
-	grpcPortPattern := regexp.MustCompile(`(?m)^(\s*grpc_port:\s*)(\d+)`)
-	if grpcPortPattern.MatchString(content) {
-		content = grpcPortPattern.ReplaceAllString(content, fmt.Sprintf("${1}%d", grpcPort))
-	} else {
-		servicePattern := regexp.MustCompile(`(?m)^(service:)`)
-		if servicePattern.MatchString(content) {
-			content = servicePattern.ReplaceAllString(content, fmt.Sprintf("${1}\n  grpc_port: %d", grpcPort))
-		}
-	}
-
-	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("写入Qdrant配置文件失败: %v", err)
-	}
-
-	return nil
-}
-
-func startBuiltinQdrant(httpPort, grpcPort int) error {
-	if httpPort <= 0 {
-		httpPort = 6333
-	}
-	if grpcPort <= 0 {
-		grpcPort = 6334
-	}
-
-	if isPortInUse(httpPort) {
-		return fmt.Errorf("HTTP端口 %d 已被占用", httpPort)
-	}
-
-	qdrantBinPath := findQdrantBinary()
-	if qdrantBinPath == "" {
-		return fmt.Errorf("未找到Qdrant二进制文件")
-	}
-
-	if _, err := os.Stat(qdrantBinPath); err != nil {
-		return fmt.Errorf("无法访问二进制文件: %v", err)
-	}
-
-	configPath, err := prepareQdrantConfig(httpPort, grpcPort)
-	if err != nil {
-		return fmt.Errorf("准备配置文件失败: %v", err)
-	}
-
-	return launchQdrantProcess(qdrantBinPath, configPath)
-}
-
-func findQdrantBinary() string {
-	paths := []string{
-		"qdrant/bin/qdrant",
-		"qdrant/qdrant",
-		"./qdrant/bin/qdrant",
-		"qdrant/bin/qdrant.exe",
-	}
-	for _, path := range paths {
-		if _, err := os.Stat(path); err == nil {
-			return path
-		}
-	}
-	return ""
-}
-
-func prepareQdrantConfig(httpPort, grpcPort int) (string, error) {
-	configPath := "qdrant/config/config.yaml"
-
-	if _, err := os.Stat(configPath); err == nil {
-		return configPath, updateQdrantConfig(configPath, httpPort, grpcPort)
-	}
-
-	if err := os.MkdirAll("qdrant/config", 0755); err != nil {
-		return "", err
-	}
-
-	config := fmt.Sprintf(`service:
-  http_port: %d
-  grpc_port: %d
-storage:
-  storage_path: ./storage
-`, httpPort, grpcPort)
-
-	return configPath, os.WriteFile(configPath, []byte(config), 0644)
-}
-
-func launchQdrantProcess(binPath, configPath string) error {
-	absBinPath, err := filepath.Abs(binPath)
-	if err != nil {
-		return fmt.Errorf("无法获取二进制文件绝对路径: %v", err)
-	}
-
-	absConfigPath, err := filepath.Abs(configPath)
-	if err != nil {
-		return fmt.Errorf("无法获取配置文件绝对路径: %v", err)
-	}
-
-	if err := os.Chmod(absBinPath, 0755); err != nil {
-		return fmt.Errorf("无法设置执行权限: %v", err)
-	}
-
-	testCmd := exec.Command(absBinPath, "--version")
-	testOutput, testErr := testCmd.CombinedOutput()
-	if testErr != nil {
-		if strings.Contains(string(testOutput), "GLIBC") || strings.Contains(testErr.Error(), "GLIBC") {
-			return fmt.Errorf("系统GLIBC版本不兼容，需要GLIBC 2.31+")
-		}
-		return fmt.Errorf("Qdrant二进制文件无法运行: %v", testErr)
-	}
-
-	workDir, _ := os.Getwd()
-	cmd := exec.Command(absBinPath, "--config-path", absConfigPath)
-	cmd.Dir = workDir
-
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		return fmt.Errorf("无法创建日志目录: %v", err)
-	}
-
-	logFile, err := os.OpenFile("logs/qdrant.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return fmt.Errorf("无法打开日志文件: %v", err)
-	}
-	defer func() {
-		if logFile != nil {
-			logFile.Close()
-		}
-	}()
-
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("启动进程失败: %v", err)
-	}
-
-	pid := cmd.Process.Pid
-	os.MkdirAll("qdrant", 0755)
-	pidFile := "qdrant/qdrant.pid"
-	os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", pid)), 0644)
-
-	time.Sleep(1 * time.Second)
-
-	if err := cmd.Process.Signal(os.Signal(nil)); err != nil {
-		return fmt.Errorf("进程启动后立即退出")
-	}
-
-	return nil
-}
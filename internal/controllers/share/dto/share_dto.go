@@ -1,35 +1,67 @@
 package dto
 
+import "time"
+
 type ShareItemDTO struct {
 	ItemType string `json:"item_type" binding:"required,oneof=folder file"`
 	ItemID   string `json:"item_id" binding:"required"`
+	Caption  string `json:"caption" binding:"omitempty,max=500"`
+	Duration int    `json:"duration" binding:"min=0,max=3600"`
 }
 
 type CreateShareDTO struct {
-	Name        string         `json:"name" binding:"omitempty,max=100"`
-	Description string         `json:"description" binding:"omitempty"`
-	Password    string         `json:"password" binding:"omitempty,max=100"`
-	ExpiredDays int            `json:"expired_days" binding:"min=0"`
-	MaxViews    int            `json:"max_views" binding:"min=0"`
-	Items       []ShareItemDTO `json:"items" binding:"required,min=1,dive"`
+	Name          string         `json:"name" binding:"omitempty,max=100"`
+	Description   string         `json:"description" binding:"omitempty"`
+	Password      string         `json:"password" binding:"omitempty,max=100"`
+	ShareKey      string         `json:"share_key" binding:"omitempty,min=3,max=32"` // 自定义链接后缀，为空时随机生成
+	StartsAt      *time.Time     `json:"starts_at"`                                  // 开放时间(RFC3339)，为空表示立即开放
+	ExpiredDays   int            `json:"expired_days" binding:"min=0"`
+	MaxViews      int            `json:"max_views" binding:"min=0"`
+	MaxDownloads  int            `json:"max_downloads" binding:"min=0"`
+	MaxDownloadMB int64          `json:"max_download_mb" binding:"min=0"`
+	Items         []ShareItemDTO `json:"items" binding:"required,min=1,dive"`
+	ExcludedFiles []string       `json:"excluded_files"` // 分享文件夹时不对访客展示的文件ID
+
+	CollectVisitorInfo       bool `json:"collect_visitor_info"`
+	RequireEmailVerification bool `json:"require_email_verification"` // 开启后自动收集访客信息
+	NotificationOnAccess     bool `json:"notification_on_access"`
+	NotificationThreshold    int  `json:"notification_threshold" binding:"omitempty,min=1"`
 
-	CollectVisitorInfo    bool `json:"collect_visitor_info"`
-	NotificationOnAccess  bool `json:"notification_on_access"`
-	NotificationThreshold int  `json:"notification_threshold" binding:"omitempty,min=1"`
+	ShareType            string `json:"share_type" binding:"omitempty,oneof=view collect"`
+	UploadMaxFileSizeMB  int    `json:"upload_max_file_size_mb" binding:"min=0"`
+	UploadMaxFiles       int    `json:"upload_max_files" binding:"min=0"`
+	UploadAllowedFormats string `json:"upload_allowed_formats" binding:"omitempty,max=255"`
+	UploadModeration     bool   `json:"upload_moderation"`
+
+	WatermarkEnabled bool   `json:"watermark_enabled"`
+	WatermarkConfig  string `json:"watermark_config"`
+
+	AccessMode string   `json:"access_mode" binding:"omitempty,oneof=public users"`
+	Recipients []string `json:"recipients" binding:"omitempty,max=200"` // 接收者用户名或邮箱，access_mode=users 时必填
 }
 
 func (d *CreateShareDTO) GetValidationMessages() map[string]string {
 	return map[string]string{
 		"Name.max":                  "分享名称不能超过100个字符",
 		"Password.max":              "密码不能超过100个字符",
+		"ShareKey.min":              "自定义链接至少3个字符",
+		"ShareKey.max":              "自定义链接不能超过32个字符",
 		"ExpiredDays.min":           "过期天数不能为负数",
 		"MaxViews.min":              "最大访问次数不能为负数",
+		"MaxDownloads.min":          "最大下载次数不能为负数",
+		"MaxDownloadMB.min":         "最大下载流量不能为负数",
 		"Items.required":            "分享项目不能为空",
 		"Items.min":                 "至少需要分享一个项目",
 		"ItemType.required":         "项目类型不能为空",
 		"ItemType.oneof":            "项目类型必须是folder或file",
 		"ItemID.required":           "项目ID不能为空",
 		"NotificationThreshold.min": "通知阈值必须大于0",
+		"AccessMode.oneof":          "访问方式必须是public或users",
+		"Recipients.max":            "接收者数量不能超过200个",
+		"ShareType.oneof":           "分享类型必须是view或collect",
+		"UploadMaxFileSizeMB.min":   "单个文件大小上限不能为负数",
+		"UploadMaxFiles.min":        "最多接收文件数不能为负数",
+		"UploadAllowedFormats.max":  "允许的文件格式不能超过255个字符",
 	}
 }
 
@@ -78,6 +110,35 @@ func (d *VisitorInfoDTO) GetValidationMessages() map[string]string {
 	}
 }
 
+type ShareEmailCodeDTO struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+}
+
+func (d *ShareEmailCodeDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Email.required": "邮箱不能为空",
+		"Email.email":    "邮箱格式不正确",
+		"Email.max":      "邮箱不能超过100个字符",
+	}
+}
+
+type ShareEmailVerifyDTO struct {
+	Name  string `json:"name" binding:"omitempty,max=100"`
+	Email string `json:"email" binding:"required,email,max=100"`
+	Code  string `json:"code" binding:"required,len=6"`
+}
+
+func (d *ShareEmailVerifyDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.max":       "姓名不能超过100个字符",
+		"Email.required": "邮箱不能为空",
+		"Email.email":    "邮箱格式不正确",
+		"Email.max":      "邮箱不能超过100个字符",
+		"Code.required":  "验证码不能为空",
+		"Code.len":       "验证码为6位数字",
+	}
+}
+
 type VisitorQueryDTO struct {
 	Page    int    `form:"page" binding:"omitempty,min=1"`
 	Size    int    `form:"size" binding:"omitempty,min=1,max=100"`
@@ -93,3 +154,191 @@ func (d *VisitorQueryDTO) GetValidationMessages() map[string]string {
 		"Keyword.max": "关键字不能超过100个字符",
 	}
 }
+
+type ShareArchiveDTO struct {
+	ShareKey    string   `json:"share_key" binding:"required"`
+	FileIDs     []string `json:"file_ids" binding:"required,min=1"`
+	AccessToken string   `json:"access_token"`
+}
+
+func (d *ShareArchiveDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"ShareKey.required": "分享密钥不能为空",
+		"FileIDs.required":  "请选择要下载的文件",
+		"FileIDs.min":       "请选择要下载的文件",
+	}
+}
+
+type ExtendShareDTO struct {
+	Days int `json:"days" binding:"omitempty,min=1,max=365"`
+}
+
+func (d *ExtendShareDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Days.min": "续期天数必须大于等于1",
+		"Days.max": "续期天数不能超过365天",
+	}
+}
+
+type ShareUploadQueryDTO struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Size   int    `form:"size" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+func (d *ShareUploadQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min":     "页码必须大于等于1",
+		"Size.min":     "每页数量必须大于等于1",
+		"Size.max":     "每页数量必须小于等于100",
+		"Status.oneof": "状态值必须是pending、approved或rejected",
+	}
+}
+
+type RevokeShareTokensDTO struct {
+	ClientIP string `json:"client_ip" binding:"omitempty,ip"` // 为空时吊销该分享的全部令牌
+}
+
+func (d *RevokeShareTokensDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"ClientIP.ip": "IP地址格式不正确",
+	}
+}
+
+type ShareExclusionsDTO struct {
+	FileIDs []string `json:"file_ids" binding:"max=1000"`
+}
+
+func (d *ShareExclusionsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FileIDs.max": "排除的文件数量不能超过1000个",
+	}
+}
+
+type ShareInvitationDTO struct {
+	Emails  []string `json:"emails" binding:"required,min=1,max=50,dive,email,max=100"`
+	Message string   `json:"message" binding:"omitempty,max=500"`
+}
+
+func (d *ShareInvitationDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Emails.required": "邀请邮箱不能为空",
+		"Emails.min":      "至少需要填写一个邮箱",
+		"Emails.max":      "单次最多邀请50个邮箱",
+		"Emails.email":    "邮箱格式不正确",
+		"Message.max":     "附言不能超过500个字符",
+	}
+}
+
+type ShareItemOrderDTO struct {
+	ID       string `json:"id" binding:"required"`
+	Caption  string `json:"caption" binding:"omitempty,max=500"`
+	Duration int    `json:"duration" binding:"min=0,max=3600"`
+}
+
+type ShareSlideshowDTO struct {
+	Items    []ShareItemOrderDTO `json:"items" binding:"required,min=1,dive"`
+	Interval int                 `json:"interval" binding:"omitempty,min=1,max=3600"`
+}
+
+func (d *ShareSlideshowDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Items.required": "排序项目不能为空",
+		"Items.min":      "至少需要一个项目",
+		"ID.required":    "项目ID不能为空",
+		"Caption.max":    "说明文字不能超过500个字符",
+		"Duration.min":   "停留时间不能为负数",
+		"Duration.max":   "停留时间不能超过3600秒",
+		"Interval.min":   "默认间隔至少1秒",
+		"Interval.max":   "默认间隔不能超过3600秒",
+	}
+}
+
+type ShareRecipientsDTO struct {
+	Recipients []string `json:"recipients" binding:"required,min=1,max=200"`
+}
+
+func (d *ShareRecipientsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Recipients.required": "接收者不能为空",
+		"Recipients.min":      "至少需要指定一个接收者",
+		"Recipients.max":      "接收者数量不能超过200个",
+	}
+}
+
+type ReceivedShareQueryDTO struct {
+	Page int `form:"page" binding:"omitempty,min=1"`
+	Size int `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+func (d *ReceivedShareQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Page.min": "页码必须大于等于1",
+		"Size.min": "每页数量必须大于等于1",
+		"Size.max": "每页数量必须小于等于100",
+	}
+}
+
+type ShareTemplateDTO struct {
+	Name                  string `json:"name" binding:"required,max=50"`
+	IsDefault             bool   `json:"is_default"`
+	ExpiredDays           int    `json:"expired_days" binding:"min=0"`
+	MaxViews              int    `json:"max_views" binding:"min=0"`
+	MaxDownloads          int    `json:"max_downloads" binding:"min=0"`
+	MaxDownloadMB         int64  `json:"max_download_mb" binding:"min=0"`
+	PasswordPolicy        string `json:"password_policy" binding:"omitempty,oneof=none random fixed"`
+	Password              string `json:"password" binding:"omitempty,max=100"`
+	CollectVisitorInfo    bool   `json:"collect_visitor_info"`
+	NotificationOnAccess  bool   `json:"notification_on_access"`
+	NotificationThreshold int    `json:"notification_threshold" binding:"omitempty,min=1"`
+	WatermarkEnabled      bool   `json:"watermark_enabled"`
+	WatermarkConfig       string `json:"watermark_config"`
+}
+
+func (d *ShareTemplateDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.required":             "模板名称不能为空",
+		"Name.max":                  "模板名称不能超过50个字符",
+		"ExpiredDays.min":           "过期天数不能为负数",
+		"MaxViews.min":              "最大访问次数不能为负数",
+		"MaxDownloads.min":          "最大下载次数不能为负数",
+		"MaxDownloadMB.min":         "最大下载流量不能为负数",
+		"PasswordPolicy.oneof":      "密码策略必须是none、random或fixed",
+		"Password.max":              "密码不能超过100个字符",
+		"NotificationThreshold.min": "通知阈值必须大于0",
+	}
+}
+
+type CreateShareFromTemplateDTO struct {
+	TemplateID  string         `json:"template_id"` // 为空时使用默认模板
+	ShareKey    string         `json:"share_key" binding:"omitempty,min=3,max=32"`
+	Name        string         `json:"name" binding:"omitempty,max=100"`
+	Description string         `json:"description"`
+	StartsAt    *time.Time     `json:"starts_at"`
+	Items       []ShareItemDTO `json:"items" binding:"required,min=1,dive"`
+}
+
+func (d *CreateShareFromTemplateDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.max":          "分享名称不能超过100个字符",
+		"ShareKey.min":      "自定义链接至少3个字符",
+		"ShareKey.max":      "自定义链接不能超过32个字符",
+		"Items.required":    "分享项目不能为空",
+		"Items.min":         "至少需要分享一个项目",
+		"ItemType.required": "项目类型不能为空",
+		"ItemType.oneof":    "项目类型必须是folder或file",
+		"ItemID.required":   "项目ID不能为空",
+	}
+}
+
+type ShareAuditExportQueryDTO struct {
+	StartDate time.Time `form:"start_date" time_format:"2006-01-02" binding:"omitempty"`
+	EndDate   time.Time `form:"end_date" time_format:"2006-01-02" binding:"omitempty"`
+	Format    string    `form:"format" binding:"omitempty,oneof=csv json"`
+}
+
+func (d *ShareAuditExportQueryDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Format.oneof": "导出格式必须是csv或json",
+	}
+}
@@ -0,0 +1,129 @@
+package share
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oembedDefaultWidth  = 640
+	oembedDefaultHeight = 480
+)
+
+var shareEmbedTemplate = template.Must(template.New("share_embed").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+*{box-sizing:border-box}
+body{margin:0;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;background:#111;color:#eee}
+header{display:flex;justify-content:space-between;align-items:center;padding:8px 12px;font-size:14px}
+header a{color:#8ab4f8;text-decoration:none;white-space:nowrap;margin-left:8px}
+.title{overflow:hidden;text-overflow:ellipsis;white-space:nowrap}
+.grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(120px,1fr));gap:4px;padding:0 4px 4px}
+.grid a{display:block;aspect-ratio:1;overflow:hidden;background:#222}
+.grid img{width:100%;height:100%;object-fit:cover;display:block}
+.more{padding:8px 12px;font-size:12px;color:#aaa}
+</style>
+</head>
+<body>
+<header><span class="title">{{.Title}}{{if .AuthorName}} · {{.AuthorName}}{{end}}</span><a href="{{.ShareURL}}" target="_blank" rel="noopener">在 {{.ProviderName}} 中查看</a></header>
+<div class="grid">
+{{range .Items}}<a href="{{$.ShareURL}}" target="_blank" rel="noopener" title="{{.Name}}"><img src="{{.ThumbURL}}" alt="{{.Name}}" loading="lazy"></a>
+{{end}}</div>
+{{if gt .Total (len .Items)}}<div class="more">共 {{.Total}} 张，点击查看全部</div>{{end}}
+</body>
+</html>`))
+
+// OEmbed 按 oEmbed 规范返回分享的嵌入信息，响应体不使用统一包装以兼容各类消费方
+func OEmbed(c *gin.Context) {
+	if format := c.Query("format"); format != "" && format != "json" {
+		c.AbortWithStatus(http.StatusNotImplemented)
+		return
+	}
+
+	shareKey := share.ParseShareKeyFromURL(c.Query("url"))
+	if shareKey == "" {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	embed, err := share.GetShareEmbed(shareKey, 1)
+	if err != nil {
+		if errors.IsCode(err, errors.CodeForbidden) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		} else {
+			c.AbortWithStatus(http.StatusNotFound)
+		}
+		return
+	}
+
+	width := boundedQueryInt(c, "maxwidth", oembedDefaultWidth)
+	height := boundedQueryInt(c, "maxheight", oembedDefaultHeight)
+	embedURL := utils.GetBaseUrl() + "/api/v1/shares/public/" + shareKey + "/embed"
+
+	resp := gin.H{
+		"version":       "1.0",
+		"type":          "rich",
+		"title":         embed.Title,
+		"author_name":   embed.AuthorName,
+		"provider_name": embed.ProviderName,
+		"provider_url":  utils.GetBaseUrl(),
+		"width":         width,
+		"height":        height,
+		"html": fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" frameborder="0" loading="lazy" allowfullscreen></iframe>`,
+			template.HTMLEscapeString(embedURL), width, height),
+		// 缩略图令牌按时间窗口对齐签发，剩余有效期最短为两者之差
+		"cache_age": int((utils.SIGNATURE_DURATION - utils.TIME_WINDOW_ALIGN).Seconds()),
+	}
+	if len(embed.Items) > 0 {
+		resp["thumbnail_url"] = embed.Items[0].ThumbURL
+		resp["thumbnail_width"] = embed.Items[0].Width
+		resp["thumbnail_height"] = embed.Items[0].Height
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ShareEmbed 可被 iframe 嵌入的分享画廊，format=json 时返回结构化数据
+func ShareEmbed(c *gin.Context) {
+	shareKey := c.Param("key")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	embed, err := share.GetShareEmbed(shareKey, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		errors.ResponseSuccess(c, embed, "获取分享嵌入内容成功")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Content-Security-Policy", "frame-ancestors *")
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Status(http.StatusOK)
+	if err := shareEmbedTemplate.Execute(c.Writer, embed); err != nil {
+		logger.ErrorContext(c.Request.Context(), "渲染分享嵌入页面失败: %v", err)
+	}
+}
+
+func boundedQueryInt(c *gin.Context, key string, def int) int {
+	v, err := strconv.Atoi(c.Query(key))
+	if err != nil || v <= 0 || v > def {
+		return def
+	}
+	return v
+}
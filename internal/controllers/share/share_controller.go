@@ -5,6 +5,7 @@ import (
 	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/apikey"
 	filesvc "pixelpunk/internal/services/file"
 	"pixelpunk/internal/services/share"
 	"pixelpunk/pkg/cache"
@@ -55,6 +56,54 @@ func CreateShare(c *gin.Context) {
 	errors.ResponseSuccess(c, data, "创建分享成功")
 }
 
+// CreateShareForApiKey API密钥创建分享，限制目录的密钥只能分享限定目录内的内容
+func CreateShareForApiKey(c *gin.Context) {
+	key := middleware.GetCurrentAPIKey(c)
+
+	req, err := common.ValidateRequest[dto.CreateShareDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	for _, item := range req.Items {
+		var accessErr error
+		if item.ItemType == "folder" {
+			accessErr = apikey.CheckFolderAccess(key, item.ItemID)
+		} else {
+			accessErr = apikey.CheckFileAccess(key, item.ItemID)
+		}
+		if accessErr != nil {
+			errors.HandleError(c, accessErr)
+			return
+		}
+	}
+
+	result, err := share.CreateShare(key.UserID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"id":        result.ID,
+		"share_key": result.ShareKey,
+		"share_url": getShareURL(c, result.ShareKey),
+	}
+
+	errors.ResponseSuccess(c, data, "创建分享成功")
+}
+
+func CheckShareSlug(c *gin.Context) {
+	slug, err := share.ValidateShareSlug(c.Query("slug"))
+	if err != nil {
+		errors.ResponseSuccess(c, gin.H{"available": false, "reason": errors.GetSafeError(err).Message}, "链接不可用")
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"available": true, "slug": slug}, "链接可用")
+}
+
 func GetShareList(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
 
@@ -126,25 +175,86 @@ func DeleteShare(c *gin.Context) {
 	errors.ResponseSuccess(c, nil, "删除分享成功")
 }
 
+func ExtendShare(c *gin.Context) {
+	shareID := c.Param("id")
+
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ExtendShareDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := share.ExtendShare(shareID, userID, req.Days)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"id":           result.ID,
+		"expired_at":   result.ExpiredAt,
+		"expired_days": result.ExpiredDays,
+	}
+
+	errors.ResponseSuccess(c, data, "分享续期成功")
+}
+
+func ExtendShareByToken(c *gin.Context) {
+	result, err := share.ExtendShareByToken(c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"id":         result.ID,
+		"name":       result.Name,
+		"expired_at": result.ExpiredAt,
+		"share_url":  getShareURL(c, result.ShareKey),
+	}
+
+	errors.ResponseSuccess(c, data, "分享续期成功")
+}
+
 func ViewShare(c *gin.Context) {
 	shareKey := c.Param("key")
 
 	shareInfo, err := share.GetShareByKey(shareKey)
 	if err != nil {
+		if errors.IsCode(err, errors.CodeShareNotStarted) {
+			errors.HandleError(c, err)
+			return
+		}
 		errors.HandleError(c, errors.New(errors.CodeNotFound, err.Error()))
 		return
 	}
 
-	if shareInfo.Password != "" {
+	if err := share.AuthorizeShareViewer(shareInfo, middleware.GetCurrentUserID(c)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	// 邀请链接自带访问凭证，受邀人无需再输入密码或验证邮箱
+	invitation := share.ResolveShareInvitation(shareInfo.ID, shareInviteToken(c))
+
+	// 分享页签发的单文件令牌与本次出示的凭证绑定
+	grant := share.ShareGrant{UserID: middleware.GetCurrentUserID(c)}
+	if invitation != nil {
+		grant.InvitationID = invitation.ID
+	}
+
+	if shareInfo.Password != "" && invitation == nil {
 		accessToken := c.Query("access_token")
 		if accessToken != "" {
-			valid, err := share.ValidateAccessToken(shareKey, accessToken)
+			token, err := share.ResolveAccessToken(shareKey, accessToken)
 			if err != nil {
 				errors.HandleError(c, err)
 				return
 			}
 
-			if !valid {
+			if token == nil {
 				errors.ResponseSuccess(c, gin.H{
 					"require_password": true,
 					"share_id":         shareInfo.ID,
@@ -152,6 +262,7 @@ func ViewShare(c *gin.Context) {
 				}, "需要密码验证")
 				return
 			}
+			grant.AccessTokenID = token.ID
 
 		} else {
 			errors.ResponseSuccess(c, gin.H{
@@ -163,9 +274,26 @@ func ViewShare(c *gin.Context) {
 		}
 	}
 
+	if err := share.AuthorizeShareEmail(shareInfo, middleware.GetCurrentUserID(c), shareEmailToken(c)); err != nil && invitation == nil {
+		errors.ResponseSuccess(c, gin.H{
+			"require_email": true,
+			"share_id":      shareInfo.ID,
+			"name":          shareInfo.Name,
+		}, "需要验证邮箱")
+		return
+	}
+	if shareInfo.RequireEmailVerification && invitation == nil {
+		grant.EmailToken = shareEmailToken(c)
+	}
+
 	folderID := c.Query("folder_id")
 
-	data, err := share.GetShareForView(shareKey, folderID)
+	var data map[string]interface{}
+	if c.Query("view") == "slideshow" {
+		data, err = share.GetShareSlideshow(shareKey, grant)
+	} else {
+		data, err = share.GetShareForView(shareKey, folderID, grant)
+	}
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -187,13 +315,17 @@ func ViewShare(c *gin.Context) {
 
 		err := cache.Set(cacheKey, "1", 24*time.Hour)
 		if err != nil {
-			logger.Error("设置访问缓存失败: %v", err)
+			logger.ErrorContext(c.Request.Context(), "设置访问缓存失败: %v", err)
 		}
 	} else {
 	}
 
 	share.LogShareAccess(shareInfo.ID, viewedItems, nil, clientIP, userAgent, referer)
 
+	if invitation != nil && folderID == "" {
+		go share.RecordInvitationOpen(invitation.ID)
+	}
+
 	errors.ResponseSuccess(c, data, "获取分享内容成功")
 }
 
@@ -223,24 +355,218 @@ func VerifySharePassword(c *gin.Context) {
 }
 
 func DownloadFilesBatch(c *gin.Context) {
-	var req struct {
-		FileIDs     []string `json:"file_ids" binding:"required,min=1"`
-		AccessToken string   `json:"access_token"`
+	req, err := common.ValidateRequest[dto.ShareArchiveDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "请求参数错误"))
+
+	shareInfo, ok := authorizeShareDownload(c, req.ShareKey, req.AccessToken)
+	if !ok {
+		return
+	}
+
+	applyWatermark := share.ShouldWatermarkShareDownload(shareInfo, middleware.GetCurrentUserID(c))
+	job, err := share.CreateShareArchiveJob(shareInfo, req.FileIDs, applyWatermark, c.ClientIP())
+	if err != nil {
+		errors.HandleError(c, err)
 		return
 	}
 
 	data := gin.H{
 		"accepted":     true,
-		"file_ids":     req.FileIDs,
-		"download_url": "", // 未来扩展
-		"message":      "批量下载任务已受理",
+		"job":          job,
+		"progress_url": fmt.Sprintf("/api/v1/shares/public/%s/archives/%s", req.ShareKey, job.ID),
+		"download_url": fmt.Sprintf("/api/v1/shares/public/%s/archives/%s/download", req.ShareKey, job.ID),
 	}
 	errors.ResponseSuccess(c, data, "已受理批量下载请求")
 }
 
+func GetShareArchiveProgress(c *gin.Context) {
+	shareKey := c.Param("key")
+
+	if _, ok := authorizeShareDownload(c, shareKey, c.Query("access_token")); !ok {
+		return
+	}
+
+	job, err := share.GetShareArchiveJob(shareKey, c.Param("job_id"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, job, "获取打包进度成功")
+}
+
+func DownloadShareArchive(c *gin.Context) {
+	shareKey := c.Param("key")
+
+	if _, ok := authorizeShareDownload(c, shareKey, c.Query("access_token")); !ok {
+		return
+	}
+
+	archive, err := share.OpenShareArchive(shareKey, c.Param("job_id"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer archive.Close()
+
+	fileName := fmt.Sprintf("share_%s_%s.zip", shareKey, archive.Job.CreatedAt.Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(fileName))
+	if archive.Size >= 0 {
+		c.Header("Content-Length", fmt.Sprintf("%d", archive.Size))
+	}
+	c.Status(http.StatusOK)
+
+	written, err := archive.WriteTo(c.Writer)
+	if err != nil {
+		logger.Warn("分享打包下载中断 [%s]: %v", archive.Job.ID, err)
+	}
+
+	clientIP := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+	go share.RecordShareArchiveDownload(archive, written, clientIP, userAgent)
+}
+
+func UploadToShare(c *gin.Context) {
+	shareKey := c.Param("key")
+
+	shareInfo, ok := authorizeShareDownload(c, shareKey, c.PostForm("access_token"))
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "文件上传失败: "+err.Error()))
+		return
+	}
+
+	upload, err := share.UploadToShare(c, shareInfo, file, c.PostForm("uploader_name"), c.PostForm("message"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"id":        upload.ID,
+		"file_name": upload.FileName,
+		"status":    upload.Status,
+	}
+
+	msg := "上传成功"
+	if upload.Status == common.ShareUploadStatusPending {
+		msg = "上传成功，等待分享者审核"
+	}
+	errors.ResponseSuccess(c, data, msg)
+}
+
+func GetShareUploads(c *gin.Context) {
+	shareID := c.Param("id")
+
+	userID := middleware.GetCurrentUserID(c)
+
+	var query dto.ShareUploadQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "请求参数错误: "+err.Error()))
+		return
+	}
+
+	uploads, total, err := share.GetShareUploads(shareID, userID, &query)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"list":  uploads,
+		"total": total,
+	}
+
+	errors.ResponseSuccess(c, data, "获取上传记录成功")
+}
+
+func ApproveShareUpload(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := share.ReviewShareUpload(c.Param("id"), c.Param("upload_id"), userID, true); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已通过该上传")
+}
+
+func RejectShareUpload(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := share.ReviewShareUpload(c.Param("id"), c.Param("upload_id"), userID, false); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已拒绝并删除该上传")
+}
+
+// authorizeShareDownload 校验分享有效性、指定用户权限及密码分享的访问令牌，失败时已写入响应
+func authorizeShareDownload(c *gin.Context, shareKey, accessToken string) (models.Share, bool) {
+	shareInfo, err := share.GetShareByKey(shareKey)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, "分享不存在或已失效"))
+		return models.Share{}, false
+	}
+
+	if err := share.AuthorizeShareViewer(shareInfo, middleware.GetCurrentUserID(c)); err != nil {
+		errors.HandleError(c, err)
+		return models.Share{}, false
+	}
+
+	if share.ValidateShareInvitation(shareInfo.ID, shareInviteToken(c)) {
+		return shareInfo, true
+	}
+
+	if shareInfo.Password != "" {
+		if accessToken == "" {
+			errors.HandleError(c, errors.New(errors.CodeUnauthorized, "需要提供访问令牌"))
+			return models.Share{}, false
+		}
+		valid, err := share.ValidateAccessToken(shareKey, accessToken)
+		if err != nil || !valid {
+			errors.HandleError(c, errors.New(errors.CodeUnauthorized, "访问令牌无效或已过期"))
+			return models.Share{}, false
+		}
+	}
+
+	if err := share.AuthorizeShareEmail(shareInfo, middleware.GetCurrentUserID(c), shareEmailToken(c)); err != nil {
+		errors.HandleError(c, err)
+		return models.Share{}, false
+	}
+	return shareInfo, true
+}
+
+// shareInviteToken 邀请令牌可通过查询参数、表单或请求头传递
+func shareInviteToken(c *gin.Context) string {
+	if token := c.Query("invite"); token != "" {
+		return token
+	}
+	if token := c.PostForm("invite"); token != "" {
+		return token
+	}
+	return c.GetHeader("X-Share-Invite")
+}
+
+// shareEmailToken 邮箱访问令牌可通过查询参数、表单或请求头传递
+func shareEmailToken(c *gin.Context) string {
+	if token := c.Query("email_token"); token != "" {
+		return token
+	}
+	if token := c.PostForm("email_token"); token != "" {
+		return token
+	}
+	return c.GetHeader("X-Share-Email-Token")
+}
+
 func SubmitVisitorInfo(c *gin.Context) {
 	shareKey := c.Param("key")
 
@@ -276,6 +602,37 @@ func SubmitVisitorInfo(c *gin.Context) {
 	errors.ResponseSuccess(c, nil, "提交访客信息成功")
 }
 
+func SendShareEmailCode(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.ShareEmailCodeDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := share.SendShareEmailCode(c.Param("key"), req.Email, c.ClientIP()); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "验证码已发送，请查收邮件")
+}
+
+func VerifyShareEmail(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.ShareEmailVerifyDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	token, err := share.VerifyShareEmailCode(c.Param("key"), req, c.ClientIP(), c.Request.UserAgent(), c.Request.Referer())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"email_token": token}, "邮箱验证成功")
+}
+
 func getShareURL(c *gin.Context, shareKey string) string {
 	baseUrl := utils.GetBaseUrl()
 	return baseUrl + "/share/" + shareKey
@@ -335,27 +692,11 @@ func DownloadSharedFile(c *gin.Context) {
 		return
 	}
 
-	accessToken := c.Query("access_token")
-
-	shareInfo, err := share.GetShareByKey(shareKey)
-	if err != nil {
-		errors.HandleError(c, errors.New(errors.CodeNotFound, "分享不存在或已失效"))
+	shareInfo, ok := authorizeShareDownload(c, shareKey, c.Query("access_token"))
+	if !ok {
 		return
 	}
 
-	if shareInfo.Password != "" {
-		if accessToken == "" {
-			errors.HandleError(c, errors.New(errors.CodeUnauthorized, "需要提供访问令牌"))
-			return
-		}
-
-		valid, err := share.ValidateAccessToken(shareKey, accessToken)
-		if err != nil || !valid {
-			errors.HandleError(c, errors.New(errors.CodeUnauthorized, "访问令牌无效或已过期"))
-			return
-		}
-	}
-
 	hasAccess, err := share.ValidateSharedFileAccess(shareInfo.ID, fileID)
 	if err != nil {
 		errors.HandleError(c, err)
@@ -375,16 +716,36 @@ func DownloadSharedFile(c *gin.Context) {
 		return
 	}
 
-	result, isLocal, isProxy, err := filesvc.ServeFile(file, false)
-	if err != nil {
+	if err := share.CheckShareDownloadQuota(shareInfo, file.Size); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	// 开启强制水印时访客拿到的是合成后的图片，分享者本人下载原图
+	var watermarked []byte
+	if share.ShouldWatermarkShareDownload(shareInfo, middleware.GetCurrentUserID(c)) {
+		watermarked, err = share.RenderShareWatermark(c.Request.Context(), shareInfo, file)
+		if err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+	}
+
+	var result interface{}
+	var isLocal, isProxy bool
+	if watermarked == nil {
+		result, isLocal, isProxy, err = filesvc.ServeFile(file, false)
+		if err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+	}
+
 	// 在 goroutine 外提取值，避免数据竞争
 	// Gin 官方警告：不要在 goroutine 中直接使用 *gin.Context
 	clientIP := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
+	invitation := share.ResolveShareInvitation(shareInfo.ID, shareInviteToken(c))
 
 	go func() {
 		downloadLog := &models.FileDownloadLog{
@@ -398,6 +759,10 @@ func DownloadSharedFile(c *gin.Context) {
 		if err := database.DB.Create(downloadLog).Error; err != nil {
 			logger.Error("记录分享下载日志失败: %v", err)
 		}
+		share.RecordShareDownload(shareInfo.ID, file.Size)
+		if invitation != nil {
+			share.RecordInvitationDownload(invitation.ID)
+		}
 	}()
 
 	fileName := file.DisplayName
@@ -411,6 +776,8 @@ func DownloadSharedFile(c *gin.Context) {
 	c.Header("Content-Disposition", utils.SetContentDispositionFilename(fileName))
 
 	switch {
+	case watermarked != nil:
+		c.Data(http.StatusOK, "application/octet-stream", watermarked)
 	case isLocal:
 		// 仅本地文件支持 Range；由 http.ServeFile 自动设置 Accept-Ranges/Content-Length
 		c.File(result.(string))
@@ -435,3 +802,51 @@ func DownloadSharedFile(c *gin.Context) {
 		io.Copy(c.Writer, fileReader)
 	}
 }
+
+func GetShareExclusions(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	fileIDs, err := share.GetShareExclusions(c.Param("id"), userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"file_ids": fileIDs}, "获取排除列表成功")
+}
+
+func UpdateShareExclusions(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareExclusionsDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	fileIDs, err := share.UpdateShareExclusions(c.Param("id"), userID, req.FileIDs)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"file_ids": fileIDs}, "更新排除列表成功")
+}
+
+func UpdateShareSlideshow(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareSlideshowDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items, err := share.UpdateShareSlideshow(c.Param("id"), userID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, items, "更新分享顺序成功")
+}
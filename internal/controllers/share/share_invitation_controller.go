@@ -0,0 +1,52 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SendShareInvitations(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareInvitationDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	invitations, err := share.SendShareInvitations(c.Param("id"), userID, req.Emails, req.Message)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, invitations, "邀请已提交发送")
+}
+
+func GetShareInvitations(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	invitations, err := share.GetShareInvitations(c.Param("id"), userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, invitations, "获取分享邀请成功")
+}
+
+func RevokeShareInvitation(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := share.RevokeShareInvitation(c.Param("id"), c.Param("invitation_id"), userID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "邀请已吊销")
+}
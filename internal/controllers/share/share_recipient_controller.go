@@ -0,0 +1,68 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetShareRecipients(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	recipients, err := share.GetShareRecipients(c.Param("id"), userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, recipients, "获取分享接收者成功")
+}
+
+func UpdateShareRecipients(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareRecipientsDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	recipients, err := share.UpdateShareRecipients(c.Param("id"), userID, req.Recipients)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, recipients, "更新分享接收者成功")
+}
+
+func GetReceivedShares(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	var query dto.ReceivedShareQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "请求参数错误: "+err.Error()))
+		return
+	}
+
+	shares, total, err := share.GetReceivedShares(userID, query.Page, query.Size)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	for _, item := range shares {
+		item["share_url"] = getShareURL(c, item["share_key"].(string))
+	}
+
+	data := gin.H{
+		"list":  shares,
+		"total": total,
+	}
+
+	errors.ResponseSuccess(c, data, "获取收到的分享成功")
+}
@@ -0,0 +1,104 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetShareTemplates(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	templates, err := share.GetShareTemplates(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, templates, "获取分享模板成功")
+}
+
+func CreateShareTemplate(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareTemplateDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	template, err := share.CreateShareTemplate(userID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, template, "创建分享模板成功")
+}
+
+func UpdateShareTemplate(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.ShareTemplateDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	template, err := share.UpdateShareTemplate(userID, c.Param("template_id"), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, template, "更新分享模板成功")
+}
+
+func DeleteShareTemplate(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := share.DeleteShareTemplate(userID, c.Param("template_id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "删除分享模板成功")
+}
+
+func CreateShareFromTemplate(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.CreateShareFromTemplateDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, password, err := share.CreateShareFromTemplate(userID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	shareType := "mixed"
+	if len(req.Items) == 1 {
+		shareType = req.Items[0].ItemType
+	}
+	activity.LogShareCreate(userID, result.ID, shareType)
+
+	data := gin.H{
+		"id":        result.ID,
+		"share_key": result.ShareKey,
+		"share_url": getShareURL(c, result.ShareKey),
+	}
+	if password != "" {
+		data["password"] = password
+	}
+
+	errors.ResponseSuccess(c, data, "创建分享成功")
+}
@@ -0,0 +1,52 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetShareAccessTokens(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	tokens, err := share.GetShareAccessTokens(c.Param("id"), userID, c.Query("all") == "true")
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, tokens, "获取访问令牌成功")
+}
+
+func RevokeShareAccessToken(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := share.RevokeShareAccessToken(c.Param("id"), c.Param("token_id"), userID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "访问令牌已吊销")
+}
+
+func RevokeShareAccessTokens(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	req, err := common.ValidateRequest[dto.RevokeShareTokensDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	count, err := share.RevokeShareAccessTokensByIP(c.Param("id"), req.ClientIP, userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"revoked": count}, "访问令牌已吊销")
+}
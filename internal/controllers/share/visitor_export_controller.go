@@ -0,0 +1,140 @@
+package share
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/share"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportShareVisitors 导出分享收集到的访客邮箱，默认CSV，format=json 时返回JSON文件
+func ExportShareVisitors(c *gin.Context) {
+	shareID := c.Param("id")
+
+	userID := middleware.GetCurrentUserID(c)
+
+	visitors, err := share.ExportShareVisitors(shareID, userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	baseName := fmt.Sprintf("share_visitors_%s_%s", shareID, time.Now().Format("20060102150405"))
+
+	if c.Query("format") == "json" {
+		c.Header("Content-Disposition", utils.SetContentDispositionFilename(baseName+".json"))
+		c.JSON(http.StatusOK, visitors)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(baseName+".csv"))
+	c.Status(http.StatusOK)
+
+	// 写入BOM，避免Excel打开中文乱码
+	c.Writer.Write([]byte("\xEF\xBB\xBF"))
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"姓名", "邮箱", "邮箱已验证", "访问次数", "首次访问", "最后访问", "IP地址"})
+	for _, v := range visitors {
+		w.Write([]string{
+			v.VisitorName,
+			v.VisitorEmail,
+			strconv.FormatBool(v.EmailVerified),
+			strconv.Itoa(v.VisitCount),
+			time.Time(v.CreatedAt).Format("2006-01-02 15:04:05"),
+			time.Time(v.LastVisitAt).Format("2006-01-02 15:04:05"),
+			v.IPAddress,
+		})
+	}
+	w.Flush()
+}
+
+// ExportShareAudit 导出分享的访问、下载和访客记录，可按日期范围筛选，默认CSV，format=json 时返回JSON文件
+func ExportShareAudit(c *gin.Context) {
+	shareID := c.Param("id")
+
+	userID := middleware.GetCurrentUserID(c)
+
+	var query dto.ShareAuditExportQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "请求参数错误: "+err.Error()))
+		return
+	}
+
+	// 结束日期包含当天
+	end := query.EndDate
+	if !end.IsZero() {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	report, err := share.ExportShareAudit(shareID, userID, query.StartDate, end)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	baseName := fmt.Sprintf("share_audit_%s_%s", shareID, time.Now().Format("20060102150405"))
+
+	if query.Format == "json" {
+		c.Header("Content-Disposition", utils.SetContentDispositionFilename(baseName+".json"))
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(baseName+".csv"))
+	c.Status(http.StatusOK)
+
+	c.Writer.Write([]byte("\xEF\xBB\xBF"))
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"时间", "类型", "IP地址", "用户代理", "来源页面", "姓名", "邮箱", "文件ID", "文件名", "文件大小", "查看内容"})
+	for _, l := range report.AccessLogs {
+		w.Write([]string{
+			time.Time(l.AccessedAt).Format("2006-01-02 15:04:05"),
+			"访问",
+			l.IPAddress,
+			l.UserAgent,
+			l.Referer,
+			l.VisitorName,
+			l.VisitorEmail,
+			"", "", "",
+			string(l.ViewedItems),
+		})
+	}
+	for _, d := range report.Downloads {
+		w.Write([]string{
+			time.Time(d.DownloadedAt).Format("2006-01-02 15:04:05"),
+			"下载",
+			d.IPAddress,
+			d.UserAgent,
+			"", "", "",
+			d.FileID,
+			d.FileName,
+			strconv.FormatInt(d.FileSize, 10),
+			"",
+		})
+	}
+	for _, v := range report.Visitors {
+		w.Write([]string{
+			time.Time(v.CreatedAt).Format("2006-01-02 15:04:05"),
+			"访客登记",
+			v.IPAddress,
+			v.UserAgent,
+			v.Referer,
+			v.VisitorName,
+			v.VisitorEmail,
+			"", "", "",
+			"",
+		})
+	}
+	w.Flush()
+}
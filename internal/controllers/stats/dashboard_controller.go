@@ -107,3 +107,19 @@ func DashboardSystemInfo(c *gin.Context) {
 
 	errors.ResponseSuccess(c, data, "获取系统信息成功")
 }
+
+func DashboardOverview(c *gin.Context) {
+	days := 7
+	if d, err := strconv.Atoi(c.DefaultQuery("days", "7")); err == nil && d > 0 {
+		days = d
+	}
+	refresh := c.Query("refresh") == "true"
+
+	data, err := stats.GetDashboardOverview(days, refresh)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, data, "获取仪表盘总览成功")
+}
@@ -39,3 +39,29 @@ type ChannelConfigDTO map[string]interface{}
 func (d *ChannelConfigDTO) GetValidationMessages() map[string]string {
 	return map[string]string{}
 }
+
+type ListChannelObjectsDTO struct {
+	Prefix string `form:"prefix" binding:"max=255"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+func (d *ListChannelObjectsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Prefix.max": "前缀不能超过255个字符",
+		"Limit.min":  "每页数量不能小于1",
+		"Limit.max":  "每页数量不能超过1000",
+	}
+}
+
+type DeleteOrphanObjectsDTO struct {
+	Keys []string `json:"keys" binding:"required,min=1,max=200"`
+}
+
+func (d *DeleteOrphanObjectsDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Keys.required": "请选择要删除的对象",
+		"Keys.min":      "请选择要删除的对象",
+		"Keys.max":      "单次最多删除200个对象",
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"pixelpunk/internal/controllers/storage/dto"
+	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/storage"
 	"pixelpunk/pkg/common"
@@ -92,6 +93,7 @@ func UpdateChannel(ctx *gin.Context) {
 		errors.HandleError(ctx, errors.New(errors.CodeNotFound, "存储渠道不存在"))
 		return
 	}
+	before := channelAuditSnapshot(channelID)
 
 	if req.Name != "" {
 		channel.Name = req.Name
@@ -130,6 +132,7 @@ func UpdateChannel(ctx *gin.Context) {
 
 	if err := storage.RefreshChannelCache(channelID); err != nil {
 	}
+	middleware.SetAuditChange(ctx, "storage.update", "storage_channel", channelID, before, channelAuditSnapshot(channelID))
 
 	errors.ResponseSuccess(ctx, channel, "更新存储渠道成功")
 }
@@ -141,6 +144,8 @@ func DeleteChannel(ctx *gin.Context) {
 		return
 	}
 
+	before := channelAuditSnapshot(channelID)
+
 	if err := storage.DeleteChannel(channelID); err != nil {
 		if _, ok := err.(*errors.Error); ok {
 			errors.HandleError(ctx, err)
@@ -150,6 +155,8 @@ func DeleteChannel(ctx *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditChange(ctx, "storage.delete", "storage_channel", channelID, before, nil)
+
 	errors.ResponseSuccess(ctx, nil, "删除存储渠道成功")
 }
 
@@ -247,6 +254,8 @@ func UpdateChannelConfigs(ctx *gin.Context) {
 		return
 	}
 
+	before := channelAuditSnapshot(channelID)
+
 	if err := storage.UpdateChannelConfigs(channelID, *req); err != nil {
 		errors.HandleError(ctx, errors.New(errors.CodeDBUpdateFailed, "更新渠道配置失败"))
 		return
@@ -254,6 +263,7 @@ func UpdateChannelConfigs(ctx *gin.Context) {
 
 	if err := storage.RefreshChannelCache(channelID); err != nil {
 	}
+	middleware.SetAuditChange(ctx, "storage.update_configs", "storage_channel", channelID, before, channelAuditSnapshot(channelID))
 
 	errors.ResponseSuccess(ctx, nil, "更新渠道配置成功")
 }
@@ -428,3 +438,67 @@ func ClearAllChannelCache(ctx *gin.Context) {
 
 	errors.ResponseSuccess(ctx, nil, "所有渠道缓存清空成功")
 }
+
+// channelAuditSnapshot 审计日志中记录的渠道基本信息与配置，密钥类配置不记录原值
+func channelAuditSnapshot(channelID string) interface{} {
+	channel, err := storage.GetChannelByID(channelID)
+	if err != nil {
+		return nil
+	}
+	configs := make(map[string]interface{})
+	if items, err := storage.GetChannelConfigs(channelID); err == nil {
+		for _, item := range items {
+			if item.IsSecret {
+				if item.Value != "" {
+					configs[item.KeyName] = "******"
+				}
+				continue
+			}
+			configs[item.KeyName] = item.Value
+		}
+	}
+	return map[string]interface{}{
+		"name":       channel.Name,
+		"status":     channel.Status,
+		"is_default": channel.IsDefault,
+		"remark":     channel.Remark,
+		"configs":    configs,
+	}
+}
+
+func ListChannelObjects(ctx *gin.Context) {
+	req, err := common.ValidateRequest[dto.ListChannelObjectsDTO](ctx)
+	if err != nil {
+		errors.HandleError(ctx, err)
+		return
+	}
+
+	page, err := storage.ListChannelObjects(ctx.Param("id"), req.Prefix, req.Cursor, req.Limit)
+	if err != nil {
+		errors.HandleError(ctx, err)
+		return
+	}
+
+	errors.ResponseSuccess(ctx, page, "获取存储对象列表成功")
+}
+
+func DeleteOrphanObjects(ctx *gin.Context) {
+	req, err := common.ValidateRequest[dto.DeleteOrphanObjectsDTO](ctx)
+	if err != nil {
+		errors.HandleError(ctx, err)
+		return
+	}
+
+	channelID := ctx.Param("id")
+	result, err := storage.DeleteOrphanObjects(channelID, req.Keys)
+	if err != nil {
+		errors.HandleError(ctx, err)
+		return
+	}
+
+	if len(result.Deleted) > 0 {
+		middleware.SetAuditChange(ctx, "storage.delete_orphans", "storage_channel", channelID, nil, gin.H{"deleted": result.Deleted})
+	}
+
+	errors.ResponseSuccess(ctx, result, "清理未引用对象完成")
+}
@@ -68,7 +68,7 @@ func (gc *GlobalTagController) CreateTag(c *gin.Context) {
 		if upd, updErr := gc.globalTagService.UpdateGlobalTag(tag.ID, nil, nil, req.SortOrder); updErr == nil {
 			tag = upd
 		} else {
-			logger.Warn("更新标签排序失败: %v", updErr)
+			logger.WarnContext(c.Request.Context(), "更新标签排序失败: %v", updErr)
 		}
 	}
 
@@ -163,7 +163,7 @@ func (gc *GlobalTagController) GetFileTags(c *gin.Context) {
 	for _, tag := range tags {
 		responseItem, err := gc.globalTagService.ConvertToResponseItem(tag)
 		if err != nil {
-			logger.Warn("转换标签响应项失败: %v", err)
+			logger.WarnContext(c.Request.Context(), "转换标签响应项失败: %v", err)
 			responseItem = tagService.GlobalTagResponseItem{
 				ID:          tag.ID,
 				Name:        tag.Name,
@@ -284,7 +284,7 @@ func (gc *GlobalTagController) SearchTags(c *gin.Context) {
 	for _, tag := range tags {
 		responseItem, err := gc.globalTagService.ConvertToResponseItem(tag)
 		if err != nil {
-			logger.Warn("转换标签响应项失败: %v", err)
+			logger.WarnContext(c.Request.Context(), "转换标签响应项失败: %v", err)
 			responseItem = tagService.GlobalTagResponseItem{
 				ID:          tag.ID,
 				Name:        tag.Name,
@@ -320,7 +320,7 @@ func (gc *GlobalTagController) GetTagStats(c *gin.Context) {
 	for _, tag := range popularTags {
 		responseItem, err := gc.globalTagService.ConvertToResponseItem(tag)
 		if err != nil {
-			logger.Warn("转换标签响应项失败: %v", err)
+			logger.WarnContext(c.Request.Context(), "转换标签响应项失败: %v", err)
 			responseItem = tagService.GlobalTagResponseItem{
 				ID:          tag.ID,
 				Name:        tag.Name,
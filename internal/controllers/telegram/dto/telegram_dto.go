@@ -0,0 +1,11 @@
+package dto
+
+type UpdateUploadFolderDTO struct {
+	FolderID string `json:"folder_id" binding:"omitempty,max=32"`
+}
+
+func (d *UpdateUploadFolderDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"FolderID.max": "文件夹ID格式不正确",
+	}
+}
@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"pixelpunk/internal/controllers/telegram/dto"
+	"pixelpunk/internal/middleware"
+	telegramService "pixelpunk/internal/services/telegram"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* GetBinding 获取机器人开启状态与当前用户的绑定信息 */
+func GetBinding(c *gin.Context) {
+	status, err := telegramService.GetBindingStatus(middleware.GetCurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, status, "获取成功")
+}
+
+/* CreateBindCode 生成绑定码，10分钟内有效 */
+func CreateBindCode(c *gin.Context) {
+	code, err := telegramService.CreateBindCode(middleware.GetCurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, code, "生成绑定码成功")
+}
+
+/* UpdateUploadFolder 设置机器人上传的目标文件夹 */
+func UpdateUploadFolder(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UpdateUploadFolderDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	binding, err := telegramService.UpdateUploadFolder(middleware.GetCurrentUserID(c), req.FolderID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, binding, "更新成功")
+}
+
+/* Unbind 解除绑定 */
+func Unbind(c *gin.Context) {
+	if err := telegramService.Unbind(middleware.GetCurrentUserID(c)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, nil, "已解除绑定")
+}
@@ -0,0 +1,87 @@
+package user
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"pixelpunk/internal/controllers/user/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/account"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateDataExport(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	job, err := account.CreateDataExportJob(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, job, "数据导出任务已创建")
+}
+
+func GetDataExport(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	job, err := account.GetDataExportJob(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, job, "获取数据导出任务成功")
+}
+
+func DownloadDataExport(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	archive, job, err := account.OpenDataExport(userID, c.Param("job_id"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer archive.Close()
+
+	fileName := fmt.Sprintf("pixelpunk_data_%s.zip", job.CreatedAt.Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", utils.SetContentDispositionFilename(fileName))
+	c.Header("Content-Length", fmt.Sprintf("%d", job.ArchiveSize))
+	c.Status(http.StatusOK)
+
+	_, _ = io.Copy(c.Writer, archive)
+}
+
+func RequestAccountDeletion(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.AccountDeletionDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+
+	scheduledAt, err := account.ScheduleAccountDeletion(userID, req.Password, req.Confirm)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"deletion_scheduled_at": scheduledAt}, "已申请注销账号，冷静期结束后将删除全部数据")
+}
+
+func CancelAccountDeletion(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := account.CancelAccountDeletion(userID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已撤销注销申请")
+}
@@ -35,11 +35,13 @@ func AdminUpdateUser(c *gin.Context) {
 	}
 
 	currentUserID := middleware.GetCurrentUserID(c)
+	before := auditUserSnapshot(req.ID)
 
 	if err := user.AdminUpdateUser(req, currentUserID); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "user.update", "user", strconv.FormatUint(uint64(req.ID), 10), before, auditUserSnapshot(req.ID))
 
 	errors.ResponseSuccess(c, nil, "更新用户信息成功")
 }
@@ -90,11 +92,13 @@ func AdminUpdateUserStorage(c *gin.Context) {
 	}
 
 	req.CurrentUserID = middleware.GetCurrentUserID(c)
+	before := auditUserSnapshot(req.UserID)
 
 	if err := user.AdminUpdateUserStorage(req); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "user.update_storage", "user", strconv.FormatUint(uint64(req.UserID), 10), before, auditUserSnapshot(req.UserID))
 
 	errors.ResponseSuccess(c, nil, "更新存储设置成功")
 }
@@ -117,6 +121,7 @@ func AdminResetUserPassword(c *gin.Context) {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "user.reset_password", "user", idStr, nil, nil)
 
 	errors.ResponseSuccess(c, result, "重置密码成功")
 }
@@ -143,10 +148,13 @@ func AdminToggleUserStatus(c *gin.Context) {
 		return
 	}
 
+	before := auditUserSnapshot(req.UserID)
+
 	if err := user.AdminToggleUserStatus(req); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "user.update_status", "user", strconv.FormatUint(uint64(req.UserID), 10), before, auditUserSnapshot(req.UserID))
 
 	errors.ResponseSuccess(c, nil, "用户状态更新成功")
 }
@@ -164,10 +172,13 @@ func AdminDeleteUser(c *gin.Context) {
 		return
 	}
 
+	before := auditUserSnapshot(uint(id))
+
 	if err := user.AdminDeleteUser(uint(id)); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	middleware.SetAuditChange(c, "user.delete", "user", idStr, before, auditUserSnapshot(uint(id)))
 
 	errors.ResponseSuccess(c, nil, "删除用户成功")
 }
@@ -186,3 +197,20 @@ func AdminBatchOperateUsers(c *gin.Context) {
 
 	errors.ResponseSuccess(c, nil, "批量操作成功")
 }
+
+// auditUserSnapshot 审计日志中记录的用户关键字段
+func auditUserSnapshot(userID uint) interface{} {
+	detail, err := user.AdminGetUserByID(userID)
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"username":        detail.Username,
+		"email":           detail.Email,
+		"status":          detail.Status,
+		"role":            detail.Role,
+		"group_id":        detail.GroupID,
+		"storage_limit":   detail.StorageLimit,
+		"bandwidth_limit": detail.BandwidthLimit,
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+type AccountDeletionDTO struct {
+	Password string `json:"password"` // 有密码的账号必填
+	Confirm  string `json:"confirm"`  // 无密码的账号（第三方登录）需填写用户名确认
+}
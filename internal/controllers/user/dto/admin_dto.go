@@ -7,14 +7,14 @@ type AdminUserListQueryDTO struct {
 	Size    int    `form:"size" json:"size"`       // 每页数量
 	Keyword string `form:"keyword" json:"keyword"` // 关键字 (用户名或邮箱)
 	Status  int    `form:"status" json:"status"`   // 用户状态, 0:所有状态, 1:正常, 2:禁用, 3:删除
-	Role    int    `form:"role" json:"role"`       // 用户角色, 0:所有角色, 1:超级管理员, 2:管理员, 3:普通用户
+	Role    int    `form:"role" json:"role"`       // 用户角色, 0:所有角色, 1:超级管理员, 2:管理员, 3:普通用户, 4:审核员
 }
 
 type AdminUpdateUserDTO struct {
 	ID       uint   `json:"id" binding:"required"`                    // 用户ID
 	Username string `json:"username" binding:"required,min=2,max=50"` // 用户名
 	Status   int    `json:"status" binding:"required,oneof=1 2 3"`    // 用户状态, 1:正常, 2:禁用, 3:删除
-	Role     int    `json:"role" binding:"required,oneof=1 2 3"`      // 用户角色, 1:超级管理员, 2:管理员, 3:普通用户
+	Role     int    `json:"role" binding:"required,oneof=1 2 3 4"`    // 用户角色, 1:超级管理员, 2:管理员, 3:普通用户, 4:审核员
 }
 
 func (d *AdminUpdateUserDTO) GetValidationMessages() map[string]string {
@@ -38,6 +38,7 @@ type AdminUserResponseDTO struct {
 	AvatarFullPath string           `json:"avatar_full_path"`
 	Status         int              `json:"status"`
 	Role           int              `json:"role"`
+	GroupID        *uint            `json:"group_id"`         // 所属用户组，组内配额优先于下方单独限额
 	StorageLimit   int64            `json:"storage_limit"`    // 存储空间限制（字节）
 	BandwidthLimit int64            `json:"bandwidth_limit"`  // 带宽限制（字节）
 	UsedStorage    int64            `json:"used_storage"`     // 已使用存储（字节）
@@ -59,7 +60,7 @@ type AdminCreateUserDTO struct {
 	Username       string `json:"username" binding:"required,min=2,max=50"` // 用户名
 	Email          string `json:"email" binding:"required,email,max=100"`   // 邮箱
 	Password       string `json:"password" binding:"required,min=6,max=50"` // 密码
-	Role           int    `json:"role" binding:"required,oneof=1 2 3"`      // 用户角色, 1:超级管理员, 2:管理员, 3:普通用户
+	Role           int    `json:"role" binding:"required,oneof=1 2 3 4"`    // 用户角色, 1:超级管理员, 2:管理员, 3:普通用户, 4:审核员
 	StorageLimit   int64  `json:"storage_limit,omitempty"`                  // 存储空间限制（字节），可选
 	BandwidthLimit int64  `json:"bandwidth_limit,omitempty"`                // 带宽限制（字节），可选
 }
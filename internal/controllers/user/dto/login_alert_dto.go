@@ -0,0 +1,11 @@
+package dto
+
+type LoginAlertLockDTO struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (d *LoginAlertLockDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Token.required": "锁定令牌不能为空",
+	}
+}
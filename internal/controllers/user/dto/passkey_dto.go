@@ -0,0 +1,32 @@
+package dto
+
+import "encoding/json"
+
+type PasskeyLoginBeginDTO struct {
+	Account string `json:"account"` // 可选，留空时使用可发现凭据登录
+}
+
+type PasskeyFinishDTO struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Name       string          `json:"name" binding:"max=100"` // 注册时的设备名称
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+func (d *PasskeyFinishDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"SessionID.required":  "会话ID不能为空",
+		"Name.max":            "设备名称不能超过100个字符",
+		"Credential.required": "凭据不能为空",
+	}
+}
+
+type PasskeyRenameDTO struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (d *PasskeyRenameDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.required": "设备名称不能为空",
+		"Name.max":      "设备名称不能超过100个字符",
+	}
+}
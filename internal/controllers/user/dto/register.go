@@ -1,10 +1,11 @@
 package dto
 
 type RegisterDTO struct {
-	Username string `json:"username" binding:"required,min=2,max=20"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6,max=20"`
-	Code     string `json:"code" binding:"required"`
+	Username   string `json:"username" binding:"required,min=2,max=20"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6,max=20"`
+	Code       string `json:"code" binding:"required"`
+	InviteCode string `json:"invite_code" binding:"omitempty,max=32"` // 仅邀请注册模式下必填
 }
 
 func (r *RegisterDTO) GetValidationMessages() map[string]string {
@@ -18,5 +19,6 @@ func (r *RegisterDTO) GetValidationMessages() map[string]string {
 		"Password.min":      "密码长度不能小于6个字符",
 		"Password.max":      "密码长度不能超过20个字符",
 		"Code.required":     "验证码不能为空",
+		"InviteCode.max":    "邀请码格式不正确",
 	}
 }
@@ -0,0 +1,11 @@
+package dto
+
+type SAMLExchangeDTO struct {
+	Ticket string `json:"ticket" binding:"required"`
+}
+
+func (d *SAMLExchangeDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Ticket.required": "登录票据不能为空",
+	}
+}
@@ -15,3 +15,25 @@ func (d *UpdateProfileDTO) GetValidationMessages() map[string]string {
 		"Website.max":  "个人网站地址不能超过255个字符",
 	}
 }
+
+type UpdateAIWebhookDTO struct {
+	URL              string `json:"url" binding:"omitempty,url,max=500"`
+	RegenerateSecret bool   `json:"regenerate_secret"`
+}
+
+func (d *UpdateAIWebhookDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"URL.url": "回调地址格式不正确",
+		"URL.max": "回调地址不能超过500个字符",
+	}
+}
+
+type UpdateAIProcessingDTO struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+func (d *UpdateAIProcessingDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Enabled.required": "请指定是否启用AI处理",
+	}
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"pixelpunk/internal/controllers/user/dto"
+	"pixelpunk/internal/services/user"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockAccountFromAlert 异常登录提醒中"这不是我"的锁定入口，凭令牌访问无需登录
+func LockAccountFromAlert(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.LoginAlertLockDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := user.LockAccountByAlertToken(req.Token); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "账号已锁定，所有设备已退出登录，请联系管理员解锁并修改密码")
+}
@@ -0,0 +1,141 @@
+package user
+
+import (
+	"pixelpunk/internal/controllers/user/dto"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/auth"
+	"pixelpunk/internal/services/user"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func BeginPasskeyLogin(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.PasskeyLoginBeginDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	options, sessionID, err := user.BeginPasskeyLogin(req.Account)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"session_id": sessionID,
+		"options":    options,
+	}, "获取登录挑战成功")
+}
+
+func FinishPasskeyLogin(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.PasskeyFinishDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	clientIP := utils.GetClientIP(c)
+	userInfo, token, err := user.FinishPasskeyLogin(req.SessionID, req.Credential, auth.ClientInfo{IP: clientIP, UserAgent: c.Request.UserAgent()})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if userID, ok := userInfo["id"].(uint); ok {
+		username, _ := userInfo["username"].(string)
+		activity.LogUserLogin(userID, username, clientIP)
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"token":    token,
+		"userInfo": userInfo,
+		"email":    userInfo["email"],
+	}, "登录成功")
+}
+
+func GetPasskeys(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	passkeys, err := user.ListUserPasskeys(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, passkeys, "获取通行密钥成功")
+}
+
+func BeginPasskeyRegistration(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	options, sessionID, err := user.BeginPasskeyRegistration(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"session_id": sessionID,
+		"options":    options,
+	}, "获取注册挑战成功")
+}
+
+func FinishPasskeyRegistration(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.PasskeyFinishDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+
+	passkey, err := user.FinishPasskeyRegistration(userID, req.SessionID, req.Name, req.Credential)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, passkey, "通行密钥注册成功")
+}
+
+func RenamePasskey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的通行密钥ID"))
+		return
+	}
+	req, err := common.ValidateRequest[dto.PasskeyRenameDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := user.RenameUserPasskey(userID, uint(id), req.Name); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "修改成功")
+}
+
+func DeletePasskey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的通行密钥ID"))
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := user.DeleteUserPasskey(userID, uint(id)); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "删除成功")
+}
@@ -0,0 +1,93 @@
+package user
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"pixelpunk/internal/controllers/user/dto"
+	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/auth"
+	samlService "pixelpunk/internal/services/saml"
+	"pixelpunk/internal/services/user"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 前端登录页，ACS 处理完成后携带票据或错误信息跳转回该页面
+const samlFrontendPath = "/auth"
+
+func SAMLMetadata(c *gin.Context) {
+	data, err := samlService.MetadataXML()
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInternal, err.Error()))
+		return
+	}
+	c.Data(http.StatusOK, "application/samlmetadata+xml", data)
+}
+
+func SAMLLogin(c *gin.Context) {
+	redirectURL, err := samlService.BeginLogin()
+	if err != nil {
+		logger.WarnContext(c.Request.Context(), "发起SAML登录失败: %v", err)
+		redirectSAMLResult(c, "saml_error", err.Error())
+		return
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// SAMLACS IdP 以 HTTP-POST 绑定回调，校验断言后签发一次性票据并跳转回前端
+func SAMLACS(c *gin.Context) {
+	identity, err := samlService.ParseResponse(c.Request)
+	if err != nil {
+		redirectSAMLResult(c, "saml_error", err.Error())
+		return
+	}
+
+	ticket, err := user.CreateSAMLLoginTicket(identity)
+	if err != nil {
+		message := err.Error()
+		if appErr, ok := err.(*errors.Error); ok {
+			message = appErr.Message
+		}
+		redirectSAMLResult(c, "saml_error", message)
+		return
+	}
+
+	redirectSAMLResult(c, "saml_ticket", ticket)
+}
+
+func SAMLExchange(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.SAMLExchangeDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	clientIP := utils.GetClientIP(c)
+	userInfo, token, err := user.ExchangeSAMLTicket(req.Ticket, auth.ClientInfo{IP: clientIP, UserAgent: c.Request.UserAgent()})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if userID, ok := userInfo["id"].(uint); ok {
+		username, _ := userInfo["username"].(string)
+		activity.LogUserLogin(userID, username, clientIP)
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"token":    token,
+		"userInfo": userInfo,
+		"email":    userInfo["email"],
+	}, "登录成功")
+}
+
+func redirectSAMLResult(c *gin.Context, key, value string) {
+	target := strings.TrimRight(utils.GetBaseUrl(), "/") + samlFrontendPath + "?" + url.Values{key: {value}}.Encode()
+	c.Redirect(http.StatusFound, target)
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/auth"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetSessions(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	currentID := middleware.GetCurrentSessionID(c)
+
+	sessions, err := auth.ListUserSessions(userID)
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeDBQueryFailed, "获取登录会话失败"))
+		return
+	}
+
+	list := make([]gin.H, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, gin.H{
+			"id":           s.ID,
+			"login_method": s.LoginMethod,
+			"device_name":  s.DeviceName,
+			"user_agent":   s.UserAgent,
+			"client_ip":    s.ClientIP,
+			"last_seen_ip": s.LastSeenIP,
+			"last_seen_at": s.LastSeenAt,
+			"created_at":   s.CreatedAt,
+			"expires_at":   s.ExpiresAt,
+			"current":      s.ID == currentID,
+		})
+	}
+
+	errors.ResponseSuccess(c, list, "获取登录会话成功")
+}
+
+func RevokeSession(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	sessionID := c.Param("id")
+
+	found, err := auth.RevokeUserSession(userID, sessionID)
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeDBUpdateFailed, "注销登录会话失败"))
+		return
+	}
+	if !found {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, "登录会话不存在"))
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "登录会话已注销")
+}
+
+func RevokeOtherSessions(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	count, err := auth.RevokeUserSessions(userID, middleware.GetCurrentSessionID(c))
+	if err != nil {
+		errors.HandleError(c, errors.Wrap(err, errors.CodeDBUpdateFailed, "注销登录会话失败"))
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"revoked": count}, "其他设备已退出登录")
+}
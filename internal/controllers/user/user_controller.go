@@ -4,10 +4,13 @@ import (
 	"pixelpunk/internal/controllers/user/dto"
 	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/services/activity"
+	"pixelpunk/internal/services/auth"
 	"pixelpunk/internal/services/user"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
 	"pixelpunk/pkg/utils"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,7 +22,7 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	userID, err := user.RegisterUser(req.Username, req.Email, req.Password, req.Code)
+	userID, err := user.RegisterUser(req.Username, req.Email, req.Password, req.Code, req.InviteCode)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -37,7 +40,8 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	userInfo, token, err := user.Login(req.Account, req.Password)
+	clientIP := utils.GetClientIP(c)
+	userInfo, token, err := user.Login(req.Account, req.Password, auth.ClientInfo{IP: clientIP, UserAgent: c.Request.UserAgent()})
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -65,8 +69,6 @@ func Login(c *gin.Context) {
 				}
 			}
 
-			clientIP := utils.GetClientIP(c)
-
 			activity.LogUserLogin(userID, username, clientIP)
 		}
 	}
@@ -133,6 +135,11 @@ func UpdatePassword(c *gin.Context) {
 		return
 	}
 
+	// 修改密码后其他设备上的登录会话全部失效
+	if _, err := auth.RevokeUserSessions(userID, middleware.GetCurrentSessionID(c)); err != nil {
+		logger.WarnContext(c.Request.Context(), "修改密码后注销其他会话失败: userID=%d, error=%v", userID, err)
+	}
+
 	activity.LogPasswordChange(userID)
 
 	errors.ResponseSuccess(c, nil, "密码修改成功")
@@ -222,6 +229,12 @@ func GetProfile(c *gin.Context) {
 		"bio":            userInfo.Bio,
 		"website":        userInfo.Website,
 		"role":           userInfo.Role,
+
+		"deletion_scheduled_at": userInfo.DeletionScheduledAt,
+	}
+	if userInfo.HasPendingEmail() {
+		data["pending_email"] = userInfo.PendingEmail
+		data["pending_email_expires_at"] = userInfo.PendingEmailExpiresAt
 	}
 
 	errors.ResponseSuccess(c, data, "获取成功")
@@ -262,3 +275,71 @@ func ChangeEmail(c *gin.Context) {
 
 	errors.ResponseSuccess(c, nil, "邮箱更换成功")
 }
+
+func CancelEmailChange(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := user.CancelEmailChange(userID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "已取消更换邮箱")
+}
+
+// GetAIWebhook 获取AI处理完成回调配置
+func GetAIWebhook(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	result, err := user.GetAIWebhookConfig(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取成功")
+}
+
+// UpdateAIWebhook 更新AI处理完成回调配置
+func UpdateAIWebhook(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UpdateAIWebhookDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	if req.URL != "" && !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "回调地址仅支持http/https"))
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+	result, err := user.UpdateAIWebhookConfig(userID, req.URL, req.RegenerateSecret)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, result, "回调配置已更新")
+}
+
+// GetAIProcessing 获取账户级AI处理开关
+func GetAIProcessing(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	settings, err := user.GetUserSettings(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{"enabled": settings.AIEnabled}, "获取成功")
+}
+
+// UpdateAIProcessing 更新账户级AI处理开关，关闭后新上传的文件不会发送给AI
+func UpdateAIProcessing(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UpdateAIProcessingDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	userID := middleware.GetCurrentUserID(c)
+	if err := user.SetUserAIEnabled(userID, *req.Enabled); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, gin.H{"enabled": *req.Enabled}, "AI处理设置已更新")
+}
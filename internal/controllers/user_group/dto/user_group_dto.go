@@ -0,0 +1,35 @@
+package dto
+
+type UserGroupSaveDTO struct {
+	Name             string `json:"name" binding:"required,max=50"`
+	Description      string `json:"description" binding:"omitempty,max=255"`
+	StorageLimit     int64  `json:"storage_limit" binding:"min=0"`       // 字节，0表示沿用用户设置
+	BandwidthLimit   int64  `json:"bandwidth_limit" binding:"min=0"`     // 字节，0表示沿用用户设置
+	DailyUploadLimit int    `json:"daily_upload_limit" binding:"min=-1"` // 0表示沿用全局设置，-1表示不限制
+	DailyAILimit     int    `json:"daily_ai_limit" binding:"min=-1"`     // 0表示不限制，-1表示禁用AI处理
+}
+
+func (d *UserGroupSaveDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.required":        "用户组名称不能为空",
+		"Name.max":             "用户组名称不能超过50个字符",
+		"Description.max":      "描述不能超过255个字符",
+		"StorageLimit.min":     "存储空间不能为负数",
+		"BandwidthLimit.min":   "流量限制不能为负数",
+		"DailyUploadLimit.min": "每日上传限制无效",
+		"DailyAILimit.min":     "每日AI处理限制无效",
+	}
+}
+
+type UserGroupAssignDTO struct {
+	GroupID uint   `json:"group_id"` // 0表示移出用户组
+	UserIDs []uint `json:"user_ids" binding:"required,min=1,max=500"`
+}
+
+func (d *UserGroupAssignDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"UserIDs.required": "请选择用户",
+		"UserIDs.min":      "请选择用户",
+		"UserIDs.max":      "单次最多设置500个用户",
+	}
+}
@@ -0,0 +1,114 @@
+package user_group
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/user_group/dto"
+	userGroupService "pixelpunk/internal/services/user_group"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* ListGroups 获取用户组列表 */
+func ListGroups(c *gin.Context) {
+	groups, err := userGroupService.ListGroups()
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, groups, "获取用户组列表成功")
+}
+
+/* CreateGroup 创建用户组 */
+func CreateGroup(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UserGroupSaveDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	group, err := userGroupService.CreateGroup(toGroupInput(req))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, group, "创建用户组成功")
+}
+
+/* UpdateGroup 更新用户组配额 */
+func UpdateGroup(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	req, err := common.ValidateRequest[dto.UserGroupSaveDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	group, err := userGroupService.UpdateGroup(groupID, toGroupInput(req))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, group, "更新用户组成功")
+}
+
+/* DeleteGroup 删除用户组 */
+func DeleteGroup(c *gin.Context) {
+	groupID, ok := parseGroupID(c)
+	if !ok {
+		return
+	}
+
+	if err := userGroupService.DeleteGroup(groupID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, nil, "删除用户组成功")
+}
+
+/* AssignUsers 批量设置用户所属用户组 */
+func AssignUsers(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.UserGroupAssignDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	affected, err := userGroupService.AssignUsers(req.GroupID, req.UserIDs)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"affected": affected}, "设置用户组成功")
+}
+
+func toGroupInput(req *dto.UserGroupSaveDTO) userGroupService.GroupInput {
+	return userGroupService.GroupInput{
+		Name:             req.Name,
+		Description:      req.Description,
+		StorageLimit:     req.StorageLimit,
+		BandwidthLimit:   req.BandwidthLimit,
+		DailyUploadLimit: req.DailyUploadLimit,
+		DailyAILimit:     req.DailyAILimit,
+	}
+}
+
+func parseGroupID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的用户组ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
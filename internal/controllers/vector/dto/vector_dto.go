@@ -137,3 +137,49 @@ type VectorRegenerateAllRequest struct {
 func (r *VectorRegenerateAllRequest) GetValidationMessages() map[string]string {
 	return map[string]string{}
 }
+
+type VectorReindexRequest struct {
+	BatchSize  int `json:"batch_size" binding:"omitempty,min=1,max=100"`    // 每批向量化数量
+	IntervalMs int `json:"interval_ms" binding:"omitempty,min=1,max=60000"` // 批次间隔(毫秒)，用于限速
+}
+
+func (r *VectorReindexRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"BatchSize.min":  "每批数量不能小于1",
+		"BatchSize.max":  "每批数量不能超过100",
+		"IntervalMs.min": "批次间隔不能小于1毫秒",
+		"IntervalMs.max": "批次间隔不能超过60000毫秒",
+	}
+}
+
+type VectorDuplicateListRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending ignored resolved"`
+	UserID uint   `form:"user_id"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+func (r *VectorDuplicateListRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Status.oneof": "状态只能是 pending、ignored 或 resolved",
+	}
+}
+
+type VectorDuplicateScanRequest struct {
+	Full bool `json:"full"` // 是否全量扫描，默认仅扫描上次扫描后新增的向量
+}
+
+func (r *VectorDuplicateScanRequest) GetValidationMessages() map[string]string {
+	return map[string]string{}
+}
+
+type VectorDuplicateStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending ignored resolved"`
+}
+
+func (r *VectorDuplicateStatusRequest) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Status.required": "状态不能为空",
+		"Status.oneof":    "状态只能是 pending、ignored 或 resolved",
+	}
+}
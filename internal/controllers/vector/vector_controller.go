@@ -10,6 +10,10 @@ import (
 	vectorService "pixelpunk/internal/services/vector"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -120,6 +124,7 @@ func RetryVector(c *gin.Context) {
 func GetVectorStats(c *gin.Context) {
 	if svc := vectorService.GetGlobalVectorQueueService(); svc != nil {
 		stats := svc.GetQueueStats()
+		stats["subsystem"] = vectorService.GetVectorSubsystemStats()
 		errors.ResponseSuccess(c, stats, "获取统计信息成功")
 		return
 	}
@@ -266,6 +271,70 @@ func RegenerateAllVectors(c *gin.Context) {
 	errors.ResponseSuccess(c, nil, message)
 }
 
+// StartVectorReindex 删除并重建向量集合，后台重新生成全部向量
+func StartVectorReindex(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.VectorReindexRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	progress, err := vectorService.StartVectorReindex(req.BatchSize, req.IntervalMs)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInternal, "启动向量重建失败: "+err.Error()))
+		return
+	}
+
+	errors.ResponseSuccess(c, progress, "向量重建任务已启动")
+}
+
+// GetVectorReindexProgress 获取向量重建进度
+func GetVectorReindexProgress(c *gin.Context) {
+	errors.ResponseSuccess(c, vectorService.GetVectorReindexProgress(), "获取重建进度成功")
+}
+
+// ExportVectors 导出全部向量（gzip 压缩的 JSON Lines）
+func ExportVectors(c *gin.Context) {
+	if !vector.IsVectorEnabled() {
+		errors.HandleError(c, errors.New(errors.CodeServiceUnavailable, "向量搜索服务不可用"))
+		return
+	}
+
+	fileName := fmt.Sprintf("vectors-%s.jsonl.gz", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Status(http.StatusOK)
+
+	// 响应已开始写出，失败时只能记录日志
+	if _, err := vectorService.ExportVectors(c.Writer); err != nil {
+		logger.ErrorContext(c.Request.Context(), "导出向量失败: %v", err)
+	}
+}
+
+// ImportVectors 从导出文件恢复向量
+func ImportVectors(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "请上传向量导出文件"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "读取上传文件失败: "+err.Error()))
+		return
+	}
+	defer file.Close()
+
+	count, err := vectorService.ImportVectors(file)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInternal, fmt.Sprintf("导入向量失败（已导入 %d 条）: %v", count, err)))
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{"imported": count}, "向量导入完成")
+}
+
 func RetryAllFailedVectors(c *gin.Context) {
 	err := vectorService.RetryAllFailedVectors()
 	if err != nil {
@@ -413,3 +482,98 @@ func GetQdrantRealStats(c *gin.Context) {
 
 	errors.ResponseSuccess(c, realStats, message)
 }
+
+// GetVectorDuplicates 获取近似重复文件报告（全部用户，可按用户筛选）
+func GetVectorDuplicates(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.VectorDuplicateListRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := vectorService.ListVectorDuplicates(req.UserID, req.Status, req.Page, req.Limit)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, "获取近似重复列表失败: "+err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, result, "获取近似重复列表成功")
+}
+
+// ScanVectorDuplicates 立即执行一次近似重复扫描
+func ScanVectorDuplicates(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.VectorDuplicateScanRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	result, err := vectorService.ScanVectorDuplicates(req.Full)
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInternal, "近似重复扫描失败: "+err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, result, fmt.Sprintf("扫描完成，发现 %d 组近似重复", result.Found))
+}
+
+// UpdateVectorDuplicateStatus 修改近似重复记录状态
+func UpdateVectorDuplicateStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的记录ID"))
+		return
+	}
+
+	req, err := common.ValidateRequest[dto.VectorDuplicateStatusRequest](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := vectorService.UpdateVectorDuplicateStatus(0, uint(id), req.Status); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeNotFound, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, nil, "更新成功")
+}
+
+// GetVectorCollections 获取向量集合版本（含构建进度）
+func GetVectorCollections(c *gin.Context) {
+	collections, err := vectorService.GetVectorCollections()
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeDBQueryFailed, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, collections, "获取向量集合版本成功")
+}
+
+// SyncVectorPayload 为已有向量回填文件元数据（归属、访问级别及分面字段）
+func SyncVectorPayload(c *gin.Context) {
+	result, err := vectorService.SyncVectorPayload()
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeConflict, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, result, fmt.Sprintf("回填完成，成功 %d，失败 %d", result.Synced, result.Failed))
+}
+
+// StartVectorCollectionMigration 按当前配置的模型构建新版本集合，完成后自动切换
+func StartVectorCollectionMigration(c *gin.Context) {
+	record, err := vectorService.StartVectorCollectionMigration()
+	if err != nil {
+		errors.HandleError(c, errors.New(errors.CodeConflict, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, record, "已开始构建新版本向量集合")
+}
+
+func GetBuiltinQdrantStatus(c *gin.Context) {
+	errors.ResponseSuccess(c, vector.GetBuiltinQdrantStatus(), "获取成功")
+}
+
+func RestartBuiltinQdrant(c *gin.Context) {
+	if err := vector.RestartBuiltinQdrant(); err != nil {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, err.Error()))
+		return
+	}
+	errors.ResponseSuccess(c, nil, "已提交重启")
+}
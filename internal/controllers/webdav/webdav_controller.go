@@ -0,0 +1,48 @@
+package webdav
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	webdavService "pixelpunk/internal/services/webdav"
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/webdav"
+)
+
+// Prefix WebDAV 挂载路径
+const Prefix = "/dav"
+
+// 锁按用户与根目录隔离，不同用户的相同路径互不影响
+var lockSystems sync.Map
+
+/* Serve 处理 WebDAV 请求，文件系统按当前用户（或API密钥限定目录）构建 */
+func Serve(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	key := middleware.GetCurrentAPIKey(c)
+
+	handler := &webdav.Handler{
+		Prefix:     Prefix,
+		FileSystem: webdavService.NewLibraryFS(c, userID, key),
+		LockSystem: userLockSystem(userID, key),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				logger.Debug("WebDAV %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		},
+	}
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+func userLockSystem(userID uint, key *models.APIKey) webdav.LockSystem {
+	id := fmt.Sprintf("%d:", userID)
+	if key != nil && key.IsFolderRestricted() {
+		id += key.FolderID
+	}
+	ls, _ := lockSystems.LoadOrStore(id, webdav.NewMemLS())
+	return ls.(webdav.LockSystem)
+}
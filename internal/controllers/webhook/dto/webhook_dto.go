@@ -0,0 +1,49 @@
+package dto
+
+type SaveWebhookDTO struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	URL         string   `json:"url" binding:"required,url,max=500"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Enabled     *bool    `json:"enabled"`
+	Description string   `json:"description" binding:"omitempty,max=255"`
+}
+
+func (d *SaveWebhookDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Name.required":   "名称不能为空",
+		"Name.max":        "名称不能超过100个字符",
+		"URL.required":    "回调地址不能为空",
+		"URL.url":         "回调地址格式不正确",
+		"URL.max":         "回调地址不能超过500个字符",
+		"Events.required": "请至少订阅一个事件",
+		"Events.min":      "请至少订阅一个事件",
+		"Description.max": "描述不能超过255个字符",
+	}
+}
+
+type ToggleWebhookDTO struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+func (d *ToggleWebhookDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Enabled.required": "请指定启用状态",
+	}
+}
+
+type DeliveryListDTO struct {
+	Event  string `form:"event" binding:"omitempty,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=pending delivering retrying success failed"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Size   int    `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+func (d *DeliveryListDTO) GetValidationMessages() map[string]string {
+	return map[string]string{
+		"Event.max":    "事件名称不能超过100个字符",
+		"Status.oneof": "状态必须是pending、delivering、retrying、success或failed",
+		"Page.min":     "页码必须大于0",
+		"Size.min":     "每页数量必须大于0",
+		"Size.max":     "每页数量不能超过100",
+	}
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"strconv"
+
+	"pixelpunk/internal/controllers/webhook/dto"
+	"pixelpunk/internal/middleware"
+	webhookService "pixelpunk/internal/services/webhook"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* GetWebhookEvents 可订阅的事件列表 */
+func GetWebhookEvents(c *gin.Context) {
+	errors.ResponseSuccess(c, webhookService.SupportedEvents(), "获取成功")
+}
+
+/* ListWebhooks 获取全部 Webhook */
+func ListWebhooks(c *gin.Context) {
+	hooks, err := webhookService.List()
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, hooks, "获取成功")
+}
+
+/* GetWebhook 获取 Webhook 详情 */
+func GetWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	hook, err := webhookService.Get(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, hook, "获取成功")
+}
+
+/* CreateWebhook 创建 Webhook，响应中包含签名密钥 */
+func CreateWebhook(c *gin.Context) {
+	req, err := common.ValidateRequest[dto.SaveWebhookDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	hook, err := webhookService.Create(middleware.GetCurrentUserID(c), toInput(req))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.create", "webhook", strconv.FormatUint(uint64(hook.ID), 10), nil, auditSnapshot(hook))
+
+	errors.ResponseSuccess(c, hook, "创建成功，请妥善保存签名密钥")
+}
+
+/* UpdateWebhook 更新 Webhook */
+func UpdateWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	req, err := common.ValidateRequest[dto.SaveWebhookDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	before, err := webhookService.Get(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	hook, err := webhookService.Update(id, toInput(req))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.update", "webhook", c.Param("id"), auditSnapshot(before), auditSnapshot(hook))
+
+	errors.ResponseSuccess(c, hook, "更新成功")
+}
+
+/* ToggleWebhook 启用或停用 Webhook */
+func ToggleWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	req, err := common.ValidateRequest[dto.ToggleWebhookDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	hook, err := webhookService.SetEnabled(id, *req.Enabled)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.toggle", "webhook", c.Param("id"), nil, gin.H{"enabled": hook.Enabled})
+
+	errors.ResponseSuccess(c, hook, "更新成功")
+}
+
+/* RotateWebhookSecret 重新生成签名密钥 */
+func RotateWebhookSecret(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	hook, err := webhookService.RotateSecret(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.rotate_secret", "webhook", c.Param("id"), nil, nil)
+
+	errors.ResponseSuccess(c, hook, "密钥已重置，请同步更新接收端配置")
+}
+
+/* DeleteWebhook 删除 Webhook 及其投递记录 */
+func DeleteWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	hook, err := webhookService.Delete(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.delete", "webhook", c.Param("id"), gin.H{"name": hook.Name, "url": hook.URL}, nil)
+
+	errors.ResponseSuccess(c, nil, "删除成功")
+}
+
+/* PingWebhook 发送测试事件并返回投递结果 */
+func PingWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	delivery, err := webhookService.Ping(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, delivery, "测试事件已发送")
+}
+
+/* ListWebhookDeliveries 分页查看投递记录 */
+func ListWebhookDeliveries(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	req, err := common.ValidateRequest[dto.DeliveryListDTO](c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	page, size := req.Page, req.Size
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+
+	deliveries, total, err := webhookService.ListDeliveries(webhookService.DeliveryListParams{
+		WebhookID: id,
+		Event:     req.Event,
+		Status:    req.Status,
+		Page:      page,
+		Size:      size,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	errors.ResponseSuccess(c, gin.H{
+		"items": deliveries,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        page,
+			"size":        size,
+			"total_pages": (total + int64(size) - 1) / int64(size),
+		},
+	}, "获取成功")
+}
+
+/* GetWebhookDelivery 查看投递详情，包括请求体与响应内容 */
+func GetWebhookDelivery(c *gin.Context) {
+	id, ok := parseID(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	delivery, err := webhookService.GetDelivery(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	errors.ResponseSuccess(c, delivery, "获取成功")
+}
+
+/* RedeliverWebhookDelivery 以原请求体重新投递 */
+func RedeliverWebhookDelivery(c *gin.Context) {
+	id, ok := parseID(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	delivery, err := webhookService.Redeliver(id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	middleware.SetAuditChange(c, "webhook.redeliver", "webhook", strconv.FormatUint(uint64(delivery.WebhookID), 10), nil, gin.H{
+		"source_delivery_id": id,
+		"delivery_id":        delivery.ID,
+		"event":              delivery.Event,
+	})
+
+	errors.ResponseSuccess(c, delivery, "已重新投递")
+}
+
+func parseID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		errors.HandleError(c, errors.New(errors.CodeInvalidParameter, "无效的ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toInput(req *dto.SaveWebhookDTO) webhookService.WebhookInput {
+	return webhookService.WebhookInput{
+		Name:        req.Name,
+		URL:         req.URL,
+		Events:      req.Events,
+		Enabled:     req.Enabled,
+		Description: req.Description,
+	}
+}
+
+func auditSnapshot(hook *webhookService.WebhookResponse) gin.H {
+	return gin.H{
+		"name":    hook.Name,
+		"url":     hook.URL,
+		"events":  hook.Events,
+		"enabled": hook.Enabled,
+	}
+}
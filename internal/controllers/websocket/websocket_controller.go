@@ -90,6 +90,13 @@ func InitWebSocketManager() {
 	globalManager.Start()
 }
 
+// StopWebSocketManager 关闭所有连接并取消跨实例转发订阅
+func StopWebSocketManager() {
+	if globalManager != nil {
+		globalManager.Stop()
+	}
+}
+
 func GetWebSocketManager() *ws.Manager {
 	return globalManager
 }
@@ -114,7 +121,7 @@ func HandleWebSocket(c *gin.Context) {
 
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		logger.Error("WebSocket upgrade failed: %v", err)
+		logger.ErrorContext(c.Request.Context(), "WebSocket upgrade failed: %v", err)
 		return
 	}
 
@@ -127,6 +134,35 @@ func HandleWebSocket(c *gin.Context) {
 	go client.ReadPump(globalManager)
 }
 
+// HandleUserWebSocket 普通用户连接，仅接收与自己相关的通知
+func HandleUserWebSocket(c *gin.Context) {
+	claims, exists := c.Get("payload")
+	if !exists {
+		errors.HandleError(c, errors.New(errors.CodeUnauthorized, "User payload not found"))
+		return
+	}
+
+	jwtClaims, ok := claims.(*auth.JWTClaims)
+	if !ok {
+		errors.HandleError(c, errors.New(errors.CodeInvalidRequest, "Invalid user payload format"))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	// 用户通道不接收管理员推送
+	client := ws.NewClient(conn, jwtClaims.UserID, false)
+
+	globalManager.RegisterClient(client)
+
+	go client.WritePump()
+	go client.ReadPump(globalManager)
+}
+
 func BroadcastMessage(msgType ws.MessageType, data interface{}) {
 	if globalManager == nil {
 		return
@@ -146,6 +182,15 @@ func BroadcastToAdmins(msgType ws.MessageType, data interface{}) {
 
 }
 
+func SendToUser(userID uint, msgType ws.MessageType, data interface{}) {
+	if globalManager == nil {
+		return
+	}
+
+	msg := ws.NewMessage(msgType, data)
+	globalManager.SendToUser(userID, msg)
+}
+
 func SendToClient(clientID string, msgType ws.MessageType, data interface{}) error {
 	if globalManager == nil {
 		return errors.New(errors.CodeInternal, "WebSocket manager not initialized")
@@ -0,0 +1,34 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/account"
+	"pixelpunk/pkg/logger"
+)
+
+func registerAccountTask() {
+	// 清理冷静期已结束的注销账号 - 每小时执行
+	err := addCleanupTask("account_purge", "注销账号清理", "0 30 * * * *", func() (int64, error) {
+		purged, err := account.PurgeDueAccounts()
+		if err != nil {
+			logger.Error("清理已注销账号数据失败: %v", err)
+		} else if purged > 0 {
+			logger.Info("已清理 %d 个注销账号的数据", purged)
+		}
+		return int64(purged), err
+	})
+	if err != nil {
+		logger.Error("注册注销账号清理任务失败: %v", err)
+	}
+
+	// 清理过期的个人数据导出包 - 每小时执行
+	err = addCleanupTask("account_export_cleanup", "数据导出包清理", "0 10 * * * *", func() (int64, error) {
+		cleaned := account.CleanExpiredDataExports()
+		if cleaned > 0 {
+			logger.Info("已清理 %d 个过期的数据导出包", cleaned)
+		}
+		return int64(cleaned), nil
+	})
+	if err != nil {
+		logger.Error("注册数据导出清理任务失败: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/announcement"
+	"pixelpunk/pkg/logger"
+)
+
+func registerAnnouncementTask() {
+	// 定时公告发布与过期下线 - 每分钟执行
+	err := addTask("announcement_schedule", "定时公告", "0 * * * * *", func() (int64, error) {
+		published, archived, err := announcement.ApplyScheduledTransitions()
+		if err != nil {
+			logger.Error("处理定时公告失败: %v", err)
+			return 0, err
+		}
+		if published > 0 || archived > 0 {
+			logger.Info("定时公告已处理: 发布 %d 条, 下线 %d 条", published, archived)
+		}
+		return published + archived, nil
+	})
+	if err != nil {
+		logger.Error("注册定时公告任务失败: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/backup"
+	"pixelpunk/pkg/logger"
+)
+
+func registerBackupTask() {
+	// 自动备份 - 每小时检查一次，开启后在 backup.hour 设置的整点执行
+	err := addTask("backup", "自动备份", "0 5 * * * *", func() (int64, error) {
+		backup.RunScheduled()
+		return 0, nil
+	})
+	if err != nil {
+		logger.Error("注册自动备份任务失败: %v", err)
+	}
+}
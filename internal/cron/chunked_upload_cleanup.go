@@ -1,79 +1,26 @@
 package cron
 
 import (
-	"pixelpunk/internal/models"
-	"pixelpunk/pkg/common"
-	"pixelpunk/pkg/database"
+	filesvc "pixelpunk/internal/services/file"
 	"pixelpunk/pkg/logger"
-	"time"
-
-	"gorm.io/gorm"
 )
 
 /* ChunkedUploadCleanupJob 分片上传清理任务 */
-type ChunkedUploadCleanupJob struct {
-	db *gorm.DB
-}
+type ChunkedUploadCleanupJob struct{}
 
 /* NewChunkedUploadCleanupJob 创建分片上传清理任务 */
 func NewChunkedUploadCleanupJob() *ChunkedUploadCleanupJob {
-	return &ChunkedUploadCleanupJob{
-		db: database.GetDB(),
-	}
+	return &ChunkedUploadCleanupJob{}
 }
 
-/* Execute 执行清理任务 */
-func (j *ChunkedUploadCleanupJob) Execute() error {
-	expiredTime := time.Now().Add(-24 * time.Hour)
-
-	var expiredSessions []models.UploadSession
-	err := j.db.Where("updated_at < ? AND status NOT IN (?)", expiredTime, []string{"completed", "failed"}).
-		Find(&expiredSessions).Error
-	if err != nil {
-		logger.Error("查询过期上传会话失败: %v", err)
-		return err
+/* Execute 执行清理任务，回收过期会话、残留分片记录与临时文件 */
+func (j *ChunkedUploadCleanupJob) Execute() (*filesvc.UploadGCResult, error) {
+	result, err := filesvc.CollectUploadGarbage()
+	if result != nil && (result.ExpiredSessions > 0 || result.DeletedSessions > 0 || result.DeletedChunks > 0 || result.OrphanDirs > 0) {
+		logger.Info("分片上传清理: 过期会话 %d 个, 删除会话记录 %d 条, 分片记录 %d 条, 残留目录 %d 个, 释放 %d 字节",
+			result.ExpiredSessions, result.DeletedSessions, result.DeletedChunks, result.OrphanDirs, result.ReclaimedBytes)
 	}
-
-	if len(expiredSessions) == 0 {
-		return nil
-	}
-
-	batchSize := 50
-	for i := 0; i < len(expiredSessions); i += batchSize {
-		end := i + batchSize
-		if end > len(expiredSessions) {
-			end = len(expiredSessions)
-		}
-
-		batch := expiredSessions[i:end]
-		if err := j.cleanupSessionBatch(batch); err != nil {
-			logger.Error("清理会话批次失败: %v", err)
-			continue
-		}
-	}
-
-	return nil
-}
-
-func (j *ChunkedUploadCleanupJob) cleanupSessionBatch(sessions []models.UploadSession) error {
-	return j.db.Transaction(func(tx *gorm.DB) error {
-		for _, session := range sessions {
-			if err := tx.Where("session_id = ?", session.SessionID).Delete(&models.UploadChunk{}).Error; err != nil {
-				logger.Error("删除会话 %s 的分片记录失败: %v", session.SessionID, err)
-				return err
-			}
-
-			session.Status = "cleaned"
-			session.UpdatedAt = common.JSONTime(time.Now())
-			if err := tx.Save(&session).Error; err != nil {
-				logger.Error("更新会话 %s 状态失败: %v", session.SessionID, err)
-				return err
-			}
-
-		}
-
-		return nil
-	})
+	return result, err
 }
 
 /* GetName 获取任务名称 */
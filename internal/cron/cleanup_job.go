@@ -0,0 +1,50 @@
+package cron
+
+import (
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/setting"
+)
+
+// cleanupJobSep 清理任务ID中任务名与调度时间的分隔符
+const cleanupJobSep = "@"
+
+func init() {
+	qqueue.Define(qqueue.JobSpec{
+		Kind:        qqueue.KindCleanup,
+		Title:       "定时清理",
+		Handler:     runCleanupJob,
+		Policy:      cleanupRetryPolicy,
+		Concurrency: 2,
+		Lease:       time.Hour,
+	})
+}
+
+// cleanupRetryPolicy 清理任务重试策略，在设置分组 jobs 中按 cleanup_ 前缀调整
+func cleanupRetryPolicy() qqueue.RetryPolicy {
+	return qqueue.RetryPolicy{
+		MaxAttempts: setting.GetInt("jobs", "cleanup_max_attempts", 3),
+		BaseDelay:   time.Duration(setting.GetInt("jobs", "cleanup_retry_base_seconds", 60)) * time.Second,
+		MaxDelay:    time.Duration(setting.GetInt("jobs", "cleanup_retry_max_seconds", 900)) * time.Second,
+	}.Normalize()
+}
+
+// runCleanupJob 执行队列中的一轮清理任务并记录执行历史；任务已下线时直接完成，上一轮仍在执行时跳过本轮
+func runCleanupJob(id string) error {
+	name := id
+	if i := strings.LastIndex(id, cleanupJobSep); i > 0 {
+		name = id[:i]
+	}
+	t, err := lookupTask(name)
+	if err != nil {
+		return nil
+	}
+	lock, ok := t.acquire()
+	if !ok {
+		return nil
+	}
+	return t.run(lock, cronLockHold, models.CronTriggerSchedule)
+}
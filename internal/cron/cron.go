@@ -1,7 +1,10 @@
 package cron
 
 import (
+	"time"
+
 	"pixelpunk/internal/services/ai"
+	"pixelpunk/internal/services/setting"
 	"pixelpunk/internal/services/stats"
 	"pixelpunk/internal/services/tag"
 	vectorSvc "pixelpunk/internal/services/vector"
@@ -16,6 +19,13 @@ var cronManager *cron.Cron
 var db *gorm.DB
 var taggingService *ai.TaggingService
 
+const (
+	// cronLockTTL 任务锁租期，执行期间自动续期，实例异常退出后最多在租期后释放
+	cronLockTTL = 2 * time.Minute
+	// cronLockHold 任务执行完成后锁的最短保留时间，吸收实例间的时钟偏差，需小于最短调度间隔
+	cronLockHold = 5 * time.Second
+)
+
 /* InitCronManager 初始化定时任务管理器 */
 func InitCronManager() {
 	db = database.GetDB()
@@ -24,7 +34,8 @@ func InitCronManager() {
 		return
 	}
 
-	cronManager = cron.New(cron.WithSeconds())
+	cronManager = cron.New(cron.WithParser(cronParser))
+	resetTasks()
 
 	taggingService = ai.NewTaggingServiceWithConfig(db) // 使用配置的并发数
 	if taggingService == nil {
@@ -47,6 +58,12 @@ func registerTasks() {
 
 	registerShareTask()
 
+	registerReviewTask()
+
+	registerSessionCleanupTask()
+
+	registerAccountTask()
+
 	registerChunkedUploadCleanupTask()
 
 	registerVectorVerificationTask()
@@ -57,26 +74,42 @@ func registerTasks() {
 
 	registerVectorReconcileTasks()
 
+	registerVectorDuplicateTask()
+
 	registerTagUsageCountCalibrationTask()
 
+	registerAnnouncementTask()
+
+	registerTrashTask()
+
+	registerWebhookTask()
+
+	registerBackupTask()
+
+	registerHistoryTask()
+
 }
 
 func registerStatsTask() {
 	statsService := stats.NewGlobalStatsService(db)
 
-	_, err := cronManager.AddFunc("0 0 1 * * *", func() {
-		if err := statsService.ReconcileAllStats(); err != nil {
+	err := addTask("stats_reconcile_all", "全量统计校准", "0 0 1 * * *", func() (int64, error) {
+		err := statsService.ReconcileAllStats()
+		if err != nil {
 			logger.Warn("全量统计数据校准失败: %v", err)
 		}
+		return 0, err
 	})
 	if err != nil {
 		logger.Warn("注册全量统计校准任务失败: %v", err)
 	}
 
-	_, err = cronManager.AddFunc("0 0 * * * *", func() {
-		if err := statsService.ReconcileTodayStats(); err != nil {
+	err = addTask("stats_reconcile_today", "今日统计校准", "0 0 * * * *", func() (int64, error) {
+		err := statsService.ReconcileTodayStats()
+		if err != nil {
 			logger.Warn("今日统计数据校准失败: %v", err)
 		}
+		return 0, err
 	})
 	if err != nil {
 		logger.Warn("注册今日统计校准任务失败: %v", err)
@@ -84,33 +117,61 @@ func registerStatsTask() {
 }
 
 func registerImageTaggingTask() {
-	_, err := cronManager.AddFunc("0 */1 * * * *", func() {
+	err := addTask("ai_tagging_schedule", "AI打标调度", "0 */1 * * * *", func() (int64, error) {
 		ai.ScheduledTaggingTask()
+		return 0, nil
 	})
 
 	if err != nil {
 		logger.Error("注册文件标记定时任务失败: %v", err)
 	}
 
-	_, err = cronManager.AddFunc("0 0 * * * *", func() {
-		count, err := ai.ResetStuckPendingFiles(60) // 60分钟未更新的视为卡住
+	err = addTask("ai_reset_stuck", "重置卡住的打标任务", "0 * * * * *", func() (int64, error) {
+		s := ai.GetGlobalTaggingService()
+		if s == nil {
+			return 0, nil
+		}
+		threshold := setting.GetInt("ai", "pending_stuck_threshold_minutes", 5)
+		count, err := ai.ResetStuckPendingFiles(threshold)
 		if err != nil {
 			logger.Error("重置卡住的文件处理任务失败: %v", err)
-		} else if count > 0 {
+			return 0, err
+		}
+		if count > 0 && !s.IsPaused() {
+			_, _ = ai.EnqueueAllPending(1000)
 		}
+		return int64(count), nil
 	})
 	if err != nil {
 		logger.Error("注册卡住任务检查任务失败: %v", err)
 	}
+
+	err = addCleanupTask("ai_job_retention", "打标任务记录清理", "0 50 3 * * *", func() (int64, error) {
+		retention := setting.GetInt("ai", "ai_job_retention_days", 14)
+		n, err := ai.CleanOldAIJobs(retention)
+		if err != nil {
+			logger.Warn("清理打标任务记录失败: %v", err)
+		} else if n > 0 {
+			logger.Info("已清理 %d 条打标任务记录", n)
+		}
+		return n, err
+	})
+	if err != nil {
+		logger.Error("注册打标任务记录清理任务失败: %v", err)
+	}
 }
 
 func registerVectorQueueTask() {
-	_, err := cronManager.AddFunc("0 0/15 * * * *", func() {
-		if svc := vectorSvc.GetGlobalVectorQueueService(); svc != nil && !svc.IsPaused() {
-			if n, err := svc.EnqueueAllPending(1000); err == nil && n > 0 {
-				logger.Info("向量队列对账入队: %d", n)
-			}
+	err := addTask("vector_enqueue_pending", "向量队列对账", "0 0/15 * * * *", func() (int64, error) {
+		svc := vectorSvc.GetGlobalVectorQueueService()
+		if svc == nil || svc.IsPaused() {
+			return 0, nil
+		}
+		n, err := svc.EnqueueAllPending(1000)
+		if err == nil && n > 0 {
+			logger.Info("向量队列对账入队: %d", n)
 		}
+		return int64(n), err
 	})
 	if err != nil {
 		logger.Warn("注册向量队列定时任务失败: %v", err)
@@ -118,40 +179,64 @@ func registerVectorQueueTask() {
 }
 
 func registerVectorReconcileTasks() {
-	_, err := cronManager.AddFunc("0 0/15 * * * *", func() {
-		if svc := vectorSvc.GetGlobalVectorQueueService(); svc != nil && !svc.IsPaused() {
-			if total, enq, err := svc.ReconcileMissing(1000, false); err == nil {
-				if total > 0 {
-					logger.Info("向量补齐缺失：发现 %d，入队 %d", total, enq)
-				}
-			} else {
-				logger.Warn("向量补齐缺失失败: %v", err)
-			}
+	err := addTask("vector_reconcile_missing", "向量补齐缺失", "0 0/15 * * * *", func() (int64, error) {
+		svc := vectorSvc.GetGlobalVectorQueueService()
+		if svc == nil || svc.IsPaused() {
+			return 0, nil
+		}
+		total, enq, err := svc.ReconcileMissing(1000, false)
+		if err != nil {
+			logger.Warn("向量补齐缺失失败: %v", err)
+		} else if total > 0 {
+			logger.Info("向量补齐缺失：发现 %d，入队 %d", total, enq)
 		}
+		return int64(enq), err
 	})
 	_ = err // 忽略重复注册导致的警告
 
-	_, err = cronManager.AddFunc("0 30 3 * * *", func() {
-		if svc := vectorSvc.GetGlobalVectorQueueService(); svc != nil {
-			if total, removed, err := svc.CleanOrphans(2000, false); err == nil {
-				if total > 0 {
-					logger.Info("向量清理孤儿：发现 %d，删除 %d", total, removed)
-				}
-			} else {
-				logger.Warn("向量清理孤儿失败: %v", err)
-			}
+	err = addCleanupTask("vector_clean_orphans", "向量孤儿清理", "0 30 3 * * *", func() (int64, error) {
+		svc := vectorSvc.GetGlobalVectorQueueService()
+		if svc == nil {
+			return 0, nil
+		}
+		total, removed, err := svc.CleanOrphans(2000, false)
+		if err != nil {
+			logger.Warn("向量清理孤儿失败: %v", err)
+		} else if total > 0 {
+			logger.Info("向量清理孤儿：发现 %d，删除 %d", total, removed)
 		}
+		return int64(removed), err
 	})
 	_ = err // 忽略重复注册导致的警告
 }
 
+func registerVectorDuplicateTask() {
+	duplicateJob := NewVectorDuplicateJob()
+
+	err := addTask("vector_duplicate_scan", "近似重复扫描", duplicateJob.GetSchedule(), func() (int64, error) {
+		err := duplicateJob.Execute()
+		if err != nil {
+			logger.Error("近似重复扫描任务执行失败: %v", err)
+		}
+		return 0, err
+	})
+	if err != nil {
+		logger.Error("注册近似重复扫描任务失败: %v", err)
+	}
+}
+
 func registerChunkedUploadCleanupTask() {
 	cleanupJob := NewChunkedUploadCleanupJob()
 
-	_, err := cronManager.AddFunc(cleanupJob.GetSchedule(), func() {
-		if err := cleanupJob.Execute(); err != nil {
+	err := addCleanupTask("chunked_upload_cleanup", "分片上传清理", cleanupJob.GetSchedule(), func() (int64, error) {
+		result, err := cleanupJob.Execute()
+		if err != nil {
 			logger.Error("分片上传清理任务执行失败: %v", err)
 		}
+		if result == nil {
+			return 0, err
+		}
+		return int64(result.ExpiredSessions + result.OrphanDirs), err
 	})
 	if err != nil {
 		logger.Error("注册分片上传清理任务失败: %v", err)
@@ -161,10 +246,12 @@ func registerChunkedUploadCleanupTask() {
 func registerVectorVerificationTask() {
 	verificationJob := NewVectorVerificationJob()
 
-	_, err := cronManager.AddFunc(verificationJob.GetSchedule(), func() {
-		if err := verificationJob.Execute(); err != nil {
+	err := addTask("vector_verification", "向量验证", verificationJob.GetSchedule(), func() (int64, error) {
+		err := verificationJob.Execute()
+		if err != nil {
 			logger.Error("向量验证任务执行失败: %v", err)
 		}
+		return 0, err
 	})
 	if err != nil {
 		logger.Error("注册向量验证任务失败: %v", err)
@@ -174,10 +261,12 @@ func registerVectorVerificationTask() {
 func registerImageCleanupTask() {
 	cleanupJob := NewImageCleanupJob()
 
-	_, err := cronManager.AddFunc(cleanupJob.GetSchedule(), func() {
-		if err := cleanupJob.Execute(); err != nil {
+	err := addCleanupTask("file_cleanup", "文件清理", cleanupJob.GetSchedule(), func() (int64, error) {
+		err := cleanupJob.Execute()
+		if err != nil {
 			logger.Error("文件清理任务执行失败: %v", err)
 		}
+		return 0, err
 	})
 	if err != nil {
 		logger.Error("注册文件清理任务失败: %v", err)
@@ -188,23 +277,17 @@ func registerTagUsageCountCalibrationTask() {
 	tagService := tag.NewFileGlobalTagService()
 
 	// 每天凌晨2点执行标签使用次数校准任务
-	_, err := cronManager.AddFunc("0 0 2 * * *", func() {
-		if err := tagService.CalibrateAllTagUsageCount(); err != nil {
-		} else {
-		}
+	err := addTask("tag_usage_calibration", "标签使用次数校准", "0 0 2 * * *", func() (int64, error) {
+		return 0, tagService.CalibrateAllTagUsageCount()
 	})
 	if err != nil {
 		logger.Error("注册标签使用次数校准任务失败: %v", err)
 	}
 }
 
-/* Stop 停止所有定时任务 */
+/* Stop 停止所有定时任务，打标服务由 ai.Shutdown 在关闭时限内停止 */
 func Stop() {
 	if cronManager != nil {
 		cronManager.Stop()
 	}
-
-	if taggingService != nil {
-		taggingService.Stop()
-	}
 }
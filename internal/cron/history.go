@@ -0,0 +1,100 @@
+package cron
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+)
+
+// HistoryQuery 执行历史查询条件
+type HistoryQuery struct {
+	TaskName string
+	Status   string
+	Offset   int
+	Limit    int
+}
+
+// startRun 写入执行记录，写入失败只记录日志，不影响任务执行
+func startRun(name, trigger string) *models.CronRun {
+	rec := &models.CronRun{
+		TaskName:  name,
+		Trigger:   trigger,
+		Status:    models.CronRunRunning,
+		Instance:  cache.InstanceID(),
+		StartedAt: time.Now(),
+	}
+	if db == nil {
+		return rec
+	}
+	if err := db.Create(rec).Error; err != nil {
+		logger.Warn("写入定时任务 %s 执行记录失败: %v", name, err)
+	}
+	return rec
+}
+
+// finishRun 补充执行结果
+func finishRun(rec *models.CronRun, items int64, runErr error) {
+	finished := time.Now()
+	rec.FinishedAt = &finished
+	rec.DurationMs = finished.Sub(rec.StartedAt).Milliseconds()
+	rec.Items = items
+	rec.Status = models.CronRunSuccess
+	if runErr != nil {
+		rec.Status = models.CronRunFailed
+		rec.Error = runErr.Error()
+	}
+
+	if db == nil || rec.ID == 0 {
+		return
+	}
+	if err := db.Model(rec).Updates(map[string]interface{}{
+		"status":      rec.Status,
+		"finished_at": rec.FinishedAt,
+		"duration_ms": rec.DurationMs,
+		"items":       rec.Items,
+		"error":       rec.Error,
+	}).Error; err != nil {
+		logger.Warn("更新定时任务 %s 执行记录失败: %v", rec.TaskName, err)
+	}
+}
+
+/* ListHistory 按任务与状态分页查询执行历史，按开始时间倒序 */
+func ListHistory(q HistoryQuery) ([]models.CronRun, int64, error) {
+	if db == nil {
+		return nil, 0, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	query := db.Model(&models.CronRun{})
+	if q.TaskName != "" {
+		query = query.Where("task_name = ?", q.TaskName)
+	}
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询执行记录总数失败")
+	}
+
+	runs := make([]models.CronRun, 0, q.Limit)
+	if err := query.Order("started_at DESC, id DESC").Offset(q.Offset).Limit(q.Limit).Find(&runs).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询执行记录失败")
+	}
+	return runs, total, nil
+}
+
+// cleanHistory 删除超过保留天数的执行记录，保留天数为 0 时不清理
+func cleanHistory(retentionDays int) (int64, error) {
+	if retentionDays <= 0 || db == nil {
+		return 0, nil
+	}
+	result := db.Where("started_at < ?", time.Now().AddDate(0, 0, -retentionDays)).Delete(&models.CronRun{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "清理定时任务执行记录失败")
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/logger"
+)
+
+func registerHistoryTask() {
+	// 清理过期的定时任务执行记录 - 每天凌晨4点25分执行，保留天数由 history_retention_days 控制
+	err := addCleanupTask("cron_history_cleanup", "定时任务执行记录清理", "0 25 4 * * *", func() (int64, error) {
+		deleted, err := cleanHistory(setting.GetInt(settingGroup, "history_retention_days", 7))
+		if err != nil {
+			logger.Error("清理定时任务执行记录失败: %v", err)
+		} else if deleted > 0 {
+			logger.Info("已清理 %d 条定时任务执行记录", deleted)
+		}
+		return deleted, err
+	})
+	if err != nil {
+		logger.Error("注册定时任务执行记录清理任务失败: %v", err)
+	}
+}
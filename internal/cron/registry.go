@@ -0,0 +1,328 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	setdto "pixelpunk/internal/controllers/setting/dto"
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/hooks"
+	"pixelpunk/pkg/logger"
+
+	"github.com/robfig/cron/v3"
+)
+
+// settingGroup 定时任务设置分组，键名为 <任务名>_schedule 与 <任务名>_enabled
+const settingGroup = "cron"
+
+// cronParser 与 cron.WithSeconds 一致的六段式表达式（秒 分 时 日 月 周），同时支持 @every 1h 等描述符
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// task 已注册的定时任务
+type task struct {
+	name        string
+	title       string
+	defaultSpec string
+	fn          func() (int64, error)
+	queued      bool // 到点后加入 cleanup 任务队列执行，失败按重试策略重试，重试耗尽进入死信
+
+	mu      sync.Mutex
+	spec    string
+	enabled bool
+	entryID cron.EntryID
+
+	running int32
+}
+
+// TaskInfo 定时任务状态
+type TaskInfo struct {
+	Name            string     `json:"name"`
+	Title           string     `json:"title"`
+	Schedule        string     `json:"schedule"`
+	DefaultSchedule string     `json:"default_schedule"`
+	Enabled         bool       `json:"enabled"`
+	Running         bool       `json:"running"` // 本实例是否正在执行
+	NextRun         *time.Time `json:"next_run,omitempty"`
+}
+
+var (
+	tasksMu    sync.RWMutex
+	tasks      []*task
+	taskByName = make(map[string]*task)
+
+	settingHookRegistered bool
+)
+
+// resetTasks 重新初始化定时任务管理器前清空已注册的任务
+func resetTasks() {
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+	tasks = nil
+	taskByName = make(map[string]*task)
+
+	if !settingHookRegistered {
+		settingHookRegistered = true
+		hooks.RegisterSettingUpdateHook(settingGroup, func(string) error {
+			reloadTasks()
+			return nil
+		})
+	}
+}
+
+/* addTask 注册定时任务，fn 返回本次处理的数量，执行周期与启停可在设置分组 cron 中覆盖；启用 Redis 时通过分布式锁保证多实例部署下每轮调度只由一个实例执行 */
+func addTask(name, title, spec string, fn func() (int64, error)) error {
+	return registerTask(&task{name: name, title: title, defaultSpec: spec, fn: fn})
+}
+
+/* addCleanupTask 注册清理类定时任务：到点后加入 cleanup 任务队列，由任务框架执行，失败时按重试策略重试，重试耗尽后进入死信，可在后台任务管理中查看与重新入队 */
+func addCleanupTask(name, title, spec string, fn func() (int64, error)) error {
+	return registerTask(&task{name: name, title: title, defaultSpec: spec, fn: fn, queued: true})
+}
+
+func registerTask(t *task) error {
+	name, spec := t.name, t.defaultSpec
+	if _, err := cronParser.Parse(spec); err != nil {
+		return err
+	}
+
+	t.spec, t.enabled = taskSettings(name, spec)
+
+	tasksMu.Lock()
+	tasks = append(tasks, t)
+	taskByName[name] = t
+	tasksMu.Unlock()
+
+	return t.schedule()
+}
+
+// taskSettings 读取任务的执行周期与启停设置，表达式无效时使用默认周期
+func taskSettings(name, defaultSpec string) (string, bool) {
+	spec := strings.TrimSpace(setting.GetString(settingGroup, name+"_schedule", ""))
+	if spec == "" {
+		spec = defaultSpec
+	} else if _, err := cronParser.Parse(spec); err != nil {
+		logger.Warn("定时任务 %s 的执行周期 %q 无效，使用默认周期 %s: %v", name, spec, defaultSpec, err)
+		spec = defaultSpec
+	}
+	return spec, setting.GetBool(settingGroup, name+"_enabled", true)
+}
+
+// schedule 按当前设置重新加入调度，停用的任务只移除
+func (t *task) schedule() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.entryID != 0 {
+		cronManager.Remove(t.entryID)
+		t.entryID = 0
+	}
+	if !t.enabled {
+		return nil
+	}
+	id, err := cronManager.AddFunc(t.spec, t.runScheduled)
+	if err != nil {
+		return err
+	}
+	t.entryID = id
+	return nil
+}
+
+// acquire 获取执行权：本实例未在执行，且启用 Redis 时抢到分布式锁
+func (t *task) acquire() (*cache.Lock, bool) {
+	if !atomic.CompareAndSwapInt32(&t.running, 0, 1) {
+		return nil, false
+	}
+	lock, ok := cache.TryLock("cron:"+t.name, cronLockTTL)
+	if !ok {
+		atomic.StoreInt32(&t.running, 0)
+		return nil, false
+	}
+	return lock, true
+}
+
+func (t *task) runScheduled() {
+	if t.queued {
+		t.enqueue()
+		return
+	}
+	if lock, ok := t.acquire(); ok {
+		t.run(lock, cronLockHold, models.CronTriggerSchedule)
+	}
+}
+
+// enqueue 将本轮调度加入 cleanup 任务队列，任务ID带上调度时间，多实例部署时通过分布式锁只由一个实例入队
+func (t *task) enqueue() {
+	lock, ok := cache.TryLock("cron:enqueue:"+t.name, cronLockTTL)
+	if !ok {
+		return
+	}
+	defer lock.UnlockAfter(cronLockHold)
+
+	id := t.name + cleanupJobSep + strconv.FormatInt(time.Now().Unix(), 10)
+	if err := qqueue.Enqueue(qqueue.KindCleanup, id, qqueue.PriorityNormal); err != nil {
+		logger.Warn("定时任务 %s 加入任务队列失败: %v", t.name, err)
+	}
+}
+
+// run 执行任务并记录执行历史，完成后保留锁 hold 时长再释放
+func (t *task) run(lock *cache.Lock, hold time.Duration, trigger string) (err error) {
+	rec := startRun(t.name, trigger)
+	var items int64
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("定时任务 %s 执行异常: %v", t.name, r)
+			err = fmt.Errorf("panic: %v", r)
+		}
+		finishRun(rec, items, err)
+		lock.UnlockAfter(hold)
+		atomic.StoreInt32(&t.running, 0)
+	}()
+	items, err = t.fn()
+	return err
+}
+
+func (t *task) info() TaskInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info := TaskInfo{
+		Name:            t.name,
+		Title:           t.title,
+		Schedule:        t.spec,
+		DefaultSchedule: t.defaultSpec,
+		Enabled:         t.enabled,
+		Running:         atomic.LoadInt32(&t.running) == 1,
+	}
+	if t.entryID != 0 {
+		if next := cronManager.Entry(t.entryID).Next; !next.IsZero() {
+			info.NextRun = &next
+		}
+	}
+	return info
+}
+
+// reloadTasks 设置变更后按新的周期与启停状态重新调度，本实例与其他实例的设置更新都会触发
+func reloadTasks() {
+	if cronManager == nil {
+		return
+	}
+
+	tasksMu.RLock()
+	list := append([]*task(nil), tasks...)
+	tasksMu.RUnlock()
+
+	for _, t := range list {
+		spec, enabled := taskSettings(t.name, t.defaultSpec)
+
+		t.mu.Lock()
+		changed := spec != t.spec || enabled != t.enabled
+		t.spec, t.enabled = spec, enabled
+		t.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+		if err := t.schedule(); err != nil {
+			logger.Error("重新调度定时任务 %s 失败: %v", t.name, err)
+		}
+	}
+}
+
+func lookupTask(name string) (*task, error) {
+	tasksMu.RLock()
+	defer tasksMu.RUnlock()
+	t, ok := taskByName[name]
+	if !ok {
+		return nil, errors.New(errors.CodeNotFound, "定时任务不存在")
+	}
+	return t, nil
+}
+
+/* ListTasks 列出所有定时任务及其执行周期、启停状态与下次执行时间 */
+func ListTasks() []TaskInfo {
+	tasksMu.RLock()
+	list := append([]*task(nil), tasks...)
+	tasksMu.RUnlock()
+
+	infos := make([]TaskInfo, 0, len(list))
+	for _, t := range list {
+		infos = append(infos, t.info())
+	}
+	return infos
+}
+
+/* UpdateTask 修改任务的执行周期或启停状态，schedule 为空字符串时恢复默认周期，保存到设置后各实例重新调度 */
+func UpdateTask(name string, schedule *string, enabled *bool) (*TaskInfo, error) {
+	t, err := lookupTask(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var upserts []setdto.SettingCreateDTO
+	if schedule != nil {
+		spec := strings.TrimSpace(*schedule)
+		if spec != "" {
+			if _, err := cronParser.Parse(spec); err != nil {
+				return nil, errors.New(errors.CodeInvalidParameter, "执行周期格式错误: "+err.Error())
+			}
+		}
+		upserts = append(upserts, setdto.SettingCreateDTO{
+			Key:         name + "_schedule",
+			Value:       spec,
+			Type:        models.SettingTypeString,
+			Group:       settingGroup,
+			Description: t.title + "执行周期（六段式 cron 表达式），为空时使用默认周期 " + t.defaultSpec,
+		})
+	}
+	if enabled != nil {
+		upserts = append(upserts, setdto.SettingCreateDTO{
+			Key:         name + "_enabled",
+			Value:       *enabled,
+			Type:        models.SettingTypeBoolean,
+			Group:       settingGroup,
+			Description: "是否启用" + t.title + "定时任务",
+		})
+	}
+	if len(upserts) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "请指定执行周期或启停状态")
+	}
+
+	result, err := setting.BatchUpsertSettings(&setdto.BatchUpsertSettingDTO{Settings: upserts})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Failed) > 0 {
+		return nil, errors.New(errors.CodeDBUpdateFailed, result.Failed[0].Message)
+	}
+
+	// 保存设置会触发 cron 分组的更新钩子，这里再同步一次，保证返回的是最新状态
+	reloadTasks()
+	info := t.info()
+	return &info, nil
+}
+
+/* RunTask 立即在本实例异步执行一次任务，任务正在执行时返回冲突错误 */
+func RunTask(name string) error {
+	t, err := lookupTask(name)
+	if err != nil {
+		return err
+	}
+	if cronManager == nil {
+		return errors.New(errors.CodeServiceUnavailable, "定时任务管理器未初始化")
+	}
+
+	lock, ok := t.acquire()
+	if !ok {
+		return errors.New(errors.CodeConflict, "任务正在执行，请稍后再试")
+	}
+	go t.run(lock, 0, models.CronTriggerManual)
+	return nil
+}
@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/review"
+	"pixelpunk/pkg/logger"
+)
+
+func registerReviewTask() {
+	// 自动通过超时无人处理的低风险待审核文件 - 每10分钟执行一次，是否启用由 review_auto_approve_enabled 控制
+	err := addTask("review_auto_approve", "自动审核", "0 */10 * * * *", func() (int64, error) {
+		approvedCount, err := review.AutoApproveLowRiskFiles()
+		if err != nil {
+			logger.Error("自动通过待审核文件失败: %v", err)
+		} else if approvedCount > 0 {
+			logger.Info("自动审核: 通过了 %d 个低风险待审核文件", approvedCount)
+		}
+		return int64(approvedCount), err
+	})
+	if err != nil {
+		logger.Error("注册自动审核任务失败: %v", err)
+	}
+
+	// 阈值调严后重新审核临界区间内的已通过文件 - 每小时执行一次，是否启用由 review_rereview_enabled 控制
+	err = addTask("review_rereview", "重新审核临界文件", "0 20 * * * *", func() (int64, error) {
+		requeuedCount, err := review.ReReviewBorderlineFiles()
+		if err != nil {
+			logger.Error("重新审核临界文件失败: %v", err)
+		} else if requeuedCount > 0 {
+			logger.Info("重新审核: %d 个临界文件重新进入审核队列", requeuedCount)
+		}
+		return int64(requeuedCount), err
+	})
+	if err != nil {
+		logger.Error("注册重新审核任务失败: %v", err)
+	}
+}
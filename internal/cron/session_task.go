@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/auth"
+	"pixelpunk/pkg/logger"
+)
+
+func registerSessionCleanupTask() {
+	// 清理已过期的登录会话 - 每天凌晨3点执行
+	err := addCleanupTask("session_cleanup", "登录会话清理", "0 0 3 * * *", func() (int64, error) {
+		cleaned, err := auth.CleanExpiredSessions()
+		if err != nil {
+			logger.Error("清理过期登录会话失败: %v", err)
+		} else if cleaned > 0 {
+			logger.Info("已清理 %d 条过期登录会话", cleaned)
+		}
+		return cleaned, err
+	})
+	if err != nil {
+		logger.Error("注册登录会话清理任务失败: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cron
+
+import (
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	trashPurgeBatch      = 500
+	trashPurgeMaxBatches = 20 // 单次最多清理的批数，积压更多时留给下一轮
+)
+
+func registerTrashTask() {
+	// 彻底删除超过保留期限的回收站文件 - 每小时执行，保留天数由 trash_retention_days 控制
+	err := addCleanupTask("trash_purge", "回收站清理", "0 40 * * * *", func() (int64, error) {
+		var total int64
+		for i := 0; i < trashPurgeMaxBatches; i++ {
+			purged, err := filesvc.PurgeExpiredTrashFiles(trashPurgeBatch)
+			total += int64(purged)
+			if err != nil {
+				logger.Error("清理过期回收站文件失败: %v", err)
+				return total, err
+			}
+			// 不满一批说明已清理完，或有文件删除失败，失败的文件留给下一轮重试
+			if purged < trashPurgeBatch {
+				break
+			}
+		}
+		if total > 0 {
+			logger.Info("已彻底删除 %d 个超过保留期限的回收站文件", total)
+		}
+		return total, nil
+	})
+	if err != nil {
+		logger.Error("注册回收站清理任务失败: %v", err)
+	}
+}
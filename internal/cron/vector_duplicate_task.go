@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/internal/services/vector"
+	"pixelpunk/pkg/logger"
+)
+
+/* VectorDuplicateJob 基于向量的近似重复文件扫描定时任务 */
+type VectorDuplicateJob struct {
+	schedule string
+}
+
+/* NewVectorDuplicateJob 创建近似重复扫描任务实例 */
+func NewVectorDuplicateJob() *VectorDuplicateJob {
+	return &VectorDuplicateJob{
+		schedule: "0 0 4 * * *", // 每天凌晨4点执行，避开向量验证和孤儿清理
+	}
+}
+
+/* GetSchedule 获取调度配置 */
+func (j *VectorDuplicateJob) GetSchedule() string {
+	return j.schedule
+}
+
+/* Execute 执行近似重复扫描（增量，仅扫描上次扫描后新增或更新的向量） */
+func (j *VectorDuplicateJob) Execute() error {
+	if !setting.GetBoolDirectFromDB("vector", "duplicate_scan_enabled", true) {
+		return nil
+	}
+	if svc := vector.GetGlobalVectorQueueService(); svc != nil && svc.IsPaused() {
+		logger.Info("向量队列已暂停，跳过近似重复扫描")
+		return nil
+	}
+
+	_, err := vector.ScanVectorDuplicates(false)
+	return err
+}
+
+/* GetName 获取任务名称 */
+func (j *VectorDuplicateJob) GetName() string {
+	return "Vector Duplicate Scan Task"
+}
@@ -0,0 +1,30 @@
+package cron
+
+import (
+	webhookService "pixelpunk/internal/services/webhook"
+	"pixelpunk/pkg/logger"
+)
+
+func registerWebhookTask() {
+	// 补发待投递与到期重试的 Webhook - 每15秒执行
+	err := addTask("webhook_retry", "Webhook重试", "*/15 * * * * *", func() (int64, error) {
+		return int64(webhookService.ProcessDueDeliveries(200)), nil
+	})
+	if err != nil {
+		logger.Error("注册Webhook重试任务失败: %v", err)
+	}
+
+	// 清理过期的投递记录 - 每天凌晨4点15分执行，保留天数由 webhook_delivery_retention_days 控制
+	err = addCleanupTask("webhook_delivery_cleanup", "Webhook投递记录清理", "0 15 4 * * *", func() (int64, error) {
+		deleted, err := webhookService.CleanupDeliveries()
+		if err != nil {
+			logger.Error("清理Webhook投递记录失败: %v", err)
+		} else if deleted > 0 {
+			logger.Info("已清理 %d 条过期的Webhook投递记录", deleted)
+		}
+		return deleted, err
+	})
+	if err != nil {
+		logger.Error("注册Webhook投递记录清理任务失败: %v", err)
+	}
+}
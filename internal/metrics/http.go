@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// httpWindowMinutes HTTP错误率统计保留的分钟桶数量
+const httpWindowMinutes = 60
+
+type httpBucket struct {
+	minute       int64
+	total        uint64
+	clientErrors uint64
+	serverErrors uint64
+}
+
+var (
+	httpRequestsTotal     uint64
+	httpServerErrorsTotal uint64
+
+	httpWindowMu sync.Mutex
+	httpWindow   [httpWindowMinutes]httpBucket
+)
+
+// HTTPStats 滑动窗口内的请求量与错误数
+type HTTPStats struct {
+	Requests     uint64  `json:"requests"`
+	ClientErrors uint64  `json:"client_errors"`
+	ServerErrors uint64  `json:"server_errors"`
+	ErrorRate    float64 `json:"error_rate"` // 5xx 占比（百分比）
+}
+
+// RecordHTTPResponse 按分钟桶记录一次请求的响应状态
+func RecordHTTPResponse(status int) {
+	atomic.AddUint64(&httpRequestsTotal, 1)
+	if status >= 500 {
+		atomic.AddUint64(&httpServerErrorsTotal, 1)
+	}
+
+	minute := time.Now().Unix() / 60
+	httpWindowMu.Lock()
+	bucket := &httpWindow[minute%httpWindowMinutes]
+	if bucket.minute != minute {
+		*bucket = httpBucket{minute: minute}
+	}
+	bucket.total++
+	switch {
+	case status >= 500:
+		bucket.serverErrors++
+	case status >= 400:
+		bucket.clientErrors++
+	}
+	httpWindowMu.Unlock()
+}
+
+// GetHTTPStats 汇总最近 minutes 分钟（最多60）的请求统计
+func GetHTTPStats(minutes int) HTTPStats {
+	if minutes <= 0 || minutes > httpWindowMinutes {
+		minutes = httpWindowMinutes
+	}
+	current := time.Now().Unix() / 60
+
+	var stats HTTPStats
+	httpWindowMu.Lock()
+	for _, bucket := range httpWindow {
+		if bucket.minute > current-int64(minutes) && bucket.minute <= current {
+			stats.Requests += bucket.total
+			stats.ClientErrors += bucket.clientErrors
+			stats.ServerErrors += bucket.serverErrors
+		}
+	}
+	httpWindowMu.Unlock()
+
+	if stats.Requests > 0 {
+		stats.ErrorRate = float64(stats.ServerErrors) / float64(stats.Requests) * 100
+	}
+	return stats
+}
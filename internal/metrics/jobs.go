@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// 后台任务处理结果计数，按任务类型与结果（ack/retry/dead）区分
+var (
+	jobResultsMu sync.Mutex
+	jobResults   = make(map[string]map[string]uint64)
+
+	jobQueueProvider func() map[string]map[string]int
+)
+
+// IncJobResult 记录一次后台任务处理结果
+func IncJobResult(kind, result string) {
+	jobResultsMu.Lock()
+	defer jobResultsMu.Unlock()
+	counts, ok := jobResults[kind]
+	if !ok {
+		counts = make(map[string]uint64)
+		jobResults[kind] = counts
+	}
+	counts[result]++
+}
+
+// JobResultCounts 获取指定任务类型的处理结果计数
+func JobResultCounts(kind string) map[string]uint64 {
+	jobResultsMu.Lock()
+	defer jobResultsMu.Unlock()
+	counts := make(map[string]uint64, len(jobResults[kind]))
+	for result, n := range jobResults[kind] {
+		counts[result] = n
+	}
+	return counts
+}
+
+// SetJobQueueProvider 注册队列长度回调，返回 任务类型 -> 状态 -> 数量
+func SetJobQueueProvider(fn func() map[string]map[string]int) { jobQueueProvider = fn }
+
+func writeJobMetrics(w io.Writer) {
+	jobResultsMu.Lock()
+	kinds := make([]string, 0, len(jobResults))
+	for kind := range jobResults {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Fprintf(w, "# HELP job_results_total Background job results by kind and result.\n")
+	fmt.Fprintf(w, "# TYPE job_results_total counter\n")
+	for _, kind := range kinds {
+		results := make([]string, 0, len(jobResults[kind]))
+		for result := range jobResults[kind] {
+			results = append(results, result)
+		}
+		sort.Strings(results)
+		for _, result := range results {
+			fmt.Fprintf(w, "job_results_total{kind=%q,result=%q} %d\n", kind, result, jobResults[kind][result])
+		}
+	}
+	jobResultsMu.Unlock()
+
+	if jobQueueProvider == nil {
+		return
+	}
+	gauges := jobQueueProvider()
+	kinds = kinds[:0]
+	for kind := range gauges {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Fprintf(w, "# HELP job_queue_items Background job queue items by kind and state.\n")
+	fmt.Fprintf(w, "# TYPE job_queue_items gauge\n")
+	for _, kind := range kinds {
+		states := make([]string, 0, len(gauges[kind]))
+		for state := range gauges[kind] {
+			states = append(states, state)
+		}
+		sort.Strings(states)
+		for _, state := range states {
+			fmt.Fprintf(w, "job_queue_items{kind=%q,state=%q} %d\n", kind, state, gauges[kind][state])
+		}
+	}
+}
@@ -142,6 +142,17 @@ func WritePrometheus(w io.Writer) {
 		}
 	}
 
+	writeJobMetrics(w)
+	writeUploadMetrics(w)
+
+	fmt.Fprintf(w, "# HELP http_requests_total Total number of HTTP requests served.\n")
+	fmt.Fprintf(w, "# TYPE http_requests_total counter\n")
+	fmt.Fprintf(w, "http_requests_total %d\n", atomic.LoadUint64(&httpRequestsTotal))
+
+	fmt.Fprintf(w, "# HELP http_server_errors_total Total number of HTTP responses with 5xx status.\n")
+	fmt.Fprintf(w, "# TYPE http_server_errors_total counter\n")
+	fmt.Fprintf(w, "http_server_errors_total %d\n", atomic.LoadUint64(&httpServerErrorsTotal))
+
 	// A lightweight timestamp
 	fmt.Fprintf(w, "# HELP metrics_timestamp_seconds Unix timestamp of this metrics snapshot.\n")
 	fmt.Fprintf(w, "# TYPE metrics_timestamp_seconds gauge\n")
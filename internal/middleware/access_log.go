@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"time"
+
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accessLogSkipPaths 探针等高频请求不记录访问日志
+var accessLogSkipPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+/* AccessLog 请求结束后输出结构化访问日志：请求ID、状态码、耗时、用户与收发字节数 */
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !logger.AccessLogEnabled() || accessLogSkipPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		userID := GetCurrentUserID(c)
+		if userID == 0 {
+			// API Key、WebDAV 等鉴权方式只设置了 user_id
+			if id, ok := c.Get("user_id"); ok {
+				userID, _ = id.(uint)
+			}
+		}
+
+		bytesIn := c.Request.ContentLength
+		if bytesIn < 0 {
+			bytesIn = 0
+		}
+		bytesOut := c.Writer.Size()
+		if bytesOut < 0 {
+			bytesOut = 0
+		}
+
+		entry := &logger.AccessEntry{
+			Time:      start,
+			RequestID: c.GetString("RequestID"),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Route:     c.FullPath(),
+			Status:    c.Writer.Status(),
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			BytesIn:   bytesIn,
+			BytesOut:  bytesOut,
+			ClientIP:  c.ClientIP(),
+			UserID:    userID,
+			UserAgent: c.Request.UserAgent(),
+		}
+		if len(c.Errors) > 0 {
+			entry.Error = c.Errors.String()
+		}
+		logger.Access(entry)
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"pixelpunk/internal/services/audit"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	adminRouteKey   = "audit_admin_route"
+	auditChangeKey  = "audit_change"
+	maxAuditBodyLen = 64 * 1024
+)
+
+// auditChange 由处理函数提供的操作详情
+type auditChange struct {
+	action     string
+	targetType string
+	targetID   string
+	before     interface{}
+	after      interface{}
+}
+
+/* SetAuditChange 处理函数记录本次管理操作的对象及修改前后快照，由 AdminAudit 统一落库 */
+func SetAuditChange(c *gin.Context, action, targetType, targetID string, before, after interface{}) {
+	c.Set(auditChangeKey, &auditChange{
+		action:     action,
+		targetType: targetType,
+		targetID:   targetID,
+		before:     before,
+		after:      after,
+	})
+}
+
+// markAdminRoute 权限中间件通过后标记为管理路由，写操作会被审计
+func markAdminRoute(c *gin.Context) {
+	c.Set(adminRouteKey, true)
+}
+
+/* AdminAudit 记录所有经过管理员/审核员权限校验的写操作 */
+func AdminAudit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if strings.HasPrefix(c.ContentType(), "application/json") && c.Request.Body != nil &&
+			c.Request.ContentLength >= 0 && c.Request.ContentLength <= maxAuditBodyLen {
+			body, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodyLen+1))
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			if len(body) > maxAuditBodyLen {
+				body = nil
+			}
+		}
+
+		c.Next()
+
+		if !c.GetBool(adminRouteKey) {
+			return
+		}
+		claims := GetCurrentUser(c)
+		if claims == nil {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		entry := audit.Entry{
+			ActorID:    claims.UserID,
+			ActorName:  claims.Username,
+			ActorRole:  claims.Role,
+			Action:     method + " " + strings.TrimPrefix(route, "/api/v1"),
+			TargetID:   c.Param("id"),
+			Method:     method,
+			Path:       c.Request.URL.Path,
+			StatusCode: c.Writer.Status(),
+			IP:         utils.GetClientIP(c),
+			UserAgent:  c.Request.UserAgent(),
+		}
+		if len(body) > 0 {
+			entry.Request = body
+		}
+		if v, ok := c.Get(auditChangeKey); ok {
+			if change, ok := v.(*auditChange); ok {
+				entry.Action = change.action
+				entry.TargetType = change.targetType
+				if change.targetID != "" {
+					entry.TargetID = change.targetID
+				}
+				entry.Before = change.before
+				entry.After = change.after
+			}
+		}
+
+		go audit.Record(entry)
+	}
+}
@@ -1,16 +1,22 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/apikey"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func APIKeyAuthMiddleware() gin.HandlerFunc {
+/* APIKeyAuthMiddleware API密钥认证，校验密钥是否拥有 scope 授权范围及目录限制 */
+func APIKeyAuthMiddleware(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Method == http.MethodOptions {
 			c.Next()
@@ -56,13 +62,52 @@ func APIKeyAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if c.Request.Method == "POST" {
+		clientIP := utils.GetClientIP(c)
+		if !apikey.IsIPAllowed(key, clientIP) {
+			apikey.RecordAPIKeyIPBlocked(key.ID)
+			errors.HandleError(c, errors.New(errors.CodeForbidden, fmt.Sprintf("当前IP(%s)不在该API密钥的IP白名单中", clientIP)))
+			c.Abort()
+			return
+		}
+
+		rate, ok := apikey.TakeRateLimit(key)
+		if rate.Limit > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(rate.Remaining))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(rate.ResetAt.Unix(), 10))
+		}
+		if !ok {
+			retryAfter := int(time.Until(rate.ResetAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			apikey.RecordAPIKeyRateLimited(key.ID)
+			errors.HandleError(c, errors.New(errors.CodeRateLimited, fmt.Sprintf("API密钥请求频率超出限制（每分钟%d次），请%d秒后重试", rate.Limit, retryAfter)))
+			c.Abort()
+			return
+		}
+		apikey.RecordAPIKeyRequest(key.ID, clientIP)
+
+		if err := apikey.CheckScope(key, scope); err != nil {
+			errors.HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		if scope == models.APIKeyScopeUpload {
 			if !key.CheckUploadCountLimit() {
 				errors.HandleError(c, errors.New(errors.CodeForbidden, "已达到上传次数限制"))
 				c.Abort()
 				return
 			}
+		}
 
+		if key.IsFolderRestricted() {
+			if folderID := requestFolderID(c); folderID != "" {
+				if err := apikey.CheckFolderAccess(key, folderID); err != nil {
+					errors.HandleError(c, err)
+					c.Abort()
+					return
+				}
+			}
 		}
 
 		c.Set("api_key", key)
@@ -72,3 +117,39 @@ func APIKeyAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// requestFolderID 读取请求中指定的目录参数，兼容查询参数和表单字段
+func requestFolderID(c *gin.Context) string {
+	for _, name := range []string{"folder_id", "folderId"} {
+		if v := c.Query(name); v != "" && v != "null" {
+			return v
+		}
+		if v := c.PostForm(name); v != "" && v != "null" {
+			return v
+		}
+	}
+	return ""
+}
+
+/* GetCurrentAPIKey 获取当前请求认证通过的API密钥 */
+func GetCurrentAPIKey(c *gin.Context) *models.APIKey {
+	if v, exists := c.Get("api_key"); exists {
+		if key, ok := v.(*models.APIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
+
+/* JWTOrAPIKeyAuth 请求头携带API密钥时按密钥认证，否则解析登录令牌，需配合 RequireAuth 使用 */
+func JWTOrAPIKeyAuth(scope string) gin.HandlerFunc {
+	apiKeyAuth := APIKeyAuthMiddleware(scope)
+	jwtAuth := JWTAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("x-pixelpunk-key") != "" || c.GetHeader("x-api-key") != "" {
+			apiKeyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	stderrors "errors"
 	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/auth"
 	"pixelpunk/internal/services/setting"
@@ -9,6 +10,7 @@ import (
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -82,6 +84,15 @@ func GetCurrentUsername(c *gin.Context) string {
 	return claims.Username
 }
 
+/* GetCurrentSessionID 当前请求的登录会话ID（JWT jti），旧版token没有会话ID时返回空 */
+func GetCurrentSessionID(c *gin.Context) string {
+	claims := GetCurrentUser(c)
+	if claims == nil {
+		return ""
+	}
+	return claims.ID
+}
+
 func GetCurrentUserRole(c *gin.Context) int {
 	claims := GetCurrentUser(c)
 	if claims == nil {
@@ -159,7 +170,11 @@ func JWTAuth() gin.HandlerFunc {
 
 		claims, err := auth.ParseToken(tokenString, jwtSecret)
 		if err != nil {
-			c.Set(AuthErrorKey, "无效的认证令牌")
+			if stderrors.Is(err, auth.ErrSessionRevoked) {
+				c.Set(AuthErrorKey, "登录会话已失效，请重新登录")
+			} else {
+				c.Set(AuthErrorKey, "无效的认证令牌")
+			}
 			c.Next()
 			return
 		}
@@ -171,6 +186,7 @@ func JWTAuth() gin.HandlerFunc {
 		}
 
 		c.Set(ContextPayloadKey, claims)
+		auth.TouchSession(claims.ID, utils.GetClientIP(c))
 
 		// 检查用户是否被禁用（在JWT解析后立即检查，覆盖所有需要认证的接口）
 		if !checkUserActive(claims) {
@@ -215,6 +231,33 @@ func RequireAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		markAdminRoute(c)
+		c.Next()
+	}
+}
+
+// RequireReviewer 审核权限校验，管理员和审核员均可通过
+func RequireReviewer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authError, exists := c.Get(AuthErrorKey); exists {
+			errors.HandleError(c, errors.New(errors.CodeUnauthorized, authError.(string)))
+			c.Abort()
+			return
+		}
+
+		claims := GetCurrentUser(c)
+		if claims == nil {
+			errors.HandleError(c, errors.New(errors.CodeUnauthorized, "用户认证信息无效"))
+			c.Abort()
+			return
+		}
+
+		if claims.Role != common.UserRoleAdmin && claims.Role != common.UserRoleSuperAdmin && claims.Role != common.UserRoleReviewer {
+			errors.HandleError(c, errors.New(errors.CodeForbidden, "需要审核权限"))
+			c.Abort()
+			return
+		}
+		markAdminRoute(c)
 		c.Next()
 	}
 }
@@ -239,6 +282,7 @@ func RequireSuperAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		markAdminRoute(c)
 		c.Next()
 	}
 }
@@ -1,57 +1,157 @@
 package middleware
 
 import (
+	"net/http"
 	"net/url"
+	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/utils"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-/* CORSMiddleware 跨域(CORS)中间件：
- * - 默认允许任意 Origin，但不允许携带凭证（避免反射 Origin + credentials 的安全风险）
- * - 当 Origin 与当前 Host / 配置的 baseUrl 匹配时，才允许携带凭证
+const corsSettingGroup = "cors"
+
+// CORS 路由分组：公开文件访问与其余接口分别配置
+const (
+	corsGroupAPI   = "api"
+	corsGroupFiles = "files"
+)
+
+// corsFilePrefixes 公开文件访问路由（原图、缩略图、短链、头像与随机图片接口）
+var corsFilePrefixes = []string{"/f/", "/t/", "/s/", "/file/", "/api/v1/r/"}
+
+// corsDefaults 各分组默认值，与早期全局配置保持一致：允许任意来源，仅本站来源可携带凭证
+var corsDefaults = map[string]corsPolicy{
+	corsGroupAPI: {
+		Origins:     "*",
+		Credentials: true,
+		Methods:     "GET, POST, PUT, DELETE, OPTIONS, PATCH",
+		MaxAge:      86400,
+	},
+	corsGroupFiles: {
+		Origins:     "*",
+		Credentials: true,
+		Methods:     "GET, HEAD, OPTIONS",
+		MaxAge:      86400,
+	},
+}
+
+// corsPolicy 单个路由分组的跨域策略
+type corsPolicy struct {
+	Origins     string // 允许的来源，逗号分隔，支持 * 与 https://*.example.com 形式的通配
+	Credentials bool   // 是否允许携带凭证，仅对本站来源与明确列出的来源生效
+	Methods     string
+	MaxAge      int // 预检结果缓存秒数，0 表示不返回
+}
+
+func corsRouteGroup(path string) string {
+	for _, prefix := range corsFilePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return corsGroupFiles
+		}
+	}
+	return corsGroupAPI
+}
+
+// loadCORSPolicy 从设置分组 cors 读取策略，键名为 <分组>_allowed_origins 等
+func loadCORSPolicy(group string) corsPolicy {
+	def := corsDefaults[group]
+	return corsPolicy{
+		Origins:     setting.GetString(corsSettingGroup, group+"_allowed_origins", def.Origins),
+		Credentials: setting.GetBool(corsSettingGroup, group+"_allow_credentials", def.Credentials),
+		Methods:     setting.GetString(corsSettingGroup, group+"_allowed_methods", def.Methods),
+		MaxAge:      setting.GetInt(corsSettingGroup, group+"_max_age", def.MaxAge),
+	}
+}
+
+// matchOrigin 判断来源是否在允许列表中，listed 表示来源被明确列出（而非通过 * 放行）
+func (p corsPolicy) matchOrigin(origin string) (allowed, listed bool) {
+	origin = strings.ToLower(strings.TrimRight(origin, "/"))
+	for _, item := range strings.Split(p.Origins, ",") {
+		pattern := strings.ToLower(strings.TrimRight(strings.TrimSpace(item), "/"))
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "*":
+			allowed = true
+		case pattern == origin:
+			return true, true
+		case strings.Contains(pattern, "*."):
+			// https://*.example.com 只匹配子域名，不匹配 example.com 本身
+			i := strings.Index(pattern, "*.")
+			prefix, suffix := pattern[:i], pattern[i+1:]
+			target := origin
+			if prefix == "" {
+				// *.example.com 不限协议
+				if j := strings.Index(target, "://"); j >= 0 {
+					target = target[j+3:]
+				}
+			}
+			if strings.HasPrefix(target, prefix) && strings.HasSuffix(target, suffix) &&
+				len(target) > len(prefix)+len(suffix) && !strings.Contains(target[len(prefix):len(target)-len(suffix)], "/") {
+				return true, true
+			}
+		}
+	}
+	return allowed, false
+}
+
+/* CORSMiddleware 跨域(CORS)中间件，按路由分组（接口 / 公开文件）读取设置分组 cors 中的策略：
+ * - 本站来源（与当前 Host / 配置的 baseUrl 匹配）始终允许，便于收紧接口跨域而不影响前端
+ * - 通过 * 放行的来源不允许携带凭证（避免反射 Origin + credentials 的安全风险）
+ * - 不在允许列表中的来源不返回跨域响应头，预检请求返回 403
  */
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		origin := strings.TrimSpace(c.Request.Header.Get("Origin"))
+		if !setting.GetBool(corsSettingGroup, "cors_enabled", true) {
+			c.Next()
+			return
+		}
 
-		allowOrigin := ""
-		allowCredentials := false
+		policy := loadCORSPolicy(corsRouteGroup(c.Request.URL.Path))
+		origin := strings.TrimSpace(c.Request.Header.Get("Origin"))
+		header := c.Writer.Header()
 
 		if origin == "" {
 			// 非浏览器请求通常没有 Origin
-			allowOrigin = "*"
+			header.Set("Access-Control-Allow-Origin", "*")
 		} else {
-			allowOrigin = origin
-			allowCredentials = isTrustedOrigin(origin, c.Request.Host, utils.GetBaseUrl())
-		}
-
-		if allowOrigin != "" {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", allowOrigin)
-			if allowOrigin != "*" {
-				c.Writer.Header().Set("Vary", "Origin")
+			header.Add("Vary", "Origin")
+			trusted := isTrustedOrigin(origin, c.Request.Host, utils.GetBaseUrl())
+			allowed, listed := policy.matchOrigin(origin)
+			if !trusted && !allowed {
+				if c.Request.Method == http.MethodOptions {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				c.Next()
+				return
 			}
-			if allowCredentials {
-				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			header.Set("Access-Control-Allow-Origin", origin)
+			if policy.Credentials && (trusted || listed) {
+				header.Set("Access-Control-Allow-Credentials", "true")
 			}
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
+		header.Set("Access-Control-Allow-Methods", policy.Methods)
 
 		requestedHeaders := c.Request.Header.Get("Access-Control-Request-Headers")
-		baseAllowedHeaders := "Authorization, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Accept, X-Requested-With, X-API-Key, x-pixelpunk-key"
+		baseAllowedHeaders := "Authorization, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Accept, X-Requested-With, X-API-Key, x-pixelpunk-key, X-Request-ID"
 		if requestedHeaders != "" {
-			c.Writer.Header().Set("Access-Control-Allow-Headers", baseAllowedHeaders+", "+requestedHeaders)
+			header.Set("Access-Control-Allow-Headers", baseAllowedHeaders+", "+requestedHeaders)
 		} else {
-			c.Writer.Header().Set("Access-Control-Allow-Headers", baseAllowedHeaders)
+			header.Set("Access-Control-Allow-Headers", baseAllowedHeaders)
 		}
 
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Disposition, Content-Type, X-Request-Id, X-Request-ID")
-		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
+		header.Set("Access-Control-Expose-Headers", "Content-Disposition, Content-Type, X-Request-Id, X-Request-ID, X-Trace-Id, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy")
+		if policy.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
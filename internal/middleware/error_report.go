@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pixelpunk/internal/services/errorreport"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/errreport"
+	"pixelpunk/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* ReportError 错误响应的上报回调，附带请求ID、用户与路由信息，通过 errors.SetReporter 注册 */
+func ReportError(c *gin.Context, err *errors.Error, status int, panicked bool) {
+	if !errorreport.Enabled() {
+		return
+	}
+
+	level := errreport.LevelWarning
+	switch {
+	case panicked:
+		level = errreport.LevelFatal
+	case status >= http.StatusInternalServerError:
+		level = errreport.LevelError
+	}
+
+	userID := GetCurrentUserID(c)
+	if userID == 0 {
+		// API Key、WebDAV 等鉴权方式只设置了 user_id
+		if id, ok := c.Get("user_id"); ok {
+			userID, _ = id.(uint)
+		}
+	}
+
+	requestID := err.RequestID
+	if requestID == "" {
+		requestID = c.GetString("RequestID")
+	}
+
+	errorreport.Capture(&errreport.Event{
+		Level:     level,
+		Code:      int(err.Code),
+		Message:   err.Message,
+		Detail:    err.Detail,
+		Stack:     err.Stack,
+		Status:    status,
+		Panic:     panicked,
+		RequestID: requestID,
+		TraceID:   tracing.TraceIDFromContext(c.Request.Context()),
+		UserID:    userID,
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+		Path:      c.Request.URL.Path,
+		ClientIP:  c.ClientIP(),
+	})
+}
@@ -57,7 +57,7 @@ func FileAccessControlMiddleware() gin.HandlerFunc {
 		}
 
 		if isInternalRequest && (file.AccessLevel == "public" || file.AccessLevel == "private") {
-			if file.Status == "pending_review" {
+			if file.Status == "pending_review" || isShareHeldFromViewer(c, file) {
 				assets.ServeDefaultFile(c, assets.FileTypeReview)
 				return
 			}
@@ -77,6 +77,13 @@ func FileAccessControlMiddleware() gin.HandlerFunc {
 				assets.ServeDefaultFile(c, assets.FileTypeUnauthorized)
 				return
 			}
+			// 分享页签发的单文件令牌免去逐级校验分享目录，但仍校验分享状态、排除项及绑定的访客凭证；无效时回退到完整的分享校验
+			grant := c.Query("sg")
+			if utils.GetURLSigner().VerifyShareFile(shareKey, file.ID, grant, c.Query("st"), c.Query("ss")) &&
+				share.AuthorizeSharedFile(shareKey, file.ID, share.ParseShareGrant(grant)) {
+				c.Next()
+				return
+			}
 			if verifyShareAccess(c, shareKey, file.ID) {
 				c.Next()
 				return
@@ -104,14 +111,42 @@ func verifyShareAccess(c *gin.Context, shareKey string, fileID string) bool {
 		return false
 	}
 
+	if !share.IsStarted() {
+		return false
+	}
+
 	if share.MaxViews > 0 && share.CurrentViews >= share.MaxViews {
 		return false
 	}
 
-	if share.Password != "" {
+	// 邀请链接可替代密码和邮箱验证
+	invited := hasShareInviteAccess(c, share.ID)
+
+	if share.Password != "" && !invited {
 		return false
 	}
 
+	if share.RequireEmailVerification && !invited && !hasShareEmailAccess(c, share) {
+		return false
+	}
+
+	// 指定用户分享仅允许分享者和接收者本人访问
+	if share.AccessMode == common.ShareAccessModeUsers {
+		userID := GetCurrentUserID(c)
+		if userID == 0 {
+			return false
+		}
+		if userID != share.UserID {
+			var recipientCount int64
+			database.DB.Model(&models.ShareRecipient{}).
+				Where("share_id = ? AND user_id = ?", share.ID, userID).
+				Count(&recipientCount)
+			if recipientCount == 0 {
+				return false
+			}
+		}
+	}
+
 	// 检查文件是否直接在分享列表中
 	var count int64
 	database.DB.Model(&models.ShareItem{}).
@@ -121,6 +156,15 @@ func verifyShareAccess(c *gin.Context, shareKey string, fileID string) bool {
 		return true
 	}
 
+	// 文件夹内被分享者排除的文件不可访问
+	var excludedCount int64
+	database.DB.Model(&models.ShareExclusion{}).
+		Where("share_id = ? AND file_id = ?", share.ID, fileID).
+		Count(&excludedCount)
+	if excludedCount > 0 {
+		return false
+	}
+
 	// 获取目标文件
 	var targetImage models.File
 	if err := database.DB.Where("id = ?", fileID).First(&targetImage).Error; err != nil {
@@ -226,12 +270,17 @@ func updateFileStats(fileID string, userID uint, size int64) {
 	stats.GetStatsAdapter().RecordFileViewed(size)
 }
 
+// isShareHeldFromViewer 收集型分享中待分享者审核的文件仅对所有者与管理员可见
+func isShareHeldFromViewer(c *gin.Context, file models.File) bool {
+	return file.Status == common.FileStatusShareHold && !CanUserAccessProtectedFile(c, file.UserID)
+}
+
 func handleFileAccessLevel(c *gin.Context, file models.File, isInternalRequest bool) bool {
 	switch file.AccessLevel {
 	case "public":
 		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", CurrentFileAccessConfig.PublicCacheMaxAge))
 
-		if file.Status == "pending_review" {
+		if file.Status == "pending_review" || isShareHeldFromViewer(c, file) {
 			assets.ServeDefaultFile(c, assets.FileTypeReview)
 			return true
 		}
@@ -510,3 +559,16 @@ func isLocalDevelopment(baseUrl, referer string) bool {
 
 	return isLocalBase && isLocalReferer
 }
+
+// hasShareEmailAccess 需要验证邮箱的分享须携带邮箱访问令牌，分享者本人除外
+func hasShareEmailAccess(c *gin.Context, shareInfo models.Share) bool {
+	if userID := GetCurrentUserID(c); userID != 0 && userID == shareInfo.UserID {
+		return true
+	}
+	return share.ValidateShareEmailToken(shareInfo.ID, c.Query("email_token"))
+}
+
+func hasShareInviteAccess(c *gin.Context, shareID string) bool {
+	token := c.Query("invite")
+	return token != "" && share.ValidateShareInvitation(shareID, token)
+}
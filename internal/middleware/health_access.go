@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"pixelpunk/pkg/config"
+	"pixelpunk/pkg/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthProbeTokenHeader 携带完整健康检查探测令牌的请求头
+const HealthProbeTokenHeader = "X-Health-Token"
+
+// HealthProbeAccess 标记调用方是否可执行完整健康检查：管理员登录态或与 app.health_token 一致的探测令牌，需在 OptionalUserAuth 之后使用
+func HealthProbeAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsCurrentUserAdmin(c) || validHealthToken(c.GetHeader(HealthProbeTokenHeader)) {
+			c.Set(health.FullAccessKey, true)
+		}
+		c.Next()
+	}
+}
+
+func validHealthToken(token string) bool {
+	expected := strings.TrimSpace(config.GetConfig().App.HealthToken)
+	token = strings.TrimSpace(token)
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
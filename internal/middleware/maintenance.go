@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultMaintenanceMessage = "系统维护中，暂时无法上传或修改数据，请稍后再试"
+
+// 维护期间仍允许的写操作：登录相关及会话安全操作，保证管理员能登录并关闭维护
+var maintenanceAllowedPaths = []string{
+	"/api/v1/setup",
+	"/api/v1/auth/login",
+	"/api/v1/auth/passkey/login",
+	"/api/v1/auth/saml",
+	"/api/v1/auth/oauth",
+	"/api/v1/auth/login-alert/lock",
+	"/api/v1/user/login",
+	"/api/v1/user/personal/sessions",
+}
+
+/* IsMaintenanceMode 是否处于维护模式 */
+func IsMaintenanceMode() bool {
+	return setting.GetBool("website", "maintenance_mode", false)
+}
+
+/* MaintenanceMode 维护模式中间件：上传和写操作返回503，读取与文件访问不受影响，管理员不受限制 */
+func MaintenanceMode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsMaintenanceMode() {
+			c.Next()
+			return
+		}
+
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		for _, allowed := range maintenanceAllowedPaths {
+			if strings.HasPrefix(path, allowed) {
+				c.Next()
+				return
+			}
+		}
+
+		if claims := GetCurrentUser(c); claims != nil && (claims.Role == common.UserRoleAdmin || claims.Role == common.UserRoleSuperAdmin) {
+			if _, hasAuthErr := c.Get(AuthErrorKey); !hasAuthErr {
+				c.Next()
+				return
+			}
+		}
+
+		message := setting.GetString("website", "maintenance_message", "")
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		endTime := setting.GetString("website", "maintenance_end_time", "")
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", endTime, time.Local); err == nil {
+			if wait := time.Until(t); wait > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())))
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"code":    int(errors.CodeServiceUnavailable),
+			"message": message,
+			"data": gin.H{
+				"maintenance": true,
+				"end_time":    endTime,
+			},
+		})
+	}
+}
@@ -22,19 +22,7 @@ func OptionalJWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := c.GetHeader("Authorization")
-		if tokenString == "" {
-			tokenString = c.Query("token")
-			if tokenString == "" {
-				tokenString, _ = c.Cookie("token")
-			}
-		} else {
-			const prefix = "Bearer "
-			if len(tokenString) > len(prefix) && tokenString[:len(prefix)] == prefix {
-				tokenString = tokenString[len(prefix):]
-			}
-		}
-		tokenString = strings.TrimSpace(tokenString)
+		tokenString := extractOptionalToken(c)
 
 		// 未携带 token，直接放行
 		if tokenString == "" {
@@ -74,3 +62,47 @@ func OptionalJWTAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalUserAuth 可选登录态解析中间件（JSON 接口使用）：
+// - 未携带或携带无效 token 时按匿名访问处理
+// - 用户被禁用时同样视为匿名，不中止请求
+//
+// 主要用于公告列表等公开接口：匿名可访问，已登录用户可获得个性化结果。
+func OptionalUserAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := extractOptionalToken(c)
+		jwtSecret := strings.TrimSpace(getJWTSecret())
+		if tokenString == "" || jwtSecret == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := auth.ParseToken(tokenString, jwtSecret)
+		if err != nil || claims.ExpiresAt == nil || claims.ExpiresAt.Unix() < auth.GetCurrentTimestamp() {
+			c.Next()
+			return
+		}
+		if checkUserActive(claims) {
+			c.Set(ContextPayloadKey, claims)
+		}
+
+		c.Next()
+	}
+}
+
+// extractOptionalToken 依次从 Authorization 头、token 参数、token Cookie 中读取令牌
+func extractOptionalToken(c *gin.Context) string {
+	tokenString := c.GetHeader("Authorization")
+	if tokenString == "" {
+		tokenString = c.Query("token")
+		if tokenString == "" {
+			tokenString, _ = c.Cookie("token")
+		}
+	} else {
+		const prefix = "Bearer "
+		if len(tokenString) > len(prefix) && tokenString[:len(prefix)] == prefix {
+			tokenString = tokenString[len(prefix):]
+		}
+	}
+	return strings.TrimSpace(tokenString)
+}
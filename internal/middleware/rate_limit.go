@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/services/ratelimit"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit 按策略进行令牌桶限流：已登录用户按用户计数，匿名请求按客户端 IP 计数，
+// 并返回 RateLimit-Limit / RateLimit-Remaining / RateLimit-Reset / RateLimit-Policy 响应头
+func RateLimit(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := ratelimit.GetPolicy(policy)
+		if p == nil {
+			c.Next()
+			return
+		}
+
+		// 使用按信任代理解析的客户端 IP，避免伪造 X-Forwarded-For 绕过限流
+		subject := "ip:" + c.ClientIP()
+		if userID := GetCurrentUserID(c); userID > 0 {
+			subject = fmt.Sprintf("user:%d", userID)
+		}
+
+		result := ratelimit.Take(p, subject)
+		window := int(math.Ceil(float64(p.Burst) * 60 / float64(p.PerMinute)))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Burst, window))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			errors.HandleError(c, errors.New(errors.CodeRateLimited, fmt.Sprintf("请求过于频繁，请%d秒后重试", retryAfter)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
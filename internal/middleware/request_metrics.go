@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"pixelpunk/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RequestMetrics 记录每个请求的响应状态，用于仪表盘错误率与 Prometheus 指标 */
+func RequestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		metrics.RecordHTTPResponse(c.Writer.Status())
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/apikey"
+	"pixelpunk/pkg/s3compat"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* S3AuthMiddleware S3 兼容接口认证：AWS V4 签名，Access Key ID 为API密钥ID，Secret Access Key 为密钥值 */
+func S3AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sr, err := s3compat.ParseRequest(c.Request)
+		if err != nil {
+			AbortS3(c, err)
+			return
+		}
+
+		key, err := apikey.ValidateAPIKeyID(sr.AccessKeyID)
+		if err != nil {
+			AbortS3(c, s3compat.ErrInvalidAccessKeyID)
+			return
+		}
+		if err := sr.Verify(c.Request, key.KeyValue, time.Now()); err != nil {
+			AbortS3(c, err)
+			return
+		}
+
+		clientIP := utils.GetClientIP(c)
+		if !apikey.IsIPAllowed(key, clientIP) {
+			apikey.RecordAPIKeyIPBlocked(key.ID)
+			AbortS3(c, s3compat.ErrAccessDenied)
+			return
+		}
+
+		rate, ok := apikey.TakeRateLimit(key)
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+			apikey.RecordAPIKeyRateLimited(key.ID)
+			AbortS3(c, s3compat.ErrSlowDown)
+			return
+		}
+		apikey.RecordAPIKeyRequest(key.ID, clientIP)
+
+		if err := apikey.CheckScope(key, s3Scope(c.Request.Method)); err != nil {
+			AbortS3(c, s3compat.ErrAccessDenied)
+			return
+		}
+
+		c.Set("api_key", key)
+		c.Set("api_key_id", key.ID)
+		c.Set("user_id", key.UserID)
+		c.Set("s3_request", sr)
+
+		c.Next()
+	}
+}
+
+// s3Scope 按请求方法映射到API密钥授权范围
+func s3Scope(method string) string {
+	switch method {
+	case http.MethodPut:
+		return models.APIKeyScopeUpload
+	case http.MethodDelete, http.MethodPost:
+		return models.APIKeyScopeDelete
+	default:
+		return models.APIKeyScopeRead
+	}
+}
+
+/* GetS3Request 获取已通过校验的签名信息 */
+func GetS3Request(c *gin.Context) *s3compat.SignedRequest {
+	if v, exists := c.Get("s3_request"); exists {
+		if sr, ok := v.(*s3compat.SignedRequest); ok {
+			return sr
+		}
+	}
+	return nil
+}
+
+/* AbortS3 以 S3 XML 格式返回错误并终止请求 */
+func AbortS3(c *gin.Context, err error) {
+	s3Err, ok := err.(*s3compat.Error)
+	if !ok {
+		s3Err = s3compat.ErrInternal
+	}
+
+	requestID, _ := c.Get("RequestID")
+	id, _ := requestID.(string)
+	c.Header("x-amz-request-id", id)
+
+	if c.Request.Method == http.MethodHead {
+		c.AbortWithStatus(s3Err.Status)
+		return
+	}
+	body, _ := xml.Marshal(s3compat.ErrorResponse{
+		Code:      s3Err.Code,
+		Message:   s3Err.Message,
+		Resource:  c.Request.URL.Path,
+		RequestID: id,
+	})
+	c.Data(s3Err.Status, "application/xml", append([]byte(xml.Header), body...))
+	c.Abort()
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"pixelpunk/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* Tracing 为每个请求创建服务端 Span，延续上游 traceparent，并在响应头返回链路ID便于排查 */
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.Start(ctx, c.Request.Method, tracing.WithKind(tracing.SpanKindServer),
+			tracing.WithAttributes(
+				tracing.Attr("http.request.method", c.Request.Method),
+				tracing.Attr("url.path", c.Request.URL.Path),
+				tracing.Attr("client.address", c.ClientIP()),
+				tracing.Attr("user_agent.original", c.Request.UserAgent()),
+			))
+		c.Request = c.Request.WithContext(ctx)
+		if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+			c.Header("X-Trace-Id", traceID)
+		}
+
+		c.Next()
+
+		// 路由匹配发生在中间件之后，结束时再用路由模板命名，避免路径参数导致 Span 名称过多
+		if route := c.FullPath(); route != "" {
+			span.SetName(c.Request.Method + " " + route)
+			span.SetAttributes(tracing.Attr("http.route", route))
+		}
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Attr("http.response.status_code", status))
+		if userID := GetCurrentUserID(c); userID > 0 {
+			span.SetAttributes(tracing.Attr("enduser.id", strconv.FormatUint(uint64(userID), 10)))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.SetAttributes(tracing.Attr("error.message", c.Errors.String()))
+		}
+		span.End()
+	}
+}
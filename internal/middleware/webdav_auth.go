@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/apikey"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/internal/services/user"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* WebDAVAuthMiddleware WebDAV 认证：支持API密钥（请求头或作为 Basic 密码）与账号密码 Basic 认证 */
+func WebDAVAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !setting.GetBool("webdav", "webdav_enabled", true) {
+			errors.HandleError(c, errors.New(errors.CodeForbidden, "WebDAV 访问未启用"))
+			c.Abort()
+			return
+		}
+
+		keyValue := c.GetHeader("x-pixelpunk-key")
+		if keyValue == "" {
+			keyValue = c.GetHeader("x-api-key")
+		}
+		username, password, hasBasic := c.Request.BasicAuth()
+		if keyValue == "" && hasBasic {
+			if key, err := apikey.ValidateAPIKey(password); err == nil {
+				authorizeWebDAVKey(c, key)
+				return
+			}
+		}
+		if keyValue != "" {
+			key, err := apikey.ValidateAPIKey(keyValue)
+			if err != nil {
+				abortWebDAVUnauthorized(c, err)
+				return
+			}
+			authorizeWebDAVKey(c, key)
+			return
+		}
+
+		if !hasBasic {
+			abortWebDAVUnauthorized(c, errors.New(errors.CodeUnauthorized, "请使用账号密码或API密钥登录"))
+			return
+		}
+		if !setting.GetBool("webdav", "webdav_allow_password_auth", true) {
+			abortWebDAVUnauthorized(c, errors.New(errors.CodeUnauthorized, "WebDAV 仅支持API密钥登录"))
+			return
+		}
+
+		// 每个请求都重新校验，修改密码、禁用、锁定或启用强制SSO后立即生效
+		u, err := user.AuthenticateBasic(username, password)
+		if err != nil {
+			abortWebDAVUnauthorized(c, err)
+			return
+		}
+		c.Set("user_id", u.ID)
+		c.Next()
+	}
+}
+
+// authorizeWebDAVKey 按请求方法校验密钥授权范围，与外部API共用IP白名单和频率限制
+func authorizeWebDAVKey(c *gin.Context, key *models.APIKey) {
+	clientIP := utils.GetClientIP(c)
+	if !apikey.IsIPAllowed(key, clientIP) {
+		apikey.RecordAPIKeyIPBlocked(key.ID)
+		errors.HandleError(c, errors.New(errors.CodeForbidden, fmt.Sprintf("当前IP(%s)不在该API密钥的IP白名单中", clientIP)))
+		c.Abort()
+		return
+	}
+
+	rate, ok := apikey.TakeRateLimit(key)
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+		apikey.RecordAPIKeyRateLimited(key.ID)
+		errors.HandleError(c, errors.New(errors.CodeRateLimited, "API密钥请求频率超出限制"))
+		c.Abort()
+		return
+	}
+	apikey.RecordAPIKeyRequest(key.ID, clientIP)
+
+	if err := apikey.CheckScope(key, webdavScope(c.Request.Method)); err != nil {
+		errors.HandleError(c, err)
+		c.Abort()
+		return
+	}
+
+	c.Set("api_key", key)
+	c.Set("api_key_id", key.ID)
+	c.Set("user_id", key.UserID)
+	c.Next()
+}
+
+// webdavScope 按 WebDAV 方法映射到API密钥授权范围
+func webdavScope(method string) string {
+	switch method {
+	case http.MethodPut, "MKCOL", "COPY", "MOVE", "PROPPATCH", "LOCK", "UNLOCK":
+		return models.APIKeyScopeUpload
+	case http.MethodDelete:
+		return models.APIKeyScopeDelete
+	default:
+		return models.APIKeyScopeRead
+	}
+}
+
+func abortWebDAVUnauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Basic realm="PixelPunk WebDAV", charset="UTF-8"`)
+	errors.HandleError(c, err)
+	c.Abort()
+}
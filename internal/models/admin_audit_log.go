@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+
+	"pixelpunk/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogImmutable 审计日志只允许追加
+var ErrAuditLogImmutable = errors.New("审计日志不允许修改或删除")
+
+/* AdminAuditLog 管理员操作审计日志，只追加不修改 */
+type AdminAuditLog struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `gorm:"index" json:"created_at"`
+
+	ActorID   uint   `gorm:"index;not null" json:"actor_id"`
+	ActorName string `gorm:"size:50" json:"actor_name"`
+	ActorRole int    `json:"actor_role"`
+
+	Action     string `gorm:"size:100;index" json:"action"`     // 操作标识，如 setting.update、user.update
+	TargetType string `gorm:"size:50;index" json:"target_type"` // 操作对象类型
+	TargetID   string `gorm:"size:100;index" json:"target_id"`  // 操作对象ID
+	Method     string `gorm:"size:10" json:"method"`            // HTTP方法
+	Path       string `gorm:"size:255" json:"path"`             // 请求路径
+	StatusCode int    `json:"status_code"`                      // 响应状态码
+	Success    bool   `gorm:"index" json:"success"`             // 操作是否成功
+	IP         string `gorm:"size:45;index" json:"ip"`          // 支持IPv6
+	UserAgent  string `gorm:"size:500" json:"user_agent"`       // 客户端信息
+
+	Request json.RawMessage `gorm:"type:json" json:"request,omitempty"` // 请求参数（已脱敏）
+	Before  json.RawMessage `gorm:"type:json" json:"before,omitempty"`  // 修改前快照
+	After   json.RawMessage `gorm:"type:json" json:"after,omitempty"`   // 修改后快照
+	Diff    json.RawMessage `gorm:"type:json" json:"diff,omitempty"`    // 变更字段 {field: {from, to}}
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
+
+func (a *AdminAuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+func (a *AdminAuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
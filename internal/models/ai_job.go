@@ -11,7 +11,7 @@ type AIJob struct {
 	UpdatedAt time.Time `json:"updated_at"`
 
 	FileID     string     `gorm:"size:32;uniqueIndex:idx_ai_job_file" json:"file_id"`
-	Status     string     `gorm:"size:20;index" json:"status"` // queued|processing|done|failed|ignored|skipped
+	Status     string     `gorm:"size:20;index" json:"status"` // queued|processing|done|failed|ignored|skipped|dead
 	Tries      int        `gorm:"default:0" json:"tries"`
 	Priority   int        `gorm:"default:0;index" json:"priority"`
 	LeaseUntil *time.Time `gorm:"index" json:"lease_until"`
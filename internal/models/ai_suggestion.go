@@ -0,0 +1,26 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* AISuggestion AI低置信度结果（待用户确认的建议） */
+type AISuggestion struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	FileID string `gorm:"size:32;not null;index:idx_ai_suggestion_file" json:"file_id"`
+	UserID uint   `gorm:"not null;index:idx_ai_suggestion_user_status" json:"user_id"`
+	Type   string `gorm:"size:20;not null" json:"type"`                                       // tag/category/recommend
+	Status string `gorm:"size:20;not null;index:idx_ai_suggestion_user_status" json:"status"` // pending/accepted/rejected
+
+	Value               string  `gorm:"size:255" json:"value"`                // 标签名/分类名
+	CategoryID          uint    `gorm:"default:0" json:"category_id"`         // 分类建议对应的已有分类ID，0表示建议新建
+	CategoryDescription string  `gorm:"size:255" json:"category_description"` // 新建分类时使用的描述
+	Confidence          float64 `gorm:"default:0" json:"confidence"`          // AI给出的置信度
+}
+
+func (AISuggestion) TableName() string {
+	return "ai_suggestion"
+}
@@ -6,6 +6,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// 公告受众
+const (
+	AnnouncementAudienceAll      = "all"
+	AnnouncementAudienceTargeted = "targeted"
+)
+
 /* Announcement 公告模型 */
 type Announcement struct {
 	ID        uint            `gorm:"primarykey" json:"id"`
@@ -17,9 +23,14 @@ type Announcement struct {
 	Content   string `gorm:"type:text;not null" json:"content"`         // 内容（Markdown格式）
 	Summary   string `gorm:"size:500" json:"summary"`                   // 摘要
 	IsPinned  bool   `gorm:"default:false;index" json:"is_pinned"`      // 是否置顶
-	Status    string `gorm:"size:20;default:draft;index" json:"status"` // 状态: draft, published, archived
+	Status    string `gorm:"size:20;default:draft;index" json:"status"` // 状态: draft, scheduled, published, archived
 	ViewCount int    `gorm:"default:0" json:"view_count"`               // 浏览次数
 	CreatedBy uint   `gorm:"index" json:"created_by"`                   // 创建者ID
+
+	PublishAt *common.JSONTime `gorm:"index" json:"publish_at"` // 定时发布时间，为空表示立即发布
+	ExpireAt  *common.JSONTime `gorm:"index" json:"expire_at"`  // 自动下线时间，为空表示长期有效
+
+	Audience string `gorm:"size:20;default:all;index" json:"audience"` // 受众: all 全部用户, targeted 指定角色/用户组/用户
 }
 
 func (Announcement) TableName() string {
@@ -31,6 +42,9 @@ func (a *Announcement) BeforeCreate(tx *gorm.DB) error {
 	if a.Status == "" {
 		a.Status = "draft"
 	}
+	if a.Audience == "" {
+		a.Audience = AnnouncementAudienceAll
+	}
 	return nil
 }
 
@@ -39,6 +53,10 @@ func (a *Announcement) IsDraft() bool {
 	return a.Status == "draft"
 }
 
+func (a *Announcement) IsScheduled() bool {
+	return a.Status == "scheduled"
+}
+
 func (a *Announcement) IsPublished() bool {
 	return a.Status == "published"
 }
@@ -46,3 +64,7 @@ func (a *Announcement) IsPublished() bool {
 func (a *Announcement) IsArchived() bool {
 	return a.Status == "archived"
 }
+
+func (a *Announcement) IsTargeted() bool {
+	return a.Audience == AnnouncementAudienceTargeted
+}
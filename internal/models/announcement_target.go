@@ -0,0 +1,36 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+// 公告投放对象类型
+const (
+	AnnouncementTargetRole  = "role"
+	AnnouncementTargetGroup = "group"
+	AnnouncementTargetUser  = "user"
+)
+
+/* AnnouncementTarget 定向公告的投放对象，命中任意一条即对该用户可见 */
+type AnnouncementTarget struct {
+	ID             uint   `gorm:"primarykey" json:"id"`
+	AnnouncementID uint   `gorm:"not null;index:idx_announcement_target" json:"announcement_id"`
+	TargetType     string `gorm:"size:10;not null;index:idx_announcement_target;index:idx_announcement_target_lookup" json:"target_type"` // role, group, user
+	TargetID       uint   `gorm:"not null;index:idx_announcement_target_lookup" json:"target_id"`                                         // 角色值、用户组ID或用户ID
+}
+
+func (AnnouncementTarget) TableName() string {
+	return "announcement_targets"
+}
+
+/* AnnouncementRead 用户的公告已读记录 */
+type AnnouncementRead struct {
+	ID             uint            `gorm:"primarykey" json:"id"`
+	AnnouncementID uint            `gorm:"not null;uniqueIndex:idx_announcement_read" json:"announcement_id"`
+	UserID         uint            `gorm:"not null;uniqueIndex:idx_announcement_read;index" json:"user_id"`
+	ReadAt         common.JSONTime `json:"read_at"`
+}
+
+func (AnnouncementRead) TableName() string {
+	return "announcement_reads"
+}
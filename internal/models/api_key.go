@@ -2,6 +2,7 @@ package models
 
 import (
 	"pixelpunk/pkg/common"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -27,6 +28,16 @@ type APIKey struct {
 	AllowedTypes string `gorm:"size:255" json:"allowed_types"` // 允许的文件类型，如: "jpg,jpeg,png,gif"
 	FolderID     string `gorm:"size:32" json:"folder_id"`      // 指定上传目录
 
+	Scopes           string `gorm:"size:100;default:'upload'" json:"scopes"` // 授权范围，逗号分隔，如: "upload,read"
+	RestrictToFolder bool   `gorm:"default:false" json:"restrict_to_folder"` // 是否将所有操作限制在 FolderID 目录及其子目录内
+
+	RateLimitPerMinute int    `gorm:"default:0" json:"rate_limit_per_minute"` // 每分钟请求次数限制，0表示不限制
+	AllowedIPs         string `gorm:"size:1000" json:"allowed_ips"`           // IP白名单，逗号分隔，支持CIDR，为空表示不限制
+	RequestCount       int64  `gorm:"default:0" json:"request_count"`         // 累计请求次数
+	RateLimitedCount   int64  `gorm:"default:0" json:"rate_limited_count"`    // 因频率限制被拒绝的次数
+	IPBlockedCount     int64  `gorm:"default:0" json:"ip_blocked_count"`      // 因IP不在白名单被拒绝的次数
+	LastUsedIP         string `gorm:"size:45" json:"last_used_ip"`            // 最后请求IP
+
 	ExpiresAt  *common.JSONTime `json:"expires_at"`   // 过期时间，nil表示永不过期
 	LastUsedAt *common.JSONTime `json:"last_used_at"` // 最后使用时间
 }
@@ -37,6 +48,28 @@ const (
 	APIKeyStatusDisabled = 2 // 禁用状态
 )
 
+/* APIKeyScope API密钥授权范围常量 */
+const (
+	APIKeyScopeUpload = "upload" // 上传文件
+	APIKeyScopeRead   = "read"   // 查询文件列表和详情
+	APIKeyScopeDelete = "delete" // 删除文件
+	APIKeyScopeShare  = "share"  // 创建分享
+	APIKeyScopeAdmin  = "admin"  // 全部权限
+)
+
+// APIKeyScopes 所有可用的授权范围
+var APIKeyScopes = []string{APIKeyScopeUpload, APIKeyScopeRead, APIKeyScopeDelete, APIKeyScopeShare, APIKeyScopeAdmin}
+
+/* IsValidAPIKeyScope 判断授权范围是否有效 */
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func (APIKey) TableName() string {
 	return "api_key"
 }
@@ -48,6 +81,35 @@ func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+/* ScopeList 返回授权范围列表，未设置时仅允许上传（兼容旧密钥） */
+func (k *APIKey) ScopeList() []string {
+	if strings.TrimSpace(k.Scopes) == "" {
+		return []string{APIKeyScopeUpload}
+	}
+	var scopes []string
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+/* HasScope 判断密钥是否拥有指定授权范围，admin 拥有全部权限 */
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope || s == APIKeyScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+/* IsFolderRestricted 是否限制在指定目录内操作 */
+func (k *APIKey) IsFolderRestricted() bool {
+	return k.RestrictToFolder && k.FolderID != ""
+}
+
 func (k *APIKey) IsActive() bool {
 	return k.Status == APIKeyStatusActive
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+/* BackgroundJob 通用后台任务队列表，未启用 Redis 时缩略图重新生成、定时清理等任务保存在此表，按任务类型与任务ID去重 */
+type BackgroundJob struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Kind       string     `gorm:"size:32;uniqueIndex:idx_background_job_kind_job" json:"kind"`
+	JobID      string     `gorm:"size:64;uniqueIndex:idx_background_job_kind_job" json:"job_id"`
+	Status     string     `gorm:"size:20;index" json:"status"` // queued|processing|done|dead
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	Priority   int        `gorm:"default:0;index" json:"priority"`
+	LeaseUntil *time.Time `gorm:"index" json:"lease_until"` // queued 时为计划执行时间，processing 时为租约到期时间
+	LeaseBy    string     `gorm:"size:64" json:"lease_by"`
+	LastError  string     `gorm:"type:text" json:"last_error"`
+}
+
+func (BackgroundJob) TableName() string { return "background_job" }
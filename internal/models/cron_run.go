@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// 定时任务执行状态
+const (
+	CronRunRunning = "running"
+	CronRunSuccess = "success"
+	CronRunFailed  = "failed"
+)
+
+// 定时任务触发方式
+const (
+	CronTriggerSchedule = "schedule"
+	CronTriggerManual   = "manual"
+)
+
+/* CronRun 定时任务执行记录，开始时写入，结束后补充耗时、处理数量与错误 */
+type CronRun struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	TaskName   string     `gorm:"size:64;index:idx_cron_run_task_started" json:"task_name"`
+	Trigger    string     `gorm:"size:20" json:"trigger"`      // schedule|manual
+	Status     string     `gorm:"size:20;index" json:"status"` // running|success|failed
+	Instance   string     `gorm:"size:64" json:"instance"`     // 执行实例
+	StartedAt  time.Time  `gorm:"index:idx_cron_run_task_started;index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Items      int64      `json:"items"` // 本次处理的数量，含义由任务决定，如清理条数、入队数量
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+}
+
+func (CronRun) TableName() string {
+	return "cron_run"
+}
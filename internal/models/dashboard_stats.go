@@ -115,3 +115,53 @@ type DashboardSystemInfoResponse struct {
 	Uptime  string `json:"uptime"`
 	Status  string `json:"status"`
 }
+
+/* DashboardOverviewResponse 管理后台总览，聚合各项运营指标 */
+type DashboardOverviewResponse struct {
+	Days        int                    `json:"days"`
+	Uploads     DashboardUploadSeries  `json:"uploads"`
+	ActiveUsers DashboardActiveUsers   `json:"active_users"`
+	Queues      DashboardQueueDepth    `json:"queues"`
+	Review      DashboardReviewBacklog `json:"review"`
+	ErrorRates  DashboardErrorRates    `json:"error_rates"`
+	GeneratedAt string                 `json:"generated_at"`
+}
+
+/* DashboardUploadSeries 按日的上传数与存储增长 */
+type DashboardUploadSeries struct {
+	Timeline      []string `json:"timeline"`       // 日期 YYYY-MM-DD
+	UploadCounts  []int64  `json:"upload_counts"`  // 每日新增文件数
+	StorageGrowth []int64  `json:"storage_growth"` // 每日新增存储(字节)
+	TotalStorage  []int64  `json:"total_storage"`  // 每日末总存储(字节)
+}
+
+/* DashboardActiveUsers 活跃用户数 */
+type DashboardActiveUsers struct {
+	Daily   int64 `json:"daily"`   // 24小时内活跃
+	Weekly  int64 `json:"weekly"`  // 7天内活跃
+	Monthly int64 `json:"monthly"` // 30天内活跃
+	Total   int64 `json:"total"`
+}
+
+/* DashboardQueueDepth AI打标与向量化队列积压 */
+type DashboardQueueDepth struct {
+	AI     map[string]int64 `json:"ai"`     // 按状态统计的任务数
+	Vector map[string]int64 `json:"vector"` // 按状态统计的任务数
+}
+
+/* DashboardReviewBacklog 审核积压 */
+type DashboardReviewBacklog struct {
+	PendingFiles   int64 `json:"pending_files"`
+	PendingAppeals int64 `json:"pending_appeals"`
+}
+
+/* DashboardErrorRates 错误率（百分比） */
+type DashboardErrorRates struct {
+	HTTPRequests     uint64  `json:"http_requests"`      // 最近一小时请求数
+	HTTPServerErrors uint64  `json:"http_server_errors"` // 最近一小时5xx数
+	HTTPErrorRate    float64 `json:"http_error_rate"`
+	AIFailed         int64   `json:"ai_failed"` // 24小时内失败的打标任务
+	AIErrorRate      float64 `json:"ai_error_rate"`
+	VectorFailed     int64   `json:"vector_failed"` // 24小时内失败的向量任务
+	VectorErrorRate  float64 `json:"vector_error_rate"`
+}
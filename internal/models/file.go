@@ -78,6 +78,7 @@ type File struct {
 	GuestFingerprint       string     `gorm:"column:guest_fingerprint;size:64;index" json:"guest_fingerprint"`
 	GuestIP                string     `gorm:"column:guest_ip;size:45;index" json:"guest_ip"`
 	ExpiryNotificationSent bool       `gorm:"column:expiry_notification_sent;default:false" json:"expiry_notification_sent"`
+	TrashedAt              *time.Time `gorm:"column:trashed_at;index" json:"trashed_at"` // 软删除进入回收站的时间
 
 	SortOrder int `gorm:"default:0" json:"sort_order"`
 
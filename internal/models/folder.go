@@ -22,6 +22,7 @@ type Folder struct {
 	Description   string `gorm:"size:500" json:"description"`                              // 文件夹描述
 	IsRecommended bool   `gorm:"default:false;index" json:"is_recommended"`                // 是否是精选资源
 	SortOrder     int    `gorm:"default:0" json:"sort_order"`                              // 排序值
+	AIEnabled     bool   `gorm:"not null;default:true" json:"ai_enabled"`                  // 是否允许AI处理（关闭后子文件夹同样跳过）
 }
 
 func (Folder) TableName() string {
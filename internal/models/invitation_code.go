@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"pixelpunk/pkg/common"
+)
+
+// 邀请码状态
+const (
+	InvitationCodeStatusActive   = 1
+	InvitationCodeStatusDisabled = 2
+)
+
+/* InvitationCode 注册邀请码，可由管理员或有配额的用户生成 */
+type InvitationCode struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	Code      string           `gorm:"size:32;not null;uniqueIndex:idx_invitation_code" json:"code"`
+	CreatorID uint             `gorm:"not null;index" json:"creator_id"`
+	MaxUses   int              `gorm:"default:1" json:"max_uses"`   // 最多可使用次数，0表示不限制（仅管理员可生成）
+	UsedCount int              `gorm:"default:0" json:"used_count"` // 已使用次数
+	Status    int              `gorm:"default:1;index" json:"status"`
+	Note      string           `gorm:"size:255" json:"note"` // 备注，如发放对象
+	ExpiresAt *common.JSONTime `json:"expires_at"`           // 过期时间，为空表示永不过期
+
+	Creator *User `gorm:"foreignKey:CreatorID;references:ID" json:"creator,omitempty"`
+}
+
+func (InvitationCode) TableName() string {
+	return "invitation_code"
+}
+
+/* IsExpired 是否已过期 */
+func (c *InvitationCode) IsExpired() bool {
+	return c.ExpiresAt != nil && time.Now().After(time.Time(*c.ExpiresAt))
+}
+
+/* IsExhausted 是否已用完 */
+func (c *InvitationCode) IsExhausted() bool {
+	return c.MaxUses > 0 && c.UsedCount >= c.MaxUses
+}
+
+/* IsUsable 是否仍可用于注册 */
+func (c *InvitationCode) IsUsable() bool {
+	return c.Status == InvitationCodeStatusActive && !c.IsExpired() && !c.IsExhausted()
+}
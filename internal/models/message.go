@@ -33,6 +33,8 @@ type Message struct {
 	RequiresPermission string `gorm:"size:100" json:"requires_permission"`
 
 	MetaData string `gorm:"type:text" json:"meta_data"`
+
+	BroadcastID *uint `gorm:"index" json:"broadcast_id,omitempty"` // 由管理员群发产生的消息
 }
 
 func (Message) TableName() string {
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+// 群发消息状态
+const (
+	MessageBroadcastSending   = "sending"
+	MessageBroadcastCompleted = "completed"
+	MessageBroadcastFailed    = "failed"
+)
+
+/* MessageBroadcast 管理员群发的站内消息，每个接收人对应一条 Message，已读情况由 Message 统计 */
+type MessageBroadcast struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	SenderID   uint   `gorm:"not null;index" json:"sender_id"`
+	Title      string `gorm:"size:200;not null" json:"title"`
+	Content    string `gorm:"type:text;not null" json:"content"`
+	Priority   int    `gorm:"default:2" json:"priority"`
+	ActionURL  string `gorm:"size:500" json:"action_url"`
+	ActionText string `gorm:"size:100" json:"action_text"`
+	Segment    string `gorm:"type:text" json:"-"` // 接收人筛选条件(JSON)，为空表示全部正常用户
+
+	Status          string           `gorm:"size:20;not null;index" json:"status"`
+	TotalRecipients int              `gorm:"default:0" json:"total_recipients"`
+	DeliveredCount  int              `gorm:"default:0" json:"delivered_count"`
+	Error           string           `gorm:"size:500" json:"error,omitempty"`
+	CompletedAt     *common.JSONTime `json:"completed_at"`
+}
+
+func (MessageBroadcast) TableName() string {
+	return "message_broadcast"
+}
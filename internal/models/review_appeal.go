@@ -0,0 +1,35 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+const (
+	ReviewAppealStatusPending  = "pending"
+	ReviewAppealStatusApproved = "approved"
+	ReviewAppealStatusDenied   = "denied"
+)
+
+/* ReviewAppeal 审核申诉，上传者对被拒绝的文件发起，每条拒绝记录只能申诉一次 */
+type ReviewAppeal struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	ReviewLogID uint             `gorm:"not null;uniqueIndex" json:"review_log_id"`   // 被申诉的审核记录
+	FileID      string           `gorm:"size:32;not null;index" json:"file_id"`       // 被拒绝的文件
+	UserID      uint             `gorm:"not null;index" json:"user_id"`               // 申诉人（上传者）
+	Reason      string           `gorm:"type:text;not null" json:"reason"`            // 申诉理由
+	Status      string           `gorm:"size:20;default:pending;index" json:"status"` // pending/approved/denied
+	HandlerID   uint             `gorm:"index" json:"handler_id"`                     // 处理人
+	Response    string           `gorm:"type:text" json:"response"`                   // 处理意见
+	HandledAt   *common.JSONTime `json:"handled_at"`                                  // 处理时间
+
+	ReviewLog *ReviewLog `gorm:"foreignKey:ReviewLogID;references:ID" json:"review_log,omitempty"`
+	File      *File      `gorm:"foreignKey:FileID;references:ID" json:"file,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (ReviewAppeal) TableName() string {
+	return "review_appeal"
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+const (
+	ReviewBulkJobPending   = "pending"
+	ReviewBulkJobRunning   = "running"
+	ReviewBulkJobCompleted = "completed"
+	ReviewBulkJobFailed    = "failed"
+	ReviewBulkJobCancelled = "cancelled"
+)
+
+/* ReviewBulkJob 按筛选条件批量审核的后台任务及其结果报告 */
+type ReviewBulkJob struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	OperatorID uint   `gorm:"not null;index" json:"operator_id"`
+	Action     string `gorm:"size:20;not null" json:"action"` // approve/reject
+	Reason     string `gorm:"type:text" json:"reason"`
+	HardDelete bool   `gorm:"default:false" json:"hard_delete"`
+	Filter     string `gorm:"type:text" json:"filter"`                     // 筛选条件JSON
+	Status     string `gorm:"size:20;default:pending;index" json:"status"` // pending/running/completed/failed/cancelled
+
+	Total     int    `json:"total"`     // 任务开始时匹配的文件数
+	Processed int    `json:"processed"` // 已处理数
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Failures  string `gorm:"type:text" json:"failures"` // 失败明细JSON（文件ID -> 原因），最多保留前200条
+	Error     string `gorm:"type:text" json:"error"`
+
+	StartedAt  *common.JSONTime `json:"started_at"`
+	FinishedAt *common.JSONTime `json:"finished_at"`
+}
+
+func (ReviewBulkJob) TableName() string {
+	return "review_bulk_job"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"pixelpunk/pkg/common"
+)
+
+/* ReviewClaim 审核认领记录，认领期间其他审核员不能处理该文件 */
+type ReviewClaim struct {
+	FileID     string          `gorm:"primarykey;size:32" json:"file_id"`
+	CreatedAt  common.JSONTime `json:"created_at"`
+	AssignedTo uint            `gorm:"not null;index" json:"assigned_to"` // 认领的审核员ID
+	ExpiresAt  common.JSONTime `gorm:"index" json:"expires_at"`           // 锁定到期时间，到期后其他人可重新认领
+
+	Assignee *User `gorm:"foreignKey:AssignedTo;references:ID" json:"assignee,omitempty"`
+}
+
+func (ReviewClaim) TableName() string {
+	return "review_claim"
+}
+
+/* IsExpired 认领是否已过期 */
+func (r *ReviewClaim) IsExpired() bool {
+	return time.Now().After(time.Time(r.ExpiresAt))
+}
@@ -6,18 +6,24 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	ReviewAuditorManual = "manual"
+	ReviewAuditorAuto   = "auto"
+)
+
 /* ReviewLog 审核记录模型 */
 type ReviewLog struct {
 	ID        uint            `gorm:"primarykey" json:"id"`
 	CreatedAt common.JSONTime `json:"created_at"`
 	UpdatedAt common.JSONTime `json:"updated_at"`
 
-	FileID     string `gorm:"size:32;not null;index:idx_review_log_file_id" json:"file_id"`
-	AuditorID  uint   `gorm:"not null;index:idx_review_log_auditor_id" json:"auditor_id"`
-	UploaderID uint   `gorm:"not null;index:idx_review_log_uploader_id" json:"uploader_id"`
-	Action     string `gorm:"size:20;not null" json:"action"` // approve/reject
-	DeleteType string `gorm:"size:20" json:"delete_type"`     // soft/hard (仅reject时使用)
-	Reason     string `gorm:"type:text" json:"reason"`        // 审核原因/备注
+	FileID      string `gorm:"size:32;not null;index:idx_review_log_file_id" json:"file_id"`
+	AuditorID   uint   `gorm:"not null;index:idx_review_log_auditor_id" json:"auditor_id"`
+	AuditorType string `gorm:"size:20;default:manual" json:"auditor_type"` // manual/auto，auto 表示系统自动审核，此时 AuditorID 为 0
+	UploaderID  uint   `gorm:"not null;index:idx_review_log_uploader_id" json:"uploader_id"`
+	Action      string `gorm:"size:20;not null" json:"action"` // approve/reject
+	DeleteType  string `gorm:"size:20" json:"delete_type"`     // soft/hard (仅reject时使用)
+	Reason      string `gorm:"type:text" json:"reason"`        // 审核原因/备注
 
 	NSFWScore     *float64 `json:"nsfw_score"`     // AI检测的NSFW分数
 	NSFWThreshold *float64 `json:"nsfw_threshold"` // 当时使用的阈值
@@ -37,6 +43,10 @@ func (r *ReviewLog) BeforeCreate(tx *gorm.DB) error {
 		return gorm.ErrInvalidValue
 	}
 
+	if r.AuditorType == "" {
+		r.AuditorType = ReviewAuditorManual
+	}
+
 	if r.Action == "reject" && r.DeleteType == "" {
 		r.DeleteType = "soft"
 	}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* SearchFeedback 用户对语义搜索结果的相关性评价，用于按部署调优阈值与融合权重 */
+type SearchFeedback struct {
+	ID         uint            `gorm:"primarykey" json:"id"`
+	UserID     uint            `gorm:"not null;uniqueIndex:idx_search_feedback" json:"user_id"`
+	QueryHash  string          `gorm:"size:32;not null;uniqueIndex:idx_search_feedback" json:"-"` // 规范化查询的MD5，同一用户对同一查询同一文件只保留最新评价
+	Query      string          `gorm:"size:500;not null" json:"query"`
+	FileID     string          `gorm:"size:32;not null;uniqueIndex:idx_search_feedback;index" json:"file_id"`
+	Relevant   bool            `gorm:"not null;index" json:"relevant"`
+	Similarity float64         `gorm:"not null;default:0" json:"similarity"` // 评价时结果的向量相似度，关键词命中时为0
+	Rank       int             `gorm:"not null;default:0" json:"rank"`       // 结果在列表中的位置（从1开始）
+	Mode       string          `gorm:"size:20;not null;default:vector;index" json:"mode"`
+	Scope      string          `gorm:"size:20;not null;default:user" json:"scope"` // user/gallery
+	CreatedAt  common.JSONTime `json:"created_at"`
+	UpdatedAt  common.JSONTime `json:"updated_at"`
+}
+
+func (SearchFeedback) TableName() string {
+	return "search_feedback"
+}
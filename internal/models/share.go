@@ -1,9 +1,12 @@
 package models
 
 import (
+	"crypto/subtle"
 	"pixelpunk/pkg/common"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -19,17 +22,42 @@ type Share struct {
 	Description string `gorm:"type:text" json:"description"`
 	Password    string `gorm:"size:100" json:"-"` // 访问密码(可选)，不返回到前端
 
-	ExpiredDays int              `gorm:"default:0" json:"expired_days"` // 过期天数(0表示永不过期)
+	AccessMode string `gorm:"size:20;not null;default:'public'" json:"access_mode"` // 访问方式：public公开链接 users指定用户
+
+	StartsAt    *common.JSONTime `json:"starts_at"`                     // 开放时间，为空表示创建后立即可访问
+	ExpiredDays int              `gorm:"default:0" json:"expired_days"` // 过期天数(0表示永不过期)，设置了开放时间时从开放时间起算
 	ExpiredAt   *common.JSONTime `json:"expired_at"`                    // 计算得出的过期时间
 
+	ExpiryRemindedAt *common.JSONTime `json:"expiry_reminded_at"`     // 已发送过期提醒的时间，续期后清空
+	ExtendToken      string           `gorm:"size:32;index" json:"-"` // 提醒中一键续期链接的令牌，使用后失效
+
 	MaxViews     int `gorm:"default:0" json:"max_views"`     // 最大访问次数(0表示不限制)
 	CurrentViews int `gorm:"default:0" json:"current_views"` // 当前访问次数
 
+	MaxDownloads     int   `gorm:"default:0" json:"max_downloads"`      // 最大下载次数(0表示不限制)
+	DownloadCount    int   `gorm:"default:0" json:"download_count"`     // 已下载次数，打包下载计为一次
+	MaxDownloadBytes int64 `gorm:"default:0" json:"max_download_bytes"` // 最大下载流量(0表示不限制)
+	DownloadBytes    int64 `gorm:"default:0" json:"download_bytes"`     // 已下载流量
+
 	Status int `gorm:"default:1;index" json:"status"` // 状态：1正常 2已过期 3已删除 4已禁用
 
-	CollectVisitorInfo    bool `gorm:"default:false" json:"collect_visitor_info"`   // 是否收集访客信息
-	NotificationOnAccess  bool `gorm:"default:false" json:"notification_on_access"` // 是否在被访问时通知创建者
-	NotificationThreshold int  `gorm:"default:100" json:"notification_threshold"`   // 访问通知阈值，默认100次
+	ShareType            string `gorm:"size:20;not null;default:'view'" json:"share_type"` // 分享类型：view普通分享 collect收集型分享
+	UploadFolderID       string `gorm:"size:32" json:"upload_folder_id"`                   // 收集型分享中访客上传的目标文件夹
+	UploadMaxFileSizeMB  int    `gorm:"default:0" json:"upload_max_file_size_mb"`          // 单个文件大小上限(0表示仅受系统限制)
+	UploadMaxFiles       int    `gorm:"default:0" json:"upload_max_files"`                 // 最多接收文件数(0表示不限制)
+	UploadAllowedFormats string `gorm:"size:255" json:"upload_allowed_formats"`            // 允许的扩展名，逗号分隔，为空表示不限制
+	UploadModeration     bool   `gorm:"default:false" json:"upload_moderation"`            // 访客上传是否需要分享者审核后才公开
+	UploadCount          int    `gorm:"default:0" json:"upload_count"`                     // 已接收的文件数
+
+	SlideshowInterval int `gorm:"default:5" json:"slideshow_interval"` // 幻灯片模式默认每项停留秒数
+
+	WatermarkEnabled bool   `gorm:"default:false" json:"watermark_enabled"` // 访客下载时是否添加水印
+	WatermarkConfig  string `gorm:"type:text" json:"watermark_config"`      // 水印配置JSON，与上传水印格式一致
+
+	CollectVisitorInfo       bool `gorm:"default:false" json:"collect_visitor_info"`       // 是否收集访客信息
+	RequireEmailVerification bool `gorm:"default:false" json:"require_email_verification"` // 访问前是否需要访客验证邮箱
+	NotificationOnAccess     bool `gorm:"default:false" json:"notification_on_access"`     // 是否在被访问时通知创建者
+	NotificationThreshold    int  `gorm:"default:100" json:"notification_threshold"`       // 访问通知阈值，默认100次
 }
 
 func (Share) TableName() string {
@@ -37,7 +65,7 @@ func (Share) TableName() string {
 }
 
 func (s *Share) BeforeCreate(tx *gorm.DB) error {
-	if s.ExpiredDays > 0 {
+	if s.ExpiredDays > 0 && s.ExpiredAt == nil {
 		expiredAt := time.Now().AddDate(0, 0, s.ExpiredDays)
 		jsonTime := common.JSONTime(expiredAt)
 		s.ExpiredAt = &jsonTime
@@ -47,6 +75,14 @@ func (s *Share) BeforeCreate(tx *gorm.DB) error {
 		s.Status = common.ShareStatusNormal
 	}
 
+	if s.ShareType == "" {
+		s.ShareType = common.ShareTypeView
+	}
+
+	if s.AccessMode == "" {
+		s.AccessMode = common.ShareAccessModePublic
+	}
+
 	return nil
 }
 
@@ -58,6 +94,14 @@ func (s *Share) IsExpired() bool {
 	return time.Now().After(time.Time(*s.ExpiredAt))
 }
 
+/* IsStarted 判断分享是否已到开放时间 */
+func (s *Share) IsStarted() bool {
+	if s.StartsAt == nil {
+		return true
+	}
+	return !time.Now().Before(time.Time(*s.StartsAt))
+}
+
 /* IsNormal 判断分享是否处于正常状态 */
 func (s *Share) IsNormal() bool {
 	return s.Status == common.ShareStatusNormal && !s.IsExpired()
@@ -65,7 +109,7 @@ func (s *Share) IsNormal() bool {
 
 /* IsAccessible 判断分享是否可访问 */
 func (s *Share) IsAccessible() bool {
-	if !s.IsNormal() {
+	if !s.IsNormal() || !s.IsStarted() {
 		return false
 	}
 
@@ -73,14 +117,47 @@ func (s *Share) IsAccessible() bool {
 		return false
 	}
 
+	if s.IsDownloadQuotaExceeded() {
+		return false
+	}
+
 	return true
 }
 
-/* CanAccessWithPassword 校验密码是否正确 */
+/* IsDownloadQuotaExceeded 判断下载次数或下载流量是否已用尽 */
+func (s *Share) IsDownloadQuotaExceeded() bool {
+	if s.MaxDownloads > 0 && s.DownloadCount >= s.MaxDownloads {
+		return true
+	}
+	return s.MaxDownloadBytes > 0 && s.DownloadBytes >= s.MaxDownloadBytes
+}
+
+/* CanAccessWithPassword 校验密码是否正确，兼容尚未迁移的明文密码 */
 func (s *Share) CanAccessWithPassword(password string) bool {
 	if s.Password == "" {
 		return true
 	}
 
-	return s.Password == password
+	if s.IsPasswordHashed() {
+		return bcrypt.CompareHashAndPassword([]byte(s.Password), []byte(password)) == nil
+	}
+
+	return subtle.ConstantTimeCompare([]byte(s.Password), []byte(password)) == 1
+}
+
+/* IsPasswordHashed 判断密码是否已是bcrypt哈希 */
+func (s *Share) IsPasswordHashed() bool {
+	return strings.HasPrefix(s.Password, "$2a$") ||
+		strings.HasPrefix(s.Password, "$2b$") ||
+		strings.HasPrefix(s.Password, "$2y$")
+}
+
+/* IsCollect 判断是否为允许访客上传的收集型分享 */
+func (s *Share) IsCollect() bool {
+	return s.ShareType == common.ShareTypeCollect && s.UploadFolderID != ""
+}
+
+/* IsUserRestricted 判断是否为仅指定用户可访问的分享 */
+func (s *Share) IsUserRestricted() bool {
+	return s.AccessMode == common.ShareAccessModeUsers
 }
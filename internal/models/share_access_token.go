@@ -17,12 +17,19 @@ type ShareAccessToken struct {
 	ClientIP  string          `gorm:"size:50" json:"client_ip"`       // 客户端IP
 	UserAgent string          `gorm:"size:255" json:"user_agent"`     // 用户代理
 	IsAdmin   bool            `gorm:"default:false" json:"is_admin"`  // 是否为管理员令牌
+
+	RevokedAt *common.JSONTime `json:"revoked_at"` // 被分享者吊销的时间，吊销后立即失效
 }
 
 func (ShareAccessToken) TableName() string {
 	return "share_access_token"
 }
 
+/* IsRevoked 判断令牌是否已被吊销 */
+func (t *ShareAccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
 /* IsExpired 判断令牌是否已过期 */
 func (t *ShareAccessToken) IsExpired() bool {
 	return time.Now().After(time.Time(t.ExpiredAt))
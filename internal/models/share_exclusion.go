@@ -0,0 +1,18 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* ShareExclusion 整个文件夹分享时被排除、不对访客展示的文件 */
+type ShareExclusion struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+
+	ShareID string `gorm:"size:32;not null;uniqueIndex:idx_share_exclusion" json:"share_id"`
+	FileID  string `gorm:"size:32;not null;uniqueIndex:idx_share_exclusion" json:"file_id"`
+}
+
+func (ShareExclusion) TableName() string {
+	return "share_exclusion"
+}
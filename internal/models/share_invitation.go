@@ -0,0 +1,36 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+// 邀请邮件发送状态
+const (
+	ShareInvitationStatusPending = "pending"
+	ShareInvitationStatusSent    = "sent"
+	ShareInvitationStatusFailed  = "failed"
+)
+
+/* ShareInvitation 分享邀请，每个受邀邮箱持有独立令牌，用于按人统计打开和下载 */
+type ShareInvitation struct {
+	ID        string          `gorm:"primarykey;size:32" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+
+	ShareID string `gorm:"size:32;not null;index" json:"share_id"`
+	Email   string `gorm:"size:100;not null;index" json:"email"`
+	Token   string `gorm:"size:64;not null;uniqueIndex:idx_share_invitation_token" json:"-"`
+	Message string `gorm:"size:500" json:"message"` // 邀请附言
+
+	Status    string           `gorm:"size:20;default:'pending'" json:"status"` // 邮件发送状态：pending/sent/failed
+	SentAt    *common.JSONTime `json:"sent_at"`
+	RevokedAt *common.JSONTime `json:"revoked_at"` // 吊销后邀请链接失效
+
+	OpenCount     int              `gorm:"default:0" json:"open_count"`     // 通过邀请链接打开的次数
+	DownloadCount int              `gorm:"default:0" json:"download_count"` // 通过邀请链接下载的次数
+	FirstOpenedAt *common.JSONTime `json:"first_opened_at"`
+	LastOpenedAt  *common.JSONTime `json:"last_opened_at"`
+}
+
+func (ShareInvitation) TableName() string {
+	return "share_invitation"
+}
@@ -14,6 +14,9 @@ type ShareItem struct {
 
 	SortOrder int `gorm:"default:0" json:"sort_order"` // 排序顺序
 
+	Caption  string `gorm:"size:500" json:"caption"`   // 幻灯片模式下展示的说明文字
+	Duration int    `gorm:"default:0" json:"duration"` // 幻灯片停留秒数(0表示使用分享的默认间隔)
+
 	CreatedAt common.JSONTime `json:"created_at"` // 创建时间
 }
 
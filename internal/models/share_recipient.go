@@ -0,0 +1,20 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* ShareRecipient 指定用户分享的接收者 */
+type ShareRecipient struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+
+	ShareID string `gorm:"size:32;not null;uniqueIndex:idx_share_recipient" json:"share_id"`
+	UserID  uint   `gorm:"not null;uniqueIndex:idx_share_recipient;index" json:"user_id"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (ShareRecipient) TableName() string {
+	return "share_recipient"
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+// 分享模板的密码策略
+const (
+	SharePasswordPolicyNone   = "none"   // 不设密码
+	SharePasswordPolicyRandom = "random" // 每次创建随机生成密码
+	SharePasswordPolicyFixed  = "fixed"  // 使用模板中的固定密码
+)
+
+/* ShareTemplate 用户保存的分享默认设置，创建分享时一次性套用 */
+type ShareTemplate struct {
+	ID        string          `gorm:"primarykey;size:32" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	Name      string `gorm:"size:50;not null" json:"name"`
+	IsDefault bool   `gorm:"default:false" json:"is_default"` // 未指定模板时使用的默认模板
+
+	ExpiredDays      int   `gorm:"default:0" json:"expired_days"`
+	MaxViews         int   `gorm:"default:0" json:"max_views"`
+	MaxDownloads     int   `gorm:"default:0" json:"max_downloads"`
+	MaxDownloadBytes int64 `gorm:"default:0" json:"max_download_bytes"`
+
+	PasswordPolicy string `gorm:"size:20;not null;default:'none'" json:"password_policy"` // none/random/fixed
+	Password       string `gorm:"size:100" json:"-"`                                      // 固定密码的哈希
+
+	CollectVisitorInfo    bool `gorm:"default:false" json:"collect_visitor_info"`
+	NotificationOnAccess  bool `gorm:"default:false" json:"notification_on_access"`
+	NotificationThreshold int  `gorm:"default:100" json:"notification_threshold"`
+
+	WatermarkEnabled bool   `gorm:"default:false" json:"watermark_enabled"`
+	WatermarkConfig  string `gorm:"type:text" json:"watermark_config"` // 水印配置JSON，与上传水印格式一致
+}
+
+func (ShareTemplate) TableName() string {
+	return "share_template"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* ShareUpload 收集型分享中访客上传的文件记录 */
+type ShareUpload struct {
+	ID        string          `gorm:"primarykey;size:32" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	ShareID      string `gorm:"size:32;not null;index" json:"share_id"`                  // 关联的分享ID
+	FileID       string `gorm:"size:32;index" json:"file_id"`                            // 上传生成的文件ID
+	UploaderName string `gorm:"size:100" json:"uploader_name"`                           // 上传者留下的称呼
+	Message      string `gorm:"size:500" json:"message"`                                 // 上传者留言
+	IPAddress    string `gorm:"size:50" json:"ip_address"`                               // 上传者IP
+	UserAgent    string `gorm:"size:255" json:"user_agent"`                              // 用户代理
+	FileName     string `gorm:"size:255" json:"file_name"`                               // 原始文件名
+	FileSize     int64  `json:"file_size"`                                               // 文件大小
+	Status       string `gorm:"size:20;not null;default:'approved';index" json:"status"` // pending/approved/rejected
+
+	File *File `gorm:"foreignKey:FileID;references:ID" json:"file,omitempty"`
+}
+
+func (ShareUpload) TableName() string {
+	return "share_upload"
+}
@@ -18,6 +18,9 @@ type ShareVisitorInfo struct {
 	Referer      string          `gorm:"size:255" json:"referer"`        // 来源页面
 	VisitCount   int             `gorm:"default:1" json:"visit_count"`   // 访问次数
 	LastVisitAt  common.JSONTime `json:"last_visit_at"`                  // 最后访问时间
+
+	EmailVerified bool             `gorm:"default:false" json:"email_verified"` // 邮箱是否已通过验证码验证
+	VerifiedAt    *common.JSONTime `json:"verified_at"`                         // 邮箱验证时间
 }
 
 func (ShareVisitorInfo) TableName() string {
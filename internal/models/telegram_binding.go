@@ -0,0 +1,22 @@
+package models
+
+import "pixelpunk/pkg/common"
+
+/* TelegramBinding 用户绑定的 Telegram 会话，机器人收到该会话的图片后上传到用户账号 */
+type TelegramBinding struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	UserID           uint   `gorm:"not null;uniqueIndex:idx_telegram_binding_user" json:"user_id"`
+	ChatID           int64  `gorm:"not null;uniqueIndex:idx_telegram_binding_chat" json:"chat_id"`
+	TelegramUsername string `gorm:"size:64" json:"telegram_username"`
+	FolderID         string `gorm:"size:32" json:"folder_id"` // 上传目标文件夹，为空时上传到根目录
+
+	UploadCount  int              `gorm:"default:0" json:"upload_count"`
+	LastUploadAt *common.JSONTime `json:"last_upload_at"`
+}
+
+func (TelegramBinding) TableName() string {
+	return "telegram_binding"
+}
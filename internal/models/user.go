@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"pixelpunk/pkg/common"
 
 	"gorm.io/gorm"
@@ -25,9 +27,19 @@ type User struct {
 	GithubID  *int64  `gorm:"uniqueIndex:idx_user_github_id,sort:asc" json:"github_id"`
 	GoogleID  *string `gorm:"size:50;uniqueIndex:idx_user_google_id,sort:asc" json:"google_id"`
 	LinuxdoID *int64  `gorm:"uniqueIndex:idx_user_linuxdo_id,sort:asc" json:"linuxdo_id"`
+	LdapDN    *string `gorm:"size:255;uniqueIndex:idx_user_ldap_dn,sort:asc" json:"ldap_dn"` // LDAP账号的DN，非空表示由LDAP认证
+	SamlID    *string `gorm:"size:255;uniqueIndex:idx_user_saml_id,sort:asc" json:"saml_id"` // SAML断言中的NameID，非空表示已关联SSO账号
 
 	LastActivityAt *common.JSONTime `gorm:"column:last_activity_at" json:"last_activity_at"`
 	LastActivityIP string           `gorm:"size:45;column:last_activity_ip" json:"last_activity_ip"` // 支持IPv6
+
+	InvitationCodeID *uint `gorm:"index" json:"invitation_code_id"` // 注册时使用的邀请码
+	GroupID          *uint `gorm:"index" json:"group_id"`           // 所属用户组，决定配额档位
+
+	PendingEmail          string           `gorm:"size:100" json:"pending_email"` // 待验证的新邮箱，验证通过后替换当前邮箱
+	PendingEmailExpiresAt *common.JSONTime `json:"pending_email_expires_at"`      // 待验证邮箱的验证码过期时间
+
+	DeletionScheduledAt *common.JSONTime `gorm:"index" json:"deletion_scheduled_at"` // 申请注销后计划删除数据的时间，冷静期内可撤销
 }
 
 func (User) TableName() string {
@@ -54,6 +66,11 @@ func (u *User) IsSuperAdmin() bool {
 	return u.Role == common.UserRoleSuperAdmin
 }
 
+/* CanReview 是否可以审核内容（管理员或审核员） */
+func (u *User) CanReview() bool {
+	return u.IsAdmin() || u.Role == common.UserRoleReviewer
+}
+
 /* IsNormal 是否是正常状态 */
 func (u *User) IsNormal() bool {
 	return u.Status == common.UserStatusNormal
@@ -67,3 +84,13 @@ func (u *User) IsDisabled() bool {
 func (u *User) IsDeleted() bool {
 	return u.Status == common.UserStatusDeleted
 }
+
+/* IsDeletionScheduled 是否已申请注销且处于冷静期 */
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+/* HasPendingEmail 是否有未过期的待验证邮箱 */
+func (u *User) HasPendingEmail() bool {
+	return u.PendingEmail != "" && u.PendingEmailExpiresAt != nil && time.Now().Before(time.Time(*u.PendingEmailExpiresAt))
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* UserGroup 用户组，承载一组配额档位；组内配置优先于用户单独设置的限额 */
+type UserGroup struct {
+	ID          uint            `gorm:"primarykey" json:"id"`
+	CreatedAt   common.JSONTime `json:"created_at"`
+	UpdatedAt   common.JSONTime `json:"updated_at"`
+	Name        string          `gorm:"size:50;not null;uniqueIndex:idx_user_group_name" json:"name"`
+	Description string          `gorm:"size:255" json:"description"`
+
+	StorageLimit     int64 `gorm:"default:0" json:"storage_limit"`      // 存储空间（字节），0表示沿用用户设置
+	BandwidthLimit   int64 `gorm:"default:0" json:"bandwidth_limit"`    // 每月流量（字节），0表示沿用用户设置
+	DailyUploadLimit int   `gorm:"default:0" json:"daily_upload_limit"` // 每日上传数，0表示沿用全局设置，-1表示不限制
+	DailyAILimit     int   `gorm:"default:0" json:"daily_ai_limit"`     // 每日AI处理文件数，0表示不限制，-1表示禁用AI处理
+}
+
+func (UserGroup) TableName() string {
+	return "user_group"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* UserLoginFootprint 用户登录过的设备与国家组合，用于识别新设备或新地区的异常登录 */
+type UserLoginFootprint struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	UserID            uint   `gorm:"not null;uniqueIndex:idx_login_footprint" json:"user_id"`
+	DeviceFingerprint string `gorm:"size:32;not null;uniqueIndex:idx_login_footprint" json:"device_fingerprint"` // 浏览器+操作系统生成的设备指纹
+	Country           string `gorm:"size:2;not null;default:'';uniqueIndex:idx_login_footprint" json:"country"`  // GeoIP国家代码，未配置GeoIP时为空
+	DeviceName        string `gorm:"size:100" json:"device_name"`
+	LastIP            string `gorm:"size:45" json:"last_ip"`
+	LoginCount        int    `gorm:"default:0" json:"login_count"`
+
+	LastLoginAt common.JSONTime `json:"last_login_at"`
+
+	LockToken          string           `gorm:"size:64;index" json:"-"` // 异常登录提醒中"不是我本人"链接的令牌，使用后失效
+	LockTokenExpiresAt *common.JSONTime `json:"-"`
+}
+
+func (UserLoginFootprint) TableName() string {
+	return "user_login_footprint"
+}
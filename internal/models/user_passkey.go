@@ -0,0 +1,31 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* UserPasskey 用户通行密钥（WebAuthn凭据），每台设备一条记录 */
+type UserPasskey struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	UserID          uint   `gorm:"not null;index" json:"user_id"`
+	Name            string `gorm:"size:100" json:"name"`                               // 设备名称，由用户自定义
+	CredentialID    string `gorm:"size:255;not null;uniqueIndex" json:"credential_id"` // 凭据ID（base64url）
+	PublicKey       []byte `json:"-"`                                                  // COSE编码的公钥
+	AttestationType string `gorm:"size:32" json:"attestation_type"`                    // 注册时的证明格式
+	AAGUID          string `gorm:"size:36" json:"aaguid"`                              // 认证器型号标识
+	Transports      string `gorm:"size:100" json:"transports"`                         // 支持的传输方式，逗号分隔
+	SignCount       uint32 `gorm:"default:0" json:"-"`                                 // 签名计数器，用于检测克隆
+	BackupEligible  bool   `gorm:"default:false" json:"backup_eligible"`               // 是否可同步备份
+	BackupState     bool   `gorm:"default:false" json:"backup_state"`                  // 是否已同步备份
+	CloneWarning    bool   `gorm:"default:false" json:"clone_warning"`                 // 签名计数器回退，可能存在克隆设备
+	LastUsedIP      string `gorm:"size:45" json:"last_used_ip"`                        // 最后使用IP
+
+	LastUsedAt *common.JSONTime `json:"last_used_at"`
+}
+
+func (UserPasskey) TableName() string {
+	return "user_passkey"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"pixelpunk/pkg/common"
+)
+
+// 配额类型
+const (
+	QuotaTypeStorage   = "storage"   // 存储空间（字节）
+	QuotaTypeBandwidth = "bandwidth" // 每月流量（字节）
+	QuotaTypeUpload    = "upload"    // 每日上传数
+)
+
+// 配额调整记录状态（由时间和撤销信息推导，不落库）
+const (
+	QuotaGrantStatusActive  = "active"
+	QuotaGrantStatusExpired = "expired"
+	QuotaGrantStatusRevoked = "revoked"
+)
+
+/* UserQuotaGrant 管理员为用户追加的配额，叠加在用户组或用户自身限额之上，同时作为调整历史 */
+type UserQuotaGrant struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	UserID    uint             `gorm:"not null;index:idx_quota_grant_user" json:"user_id"`
+	QuotaType string           `gorm:"size:20;not null;index:idx_quota_grant_user" json:"quota_type"` // storage, bandwidth, upload
+	Amount    int64            `gorm:"not null" json:"amount"`                                        // 追加量，存储/流量为字节，上传为次数
+	Reason    string           `gorm:"size:255;not null" json:"reason"`
+	ExpiresAt *common.JSONTime `gorm:"index" json:"expires_at"` // 到期时间，为空表示永久有效
+	GrantedBy uint             `gorm:"index" json:"granted_by"`
+
+	RevokedAt    *common.JSONTime `json:"revoked_at"`
+	RevokedBy    uint             `json:"revoked_by"`
+	RevokeReason string           `gorm:"size:255" json:"revoke_reason"`
+
+	User    *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Granter *User `gorm:"foreignKey:GrantedBy;references:ID" json:"granter,omitempty"`
+}
+
+func (UserQuotaGrant) TableName() string {
+	return "user_quota_grant"
+}
+
+/* Status 当前状态 */
+func (g *UserQuotaGrant) Status() string {
+	if g.RevokedAt != nil {
+		return QuotaGrantStatusRevoked
+	}
+	if g.ExpiresAt != nil && !time.Now().Before(time.Time(*g.ExpiresAt)) {
+		return QuotaGrantStatusExpired
+	}
+	return QuotaGrantStatusActive
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+	"time"
+)
+
+/* UserSession 登录会话，每次登录签发的JWT对应一条记录，jti 即会话ID */
+type UserSession struct {
+	ID        string          `gorm:"primarykey;size:32" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+
+	UserID      uint            `gorm:"not null;index" json:"user_id"`
+	LoginMethod string          `gorm:"size:20" json:"login_method"` // 登录方式：password/passkey/oauth/setup
+	DeviceName  string          `gorm:"size:100" json:"device_name"` // 根据UA解析的设备描述
+	UserAgent   string          `gorm:"size:255" json:"user_agent"`  // 用户代理
+	ClientIP    string          `gorm:"size:45" json:"client_ip"`    // 登录IP，支持IPv6
+	LastSeenIP  string          `gorm:"size:45" json:"last_seen_ip"` // 最后活跃IP
+	LastSeenAt  common.JSONTime `json:"last_seen_at"`                // 最后活跃时间
+	ExpiresAt   common.JSONTime `gorm:"index" json:"expires_at"`     // 与JWT过期时间一致
+
+	RevokedAt *common.JSONTime `json:"revoked_at"` // 吊销时间，吊销后对应JWT立即失效
+}
+
+func (UserSession) TableName() string {
+	return "user_session"
+}
+
+/* IsRevoked 判断会话是否已被吊销 */
+func (s *UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+/* IsExpired 判断会话是否已过期 */
+func (s *UserSession) IsExpired() bool {
+	return time.Now().After(time.Time(s.ExpiresAt))
+}
@@ -14,6 +14,9 @@ type UserSettings struct {
 	BandwidthLimit     int64           `gorm:"not null;default:107374182400" json:"bandwidth_limit"` // 默认1GB
 	DefaultAccessLevel string          `gorm:"size:20;not null;default:private" json:"default_access_level"`
 	OptimizeImages     bool            `gorm:"not null;default:false" json:"optimize_files"`
+	AIEnabled          bool            `gorm:"not null;default:true" json:"ai_enabled"` // 是否允许上传文件发送给AI处理
+	AIWebhookURL       string          `gorm:"size:500" json:"ai_webhook_url"`          // AI处理完成回调地址
+	AIWebhookSecret    string          `gorm:"size:64" json:"-"`                        // 回调签名密钥
 	CreatedAt          common.JSONTime `json:"created_at"`
 	UpdatedAt          common.JSONTime `json:"updated_at"`
 }
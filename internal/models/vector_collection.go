@@ -0,0 +1,39 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* VectorCollection 向量集合版本，记录每个集合使用的向量化模型及维度 */
+type VectorCollection struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	Backend   string `gorm:"size:20;not null;uniqueIndex:idx_vector_collection_version" json:"backend"`
+	Version   int    `gorm:"not null;uniqueIndex:idx_vector_collection_version" json:"version"`
+	Provider  string `gorm:"size:20;not null" json:"provider"`
+	Model     string `gorm:"size:100;not null" json:"model"`
+	Dimension int    `gorm:"not null;default:0" json:"dimension"`
+	Status    string `gorm:"size:20;not null;default:building;index" json:"status"` // building/active/retired/failed
+
+	Total     int64  `gorm:"default:0" json:"total"`
+	Processed int64  `gorm:"default:0" json:"processed"`
+	Failed    int64  `gorm:"default:0" json:"failed"`
+	Error     string `gorm:"type:text" json:"error,omitempty"`
+
+	PayloadVersion int `gorm:"default:0" json:"payload_version"` // 向量附带的文件元数据版本，低于当前版本时不能依赖存储层过滤
+
+	ActivatedAt *common.JSONTime `gorm:"type:timestamp" json:"activated_at,omitempty"`
+	CreatedAt   common.JSONTime  `json:"created_at"`
+	UpdatedAt   common.JSONTime  `json:"updated_at"`
+}
+
+/* 向量集合状态 */
+const (
+	VectorCollectionBuilding = "building"
+	VectorCollectionActive   = "active"
+	VectorCollectionRetired  = "retired"
+	VectorCollectionFailed   = "failed"
+)
+
+func (VectorCollection) TableName() string {
+	return "vector_collection"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"pixelpunk/pkg/common"
+)
+
+/* VectorDuplicateCandidate 基于向量相似度发现的近似重复文件对 */
+type VectorDuplicateCandidate struct {
+	ID              uint            `gorm:"primarykey" json:"id"`
+	UserID          uint            `gorm:"index;not null" json:"user_id"`
+	FileID          string          `gorm:"size:32;not null;uniqueIndex:idx_dup_pair" json:"file_id"`           // 文件对中ID较小的一方
+	DuplicateFileID string          `gorm:"size:32;not null;uniqueIndex:idx_dup_pair" json:"duplicate_file_id"` // 文件对中ID较大的一方
+	Similarity      float64         `gorm:"not null;default:0" json:"similarity"`
+	Status          string          `gorm:"size:20;not null;default:pending;index" json:"status"` // pending/ignored/resolved
+	ScannedAt       common.JSONTime `json:"scanned_at"`
+	CreatedAt       common.JSONTime `json:"created_at"`
+	UpdatedAt       common.JSONTime `json:"updated_at"`
+}
+
+/* 近似重复候选状态 */
+const (
+	DuplicateStatusPending  = "pending"
+	DuplicateStatusIgnored  = "ignored"
+	DuplicateStatusResolved = "resolved"
+)
+
+func (VectorDuplicateCandidate) TableName() string {
+	return "vector_duplicate_candidate"
+}
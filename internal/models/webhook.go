@@ -0,0 +1,88 @@
+package models
+
+import (
+	"strings"
+
+	"pixelpunk/pkg/common"
+)
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivering = "delivering"
+	WebhookDeliveryRetrying   = "retrying"
+	WebhookDeliverySuccess    = "success"
+	WebhookDeliveryFailed     = "failed" // 重试次数用尽
+)
+
+// WebhookAllEvents 订阅全部事件
+const WebhookAllEvents = "*"
+
+/* Webhook 管理员配置的事件回调地址，事件发生时以 HMAC 签名的 JSON 推送 */
+type Webhook struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	Name        string `gorm:"size:100;not null" json:"name"`
+	URL         string `gorm:"size:500;not null" json:"url"`
+	Secret      string `gorm:"size:64" json:"-"`
+	Events      string `gorm:"type:text" json:"-"` // 订阅的事件，逗号分隔，* 表示全部
+	Enabled     bool   `gorm:"default:true;index" json:"enabled"`
+	Description string `gorm:"size:255" json:"description"`
+	CreatedBy   uint   `gorm:"index" json:"created_by"`
+
+	LastDeliveryAt      *common.JSONTime `json:"last_delivery_at"`
+	LastStatus          string           `gorm:"size:20" json:"last_status"`
+	ConsecutiveFailures int              `gorm:"default:0" json:"consecutive_failures"`
+}
+
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+/* EventList 订阅的事件列表 */
+func (w *Webhook) EventList() []string {
+	events := make([]string, 0)
+	for _, e := range strings.Split(w.Events, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+/* Subscribes 是否订阅了指定事件 */
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.EventList() {
+		if e == WebhookAllEvents || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+/* WebhookDelivery 单次事件投递记录，失败时按退避时间重试 */
+type WebhookDelivery struct {
+	ID        uint            `gorm:"primarykey" json:"id"`
+	CreatedAt common.JSONTime `json:"created_at"`
+	UpdatedAt common.JSONTime `json:"updated_at"`
+
+	WebhookID uint   `gorm:"not null;index" json:"webhook_id"`
+	EventID   string `gorm:"size:32;index" json:"event_id"` // 同一事件投递到多个 Webhook 时相同
+	Event     string `gorm:"size:100;index" json:"event"`
+	Payload   string `gorm:"type:text" json:"payload,omitempty"`
+
+	Status         string           `gorm:"size:20;not null;index:idx_webhook_delivery_due" json:"status"`
+	Attempts       int              `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  *common.JSONTime `gorm:"index:idx_webhook_delivery_due" json:"next_attempt_at"`
+	ResponseStatus int              `json:"response_status"`
+	ResponseBody   string           `gorm:"type:text" json:"response_body,omitempty"`
+	Error          string           `gorm:"size:500" json:"error,omitempty"`
+	DurationMs     int64            `json:"duration_ms"`
+	DeliveredAt    *common.JSONTime `json:"delivered_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
@@ -87,7 +87,7 @@ func (q *DBQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc, e
 	leaseUntil := now.Add(lease)
 
 	var candidate models.AIJob
-	if err := q.db.Where("(status = ? OR (status = ? AND lease_until < ?))", "queued", "processing", now).
+	if err := q.db.Where("((status = ? AND (lease_until IS NULL OR lease_until <= ?)) OR (status = ? AND lease_until < ?))", "queued", now, "processing", now).
 		Order("priority DESC, created_at ASC").
 		Take(&candidate).Error; err != nil {
 		return nil, nil, nil, err
@@ -95,12 +95,12 @@ func (q *DBQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc, e
 
 	// 只有当任务状态未被其他 Worker 改变时才会更新成功
 	result := q.db.Model(&models.AIJob{}).
-		Where("id = ? AND (status = ? OR (status = ? AND lease_until < ?))",
-			candidate.ID, "queued", "processing", now).
+		Where("id = ? AND ((status = ? AND (lease_until IS NULL OR lease_until <= ?)) OR (status = ? AND lease_until < ?))",
+			candidate.ID, "queued", now, "processing", now).
 		Updates(map[string]interface{}{
 			"status":      "processing",
 			"lease_until": leaseUntil,
-			"lease_by":    "tagger",
+			"lease_by":    leaseOwner("tagger"),
 		})
 
 	if result.Error != nil {
@@ -126,7 +126,8 @@ func (q *DBQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc, e
 	nack := func(delay time.Duration, toDLQ bool, lastError string) error {
 		if toDLQ {
 			return q.db.Model(&models.AIJob{}).Where("id = ?", picked.ID).Updates(map[string]interface{}{
-				"status":      "failed",
+				"status":      "dead",
+				"tries":       gorm.Expr("tries + 1"),
 				"last_error":  lastError,
 				"lease_until": gorm.Expr("NULL"),
 				"lease_by":    "",
@@ -145,6 +146,23 @@ func (q *DBQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc, e
 	return task, ack, nack, nil
 }
 
+/* RequeueDead 将死信任务清零重试次数后以指定优先级重新入队，返回重新入队的数量 */
+func (q *DBQueue) RequeueDead(ids []string, priority int) (int, error) {
+	if q.db == nil {
+		return 0, errors.New("db not initialized")
+	}
+	result := q.db.Model(&models.AIJob{}).
+		Where("file_id IN ? AND status = ?", ids, "dead").
+		Updates(map[string]interface{}{
+			"status":      "queued",
+			"tries":       0,
+			"priority":    priority,
+			"lease_until": gorm.Expr("NULL"),
+			"lease_by":    "",
+		})
+	return int(result.RowsAffected), result.Error
+}
+
 func (q *DBQueue) Metrics() (*Metrics, error) {
 	if q.db == nil {
 		return nil, errors.New("db not initialized")
@@ -160,7 +178,7 @@ func (q *DBQueue) Metrics() (*Metrics, error) {
 	if err := q.db.Model(&models.AIJob{}).Where("status = ? AND lease_until > ?", "queued", now).Count(&delayed).Error; err != nil {
 		return nil, err
 	}
-	if err := q.db.Model(&models.AIJob{}).Where("status = ?", "failed").Count(&dlq).Error; err != nil {
+	if err := q.db.Model(&models.AIJob{}).Where("status = ?", "dead").Count(&dlq).Error; err != nil {
 		return nil, err
 	}
 	return &Metrics{QueueLength: int(queued), InFlight: int(processing), DelayedCount: int(delayed), DLQCount: int(dlq)}, nil
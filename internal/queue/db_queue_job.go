@@ -0,0 +1,158 @@
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// DBJobQueue 使用 background_job 表的通用任务队列，按任务类型区分，完成的任务直接删除
+type DBJobQueue struct {
+	db   *gorm.DB
+	kind string
+}
+
+func NewDBJobQueue(kind string) *DBJobQueue {
+	return &DBJobQueue{db: database.GetDB(), kind: kind}
+}
+
+func (q *DBJobQueue) EnqueueUnique(id string, priority int) error {
+	return q.enqueue(id, priority, nil)
+}
+
+// EnqueueAt 计划在 runAt 执行，任务已在队列中时只提前不推后
+func (q *DBJobQueue) EnqueueAt(id string, runAt time.Time) error {
+	return q.enqueue(id, PriorityNormal, &runAt)
+}
+
+// enqueue 幂等入队：排队或处理中的任务不重复入队，死信任务需通过 RequeueDead 重新入队
+func (q *DBJobQueue) enqueue(id string, priority int, runAt *time.Time) error {
+	if q.db == nil {
+		return errors.New("db not initialized")
+	}
+	var existing models.BackgroundJob
+	err := q.db.Where("kind = ? AND job_id = ?", q.kind, id).Take(&existing).Error
+	if err == nil {
+		if existing.Status != "queued" {
+			return nil
+		}
+		updates := map[string]interface{}{}
+		if priority > existing.Priority {
+			updates["priority"] = priority
+		}
+		if existing.LeaseUntil != nil && (runAt == nil || runAt.Before(*existing.LeaseUntil)) {
+			updates["lease_until"] = runAt
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return q.db.Model(&models.BackgroundJob{}).Where("id = ? AND status = ?", existing.ID, "queued").Updates(updates).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	job := models.BackgroundJob{Kind: q.kind, JobID: id, Status: "queued", Priority: priority, LeaseUntil: runAt}
+	if err := q.db.Create(&job).Error; err != nil {
+		// 唯一索引冲突说明其他实例已入队
+		var count int64
+		if q.db.Model(&models.BackgroundJob{}).Where("kind = ? AND job_id = ?", q.kind, id).Count(&count).Error == nil && count > 0 {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (q *DBJobQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc, error) {
+	if q.db == nil {
+		return nil, nil, nil, errors.New("db not initialized")
+	}
+
+	now := time.Now()
+	ready := "kind = ? AND ((status = ? AND (lease_until IS NULL OR lease_until <= ?)) OR (status = ? AND lease_until < ?))"
+	var candidate models.BackgroundJob
+	if err := q.db.Where(ready, q.kind, "queued", now, "processing", now).
+		Order("priority DESC, created_at ASC").Take(&candidate).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	result := q.db.Model(&models.BackgroundJob{}).
+		Where("id = ? AND "+ready, candidate.ID, q.kind, "queued", now, "processing", now).
+		Updates(map[string]interface{}{
+			"status":      "processing",
+			"lease_until": now.Add(lease),
+			"lease_by":    leaseOwner(q.kind),
+		})
+	if result.Error != nil {
+		return nil, nil, nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil, nil, gorm.ErrRecordNotFound
+	}
+
+	picked := candidate
+	task := &TaggingTask{FileID: picked.JobID, Attempts: picked.Attempts}
+	ack := func() error {
+		return q.db.Where("id = ? AND status = ?", picked.ID, "processing").Delete(&models.BackgroundJob{}).Error
+	}
+	nack := func(delay time.Duration, toDLQ bool, lastError string) error {
+		updates := map[string]interface{}{
+			"status":      "queued",
+			"attempts":    gorm.Expr("attempts + 1"),
+			"last_error":  lastError,
+			"lease_until": time.Now().Add(delay),
+			"lease_by":    "",
+		}
+		if toDLQ {
+			updates["status"] = "dead"
+			updates["lease_until"] = gorm.Expr("NULL")
+		}
+		return q.db.Model(&models.BackgroundJob{}).Where("id = ?", picked.ID).Updates(updates).Error
+	}
+	return task, ack, nack, nil
+}
+
+/* RequeueDead 将死信任务清零重试次数后以指定优先级重新入队，返回重新入队的数量 */
+func (q *DBJobQueue) RequeueDead(ids []string, priority int) (int, error) {
+	if q.db == nil {
+		return 0, errors.New("db not initialized")
+	}
+	result := q.db.Model(&models.BackgroundJob{}).
+		Where("kind = ? AND job_id IN ? AND status = ?", q.kind, ids, "dead").
+		Updates(map[string]interface{}{
+			"status":      "queued",
+			"attempts":    0,
+			"priority":    priority,
+			"last_error":  "",
+			"lease_until": gorm.Expr("NULL"),
+		})
+	return int(result.RowsAffected), result.Error
+}
+
+func (q *DBJobQueue) Metrics() (*Metrics, error) {
+	if q.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	var queued, processing, delayed, dlq int64
+	now := time.Now()
+	base := func() *gorm.DB { return q.db.Model(&models.BackgroundJob{}).Where("kind = ?", q.kind) }
+	if err := base().Where("status = ?", "queued").Count(&queued).Error; err != nil {
+		return nil, err
+	}
+	if err := base().Where("status = ?", "processing").Count(&processing).Error; err != nil {
+		return nil, err
+	}
+	if err := base().Where("status = ? AND lease_until > ?", "queued", now).Count(&delayed).Error; err != nil {
+		return nil, err
+	}
+	if err := base().Where("status = ?", "dead").Count(&dlq).Error; err != nil {
+		return nil, err
+	}
+	return &Metrics{QueueLength: int(queued), InFlight: int(processing), DelayedCount: int(delayed), DLQCount: int(dlq)}, nil
+}
+
+func (q *DBJobQueue) Close() error { return nil }
@@ -45,18 +45,18 @@ func (q *DBQueueVector) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackF
 	var candidate models.VectorJob
 	now := time.Now()
 
-	if err := q.db.Where("(status = ? OR (status = ? AND lease_until < ?))", "queued", "processing", now).
+	if err := q.db.Where("((status = ? AND (lease_until IS NULL OR lease_until <= ?)) OR (status = ? AND lease_until < ?))", "queued", now, "processing", now).
 		Order("priority DESC, created_at ASC").Take(&candidate).Error; err != nil {
 		return nil, nil, nil, err
 	}
 
 	leaseUntil := now.Add(lease)
 	result := q.db.Model(&models.VectorJob{}).
-		Where("id = ? AND (status = ? OR (status = ? AND lease_until < ?))", candidate.ID, "queued", "processing", now).
+		Where("id = ? AND ((status = ? AND (lease_until IS NULL OR lease_until <= ?)) OR (status = ? AND lease_until < ?))", candidate.ID, "queued", now, "processing", now).
 		Updates(map[string]interface{}{
 			"status":      "processing",
 			"lease_until": leaseUntil,
-			"lease_by":    "vector",
+			"lease_by":    leaseOwner("vector"),
 		})
 
 	if result.Error != nil {
@@ -88,6 +88,24 @@ func (q *DBQueueVector) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackF
 	return task, ack, nack, nil
 }
 
+/* RequeueDead 将进入死信（failed）的向量任务清零重试次数后以指定优先级重新入队，返回重新入队的数量 */
+func (q *DBQueueVector) RequeueDead(ids []string, priority int) (int, error) {
+	if q.db == nil {
+		return 0, errors.New("db not initialized")
+	}
+	result := q.db.Model(&models.VectorJob{}).
+		Where("file_id IN ? AND status = ?", ids, "failed").
+		Updates(map[string]interface{}{
+			"status":      "queued",
+			"attempt":     0,
+			"priority":    priority,
+			"last_error":  "",
+			"lease_until": gorm.Expr("NULL"),
+			"lease_by":    "",
+		})
+	return int(result.RowsAffected), result.Error
+}
+
 func (q *DBQueueVector) Metrics() (*Metrics, error) {
 	if q.db == nil {
 		return nil, errors.New("db not initialized")
@@ -0,0 +1,285 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+)
+
+// 通用任务类型
+const (
+	KindThumbnail = "thumbnail" // 缩略图生成失败后的重新生成
+	KindCleanup   = "cleanup"   // 定时清理任务
+)
+
+const (
+	defaultJobLease = 5 * time.Minute
+	jobPollInterval = time.Second
+)
+
+// Handler 通用任务处理函数，id 为入队时的任务ID；返回错误时按重试策略延迟重试，重试耗尽后进入死信
+type Handler func(id string) error
+
+// JobSpec 通用后台任务定义，入队、定时调度、重试、死信、指标与后台列表由任务框架处理，服务只提供处理函数
+type JobSpec struct {
+	Kind        string
+	Title       string
+	Handler     Handler
+	Policy      func() RetryPolicy
+	Concurrency int           // 每个实例的并发数，默认 1
+	Lease       time.Duration // 任务租约，超时未确认的任务会被重新取出，默认 5 分钟
+}
+
+// jobRunner 一类通用任务的队列与消费者，启用 Redis 时队列保存在 Redis 中，否则保存在 background_job 表
+type jobRunner struct {
+	spec    JobSpec
+	tracker *Tracker
+
+	mu    sync.Mutex
+	queue Queue
+
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	reaperStop chan struct{}
+}
+
+var (
+	runnersMu sync.RWMutex
+	runners   = make(map[string]*jobRunner)
+)
+
+/* Define 定义一类通用任务并注册到任务注册表，通常在服务包的 init 中调用，StartJobs 后开始消费 */
+func Define(spec JobSpec) {
+	if spec.Concurrency < 1 {
+		spec.Concurrency = 1
+	}
+	if spec.Lease <= 0 {
+		spec.Lease = defaultJobLease
+	}
+	r := &jobRunner{spec: spec, tracker: NewTracker(spec.Kind)}
+
+	runnersMu.Lock()
+	runners[spec.Kind] = r
+	runnersMu.Unlock()
+
+	Register(&Descriptor{
+		Kind:    spec.Kind,
+		Title:   spec.Title,
+		Queue:   r.currentQueue,
+		Policy:  r.policy,
+		Requeue: r.requeueDead,
+	})
+}
+
+func lookupRunner(kind string) (*jobRunner, error) {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+	r, ok := runners[kind]
+	if !ok {
+		return nil, errors.New(errors.CodeNotFound, "任务类型不存在")
+	}
+	return r, nil
+}
+
+/* Enqueue 将任务加入指定类型的队列，同一任务ID在排队或处理中时不重复入队 */
+func Enqueue(kind, id string, priority int) error {
+	r, err := lookupRunner(kind)
+	if err != nil {
+		return err
+	}
+	q := r.currentQueue()
+	if q == nil {
+		return errors.New(errors.CodeServiceUnavailable, "队列未初始化")
+	}
+	return q.EnqueueUnique(id, priority)
+}
+
+/* Schedule 计划在 runAt 执行任务，runAt 不晚于当前时间时立即入队 */
+func Schedule(kind, id string, runAt time.Time) error {
+	if !runAt.After(time.Now()) {
+		return Enqueue(kind, id, PriorityNormal)
+	}
+	r, err := lookupRunner(kind)
+	if err != nil {
+		return err
+	}
+	q, ok := r.currentQueue().(interface {
+		EnqueueAt(id string, runAt time.Time) error
+	})
+	if !ok {
+		return errors.New(errors.CodeServiceUnavailable, "队列未初始化")
+	}
+	return q.EnqueueAt(id, runAt)
+}
+
+// currentQueue 首次使用时按是否启用 Redis 创建队列，数据库未就绪时返回 nil 并在下次重试
+func (r *jobRunner) currentQueue() Queue {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.queue != nil {
+		return r.queue
+	}
+	if cache.IsRedisEnabled() {
+		if rq := NewRedisJobQueue(r.spec.Kind); rq != nil {
+			r.queue = rq
+			return r.queue
+		}
+	}
+	if database.GetDB() != nil {
+		r.queue = NewDBJobQueue(r.spec.Kind)
+	}
+	return r.queue
+}
+
+func (r *jobRunner) policy() RetryPolicy {
+	if r.spec.Policy != nil {
+		return r.spec.Policy().Normalize()
+	}
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+func (r *jobRunner) requeueDead(ids []string) (int, error) {
+	q, ok := r.currentQueue().(DeadRequeuer)
+	if !ok {
+		return 0, errors.New(errors.CodeServiceUnavailable, "队列未初始化")
+	}
+	n, err := q.RequeueDead(ids, PriorityHigh)
+	if err != nil {
+		return n, errors.Wrap(err, errors.CodeInternal, "重新入队失败")
+	}
+	return n, nil
+}
+
+func (r *jobRunner) start() {
+	if r.cancel != nil {
+		return
+	}
+	q := r.currentQueue()
+	if q == nil {
+		logger.Warn("任务 %s 的队列初始化失败，未启动消费", r.spec.Kind)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	if rq, ok := q.(*RedisQueue); ok {
+		r.reaperStop = make(chan struct{})
+		rq.StartReaper(time.Second, r.reaperStop)
+	}
+	for i := 0; i < r.spec.Concurrency; i++ {
+		r.wg.Add(1)
+		go r.worker(ctx, q)
+	}
+}
+
+func (r *jobRunner) worker(ctx context.Context, q Queue) {
+	defer r.wg.Done()
+	for ctx.Err() == nil {
+		task, ack, nack, err := q.Fetch(r.spec.Lease)
+		if err != nil || task == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(jobPollInterval):
+			}
+			continue
+		}
+		ack, nack = r.tracker.Wrap(task.FileID, ack, nack)
+		r.process(task, ack, nack)
+	}
+}
+
+// process 执行任务，失败时按第几次失败计算重试延迟，达到最大尝试次数后进入死信
+func (r *jobRunner) process(task *TaggingTask, ack AckFunc, nack NackFunc) {
+	err := r.handle(task.FileID)
+	if err == nil {
+		if e := ack(); e != nil {
+			logger.Warn("任务 %s/%s 确认失败: %v", r.spec.Kind, task.FileID, e)
+		}
+		return
+	}
+
+	failures := task.Attempts + 1
+	policy := r.policy()
+	if policy.Exhausted(failures) {
+		logger.Warn("任务 %s/%s 失败 %d 次，进入死信: %v", r.spec.Kind, task.FileID, failures, err)
+		err = nack(0, true, err.Error())
+	} else {
+		err = nack(policy.Delay(failures), false, err.Error())
+	}
+	if err != nil {
+		logger.Warn("任务 %s/%s 失败处理出错: %v", r.spec.Kind, task.FileID, err)
+	}
+}
+
+func (r *jobRunner) handle(id string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logger.Error("任务 %s/%s 执行异常: %v", r.spec.Kind, id, p)
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return r.spec.Handler(id)
+}
+
+// stop 停止取新任务并等待进行中的任务完成，ctx 到期时将未完成的任务放回队列
+func (r *jobRunner) stop(ctx context.Context) {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if ids := r.tracker.Checkpoint("service shutdown"); len(ids) > 0 {
+			logger.Warn("任务 %s 未在关闭时限内完成，已将 %d 个任务放回队列", r.spec.Kind, len(ids))
+		}
+	}
+
+	if r.reaperStop != nil {
+		close(r.reaperStop)
+		r.reaperStop = nil
+	}
+	r.cancel = nil
+}
+
+func allRunners() []*jobRunner {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+	list := make([]*jobRunner, 0, len(runners))
+	for _, r := range runners {
+		list = append(list, r)
+	}
+	return list
+}
+
+/* StartJobs 启动所有通用任务的消费者，需在数据库与缓存初始化之后调用，已启动的任务类型不重复启动 */
+func StartJobs() {
+	for _, r := range allRunners() {
+		r.start()
+	}
+}
+
+/* StopJobs 停止所有通用任务的消费者，ctx 到期时未完成的任务放回队列 */
+func StopJobs(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range allRunners() {
+		wg.Add(1)
+		go func(r *jobRunner) {
+			defer wg.Done()
+			r.stop(ctx)
+		}(r)
+	}
+	wg.Wait()
+}
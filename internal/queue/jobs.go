@@ -0,0 +1,290 @@
+package queue
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/metrics"
+	"pixelpunk/pkg/errors"
+)
+
+// 任务类型
+const (
+	KindAITagging = "ai_tagging"
+	KindVector    = "vector"
+)
+
+// 任务优先级，数值越大越先处理
+const (
+	PriorityNormal = 0
+	PriorityHigh   = 10 // 后台手动重新入队等需要尽快处理的任务
+)
+
+// 任务状态，用于后台按状态列出任务
+const (
+	StateQueued     = "queued"
+	StateProcessing = "processing"
+	StateDelayed    = "delayed"
+	StateDead       = "dead"
+)
+
+// 任务处理结果，用于指标统计
+const (
+	ResultAck   = "ack"
+	ResultRetry = "retry"
+	ResultDead  = "dead"
+)
+
+// RetryPolicy 失败重试策略：第 n 次失败后延迟 BaseDelay*2^(n-1) 再重试，不超过 MaxDelay，失败 MaxAttempts 次后进入死信
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+/* MarshalJSON 延迟以秒输出，便于后台展示 */
+func (p RetryPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"max_attempts":       p.MaxAttempts,
+		"base_delay_seconds": p.BaseDelay.Seconds(),
+		"max_delay_seconds":  p.MaxDelay.Seconds(),
+	})
+}
+
+/* Exhausted 失败次数是否已达上限 */
+func (p RetryPolicy) Exhausted(failures int) bool {
+	return failures >= p.MaxAttempts
+}
+
+/* Normalize 修正无效配置：至少尝试一次，延迟不为负 */
+func (p RetryPolicy) Normalize() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.BaseDelay < 0 {
+		p.BaseDelay = 0
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+/* Delay 第 failures 次失败后的重试延迟 */
+func (p RetryPolicy) Delay(failures int) time.Duration {
+	if failures < 1 {
+		failures = 1
+	}
+	delay := p.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// JobInfo 后台任务列表中的单个任务
+type JobInfo struct {
+	FileID    string     `json:"file_id"`
+	State     string     `json:"state"`
+	Attempts  int        `json:"attempts"`
+	Priority  int        `json:"priority"`
+	LastError string     `json:"last_error,omitempty"`
+	RunAt     *time.Time `json:"run_at,omitempty"` // 延迟任务的计划执行时间，处理中任务的租约到期时间
+	LeaseBy   string     `json:"lease_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Lister 支持按状态分页列出任务的队列实现
+type Lister interface {
+	List(state string, offset, limit int) ([]JobInfo, int64, error)
+}
+
+// DeadRequeuer 支持将死信任务移出死信并重新入队的队列实现
+type DeadRequeuer interface {
+	RequeueDead(ids []string, priority int) (int, error)
+}
+
+/* RequeueDeadIDs 后台手动重试时使用：死信中的任务移出死信后重新入队，不在死信中的任务按普通任务幂等入队，返回入队的数量。
+ * 死信任务会一直占用去重标记，补偿扫描等自动入队不会让它们重新执行 */
+func RequeueDeadIDs(q Queue, ids []string, priority int) int {
+	requeuer, _ := q.(DeadRequeuer)
+	count := 0
+	for _, id := range ids {
+		if requeuer != nil {
+			if n, err := requeuer.RequeueDead([]string{id}, priority); err == nil && n > 0 {
+				count++
+				continue
+			}
+		}
+		if q.EnqueueUnique(id, priority) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Descriptor 注册到任务框架的一类后台任务，各字段由所属服务提供，服务重建队列后仍能取到当前实例
+type Descriptor struct {
+	Kind   string
+	Title  string
+	Queue  func() Queue
+	Policy func() RetryPolicy
+	Paused func() bool
+	// Requeue 将死信任务重新入队，由服务重置自身的重试状态，返回成功入队的数量
+	Requeue func(fileIDs []string) (int, error)
+}
+
+// KindStats 后台任务概览
+type KindStats struct {
+	Kind     string            `json:"kind"`
+	Title    string            `json:"title"`
+	Paused   bool              `json:"paused"`
+	Policy   RetryPolicy       `json:"policy"`
+	Queued   int               `json:"queued"`
+	InFlight int               `json:"in_flight"`
+	Delayed  int               `json:"delayed"`
+	Dead     int               `json:"dead"`
+	Results  map[string]uint64 `json:"results"` // 本实例启动以来的处理结果计数
+	Error    string            `json:"error,omitempty"`
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Descriptor)
+)
+
+func init() {
+	metrics.SetJobQueueProvider(queueGauges)
+}
+
+/* Register 注册一类后台任务，同名任务重复注册时覆盖 */
+func Register(d *Descriptor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[d.Kind] = d
+}
+
+/* Lookup 获取已注册的任务类型 */
+func Lookup(kind string) (*Descriptor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[kind]
+	return d, ok
+}
+
+/* PolicyFor 获取任务类型当前的重试策略，未注册时返回 fallback */
+func PolicyFor(kind string, fallback RetryPolicy) RetryPolicy {
+	if d, ok := Lookup(kind); ok && d.Policy != nil {
+		return d.Policy()
+	}
+	return fallback
+}
+
+func descriptors() []*Descriptor {
+	registryMu.RLock()
+	list := make([]*Descriptor, 0, len(registry))
+	for _, d := range registry {
+		list = append(list, d)
+	}
+	registryMu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Kind < list[j].Kind })
+	return list
+}
+
+/* Stats 汇总所有已注册任务类型的队列指标、重试策略与处理结果 */
+func Stats() []KindStats {
+	list := descriptors()
+	stats := make([]KindStats, 0, len(list))
+	for _, d := range list {
+		st := KindStats{
+			Kind:    d.Kind,
+			Title:   d.Title,
+			Results: metrics.JobResultCounts(d.Kind),
+		}
+		if d.Policy != nil {
+			st.Policy = d.Policy()
+		}
+		if d.Paused != nil {
+			st.Paused = d.Paused()
+		}
+		if q := currentQueue(d); q == nil {
+			st.Error = "队列未初始化"
+		} else if m, err := q.Metrics(); err != nil {
+			st.Error = err.Error()
+		} else if m != nil {
+			st.Queued, st.InFlight, st.Delayed, st.Dead = m.QueueLength, m.InFlight, m.DelayedCount, m.DLQCount
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+/* ListJobs 按状态分页列出指定类型的任务 */
+func ListJobs(kind, state string, offset, limit int) ([]JobInfo, int64, error) {
+	d, ok := Lookup(kind)
+	if !ok {
+		return nil, 0, errors.New(errors.CodeNotFound, "任务类型不存在")
+	}
+	q := currentQueue(d)
+	if q == nil {
+		return nil, 0, errors.New(errors.CodeServiceUnavailable, "队列未初始化")
+	}
+	lister, ok := q.(Lister)
+	if !ok {
+		return nil, 0, errors.New(errors.CodeInvalidParameter, "该队列不支持列出任务")
+	}
+	return lister.List(state, offset, limit)
+}
+
+/* RequeueDead 将指定类型的死信任务重新入队 */
+func RequeueDead(kind string, fileIDs []string) (int, error) {
+	d, ok := Lookup(kind)
+	if !ok {
+		return 0, errors.New(errors.CodeNotFound, "任务类型不存在")
+	}
+	if d.Requeue == nil {
+		return 0, errors.New(errors.CodeInvalidParameter, "该任务类型不支持重新入队")
+	}
+	if len(fileIDs) == 0 {
+		return 0, errors.New(errors.CodeInvalidParameter, "请选择需要重新入队的任务")
+	}
+	return d.Requeue(fileIDs)
+}
+
+func currentQueue(d *Descriptor) Queue {
+	if d.Queue == nil {
+		return nil
+	}
+	return d.Queue()
+}
+
+// queueGauges 导出队列长度指标，队列不可用的任务类型跳过
+func queueGauges() map[string]map[string]int {
+	gauges := make(map[string]map[string]int)
+	for _, d := range descriptors() {
+		q := currentQueue(d)
+		if q == nil {
+			continue
+		}
+		m, err := q.Metrics()
+		if err != nil || m == nil {
+			continue
+		}
+		gauges[d.Kind] = map[string]int{
+			StateQueued:     m.QueueLength,
+			StateProcessing: m.InFlight,
+			StateDelayed:    m.DelayedCount,
+			StateDead:       m.DLQCount,
+		}
+	}
+	return gauges
+}
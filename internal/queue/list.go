@@ -0,0 +1,181 @@
+package queue
+
+import (
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/errors"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// jobRow 数据库队列表的公共字段，打标队列的重试次数列为 tries，向量队列为 attempt
+type jobRow struct {
+	FileID     string
+	Status     string
+	Attempts   int
+	Priority   int
+	LeaseUntil *time.Time
+	LeaseBy    string
+	LastError  string
+	UpdatedAt  time.Time
+}
+
+func invalidStateError() error {
+	return errors.New(errors.CodeInvalidParameter, "任务状态无效，可选值: queued、processing、delayed、dead")
+}
+
+// listDBJobs 按状态分页列出数据库队列中的任务，scope 限定查询的表与范围，idColumn 为任务ID列，deadStatus 为该表死信任务的状态值
+func listDBJobs(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, idColumn, attemptsColumn, deadStatus, state string, offset, limit int) ([]JobInfo, int64, error) {
+	if db == nil {
+		return nil, 0, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	now := time.Now()
+	query := scope(db)
+	order := "updated_at DESC"
+	switch state {
+	case StateQueued:
+		query = query.Where("status = ? AND (lease_until IS NULL OR lease_until <= ?)", "queued", now)
+		order = "priority DESC, created_at ASC"
+	case StateDelayed:
+		query = query.Where("status = ? AND lease_until > ?", "queued", now)
+		order = "lease_until ASC"
+	case StateProcessing:
+		query = query.Where("status = ?", "processing")
+		order = "lease_until ASC"
+	case StateDead:
+		query = query.Where("status = ?", deadStatus)
+	default:
+		return nil, 0, invalidStateError()
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询任务总数失败")
+	}
+
+	var rows []jobRow
+	if err := query.Select(idColumn + " AS file_id, status, " + attemptsColumn + " AS attempts, priority, lease_until, lease_by, last_error, updated_at").
+		Order(order).Offset(offset).Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询任务失败")
+	}
+
+	jobs := make([]JobInfo, 0, len(rows))
+	for _, row := range rows {
+		updatedAt := row.UpdatedAt
+		job := JobInfo{
+			FileID:    row.FileID,
+			State:     state,
+			Attempts:  row.Attempts,
+			Priority:  row.Priority,
+			LastError: row.LastError,
+			LeaseBy:   row.LeaseBy,
+			UpdatedAt: &updatedAt,
+		}
+		if state == StateDelayed || state == StateProcessing {
+			job.RunAt = row.LeaseUntil
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, total, nil
+}
+
+/* List 按状态分页列出打标任务 */
+func (q *DBQueue) List(state string, offset, limit int) ([]JobInfo, int64, error) {
+	return listDBJobs(q.db, modelScope(&models.AIJob{}), "file_id", "tries", "dead", state, offset, limit)
+}
+
+/* List 按状态分页列出向量任务，重试耗尽的任务状态为 failed */
+func (q *DBQueueVector) List(state string, offset, limit int) ([]JobInfo, int64, error) {
+	return listDBJobs(q.db, modelScope(&models.VectorJob{}), "file_id", "attempt", "failed", state, offset, limit)
+}
+
+/* List 按状态分页列出通用任务，FileID 字段为任务ID */
+func (q *DBJobQueue) List(state string, offset, limit int) ([]JobInfo, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Model(&models.BackgroundJob{}).Where("kind = ?", q.kind)
+	}
+	return listDBJobs(q.db, scope, "job_id", "attempts", "dead", state, offset, limit)
+}
+
+func modelScope(model interface{}) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB { return db.Model(model) }
+}
+
+/* List 按状态分页列出任务，AI 与向量队列只保存文件ID，重试次数与错误信息由所属服务记录；通用任务队列从失败记录中补全 */
+func (q *RedisQueue) List(state string, offset, limit int) ([]JobInfo, int64, error) {
+	if !q.keyExists() {
+		return nil, 0, errors.New(errors.CodeServiceUnavailable, "Redis 不可用")
+	}
+
+	var (
+		ids   []string
+		runAt []*time.Time
+		total int64
+		err   error
+	)
+	switch state {
+	case StateQueued:
+		// 从右端取任务，按处理顺序从右往左列出
+		if total, err = q.cli.LLen(q.ctx, q.kQueue).Result(); err != nil {
+			break
+		}
+		ids, err = q.cli.LRange(q.ctx, q.kQueue, int64(-(offset + limit)), int64(-(offset + 1))).Result()
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	case StateDead:
+		if total, err = q.cli.LLen(q.ctx, q.kDLQ).Result(); err != nil {
+			break
+		}
+		ids, err = q.cli.LRange(q.ctx, q.kDLQ, int64(offset), int64(offset+limit-1)).Result()
+	case StateProcessing, StateDelayed:
+		key := q.kProcZ
+		if state == StateDelayed {
+			key = q.kDelayedZ
+		}
+		if total, err = q.cli.ZCard(q.ctx, key).Result(); err != nil {
+			break
+		}
+		var zs []redis.Z
+		zs, err = q.cli.ZRangeWithScores(q.ctx, key, int64(offset), int64(offset+limit-1)).Result()
+		for _, z := range zs {
+			id, _ := z.Member.(string)
+			at := time.Unix(int64(z.Score), 0)
+			ids = append(ids, id)
+			runAt = append(runAt, &at)
+		}
+	default:
+		return nil, 0, invalidStateError()
+	}
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeRedisError, "查询队列失败")
+	}
+
+	var attempts, lastErrors []interface{}
+	if q.kAttempts != "" && len(ids) > 0 {
+		attempts, _ = q.cli.HMGet(q.ctx, q.kAttempts, ids...).Result()
+		lastErrors, _ = q.cli.HMGet(q.ctx, q.kErrors, ids...).Result()
+	}
+
+	jobs := make([]JobInfo, 0, len(ids))
+	for i, id := range ids {
+		job := JobInfo{FileID: id, State: state}
+		if i < len(runAt) {
+			job.RunAt = runAt[i]
+		}
+		if i < len(attempts) {
+			if v, ok := attempts[i].(string); ok {
+				job.Attempts, _ = strconv.Atoi(v)
+			}
+		}
+		if i < len(lastErrors) {
+			job.LastError, _ = lastErrors[i].(string)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, total, nil
+}
@@ -2,12 +2,14 @@ package queue
 
 import (
 	"time"
+
+	"pixelpunk/pkg/cache"
 )
 
 // TaggingTask 表示一条AI打标任务（最小信息：文件ID）
 type TaggingTask struct {
-	FileID string
-	// 可扩展字段：优先级、重试次数等
+	FileID   string // 通用任务队列中为任务ID
+	Attempts int    // 已失败次数，仅通用任务队列填写
 }
 
 // MessageType 支持多队列类型
@@ -18,6 +20,11 @@ const (
 	MessageTypeVector    MessageType = "vector"
 )
 
+// leaseOwner 数据库队列的租约持有者，带上实例标识，多实例部署时可区分任务由哪个实例处理
+func leaseOwner(kind string) string {
+	return kind + "@" + cache.InstanceID()
+}
+
 // Metrics 队列运行时指标（用于WS推送与监控）
 type Metrics struct {
 	QueueLength  int
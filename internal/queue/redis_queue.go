@@ -14,9 +14,9 @@ import (
 
 // RedisQueue 使用 Redis List + Lua 原子脚本实现
 type RedisQueue struct {
-	cli *redis.Client
+	cli redis.UniversalClient
 	ctx context.Context
-	pfx string // key 前缀：<app.ns>:<kind>
+	pfx string // key 前缀：<app.ns>:<kind>，集群模式下带哈希标签
 
 	kQueue      string // 列表：主队列
 	kProcessing string // 列表：处理中
@@ -24,6 +24,10 @@ type RedisQueue struct {
 	kDelayedZ   string // zset：延迟重试
 	kDLQ        string // 列表：死信
 	kEnqueued   string // set：去重
+	kAttempts   string // hash：失败次数，仅通用任务队列记录
+	kErrors     string // hash：最近一次失败原因，仅通用任务队列记录
+
+	fileGuard bool // 入队前检查文件的打标心跳，通用任务队列不检查
 }
 
 func NewRedisQueue() *RedisQueue {
@@ -34,11 +38,12 @@ func NewRedisQueue() *RedisQueue {
 	ctx := cache.GetRedisContext()
 	ns := cache.GetNamespace()
 	// 默认用于打标队列；向量队列在外层设置 pfx
-	pfx := fmt.Sprintf("%s:%s", ns, "ai:tagging")
+	pfx := cache.HashTag(fmt.Sprintf("%s:%s", ns, "ai:tagging"))
 	q := &RedisQueue{
-		cli: rc,
-		ctx: ctx,
-		pfx: pfx,
+		cli:       rc,
+		ctx:       ctx,
+		pfx:       pfx,
+		fileGuard: true,
 	}
 	q.kQueue = pfx + ":queue"
 	q.kProcessing = pfx + ":processing:list"
@@ -51,7 +56,7 @@ func NewRedisQueue() *RedisQueue {
 
 // WithPrefix 允许自定义业务前缀（例如 vector）
 func (q *RedisQueue) WithPrefix(kind string) *RedisQueue {
-	q.pfx = fmt.Sprintf("%s:%s", cache.GetNamespace(), kind)
+	q.pfx = cache.HashTag(fmt.Sprintf("%s:%s", cache.GetNamespace(), kind))
 	q.kQueue = q.pfx + ":queue"
 	q.kProcessing = q.pfx + ":processing:list"
 	q.kProcZ = q.pfx + ":processing:z"
@@ -61,43 +66,120 @@ func (q *RedisQueue) WithPrefix(kind string) *RedisQueue {
 	return q
 }
 
+// NewRedisJobQueue 通用任务队列，键前缀为 jobs:<kind>，记录失败次数与原因，入队不检查文件心跳
+func NewRedisJobQueue(kind string) *RedisQueue {
+	q := NewRedisQueue()
+	if q == nil {
+		return nil
+	}
+	q = q.WithPrefix("jobs:" + kind)
+	q.kAttempts = q.pfx + ":attempts"
+	q.kErrors = q.pfx + ":errors"
+	q.fileGuard = false
+	return q
+}
+
 func (q *RedisQueue) keyExists() bool { return q.cli != nil }
 
-// EnqueueUnique：SADD去重成功才LPUSH（改进版：检查心跳避免重复入队）
+// EnqueueUnique：SADD去重成功才入队，高优先级任务插到队首（改进版：检查心跳避免重复入队）
 func (q *RedisQueue) EnqueueUnique(fileID string, priority int) error {
 	if !q.keyExists() {
 		return fmt.Errorf("redis not available")
 	}
 
-	// 先检查File表心跳时间，避免重复入队正在处理的任务
+	if q.fileGuard && q.fileBusy(fileID) {
+		return nil
+	}
+
+	added, err := q.cli.SAdd(q.ctx, q.kEnqueued, fileID).Result()
+	if err != nil {
+		return err
+	}
+	if added == 1 {
+		// 旧版本进入死信的任务不在去重集合中，入队时同时从DLQ移除
+		pipe := q.cli.TxPipeline()
+		pipe.LRem(q.ctx, q.kDLQ, 0, fileID)
+		if priority > PriorityNormal {
+			// 取任务从列表右端弹出，高优先级任务放到右端优先处理
+			pipe.RPush(q.ctx, q.kQueue, fileID)
+		} else {
+			pipe.LPush(q.ctx, q.kQueue, fileID)
+		}
+		_, err := pipe.Exec(q.ctx)
+		return err
+	}
+	return nil
+}
+
+// fileBusy 检查File表心跳时间，文件正在处理中（pending + 心跳时间在2分钟内）时不重复入队
+func (q *RedisQueue) fileBusy(fileID string) bool {
 	var file struct {
 		AITaggingStatus      string
 		AILastHeartbeatAt    *time.Time
 		AIProcessingWorkerID string
 	}
 	db := database.GetDB()
-	if db != nil {
-		if err := db.Table("file").
-			Select("ai_tagging_status, ai_last_heartbeat_at, ai_processing_worker_id").
-			Where("id = ?", fileID).
-			Take(&file).Error; err == nil {
-			// 如果文件正在处理中（pending + 心跳时间在2分钟内），跳过入队
-			if file.AITaggingStatus == "pending" &&
-				file.AILastHeartbeatAt != nil &&
-				time.Since(*file.AILastHeartbeatAt) < 2*time.Minute {
-				return nil
-			}
-		}
+	if db == nil {
+		return false
+	}
+	if err := db.Table("file").
+		Select("ai_tagging_status, ai_last_heartbeat_at, ai_processing_worker_id").
+		Where("id = ?", fileID).
+		Take(&file).Error; err != nil {
+		return false
 	}
+	return file.AITaggingStatus == "pending" &&
+		file.AILastHeartbeatAt != nil &&
+		time.Since(*file.AILastHeartbeatAt) < 2*time.Minute
+}
 
-	added, err := q.cli.SAdd(q.ctx, q.kEnqueued, fileID).Result()
-	if err != nil {
+// EnqueueAt 计划在 runAt 执行：放入延迟集合，由 reaper 到期后搬到主队列，已在队列中的任务不重复入队
+func (q *RedisQueue) EnqueueAt(id string, runAt time.Time) error {
+	if !q.keyExists() {
+		return fmt.Errorf("redis not available")
+	}
+	added, err := q.cli.SAdd(q.ctx, q.kEnqueued, id).Result()
+	if err != nil || added == 0 {
 		return err
 	}
-	if added == 1 {
-		return q.cli.LPush(q.ctx, q.kQueue, fileID).Err()
+	pipe := q.cli.TxPipeline()
+	pipe.LRem(q.ctx, q.kDLQ, 0, id)
+	pipe.ZAdd(q.ctx, q.kDelayedZ, redis.Z{Score: float64(runAt.Unix()), Member: id})
+	_, err = pipe.Exec(q.ctx)
+	return err
+}
+
+/* RequeueDead 将死信任务移出DLQ，清零失败次数后以指定优先级重新入队，返回重新入队的数量 */
+func (q *RedisQueue) RequeueDead(ids []string, priority int) (int, error) {
+	if !q.keyExists() {
+		return 0, fmt.Errorf("redis not available")
 	}
-	return nil
+	count := 0
+	for _, id := range ids {
+		removed, err := q.cli.LRem(q.ctx, q.kDLQ, 0, id).Result()
+		if err != nil {
+			return count, err
+		}
+		if removed == 0 {
+			continue
+		}
+		pipe := q.cli.TxPipeline()
+		pipe.SAdd(q.ctx, q.kEnqueued, id)
+		if q.kAttempts != "" {
+			pipe.HDel(q.ctx, q.kAttempts, id)
+			pipe.HDel(q.ctx, q.kErrors, id)
+		}
+		if priority > PriorityNormal {
+			pipe.RPush(q.ctx, q.kQueue, id)
+		} else {
+			pipe.LPush(q.ctx, q.kQueue, id)
+		}
+		if _, err := pipe.Exec(q.ctx); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
 }
 
 // Lua：原子 RPOPLPUSH + ZADD(lease)
@@ -127,6 +209,9 @@ func (q *RedisQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc
 	}
 	id, _ := v.(string)
 	task := &TaggingTask{FileID: id}
+	if q.kAttempts != "" {
+		task.Attempts, _ = q.cli.HGet(q.ctx, q.kAttempts, id).Int()
+	}
 
 	// Ack：LREM processing:list + ZREM processing:z + SREM enqueued
 	ack := func() error {
@@ -134,18 +219,27 @@ func (q *RedisQueue) Fetch(lease time.Duration) (*TaggingTask, AckFunc, NackFunc
 		pipe.LRem(q.ctx, q.kProcessing, 0, id)
 		pipe.ZRem(q.ctx, q.kProcZ, id)
 		pipe.SRem(q.ctx, q.kEnqueued, id)
+		if q.kAttempts != "" {
+			pipe.HDel(q.ctx, q.kAttempts, id)
+			pipe.HDel(q.ctx, q.kErrors, id)
+		}
 		_, e := pipe.Exec(q.ctx)
 		return e
 	}
 
-	// Nack：从processing移除；toDLQ则LPUSH到DLQ；否则ZADD delayed
+	// Nack：从processing移除；toDLQ则LPUSH到DLQ并保留去重标记；否则ZADD delayed
 	nack := func(delay time.Duration, toDLQ bool, lastError string) error {
 		pipe := q.cli.TxPipeline()
 		pipe.LRem(q.ctx, q.kProcessing, 0, id)
 		pipe.ZRem(q.ctx, q.kProcZ, id)
+		if q.kAttempts != "" {
+			pipe.HIncrBy(q.ctx, q.kAttempts, id, 1)
+			pipe.HSet(q.ctx, q.kErrors, id, lastError)
+		}
 		if toDLQ {
+			pipe.LRem(q.ctx, q.kDLQ, 0, id)
 			pipe.LPush(q.ctx, q.kDLQ, id)
-			// 不立即从去重集合删除，保留不再自动进入主队列
+			// 保留去重标记，补偿扫描不会自动重新入队，需通过 RequeueDead 手动重新入队
 		} else {
 			when := time.Now().Add(delay).Unix()
 			pipe.ZAdd(q.ctx, q.kDelayedZ, redis.Z{Score: float64(when), Member: id})
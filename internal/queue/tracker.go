@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"pixelpunk/internal/metrics"
+)
+
+// Tracker 记录本进程已取出但尚未确认的任务，关闭时通过 Checkpoint 放回队列，
+// 避免任务停留在处理中直到租约过期，重启后的补偿逻辑可以拿到准确的状态；同时按任务类型统计处理结果
+type Tracker struct {
+	kind  string
+	mu    sync.Mutex
+	next  uint64
+	tasks map[uint64]*trackedTask
+}
+
+type trackedTask struct {
+	fileID string
+	nack   NackFunc
+}
+
+/* NewTracker 创建指定任务类型的跟踪器 */
+func NewTracker(kind string) *Tracker {
+	return &Tracker{kind: kind, tasks: make(map[uint64]*trackedTask)}
+}
+
+/* Wrap 登记取出的任务，返回包装后的确认函数：确认或失败处理后自动注销，已被 Checkpoint 放回的任务再次 Nack 时忽略 */
+func (t *Tracker) Wrap(fileID string, ack AckFunc, nack NackFunc) (AckFunc, NackFunc) {
+	t.mu.Lock()
+	t.next++
+	id := t.next
+	t.tasks[id] = &trackedTask{fileID: fileID, nack: nack}
+	t.mu.Unlock()
+
+	wrappedAck := func() error {
+		t.release(id)
+		metrics.IncJobResult(t.kind, ResultAck)
+		return ack()
+	}
+	wrappedNack := func(delay time.Duration, toDLQ bool, lastError string) error {
+		if !t.release(id) {
+			return nil
+		}
+		if toDLQ {
+			metrics.IncJobResult(t.kind, ResultDead)
+		} else {
+			metrics.IncJobResult(t.kind, ResultRetry)
+		}
+		return nack(delay, toDLQ, lastError)
+	}
+	return wrappedAck, wrappedNack
+}
+
+func (t *Tracker) release(id uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.tasks[id]; !ok {
+		return false
+	}
+	delete(t.tasks, id)
+	return true
+}
+
+/* Len 当前未确认的任务数 */
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.tasks)
+}
+
+/* Checkpoint 将所有未确认的任务立即放回队列，返回被放回任务的文件ID */
+func (t *Tracker) Checkpoint(reason string) []string {
+	t.mu.Lock()
+	tasks := t.tasks
+	t.tasks = make(map[uint64]*trackedTask)
+	t.mu.Unlock()
+
+	fileIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if err := task.nack(0, false, reason); err != nil {
+			continue
+		}
+		fileIDs = append(fileIDs, task.fileID)
+	}
+	return fileIDs
+}
@@ -10,12 +10,15 @@ import (
 func RegisterAdminContentReviewRoutes(r *gin.RouterGroup) {
 	reviewGroup := r.Group("/content-review")
 	reviewGroup.Use(middleware.RequireAuth())
-	reviewGroup.Use(middleware.RequireAdmin())
+	// 审核员可以查看队列、记录并审核，删除和恢复文件仍需管理员权限
+	reviewGroup.Use(middleware.RequireReviewer())
 	{
 		reviewGroup.GET("/queue", adminController.GetReviewQueue)
 
 		reviewGroup.GET("/logs", adminController.GetReviewLogs)
 
+		reviewGroup.GET("/logs/export", adminController.ExportReviewLogs)
+
 		reviewGroup.GET("/stats", adminController.GetReviewStats)
 
 		reviewGroup.GET("/files/:fileId", adminController.GetFileDetail)
@@ -24,15 +27,36 @@ func RegisterAdminContentReviewRoutes(r *gin.RouterGroup) {
 
 		reviewGroup.POST("/batch-review", adminController.BatchReview)
 
-		reviewGroup.DELETE("/files/:fileId/hard-delete", adminController.HardDeleteReviewedFile)
+		reviewGroup.POST("/claim", adminController.ClaimReviewFiles)
+
+		reviewGroup.POST("/release", adminController.ReleaseReviewFiles)
+
+		reviewGroup.GET("/appeals", adminController.GetReviewAppeals)
+
+		reviewGroup.POST("/appeals/:id/approve", adminController.ApproveReviewAppeal)
+
+		reviewGroup.POST("/appeals/:id/deny", adminController.DenyReviewAppeal)
+
+		// 按筛选条件批量审核（后台任务）
+		reviewGroup.POST("/bulk-jobs/preview", middleware.RequireAdmin(), adminController.PreviewReviewBulkFilter)
+
+		reviewGroup.POST("/bulk-jobs", middleware.RequireAdmin(), adminController.CreateReviewBulkJob)
+
+		reviewGroup.GET("/bulk-jobs", middleware.RequireAdmin(), adminController.GetReviewBulkJobs)
+
+		reviewGroup.GET("/bulk-jobs/:id", middleware.RequireAdmin(), adminController.GetReviewBulkJob)
+
+		reviewGroup.POST("/bulk-jobs/:id/cancel", middleware.RequireAdmin(), adminController.CancelReviewBulkJob)
+
+		reviewGroup.DELETE("/files/:fileId/hard-delete", middleware.RequireAdmin(), adminController.HardDeleteReviewedFile)
 
 		// 新增：批量硬删除
-		reviewGroup.POST("/batch-hard-delete", adminController.BatchHardDeleteReviewedFiles)
+		reviewGroup.POST("/batch-hard-delete", middleware.RequireAdmin(), adminController.BatchHardDeleteReviewedFiles)
 
 		// 新增：恢复已软删除的文件
-		reviewGroup.POST("/files/:fileId/restore", adminController.RestoreReviewedFile)
+		reviewGroup.POST("/files/:fileId/restore", middleware.RequireAdmin(), adminController.RestoreReviewedFile)
 
 		// 新增：批量恢复已软删除的文件
-		reviewGroup.POST("/batch-restore", adminController.BatchRestoreReviewedFiles)
+		reviewGroup.POST("/batch-restore", middleware.RequireAdmin(), adminController.BatchRestoreReviewedFiles)
 	}
 }
@@ -18,6 +18,8 @@ func RegisterAdminRoutes(r *gin.RouterGroup) {
 		r.GET("/user", statsController.UserStats)
 	}
 
+	r.GET("/dashboard", middleware.RequireAdmin(), statsController.DashboardOverview)
+
 	statsAdmin := r.Group("/stats")
 	statsAdmin.Use(middleware.RequireAdmin())
 	{
@@ -61,6 +63,10 @@ func RegisterAdminRoutes(r *gin.RouterGroup) {
 		imageRoutes.POST("/batch-recommend", fileController.AdminBatchRecommendFiles)
 		imageRoutes.POST("/delete", fileController.AdminDeleteFile)
 		imageRoutes.POST("/batch-delete", fileController.AdminBatchDeleteFiles)
+		imageRoutes.GET("/trash", fileController.AdminGetTrashList)
+		imageRoutes.POST("/trash/restore", fileController.AdminRestoreTrashFiles)
+		imageRoutes.POST("/trash/purge", fileController.AdminPurgeTrashFiles)
+		imageRoutes.DELETE("/trash/:id", fileController.AdminPurgeTrashFile)
 	}
 
 	aiRoutes := r.Group("/ai")
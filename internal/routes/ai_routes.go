@@ -27,6 +27,11 @@ func RegisterAIRoutes(r *gin.RouterGroup) {
 			taggingGroup.POST("/unignore", aiController.UnignoreTagging) // 取消忽略
 			taggingGroup.POST("/retry", aiController.RetryTagging)
 			taggingGroup.POST("/trigger", aiController.TriggerTagging)
+			taggingGroup.POST("/dry-run", aiController.DryRunAIConfig) // 配置试运行(不落库)
+
+			taggingGroup.GET("/dead-letter", aiController.GetDeadLetterJobs)              // 死信任务列表
+			taggingGroup.GET("/dead-letter/:id", aiController.GetDeadLetterJobDetail)     // 死信任务详情
+			taggingGroup.POST("/dead-letter/requeue", aiController.RequeueDeadLetterJobs) // 批量重新入队
 		}
 	}
 }
@@ -9,14 +9,25 @@ import (
 
 /* RegisterPublicAnnouncementRoutes 注册公告公开路由（无需认证） */
 func RegisterPublicAnnouncementRoutes(r *gin.RouterGroup) {
-	// 公开路由 - 获取公告列表和详情
+	// 公开路由 - 获取公告列表和详情，携带登录态时返回定向公告与已读状态
 	public := r.Group("/announcements")
+	public.Use(middleware.OptionalUserAuth())
 	{
 		public.GET("", announcementController.GetPublicAnnouncementListHandler)
 		public.GET("/:id", announcementController.GetPublicAnnouncementDetailHandler)
 	}
 }
 
+/* RegisterAnnouncementReadRoutes 注册公告已读状态路由（需要认证） */
+func RegisterAnnouncementReadRoutes(r *gin.RouterGroup) {
+	read := r.Group("/announcements")
+	{
+		read.GET("/unread-count", announcementController.GetUnreadAnnouncementCountHandler)
+		read.POST("/read-all", announcementController.MarkAllAnnouncementsReadHandler)
+		read.POST("/:id/read", announcementController.MarkAnnouncementReadHandler)
+	}
+}
+
 /* RegisterAdminAnnouncementRoutes 注册公告管理端路由（需要管理员权限） */
 func RegisterAdminAnnouncementRoutes(r *gin.RouterGroup) {
 	// 管理端路由 - 需要管理员权限
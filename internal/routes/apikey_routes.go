@@ -25,4 +25,8 @@ func RegisterAPIKeyRoutes(r *gin.RouterGroup) {
 	r.GET("/:key_id/stats", apikeyController.GetAPIKeyStats)
 
 	r.POST("/:key_id/regenerate", apikeyController.RegenerateAPIKey)
+
+	r.GET("/:key_id/config/sharex", apikeyController.DownloadShareXConfig)
+
+	r.GET("/:key_id/config/picgo", apikeyController.DownloadPicGoConfig)
 }
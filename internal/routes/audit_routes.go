@@ -0,0 +1,18 @@
+package routes
+
+import (
+	auditController "pixelpunk/internal/controllers/audit"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterAuditRoutes 注册管理员审计日志路由（需要超级管理员权限） */
+func RegisterAuditRoutes(r *gin.RouterGroup) {
+	auditRoutes := r.Group("/admin/audit-logs")
+	auditRoutes.Use(middleware.RequireSuperAdmin())
+	{
+		auditRoutes.GET("", auditController.ListAuditLogs)
+		auditRoutes.GET("/:id", auditController.GetAuditLog)
+	}
+}
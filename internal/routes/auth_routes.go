@@ -23,6 +23,22 @@ func RegisterAuthRoutes(r *gin.RouterGroup) {
 	r.POST("/verify-reset-token", userController.VerifyResetToken)
 	r.POST("/reset-password-token", userController.ResetPasswordWithToken)
 
+	// 通行密钥（WebAuthn）登录
+	r.POST("/passkey/login/begin", userController.BeginPasskeyLogin)
+	r.POST("/passkey/login/finish", userController.FinishPasskeyLogin)
+
+	// SAML 2.0 单点登录（SP-initiated）
+	samlRoutes := r.Group("/saml")
+	{
+		samlRoutes.GET("/metadata", userController.SAMLMetadata)
+		samlRoutes.GET("/login", userController.SAMLLogin)
+		samlRoutes.POST("/acs", userController.SAMLACS)
+		samlRoutes.POST("/exchange", userController.SAMLExchange)
+	}
+
+	// 异常登录提醒中的一键锁定账号
+	r.POST("/login-alert/lock", userController.LockAccountFromAlert)
+
 	oauthRoutes := r.Group("/oauth")
 	{
 		oauthRoutes.POST("/github/login", oauthController.GithubLogin)
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	backupController "pixelpunk/internal/controllers/backup"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterBackupRoutes 注册备份管理路由，仅超级管理员可访问，恢复需通过命令行执行 */
+func RegisterBackupRoutes(r *gin.RouterGroup) {
+	backups := r.Group("/backups")
+	backups.Use(middleware.RequireAuth(), middleware.RequireSuperAdmin())
+	{
+		backups.GET("", backupController.ListBackups)
+		backups.GET("/status", backupController.GetBackupStatus)
+		backups.POST("", backupController.CreateBackup)
+		backups.GET("/download", backupController.DownloadBackup)
+		backups.DELETE("", backupController.DeleteBackup)
+	}
+}
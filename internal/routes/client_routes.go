@@ -3,6 +3,7 @@ package routes
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"os"
 	"pixelpunk/internal/middleware"
 	"pixelpunk/internal/static"
@@ -48,6 +49,11 @@ func RegisterClientRoutes(r *gin.Engine) {
 			c.Next()
 			return
 		}
+		// 分享页是前端渲染的，通过 Link 头声明 oEmbed 地址供第三方发现
+		if strings.HasPrefix(path, "/share/") {
+			shareURL := getRequestOrigin(c) + path
+			c.Header("Link", "<"+getRequestOrigin(c)+"/api/v1/oembed?format=json&url="+url.QueryEscape(shareURL)+`>; rel="alternate"; type="application/json+oembed"`)
+		}
 		middleware.StaticFileHandler(distFS)(c)
 	})
 }
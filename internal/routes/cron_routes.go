@@ -0,0 +1,20 @@
+package routes
+
+import (
+	cronController "pixelpunk/internal/controllers/cron"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterCronRoutes 注册定时任务管理路由：管理员可查看任务与执行历史，超级管理员可修改周期、启停与手动执行 */
+func RegisterCronRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin/cron")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("/jobs", cronController.ListCronTasks)
+		admin.GET("/history", cronController.ListCronHistory)
+		admin.PUT("/jobs/:name", middleware.RequireSuperAdmin(), cronController.UpdateCronTask)
+		admin.POST("/jobs/:name/run", middleware.RequireSuperAdmin(), cronController.RunCronTask)
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	debugController "pixelpunk/internal/controllers/debug"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterDebugRoutes 注册 pprof 与运行时调试路由，仅超级管理员可访问 */
+func RegisterDebugRoutes(r *gin.RouterGroup) {
+	debug := r.Group("/debug")
+	debug.Use(middleware.RequireAuth(), middleware.RequireSuperAdmin())
+	{
+		debug.GET("/runtime", debugController.GetRuntimeStats)
+		debug.GET("/goroutines", debugController.GoroutineDump)
+		debug.POST("/gc", debugController.ForceGC)
+		debug.PUT("/profiling", debugController.UpdateProfilingRate)
+
+		// 兼容 go tool pprof：curl -H "Authorization: Bearer <token>" .../pprof/heap > heap.pb.gz
+		debug.GET("/pprof/", debugController.PprofIndex)
+		debug.GET("/pprof/cmdline", debugController.PprofCmdline)
+		debug.GET("/pprof/profile", debugController.PprofProfile)
+		debug.GET("/pprof/symbol", debugController.PprofSymbol)
+		debug.POST("/pprof/symbol", debugController.PprofSymbol)
+		debug.GET("/pprof/trace", debugController.PprofTrace)
+		debug.GET("/pprof/:name", debugController.PprofLookup)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	feedController "pixelpunk/internal/controllers/feed"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterFeedRoutes 注册公开订阅源，无需登录即可订阅 */
+func RegisterFeedRoutes(r *gin.RouterGroup) {
+	feeds := r.Group("/feeds")
+	feeds.Use(middleware.MaintenanceMode())
+	{
+		feeds.GET("/recommended/:format", feedController.GetRecommendedFeed)
+		feeds.GET("/authors/:author_id/:format", feedController.GetAuthorFeed)
+	}
+}
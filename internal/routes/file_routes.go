@@ -1,6 +1,7 @@
 package routes
 
 import (
+	aiController "pixelpunk/internal/controllers/ai"
 	fileController "pixelpunk/internal/controllers/file"
 	"pixelpunk/internal/middleware"
 
@@ -39,6 +40,16 @@ func RegisterFileRoutes(r *gin.RouterGroup) {
 
 	authGroup.POST("/move", fileController.MoveFiles)
 
+	// 低置信度AI结果（待确认的标签/分类/推荐）
+	authGroup.GET("/ai-suggestions", aiController.GetAISuggestions)
+	authGroup.POST("/ai-suggestions/:id/accept", aiController.AcceptAISuggestion)
+	authGroup.POST("/ai-suggestions/:id/reject", aiController.RejectAISuggestion)
+
+	// 基于向量的近似重复文件（每晚扫描生成）
+	authGroup.GET("/duplicates", fileController.GetDuplicateCandidates)
+	authGroup.POST("/duplicates/:id/ignore", fileController.IgnoreDuplicateCandidate)
+	authGroup.POST("/duplicates/:id/resolve", fileController.ResolveDuplicateCandidate)
+
 	authGroup.GET("/:file_id/link", fileController.GenerateFileLink)
 	authGroup.POST("/:file_id/toggle-access-level", fileController.ToggleAccessLevel)
 
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	graphqlController "pixelpunk/internal/controllers/graphql"
+	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterGraphQLRoutes 注册只读 GraphQL 接口，支持登录令牌或带读取权限的API密钥 */
+func RegisterGraphQLRoutes(r *gin.Engine) {
+	graphql := r.Group("/api/graphql")
+	graphql.Use(middleware.InstallCheckMiddleware())
+	graphql.Use(middleware.MaintenanceMode())
+	graphql.Use(middleware.JWTOrAPIKeyAuth(models.APIKeyScopeRead))
+	graphql.Use(middleware.RequireAuth())
+	graphql.GET("", graphqlController.Query)
+	graphql.POST("", graphqlController.Query)
+}
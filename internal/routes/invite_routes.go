@@ -0,0 +1,34 @@
+package routes
+
+import (
+	inviteController "pixelpunk/internal/controllers/invite"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterPublicInviteRoutes 注册邀请码公开路由（无需认证） */
+func RegisterPublicInviteRoutes(r *gin.RouterGroup) {
+	r.POST("/invite-codes/check", inviteController.CheckInviteCode)
+}
+
+/* RegisterInviteRoutes 注册邀请码路由：用户在配额内生成，管理员管理全部 */
+func RegisterInviteRoutes(r *gin.RouterGroup) {
+	personal := r.Group("/invite-codes")
+	personal.Use(middleware.RequireAuth())
+	{
+		personal.GET("", inviteController.GetMyInviteCodes)
+		personal.POST("", inviteController.GenerateMyInviteCodes)
+		personal.PUT("/:id/disable", inviteController.DisableMyInviteCode)
+	}
+
+	admin := r.Group("/admin/invite-codes")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", inviteController.AdminListInviteCodes)
+		admin.POST("", inviteController.AdminGenerateInviteCodes)
+		admin.PUT("/:id/status", inviteController.AdminUpdateInviteCodeStatus)
+		admin.DELETE("/:id", inviteController.AdminDeleteInviteCode)
+		admin.GET("/:id/users", inviteController.AdminGetInviteCodeUsers)
+	}
+}
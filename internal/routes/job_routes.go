@@ -0,0 +1,19 @@
+package routes
+
+import (
+	jobController "pixelpunk/internal/controllers/job"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterJobRoutes 注册后台任务管理路由：管理员可查看，超级管理员可重新入队死信任务 */
+func RegisterJobRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin/jobs")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", jobController.ListJobKinds)
+		admin.GET("/:kind", jobController.ListJobs)
+		admin.POST("/:kind/requeue", middleware.RequireSuperAdmin(), jobController.RequeueDeadJobs)
+	}
+}
@@ -20,6 +20,10 @@ func RegisterMessageRoutes(r *gin.RouterGroup) {
 		userMessageGroup.DELETE("/:id", messageController.DeleteMessage)
 
 		userMessageGroup.GET("/unread-count", messageController.GetUnreadCount)
+
+		userMessageGroup.POST("/:id/appeal", messageController.AppealReviewMessage)
+
+		userMessageGroup.GET("/appeals", messageController.GetMyReviewAppeals)
 	}
 
 	adminMessageGroup := r.Group("/admin/messages")
@@ -41,5 +45,11 @@ func RegisterMessageRoutes(r *gin.RouterGroup) {
 		adminMessageGroup.POST("/send", messageController.SendMessage)
 
 		adminMessageGroup.GET("/stats", messageController.GetMessageStats)
+
+		adminMessageGroup.POST("/broadcasts", messageController.CreateBroadcast)
+
+		adminMessageGroup.GET("/broadcasts", messageController.GetBroadcasts)
+
+		adminMessageGroup.GET("/broadcasts/:id", messageController.GetBroadcast)
 	}
 }
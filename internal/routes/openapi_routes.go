@@ -0,0 +1,151 @@
+package routes
+
+import (
+	"net/http"
+	"sync"
+
+	fileDTO "pixelpunk/internal/controllers/file/dto"
+	shareDTO "pixelpunk/internal/controllers/share/dto"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/health"
+	"pixelpunk/pkg/openapi"
+	"pixelpunk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOpenAPIRoutes 提供 /api/v1 的 OpenAPI 文档，需在 JWT 中间件之前调用，此前注册的路由都视为公开接口
+func RegisterOpenAPIRoutes(r *gin.Engine, version *gin.RouterGroup) {
+	generator := newOpenAPIGenerator()
+	generator.MarkPublic(r.Routes())
+
+	var (
+		once sync.Once
+		doc  *openapi.Document
+	)
+	version.GET("/openapi.json", func(c *gin.Context) {
+		// 路由在启动后不再变化，首次请求时生成一次即可
+		once.Do(func() {
+			doc = generator.Build(r.Routes(), "")
+		})
+
+		spec := *doc
+		spec.Servers = []openapi.Server{{URL: firstNonEmpty(utils.GetBaseUrl(), getRequestOrigin(c))}}
+		c.JSON(http.StatusOK, spec)
+	})
+}
+
+func newOpenAPIGenerator() *openapi.Generator {
+	g := openapi.NewGenerator(openapi.Info{
+		Title:       "PixelPunk API",
+		Description: "PixelPunk 图床接口。外部接口(/api/v1/external)使用 API Key 鉴权，其余接口使用登录后获得的 JWT。",
+		Version:     health.GetVersion(),
+	}, "/api/v1")
+
+	g.AddSecurityScheme("bearerAuth", &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+	g.AddSecurityScheme("apiKeyAuth", &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "x-pixelpunk-key",
+		Description: "在个人设置中创建的 API Key，也可通过 x-api-key 请求头传入",
+	})
+	g.UseSecurity("/api/v1/external", "apiKeyAuth")
+	g.MarkPublicPrefix("/api/v1/r/")
+
+	describeExternalAPI(g)
+	return g
+}
+
+// describeExternalAPI 外部接口是第三方客户端(PicGo、ShareX 等)的主要对接面，补充完整的请求与响应结构
+func describeExternalAPI(g *openapi.Generator) {
+	tags := []string{"external"}
+
+	g.Describe(http.MethodPost, "/api/v1/external/upload", openapi.Endpoint{
+		Summary:     "上传文件",
+		Description: "需要 upload 权限。单文件使用 file 字段，多文件使用 files[] 字段。",
+		Tags:        tags,
+		Form: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"file":         {Type: "string", Format: "binary", Description: "单个文件"},
+				"files[]":      {Type: "array", Items: &openapi.Schema{Type: "string", Format: "binary"}, Description: "多个文件"},
+				"folderId":     {Type: "string", Description: "目标文件夹ID"},
+				"filePath":     {Type: "string", Description: "目标文件夹路径，不存在时自动创建"},
+				"access_level": {Type: "string", Enum: []interface{}{"public", "private", "protected"}},
+				"optimize":     {Type: "boolean", Description: "是否压缩优化"},
+			},
+		},
+		Response: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"uploaded": {
+					Description: "单文件上传时为对象，多文件上传时为数组",
+					OneOf: []*openapi.Schema{
+						openapi.TypeOf(filesvc.ExternalAPIFileResponse{}),
+						{Type: "array", Items: openapi.TypeOf(filesvc.ExternalAPIFileResponse{})},
+					},
+				},
+				"oversized_files": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+				"size_limit":      {Type: "string"},
+				"upload_errors":   {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			},
+		},
+	})
+
+	g.Describe(http.MethodGet, "/api/v1/external/files", openapi.Endpoint{
+		Summary:  "获取文件列表",
+		Tags:     tags,
+		Query:    fileDTO.FileListQueryDTO{},
+		Response: externalFileList{},
+	})
+
+	g.Describe(http.MethodGet, "/api/v1/external/files/:file_id", openapi.Endpoint{
+		Summary:  "获取文件详情",
+		Tags:     tags,
+		Response: filesvc.FileDetailResponse{},
+	})
+
+	g.Describe(http.MethodDelete, "/api/v1/external/files/:file_id", openapi.Endpoint{
+		Summary: "删除文件",
+		Tags:    tags,
+		Response: &openapi.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"id": {Type: "string"}},
+		},
+	})
+
+	g.Describe(http.MethodPost, "/api/v1/external/shares", openapi.Endpoint{
+		Summary: "创建分享",
+		Tags:    tags,
+		Body:    shareDTO.CreateShareDTO{},
+		Response: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id":        {Type: "string"},
+				"share_key": {Type: "string"},
+				"share_url": {Type: "string", Format: "uri"},
+			},
+		},
+	})
+
+	g.Describe(http.MethodGet, "/api/v1/r/:api_key", openapi.Endpoint{
+		Summary:     "随机图片",
+		Description: "按随机图片API配置返回一张图片或重定向到图片地址",
+		Tags:        []string{"random"},
+	})
+}
+
+// externalFileList 外部文件列表响应结构，仅用于生成文档
+type externalFileList struct {
+	Items      []filesvc.FileDetailResponse `json:"items"`
+	Pagination struct {
+		Total       int64 `json:"total"`
+		Size        int   `json:"size"`
+		CurrentPage int   `json:"current_page"`
+		LastPage    int64 `json:"last_page"`
+	} `json:"pagination"`
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	quotaController "pixelpunk/internal/controllers/quota"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterQuotaRoutes 注册配额调整路由：用户查看自己的记录，超级管理员追加或撤销 */
+func RegisterQuotaRoutes(r *gin.RouterGroup) {
+	personal := r.Group("/quota-grants")
+	personal.Use(middleware.RequireAuth())
+	{
+		personal.GET("", quotaController.GetMyQuotaGrants)
+	}
+
+	admin := r.Group("/admin/quota-grants")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", quotaController.AdminListQuotaGrants)
+		admin.POST("", middleware.RequireSuperAdmin(), quotaController.AdminGrantQuota)
+		admin.PUT("/:id/revoke", middleware.RequireSuperAdmin(), quotaController.AdminRevokeQuotaGrant)
+	}
+}
@@ -3,7 +3,10 @@ package routes
 import (
 	fileController "pixelpunk/internal/controllers/file"
 	randomAPIController "pixelpunk/internal/controllers/random_api"
+	shareController "pixelpunk/internal/controllers/share"
 	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/ratelimit"
 	"pixelpunk/pkg/health"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +14,10 @@ import (
 
 func RegisterRoutes(r *gin.Engine) {
 
+	// 容器探针在 IP 黑白名单与安装检查之前注册，安装模式下 /readyz 返回 503
+	r.GET("/healthz", health.LivenessHandler)
+	r.GET("/readyz", health.ReadinessHandler)
+
 	r.Use(middleware.IpRefererMiddleware())
 
 	RegisterClientRoutes(r)
@@ -21,10 +28,11 @@ func RegisterRoutes(r *gin.Engine) {
 	RegisterSetupRoutes(version)
 
 	version.Use(middleware.InstallCheckMiddleware())
+	version.Use(middleware.RateLimit(ratelimit.PolicyGlobal))
 
 	version.GET("/health", health.SimpleHealthHandler)
 	version.GET("/health/basic", health.BasicHealthHandler)
-	version.GET("/health/complete", health.CompleteHealthHandler)
+	version.GET("/health/complete", middleware.OptionalUserAuth(), middleware.HealthProbeAccess(), health.CompleteHealthHandler)
 
 	RegisterMetricsRoutes(version)
 
@@ -35,18 +43,33 @@ func RegisterRoutes(r *gin.Engine) {
 
 	// 注册公开的认证路由（不需要JWT认证）
 	authRoutes := version.Group("/auth")
+	authRoutes.Use(middleware.MaintenanceMode())
+	authRoutes.Use(middleware.RateLimit(ratelimit.PolicyAuth))
 	RegisterAuthRoutes(authRoutes)
 
 	// 注册公开的用户路由（兼容旧的API路径，不需要JWT认证）
 	publicUserRoutes := version.Group("/user")
+	publicUserRoutes.Use(middleware.MaintenanceMode())
+	publicUserRoutes.Use(middleware.RateLimit(ratelimit.PolicyAuth))
 	RegisterPublicUserRoutes(publicUserRoutes)
 
 	// 注册公开的公告路由（不需要JWT认证）
 	RegisterPublicAnnouncementRoutes(version)
 
+	// 注册邀请码校验路由（不需要JWT认证）
+	RegisterPublicInviteRoutes(version)
+
+	// 注册公开订阅源（不需要JWT认证）
+	RegisterFeedRoutes(version)
+
+	// OpenAPI 文档（不需要JWT认证），须在所有公开路由之后注册
+	RegisterOpenAPIRoutes(r, version)
+
 	// JWT 中间件必须在所有需要认证的路由之前注册
 	version.Use(middleware.JWTAuth())
 	version.Use(middleware.TrackUserActivity())
+	version.Use(middleware.AdminAudit())
+	version.Use(middleware.MaintenanceMode())
 
 	// 头像上传（需要认证）
 	version.POST("/avatar/upload", middleware.RequireAuth(), fileController.UploadAvatar)
@@ -93,8 +116,11 @@ func RegisterRoutes(r *gin.Engine) {
 
 	adminRoutes := version.Group("/admin")
 	RegisterAdminRoutes(adminRoutes)
+	RegisterDebugRoutes(adminRoutes)
+	RegisterBackupRoutes(adminRoutes)
 
 	RegisterWebSocketRoutes(adminRoutes)
+	RegisterUserWebSocketRoutes(version)
 
 	adminShareRoutes := version.Group("/admin/shares")
 	RegisterAdminShareRoutes(adminShareRoutes)
@@ -113,6 +139,7 @@ func RegisterRoutes(r *gin.Engine) {
 
 	shareRoutes := version.Group("/shares")
 	RegisterShareRoutes(shareRoutes)
+	RegisterOEmbedRoutes(version)
 
 	RegisterSearchRoutes(version)
 
@@ -121,6 +148,25 @@ func RegisterRoutes(r *gin.Engine) {
 
 	RegisterMessageRoutes(version)
 
+	RegisterInviteRoutes(version)
+
+	RegisterUserGroupRoutes(version)
+
+	RegisterAuditRoutes(version)
+
+	RegisterQuotaRoutes(version)
+
+	RegisterWebhookRoutes(version)
+
+	RegisterJobRoutes(version)
+
+	RegisterCronRoutes(version)
+
+	RegisterTelegramRoutes(version)
+
+	// 注册公告已读状态路由
+	RegisterAnnouncementReadRoutes(version)
+
 	// 注册公告管理端路由（需要管理员权限）
 	RegisterAdminAnnouncementRoutes(version)
 
@@ -161,11 +207,24 @@ func RegisterRoutes(r *gin.Engine) {
 
 	apiUploadRoutes := r.Group("/api/v1/external")
 	apiUploadRoutes.Use(middleware.InstallCheckMiddleware())
-	apiUploadRoutes.Use(middleware.APIKeyAuthMiddleware())
-	apiUploadRoutes.POST("/upload", fileController.UploadForApiKey)
+	apiUploadRoutes.Use(middleware.MaintenanceMode())
+	apiUploadRoutes.POST("/upload", middleware.APIKeyAuthMiddleware(models.APIKeyScopeUpload), fileController.UploadForApiKey)
+	apiUploadRoutes.GET("/files", middleware.APIKeyAuthMiddleware(models.APIKeyScopeRead), fileController.ListFilesForApiKey)
+	apiUploadRoutes.GET("/files/:file_id", middleware.APIKeyAuthMiddleware(models.APIKeyScopeRead), fileController.GetFileForApiKey)
+	apiUploadRoutes.DELETE("/files/:file_id", middleware.APIKeyAuthMiddleware(models.APIKeyScopeDelete), fileController.DeleteFileForApiKey)
+	apiUploadRoutes.POST("/shares", middleware.APIKeyAuthMiddleware(models.APIKeyScopeShare), shareController.CreateShareForApiKey)
+	apiUploadRoutes.POST("/sharex/upload", middleware.APIKeyAuthMiddleware(models.APIKeyScopeUpload), fileController.ShareXUpload)
+	apiUploadRoutes.POST("/picgo/upload", middleware.APIKeyAuthMiddleware(models.APIKeyScopeUpload), fileController.PicGoUpload)
+	apiUploadRoutes.GET("/compat/delete/:key_id/:file_id/:token", fileController.CompatDeletionPage)
+	apiUploadRoutes.POST("/compat/delete/:key_id/:file_id/:token", fileController.CompatDeleteFile)
+
+	RegisterS3Routes(r)
+	RegisterWebDAVRoutes(r)
+	RegisterGraphQLRoutes(r)
 
 	// 随机图片API公开接口（不需要认证）
 	randomImageRoutes := r.Group("/api/v1/r")
 	randomImageRoutes.Use(middleware.InstallCheckMiddleware())
+	randomImageRoutes.Use(middleware.RateLimit(ratelimit.PolicyRandomAPI))
 	randomImageRoutes.GET("/:api_key", randomAPIController.GetRandomImage)
 }
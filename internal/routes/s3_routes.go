@@ -0,0 +1,31 @@
+package routes
+
+import (
+	s3Controller "pixelpunk/internal/controllers/s3"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterS3Routes 注册 S3 兼容接口，使用 AWS V4 签名认证，每个API密钥对应一个 library 存储桶 */
+func RegisterS3Routes(r *gin.Engine) {
+	s3 := r.Group("/s3")
+	s3.Use(middleware.InstallCheckMiddleware())
+	s3.Use(middleware.MaintenanceMode())
+	s3.Use(middleware.S3AuthMiddleware())
+	{
+		s3.GET("", s3Controller.ListBuckets)
+		s3.GET("/", s3Controller.ListBuckets)
+
+		s3.GET("/:bucket", s3Controller.GetBucket)
+		s3.HEAD("/:bucket", s3Controller.HeadBucket)
+		s3.PUT("/:bucket", s3Controller.PutBucket)
+		s3.POST("/:bucket", s3Controller.NotImplemented)
+
+		s3.GET("/:bucket/*key", s3Controller.GetObject)
+		s3.HEAD("/:bucket/*key", s3Controller.HeadObject)
+		s3.PUT("/:bucket/*key", s3Controller.PutObject)
+		s3.DELETE("/:bucket/*key", s3Controller.DeleteObject)
+		s3.POST("/:bucket/*key", s3Controller.NotImplemented)
+	}
+}
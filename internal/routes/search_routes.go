@@ -3,12 +3,14 @@ package routes
 import (
 	searchController "pixelpunk/internal/controllers/search"
 	"pixelpunk/internal/middleware"
+	"pixelpunk/internal/services/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterSearchRoutes(r *gin.RouterGroup) {
 	searchGroup := r.Group("/search")
+	searchGroup.Use(middleware.RateLimit(ratelimit.PolicySearch))
 	{
 		vectorGroup := searchGroup.Group("/vector")
 		{
@@ -31,6 +33,8 @@ func RegisterSearchRoutes(r *gin.RouterGroup) {
 		userGroup.Use(middleware.RequireAuth())
 		{
 			userGroup.POST("/vector/search", searchController.UserVectorSearch)
+
+			userGroup.POST("/feedback", searchController.SubmitSearchFeedback)
 		}
 
 		galleryGroup := searchGroup.Group("/gallery")
@@ -51,6 +55,9 @@ func RegisterSearchRoutes(r *gin.RouterGroup) {
 		adminSimilarGroup.Use(middleware.RequireAdmin())
 		{
 			adminSimilarGroup.GET("/similar/:fileId", searchController.AdminSimilarFiles)
+
+			adminSimilarGroup.GET("/feedback", searchController.GetSearchFeedbackList)
+			adminSimilarGroup.GET("/feedback/stats", searchController.GetSearchFeedbackStats)
 		}
 	}
 }
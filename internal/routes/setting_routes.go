@@ -13,6 +13,10 @@ func RegisterSettingRoutes(r *gin.RouterGroup) {
 	{
 		r.GET("", settingController.GetSettings)
 
+		r.GET("/export", middleware.RequireSuperAdmin(), settingController.ExportSettings)
+		r.POST("/import/preview", middleware.RequireSuperAdmin(), settingController.PreviewSettingsImport)
+		r.POST("/import", middleware.RequireSuperAdmin(), settingController.ImportSettings)
+
 		r.GET("/:key", settingController.GetSetting)
 
 		r.GET("/group/:group/map", settingController.GetSettingsByGroupAsMap)
@@ -38,5 +42,9 @@ func RegisterSettingRoutes(r *gin.RouterGroup) {
 		r.POST("/vector/test-qdrant", settingController.TestQdrantConnection)
 
 		r.POST("/test-proxy", settingController.TestProxy)
+
+		r.POST("/ldap/test", settingController.TestLDAPSettings)
+
+		r.POST("/error-reporting/test", settingController.TestErrorReporting)
 	}
 }
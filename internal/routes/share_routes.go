@@ -9,6 +9,7 @@ import (
 
 func RegisterShareRoutes(r *gin.RouterGroup) {
 	r.POST("/download-files", shareController.DownloadFilesBatch)
+	r.GET("/extend/:token", shareController.ExtendShareByToken)
 	userShareGroup := r.Group("")
 	userShareGroup.Use(middleware.RequireAuth())
 
@@ -16,14 +17,62 @@ func RegisterShareRoutes(r *gin.RouterGroup) {
 
 	userShareGroup.GET("", shareController.GetShareList)
 
+	userShareGroup.POST("/from-template", shareController.CreateShareFromTemplate)
+
+	userShareGroup.GET("/slug-check", shareController.CheckShareSlug)
+
+	userShareGroup.GET("/received", shareController.GetReceivedShares)
+
+	userShareGroup.GET("/templates", shareController.GetShareTemplates)
+
+	userShareGroup.POST("/templates", shareController.CreateShareTemplate)
+
+	userShareGroup.PUT("/templates/:template_id", shareController.UpdateShareTemplate)
+
+	userShareGroup.DELETE("/templates/:template_id", shareController.DeleteShareTemplate)
+
 	userShareGroup.GET("/:id", shareController.GetShareDetail)
 
 	userShareGroup.GET("/:id/visitors", shareController.GetShareVisitors)
 
+	userShareGroup.GET("/:id/visitors/export", shareController.ExportShareVisitors)
+
+	userShareGroup.GET("/:id/audit/export", shareController.ExportShareAudit)
+
 	userShareGroup.DELETE("/:id/visitors/:visitor_id", shareController.DeleteShareVisitor)
 
 	userShareGroup.DELETE("/:id", shareController.DeleteShare)
 
+	userShareGroup.POST("/:id/extend", shareController.ExtendShare)
+
+	userShareGroup.GET("/:id/uploads", shareController.GetShareUploads)
+
+	userShareGroup.POST("/:id/uploads/:upload_id/approve", shareController.ApproveShareUpload)
+
+	userShareGroup.POST("/:id/uploads/:upload_id/reject", shareController.RejectShareUpload)
+
+	userShareGroup.PUT("/:id/slideshow", shareController.UpdateShareSlideshow)
+
+	userShareGroup.GET("/:id/exclusions", shareController.GetShareExclusions)
+
+	userShareGroup.PUT("/:id/exclusions", shareController.UpdateShareExclusions)
+
+	userShareGroup.GET("/:id/invitations", shareController.GetShareInvitations)
+
+	userShareGroup.POST("/:id/invitations", shareController.SendShareInvitations)
+
+	userShareGroup.DELETE("/:id/invitations/:invitation_id", shareController.RevokeShareInvitation)
+
+	userShareGroup.GET("/:id/tokens", shareController.GetShareAccessTokens)
+
+	userShareGroup.POST("/:id/tokens/revoke", shareController.RevokeShareAccessTokens)
+
+	userShareGroup.DELETE("/:id/tokens/:token_id", shareController.RevokeShareAccessToken)
+
+	userShareGroup.GET("/:id/recipients", shareController.GetShareRecipients)
+
+	userShareGroup.PUT("/:id/recipients", shareController.UpdateShareRecipients)
+
 	publicGroup := r.Group("/public")
 
 	publicGroup.GET("/:key", shareController.ViewShare)
@@ -32,5 +81,22 @@ func RegisterShareRoutes(r *gin.RouterGroup) {
 
 	publicGroup.POST("/:key/visitor", shareController.SubmitVisitorInfo)
 
+	publicGroup.POST("/:key/email-code", shareController.SendShareEmailCode)
+
+	publicGroup.POST("/:key/email-verify", shareController.VerifyShareEmail)
+
 	publicGroup.GET("/:key/files/:file_id/download", shareController.DownloadSharedFile)
+
+	publicGroup.POST("/:key/upload", middleware.UploadConcurrencyLimit(), shareController.UploadToShare)
+
+	publicGroup.GET("/:key/embed", shareController.ShareEmbed)
+
+	publicGroup.GET("/:key/archives/:job_id", shareController.GetShareArchiveProgress)
+
+	publicGroup.GET("/:key/archives/:job_id/download", shareController.DownloadShareArchive)
+}
+
+// RegisterOEmbedRoutes oEmbed 接口，供博客、Notion 等解析分享链接
+func RegisterOEmbedRoutes(r *gin.RouterGroup) {
+	r.GET("/oembed", shareController.OEmbed)
 }
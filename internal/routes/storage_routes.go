@@ -45,6 +45,10 @@ func RegisterStorageRoutes(r *gin.RouterGroup) {
 
 	r.POST("/import", storageController.ImportChannelConfig)
 
+	r.GET("/:id/objects", storageController.ListChannelObjects)
+
+	r.POST("/:id/objects/delete-orphans", middleware.RequireSuperAdmin(), storageController.DeleteOrphanObjects)
+
 	r.POST("/:id/refresh-cache", storageController.RefreshChannelCache)
 
 	r.POST("/clear-cache", storageController.ClearAllChannelCache)
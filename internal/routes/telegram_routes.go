@@ -0,0 +1,20 @@
+package routes
+
+import (
+	telegramController "pixelpunk/internal/controllers/telegram"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterTelegramRoutes 注册 Telegram 机器人绑定路由 */
+func RegisterTelegramRoutes(r *gin.RouterGroup) {
+	telegram := r.Group("/telegram/binding")
+	telegram.Use(middleware.RequireAuth())
+	{
+		telegram.GET("", telegramController.GetBinding)
+		telegram.POST("/code", telegramController.CreateBindCode)
+		telegram.PUT("/folder", telegramController.UpdateUploadFolder)
+		telegram.DELETE("", telegramController.Unbind)
+	}
+}
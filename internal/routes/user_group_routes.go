@@ -0,0 +1,21 @@
+package routes
+
+import (
+	userGroupController "pixelpunk/internal/controllers/user_group"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterUserGroupRoutes 注册用户组管理路由，查看需要管理员权限，修改需要超级管理员权限 */
+func RegisterUserGroupRoutes(r *gin.RouterGroup) {
+	groupRoutes := r.Group("/admin/user-groups")
+	groupRoutes.Use(middleware.RequireAdmin())
+	{
+		groupRoutes.GET("", userGroupController.ListGroups)
+		groupRoutes.POST("", middleware.RequireSuperAdmin(), userGroupController.CreateGroup)
+		groupRoutes.PUT("/:id", middleware.RequireSuperAdmin(), userGroupController.UpdateGroup)
+		groupRoutes.DELETE("/:id", middleware.RequireSuperAdmin(), userGroupController.DeleteGroup)
+		groupRoutes.POST("/assign", middleware.RequireSuperAdmin(), userGroupController.AssignUsers)
+	}
+}
@@ -33,6 +33,17 @@ func RegisterUserRoutes(r *gin.RouterGroup) {
 		userGroup.POST("/send-change-email-code", userController.SendChangeEmailCode)
 
 		userGroup.POST("/change-email", userController.ChangeEmail)
+		userGroup.DELETE("/change-email", userController.CancelEmailChange)
+
+		userGroup.GET("/sessions", userController.GetSessions)
+		userGroup.DELETE("/sessions/:id", userController.RevokeSession)
+		userGroup.POST("/sessions/revoke-others", userController.RevokeOtherSessions)
+
+		userGroup.GET("/passkeys", userController.GetPasskeys)
+		userGroup.POST("/passkeys/register/begin", userController.BeginPasskeyRegistration)
+		userGroup.POST("/passkeys/register/finish", userController.FinishPasskeyRegistration)
+		userGroup.PUT("/passkeys/:id", userController.RenamePasskey)
+		userGroup.DELETE("/passkeys/:id", userController.DeletePasskey)
 
 		userGroup.GET("/access-control", userController.GetUserAccessControl)
 		userGroup.POST("/access-control/createOrUpdate", userController.CreateOrUpdateUserAccessControl)
@@ -40,7 +51,19 @@ func RegisterUserRoutes(r *gin.RouterGroup) {
 
 		userGroup.GET("/workspace/stats", userController.GetWorkspaceStats)
 
+		userGroup.GET("/ai-webhook", userController.GetAIWebhook)
+		userGroup.POST("/ai-webhook", userController.UpdateAIWebhook)
+		userGroup.GET("/ai-processing", userController.GetAIProcessing)
+		userGroup.POST("/ai-processing", userController.UpdateAIProcessing)
+
 		userGroup.GET("/activities", activityController.GetUserActivities)
+
+		userGroup.POST("/data-export", userController.CreateDataExport)
+		userGroup.GET("/data-export", userController.GetDataExport)
+		userGroup.GET("/data-export/:job_id/download", userController.DownloadDataExport)
+
+		userGroup.POST("/account/deletion", userController.RequestAccountDeletion)
+		userGroup.DELETE("/account/deletion", userController.CancelAccountDeletion)
 	}
 
 	adminGroup := r.Group("/admin")
@@ -12,10 +12,12 @@ func RegisterVectorRoutes(r *gin.RouterGroup) {
 	vectorGroup.Use(middleware.RequireAuth())
 	vectorGroup.Use(middleware.RequireAdmin()) // 管理员权限
 	{
-		vectorGroup.GET("/list", vectorController.GetVectorList)              // 获取向量列表
-		vectorGroup.GET("/stats", vectorController.GetVectorStats)            // 获取向量统计
-		vectorGroup.GET("/qdrant-stats", vectorController.GetQdrantRealStats) // 获取 Qdrant 实际统计
-		vectorGroup.GET("/models", vectorController.GetAvailableModels)       // 获取可用模型
+		vectorGroup.GET("/list", vectorController.GetVectorList)                           // 获取向量列表
+		vectorGroup.GET("/stats", vectorController.GetVectorStats)                         // 获取向量统计及子系统运行状况
+		vectorGroup.GET("/qdrant-stats", vectorController.GetQdrantRealStats)              // 获取 Qdrant 实际统计
+		vectorGroup.GET("/builtin-qdrant", vectorController.GetBuiltinQdrantStatus)        // 内置Qdrant进程状态
+		vectorGroup.POST("/builtin-qdrant/restart", vectorController.RestartBuiltinQdrant) // 重启内置Qdrant
+		vectorGroup.GET("/models", vectorController.GetAvailableModels)                    // 获取可用模型
 
 		vectorGroup.GET("/detail/:fileId", vectorController.GetVectorDetail)
 
@@ -32,6 +34,17 @@ func RegisterVectorRoutes(r *gin.RouterGroup) {
 		vectorGroup.POST("/reconcile/missing", vectorController.ReconcileMissing)
 		vectorGroup.POST("/reconcile/orphans", vectorController.CleanOrphans)
 		vectorGroup.POST("/rebuild/stale", vectorController.RebuildStale)
+		vectorGroup.POST("/reindex", vectorController.StartVectorReindex)                         // 删除集合并全量重建
+		vectorGroup.GET("/reindex/progress", vectorController.GetVectorReindexProgress)           // 重建进度
+		vectorGroup.GET("/collections", vectorController.GetVectorCollections)                    // 集合版本列表
+		vectorGroup.POST("/collections/migrate", vectorController.StartVectorCollectionMigration) // 按当前模型构建新版本集合
+		vectorGroup.POST("/payload/sync", vectorController.SyncVectorPayload)                     // 回填向量附带的文件元数据
+		vectorGroup.GET("/export", vectorController.ExportVectors)                                // 导出全部向量
+		vectorGroup.POST("/import", vectorController.ImportVectors)                               // 从导出文件恢复向量
+
+		vectorGroup.GET("/duplicates", vectorController.GetVectorDuplicates)
+		vectorGroup.POST("/duplicates/scan", vectorController.ScanVectorDuplicates)
+		vectorGroup.PUT("/duplicates/:id/status", vectorController.UpdateVectorDuplicateStatus)
 
 		vectorGroup.GET("/logs", vectorController.GetVectorLogs) // 获取处理日志
 	}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	webdavController "pixelpunk/internal/controllers/webdav"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebDAV 使用的全部请求方法，gin 的 Any 不包含扩展方法，需逐一注册
+var webdavMethods = []string{
+	"OPTIONS", "GET", "HEAD", "PUT", "DELETE",
+	"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK",
+}
+
+/* RegisterWebDAVRoutes 注册 WebDAV 挂载点，每个用户看到自己的文件夹与文件 */
+func RegisterWebDAVRoutes(r *gin.Engine) {
+	dav := r.Group(webdavController.Prefix)
+	dav.Use(middleware.InstallCheckMiddleware())
+	dav.Use(middleware.MaintenanceMode())
+	dav.Use(middleware.WebDAVAuthMiddleware())
+	for _, method := range webdavMethods {
+		dav.Handle(method, "", webdavController.Serve)
+		dav.Handle(method, "/*path", webdavController.Serve)
+	}
+}
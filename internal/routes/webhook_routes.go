@@ -0,0 +1,29 @@
+package routes
+
+import (
+	webhookController "pixelpunk/internal/controllers/webhook"
+	"pixelpunk/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* RegisterWebhookRoutes 注册 Webhook 管理路由：管理员可查看，超级管理员可修改 */
+func RegisterWebhookRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin/webhooks")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("/events", webhookController.GetWebhookEvents)
+		admin.GET("", webhookController.ListWebhooks)
+		admin.GET("/:id", webhookController.GetWebhook)
+		admin.POST("", middleware.RequireSuperAdmin(), webhookController.CreateWebhook)
+		admin.PUT("/:id", middleware.RequireSuperAdmin(), webhookController.UpdateWebhook)
+		admin.PUT("/:id/status", middleware.RequireSuperAdmin(), webhookController.ToggleWebhook)
+		admin.POST("/:id/rotate-secret", middleware.RequireSuperAdmin(), webhookController.RotateWebhookSecret)
+		admin.DELETE("/:id", middleware.RequireSuperAdmin(), webhookController.DeleteWebhook)
+		admin.POST("/:id/ping", middleware.RequireSuperAdmin(), webhookController.PingWebhook)
+
+		admin.GET("/:id/deliveries", webhookController.ListWebhookDeliveries)
+		admin.GET("/deliveries/:delivery_id", webhookController.GetWebhookDelivery)
+		admin.POST("/deliveries/:delivery_id/redeliver", middleware.RequireSuperAdmin(), webhookController.RedeliverWebhookDelivery)
+	}
+}
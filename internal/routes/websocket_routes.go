@@ -17,3 +17,13 @@ func RegisterWebSocketRoutes(r *gin.RouterGroup) {
 		wsGroup.GET("/stats", websocket.GetStats)
 	}
 }
+
+// RegisterUserWebSocketRoutes 普通用户WebSocket（AI处理完成等个人通知）
+func RegisterUserWebSocketRoutes(r *gin.RouterGroup) {
+	wsGroup := r.Group("/ws")
+	wsGroup.Use(middleware.JWTAuth())
+	wsGroup.Use(middleware.RequireAuth())
+	{
+		wsGroup.GET("/user", websocket.HandleUserWebSocket)
+	}
+}
@@ -0,0 +1,209 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/auth"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 分享关联表，注销时按 share_id 清理
+var shareRelatedModels = []interface{}{
+	&models.ShareItem{},
+	&models.ShareAccessLog{},
+	&models.ShareVisitorInfo{},
+	&models.ShareAccessToken{},
+	&models.ShareUpload{},
+	&models.ShareRecipient{},
+	&models.ShareExclusion{},
+	&models.ShareInvitation{},
+}
+
+// 用户个人数据表，注销时按 user_id 清理
+var userOwnedModels = []interface{}{
+	&models.Share{},
+	&models.ShareRecipient{},
+	&models.ShareTemplate{},
+	&models.Folder{},
+	&models.ActivityLog{},
+	&models.FileDownloadLog{},
+	&models.APIKey{},
+	&models.UserPasskey{},
+	&models.UserSession{},
+	&models.UserLoginFootprint{},
+	&models.UserAccessControl{},
+	&models.UserBandwidthUsage{},
+	&models.UserTagReference{},
+	&models.FileCategory{},
+	&models.RandomImageAPI{},
+	&models.SearchFeedback{},
+	&models.Message{},
+	&models.AISuggestion{},
+	&models.ReviewAppeal{},
+	&models.UploadSession{},
+	&models.PasswordResetToken{},
+	&models.UserSettings{},
+	&models.UserUsageStats{},
+	&models.AnnouncementRead{},
+	&models.UserQuotaGrant{},
+	&models.TelegramBinding{},
+}
+
+/* ScheduleAccountDeletion 申请注销账号，冷静期结束后删除全部数据；有密码的账号需验证密码，其余账号需输入用户名确认 */
+func ScheduleAccountDeletion(userID uint, password, confirm string) (*common.JSONTime, error) {
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	if user.IsSuperAdmin() {
+		return nil, errors.New(errors.CodeForbidden, "超级管理员不能注销账号")
+	}
+	if user.IsDeletionScheduled() {
+		return nil, errors.New(errors.CodeInvalidRequest, "账号已在注销冷静期内")
+	}
+
+	if user.Password != "" {
+		if !utils.ComparePasswords(user.Password, password) {
+			return nil, errors.New(errors.CodeWrongPassword, "密码错误")
+		}
+	} else if confirm != user.Username {
+		return nil, errors.New(errors.CodeInvalidParameter, "请输入用户名以确认注销")
+	}
+
+	graceDays := setting.GetInt("security", "account_deletion_grace_days", 7)
+	if graceDays < 0 {
+		graceDays = 0
+	}
+	scheduledAt := common.JSONTime(time.Now().AddDate(0, 0, graceDays))
+
+	if err := db.Model(&user).Update("deletion_scheduled_at", &scheduledAt).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "申请注销失败")
+	}
+
+	logger.Info("用户申请注销账号: userID=%d, 计划删除时间=%s", userID, time.Time(scheduledAt).Format(time.RFC3339))
+	return &scheduledAt, nil
+}
+
+/* CancelAccountDeletion 冷静期内撤销注销 */
+func CancelAccountDeletion(userID uint) error {
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	if !user.IsDeletionScheduled() {
+		return errors.New(errors.CodeInvalidRequest, "账号未申请注销")
+	}
+
+	if err := db.Model(&user).Update("deletion_scheduled_at", nil).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "撤销注销失败")
+	}
+
+	logger.Info("用户撤销注销账号: userID=%d", userID)
+	return nil
+}
+
+/* PurgeDueAccounts 删除冷静期已结束的账号数据，返回处理的账号数 */
+func PurgeDueAccounts() (int, error) {
+	var userIDs []uint
+	if err := database.GetDB().Model(&models.User{}).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", time.Now()).
+		Pluck("id", &userIDs).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询待注销账号失败")
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if err := PurgeAccount(userID); err != nil {
+			logger.Error("注销账号清理数据失败，将在下次任务重试: userID=%d, error=%v", userID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+/* PurgeAccount 删除账号的文件、向量、AI信息、分享与日志，并匿名化用户记录 */
+func PurgeAccount(userID uint) error {
+	if _, err := auth.RevokeUserSessions(userID, ""); err != nil {
+		logger.Warn("注销账号时注销登录会话失败: userID=%d, error=%v", userID, err)
+	}
+
+	fileCount, err := filesvc.PurgeUserFiles(userID)
+	if err != nil {
+		return err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var shareIDs []string
+		if err := tx.Model(&models.Share{}).Where("user_id = ?", userID).Pluck("id", &shareIDs).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBQueryFailed, "查询用户分享失败")
+		}
+		if len(shareIDs) > 0 {
+			for _, model := range shareRelatedModels {
+				if err := tx.Unscoped().Where("share_id IN ?", shareIDs).Delete(model).Error; err != nil {
+					return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除分享数据失败")
+				}
+			}
+		}
+
+		for _, model := range userOwnedModels {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除用户数据失败")
+			}
+		}
+
+		if err := tx.Where("target_type = ? AND target_id = ?", models.AnnouncementTargetUser, userID).
+			Delete(&models.AnnouncementTarget{}).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除公告投放对象失败")
+		}
+
+		// 已注册用户仍引用邀请码记录，只停用不删除
+		if err := tx.Model(&models.InvitationCode{}).Where("creator_id = ?", userID).
+			Update("status", models.InvitationCodeStatusDisabled).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBUpdateFailed, "停用邀请码失败")
+		}
+
+		// 保留用户记录以免历史引用失效，但清除全部可识别个人身份的信息
+		return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"username":                 fmt.Sprintf("deleted_%d", userID),
+			"email":                    fmt.Sprintf("deleted_%d@deleted.invalid", userID),
+			"password":                 "",
+			"avatar":                   "",
+			"bio":                      "",
+			"website":                  "",
+			"github_id":                nil,
+			"google_id":                nil,
+			"linuxdo_id":               nil,
+			"ldap_dn":                  nil,
+			"saml_id":                  nil,
+			"last_activity_ip":         "",
+			"pending_email":            "",
+			"pending_email_expires_at": nil,
+			"path_alias":               fmt.Sprintf("deleted_%d", userID), // 路径别名出现在文件地址中，唯一索引不允许置空
+			"status":                   common.UserStatusDeleted,
+			"deletion_scheduled_at":    nil,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	removeDataExport(userID)
+
+	logger.Info("账号注销完成: userID=%d, 删除文件数=%d", userID, fileCount)
+	return nil
+}
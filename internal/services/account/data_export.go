@@ -0,0 +1,334 @@
+package account
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+// 导出任务状态
+const (
+	ExportStatusPending = "pending"
+	ExportStatusPacking = "packing"
+	ExportStatusReady   = "ready"
+	ExportStatusFailed  = "failed"
+)
+
+const dataExportDir = "temp/data_exports"
+
+// DataExportJob 个人数据导出任务，每个用户同时只保留最近一次
+type DataExportJob struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"`
+	ArchiveSize int64     `json:"archive_size"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	userID uint
+	path   string
+}
+
+var (
+	exportJobsMu sync.Mutex
+	exportJobs   = make(map[uint]*DataExportJob)
+	exportSlot   = make(chan struct{}, 1) // 导出需要全量查询用户数据，串行执行避免拖慢数据库
+)
+
+// exportSection 导出包中的一个JSON文件
+type exportSection struct {
+	name  string
+	query func(userID uint) (interface{}, error)
+}
+
+var exportSections = []exportSection{
+	{"profile.json", exportProfile},
+	{"files.json", exportFiles},
+	{"folders.json", exportFolders},
+	{"shares.json", exportShares},
+	{"activity_logs.json", exportActivityLogs},
+	{"download_logs.json", exportDownloadLogs},
+	{"login_sessions.json", exportSessions},
+	{"api_keys.json", exportAPIKeys},
+	{"passkeys.json", exportPasskeys},
+}
+
+// CreateDataExportJob 创建数据导出任务，已有进行中的任务时直接返回该任务
+func CreateDataExportJob(userID uint) (*DataExportJob, error) {
+	ttl := setting.GetInt("security", "data_export_ttl_hours", 24)
+	if ttl <= 0 {
+		ttl = 24
+	}
+
+	exportJobsMu.Lock()
+	defer exportJobsMu.Unlock()
+
+	if old, ok := exportJobs[userID]; ok {
+		if old.Status == ExportStatusPending || old.Status == ExportStatusPacking {
+			snapshot := *old
+			return &snapshot, nil
+		}
+		if old.path != "" {
+			os.Remove(old.path)
+		}
+	}
+
+	now := time.Now()
+	job := &DataExportJob{
+		ID:        utils.GenerateRandomString(32),
+		Status:    ExportStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(ttl) * time.Hour),
+		userID:    userID,
+	}
+	exportJobs[userID] = job
+	snapshot := *job
+
+	go runDataExportJob(job)
+	return &snapshot, nil
+}
+
+// GetDataExportJob 查询用户最近一次导出任务
+func GetDataExportJob(userID uint) (*DataExportJob, error) {
+	exportJobsMu.Lock()
+	defer exportJobsMu.Unlock()
+
+	job, ok := exportJobs[userID]
+	if !ok {
+		return nil, errors.New(errors.CodeNotFound, "暂无数据导出任务")
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// OpenDataExport 打开已完成的导出包，调用方负责关闭
+func OpenDataExport(userID uint, jobID string) (*os.File, *DataExportJob, error) {
+	exportJobsMu.Lock()
+	job, ok := exportJobs[userID]
+	var snapshot DataExportJob
+	if ok {
+		snapshot = *job
+	}
+	exportJobsMu.Unlock()
+
+	if !ok || snapshot.ID != jobID {
+		return nil, nil, errors.New(errors.CodeNotFound, "导出任务不存在或已过期")
+	}
+	if snapshot.Status != ExportStatusReady {
+		return nil, nil, errors.New(errors.CodeInvalidParameter, "数据导出尚未完成")
+	}
+
+	f, err := os.Open(snapshot.path)
+	if err != nil {
+		return nil, nil, errors.New(errors.CodeNotFound, "导出文件已过期")
+	}
+	return f, &snapshot, nil
+}
+
+// CleanExpiredDataExports 删除过期的导出包及任务
+func CleanExpiredDataExports() int {
+	now := time.Now()
+	var expired []*DataExportJob
+
+	exportJobsMu.Lock()
+	for userID, job := range exportJobs {
+		if now.After(job.ExpiresAt) && job.Status != ExportStatusPacking {
+			expired = append(expired, job)
+			delete(exportJobs, userID)
+		}
+	}
+	exportJobsMu.Unlock()
+
+	for _, job := range expired {
+		if job.path != "" {
+			os.Remove(job.path)
+		}
+	}
+	return len(expired)
+}
+
+// removeDataExport 删除用户的导出包，注销账号清理数据时调用
+func removeDataExport(userID uint) {
+	exportJobsMu.Lock()
+	job, ok := exportJobs[userID]
+	if ok {
+		delete(exportJobs, userID)
+	}
+	exportJobsMu.Unlock()
+
+	if ok && job.path != "" {
+		os.Remove(job.path)
+	}
+}
+
+func updateExportJob(job *DataExportJob, fn func(j *DataExportJob)) {
+	exportJobsMu.Lock()
+	defer exportJobsMu.Unlock()
+	fn(job)
+}
+
+func runDataExportJob(job *DataExportJob) {
+	exportSlot <- struct{}{}
+	defer func() { <-exportSlot }()
+
+	updateExportJob(job, func(j *DataExportJob) { j.Status = ExportStatusPacking })
+
+	path, size, err := writeDataExport(job)
+	if err != nil {
+		logger.Error("个人数据导出失败: userID=%d, error=%v", job.userID, err)
+		if path != "" {
+			os.Remove(path)
+		}
+		updateExportJob(job, func(j *DataExportJob) {
+			j.Status = ExportStatusFailed
+			j.Error = err.Error()
+		})
+		return
+	}
+
+	updateExportJob(job, func(j *DataExportJob) {
+		j.Status = ExportStatusReady
+		j.path = path
+		j.ArchiveSize = size
+	})
+}
+
+// writeDataExport 将各类数据分别序列化为JSON写入ZIP
+func writeDataExport(job *DataExportJob) (string, int64, error) {
+	if err := os.MkdirAll(dataExportDir, 0755); err != nil {
+		return "", 0, fmt.Errorf("创建导出目录失败: %v", err)
+	}
+	path := filepath.Join(dataExportDir, job.ID+".zip")
+	out, err := os.Create(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("创建导出文件失败: %v", err)
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, section := range exportSections {
+		data, err := section.query(job.userID)
+		if err != nil {
+			return path, 0, fmt.Errorf("导出 %s 失败: %v", section.name, err)
+		}
+		content, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return path, 0, fmt.Errorf("序列化 %s 失败: %v", section.name, err)
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     section.name,
+			Method:   zip.Deflate,
+			Modified: job.CreatedAt,
+		})
+		if err != nil {
+			return path, 0, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return path, 0, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return path, 0, fmt.Errorf("写入导出文件失败: %v", err)
+	}
+
+	info, err := out.Stat()
+	if err != nil {
+		return path, 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func exportProfile(userID uint) (interface{}, error) {
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	var settings models.UserSettings
+	db.Where("user_id = ?", userID).First(&settings)
+	var usage models.UserUsageStats
+	db.Where("user_id = ?", userID).First(&usage)
+
+	return map[string]interface{}{
+		"user":        user,
+		"settings":    settings,
+		"usage_stats": usage,
+		"exported_at": time.Now(),
+	}, nil
+}
+
+func exportFiles(userID uint) (interface{}, error) {
+	var files []models.File
+	err := database.GetDB().Preload("AIInfo").Preload("Category").
+		Where("user_id = ?", userID).Order("created_at ASC").Find(&files).Error
+	return files, err
+}
+
+func exportFolders(userID uint) (interface{}, error) {
+	var folders []models.Folder
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&folders).Error
+	return folders, err
+}
+
+func exportShares(userID uint) (interface{}, error) {
+	db := database.GetDB()
+
+	var shares []models.Share
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(shares))
+	for _, s := range shares {
+		var items []models.ShareItem
+		db.Where("share_id = ?", s.ID).Find(&items)
+		result = append(result, map[string]interface{}{
+			"share": s,
+			"items": items,
+		})
+	}
+	return result, nil
+}
+
+func exportActivityLogs(userID uint) (interface{}, error) {
+	var logs []models.ActivityLog
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&logs).Error
+	return logs, err
+}
+
+func exportDownloadLogs(userID uint) (interface{}, error) {
+	var logs []models.FileDownloadLog
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&logs).Error
+	return logs, err
+}
+
+func exportSessions(userID uint) (interface{}, error) {
+	var sessions []models.UserSession
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&sessions).Error
+	return sessions, err
+}
+
+func exportAPIKeys(userID uint) (interface{}, error) {
+	var keys []models.APIKey
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&keys).Error
+	return keys, err
+}
+
+func exportPasskeys(userID uint) (interface{}, error) {
+	var passkeys []models.UserPasskey
+	err := database.GetDB().Where("user_id = ?", userID).Order("created_at ASC").Find(&passkeys).Error
+	return passkeys, err
+}
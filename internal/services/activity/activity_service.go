@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/webhook"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/logger"
 	"sync"
@@ -251,6 +252,11 @@ func LogShareCreate(userID uint, shareID string, shareType string) {
 	}
 
 	globalService.LogActivityAsync(params)
+	webhook.Dispatch(webhook.EventShareCreated, map[string]any{
+		"user_id":    userID,
+		"share_id":   shareID,
+		"share_type": shareType,
+	})
 }
 
 /* LogShareMilestone 记录分享访问里程碑 */
@@ -565,6 +571,18 @@ func LogImageUploadByID(fileID string, folderID string) {
 			folderID,
 			folderName,
 		)
+
+		webhook.Dispatch(webhook.EventFileUploaded, map[string]any{
+			"user_id":       file.UserID,
+			"file_id":       file.ID,
+			"original_name": file.OriginalName,
+			"size":          file.Size,
+			"mime":          file.Mime,
+			"width":         file.Width,
+			"height":        file.Height,
+			"access_level":  file.AccessLevel,
+			"folder_id":     folderID,
+		})
 	}()
 }
 
@@ -657,6 +675,11 @@ func LogUserRegister(userID uint, username string, email string) {
 	}
 
 	globalService.LogActivityAsync(params)
+	webhook.Dispatch(webhook.EventUserRegistered, map[string]any{
+		"user_id":  userID,
+		"username": username,
+		"email":    email,
+	})
 }
 
 /* LogFileDelete 记录单个文件删除 */
@@ -676,6 +699,11 @@ func LogFileDelete(userID uint, fileName string, fileID string) {
 	}
 
 	globalService.LogActivityAsync(params)
+	webhook.Dispatch(webhook.EventFileDeleted, map[string]any{
+		"user_id":   userID,
+		"file_id":   fileID,
+		"file_name": fileName,
+	})
 }
 
 /* LogFileRename 记录文件重命名 */
@@ -781,6 +809,11 @@ func LogShareDelete(userID uint, shareID string, shareType string) {
 	}
 
 	globalService.LogActivityAsync(params)
+	webhook.Dispatch(webhook.EventShareDeleted, map[string]any{
+		"user_id":    userID,
+		"share_id":   shareID,
+		"share_type": shareType,
+	})
 }
 
 /* LogAPIKeyToggleStatus 记录API密钥状态切换 */
@@ -46,7 +46,10 @@ type AITaggingResult struct {
 	SemanticKeywords []string `json:"semantic_keywords"` // 语义关键词数组
 	IsRecommended    bool     `json:"is_recommended"`
 	Tags             []string `json:"tags"`
-	VisualElements   struct {
+	// 置信度（0-1），AI未返回时视为1；用于低置信度结果转为建议
+	TagConfidence       map[string]float64 `json:"tag_confidence"`
+	RecommendConfidence float64            `json:"recommend_confidence"`
+	VisualElements      struct {
 		ColorPalette  []string `json:"color_palette"`
 		Composition   string   `json:"composition"`
 		DominantColor string   `json:"dominant_color"`
@@ -97,7 +100,8 @@ func AiImageTaggingAndSaveWithBase64(file models.File, base64Data, imageFormat s
 		logger.Warn("保存分类结果失败，继续执行: %v", err)
 	}
 
-	if err := processAIResponse(db, file, aiResponse, contentDetectionEnabled, sensitiveContentHandling, base64Data, imageFormat); err != nil {
+	event, err := processAIResponse(db, file, aiResponse, contentDetectionEnabled, sensitiveContentHandling, base64Data, imageFormat)
+	if err != nil {
 		logger.Warn("保存标签结果失败，继续执行: %v", err)
 	}
 
@@ -106,6 +110,9 @@ func AiImageTaggingAndSaveWithBase64(file models.File, base64Data, imageFormat s
 		return err
 	}
 
+	if event != nil {
+		notifyAICompletion(file.UserID, *event)
+	}
 	return nil
 }
 
@@ -127,8 +134,8 @@ func isDeadlockError(err error) bool {
 	return strings.Contains(s, "deadlock found") || strings.Contains(s, "error 1213")
 }
 
-// processAIResponse 处理AI响应并保存结果
-func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, contentDetectionEnabled bool, sensitiveContentHandling string, base64Data string, imageFormat string) error {
+// processAIResponse 处理AI响应并保存结果，返回的完成通知由调用方在写入提交后发送
+func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, contentDetectionEnabled bool, sensitiveContentHandling string, base64Data string, imageFormat string) (*AICompletionEvent, error) {
 	if aiResp == nil || !aiResp.Success {
 		errMsg := "AI分析返回无效结果"
 		if aiResp != nil && aiResp.ErrMsg != "" {
@@ -159,19 +166,25 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 			}
 
 			updateFileStatus(tx, file.ID, common.AITaggingStatusDone)
-			return fmt.Errorf("文件 %s 疑似违规内容，AI拒绝处理", file.ID)
+			return &AICompletionEvent{
+				FileID:     file.ID,
+				FileName:   file.DisplayName,
+				Status:     common.AITaggingStatusDone,
+				IsNSFW:     true,
+				NSFWReason: aiResp.ErrMsg,
+			}, fmt.Errorf("文件 %s 疑似违规内容，AI拒绝处理", file.ID)
 		}
 
 		logger.Error(errMsg)
 		updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
-		return errors.New(errMsg)
+		return nil, errors.New(errMsg)
 	}
 
 	result, err := parseAITaggingResult(aiResp.Data)
 	if err != nil {
 		logger.Error("解析AI返回数据失败: %v", err)
 		updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
-		return err
+		return nil, err
 	}
 
 	// 如果原始文件已有分辨率信息，优先使用它而不是AI识别的分辨率
@@ -179,22 +192,57 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 		result.BasicInfo.Resolution = file.Resolution
 	}
 
+	// 分类阈值规则：任一分类评分达到其阈值即视为违规，并按规则中最严格的动作处理
+	if contentDetectionEnabled {
+		matched, categoryAction := evaluateNSFWCategories(result, getNSFWCategoryRules(), sensitiveContentHandling)
+		if len(matched) > 0 {
+			if result.ContentSafety.IsNSFW {
+				sensitiveContentHandling = moreSevereNSFWAction(sensitiveContentHandling, categoryAction)
+			} else {
+				result.ContentSafety.IsNSFW = true
+				sensitiveContentHandling = categoryAction
+			}
+			ruleReason := "命中分类阈值规则: " + strings.Join(matched, ", ")
+			if result.ContentSafety.NSFWReason == "" {
+				result.ContentSafety.NSFWReason = ruleReason
+			} else {
+				result.ContentSafety.NSFWReason += "；" + ruleReason
+			}
+		}
+	}
+
 	// 使用 UPSERT 保存AI信息，自动处理新建或更新
 	_, err = saveFileAIInfo(tx, file.ID, result, aiResp.Usage)
 	if err != nil {
 		if isDeadlockError(err) && !fileExists(tx, file.ID) {
-			return errFileDeleted
+			return nil, errFileDeleted
 		}
 		updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
 		logger.Error("保存AI标记结果失败: %v", err)
-		return err
+		return nil, err
+	}
+
+	// 低置信度的标签/推荐不直接写入，转为待用户确认的建议
+	tagThreshold := getMinConfidence("tag_min_confidence")
+	appliedTags, tagSuggestions := splitTagsByConfidence(result, tagThreshold)
+	if tagThreshold > 0 {
+		replacePendingSuggestions(tx, file, SuggestionTypeTag, tagSuggestions)
+	}
+	applyRecommend := result.IsRecommended
+	if recommendThreshold := getMinConfidence("recommend_min_confidence"); recommendThreshold > 0 {
+		var recommendSuggestions []models.AISuggestion
+		if result.IsRecommended && result.RecommendConfidence < recommendThreshold {
+			applyRecommend = false
+			recommendSuggestions = append(recommendSuggestions, models.AISuggestion{Confidence: result.RecommendConfidence})
+		}
+		replacePendingSuggestions(tx, file, SuggestionTypeRecommend, recommendSuggestions)
 	}
 
 	// AI 推荐结果写回（仅当 AI 判定为推荐时写入，避免覆盖管理员手动推荐/取消）
-	if result != nil && result.IsRecommended {
+	if applyRecommend {
 		if err := tx.Model(&models.File{}).Where("id = ?", file.ID).Update("is_recommended", true).Error; err != nil {
 			if isDeadlockError(err) && !fileExists(tx, file.ID) {
-				return errFileDeleted
+				return nil, errFileDeleted
 			}
 			logger.Warn("更新文件推荐状态失败: %v", err)
 		}
@@ -205,13 +253,13 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 		// 如果没有启用内容检测或不是违规内容，正常处理标签
 		if len(result.Tags) == 0 {
 		}
-		if err := processAndSaveTags(tx, file, result.Tags); err != nil {
+		if err := processAndSaveTags(tx, file, appliedTags); err != nil {
 			if isDeadlockError(err) && !fileExists(tx, file.ID) {
-				return errFileDeleted
+				return nil, errFileDeleted
 			}
 			updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
 			logger.Error("保存AI标记结果失败: %v", err)
-			return err
+			return nil, err
 		}
 	} else {
 		// 根据敏感内容处理方式处理
@@ -224,23 +272,23 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 				logger.Error("标记文件为待审核失败: %v", err)
 			}
 			// 仍然保存标签，以便管理员审核时查看
-			if err := processAndSaveTags(tx, file, result.Tags); err != nil {
+			if err := processAndSaveTags(tx, file, appliedTags); err != nil {
 				if isDeadlockError(err) && !fileExists(tx, file.ID) {
-					return errFileDeleted
+					return nil, errFileDeleted
 				}
 				updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
 				logger.Error("保存AI标记结果失败: %v", err)
-				return err
+				return nil, err
 			}
 		} else {
 			// 仅标记模式（默认），仍然保存标签
-			if err := processAndSaveTags(tx, file, result.Tags); err != nil {
+			if err := processAndSaveTags(tx, file, appliedTags); err != nil {
 				if isDeadlockError(err) && !fileExists(tx, file.ID) {
-					return errFileDeleted
+					return nil, errFileDeleted
 				}
 				updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
 				logger.Error("保存AI标记结果失败: %v", err)
-				return err
+				return nil, err
 			}
 		}
 	}
@@ -249,11 +297,11 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 	if contentDetectionEnabled && result.ContentSafety.IsNSFW {
 		if err := updateFileNSFWStatus(tx, file.ID, true); err != nil {
 			if isDeadlockError(err) && !fileExists(tx, file.ID) {
-				return errFileDeleted
+				return nil, errFileDeleted
 			}
 			updateFileStatus(tx, file.ID, common.AITaggingStatusFailed)
 			logger.Error("保存AI标记结果失败: %v", err)
-			return err
+			return nil, err
 		}
 	}
 
@@ -288,7 +336,8 @@ func processAIResponse(tx *gorm.DB, file models.File, aiResp *AIFileResponse, co
 
 	go propagateAIToDuplicates(file.ID)
 
-	return nil
+	event := completionEventForResult(file, result, appliedTags)
+	return &event, nil
 }
 
 // propagateAIToDuplicates 将原图的AI信息/标签/分类传播给其重复文件
@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"pixelpunk/internal/models"
+	tagService "pixelpunk/internal/services/tag"
+	"pixelpunk/pkg/ai"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+// ListAISuggestions 获取用户待确认的AI建议，fileID 为空时返回全部文件
+func ListAISuggestions(userID uint, fileID string, page, limit int) (map[string]interface{}, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	db := GetDBFromContext()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	query := db.Model(&models.AISuggestion{}).Where("user_id = ? AND status = ?", userID, SuggestionStatusPending)
+	if fileID != "" {
+		query = query.Where("file_id = ?", fileID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询AI建议总数失败")
+	}
+
+	var suggestions []models.AISuggestion
+	if err := query.Order("created_at DESC, id ASC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&suggestions).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询AI建议失败")
+	}
+
+	return map[string]interface{}{
+		"suggestions": suggestions,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}, nil
+}
+
+// AcceptAISuggestion 用户确认AI建议并写入文件
+func AcceptAISuggestion(userID, suggestionID uint) error {
+	db := GetDBFromContext()
+	if db == nil {
+		return errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	suggestion, file, err := getPendingSuggestion(db, userID, suggestionID)
+	if err != nil {
+		return err
+	}
+
+	switch suggestion.Type {
+	case SuggestionTypeTag:
+		globalTags, err := tagService.NewGlobalTagService().CreateTagsFromNames([]string{suggestion.Value}, file.UserID, "ai")
+		if err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "创建标签失败")
+		}
+		tagIDs := make([]uint, 0, len(globalTags))
+		for _, tag := range globalTags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		if err := tagService.NewFileGlobalTagService().AddTagsToFile(file.ID, tagIDs, "ai", suggestion.Confidence); err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "保存文件标签失败")
+		}
+	case SuggestionTypeCategory:
+		if err := applyCategoryResult(db, *file, &ai.FileCategorizationResponse{
+			Success:             true,
+			CategoryID:          suggestion.CategoryID,
+			CategoryName:        suggestion.Value,
+			CategoryDescription: suggestion.CategoryDescription,
+			Confidence:          suggestion.Confidence,
+		}); err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "保存文件分类失败")
+		}
+	case SuggestionTypeRecommend:
+		if err := db.Model(&models.File{}).Where("id = ?", file.ID).Update("is_recommended", true).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新推荐状态失败")
+		}
+		go vector.SyncFileMeta(file.ID)
+	default:
+		return errors.New(errors.CodeInvalidParameter, "不支持的建议类型")
+	}
+
+	return updateSuggestionStatus(db, suggestion.ID, SuggestionStatusAccepted)
+}
+
+// RejectAISuggestion 用户拒绝AI建议
+func RejectAISuggestion(userID, suggestionID uint) error {
+	db := GetDBFromContext()
+	if db == nil {
+		return errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	suggestion, _, err := getPendingSuggestion(db, userID, suggestionID)
+	if err != nil {
+		return err
+	}
+	return updateSuggestionStatus(db, suggestion.ID, SuggestionStatusRejected)
+}
+
+func getPendingSuggestion(db *gorm.DB, userID, suggestionID uint) (*models.AISuggestion, *models.File, error) {
+	var suggestion models.AISuggestion
+	if err := db.Where("id = ? AND user_id = ?", suggestionID, userID).Take(&suggestion).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeNotFound, "AI建议不存在")
+		}
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询AI建议失败")
+	}
+	if suggestion.Status != SuggestionStatusPending {
+		return nil, nil, errors.New(errors.CodeInvalidParameter, "该建议已处理")
+	}
+
+	var file models.File
+	if err := db.Where("id = ? AND user_id = ?", suggestion.FileID, userID).Take(&file).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeFileNotFound, "文件不存在")
+		}
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	return &suggestion, &file, nil
+}
+
+func updateSuggestionStatus(db *gorm.DB, id uint, status string) error {
+	if err := db.Model(&models.AISuggestion{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新AI建议状态失败")
+	}
+	return nil
+}
@@ -170,34 +170,7 @@ func InitGlobalTaggingQueue() error {
 		_, _ = EnqueueAllPending(1000)
 	}()
 
-	go func() {
-		ticker := time.NewTicker(60 * time.Second)
-		defer ticker.Stop()
-		for range ticker.C {
-			if s := GetGlobalTaggingService(); s != nil {
-				threshold := setting.GetInt("ai", "pending_stuck_threshold_minutes", 5)
-				count, err := ResetStuckPendingFiles(threshold)
-				if err != nil {
-					continue
-				}
-				if count > 0 {
-					if !s.IsPaused() {
-						_, _ = EnqueueAllPending(1000)
-					}
-				}
-			}
-		}
-	}()
-
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
-		for range ticker.C {
-			retention := setting.GetInt("ai", "ai_job_retention_days", 14)
-			_, _ = CleanOldAIJobs(retention)
-		}
-	}()
-
+	// 卡住任务重置与任务记录清理由定时任务管理器调度（ai_reset_stuck、ai_job_retention）
 	return nil
 }
 
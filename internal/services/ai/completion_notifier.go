@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/controllers/websocket"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/webhook"
+	ws "pixelpunk/internal/websocket"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+const (
+	// 同一用户在窗口期内完成的文件合并为一次通知（批量上传时避免消息风暴）
+	completionFlushDelay   = 2 * time.Second
+	completionMaxBatchSize = 100
+	completionWebhookEvent = "ai.tagging.completed"
+)
+
+// AICompletionEvent 单个文件的AI处理结果通知
+type AICompletionEvent struct {
+	FileID     string   `json:"file_id"`
+	FileName   string   `json:"file_name"`
+	Status     string   `json:"status"` // done/failed
+	Tags       []string `json:"tags,omitempty"`
+	IsNSFW     bool     `json:"is_nsfw"`
+	NSFWScore  float64  `json:"nsfw_score"`
+	NSFWReason string   `json:"nsfw_reason,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type completionNotifier struct {
+	mu      sync.Mutex
+	pending map[uint][]AICompletionEvent
+	timers  map[uint]*time.Timer
+	client  *http.Client
+}
+
+var globalCompletionNotifier = &completionNotifier{
+	pending: make(map[uint][]AICompletionEvent),
+	timers:  make(map[uint]*time.Timer),
+	client:  utils.NewPublicHTTPClient(10 * time.Second),
+}
+
+// notifyAICompletion 记录文件AI处理完成事件，延迟合并后推送给上传用户
+func notifyAICompletion(userID uint, event AICompletionEvent) {
+	if userID == 0 {
+		return
+	}
+	n := globalCompletionNotifier
+
+	n.mu.Lock()
+	n.pending[userID] = append(n.pending[userID], event)
+	if len(n.pending[userID]) >= completionMaxBatchSize {
+		events := n.takeLocked(userID)
+		n.mu.Unlock()
+		go n.deliver(userID, events)
+		return
+	}
+	if _, ok := n.timers[userID]; !ok {
+		n.timers[userID] = time.AfterFunc(completionFlushDelay, func() {
+			n.mu.Lock()
+			events := n.takeLocked(userID)
+			n.mu.Unlock()
+			n.deliver(userID, events)
+		})
+	}
+	n.mu.Unlock()
+}
+
+func (n *completionNotifier) takeLocked(userID uint) []AICompletionEvent {
+	if t, ok := n.timers[userID]; ok {
+		t.Stop()
+		delete(n.timers, userID)
+	}
+	events := n.pending[userID]
+	delete(n.pending, userID)
+	return events
+}
+
+func (n *completionNotifier) deliver(userID uint, events []AICompletionEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"event":     completionWebhookEvent,
+		"count":     len(events),
+		"files":     events,
+		"timestamp": time.Now().Unix(),
+	}
+
+	websocket.SendToUser(userID, ws.MessageTypeAITagging, payload)
+	webhook.Dispatch(webhook.EventAITaggingCompleted, map[string]interface{}{
+		"user_id": userID,
+		"count":   len(events),
+		"files":   events,
+	})
+
+	db := database.GetDB()
+	if db == nil {
+		return
+	}
+	var settings models.UserSettings
+	if err := db.Select("ai_webhook_url, ai_webhook_secret").Where("user_id = ?", userID).Take(&settings).Error; err != nil {
+		return
+	}
+	if settings.AIWebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, settings.AIWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		logger.Warn("构建AI完成回调请求失败: user=%d err=%v", userID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-PixelPunk-Event", completionWebhookEvent)
+	if settings.AIWebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(settings.AIWebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-PixelPunk-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		logger.Warn("AI完成回调失败: user=%d err=%v", userID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		logger.Warn("AI完成回调返回异常状态: user=%d status=%d", userID, resp.StatusCode)
+	}
+}
+
+// completionEventForResult 根据AI解析结果构造完成通知
+func completionEventForResult(file models.File, result *AITaggingResult, tags []string) AICompletionEvent {
+	event := AICompletionEvent{
+		FileID:   file.ID,
+		FileName: file.DisplayName,
+		Status:   common.AITaggingStatusDone,
+		Tags:     tags,
+	}
+	if result != nil {
+		event.IsNSFW = result.ContentSafety.IsNSFW
+		event.NSFWScore = result.ContentSafety.NSFWScore
+		event.NSFWReason = result.ContentSafety.NSFWReason
+	}
+	return event
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// AI建议类型与状态
+const (
+	SuggestionTypeTag       = "tag"
+	SuggestionTypeCategory  = "category"
+	SuggestionTypeRecommend = "recommend"
+
+	SuggestionStatusPending  = "pending"
+	SuggestionStatusAccepted = "accepted"
+	SuggestionStatusRejected = "rejected"
+)
+
+// getMinConfidence 读取自动应用的最低置信度，0 表示不限制
+func getMinConfidence(key string) float64 {
+	v := setting.GetFloat("ai", key, 0)
+	if v < 0 || v > 1 {
+		return 0
+	}
+	return v
+}
+
+// fillAIConfidences 从原始数据提取置信度，AI未返回时视为完全确定
+func fillAIConfidences(rawData map[string]interface{}, result *AITaggingResult) {
+	if m, ok := rawData["tag_confidence"].(map[string]interface{}); ok {
+		if result.TagConfidence == nil {
+			result.TagConfidence = make(map[string]float64, len(m))
+		}
+		for name, v := range m {
+			if c, ok := v.(float64); ok {
+				result.TagConfidence[strings.TrimSpace(name)] = c
+			}
+		}
+	}
+
+	result.RecommendConfidence = 1
+	if v, ok := rawData["recommend_confidence"].(float64); ok && v >= 0 && v <= 1 {
+		result.RecommendConfidence = v
+	}
+}
+
+// tagConfidence 获取单个标签的置信度（未返回时为1）
+func tagConfidence(result *AITaggingResult, tag string) float64 {
+	if c, ok := result.TagConfidence[tag]; ok && c >= 0 && c <= 1 {
+		return c
+	}
+	return 1
+}
+
+// splitTagsByConfidence 按阈值拆分为直接应用的标签与需用户确认的建议
+func splitTagsByConfidence(result *AITaggingResult, threshold float64) ([]string, []models.AISuggestion) {
+	if threshold <= 0 {
+		return result.Tags, nil
+	}
+	applied := make([]string, 0, len(result.Tags))
+	var suggestions []models.AISuggestion
+	for _, tag := range result.Tags {
+		c := tagConfidence(result, tag)
+		if c >= threshold {
+			applied = append(applied, tag)
+			continue
+		}
+		suggestions = append(suggestions, models.AISuggestion{Type: SuggestionTypeTag, Value: tag, Confidence: c})
+	}
+	return applied, suggestions
+}
+
+// replacePendingSuggestions 替换文件某类型的待确认建议（重新打标时旧建议失效）
+func replacePendingSuggestions(tx *gorm.DB, file models.File, suggestionType string, suggestions []models.AISuggestion) {
+	if err := tx.Where("file_id = ? AND type = ? AND status = ?", file.ID, suggestionType, SuggestionStatusPending).
+		Delete(&models.AISuggestion{}).Error; err != nil {
+		logger.Warn("清理旧的AI建议失败: file=%s type=%s err=%v", file.ID, suggestionType, err)
+		return
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+	for i := range suggestions {
+		suggestions[i].FileID = file.ID
+		suggestions[i].UserID = file.UserID
+		suggestions[i].Type = suggestionType
+		suggestions[i].Status = SuggestionStatusPending
+	}
+	if err := tx.Create(&suggestions).Error; err != nil {
+		logger.Warn("保存AI建议失败: file=%s type=%s err=%v", file.ID, suggestionType, err)
+	}
+}
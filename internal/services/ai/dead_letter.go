@@ -0,0 +1,238 @@
+package ai
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/folder"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const aiJobStatusDead = "dead"
+
+// recordDeadLetterJob 记录耗尽重试的任务
+// DB队列在 Nack(toDLQ) 时已更新 ai_job；Redis 队列的死信只保存文件ID，错误详情统一落到 ai_job 表便于后台查询
+func recordDeadLetterJob(db *gorm.DB, queue qqueue.Queue, fileID string, tries int, lastError string) {
+	if _, ok := queue.(*qqueue.DBQueue); ok {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":      aiJobStatusDead,
+		"tries":       tries,
+		"last_error":  lastError,
+		"lease_until": gorm.Expr("NULL"),
+		"lease_by":    "",
+	}
+	res := db.Model(&models.AIJob{}).Where("file_id = ?", fileID).Updates(updates)
+	if res.Error != nil {
+		logger.Warn("记录死信任务失败: file=%s err=%v", fileID, res.Error)
+		return
+	}
+	if res.RowsAffected == 0 {
+		job := models.AIJob{FileID: fileID, Status: aiJobStatusDead, Tries: tries, LastError: lastError}
+		if err := db.Create(&job).Error; err != nil {
+			logger.Warn("记录死信任务失败: file=%s err=%v", fileID, err)
+		}
+	}
+}
+
+// ListDeadLetterJobs 分页获取死信任务列表
+func ListDeadLetterJobs(fileID string, page, limit int) (map[string]interface{}, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	db := GetDBFromContext()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	query := db.Model(&models.AIJob{}).Where("status = ?", aiJobStatusDead)
+	if fileID != "" {
+		query = query.Where("file_id = ?", fileID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询死信任务总数失败")
+	}
+
+	var jobs []models.AIJob
+	if err := query.Order("updated_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&jobs).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询死信任务失败")
+	}
+
+	fileIDs := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		fileIDs = append(fileIDs, job.FileID)
+	}
+	fileMap := make(map[string]models.File, len(fileIDs))
+	if len(fileIDs) > 0 {
+		var files []models.File
+		if err := db.Select("id, display_name, thumb_url, ai_tagging_status, ai_tagging_tries").
+			Where("id IN ?", fileIDs).Find(&files).Error; err == nil {
+			for _, f := range files {
+				fileMap[f.ID] = f
+			}
+		}
+	}
+
+	items := make([]map[string]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		items = append(items, deadLetterJobToMap(job, fileMap))
+	}
+
+	return map[string]interface{}{
+		"jobs": items,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}, nil
+}
+
+// GetDeadLetterJobDetail 获取单个死信任务详情（含最近的打标日志）
+func GetDeadLetterJobDetail(id uint) (map[string]interface{}, error) {
+	db := GetDBFromContext()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	var job models.AIJob
+	if err := db.Where("id = ? AND status = ?", id, aiJobStatusDead).Take(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "死信任务不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询死信任务失败")
+	}
+
+	fileMap := make(map[string]models.File, 1)
+	var file models.File
+	if err := db.Select("id, display_name, thumb_url, ai_tagging_status, ai_tagging_tries").
+		Where("id = ?", job.FileID).Take(&file).Error; err == nil {
+		fileMap[file.ID] = file
+	}
+
+	var logs []models.FileTaggingLog
+	if err := db.Where("file_id = ?", job.FileID).
+		Order("created_at DESC").
+		Limit(10).
+		Find(&logs).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询打标日志失败")
+	}
+
+	result := deadLetterJobToMap(job, fileMap)
+	result["logs"] = logs
+	return result, nil
+}
+
+// RequeueDeadLetterJobs 将死信任务重新入队；all=true 时忽略 ids 重新入队全部死信
+func RequeueDeadLetterJobs(ids []uint, all bool) (int, error) {
+	db := GetDBFromContext()
+	if db == nil {
+		return 0, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	globalService := GetGlobalTaggingService()
+	if globalService == nil {
+		if err := InitGlobalTaggingQueue(); err != nil {
+			return 0, errors.Wrap(err, errors.CodeInternal, "初始化AI队列失败")
+		}
+		globalService = GetGlobalTaggingService()
+		if globalService == nil || globalService.taskQueue == nil {
+			return 0, errors.New(errors.CodeInternal, "AI队列未初始化")
+		}
+	}
+
+	query := db.Model(&models.AIJob{}).Where("status = ?", aiJobStatusDead)
+	if !all {
+		if len(ids) == 0 {
+			return 0, errors.New(errors.CodeInvalidParameter, "请选择需要重新入队的任务")
+		}
+		query = query.Where("id IN ?", ids)
+	}
+
+	var jobs []models.AIJob
+	if err := query.Find(&jobs).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询死信任务失败")
+	}
+
+	// 用户或所在文件夹已关闭AI处理的文件保留在死信中，不再重新入队
+	fileIDs := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		fileIDs = append(fileIDs, job.FileID)
+	}
+	allowed := make(map[string]bool, len(fileIDs))
+	for _, id := range folder.FilterAIProcessingAllowed(fileIDs) {
+		allowed[id] = true
+	}
+
+	count := 0
+	for _, job := range jobs {
+		if !allowed[job.FileID] {
+			continue
+		}
+		// 重置文件重试计数，否则会再次直接进入死信
+		if err := db.Model(&models.File{}).Where("id = ?", job.FileID).Updates(map[string]interface{}{
+			"ai_tagging_status":    common.AITaggingStatusPending,
+			"ai_tagging_tries":     0,
+			"ai_last_heartbeat_at": nil,
+		}).Error; err != nil {
+			logger.Warn("重置死信文件状态失败: file=%s err=%v", job.FileID, err)
+			continue
+		}
+		if err := db.Model(&models.AIJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"status":      "queued",
+			"tries":       0,
+			"priority":    qqueue.PriorityHigh,
+			"lease_until": gorm.Expr("NULL"),
+			"lease_by":    "",
+		}).Error; err != nil {
+			logger.Warn("重置死信任务失败: job=%d err=%v", job.ID, err)
+			continue
+		}
+		// Redis 队列的死信保留去重标记，需移出DLQ后才能重新入队
+		if qqueue.RequeueDeadIDs(globalService.taskQueue, []string{job.FileID}, qqueue.PriorityHigh) == 0 {
+			logger.Warn("死信任务重新入队失败: file=%s", job.FileID)
+			continue
+		}
+		count++
+	}
+
+	if count > 0 {
+		globalService.notifyQueueStatsChange()
+	}
+	return count, nil
+}
+
+func deadLetterJobToMap(job models.AIJob, fileMap map[string]models.File) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":         job.ID,
+		"file_id":    job.FileID,
+		"status":     job.Status,
+		"tries":      job.Tries,
+		"last_error": job.LastError,
+		"created_at": job.CreatedAt.Format(time.DateTime),
+		"updated_at": job.UpdatedAt.Format(time.DateTime),
+	}
+	if f, ok := fileMap[job.FileID]; ok {
+		item["file_name"] = f.DisplayName
+		item["thumb_url"] = f.ThumbURL
+		item["ai_tagging_status"] = f.AITaggingStatus
+		item["ai_tagging_tries"] = f.AITaggingTries
+	}
+	return item
+}
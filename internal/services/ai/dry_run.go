@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"fmt"
+	mathrand "math/rand"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/ai"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/storage"
+)
+
+const (
+	dryRunMaxSamples     = 20
+	dryRunCandidatePool  = 500
+	dryRunDefaultSamples = 5
+)
+
+// DryRunItem 单个样本的试运行结果（不落库）
+type DryRunItem struct {
+	FileID          string                         `json:"file_id"`
+	FileName        string                         `json:"file_name"`
+	Success         bool                           `json:"success"`
+	Error           string                         `json:"error,omitempty"`
+	Category        *ai.FileCategorizationResponse `json:"category,omitempty"`
+	RawResponse     string                         `json:"raw_response,omitempty"`
+	Parsed          *AITaggingResult               `json:"parsed,omitempty"`
+	NSFWRuleMatches []string                       `json:"nsfw_rule_matches,omitempty"`
+	NSFWAction      string                         `json:"nsfw_action,omitempty"`
+	Usage           *TokenUsage                    `json:"usage,omitempty"`
+	HttpDuration    int64                          `json:"http_duration"`
+	Duration        int64                          `json:"duration"`
+}
+
+// DryRunAIConfig 使用当前AI配置对样本文件执行分类+打标，只返回原始结果，不写入任何数据
+// fileIDs 为空时从图片文件中随机抽取 sampleSize 个样本
+func DryRunAIConfig(fileIDs []string, sampleSize int) (map[string]interface{}, error) {
+	db := GetDBFromContext()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	if sampleSize <= 0 {
+		sampleSize = dryRunDefaultSamples
+	}
+	if sampleSize > dryRunMaxSamples {
+		sampleSize = dryRunMaxSamples
+	}
+
+	var files []models.File
+	if len(fileIDs) > 0 {
+		if len(fileIDs) > dryRunMaxSamples {
+			fileIDs = fileIDs[:dryRunMaxSamples]
+		}
+		if err := db.Where("id IN ?", fileIDs).Find(&files).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询样本文件失败")
+		}
+	} else {
+		var candidateIDs []string
+		if err := db.Model(&models.File{}).
+			Where("file_type = ? AND status = ?", models.FileTypeImage, "active").
+			Order("created_at DESC").
+			Limit(dryRunCandidatePool).
+			Pluck("id", &candidateIDs).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询候选样本失败")
+		}
+		mathrand.Shuffle(len(candidateIDs), func(i, j int) {
+			candidateIDs[i], candidateIDs[j] = candidateIDs[j], candidateIDs[i]
+		})
+		if len(candidateIDs) > sampleSize {
+			candidateIDs = candidateIDs[:sampleSize]
+		}
+		if len(candidateIDs) > 0 {
+			if err := db.Where("id IN ?", candidateIDs).Find(&files).Error; err != nil {
+				return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询样本文件失败")
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New(errors.CodeNotFound, "没有可用于试运行的样本文件")
+	}
+
+	// 复用全局服务的存储读取能力；服务未启动（如自动处理未开启）时使用临时实例
+	reader := GetGlobalTaggingService()
+	if reader == nil {
+		reader = &TaggingService{db: db, storage: storage.NewGlobalStorage()}
+	}
+
+	rules := getNSFWCategoryRules()
+	defaultAction := getSensitiveContentHandling()
+
+	items := make([]DryRunItem, 0, len(files))
+	succeeded := 0
+	var totalTokens int
+	for _, file := range files {
+		item := dryRunSingleFile(reader, file, rules, defaultAction)
+		if item.Success {
+			succeeded++
+		}
+		if item.Usage != nil {
+			totalTokens += item.Usage.TotalTokens
+		}
+		items = append(items, item)
+	}
+
+	return map[string]interface{}{
+		"total":        len(items),
+		"succeeded":    succeeded,
+		"failed":       len(items) - succeeded,
+		"total_tokens": totalTokens,
+		"items":        items,
+	}, nil
+}
+
+func dryRunSingleFile(reader *TaggingService, file models.File, rules map[string]nsfwCategoryRule, defaultAction string) (item DryRunItem) {
+	start := time.Now()
+	item = DryRunItem{FileID: file.ID, FileName: file.DisplayName}
+	defer func() {
+		item.Duration = time.Since(start).Milliseconds()
+	}()
+
+	base64Data, imageFormat, err := reader.readImageAsBase64(file)
+	if err != nil {
+		item.Error = fmt.Sprintf("读取文件失败: %v", err)
+		return item
+	}
+
+	categoryResult, err := performAIImageCategorizationOutsideTx(file, base64Data, imageFormat)
+	if err != nil {
+		item.Error = fmt.Sprintf("AI分类失败: %v", err)
+		return item
+	}
+	item.Category = categoryResult
+
+	categoryName, categoryDescription, categoryID := buildTaggingContext(categoryResult)
+	aiResp, err := performAITagging(file, base64Data, imageFormat, categoryName, categoryDescription, categoryID)
+	if err != nil {
+		item.Error = fmt.Sprintf("AI标签识别失败: %v", err)
+		return item
+	}
+
+	item.RawResponse = aiResp.RawResponse
+	item.Usage = aiResp.Usage
+	item.HttpDuration = aiResp.HttpDuration
+	if !aiResp.Success {
+		item.Error = aiResp.ErrMsg
+		return item
+	}
+
+	parsed, err := parseAITaggingResult(aiResp.Data)
+	if err != nil {
+		item.Error = fmt.Sprintf("解析AI返回数据失败: %v", err)
+		return item
+	}
+	item.Parsed = parsed
+	item.NSFWRuleMatches, item.NSFWAction = evaluateNSFWCategories(parsed, rules, defaultAction)
+	item.Success = true
+	return item
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/errors"
+)
+
+func init() {
+	qqueue.Register(&qqueue.Descriptor{
+		Kind:  qqueue.KindAITagging,
+		Title: "AI打标",
+		Queue: func() qqueue.Queue {
+			if svc := GetGlobalTaggingService(); svc != nil {
+				return svc.taskQueue
+			}
+			return nil
+		},
+		Policy: taggingRetryPolicy,
+		Paused: func() bool {
+			svc := GetGlobalTaggingService()
+			return svc != nil && svc.IsPaused()
+		},
+		Requeue: requeueDeadTaggingJobs,
+	})
+}
+
+// taggingRetryPolicy 打标任务重试策略，在设置分组 jobs 中按 ai_tagging_ 前缀调整
+func taggingRetryPolicy() qqueue.RetryPolicy {
+	return qqueue.RetryPolicy{
+		MaxAttempts: setting.GetInt("jobs", "ai_tagging_max_attempts", 3),
+		BaseDelay:   time.Duration(setting.GetInt("jobs", "ai_tagging_retry_base_seconds", 1)) * time.Second,
+		MaxDelay:    time.Duration(setting.GetInt("jobs", "ai_tagging_retry_max_seconds", 60)) * time.Second,
+	}.Normalize()
+}
+
+// requeueDeadTaggingJobs 按文件ID重新入队死信打标任务，死信详情统一记录在 ai_job 表
+func requeueDeadTaggingJobs(fileIDs []string) (int, error) {
+	db := GetDBFromContext()
+	if db == nil {
+		return 0, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	var ids []uint
+	if err := db.Model(&models.AIJob{}).
+		Where("file_id IN ? AND status = ?", fileIDs, aiJobStatusDead).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询死信任务失败")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return RequeueDeadLetterJobs(ids, false)
+}
@@ -1,9 +1,32 @@
 package ai
 
 import (
+	"sort"
+	"strings"
+
 	"pixelpunk/internal/services/setting"
 )
 
+// 敏感内容处理动作（与 upload.sensitive_content_handling 取值一致）
+const (
+	nsfwActionMarkOnly      = "mark_only"
+	nsfwActionPendingReview = "pending_review"
+	nsfwActionAutoDelete    = "auto_delete"
+)
+
+// nsfwActionSeverity 动作严重程度，多个分类同时命中时取最严格的动作
+var nsfwActionSeverity = map[string]int{
+	nsfwActionMarkOnly:      1,
+	nsfwActionPendingReview: 2,
+	nsfwActionAutoDelete:    3,
+}
+
+// nsfwCategoryRule 单个NSFW分类的阈值与处理动作
+type nsfwCategoryRule struct {
+	Threshold float64
+	Action    string
+}
+
 // getNSFWThresholdFromSettings 获取当前NSFW阈值配置（直接从数据库读取，绕过缓存）
 func getNSFWThresholdFromSettings() float64 {
 	return setting.GetFloatDirectFromDB("ai", "nsfw_threshold", 0.6)
@@ -18,3 +41,78 @@ func applyNSFWThreshold(nsfwScore float64, aiDecision bool) bool {
 	}
 	return aiDecision
 }
+
+// getNSFWCategoryRules 读取分类阈值规则（ai.nsfw_category_rules）
+// 配置格式: {"violence": {"threshold": 0.7, "action": "pending_review"}, "nudity": {"threshold": 0.8, "action": "auto_delete"}}
+func getNSFWCategoryRules() map[string]nsfwCategoryRule {
+	raw := setting.GetJSONDirectFromDB("ai", "nsfw_category_rules")
+	if len(raw) == 0 {
+		return nil
+	}
+
+	rules := make(map[string]nsfwCategoryRule, len(raw))
+	for category, v := range raw {
+		cfg, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		threshold, ok := cfg["threshold"].(float64)
+		if !ok || threshold <= 0 || threshold > 1 {
+			continue
+		}
+		action, _ := cfg["action"].(string)
+		action = strings.TrimSpace(action)
+		if _, valid := nsfwActionSeverity[action]; !valid {
+			action = ""
+		}
+		rules[strings.TrimSpace(category)] = nsfwCategoryRule{Threshold: threshold, Action: action}
+	}
+	return rules
+}
+
+// nsfwCategoryScores 将分类评分展开为 分类名 -> 分数
+func nsfwCategoryScores(result *AITaggingResult) map[string]float64 {
+	c := result.ContentSafety.Categories
+	return map[string]float64{
+		"nudity":          c.Nudity,
+		"violence":        c.Violence,
+		"hate_speech":     c.HateSpeech,
+		"gambling":        c.Gambling,
+		"alcohol_tobacco": c.AlcoholTobacco,
+	}
+}
+
+// evaluateNSFWCategories 按分类规则评估AI结果
+// 返回命中的分类（按名称排序）以及命中规则中最严格的处理动作；
+// 规则未指定动作时使用 defaultAction
+func evaluateNSFWCategories(result *AITaggingResult, rules map[string]nsfwCategoryRule, defaultAction string) ([]string, string) {
+	if result == nil || len(rules) == 0 {
+		return nil, ""
+	}
+
+	var matched []string
+	action := ""
+	for category, score := range nsfwCategoryScores(result) {
+		rule, ok := rules[category]
+		if !ok || score < rule.Threshold {
+			continue
+		}
+		matched = append(matched, category)
+
+		ruleAction := rule.Action
+		if ruleAction == "" {
+			ruleAction = defaultAction
+		}
+		action = moreSevereNSFWAction(action, ruleAction)
+	}
+	sort.Strings(matched)
+	return matched, action
+}
+
+// moreSevereNSFWAction 返回两个处理动作中更严格的一个
+func moreSevereNSFWAction(a, b string) string {
+	if nsfwActionSeverity[b] > nsfwActionSeverity[a] {
+		return b
+	}
+	return a
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -8,6 +9,7 @@ import (
 
 	"pixelpunk/internal/models"
 	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/folder"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/logger"
 	ai "pixelpunk/pkg/ai"
@@ -80,6 +82,7 @@ type PipelineProcessor struct {
 	resultChan chan *ProcessResult
 
 	aiSemaphore *DynamicSemaphore
+	tracker     *qqueue.Tracker // 已取出未确认的任务，关闭超时时放回队列
 	stopping    bool
 	stopMu      sync.Mutex
 	wg          sync.WaitGroup
@@ -125,6 +128,7 @@ type CategoryResult struct {
 	CategoryID          uint
 	CategoryName        string
 	CategoryDescription string
+	Confidence          float64
 }
 
 func NewPipelineProcessor(service *TaggingService, aiConcurrency int) *PipelineProcessor {
@@ -138,6 +142,7 @@ func NewPipelineProcessor(service *TaggingService, aiConcurrency int) *PipelineP
 		fileChan:            make(chan *FileTask, 200),
 		resultChan:          make(chan *ProcessResult, 200),
 		aiSemaphore:         NewDynamicSemaphore(aiConcurrency),
+		tracker:             qqueue.NewTracker(qqueue.KindAITagging),
 		fileLoaderPoolSize:  10,
 		aiProcessorPoolSize: aiConcurrency,
 		dbSaverPoolSize:     5,
@@ -172,6 +177,11 @@ func (pp *PipelineProcessor) Start() {
 }
 
 func (pp *PipelineProcessor) Stop() {
+	pp.Shutdown(context.Background())
+}
+
+// Shutdown 停止取新任务并等待已取出的任务处理完成，ctx 到期时将未完成的任务放回队列
+func (pp *PipelineProcessor) Shutdown(ctx context.Context) {
 	pp.stopMu.Lock()
 	if pp.stopping {
 		pp.stopMu.Unlock()
@@ -180,10 +190,20 @@ func (pp *PipelineProcessor) Stop() {
 	pp.stopping = true
 	pp.stopMu.Unlock()
 
-	close(pp.taskChan)
-	pp.wg.Wait()
-
-	logger.Info("Pipeline已停止")
+	// taskChan 由 taskFetcher 退出时关闭，避免与发送并发
+	done := make(chan struct{})
+	go func() {
+		pp.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("Pipeline已停止")
+	case <-ctx.Done():
+		fileIDs := pp.tracker.Checkpoint("service shutdown")
+		logger.Warn("Pipeline未在关闭时限内完成，已将 %d 个任务放回队列", len(fileIDs))
+	}
 }
 
 func (pp *PipelineProcessor) IsStopping() bool {
@@ -218,6 +238,7 @@ func (pp *PipelineProcessor) channelCloser() {
 
 func (pp *PipelineProcessor) taskFetcher() {
 	defer pp.wg.Done()
+	defer close(pp.taskChan)
 
 	for !pp.IsStopping() {
 		if pp.service.IsPaused() {
@@ -237,6 +258,7 @@ func (pp *PipelineProcessor) taskFetcher() {
 			continue
 		}
 
+		ack, nack = pp.tracker.Wrap(task.FileID, ack, nack)
 		queueTask := &QueueTask{
 			FileID: task.FileID,
 			Ack:    ack,
@@ -272,6 +294,13 @@ func (pp *PipelineProcessor) fileLoader(workerID int) {
 			continue
 		}
 
+		// 入队后文件可能被移入关闭AI处理的文件夹，或用户关闭了AI处理
+		if !folder.IsAIProcessingAllowed(file.UserID, file.FolderID) {
+			folder.MarkAIProcessingSkipped([]string{file.ID})
+			task.Ack()
+			continue
+		}
+
 		base64Data, imageFormat, err := pp.service.readImageAsBase64(file)
 		if err != nil {
 			if errors.Is(err, errMissingFile) {
@@ -359,6 +388,7 @@ func (pp *PipelineProcessor) processImageWithAI(fileTask *FileTask, result *Proc
 			CategoryID:          categoryResult.CategoryID,
 			CategoryName:        categoryResult.CategoryName,
 			CategoryDescription: categoryResult.CategoryDescription,
+			Confidence:          categoryResult.Confidence,
 		}
 		result.CategoryID = categoryID
 	}
@@ -437,23 +467,25 @@ func (pp *PipelineProcessor) saveBatch(batch []*ProcessResult) {
 				Scan(&currentTries).Error
 
 			currentTries++
-			maxRetries := 3
+			policy := taggingRetryPolicy()
 
-			if currentTries >= maxRetries {
+			if policy.Exhausted(currentTries) {
 				_ = pp.service.db.Model(&models.File{}).
 					Where("id = ?", result.FileID).
 					Updates(map[string]interface{}{
 						"ai_tagging_status": common.AITaggingStatusFailed,
-						"ai_tagging_tries":  maxRetries,
+						"ai_tagging_tries":  currentTries,
 					}).Error
-				result.Ack()
+				// 重试耗尽进入死信，由后台查看错误详情后手动重新入队
+				result.Nack(0, true, result.Error.Error())
+				recordDeadLetterJob(pp.service.db, pp.service.taskQueue, result.FileID, currentTries, result.Error.Error())
+				pp.notifyFailure(result)
 			} else {
 				_ = pp.service.db.Model(&models.File{}).
 					Where("id = ?", result.FileID).
 					Update("ai_tagging_tries", currentTries).Error
 
-				retryDelay := time.Duration(currentTries) * time.Second
-				result.Nack(retryDelay, false, result.Error.Error())
+				result.Nack(policy.Delay(currentTries), false, result.Error.Error())
 			}
 		}
 	}
@@ -463,7 +495,7 @@ func (pp *PipelineProcessor) saveResultToDB(result *ProcessResult) error {
 	db := pp.service.db
 
 	var fileCheck models.File
-	if err := db.Where("id = ?", result.FileID).Select("id").Take(&fileCheck).Error; err != nil {
+	if err := db.Where("id = ?", result.FileID).Select("id, user_id, display_name").Take(&fileCheck).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errFileDeleted
 		}
@@ -476,6 +508,7 @@ func (pp *PipelineProcessor) saveResultToDB(result *ProcessResult) error {
 			CategoryID:          result.CategoryResult.CategoryID,
 			CategoryName:        result.CategoryResult.CategoryName,
 			CategoryDescription: result.CategoryResult.CategoryDescription,
+			Confidence:          result.CategoryResult.Confidence,
 		})
 		if err != nil {
 			logger.Warn("保存分类结果失败: %v", err)
@@ -485,7 +518,7 @@ func (pp *PipelineProcessor) saveResultToDB(result *ProcessResult) error {
 	contentDetectionEnabled := getContentDetectionEnabled()
 	sensitiveContentHandling := getSensitiveContentHandling()
 
-	err := processAIResponse(
+	event, err := processAIResponse(
 		db,
 		fileCheck,
 		result.AIResponse,
@@ -495,16 +528,26 @@ func (pp *PipelineProcessor) saveResultToDB(result *ProcessResult) error {
 		"",
 	)
 	if err != nil {
+		if event != nil {
+			notifyAICompletion(fileCheck.UserID, *event)
+		}
 		return err
 	}
 
-	return db.Model(&models.File{}).
+	if err := db.Model(&models.File{}).
 		Where("id = ?", result.FileID).
 		Updates(map[string]interface{}{
 			"ai_tagging_status": common.AITaggingStatusDone,
 			"ai_tagging_tries":  0,
 			"ai_http_duration":  result.HttpDuration,
-		}).Error
+		}).Error; err != nil {
+		return err
+	}
+
+	if event != nil {
+		notifyAICompletion(fileCheck.UserID, *event)
+	}
+	return nil
 }
 
 func getContentDetectionEnabled() bool {
@@ -514,3 +557,17 @@ func getContentDetectionEnabled() bool {
 func getSensitiveContentHandling() string {
 	return "mark_only"
 }
+
+// notifyFailure 重试耗尽后通知上传用户
+func (pp *PipelineProcessor) notifyFailure(result *ProcessResult) {
+	var file models.File
+	if err := pp.service.db.Select("id, user_id, display_name").Where("id = ?", result.FileID).Take(&file).Error; err != nil {
+		return
+	}
+	notifyAICompletion(file.UserID, AICompletionEvent{
+		FileID:   file.ID,
+		FileName: file.DisplayName,
+		Status:   common.AITaggingStatusFailed,
+		Error:    result.Error.Error(),
+	})
+}
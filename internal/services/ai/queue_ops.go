@@ -46,7 +46,7 @@ func GetAIQueueStats() (map[string]interface{}, error) {
 		stats["total_count"] += sc.Count
 	}
 
-	var configuredConcurrency, activeWorkers, queueLength, inFlight int
+	var configuredConcurrency, activeWorkers, queueLength, inFlight, dlqCount int
 	gs := GetGlobalTaggingService()
 	if gs == nil {
 		_ = InitGlobalTaggingQueue()
@@ -66,6 +66,7 @@ func GetAIQueueStats() (map[string]interface{}, error) {
 		if m, err := gs.taskQueue.Metrics(); err == nil && m != nil {
 			queueLength = m.QueueLength
 			inFlight = m.InFlight
+			dlqCount = m.DLQCount
 		}
 	}
 
@@ -87,7 +88,7 @@ func GetAIQueueStats() (map[string]interface{}, error) {
 			// 处理中数量以队列侧 InFlight 为准（更接近真实处理中的任务数）
 			"processing": inFlight,
 			"delayed":    0,
-			"dlq":        0,
+			"dlq":        dlqCount,
 		},
 		"config": map[string]interface{}{
 			"current_concurrency":    configuredConcurrency,
@@ -161,7 +162,7 @@ func TriggerFullTaggingProcess(maxImages int) (int, error) {
 		}
 	}
 
-	// 解冻超上限的 tries>=3 任务和已跳过的任务，置 pending 并入队（无事务）
+	// 解冻达到重试上限的任务和已跳过的任务，置 pending 并入队（无事务）
 	// 手动触发时包含 Skipped 状态，允许用户修正配置后重新处理
 	rescueLimit := 500
 	if maxImages > 0 && maxImages < rescueLimit {
@@ -169,7 +170,7 @@ func TriggerFullTaggingProcess(maxImages int) (int, error) {
 	}
 	var rescueIDs []string
 	if err := db.Table("file").Where("ai_tagging_status IN ?", []string{common.AITaggingStatusNone, common.AITaggingStatusFailed, common.AITaggingStatusSkipped}).
-		Where("ai_tagging_tries >= ?", 3).Order("updated_at ASC").Limit(rescueLimit).Pluck("id", &rescueIDs).Error; err == nil && len(rescueIDs) > 0 {
+		Where("ai_tagging_tries >= ?", taggingRetryPolicy().MaxAttempts).Order("updated_at ASC").Limit(rescueLimit).Pluck("id", &rescueIDs).Error; err == nil && len(rescueIDs) > 0 {
 		_ = db.Model(&models.File{}).Where("id IN ?", rescueIDs).Updates(map[string]interface{}{
 			"ai_tagging_status": common.AITaggingStatusPending, "ai_tagging_tries": 0,
 		}).Error
@@ -181,9 +182,9 @@ func TriggerFullTaggingProcess(maxImages int) (int, error) {
 		}
 	}
 
-	// 常规 tries<3 任务入队（无事务，手动触发时包含已跳过的文件）
+	// 常规未达重试上限的任务入队（无事务，手动触发时包含已跳过的文件）
 	var images []models.File
-	query := db.Table("file").Where("ai_tagging_status IN ? AND ai_tagging_tries < 3", []string{common.AITaggingStatusNone, common.AITaggingStatusFailed, common.AITaggingStatusSkipped})
+	query := db.Table("file").Where("ai_tagging_status IN ? AND ai_tagging_tries < ?", []string{common.AITaggingStatusNone, common.AITaggingStatusFailed, common.AITaggingStatusSkipped}, taggingRetryPolicy().MaxAttempts)
 	if maxImages > 0 {
 		query = query.Limit(maxImages)
 	}
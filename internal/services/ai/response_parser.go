@@ -119,6 +119,7 @@ func parseAITaggingResult(data interface{}) (*AITaggingResult, error) {
 			result.SearchContent = s
 		}
 	}
+	fillAIConfidences(rawData, &result)
 	return &result, nil
 }
 
@@ -256,18 +257,31 @@ func parseNewFormatAIResult(rawData map[string]interface{}, jsonData []byte) (*A
 		tags := make([]string, 0, len(arr))
 		seen := make(map[string]struct{}, len(arr))
 		for _, v := range arr {
-			if sv, ok := v.(string); ok {
-				sv = strings.TrimSpace(sv)
-				if sv == "" {
+			sv, ok := v.(string)
+			if !ok {
+				// 兼容 {"name": "标签", "confidence": 0.9} 形式
+				obj, isObj := v.(map[string]interface{})
+				if !isObj {
 					continue
 				}
-				key := strings.ToLower(sv)
-				if _, dup := seen[key]; dup {
-					continue
+				sv, _ = obj["name"].(string)
+				if c, ok := obj["confidence"].(float64); ok && strings.TrimSpace(sv) != "" {
+					if result.TagConfidence == nil {
+						result.TagConfidence = make(map[string]float64)
+					}
+					result.TagConfidence[strings.TrimSpace(sv)] = c
 				}
-				seen[key] = struct{}{}
-				tags = append(tags, sv)
 			}
+			sv = strings.TrimSpace(sv)
+			if sv == "" {
+				continue
+			}
+			key := strings.ToLower(sv)
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			tags = append(tags, sv)
 		}
 		result.Tags = tags
 	}
@@ -296,5 +310,6 @@ func parseNewFormatAIResult(rawData map[string]interface{}, jsonData []byte) (*A
 		}
 	}
 
+	fillAIConfidences(rawData, result)
 	return result, nil
 }
@@ -1,8 +1,11 @@
 package ai
 
 import (
+	"context"
 	"fmt"
 	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/folder"
 	"pixelpunk/pkg/logger"
 )
 
@@ -33,9 +36,15 @@ func (s *TaggingService) ProcessSingleFile(file models.File) {
 	}()
 }
 
-func (s *TaggingService) BatchProcessFiles(files []models.File) { s.BatchProcessFilesWithResult(files) }
+func (s *TaggingService) BatchProcessFiles(files []models.File) { s.batchEnqueue(files, false) }
 
+// BatchProcessFilesWithResult 后台手动重试、取消忽略时使用，进入死信的任务同样重新入队
 func (s *TaggingService) BatchProcessFilesWithResult(files []models.File) (int, int) {
+	return s.batchEnqueue(files, true)
+}
+
+// batchEnqueue 批量入队并返回入队与跳过的数量，requeueDead 为 false 时不重新执行已进入死信的任务
+func (s *TaggingService) batchEnqueue(files []models.File, requeueDead bool) (int, int) {
 	if len(files) == 0 {
 		return 0, 0
 	}
@@ -51,12 +60,24 @@ func (s *TaggingService) BatchProcessFilesWithResult(files []models.File) (int,
 		return 0, len(files)
 	}
 
-	enqueued, skipped := 0, 0
+	// 用户或所在文件夹关闭了AI处理的文件标记为已忽略，计入跳过
+	fileIDs := make([]string, 0, len(files))
 	for _, file := range files {
-		if err := s.taskQueue.EnqueueUnique(file.ID, 0); err == nil {
-			enqueued++
-		} else {
-			skipped++
+		fileIDs = append(fileIDs, file.ID)
+	}
+	allowed := folder.FilterAIProcessingAllowed(fileIDs)
+
+	enqueued, skipped := 0, len(files)-len(allowed)
+	if requeueDead {
+		enqueued = qqueue.RequeueDeadIDs(s.taskQueue, allowed, qqueue.PriorityNormal)
+		skipped += len(allowed) - enqueued
+	} else {
+		for _, id := range allowed {
+			if err := s.taskQueue.EnqueueUnique(id, 0); err == nil {
+				enqueued++
+			} else {
+				skipped++
+			}
 		}
 	}
 	s.notifyQueueStatsChange()
@@ -71,12 +92,21 @@ func (s *TaggingService) IsProcessing() bool {
 }
 
 func (s *TaggingService) Stop() {
+	s.Shutdown(context.Background())
+}
+
+// Shutdown 停止打标服务，ctx 到期时仍在处理的任务放回队列，由重启后的补偿逻辑重新处理
+func (s *TaggingService) Shutdown(ctx context.Context) {
 	s.mutex.Lock()
+	if s.stopping {
+		s.mutex.Unlock()
+		return
+	}
 	s.stopping = true
 	s.mutex.Unlock()
 
 	if s.pipeline != nil {
-		s.pipeline.Stop()
+		s.pipeline.Shutdown(ctx)
 	}
 
 	if s.reaperStop != nil {
@@ -84,6 +114,13 @@ func (s *TaggingService) Stop() {
 	}
 }
 
+/* Shutdown 关闭全局打标服务，应用退出时调用 */
+func Shutdown(ctx context.Context) {
+	if svc := GetGlobalTaggingService(); svc != nil {
+		svc.Shutdown(ctx)
+	}
+}
+
 func (s *TaggingService) GetQueueStats() map[string]interface{} {
 	s.mutex.Lock()
 	activeWorkers := s.activeWorkers
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/folder"
 	"pixelpunk/pkg/cache"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
@@ -257,8 +258,9 @@ func EnqueueAllPending(batch int) (int, error) {
 	for round := 0; round < maxRounds; round++ {
 		var ids []string
 		if err := db.Table("file").
-			Where("ai_tagging_status IN ? AND ai_tagging_tries < 3",
-				[]string{common.AITaggingStatusNone, common.AITaggingStatusFailed}).
+			Where("ai_tagging_status IN ? AND ai_tagging_tries < ?",
+				[]string{common.AITaggingStatusNone, common.AITaggingStatusFailed}, taggingRetryPolicy().MaxAttempts).
+			Where("status <> ?", common.FileStatusShareHold).
 			Order("created_at ASC").
 			Limit(batch).
 			Pluck("id", &ids).Error; err != nil {
@@ -271,15 +273,17 @@ func EnqueueAllPending(batch int) (int, error) {
 			_ = db.Where("file_id IN ? AND status IN ?", ids, []string{"done", "ignored", "skipped"}).
 				Delete(&models.AIJob{}).Error
 		}
+		// 关闭了AI处理的文件标记为已忽略，下一轮不会再被扫描到
+		allowed := folder.FilterAIProcessingAllowed(ids)
 		// 然后更新file状态为pending
-		if len(ids) > 0 {
-			_ = db.Model(&models.File{}).Where("id IN ?", ids).
+		if len(allowed) > 0 {
+			_ = db.Model(&models.File{}).Where("id IN ?", allowed).
 				Update("ai_tagging_status", common.AITaggingStatusPending).Error
 		}
-		for _, id := range ids {
+		for _, id := range allowed {
 			_ = svc.taskQueue.EnqueueUnique(id, 0)
 		}
-		total += len(ids)
+		total += len(allowed)
 		if len(ids) < batch {
 			break
 		}
@@ -300,12 +304,13 @@ func ReconcileAIJobs() error {
 
 	var ids []string
 	if err := db.Table("file").
-		Where("ai_tagging_status IN ? AND ai_tagging_tries < 3", []string{common.AITaggingStatusNone, common.AITaggingStatusFailed}).
+		Where("ai_tagging_status IN ? AND ai_tagging_tries < ?", []string{common.AITaggingStatusNone, common.AITaggingStatusFailed}, taggingRetryPolicy().MaxAttempts).
+		Where("status <> ?", common.FileStatusShareHold).
 		Limit(200).
 		Pluck("id", &ids).Error; err != nil {
 		return err
 	}
-	for _, id := range ids {
+	for _, id := range folder.FilterAIProcessingAllowed(ids) {
 		_ = svc.taskQueue.EnqueueUnique(id, 0)
 	}
 	return nil
@@ -438,7 +443,7 @@ func UnignoreTagging(imageIDs []string, operatorID uint) (int, []string, int, in
 
 // RetryFailedAll 批量重试失败与超过尝试上限的任务
 // 规则：
-// 1) 选择 ai_tagging_status in ('failed','none') 且 ai_tagging_tries >= 3 的文件作为优先重试目标
+// 1) 选择 ai_tagging_status in ('failed','none') 且 ai_tagging_tries 达到重试上限的文件作为优先重试目标
 // 2) 可选 limit 限制单次重试数量，默认最多500
 // 3) 将这些文件的 tries 重置为0，状态改为 none，并入队
 func RetryFailedAll(limit int, operatorID uint) (int, int, int, error) {
@@ -452,7 +457,7 @@ func RetryFailedAll(limit int, operatorID uint) (int, int, int, error) {
 	var imageIDs []string
 	query := db.Table("file").
 		Where("(ai_tagging_status IN ? AND ai_tagging_tries >= ?) OR ai_tagging_status = ?",
-			[]string{common.AITaggingStatusFailed, common.AITaggingStatusNone}, 3, common.AITaggingStatusSkipped).
+			[]string{common.AITaggingStatusFailed, common.AITaggingStatusNone}, taggingRetryPolicy().MaxAttempts, common.AITaggingStatusSkipped).
 		Order("updated_at ASC").
 		Limit(limit)
 	if err := query.Pluck("id", &imageIDs).Error; err != nil {
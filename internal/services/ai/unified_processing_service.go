@@ -109,69 +109,93 @@ func saveCategoryResultIfNeeded(tx *gorm.DB, fileID string, categoryResult *ai.F
 			return fmt.Errorf("获取图片信息失败: %v", err)
 		}
 
-		var finalCategoryID uint
+		// 低置信度分类转为待用户确认的建议，不直接写入
+		if threshold := getMinConfidence("category_min_confidence"); threshold > 0 {
+			var suggestions []models.AISuggestion
+			if categoryResult.Confidence < threshold {
+				suggestions = append(suggestions, models.AISuggestion{
+					Value:               categoryResult.CategoryName,
+					CategoryID:          categoryResult.CategoryID,
+					CategoryDescription: categoryResult.CategoryDescription,
+					Confidence:          categoryResult.Confidence,
+				})
+			}
+			replacePendingSuggestions(tx, file, SuggestionTypeCategory, suggestions)
+			if len(suggestions) > 0 {
+				return nil
+			}
+		}
 
-		if categoryResult.CategoryID > 0 {
-			finalCategoryID = categoryResult.CategoryID
-		} else {
-			// AI建议创建新分类，先检查是否已存在同名分类
-
-			// 首先检查是否已存在同名的用户分类
-			var existingCategory models.FileCategory
-			err = tx.Where("user_id = ? AND name = ? AND status = ?", file.UserID, categoryResult.CategoryName, "active").
-				First(&existingCategory).Error
-
-			if err == nil {
-				// 找到现有分类，直接使用
-				finalCategoryID = existingCategory.ID
-
-				// 更新现有分类的使用次数（忽略错误）
-				_ = tx.Model(&models.FileCategory{}).
-					Where("id = ?", existingCategory.ID).
-					UpdateColumn("file_count", gorm.Expr("file_count + 1")).Error
-			} else if err == gorm.ErrRecordNotFound {
-				// 不存在同名分类，创建新的用户分类
-				newCategory := &models.FileCategory{
-					Name:        categoryResult.CategoryName,
-					Description: categoryResult.CategoryDescription,
-					UserID:      file.UserID,
-					Source:      "ai_suggestion",
-					Status:      "active",
-				}
-
-				err := tx.Create(newCategory).Error
-				if err != nil {
-					return fmt.Errorf("创建AI建议分类失败: %v", err)
-				}
-
-				finalCategoryID = newCategory.ID
-			} else {
-				// 查询出错（非记录不存在错误）
-				return fmt.Errorf("查询现有分类失败: %v", err)
+		return applyCategoryResult(tx, file, categoryResult)
+	}
+	return nil
+}
+
+// applyCategoryResult 将分类结果写入文件（必要时创建AI建议的新分类）
+func applyCategoryResult(tx *gorm.DB, file models.File, categoryResult *ai.FileCategorizationResponse) error {
+	var err error
+	var finalCategoryID uint
+
+	if categoryResult.CategoryID > 0 {
+		finalCategoryID = categoryResult.CategoryID
+	} else {
+		// AI建议创建新分类，先检查是否已存在同名分类
+
+		// 首先检查是否已存在同名的用户分类
+		var existingCategory models.FileCategory
+		err = tx.Where("user_id = ? AND name = ? AND status = ?", file.UserID, categoryResult.CategoryName, "active").
+			First(&existingCategory).Error
+
+		if err == nil {
+			// 找到现有分类，直接使用
+			finalCategoryID = existingCategory.ID
+
+			// 更新现有分类的使用次数（忽略错误）
+			_ = tx.Model(&models.FileCategory{}).
+				Where("id = ?", existingCategory.ID).
+				UpdateColumn("file_count", gorm.Expr("file_count + 1")).Error
+		} else if err == gorm.ErrRecordNotFound {
+			// 不存在同名分类，创建新的用户分类
+			newCategory := &models.FileCategory{
+				Name:        categoryResult.CategoryName,
+				Description: categoryResult.CategoryDescription,
+				UserID:      file.UserID,
+				Source:      "ai_suggestion",
+				Status:      "active",
 			}
 
-			// 更新AI返回结果中的CategoryID，方便后续使用
-			categoryResult.CategoryID = finalCategoryID
-		}
+			err := tx.Create(newCategory).Error
+			if err != nil {
+				return fmt.Errorf("创建AI建议分类失败: %v", err)
+			}
 
-		// 直接更新图片的分类ID，不再创建关联表记录（避免数据库锁冲突）
-		err = tx.Model(&models.File{}).
-			Where("id = ?", fileID).
-			Updates(map[string]interface{}{
-				"category_id":     finalCategoryID,
-				"category_source": "ai",
-			}).Error
-		if err != nil {
-			return fmt.Errorf("保存分类结果到图片失败: %v", err)
+			finalCategoryID = newCategory.ID
+		} else {
+			// 查询出错（非记录不存在错误）
+			return fmt.Errorf("查询现有分类失败: %v", err)
 		}
 
-		// 异步更新分类使用次数（避免在主事务中造成锁冲突）
-		if finalCategoryID > 0 {
-			go func(categoryID uint, userID uint) {
-				// 使用新的数据库连接，避免事务冲突
-				_ = updateCategoryUsageCountAsync(categoryID, userID)
-			}(finalCategoryID, file.UserID)
-		}
+		// 更新AI返回结果中的CategoryID，方便后续使用
+		categoryResult.CategoryID = finalCategoryID
+	}
+
+	// 直接更新图片的分类ID，不再创建关联表记录（避免数据库锁冲突）
+	err = tx.Model(&models.File{}).
+		Where("id = ?", file.ID).
+		Updates(map[string]interface{}{
+			"category_id":     finalCategoryID,
+			"category_source": "ai",
+		}).Error
+	if err != nil {
+		return fmt.Errorf("保存分类结果到图片失败: %v", err)
+	}
+
+	// 异步更新分类使用次数（避免在主事务中造成锁冲突）
+	if finalCategoryID > 0 {
+		go func(categoryID uint, userID uint) {
+			// 使用新的数据库连接，避免事务冲突
+			_ = updateCategoryUsageCountAsync(categoryID, userID)
+		}(finalCategoryID, file.UserID)
 	}
 	return nil
 }
@@ -0,0 +1,229 @@
+package announcement
+
+import (
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/controllers/announcement/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/* Viewer 查看公告的用户身份，用于匹配定向公告；匿名访问时为nil */
+type Viewer struct {
+	UserID  uint
+	Role    int
+	GroupID uint
+}
+
+/* LoadViewer 加载用户的角色与用户组，userID为0或用户不存在时返回nil */
+func LoadViewer(userID uint) *Viewer {
+	if userID == 0 {
+		return nil
+	}
+	var user models.User
+	if err := database.GetDB().Select("id, role, group_id").First(&user, userID).Error; err != nil {
+		return nil
+	}
+	viewer := &Viewer{UserID: user.ID, Role: user.Role}
+	if user.GroupID != nil {
+		viewer.GroupID = *user.GroupID
+	}
+	return viewer
+}
+
+/* GetUnreadCount 当前用户可见且未读的公告数 */
+func GetUnreadCount(viewer *Viewer) (int64, error) {
+	if viewer == nil {
+		return 0, nil
+	}
+	var count int64
+	if err := unreadAnnouncements(database.GetDB().Model(&models.Announcement{}), viewer).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("查询未读公告失败: %v", err)
+	}
+	return count, nil
+}
+
+/* MarkAnnouncementRead 标记单条公告为已读 */
+func MarkAnnouncementRead(viewer *Viewer, id uint) error {
+	db := database.GetDB()
+
+	var count int64
+	if err := visibleAnnouncements(db.Model(&models.Announcement{}), viewer).Where("id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询公告失败: %v", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("公告不存在")
+	}
+
+	return saveReads(db, viewer.UserID, []uint{id})
+}
+
+/* MarkAllAnnouncementsRead 将当前用户可见的全部公告标记为已读，返回新标记的数量 */
+func MarkAllAnnouncementsRead(viewer *Viewer) (int, error) {
+	db := database.GetDB()
+
+	var ids []uint
+	if err := unreadAnnouncements(db.Model(&models.Announcement{}), viewer).Pluck("id", &ids).Error; err != nil {
+		return 0, fmt.Errorf("查询未读公告失败: %v", err)
+	}
+	if err := saveReads(db, viewer.UserID, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+/* audienceScope 定向公告只对命中投放对象的用户可见，匿名访问只能看到面向全部用户的公告 */
+func audienceScope(db *gorm.DB, viewer *Viewer) *gorm.DB {
+	if viewer == nil {
+		return db.Where("announcements.audience = ?", models.AnnouncementAudienceAll)
+	}
+	return db.Where(
+		"announcements.audience = ? OR EXISTS (SELECT 1 FROM announcement_targets t WHERE t.announcement_id = announcements.id AND "+
+			"((t.target_type = ? AND t.target_id = ?) OR (t.target_type = ? AND t.target_id = ?) OR (t.target_type = ? AND t.target_id = ?)))",
+		models.AnnouncementAudienceAll,
+		models.AnnouncementTargetRole, viewer.Role,
+		models.AnnouncementTargetGroup, viewer.GroupID,
+		models.AnnouncementTargetUser, viewer.UserID,
+	)
+}
+
+func unreadAnnouncements(db *gorm.DB, viewer *Viewer) *gorm.DB {
+	return visibleAnnouncements(db, viewer).
+		Where("NOT EXISTS (SELECT 1 FROM announcement_reads r WHERE r.announcement_id = announcements.id AND r.user_id = ?)", viewer.UserID)
+}
+
+/* readAnnouncementIDs 返回给定公告中用户已读的ID集合 */
+func readAnnouncementIDs(userID uint, ids []uint) map[uint]bool {
+	result := make(map[uint]bool)
+	if len(ids) == 0 {
+		return result
+	}
+	var readIDs []uint
+	database.GetDB().Model(&models.AnnouncementRead{}).
+		Where("user_id = ? AND announcement_id IN ?", userID, ids).
+		Pluck("announcement_id", &readIDs)
+	for _, id := range readIDs {
+		result[id] = true
+	}
+	return result
+}
+
+func saveReads(db *gorm.DB, userID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := common.JSONTime(time.Now())
+	reads := make([]models.AnnouncementRead, len(ids))
+	for i, id := range ids {
+		reads[i] = models.AnnouncementRead{AnnouncementID: id, UserID: userID, ReadAt: now}
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(reads, 200).Error; err != nil {
+		return fmt.Errorf("标记公告已读失败: %v", err)
+	}
+	return nil
+}
+
+/* replaceTargets 整体替换公告的投放对象并同步受众类型 */
+func replaceTargets(tx *gorm.DB, announcement *models.Announcement, targets *dto.AnnouncementTargetsDTO) error {
+	if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&models.AnnouncementTarget{}).Error; err != nil {
+		return fmt.Errorf("更新投放对象失败: %v", err)
+	}
+
+	audience := models.AnnouncementAudienceAll
+	if !targets.IsEmpty() {
+		audience = models.AnnouncementAudienceTargeted
+		rows := buildTargetRows(announcement.ID, targets)
+		if err := tx.Create(&rows).Error; err != nil {
+			return fmt.Errorf("保存投放对象失败: %v", err)
+		}
+	}
+
+	if announcement.Audience != audience {
+		if err := tx.Model(announcement).Update("audience", audience).Error; err != nil {
+			return fmt.Errorf("更新公告受众失败: %v", err)
+		}
+		announcement.Audience = audience
+	}
+	return nil
+}
+
+/* validateTargets 去重并校验投放的用户组和用户均存在 */
+func validateTargets(targets *dto.AnnouncementTargetsDTO) error {
+	if targets.IsEmpty() {
+		return nil
+	}
+	targets.Roles = dedupe(targets.Roles)
+	targets.GroupIDs = dedupe(targets.GroupIDs)
+	targets.UserIDs = dedupe(targets.UserIDs)
+
+	db := database.GetDB()
+	var count int64
+	if len(targets.GroupIDs) > 0 {
+		db.Model(&models.UserGroup{}).Where("id IN ?", targets.GroupIDs).Count(&count)
+		if int(count) != len(targets.GroupIDs) {
+			return fmt.Errorf("投放的用户组不存在")
+		}
+	}
+	if len(targets.UserIDs) > 0 {
+		db.Model(&models.User{}).Where("id IN ?", targets.UserIDs).Count(&count)
+		if int(count) != len(targets.UserIDs) {
+			return fmt.Errorf("投放的用户不存在")
+		}
+	}
+	return nil
+}
+
+func buildTargetRows(announcementID uint, targets *dto.AnnouncementTargetsDTO) []models.AnnouncementTarget {
+	var rows []models.AnnouncementTarget
+	for _, role := range targets.Roles {
+		rows = append(rows, models.AnnouncementTarget{AnnouncementID: announcementID, TargetType: models.AnnouncementTargetRole, TargetID: uint(role)})
+	}
+	for _, id := range targets.GroupIDs {
+		rows = append(rows, models.AnnouncementTarget{AnnouncementID: announcementID, TargetType: models.AnnouncementTargetGroup, TargetID: id})
+	}
+	for _, id := range targets.UserIDs {
+		rows = append(rows, models.AnnouncementTarget{AnnouncementID: announcementID, TargetType: models.AnnouncementTargetUser, TargetID: id})
+	}
+	return rows
+}
+
+/* loadTargets 批量加载公告的投放对象 */
+func loadTargets(ids []uint) map[uint]dto.AnnouncementTargetsDTO {
+	result := make(map[uint]dto.AnnouncementTargetsDTO)
+	if len(ids) == 0 {
+		return result
+	}
+	var rows []models.AnnouncementTarget
+	database.GetDB().Where("announcement_id IN ?", ids).Order("id ASC").Find(&rows)
+	for _, row := range rows {
+		targets := result[row.AnnouncementID]
+		switch row.TargetType {
+		case models.AnnouncementTargetRole:
+			targets.Roles = append(targets.Roles, int(row.TargetID))
+		case models.AnnouncementTargetGroup:
+			targets.GroupIDs = append(targets.GroupIDs, row.TargetID)
+		case models.AnnouncementTargetUser:
+			targets.UserIDs = append(targets.UserIDs, row.TargetID)
+		}
+		result[row.AnnouncementID] = targets
+	}
+	return result
+}
+
+func dedupe[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	result := make([]T, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"pixelpunk/internal/controllers/announcement/dto"
 	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -20,13 +23,28 @@ func CreateAnnouncement(userID uint, createDTO *dto.AnnouncementCreateDTO) (*dto
 		IsPinned:  createDTO.IsPinned,
 		Status:    createDTO.Status,
 		CreatedBy: userID,
+		PublishAt: toJSONTime(createDTO.PublishAt),
+		ExpireAt:  toJSONTime(createDTO.ExpireAt),
 	}
 
-	if err := db.Create(announcement).Error; err != nil {
-		return nil, fmt.Errorf("创建公告失败: %v", err)
+	if err := normalizeSchedule(announcement); err != nil {
+		return nil, err
+	}
+	if err := validateTargets(createDTO.Targets); err != nil {
+		return nil, err
 	}
 
-	return modelToResponseDTO(announcement), nil
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(announcement).Error; err != nil {
+			return fmt.Errorf("创建公告失败: %v", err)
+		}
+		return replaceTargets(tx, announcement, createDTO.Targets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponseWithTargets(announcement), nil
 }
 
 /* UpdateAnnouncement 更新公告 */
@@ -56,18 +74,46 @@ func UpdateAnnouncement(id uint, updateDTO *dto.AnnouncementUpdateDTO) (*dto.Ann
 		updates["is_pinned"] = *updateDTO.IsPinned
 	}
 	if updateDTO.Status != nil {
-		updates["status"] = *updateDTO.Status
+		announcement.Status = *updateDTO.Status
+	}
+	if updateDTO.ClearPublishAt {
+		announcement.PublishAt = nil
+	} else if updateDTO.PublishAt != nil {
+		announcement.PublishAt = toJSONTime(updateDTO.PublishAt)
+	}
+	if updateDTO.ClearExpireAt {
+		announcement.ExpireAt = nil
+	} else if updateDTO.ExpireAt != nil {
+		announcement.ExpireAt = toJSONTime(updateDTO.ExpireAt)
+	}
+	if err := normalizeSchedule(&announcement); err != nil {
+		return nil, err
+	}
+	if err := validateTargets(updateDTO.Targets); err != nil {
+		return nil, err
 	}
+	updates["status"] = announcement.Status
+	updates["publish_at"] = announcement.PublishAt
+	updates["expire_at"] = announcement.ExpireAt
 
-	if err := db.Model(&announcement).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("更新公告失败: %v", err)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&announcement).Updates(updates).Error; err != nil {
+			return fmt.Errorf("更新公告失败: %v", err)
+		}
+		if updateDTO.Targets != nil {
+			return replaceTargets(tx, &announcement, updateDTO.Targets)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if err := db.First(&announcement, id).Error; err != nil {
 		return nil, fmt.Errorf("查询更新后的公告失败: %v", err)
 	}
 
-	return modelToResponseDTO(&announcement), nil
+	return toResponseWithTargets(&announcement), nil
 }
 
 /* DeleteAnnouncement 删除公告（软删除） */
@@ -123,7 +169,7 @@ func TogglePinAnnouncement(id uint) (*dto.AnnouncementResponseDTO, error) {
 		return nil, fmt.Errorf("查询更新后的公告失败: %v", err)
 	}
 
-	return modelToResponseDTO(&announcement), nil
+	return toResponseWithTargets(&announcement), nil
 }
 
 /* GetAnnouncementByID 根据ID获取公告 */
@@ -138,7 +184,7 @@ func GetAnnouncementByID(id uint) (*dto.AnnouncementResponseDTO, error) {
 		return nil, fmt.Errorf("查询公告失败: %v", err)
 	}
 
-	return modelToResponseDTO(&announcement), nil
+	return toResponseWithTargets(&announcement), nil
 }
 
 /* GetAnnouncementList 获取公告列表（管理端） */
@@ -181,9 +227,15 @@ func GetAnnouncementList(query *dto.AnnouncementQueryDTO) (*dto.AnnouncementList
 		return nil, fmt.Errorf("查询公告列表失败: %v", err)
 	}
 
+	ids := make([]uint, len(announcements))
+	for i := range announcements {
+		ids[i] = announcements[i].ID
+	}
+	targets := loadTargets(ids)
+
 	announcementDTOs := make([]dto.AnnouncementResponseDTO, len(announcements))
 	for i, announcement := range announcements {
-		announcementDTOs[i] = *modelToResponseDTO(&announcement)
+		announcementDTOs[i] = *modelToResponseDTO(&announcement, targets[announcement.ID])
 	}
 
 	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
@@ -199,8 +251,8 @@ func GetAnnouncementList(query *dto.AnnouncementQueryDTO) (*dto.AnnouncementList
 	}, nil
 }
 
-/* GetPublicAnnouncementList 获取公开的公告列表（用户端） */
-func GetPublicAnnouncementList() (*dto.PublicAnnouncementListDTO, error) {
+/* GetPublicAnnouncementList 获取公开的公告列表（用户端），登录用户可看到投放给自己的公告及已读状态 */
+func GetPublicAnnouncementList(viewer *Viewer) (*dto.PublicAnnouncementListDTO, error) {
 	db := database.GetDB()
 
 	config, err := GetAnnouncementSettings()
@@ -225,7 +277,7 @@ func GetPublicAnnouncementList() (*dto.PublicAnnouncementListDTO, error) {
 
 	var announcements []models.Announcement
 
-	if err := db.Where("status = ?", "published").
+	if err := visibleAnnouncements(db, viewer).
 		Order("is_pinned DESC, created_at DESC").
 		Limit(limit).
 		Find(&announcements).Error; err != nil {
@@ -237,15 +289,31 @@ func GetPublicAnnouncementList() (*dto.PublicAnnouncementListDTO, error) {
 		simpleDTOs[i] = *modelToSimpleDTO(&announcement)
 	}
 
+	var unreadCount int64
+	if viewer != nil {
+		ids := make([]uint, len(announcements))
+		for i := range announcements {
+			ids[i] = announcements[i].ID
+		}
+		readIDs := readAnnouncementIDs(viewer.UserID, ids)
+		for i := range simpleDTOs {
+			simpleDTOs[i].IsRead = readIDs[simpleDTOs[i].ID]
+		}
+		if unreadCount, err = GetUnreadCount(viewer); err != nil {
+			return nil, err
+		}
+	}
+
 	return &dto.PublicAnnouncementListDTO{
 		Announcements: simpleDTOs,
 		Total:         len(simpleDTOs),
+		UnreadCount:   unreadCount,
 		Config:        config, // 返回配置信息
 	}, nil
 }
 
-/* GetPublicAnnouncementDetail 获取公告详情（用户端） */
-func GetPublicAnnouncementDetail(id uint) (*dto.AnnouncementDetailDTO, error) {
+/* GetPublicAnnouncementDetail 获取公告详情（用户端），登录用户查看后自动标记为已读 */
+func GetPublicAnnouncementDetail(id uint, viewer *Viewer) (*dto.AnnouncementDetailDTO, error) {
 	db := database.GetDB()
 
 	var announcement models.Announcement
@@ -256,11 +324,21 @@ func GetPublicAnnouncementDetail(id uint) (*dto.AnnouncementDetailDTO, error) {
 		return nil, fmt.Errorf("查询公告失败: %v", err)
 	}
 
-	// 只返回已发布的公告
-	if announcement.Status != "published" {
+	// 只返回已发布、在有效期内且投放给当前用户的公告
+	var visible int64
+	if err := visibleAnnouncements(db.Model(&models.Announcement{}), viewer).Where("id = ?", id).Count(&visible).Error; err != nil {
+		return nil, fmt.Errorf("查询公告失败: %v", err)
+	}
+	if visible == 0 {
 		return nil, fmt.Errorf("公告未发布")
 	}
 
+	if viewer != nil {
+		if err := saveReads(db, viewer.UserID, []uint{id}); err != nil {
+			logger.Warn("标记公告已读失败: announcementID=%d, userID=%d, error=%v", id, viewer.UserID, err)
+		}
+	}
+
 	if err := db.Model(&announcement).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error; err != nil {
 		// 浏览次数更新失败不影响返回结果，只记录错误
 	}
@@ -268,8 +346,13 @@ func GetPublicAnnouncementDetail(id uint) (*dto.AnnouncementDetailDTO, error) {
 	return modelToDetailDTO(&announcement), nil
 }
 
+/* toResponseWithTargets 转换为响应DTO并加载投放对象 */
+func toResponseWithTargets(announcement *models.Announcement) *dto.AnnouncementResponseDTO {
+	return modelToResponseDTO(announcement, loadTargets([]uint{announcement.ID})[announcement.ID])
+}
+
 /* modelToResponseDTO 将模型转换为响应DTO */
-func modelToResponseDTO(announcement *models.Announcement) *dto.AnnouncementResponseDTO {
+func modelToResponseDTO(announcement *models.Announcement, targets dto.AnnouncementTargetsDTO) *dto.AnnouncementResponseDTO {
 	return &dto.AnnouncementResponseDTO{
 		ID:        announcement.ID,
 		Title:     announcement.Title,
@@ -279,6 +362,10 @@ func modelToResponseDTO(announcement *models.Announcement) *dto.AnnouncementResp
 		Status:    announcement.Status,
 		ViewCount: announcement.ViewCount,
 		CreatedBy: announcement.CreatedBy,
+		PublishAt: announcement.PublishAt,
+		ExpireAt:  announcement.ExpireAt,
+		Audience:  announcement.Audience,
+		Targets:   targets,
 		CreatedAt: announcement.CreatedAt,
 		UpdatedAt: announcement.UpdatedAt,
 	}
@@ -292,6 +379,8 @@ func modelToSimpleDTO(announcement *models.Announcement) *dto.AnnouncementSimple
 		Summary:   announcement.Summary,
 		IsPinned:  announcement.IsPinned,
 		ViewCount: announcement.ViewCount,
+		PublishAt: announcement.PublishAt,
+		ExpireAt:  announcement.ExpireAt,
 		CreatedAt: announcement.CreatedAt,
 	}
 }
@@ -305,6 +394,73 @@ func modelToDetailDTO(announcement *models.Announcement) *dto.AnnouncementDetail
 		Summary:   announcement.Summary,
 		IsPinned:  announcement.IsPinned,
 		ViewCount: announcement.ViewCount,
+		PublishAt: announcement.PublishAt,
+		ExpireAt:  announcement.ExpireAt,
 		CreatedAt: announcement.CreatedAt,
 	}
 }
+
+/* ApplyScheduledTransitions 定时任务调用：到达发布时间的公告自动发布，到达下线时间的公告自动归档 */
+func ApplyScheduledTransitions() (int64, int64, error) {
+	db := database.GetDB()
+	now := time.Now()
+
+	published := db.Model(&models.Announcement{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", "scheduled", now).
+		Update("status", "published")
+	if published.Error != nil {
+		return 0, 0, fmt.Errorf("发布定时公告失败: %v", published.Error)
+	}
+
+	archived := db.Model(&models.Announcement{}).
+		Where("status = ? AND expire_at IS NOT NULL AND expire_at <= ?", "published", now).
+		Update("status", "archived")
+	if archived.Error != nil {
+		return published.RowsAffected, 0, fmt.Errorf("归档过期公告失败: %v", archived.Error)
+	}
+
+	return published.RowsAffected, archived.RowsAffected, nil
+}
+
+/* normalizeSchedule 根据发布时间修正状态：发布时间在未来的已发布公告转为待发布，已到时间的待发布公告直接发布 */
+func normalizeSchedule(announcement *models.Announcement) error {
+	now := time.Now()
+	if announcement.PublishAt != nil && announcement.ExpireAt != nil &&
+		!time.Time(*announcement.ExpireAt).After(time.Time(*announcement.PublishAt)) {
+		return fmt.Errorf("下线时间必须晚于发布时间")
+	}
+
+	switch announcement.Status {
+	case "published", "scheduled":
+		if announcement.PublishAt != nil && time.Time(*announcement.PublishAt).After(now) {
+			announcement.Status = "scheduled"
+		} else if announcement.Status == "scheduled" {
+			if announcement.PublishAt == nil {
+				return fmt.Errorf("定时发布需要设置发布时间")
+			}
+			announcement.Status = "published"
+		}
+	}
+
+	if announcement.Status == "published" && announcement.ExpireAt != nil && !time.Time(*announcement.ExpireAt).After(now) {
+		return fmt.Errorf("下线时间必须晚于当前时间")
+	}
+	return nil
+}
+
+/* visibleAnnouncements 用户端可见的公告：已发布、在有效期内且面向当前用户，不依赖定时任务的执行时机 */
+func visibleAnnouncements(db *gorm.DB, viewer *Viewer) *gorm.DB {
+	now := time.Now()
+	db = db.Where("announcements.status = ?", "published").
+		Where("announcements.publish_at IS NULL OR announcements.publish_at <= ?", now).
+		Where("announcements.expire_at IS NULL OR announcements.expire_at > ?", now)
+	return audienceScope(db, viewer)
+}
+
+func toJSONTime(t *time.Time) *common.JSONTime {
+	if t == nil {
+		return nil
+	}
+	jt := common.JSONTime(*t)
+	return &jt
+}
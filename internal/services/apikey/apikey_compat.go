@@ -0,0 +1,120 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+)
+
+// 上传工具兼容接口路径
+const (
+	ShareXUploadPath   = "/api/v1/external/sharex/upload"
+	PicGoUploadPath    = "/api/v1/external/picgo/upload"
+	compatDeletionPath = "/api/v1/external/compat/delete/"
+)
+
+// ShareXUploaderConfig ShareX 自定义上传器配置（.sxcu）
+type ShareXUploaderConfig struct {
+	Version         string            `json:"Version"`
+	Name            string            `json:"Name"`
+	DestinationType string            `json:"DestinationType"`
+	RequestMethod   string            `json:"RequestMethod"`
+	RequestURL      string            `json:"RequestURL"`
+	Headers         map[string]string `json:"Headers"`
+	Body            string            `json:"Body"`
+	FileFormName    string            `json:"FileFormName"`
+	URL             string            `json:"URL"`
+	ThumbnailURL    string            `json:"ThumbnailURL"`
+	DeletionURL     string            `json:"DeletionURL,omitempty"`
+	ErrorMessage    string            `json:"ErrorMessage"`
+}
+
+/* DeletionToken 生成文件删除链接的签名，以密钥值为签名密钥，重新生成密钥后旧链接随之失效 */
+func DeletionToken(key *models.APIKey, fileID string) string {
+	mac := hmac.New(sha256.New, []byte(key.KeyValue))
+	mac.Write([]byte("delete:" + fileID))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+/* DeletionURL 生成免登录的文件删除链接，密钥没有删除权限时返回空 */
+func DeletionURL(key *models.APIKey, fileID string) string {
+	if !key.HasScope(models.APIKeyScopeDelete) {
+		return ""
+	}
+	return baseURL() + compatDeletionPath + key.ID + "/" + fileID + "/" + DeletionToken(key, fileID)
+}
+
+/* VerifyDeletionToken 校验删除链接，返回签发链接的密钥 */
+func VerifyDeletionToken(keyID, fileID, token string) (*models.APIKey, error) {
+	key, err := ValidateAPIKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(token), []byte(DeletionToken(key, fileID))) {
+		return nil, errors.New(errors.CodeForbidden, "删除链接无效")
+	}
+	if err := CheckScope(key, models.APIKeyScopeDelete); err != nil {
+		return nil, err
+	}
+	if err := CheckFileAccess(key, fileID); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+/* BuildShareXConfig 按密钥生成 ShareX 自定义上传器配置 */
+func BuildShareXConfig(key *models.APIKey) *ShareXUploaderConfig {
+	config := &ShareXUploaderConfig{
+		Version:         "15.0.0",
+		Name:            siteName() + " - " + key.Name,
+		DestinationType: "ImageUploader, FileUploader",
+		RequestMethod:   "POST",
+		RequestURL:      baseURL() + ShareXUploadPath,
+		Headers:         map[string]string{"X-Pixelpunk-Key": key.KeyValue},
+		Body:            "MultipartFormData",
+		FileFormName:    "file",
+		URL:             "{json:url}",
+		ThumbnailURL:    "{json:thumbnail_url}",
+		ErrorMessage:    "{json:message}",
+	}
+	if key.HasScope(models.APIKeyScopeDelete) {
+		config.DeletionURL = "{json:deletion_url}"
+	}
+	return config
+}
+
+/* BuildPicGoConfig 按密钥生成 PicGo 配置片段，配合 picgo-plugin-web-uploader 插件使用 */
+func BuildPicGoConfig(key *models.APIKey) map[string]interface{} {
+	header, _ := json.Marshal(map[string]string{"X-Pixelpunk-Key": key.KeyValue})
+	return map[string]interface{}{
+		"picBed": map[string]interface{}{
+			"uploader": "web-uploader",
+			"current":  "web-uploader",
+			"web-uploader": map[string]interface{}{
+				"url":          baseURL() + PicGoUploadPath,
+				"paramName":    "file",
+				"jsonPath":     "url",
+				"customHeader": string(header),
+				"customBody":   "",
+			},
+		},
+		"picgoPlugins": map[string]interface{}{
+			"picgo-plugin-web-uploader": true,
+		},
+	}
+}
+
+func baseURL() string {
+	return strings.TrimRight(utils.GetBaseUrl(), "/")
+}
+
+func siteName() string {
+	return setting.GetString("website_info", "site_name", "PixelPunk")
+}
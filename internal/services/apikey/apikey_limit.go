@@ -0,0 +1,122 @@
+package apikey
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/ratelimit"
+	"pixelpunk/pkg/errors"
+)
+
+const (
+	rateLimitName   = "apikey"
+	rateLimitWindow = time.Minute
+	maxAllowedIPs   = 50
+)
+
+/* RateLimitStatus 当前分钟窗口内的请求频率状态 */
+type RateLimitStatus struct {
+	Limit     int       // 每分钟限制，0表示不限制
+	Used      int       // 当前窗口已用次数（含本次）
+	Remaining int       // 当前窗口剩余次数
+	ResetAt   time.Time // 窗口重置时间
+}
+
+/* NormalizeAllowedIPs 校验并规范化IP白名单，支持单个IP和CIDR */
+func NormalizeAllowedIPs(ips []string) (string, error) {
+	seen := make(map[string]bool, len(ips))
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		ip = strings.TrimSpace(ip)
+		if ip == "" || seen[ip] {
+			continue
+		}
+		if strings.Contains(ip, "/") {
+			_, ipNet, err := net.ParseCIDR(ip)
+			if err != nil {
+				return "", errors.New(errors.CodeInvalidParameter, fmt.Sprintf("无效的CIDR网段: %s", ip))
+			}
+			ip = ipNet.String()
+		} else if net.ParseIP(ip) == nil {
+			return "", errors.New(errors.CodeInvalidParameter, fmt.Sprintf("无效的IP地址: %s", ip))
+		}
+		seen[ip] = true
+		result = append(result, ip)
+	}
+	if len(result) > maxAllowedIPs {
+		return "", errors.New(errors.CodeInvalidParameter, fmt.Sprintf("IP白名单最多%d条", maxAllowedIPs))
+	}
+
+	allowed := strings.Join(result, ",")
+	if len(allowed) > 1000 {
+		return "", errors.New(errors.CodeInvalidParameter, "IP白名单内容过长")
+	}
+	return allowed, nil
+}
+
+/* ParseAllowedIPs 解析IP白名单为切片 */
+func ParseAllowedIPs(ipsStr string) []string {
+	if ipsStr == "" {
+		return []string{}
+	}
+	return strings.Split(ipsStr, ",")
+}
+
+/* IsIPAllowed 判断客户端IP是否在密钥白名单内，未配置白名单时全部放行 */
+func IsIPAllowed(apiKey *models.APIKey, clientIP string) bool {
+	if apiKey.AllowedIPs == "" {
+		return true
+	}
+
+	ipAddr := net.ParseIP(clientIP)
+	if ipAddr == nil {
+		return false
+	}
+
+	for _, entry := range ParseAllowedIPs(apiKey.AllowedIPs) {
+		if strings.Contains(entry, "/") {
+			if _, ipNet, err := net.ParseCIDR(entry); err == nil && ipNet.Contains(ipAddr) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ipAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+/* TakeRateLimit 原子地占用一次当前分钟窗口的请求额度，超出限制时返回 false，多实例共享同一计数 */
+func TakeRateLimit(apiKey *models.APIKey) (RateLimitStatus, bool) {
+	now := time.Now()
+	windowStart := now.Truncate(rateLimitWindow)
+	status := RateLimitStatus{
+		Limit:   apiKey.RateLimitPerMinute,
+		ResetAt: windowStart.Add(rateLimitWindow),
+	}
+	if apiKey.RateLimitPerMinute <= 0 {
+		return status, true
+	}
+
+	used := int(ratelimit.Incr(rateLimitName, rateWindowSubject(apiKey.ID, windowStart), 2*rateLimitWindow))
+	if used > apiKey.RateLimitPerMinute {
+		status.Used = apiKey.RateLimitPerMinute
+		return status, false
+	}
+
+	status.Used = used
+	status.Remaining = apiKey.RateLimitPerMinute - used
+	return status, true
+}
+
+/* CurrentRateUsage 获取密钥当前分钟窗口已用的请求次数 */
+func CurrentRateUsage(keyID string) int {
+	windowStart := time.Now().Truncate(rateLimitWindow)
+	return int(ratelimit.Count(rateLimitName, rateWindowSubject(keyID, windowStart)))
+}
+
+func rateWindowSubject(keyID string, windowStart time.Time) string {
+	return fmt.Sprintf("%s:%d", keyID, windowStart.Unix())
+}
@@ -0,0 +1,91 @@
+package apikey
+
+import (
+	"fmt"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// 目录层级向上查找的最大深度，防止异常数据导致死循环
+const maxFolderDepth = 64
+
+/* NormalizeScopes 校验并规范化授权范围，为空时默认仅允许上传 */
+func NormalizeScopes(scopes []string) (string, error) {
+	if len(scopes) == 0 {
+		return models.APIKeyScopeUpload, nil
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if !models.IsValidAPIKeyScope(scope) {
+			return "", errors.New(errors.CodeInvalidParameter, fmt.Sprintf("无效的授权范围: %s", scope))
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	if len(result) == 0 {
+		return models.APIKeyScopeUpload, nil
+	}
+	return strings.Join(result, ","), nil
+}
+
+/* CheckScope 检查密钥是否拥有指定授权范围 */
+func CheckScope(apiKey *models.APIKey, scope string) error {
+	if apiKey.HasScope(scope) {
+		return nil
+	}
+	return errors.New(errors.CodeForbidden, fmt.Sprintf("API密钥缺少 %s 权限", scope))
+}
+
+/* CheckFolderAccess 检查目录是否在密钥允许的范围内（限定目录本身或其子目录） */
+func CheckFolderAccess(apiKey *models.APIKey, folderID string) error {
+	if !apiKey.IsFolderRestricted() {
+		return nil
+	}
+	if folderID == apiKey.FolderID {
+		return nil
+	}
+	if folderID == "" {
+		return errors.New(errors.CodeForbidden, "API密钥仅允许访问指定目录")
+	}
+
+	db := database.DB
+	current := folderID
+	for i := 0; i < maxFolderDepth && current != ""; i++ {
+		if current == apiKey.FolderID {
+			return nil
+		}
+		var folder models.Folder
+		if err := db.Select("id", "parent_id").Where("id = ? AND user_id = ?", current, apiKey.UserID).First(&folder).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New(errors.CodeFolderNotFound, "文件夹不存在")
+			}
+			return errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+		}
+		current = folder.ParentID
+	}
+
+	return errors.New(errors.CodeForbidden, "API密钥仅允许访问指定目录")
+}
+
+/* CheckFileAccess 检查文件是否属于密钥所有者且位于允许的目录内 */
+func CheckFileAccess(apiKey *models.APIKey, fileID string) error {
+	var file models.File
+	if err := database.DB.Select("id", "folder_id").Where("id = ? AND user_id = ?", fileID, apiKey.UserID).First(&file).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New(errors.CodeFileNotFound, "文件不存在")
+		}
+		return errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	return CheckFolderAccess(apiKey, file.FolderID)
+}
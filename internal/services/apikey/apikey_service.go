@@ -83,9 +83,21 @@ func calculateExpiresAt(expiresInDays int) *common.JSONTime {
 }
 
 /* CreateAPIKey 创建新的API密钥 */
-func CreateAPIKey(userID uint, name string, storageLimit, singleFileLimit int64, uploadCountLimit int, allowedTypes []string, folderID string, expiresInDays int) (*models.APIKey, string, error) {
+func CreateAPIKey(userID uint, name string, storageLimit, singleFileLimit int64, uploadCountLimit int, allowedTypes []string, folderID string, restrictToFolder bool, scopes []string, rateLimitPerMinute int, allowedIPs []string, expiresInDays int) (*models.APIKey, string, error) {
 	db := database.DB
 
+	scopesStr, err := NormalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	allowedIPsStr, err := NormalizeAllowedIPs(allowedIPs)
+	if err != nil {
+		return nil, "", err
+	}
+	if restrictToFolder && folderID == "" {
+		return nil, "", errors.New(errors.CodeInvalidParameter, "限制目录访问时必须指定文件夹")
+	}
+
 	keyID := generateAPIKeyID()
 	keyValue, err := generateAPIKeyValue()
 	if err != nil {
@@ -106,21 +118,25 @@ func CreateAPIKey(userID uint, name string, storageLimit, singleFileLimit int64,
 	expiresAt := calculateExpiresAt(expiresInDays)
 
 	apiKey := models.APIKey{
-		ID:               keyID,
-		UserID:           userID,
-		Name:             name,
-		KeyValue:         keyValue, // 实际值，不会返回给用户
-		Status:           models.APIKeyStatusActive,
-		StorageLimit:     storageLimit,
-		StorageUsed:      0,
-		UploadCountLimit: uploadCountLimit,
-		UploadCountUsed:  0,
-		SingleFileLimit:  singleFileLimit,
-		AllowedTypes:     formatAllowedTypes(allowedTypes),
-		FolderID:         folderID,
-		ExpiresAt:        expiresAt,
-		CreatedAt:        common.JSONTimeNow(),
-		UpdatedAt:        common.JSONTimeNow(),
+		ID:                 keyID,
+		UserID:             userID,
+		Name:               name,
+		KeyValue:           keyValue, // 实际值，不会返回给用户
+		Status:             models.APIKeyStatusActive,
+		StorageLimit:       storageLimit,
+		StorageUsed:        0,
+		UploadCountLimit:   uploadCountLimit,
+		UploadCountUsed:    0,
+		SingleFileLimit:    singleFileLimit,
+		AllowedTypes:       formatAllowedTypes(allowedTypes),
+		FolderID:           folderID,
+		Scopes:             scopesStr,
+		RestrictToFolder:   restrictToFolder,
+		RateLimitPerMinute: rateLimitPerMinute,
+		AllowedIPs:         allowedIPsStr,
+		ExpiresAt:          expiresAt,
+		CreatedAt:          common.JSONTimeNow(),
+		UpdatedAt:          common.JSONTimeNow(),
 	}
 
 	if err := db.Create(&apiKey).Error; err != nil {
@@ -204,6 +220,29 @@ func UpdateAPIKey(userID uint, keyID string, updates map[string]interface{}) (*m
 		updates["allowed_types"] = formatAllowedTypes(allowedTypes)
 	}
 
+	if scopes, ok := updates["scopes"].([]string); ok {
+		scopesStr, err := NormalizeScopes(scopes)
+		if err != nil {
+			return nil, err
+		}
+		updates["scopes"] = scopesStr
+	}
+
+	if allowedIPs, ok := updates["allowed_ips"].([]string); ok {
+		allowedIPsStr, err := NormalizeAllowedIPs(allowedIPs)
+		if err != nil {
+			return nil, err
+		}
+		updates["allowed_ips"] = allowedIPsStr
+	}
+
+	if restrict, ok := updates["restrict_to_folder"].(bool); ok && restrict {
+		folderID, _ := updates["folder_id"].(string)
+		if folderID == "" && apiKey.FolderID == "" {
+			return nil, errors.New(errors.CodeInvalidParameter, "限制目录访问时必须指定文件夹")
+		}
+	}
+
 	if folderID, ok := updates["folder_id"].(string); ok && folderID != "" {
 		var count int64
 		if err := db.Model(&models.Folder{}).Where("id = ? AND user_id = ?", folderID, userID).Count(&count).Error; err != nil {
@@ -442,6 +481,14 @@ func GetAPIKeyStats(userID uint, keyID string) (map[string]interface{}, error) {
 		"is_active":          apiKey.IsActive(),
 		"folder_id":          apiKey.FolderID,
 		"folder_path":        folderPath,
+
+		"request_count":         apiKey.RequestCount,
+		"rate_limited_count":    apiKey.RateLimitedCount,
+		"ip_blocked_count":      apiKey.IPBlockedCount,
+		"rate_limit_per_minute": apiKey.RateLimitPerMinute,
+		"current_minute_usage":  CurrentRateUsage(apiKey.ID),
+		"allowed_ips":           ParseAllowedIPs(apiKey.AllowedIPs),
+		"last_used_ip":          apiKey.LastUsedIP,
 	}
 
 	return stats, nil
@@ -492,6 +539,27 @@ func ValidateAPIKey(keyValue string) (*models.APIKey, error) {
 	return apiKey, nil
 }
 
+/* ValidateAPIKeyID 按密钥ID验证API密钥，用于以密钥ID作为 Access Key 的签名认证 */
+func ValidateAPIKeyID(keyID string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := database.DB.Where("id = ?", keyID).First(&apiKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeInvalidParameter, "无效的API密钥")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询API密钥失败")
+	}
+
+	if !apiKey.IsActive() {
+		return nil, errors.New(errors.CodeForbidden, "API密钥已禁用")
+	}
+
+	if apiKey.IsExpired() {
+		return nil, errors.New(errors.CodeForbidden, "API密钥已过期")
+	}
+
+	return &apiKey, nil
+}
+
 /* CheckAPIKeyLimits 检查API密钥是否超过限制 */
 func CheckAPIKeyLimits(apiKey *models.APIKey, fileSize int64) error {
 	if !apiKey.CheckUploadCountLimit() {
@@ -0,0 +1,101 @@
+package apikey
+
+import (
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// usageFlushInterval 请求计数写入数据库的间隔
+const usageFlushInterval = 10 * time.Second
+
+// usageDelta 上次写入后某个密钥累积的计数
+type usageDelta struct {
+	requests    int64
+	rateLimited int64
+	ipBlocked   int64
+	lastIP      string
+}
+
+var (
+	usageMu      sync.Mutex
+	usageBuffer  = make(map[string]*usageDelta)
+	usageStarter sync.Once
+)
+
+/* RecordAPIKeyRequest 记录一次通过校验的请求 */
+func RecordAPIKeyRequest(keyID, clientIP string) {
+	recordUsage(keyID, func(d *usageDelta) {
+		d.requests++
+		d.lastIP = clientIP
+	})
+}
+
+/* RecordAPIKeyRateLimited 记录一次因频率限制被拒绝的请求 */
+func RecordAPIKeyRateLimited(keyID string) {
+	recordUsage(keyID, func(d *usageDelta) { d.rateLimited++ })
+}
+
+/* RecordAPIKeyIPBlocked 记录一次因IP不在白名单被拒绝的请求 */
+func RecordAPIKeyIPBlocked(keyID string) {
+	recordUsage(keyID, func(d *usageDelta) { d.ipBlocked++ })
+}
+
+// recordUsage 在内存中累积计数，由后台协程每 usageFlushInterval 批量写入，避免每个请求都更新数据库
+func recordUsage(keyID string, fn func(d *usageDelta)) {
+	usageStarter.Do(func() { go usageFlushLoop() })
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	d, ok := usageBuffer[keyID]
+	if !ok {
+		d = &usageDelta{}
+		usageBuffer[keyID] = d
+	}
+	fn(d)
+}
+
+func usageFlushLoop() {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		FlushUsage()
+	}
+}
+
+/* FlushUsage 将累积的请求计数写入数据库，服务关闭前调用以免丢失最后一批计数 */
+func FlushUsage() {
+	usageMu.Lock()
+	pending := usageBuffer
+	usageBuffer = make(map[string]*usageDelta, len(pending))
+	usageMu.Unlock()
+
+	if len(pending) == 0 || database.DB == nil {
+		return
+	}
+
+	for keyID, d := range pending {
+		updates := make(map[string]interface{}, 4)
+		if d.requests > 0 {
+			updates["request_count"] = gorm.Expr("request_count + ?", d.requests)
+		}
+		if d.rateLimited > 0 {
+			updates["rate_limited_count"] = gorm.Expr("rate_limited_count + ?", d.rateLimited)
+		}
+		if d.ipBlocked > 0 {
+			updates["ip_blocked_count"] = gorm.Expr("ip_blocked_count + ?", d.ipBlocked)
+		}
+		if d.lastIP != "" {
+			updates["last_used_ip"] = d.lastIP
+		}
+		// 使用 UpdateColumns 避免刷新 updated_at
+		if err := database.DB.Model(&models.APIKey{}).Where("id = ?", keyID).UpdateColumns(updates).Error; err != nil {
+			logger.Warn("更新API密钥请求计数失败: keyID=%s, error=%v", keyID, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+)
+
+// 脱敏后的占位值
+const redactedValue = "******"
+
+// 字段名包含以下片段时视为敏感信息，写入审计日志前脱敏
+var sensitiveKeyParts = []string{"password", "secret", "private_key", "access_key", "api_key", "credential"}
+
+/* Entry 一次管理员操作的审计记录 */
+type Entry struct {
+	ActorID    uint
+	ActorName  string
+	ActorRole  int
+	Action     string
+	TargetType string
+	TargetID   string
+	Method     string
+	Path       string
+	StatusCode int
+	IP         string
+	UserAgent  string
+	Request    interface{}
+	Before     interface{}
+	After      interface{}
+}
+
+/* QueryParams 审计日志查询条件 */
+type QueryParams struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string
+	Keyword    string
+	Success    *bool
+	StartTime  *time.Time
+	EndTime    *time.Time
+	Page       int
+	Size       int
+}
+
+/* Record 写入审计日志，before/after 会脱敏后计算字段级差异 */
+func Record(entry Entry) {
+	before := normalize(entry.Before)
+	after := normalize(entry.After)
+
+	log := models.AdminAuditLog{
+		ActorID:    entry.ActorID,
+		ActorName:  entry.ActorName,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Method:     entry.Method,
+		Path:       truncate(entry.Path, 255),
+		StatusCode: entry.StatusCode,
+		Success:    entry.StatusCode > 0 && entry.StatusCode < 400,
+		IP:         entry.IP,
+		UserAgent:  truncate(entry.UserAgent, 500),
+		Request:    marshal(normalize(entry.Request)),
+		Before:     marshal(before),
+		After:      marshal(after),
+		Diff:       marshal(diff(before, after)),
+	}
+
+	if err := database.GetDB().Create(&log).Error; err != nil {
+		logger.Error("写入管理员审计日志失败: actor=%d, action=%s, error=%v", entry.ActorID, entry.Action, err)
+	}
+}
+
+/* List 分页查询审计日志 */
+func List(params QueryParams) ([]models.AdminAuditLog, int64, error) {
+	query := database.GetDB().Model(&models.AdminAuditLog{})
+
+	if params.ActorID > 0 {
+		query = query.Where("actor_id = ?", params.ActorID)
+	}
+	if params.Action != "" {
+		query = query.Where("action LIKE ?", params.Action+"%")
+	}
+	if params.TargetType != "" {
+		query = query.Where("target_type = ?", params.TargetType)
+	}
+	if params.TargetID != "" {
+		query = query.Where("target_id = ?", params.TargetID)
+	}
+	if params.IP != "" {
+		query = query.Where("ip = ?", params.IP)
+	}
+	if params.Success != nil {
+		query = query.Where("success = ?", *params.Success)
+	}
+	if params.StartTime != nil {
+		query = query.Where("created_at >= ?", *params.StartTime)
+	}
+	if params.EndTime != nil {
+		query = query.Where("created_at <= ?", *params.EndTime)
+	}
+	if params.Keyword != "" {
+		like := "%" + params.Keyword + "%"
+		query = query.Where("actor_name LIKE ? OR path LIKE ? OR action LIKE ? OR target_id LIKE ?", like, like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询审计日志失败")
+	}
+
+	var logs []models.AdminAuditLog
+	if err := query.Order("id DESC").Offset((params.Page - 1) * params.Size).Limit(params.Size).
+		Find(&logs).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询审计日志失败")
+	}
+	return logs, total, nil
+}
+
+/* Get 获取单条审计日志 */
+func Get(id uint) (*models.AdminAuditLog, error) {
+	var log models.AdminAuditLog
+	if err := database.GetDB().First(&log, id).Error; err != nil {
+		return nil, errors.New(errors.CodeNotFound, "审计日志不存在")
+	}
+	return &log, nil
+}
+
+/* IsSensitiveKey 判断字段名是否需要脱敏 */
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	// token 只按完整单词匹配，避免误伤 max_tokens 之类的普通配置
+	for _, word := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if word == "token" {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize 将任意值转换为JSON通用结构并脱敏
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+
+	var generic interface{}
+	switch data := v.(type) {
+	case []byte:
+		if len(data) == 0 || json.Unmarshal(data, &generic) != nil {
+			return nil
+		}
+	case json.RawMessage:
+		if len(data) == 0 || json.Unmarshal(data, &generic) != nil {
+			return nil
+		}
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		if json.Unmarshal(raw, &generic) != nil {
+			return nil
+		}
+	}
+	return redact(generic)
+}
+
+func redact(v interface{}) interface{} {
+	switch data := v.(type) {
+	case map[string]interface{}:
+		for k, val := range data {
+			if IsSensitiveKey(k) {
+				if val != nil && val != "" {
+					data[k] = redactedValue
+				}
+				continue
+			}
+			data[k] = redact(val)
+		}
+		// 设置项以 key/value 形式出现时按 key 判断是否敏感
+		if key, ok := data["key"].(string); ok && IsSensitiveKey(key) {
+			if _, has := data["value"]; has {
+				data["value"] = redactedValue
+			}
+		}
+		return data
+	case []interface{}:
+		for i := range data {
+			data[i] = redact(data[i])
+		}
+		return data
+	default:
+		return v
+	}
+}
+
+// diff 比较两个对象的顶层字段，返回有变化的字段
+func diff(before, after interface{}) map[string]interface{} {
+	if before == nil && after == nil {
+		return nil
+	}
+	beforeMap, _ := before.(map[string]interface{})
+	afterMap, _ := after.(map[string]interface{})
+	if beforeMap == nil && afterMap == nil {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return map[string]interface{}{"value": map[string]interface{}{"from": before, "to": after}}
+	}
+
+	changes := make(map[string]interface{})
+	for k, to := range afterMap {
+		from, ok := beforeMap[k]
+		if !ok || !reflect.DeepEqual(from, to) {
+			changes[k] = map[string]interface{}{"from": from, "to": to}
+		}
+	}
+	for k, from := range beforeMap {
+		if _, ok := afterMap[k]; !ok {
+			changes[k] = map[string]interface{}{"from": from, "to": nil}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func marshal(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -27,8 +27,13 @@ func GetCurrentTimestamp() int64 {
 	return time.Now().Unix()
 }
 
-/* GenerateToken 生成JWT令牌 */
+/* GenerateToken 生成不关联登录会话的JWT令牌，登录场景应使用 IssueSessionToken */
 func GenerateToken(userID uint, username string, role int, jwtSecret string, expiresHours int) (string, error) {
+	return generateToken(userID, username, role, "", jwtSecret, expiresHours)
+}
+
+// generateToken sessionID 非空时写入 jti，用于服务端吊销
+func generateToken(userID uint, username string, role int, sessionID string, jwtSecret string, expiresHours int) (string, error) {
 	// 安全检查：不再使用默认密钥，强制要求配置
 	if jwtSecret == "" {
 		return "", fmt.Errorf("JWT密钥未配置，拒绝生成Token")
@@ -50,6 +55,7 @@ func GenerateToken(userID uint, username string, role int, jwtSecret string, exp
 		Username: username,
 		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -76,6 +82,9 @@ func ParseToken(tokenString string, jwtSecret string) (*JWTClaims, error) {
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if IsSessionRevoked(claims.ID) {
+			return nil, ErrSessionRevoked
+		}
 		return claims, nil
 	}
 
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sessionStateKeyPrefix = "auth:session:state:"
+	sessionSeenKeyPrefix  = "auth:session:seen:"
+	sessionStateRevoked   = "revoked"
+	sessionStateActive    = "active"
+
+	// 有效会话的状态缓存时间，多实例下吊销最晚在该时间后生效（同一缓存后端时立即生效）
+	sessionActiveCacheTTL = 5 * time.Minute
+	// 最后活跃时间的写库间隔，避免每个请求都更新数据库
+	sessionTouchInterval = time.Minute
+)
+
+// ErrSessionRevoked 会话已被吊销，对应的JWT不再有效
+var ErrSessionRevoked = stderrors.New("登录会话已失效")
+
+/* ClientInfo 签发会话时记录的客户端信息 */
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+/* IssueSessionToken 创建登录会话并签发携带会话ID(jti)的JWT */
+func IssueSessionToken(user *models.User, loginMethod string, client ClientInfo, jwtSecret string, expiresHours int) (string, error) {
+	if expiresHours <= 0 {
+		expiresHours = defaultExpiresHours
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session := models.UserSession{
+		ID:          utils.GenerateRandomString(32),
+		UserID:      user.ID,
+		LoginMethod: loginMethod,
+		DeviceName:  common.DescribeUserAgent(userAgent),
+		UserAgent:   userAgent,
+		ClientIP:    client.IP,
+		LastSeenIP:  client.IP,
+		LastSeenAt:  common.JSONTime(now),
+		ExpiresAt:   common.JSONTime(now.Add(time.Duration(expiresHours) * time.Hour)),
+	}
+
+	token, err := generateToken(user.ID, user.Username, int(user.Role), session.ID, jwtSecret, expiresHours)
+	if err != nil {
+		return "", err
+	}
+
+	if err := database.GetDB().Create(&session).Error; err != nil {
+		return "", fmt.Errorf("创建登录会话失败: %v", err)
+	}
+	_ = cache.GetCache().Set(sessionStateKeyPrefix+session.ID, sessionStateActive, sessionActiveCacheTTL)
+
+	return token, nil
+}
+
+/* IsSessionRevoked 判断会话是否已被吊销，优先读取缓存，未命中时查库并回填 */
+func IsSessionRevoked(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	key := sessionStateKeyPrefix + sessionID
+	if state, err := cache.GetCache().Get(key); err == nil && state != "" {
+		return state == sessionStateRevoked
+	}
+
+	var session models.UserSession
+	if err := database.GetDB().Select("id", "revoked_at", "expires_at").
+		Where("id = ?", sessionID).First(&session).Error; err != nil {
+		// 会话记录不存在（被清理或伪造的jti）视为失效，查询出错时放行避免数据库抖动导致全员掉线
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			_ = cache.GetCache().Set(key, sessionStateRevoked, sessionActiveCacheTTL)
+			return true
+		}
+		logger.Warn("查询登录会话失败: sessionID=%s, error=%v", sessionID, err)
+		return false
+	}
+
+	if session.IsRevoked() {
+		markSessionRevoked(&session)
+		return true
+	}
+	_ = cache.GetCache().Set(key, sessionStateActive, sessionActiveCacheTTL)
+	return false
+}
+
+/* TouchSession 更新会话最后活跃时间和IP，按固定间隔节流写库 */
+func TouchSession(sessionID, clientIP string) {
+	if sessionID == "" {
+		return
+	}
+	seenKey := sessionSeenKeyPrefix + sessionID
+	if cache.GetCache().Exists(seenKey) {
+		return
+	}
+	_ = cache.GetCache().Set(seenKey, "1", sessionTouchInterval)
+
+	if err := database.GetDB().Model(&models.UserSession{}).
+		Where("id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"last_seen_at": common.JSONTimeNow(),
+			"last_seen_ip": clientIP,
+		}).Error; err != nil {
+		logger.Warn("更新会话活跃时间失败: sessionID=%s, error=%v", sessionID, err)
+	}
+}
+
+/* ListUserSessions 获取用户未过期且未吊销的会话，按最后活跃时间倒序 */
+func ListUserSessions(userID uint) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := database.GetDB().
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+/* RevokeUserSession 吊销用户的指定会话，返回是否找到对应会话 */
+func RevokeUserSession(userID uint, sessionID string) (bool, error) {
+	var session models.UserSession
+	if err := database.GetDB().Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if session.IsRevoked() {
+		return true, nil
+	}
+
+	if err := revokeSessions([]models.UserSession{session}); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+/* RevokeUserSessions 吊销用户的全部会话，exceptSessionID 非空时保留该会话（通常为当前会话） */
+func RevokeUserSessions(userID uint, exceptSessionID string) (int, error) {
+	query := database.GetDB().Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now())
+	if exceptSessionID != "" {
+		query = query.Where("id <> ?", exceptSessionID)
+	}
+
+	var sessions []models.UserSession
+	if err := query.Find(&sessions).Error; err != nil {
+		return 0, err
+	}
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	if err := revokeSessions(sessions); err != nil {
+		return 0, err
+	}
+	return len(sessions), nil
+}
+
+/* CleanExpiredSessions 删除已过期的会话记录 */
+func CleanExpiredSessions() (int64, error) {
+	result := database.GetDB().Where("expires_at < ?", time.Now()).Delete(&models.UserSession{})
+	return result.RowsAffected, result.Error
+}
+
+func revokeSessions(sessions []models.UserSession) error {
+	ids := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+
+	now := common.JSONTimeNow()
+	if err := database.GetDB().Model(&models.UserSession{}).
+		Where("id IN ?", ids).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		sessions[i].RevokedAt = &now
+		markSessionRevoked(&sessions[i])
+	}
+	return nil
+}
+
+// markSessionRevoked 将吊销状态写入缓存，保留到JWT自然过期为止
+func markSessionRevoked(session *models.UserSession) {
+	ttl := time.Until(time.Time(session.ExpiresAt))
+	if ttl <= 0 {
+		ttl = sessionActiveCacheTTL
+	}
+	_ = cache.GetCache().Set(sessionStateKeyPrefix+session.ID, sessionStateRevoked, ttl)
+}
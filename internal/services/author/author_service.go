@@ -144,7 +144,7 @@ func formatFileSize(bytes int64) string {
 
 /* GetAuthorHomepage 获取作者主页信息 */
 func GetAuthorHomepage(authorID uint) (*AuthorHomepage, error) {
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	var user models.User
 	if err := db.First(&user, authorID).Error; err != nil {
@@ -360,7 +360,7 @@ func GetAuthorHomepage(authorID uint) (*AuthorHomepage, error) {
 
 /* GetAuthorFolder 获取作者特定文件夹内容 */
 func GetAuthorFolder(authorID uint, folderID string, page, size int) (*FolderContent, error) {
-	db := database.GetDB()
+	db := database.ReadDB()
 
 	var folder models.Folder
 	if err := db.Where("id = ? AND user_id = ? AND permission = 'public'", folderID, authorID).
@@ -533,7 +533,7 @@ func GetAuthorFolder(authorID uint, folderID string, page, size int) (*FolderCon
 
 /* getFileAIInfo 获取文件AI信息 */
 func getFileAIInfo(fileID string) (*FileAIInfo, error) {
-	db := database.GetDB()
+	db := database.ReadDB()
 	var aiInfo models.FileAIInfo
 	if err := db.Where("file_id = ?", fileID).First(&aiInfo).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -0,0 +1,236 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/services/storage"
+	"pixelpunk/pkg/config"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	formatVersion = 1
+
+	manifestEntry = "manifest.json"
+	configEntry   = "config.yaml"
+	databaseDir   = "database/"
+	storageDir    = "storage/"
+)
+
+// Manifest 备份清单，位于归档首位，恢复时先校验
+type Manifest struct {
+	Version      int           `json:"version"`
+	AppVersion   string        `json:"app_version"`
+	DBType       string        `json:"db_type"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Tables       []string      `json:"tables"`
+	Channels     []ChannelInfo `json:"channels"`
+	HasConfig    bool          `json:"has_config"`
+	StorageRoots []string      `json:"storage_roots,omitempty"` // 已打包的本地存储目录
+}
+
+/* WriteArchive 将数据库、配置文件以及可选的本地存储目录打包为 tar.gz */
+func WriteArchive(w io.Writer, includeStorage bool) (*Manifest, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据表失败: %w", err)
+	}
+	sort.Strings(tables)
+
+	channels, err := collectChannels(db)
+	if err != nil {
+		return nil, fmt.Errorf("获取存储渠道失败: %w", err)
+	}
+
+	configPath := findConfigFile()
+	manifest := &Manifest{
+		Version:    formatVersion,
+		AppVersion: appVersion,
+		DBType:     config.GetConfig().Database.Type,
+		CreatedAt:  time.Now(),
+		Tables:     tables,
+		Channels:   channels,
+		HasConfig:  configPath != "",
+	}
+	if includeStorage {
+		manifest.StorageRoots = localStorageRoots()
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := writeEntry(tw, manifestEntry, manifestData); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		if err := writeTableEntry(tw, table); err != nil {
+			return nil, fmt.Errorf("导出数据表 %s 失败: %w", table, err)
+		}
+	}
+
+	if configPath != "" {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+		if err := writeEntry(tw, configEntry, data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, root := range manifest.StorageRoots {
+		if err := writeDirEntries(tw, root); err != nil {
+			return nil, fmt.Errorf("打包存储目录 %s 失败: %w", root, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// writeTableEntry 先导出到临时文件以获得大小，避免整表数据驻留内存
+func writeTableEntry(tw *tar.Writer, table string) error {
+	tmp, err := os.CreateTemp("", "pixelpunk-backup-table-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err := dumpTable(database.GetDB(), table, tmp); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{Name: databaseDir + table + ".jsonl", Mode: 0600, Size: size, ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, tmp)
+	return err
+}
+
+func writeDirEntries(tw *tar.Writer, root string) error {
+	prefix := storageDir + storageArchiveName(root)
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		hdr := &tar.Header{Name: prefix + "/" + filepath.ToSlash(rel), Mode: 0644, Size: info.Size(), ModTime: info.ModTime()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// storageArchiveName 存储目录在归档中的名称，绝对路径去掉开头的斜杠
+func storageArchiveName(root string) string {
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(root)), "/")
+}
+
+// localStorageRoots 所有本地存储渠道的原图与缩略图目录
+func localStorageRoots() []string {
+	channels, err := storage.GetAllChannels()
+	if err != nil {
+		logger.Warn("获取存储渠道失败，跳过本地存储备份: %v", err)
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var roots []string
+	for _, ch := range channels {
+		if ch.Type != "local" {
+			continue
+		}
+		cfg, err := storage.GetChannelConfigMap(ch.ID)
+		if err != nil {
+			logger.Warn("获取存储渠道 %s 配置失败，跳过: %v", ch.Name, err)
+			continue
+		}
+		for key, def := range map[string]string{"base_path": "uploads/files", "thumbnail_path": "uploads/thumbnails"} {
+			root := def
+			if v, ok := cfg[key].(string); ok && strings.TrimSpace(v) != "" {
+				root = strings.TrimSpace(v)
+			}
+			root = filepath.Clean(root)
+			if !seen[root] {
+				seen[root] = true
+				roots = append(roots, root)
+			}
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+func findConfigFile() string {
+	for _, p := range []string{"configs/config.yaml", "config.yaml"} {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
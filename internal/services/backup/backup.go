@@ -0,0 +1,319 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/internal/services/storage"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage/adapter"
+)
+
+const (
+	settingGroup   = "backup"
+	archivePrefix  = "pixelpunk-backup-"
+	archiveSuffix  = ".tar.gz"
+	archiveTimeFmt = "20060102-150405"
+	uploadTimeout  = 2 * time.Hour
+)
+
+// Config 备份设置
+type Config struct {
+	Enabled        bool   `json:"enabled"`
+	ChannelID      string `json:"channel_id"`      // 备份上传的目标存储渠道，需支持直接写入（S3 兼容存储或 WebDAV）
+	Path           string `json:"path"`            // 目标渠道中的目录
+	Hour           int    `json:"hour"`            // 每天执行的整点
+	RetentionCount int    `json:"retention_count"` // 保留最近的备份数量，0 表示不清理
+	IncludeStorage bool   `json:"include_storage"` // 是否打包本地存储渠道的文件
+}
+
+// Info 备份文件信息
+type Info struct {
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Status 备份运行状态
+type Status struct {
+	Running    bool       `json:"running"`
+	LastRunAt  *time.Time `json:"last_run_at"`
+	LastBackup *Info      `json:"last_backup"`
+	LastError  string     `json:"last_error"`
+}
+
+var (
+	appVersion string
+	running    atomic.Bool
+	statusMu   sync.Mutex
+	lastStatus Status
+)
+
+/* SetAppVersion 记录应用版本，写入备份清单 */
+func SetAppVersion(version string) {
+	appVersion = version
+}
+
+/* GetConfig 读取备份设置 */
+func GetConfig() Config {
+	return Config{
+		Enabled:        setting.GetBool(settingGroup, "enabled", false),
+		ChannelID:      setting.GetString(settingGroup, "channel_id", ""),
+		Path:           strings.Trim(setting.GetString(settingGroup, "path", "backups"), "/"),
+		Hour:           setting.GetInt(settingGroup, "hour", 3),
+		RetentionCount: setting.GetInt(settingGroup, "retention_count", 7),
+		IncludeStorage: setting.GetBool(settingGroup, "include_storage", false),
+	}
+}
+
+/* GetStatus 获取最近一次备份的运行状态 */
+func GetStatus() Status {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	status := lastStatus
+	status.Running = running.Load()
+	return status
+}
+
+/* RunScheduled 定时任务入口，开启备份且到达设置的整点时执行 */
+func RunScheduled() {
+	cfg := GetConfig()
+	if !cfg.Enabled || time.Now().Hour() != cfg.Hour {
+		return
+	}
+	if _, err := Run(); err != nil {
+		logger.Error("定时备份失败: %v", err)
+	}
+}
+
+/* Run 立即执行一次备份：打包到临时文件后上传到目标渠道，并按保留数量清理旧备份 */
+func Run() (*Info, error) {
+	cfg, t, err := begin()
+	if err != nil {
+		return nil, err
+	}
+	defer running.Store(false)
+	return execute(cfg, t)
+}
+
+/* Start 校验设置后在后台执行备份，结果通过 GetStatus 查看 */
+func Start() error {
+	cfg, t, err := begin()
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer running.Store(false)
+		if _, err := execute(cfg, t); err != nil {
+			logger.Error("手动备份失败: %v", err)
+		}
+	}()
+	return nil
+}
+
+// begin 校验目标渠道并占用运行标记，成功后调用方负责释放
+func begin() (Config, *target, error) {
+	cfg := GetConfig()
+	t, err := getTarget(cfg.ChannelID)
+	if err != nil {
+		return cfg, nil, err
+	}
+	if !running.CompareAndSwap(false, true) {
+		return cfg, nil, errors.New(errors.CodeConflict, "已有备份正在进行")
+	}
+	return cfg, t, nil
+}
+
+func execute(cfg Config, t *target) (*Info, error) {
+	info, err := runBackup(cfg, t.writer)
+	now := time.Now()
+	statusMu.Lock()
+	lastStatus.LastRunAt = &now
+	if err != nil {
+		lastStatus.LastError = err.Error()
+	} else {
+		lastStatus.LastError = ""
+		lastStatus.LastBackup = info
+	}
+	statusMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("备份完成: %s (%d 字节)", info.Key, info.Size)
+	if cfg.RetentionCount > 0 {
+		if err := prune(cfg, t); err != nil {
+			logger.Warn("清理旧备份失败: %v", err)
+		}
+	}
+	return info, nil
+}
+
+func runBackup(cfg Config, writer adapter.ObjectWriter) (*Info, error) {
+	tmp, err := os.CreateTemp("", "pixelpunk-backup-*"+archiveSuffix)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "创建临时文件失败")
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	manifest, err := WriteArchive(tmp, cfg.IncludeStorage)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "生成备份失败")
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "生成备份失败")
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "生成备份失败")
+	}
+
+	key := path.Join(cfg.Path, archivePrefix+manifest.CreatedAt.Format(archiveTimeFmt)+archiveSuffix)
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+	if err := writer.PutObject(ctx, key, tmp, size, "application/gzip"); err != nil {
+		return nil, errors.Wrap(err, errors.CodeFileUploadFailed, "上传备份失败")
+	}
+	return &Info{Key: key, Size: size, CreatedAt: manifest.CreatedAt}, nil
+}
+
+/* List 列出目标渠道中的备份，按时间倒序 */
+func List() ([]Info, error) {
+	cfg := GetConfig()
+	t, err := getTarget(cfg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	return listBackups(cfg, t.lister)
+}
+
+func listBackups(cfg Config, lister adapter.ObjectLister) ([]Info, error) {
+	prefix := archivePrefix
+	if cfg.Path != "" {
+		prefix = cfg.Path + "/" + archivePrefix
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var backups []Info
+	cursor := ""
+	for {
+		page, err := lister.ListObjects(ctx, prefix, cursor, 1000)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeInternal, "列举备份失败")
+		}
+		for _, obj := range page.Objects {
+			name := strings.TrimSuffix(path.Base(obj.Key), archiveSuffix)
+			createdAt, err := time.ParseInLocation(archiveTimeFmt, strings.TrimPrefix(name, archivePrefix), time.Local)
+			if err != nil || !strings.HasSuffix(obj.Key, archiveSuffix) {
+				continue
+			}
+			backups = append(backups, Info{Key: obj.Key, Size: obj.Size, CreatedAt: createdAt})
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// prune 删除超出保留数量的旧备份
+func prune(cfg Config, t *target) error {
+	backups, err := listBackups(cfg, t.lister)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= cfg.RetentionCount {
+		return nil
+	}
+	for _, b := range backups[cfg.RetentionCount:] {
+		if err := t.adapter.Delete(context.Background(), b.Key); err != nil {
+			logger.Warn("删除旧备份 %s 失败: %v", b.Key, err)
+			continue
+		}
+		logger.Info("已删除旧备份: %s", b.Key)
+	}
+	return nil
+}
+
+/* Delete 删除指定备份 */
+func Delete(key string) error {
+	st, err := getBackupObject(key)
+	if err != nil {
+		return err
+	}
+	if err := st.Delete(context.Background(), key); err != nil {
+		return errors.Wrap(err, errors.CodeFileDeleteFailed, "删除备份失败")
+	}
+	return nil
+}
+
+/* Open 读取目标渠道中的备份文件，调用方负责关闭 */
+func Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	st, err := getBackupObject(key)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := st.ReadFile(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeFileDownloadFailed, "读取备份失败")
+	}
+	return rc, nil
+}
+
+// getBackupObject 校验键名属于备份目录，避免借备份接口操作渠道中的其他文件
+func getBackupObject(key string) (adapter.StorageAdapter, error) {
+	cfg := GetConfig()
+	dir := cfg.Path
+	if dir == "" {
+		dir = "."
+	}
+	name := path.Base(key)
+	if path.Dir(key) != dir || !strings.HasPrefix(name, archivePrefix) || !strings.HasSuffix(name, archiveSuffix) {
+		return nil, errors.New(errors.CodeInvalidParameter, "无效的备份文件")
+	}
+	t, err := getTarget(cfg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	return t.adapter, nil
+}
+
+// target 备份目标渠道，要求支持直接写入与列举对象
+type target struct {
+	adapter adapter.StorageAdapter
+	writer  adapter.ObjectWriter
+	lister  adapter.ObjectLister
+}
+
+func getTarget(channelID string) (*target, error) {
+	if channelID == "" {
+		return nil, errors.New(errors.CodeInvalidParameter, "未设置备份目标存储渠道")
+	}
+	st, err := storage.GetChannelAdapter(channelID)
+	if err != nil {
+		return nil, err
+	}
+	writer, canWrite := st.(adapter.ObjectWriter)
+	lister, canList := st.(adapter.ObjectLister)
+	if !canWrite || !canList {
+		return nil, errors.New(errors.CodeInvalidParameter, "备份目标仅支持 S3 兼容存储或 WebDAV 渠道")
+	}
+	return &target{adapter: st, writer: writer, lister: lister}, nil
+}
@@ -0,0 +1,127 @@
+package backup
+
+import (
+	"fmt"
+	"strconv"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// ChannelInfo 导出时的存储渠道，导入到新实例时用于确定渠道ID映射
+type ChannelInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	IsDefault bool   `json:"is_default"`
+	Files     int64  `json:"files"`
+}
+
+/* ListCurrentChannels 当前实例的存储渠道，用于与导出文件中的渠道对照 */
+func ListCurrentChannels() ([]ChannelInfo, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	return collectChannels(db)
+}
+
+func collectChannels(db *gorm.DB) ([]ChannelInfo, error) {
+	var channels []models.StorageChannel
+	if err := db.Order("created_at").Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	result := make([]ChannelInfo, 0, len(channels))
+	for _, ch := range channels {
+		info := ChannelInfo{ID: ch.ID, Name: ch.Name, Type: ch.Type, IsDefault: ch.IsDefault}
+		db.Model(&models.File{}).Where("storage_provider_id = ?", ch.ID).Count(&info.Files)
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// preservedChannel 导入前保存的目标渠道，数据表替换后重新写入
+type preservedChannel struct {
+	channel models.StorageChannel
+	configs []models.StorageConfigItem
+}
+
+// loadMappedChannels 读取映射目标渠道的当前配置，目标渠道必须已存在于当前实例
+func loadMappedChannels(db *gorm.DB, mapping map[string]string) (map[string]preservedChannel, error) {
+	preserved := make(map[string]preservedChannel, len(mapping))
+	for _, newID := range mapping {
+		if _, ok := preserved[newID]; ok {
+			continue
+		}
+		var p preservedChannel
+		if err := db.First(&p.channel, "id = ?", newID).Error; err != nil {
+			return nil, fmt.Errorf("目标存储渠道 %s 在当前实例中不存在", newID)
+		}
+		if err := db.Where("channel_id = ?", newID).Find(&p.configs).Error; err != nil {
+			return nil, err
+		}
+		preserved[newID] = p
+	}
+	return preserved, nil
+}
+
+// remapChannels 将导入数据中的旧渠道替换为当前实例的渠道：删除旧渠道及其配置，写回目标渠道，并改写文件记录与设置中的渠道ID
+func remapChannels(tx *gorm.DB, mapping map[string]string, preserved map[string]preservedChannel) error {
+	for newID, p := range preserved {
+		if err := tx.Where("channel_id = ?", newID).Delete(&models.StorageConfigItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", newID).Delete(&models.StorageChannel{}).Error; err != nil {
+			return err
+		}
+		channel := p.channel
+		if err := tx.Create(&channel).Error; err != nil {
+			return err
+		}
+		if len(p.configs) > 0 {
+			configs := p.configs
+			if err := tx.Create(&configs).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	for oldID, newID := range mapping {
+		var old models.StorageChannel
+		wasDefault := tx.First(&old, "id = ?", oldID).Error == nil && old.IsDefault
+		if oldID != newID {
+			if err := tx.Where("channel_id = ?", oldID).Delete(&models.StorageConfigItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id = ?", oldID).Delete(&models.StorageChannel{}).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.File{}).Where("storage_provider_id = ?", oldID).Update("storage_provider_id", newID)
+		if result.Error != nil {
+			return result.Error
+		}
+		// 设置值以 JSON 字符串保存，兼容带引号与不带引号两种形式
+		if err := tx.Model(&models.Setting{}).Where("value = ?", strconv.Quote(oldID)).Update("value", strconv.Quote(newID)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Setting{}).Where("value = ?", oldID).Update("value", newID).Error; err != nil {
+			return err
+		}
+
+		if wasDefault {
+			if err := tx.Model(&models.StorageChannel{}).Where("id <> ?", newID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.StorageChannel{}).Where("id = ?", newID).Update("is_default", true).Error; err != nil {
+				return err
+			}
+		}
+		logger.Info("存储渠道映射 %s -> %s，更新文件记录 %d 条", oldID, newID, result.RowsAffected)
+	}
+	return nil
+}
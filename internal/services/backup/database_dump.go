@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const restoreBatchSize = 200
+
+// dumpTable 将表中所有行以 JSON Lines 写出，与数据库类型无关，便于在 MySQL 与 SQLite 之间恢复
+func dumpTable(db *gorm.DB, table string, w io.Writer) (int64, error) {
+	rows, err := db.Table(table).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	enc := json.NewEncoder(w)
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = normalizeValue(values[i])
+		}
+		if err := enc.Encode(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// restoreTable 清空目标表后写入备份数据，只写入目标表中存在的列，时间列按目标库类型还原
+func restoreTable(tx *gorm.DB, table string, r io.Reader) (int64, error) {
+	columnTypes, err := tx.Migrator().ColumnTypes(table)
+	if err != nil {
+		return 0, err
+	}
+	timeColumns := make(map[string]bool, len(columnTypes))
+	known := make(map[string]bool, len(columnTypes))
+	for _, ct := range columnTypes {
+		known[ct.Name()] = true
+		typeName := strings.ToUpper(ct.DatabaseTypeName())
+		if strings.Contains(typeName, "DATE") || strings.Contains(typeName, "TIME") {
+			timeColumns[ct.Name()] = true
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM ?", clause.Table{Name: table}).Error; err != nil {
+		return 0, err
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+
+	var count int64
+	batch := make([]map[string]interface{}, 0, restoreBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := tx.Table(table).Create(&batch).Error; err != nil {
+			return err
+		}
+		count += int64(len(batch))
+		batch = make([]map[string]interface{}, 0, restoreBatchSize)
+		return nil
+	}
+
+	for {
+		var row map[string]interface{}
+		if err := dec.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return count, fmt.Errorf("解析备份数据失败: %w", err)
+		}
+		for col, val := range row {
+			if !known[col] {
+				delete(row, col)
+				continue
+			}
+			if s, ok := val.(string); ok && timeColumns[col] {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					row[col] = t
+				}
+			}
+		}
+		if len(row) == 0 {
+			continue
+		}
+		batch = append(batch, row)
+		if len(batch) >= restoreBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
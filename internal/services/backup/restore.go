@@ -0,0 +1,207 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+)
+
+// RestoreOptions 恢复选项
+type RestoreOptions struct {
+	Storage    bool              // 恢复本地存储文件
+	ConfigPath string            // 配置文件写入路径，为空时不恢复配置文件
+	ChannelMap map[string]string // 存储渠道映射 旧渠道ID -> 当前实例渠道ID，迁移到新实例时使用
+}
+
+// RestoreResult 恢复结果
+type RestoreResult struct {
+	Manifest     *Manifest        `json:"manifest"`
+	Tables       map[string]int64 `json:"tables"`
+	Skipped      []string         `json:"skipped"` // 当前数据库中不存在的表
+	StorageFiles int              `json:"storage_files"`
+	Config       bool             `json:"config"`
+}
+
+/* Restore 从备份归档恢复数据库，数据表在同一事务中整体替换，失败时数据库保持原样（已写出的存储文件不回滚） */
+func Restore(r io.Reader, opts RestoreOptions) (*RestoreResult, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的备份文件: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	manifest, err := readManifestEntry(tr)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version > formatVersion {
+		return nil, fmt.Errorf("备份格式版本 %d 高于当前支持的版本 %d，请升级后再恢复", manifest.Version, formatVersion)
+	}
+
+	existing, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据表失败: %w", err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		known[t] = true
+	}
+
+	preserved, err := loadMappedChannels(db, opts.ChannelMap)
+	if err != nil {
+		return nil, err
+	}
+	for oldID := range opts.ChannelMap {
+		if !manifestHasChannel(manifest, oldID) {
+			logger.Warn("导出文件中不存在存储渠道 %s，仅改写引用该ID的文件记录", oldID)
+		}
+	}
+
+	result := &RestoreResult{Manifest: manifest, Tables: map[string]int64{}, Skipped: []string{}}
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取备份文件失败: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(hdr.Name, databaseDir):
+			table := strings.TrimSuffix(strings.TrimPrefix(hdr.Name, databaseDir), ".jsonl")
+			if !known[table] {
+				result.Skipped = append(result.Skipped, table)
+				continue
+			}
+			count, err := restoreTable(tx, table, tr)
+			if err != nil {
+				return nil, fmt.Errorf("恢复数据表 %s 失败: %w", table, err)
+			}
+			result.Tables[table] = count
+			logger.Info("已恢复数据表 %s: %d 行", table, count)
+		case hdr.Name == configEntry:
+			if opts.ConfigPath == "" {
+				continue
+			}
+			if err := writeFile(opts.ConfigPath, tr, 0600); err != nil {
+				return nil, fmt.Errorf("恢复配置文件失败: %w", err)
+			}
+			result.Config = true
+		case strings.HasPrefix(hdr.Name, storageDir):
+			if !opts.Storage {
+				continue
+			}
+			target, ok := storageTargetPath(manifest.StorageRoots, strings.TrimPrefix(hdr.Name, storageDir))
+			if !ok {
+				logger.Warn("跳过无法识别的存储文件: %s", hdr.Name)
+				continue
+			}
+			if err := writeFile(target, tr, 0644); err != nil {
+				return nil, fmt.Errorf("恢复存储文件 %s 失败: %w", target, err)
+			}
+			result.StorageFiles++
+		}
+	}
+
+	if len(opts.ChannelMap) > 0 {
+		if err := remapChannels(tx, opts.ChannelMap, preserved); err != nil {
+			return nil, fmt.Errorf("映射存储渠道失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交恢复事务失败: %w", err)
+	}
+	committed = true
+	return result, nil
+}
+
+// storageTargetPath 将归档中的存储文件映射回原目录，拒绝跳出目录的路径
+func storageTargetPath(roots []string, name string) (string, bool) {
+	for _, root := range roots {
+		prefix := storageArchiveName(root) + "/"
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rel := filepath.FromSlash(strings.TrimPrefix(name, prefix))
+		if rel == "" || !filepath.IsLocal(rel) {
+			return "", false
+		}
+		return filepath.Join(root, rel), true
+	}
+	return "", false
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func manifestHasChannel(m *Manifest, id string) bool {
+	for _, ch := range m.Channels {
+		if ch.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+/* ReadManifest 只读取归档中的清单，用于导入前查看导出内容与渠道 */
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的备份文件: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	return readManifestEntry(tr)
+}
+
+func readManifestEntry(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestEntry {
+		return nil, fmt.Errorf("备份文件缺少清单")
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("解析备份清单失败: %w", err)
+	}
+	return &manifest, nil
+}
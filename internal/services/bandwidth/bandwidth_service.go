@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"pixelpunk/internal/models"
+	quotaService "pixelpunk/internal/services/quota"
 	"pixelpunk/pkg/cache"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
@@ -13,12 +14,18 @@ import (
 	"gorm.io/gorm"
 )
 
+const userSettingsCachePrefix = "user_settings:"
+
+func init() {
+	cache.RegisterLocalPrefix(userSettingsCachePrefix)
+}
+
 /* BandwidthService 带宽服务 */
 type BandwidthService struct{}
 
 /* GetUserSettings 获取用户设置（支持缓存） */
 func (s *BandwidthService) GetUserSettings(userID uint) (models.UserSettings, error) {
-	cacheKey := fmt.Sprintf("user_settings:%d", userID)
+	cacheKey := fmt.Sprintf("%s%d", userSettingsCachePrefix, userID)
 
 	if cachedData, err := cache.Get(cacheKey); err == nil {
 		var settings models.UserSettings
@@ -102,6 +109,7 @@ func (s *BandwidthService) CheckBandwidthAvailable(userID uint, estimatedBytes i
 	if err != nil {
 		return false, err
 	}
+	quotaService.ApplyLimits(userID, &settings)
 
 	year, month, err := s.GetCurrentYearMonth()
 	if err != nil {
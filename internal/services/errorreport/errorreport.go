@@ -0,0 +1,214 @@
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/errreport"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	settingGroup = "error_reporting"
+
+	ProviderSentry  = "sentry"
+	ProviderWebhook = "webhook"
+
+	queueSize = 100
+	// 相同错误码与路由在该时间内只上报一次，避免故障时刷屏
+	dedupeWindow = time.Minute
+	sendTimeout  = 10 * time.Second
+)
+
+// Config 错误上报配置
+type Config struct {
+	Enabled      bool
+	Provider     string
+	SentryDSN    string
+	WebhookURL   string
+	WebhookToken string
+	MinLevel     errreport.Level
+	Environment  string
+}
+
+var (
+	release    string
+	serverName string
+
+	queue     chan *errreport.Event
+	queueOnce sync.Once
+
+	senderMu  sync.Mutex
+	senderKey string
+	sender    errreport.Sender
+
+	recentMu sync.Mutex
+	recent   = make(map[string]time.Time)
+)
+
+func init() {
+	serverName, _ = os.Hostname()
+}
+
+/* SetRelease 设置上报时附带的版本号 */
+func SetRelease(version string) {
+	release = version
+}
+
+/* GetConfig 读取当前的错误上报设置 */
+func GetConfig() Config {
+	return Config{
+		Enabled:      setting.GetBool(settingGroup, "enabled", false),
+		Provider:     setting.GetString(settingGroup, "provider", ProviderSentry),
+		SentryDSN:    setting.GetString(settingGroup, "sentry_dsn", ""),
+		WebhookURL:   setting.GetString(settingGroup, "webhook_url", ""),
+		WebhookToken: setting.GetString(settingGroup, "webhook_token", ""),
+		MinLevel:     errreport.Level(setting.GetString(settingGroup, "min_level", string(errreport.LevelError))),
+		Environment:  setting.GetString(settingGroup, "environment", "production"),
+	}
+}
+
+/* Enabled 是否开启错误上报 */
+func Enabled() bool {
+	return setting.GetBool(settingGroup, "enabled", false)
+}
+
+/* Capture 按级别阈值过滤后异步上报，队列已满时丢弃 */
+func Capture(event *errreport.Event) {
+	cfg := GetConfig()
+	if !cfg.Enabled || !event.Level.AtLeast(cfg.MinLevel) {
+		return
+	}
+	if isDuplicate(event) {
+		return
+	}
+
+	fillEvent(event, cfg)
+	queueOnce.Do(startWorker)
+	select {
+	case queue <- event:
+	default:
+		logger.Warn("错误上报队列已满，丢弃事件: requestID=%s", event.RequestID)
+	}
+}
+
+/* SendTest 使用给定配置同步发送一条测试事件 */
+func SendTest(cfg Config) error {
+	s, err := newSender(cfg)
+	if err != nil {
+		return err
+	}
+	event := &errreport.Event{
+		Level:   errreport.LevelError,
+		Message: "PixelPunk 错误上报测试",
+		Detail:  "这是一条由管理后台发送的测试事件",
+		Status:  500,
+		Method:  "POST",
+		Route:   "/api/v1/settings/error-reporting/test",
+		Tags:    map[string]string{"test": "true"},
+	}
+	fillEvent(event, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	return s.Send(ctx, event)
+}
+
+func fillEvent(event *errreport.Event, cfg Config) {
+	if event.ID == "" {
+		event.ID = errreport.NewEventID()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Environment = cfg.Environment
+	event.Release = release
+	event.ServerName = serverName
+}
+
+func isDuplicate(event *errreport.Event) bool {
+	key := fmt.Sprintf("%d|%s|%s|%s", event.Code, event.Method, event.Route, event.Message)
+	now := time.Now()
+
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	if last, ok := recent[key]; ok && now.Sub(last) < dedupeWindow {
+		return true
+	}
+	recent[key] = now
+	if len(recent) > 1000 {
+		for k, t := range recent {
+			if now.Sub(t) >= dedupeWindow {
+				delete(recent, k)
+			}
+		}
+	}
+	return false
+}
+
+func startWorker() {
+	queue = make(chan *errreport.Event, queueSize)
+	go func() {
+		for event := range queue {
+			send(event)
+		}
+	}()
+}
+
+func send(event *errreport.Event) {
+	s, err := currentSender()
+	if err != nil {
+		logger.Warn("错误上报配置无效: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := s.Send(ctx, event); err != nil {
+		// 不使用 errors.New，避免上报失败本身再次产生错误日志与上报
+		logger.Warn("错误上报失败: requestID=%s, error=%v", event.RequestID, err)
+	}
+}
+
+// currentSender 复用上报通道，配置变化时重新创建
+func currentSender() (errreport.Sender, error) {
+	cfg := GetConfig()
+	key := strings.Join([]string{cfg.Provider, cfg.SentryDSN, cfg.WebhookURL, cfg.WebhookToken}, "\x00")
+
+	senderMu.Lock()
+	defer senderMu.Unlock()
+	if sender != nil && senderKey == key {
+		return sender, nil
+	}
+	s, err := newSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sender, senderKey = s, key
+	return s, nil
+}
+
+func newSender(cfg Config) (errreport.Sender, error) {
+	switch cfg.Provider {
+	case ProviderSentry, "":
+		if cfg.SentryDSN == "" {
+			return nil, fmt.Errorf("未配置 Sentry DSN")
+		}
+		s, err := errreport.NewSentryClient(cfg.SentryDSN)
+		if err != nil {
+			return nil, fmt.Errorf("Sentry DSN 格式错误: %w", err)
+		}
+		return s, nil
+	case ProviderWebhook:
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("未配置回调地址")
+		}
+		return errreport.NewWebhookSender(cfg.WebhookURL, cfg.WebhookToken)
+	default:
+		return nil, fmt.Errorf("不支持的上报方式: %s", cfg.Provider)
+	}
+}
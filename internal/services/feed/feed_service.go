@@ -0,0 +1,189 @@
+package feed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/feed"
+	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const maxFeedItems = 200
+
+/* IsEnabled 是否开放订阅源 */
+func IsEnabled() bool {
+	return setting.GetBool("feed", "enabled", true)
+}
+
+/* AuthorFeed 作者公开文件的订阅源，不包含敏感内容 */
+func AuthorFeed(authorID uint, feedURL string) (*feed.Feed, error) {
+	var user models.User
+	if err := database.GetDB().Where("id = ? AND status = ?", authorID, common.UserStatusNormal).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeUserNotFound, "作者不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询作者失败")
+	}
+
+	files, err := publicFiles(database.GetDB().Where("user_id = ?", authorID))
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := utils.GetBaseUrl()
+	f := &feed.Feed{
+		ID:          fmt.Sprintf("%s/author/%d", baseURL, authorID),
+		Title:       fmt.Sprintf("%s - %s", user.Username, siteName()),
+		Link:        fmt.Sprintf("%s/author/%d", baseURL, authorID),
+		FeedURL:     feedURL,
+		Description: user.Bio,
+		Author:      user.Username,
+		Icon:        utils.GetSystemFileURL(user.Avatar),
+	}
+	if f.Description == "" {
+		f.Description = fmt.Sprintf("%s 公开上传的文件", user.Username)
+	}
+	fillItems(f, files, map[uint]string{user.ID: user.Username})
+	return f, nil
+}
+
+/* RecommendedFeed 全站推荐文件的订阅源 */
+func RecommendedFeed(feedURL string) (*feed.Feed, error) {
+	files, err := publicFiles(database.GetDB().Where("is_recommended = ?", true))
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, 0, len(files))
+	for _, file := range files {
+		userIDs = append(userIDs, file.UserID)
+	}
+	authors := make(map[uint]string)
+	if len(userIDs) > 0 {
+		var users []models.User
+		database.GetDB().Select("id", "username").Where("id IN ?", userIDs).Find(&users)
+		for _, u := range users {
+			authors[u.ID] = u.Username
+		}
+	}
+
+	baseURL := utils.GetBaseUrl()
+	f := &feed.Feed{
+		ID:          baseURL + "/explore",
+		Title:       siteName() + " 推荐",
+		Link:        baseURL + "/explore",
+		FeedURL:     feedURL,
+		Description: siteName() + " 精选推荐的文件",
+	}
+	fillItems(f, files, authors)
+	return f, nil
+}
+
+// publicFiles 按上传时间倒序查询公开且非敏感的文件
+func publicFiles(query *gorm.DB) ([]models.File, error) {
+	limit := setting.GetInt("feed", "item_limit", 50)
+	if limit <= 0 || limit > maxFeedItems {
+		limit = maxFeedItems
+	}
+
+	var files []models.File
+	err := query.Where("access_level = ? AND nsfw = ? AND status NOT IN ?", filesvc.AccessPublic, false, filesvc.LibraryHiddenStatuses()).
+		Order("created_at DESC").Limit(limit).Find(&files).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	return files, nil
+}
+
+// fillItems 将文件转换为订阅条目，原图作为附件，缩略图作为预览
+func fillItems(f *feed.Feed, files []models.File, authors map[uint]string) {
+	tags := fileTags(files)
+
+	f.Items = make([]feed.Item, 0, len(files))
+	for _, file := range files {
+		fullURL, thumbURL, shortURL := storage.GetFullURLs(file)
+		link := shortURL
+		if link == "" {
+			link = fullURL
+		}
+		title := file.DisplayName
+		if title == "" {
+			title = file.OriginalName
+		}
+		mime := file.Mime
+		if mime == "" {
+			mime = file.MimeType
+		}
+
+		published := time.Time(file.CreatedAt)
+		item := feed.Item{
+			ID:          link,
+			Title:       title,
+			Link:        link,
+			Description: itemDescription(&file),
+			Author:      authors[file.UserID],
+			Published:   published,
+			Updated:     time.Time(file.UpdatedAt),
+			Tags:        tags[file.ID],
+			Thumbnail:   thumbURL,
+			Enclosure:   &feed.Enclosure{URL: fullURL, Type: mime, Length: file.Size},
+		}
+		f.Items = append(f.Items, item)
+		if published.After(f.Updated) {
+			f.Updated = published
+		}
+	}
+}
+
+func itemDescription(file *models.File) string {
+	if desc := strings.TrimSpace(file.Description); desc != "" {
+		return desc
+	}
+	parts := []string{strings.ToUpper(file.Format)}
+	if file.Width > 0 && file.Height > 0 {
+		parts = append(parts, fmt.Sprintf("%d×%d", file.Width, file.Height))
+	}
+	parts = append(parts, utils.FormatBytes(file.Size))
+	return strings.Join(parts, " · ")
+}
+
+// fileTags 一次查询全部条目的标签名
+func fileTags(files []models.File) map[string][]string {
+	result := make(map[string][]string)
+	if len(files) == 0 {
+		return result
+	}
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+
+	var rows []struct {
+		FileID string
+		Name   string
+	}
+	database.GetDB().Model(&models.FileGlobalTagRelation{}).
+		Select("file_global_tag_relation.file_id, global_tag.name").
+		Joins("JOIN global_tag ON global_tag.id = file_global_tag_relation.tag_id").
+		Where("file_global_tag_relation.file_id IN ?", ids).
+		Order("file_global_tag_relation.id ASC").
+		Scan(&rows)
+	for _, row := range rows {
+		result[row.FileID] = append(result[row.FileID], row.Name)
+	}
+	return result
+}
+
+func siteName() string {
+	return setting.GetString("website_info", "site_name", "PixelPunk")
+}
@@ -0,0 +1,164 @@
+package file
+
+import (
+	"fmt"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// facetLimit 用户、格式等取值较多的维度只返回数量最多的前若干项
+const facetLimit = 20
+
+// FacetBucket 某个维度下一个取值的文件数
+type FacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// AdminFileFacets 管理员文件搜索的分面统计
+type AdminFileFacets struct {
+	Status  []FacetBucket `json:"status"`
+	Format  []FacetBucket `json:"format"`
+	Channel []FacetBucket `json:"channel"`
+	NSFW    []FacetBucket `json:"nsfw"`
+	User    []FacetBucket `json:"user"`
+}
+
+/* AdminGetFileFacets 统计当前筛选条件下各维度的文件数；每个维度忽略自身的筛选条件，便于切换取值 */
+func AdminGetFileFacets(params AdminFileSearchParams) (*AdminFileFacets, error) {
+	facets := &AdminFileFacets{}
+	var err error
+
+	p := params
+	p.Status = ""
+	if facets.Status, err = groupFacet(p, "status"); err != nil {
+		return nil, err
+	}
+
+	p = params
+	p.Format = ""
+	if facets.Format, err = groupFacet(p, "format"); err != nil {
+		return nil, err
+	}
+
+	p = params
+	p.StorageProviderID = ""
+	if facets.Channel, err = groupFacet(p, "storage_provider_id"); err != nil {
+		return nil, err
+	}
+	labelChannels(facets.Channel)
+
+	p = params
+	p.IsNSFW = nil
+	if facets.NSFW, err = nsfwFacet(p); err != nil {
+		return nil, err
+	}
+
+	p = params
+	p.UserID = 0
+	if facets.User, err = groupFacet(p, "user_id"); err != nil {
+		return nil, err
+	}
+	labelUsers(facets.User)
+
+	return facets, nil
+}
+
+func groupFacet(params AdminFileSearchParams, column string) ([]FacetBucket, error) {
+	query, ok, err := buildAdminFileQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	buckets := []FacetBucket{}
+	if !ok {
+		return buckets, nil
+	}
+
+	if err := query.Select(fmt.Sprintf("%s AS value, COUNT(*) AS count", column)).
+		Group(column).Order("count DESC").Limit(facetLimit).
+		Scan(&buckets).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计文件分面失败")
+	}
+	return buckets, nil
+}
+
+// nsfwFacet 按AI识别结果分为 nsfw、safe 和尚未识别的 unknown
+func nsfwFacet(params AdminFileSearchParams) ([]FacetBucket, error) {
+	countWhere := func(isNSFW bool) (int64, error) {
+		query, ok, err := buildAdminFileQuery(params)
+		if err != nil || !ok {
+			return 0, err
+		}
+		var count int64
+		err = query.Where("id IN (?)", aiInfoSubquery(isNSFW)).Count(&count).Error
+		return count, err
+	}
+
+	query, ok, err := buildAdminFileQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []FacetBucket{}, nil
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计文件分面失败")
+	}
+
+	nsfw, err := countWhere(true)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计文件分面失败")
+	}
+	safe, err := countWhere(false)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计文件分面失败")
+	}
+
+	return []FacetBucket{
+		{Value: "nsfw", Label: "敏感内容", Count: nsfw},
+		{Value: "safe", Label: "正常内容", Count: safe},
+		{Value: "unknown", Label: "未识别", Count: total - nsfw - safe},
+	}, nil
+}
+
+func aiInfoSubquery(isNSFW bool) *gorm.DB {
+	return database.ReadDB().Model(&models.FileAIInfo{}).Select("file_id").Where("is_nsfw = ?", isNSFW)
+}
+
+func labelChannels(buckets []FacetBucket) {
+	ids := make([]string, 0, len(buckets))
+	for _, b := range buckets {
+		ids = append(ids, b.Value)
+	}
+	var channels []models.StorageChannel
+	database.ReadDB().Select("id", "name").Where("id IN ?", ids).Find(&channels)
+	names := make(map[string]string, len(channels))
+	for _, ch := range channels {
+		names[ch.ID] = ch.Name
+	}
+	for i := range buckets {
+		buckets[i].Label = names[buckets[i].Value]
+	}
+}
+
+func labelUsers(buckets []FacetBucket) {
+	ids := make([]string, 0, len(buckets))
+	for _, b := range buckets {
+		ids = append(ids, b.Value)
+	}
+	var users []models.User
+	database.ReadDB().Select("id", "username").Where("id IN ?", ids).Find(&users)
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[fmt.Sprintf("%d", u.ID)] = u.Username
+	}
+	for i := range buckets {
+		buckets[i].Label = names[buckets[i].Value]
+	}
+}
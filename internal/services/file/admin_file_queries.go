@@ -10,25 +10,23 @@ import (
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/logger"
 	"strings"
-)
 
-/* AdminGetFileList 管理员获取文件列表（语义化命名） */
-func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse, int64, error) {
-	var total int64
-	var images []models.File
-	var responses []AdminFileDetailResponse
+	"gorm.io/gorm"
+)
 
-	query := database.DB.Model(&models.File{}).Where("status <> ?", StatusPendingDeletion)
+/* buildAdminFileQuery 按管理员筛选条件构造文件查询，ok 为 false 表示条件已确定无匹配结果 */
+func buildAdminFileQuery(params AdminFileSearchParams) (*gorm.DB, bool, error) {
+	query := database.ReadDB().Model(&models.File{}).Where("status <> ?", StatusPendingDeletion)
 
 	if len(params.Tags) > 0 {
 		var imageIDs []string
-		if err := database.DB.Model(&models.FileGlobalTagRelation{}).Where("tag_id IN ?", params.Tags).Distinct("file_id").Pluck("file_id", &imageIDs).Error; err != nil {
-			return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询标签关系失败")
+		if err := database.ReadDB().Model(&models.FileGlobalTagRelation{}).Where("tag_id IN ?", params.Tags).Distinct("file_id").Pluck("file_id", &imageIDs).Error; err != nil {
+			return nil, false, errors.Wrap(err, errors.CodeDBQueryFailed, "查询标签关系失败")
 		}
 		if len(imageIDs) > 0 {
 			query = query.Where("id IN ?", imageIDs)
 		} else {
-			return []AdminFileDetailResponse{}, 0, nil
+			return nil, false, nil
 		}
 	}
 
@@ -44,19 +42,19 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 		if len(categoryIDs) > 0 {
 			query = query.Where("category_id IN ?", categoryIDs)
 		} else {
-			return []AdminFileDetailResponse{}, 0, nil
+			return nil, false, nil
 		}
 	}
 
 	if params.Keyword != "" {
-		nameQuery := database.DB.Where("original_name LIKE ? OR display_name LIKE ?", "%"+params.Keyword+"%", "%"+params.Keyword+"%")
+		nameQuery := database.ReadDB().Where("original_name LIKE ? OR display_name LIKE ?", "%"+params.Keyword+"%", "%"+params.Keyword+"%")
 		var aiMatchingIDs []string
-		database.DB.Model(&models.FileAIInfo{}).Where("description LIKE ?", "%"+params.Keyword+"%").Pluck("file_id", &aiMatchingIDs)
+		database.ReadDB().Model(&models.FileAIInfo{}).Where("description LIKE ?", "%"+params.Keyword+"%").Pluck("file_id", &aiMatchingIDs)
 		var tagIDs []uint
-		database.DB.Model(&models.GlobalTag{}).Where("name LIKE ?", "%"+params.Keyword+"%").Pluck("id", &tagIDs)
+		database.ReadDB().Model(&models.GlobalTag{}).Where("name LIKE ?", "%"+params.Keyword+"%").Pluck("id", &tagIDs)
 		var tagMatchingIDs []string
 		if len(tagIDs) > 0 {
-			database.DB.Model(&models.FileGlobalTagRelation{}).Where("tag_id IN ?", tagIDs).Pluck("file_id", &tagMatchingIDs)
+			database.ReadDB().Model(&models.FileGlobalTagRelation{}).Where("tag_id IN ?", tagIDs).Pluck("file_id", &tagMatchingIDs)
 		}
 		var allMatchingIDs []string
 		allMatchingIDs = append(allMatchingIDs, aiMatchingIDs...)
@@ -70,7 +68,7 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 			uniqueMatchingIDs = append(uniqueMatchingIDs, id)
 		}
 		if len(uniqueMatchingIDs) > 0 {
-			query = query.Where(database.DB.Where(nameQuery).Or("id IN ?", uniqueMatchingIDs))
+			query = query.Where(database.ReadDB().Where(nameQuery).Or("id IN ?", uniqueMatchingIDs))
 		} else {
 			query = query.Where(nameQuery)
 		}
@@ -91,6 +89,15 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 	if params.StorageType != "" {
 		query = query.Where("storage_type = ?", params.StorageType)
 	}
+	if params.StorageProviderID != "" {
+		query = query.Where("storage_provider_id = ?", params.StorageProviderID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.Format != "" {
+		query = query.Where("format = ?", strings.ToLower(params.Format))
+	}
 	if params.MinWidth > 0 {
 		query = query.Where("width >= ?", params.MinWidth)
 	}
@@ -114,17 +121,17 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 			}
 		}
 		var colorMatchFileIDs []string
-		database.DB.Model(&models.FileAIInfo{}).Where("dominant_color IN ?", allColorFormats).Pluck("file_id", &colorMatchFileIDs)
+		database.ReadDB().Model(&models.FileAIInfo{}).Where("dominant_color IN ?", allColorFormats).Pluck("file_id", &colorMatchFileIDs)
 		if len(colorMatchFileIDs) > 0 {
 			query = query.Where("id IN ?", colorMatchFileIDs)
 		} else {
-			return []AdminFileDetailResponse{}, 0, nil
+			return nil, false, nil
 		}
 	}
 
 	var aiFiltered bool
 	var aiFilterFileIDs []string
-	aiQuery := database.DB.Model(&models.FileAIInfo{})
+	aiQuery := database.ReadDB().Model(&models.FileAIInfo{})
 	if params.Resolution != "" {
 		aiQuery = aiQuery.Where("resolution = ?", params.Resolution)
 	}
@@ -145,10 +152,27 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 		if len(aiFilterFileIDs) > 0 {
 			query = query.Where("id IN ?", aiFilterFileIDs)
 		} else {
-			return []AdminFileDetailResponse{}, 0, nil
+			return nil, false, nil
 		}
 	}
 
+	return query, true, nil
+}
+
+/* AdminGetFileList 管理员获取文件列表（语义化命名） */
+func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse, int64, error) {
+	var total int64
+	var images []models.File
+	var responses []AdminFileDetailResponse
+
+	query, ok, err := buildAdminFileQuery(params)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return []AdminFileDetailResponse{}, 0, nil
+	}
+
 	var countQuery = query
 	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "获取文件总数失败")
@@ -195,7 +219,7 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 	}
 	var aiInfoList []models.FileAIInfo
 	if len(imageIDs) > 0 {
-		database.DB.Where("file_id IN ?", imageIDs).Find(&aiInfoList)
+		database.ReadDB().Where("file_id IN ?", imageIDs).Find(&aiInfoList)
 	}
 	aiInfoMap := make(map[string]models.FileAIInfo)
 	for _, ai := range aiInfoList {
@@ -203,7 +227,7 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 	}
 	var statsList []models.FileStats
 	if len(imageIDs) > 0 {
-		database.DB.Where("file_id IN ?", imageIDs).Find(&statsList)
+		database.ReadDB().Where("file_id IN ?", imageIDs).Find(&statsList)
 	}
 	statsMap := make(map[string]int64)
 	for _, s := range statsList {
@@ -214,7 +238,7 @@ func AdminGetFileList(params AdminFileSearchParams) ([]AdminFileDetailResponse,
 		var userName string
 		if file.UserID > 0 {
 			var user models.User
-			if err := database.DB.Select("username").Where("id = ?", file.UserID).First(&user).Error; err == nil {
+			if err := database.ReadDB().Select("username").Where("id = ?", file.UserID).First(&user).Error; err == nil {
 				userName = user.Username
 			}
 		}
@@ -240,7 +264,7 @@ func convertToAIResponse(ai models.FileAIInfo) *AIInfoResponse {
 	}
 	if len(tagNames) == 0 {
 		var tags []models.GlobalTag
-		database.DB.Model(&models.GlobalTag{}).Joins("JOIN file_global_tag_relation ON file_global_tag_relation.tag_id = global_tag.id").Where("file_global_tag_relation.file_id = ?", ai.FileID).Find(&tags)
+		database.ReadDB().Model(&models.GlobalTag{}).Joins("JOIN file_global_tag_relation ON file_global_tag_relation.tag_id = global_tag.id").Where("file_global_tag_relation.file_id = ?", ai.FileID).Find(&tags)
 		for _, tag := range tags {
 			tagNames = append(tagNames, tag.Name)
 		}
@@ -0,0 +1,293 @@
+package file
+
+import (
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	messageService "pixelpunk/internal/services/message"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// 旧数据没有 trashed_at，以最后更新时间近似进入回收站的时间
+const trashedAtExpr = "COALESCE(trashed_at, updated_at)"
+
+// AdminTrashSearchParams 回收站查询参数
+type AdminTrashSearchParams struct {
+	Page              int
+	Size              int
+	Keyword           string
+	UserID            uint
+	StorageProviderID string
+	TrashedFrom       *time.Time
+	TrashedTo         *time.Time
+	Sort              string // newest / oldest / size
+}
+
+// AdminTrashFileResponse 回收站文件
+type AdminTrashFileResponse struct {
+	AdminFileDetailResponse
+	TrashedAt   *time.Time `json:"trashed_at"`
+	PurgeAt     *time.Time `json:"purge_at,omitempty"` // 按保留期限预计自动清理的时间
+	Reason      string     `json:"reason,omitempty"`
+	HasAppealed bool       `json:"has_appealed"`
+}
+
+/* GetTrashRetentionDays 回收站保留天数，0 表示不自动清理 */
+func GetTrashRetentionDays() int {
+	days := setting.GetInt("upload", "trash_retention_days", 30)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+func buildTrashQuery(params AdminTrashSearchParams) *gorm.DB {
+	query := database.DB.Model(&models.File{}).Where("status = ?", StatusDeleted)
+
+	if kw := strings.TrimSpace(params.Keyword); kw != "" {
+		query = query.Where("original_name LIKE ? OR display_name LIKE ? OR id = ?", "%"+kw+"%", "%"+kw+"%", kw)
+	}
+	if params.UserID > 0 {
+		query = query.Where("user_id = ?", params.UserID)
+	}
+	if params.StorageProviderID != "" {
+		query = query.Where("storage_provider_id = ?", params.StorageProviderID)
+	}
+	if params.TrashedFrom != nil {
+		query = query.Where(trashedAtExpr+" >= ?", *params.TrashedFrom)
+	}
+	if params.TrashedTo != nil {
+		query = query.Where(trashedAtExpr+" <= ?", *params.TrashedTo)
+	}
+	return query
+}
+
+/* AdminGetTrashList 管理员查看所有用户的回收站文件 */
+func AdminGetTrashList(params AdminTrashSearchParams) ([]AdminTrashFileResponse, int64, error) {
+	query := buildTrashQuery(params)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "获取回收站文件总数失败")
+	}
+
+	switch strings.ToLower(params.Sort) {
+	case "oldest":
+		query = query.Order(trashedAtExpr + " ASC")
+	case "size":
+		query = query.Order("size DESC")
+	default:
+		query = query.Order(trashedAtExpr + " DESC")
+	}
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Size <= 0 {
+		params.Size = 20
+	}
+
+	var files []models.File
+	if err := query.Offset((params.Page - 1) * params.Size).Limit(params.Size).Find(&files).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询回收站文件失败")
+	}
+	if len(files) == 0 {
+		return []AdminTrashFileResponse{}, total, nil
+	}
+
+	fileIDs := make([]string, 0, len(files))
+	userIDs := make([]uint, 0, len(files))
+	for _, f := range files {
+		fileIDs = append(fileIDs, f.ID)
+		userIDs = append(userIDs, f.UserID)
+	}
+
+	userMap := make(map[uint]string)
+	var users []models.User
+	database.DB.Select("id, username").Where("id IN ?", userIDs).Find(&users)
+	for _, u := range users {
+		userMap[u.ID] = u.Username
+	}
+
+	// 每个文件取最近一次软删除的审核记录作为删除原因
+	var logs []models.ReviewLog
+	database.DB.Where("file_id IN ? AND action = ? AND delete_type <> ?", fileIDs, "reject", "hard").
+		Order("created_at ASC").Find(&logs)
+	reasonMap := make(map[string]string)
+	for _, l := range logs {
+		reasonMap[l.FileID] = l.Reason
+	}
+
+	var appealedIDs []string
+	database.DB.Model(&models.ReviewAppeal{}).
+		Where("file_id IN ? AND status = ?", fileIDs, models.ReviewAppealStatusPending).
+		Pluck("file_id", &appealedIDs)
+	appealed := make(map[string]bool, len(appealedIDs))
+	for _, id := range appealedIDs {
+		appealed[id] = true
+	}
+
+	retention := GetTrashRetentionDays()
+	items := make([]AdminTrashFileResponse, 0, len(files))
+	for _, f := range files {
+		trashedAt := f.TrashedAt
+		if trashedAt == nil {
+			t := time.Time(f.UpdatedAt)
+			trashedAt = &t
+		}
+		item := AdminTrashFileResponse{
+			AdminFileDetailResponse: BuildAdminFileDetailResponse(f, 0, userMap[f.UserID], nil),
+			TrashedAt:               trashedAt,
+			Reason:                  reasonMap[f.ID],
+			HasAppealed:             appealed[f.ID],
+		}
+		if retention > 0 {
+			purgeAt := trashedAt.AddDate(0, 0, retention)
+			item.PurgeAt = &purgeAt
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+/* AdminRestoreTrashFiles 批量恢复回收站文件为正常状态并通知上传者 */
+func AdminRestoreTrashFiles(fileIDs []string) (*BatchOperationResult, error) {
+	files, result, err := loadTrashFiles(fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		err := database.DB.Model(&models.File{}).
+			Where("id = ? AND status = ?", f.ID, StatusDeleted).
+			Updates(map[string]interface{}{"status": "active", "trashed_at": nil}).Error
+		if err != nil {
+			result.addFailure(f.ID, "恢复文件失败")
+			continue
+		}
+		result.addSuccess(f.ID)
+		go sendTrashRestoreNotification(f)
+	}
+	return result, nil
+}
+
+/* AdminPurgeTrashFiles 批量彻底删除回收站文件，包括物理文件与关联数据 */
+func AdminPurgeTrashFiles(fileIDs []string) (*BatchOperationResult, error) {
+	files, result, err := loadTrashFiles(fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		if err := deleteFileWithCascade(&files[i], files[i].UserID); err != nil {
+			logger.Error("彻底删除回收站文件失败: fileID=%s, error=%v", files[i].ID, err)
+			result.addFailure(files[i].ID, "彻底删除失败")
+			continue
+		}
+		result.addSuccess(files[i].ID)
+	}
+	return result, nil
+}
+
+/* AdminPurgeTrashFile 立即彻底删除单个回收站文件，不等待保留期限 */
+func AdminPurgeTrashFile(fileID string) error {
+	var file models.File
+	err := database.DB.Where("id = ? AND status = ?", fileID, StatusDeleted).First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New(errors.CodeFileNotFound, "文件不存在或不在回收站中")
+		}
+		return errors.Wrap(err, errors.CodeDBQueryFailed, "查询回收站文件失败")
+	}
+
+	if err := deleteFileWithCascade(&file, file.UserID); err != nil {
+		logger.Error("彻底删除回收站文件失败: fileID=%s, error=%v", file.ID, err)
+		return errors.Wrap(err, errors.CodeFileDeleteFailed, "彻底删除失败")
+	}
+	return nil
+}
+
+/* PurgeExpiredTrashFiles 清理超过保留期限的回收站文件，申诉处理中的文件暂不清理 */
+func PurgeExpiredTrashFiles(limit int) (int, error) {
+	retention := GetTrashRetentionDays()
+	if retention == 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retention)
+
+	var files []models.File
+	err := database.DB.Where("status = ?", StatusDeleted).
+		Where(trashedAtExpr+" < ?", cutoff).
+		Where("id NOT IN (?)", database.DB.Model(&models.ReviewAppeal{}).
+			Select("file_id").Where("status = ?", models.ReviewAppealStatusPending)).
+		Limit(limit).Find(&files).Error
+	if err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询过期回收站文件失败")
+	}
+
+	purged := 0
+	for i := range files {
+		if err := deleteFileWithCascade(&files[i], files[i].UserID); err != nil {
+			logger.Error("自动清理回收站文件失败: fileID=%s, error=%v", files[i].ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// loadTrashFiles 查询处于回收站中的文件，不存在或不在回收站的记为失败
+func loadTrashFiles(fileIDs []string) ([]models.File, *BatchOperationResult, error) {
+	result := &BatchOperationResult{
+		SuccessIDs: make([]string, 0),
+		FailIDs:    make([]string, 0),
+		Errors:     make(map[string]string),
+	}
+
+	var files []models.File
+	if len(fileIDs) > 0 {
+		if err := database.DB.Where("id IN ? AND status = ?", fileIDs, StatusDeleted).Find(&files).Error; err != nil {
+			return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询回收站文件失败")
+		}
+	}
+
+	found := make(map[string]bool, len(files))
+	for _, f := range files {
+		found[f.ID] = true
+	}
+	for _, id := range fileIDs {
+		if !found[id] {
+			result.addFailure(id, "文件不存在或不在回收站中")
+		}
+	}
+	return files, result, nil
+}
+
+func (r *BatchOperationResult) addSuccess(fileID string) {
+	r.SuccessIDs = append(r.SuccessIDs, fileID)
+	r.SuccessCount++
+}
+
+func (r *BatchOperationResult) addFailure(fileID, reason string) {
+	r.FailIDs = append(r.FailIDs, fileID)
+	r.Errors[fileID] = reason
+	r.FailCount++
+}
+
+func sendTrashRestoreNotification(f models.File) {
+	variables := map[string]interface{}{
+		"file_id":      f.ID,
+		"file_name":    f.OriginalName,
+		"related_type": "file",
+		"related_id":   f.ID,
+	}
+	if err := messageService.GetMessageService().SendTemplateMessage(f.UserID, common.MessageTypeContentReviewApproved, variables); err != nil {
+		logger.Warn("发送文件恢复消息失败: userID=%d, fileID=%s, error=%v", f.UserID, f.ID, err)
+	}
+}
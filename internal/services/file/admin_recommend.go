@@ -10,6 +10,7 @@ import (
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/storage"
 	"pixelpunk/pkg/utils"
+	"pixelpunk/pkg/vector"
 	"time"
 
 	"gorm.io/gorm"
@@ -28,6 +29,7 @@ func ToggleFileRecommendStatus(fileID string) (*AdminFileDetailResponse, error)
 	if err := database.DB.Save(&file).Error; err != nil {
 		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新文件推荐状态失败")
 	}
+	go vector.SyncFileMeta(file.ID)
 	var userName string
 	if file.UserID > 0 {
 		var user models.User
@@ -44,7 +46,7 @@ func ToggleFileRecommendStatus(fileID string) (*AdminFileDetailResponse, error)
 func getRandomFileGlobal() (*AdminFileDetailResponse, error) {
 	var file models.File
 	var totalCount int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("is_recommended = ? AND access_level = ?", true, AccessPublic).
 		Where("status <> ?", StatusPendingDeletion).
 		Count(&totalCount).Error; err != nil {
@@ -60,7 +62,7 @@ func getRandomFileGlobal() (*AdminFileDetailResponse, error) {
 		}
 	}
 	offset := rand.Int63n(totalCount)
-	if err := database.DB.
+	if err := database.ReadDB().
 		Where("is_recommended = ? AND access_level = ?", true, AccessPublic).
 		Where("status <> ?", StatusPendingDeletion).
 		Offset(int(offset)).
@@ -10,6 +10,7 @@ import (
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/logger"
 	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/vector"
 
 	"strings"
 
@@ -38,6 +39,10 @@ type AdminFileSearchParams struct {
 	IsRecommended *bool    // 是否推荐内容(可选)
 	FolderID      string   // 文件夹ID
 	AccessLevel   string   // 访问级别
+
+	Status            string // 文件状态
+	Format            string // 文件格式
+	StorageProviderID string // 存储渠道ID
 }
 
 type AdminImageSearchParams = AdminFileSearchParams
@@ -102,6 +107,7 @@ func BatchSetFileRecommendation(fileIDs []string, isRecommended bool) (*BatchOpe
 	if err != nil {
 		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "批量更新推荐状态失败")
 	}
+	go vector.SyncFileMeta(fileIDs...)
 
 	for _, fileID := range fileIDs {
 		file, exists := fileMap[fileID]
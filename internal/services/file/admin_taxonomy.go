@@ -12,7 +12,7 @@ import (
 func AdminGetTagList(page, size int, userID ...uint) ([]TagWithCount, int64, error) {
 	var tags []models.GlobalTag
 	var total int64
-	query := database.DB.Model(&models.GlobalTag{})
+	query := database.ReadDB().Model(&models.GlobalTag{})
 
 	var specificUserID uint = 0
 	var onlyRecommended bool = false
@@ -23,7 +23,7 @@ func AdminGetTagList(page, size int, userID ...uint) ([]TagWithCount, int64, err
 		onlyRecommended = true
 	}
 
-	imageQuery := database.DB.Model(&models.File{})
+	imageQuery := database.ReadDB().Model(&models.File{})
 	if specificUserID > 0 {
 		imageQuery = imageQuery.Where("user_id = ?", specificUserID)
 	}
@@ -38,7 +38,7 @@ func AdminGetTagList(page, size int, userID ...uint) ([]TagWithCount, int64, err
 		}
 		if len(imageIDs) > 0 {
 			var tagIDs []uint
-			if err := database.DB.Model(&models.FileGlobalTagRelation{}).Where("file_id IN ?", imageIDs).Pluck("DISTINCT tag_id", &tagIDs).Error; err != nil {
+			if err := database.ReadDB().Model(&models.FileGlobalTagRelation{}).Where("file_id IN ?", imageIDs).Pluck("DISTINCT tag_id", &tagIDs).Error; err != nil {
 				return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件标签关系失败")
 			}
 			if len(tagIDs) > 0 {
@@ -66,7 +66,7 @@ func AdminGetTagList(page, size int, userID ...uint) ([]TagWithCount, int64, err
 	var tagsWithCount []TagWithCount
 	for _, tag := range tags {
 		var count int64
-		countQuery := database.DB.Model(&models.FileGlobalTagRelation{}).Where("tag_id = ?", tag.ID)
+		countQuery := database.ReadDB().Model(&models.FileGlobalTagRelation{}).Where("tag_id = ?", tag.ID)
 		if specificUserID > 0 || onlyRecommended {
 			countQuery = countQuery.Joins("JOIN file ON file_global_tag_relation.file_id = file.id")
 			if specificUserID > 0 {
@@ -89,7 +89,7 @@ func GetPublicTagList(page, size int, keyword string) ([]TagWithCount, int64, er
 	var tagsWithCount []TagWithCount
 	var total int64
 
-	db := database.DB
+	db := database.ReadDB()
 
 	query := db.Model(&models.GlobalTag{}).
 		Select("global_tag.id, global_tag.name, global_tag.slug, global_tag.description, global_tag.is_system, global_tag.creator_id, global_tag.sort_order, global_tag.created_at, global_tag.updated_at, COUNT(DISTINCT file_global_tag_relation.file_id) as count").
@@ -153,7 +153,7 @@ func AdminGetColorList(page, size int, userID ...uint) ([]string, int64, error)
 	if len(userID) > 1 && userID[1] == 1 {
 		onlyRecommended = true
 	}
-	query := database.DB.Model(&models.FileAIInfo{}).Select("DISTINCT dominant_color").Where("dominant_color IS NOT NULL AND dominant_color != ''")
+	query := database.ReadDB().Model(&models.FileAIInfo{}).Select("DISTINCT dominant_color").Where("dominant_color IS NOT NULL AND dominant_color != ''")
 	if specificUserID > 0 || onlyRecommended {
 		query = query.Joins("JOIN file ON file_ai_info.file_id = file.id")
 		if specificUserID > 0 {
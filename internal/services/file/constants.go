@@ -9,6 +9,7 @@ import (
 
 const (
 	StatusPendingDeletion = "pending_deletion"
+	StatusDeleted         = "deleted" // 软删除，保留在回收站中直到恢复或彻底删除
 
 	AccessPublic    = "public"
 	AccessPrivate   = "private"
@@ -26,6 +27,9 @@ var (
 	globalCancel    context.CancelFunc
 	globalCtxMutex  sync.RWMutex
 	serviceInitOnce sync.Once
+
+	// uploadTasks 进行中的上传后处理任务（AI、向量入队），关闭时等待其完成
+	uploadTasks sync.WaitGroup
 )
 
 // InitUploadService 初始化上传服务的全局context
@@ -46,6 +50,21 @@ func ShutdownUploadService() {
 	}
 }
 
+// WaitUploadTasks 等待进行中的上传后处理任务完成，ctx 到期时返回 false
+func WaitUploadTasks(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		uploadTasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // GetServiceContext 获取服务的全局context
 func GetServiceContext() context.Context {
 	globalCtxMutex.RLock()
@@ -96,3 +96,23 @@ func DeleteNSFWFile(fileID string) error {
 	logger.Warn("违规文件自动删除：用户ID=%d, 文件ID=%s, 文件名=%s", file.UserID, fileID, file.OriginalName)
 	return deleteFileWithCascade(&file, file.UserID)
 }
+
+/* PurgeUserFiles 彻底删除用户的全部文件及关联的AI信息、向量、标签与日志（注销账号时使用） */
+func PurgeUserFiles(userID uint) (int, error) {
+	deleted := 0
+	for {
+		var files []models.File
+		if err := database.DB.Where("user_id = ?", userID).Limit(200).Find(&files).Error; err != nil {
+			return deleted, errors.Wrap(err, errors.CodeDBQueryFailed, "查询用户文件失败")
+		}
+		if len(files) == 0 {
+			return deleted, nil
+		}
+		for i := range files {
+			if err := deleteFileWithCascade(&files[i], userID); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/logger"
 	"pixelpunk/pkg/utils"
+	"pixelpunk/pkg/vector"
 	"strings"
 	"time"
 
@@ -43,6 +44,7 @@ func UpdateFile(userID uint, fileID, name, folderID, accessLevel string) (*FileD
 	if err := database.DB.Save(&file).Error; err != nil {
 		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "保存文件信息失败")
 	}
+	go vector.SyncFileMeta(file.ID)
 
 	var stats models.FileStats
 	if err := database.DB.Where("file_id = ?", fileID).First(&stats).Error; err != nil {
@@ -77,6 +79,7 @@ func ToggleFileAccessLevel(userID uint, fileID string) (*FileDetailResponse, err
 	if err := database.DB.Save(&file).Error; err != nil {
 		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新文件失败")
 	}
+	go vector.SyncFileMeta(file.ID)
 	var stats models.FileStats
 	if err := database.DB.Where("file_id = ?", fileID).First(&stats).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -112,7 +115,7 @@ func DeleteFile(userID uint, fileID string) error {
 /* GetTotalFileCount 获取文件总数（新语义） */
 func GetTotalFileCount() (int64, error) {
 	var count int64
-	err := database.DB.Model(&models.File{}).Where("status IS NULL OR status <> ?", StatusPendingDeletion).Count(&count).Error
+	err := database.ReadDB().Model(&models.File{}).Where("status IS NULL OR status <> ?", StatusPendingDeletion).Count(&count).Error
 	if err != nil {
 		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "获取文件总数失败")
 	}
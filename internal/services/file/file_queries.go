@@ -18,7 +18,7 @@ func GetFileList(userID uint, folderID string, page, size int, sort string, acce
 	var images []models.File
 	var responses []FileDetailResponse
 
-	query := database.DB.Where("user_id = ?", userID).Where("status <> ?", StatusPendingDeletion).Joins("LEFT JOIN file_ai_info ON file_ai_info.file_id = file.id")
+	query := database.ReadDB().Where("user_id = ?", userID).Where("status <> ?", StatusPendingDeletion).Joins("LEFT JOIN file_ai_info ON file_ai_info.file_id = file.id")
 	if folderID != "" {
 		query = query.Where("folder_id = ?", folderID)
 	}
@@ -31,13 +31,13 @@ func GetFileList(userID uint, folderID string, page, size int, sort string, acce
 
 	if len(tags) > 0 {
 		var tagIDs []uint
-		if err := database.DB.Model(&models.GlobalTag{}).Where("name IN ?", tags).Pluck("id", &tagIDs).Error; err != nil {
+		if err := database.ReadDB().Model(&models.GlobalTag{}).Where("name IN ?", tags).Pluck("id", &tagIDs).Error; err != nil {
 			return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询标签失败")
 		}
 		if len(tagIDs) == 0 {
 			return []FileDetailResponse{}, 0, nil
 		}
-		sub := database.DB.Model(&models.FileGlobalTagRelation{}).Select("DISTINCT file_id").Where("tag_id IN ?", tagIDs)
+		sub := database.ReadDB().Model(&models.FileGlobalTagRelation{}).Select("DISTINCT file_id").Where("tag_id IN ?", tagIDs)
 		if len(tags) > 1 {
 			sub = sub.Group("file_id").Having("COUNT(DISTINCT tag_id) = ?", len(tagIDs))
 		}
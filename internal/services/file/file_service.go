@@ -191,6 +191,10 @@ func deleteFileWithCascade(file *models.File, userID uint) error {
 		return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除文件向量数据失败")
 	}
 
+	if err := database.DB.Where("file_id = ? OR duplicate_file_id = ?", fileID, fileID).Delete(&models.VectorDuplicateCandidate{}).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除近似重复记录失败")
+	}
+
 	var userStats models.UserUsageStats
 	if err := database.DB.Where("user_id = ?", userID).First(&userStats).Error; err == nil {
 		updates := make(map[string]interface{})
@@ -0,0 +1,146 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 回收站与待删除的文件不出现在文件库中
+var libraryHiddenStatuses = []string{StatusPendingDeletion, StatusDeleted}
+
+/* LibraryHiddenStatuses 以文件库形式访问（S3、WebDAV）时不可见的文件状态 */
+func LibraryHiddenStatuses() []string {
+	return libraryHiddenStatuses
+}
+
+/* ReplaceLibraryFile 将内容上传到指定文件夹，同名文件视为覆盖，上传成功后删除旧文件；key 为空时按用户身份上传 */
+func ReplaceLibraryFile(c *gin.Context, userID uint, key *models.APIKey, folderID, name string, content *os.File, contentType string) (*ExternalAPIFileResponse, error) {
+	var previousIDs []string
+	database.DB.Model(&models.File{}).
+		Where("user_id = ? AND folder_id = ? AND original_name = ? AND status NOT IN ?", userID, folderID, name, libraryHiddenStatuses).
+		Pluck("id", &previousIDs)
+
+	form, err := buildUploadForm(content, name, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer form.RemoveAll()
+
+	var uploaded *ExternalAPIFileResponse
+	if key != nil {
+		result, err := processSingleFileUpload(c, key, folderID, "", false, form.File["file"][0])
+		if err != nil {
+			return nil, err
+		}
+		uploaded = result.UploadedSingle
+	} else {
+		uploaded, err = UploadFileForAPI(c, userID, form.File["file"][0], folderID, "", false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range previousIDs {
+		if err := DeleteFile(userID, id); err != nil {
+			logger.Warn("覆盖上传时删除旧文件失败: fileID=%s, error=%v", id, err)
+		}
+	}
+	return uploaded, nil
+}
+
+/* UploadFileContent 在请求上下文之外按用户身份上传内容，供机器人等后台入口使用 */
+func UploadFileContent(userID uint, folderID, name string, content *os.File, contentType string) (*ExternalAPIFileResponse, error) {
+	form, err := buildUploadForm(content, name, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer form.RemoveAll()
+
+	return UploadFileForAPI(nil, userID, form.File["file"][0], folderID, "", false)
+}
+
+/* MaxLibraryFileSize 单个文件的大小上限，取系统单文件上限与密钥单文件限制中较小者 */
+func MaxLibraryFileSize(key *models.APIKey) int64 {
+	maxSizeMB, err := setting.GetNumberValue("max_file_size", 100.0)
+	if err != nil || maxSizeMB <= 0 {
+		maxSizeMB = 100.0
+	}
+	maxSize := int64(maxSizeMB * 1024 * 1024)
+	if key != nil && key.SingleFileLimit > 0 && key.SingleFileLimit < maxSize {
+		maxSize = key.SingleFileLimit
+	}
+	return maxSize
+}
+
+/* OpenFileContent 读取文件原始内容，适用于所有存储渠道 */
+func OpenFileContent(ctx context.Context, file models.File) (io.ReadCloser, error) {
+	result, isLocal, isProxy, err := ServeFile(file, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case isLocal:
+		f, err := os.Open(result.(string))
+		if err != nil {
+			return nil, errors.New(errors.CodeFileNotFound, "文件不存在")
+		}
+		return f, nil
+	case isProxy:
+		return result.(*ProxyResponse).Content, nil
+	default:
+		reader, err := storage.NewGlobalStorage().ReadFile(ctx, file.StorageProviderID, file.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeFileNotFound, "读取文件失败")
+		}
+		return reader, nil
+	}
+}
+
+// buildUploadForm 将内容包装成 multipart 文件，复用现有的上传流程
+func buildUploadForm(content *os.File, name, contentType string) (*multipart.Form, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, strings.ReplaceAll(name, `"`, "")))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, content)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(pr, writer.Boundary()).ReadForm(32 << 20)
+	pr.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "解析上传内容失败")
+	}
+	if len(form.File["file"]) == 0 {
+		form.RemoveAll()
+		return nil, errors.New(errors.CodeInternal, "上传内容为空")
+	}
+	return form, nil
+}
@@ -3,8 +3,10 @@ package file
 import (
 	"fmt"
 	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/folder"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/vector"
 	"strings"
 
 	"gorm.io/gorm"
@@ -56,6 +58,12 @@ func MoveFiles(userID uint, fileIDs []string, targetFolderID string) error {
 		return errors.New(errors.CodeInvalidParameter, "没有可移动的文件或无权限")
 	}
 
+	// 移入关闭AI处理的文件夹后，尚未完成的AI打标与向量化不再进行
+	if !folder.IsAIProcessingAllowed(userID, targetFolderID) {
+		folder.MarkAIProcessingSkipped(fileIDs)
+	}
+
+	go vector.SyncFileMeta(fileIDs...)
 	return nil
 }
 
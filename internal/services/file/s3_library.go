@@ -0,0 +1,200 @@
+package file
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/folder"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LibraryObject 以对象存储形式呈现的文件，Key 为相对密钥根目录的文件夹路径加原始文件名
+type LibraryObject struct {
+	Key          string
+	FileID       string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	ContentType  string
+}
+
+/* ListLibraryObjects 列出密钥可见的文件，同一 Key 只保留最新上传的文件，结果按 Key 排序 */
+func ListLibraryObjects(key *models.APIKey, prefix string) ([]LibraryObject, error) {
+	folderPaths, err := libraryFolderPaths(key)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Model(&models.File{}).
+		Select("id", "folder_id", "original_name", "size", "md5_hash", "mime", "created_at").
+		Where("user_id = ? AND status NOT IN ?", key.UserID, libraryHiddenStatuses)
+	if key.IsFolderRestricted() {
+		folderIDs := make([]string, 0, len(folderPaths))
+		for id := range folderPaths {
+			folderIDs = append(folderIDs, id)
+		}
+		query = query.Where("folder_id IN ?", folderIDs)
+	}
+
+	var files []models.File
+	if err := query.Order("created_at DESC").Find(&files).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+
+	seen := make(map[string]bool, len(files))
+	objects := make([]LibraryObject, 0, len(files))
+	for _, f := range files {
+		dir, ok := folderPaths[f.FolderID]
+		if !ok {
+			continue
+		}
+		objectKey := path.Join(dir, f.OriginalName)
+		if seen[objectKey] || !strings.HasPrefix(objectKey, prefix) {
+			continue
+		}
+		seen[objectKey] = true
+		objects = append(objects, toLibraryObject(objectKey, &f))
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+/* FindLibraryObject 按 Key 查找文件，同名时返回最新上传的一个 */
+func FindLibraryObject(key *models.APIKey, objectKey string) (*models.File, *LibraryObject, error) {
+	dir, name := splitObjectKey(objectKey)
+	if name == "" {
+		return nil, nil, errors.New(errors.CodeFileNotFound, "文件不存在")
+	}
+
+	folderID, err := resolveLibraryFolder(key, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var file models.File
+	if err := database.DB.Where("user_id = ? AND folder_id = ? AND original_name = ? AND status NOT IN ?", key.UserID, folderID, name, libraryHiddenStatuses).
+		Order("created_at DESC").First(&file).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeFileNotFound, "文件不存在")
+		}
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+
+	object := toLibraryObject(path.Join(dir, name), &file)
+	return &file, &object, nil
+}
+
+/* PutLibraryObject 将内容上传到 Key 对应的目录，目录不存在时自动创建；同名文件视为覆盖 */
+func PutLibraryObject(c *gin.Context, key *models.APIKey, objectKey string, content *os.File, contentType string) (*ExternalAPIFileResponse, error) {
+	dir, name := splitObjectKey(objectKey)
+	if name == "" {
+		return nil, errors.New(errors.CodeInvalidParameter, "对象名称不能为空")
+	}
+
+	folderID, err := folder.CreateFolderByPathUnder(key.UserID, libraryRoot(key), dir)
+	if err != nil {
+		return nil, err
+	}
+	return ReplaceLibraryFile(c, key.UserID, key, folderID, name, content, contentType)
+}
+
+/* CreateLibraryFolder 创建以 / 结尾的目录占位对象对应的文件夹 */
+func CreateLibraryFolder(key *models.APIKey, dir string) error {
+	_, err := folder.CreateFolderByPathUnder(key.UserID, libraryRoot(key), dir)
+	return err
+}
+
+// libraryRoot 限制目录的密钥以限定目录为根，其余以用户根目录为根
+func libraryRoot(key *models.APIKey) string {
+	if key.IsFolderRestricted() {
+		return key.FolderID
+	}
+	return ""
+}
+
+// libraryFolderPaths 计算根目录下每个文件夹相对根目录的路径，根目录自身为空串
+func libraryFolderPaths(key *models.APIKey) (map[string]string, error) {
+	var folders []models.Folder
+	if err := database.DB.Select("id", "parent_id", "name").Where("user_id = ?", key.UserID).Find(&folders).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+	}
+
+	byID := make(map[string]models.Folder, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+
+	root := libraryRoot(key)
+	paths := map[string]string{root: ""}
+	var resolve func(id string, depth int) (string, bool)
+	resolve = func(id string, depth int) (string, bool) {
+		if p, ok := paths[id]; ok {
+			return p, true
+		}
+		f, ok := byID[id]
+		// 超出根目录或数据中存在环时不可见
+		if !ok || depth > 64 {
+			return "", false
+		}
+		parent, ok := resolve(f.ParentID, depth+1)
+		if !ok {
+			return "", false
+		}
+		p := path.Join(parent, f.Name)
+		paths[id] = p
+		return p, true
+	}
+	for id := range byID {
+		resolve(id, 0)
+	}
+	return paths, nil
+}
+
+// resolveLibraryFolder 按路径逐级查找文件夹，不会创建
+func resolveLibraryFolder(key *models.APIKey, dir string) (string, error) {
+	current := libraryRoot(key)
+	for _, name := range strings.Split(dir, "/") {
+		if name == "" {
+			continue
+		}
+		var f models.Folder
+		if err := database.DB.Select("id").Where("user_id = ? AND parent_id = ? AND name = ?", key.UserID, current, name).First(&f).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return "", errors.New(errors.CodeFileNotFound, "文件不存在")
+			}
+			return "", errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+		}
+		current = f.ID
+	}
+	return current, nil
+}
+
+func splitObjectKey(objectKey string) (string, string) {
+	objectKey = strings.Trim(objectKey, "/")
+	dir, name := path.Split(objectKey)
+	return strings.Trim(dir, "/"), name
+}
+
+func toLibraryObject(objectKey string, f *models.File) LibraryObject {
+	etag := f.MD5Hash
+	if etag == "" {
+		etag = f.ID
+	}
+	return LibraryObject{
+		Key:          objectKey,
+		FileID:       f.ID,
+		Size:         f.Size,
+		ETag:         `"` + etag + `"`,
+		LastModified: time.Time(f.CreatedAt),
+		ContentType:  f.Mime,
+	}
+}
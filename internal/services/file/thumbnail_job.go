@@ -0,0 +1,85 @@
+package file
+
+import (
+	"context"
+	"time"
+
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	newstorage "pixelpunk/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	qqueue.Define(qqueue.JobSpec{
+		Kind:    qqueue.KindThumbnail,
+		Title:   "缩略图重新生成",
+		Handler: regenerateThumbnail,
+		Policy:  thumbnailRetryPolicy,
+	})
+}
+
+// thumbnailRetryPolicy 缩略图重新生成的重试策略，在设置分组 jobs 中按 thumbnail_ 前缀调整
+func thumbnailRetryPolicy() qqueue.RetryPolicy {
+	return qqueue.RetryPolicy{
+		MaxAttempts: setting.GetInt("jobs", "thumbnail_max_attempts", 5),
+		BaseDelay:   time.Duration(setting.GetInt("jobs", "thumbnail_retry_base_seconds", 60)) * time.Second,
+		MaxDelay:    time.Duration(setting.GetInt("jobs", "thumbnail_retry_max_seconds", 3600)) * time.Second,
+	}.Normalize()
+}
+
+// scheduleThumbnailRetry 上传时缩略图生成失败，按重试策略的首次延迟安排重新生成
+func scheduleThumbnailRetry(fileID string) {
+	runAt := time.Now().Add(thumbnailRetryPolicy().BaseDelay)
+	if err := qqueue.Schedule(qqueue.KindThumbnail, fileID, runAt); err != nil {
+		logger.Warn("安排缩略图重新生成失败: fileID=%s, error=%v", fileID, err)
+	}
+}
+
+// regenerateThumbnail 基于已保存的原图重新生成缩略图，成功后更新文件记录并清除失败标记；文件已删除或缩略图已恢复时直接完成
+func regenerateThumbnail(fileID string) error {
+	var file models.File
+	if err := database.DB.Where("id = ?", fileID).Take(&file).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if !file.ThumbnailGenerationFailed {
+		return nil
+	}
+
+	storageSvc, err := GetStorageServiceInstance()
+	if err != nil {
+		return err
+	}
+	opts := createCompressOptions()
+	result, err := storageSvc.RegenerateThumbnail(context.Background(), file.StorageProviderID, file.LocalFilePath, &newstorage.UploadRequest{
+		UserID:       file.UserID,
+		FileName:     file.FileName,
+		Quality:      opts.Quality,
+		ThumbWidth:   opts.MaxWidth,
+		ThumbHeight:  opts.MaxHeight,
+		ThumbQuality: opts.Quality,
+	})
+	if err != nil {
+		reason := err.Error()
+		if len(reason) > 255 {
+			reason = reason[:255]
+		}
+		_ = database.DB.Model(&models.File{}).Where("id = ?", fileID).Update("thumbnail_failure_reason", reason).Error
+		return err
+	}
+
+	return database.DB.Model(&models.File{}).Where("id = ?", fileID).Updates(map[string]interface{}{
+		"local_thumb_path":            result.ThumbnailPath,
+		"thumb_url":                   result.ThumbnailURL,
+		"remote_thumb_url":            result.RemoteThumbURL,
+		"thumbnail_generation_failed": false,
+		"thumbnail_failure_reason":    "",
+	}).Error
+}
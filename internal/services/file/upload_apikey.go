@@ -57,6 +57,19 @@ func UploadFileWithAPIKey(c *gin.Context, key *models.APIKey, folderID, filePath
 }
 
 func determineTargetFolder(key *models.APIKey, folderID, filePath string) (string, error) {
+	if key.IsFolderRestricted() {
+		// 限制目录的密钥，路径在限定目录下创建，指定目录必须位于限定目录内
+		if filePath != "" {
+			return folder.CreateFolderByPathUnder(key.UserID, key.FolderID, filePath)
+		}
+		if folderID != "" && folderID != "null" {
+			if err := apikey.CheckFolderAccess(key, folderID); err != nil {
+				return "", err
+			}
+			return folderID, nil
+		}
+		return key.FolderID, nil
+	}
 	if filePath != "" {
 		return folder.CreateFolderByPath(key.UserID, filePath)
 	}
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"context"
 	"mime/multipart"
 	"net"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"pixelpunk/internal/services/stats"
 	"pixelpunk/pkg/common"
 	pkgStorage "pixelpunk/pkg/storage"
+	"pixelpunk/pkg/tracing"
 	"strings"
 	"time"
 
@@ -127,6 +129,8 @@ type UploadContext struct {
 	GuestIP          string // 游客IP地址
 	GuestUserAgent   string // 游客User-Agent
 
+	InitialStatus string // 文件创建时的状态，为空时为 active
+
 	WatermarkEnabled       bool        // 是否启用水印
 	WatermarkConfig        string      // 水印配置JSON字符串
 	WatermarkWrapper       interface{} // 水印处理后的文件包装器（内部使用）
@@ -136,6 +140,38 @@ type UploadContext struct {
 
 	EXIFData  *models.FileEXIF // 提取的 EXIF 元数据
 	FileModel *models.File     // 文件模型（用于后续操作）
+
+	traceCtx context.Context // 链路追踪上下文，当前阶段的存储与数据库操作挂在其下
+}
+
+// traceContext 获取链路追踪上下文，不继承请求的取消，避免客户端断开后上传收尾被中断
+func (ctx *UploadContext) traceContext() context.Context {
+	if ctx.traceCtx == nil {
+		if ctx.Context != nil && ctx.Context.Request != nil {
+			ctx.traceCtx = tracing.Detach(ctx.Context.Request.Context())
+		} else {
+			ctx.traceCtx = context.Background()
+		}
+	}
+	return ctx.traceCtx
+}
+
+// traceStep 在子 Span 中执行一个上传阶段
+func (ctx *UploadContext) traceStep(name string, fn func() error) error {
+	parent := ctx.traceContext()
+	spanCtx, span := tracing.Start(parent, name, tracing.WithAttributes(
+		tracing.Attr("upload.user_id", ctx.UserID),
+		tracing.Attr("upload.file_size", ctx.FileSize),
+	))
+	ctx.traceCtx = spanCtx
+	defer func() {
+		ctx.traceCtx = parent
+		span.End()
+	}()
+
+	err := fn()
+	span.RecordError(err)
+	return err
 }
 
 /* CreateUploadContext 创建一个新的上传上下文 */
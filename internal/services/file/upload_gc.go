@@ -0,0 +1,258 @@
+package file
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"pixelpunk/internal/metrics"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	// uploadGCBatch 每批处理的会话数量
+	uploadGCBatch = 200
+	// orphanChunkDirGrace 临时目录没有对应的进行中会话且超过该时长未修改时视为残留
+	orphanChunkDirGrace = time.Hour
+)
+
+// 进行中与已结束的上传会话状态，cleaned 为旧版本清理任务写入的状态
+var (
+	activeUploadStatuses   = []string{"pending", "uploading"}
+	finishedUploadStatuses = []string{"completed", "failed", "expired", "cleaned"}
+)
+
+// UploadGCResult 上传垃圾回收结果
+type UploadGCResult struct {
+	ExpiredSessions int   `json:"expired_sessions"` // 标记为过期的会话
+	DeletedSessions int   `json:"deleted_sessions"` // 超过保留期限删除的会话记录
+	DeletedChunks   int64 `json:"deleted_chunks"`   // 删除的分片记录
+	OrphanDirs      int   `json:"orphan_dirs"`      // 删除的残留临时目录
+	ReclaimedBytes  int64 `json:"reclaimed_bytes"`  // 释放的临时文件字节数
+}
+
+func init() {
+	metrics.SetUploadSessionProvider(uploadSessionGauges)
+}
+
+func chunkTempRoot() string {
+	return filepath.Join("temp", "chunks")
+}
+
+/* CollectUploadGarbage 回收分片上传产生的垃圾：过期会话及已结束会话的分片记录与临时文件、超过保留天数的会话记录、没有进行中会话的临时目录 */
+func CollectUploadGarbage() (*UploadGCResult, error) {
+	result := &UploadGCResult{}
+
+	if err := expireStaleSessions(result); err != nil {
+		return result, err
+	}
+	if err := purgeFinishedSessions(result); err != nil {
+		return result, err
+	}
+	removeOrphanChunkDirs(result)
+
+	metrics.AddUploadGC(result.ExpiredSessions, result.ReclaimedBytes)
+	return result, nil
+}
+
+// expireStaleSessions 超过过期时间仍未完成的会话标记为 expired，释放分片记录与临时文件
+func expireStaleSessions(result *UploadGCResult) error {
+	for {
+		var sessionIDs []string
+		if err := database.DB.Model(&models.UploadSession{}).
+			Where("status IN ? AND expires_at < ?", activeUploadStatuses, time.Now()).
+			Limit(uploadGCBatch).Pluck("session_id", &sessionIDs).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBQueryFailed, "查询过期上传会话失败")
+		}
+		if len(sessionIDs) == 0 {
+			return nil
+		}
+
+		chunks, err := deleteSessionChunks(sessionIDs)
+		if err != nil {
+			return err
+		}
+		result.DeletedChunks += chunks
+
+		if err := database.DB.Model(&models.UploadSession{}).
+			Where("session_id IN ? AND status IN ?", sessionIDs, activeUploadStatuses).
+			Update("status", "expired").Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新上传会话状态失败")
+		}
+		result.ExpiredSessions += len(sessionIDs)
+
+		for _, id := range sessionIDs {
+			result.ReclaimedBytes += removeChunkDir(id)
+		}
+		if len(sessionIDs) < uploadGCBatch {
+			return nil
+		}
+	}
+}
+
+// purgeFinishedSessions 删除已结束会话残留的分片记录，超过保留天数的会话记录一并删除
+func purgeFinishedSessions(result *UploadGCResult) error {
+	// 完成与取消时只异步删除临时目录，分片记录一直保留
+	for {
+		var sessionIDs []string
+		if err := database.DB.Model(&models.UploadChunk{}).
+			Distinct("session_id").
+			Where("session_id IN (?)", database.DB.Model(&models.UploadSession{}).
+				Select("session_id").Where("status IN ?", finishedUploadStatuses)).
+			Limit(uploadGCBatch).Pluck("session_id", &sessionIDs).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBQueryFailed, "查询已结束会话的分片记录失败")
+		}
+		if len(sessionIDs) == 0 {
+			break
+		}
+
+		chunks, err := deleteSessionChunks(sessionIDs)
+		if err != nil {
+			return err
+		}
+		result.DeletedChunks += chunks
+		for _, id := range sessionIDs {
+			result.ReclaimedBytes += removeChunkDir(id)
+		}
+		if len(sessionIDs) < uploadGCBatch {
+			break
+		}
+	}
+
+	days := setting.GetInt("upload", "upload_session_retention_days", 7)
+	if days <= 0 {
+		return nil
+	}
+	res := database.DB.
+		Where("status IN ? AND updated_at < ?", finishedUploadStatuses, time.Now().AddDate(0, 0, -days)).
+		Delete(&models.UploadSession{})
+	if res.Error != nil {
+		return errors.Wrap(res.Error, errors.CodeDBDeleteFailed, "删除上传会话记录失败")
+	}
+	result.DeletedSessions = int(res.RowsAffected)
+	return nil
+}
+
+func deleteSessionChunks(sessionIDs []string) (int64, error) {
+	res := database.DB.Where("session_id IN ?", sessionIDs).Delete(&models.UploadChunk{})
+	if res.Error != nil {
+		return 0, errors.Wrap(res.Error, errors.CodeDBDeleteFailed, "删除分片记录失败")
+	}
+	return res.RowsAffected, nil
+}
+
+// removeOrphanChunkDirs 删除没有进行中会话的临时目录，如进程在合并或取消后退出时遗留的目录
+func removeOrphanChunkDirs(result *UploadGCResult) {
+	entries, err := os.ReadDir(chunkTempRoot())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("读取分片临时目录失败: %v", err)
+		}
+		return
+	}
+
+	cutoff := time.Now().Add(-orphanChunkDirGrace)
+	candidates := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		candidates = append(candidates, entry.Name())
+	}
+
+	for start := 0; start < len(candidates); start += uploadGCBatch {
+		end := start + uploadGCBatch
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		batch := candidates[start:end]
+
+		var active []string
+		if err := database.DB.Model(&models.UploadSession{}).
+			Where("session_id IN ? AND status IN ?", batch, activeUploadStatuses).
+			Pluck("session_id", &active).Error; err != nil {
+			logger.Warn("查询进行中的上传会话失败: %v", err)
+			return
+		}
+		activeSet := make(map[string]bool, len(active))
+		for _, id := range active {
+			activeSet[id] = true
+		}
+
+		for _, id := range batch {
+			if activeSet[id] {
+				continue
+			}
+			result.ReclaimedBytes += removeChunkDir(id)
+			result.OrphanDirs++
+		}
+	}
+}
+
+// removeChunkDir 删除会话的临时目录，返回释放的字节数
+func removeChunkDir(sessionID string) int64 {
+	dir := filepath.Join(chunkTempRoot(), sessionID)
+	size := dirSize(dir)
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Error("清理临时文件失败: session_id=%s, error=%v", sessionID, err)
+		return 0
+	}
+	return size
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// UploadSessionStats 未完成的上传会话统计
+type UploadSessionStats struct {
+	Active int64 `json:"active"` // 进行中且未过期
+	Stale  int64 `json:"stale"`  // 已过期、等待回收
+}
+
+/* GetUploadSessionStats 统计未完成的上传会话 */
+func GetUploadSessionStats() (*UploadSessionStats, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+	var unfinished, stale int64
+	if err := db.Model(&models.UploadSession{}).Where("status IN ?", activeUploadStatuses).Count(&unfinished).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计上传会话失败")
+	}
+	if err := db.Model(&models.UploadSession{}).
+		Where("status IN ? AND expires_at < ?", activeUploadStatuses, time.Now()).Count(&stale).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计过期上传会话失败")
+	}
+	return &UploadSessionStats{Active: unfinished - stale, Stale: stale}, nil
+}
+
+func uploadSessionGauges() map[string]int64 {
+	stats, err := GetUploadSessionStats()
+	if err != nil {
+		return nil
+	}
+	return map[string]int64{
+		"active": stats.Active,
+		"stale":  stats.Stale,
+	}
+}
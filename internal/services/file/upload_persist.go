@@ -83,6 +83,7 @@ func createFileModel(ctx *UploadContext) *models.File {
 		OriginalFileID:            ctx.OriginalFileID,
 		StorageProviderID:         ctx.StorageChannel.ID,
 		StorageType:               ctx.StorageChannel.Type,
+		Status:                    ctx.InitialStatus,
 		AITaggingStatus:           "none",
 		AITaggingTries:            0,
 		AITaggingDuration:         0,
@@ -7,8 +7,10 @@ import (
 	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/activity"
 	"pixelpunk/internal/services/ai"
+	"pixelpunk/internal/services/folder"
 	messageService "pixelpunk/internal/services/message"
 	"pixelpunk/internal/services/stats"
+	userGroupService "pixelpunk/internal/services/user_group"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/errors"
@@ -30,6 +32,20 @@ func UploadFile(c *gin.Context, userID uint, file *multipart.FileHeader, folderI
 
 /* UploadFileWithDuration 上传单张文件（支持存储时长） */
 func UploadFileWithDuration(c *gin.Context, userID uint, file *multipart.FileHeader, folderID, accessLevel string, optimize bool, storageDuration string) (*FileDetailResponse, error) {
+	ctx := CreateUploadContextWithDuration(c, userID, file, folderID, accessLevel, optimize, storageDuration)
+	return uploadWithContext(ctx)
+}
+
+/* UploadFileWithStatus 上传单张文件并以指定状态创建记录，如收集型分享中待分享者审核的访客上传；
+ * 状态为 common.FileStatusShareHold 时不触发AI与向量处理，审核通过后由 ReleaseShareHold 补做 */
+func UploadFileWithStatus(c *gin.Context, userID uint, file *multipart.FileHeader, folderID, accessLevel, status string) (*FileDetailResponse, error) {
+	ctx := CreateUploadContextWithDuration(c, userID, file, folderID, accessLevel, false, "")
+	ctx.InitialStatus = status
+	return uploadWithContext(ctx)
+}
+
+func uploadWithContext(ctx *UploadContext) (*FileDetailResponse, error) {
+	userID, file := ctx.UserID, ctx.File
 	available, err := stats.CheckUserStorageAvailable(userID, file.Size)
 	if err != nil {
 		logger.Error("检查用户存储空间失败: %v", err)
@@ -45,8 +61,6 @@ func UploadFileWithDuration(c *gin.Context, userID uint, file *multipart.FileHea
 		return nil, errors.New(errors.CodeUploadLimitExceeded, "已达到每日上传限制")
 	}
 
-	ctx := CreateUploadContextWithDuration(c, userID, file, folderID, accessLevel, optimize, storageDuration)
-
 	if err := validateUploadRequest(ctx); err != nil {
 		return nil, err
 	}
@@ -73,21 +87,23 @@ func completeFileUpload(ctx *UploadContext) (*FileDetailResponse, error) {
 }
 
 func validateUploadRequest(ctx *UploadContext) error {
-	if err := validateUploadInput(ctx); err != nil {
-		return err
-	}
-	return prepareUploadEnvironment(ctx)
+	return ctx.traceStep("upload.validate", func() error {
+		if err := validateUploadInput(ctx); err != nil {
+			return err
+		}
+		return prepareUploadEnvironment(ctx)
+	})
 }
 
 func processFileAndUpload(ctx *UploadContext) error {
-	if err := processFile(ctx); err != nil {
+	if err := ctx.traceStep("upload.process", func() error { return processFile(ctx) }); err != nil {
 		return err
 	}
-	return executeUpload(ctx)
+	return ctx.traceStep("upload.store", func() error { return executeUpload(ctx) })
 }
 
 func saveFileRecordAndStats(ctx *UploadContext) error {
-	if err := saveFileData(ctx); err != nil {
+	if err := ctx.traceStep("upload.save", func() error { return saveFileData(ctx) }); err != nil {
 		return err
 	}
 	updateStatisticsAsync(ctx)
@@ -110,7 +126,7 @@ func uploadNewFile(ctx *UploadContext) error {
 
 	uploadReq := convertToNewStorageRequest(ctx)
 
-	uploadResult, err := storageService.Upload(context.Background(), uploadReq)
+	uploadResult, err := storageService.Upload(ctx.traceContext(), uploadReq)
 	if err != nil {
 		logger.Error("新存储服务上传失败: %v", err)
 		return errors.Wrap(err, errors.CodeFileUploadFailed, "上传文件失败")
@@ -151,7 +167,7 @@ func reuseExistingFile(ctx *UploadContext) error {
 func saveFileData(ctx *UploadContext) error {
 	file := createFileModel(ctx)
 
-	err := database.DB.Transaction(func(tx *gorm.DB) error {
+	err := database.DB.WithContext(ctx.traceContext()).Transaction(func(tx *gorm.DB) error {
 		ctx.Tx = tx
 
 		if err := saveFileRecord(tx, file); err != nil {
@@ -179,8 +195,9 @@ func saveFileData(ctx *UploadContext) error {
 	ctx.SavedFile = file
 	ctx.FileModel = file
 
-	// 检查缩略图生成是否失败，如果失败则发送通知
+	// 检查缩略图生成是否失败，如果失败则安排重新生成并发送通知
 	if file.ThumbnailGenerationFailed {
+		scheduleThumbnailRetry(file.ID)
 		userID := ctx.UserID
 		go func() {
 			msgService := messageService.GetMessageService()
@@ -218,7 +235,9 @@ func saveFileData(ctx *UploadContext) error {
 
 	// 异步执行所有后处理操作，避免阻塞上传接口返回
 	// 使用全局context支持优雅关闭
+	uploadTasks.Add(1)
 	go func(serviceCtx context.Context, fileData models.File, uploadCtx *UploadContext) {
+		defer uploadTasks.Done()
 		defer func() {
 			if r := recover(); r != nil {
 				logger.Error("[上传后处理] panic: %v, 文件ID: %s", r, fileData.ID)
@@ -233,29 +252,67 @@ func saveFileData(ctx *UploadContext) error {
 		default:
 		}
 
-		if utils.GetAiAnalysisEnabled() {
-			// 当前 AI pipeline 为图片视觉识别（image_url/base64）。为避免非图片文件读取大体积 base64
-			// 或进入队列后失败，这里仅对图片类型文件入队处理。
-			isImage := strings.EqualFold(fileData.FileType, "image") ||
-				strings.HasPrefix(strings.ToLower(fileData.Mime), "image/") ||
-				strings.HasPrefix(strings.ToLower(fileData.MimeType), "image/")
-			if isImage {
+		if fileData.Status == common.FileStatusShareHold {
+			return
+		}
+		queueFileAnalysis(fileData, uploadCtx)
+	}(GetServiceContext(), *file, ctx)
+
+	return nil
+}
+
+// queueFileAnalysis 按设置将文件加入AI与向量处理队列，uploadCtx 为空时不预读缩略图
+func queueFileAnalysis(fileData models.File, uploadCtx *UploadContext) {
+	// 用户或所在文件夹关闭了AI处理时，打标与向量化都跳过
+	allowed := folder.IsAIProcessingAllowed(fileData.UserID, fileData.FolderID)
+
+	if utils.GetAiAnalysisEnabled() {
+		// 当前 AI pipeline 为图片视觉识别（image_url/base64）。为避免非图片文件读取大体积 base64
+		// 或进入队列后失败，这里仅对图片类型文件入队处理。
+		isImage := strings.EqualFold(fileData.FileType, "image") ||
+			strings.HasPrefix(strings.ToLower(fileData.Mime), "image/") ||
+			strings.HasPrefix(strings.ToLower(fileData.MimeType), "image/")
+		if isImage && (!allowed || !userGroupService.CheckAIQuota(fileData.UserID)) {
+			// 标记为已忽略，避免被补偿任务再次入队
+			if err := database.DB.Model(&models.File{}).Where("id = ?", fileData.ID).
+				Update("ai_tagging_status", common.AITaggingStatusIgnored).Error; err != nil {
+				logger.Warn("[上传后处理] 标记文件跳过AI处理失败: %v, file_id=%s", err, fileData.ID)
+			}
+		} else if isImage {
+			if uploadCtx != nil {
 				if err := captureThumbnailBase64(uploadCtx); err != nil {
 					logger.Warn("[上传后处理] 捕获缩略图base64数据失败: %v, file_id=%s", err, fileData.ID)
 				}
+			}
 
-				if err := ai.AddFileToQueue(fileData); err != nil {
-					logger.Error("[上传后处理] 将文件加入AI处理队列失败，文件ID: %s, 错误: %v", fileData.ID, err)
-				}
+			if err := ai.AddFileToQueue(fileData); err != nil {
+				logger.Error("[上传后处理] 将文件加入AI处理队列失败，文件ID: %s, 错误: %v", fileData.ID, err)
 			}
 		}
+	}
 
-		if vector.IsVectorEnabled() && fileData.Description != "" {
-			vector.AddFileToVectorQueue(fileData)
-		}
+	if allowed && vector.IsVectorEnabled() && fileData.Description != "" {
+		vector.AddFileToVectorQueue(fileData)
+	}
+}
 
-	}(GetServiceContext(), *file, ctx)
+/* ReleaseShareHold 收集型分享的访客上传审核通过：恢复为正常状态并补做上传时跳过的AI与向量处理 */
+func ReleaseShareHold(userID uint, fileID string) error {
+	result := database.DB.Model(&models.File{}).
+		Where("id = ? AND user_id = ? AND status = ?", fileID, userID, common.FileStatusShareHold).
+		Update("status", "active")
+	if result.Error != nil {
+		return errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "更新文件状态失败")
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
 
+	var file models.File
+	if err := database.DB.Where("id = ?", fileID).First(&file).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	go queueFileAnalysis(file, nil)
 	return nil
 }
 
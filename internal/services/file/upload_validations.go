@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"path/filepath"
 	"pixelpunk/internal/models"
+	quotaService "pixelpunk/internal/services/quota"
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
@@ -148,6 +149,7 @@ func checkDailyUploadLimit(userID uint, uploadCount int) (bool, error) {
 			dailyLimit = int(limit)
 		}
 	}
+	dailyLimit = quotaService.GetDailyUploadLimit(userID, dailyLimit)
 	if dailyLimit == -1 {
 		return false, nil
 	}
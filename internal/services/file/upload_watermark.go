@@ -11,7 +11,7 @@ import (
 )
 
 func processFileAndUploadWithWatermark(ctx *UploadContext) error {
-	if err := processFile(ctx); err != nil {
+	if err := ctx.traceStep("upload.process", func() error { return processFile(ctx) }); err != nil {
 		logger.Error("常规文件处理失败: %v", err)
 		return err
 	}
@@ -27,7 +27,7 @@ func processFileAndUploadWithWatermark(ctx *UploadContext) error {
 		}
 	}
 
-	if err := executeUpload(ctx); err != nil {
+	if err := ctx.traceStep("upload.store", func() error { return executeUpload(ctx) }); err != nil {
 		logger.Error("文件上传失败: %v", err)
 		return err
 	}
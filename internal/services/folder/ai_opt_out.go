@@ -0,0 +1,94 @@
+package folder
+
+import (
+	"fmt"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+)
+
+/* IsAIProcessingAllowed 用户设置及文件夹（含上级文件夹）均未关闭AI处理时返回 true，AI打标与向量化共用 */
+func IsAIProcessingAllowed(userID uint, folderID string) bool {
+	var settings models.UserSettings
+	if err := database.DB.Select("ai_enabled").Where("user_id = ?", userID).Take(&settings).Error; err == nil && !settings.AIEnabled {
+		return false
+	}
+
+	// 逐级向上检查，限制深度防止异常数据导致死循环
+	for depth := 0; folderID != "" && depth < 32; depth++ {
+		var folder models.Folder
+		if err := database.DB.Select("id, parent_id, ai_enabled").Where("id = ?", folderID).Take(&folder).Error; err != nil {
+			break
+		}
+		if !folder.AIEnabled {
+			return false
+		}
+		folderID = folder.ParentID
+	}
+	return true
+}
+
+/* FilterAIProcessingAllowed 返回允许AI处理的文件ID，用户或所在文件夹关闭了AI处理的文件标记为已忽略，
+ * 供补偿扫描、重试与死信重新入队前调用 */
+func FilterAIProcessingAllowed(fileIDs []string) []string {
+	if len(fileIDs) == 0 {
+		return fileIDs
+	}
+	var files []models.File
+	if err := database.DB.Select("id, user_id, folder_id").Where("id IN ?", fileIDs).Find(&files).Error; err != nil {
+		return fileIDs
+	}
+
+	// 同一用户同一文件夹的文件只检查一次
+	checked := make(map[string]bool)
+	optedOut := make(map[string]bool)
+	for _, f := range files {
+		key := fmt.Sprintf("%d:%s", f.UserID, f.FolderID)
+		allowed, ok := checked[key]
+		if !ok {
+			allowed = IsAIProcessingAllowed(f.UserID, f.FolderID)
+			checked[key] = allowed
+		}
+		if !allowed {
+			optedOut[f.ID] = true
+		}
+	}
+	if len(optedOut) == 0 {
+		return fileIDs
+	}
+
+	allowed := make([]string, 0, len(fileIDs)-len(optedOut))
+	skipped := make([]string, 0, len(optedOut))
+	for _, id := range fileIDs {
+		if optedOut[id] {
+			skipped = append(skipped, id)
+		} else {
+			allowed = append(allowed, id)
+		}
+	}
+	MarkAIProcessingSkipped(skipped)
+	return allowed
+}
+
+/* MarkAIProcessingSkipped 将尚未完成的AI打标与向量化标记为已忽略，避免被补偿扫描再次入队；已完成的结果保留 */
+func MarkAIProcessingSkipped(fileIDs []string) {
+	if len(fileIDs) == 0 {
+		return
+	}
+	if err := database.DB.Model(&models.File{}).
+		Where("id IN ? AND ai_tagging_status IN ?", fileIDs, []string{
+			common.AITaggingStatusNone, common.AITaggingStatusPending, common.AITaggingStatusFailed,
+		}).
+		Update("ai_tagging_status", common.AITaggingStatusIgnored).Error; err != nil {
+		logger.Warn("标记文件跳过AI处理失败: %v", err)
+	}
+	if err := database.DB.Model(&models.FileVector{}).
+		Where("file_id IN ? AND status IN ?", fileIDs, []string{
+			common.VectorStatusPending, common.VectorStatusReset, common.VectorStatusFailed, common.VectorStatusStale,
+		}).
+		Update("status", common.VectorStatusIgnored).Error; err != nil {
+		logger.Warn("标记文件跳过向量化失败: %v", err)
+	}
+}
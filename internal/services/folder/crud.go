@@ -10,7 +10,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func CreateFolder(userID uint, name, parentID, permission, description string) (*FolderResponse, error) {
+func CreateFolder(userID uint, name, parentID, permission, description string, aiEnabled *bool) (*FolderResponse, error) {
 	if !file.IsValidFolderName(name) {
 		return nil, errors.New(errors.CodeInvalidParameter, "文件夹名称无效：不能为空或包含 / \\ : * ? \" < > | 等特殊字符")
 	}
@@ -33,16 +33,23 @@ func CreateFolder(userID uint, name, parentID, permission, description string) (
 		return nil, errors.New(errors.CodeFolderNameDuplicate, "同级目录下已存在同名文件夹")
 	}
 
-	folder := models.Folder{ID: file.GenerateFolderID(), UserID: userID, ParentID: parentID, Name: name, Permission: permission, Description: description}
+	folder := models.Folder{ID: file.GenerateFolderID(), UserID: userID, ParentID: parentID, Name: name, Permission: permission, Description: description, AIEnabled: true}
 	if err := database.DB.Create(&folder).Error; err != nil {
 		return nil, errors.Wrap(err, errors.CodeFolderCreateFailed, "创建文件夹失败")
 	}
+	// ai_enabled 带默认值，false 需在创建后单独更新
+	if aiEnabled != nil && !*aiEnabled {
+		if err := database.DB.Model(&folder).Update("ai_enabled", false).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeFolderUpdateFailed, "更新文件夹AI设置失败")
+		}
+		folder.AIEnabled = false
+	}
 	stats.GetStatsAdapter().RecordFolderCreated()
 
 	return toResponse(&folder), nil
 }
 
-func UpdateFolder(userID uint, folderID, name, parentID, permission, description string) (*FolderResponse, error) {
+func UpdateFolder(userID uint, folderID, name, parentID, permission, description string, aiEnabled *bool) (*FolderResponse, error) {
 	var folder models.Folder
 	if err := database.DB.Where("id = ? AND user_id = ?", folderID, userID).First(&folder).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -113,6 +120,9 @@ func UpdateFolder(userID uint, folderID, name, parentID, permission, description
 	if description != "" {
 		folder.Description = description
 	}
+	if aiEnabled != nil {
+		folder.AIEnabled = *aiEnabled
+	}
 	if err := database.DB.Save(&folder).Error; err != nil {
 		return nil, errors.Wrap(err, errors.CodeFolderUpdateFailed, "更新文件夹失败")
 	}
@@ -35,12 +35,17 @@ func GetFolderPath(baseDir string, userID uint, folderID string) (string, error)
 }
 
 func CreateFolderByPath(userID uint, filePath string) (string, error) {
+	return CreateFolderByPathUnder(userID, "", filePath)
+}
+
+/* CreateFolderByPathUnder 在指定父目录下按路径逐级创建文件夹，返回最末级文件夹ID */
+func CreateFolderByPathUnder(userID uint, parentID, filePath string) (string, error) {
 	filePath = strings.Trim(filePath, "/")
 	if filePath == "" {
-		return "", nil
+		return parentID, nil
 	}
 	parts := strings.Split(filePath, "/")
-	var currentParentID string
+	currentParentID := parentID
 	for _, folderName := range parts {
 		folderName = strings.TrimSpace(folderName)
 		if folderName == "" {
@@ -55,7 +60,7 @@ func CreateFolderByPath(userID uint, filePath string) (string, error) {
 		if result.Error != gorm.ErrRecordNotFound {
 			return "", errors.Wrap(result.Error, errors.CodeDBQueryFailed, "查询文件夹失败")
 		}
-		newFolder, err := CreateFolder(userID, folderName, currentParentID, "private", "")
+		newFolder, err := CreateFolder(userID, folderName, currentParentID, "private", "", nil)
 		if err != nil {
 			return "", errors.Wrap(err, errors.CodeFolderCreateFailed, fmt.Sprintf("创建文件夹路径失败: %s", folderName))
 		}
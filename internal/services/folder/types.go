@@ -15,6 +15,7 @@ type FolderResponse struct {
 	HasChildren bool            `json:"has_children"`
 	SortOrder   int             `json:"sort_order"`
 	Level       int             `json:"level"`
+	AIEnabled   bool            `json:"ai_enabled"`
 	CreatedAt   common.JSONTime `json:"created_at"`
 	UpdatedAt   common.JSONTime `json:"updated_at"`
 }
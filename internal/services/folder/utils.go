@@ -32,6 +32,7 @@ func toResponse(folder *models.Folder) *FolderResponse {
 		HasChildren: childCount > 0,
 		SortOrder:   folder.SortOrder,
 		Level:       level,
+		AIEnabled:   folder.AIEnabled,
 		CreatedAt:   folder.CreatedAt,
 		UpdatedAt:   folder.UpdatedAt,
 	}
@@ -0,0 +1,115 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"pixelpunk/pkg/errors"
+	gql "pixelpunk/pkg/graphql"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// connection 游标分页结果，totalCount 仅在被查询时统计
+type connection struct {
+	edges   []*edge
+	hasNext bool
+	count   func() (int64, error)
+}
+
+type edge struct {
+	cursor string
+	node   interface{}
+}
+
+var pageInfoType = gql.NewObject("PageInfo", "分页信息",
+	&gql.FieldDefinition{Name: "hasNextPage", Type: gql.NewNonNull(gql.Boolean), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return p.Source.(*connection).hasNext, nil
+	}},
+	&gql.FieldDefinition{Name: "endCursor", Type: gql.String, Description: "最后一条记录的游标，作为下一页的 after 参数", Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		edges := p.Source.(*connection).edges
+		if len(edges) == 0 {
+			return nil, nil
+		}
+		return edges[len(edges)-1].cursor, nil
+	}},
+)
+
+// paginationArgs 游标分页参数
+func paginationArgs(extra ...*gql.ArgumentDefinition) []*gql.ArgumentDefinition {
+	return append([]*gql.ArgumentDefinition{
+		{Name: "first", Type: gql.Int, DefaultValue: defaultPageSize, Description: "每页数量，最大 100"},
+		{Name: "after", Type: gql.String, Description: "上一页的 endCursor"},
+	}, extra...)
+}
+
+// newConnectionType 创建 XxxConnection 与 XxxEdge 类型
+func newConnectionType(node *gql.Object) *gql.Object {
+	edgeType := gql.NewObject(node.Name+"Edge", "",
+		&gql.FieldDefinition{Name: "cursor", Type: gql.NewNonNull(gql.String), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*edge).cursor, nil
+		}},
+		&gql.FieldDefinition{Name: "node", Type: gql.NewNonNull(node), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*edge).node, nil
+		}},
+	)
+	return gql.NewObject(node.Name+"Connection", "",
+		&gql.FieldDefinition{Name: "edges", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(edgeType))), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*connection).edges, nil
+		}},
+		&gql.FieldDefinition{Name: "nodes", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(node))), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			edges := p.Source.(*connection).edges
+			nodes := make([]interface{}, len(edges))
+			for i, e := range edges {
+				nodes[i] = e.node
+			}
+			return nodes, nil
+		}},
+		&gql.FieldDefinition{Name: "pageInfo", Type: gql.NewNonNull(pageInfoType), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source, nil
+		}},
+		&gql.FieldDefinition{Name: "totalCount", Type: gql.NewNonNull(gql.Int), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*connection).count()
+		}},
+	)
+}
+
+// pageSize 读取 first 参数并限制范围
+func pageSize(args map[string]interface{}) (int, error) {
+	first, _ := args["first"].(int)
+	if first < 1 || first > maxPageSize {
+		return 0, errors.New(errors.CodeInvalidParameter, "first 取值范围为 1-100")
+	}
+	return first, nil
+}
+
+// encodeCursor 以创建时间与ID组成游标，与 created_at DESC, id DESC 的排序一致
+func encodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.Format(time.RFC3339Nano) + "|" + id))
+}
+
+// applyCursor 按 created_at DESC, id DESC 排序并跳过游标之前的记录
+func applyCursor(query *gorm.DB, after string) (*gorm.DB, error) {
+	if after != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil {
+			return nil, errors.New(errors.CodeInvalidParameter, "无效的分页游标")
+		}
+		parts := strings.SplitN(string(raw), "|", 2)
+		if len(parts) != 2 {
+			return nil, errors.New(errors.CodeInvalidParameter, "无效的分页游标")
+		}
+		createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			return nil, errors.New(errors.CodeInvalidParameter, "无效的分页游标")
+		}
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", createdAt, createdAt, parts[1])
+	}
+	return query.Order("created_at DESC, id DESC"), nil
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/errors"
+	gql "pixelpunk/pkg/graphql"
+	"pixelpunk/pkg/logger"
+)
+
+// Request GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type userIDKey struct{}
+
+var (
+	schemaOnce sync.Once
+	schema     *gql.Schema
+	schemaErr  error
+)
+
+/* IsEnabled 是否开放 GraphQL 接口，默认关闭 */
+func IsEnabled() bool {
+	return setting.GetBool("graphql", "graphql_enabled", false)
+}
+
+/* Execute 以指定用户身份执行只读查询 */
+func Execute(ctx context.Context, userID uint, req Request) *gql.Result {
+	schemaOnce.Do(func() {
+		schema, schemaErr = buildSchema()
+	})
+	if schemaErr != nil {
+		logger.Error("构建 GraphQL Schema 失败: %v", schemaErr)
+		return &gql.Result{Errors: []*gql.Error{{Message: "GraphQL 服务不可用"}}}
+	}
+
+	return gql.Do(gql.Params{
+		Schema:        schema,
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		Context:       context.WithValue(ctx, userIDKey{}, userID),
+		MaxDepth:      setting.GetInt("graphql", "graphql_max_depth", gql.DefaultMaxDepth),
+		MaxFields:     setting.GetInt("graphql", "graphql_max_fields", gql.DefaultMaxFields),
+		FormatError:   formatError,
+	})
+}
+
+func currentUserID(ctx context.Context) uint {
+	userID, _ := ctx.Value(userIDKey{}).(uint)
+	return userID
+}
+
+// formatError 业务错误只返回提示信息，其余错误记录日志后返回通用提示，避免泄露内部细节
+func formatError(err error) string {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Message
+	}
+	logger.Error("GraphQL 字段解析失败: %v", err)
+	return "服务器内部错误"
+}
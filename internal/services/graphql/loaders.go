@@ -0,0 +1,137 @@
+package graphql
+
+import (
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/storage"
+)
+
+// fileNode 文件节点，同一页的文件共享 batch，以便一次性加载标签与所在文件夹
+type fileNode struct {
+	models.File
+	batch *fileBatch
+	urls  []string
+}
+
+// fileBatch 同一次查询得到的一组文件
+type fileBatch struct {
+	userID  uint
+	nodes   []*fileNode
+	tags    map[string][]models.GlobalTag
+	folders map[string]*models.Folder
+}
+
+func newFileBatch(userID uint, files []models.File) *fileBatch {
+	b := &fileBatch{userID: userID, nodes: make([]*fileNode, len(files))}
+	for i := range files {
+		b.nodes[i] = &fileNode{File: files[i], batch: b}
+	}
+	return b
+}
+
+// fullURLs 原图、缩略图与短链地址，首次访问时计算
+func (n *fileNode) fullURLs() []string {
+	if n.urls == nil {
+		full, thumb, short := storage.GetFullURLs(n.File)
+		n.urls = []string{full, thumb, short}
+	}
+	return n.urls
+}
+
+// loadTags 一次查询整页文件的标签
+func (b *fileBatch) loadTags() (map[string][]models.GlobalTag, error) {
+	if b.tags != nil {
+		return b.tags, nil
+	}
+	ids := make([]string, len(b.nodes))
+	for i, n := range b.nodes {
+		ids[i] = n.ID
+	}
+
+	var relations []models.FileGlobalTagRelation
+	if err := database.DB.Preload("Tag").
+		Where("file_id IN ? AND user_id = ?", ids, b.userID).
+		Order("id ASC").Find(&relations).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件标签失败")
+	}
+	b.tags = make(map[string][]models.GlobalTag, len(ids))
+	for _, r := range relations {
+		b.tags[r.FileID] = append(b.tags[r.FileID], r.Tag)
+	}
+	return b.tags, nil
+}
+
+// loadFolders 一次查询整页文件所在的文件夹
+func (b *fileBatch) loadFolders() (map[string]*models.Folder, error) {
+	if b.folders != nil {
+		return b.folders, nil
+	}
+	ids := make([]string, 0, len(b.nodes))
+	for _, n := range b.nodes {
+		if n.FolderID != "" {
+			ids = append(ids, n.FolderID)
+		}
+	}
+
+	b.folders = make(map[string]*models.Folder, len(ids))
+	if len(ids) == 0 {
+		return b.folders, nil
+	}
+	var folders []models.Folder
+	if err := database.DB.Where("id IN ? AND user_id = ?", ids, b.userID).Find(&folders).Error; err != nil {
+		b.folders = nil
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+	}
+	for i := range folders {
+		b.folders[folders[i].ID] = &folders[i]
+	}
+	return b.folders, nil
+}
+
+// tagNode 标签节点，同一列表的标签共享 batch 以便一次性统计文件数
+type tagNode struct {
+	models.GlobalTag
+	batch *tagBatch
+}
+
+type tagBatch struct {
+	userID uint
+	nodes  []*tagNode
+	counts map[uint]int64
+}
+
+func newTagBatch(userID uint, tags []models.GlobalTag) *tagBatch {
+	b := &tagBatch{userID: userID, nodes: make([]*tagNode, len(tags))}
+	for i := range tags {
+		b.nodes[i] = &tagNode{GlobalTag: tags[i], batch: b}
+	}
+	return b
+}
+
+// loadCounts 按标签分组统计用户文件数
+func (b *tagBatch) loadCounts() (map[uint]int64, error) {
+	if b.counts != nil {
+		return b.counts, nil
+	}
+	ids := make([]uint, len(b.nodes))
+	for i, n := range b.nodes {
+		ids[i] = n.ID
+	}
+
+	var rows []struct {
+		TagID uint
+		Count int64
+	}
+	if err := database.DB.Model(&models.FileGlobalTagRelation{}).
+		Select("tag_id, COUNT(*) AS count").
+		Where("user_id = ? AND tag_id IN ?", b.userID, ids).
+		Group("tag_id").Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计标签文件数失败")
+	}
+	b.counts = make(map[uint]int64, len(rows))
+	for _, r := range rows {
+		b.counts[r.TagID] = r.Count
+	}
+	return b.counts, nil
+}
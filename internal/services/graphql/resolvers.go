@@ -0,0 +1,232 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/stats"
+	"pixelpunk/internal/services/user_tag"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	gql "pixelpunk/pkg/graphql"
+
+	"gorm.io/gorm"
+)
+
+// fileFilter 文件列表筛选条件，folderID 为 nil 表示不限文件夹
+type fileFilter struct {
+	folderID *string
+	tagID    uint
+	keyword  string
+}
+
+func resolveViewer(p gql.ResolveParams) (interface{}, error) {
+	var user models.User
+	if err := database.DB.First(&user, currentUserID(p.Context)).Error; err != nil {
+		return nil, errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	return &user, nil
+}
+
+func resolveFiles(p gql.ResolveParams) (interface{}, error) {
+	var filter fileFilter
+	if folderID, ok := p.Args["folderId"].(string); ok {
+		filter.folderID = &folderID
+	}
+	if tagID, ok := p.Args["tagId"].(string); ok {
+		id, err := strconv.ParseUint(tagID, 10, 64)
+		if err != nil {
+			return nil, errors.New(errors.CodeInvalidParameter, "无效的标签ID")
+		}
+		filter.tagID = uint(id)
+	}
+	filter.keyword, _ = p.Args["keyword"].(string)
+	return queryFiles(p, filter)
+}
+
+// queryFiles 游标分页查询当前用户的文件，不包含回收站与待删除的文件
+func queryFiles(p gql.ResolveParams, filter fileFilter) (*connection, error) {
+	userID := currentUserID(p.Context)
+	first, err := pageSize(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	after, _ := p.Args["after"].(string)
+
+	build := func() *gorm.DB {
+		query := database.DB.Model(&models.File{}).
+			Where("user_id = ? AND status NOT IN ?", userID, filesvc.LibraryHiddenStatuses())
+		if filter.folderID != nil {
+			query = query.Where("folder_id = ?", *filter.folderID)
+		}
+		if filter.tagID > 0 {
+			query = query.Where("id IN (?)", database.DB.Model(&models.FileGlobalTagRelation{}).
+				Select("file_id").Where("user_id = ? AND tag_id = ?", userID, filter.tagID))
+		}
+		if kw := strings.TrimSpace(filter.keyword); kw != "" {
+			query = query.Where("original_name LIKE ? OR display_name LIKE ?", "%"+kw+"%", "%"+kw+"%")
+		}
+		return query
+	}
+
+	query, err := applyCursor(build(), after)
+	if err != nil {
+		return nil, err
+	}
+	var files []models.File
+	if err := query.Limit(first + 1).Find(&files).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+
+	conn := &connection{count: func() (int64, error) {
+		var total int64
+		if err := build().Count(&total).Error; err != nil {
+			return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "统计文件数失败")
+		}
+		return total, nil
+	}}
+	if len(files) > first {
+		files = files[:first]
+		conn.hasNext = true
+	}
+	for _, n := range newFileBatch(userID, files).nodes {
+		conn.edges = append(conn.edges, &edge{cursor: encodeCursor(time.Time(n.CreatedAt), n.ID), node: n})
+	}
+	return conn, nil
+}
+
+func resolveFile(p gql.ResolveParams) (interface{}, error) {
+	userID := currentUserID(p.Context)
+	var file models.File
+	err := database.DB.Where("id = ? AND user_id = ? AND status NOT IN ?", p.Args["id"], userID, filesvc.LibraryHiddenStatuses()).
+		First(&file).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	return newFileBatch(userID, []models.File{file}).nodes[0], nil
+}
+
+func resolveFileFolder(p gql.ResolveParams) (interface{}, error) {
+	n := p.Source.(*fileNode)
+	if n.FolderID == "" {
+		return nil, nil
+	}
+	folders, err := n.batch.loadFolders()
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := folders[n.FolderID]; ok {
+		return f, nil
+	}
+	return nil, nil
+}
+
+func resolveFileTags(p gql.ResolveParams) (interface{}, error) {
+	n := p.Source.(*fileNode)
+	tags, err := n.batch.loadTags()
+	if err != nil {
+		return nil, err
+	}
+	b := newTagBatch(n.batch.userID, tags[n.ID])
+	return b.nodes, nil
+}
+
+// findFolder 查找当前用户的文件夹，不存在时返回 null
+func findFolder(userID uint, id string) (interface{}, error) {
+	var folder models.Folder
+	err := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&folder).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+	}
+	return &folder, nil
+}
+
+func listFolders(userID uint, parentID string) ([]*models.Folder, error) {
+	var folders []models.Folder
+	if err := database.DB.Where("user_id = ? AND parent_id = ?", userID, parentID).
+		Order("sort_order ASC, created_at ASC").Find(&folders).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+	}
+	result := make([]*models.Folder, len(folders))
+	for i := range folders {
+		result[i] = &folders[i]
+	}
+	return result, nil
+}
+
+func resolveTags(p gql.ResolveParams) (interface{}, error) {
+	userID := currentUserID(p.Context)
+	tags, err := user_tag.GetAllUserTags(userID)
+	if err != nil {
+		return nil, err
+	}
+	return newTagBatch(userID, tags).nodes, nil
+}
+
+func resolveShares(p gql.ResolveParams) (interface{}, error) {
+	userID := currentUserID(p.Context)
+	first, err := pageSize(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	after, _ := p.Args["after"].(string)
+
+	build := func() *gorm.DB {
+		query := database.DB.Model(&models.Share{}).Where("user_id = ?", userID)
+		if status, ok := p.Args["status"].(int); ok {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	query, err := applyCursor(build(), after)
+	if err != nil {
+		return nil, err
+	}
+	var shares []models.Share
+	if err := query.Limit(first + 1).Find(&shares).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+
+	conn := &connection{count: func() (int64, error) {
+		var total int64
+		if err := build().Count(&total).Error; err != nil {
+			return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "统计分享数失败")
+		}
+		return total, nil
+	}}
+	if len(shares) > first {
+		shares = shares[:first]
+		conn.hasNext = true
+	}
+	for i := range shares {
+		s := &shares[i]
+		conn.edges = append(conn.edges, &edge{cursor: encodeCursor(time.Time(s.CreatedAt), s.ID), node: s})
+	}
+	return conn, nil
+}
+
+func resolveShare(p gql.ResolveParams) (interface{}, error) {
+	var share models.Share
+	err := database.DB.Where("id = ? AND user_id = ?", p.Args["id"], currentUserID(p.Context)).First(&share).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+	return &share, nil
+}
+
+func resolveStats(p gql.ResolveParams) (interface{}, error) {
+	return stats.GetUserStats(currentUserID(p.Context))
+}
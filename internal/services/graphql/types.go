@@ -0,0 +1,185 @@
+package graphql
+
+import (
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	gql "pixelpunk/pkg/graphql"
+	"pixelpunk/pkg/utils"
+)
+
+var shareStatusEnum = &gql.Enum{
+	Name:        "ShareStatus",
+	Description: "分享状态",
+	Values: []*gql.EnumValueDefinition{
+		{Name: "NORMAL", Value: common.ShareStatusNormal, Description: "正常"},
+		{Name: "EXPIRED", Value: common.ShareStatusExpired, Description: "已过期"},
+		{Name: "DELETED", Value: common.ShareStatusDeleted, Description: "已删除"},
+		{Name: "DISABLED", Value: common.ShareStatusDisabled, Description: "已禁用"},
+	},
+}
+
+// buildSchema 构建面向当前用户的只读 Schema
+func buildSchema() (*gql.Schema, error) {
+	nonNullID := gql.NewNonNull(gql.ID)
+	nonNullString := gql.NewNonNull(gql.String)
+	nonNullInt := gql.NewNonNull(gql.Int)
+	nonNullLong := gql.NewNonNull(gql.Long)
+
+	userType := gql.NewObject("User", "当前用户",
+		&gql.FieldDefinition{Name: "id", Type: nonNullID},
+		&gql.FieldDefinition{Name: "username", Type: nonNullString},
+		&gql.FieldDefinition{Name: "email", Type: gql.String},
+		&gql.FieldDefinition{Name: "avatar", Type: gql.String},
+		&gql.FieldDefinition{Name: "bio", Type: gql.String},
+		&gql.FieldDefinition{Name: "website", Type: gql.String},
+		&gql.FieldDefinition{Name: "createdAt", Type: gql.DateTime},
+	)
+
+	tagType := gql.NewObject("Tag", "标签",
+		&gql.FieldDefinition{Name: "id", Type: nonNullID},
+		&gql.FieldDefinition{Name: "name", Type: nonNullString},
+		&gql.FieldDefinition{Name: "slug", Type: gql.String},
+		&gql.FieldDefinition{Name: "description", Type: gql.String},
+	)
+
+	folderType := gql.NewObject("Folder", "文件夹",
+		&gql.FieldDefinition{Name: "id", Type: nonNullID},
+		&gql.FieldDefinition{Name: "name", Type: nonNullString},
+		&gql.FieldDefinition{Name: "description", Type: gql.String},
+		&gql.FieldDefinition{Name: "permission", Type: nonNullString, Description: "private 或 public"},
+		&gql.FieldDefinition{Name: "sortOrder", Type: nonNullInt},
+		&gql.FieldDefinition{Name: "createdAt", Type: gql.DateTime},
+		&gql.FieldDefinition{Name: "updatedAt", Type: gql.DateTime},
+	)
+
+	fileType := gql.NewObject("File", "文件",
+		&gql.FieldDefinition{Name: "id", Type: nonNullID},
+		&gql.FieldDefinition{Name: "name", Type: nonNullString, Description: "原始文件名", Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*fileNode).OriginalName, nil
+		}},
+		&gql.FieldDefinition{Name: "displayName", Type: gql.String},
+		&gql.FieldDefinition{Name: "description", Type: gql.String},
+		&gql.FieldDefinition{Name: "url", Type: gql.String, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*fileNode).fullURLs()[0], nil
+		}},
+		&gql.FieldDefinition{Name: "thumbUrl", Type: gql.String, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*fileNode).fullURLs()[1], nil
+		}},
+		&gql.FieldDefinition{Name: "shortUrl", Type: gql.String, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*fileNode).fullURLs()[2], nil
+		}},
+		&gql.FieldDefinition{Name: "size", Type: nonNullLong, Description: "字节数"},
+		&gql.FieldDefinition{Name: "width", Type: nonNullInt},
+		&gql.FieldDefinition{Name: "height", Type: nonNullInt},
+		&gql.FieldDefinition{Name: "format", Type: gql.String},
+		&gql.FieldDefinition{Name: "mime", Type: gql.String},
+		&gql.FieldDefinition{Name: "fileType", Type: nonNullString, Description: "image、video、document、archive、audio 或 other"},
+		&gql.FieldDefinition{Name: "accessLevel", Type: nonNullString, Description: "public、private 或 protected"},
+		&gql.FieldDefinition{Name: "nsfw", Type: gql.NewNonNull(gql.Boolean)},
+		&gql.FieldDefinition{Name: "createdAt", Type: gql.DateTime},
+		&gql.FieldDefinition{Name: "updatedAt", Type: gql.DateTime},
+		&gql.FieldDefinition{Name: "folder", Type: folderType, Description: "所在文件夹，根目录为 null", Resolve: resolveFileFolder},
+		&gql.FieldDefinition{Name: "tags", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(tagType))), Resolve: resolveFileTags},
+	)
+	fileConnection := newConnectionType(fileType)
+
+	tagType.AddFields(
+		&gql.FieldDefinition{Name: "fileCount", Type: nonNullInt, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			n := p.Source.(*tagNode)
+			counts, err := n.batch.loadCounts()
+			if err != nil {
+				return nil, err
+			}
+			return counts[n.ID], nil
+		}},
+		&gql.FieldDefinition{Name: "files", Type: gql.NewNonNull(fileConnection), Args: paginationArgs(), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return queryFiles(p, fileFilter{tagID: p.Source.(*tagNode).ID})
+		}},
+	)
+
+	folderType.AddFields(
+		&gql.FieldDefinition{Name: "parent", Type: folderType, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			f := p.Source.(*models.Folder)
+			if f.ParentID == "" {
+				return nil, nil
+			}
+			return findFolder(currentUserID(p.Context), f.ParentID)
+		}},
+		&gql.FieldDefinition{Name: "folders", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(folderType))), Description: "子文件夹", Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return listFolders(currentUserID(p.Context), p.Source.(*models.Folder).ID)
+		}},
+		&gql.FieldDefinition{Name: "files", Type: gql.NewNonNull(fileConnection), Args: paginationArgs(), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			folderID := p.Source.(*models.Folder).ID
+			return queryFiles(p, fileFilter{folderID: &folderID})
+		}},
+	)
+
+	shareType := gql.NewObject("Share", "分享",
+		&gql.FieldDefinition{Name: "id", Type: nonNullID},
+		&gql.FieldDefinition{Name: "name", Type: gql.String},
+		&gql.FieldDefinition{Name: "description", Type: gql.String},
+		&gql.FieldDefinition{Name: "shareKey", Type: nonNullString},
+		&gql.FieldDefinition{Name: "url", Type: nonNullString, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return utils.GetBaseUrl() + "/share/" + p.Source.(*models.Share).ShareKey, nil
+		}},
+		&gql.FieldDefinition{Name: "accessMode", Type: nonNullString, Description: "public 或 users"},
+		&gql.FieldDefinition{Name: "shareType", Type: nonNullString, Description: "view 或 collect"},
+		&gql.FieldDefinition{Name: "status", Type: gql.NewNonNull(shareStatusEnum)},
+		&gql.FieldDefinition{Name: "hasPassword", Type: gql.NewNonNull(gql.Boolean), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*models.Share).Password != "", nil
+		}},
+		&gql.FieldDefinition{Name: "currentViews", Type: nonNullInt},
+		&gql.FieldDefinition{Name: "maxViews", Type: nonNullInt, Description: "0 表示不限制"},
+		&gql.FieldDefinition{Name: "downloadCount", Type: nonNullInt},
+		&gql.FieldDefinition{Name: "maxDownloads", Type: nonNullInt, Description: "0 表示不限制"},
+		&gql.FieldDefinition{Name: "startsAt", Type: gql.DateTime},
+		&gql.FieldDefinition{Name: "expiredAt", Type: gql.DateTime},
+		&gql.FieldDefinition{Name: "createdAt", Type: gql.DateTime},
+	)
+	shareConnection := newConnectionType(shareType)
+
+	quotaType := gql.NewObject("QuotaUsage", "配额使用情况",
+		&gql.FieldDefinition{Name: "used", Type: nonNullLong, Description: "字节数"},
+		&gql.FieldDefinition{Name: "limit", Type: nonNullLong, Description: "字节数"},
+		&gql.FieldDefinition{Name: "percentage", Type: gql.NewNonNull(gql.Float)},
+	)
+	statsType := gql.NewObject("Stats", "当前用户的使用统计",
+		&gql.FieldDefinition{Name: "storage", Type: gql.NewNonNull(quotaType)},
+		&gql.FieldDefinition{Name: "bandwidth", Type: gql.NewNonNull(quotaType)},
+		&gql.FieldDefinition{Name: "fileCount", Type: nonNullLong, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*models.UserStatsResponse).Files.Total, nil
+		}},
+		&gql.FieldDefinition{Name: "views", Type: nonNullLong, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return p.Source.(*models.UserStatsResponse).Files.Views, nil
+		}},
+	)
+
+	query := gql.NewObject("Query", "",
+		&gql.FieldDefinition{Name: "viewer", Type: gql.NewNonNull(userType), Description: "当前用户", Resolve: resolveViewer},
+		&gql.FieldDefinition{Name: "files", Type: gql.NewNonNull(fileConnection), Description: "按上传时间倒序列出文件",
+			Args: paginationArgs(
+				&gql.ArgumentDefinition{Name: "folderId", Type: gql.ID, Description: "只列出该文件夹中的文件，空字符串表示根目录"},
+				&gql.ArgumentDefinition{Name: "tagId", Type: gql.ID},
+				&gql.ArgumentDefinition{Name: "keyword", Type: gql.String, Description: "按文件名搜索"},
+			),
+			Resolve: resolveFiles},
+		&gql.FieldDefinition{Name: "file", Type: fileType, Args: []*gql.ArgumentDefinition{{Name: "id", Type: nonNullID}}, Resolve: resolveFile},
+		&gql.FieldDefinition{Name: "folders", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(folderType))), Description: "列出文件夹，默认为根目录下的文件夹",
+			Args: []*gql.ArgumentDefinition{{Name: "parentId", Type: gql.ID, DefaultValue: ""}},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				parentID, _ := p.Args["parentId"].(string)
+				return listFolders(currentUserID(p.Context), parentID)
+			}},
+		&gql.FieldDefinition{Name: "folder", Type: folderType, Args: []*gql.ArgumentDefinition{{Name: "id", Type: nonNullID}}, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return findFolder(currentUserID(p.Context), p.Args["id"].(string))
+		}},
+		&gql.FieldDefinition{Name: "tags", Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(tagType))), Resolve: resolveTags},
+		&gql.FieldDefinition{Name: "shares", Type: gql.NewNonNull(shareConnection), Description: "按创建时间倒序列出分享",
+			Args:    paginationArgs(&gql.ArgumentDefinition{Name: "status", Type: shareStatusEnum}),
+			Resolve: resolveShares},
+		&gql.FieldDefinition{Name: "share", Type: shareType, Args: []*gql.ArgumentDefinition{{Name: "id", Type: nonNullID}}, Resolve: resolveShare},
+		&gql.FieldDefinition{Name: "stats", Type: gql.NewNonNull(statsType), Resolve: resolveStats},
+	)
+
+	return gql.NewSchema(query, "PixelPunk 只读查询接口，数据范围限定为当前用户")
+}
@@ -0,0 +1,221 @@
+package invite
+
+import (
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 注册模式
+const (
+	RegistrationModeOpen   = "open"
+	RegistrationModeInvite = "invite"
+	RegistrationModeClosed = "closed"
+)
+
+const (
+	inviteCodeLength   = 12
+	maxCodesPerRequest = 100
+)
+
+/* GenerateOptions 生成邀请码的参数 */
+type GenerateOptions struct {
+	Count         int
+	MaxUses       int
+	ExpiresInDays int
+	Note          string
+}
+
+/* GetRegistrationMode 获取当前注册模式，开放注册开关关闭时视为关闭注册 */
+func GetRegistrationMode() string {
+	if !setting.GetBool("registration", "enable_registration", true) {
+		return RegistrationModeClosed
+	}
+	if setting.GetString("registration", "registration_mode", RegistrationModeOpen) == RegistrationModeInvite {
+		return RegistrationModeInvite
+	}
+	return RegistrationModeOpen
+}
+
+/* GetUserQuota 普通用户可生成的邀请码总数及已生成数量 */
+func GetUserQuota(userID uint) (int, int64) {
+	quota := setting.GetInt("registration", "user_invite_quota", 0)
+	var used int64
+	database.GetDB().Model(&models.InvitationCode{}).Where("creator_id = ?", userID).Count(&used)
+	return quota, used
+}
+
+/* GenerateCodes 生成邀请码；普通用户受配额限制且每个码仅能使用一次 */
+func GenerateCodes(creatorID uint, isAdmin bool, opts GenerateOptions) ([]models.InvitationCode, error) {
+	if opts.Count <= 0 {
+		opts.Count = 1
+	}
+	if opts.Count > maxCodesPerRequest {
+		return nil, errors.New(errors.CodeInvalidParameter, "单次最多生成100个邀请码")
+	}
+
+	if !isAdmin {
+		quota, used := GetUserQuota(creatorID)
+		if quota <= 0 {
+			return nil, errors.New(errors.CodeForbidden, "当前没有生成邀请码的权限")
+		}
+		if used+int64(opts.Count) > int64(quota) {
+			return nil, errors.New(errors.CodeForbidden, "邀请码配额不足")
+		}
+		opts.MaxUses = 1
+	} else if opts.MaxUses < 0 {
+		opts.MaxUses = 1
+	}
+
+	var expiresAt *common.JSONTime
+	if opts.ExpiresInDays > 0 {
+		t := common.JSONTime(time.Now().AddDate(0, 0, opts.ExpiresInDays))
+		expiresAt = &t
+	}
+
+	codes := make([]models.InvitationCode, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		codes = append(codes, models.InvitationCode{
+			Code:      strings.ToUpper(utils.GenerateRandomString(inviteCodeLength)),
+			CreatorID: creatorID,
+			MaxUses:   opts.MaxUses,
+			Status:    models.InvitationCodeStatusActive,
+			Note:      opts.Note,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	if err := database.GetDB().Create(&codes).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "生成邀请码失败")
+	}
+	return codes, nil
+}
+
+/* ListCodes 分页获取邀请码，creatorID 为0时返回全部（管理员） */
+func ListCodes(creatorID uint, page, size int, status int) ([]models.InvitationCode, int64, error) {
+	query := database.GetDB().Model(&models.InvitationCode{})
+	if creatorID > 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询邀请码失败")
+	}
+
+	var codes []models.InvitationCode
+	if err := query.Preload("Creator", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username")
+	}).Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&codes).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询邀请码失败")
+	}
+	return codes, total, nil
+}
+
+/* GetCodeUsers 获取使用某个邀请码注册的用户 */
+func GetCodeUsers(codeID uint) ([]models.User, error) {
+	var users []models.User
+	if err := database.GetDB().Select("id", "username", "created_at").
+		Where("invitation_code_id = ?", codeID).Order("id ASC").Find(&users).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询邀请注册用户失败")
+	}
+	return users, nil
+}
+
+/* SetCodeStatus 启用或停用邀请码，creatorID 非0时只能操作自己生成的码 */
+func SetCodeStatus(codeID, creatorID uint, status int) error {
+	if status != models.InvitationCodeStatusActive && status != models.InvitationCodeStatusDisabled {
+		return errors.New(errors.CodeInvalidParameter, "无效的邀请码状态")
+	}
+	code, err := findCode(codeID, creatorID)
+	if err != nil {
+		return err
+	}
+	if err := database.GetDB().Model(code).Update("status", status).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新邀请码状态失败")
+	}
+	return nil
+}
+
+/* DeleteCode 删除邀请码，已被使用过的码保留记录只能停用 */
+func DeleteCode(codeID, creatorID uint) error {
+	code, err := findCode(codeID, creatorID)
+	if err != nil {
+		return err
+	}
+	if code.UsedCount > 0 {
+		return errors.New(errors.CodeInvalidRequest, "邀请码已被使用，只能停用")
+	}
+	if err := database.GetDB().Delete(code).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除邀请码失败")
+	}
+	return nil
+}
+
+/* CheckCode 校验邀请码是否可用，供注册页提前提示 */
+func CheckCode(code string) (*models.InvitationCode, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, errors.New(errors.CodeInvalidParameter, "请填写邀请码")
+	}
+
+	var invitation models.InvitationCode
+	if err := database.GetDB().Where("code = ?", code).First(&invitation).Error; err != nil {
+		return nil, errors.New(errors.CodeInvalidParameter, "邀请码无效")
+	}
+	if invitation.Status != models.InvitationCodeStatusActive {
+		return nil, errors.New(errors.CodeInvalidParameter, "邀请码已停用")
+	}
+	if invitation.IsExpired() {
+		return nil, errors.New(errors.CodeInvalidParameter, "邀请码已过期")
+	}
+	if invitation.IsExhausted() {
+		return nil, errors.New(errors.CodeInvalidParameter, "邀请码已被使用")
+	}
+	return &invitation, nil
+}
+
+/* ConsumeCode 在注册事务内占用一次邀请码使用次数，条件更新避免并发超用 */
+func ConsumeCode(tx *gorm.DB, code string) (*models.InvitationCode, error) {
+	invitation, err := CheckCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := tx.Model(&models.InvitationCode{}).
+		Where("id = ? AND status = ? AND (max_uses = 0 OR used_count < max_uses)", invitation.ID, models.InvitationCodeStatusActive).
+		UpdateColumn("used_count", gorm.Expr("used_count + ?", 1))
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "使用邀请码失败")
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "邀请码已被使用")
+	}
+	return invitation, nil
+}
+
+func findCode(codeID, creatorID uint) (*models.InvitationCode, error) {
+	query := database.GetDB().Where("id = ?", codeID)
+	if creatorID > 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+	var code models.InvitationCode
+	if err := query.First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "邀请码不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询邀请码失败")
+	}
+	return &code, nil
+}
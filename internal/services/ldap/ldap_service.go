@@ -0,0 +1,182 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const ldapTimeout = 10 * time.Second
+
+// ErrInvalidCredentials 用户不存在或密码错误，与连接、配置错误区分开
+var ErrInvalidCredentials = errors.New("LDAP账号或密码错误")
+
+// Config LDAP 认证配置，对应 ldap 设置分组
+type Config struct {
+	Enabled            bool
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	UsernameAttribute  string
+	EmailAttribute     string
+	GroupAttribute     string
+	GroupRoleMapping   string
+	DefaultRole        int
+	AutoCreateUser     bool
+}
+
+// Entry LDAP 认证成功后返回的用户信息
+type Entry struct {
+	DN       string
+	Username string
+	Email    string
+	Groups   []string
+	Role     int
+}
+
+/* GetConfig 读取 LDAP 配置 */
+func GetConfig() *Config {
+	return &Config{
+		Enabled:            setting.GetBool("ldap", "ldap_enabled", false),
+		URL:                setting.GetString("ldap", "ldap_url", ""),
+		StartTLS:           setting.GetBool("ldap", "ldap_start_tls", false),
+		InsecureSkipVerify: setting.GetBool("ldap", "ldap_insecure_skip_verify", false),
+		BindDN:             setting.GetString("ldap", "ldap_bind_dn", ""),
+		BindPassword:       setting.GetString("ldap", "ldap_bind_password", ""),
+		BaseDN:             setting.GetString("ldap", "ldap_base_dn", ""),
+		UserFilter:         setting.GetString("ldap", "ldap_user_filter", "(uid={username})"),
+		UsernameAttribute:  setting.GetString("ldap", "ldap_username_attribute", "uid"),
+		EmailAttribute:     setting.GetString("ldap", "ldap_email_attribute", "mail"),
+		GroupAttribute:     setting.GetString("ldap", "ldap_group_attribute", "memberOf"),
+		GroupRoleMapping:   setting.GetString("ldap", "ldap_group_role_mapping", ""),
+		DefaultRole:        setting.GetInt("ldap", "ldap_default_role", common.UserRoleUser),
+		AutoCreateUser:     setting.GetBool("ldap", "ldap_auto_create_user", true),
+	}
+}
+
+/* IsEnabled LDAP 登录是否已启用且配置完整 */
+func IsEnabled() bool {
+	cfg := GetConfig()
+	return cfg.Enabled && cfg.URL != "" && cfg.BaseDN != ""
+}
+
+/* Authenticate 使用服务账号搜索用户后以用户DN和密码绑定校验 */
+func Authenticate(cfg *Config, account, password string) (*Entry, error) {
+	// 空密码会被多数目录服务视为匿名绑定而直接成功，必须拒绝
+	if account == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := bindService(conn, cfg); err != nil {
+		return nil, err
+	}
+
+	filter := strings.ReplaceAll(cfg.UserFilter, "{username}", goldap.EscapeFilter(account))
+	attributes := []string{cfg.UsernameAttribute, cfg.EmailAttribute, cfg.GroupAttribute}
+	result, err := conn.Search(goldap.NewSearchRequest(
+		cfg.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, int(ldapTimeout.Seconds()), false, filter, attributes, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("搜索LDAP用户失败: %w", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("LDAP搜索匹配到多个用户，请检查用户过滤器")
+	}
+	ldapEntry := result.Entries[0]
+
+	if err := conn.Bind(ldapEntry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("LDAP用户绑定失败: %w", err)
+	}
+
+	entry := &Entry{
+		DN:       ldapEntry.DN,
+		Username: ldapEntry.GetAttributeValue(cfg.UsernameAttribute),
+		Email:    ldapEntry.GetAttributeValue(cfg.EmailAttribute),
+		Groups:   ldapEntry.GetAttributeValues(cfg.GroupAttribute),
+	}
+	if entry.Username == "" {
+		entry.Username = account
+	}
+	entry.Role = ResolveRole(cfg, entry.Groups)
+
+	return entry, nil
+}
+
+/* TestConnection 测试服务器连接和服务账号绑定 */
+func TestConnection(cfg *Config) error {
+	conn, err := dial(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return bindService(conn, cfg)
+}
+
+/* ResolveRole 按组映射计算角色，匹配多个组时取权限最高者 */
+func ResolveRole(cfg *Config, groups []string) int {
+	return common.ResolveMappedRole(cfg.GroupRoleMapping, groups, cfg.DefaultRole)
+}
+
+func dial(cfg *Config) (*goldap.Conn, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("LDAP服务器地址未配置")
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	conn, err := goldap.DialURL(cfg.URL,
+		goldap.DialWithTLSConfig(tlsConfig),
+		goldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("连接LDAP服务器失败: %w", err)
+	}
+	conn.SetTimeout(ldapTimeout)
+
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("LDAP StartTLS失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// bindService 以服务账号绑定，未配置服务账号时使用匿名绑定
+func bindService(conn *goldap.Conn, cfg *Config) error {
+	var err error
+	if cfg.BindDN != "" {
+		err = conn.Bind(cfg.BindDN, cfg.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return fmt.Errorf("LDAP服务账号绑定失败: %w", err)
+	}
+	return nil
+}
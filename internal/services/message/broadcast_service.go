@@ -0,0 +1,300 @@
+package message
+
+import (
+	"encoding/json"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// 每批写入的消息数量
+const broadcastBatchSize = 500
+
+// BroadcastSegment 群发接收人筛选条件，角色/用户组/用户之间为"或"关系，活跃天数为附加条件
+type BroadcastSegment struct {
+	Roles            []int  `json:"roles,omitempty"`
+	GroupIDs         []uint `json:"group_ids,omitempty"`
+	UserIDs          []uint `json:"user_ids,omitempty"`
+	ActiveWithinDays int    `json:"active_within_days,omitempty"`
+}
+
+// BroadcastRequest 创建群发请求
+type BroadcastRequest struct {
+	Title      string
+	Content    string
+	Priority   int
+	ActionURL  string
+	ActionText string
+	Segment    BroadcastSegment
+}
+
+// BroadcastStats 群发送达与已读统计
+type BroadcastStats struct {
+	Delivered int64   `json:"delivered"`
+	Read      int64   `json:"read"`
+	Unread    int64   `json:"unread"`
+	Deleted   int64   `json:"deleted"`
+	ReadRate  float64 `json:"read_rate"`
+}
+
+// BroadcastResponse 群发详情
+type BroadcastResponse struct {
+	models.MessageBroadcast
+	Segment BroadcastSegment `json:"segment"`
+	Stats   BroadcastStats   `json:"stats"`
+}
+
+/* CreateBroadcast 创建群发并在后台逐批投递到接收人的消息中心 */
+func (s *MessageService) CreateBroadcast(senderID uint, req BroadcastRequest) (*BroadcastResponse, error) {
+	db := database.GetDB()
+
+	total, err := countBroadcastRecipients(req.Segment)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "没有符合条件的接收用户")
+	}
+
+	segment, _ := json.Marshal(req.Segment)
+	priority := req.Priority
+	if priority == 0 {
+		priority = common.MessagePriorityNormal
+	}
+	broadcast := &models.MessageBroadcast{
+		SenderID:        senderID,
+		Title:           req.Title,
+		Content:         req.Content,
+		Priority:        priority,
+		ActionURL:       req.ActionURL,
+		ActionText:      req.ActionText,
+		Segment:         string(segment),
+		Status:          models.MessageBroadcastSending,
+		TotalRecipients: int(total),
+	}
+	if err := db.Create(broadcast).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建群发消息失败")
+	}
+
+	go s.deliverBroadcast(*broadcast, req.Segment)
+
+	return &BroadcastResponse{MessageBroadcast: *broadcast, Segment: req.Segment}, nil
+}
+
+/* ListBroadcasts 分页获取群发记录及统计 */
+func (s *MessageService) ListBroadcasts(page, pageSize int) ([]BroadcastResponse, int64, error) {
+	db := database.GetDB()
+
+	var total int64
+	if err := db.Model(&models.MessageBroadcast{}).Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询群发记录失败")
+	}
+
+	var broadcasts []models.MessageBroadcast
+	if err := db.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&broadcasts).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询群发记录失败")
+	}
+
+	ids := make([]uint, 0, len(broadcasts))
+	for _, b := range broadcasts {
+		ids = append(ids, b.ID)
+	}
+	stats, err := loadBroadcastStats(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]BroadcastResponse, 0, len(broadcasts))
+	for _, b := range broadcasts {
+		items = append(items, toBroadcastResponse(b, stats[b.ID]))
+	}
+	return items, total, nil
+}
+
+/* GetBroadcast 获取群发详情及统计 */
+func (s *MessageService) GetBroadcast(id uint) (*BroadcastResponse, error) {
+	var broadcast models.MessageBroadcast
+	if err := database.GetDB().First(&broadcast, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "群发记录不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询群发记录失败")
+	}
+
+	stats, err := loadBroadcastStats([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	resp := toBroadcastResponse(broadcast, stats[id])
+	return &resp, nil
+}
+
+// deliverBroadcast 按用户ID游标分批写入消息，每批完成后更新送达数
+func (s *MessageService) deliverBroadcast(broadcast models.MessageBroadcast, segment BroadcastSegment) {
+	db := database.GetDB()
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"title":   broadcast.Title,
+		"content": broadcast.Content,
+	})
+	actionType := ""
+	if broadcast.ActionURL != "" {
+		actionType = common.ActionTypeView
+	}
+	broadcastID := broadcast.ID
+
+	var lastID uint
+	delivered := 0
+	for {
+		var userIDs []uint
+		err := broadcastRecipients(db, segment).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(broadcastBatchSize).
+			Pluck("id", &userIDs).Error
+		if err != nil {
+			s.finishBroadcast(broadcastID, delivered, err)
+			return
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		messages := make([]models.Message, 0, len(userIDs))
+		for _, userID := range userIDs {
+			messages = append(messages, models.Message{
+				UserID:       userID,
+				Type:         common.MessageTypeSystemBroadcast,
+				Data:         string(data),
+				Priority:     broadcast.Priority,
+				RelatedType:  "broadcast",
+				IsActionable: actionType != "",
+				ActionType:   actionType,
+				ActionURL:    broadcast.ActionURL,
+				ActionText:   broadcast.ActionText,
+				BroadcastID:  &broadcastID,
+			})
+		}
+		if err := db.CreateInBatches(messages, broadcastBatchSize).Error; err != nil {
+			s.finishBroadcast(broadcastID, delivered, err)
+			return
+		}
+		for _, userID := range userIDs {
+			s.clearUnreadCountCache(userID)
+		}
+
+		delivered += len(userIDs)
+		lastID = userIDs[len(userIDs)-1]
+		db.Model(&models.MessageBroadcast{}).Where("id = ?", broadcastID).Update("delivered_count", delivered)
+	}
+
+	s.finishBroadcast(broadcastID, delivered, nil)
+}
+
+func (s *MessageService) finishBroadcast(id uint, delivered int, deliverErr error) {
+	now := common.JSONTime(time.Now())
+	updates := map[string]interface{}{
+		"status":          models.MessageBroadcastCompleted,
+		"delivered_count": delivered,
+		"completed_at":    &now,
+	}
+	if deliverErr != nil {
+		logger.Error("群发消息投递失败: broadcastID=%d, 已送达=%d, error=%v", id, delivered, deliverErr)
+		msg := deliverErr.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		updates["status"] = models.MessageBroadcastFailed
+		updates["error"] = msg
+	} else {
+		logger.Info("群发消息投递完成: broadcastID=%d, 送达=%d", id, delivered)
+	}
+	database.GetDB().Model(&models.MessageBroadcast{}).Where("id = ?", id).Updates(updates)
+}
+
+// broadcastRecipients 构造接收人查询，只发送给状态正常的用户
+func broadcastRecipients(db *gorm.DB, segment BroadcastSegment) *gorm.DB {
+	query := db.Model(&models.User{}).Where("status = ?", common.UserStatusNormal)
+
+	if len(segment.Roles) > 0 || len(segment.GroupIDs) > 0 || len(segment.UserIDs) > 0 {
+		cond := db.Where("1 = 0")
+		if len(segment.Roles) > 0 {
+			cond = cond.Or("role IN ?", segment.Roles)
+		}
+		if len(segment.GroupIDs) > 0 {
+			cond = cond.Or("group_id IN ?", segment.GroupIDs)
+		}
+		if len(segment.UserIDs) > 0 {
+			cond = cond.Or("id IN ?", segment.UserIDs)
+		}
+		query = query.Where(cond)
+	}
+	if segment.ActiveWithinDays > 0 {
+		query = query.Where("last_activity_at >= ?", time.Now().AddDate(0, 0, -segment.ActiveWithinDays))
+	}
+	return query
+}
+
+func countBroadcastRecipients(segment BroadcastSegment) (int64, error) {
+	var count int64
+	if err := broadcastRecipients(database.GetDB(), segment).Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "统计接收用户失败")
+	}
+	return count, nil
+}
+
+// loadBroadcastStats 按群发ID汇总消息状态，已读以 read_at 为准，删除前已读的消息也计入已读
+func loadBroadcastStats(ids []uint) (map[uint]BroadcastStats, error) {
+	result := make(map[uint]BroadcastStats)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		BroadcastID uint
+		Delivered   int64
+		ReadCount   int64
+		Unread      int64
+		Deleted     int64
+	}
+	err := database.GetDB().Model(&models.Message{}).
+		Select("broadcast_id, COUNT(*) AS delivered, "+
+			"SUM(CASE WHEN read_at IS NOT NULL THEN 1 ELSE 0 END) AS read_count, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS unread, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS deleted",
+			common.MessageStatusUnread, common.MessageStatusDeleted).
+		Where("broadcast_id IN ?", ids).
+		Group("broadcast_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计群发已读情况失败")
+	}
+
+	for _, row := range rows {
+		stats := BroadcastStats{
+			Delivered: row.Delivered,
+			Read:      row.ReadCount,
+			Unread:    row.Unread,
+			Deleted:   row.Deleted,
+		}
+		if row.Delivered > 0 {
+			stats.ReadRate = float64(row.ReadCount) / float64(row.Delivered)
+		}
+		result[row.BroadcastID] = stats
+	}
+	return result, nil
+}
+
+func toBroadcastResponse(b models.MessageBroadcast, stats BroadcastStats) BroadcastResponse {
+	resp := BroadcastResponse{MessageBroadcast: b, Stats: stats}
+	if b.Segment != "" {
+		_ = json.Unmarshal([]byte(b.Segment), &resp.Segment)
+	}
+	return resp
+}
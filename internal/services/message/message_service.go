@@ -310,6 +310,18 @@ func (s *MessageService) GetMessageTemplate(templateType string) (*models.Messag
 	return &template, nil
 }
 
+// RenderTemplate 渲染模板标题和内容，供不产生站内消息的邮件（如发给站外邮箱的邀请）复用模板配置
+func (s *MessageService) RenderTemplate(templateType string, variables map[string]interface{}) (string, string, error) {
+	template, err := s.GetMessageTemplate(templateType)
+	if err != nil {
+		return "", "", err
+	}
+	if !template.IsTemplateEnabled() {
+		return "", "", errors.New(errors.CodeForbidden, "消息模板已停用: "+templateType)
+	}
+	return s.processTemplate(template.Title, variables), s.processTemplate(template.Content, variables), nil
+}
+
 // processTemplate 处理模板变量替换
 func (s *MessageService) processTemplate(templateStr string, variables map[string]interface{}) string {
 	// 使用Go模板引擎处理条件语句和变量替换
@@ -167,6 +167,34 @@ func (s *TemplateService) InitDefaultTemplates() error {
 			DefaultActionStyle: "secondary",
 			ActionURLTemplate:  "/review-details/{{.review_id}}",
 		},
+		{
+			Type:               common.MessageTypeContentAppealApproved,
+			Title:              "申诉已通过",
+			Content:            "您对文件 \"{{.file_name}}\" 的申诉已通过，文件已恢复。{{if .response}}处理意见：{{.response}}{{end}}",
+			Description:        "审核申诉通过通知",
+			IsEnabled:          true,
+			SendEmail:          false,
+			ShowToast:          true,
+			ToastType:          "success",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "查看文件",
+			DefaultActionStyle: "primary",
+			ActionURLTemplate:  "/files/{{.file_id}}",
+		},
+		{
+			Type:               common.MessageTypeContentAppealDenied,
+			Title:              "申诉未通过",
+			Content:            "很抱歉，您对文件 \"{{.file_name}}\" 的申诉未通过，维持原审核结果。{{if .response}}处理意见：{{.response}}{{end}}",
+			Description:        "审核申诉驳回通知",
+			IsEnabled:          true,
+			SendEmail:          false,
+			ShowToast:          true,
+			ToastType:          "error",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "查看详情",
+			DefaultActionStyle: "secondary",
+			ActionURLTemplate:  "/review-details/{{.review_log_id}}",
+		},
 		{
 			Type:               common.MessageTypeContentReviewPending,
 			Title:              "文件进入审核",
@@ -209,6 +237,30 @@ func (s *TemplateService) InitDefaultTemplates() error {
 			DefaultActionStyle: "primary",
 			ActionURLTemplate:  "/stats/bandwidth",
 		},
+		{
+			Type:               common.MessageTypeAccountQuotaGranted,
+			Title:              "配额调整",
+			Content:            "管理员为您增加了{{.quota_name}}：+{{.amount}}，{{.expire_text}}。原因：{{.reason}}",
+			Description:        "临时或永久增加存储、流量、上传配额的通知",
+			IsEnabled:          true,
+			SendEmail:          false,
+			ShowToast:          true,
+			ToastType:          "success",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "查看配额记录",
+			DefaultActionStyle: "primary",
+			ActionURLTemplate:  "/storage/overview",
+		},
+		{
+			Type:        common.MessageTypeSystemBroadcast,
+			Title:       "{{.title}}",
+			Content:     "{{.content}}",
+			Description: "管理员群发的站内通知",
+			IsEnabled:   true,
+			SendEmail:   false,
+			ShowToast:   true,
+			ToastType:   "info",
+		},
 		{
 			Type:               common.MessageTypeSystemMaintenance,
 			Title:              "系统维护通知",
@@ -450,7 +502,7 @@ func (s *TemplateService) InitDefaultTemplates() error {
 		{
 			Type:               common.MessageTypeShareExpiryWarning,
 			Title:              "分享即将过期",
-			Content:            "您的分享「{{.share_name}}」将于 {{.expires_in}} 后过期。过期后，访问链接将失效，请及时续期或备份。",
+			Content:            "您的分享「{{.share_name}}」将于 {{.expires_in}} 后过期。过期后，访问链接将失效，请及时续期或备份。{{if .extend_url}}点击链接可一键续期 {{.extend_days}} 天：{{.extend_url}}{{end}}",
 			Description:        "分享过期提醒通知",
 			IsEnabled:          true,
 			SendEmail:          false,
@@ -461,6 +513,62 @@ func (s *TemplateService) InitDefaultTemplates() error {
 			DefaultActionStyle: "secondary",
 			ActionURLTemplate:  "/admin/shares",
 		},
+		{
+			Type:               common.MessageTypeShareInvitation,
+			Title:              "{{.sender_name}} 邀请您查看分享「{{.share_name}}」",
+			Content:            "{{.sender_name}} 在 {{.site_name}} 与您分享了「{{.share_name}}」。{{if .message}}<br/>附言：{{.message}}{{end}}<br/>点击链接查看：<a href=\"{{.invite_url}}\">{{.invite_url}}</a>{{if .expired_at}}<br/>该分享将于 {{.expired_at}} 失效。{{end}}",
+			Description:        "分享邀请邮件，发送给受邀的邮箱，不产生站内消息",
+			IsEnabled:          true,
+			SendEmail:          true,
+			ShowToast:          false,
+			ToastType:          "info",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "查看分享",
+			DefaultActionStyle: "primary",
+			ActionURLTemplate:  "{{.invite_url}}",
+		},
+		{
+			Type:               common.MessageTypeSecurityEmailChangeCode,
+			Title:              "{{.site_name}} 更换邮箱验证码",
+			Content:            "您好，{{.username}}：<br/>您正在将 {{.site_name}} 账号的邮箱更换为本邮箱，验证码为 <b>{{.code}}</b>，{{.expire_minutes}}分钟内有效。<br/>如果这不是您本人的操作，请忽略此邮件。",
+			Description:        "更换邮箱验证码邮件，发送到新邮箱，不产生站内消息",
+			IsEnabled:          true,
+			SendEmail:          true,
+			ShowToast:          false,
+			ToastType:          "info",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "",
+			DefaultActionStyle: "primary",
+			ActionURLTemplate:  "",
+		},
+		{
+			Type:               common.MessageTypeSecurityEmailChanged,
+			Title:              "{{.site_name}} 账号邮箱已更换",
+			Content:            "您好，{{.username}}：<br/>您的 {{.site_name}} 账号邮箱已于 {{.changed_at}} 由 {{.old_email}} 更换为 {{.new_email}}，此后通知将发送到新邮箱。<br/>如果这不是您本人的操作，请立即联系管理员。",
+			Description:        "邮箱更换通知邮件，发送到原邮箱，不产生站内消息",
+			IsEnabled:          true,
+			SendEmail:          true,
+			ShowToast:          false,
+			ToastType:          "warning",
+			DefaultActionType:  common.ActionTypeView,
+			DefaultActionText:  "",
+			DefaultActionStyle: "secondary",
+			ActionURLTemplate:  "",
+		},
+		{
+			Type:               common.MessageTypeSecurityLoginAlert,
+			Title:              "账号在{{.reason}}登录",
+			Content:            "您的账号于 {{.login_time}} 在{{.reason}}登录。<br/>设备：{{.device_name}}<br/>IP：{{.ip}}{{if .location}}（{{.location}}）{{end}}<br/>如果这不是您本人的操作，请点击 <a href=\"{{.lock_url}}\">这不是我，锁定账号</a>，所有设备将立即退出登录，账号需联系管理员解锁。该链接 {{.expire_days}} 天内有效。",
+			Description:        "新设备或新国家登录提醒，附带一键锁定账号链接",
+			IsEnabled:          true,
+			SendEmail:          true,
+			ShowToast:          true,
+			ToastType:          "warning",
+			DefaultActionType:  common.ActionTypeManage,
+			DefaultActionText:  "这不是我，锁定账号",
+			DefaultActionStyle: "warning",
+			ActionURLTemplate:  "{{.lock_url}}",
+		},
 	}
 
 	for _, template := range templates {
@@ -0,0 +1,254 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	messageService "pixelpunk/internal/services/message"
+	userGroupService "pixelpunk/internal/services/user_group"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	bonusCacheKey = "quota_grant_bonus:%d"
+	bonusCacheTTL = 5 * time.Minute
+)
+
+/* GrantInput 追加配额的参数 */
+type GrantInput struct {
+	UserID    uint
+	QuotaType string
+	Amount    int64
+	Reason    string
+	ExpiresAt *time.Time
+}
+
+/* ListParams 配额记录查询参数 */
+type ListParams struct {
+	UserID    uint
+	QuotaType string
+	Status    string
+	Page      int
+	Size      int
+}
+
+// Bonus 用户当前生效的追加配额合计
+type Bonus struct {
+	Storage   int64 `json:"storage"`
+	Bandwidth int64 `json:"bandwidth"`
+	Upload    int64 `json:"upload"`
+}
+
+/* Grant 为用户追加配额并通知用户 */
+func Grant(adminID uint, input GrantInput) (*models.UserQuotaGrant, error) {
+	if !isValidType(input.QuotaType) {
+		return nil, errors.New(errors.CodeInvalidParameter, "无效的配额类型")
+	}
+	if input.Amount <= 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "追加量必须大于0")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, errors.New(errors.CodeInvalidParameter, "请填写调整原因")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errors.New(errors.CodeInvalidParameter, "到期时间必须晚于当前时间")
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.Select("id").First(&user, input.UserID).Error; err != nil {
+		return nil, errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+
+	grant := &models.UserQuotaGrant{
+		UserID:    input.UserID,
+		QuotaType: input.QuotaType,
+		Amount:    input.Amount,
+		Reason:    reason,
+		GrantedBy: adminID,
+	}
+	if input.ExpiresAt != nil {
+		expiresAt := common.JSONTime(*input.ExpiresAt)
+		grant.ExpiresAt = &expiresAt
+	}
+	if err := db.Create(grant).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "追加配额失败")
+	}
+
+	clearBonusCache(input.UserID)
+	notifyGrant(grant)
+	logger.Info("管理员追加用户配额: adminID=%d, userID=%d, type=%s, amount=%d", adminID, input.UserID, input.QuotaType, input.Amount)
+	return grant, nil
+}
+
+/* Revoke 提前撤销一条仍在生效的追加配额 */
+func Revoke(adminID, grantID uint, reason string) (*models.UserQuotaGrant, error) {
+	db := database.GetDB()
+
+	var grant models.UserQuotaGrant
+	if err := db.First(&grant, grantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "配额记录不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询配额记录失败")
+	}
+	if grant.Status() != models.QuotaGrantStatusActive {
+		return nil, errors.New(errors.CodeInvalidRequest, "该配额已失效，无需撤销")
+	}
+
+	now := common.JSONTimeNow()
+	if err := db.Model(&grant).Updates(map[string]interface{}{
+		"revoked_at":    now,
+		"revoked_by":    adminID,
+		"revoke_reason": strings.TrimSpace(reason),
+	}).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "撤销配额失败")
+	}
+
+	clearBonusCache(grant.UserID)
+	logger.Info("管理员撤销用户配额: adminID=%d, grantID=%d, userID=%d", adminID, grantID, grant.UserID)
+	return &grant, nil
+}
+
+/* List 查询配额调整历史，UserID 为0时查询全部用户 */
+func List(params ListParams) ([]models.UserQuotaGrant, int64, error) {
+	query := database.GetDB().Model(&models.UserQuotaGrant{})
+	if params.UserID > 0 {
+		query = query.Where("user_id = ?", params.UserID)
+	}
+	if params.QuotaType != "" {
+		query = query.Where("quota_type = ?", params.QuotaType)
+	}
+
+	now := time.Now()
+	switch params.Status {
+	case models.QuotaGrantStatusActive:
+		query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now)
+	case models.QuotaGrantStatusExpired:
+		query = query.Where("revoked_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?", now)
+	case models.QuotaGrantStatusRevoked:
+		query = query.Where("revoked_at IS NOT NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询配额记录失败")
+	}
+
+	var grants []models.UserQuotaGrant
+	if err := query.
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "username") }).
+		Preload("Granter", func(db *gorm.DB) *gorm.DB { return db.Select("id", "username") }).
+		Order("id DESC").Offset((params.Page - 1) * params.Size).Limit(params.Size).
+		Find(&grants).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询配额记录失败")
+	}
+	return grants, total, nil
+}
+
+/* GetActiveBonus 获取用户当前生效的追加配额（带缓存，缓存不会跨越最近一条记录的到期时间） */
+func GetActiveBonus(userID uint) Bonus {
+	key := fmt.Sprintf(bonusCacheKey, userID)
+	if cached, err := cache.Get(key); err == nil && cached != "" {
+		var bonus Bonus
+		if err := json.Unmarshal([]byte(cached), &bonus); err == nil {
+			return bonus
+		}
+	}
+
+	now := time.Now()
+	var grants []models.UserQuotaGrant
+	database.GetDB().Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Find(&grants)
+
+	var bonus Bonus
+	ttl := bonusCacheTTL
+	for _, g := range grants {
+		switch g.QuotaType {
+		case models.QuotaTypeStorage:
+			bonus.Storage += g.Amount
+		case models.QuotaTypeBandwidth:
+			bonus.Bandwidth += g.Amount
+		case models.QuotaTypeUpload:
+			bonus.Upload += g.Amount
+		}
+		if g.ExpiresAt != nil {
+			if remaining := time.Time(*g.ExpiresAt).Sub(now); remaining < ttl {
+				ttl = remaining
+			}
+		}
+	}
+
+	if data, err := json.Marshal(bonus); err == nil && ttl > time.Second {
+		cache.Set(key, string(data), ttl)
+	}
+	return bonus
+}
+
+/* ApplyLimits 计算用户的有效存储与流量限额：用户组配额优先，再叠加追加配额 */
+func ApplyLimits(userID uint, settings *models.UserSettings) {
+	userGroupService.ApplyGroupLimits(userID, settings)
+	bonus := GetActiveBonus(userID)
+	settings.StorageLimit += bonus.Storage
+	settings.BandwidthLimit += bonus.Bandwidth
+}
+
+/* GetDailyUploadLimit 返回用户的每日上传数限制，不限制(-1)时保持不变 */
+func GetDailyUploadLimit(userID uint, globalLimit int) int {
+	limit := userGroupService.GetDailyUploadLimit(userID, globalLimit)
+	if limit < 0 {
+		return limit
+	}
+	return limit + int(GetActiveBonus(userID).Upload)
+}
+
+func isValidType(quotaType string) bool {
+	switch quotaType {
+	case models.QuotaTypeStorage, models.QuotaTypeBandwidth, models.QuotaTypeUpload:
+		return true
+	}
+	return false
+}
+
+func clearBonusCache(userID uint) {
+	cache.Del(fmt.Sprintf(bonusCacheKey, userID))
+}
+
+func notifyGrant(grant *models.UserQuotaGrant) {
+	names := map[string]string{
+		models.QuotaTypeStorage:   "存储空间",
+		models.QuotaTypeBandwidth: "每月流量",
+		models.QuotaTypeUpload:    "每日上传数",
+	}
+	amount := fmt.Sprintf("%d 次", grant.Amount)
+	if grant.QuotaType != models.QuotaTypeUpload {
+		amount = utils.FormatBytes(grant.Amount)
+	}
+	expireText := "长期有效"
+	if grant.ExpiresAt != nil {
+		expireText = "有效期至 " + time.Time(*grant.ExpiresAt).Format("2006-01-02 15:04")
+	}
+
+	variables := map[string]interface{}{
+		"quota_name":   names[grant.QuotaType],
+		"amount":       amount,
+		"expire_text":  expireText,
+		"reason":       grant.Reason,
+		"related_type": "quota",
+		"related_id":   fmt.Sprintf("%d", grant.ID),
+	}
+	if err := messageService.GetMessageService().SendTemplateMessage(grant.UserID, common.MessageTypeAccountQuotaGranted, variables); err != nil {
+		logger.Warn("发送配额调整通知失败: userID=%d, error=%v", grant.UserID, err)
+	}
+}
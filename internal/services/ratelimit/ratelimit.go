@@ -0,0 +1,203 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const settingGroup = "rate_limit"
+
+// 限流策略
+const (
+	PolicyGlobal    = "global"     // 所有 API 请求
+	PolicyAuth      = "auth"       // 登录、注册、找回密码等认证接口
+	PolicySearch    = "search"     // 搜索接口
+	PolicyRandomAPI = "random_api" // 公开随机图片接口
+)
+
+// policyDefaults 各策略默认值：每分钟补充的令牌数与桶容量，速率为 0 表示不限制
+var policyDefaults = map[string][2]int{
+	PolicyGlobal:    {0, 0},
+	PolicyAuth:      {20, 10},
+	PolicySearch:    {60, 20},
+	PolicyRandomAPI: {120, 60},
+}
+
+// Policy 限流策略配置
+type Policy struct {
+	Name      string
+	PerMinute int // 每分钟补充的令牌数
+	Burst     int // 桶容量，即允许的突发请求数
+}
+
+// Result 单次请求的限流结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // 令牌桶补满所需时间
+	RetryAfter time.Duration // 被拒绝时，下一个令牌可用前需等待的时间
+}
+
+/* GetPolicy 读取策略配置，未启用限流或速率为 0 时返回 nil */
+func GetPolicy(name string) *Policy {
+	if !setting.GetBool(settingGroup, "rate_limit_enabled", true) {
+		return nil
+	}
+	defaults := policyDefaults[name]
+	p := &Policy{
+		Name:      name,
+		PerMinute: setting.GetInt(settingGroup, name+"_per_minute", defaults[0]),
+		Burst:     setting.GetInt(settingGroup, name+"_burst", defaults[1]),
+	}
+	if p.PerMinute <= 0 {
+		return nil
+	}
+	if p.Burst <= 0 {
+		p.Burst = p.PerMinute
+	}
+	return p
+}
+
+/* Take 从 subject（如 ip:1.2.3.4、user:42）对应的令牌桶中取一个令牌，Redis 不可用时使用进程内令牌桶 */
+func Take(p *Policy, subject string) Result {
+	key := fmt.Sprintf("ratelimit:%s:%s", p.Name, subject)
+	ratePerMs := float64(p.PerMinute) / float64(time.Minute/time.Millisecond)
+	now := time.Now().UnixMilli()
+
+	var allowed bool
+	var tokens float64
+	if client := cache.GetRedisClient(); client != nil {
+		var err error
+		allowed, tokens, err = takeRedis(client, cache.GetNamespace()+":"+key, ratePerMs, p.Burst, now)
+		if err != nil {
+			// Redis 故障时降级为进程内限流，不阻断请求
+			logger.Warn("Redis限流失败，降级为本地限流: %v", err)
+			allowed, tokens = local.take(key, ratePerMs, p.Burst, now)
+		}
+	} else {
+		allowed, tokens = local.take(key, ratePerMs, p.Burst, now)
+	}
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     p.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     msDuration((float64(p.Burst) - tokens) / ratePerMs),
+	}
+	if !allowed {
+		result.RetryAfter = msDuration((1 - tokens) / ratePerMs)
+	}
+	return result
+}
+
+func msDuration(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(ms)) * time.Millisecond
+}
+
+// luaTokenBucket 原子地补充并扣减令牌，返回是否放行及剩余令牌（字符串形式保留小数）
+var luaTokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local data = redis.call('HMGET', KEYS[1], 't', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 't', tostring(tokens), 'ts', tostring(math.max(now, ts)))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+func takeRedis(client redis.UniversalClient, key string, ratePerMs float64, burst int, now int64) (bool, float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := luaTokenBucket.Run(ctx, client, []string{key}, ratePerMs, burst, now).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("限流脚本返回值异常")
+	}
+	allowed, _ := res[0].(int64)
+	tokenStr, _ := res[1].(string)
+	tokens, _ := strconv.ParseFloat(tokenStr, 64)
+	return allowed == 1, tokens, nil
+}
+
+// localBuckets 进程内令牌桶，未启用 Redis 或 Redis 故障时使用
+type localBuckets struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	ts     int64
+	expire int64
+}
+
+var local = &localBuckets{buckets: make(map[string]*bucket)}
+
+func (l *localBuckets) take(key string, ratePerMs float64, burst int, now int64) (bool, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), ts: now}
+		l.buckets[key] = b
+	}
+	if now > b.ts {
+		b.tokens = math.Min(float64(burst), b.tokens+float64(now-b.ts)*ratePerMs)
+		b.ts = now
+	}
+	b.expire = now + int64(math.Ceil(float64(burst)/ratePerMs))
+
+	if b.tokens < 1 {
+		return false, b.tokens
+	}
+	b.tokens--
+	return true, b.tokens
+}
+
+// sweep 每分钟清理一次已补满的桶，避免按 IP 建立的桶无限增长
+func (l *localBuckets) sweep(now int64) {
+	if time.Since(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = time.Now()
+	for key, b := range l.buckets {
+		if now > b.expire {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	messageService "pixelpunk/internal/services/message"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+const reviewAppealWindow = 30 * 24 * time.Hour // 拒绝后可申诉的期限
+
+/* ResolveAppealReviewLog 根据消息中心的审核拒绝通知找到对应的拒绝记录，旧消息没有记录ID时按文件查找最近一次拒绝 */
+func ResolveAppealReviewLog(userID uint, messageID string) (uint, error) {
+	var msg models.Message
+	if err := database.GetDB().Where("id = ? AND user_id = ? AND type = ?", messageID, userID, common.MessageTypeContentReviewRejected).
+		First(&msg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, errors.New(errors.CodeNotFound, "审核通知不存在")
+		}
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询审核通知失败")
+	}
+
+	var related struct {
+		ReviewLogID uint `json:"review_log_id"`
+	}
+	if err := json.Unmarshal([]byte(msg.RelatedData), &related); err == nil && related.ReviewLogID > 0 {
+		return related.ReviewLogID, nil
+	}
+
+	var log models.ReviewLog
+	if err := database.GetDB().Where("file_id = ? AND uploader_id = ? AND action = ?", msg.RelatedID, userID, "reject").
+		Order("created_at DESC").First(&log).Error; err != nil {
+		return 0, errors.New(errors.CodeNotFound, "未找到对应的审核记录")
+	}
+	return log.ID, nil
+}
+
+/* CreateReviewAppeal 上传者对审核拒绝发起申诉，仅软删除且在申诉期限内的文件可以申诉 */
+func CreateReviewAppeal(userID, reviewLogID uint, reason string) (*models.ReviewAppeal, error) {
+	var log models.ReviewLog
+	if err := database.GetDB().Where("id = ? AND uploader_id = ? AND action = ?", reviewLogID, userID, "reject").
+		First(&log).Error; err != nil {
+		return nil, errors.New(errors.CodeNotFound, "审核记录不存在")
+	}
+	if log.DeleteType == "hard" {
+		return nil, errors.New(errors.CodeForbidden, "文件已被永久删除，无法申诉")
+	}
+	if time.Since(time.Time(log.CreatedAt)) > reviewAppealWindow {
+		return nil, errors.New(errors.CodeForbidden, "已超过申诉期限")
+	}
+
+	var file models.File
+	if err := database.GetDB().Select("id, status").Where("id = ?", log.FileID).First(&file).Error; err != nil || file.Status != "deleted" {
+		return nil, errors.New(errors.CodeForbidden, "文件当前状态不支持申诉")
+	}
+
+	var count int64
+	database.GetDB().Model(&models.ReviewAppeal{}).Where("review_log_id = ?", reviewLogID).Count(&count)
+	if count > 0 {
+		return nil, errors.New(errors.CodeConflict, "该审核记录已申诉过")
+	}
+
+	appeal := &models.ReviewAppeal{
+		ReviewLogID: reviewLogID,
+		FileID:      log.FileID,
+		UserID:      userID,
+		Reason:      reason,
+		Status:      models.ReviewAppealStatusPending,
+	}
+	if err := database.GetDB().Create(appeal).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "提交申诉失败")
+	}
+	return appeal, nil
+}
+
+/* GetUserReviewAppeals 获取用户自己的申诉记录 */
+func GetUserReviewAppeals(userID uint, page, pageSize int) ([]models.ReviewAppeal, int64, error) {
+	return queryReviewAppeals(database.GetDB().Where("user_id = ?", userID), page, pageSize)
+}
+
+/* GetReviewAppeals 管理端申诉队列，status 为空时返回全部 */
+func GetReviewAppeals(status string, page, pageSize int) ([]models.ReviewAppeal, int64, error) {
+	db := database.GetDB()
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	return queryReviewAppeals(db.Preload("User", func(tx *gorm.DB) *gorm.DB {
+		return tx.Select("id, username, email")
+	}).Preload("ReviewLog.Auditor", func(tx *gorm.DB) *gorm.DB {
+		return tx.Select("id, username")
+	}), page, pageSize)
+}
+
+func queryReviewAppeals(db *gorm.DB, page, pageSize int) ([]models.ReviewAppeal, int64, error) {
+	var appeals []models.ReviewAppeal
+	var total int64
+
+	if err := db.Model(&models.ReviewAppeal{}).Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询申诉记录失败")
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Preload("ReviewLog").
+		Preload("File").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&appeals).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询申诉记录失败")
+	}
+	return appeals, total, nil
+}
+
+/* ApproveReviewAppeal 申诉通过：恢复文件为正常状态并记录审核日志 */
+func ApproveReviewAppeal(appealID, handlerID uint, response string) error {
+	var file models.File
+	var reviewLogID uint
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		appeal, err := lockPendingAppeal(tx, appealID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ? AND status = ?", appeal.FileID, "deleted").First(&file).Error; err != nil {
+			return errors.New(errors.CodeFileNotFound, "文件已不存在，无法恢复")
+		}
+
+		if err := tx.Model(&models.File{}).Where("id = ?", file.ID).
+			Updates(map[string]interface{}{
+				"status":     "active",
+				"nsfw":       false,
+				"trashed_at": nil,
+			}).Error; err != nil {
+			return fmt.Errorf("恢复文件失败: %v", err)
+		}
+
+		reviewLog := &models.ReviewLog{
+			FileID:     file.ID,
+			AuditorID:  handlerID,
+			UploaderID: file.UserID,
+			Action:     "approve",
+			Reason:     "申诉通过: " + response,
+		}
+		if err := tx.Create(reviewLog).Error; err != nil {
+			return fmt.Errorf("创建审核记录失败: %v", err)
+		}
+		reviewLogID = appeal.ReviewLogID
+
+		return finishAppeal(tx, appealID, handlerID, models.ReviewAppealStatusApproved, response)
+	})
+	if err != nil {
+		return err
+	}
+
+	go vector.SyncFileMeta(file.ID)
+	go sendAppealNotification(file.UserID, file.ID, file.OriginalName, common.MessageTypeContentAppealApproved, response, reviewLogID)
+	return nil
+}
+
+/* DenyReviewAppeal 驳回申诉，维持原审核结果并通知上传者 */
+func DenyReviewAppeal(appealID, handlerID uint, response string) error {
+	var appeal *models.ReviewAppeal
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		if appeal, err = lockPendingAppeal(tx, appealID); err != nil {
+			return err
+		}
+		return finishAppeal(tx, appealID, handlerID, models.ReviewAppealStatusDenied, response)
+	})
+	if err != nil {
+		return err
+	}
+
+	var file models.File
+	database.GetDB().Select("id, original_name").Where("id = ?", appeal.FileID).First(&file)
+	go sendAppealNotification(appeal.UserID, appeal.FileID, file.OriginalName, common.MessageTypeContentAppealDenied, response, appeal.ReviewLogID)
+	return nil
+}
+
+func lockPendingAppeal(tx *gorm.DB, appealID uint) (*models.ReviewAppeal, error) {
+	var appeal models.ReviewAppeal
+	if err := tx.Where("id = ?", appealID).First(&appeal).Error; err != nil {
+		return nil, errors.New(errors.CodeNotFound, "申诉不存在")
+	}
+	if appeal.Status != models.ReviewAppealStatusPending {
+		return nil, errors.New(errors.CodeConflict, "该申诉已处理")
+	}
+	return &appeal, nil
+}
+
+func finishAppeal(tx *gorm.DB, appealID, handlerID uint, status, response string) error {
+	now := common.JSONTime(time.Now())
+	// 带上状态条件，避免并发处理同一申诉
+	result := tx.Model(&models.ReviewAppeal{}).
+		Where("id = ? AND status = ?", appealID, models.ReviewAppealStatusPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"handler_id": handlerID,
+			"response":   response,
+			"handled_at": now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("更新申诉状态失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeConflict, "该申诉已处理")
+	}
+	return nil
+}
+
+func sendAppealNotification(userID uint, fileID, fileName, messageType, response string, reviewLogID uint) {
+	variables := map[string]interface{}{
+		"file_id":       fileID,
+		"file_name":     fileName,
+		"response":      response,
+		"review_log_id": reviewLogID,
+		"related_type":  "file",
+		"related_id":    fileID,
+	}
+
+	msgService := messageService.GetMessageService()
+	if err := msgService.SendTemplateMessage(userID, messageType, variables); err != nil {
+		logger.Warn("发送申诉处理消息失败: userID=%d, fileID=%s, type=%s, error=%v", userID, fileID, messageType, err)
+	}
+}
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+)
+
+const autoApproveBatchSize = 200
+
+/* AutoApproveLowRiskFiles 自动通过超时无人处理的低风险待审核文件：
+ * 仅处理NSFW分数低于阈值、上传超过指定时长、没有人工审核记录且未被认领的文件
+ */
+func AutoApproveLowRiskFiles() (int, error) {
+	if !setting.GetBoolDirectFromDB("ai", "review_auto_approve_enabled", false) {
+		return 0, nil
+	}
+	maxScore := setting.GetFloatDirectFromDB("ai", "review_auto_approve_max_nsfw_score", 0.3)
+	afterHours := setting.GetIntDirectFromDB("ai", "review_auto_approve_after_hours", 24)
+	if maxScore <= 0 || afterHours <= 0 {
+		return 0, nil
+	}
+
+	db := database.GetDB()
+	cutoff := time.Now().Add(-time.Duration(afterHours) * time.Hour)
+
+	var fileIDs []string
+	query := db.Model(&models.File{}).
+		Joins("JOIN file_ai_info ON file_ai_info.file_id = file.id").
+		Where("file.status = ? AND file.created_at < ?", "pending_review", cutoff).
+		Where("file_ai_info.nsfw_score < ?", maxScore).
+		Where("file.id NOT IN (?)", db.Model(&models.ReviewLog{}).Select("file_id"))
+	if err := FilterReviewQueueByAssignee(query, ReviewAssignedNone, 0).
+		Order("file.created_at ASC").
+		Limit(autoApproveBatchSize).
+		Pluck("file.id", &fileIDs).Error; err != nil {
+		return 0, err
+	}
+
+	approved := 0
+	for _, fileID := range fileIDs {
+		if err := approveFileWithLog(fileID, 0, models.ReviewAuditorAuto, "超时未处理，NSFW分数低于自动通过阈值"); err != nil {
+			logger.Warn("自动通过待审核文件失败: fileID=%s, error=%v", fileID, err)
+			continue
+		}
+		approved++
+	}
+	return approved, nil
+}
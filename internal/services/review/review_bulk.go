@@ -0,0 +1,270 @@
+package review
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	reviewBulkBatchSize   = 200
+	reviewBulkMaxFailures = 200
+)
+
+// ReviewBulkFilter 批量审核的筛选条件，只作用于待审核文件
+type ReviewBulkFilter struct {
+	UploaderID   uint     `json:"uploader_id,omitempty"`
+	NSFWScoreMin *float64 `json:"nsfw_score_min,omitempty"` // 包含
+	NSFWScoreMax *float64 `json:"nsfw_score_max,omitempty"` // 不包含
+	Keyword      string   `json:"keyword,omitempty"`        // 文件名关键词
+	DateFrom     string   `json:"date_from,omitempty"`      // 上传日期 YYYY-MM-DD
+	DateTo       string   `json:"date_to,omitempty"`
+}
+
+var (
+	bulkJobsMu      sync.Mutex
+	runningBulkJobs = make(map[uint]bool) // 运行中的任务，值为 false 表示已请求取消
+)
+
+/* CountReviewBulkMatches 预览筛选条件匹配的待审核文件数量 */
+func CountReviewBulkMatches(filter ReviewBulkFilter) (int64, error) {
+	if err := validateReviewBulkFilter(filter); err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := buildReviewBulkQuery(filter).Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "统计匹配文件失败")
+	}
+	return count, nil
+}
+
+/* CreateReviewBulkJob 创建按筛选条件批量审核的后台任务，同一时间只允许一个任务运行 */
+func CreateReviewBulkJob(operatorID uint, filter ReviewBulkFilter, action, reason string, hardDelete bool) (*models.ReviewBulkJob, error) {
+	if err := validateReviewBulkFilter(filter); err != nil {
+		return nil, err
+	}
+	if action != "approve" && action != "reject" {
+		return nil, errors.New(errors.CodeInvalidParameter, "无效的审核操作")
+	}
+
+	total, err := CountReviewBulkMatches(filter)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "没有符合条件的待审核文件")
+	}
+
+	filterJSON, _ := json.Marshal(filter)
+	job := &models.ReviewBulkJob{
+		OperatorID: operatorID,
+		Action:     action,
+		Reason:     reason,
+		HardDelete: hardDelete && action == "reject",
+		Filter:     string(filterJSON),
+		Status:     models.ReviewBulkJobPending,
+		Total:      int(total),
+	}
+
+	bulkJobsMu.Lock()
+	defer bulkJobsMu.Unlock()
+	if len(runningBulkJobs) > 0 {
+		return nil, errors.New(errors.CodeConflict, "已有批量审核任务在运行，请稍后再试")
+	}
+	if err := database.GetDB().Create(job).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建批量审核任务失败")
+	}
+	runningBulkJobs[job.ID] = true
+
+	go runReviewBulkJob(*job, filter)
+	return job, nil
+}
+
+/* GetReviewBulkJobs 获取批量审核任务列表 */
+func GetReviewBulkJobs(page, pageSize int) ([]models.ReviewBulkJob, int64, error) {
+	var jobs []models.ReviewBulkJob
+	var total int64
+
+	db := database.GetDB().Model(&models.ReviewBulkJob{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询批量审核任务失败")
+	}
+	if err := db.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&jobs).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询批量审核任务失败")
+	}
+	for i := range jobs {
+		markStaleBulkJob(&jobs[i])
+	}
+	return jobs, total, nil
+}
+
+/* GetReviewBulkJob 获取单个批量审核任务及结果报告 */
+func GetReviewBulkJob(jobID uint) (*models.ReviewBulkJob, error) {
+	var job models.ReviewBulkJob
+	if err := database.GetDB().Where("id = ?", jobID).First(&job).Error; err != nil {
+		return nil, errors.New(errors.CodeNotFound, "批量审核任务不存在")
+	}
+	markStaleBulkJob(&job)
+	return &job, nil
+}
+
+/* CancelReviewBulkJob 请求取消运行中的任务，当前批次处理完后停止 */
+func CancelReviewBulkJob(jobID uint) error {
+	bulkJobsMu.Lock()
+	defer bulkJobsMu.Unlock()
+	if _, ok := runningBulkJobs[jobID]; !ok {
+		return errors.New(errors.CodeInvalidParameter, "任务未在运行")
+	}
+	runningBulkJobs[jobID] = false
+	return nil
+}
+
+func runReviewBulkJob(job models.ReviewBulkJob, filter ReviewBulkFilter) {
+	db := database.GetDB()
+	defer func() {
+		bulkJobsMu.Lock()
+		delete(runningBulkJobs, job.ID)
+		bulkJobsMu.Unlock()
+	}()
+
+	startedAt := common.JSONTime(time.Now())
+	db.Model(&models.ReviewBulkJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":     models.ReviewBulkJobRunning,
+		"started_at": startedAt,
+	})
+
+	failures := make(map[string]string)
+	processed, succeeded, failed := 0, 0, 0
+	status := models.ReviewBulkJobCompleted
+	lastID := ""
+
+	for {
+		if !isBulkJobActive(job.ID) {
+			status = models.ReviewBulkJobCancelled
+			break
+		}
+
+		// 按ID递增分批，处理失败仍停留在待审核状态的文件不会被重复处理
+		var fileIDs []string
+		if err := buildReviewBulkQuery(filter).Where("file.id > ?", lastID).
+			Order("file.id ASC").Limit(reviewBulkBatchSize).
+			Pluck("file.id", &fileIDs).Error; err != nil {
+			logger.Error("批量审核任务查询失败: jobID=%d, error=%v", job.ID, err)
+			job.Error = err.Error()
+			status = models.ReviewBulkJobFailed
+			break
+		}
+		if len(fileIDs) == 0 {
+			break
+		}
+		lastID = fileIDs[len(fileIDs)-1]
+
+		for _, fileID := range fileIDs {
+			var err error
+			if job.Action == "approve" {
+				err = ApproveFileWithLog(fileID, job.OperatorID, job.Reason)
+			} else {
+				err = RejectFileWithLog(fileID, job.OperatorID, job.Reason, job.HardDelete)
+			}
+			processed++
+			if err != nil {
+				failed++
+				if len(failures) < reviewBulkMaxFailures {
+					failures[fileID] = err.Error()
+				}
+			} else {
+				succeeded++
+			}
+		}
+
+		db.Model(&models.ReviewBulkJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"processed": processed,
+			"succeeded": succeeded,
+			"failed":    failed,
+		})
+	}
+
+	failuresJSON, _ := json.Marshal(failures)
+	finishedAt := common.JSONTime(time.Now())
+	db.Model(&models.ReviewBulkJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":      status,
+		"processed":   processed,
+		"succeeded":   succeeded,
+		"failed":      failed,
+		"failures":    string(failuresJSON),
+		"error":       job.Error,
+		"finished_at": finishedAt,
+	})
+	logger.Info("批量审核任务结束: jobID=%d, status=%s, 成功=%d, 失败=%d", job.ID, status, succeeded, failed)
+}
+
+func isBulkJobActive(jobID uint) bool {
+	bulkJobsMu.Lock()
+	defer bulkJobsMu.Unlock()
+	return runningBulkJobs[jobID]
+}
+
+// markStaleBulkJob 服务重启后遗留的运行中任务标记为失败
+func markStaleBulkJob(job *models.ReviewBulkJob) {
+	if job.Status != models.ReviewBulkJobRunning && job.Status != models.ReviewBulkJobPending {
+		return
+	}
+	bulkJobsMu.Lock()
+	_, running := runningBulkJobs[job.ID]
+	bulkJobsMu.Unlock()
+	if running {
+		return
+	}
+	job.Status = models.ReviewBulkJobFailed
+	job.Error = "服务重启导致任务中断"
+	database.GetDB().Model(&models.ReviewBulkJob{}).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"status": job.Status, "error": job.Error})
+}
+
+func validateReviewBulkFilter(filter ReviewBulkFilter) error {
+	for _, v := range []*float64{filter.NSFWScoreMin, filter.NSFWScoreMax} {
+		if v != nil && (*v < 0 || *v > 1) {
+			return errors.New(errors.CodeInvalidParameter, "NSFW分数范围必须在0-1之间")
+		}
+	}
+	if filter.NSFWScoreMin != nil && filter.NSFWScoreMax != nil && *filter.NSFWScoreMin >= *filter.NSFWScoreMax {
+		return errors.New(errors.CodeInvalidParameter, "NSFW分数下限必须小于上限")
+	}
+	return nil
+}
+
+func buildReviewBulkQuery(filter ReviewBulkFilter) *gorm.DB {
+	query := database.GetDB().Model(&models.File{}).Where("file.status = ?", "pending_review")
+
+	if filter.UploaderID > 0 {
+		query = query.Where("file.user_id = ?", filter.UploaderID)
+	}
+	if filter.NSFWScoreMin != nil || filter.NSFWScoreMax != nil {
+		query = query.Joins("JOIN file_ai_info ON file_ai_info.file_id = file.id")
+		if filter.NSFWScoreMin != nil {
+			query = query.Where("file_ai_info.nsfw_score >= ?", *filter.NSFWScoreMin)
+		}
+		if filter.NSFWScoreMax != nil {
+			query = query.Where("file_ai_info.nsfw_score < ?", *filter.NSFWScoreMax)
+		}
+	}
+	if filter.Keyword != "" {
+		keyword := "%" + filter.Keyword + "%"
+		query = query.Where("file.original_name LIKE ? OR file.display_name LIKE ?", keyword, keyword)
+	}
+	if filter.DateFrom != "" {
+		query = query.Where("DATE(file.created_at) >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		query = query.Where("DATE(file.created_at) <= ?", filter.DateTo)
+	}
+	return query
+}
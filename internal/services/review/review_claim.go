@@ -0,0 +1,146 @@
+package review
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewClaimTTL       = 15 * time.Minute // 认领锁定时长
+	maxReviewClaimBatch  = 50
+	ReviewAssignedMine   = "mine"      // 队列筛选：我认领的文件
+	ReviewAssignedNone   = "unclaimed" // 队列筛选：无人认领的文件
+	ReviewAssignedOthers = "others"    // 队列筛选：他人认领的文件
+)
+
+/* ClaimReviewFiles 认领指定的待审核文件，已被他人认领且未过期的文件不会被抢占；
+ * 未指定文件时按上传时间从队列中认领 count 个无人认领的文件
+ */
+func ClaimReviewFiles(fileIDs []string, count int, reviewerID uint) ([]models.ReviewClaim, map[string]string, error) {
+	if len(fileIDs) > maxReviewClaimBatch || count > maxReviewClaimBatch {
+		return nil, nil, errors.New(errors.CodeInvalidParameter, "单次最多认领50个文件")
+	}
+
+	db := database.GetDB()
+	if len(fileIDs) == 0 {
+		if count <= 0 {
+			return nil, nil, errors.New(errors.CodeInvalidParameter, "请指定要认领的文件或数量")
+		}
+		if err := FilterReviewQueueByAssignee(db.Model(&models.File{}).Where("status = ?", "pending_review"), ReviewAssignedNone, reviewerID).
+			Order("created_at ASC").Limit(count).Pluck("id", &fileIDs).Error; err != nil {
+			return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询审核队列失败")
+		}
+	}
+
+	failed := make(map[string]string)
+	claims := make([]models.ReviewClaim, 0, len(fileIDs))
+	expiresAt := common.JSONTime(time.Now().Add(ReviewClaimTTL))
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, fileID := range fileIDs {
+			var pending int64
+			if err := tx.Model(&models.File{}).Where("id = ? AND status = ?", fileID, "pending_review").Count(&pending).Error; err != nil {
+				return err
+			}
+			if pending == 0 {
+				failed[fileID] = "待审核文件不存在"
+				continue
+			}
+
+			var claim models.ReviewClaim
+			err := tx.Where("file_id = ?", fileID).First(&claim).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+			if err == nil && claim.AssignedTo != reviewerID && !claim.IsExpired() {
+				failed[fileID] = "已被其他审核员认领"
+				continue
+			}
+
+			claim = models.ReviewClaim{
+				FileID:     fileID,
+				CreatedAt:  common.JSONTime(time.Now()),
+				AssignedTo: reviewerID,
+				ExpiresAt:  expiresAt,
+			}
+			if err := tx.Save(&claim).Error; err != nil {
+				return err
+			}
+			claims = append(claims, claim)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "认领审核文件失败")
+	}
+	return claims, failed, nil
+}
+
+/* ReleaseReviewFiles 释放认领，force 为 true 时可释放他人的认领（仅管理员） */
+func ReleaseReviewFiles(fileIDs []string, reviewerID uint, force bool) (int64, error) {
+	if len(fileIDs) == 0 {
+		return 0, nil
+	}
+	db := database.GetDB().Where("file_id IN ?", fileIDs)
+	if !force {
+		db = db.Where("assigned_to = ?", reviewerID)
+	}
+	result := db.Delete(&models.ReviewClaim{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "释放认领失败")
+	}
+	return result.RowsAffected, nil
+}
+
+/* GetReviewClaims 获取文件当前有效的认领信息 */
+func GetReviewClaims(fileIDs []string) map[string]models.ReviewClaim {
+	claims := make(map[string]models.ReviewClaim)
+	if len(fileIDs) == 0 {
+		return claims
+	}
+	var list []models.ReviewClaim
+	database.GetDB().Preload("Assignee", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, username")
+	}).Where("file_id IN ? AND expires_at > ?", fileIDs, time.Now()).Find(&list)
+	for _, claim := range list {
+		claims[claim.FileID] = claim
+	}
+	return claims
+}
+
+/* FilterReviewQueueByAssignee 按认领情况筛选审核队列 */
+func FilterReviewQueueByAssignee(db *gorm.DB, assigned string, reviewerID uint) *gorm.DB {
+	activeClaims := database.GetDB().Model(&models.ReviewClaim{}).Select("file_id").Where("expires_at > ?", time.Now())
+	switch assigned {
+	case ReviewAssignedMine:
+		return db.Where("file.id IN (?)", activeClaims.Where("assigned_to = ?", reviewerID))
+	case ReviewAssignedOthers:
+		return db.Where("file.id IN (?)", activeClaims.Where("assigned_to <> ?", reviewerID))
+	case ReviewAssignedNone:
+		return db.Where("file.id NOT IN (?)", activeClaims)
+	}
+	return db
+}
+
+/* checkReviewClaim 文件被他人认领且未过期时拒绝审核 */
+func checkReviewClaim(tx *gorm.DB, fileID string, auditorID uint) error {
+	var claim models.ReviewClaim
+	if err := tx.Where("file_id = ?", fileID).First(&claim).Error; err != nil {
+		return nil
+	}
+	if claim.AssignedTo != auditorID && !claim.IsExpired() {
+		return errors.New(errors.CodeConflict, "该文件已被其他审核员认领")
+	}
+	return nil
+}
+
+/* clearReviewClaim 审核完成后移除认领 */
+func clearReviewClaim(tx *gorm.DB, fileID string) error {
+	return tx.Where("file_id = ?", fileID).Delete(&models.ReviewClaim{}).Error
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"math"
+	"strconv"
+
+	"pixelpunk/internal/models"
+	messageService "pixelpunk/internal/services/message"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+const (
+	reReviewBatchSize = 200
+	reReviewMaxPerRun = 5000
+)
+
+/* ReReviewBorderlineFiles NSFW阈值调低后，将分数处于临界区间的已通过文件重新放入审核队列，
+ * 使更严格的策略对历史文件生效。区间为 [新阈值, min(旧阈值, 新阈值+区间宽度))，
+ * 旧阈值以上的文件当初已由人工明确放行，不再重复审核。全部处理完后才记录新阈值，中断后下次继续。
+ */
+func ReReviewBorderlineFiles() (int, error) {
+	if !setting.GetBoolDirectFromDB("ai", "review_rereview_enabled", false) {
+		return 0, nil
+	}
+
+	threshold, _ := getNSFWThreshold()
+	applied, err := strconv.ParseFloat(setting.GetStringDirectFromDB("ai", "review_rereview_applied_threshold", ""), 64)
+	if err != nil || threshold >= applied {
+		// 首次运行或阈值未调严，只记录当前阈值
+		return 0, saveAppliedReReviewThreshold(threshold)
+	}
+
+	band := setting.GetFloatDirectFromDB("ai", "review_rereview_band", 0.1)
+	if band <= 0 {
+		return 0, saveAppliedReReviewThreshold(threshold)
+	}
+	upper := math.Min(applied, threshold+band)
+
+	db := database.GetDB()
+	requeued := 0
+	for requeued < reReviewMaxPerRun {
+		var files []models.File
+		if err := db.Model(&models.File{}).
+			Select("file.id, file.user_id, file.original_name").
+			Joins("JOIN file_ai_info ON file_ai_info.file_id = file.id").
+			Where("file.status = ?", "active").
+			Where("file_ai_info.nsfw_score >= ? AND file_ai_info.nsfw_score < ?", threshold, upper).
+			Limit(reReviewBatchSize).
+			Find(&files).Error; err != nil {
+			return requeued, err
+		}
+		if len(files) == 0 {
+			return requeued, saveAppliedReReviewThreshold(threshold)
+		}
+
+		for _, file := range files {
+			if err := requeueFileForReview(db, file); err != nil {
+				logger.Warn("重新审核文件入队失败: fileID=%s, error=%v", file.ID, err)
+				return requeued, err
+			}
+			requeued++
+		}
+	}
+	return requeued, nil
+}
+
+func requeueFileForReview(db *gorm.DB, file models.File) error {
+	result := db.Model(&models.File{}).
+		Where("id = ? AND status = ?", file.ID, "active").
+		Updates(map[string]interface{}{
+			"status": "pending_review",
+			"nsfw":   true,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	go vector.SyncFileMeta(file.ID)
+	go sendReReviewNotification(file.UserID, file.ID, file.OriginalName)
+	return nil
+}
+
+func sendReReviewNotification(userID uint, fileID, fileName string) {
+	variables := map[string]interface{}{
+		"file_id":      fileID,
+		"file_name":    fileName,
+		"nsfw_reason":  "内容安全策略调整后重新审核",
+		"related_type": "file",
+		"related_id":   fileID,
+	}
+	if err := messageService.GetMessageService().SendTemplateMessage(userID, common.MessageTypeContentReviewPending, variables); err != nil {
+		logger.Warn("发送重新审核消息失败: userID=%d, fileID=%s, error=%v", userID, fileID, err)
+	}
+}
+
+func saveAppliedReReviewThreshold(threshold float64) error {
+	return setting.UpdateSettingDirectToDB("ai", "review_rereview_applied_threshold", strconv.FormatFloat(threshold, 'f', -1, 64))
+}
@@ -2,21 +2,29 @@ package review
 
 import (
 	"fmt"
+	"time"
+
 	"pixelpunk/internal/models"
 	messageService "pixelpunk/internal/services/message"
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
 
 	"gorm.io/gorm"
 )
 
 /* ApproveFileWithLog 批准文件并记录审核日志 */
 func ApproveFileWithLog(fileID string, auditorID uint, reason string) error {
+	return approveFileWithLog(fileID, auditorID, models.ReviewAuditorManual, reason)
+}
+
+func approveFileWithLog(fileID string, auditorID uint, auditorType, reason string) error {
 	db := database.GetDB()
+	var event ModerationEvent
 
-	return db.Transaction(func(tx *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
 		var file models.File
 		if err := tx.Where("id = ? AND status = ?", fileID, "pending_review").First(&file).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -24,6 +32,9 @@ func ApproveFileWithLog(fileID string, auditorID uint, reason string) error {
 			}
 			return fmt.Errorf("查询待审核文件失败: %v", err)
 		}
+		if err := checkReviewClaim(tx, fileID, auditorID); err != nil {
+			return err
+		}
 
 		var nsfwScore, nsfwThreshold *float64
 		var isNSFW *bool
@@ -41,6 +52,7 @@ func ApproveFileWithLog(fileID string, auditorID uint, reason string) error {
 		reviewLog := &models.ReviewLog{
 			FileID:        fileID,
 			AuditorID:     auditorID,
+			AuditorType:   auditorType,
 			UploaderID:    file.UserID,
 			Action:        "approve",
 			Reason:        reason,
@@ -61,11 +73,32 @@ func ApproveFileWithLog(fileID string, auditorID uint, reason string) error {
 			}).Error; err != nil {
 			return fmt.Errorf("批准文件失败: %v", err)
 		}
+		if err := clearReviewClaim(tx, fileID); err != nil {
+			return fmt.Errorf("清除审核认领失败: %v", err)
+		}
 
-		go sendFileReviewNotification(file.UserID, fileID, file.OriginalName, "approve", "", auditorID)
-
+		go sendFileReviewNotification(file.UserID, fileID, file.OriginalName, "approve", "", auditorID, reviewLog.ID)
+
+		event = ModerationEvent{
+			Event:       ModerationEventApproved,
+			FileID:      fileID,
+			FileName:    file.OriginalName,
+			UploaderID:  file.UserID,
+			AuditorID:   auditorID,
+			AuditorType: auditorType,
+			Reason:      reason,
+			NSFWScore:   nsfwScore,
+		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// 审核通过会清除 NSFW 标记，同步到向量元数据
+	go vector.SyncFileMeta(fileID)
+	emitModerationEvent(event)
+	return nil
 }
 
 /* RejectFileWithLog 拒绝文件并记录审核日志（默认软删除） */
@@ -73,6 +106,8 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 	db := database.GetDB()
 
 	var fileToDelete models.File
+	var rejectLogID uint
+	var event ModerationEvent
 
 	// 使用 GORM Transaction 方法替代手动事务管理，确保 SQLite 兼容性
 	err := db.Transaction(func(tx *gorm.DB) error {
@@ -83,6 +118,9 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 			}
 			return fmt.Errorf("查询待审核文件失败: %v", err)
 		}
+		if err := checkReviewClaim(tx, fileID, auditorID); err != nil {
+			return err
+		}
 
 		// 保存文件信息用于后续删除
 		fileToDelete = file
@@ -120,6 +158,18 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 		if err := tx.Create(reviewLog).Error; err != nil {
 			return fmt.Errorf("创建审核记录失败: %v", err)
 		}
+		rejectLogID = reviewLog.ID
+		event = ModerationEvent{
+			Event:       ModerationEventRejected,
+			FileID:      fileID,
+			FileName:    file.OriginalName,
+			UploaderID:  file.UserID,
+			AuditorID:   auditorID,
+			AuditorType: models.ReviewAuditorManual,
+			DeleteType:  deleteType,
+			Reason:      reason,
+			NSFWScore:   nsfwScore,
+		}
 
 		if hardDelete {
 			// 硬删除：在事务中标记，然后在事务外执行删除
@@ -136,11 +186,15 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 			if err := tx.Model(&models.File{}).
 				Where("id = ?", fileID).
 				Updates(map[string]interface{}{
-					"status": "deleted",
+					"status":     "deleted",
+					"trashed_at": time.Now(),
 				}).Error; err != nil {
 				return fmt.Errorf("软删除文件失败: %v", err)
 			}
 		}
+		if err := clearReviewClaim(tx, fileID); err != nil {
+			return fmt.Errorf("清除审核认领失败: %v", err)
+		}
 
 		return nil
 	})
@@ -149,6 +203,8 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 		return err
 	}
 
+	emitModerationEvent(event)
+
 	// 在事务外执行硬删除操作（避免事务锁定）
 	if hardDelete {
 		// 使用 goroutine 异步执行硬删除，避免阻塞
@@ -156,11 +212,13 @@ func RejectFileWithLog(fileID string, auditorID uint, reason string, hardDelete
 			if err := executeFileHardDeletion(&fileToDelete); err != nil {
 				logger.Error("硬删除文件失败: fileID=%s, error=%v", fileID, err)
 			} else {
-				go sendFileReviewNotification(fileToDelete.UserID, fileID, fileToDelete.OriginalName, "reject", reason, auditorID)
+				go sendFileReviewNotification(fileToDelete.UserID, fileID, fileToDelete.OriginalName, "reject", reason, auditorID, rejectLogID)
+				event.Event = ModerationEventHardDeleted
+				emitModerationEvent(event)
 			}
 		}()
 	} else {
-		go sendFileReviewNotification(fileToDelete.UserID, fileID, fileToDelete.OriginalName, "reject", reason, auditorID)
+		go sendFileReviewNotification(fileToDelete.UserID, fileID, fileToDelete.OriginalName, "reject", reason, auditorID, rejectLogID)
 	}
 
 	return nil
@@ -238,6 +296,16 @@ func HardDeleteSoftDeletedFile(fileID string, operatorID uint) error {
 
 	go sendHardDeleteNotification(file.UserID, fileID, file.OriginalName, "管理员执行硬删除")
 
+	emitModerationEvent(ModerationEvent{
+		Event:       ModerationEventHardDeleted,
+		FileID:      fileID,
+		FileName:    file.OriginalName,
+		UploaderID:  file.UserID,
+		AuditorID:   operatorID,
+		AuditorType: models.ReviewAuditorManual,
+		DeleteType:  "hard",
+		Reason:      reviewLog.Reason,
+	})
 	return nil
 }
 
@@ -263,27 +331,6 @@ func GetReviewLogsByAuditorID(auditorID uint, page, pageSize int) ([]models.Revi
 	return logs, total, err
 }
 
-/* GetReviewStats 获取审核统计信息 */
-func GetReviewStats() (map[string]interface{}, error) {
-	var approveCount, rejectCount int64
-
-	db := database.GetDB().Model(&models.ReviewLog{})
-
-	if err := db.Where("action = ?", "approve").Count(&approveCount).Error; err != nil {
-		return nil, err
-	}
-
-	if err := db.Where("action = ?", "reject").Count(&rejectCount).Error; err != nil {
-		return nil, err
-	}
-
-	return map[string]interface{}{
-		"approve_count": approveCount,
-		"reject_count":  rejectCount,
-		"total_count":   approveCount + rejectCount,
-	}, nil
-}
-
 /* HardDeleteFile 强制硬删除文件 */
 func HardDeleteFile(fileID string, auditorID uint, reason string) error {
 	return RejectFileWithLog(fileID, auditorID, reason, true)
@@ -306,7 +353,8 @@ func RestoreSoftDeletedFile(fileID string, operatorID uint) error {
 		if err := tx.Model(&models.File{}).
 			Where("id = ?", fileID).
 			Updates(map[string]interface{}{
-				"status": "pending_review",
+				"status":     "pending_review",
+				"trashed_at": nil,
 			}).Error; err != nil {
 			return fmt.Errorf("恢复文件失败: %v", err)
 		}
@@ -366,13 +414,14 @@ func deletePhysicalFiles(file *models.File) error {
 	return nil
 }
 
-func sendFileReviewNotification(userID uint, fileID, fileName, action, reason string, auditorID uint) {
+func sendFileReviewNotification(userID uint, fileID, fileName, action, reason string, auditorID, reviewLogID uint) {
 	var messageType string
 	variables := map[string]interface{}{
-		"file_id":      fileID,
-		"file_name":    fileName,
-		"related_type": "file",
-		"related_id":   fileID,
+		"file_id":       fileID,
+		"file_name":     fileName,
+		"review_log_id": reviewLogID,
+		"related_type":  "file",
+		"related_id":    fileID,
 	}
 
 	switch action {
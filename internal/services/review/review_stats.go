@@ -0,0 +1,183 @@
+package review
+
+import (
+	"sort"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+)
+
+const (
+	defaultReviewStatsDays = 30
+	maxReviewStatsDays     = 365
+)
+
+// AuditorStats 单个审核员在统计区间内的表现
+type AuditorStats struct {
+	AuditorID         uint    `json:"auditor_id"`
+	AuditorType       string  `json:"auditor_type"`
+	Username          string  `json:"username"`
+	Total             int     `json:"total"`
+	ApproveCount      int     `json:"approve_count"`
+	RejectCount       int     `json:"reject_count"`
+	ApproveRatio      float64 `json:"approve_ratio"`
+	AvgLatencySeconds float64 `json:"avg_latency_seconds"` // 上传到审核决定的平均耗时
+	latencySum        float64
+	latencyCount      int
+}
+
+// DailyReviewStats 按天汇总的审核数量
+type DailyReviewStats struct {
+	Date         string `json:"date"`
+	ApproveCount int    `json:"approve_count"`
+	RejectCount  int    `json:"reject_count"`
+	Total        int    `json:"total"`
+}
+
+type auditorKey struct {
+	id          uint
+	auditorType string
+}
+
+type reviewStatsRow struct {
+	AuditorID   uint
+	AuditorType string
+	Action      string
+	CreatedAt   common.JSONTime
+	UploadedAt  *common.JSONTime
+}
+
+/* GetReviewStats 获取审核统计信息，包含总量、各审核员的处理量、通过率、平均决策耗时以及每日趋势 */
+func GetReviewStats(days int) (map[string]interface{}, error) {
+	if days <= 0 {
+		days = defaultReviewStatsDays
+	}
+	if days > maxReviewStatsDays {
+		days = maxReviewStatsDays
+	}
+
+	db := database.GetDB()
+
+	var approveCount, rejectCount int64
+	if err := db.Model(&models.ReviewLog{}).Where("action = ?", "approve").Count(&approveCount).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计审核记录失败")
+	}
+	if err := db.Model(&models.ReviewLog{}).Where("action = ?", "reject").Count(&rejectCount).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计审核记录失败")
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+
+	// 逐行汇总，耗时在应用层计算以兼容不同数据库的时间函数
+	rows, err := db.Table("review_log").
+		Select("review_log.auditor_id, review_log.auditor_type, review_log.action, review_log.created_at, file.created_at AS uploaded_at").
+		Joins("LEFT JOIN file ON file.id = review_log.file_id").
+		Where("review_log.created_at >= ?", start).
+		Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询审核记录失败")
+	}
+	defer rows.Close()
+
+	auditors := make(map[auditorKey]*AuditorStats)
+	daily := make(map[string]*DailyReviewStats, days)
+	for i := 0; i < days; i++ {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		daily[date] = &DailyReviewStats{Date: date}
+	}
+
+	for rows.Next() {
+		var row reviewStatsRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "读取审核记录失败")
+		}
+		if row.AuditorType == "" {
+			row.AuditorType = models.ReviewAuditorManual
+		}
+
+		key := auditorKey{id: row.AuditorID, auditorType: row.AuditorType}
+		stats, ok := auditors[key]
+		if !ok {
+			stats = &AuditorStats{AuditorID: row.AuditorID, AuditorType: row.AuditorType}
+			auditors[key] = stats
+		}
+		stats.Total++
+
+		day := daily[time.Time(row.CreatedAt).Format("2006-01-02")]
+		switch row.Action {
+		case "approve":
+			stats.ApproveCount++
+			if day != nil {
+				day.ApproveCount++
+			}
+		case "reject":
+			stats.RejectCount++
+			if day != nil {
+				day.RejectCount++
+			}
+		}
+		if day != nil {
+			day.Total++
+		}
+
+		if row.UploadedAt != nil {
+			latency := time.Time(row.CreatedAt).Sub(time.Time(*row.UploadedAt)).Seconds()
+			if latency >= 0 {
+				stats.latencySum += latency
+				stats.latencyCount++
+			}
+		}
+	}
+
+	auditorIDs := make([]uint, 0, len(auditors))
+	for _, stats := range auditors {
+		if stats.AuditorType == models.ReviewAuditorManual {
+			auditorIDs = append(auditorIDs, stats.AuditorID)
+		}
+	}
+	names := make(map[uint]string, len(auditorIDs))
+	if len(auditorIDs) > 0 {
+		var users []models.User
+		db.Select("id, username").Where("id IN ?", auditorIDs).Find(&users)
+		for _, u := range users {
+			names[u.ID] = u.Username
+		}
+	}
+
+	auditorList := make([]*AuditorStats, 0, len(auditors))
+	for _, stats := range auditors {
+		if stats.AuditorType == models.ReviewAuditorAuto {
+			stats.Username = "自动审核"
+		} else {
+			stats.Username = names[stats.AuditorID]
+		}
+		if stats.Total > 0 {
+			stats.ApproveRatio = float64(stats.ApproveCount) / float64(stats.Total)
+		}
+		if stats.latencyCount > 0 {
+			stats.AvgLatencySeconds = stats.latencySum / float64(stats.latencyCount)
+		}
+		auditorList = append(auditorList, stats)
+	}
+	sort.Slice(auditorList, func(i, j int) bool {
+		return auditorList[i].Total > auditorList[j].Total
+	})
+
+	trend := make([]*DailyReviewStats, 0, days)
+	for i := 0; i < days; i++ {
+		trend = append(trend, daily[start.AddDate(0, 0, i).Format("2006-01-02")])
+	}
+
+	return map[string]interface{}{
+		"approve_count": approveCount,
+		"reject_count":  rejectCount,
+		"total_count":   approveCount + rejectCount,
+		"days":          days,
+		"auditors":      auditorList,
+		"daily_trend":   trend,
+	}, nil
+}
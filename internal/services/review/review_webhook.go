@@ -0,0 +1,105 @@
+package review
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/internal/services/webhook"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	ModerationEventApproved    = "content.review.approved"
+	ModerationEventRejected    = "content.review.rejected"
+	ModerationEventHardDeleted = "content.file.hard_deleted"
+)
+
+var moderationWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// ModerationEvent 审核决定事件，推送给外部合规系统
+type ModerationEvent struct {
+	Event       string   `json:"event"`
+	FileID      string   `json:"file_id"`
+	FileName    string   `json:"file_name"`
+	UploaderID  uint     `json:"uploader_id"`
+	AuditorID   uint     `json:"auditor_id"`
+	AuditorType string   `json:"auditor_type"`
+	DeleteType  string   `json:"delete_type,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	NSFWScore   *float64 `json:"nsfw_score,omitempty"`
+	Timestamp   int64    `json:"timestamp"`
+}
+
+// emitModerationEvent 推送审核事件到订阅的 Webhook，并兼容旧的审核回调地址配置
+func emitModerationEvent(event ModerationEvent) {
+	event.Timestamp = time.Now().Unix()
+	webhook.Dispatch(event.Event, event)
+
+	url := setting.GetStringDirectFromDB("ai", "review_webhook_url", "")
+	if url == "" {
+		return
+	}
+	go deliverModerationEvent(url, event)
+}
+
+func deliverModerationEvent(url string, event ModerationEvent) {
+	var payload interface{} = event
+	if setting.GetStringDirectFromDB("ai", "review_webhook_format", "json") == "discord" {
+		payload = map[string]interface{}{"content": formatDiscordModerationMessage(event)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		logger.Warn("构建审核Webhook请求失败: event=%s err=%v", event.Event, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-PixelPunk-Event", event.Event)
+	if secret := setting.GetStringDirectFromDB("ai", "review_webhook_secret", ""); secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set("X-PixelPunk-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := moderationWebhookClient.Do(req)
+	if err != nil {
+		logger.Warn("审核Webhook推送失败: event=%s fileID=%s err=%v", event.Event, event.FileID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		logger.Warn("审核Webhook返回异常状态: event=%s fileID=%s status=%d", event.Event, event.FileID, resp.StatusCode)
+	}
+}
+
+func formatDiscordModerationMessage(event ModerationEvent) string {
+	action := map[string]string{
+		ModerationEventApproved:    "✅ 审核通过",
+		ModerationEventRejected:    "⛔ 审核拒绝",
+		ModerationEventHardDeleted: "🗑️ 文件已永久删除",
+	}[event.Event]
+
+	auditor := fmt.Sprintf("审核员 #%d", event.AuditorID)
+	if event.AuditorType == "auto" {
+		auditor = "自动审核"
+	}
+	msg := fmt.Sprintf("**%s** `%s` (%s)\n上传者 #%d · %s", action, event.FileName, event.FileID, event.UploaderID, auditor)
+	if event.NSFWScore != nil {
+		msg += fmt.Sprintf(" · NSFW %.2f", *event.NSFWScore)
+	}
+	if event.Reason != "" {
+		msg += "\n原因：" + event.Reason
+	}
+	return msg
+}
@@ -0,0 +1,431 @@
+package saml
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/controllers/setting/dto"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+)
+
+const (
+	requestKeyPrefix = "saml:request:"
+	requestTTL       = 10 * time.Minute
+	metadataTTL      = time.Hour
+	metadataTimeout  = 10 * time.Second
+
+	metadataPath = "/api/v1/auth/saml/metadata"
+	acsPath      = "/api/v1/auth/saml/acs"
+)
+
+// ErrNotEnabled SAML 未启用或配置不完整
+var ErrNotEnabled = errors.New("SAML单点登录未启用")
+
+// Config SAML 配置，对应 saml 设置分组
+type Config struct {
+	Enabled           bool
+	IDPMetadataURL    string
+	IDPMetadataXML    string
+	SPEntityID        string
+	SPCertificate     string
+	SPPrivateKey      string
+	UsernameAttribute string
+	EmailAttribute    string
+	GroupAttribute    string
+	GroupRoleMapping  string
+	DefaultRole       int
+	AutoCreateUser    bool
+}
+
+// Identity 断言校验通过后提取的用户信息
+type Identity struct {
+	NameID   string
+	Username string
+	Email    string
+	Groups   []string
+	Role     int
+}
+
+var (
+	metadataMu        sync.Mutex
+	cachedMetadata    *saml.EntityDescriptor
+	cachedMetadataSrc string
+	cachedMetadataAt  time.Time
+
+	keyPairMu sync.Mutex
+)
+
+/* GetConfig 读取 SAML 配置 */
+func GetConfig() *Config {
+	return &Config{
+		Enabled:           setting.GetBool("saml", "saml_enabled", false),
+		IDPMetadataURL:    setting.GetString("saml", "saml_idp_metadata_url", ""),
+		IDPMetadataXML:    setting.GetString("saml", "saml_idp_metadata_xml", ""),
+		SPEntityID:        setting.GetString("saml", "saml_sp_entity_id", ""),
+		SPCertificate:     setting.GetString("saml", "saml_sp_certificate", ""),
+		SPPrivateKey:      setting.GetString("saml", "saml_sp_private_key", ""),
+		UsernameAttribute: setting.GetString("saml", "saml_username_attribute", "uid"),
+		EmailAttribute:    setting.GetString("saml", "saml_email_attribute", "email"),
+		GroupAttribute:    setting.GetString("saml", "saml_group_attribute", "groups"),
+		GroupRoleMapping:  setting.GetString("saml", "saml_group_role_mapping", ""),
+		DefaultRole:       setting.GetInt("saml", "saml_default_role", common.UserRoleUser),
+		AutoCreateUser:    setting.GetBool("saml", "saml_auto_create_user", true),
+	}
+}
+
+/* IsEnabled SAML 登录是否已启用且配置了 IdP 元数据 */
+func IsEnabled() bool {
+	cfg := GetConfig()
+	return cfg.Enabled && (cfg.IDPMetadataURL != "" || cfg.IDPMetadataXML != "")
+}
+
+/* IsSSOEnforced 是否强制使用SSO登录，仅在SAML可用时生效，避免误配置导致无法登录 */
+func IsSSOEnforced() bool {
+	return setting.GetBool("security", "sso_enforced", false) && IsEnabled()
+}
+
+/* MetadataXML 生成 SP 元数据，供 IdP 配置使用 */
+func MetadataXML() ([]byte, error) {
+	sp, err := serviceProvider(GetConfig(), false)
+	if err != nil {
+		return nil, err
+	}
+	data, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成SP元数据失败: %v", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+/* BeginLogin 发起 SP-initiated 登录，返回跳转到 IdP 的地址 */
+func BeginLogin() (string, error) {
+	cfg := GetConfig()
+	if !cfg.Enabled {
+		return "", ErrNotEnabled
+	}
+	sp, err := serviceProvider(cfg, true)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", fmt.Errorf("创建SAML认证请求失败: %v", err)
+	}
+
+	// RelayState 作为一次性票据关联本次请求ID，回调时用于校验 InResponseTo
+	relayState := utils.GenerateRandomString(32)
+	if err := cache.GetCache().Set(requestKeyPrefix+relayState, req.ID, requestTTL); err != nil {
+		return "", fmt.Errorf("保存SAML请求失败: %v", err)
+	}
+
+	redirectURL, err := req.Redirect(relayState, sp)
+	if err != nil {
+		return "", fmt.Errorf("生成SAML跳转地址失败: %v", err)
+	}
+	return redirectURL.String(), nil
+}
+
+/* ParseResponse 校验 IdP 回调的断言并提取用户信息 */
+func ParseResponse(r *http.Request) (*Identity, error) {
+	cfg := GetConfig()
+	if !cfg.Enabled {
+		return nil, ErrNotEnabled
+	}
+	sp, err := serviceProvider(cfg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("解析SAML响应失败: %v", err)
+	}
+	relayState := r.PostForm.Get("RelayState")
+	if relayState == "" {
+		return nil, errors.New("缺少RelayState，仅支持从本站发起的登录")
+	}
+	key := requestKeyPrefix + relayState
+	requestID, err := cache.GetCache().Get(key)
+	if err != nil || requestID == "" {
+		return nil, errors.New("登录请求已过期，请重新登录")
+	}
+	_ = cache.GetCache().Del(key)
+
+	assertion, err := sp.ParseResponse(r, []string{requestID})
+	if err != nil {
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			logger.Warn("SAML断言校验失败: %v", invalid.PrivateErr)
+		} else {
+			logger.Warn("SAML断言校验失败: %v", err)
+		}
+		return nil, errors.New("SAML断言校验失败")
+	}
+
+	return extractIdentity(cfg, assertion)
+}
+
+func extractIdentity(cfg *Config, assertion *saml.Assertion) (*Identity, error) {
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || strings.TrimSpace(assertion.Subject.NameID.Value) == "" {
+		return nil, errors.New("SAML断言缺少NameID")
+	}
+
+	identity := &Identity{NameID: strings.TrimSpace(assertion.Subject.NameID.Value)}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]string, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				if s := strings.TrimSpace(v.Value); s != "" {
+					values = append(values, s)
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			switch {
+			case matchAttribute(attr, cfg.UsernameAttribute) && identity.Username == "":
+				identity.Username = values[0]
+			case matchAttribute(attr, cfg.EmailAttribute) && identity.Email == "":
+				identity.Email = values[0]
+			case matchAttribute(attr, cfg.GroupAttribute):
+				identity.Groups = append(identity.Groups, values...)
+			}
+		}
+	}
+
+	if identity.Username == "" {
+		identity.Username = identity.NameID
+		if at := strings.Index(identity.Username, "@"); at > 0 {
+			identity.Username = identity.Username[:at]
+		}
+	}
+	if identity.Email == "" && strings.Contains(identity.NameID, "@") {
+		identity.Email = identity.NameID
+	}
+	identity.Role = common.ResolveMappedRole(cfg.GroupRoleMapping, identity.Groups, cfg.DefaultRole)
+	return identity, nil
+}
+
+// matchAttribute 属性名和友好名称均可匹配，兼容 URI 形式的属性名
+func matchAttribute(attr saml.Attribute, name string) bool {
+	if name == "" {
+		return false
+	}
+	return strings.EqualFold(attr.Name, name) || strings.EqualFold(attr.FriendlyName, name)
+}
+
+// serviceProvider 构建 SP，withIDP 为 true 时加载 IdP 元数据
+func serviceProvider(cfg *Config, withIDP bool) (*saml.ServiceProvider, error) {
+	baseURL := strings.TrimRight(utils.GetBaseUrl(), "/")
+	if baseURL == "" {
+		return nil, errors.New("未配置站点地址，无法生成SAML回调地址")
+	}
+	metadataURL, err := url.Parse(baseURL + metadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("站点地址无效: %v", err)
+	}
+	acsURL, _ := url.Parse(baseURL + acsPath)
+
+	key, cert, err := loadKeyPair(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:          cfg.SPEntityID,
+		Key:               key,
+		Certificate:       cert,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+		AllowIDPInitiated: false,
+	}
+
+	if withIDP {
+		metadata, err := loadIDPMetadata(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sp.IDPMetadata = metadata
+	}
+	return sp, nil
+}
+
+// loadIDPMetadata 优先使用配置的元数据XML，否则从元数据地址拉取并缓存
+func loadIDPMetadata(cfg *Config) (*saml.EntityDescriptor, error) {
+	source := cfg.IDPMetadataXML
+	if strings.TrimSpace(source) == "" {
+		source = cfg.IDPMetadataURL
+	}
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.New("未配置IdP元数据")
+	}
+
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	if cachedMetadata != nil && cachedMetadataSrc == source && time.Since(cachedMetadataAt) < metadataTTL {
+		return cachedMetadata, nil
+	}
+
+	var metadata *saml.EntityDescriptor
+	var err error
+	if strings.TrimSpace(cfg.IDPMetadataXML) != "" {
+		metadata, err = samlsp.ParseMetadata([]byte(cfg.IDPMetadataXML))
+	} else {
+		var metadataURL *url.URL
+		metadataURL, err = url.Parse(cfg.IDPMetadataURL)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+			defer cancel()
+			metadata, err = samlsp.FetchMetadata(ctx, &http.Client{Timeout: metadataTimeout}, *metadataURL)
+		}
+	}
+	if err != nil {
+		// 拉取失败时继续使用旧的元数据，避免IdP短暂不可用导致无法登录
+		if cachedMetadata != nil && cachedMetadataSrc == source {
+			logger.Warn("刷新IdP元数据失败，继续使用缓存: %v", err)
+			return cachedMetadata, nil
+		}
+		return nil, fmt.Errorf("加载IdP元数据失败: %v", err)
+	}
+
+	cachedMetadata = metadata
+	cachedMetadataSrc = source
+	cachedMetadataAt = time.Now()
+	return metadata, nil
+}
+
+// loadKeyPair 读取 SP 签名密钥，未配置时生成自签名证书并保存到设置
+func loadKeyPair(cfg *Config) (*rsa.PrivateKey, *x509.Certificate, error) {
+	if cfg.SPCertificate == "" || cfg.SPPrivateKey == "" {
+		keyPairMu.Lock()
+		defer keyPairMu.Unlock()
+
+		// 加锁后重新读取，避免并发请求重复生成
+		certPEM := setting.GetString("saml", "saml_sp_certificate", "")
+		keyPEM := setting.GetString("saml", "saml_sp_private_key", "")
+		if certPEM == "" || keyPEM == "" {
+			var err error
+			certPEM, keyPEM, err = generateKeyPair()
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := saveKeyPair(certPEM, keyPEM); err != nil {
+				return nil, nil, err
+			}
+			logger.Info("已生成SAML SP签名证书")
+		}
+		cfg.SPCertificate = certPEM
+		cfg.SPPrivateKey = keyPEM
+	}
+
+	return parseKeyPair(cfg.SPCertificate, cfg.SPPrivateKey)
+}
+
+func parseKeyPair(certPEM, keyPEM string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	certBlock, _ := pem.Decode([]byte(certPEM))
+	if certBlock == nil {
+		return nil, nil, errors.New("SP证书格式错误")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析SP证书失败: %v", err)
+	}
+
+	keyBlock, _ := pem.Decode([]byte(keyPEM))
+	if keyBlock == nil {
+		return nil, nil, errors.New("SP私钥格式错误")
+	}
+	var key *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes); err == nil {
+		key = parsed
+	} else if parsed, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes); err == nil {
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, nil, errors.New("SP私钥必须为RSA密钥")
+		}
+		key = rsaKey
+	} else {
+		return nil, nil, fmt.Errorf("解析SP私钥失败: %v", err)
+	}
+	return key, cert, nil
+}
+
+func generateKeyPair() (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", fmt.Errorf("生成SP私钥失败: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return "", "", fmt.Errorf("生成证书序列号失败: %v", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "PixelPunk SAML SP"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", fmt.Errorf("生成SP证书失败: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(certPEM), string(keyPEM), nil
+}
+
+func saveKeyPair(certPEM, keyPEM string) error {
+	result, err := setting.BatchUpsertSettings(&dto.BatchUpsertSettingDTO{
+		Settings: []dto.SettingCreateDTO{
+			{
+				Key:         "saml_sp_certificate",
+				Value:       certPEM,
+				Type:        "string",
+				Group:       "saml",
+				Description: "SP签名证书(PEM)，留空时首次使用自动生成",
+				IsSystem:    true,
+			},
+			{
+				Key:         "saml_sp_private_key",
+				Value:       keyPEM,
+				Type:        "string",
+				Group:       "saml",
+				Description: "SP签名私钥(PEM)，留空时首次使用自动生成",
+				IsSystem:    true,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("保存SP证书失败: %v", err)
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("保存SP证书失败: %s", result.Failed[0].Message)
+	}
+	return nil
+}
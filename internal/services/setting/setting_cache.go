@@ -4,8 +4,44 @@ import (
 	"encoding/json"
 	"pixelpunk/internal/controllers/setting/dto"
 	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/hooks"
+	"strings"
+	"sync"
+	"time"
 )
 
+// remoteUpdateDelay 合并其他实例批量更新同一分组时的多次失效通知
+const remoteUpdateDelay = 500 * time.Millisecond
+
+var (
+	remoteUpdateMu     sync.Mutex
+	remoteUpdateTimers = make(map[string]*time.Timer)
+)
+
+func init() {
+	// 设置读取频繁且极少修改，缓存在进程内，减少文件访问等热路径上的 Redis 往返
+	cache.RegisterLocalPrefix(SettingCachePrefix)
+	cache.OnInvalidate(SettingGroupPrefix, onRemoteSettingGroupInvalidated)
+}
+
+// onRemoteSettingGroupInvalidated 其他实例更新设置后，在本实例触发同一分组的更新钩子
+func onRemoteSettingGroupInvalidated(key string) {
+	group := strings.TrimPrefix(key, SettingGroupPrefix)
+
+	remoteUpdateMu.Lock()
+	defer remoteUpdateMu.Unlock()
+	if timer, ok := remoteUpdateTimers[group]; ok {
+		timer.Reset(remoteUpdateDelay)
+		return
+	}
+	remoteUpdateTimers[group] = time.AfterFunc(remoteUpdateDelay, func() {
+		remoteUpdateMu.Lock()
+		delete(remoteUpdateTimers, group)
+		remoteUpdateMu.Unlock()
+		hooks.TriggerSettingUpdate(group)
+	})
+}
+
 func getSettingCacheKey(key string) string {
 	return SettingCachePrefix + key
 }
@@ -50,6 +50,11 @@ func GetSettingDirectFromDB(group, key string, defaultValue interface{}) interfa
 			return strVal
 		}
 		return row.Value
+	case "json", "array":
+		var anyVal interface{}
+		if err := json.Unmarshal([]byte(row.Value), &anyVal); err == nil {
+			return anyVal
+		}
 	}
 
 	return defaultValue
@@ -91,6 +96,22 @@ func GetStringDirectFromDB(group, key string, defaultValue string) string {
 	return defaultValue
 }
 
+// GetJSONDirectFromDB 直接从数据库获取JSON对象配置
+func GetJSONDirectFromDB(group, key string) map[string]interface{} {
+	val := GetSettingDirectFromDB(group, key, nil)
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return v
+	case string:
+		// 兼容以字符串形式保存的JSON
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &m); err == nil {
+			return m
+		}
+	}
+	return nil
+}
+
 // GetMultipleSettingsDirectFromDB 直接从数据库批量获取配置（绕过缓存）
 // 返回map[key]interface{}
 func GetMultipleSettingsDirectFromDB(group string, keys []string) (map[string]interface{}, error) {
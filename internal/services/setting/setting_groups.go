@@ -88,7 +88,7 @@ func GetGlobalSettingsGroups() (*dto.GlobalSettingsResponseDTO, error) {
 			result.Theme = groupSettings.Settings
 		case "registration":
 			registrationConfig := make(map[string]interface{})
-			allowedKeys := []string{"enable_registration", "email_verification"}
+			allowedKeys := []string{"enable_registration", "email_verification", "registration_mode"}
 			for _, key := range allowedKeys {
 				if value, exists := groupSettings.Settings[key]; exists {
 					registrationConfig[key] = value
@@ -164,6 +164,13 @@ func GetGlobalSettingsGroups() (*dto.GlobalSettingsResponseDTO, error) {
 		}
 	}
 
+	// 与 saml.IsEnabled/IsSSOEnforced 保持一致，setting 包不能反向依赖 saml 包
+	samlEnabled := GetBool("saml", "saml_enabled", false) &&
+		(GetString("saml", "saml_idp_metadata_url", "") != "" || GetString("saml", "saml_idp_metadata_xml", "") != "")
+	result.OAuthProviders.SAMLEnabled = samlEnabled
+	result.OAuthProviders.SAMLButtonText = GetString("saml", "saml_button_text", "")
+	result.OAuthProviders.SSOEnforced = samlEnabled && GetBool("security", "sso_enforced", false)
+
 	result.DeployMode = common.GetDeployMode()
 
 	return result, nil
@@ -48,6 +48,7 @@ func InitSettingService() {
 
 	hooks.RegisterSettingUpdateHook("security", func(group string) error {
 		syncGlobalSettings()
+		utils.ResetURLSigner()
 		return nil
 	})
 
@@ -0,0 +1,68 @@
+package setting
+
+import (
+	"fmt"
+	"pixelpunk/internal/controllers/setting/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"strings"
+)
+
+// SettingOverride 由环境变量或配置文件指定的设置值
+type SettingOverride struct {
+	Group string
+	Key   string
+	Type  string
+	Value interface{}
+}
+
+/* ApplySettingOverrides 将外部指定的设置写入数据库，只更新值有变化的项并保留原有描述，返回实际更新的键 */
+func ApplySettingOverrides(overrides []SettingOverride) ([]string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "数据库连接不可用")
+	}
+
+	var upserts []dto.SettingCreateDTO
+	for _, o := range overrides {
+		item := dto.SettingCreateDTO{
+			Key:      o.Key,
+			Value:    o.Value,
+			Type:     o.Type,
+			Group:    o.Group,
+			IsSystem: true,
+		}
+
+		var existing models.Setting
+		if err := db.Where("`key` = ?", o.Key).First(&existing).Error; err == nil {
+			if fmt.Sprint(parseSettingValue(existing)) == fmt.Sprint(o.Value) {
+				continue
+			}
+			item.Description = existing.Description
+			item.IsSystem = existing.IsSystem
+		}
+		upserts = append(upserts, item)
+	}
+	if len(upserts) == 0 {
+		return nil, nil
+	}
+
+	result, err := BatchUpsertSettings(&dto.BatchUpsertSettingDTO{Settings: upserts})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Failed) > 0 {
+		messages := make([]string, 0, len(result.Failed))
+		for _, f := range result.Failed {
+			messages = append(messages, f.Key+": "+f.Message)
+		}
+		return nil, errors.New(errors.CodeDBUpdateFailed, "部分设置写入失败: "+strings.Join(messages, "; "))
+	}
+
+	keys := make([]string, 0, len(result.Success))
+	for _, s := range result.Success {
+		keys = append(keys, s.Group+"."+s.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,318 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/controllers/setting/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/audit"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// settingsExportVersion 导出文件格式版本
+const settingsExportVersion = 1
+
+// 导入差异中的变更类型
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+)
+
+// maskedSecret 敏感设置在差异预览中的显示值
+const maskedSecret = "******"
+
+// ExportedSetting 导出文件中的单个设置
+type ExportedSetting struct {
+	Type        string      `json:"type" yaml:"type"`
+	Value       interface{} `json:"value" yaml:"value"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// SettingsExport 设置导出文件，按 分组 -> 键名 组织
+type SettingsExport struct {
+	Version    int                                   `json:"version" yaml:"version"`
+	ExportedAt string                                `json:"exported_at" yaml:"exported_at"`
+	Settings   map[string]map[string]ExportedSetting `json:"settings" yaml:"settings"`
+}
+
+// ImportChange 导入时单个设置的变更
+type ImportChange struct {
+	Group    string      `json:"group"`
+	Key      string      `json:"key"`
+	Type     string      `json:"type"`
+	Action   string      `json:"action"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// ImportIssue 导入校验失败的设置
+type ImportIssue struct {
+	Group   string `json:"group"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ImportPreview 导入差异预览，Changes 只包含新增与修改的设置
+type ImportPreview struct {
+	Changes   []ImportChange `json:"changes"`
+	Errors    []ImportIssue  `json:"errors"`
+	Creates   int            `json:"creates"`
+	Updates   int            `json:"updates"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// ImportResult 导入结果，Errors 为写入数据库时失败的设置
+type ImportResult struct {
+	ImportPreview
+	Applied int `json:"applied"`
+}
+
+/* ExportSettings 导出全部或指定分组的设置，默认不导出密码、密钥等敏感设置 */
+func ExportSettings(group string, includeSecrets bool) (*SettingsExport, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New(errors.CodeDBConnectionFailed, "数据库连接不可用")
+	}
+
+	query := db.Model(&models.Setting{})
+	if group != "" {
+		query = query.Where("`group` = ?", group)
+	}
+	var settings []models.Setting
+	if err := query.Find(&settings).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询设置失败")
+	}
+
+	export := &SettingsExport{
+		Version:    settingsExportVersion,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Settings:   make(map[string]map[string]ExportedSetting),
+	}
+	for _, s := range settings {
+		if !includeSecrets && audit.IsSensitiveKey(s.Key) {
+			continue
+		}
+		if export.Settings[s.Group] == nil {
+			export.Settings[s.Group] = make(map[string]ExportedSetting)
+		}
+		export.Settings[s.Group][s.Key] = ExportedSetting{
+			Type:        s.Type,
+			Value:       parseSettingValue(s),
+			Description: s.Description,
+		}
+	}
+	return export, nil
+}
+
+/* ParseSettingsImport 解析导入文件，YAML 与 JSON 均可（JSON 是 YAML 的子集） */
+func ParseSettingsImport(data []byte) (*SettingsExport, error) {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "导入内容不能为空")
+	}
+
+	var doc SettingsExport
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.New(errors.CodeInvalidParameter, "导入文件格式错误: "+err.Error())
+	}
+	if doc.Version != settingsExportVersion {
+		return nil, errors.New(errors.CodeInvalidParameter, fmt.Sprintf("不支持的导入文件版本: %d", doc.Version))
+	}
+	if len(doc.Settings) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "导入文件中没有设置")
+	}
+	return &doc, nil
+}
+
+/* PreviewSettingsImport 校验导入内容并与当前设置比较，不写入数据库 */
+func PreviewSettingsImport(doc *SettingsExport) (*ImportPreview, error) {
+	preview, _, err := planSettingsImport(doc)
+	return preview, err
+}
+
+/* ImportSettings 校验通过后写入有变化的设置，存在校验错误时不写入任何设置 */
+func ImportSettings(doc *SettingsExport) (*ImportResult, error) {
+	preview, upserts, err := planSettingsImport(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(preview.Errors) > 0 {
+		return nil, errors.New(errors.CodeValidationFailed, fmt.Sprintf("导入内容有 %d 项校验失败，未导入任何设置，请先预览查看详情", len(preview.Errors)))
+	}
+	result := &ImportResult{ImportPreview: *preview}
+	if len(upserts) == 0 {
+		return result, nil
+	}
+
+	batch, err := BatchUpsertSettings(&dto.BatchUpsertSettingDTO{Settings: upserts})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range batch.Failed {
+		result.Errors = append(result.Errors, ImportIssue{Key: f.Key, Message: f.Message})
+	}
+	result.Applied = len(batch.Success)
+	return result, nil
+}
+
+// planSettingsImport 校验导入内容并计算差异，返回需要写入的设置
+func planSettingsImport(doc *SettingsExport) (*ImportPreview, []dto.SettingCreateDTO, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, nil, errors.New(errors.CodeDBConnectionFailed, "数据库连接不可用")
+	}
+
+	var existing []models.Setting
+	if err := db.Find(&existing).Error; err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询设置失败")
+	}
+	current := make(map[string]models.Setting, len(existing))
+	for _, s := range existing {
+		current[s.Key] = s
+	}
+
+	preview := &ImportPreview{Changes: []ImportChange{}, Errors: []ImportIssue{}}
+	var upserts []dto.SettingCreateDTO
+	seen := make(map[string]string)
+
+	groups := make([]string, 0, len(doc.Settings))
+	for group := range doc.Settings {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		keys := make([]string, 0, len(doc.Settings[group]))
+		for key := range doc.Settings[group] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			item := doc.Settings[group][key]
+			if msg := validateImportedSetting(group, key, item); msg != "" {
+				preview.Errors = append(preview.Errors, ImportIssue{Group: group, Key: key, Message: msg})
+				continue
+			}
+			if other, dup := seen[key]; dup {
+				preview.Errors = append(preview.Errors, ImportIssue{Group: group, Key: key, Message: "键名与分组 " + other + " 中的设置重复"})
+				continue
+			}
+			seen[key] = group
+
+			change := ImportChange{Group: group, Key: key, Type: item.Type, NewValue: item.Value, Action: ImportActionCreate}
+			description := item.Description
+			if old, ok := current[key]; ok {
+				if old.IsSystem && old.Group != group {
+					preview.Errors = append(preview.Errors, ImportIssue{Group: group, Key: key, Message: "系统设置不能修改分组，当前分组为 " + old.Group})
+					continue
+				}
+				oldValue := parseSettingValue(old)
+				change.OldValue = oldValue
+				if old.Group == group && old.Type == item.Type && sameSettingValue(oldValue, item.Value) {
+					change.Action = ImportActionUnchanged
+				} else {
+					change.Action = ImportActionUpdate
+				}
+				if description == "" {
+					description = old.Description
+				}
+			}
+
+			switch change.Action {
+			case ImportActionCreate:
+				preview.Creates++
+			case ImportActionUpdate:
+				preview.Updates++
+			default:
+				preview.Unchanged++
+			}
+			if change.Action == ImportActionUnchanged {
+				continue
+			}
+			upserts = append(upserts, dto.SettingCreateDTO{
+				Key:         key,
+				Value:       item.Value,
+				Type:        item.Type,
+				Group:       group,
+				Description: description,
+			})
+
+			if audit.IsSensitiveKey(key) {
+				if change.OldValue != nil {
+					change.OldValue = maskedSecret
+				}
+				change.NewValue = maskedSecret
+			}
+			preview.Changes = append(preview.Changes, change)
+		}
+	}
+	return preview, upserts, nil
+}
+
+// validateImportedSetting 校验单个设置的键名、类型与值，返回错误信息
+func validateImportedSetting(group, key string, item ExportedSetting) string {
+	switch {
+	case group == "" || len(group) > 50:
+		return "设置分组不能为空且不能超过50个字符"
+	case key == "" || len(key) > 100:
+		return "设置键名不能为空且不能超过100个字符"
+	case len(item.Description) > 500:
+		return "设置描述长度不能超过500个字符"
+	case item.Value == nil:
+		return "设置值不能为空"
+	}
+	if s, ok := item.Value.(string); ok && s == maskedSecret {
+		return "设置值为脱敏占位符，请填写实际值或从文件中删除该项"
+	}
+
+	switch item.Type {
+	case models.SettingTypeString, "text":
+		if _, ok := item.Value.(string); !ok {
+			return "字符串类型的值必须是字符串"
+		}
+	case models.SettingTypeNumber:
+		switch item.Value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			return "数字类型的值必须是数字"
+		}
+	case models.SettingTypeBoolean:
+		if _, ok := item.Value.(bool); !ok {
+			return "布尔类型的值必须是 true 或 false"
+		}
+	case models.SettingTypeJSON:
+	case models.SettingTypeArray:
+		if _, ok := item.Value.([]interface{}); !ok {
+			return "数组类型的值必须是数组"
+		}
+	default:
+		return "不支持的值类型: " + item.Type
+	}
+	return ""
+}
+
+// sameSettingValue 比较当前值与导入值，统一转为 JSON 后比较，避免 YAML 整数与 JSON 浮点数等类型差异
+func sameSettingValue(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var na, nb interface{}
+	if json.Unmarshal(ja, &na) != nil || json.Unmarshal(jb, &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
@@ -9,23 +9,29 @@ import (
 	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/storage"
 	"pixelpunk/pkg/utils"
-	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-func GetShareForView(shareKey string, folderID string) (map[string]interface{}, error) {
+/* GetShareForView 获取分享页内容，文件URL附带绑定 grant 的单文件令牌 */
+func GetShareForView(shareKey string, folderID string, grant ShareGrant) (map[string]interface{}, error) {
 	share, err := GetShareByKey(shareKey)
 	if err != nil {
 		return nil, err
 	}
+	sg := grant.Encode()
 
 	shareItems, err := GetShareItems(share.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	excludedFileIDs, err := getExcludedFileIDs(share.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	var user models.User
 	if err := database.DB.Select("id, username, avatar").Where("id = ?", share.UserID).First(&user).Error; err != nil {
 		return nil, err
@@ -101,30 +107,17 @@ func GetShareForView(shareKey string, folderID string) (map[string]interface{},
 		}
 
 		var folderImages []models.File
-		if err := database.DB.Preload("AIInfo").Where("folder_id = ? AND user_id = ?", folderID, share.UserID).
-			Where("status <> ?", "pending_deletion").
-			Find(&folderImages).Error; err != nil {
+		query := database.DB.Preload("AIInfo").Where("folder_id = ? AND user_id = ?", folderID, share.UserID).
+			Where("status NOT IN ?", hiddenShareFileStatuses)
+		if err := excludeSharedFiles(query, excludedFileIDs).Find(&folderImages).Error; err != nil {
 			return nil, err
 		}
 
 		for _, file := range folderImages {
 			fullURL, fullThumbURL, _ := storage.GetFullURLs(file)
 
-			if fullURL != "" {
-				if strings.Contains(fullURL, "?") {
-					fullURL = fullURL + "&share=" + shareKey
-				} else {
-					fullURL = fullURL + "?share=" + shareKey
-				}
-			}
-
-			if fullThumbURL != "" {
-				if strings.Contains(fullThumbURL, "?") {
-					fullThumbURL = fullThumbURL + "&share=" + shareKey
-				} else {
-					fullThumbURL = fullThumbURL + "?share=" + shareKey
-				}
-			}
+			fullURL = appendShareParam(fullURL, shareKey, file.ID, sg)
+			fullThumbURL = appendShareParam(fullThumbURL, shareKey, file.ID, sg)
 
 			fileMap := map[string]interface{}{
 				"id":             file.ID,
@@ -175,25 +168,12 @@ func GetShareForView(shareKey string, folderID string) (map[string]interface{},
 			} else if item.ItemType == common.ShareItemTypeFile {
 				var file models.File
 				if err := database.DB.Preload("AIInfo").Where("id = ? AND user_id = ?", item.ItemID, share.UserID).
-					Where("status <> ?", "pending_deletion").
+					Where("status NOT IN ?", hiddenShareFileStatuses).
 					First(&file).Error; err == nil {
 					fullURL, fullThumbURL, _ := storage.GetFullURLs(file)
 
-					if fullURL != "" {
-						if strings.Contains(fullURL, "?") {
-							fullURL = fullURL + "&share=" + shareKey
-						} else {
-							fullURL = fullURL + "?share=" + shareKey
-						}
-					}
-
-					if fullThumbURL != "" {
-						if strings.Contains(fullThumbURL, "?") {
-							fullThumbURL = fullThumbURL + "&share=" + shareKey
-						} else {
-							fullThumbURL = fullThumbURL + "?share=" + shareKey
-						}
-					}
+					fullURL = appendShareParam(fullURL, shareKey, file.ID, sg)
+					fullThumbURL = appendShareParam(fullThumbURL, shareKey, file.ID, sg)
 
 					fileMap := map[string]interface{}{
 						"id":             file.ID,
@@ -244,13 +224,30 @@ func GetShareForView(shareKey string, folderID string) (map[string]interface{},
 			"name":                   share.Name,
 			"description":            share.Description,
 			"created_at":             share.CreatedAt,
+			"starts_at":              share.StartsAt,
 			"expired_at":             share.ExpiredAt,
 			"expired_days":           share.ExpiredDays,
 			"current_views":          share.CurrentViews,
 			"max_views":              share.MaxViews,
 			"has_password":           share.Password != "",
 			"collect_visitor_info":   share.CollectVisitorInfo,
+			"require_email":          share.RequireEmailVerification,
+			"watermark_enabled":      share.WatermarkEnabled,
 			"notification_on_access": share.NotificationOnAccess,
+			"share_type":             share.ShareType,
+			"access_mode":            share.AccessMode,
+			"max_downloads":          share.MaxDownloads,
+			"download_count":         share.DownloadCount,
+			"max_download_bytes":     share.MaxDownloadBytes,
+			"download_bytes":         share.DownloadBytes,
+			"upload": map[string]interface{}{
+				"enabled":          share.IsCollect(),
+				"max_file_size_mb": share.UploadMaxFileSizeMB,
+				"max_files":        share.UploadMaxFiles,
+				"allowed_formats":  share.UploadAllowedFormats,
+				"moderation":       share.UploadModeration,
+				"upload_count":     share.UploadCount,
+			},
 		},
 		"user": map[string]interface{}{
 			"username": user.Username,
@@ -272,8 +269,8 @@ func GenerateAccessToken(shareKey string, password string, clientIP, userAgent s
 		return "", err
 	}
 
-	if share.Password != password {
-		return "", errors.New(errors.CodeWrongPassword, "密码错误")
+	if err := checkSharePassword(&share, password, clientIP); err != nil {
+		return "", err
 	}
 
 	tokenStr := utils.GenerateRandomString(32)
@@ -300,32 +297,38 @@ func GenerateAccessToken(shareKey string, password string, clientIP, userAgent s
 
 /* ValidateAccessToken 验证临时访问令牌 */
 func ValidateAccessToken(shareKey string, accessToken string) (bool, error) {
+	token, err := ResolveAccessToken(shareKey, accessToken)
+	return token != nil, err
+}
+
+/* ResolveAccessToken 验证临时访问令牌并返回令牌记录，令牌不存在、已过期或已吊销时返回 nil */
+func ResolveAccessToken(shareKey string, accessToken string) (*models.ShareAccessToken, error) {
 	var share models.Share
 	if err := database.DB.Where("share_key = ? AND status = ?", shareKey, common.ShareStatusNormal).First(&share).Error; err != nil {
-		return false, errors.New(errors.CodeNotFound, "分享不存在或已失效")
+		return nil, errors.New(errors.CodeNotFound, "分享不存在或已失效")
 	}
 
 	var token models.ShareAccessToken
-	err := database.DB.Where("token = ? AND share_key = ? AND expired_at > ?",
+	err := database.DB.Where("token = ? AND share_key = ? AND expired_at > ? AND revoked_at IS NULL",
 		accessToken, shareKey, time.Now()).
 		First(&token).Error
 
 	if err != nil {
 		if stderrors.Is(err, gorm.ErrRecordNotFound) {
-			return false, nil // 令牌不存在或已过期
+			return nil, nil // 令牌不存在或已过期
 		}
-		return false, err
+		return nil, err
 	}
 
 	if token.IsAdmin {
-		return true, nil
+		return &token, nil
 	}
 
 	if !share.IsAccessible() {
-		return false, errors.New(errors.CodeValidationFailed, "分享已过期或已达最大访问次数")
+		return nil, errors.New(errors.CodeValidationFailed, "分享已过期或已达最大访问次数")
 	}
 
-	return true, nil
+	return &token, nil
 }
 
 /* CleanExpiredTokens 清理过期的访问令牌 */
@@ -391,6 +394,11 @@ func ValidateSharedFileAccess(shareID, fileID string) (bool, error) {
 
 	if err != nil {
 		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			// 通过文件夹分享的文件需排除分享者屏蔽的部分
+			excluded, err := isFileExcluded(shareID, fileID)
+			if err != nil || excluded {
+				return false, err
+			}
 			return validateFileInSharedFolder(shareID, fileID)
 		}
 		return false, err
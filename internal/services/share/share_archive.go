@@ -0,0 +1,697 @@
+package share
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/bandwidth"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/utils"
+)
+
+// 打包任务状态
+const (
+	ArchiveStatusPending = "pending"
+	ArchiveStatusPacking = "packing"
+	ArchiveStatusReady   = "ready"
+	ArchiveStatusFailed  = "failed"
+)
+
+const (
+	shareArchiveDir           = "temp/share_archives"
+	shareArchiveConcurrency   = 2               // 同时打包的任务数，避免占满磁盘IO
+	shareArchiveSaveInterval  = time.Second     // 打包进度写入缓存的最小间隔
+	shareArchiveSweepInterval = time.Minute     // 创建任务时顺带清理本实例过期打包文件的最小间隔
+	shareArchiveReuseMinTTL   = 5 * time.Minute // 复用相同文件的已有任务时，剩余有效期至少需要的时长
+	shareArchiveJobKeyPrefix  = "share_archive:job:"
+	shareArchiveDedupePrefix  = "share_archive:dedupe:"
+)
+
+// ShareArchiveJob 分享打包下载任务
+type ShareArchiveJob struct {
+	ID          string    `json:"id"`
+	ShareKey    string    `json:"share_key"`
+	Status      string    `json:"status"`
+	TotalFiles  int       `json:"total_files"`
+	PackedFiles int       `json:"packed_files"`
+	TotalBytes  int64     `json:"total_bytes"`
+	PackedBytes int64     `json:"packed_bytes"`
+	ArchiveSize int64     `json:"archive_size"`
+	Skipped     []string  `json:"skipped,omitempty"` // 读取失败而跳过的文件名
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// archiveJobRecord 写入缓存的任务记录，多实例部署时任一实例都能查询进度并提供下载
+type archiveJobRecord struct {
+	ShareArchiveJob
+	OwnerID   uint           `json:"owner_id"`
+	ShareID   string         `json:"share_id"`
+	ClientIP  string         `json:"client_ip"`
+	Watermark bool           `json:"watermark"`
+	Path      string         `json:"path,omitempty"`
+	Entries   []archiveEntry `json:"entries,omitempty"`
+}
+
+// archiveEntry 文件数据在压缩包中的字节区间，用于按实际传输的字节记录各文件的下载
+type archiveEntry struct {
+	FileID string `json:"file_id"`
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"`
+}
+
+// localArchiveJob 本实例正在排队或打包的任务
+type localArchiveJob struct {
+	record    *archiveJobRecord
+	files     []models.File
+	watermark *models.Share // 非空时图片按分享的水印配置合成后再打包
+	ttl       time.Duration
+	savedAt   time.Time
+}
+
+var (
+	archiveJobsMu    sync.Mutex
+	archiveJobs      = make(map[string]*localArchiveJob)
+	archiveSlots     = make(chan struct{}, shareArchiveConcurrency)
+	lastArchiveSweep time.Time
+)
+
+/* CreateShareArchiveJob 校验所选文件均属于分享后创建异步打包任务，applyWatermark 为真时图片加水印打包。
+ * 相同文件集合已有未过期的任务时直接返回该任务；同一分享、同一IP的进行中任务数及本实例打包目录的磁盘占用均有上限 */
+func CreateShareArchiveJob(shareInfo models.Share, fileIDs []string, applyWatermark bool, clientIP string) (*ShareArchiveJob, error) {
+	maxFiles := setting.GetIntDirectFromDB("share", "share_archive_max_files", 500)
+	maxSizeMB := setting.GetIntDirectFromDB("share", "share_archive_max_size_mb", 2048)
+	maxPerShare := setting.GetIntDirectFromDB("share", "share_archive_max_active_per_share", 3)
+	maxPerIP := setting.GetIntDirectFromDB("share", "share_archive_max_active_per_ip", 2)
+	budgetMB := setting.GetIntDirectFromDB("share", "share_archive_disk_budget_mb", 10240)
+
+	fileIDs = uniqueStrings(fileIDs)
+	if maxFiles > 0 && len(fileIDs) > maxFiles {
+		return nil, errors.New(errors.CodeInvalidParameter, fmt.Sprintf("单次最多打包 %d 个文件", maxFiles))
+	}
+
+	for _, fileID := range fileIDs {
+		hasAccess, err := ValidateSharedFileAccess(shareInfo.ID, fileID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasAccess {
+			return nil, errors.New(errors.CodeFileAccessDenied, "所选文件不在分享内容中")
+		}
+	}
+
+	dedupeKey := archiveDedupeKey(shareInfo.ID, fileIDs, applyWatermark)
+	if existing := findReusableArchiveJob(dedupeKey, shareInfo.ShareKey); existing != nil {
+		return existing, nil
+	}
+
+	var files []models.File
+	if err := database.DB.Where("id IN ? AND user_id = ?", fileIDs, shareInfo.UserID).
+		Where("status NOT IN ?", hiddenShareFileStatuses).
+		Find(&files).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享文件失败")
+	}
+	if len(files) == 0 {
+		return nil, errors.New(errors.CodeFileNotFound, "所选文件不存在")
+	}
+
+	var totalBytes int64
+	for _, file := range files {
+		totalBytes += file.Size
+	}
+	if maxSizeMB > 0 && totalBytes > int64(maxSizeMB)*1024*1024 {
+		return nil, errors.New(errors.CodeInvalidParameter, fmt.Sprintf("打包文件总大小不能超过 %dMB", maxSizeMB))
+	}
+
+	if err := CheckShareDownloadQuota(shareInfo, totalBytes); err != nil {
+		return nil, err
+	}
+
+	available, err := bandwidth.Service.CheckBandwidthAvailable(shareInfo.UserID, totalBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "检查带宽失败")
+	}
+	if !available {
+		return nil, errors.New(errors.CodeForbidden, "分享者的流量已用尽，暂时无法下载")
+	}
+
+	sweepShareArchives(false)
+
+	ttl := shareArchiveTTL()
+	now := time.Now()
+	job := &localArchiveJob{
+		record: &archiveJobRecord{
+			ShareArchiveJob: ShareArchiveJob{
+				ID:         generateID(),
+				ShareKey:   shareInfo.ShareKey,
+				Status:     ArchiveStatusPending,
+				TotalFiles: len(files),
+				TotalBytes: totalBytes,
+				CreatedAt:  now,
+				ExpiresAt:  now.Add(ttl),
+			},
+			OwnerID:   shareInfo.UserID,
+			ShareID:   shareInfo.ID,
+			ClientIP:  clientIP,
+			Watermark: applyWatermark,
+		},
+		files: files,
+		ttl:   ttl,
+	}
+	if applyWatermark {
+		job.watermark = &shareInfo
+	}
+
+	if err := acquireArchiveActive(shareInfo.ID, clientIP, job.record.ID, maxPerShare, maxPerIP); err != nil {
+		return nil, err
+	}
+	if err := reserveArchiveDisk(job, budgetMB); err != nil {
+		releaseArchiveActive(shareInfo.ID, clientIP, job.record.ID)
+		return nil, err
+	}
+
+	updateArchiveJob(job, true, func(r *archiveJobRecord) {})
+	if err := cache.Set(dedupeKey, job.record.ID, ttl); err != nil {
+		logger.Warn("记录分享打包任务失败: %v", err)
+	}
+
+	archiveJobsMu.Lock()
+	snapshot := job.record.ShareArchiveJob
+	archiveJobsMu.Unlock()
+
+	go runShareArchiveJob(job)
+	return &snapshot, nil
+}
+
+// GetShareArchiveJob 查询打包进度，任务须属于该分享
+func GetShareArchiveJob(shareKey, jobID string) (*ShareArchiveJob, error) {
+	record, err := loadArchiveRecord(shareKey, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return &record.ShareArchiveJob, nil
+}
+
+/* ShareArchiveDownload 一次打包下载：打包文件在本实例上时直接读取，
+ * 否则（由其他实例打包）按任务记录从存储重新生成相同内容的压缩包 */
+type ShareArchiveDownload struct {
+	Job  *ShareArchiveJob
+	Size int64 // 响应长度，重新生成时未知为 -1
+
+	record  *archiveJobRecord
+	file    *os.File
+	entries []archiveEntry
+}
+
+// OpenShareArchive 打开已完成的打包任务，调用方负责关闭
+func OpenShareArchive(shareKey, jobID string) (*ShareArchiveDownload, error) {
+	record, err := loadArchiveRecord(shareKey, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != ArchiveStatusReady {
+		return nil, errors.New(errors.CodeInvalidParameter, "打包尚未完成")
+	}
+
+	d := &ShareArchiveDownload{Job: &record.ShareArchiveJob, Size: -1, record: record, entries: record.Entries}
+	if record.Path != "" {
+		if f, err := os.Open(record.Path); err == nil {
+			if info, err := f.Stat(); err == nil && info.Size() == record.ArchiveSize {
+				d.file = f
+				d.Size = record.ArchiveSize
+			} else {
+				f.Close()
+			}
+		}
+	}
+	return d, nil
+}
+
+// WriteTo 将压缩包写入 w，返回实际写出的字节数
+func (d *ShareArchiveDownload) WriteTo(w io.Writer) (int64, error) {
+	if d.file != nil {
+		return io.Copy(w, d.file)
+	}
+
+	files, watermark, err := loadArchiveSources(d.record)
+	if err != nil {
+		return 0, err
+	}
+	cw := &countingWriter{w: w}
+	entries, err := packShareArchive(cw, files, watermark, nil)
+	d.entries = entries
+	return cw.n, err
+}
+
+// Close 关闭本地打包文件
+func (d *ShareArchiveDownload) Close() error {
+	if d.file != nil {
+		return d.file.Close()
+	}
+	return nil
+}
+
+// RecordShareArchiveDownload 按实际传输字节记入分享者流量，并只为已传输的文件按传输字节记录下载日志
+func RecordShareArchiveDownload(d *ShareArchiveDownload, bytesSent int64, clientIP, userAgent string) {
+	if d == nil || bytesSent <= 0 {
+		return
+	}
+	record := d.record
+
+	if err := bandwidth.Service.RecordBandwidthTransfer(record.OwnerID, bytesSent); err != nil {
+		logger.Error("记录分享打包下载流量失败: %v", err)
+	}
+	RecordShareDownload(record.ShareID, bytesSent)
+
+	for _, entry := range d.entries {
+		delivered := entry.delivered(bytesSent)
+		if delivered <= 0 {
+			continue
+		}
+		filesvc.UpdateBandwidth(entry.FileID, delivered)
+		downloadLog := &models.FileDownloadLog{
+			UserID:    0, // 分享下载设置为0，表示游客下载
+			FileID:    entry.FileID,
+			FileSize:  delivered,
+			IPAddress: clientIP,
+			UserAgent: userAgent,
+			ShareKey:  record.ShareKey,
+		}
+		if err := database.DB.Create(downloadLog).Error; err != nil {
+			logger.Error("记录分享下载日志失败: %v", err)
+		}
+	}
+}
+
+// delivered 压缩包传输 bytesSent 字节时该文件已传输的数据字节数
+func (e archiveEntry) delivered(bytesSent int64) int64 {
+	if bytesSent <= e.Start {
+		return 0
+	}
+	if bytesSent > e.End {
+		bytesSent = e.End
+	}
+	return bytesSent - e.Start
+}
+
+// CleanExpiredShareArchives 删除本实例上已过期的打包文件，任务记录随缓存过期自动失效
+func CleanExpiredShareArchives() int {
+	return sweepShareArchives(true)
+}
+
+/* sweepShareArchives 删除打包目录中超过保留时间的文件，正在打包的任务除外。
+ * 定时任务只在抢到锁的实例上执行，因此各实例创建任务时也会顺带清理自己的打包目录 */
+func sweepShareArchives(force bool) int {
+	archiveJobsMu.Lock()
+	if !force && time.Since(lastArchiveSweep) < shareArchiveSweepInterval {
+		archiveJobsMu.Unlock()
+		return 0
+	}
+	lastArchiveSweep = time.Now()
+	active := make(map[string]struct{}, len(archiveJobs))
+	for id := range archiveJobs {
+		active[id] = struct{}{}
+	}
+	archiveJobsMu.Unlock()
+
+	entries, err := os.ReadDir(shareArchiveDir)
+	if err != nil {
+		return 0
+	}
+	ttl := shareArchiveTTL()
+	cleaned := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".zip" {
+			continue
+		}
+		if _, ok := active[strings.TrimSuffix(name, ".zip")]; ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < ttl {
+			continue
+		}
+		if err := os.Remove(filepath.Join(shareArchiveDir, name)); err == nil {
+			cleaned++
+		}
+	}
+	return cleaned
+}
+
+func shareArchiveTTL() time.Duration {
+	ttl := setting.GetIntDirectFromDB("share", "share_archive_ttl_minutes", 60)
+	if ttl <= 0 {
+		ttl = 60
+	}
+	return time.Duration(ttl) * time.Minute
+}
+
+// archiveDedupeKey 同一分享下相同文件集合与水印选项的去重键
+func archiveDedupeKey(shareID string, fileIDs []string, watermark bool) string {
+	sorted := append([]string(nil), fileIDs...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%t|%s", watermark, strings.Join(sorted, ","))))
+	return shareArchiveDedupePrefix + shareID + ":" + hex.EncodeToString(sum[:])
+}
+
+// findReusableArchiveJob 返回相同文件集合仍在进行或已完成且剩余有效期充足的任务
+func findReusableArchiveJob(dedupeKey, shareKey string) *ShareArchiveJob {
+	jobID, err := cache.Get(dedupeKey)
+	if err != nil || jobID == "" {
+		return nil
+	}
+	record, err := loadArchiveRecord(shareKey, jobID)
+	if err != nil || record.Status == ArchiveStatusFailed {
+		return nil
+	}
+	if time.Until(record.ExpiresAt) < shareArchiveReuseMinTTL {
+		return nil
+	}
+	return &record.ShareArchiveJob
+}
+
+func loadArchiveRecord(shareKey, jobID string) (*archiveJobRecord, error) {
+	notFound := errors.New(errors.CodeNotFound, "打包任务不存在或已过期")
+	data, err := cache.Get(shareArchiveJobKeyPrefix + jobID)
+	if err != nil || data == "" {
+		return nil, notFound
+	}
+	var record archiveJobRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, notFound
+	}
+	if record.ShareKey != shareKey || time.Now().After(record.ExpiresAt) {
+		return nil, notFound
+	}
+	return &record, nil
+}
+
+// loadArchiveSources 按任务记录重新查询已打包的文件及水印配置，用于其他实例重新生成压缩包
+func loadArchiveSources(record *archiveJobRecord) ([]models.File, *models.Share, error) {
+	fileIDs := make([]string, 0, len(record.Entries))
+	for _, entry := range record.Entries {
+		fileIDs = append(fileIDs, entry.FileID)
+	}
+
+	var files []models.File
+	if err := database.DB.Where("id IN ? AND user_id = ?", fileIDs, record.OwnerID).
+		Where("status NOT IN ?", hiddenShareFileStatuses).
+		Find(&files).Error; err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享文件失败")
+	}
+	order := make(map[string]int, len(fileIDs))
+	for i, id := range fileIDs {
+		order[id] = i
+	}
+	sort.Slice(files, func(i, j int) bool { return order[files[i].ID] < order[files[j].ID] })
+
+	if !record.Watermark {
+		return files, nil, nil
+	}
+	var shareInfo models.Share
+	if err := database.DB.Where("id = ?", record.ShareID).First(&shareInfo).Error; err != nil {
+		return nil, nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+	return files, &shareInfo, nil
+}
+
+/* reserveArchiveDisk 登记本实例上的任务；打包目录已占用空间加上排队任务尚未写入的大小超过预算时拒绝 */
+func reserveArchiveDisk(job *localArchiveJob, budgetMB int) error {
+	archiveJobsMu.Lock()
+	defer archiveJobsMu.Unlock()
+
+	if budgetMB > 0 {
+		used := archiveDirUsage()
+		for _, j := range archiveJobs {
+			used += j.record.TotalBytes - j.record.PackedBytes
+		}
+		if used+job.record.TotalBytes > int64(budgetMB)*1024*1024 {
+			return errors.New(errors.CodeRateLimited, "打包下载请求较多，请稍后再试")
+		}
+	}
+	archiveJobs[job.record.ID] = job
+	return nil
+}
+
+func archiveDirUsage() int64 {
+	entries, err := os.ReadDir(shareArchiveDir)
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// updateArchiveJob 修改任务记录并写入缓存，仅进度变化时按 shareArchiveSaveInterval 节流
+func updateArchiveJob(job *localArchiveJob, force bool, fn func(r *archiveJobRecord)) {
+	archiveJobsMu.Lock()
+	fn(job.record)
+	if !force && time.Since(job.savedAt) < shareArchiveSaveInterval {
+		archiveJobsMu.Unlock()
+		return
+	}
+	job.savedAt = time.Now()
+	data, err := json.Marshal(job.record)
+	expiresAt := job.record.ExpiresAt
+	archiveJobsMu.Unlock()
+
+	if err != nil {
+		logger.Error("序列化分享打包任务失败: %v", err)
+		return
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return
+	}
+	if err := cache.Set(shareArchiveJobKeyPrefix+job.record.ID, string(data), ttl); err != nil {
+		logger.Warn("保存分享打包任务失败: %v", err)
+	}
+}
+
+func runShareArchiveJob(job *localArchiveJob) {
+	archiveSlots <- struct{}{}
+	defer func() { <-archiveSlots }()
+
+	record := job.record
+	defer func() {
+		archiveJobsMu.Lock()
+		delete(archiveJobs, record.ID)
+		archiveJobsMu.Unlock()
+		releaseArchiveActive(record.ShareID, record.ClientIP, record.ID)
+	}()
+
+	// 排队时间计入有效期会使大任务刚完成就过期，状态变化时重新计时
+	updateArchiveJob(job, true, func(r *archiveJobRecord) {
+		r.Status = ArchiveStatusPacking
+		r.ExpiresAt = time.Now().Add(job.ttl)
+	})
+
+	path := filepath.Join(shareArchiveDir, record.ID+".zip")
+	entries, size, err := writeShareArchive(job, path)
+	if err != nil {
+		logger.Error("分享打包失败 [%s]: %v", record.ID, err)
+		os.Remove(path)
+		updateArchiveJob(job, true, func(r *archiveJobRecord) {
+			r.Status = ArchiveStatusFailed
+			r.Error = err.Error()
+		})
+		return
+	}
+
+	updateArchiveJob(job, true, func(r *archiveJobRecord) {
+		r.Status = ArchiveStatusReady
+		r.Path = path
+		r.ArchiveSize = size
+		r.Entries = entries
+		r.ExpiresAt = time.Now().Add(job.ttl)
+	})
+}
+
+// writeShareArchive 将任务文件打包到本地临时文件
+func writeShareArchive(job *localArchiveJob, path string) ([]archiveEntry, int64, error) {
+	if err := os.MkdirAll(shareArchiveDir, 0755); err != nil {
+		return nil, 0, fmt.Errorf("创建打包目录失败: %v", err)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("创建打包文件失败: %v", err)
+	}
+	defer out.Close()
+
+	cw := &countingWriter{w: out}
+	packed := 0
+	entries, err := packShareArchive(cw, job.files, job.watermark, func(file models.File, name string, err error) {
+		if err != nil {
+			updateArchiveJob(job, false, func(r *archiveJobRecord) { r.Skipped = append(r.Skipped, name) })
+			return
+		}
+		packed++
+		updateArchiveJob(job, false, func(r *archiveJobRecord) {
+			r.PackedFiles++
+			r.PackedBytes += file.Size
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if packed == 0 {
+		return nil, 0, fmt.Errorf("所选文件均无法读取")
+	}
+	return entries, cw.n, nil
+}
+
+/* packShareArchive 逐个读取存储中的文件写入ZIP，图片已压缩过因此只存储不再压缩。
+ * 读取失败的文件跳过，写出失败（磁盘已满或客户端断开）时立即终止 */
+func packShareArchive(cw *countingWriter, files []models.File, watermark *models.Share, onFile func(file models.File, name string, err error)) ([]archiveEntry, error) {
+	zw := zip.NewWriter(cw)
+	storageService := storage.NewGlobalStorage()
+	usedNames := make(map[string]int)
+	var entries []archiveEntry
+
+	for _, file := range files {
+		name := archiveEntryName(file, usedNames)
+		entry, err := copyFileToArchive(zw, cw, storageService, file, name, watermark)
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+		if cw.err != nil {
+			return entries, fmt.Errorf("写入打包文件失败: %v", cw.err)
+		}
+		if err != nil {
+			logger.Warn("分享打包跳过文件 [%s]: %v", file.ID, err)
+		}
+		if onFile != nil {
+			onFile(file, name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return entries, fmt.Errorf("写入打包文件失败: %v", err)
+	}
+	return entries, nil
+}
+
+// copyFileToArchive 写入单个文件，返回其数据在压缩包中的区间，未写入任何内容时为 nil
+func copyFileToArchive(zw *zip.Writer, cw *countingWriter, storageService *storage.Storage, file models.File, name string, watermarkShare *models.Share) (*archiveEntry, error) {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: time.Time(file.CreatedAt),
+	}
+
+	var data []byte
+	if watermarkShare != nil {
+		rendered, err := RenderShareWatermark(context.Background(), *watermarkShare, file)
+		if err != nil {
+			return nil, err
+		}
+		data = rendered
+	}
+
+	var reader io.ReadCloser
+	if data == nil {
+		r, err := storageService.ReadFile(context.Background(), file.StorageProviderID, file.URL)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		reader = r
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if err := zw.Flush(); err != nil {
+		return nil, err
+	}
+
+	entry := &archiveEntry{FileID: file.ID, Start: cw.n}
+	if data != nil {
+		_, err = w.Write(data)
+	} else {
+		_, err = io.Copy(w, reader)
+	}
+	if flushErr := zw.Flush(); err == nil {
+		err = flushErr
+	}
+	entry.End = cw.n
+	return entry, err
+}
+
+// countingWriter 统计已写出的字节数并保留第一个写出错误
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// archiveEntryName 使用展示名作为压缩包内文件名，重名时追加序号
+func archiveEntryName(file models.File, used map[string]int) string {
+	name := file.DisplayName
+	if name == "" {
+		name = file.OriginalName
+	}
+	name = utils.GetSafeFilename(name)
+	if name == "" {
+		name = file.ID
+	}
+	if filepath.Ext(name) == "" && file.Format != "" {
+		name += "." + strings.ToLower(file.Format)
+	}
+
+	count := used[name]
+	used[name] = count + 1
+	if count == 0 {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), count, ext)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok || v == "" {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
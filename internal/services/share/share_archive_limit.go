@@ -0,0 +1,133 @@
+package share
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// shareArchiveActiveLease 进行中任务登记的最长保留时间，实例异常退出未释放时到期自动失效
+const shareArchiveActiveLease = 2 * time.Hour
+
+/* luaArchiveAcquire 原子地检查并登记进行中的任务：KEYS 依次为分享与IP的有序集合，成员为任务ID、分值为失效时间。
+ * 返回 0 表示登记成功，1、2 分别表示分享或IP的进行中任务已达上限 */
+var luaArchiveAcquire = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local deadline = tonumber(ARGV[2])
+for i = 1, 2 do
+  redis.call('ZREMRANGEBYSCORE', KEYS[i], '-inf', now)
+  local limit = tonumber(ARGV[3 + i])
+  if limit > 0 and redis.call('ZCARD', KEYS[i]) >= limit then
+    return i
+  end
+end
+for i = 1, 2 do
+  redis.call('ZADD', KEYS[i], deadline, ARGV[3])
+  redis.call('PEXPIRE', KEYS[i], deadline - now)
+end
+return 0
+`)
+
+// localArchiveActive 进程内的进行中任务登记，未启用 Redis 或 Redis 故障时使用
+var localArchiveActive = struct {
+	mu   sync.Mutex
+	sets map[string]map[string]time.Time
+}{sets: make(map[string]map[string]time.Time)}
+
+func archiveActiveKeys(shareID, clientIP string) []string {
+	if clientIP == "" {
+		clientIP = "unknown"
+	}
+	prefix := "share_archive:active"
+	if cache.IsRedisEnabled() {
+		prefix = cache.HashTag(cache.GetNamespace() + ":" + prefix)
+	}
+	return []string{prefix + ":share:" + shareID, prefix + ":ip:" + clientIP}
+}
+
+/* acquireArchiveActive 登记排队或打包中的任务，同一分享或同一IP的进行中任务达到上限时拒绝，上限为 0 表示不限制 */
+func acquireArchiveActive(shareID, clientIP, jobID string, maxPerShare, maxPerIP int) error {
+	keys := archiveActiveKeys(shareID, clientIP)
+	now := time.Now()
+	deadline := now.Add(shareArchiveActiveLease)
+
+	code := -1
+	if client := cache.GetRedisClient(); client != nil && cache.IsRedisEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		n, err := luaArchiveAcquire.Run(ctx, client, keys, now.UnixMilli(), deadline.UnixMilli(), jobID, maxPerShare, maxPerIP).Int()
+		cancel()
+		if err != nil {
+			logger.Warn("登记分享打包任务失败，降级为本实例统计: %v", err)
+		} else {
+			code = n
+		}
+	}
+	if code < 0 {
+		code = acquireLocalArchiveActive(keys, []int{maxPerShare, maxPerIP}, jobID, now, deadline)
+	}
+
+	switch code {
+	case 1:
+		return errors.New(errors.CodeRateLimited, "该分享正在打包的任务较多，请稍后再试")
+	case 2:
+		return errors.New(errors.CodeRateLimited, "您已有打包任务正在进行，请等待完成后再试")
+	}
+	return nil
+}
+
+// releaseArchiveActive 任务结束后释放登记，Redis 与进程内登记都会清除
+func releaseArchiveActive(shareID, clientIP, jobID string) {
+	keys := archiveActiveKeys(shareID, clientIP)
+	if client := cache.GetRedisClient(); client != nil && cache.IsRedisEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		for _, key := range keys {
+			if err := client.ZRem(ctx, key, jobID).Err(); err != nil {
+				logger.Warn("释放分享打包任务登记失败: %v", err)
+			}
+		}
+		cancel()
+	}
+
+	localArchiveActive.mu.Lock()
+	defer localArchiveActive.mu.Unlock()
+	for _, key := range keys {
+		if set, ok := localArchiveActive.sets[key]; ok {
+			delete(set, jobID)
+			if len(set) == 0 {
+				delete(localArchiveActive.sets, key)
+			}
+		}
+	}
+}
+
+func acquireLocalArchiveActive(keys []string, limits []int, jobID string, now, deadline time.Time) int {
+	localArchiveActive.mu.Lock()
+	defer localArchiveActive.mu.Unlock()
+
+	for i, key := range keys {
+		set := localArchiveActive.sets[key]
+		for id, expireAt := range set {
+			if now.After(expireAt) {
+				delete(set, id)
+			}
+		}
+		if limits[i] > 0 && len(set) >= limits[i] {
+			return i + 1
+		}
+	}
+	for _, key := range keys {
+		set, ok := localArchiveActive.sets[key]
+		if !ok {
+			set = make(map[string]time.Time)
+			localArchiveActive.sets[key] = set
+		}
+		set[jobID] = deadline
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package share
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+)
+
+const maxShareAuditRecords = 50000
+
+// ShareAuditReport 分享审计导出内容，包含访问记录、下载记录和访客信息
+type ShareAuditReport struct {
+	ShareID    string                    `json:"share_id"`
+	ShareKey   string                    `json:"share_key"`
+	ShareName  string                    `json:"share_name"`
+	StartDate  *common.JSONTime          `json:"start_date"`
+	EndDate    *common.JSONTime          `json:"end_date"`
+	ExportedAt common.JSONTime           `json:"exported_at"`
+	AccessLogs []models.ShareAccessLog   `json:"access_logs"`
+	Downloads  []ShareAuditDownload      `json:"downloads"`
+	Visitors   []models.ShareVisitorInfo `json:"visitors"`
+}
+
+// ShareAuditDownload 通过分享产生的一次文件下载
+type ShareAuditDownload struct {
+	DownloadedAt common.JSONTime `json:"downloaded_at"`
+	FileID       string          `json:"file_id"`
+	FileName     string          `json:"file_name"`
+	FileSize     int64           `json:"file_size"`
+	IPAddress    string          `json:"ip_address"`
+	UserAgent    string          `json:"user_agent"`
+}
+
+// ExportShareAudit 导出分享在时间范围内的访问、下载和访客记录，end 为开区间，均为空时导出全部
+func ExportShareAudit(shareID string, userID uint, start, end time.Time) (*ShareAuditReport, error) {
+	shareObj, err := getOwnedShare(shareID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return nil, errors.New(errors.CodeInvalidParameter, "结束日期不能早于开始日期")
+	}
+
+	report := &ShareAuditReport{
+		ShareID:    shareObj.ID,
+		ShareKey:   shareObj.ShareKey,
+		ShareName:  shareObj.Name,
+		ExportedAt: common.JSONTime(time.Now()),
+	}
+	if !start.IsZero() {
+		t := common.JSONTime(start)
+		report.StartDate = &t
+	}
+	if !end.IsZero() {
+		t := common.JSONTime(end)
+		report.EndDate = &t
+	}
+
+	logQuery := database.DB.Where("share_id = ?", shareID)
+	if !start.IsZero() {
+		logQuery = logQuery.Where("accessed_at >= ?", start)
+	}
+	if !end.IsZero() {
+		logQuery = logQuery.Where("accessed_at < ?", end)
+	}
+	if err := logQuery.Order("accessed_at ASC").Limit(maxShareAuditRecords).
+		Find(&report.AccessLogs).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询访问记录失败")
+	}
+
+	var downloads []models.FileDownloadLog
+	downloadQuery := database.DB.Where("share_key = ?", shareObj.ShareKey)
+	if !start.IsZero() {
+		downloadQuery = downloadQuery.Where("created_at >= ?", start)
+	}
+	if !end.IsZero() {
+		downloadQuery = downloadQuery.Where("created_at < ?", end)
+	}
+	if err := downloadQuery.Order("created_at ASC").Limit(maxShareAuditRecords).
+		Find(&downloads).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询下载记录失败")
+	}
+	report.Downloads = buildShareAuditDownloads(downloads)
+
+	// 访客信息按访问时间段筛选：首次访问早于结束时间且最后访问不早于开始时间
+	visitorQuery := database.DB.Where("share_id = ?", shareID)
+	if !start.IsZero() {
+		visitorQuery = visitorQuery.Where("last_visit_at >= ?", start)
+	}
+	if !end.IsZero() {
+		visitorQuery = visitorQuery.Where("created_at < ?", end)
+	}
+	if err := visitorQuery.Order("created_at ASC").Limit(maxShareAuditRecords).
+		Find(&report.Visitors).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询访客信息失败")
+	}
+
+	return report, nil
+}
+
+func buildShareAuditDownloads(logs []models.FileDownloadLog) []ShareAuditDownload {
+	fileIDs := make([]string, 0, len(logs))
+	for _, l := range logs {
+		fileIDs = append(fileIDs, l.FileID)
+	}
+	fileIDs = uniqueStrings(fileIDs)
+
+	names := make(map[string]string, len(fileIDs))
+	if len(fileIDs) > 0 {
+		var files []models.File
+		database.DB.Select("id, original_name, display_name").
+			Where("id IN ?", fileIDs).Find(&files)
+		for _, f := range files {
+			name := f.DisplayName
+			if name == "" {
+				name = f.OriginalName
+			}
+			names[f.ID] = name
+		}
+	}
+
+	result := make([]ShareAuditDownload, 0, len(logs))
+	for _, l := range logs {
+		result = append(result, ShareAuditDownload{
+			DownloadedAt: l.CreatedAt,
+			FileID:       l.FileID,
+			FileName:     names[l.FileID],
+			FileSize:     l.FileSize,
+			IPAddress:    l.IPAddress,
+			UserAgent:    l.UserAgent,
+		})
+	}
+	return result
+}
@@ -5,6 +5,7 @@ import (
 	"pixelpunk/internal/models"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
 	"pixelpunk/pkg/utils"
 	"time"
 
@@ -12,17 +13,30 @@ import (
 )
 
 func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
-	shareKey := utils.GenerateRandomString(16)
+	password, err := hashSharePassword(req.Password)
+	if err != nil {
+		return models.Share{}, err
+	}
+	return createShare(userID, req, password)
+}
 
-	for {
-		var count int64
-		if err := database.DB.Model(&models.Share{}).Where("share_key = ?", shareKey).Count(&count).Error; err != nil {
+// createShare password 为已哈希的密码
+func createShare(userID uint, req *dto.CreateShareDTO, password string) (models.Share, error) {
+	if err := validateShareWatermarkConfig(req.WatermarkEnabled, req.WatermarkConfig); err != nil {
+		return models.Share{}, err
+	}
+
+	if req.ShareKey != "" {
+		slug, err := ValidateShareSlug(req.ShareKey)
+		if err != nil {
 			return models.Share{}, err
 		}
-		if count == 0 {
-			break
-		}
-		shareKey = utils.GenerateRandomString(16)
+		req.ShareKey = slug
+	}
+
+	shareKey := req.ShareKey
+	if shareKey == "" {
+		shareKey = generateShareKey()
 	}
 
 	share := models.Share{
@@ -31,12 +45,21 @@ func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
 		ShareKey:             shareKey,
 		Name:                 req.Name,
 		Description:          req.Description,
-		Password:             req.Password,
+		Password:             password,
 		ExpiredDays:          req.ExpiredDays,
 		MaxViews:             req.MaxViews,
+		MaxDownloads:         req.MaxDownloads,
+		MaxDownloadBytes:     req.MaxDownloadMB * 1024 * 1024,
 		Status:               common.ShareStatusNormal,
-		CollectVisitorInfo:   req.CollectVisitorInfo,
+		ShareType:            common.ShareTypeView,
+		CollectVisitorInfo:   req.CollectVisitorInfo || req.RequireEmailVerification,
 		NotificationOnAccess: req.NotificationOnAccess,
+		WatermarkEnabled:     req.WatermarkEnabled,
+		WatermarkConfig:      req.WatermarkConfig,
+	}
+
+	if req.RequireEmailVerification {
+		share.RequireEmailVerification = true
 	}
 
 	if req.NotificationOnAccess && req.NotificationThreshold > 0 {
@@ -45,14 +68,43 @@ func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
 		share.NotificationThreshold = 100
 	}
 
+	if req.ShareType == common.ShareTypeCollect {
+		if err := applyCollectOptions(userID, &share, req); err != nil {
+			return models.Share{}, err
+		}
+	}
+
+	excludedFiles, err := validateExcludedFiles(userID, req.ExcludedFiles)
+	if err != nil {
+		return models.Share{}, err
+	}
+
+	var recipientIDs []uint
+	if req.AccessMode == common.ShareAccessModeUsers {
+		ids, err := resolveShareRecipients(userID, req.Recipients)
+		if err != nil {
+			return models.Share{}, err
+		}
+		recipientIDs = ids
+		share.AccessMode = common.ShareAccessModeUsers
+	}
+
+	// 设置了开放时间时，有效期从开放时间开始计算
+	validFrom := time.Now()
+	if req.StartsAt != nil && req.StartsAt.After(validFrom) {
+		startsAt := common.JSONTime(*req.StartsAt)
+		share.StartsAt = &startsAt
+		validFrom = *req.StartsAt
+	}
+
 	if req.ExpiredDays > 0 {
-		expiredAt := time.Now().AddDate(0, 0, req.ExpiredDays)
+		expiredAt := validFrom.AddDate(0, 0, req.ExpiredDays)
 		jsonTime := common.JSONTime(expiredAt)
 		share.ExpiredAt = &jsonTime
 	}
 
 	// 使用 GORM Transaction 方法替代手动事务管理，确保 SQLite 兼容性
-	err := database.DB.Transaction(func(tx *gorm.DB) error {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&share).Error; err != nil {
 			return err
 		}
@@ -64,6 +116,8 @@ func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
 				ItemType:  item.ItemType,
 				ItemID:    item.ItemID,
 				SortOrder: i,
+				Caption:   item.Caption,
+				Duration:  item.Duration,
 			}
 
 			if err := tx.Create(&shareItem).Error; err != nil {
@@ -71,7 +125,10 @@ func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
 			}
 		}
 
-		return nil
+		if err := saveShareExclusions(tx, share.ID, excludedFiles); err != nil {
+			return err
+		}
+		return saveShareRecipients(tx, share.ID, recipientIDs)
 	})
 
 	if err != nil {
@@ -80,3 +137,43 @@ func CreateShare(userID uint, req *dto.CreateShareDTO) (models.Share, error) {
 
 	return share, nil
 }
+
+// applyCollectOptions 收集型分享以第一个分享的文件夹作为访客上传目标
+func applyCollectOptions(userID uint, share *models.Share, req *dto.CreateShareDTO) error {
+	for _, item := range req.Items {
+		if item.ItemType != common.ShareItemTypeFolder {
+			continue
+		}
+		var count int64
+		if err := database.DB.Model(&models.Folder{}).Where("id = ? AND user_id = ?", item.ItemID, userID).Count(&count).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败")
+		}
+		if count == 0 {
+			return errors.New(errors.CodeFolderNotFound, "上传目标文件夹不存在")
+		}
+		share.UploadFolderID = item.ItemID
+		break
+	}
+	if share.UploadFolderID == "" {
+		return errors.New(errors.CodeInvalidParameter, "收集型分享至少需要包含一个文件夹")
+	}
+
+	share.ShareType = common.ShareTypeCollect
+	share.UploadMaxFileSizeMB = req.UploadMaxFileSizeMB
+	share.UploadMaxFiles = req.UploadMaxFiles
+	share.UploadAllowedFormats = normalizeUploadFormats(req.UploadAllowedFormats)
+	share.UploadModeration = req.UploadModeration
+	return nil
+}
+
+// generateShareKey 生成未被占用的随机分享密钥
+func generateShareKey() string {
+	shareKey := utils.GenerateRandomString(16)
+	for {
+		var count int64
+		if err := database.DB.Model(&models.Share{}).Where("share_key = ?", shareKey).Count(&count).Error; err != nil || count == 0 {
+			return shareKey
+		}
+		shareKey = utils.GenerateRandomString(16)
+	}
+}
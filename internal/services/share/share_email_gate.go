@@ -0,0 +1,208 @@
+package share
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/ratelimit"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/email"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+const (
+	shareEmailCodeTTL      = 10 * time.Minute
+	shareEmailCodeInterval = time.Minute
+	shareEmailCodeMaxTries = 5
+	shareEmailTokenTTL     = 24 * time.Hour
+)
+
+/* SendShareEmailCode 向访客邮箱发送访问验证码。
+ * 同一分享同一邮箱每分钟一封，另按来源IP、分享及全站限制每小时的发送次数，防止被用来批量发信 */
+func SendShareEmailCode(shareKey, emailAddr, clientIP string) error {
+	share, err := GetShareByKey(shareKey)
+	if err != nil {
+		return err
+	}
+	if !share.RequireEmailVerification {
+		return errors.New(errors.CodeInvalidParameter, "该分享不需要验证邮箱")
+	}
+	if !email.IsMailEnabled() {
+		return errors.New(errors.CodeEmailServiceError, "邮件服务不可用，请联系分享者")
+	}
+
+	emailAddr = normalizeVisitorEmail(emailAddr)
+	rateKey := fmt.Sprintf("share_email_rate:%s:%s", share.ID, emailAddr)
+	if cache.Exists(rateKey) {
+		return errors.New(errors.CodeRateLimited, "请求过于频繁，请一分钟后再试")
+	}
+	if err := checkShareEmailSendCaps(share.ID, clientIP); err != nil {
+		return err
+	}
+
+	code, err := generateShareEmailCode()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "生成验证码失败")
+	}
+	codeKey := shareEmailCodeKey(share.ID, emailAddr)
+	if err := cache.Set(codeKey, code, shareEmailCodeTTL); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "保存验证码失败")
+	}
+	cache.Del(codeKey + ":tries")
+
+	shareName := share.Name
+	if shareName == "" {
+		shareName = share.ShareKey
+	}
+	siteName := setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk")
+	subject := fmt.Sprintf("【%s】分享访问验证码", siteName)
+	body := fmt.Sprintf("您正在访问分享「%s」，验证码是: %s，%d分钟内有效。", shareName, code, int(shareEmailCodeTTL.Minutes()))
+	if err := email.SendMail(emailAddr, subject, body); err != nil {
+		logger.Error("发送分享访问验证码失败: shareID=%s, error=%v", share.ID, err)
+		return errors.Wrap(err, errors.CodeEmailSendFailed, "发送邮件失败")
+	}
+
+	_ = cache.Set(rateKey, "1", shareEmailCodeInterval)
+	return nil
+}
+
+// checkShareEmailSendCaps 检查来源IP、分享与全站每小时的验证码发送上限，设置为 0 表示不限制
+func checkShareEmailSendCaps(shareID, clientIP string) error {
+	if clientIP == "" {
+		clientIP = "unknown"
+	}
+	perIP := setting.GetIntDirectFromDB("share", "share_email_code_ip_hourly", 10)
+	if !ratelimit.Hit("share_email_ip", clientIP, perIP, time.Hour) {
+		return errors.New(errors.CodeRateLimited, "发送验证码过于频繁，请稍后再试")
+	}
+	perShare := setting.GetIntDirectFromDB("share", "share_email_code_share_hourly", 50)
+	if !ratelimit.Hit("share_email_share", shareID, perShare, time.Hour) {
+		return errors.New(errors.CodeRateLimited, "该分享的验证码发送次数已达上限，请稍后再试")
+	}
+	global := setting.GetIntDirectFromDB("share", "share_email_code_global_hourly", 200)
+	if !ratelimit.Hit("share_email_global", "all", global, time.Hour) {
+		return errors.New(errors.CodeRateLimited, "验证码发送次数已达上限，请稍后再试")
+	}
+	return nil
+}
+
+// VerifyShareEmailCode 校验验证码，通过后记录访客信息并签发邮箱访问令牌
+func VerifyShareEmailCode(shareKey string, req *dto.ShareEmailVerifyDTO, ip, userAgent, referer string) (string, error) {
+	share, err := GetShareByKey(shareKey)
+	if err != nil {
+		return "", err
+	}
+	if !share.RequireEmailVerification {
+		return "", errors.New(errors.CodeInvalidParameter, "该分享不需要验证邮箱")
+	}
+
+	emailAddr := normalizeVisitorEmail(req.Email)
+	codeKey := shareEmailCodeKey(share.ID, emailAddr)
+	cachedCode, err := cache.Get(codeKey)
+	if err != nil || cachedCode == "" {
+		return "", errors.New(errors.CodeInvalidVerifyCode, "验证码无效或已过期")
+	}
+	if cachedCode != strings.TrimSpace(req.Code) {
+		// 超过尝试次数后作废验证码，防止暴力枚举
+		tries := 1
+		if v, err := cache.Get(codeKey + ":tries"); err == nil {
+			fmt.Sscanf(v, "%d", &tries)
+			tries++
+		}
+		if tries >= shareEmailCodeMaxTries {
+			cache.Del(codeKey)
+			cache.Del(codeKey + ":tries")
+		} else {
+			_ = cache.Set(codeKey+":tries", fmt.Sprintf("%d", tries), shareEmailCodeTTL)
+		}
+		return "", errors.New(errors.CodeInvalidVerifyCode, "验证码无效或已过期")
+	}
+	cache.Del(codeKey)
+	cache.Del(codeKey + ":tries")
+
+	visitor := &dto.VisitorInfoDTO{Name: req.Name, Email: emailAddr}
+	if visitor.Name == "" {
+		visitor.Name = emailAddr
+	}
+	if err := SaveVisitorInfo(shareKey, visitor, ip, userAgent, referer); err != nil {
+		return "", err
+	}
+	now := common.JSONTime(time.Now())
+	database.DB.Model(&models.ShareVisitorInfo{}).
+		Where("share_id = ? AND visitor_email = ?", share.ID, emailAddr).
+		Updates(map[string]interface{}{"email_verified": true, "verified_at": now})
+
+	token := utils.GenerateRandomString(32)
+	if err := cache.Set(shareEmailTokenKey(token), share.ID+"|"+emailAddr, shareEmailTokenTTL); err != nil {
+		return "", errors.Wrap(err, errors.CodeInternal, "生成访问令牌失败")
+	}
+	return token, nil
+}
+
+// ValidateShareEmailToken 校验邮箱访问令牌是否属于该分享
+func ValidateShareEmailToken(shareID, token string) bool {
+	if token == "" {
+		return false
+	}
+	value, err := cache.Get(shareEmailTokenKey(token))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(value, shareID+"|")
+}
+
+// AuthorizeShareEmail 需要验证邮箱的分享必须携带有效的邮箱令牌，分享者本人不受限制
+func AuthorizeShareEmail(share models.Share, userID uint, token string) error {
+	if !share.RequireEmailVerification || (userID != 0 && userID == share.UserID) {
+		return nil
+	}
+	if !ValidateShareEmailToken(share.ID, token) {
+		return errors.New(errors.CodeUnauthorized, "请先验证邮箱后再访问")
+	}
+	return nil
+}
+
+// ExportShareVisitors 导出分享的全部访客信息，仅分享者可用
+func ExportShareVisitors(shareID string, userID uint) ([]models.ShareVisitorInfo, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	var visitors []models.ShareVisitorInfo
+	if err := database.DB.Where("share_id = ?", shareID).
+		Order("created_at ASC").
+		Find(&visitors).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询访客信息失败")
+	}
+	return visitors, nil
+}
+
+func generateShareEmailCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func normalizeVisitorEmail(emailAddr string) string {
+	return strings.ToLower(strings.TrimSpace(emailAddr))
+}
+
+func shareEmailCodeKey(shareID, emailAddr string) string {
+	return fmt.Sprintf("share_email_code:%s:%s", shareID, emailAddr)
+}
+
+func shareEmailTokenKey(token string) string {
+	return "share_email_token:" + token
+}
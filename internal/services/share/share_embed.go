@@ -0,0 +1,167 @@
+package share
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/utils"
+)
+
+const shareEmbedMaxItems = 60
+
+// ShareEmbedItem 嵌入展示用的图片
+type ShareEmbedItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	URL      string `json:"url"`
+	ThumbURL string `json:"thumb_url"`
+}
+
+// ShareEmbed 公开分享的轻量展示数据，供 oEmbed 和嵌入页面使用
+type ShareEmbed struct {
+	ShareKey     string           `json:"share_key"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	AuthorName   string           `json:"author_name"`
+	ProviderName string           `json:"provider_name"`
+	ShareURL     string           `json:"share_url"`
+	Total        int              `json:"total"`
+	Items        []ShareEmbedItem `json:"items"`
+}
+
+// GetShareEmbed 获取可嵌入的分享内容，仅包含分享本身及顶层文件夹中的图片；
+// 密码、指定用户及邮箱验证分享无法在第三方页面中完成验证，因此不允许嵌入
+func GetShareEmbed(shareKey string, limit int) (*ShareEmbed, error) {
+	share, err := GetShareByKey(shareKey)
+	if err != nil {
+		return nil, err
+	}
+	if share.Password != "" {
+		return nil, errors.New(errors.CodeForbidden, "密码保护的分享不支持嵌入")
+	}
+	if share.IsUserRestricted() {
+		return nil, errors.New(errors.CodeForbidden, "指定用户分享不支持嵌入")
+	}
+	if share.RequireEmailVerification {
+		return nil, errors.New(errors.CodeForbidden, "需要验证邮箱的分享不支持嵌入")
+	}
+	if limit <= 0 || limit > shareEmbedMaxItems {
+		limit = shareEmbedMaxItems
+	}
+
+	items, err := GetShareItems(share.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享内容失败")
+	}
+
+	var fileIDs, folderIDs []string
+	for _, item := range items {
+		switch item.ItemType {
+		case common.ShareItemTypeFile:
+			fileIDs = append(fileIDs, item.ItemID)
+		case common.ShareItemTypeFolder:
+			folderIDs = append(folderIDs, item.ItemID)
+		}
+	}
+
+	excludedFileIDs, err := getExcludedFileIDs(share.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []models.File
+	if len(fileIDs) > 0 || len(folderIDs) > 0 {
+		query := database.DB.Where("user_id = ? AND file_type = ?", share.UserID, models.FileTypeImage).
+			Where("status NOT IN ?", hiddenShareFileStatuses)
+		query = excludeSharedFiles(query, excludedFileIDs)
+		switch {
+		case len(fileIDs) > 0 && len(folderIDs) > 0:
+			query = query.Where("(id IN ? OR folder_id IN ?)", fileIDs, folderIDs)
+		case len(fileIDs) > 0:
+			query = query.Where("id IN ?", fileIDs)
+		default:
+			query = query.Where("folder_id IN ?", folderIDs)
+		}
+		if err := query.Order("sort_order ASC, created_at DESC").Find(&files).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享文件失败")
+		}
+	}
+
+	var user models.User
+	database.DB.Select("id, username").Where("id = ?", share.UserID).First(&user)
+
+	title := share.Name
+	if title == "" {
+		title = user.Username + " 的分享"
+	}
+
+	embed := &ShareEmbed{
+		ShareKey:     share.ShareKey,
+		Title:        title,
+		Description:  share.Description,
+		AuthorName:   user.Username,
+		ProviderName: setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk"),
+		ShareURL:     utils.GetBaseUrl() + "/share/" + share.ShareKey,
+		Total:        len(files),
+		Items:        make([]ShareEmbedItem, 0, limit),
+	}
+
+	for _, file := range files {
+		if len(embed.Items) >= limit {
+			break
+		}
+		fullURL, fullThumbURL, _ := storage.GetFullURLs(file)
+		name := file.DisplayName
+		if name == "" {
+			name = file.OriginalName
+		}
+		embed.Items = append(embed.Items, ShareEmbedItem{
+			ID:       file.ID,
+			Name:     name,
+			Width:    file.Width,
+			Height:   file.Height,
+			URL:      appendShareParam(fullURL, shareKey, file.ID, ""),
+			ThumbURL: appendShareParam(fullThumbURL, shareKey, file.ID, ""),
+		})
+	}
+
+	return embed, nil
+}
+
+// ParseShareKeyFromURL 从分享链接中解析分享密钥，支持 /share/:key 形式
+func ParseShareKeyFromURL(rawURL string) string {
+	idx := strings.Index(rawURL, "/share/")
+	if idx < 0 {
+		return ""
+	}
+	key := rawURL[idx+len("/share/"):]
+	if end := strings.IndexAny(key, "/?#"); end >= 0 {
+		key = key[:end]
+	}
+	return key
+}
+
+// appendShareParam 为分享文件URL附加分享标识和绑定访客凭证的单文件签名令牌
+func appendShareParam(rawURL, shareKey, fileID, grant string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+	expiry, signature := utils.GetURLSigner().SignShareFile(shareKey, fileID, grant, utils.SIGNATURE_DURATION)
+	params := fmt.Sprintf("share=%s&st=%d&ss=%s", shareKey, expiry, signature)
+	if grant != "" {
+		params += "&sg=" + url.QueryEscape(grant)
+	}
+	if strings.Contains(rawURL, "?") {
+		return rawURL + "&" + params
+	}
+	return rawURL + "?" + params
+}
@@ -0,0 +1,103 @@
+package share
+
+import (
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+const maxShareExclusions = 1000
+
+// GetShareExclusions 获取分享中被排除的文件ID，仅分享者可用
+func GetShareExclusions(shareID string, userID uint) ([]string, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+	return getExcludedFileIDs(shareID)
+}
+
+// UpdateShareExclusions 替换分享的排除列表，传空列表表示取消全部排除
+func UpdateShareExclusions(shareID string, userID uint, fileIDs []string) ([]string, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	fileIDs, err := validateExcludedFiles(userID, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return saveShareExclusions(tx, shareID, fileIDs)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新排除列表失败")
+	}
+	return fileIDs, nil
+}
+
+// validateExcludedFiles 去重并确认文件均属于分享者
+func validateExcludedFiles(userID uint, fileIDs []string) ([]string, error) {
+	fileIDs = uniqueStrings(fileIDs)
+	if len(fileIDs) > maxShareExclusions {
+		return nil, errors.New(errors.CodeInvalidParameter, "排除的文件数量超过上限")
+	}
+	if len(fileIDs) == 0 {
+		return fileIDs, nil
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.File{}).
+		Where("id IN ? AND user_id = ?", fileIDs, userID).
+		Count(&count).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败")
+	}
+	if int(count) != len(fileIDs) {
+		return nil, errors.New(errors.CodeFileNotFound, "部分排除的文件不存在")
+	}
+	return fileIDs, nil
+}
+
+func saveShareExclusions(tx *gorm.DB, shareID string, fileIDs []string) error {
+	if err := tx.Where("share_id = ?", shareID).Delete(&models.ShareExclusion{}).Error; err != nil {
+		return err
+	}
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	exclusions := make([]models.ShareExclusion, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		exclusions = append(exclusions, models.ShareExclusion{ShareID: shareID, FileID: id})
+	}
+	return tx.Create(&exclusions).Error
+}
+
+func getExcludedFileIDs(shareID string) ([]string, error) {
+	var fileIDs []string
+	if err := database.DB.Model(&models.ShareExclusion{}).
+		Where("share_id = ?", shareID).
+		Pluck("file_id", &fileIDs).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询排除列表失败")
+	}
+	return fileIDs, nil
+}
+
+func isFileExcluded(shareID, fileID string) (bool, error) {
+	var count int64
+	if err := database.DB.Model(&models.ShareExclusion{}).
+		Where("share_id = ? AND file_id = ?", shareID, fileID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// excludeSharedFiles 为文件查询追加排除条件
+func excludeSharedFiles(db *gorm.DB, excluded []string) *gorm.DB {
+	if len(excluded) == 0 {
+		return db
+	}
+	return db.Where("id NOT IN ?", excluded)
+}
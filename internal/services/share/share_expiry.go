@@ -0,0 +1,192 @@
+package share
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"time"
+
+	"pixelpunk/internal/models"
+	messageService "pixelpunk/internal/services/message"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/email"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// NotifyExpiringShares 在分享过期前提醒分享者，每个分享在一次有效期内只提醒一次
+func NotifyExpiringShares() (int, error) {
+	hours := setting.GetIntDirectFromDB("share", "share_expiry_reminder_hours", 24)
+	if hours <= 0 {
+		return 0, nil
+	}
+	extendDays := setting.GetIntDirectFromDB("share", "share_extend_days", 7)
+	sendEmail := setting.GetBoolDirectFromDB("share", "share_expiry_reminder_email", false)
+
+	now := time.Now()
+	var shares []models.Share
+	if err := database.DB.Where("status = ? AND expired_at IS NOT NULL AND expired_at > ? AND expired_at <= ? AND expiry_reminded_at IS NULL",
+		common.ShareStatusNormal,
+		now,
+		now.Add(time.Duration(hours)*time.Hour),
+	).Find(&shares).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询即将过期的分享失败")
+	}
+	if len(shares) == 0 {
+		return 0, nil
+	}
+
+	msgService := messageService.GetMessageService()
+	// 模板本身开启邮件时由消息服务发送，避免重复
+	if sendEmail {
+		if template, err := msgService.GetMessageTemplate(common.MessageTypeShareExpiryWarning); err == nil && template.ShouldSendEmail() {
+			sendEmail = false
+		}
+	}
+
+	notifiedCount := 0
+	for _, shareObj := range shares {
+		token := generateID()
+		remindedAt := common.JSONTime(now)
+		// 以 expiry_reminded_at IS NULL 为条件占位，多实例同时执行时只有一个能发出提醒
+		result := database.DB.Model(&models.Share{}).
+			Where("id = ? AND expiry_reminded_at IS NULL", shareObj.ID).
+			Updates(map[string]interface{}{
+				"expiry_reminded_at": &remindedAt,
+				"extend_token":       token,
+			})
+		if result.Error != nil {
+			logger.Warn("标记分享过期提醒失败: shareID=%s, error=%v", shareObj.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		expiresIn := formatExpiresIn(time.Until(time.Time(*shareObj.ExpiredAt)))
+		extendURL := utils.GetSystemFileURL("/api/v1/shares/extend/" + token)
+		variables := map[string]interface{}{
+			"share_id":     shareObj.ID,
+			"share_name":   shareObj.Name,
+			"expires_in":   expiresIn,
+			"extend_days":  extendDays,
+			"extend_url":   extendURL,
+			"related_type": "share",
+			"related_id":   shareObj.ID,
+		}
+
+		if err := msgService.SendTemplateMessage(shareObj.UserID, common.MessageTypeShareExpiryWarning, variables); err != nil {
+			logger.Warn("发送分享过期提醒失败: userID=%d, shareID=%s, error=%v", shareObj.UserID, shareObj.ID, err)
+			// 发送失败时撤销占位，下次执行重试
+			database.DB.Model(&models.Share{}).Where("id = ?", shareObj.ID).
+				Updates(map[string]interface{}{"expiry_reminded_at": nil, "extend_token": ""})
+			continue
+		}
+		notifiedCount++
+
+		if sendEmail {
+			go sendShareExpiryEmail(shareObj, expiresIn, extendDays, extendURL)
+		}
+	}
+
+	return notifiedCount, nil
+}
+
+// ExtendShare 分享者手动续期，days 为 0 时使用系统默认续期天数
+func ExtendShare(shareID string, userID uint, days int) (*models.Share, error) {
+	var shareObj models.Share
+	if err := database.DB.Where("id = ? AND user_id = ?", shareID, userID).First(&shareObj).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "分享不存在或您无权访问")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+	return extendShare(&shareObj, days)
+}
+
+// ExtendShareByToken 通过过期提醒中的一键续期链接续期，令牌使用一次后失效
+func ExtendShareByToken(token string) (*models.Share, error) {
+	if token == "" {
+		return nil, errors.New(errors.CodeInvalidParameter, "续期链接无效")
+	}
+
+	var shareObj models.Share
+	if err := database.DB.Where("extend_token = ?", token).First(&shareObj).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "续期链接无效或已使用")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+	return extendShare(&shareObj, 0)
+}
+
+func extendShare(shareObj *models.Share, days int) (*models.Share, error) {
+	if days <= 0 {
+		days = setting.GetIntDirectFromDB("share", "share_extend_days", 7)
+	}
+	if days <= 0 {
+		days = 7
+	}
+
+	if shareObj.ExpiredAt == nil {
+		return nil, errors.New(errors.CodeInvalidParameter, "该分享永不过期，无需续期")
+	}
+	if shareObj.Status != common.ShareStatusNormal && shareObj.Status != common.ShareStatusExpired {
+		return nil, errors.New(errors.CodeForbidden, "该分享已被删除或禁用，无法续期")
+	}
+
+	// 未过期时在原过期时间上顺延，已过期则从当前时间起算
+	base := time.Time(*shareObj.ExpiredAt)
+	if now := time.Now(); base.Before(now) {
+		base = now
+	}
+	expiredAt := common.JSONTime(base.AddDate(0, 0, days))
+	expiredDays := int(math.Ceil(time.Time(expiredAt).Sub(time.Time(shareObj.CreatedAt)).Hours() / 24))
+
+	if err := database.DB.Model(&models.Share{}).Where("id = ?", shareObj.ID).Updates(map[string]interface{}{
+		"expired_at":         &expiredAt,
+		"expired_days":       expiredDays,
+		"status":             common.ShareStatusNormal,
+		"expiry_reminded_at": nil,
+		"extend_token":       "",
+	}).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "分享续期失败")
+	}
+
+	shareObj.ExpiredAt = &expiredAt
+	shareObj.ExpiredDays = expiredDays
+	shareObj.Status = common.ShareStatusNormal
+	shareObj.ExpiryRemindedAt = nil
+	shareObj.ExtendToken = ""
+	return shareObj, nil
+}
+
+func formatExpiresIn(remaining time.Duration) string {
+	if remaining < time.Hour {
+		return "1小时内"
+	}
+	return fmt.Sprintf("%d小时", int(remaining.Hours()))
+}
+
+func sendShareExpiryEmail(shareObj models.Share, expiresIn string, extendDays int, extendURL string) {
+	if !email.IsMailEnabled() {
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Select("email").Where("id = ?", shareObj.UserID).First(&user).Error; err != nil || user.Email == "" {
+		return
+	}
+
+	subject := "分享即将过期"
+	body := fmt.Sprintf("您的分享「%s」将于 %s 后过期。过期后，访问链接将失效。<br/>点击链接可一键续期 %d 天：<a href=\"%s\">%s</a>",
+		html.EscapeString(shareObj.Name), expiresIn, extendDays, extendURL, extendURL)
+	if err := email.SendMail(user.Email, subject, body); err != nil {
+		logger.Warn("发送分享过期提醒邮件失败: shareID=%s, error=%v", shareObj.ID, err)
+	}
+}
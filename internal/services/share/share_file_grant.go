@@ -0,0 +1,123 @@
+package share
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+)
+
+// ShareGrant 访客打开分享时出示的凭证，分享页签发的单文件令牌与之绑定，凭证撤销或过期后令牌随之失效
+type ShareGrant struct {
+	AccessTokenID string // 密码验证后取得的访问令牌记录ID
+	InvitationID  string
+	EmailToken    string
+	UserID        uint // 已登录的访问者，用于指定用户分享与分享者本人
+}
+
+const shareGrantSep = "~"
+
+// Encode 编码为单文件令牌的 sg 参数，没有任何凭证时为空
+func (g ShareGrant) Encode() string {
+	var parts []string
+	if g.AccessTokenID != "" {
+		parts = append(parts, "t."+g.AccessTokenID)
+	}
+	if g.InvitationID != "" {
+		parts = append(parts, "i."+g.InvitationID)
+	}
+	if g.EmailToken != "" {
+		parts = append(parts, "e."+g.EmailToken)
+	}
+	if g.UserID != 0 {
+		parts = append(parts, "u."+strconv.FormatUint(uint64(g.UserID), 10))
+	}
+	return strings.Join(parts, shareGrantSep)
+}
+
+// ParseShareGrant 解析 sg 参数，无法识别的部分忽略
+func ParseShareGrant(raw string) ShareGrant {
+	var g ShareGrant
+	for _, part := range strings.Split(raw, shareGrantSep) {
+		kind, value, ok := strings.Cut(part, ".")
+		if !ok || value == "" {
+			continue
+		}
+		switch kind {
+		case "t":
+			g.AccessTokenID = value
+		case "i":
+			g.InvitationID = value
+		case "e":
+			g.EmailToken = value
+		case "u":
+			if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+				g.UserID = uint(id)
+			}
+		}
+	}
+	return g
+}
+
+/* AuthorizeSharedFile 校验单文件令牌对应的访问：令牌签名证明文件签发时属于该分享，
+ * 此处仍需校验分享状态与访问/下载上限、绑定的访客凭证、排除项及文件状态 */
+func AuthorizeSharedFile(shareKey, fileID string, grant ShareGrant) bool {
+	var share models.Share
+	if err := database.DB.Where("share_key = ? AND status = ?", shareKey, common.ShareStatusNormal).
+		First(&share).Error; err != nil {
+		return false
+	}
+
+	var token *models.ShareAccessToken
+	if grant.AccessTokenID != "" {
+		var t models.ShareAccessToken
+		if err := database.DB.Where("id = ? AND share_key = ? AND expired_at > ? AND revoked_at IS NULL",
+			grant.AccessTokenID, shareKey, time.Now()).First(&t).Error; err != nil {
+			return false
+		}
+		token = &t
+	}
+
+	// 管理员令牌与 ValidateAccessToken 一致，不受分享过期及次数限制
+	if (token == nil || !token.IsAdmin) && !share.IsAccessible() {
+		return false
+	}
+	if AuthorizeShareViewer(share, grant.UserID) != nil {
+		return false
+	}
+
+	invited := false
+	if grant.InvitationID != "" {
+		var count int64
+		database.DB.Model(&models.ShareInvitation{}).
+			Where("id = ? AND share_id = ? AND revoked_at IS NULL", grant.InvitationID, share.ID).
+			Count(&count)
+		if count == 0 {
+			return false
+		}
+		invited = true
+	}
+	if share.Password != "" && !invited && token == nil {
+		return false
+	}
+	if !invited && AuthorizeShareEmail(share, grant.UserID, grant.EmailToken) != nil {
+		return false
+	}
+
+	var excludedCount int64
+	database.DB.Model(&models.ShareExclusion{}).
+		Where("share_id = ? AND file_id = ?", share.ID, fileID).
+		Count(&excludedCount)
+	if excludedCount > 0 {
+		return false
+	}
+
+	var fileCount int64
+	database.DB.Model(&models.File{}).
+		Where("id = ? AND user_id = ? AND status NOT IN ?", fileID, share.UserID, hiddenShareFileStatuses).
+		Count(&fileCount)
+	return fileCount > 0
+}
@@ -3,9 +3,14 @@ package share
 import (
 	"strings"
 
+	"pixelpunk/pkg/common"
+
 	"github.com/google/uuid"
 )
 
+// 待删除、待管理员审核及收集型分享中未经分享者审核的访客上传不对访客展示
+var hiddenShareFileStatuses = []string{"pending_deletion", "pending_review", common.FileStatusShareHold}
+
 func generateID() string {
 	return strings.Replace(uuid.New().String(), "-", "", -1)
 }
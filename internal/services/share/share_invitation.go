@@ -0,0 +1,198 @@
+package share
+
+import (
+	"html"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/message"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/email"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxInvitationsPerRequest = 50
+	maxInvitationsPerDay     = 200
+)
+
+// SendShareInvitations 为每个邮箱生成独立的邀请令牌并异步发送邀请邮件，已邀请过的邮箱会重新生成链接
+func SendShareInvitations(shareID string, userID uint, emails []string, note string) ([]models.ShareInvitation, error) {
+	shareObj, err := getOwnedShare(shareID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !shareObj.IsAccessible() {
+		return nil, errors.New(errors.CodeShareExpired, "分享已失效，无法发送邀请")
+	}
+	if !email.IsMailEnabled() {
+		return nil, errors.New(errors.CodeEmailServiceError, "邮件服务不可用，请联系管理员")
+	}
+
+	normalized := make([]string, 0, len(emails))
+	for _, e := range emails {
+		normalized = append(normalized, normalizeVisitorEmail(e))
+	}
+	normalized = uniqueStrings(normalized)
+	if len(normalized) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "请至少填写一个邮箱")
+	}
+	if len(normalized) > maxInvitationsPerRequest {
+		return nil, errors.New(errors.CodeInvalidParameter, "单次最多邀请50个邮箱")
+	}
+
+	var sentToday int64
+	database.DB.Model(&models.ShareInvitation{}).
+		Where("share_id = ? AND created_at > ?", shareID, time.Now().Add(-24*time.Hour)).
+		Count(&sentToday)
+	if int(sentToday)+len(normalized) > maxInvitationsPerDay {
+		return nil, errors.New(errors.CodeRateLimited, "今日邀请数量已达上限，请明天再试")
+	}
+
+	invitations := make([]models.ShareInvitation, 0, len(normalized))
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		now := common.JSONTime(time.Now())
+		// 重新邀请时作废旧链接，避免同一邮箱持有多个有效令牌
+		if err := tx.Model(&models.ShareInvitation{}).
+			Where("share_id = ? AND email IN ? AND revoked_at IS NULL", shareID, normalized).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		for _, addr := range normalized {
+			invitations = append(invitations, models.ShareInvitation{
+				ID:      generateID(),
+				ShareID: shareID,
+				Email:   addr,
+				Token:   utils.GenerateRandomString(32),
+				Message: note,
+				Status:  models.ShareInvitationStatusPending,
+			})
+		}
+		return tx.Create(&invitations).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建分享邀请失败")
+	}
+
+	go deliverShareInvitations(shareObj, userID, invitations)
+	return invitations, nil
+}
+
+func deliverShareInvitations(shareObj *models.Share, userID uint, invitations []models.ShareInvitation) {
+	var sender models.User
+	database.DB.Select("id, username").Where("id = ?", userID).First(&sender)
+
+	shareName := shareObj.Name
+	if shareName == "" {
+		shareName = shareObj.ShareKey
+	}
+	expiredAt := ""
+	if shareObj.ExpiredAt != nil {
+		expiredAt = time.Time(*shareObj.ExpiredAt).Format("2006-01-02 15:04")
+	}
+	siteName := setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk")
+	shareURL := utils.GetBaseUrl() + "/share/" + shareObj.ShareKey
+
+	for _, inv := range invitations {
+		variables := map[string]interface{}{
+			"sender_name": html.EscapeString(sender.Username),
+			"share_name":  html.EscapeString(shareName),
+			"site_name":   html.EscapeString(siteName),
+			"message":     html.EscapeString(inv.Message),
+			"invite_url":  shareURL + "?invite=" + inv.Token,
+			"expired_at":  expiredAt,
+		}
+
+		status := models.ShareInvitationStatusSent
+		title, content, err := message.GetMessageService().RenderTemplate(common.MessageTypeShareInvitation, variables)
+		if err == nil {
+			err = email.SendMail(inv.Email, title, content)
+		}
+		if err != nil {
+			logger.Warn("发送分享邀请邮件失败: shareID=%s, invitationID=%s, error=%v", shareObj.ID, inv.ID, err)
+			status = models.ShareInvitationStatusFailed
+		}
+
+		updates := map[string]interface{}{"status": status}
+		if status == models.ShareInvitationStatusSent {
+			updates["sent_at"] = common.JSONTime(time.Now())
+		}
+		database.DB.Model(&models.ShareInvitation{}).Where("id = ?", inv.ID).Updates(updates)
+	}
+}
+
+// GetShareInvitations 获取分享的邀请记录及各受邀人的打开、下载统计
+func GetShareInvitations(shareID string, userID uint) ([]models.ShareInvitation, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	var invitations []models.ShareInvitation
+	if err := database.DB.Where("share_id = ?", shareID).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享邀请失败")
+	}
+	return invitations, nil
+}
+
+// RevokeShareInvitation 吊销单个邀请链接
+func RevokeShareInvitation(shareID, invitationID string, userID uint) error {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return err
+	}
+
+	result := database.DB.Model(&models.ShareInvitation{}).
+		Where("id = ? AND share_id = ? AND revoked_at IS NULL", invitationID, shareID).
+		Update("revoked_at", common.JSONTime(time.Now()))
+	if result.Error != nil {
+		return errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "吊销邀请失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeNotFound, "邀请不存在或已吊销")
+	}
+	return nil
+}
+
+// ResolveShareInvitation 查找分享下有效的邀请，邀请链接可替代密码和邮箱验证
+func ResolveShareInvitation(shareID, token string) *models.ShareInvitation {
+	if token == "" {
+		return nil
+	}
+	var inv models.ShareInvitation
+	if err := database.DB.Where("token = ? AND share_id = ? AND revoked_at IS NULL", token, shareID).
+		First(&inv).Error; err != nil {
+		return nil
+	}
+	return &inv
+}
+
+// ValidateShareInvitation 判断邀请令牌是否对该分享有效
+func ValidateShareInvitation(shareID, token string) bool {
+	return ResolveShareInvitation(shareID, token) != nil
+}
+
+// RecordInvitationOpen 记录受邀人通过邀请链接打开分享
+func RecordInvitationOpen(invitationID string) {
+	now := common.JSONTime(time.Now())
+	database.DB.Model(&models.ShareInvitation{}).Where("id = ?", invitationID).
+		UpdateColumns(map[string]interface{}{
+			"open_count":     gorm.Expr("open_count + 1"),
+			"last_opened_at": now,
+		})
+	database.DB.Model(&models.ShareInvitation{}).
+		Where("id = ? AND first_opened_at IS NULL", invitationID).
+		UpdateColumn("first_opened_at", now)
+}
+
+// RecordInvitationDownload 记录受邀人通过邀请链接下载文件
+func RecordInvitationDownload(invitationID string) {
+	database.DB.Model(&models.ShareInvitation{}).Where("id = ?", invitationID).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1"))
+}
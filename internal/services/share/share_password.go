@@ -0,0 +1,87 @@
+package share
+
+import (
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/ratelimit"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+// sharePasswordAttemptName 分享密码错误次数的计数名称，按 分享+IP 计数
+const sharePasswordAttemptName = "share_password"
+
+// hashSharePassword 分享密码使用bcrypt存储，空密码表示无需密码
+func hashSharePassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return "", errors.Wrap(err, errors.CodeInternal, "加密分享密码失败")
+	}
+	return hashed, nil
+}
+
+// checkSharePassword 按 IP+分享 统计密码错误次数，超过上限后锁定一段时间；
+// 旧的明文密码在首次验证成功时迁移为哈希
+func checkSharePassword(share *models.Share, password, clientIP string) error {
+	if share.Password == "" {
+		return nil
+	}
+
+	maxAttempts := setting.GetIntDirectFromDB("share", "share_password_max_attempts", 5)
+	lockoutMinutes := setting.GetIntDirectFromDB("share", "share_password_lockout_minutes", 15)
+	if lockoutMinutes <= 0 {
+		lockoutMinutes = 15
+	}
+
+	lockKey := fmt.Sprintf("share:password:lock:%s:%s", share.ShareKey, clientIP)
+	c := cache.GetCache()
+
+	if maxAttempts > 0 && c.Exists(lockKey) {
+		if ttl, err := c.TTL(lockKey); err == nil && ttl > 0 {
+			return errors.New(errors.CodeRateLimited, fmt.Sprintf("密码错误次数过多，请%d分钟后再试", int(ttl.Minutes())+1))
+		}
+		return errors.New(errors.CodeRateLimited, "密码错误次数过多，请稍后再试")
+	}
+
+	if !share.CanAccessWithPassword(password) {
+		if maxAttempts <= 0 {
+			return errors.New(errors.CodeWrongPassword, "密码错误")
+		}
+
+		// 原子计数，并发的错误尝试不会相互覆盖；窗口从首次错误开始，长度与锁定时长一致
+		subject := share.ShareKey + ":" + clientIP
+		attemptCount := int(ratelimit.Incr(sharePasswordAttemptName, subject, time.Duration(lockoutMinutes)*time.Minute))
+
+		if attemptCount >= maxAttempts {
+			_ = c.Set(lockKey, "1", time.Duration(lockoutMinutes)*time.Minute)
+			ratelimit.Reset(sharePasswordAttemptName, subject)
+			logger.Warn("分享密码错误次数过多已锁定: shareKey=%s, ip=%s", share.ShareKey, clientIP)
+			return errors.New(errors.CodeRateLimited, fmt.Sprintf("密码错误次数过多，已锁定%d分钟", lockoutMinutes))
+		}
+
+		return errors.New(errors.CodeWrongPassword, fmt.Sprintf("密码错误，还有%d次尝试机会", maxAttempts-attemptCount))
+	}
+
+	ratelimit.Reset(sharePasswordAttemptName, share.ShareKey+":"+clientIP)
+
+	if !share.IsPasswordHashed() {
+		if hashed, err := hashSharePassword(password); err == nil {
+			if err := database.DB.Model(&models.Share{}).Where("id = ? AND password = ?", share.ID, share.Password).
+				Update("password", hashed).Error; err != nil {
+				logger.Warn("迁移分享密码为哈希失败: shareID=%s, error=%v", share.ID, err)
+			} else {
+				share.Password = hashed
+			}
+		}
+	}
+	return nil
+}
@@ -35,21 +35,37 @@ func GetShareByKey(shareKey string) (models.Share, error) {
 		return models.Share{}, errors.New(errors.CodeValidationFailed, "分享已过期")
 	}
 
+	if !share.IsStarted() {
+		startsAt := time.Time(*share.StartsAt).Format("2006-01-02 15:04:05")
+		return models.Share{}, errors.New(errors.CodeShareNotStarted, "分享尚未开放，开放时间: "+startsAt)
+	}
+
 	if share.MaxViews > 0 && share.CurrentViews >= share.MaxViews {
 		return models.Share{}, errors.New(errors.CodeValidationFailed, "分享已达到最大访问次数")
 	}
 
+	if share.IsDownloadQuotaExceeded() {
+		database.DB.Model(&share).Update("status", common.ShareStatusExpired)
+		return models.Share{}, errors.New(errors.CodeValidationFailed, "分享已达到下载上限")
+	}
+
 	return share, nil
 }
 
-/* VerifySharePassword 验证分享密码 */
-func VerifySharePassword(shareKey string, password string) (bool, error) {
+/* VerifySharePassword 验证分享密码，同一IP连续错误过多时锁定 */
+func VerifySharePassword(shareKey string, password string, clientIP string) (bool, error) {
 	share, err := GetShareByKey(shareKey)
 	if err != nil {
 		return false, err
 	}
 
-	return share.CanAccessWithPassword(password), nil
+	if err := checkSharePassword(&share, password, clientIP); err != nil {
+		if errors.IsCode(err, errors.CodeWrongPassword) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 /* GetShareItems 获取分享的项目列表 */
@@ -242,6 +258,7 @@ func GetUserShares(userID uint, query *dto.ShareListQueryDTO) ([]map[string]inte
 			"name":                   share.Name,
 			"description":            share.Description,
 			"expired_days":           share.ExpiredDays,
+			"starts_at":              share.StartsAt,
 			"expired_at":             share.ExpiredAt,
 			"max_views":              share.MaxViews,
 			"current_views":          share.CurrentViews,
@@ -251,7 +268,16 @@ func GetUserShares(userID uint, query *dto.ShareListQueryDTO) ([]map[string]inte
 			"folder_count":           folderCount,
 			"file_count":             fileCount,
 			"collect_visitor_info":   share.CollectVisitorInfo,
+			"require_email":          share.RequireEmailVerification,
+			"watermark_enabled":      share.WatermarkEnabled,
 			"notification_on_access": share.NotificationOnAccess,
+			"share_type":             share.ShareType,
+			"access_mode":            share.AccessMode,
+			"upload_count":           share.UploadCount,
+			"max_downloads":          share.MaxDownloads,
+			"download_count":         share.DownloadCount,
+			"max_download_bytes":     share.MaxDownloadBytes,
+			"download_bytes":         share.DownloadBytes,
 		}
 
 		result[i] = shareMap
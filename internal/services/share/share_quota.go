@@ -0,0 +1,43 @@
+package share
+
+import (
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// CheckShareDownloadQuota 下载前检查分享剩余的下载次数和流量是否足够
+func CheckShareDownloadQuota(shareInfo models.Share, bytes int64) error {
+	if shareInfo.MaxDownloads > 0 && shareInfo.DownloadCount >= shareInfo.MaxDownloads {
+		return errors.New(errors.CodeShareAccessExceeded, "分享已达到最大下载次数")
+	}
+	if shareInfo.MaxDownloadBytes > 0 && shareInfo.DownloadBytes+bytes > shareInfo.MaxDownloadBytes {
+		return errors.New(errors.CodeShareAccessExceeded, "分享剩余下载流量不足")
+	}
+	return nil
+}
+
+// RecordShareDownload 累计分享的下载次数和流量，用尽后分享自动失效
+func RecordShareDownload(shareID string, bytes int64) {
+	if err := database.DB.Model(&models.Share{}).Where("id = ?", shareID).
+		UpdateColumns(map[string]interface{}{
+			"download_count": gorm.Expr("download_count + 1"),
+			"download_bytes": gorm.Expr("download_bytes + ?", bytes),
+		}).Error; err != nil {
+		logger.Error("更新分享下载统计失败: shareID=%s, error=%v", shareID, err)
+		return
+	}
+
+	var shareInfo models.Share
+	if err := database.DB.Where("id = ?", shareID).First(&shareInfo).Error; err != nil {
+		return
+	}
+	if shareInfo.Status == common.ShareStatusNormal && shareInfo.IsDownloadQuotaExceeded() {
+		database.DB.Model(&models.Share{}).Where("id = ?", shareID).Update("status", common.ShareStatusExpired)
+		logger.Info("分享下载额度已用尽，自动失效: shareID=%s", shareID)
+	}
+}
@@ -0,0 +1,221 @@
+package share
+
+import (
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+const maxShareRecipients = 200
+
+// AuthorizeShareViewer 指定用户分享只允许分享者本人和接收者访问，其余分享不做限制
+func AuthorizeShareViewer(share models.Share, userID uint) error {
+	if !share.IsUserRestricted() {
+		return nil
+	}
+	if userID == 0 {
+		return errors.New(errors.CodeUnauthorized, "该分享仅限指定用户访问，请先登录")
+	}
+	if userID == share.UserID {
+		return nil
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.ShareRecipient{}).
+		Where("share_id = ? AND user_id = ?", share.ID, userID).
+		Count(&count).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享接收者失败")
+	}
+	if count == 0 {
+		return errors.New(errors.CodeForbidden, "您没有访问该分享的权限")
+	}
+	return nil
+}
+
+// resolveShareRecipients 按用户名或邮箱查找接收者，任一不存在时返回错误
+func resolveShareRecipients(ownerID uint, identifiers []string) ([]uint, error) {
+	identifiers = normalizeRecipientIdentifiers(identifiers)
+	if len(identifiers) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "请至少指定一个接收者")
+	}
+	if len(identifiers) > maxShareRecipients {
+		return nil, errors.New(errors.CodeInvalidParameter, "接收者数量超过上限")
+	}
+
+	var users []models.User
+	if err := database.DB.Select("id, username, email").
+		Where("username IN ? OR email IN ?", identifiers, identifiers).
+		Where("status = ?", common.UserStatusNormal).
+		Find(&users).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询用户失败")
+	}
+
+	found := make(map[string]uint, len(users)*2)
+	for _, u := range users {
+		found[u.Username] = u.ID
+		if u.Email != "" {
+			found[u.Email] = u.ID
+		}
+	}
+
+	var missing []string
+	seen := make(map[uint]struct{})
+	var userIDs []uint
+	for _, identifier := range identifiers {
+		id, ok := found[identifier]
+		if !ok {
+			missing = append(missing, identifier)
+			continue
+		}
+		if id == ownerID {
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		userIDs = append(userIDs, id)
+	}
+	if len(missing) > 0 {
+		return nil, errors.New(errors.CodeNotFound, "以下用户不存在: "+strings.Join(missing, ", "))
+	}
+	if len(userIDs) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "不能只分享给自己")
+	}
+	return userIDs, nil
+}
+
+func saveShareRecipients(tx *gorm.DB, shareID string, userIDs []uint) error {
+	if err := tx.Where("share_id = ?", shareID).Delete(&models.ShareRecipient{}).Error; err != nil {
+		return err
+	}
+	recipients := make([]models.ShareRecipient, 0, len(userIDs))
+	for _, id := range userIDs {
+		recipients = append(recipients, models.ShareRecipient{ShareID: shareID, UserID: id})
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	return tx.Create(&recipients).Error
+}
+
+// GetShareRecipients 分享者查看接收者列表
+func GetShareRecipients(shareID string, userID uint) ([]models.ShareRecipient, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	var recipients []models.ShareRecipient
+	if err := database.DB.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, username, avatar")
+	}).Where("share_id = ?", shareID).Order("id ASC").Find(&recipients).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享接收者失败")
+	}
+	return recipients, nil
+}
+
+// UpdateShareRecipients 替换接收者列表，同时把分享切换为指定用户访问
+func UpdateShareRecipients(shareID string, userID uint, identifiers []string) ([]models.ShareRecipient, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := resolveShareRecipients(userID, identifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveShareRecipients(tx, shareID, userIDs); err != nil {
+			return err
+		}
+		return tx.Model(&models.Share{}).Where("id = ?", shareID).
+			Update("access_mode", common.ShareAccessModeUsers).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新分享接收者失败")
+	}
+	return GetShareRecipients(shareID, userID)
+}
+
+// GetReceivedShares 获取分享给当前用户的有效分享
+func GetReceivedShares(userID uint, page, size int) ([]map[string]interface{}, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = common.DefaultPageSize
+	}
+	if size > common.MaxPageSize {
+		size = common.MaxPageSize
+	}
+
+	db := database.DB.Model(&models.Share{}).
+		Joins("JOIN share_recipient ON share_recipient.share_id = share.id").
+		Where("share_recipient.user_id = ? AND share.status = ?", userID, common.ShareStatusNormal)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询收到的分享失败")
+	}
+
+	var shares []models.Share
+	if err := db.Select("share.*").Order("share.created_at DESC").
+		Offset((page - 1) * size).Limit(size).
+		Find(&shares).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询收到的分享失败")
+	}
+
+	ownerIDs := make([]uint, 0, len(shares))
+	for _, s := range shares {
+		ownerIDs = append(ownerIDs, s.UserID)
+	}
+	owners := make(map[uint]models.User)
+	if len(ownerIDs) > 0 {
+		var users []models.User
+		database.DB.Select("id, username, avatar").Where("id IN ?", ownerIDs).Find(&users)
+		for _, u := range users {
+			owners[u.ID] = u
+		}
+	}
+
+	result := make([]map[string]interface{}, 0, len(shares))
+	for _, s := range shares {
+		owner := owners[s.UserID]
+		result = append(result, map[string]interface{}{
+			"id":          s.ID,
+			"share_key":   s.ShareKey,
+			"name":        s.Name,
+			"description": s.Description,
+			"expired_at":  s.ExpiredAt,
+			"created_at":  s.CreatedAt,
+			"owner": map[string]interface{}{
+				"username": owner.Username,
+				"avatar":   owner.Avatar,
+			},
+		})
+	}
+	return result, total, nil
+}
+
+func normalizeRecipientIdentifiers(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
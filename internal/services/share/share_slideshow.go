@@ -0,0 +1,174 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+const defaultSlideshowInterval = 5
+
+// SlideshowItem 幻灯片中的一项，文件夹分享项会展开为其中的文件
+type SlideshowItem struct {
+	Order    int    `json:"order"`
+	ItemID   string `json:"item_id"` // 所属的分享项ID
+	FileID   string `json:"file_id"`
+	Name     string `json:"name"`
+	Caption  string `json:"caption"`
+	Duration int    `json:"duration"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	URL      string `json:"url"`
+	ThumbURL string `json:"thumb_url"`
+}
+
+// GetShareSlideshow 按分享者设定的顺序返回幻灯片数据，文件夹内文件沿用文件自身的排序和描述，文件URL附带绑定 grant 的单文件令牌
+func GetShareSlideshow(shareKey string, grant ShareGrant) (map[string]interface{}, error) {
+	share, err := GetShareByKey(shareKey)
+	if err != nil {
+		return nil, err
+	}
+	sg := grant.Encode()
+
+	items, err := GetShareItems(share.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享内容失败")
+	}
+	excluded, err := getExcludedFileIDs(share.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := share.SlideshowInterval
+	if interval <= 0 {
+		interval = defaultSlideshowInterval
+	}
+
+	slides := make([]SlideshowItem, 0, len(items))
+	for _, item := range items {
+		var files []models.File
+		query := database.DB.Where("user_id = ? AND file_type = ?", share.UserID, models.FileTypeImage).
+			Where("status NOT IN ?", hiddenShareFileStatuses)
+		switch item.ItemType {
+		case common.ShareItemTypeFile:
+			query = query.Where("id = ?", item.ItemID)
+		case common.ShareItemTypeFolder:
+			query = excludeSharedFiles(query.Where("folder_id = ?", item.ItemID), excluded).
+				Order("sort_order ASC, created_at ASC")
+		default:
+			continue
+		}
+		if err := query.Find(&files).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享文件失败")
+		}
+
+		duration := item.Duration
+		if duration <= 0 {
+			duration = interval
+		}
+		for _, file := range files {
+			name := file.DisplayName
+			if name == "" {
+				name = file.OriginalName
+			}
+			// 单文件项优先使用分享者填写的说明，文件夹展开的文件优先使用文件自身描述
+			caption := item.Caption
+			if caption == "" || (item.ItemType == common.ShareItemTypeFolder && file.Description != "") {
+				caption = file.Description
+			}
+			fullURL, fullThumbURL, _ := storage.GetFullURLs(file)
+			slides = append(slides, SlideshowItem{
+				Order:    len(slides),
+				ItemID:   item.ID,
+				FileID:   file.ID,
+				Name:     name,
+				Caption:  caption,
+				Duration: duration,
+				Width:    file.Width,
+				Height:   file.Height,
+				URL:      appendShareParam(fullURL, shareKey, file.ID, sg),
+				ThumbURL: appendShareParam(fullThumbURL, shareKey, file.ID, sg),
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"view": "slideshow",
+		"share": map[string]interface{}{
+			"id":          share.ID,
+			"share_key":   share.ShareKey,
+			"name":        share.Name,
+			"description": share.Description,
+			"expired_at":  share.ExpiredAt,
+		},
+		"interval": interval,
+		"total":    len(slides),
+		"items":    slides,
+	}, nil
+}
+
+// UpdateShareSlideshow 按传入顺序重排分享项并更新说明文字和停留时间，未列出的项排在最后
+func UpdateShareSlideshow(shareID string, userID uint, req *dto.ShareSlideshowDTO) ([]models.ShareItem, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := GetShareItems(shareID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享内容失败")
+	}
+	existing := make(map[string]bool, len(items))
+	for _, item := range items {
+		existing[item.ID] = true
+	}
+
+	listed := make(map[string]bool, len(req.Items))
+	for _, item := range req.Items {
+		if !existing[item.ID] {
+			return nil, errors.New(errors.CodeNotFound, "分享项不存在: "+item.ID)
+		}
+		if listed[item.ID] {
+			return nil, errors.New(errors.CodeInvalidParameter, "分享项重复: "+item.ID)
+		}
+		listed[item.ID] = true
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, item := range req.Items {
+			if err := tx.Model(&models.ShareItem{}).Where("id = ?", item.ID).
+				Updates(map[string]interface{}{
+					"sort_order": i,
+					"caption":    item.Caption,
+					"duration":   item.Duration,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		order := len(req.Items)
+		for _, item := range items {
+			if listed[item.ID] {
+				continue
+			}
+			if err := tx.Model(&models.ShareItem{}).Where("id = ?", item.ID).
+				Update("sort_order", order).Error; err != nil {
+				return err
+			}
+			order++
+		}
+		if req.Interval > 0 {
+			return tx.Model(&models.Share{}).Where("id = ?", shareID).
+				Update("slideshow_interval", req.Interval).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新分享顺序失败")
+	}
+
+	return GetShareItems(shareID)
+}
@@ -0,0 +1,41 @@
+package share
+
+import (
+	"regexp"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+)
+
+var shareSlugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{1,30})[a-z0-9]$`)
+
+// 保留的链接后缀，避免与路由或常见页面混淆
+var reservedShareSlugs = map[string]struct{}{
+	"admin": {}, "api": {}, "public": {}, "share": {}, "shares": {}, "extend": {},
+	"templates": {}, "from-template": {}, "slug-check": {}, "download-files": {},
+	"login": {}, "logout": {}, "register": {}, "setup": {}, "settings": {}, "user": {},
+	"users": {}, "new": {}, "edit": {}, "help": {}, "about": {}, "static": {}, "assets": {},
+	"system": {}, "official": {}, "support": {}, "pixelpunk": {},
+}
+
+// ValidateShareSlug 校验自定义分享链接：小写字母、数字和连字符，不能是保留词且未被占用
+func ValidateShareSlug(slug string) (string, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if !shareSlugPattern.MatchString(slug) || strings.Contains(slug, "--") {
+		return "", errors.New(errors.CodeInvalidParameter, "自定义链接只能包含小写字母、数字和连字符，长度3-32，且不能以连字符开头或结尾")
+	}
+	if _, reserved := reservedShareSlugs[slug]; reserved {
+		return "", errors.New(errors.CodeInvalidParameter, "该链接为系统保留，请换一个")
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.Share{}).Where("LOWER(share_key) = ?", slug).Count(&count).Error; err != nil {
+		return "", errors.Wrap(err, errors.CodeDBQueryFailed, "检查链接是否可用失败")
+	}
+	if count > 0 {
+		return "", errors.New(errors.CodeConflict, "该链接已被使用")
+	}
+	return slug, nil
+}
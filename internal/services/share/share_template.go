@@ -0,0 +1,213 @@
+package share
+
+import (
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const maxShareTemplatesPerUser = 20
+
+// GetShareTemplates 获取用户的分享模板，默认模板排在最前
+func GetShareTemplates(userID uint) ([]models.ShareTemplate, error) {
+	var templates []models.ShareTemplate
+	if err := database.DB.Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&templates).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享模板失败")
+	}
+	return templates, nil
+}
+
+// CreateShareTemplate 创建分享模板
+func CreateShareTemplate(userID uint, req *dto.ShareTemplateDTO) (*models.ShareTemplate, error) {
+	var count int64
+	if err := database.DB.Model(&models.ShareTemplate{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享模板失败")
+	}
+	if count >= maxShareTemplatesPerUser {
+		return nil, errors.New(errors.CodeInvalidParameter, "分享模板数量已达上限")
+	}
+
+	template := &models.ShareTemplate{
+		ID:     generateID(),
+		UserID: userID,
+	}
+	if err := applyShareTemplateDTO(template, req); err != nil {
+		return nil, err
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if template.IsDefault {
+			if err := clearDefaultShareTemplate(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(template).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建分享模板失败")
+	}
+	return template, nil
+}
+
+// UpdateShareTemplate 更新分享模板，固定密码策略下未填写密码则保留原密码
+func UpdateShareTemplate(userID uint, templateID string, req *dto.ShareTemplateDTO) (*models.ShareTemplate, error) {
+	template, err := getShareTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyShareTemplateDTO(template, req); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if template.IsDefault {
+			if err := clearDefaultShareTemplate(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(template).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新分享模板失败")
+	}
+	return template, nil
+}
+
+// DeleteShareTemplate 删除分享模板，已创建的分享不受影响
+func DeleteShareTemplate(userID uint, templateID string) error {
+	result := database.DB.Where("id = ? AND user_id = ?", templateID, userID).Delete(&models.ShareTemplate{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "删除分享模板失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeNotFound, "分享模板不存在")
+	}
+	return nil
+}
+
+// CreateShareFromTemplate 按模板创建分享，随机密码策略时返回生成的明文密码
+func CreateShareFromTemplate(userID uint, req *dto.CreateShareFromTemplateDTO) (models.Share, string, error) {
+	var template *models.ShareTemplate
+	var err error
+	if req.TemplateID != "" {
+		template, err = getShareTemplate(userID, req.TemplateID)
+	} else {
+		template, err = getDefaultShareTemplate(userID)
+	}
+	if err != nil {
+		return models.Share{}, "", err
+	}
+
+	createReq := &dto.CreateShareDTO{
+		Name:                  req.Name,
+		Description:           req.Description,
+		ShareKey:              req.ShareKey,
+		StartsAt:              req.StartsAt,
+		ExpiredDays:           template.ExpiredDays,
+		MaxViews:              template.MaxViews,
+		MaxDownloads:          template.MaxDownloads,
+		MaxDownloadMB:         template.MaxDownloadBytes / 1024 / 1024,
+		Items:                 req.Items,
+		CollectVisitorInfo:    template.CollectVisitorInfo,
+		NotificationOnAccess:  template.NotificationOnAccess,
+		NotificationThreshold: template.NotificationThreshold,
+		WatermarkEnabled:      template.WatermarkEnabled,
+		WatermarkConfig:       template.WatermarkConfig,
+	}
+
+	var plainPassword, hashedPassword string
+	switch template.PasswordPolicy {
+	case models.SharePasswordPolicyRandom:
+		plainPassword = utils.GenerateRandomString(6)
+		if hashedPassword, err = hashSharePassword(plainPassword); err != nil {
+			return models.Share{}, "", err
+		}
+	case models.SharePasswordPolicyFixed:
+		hashedPassword = template.Password
+	}
+
+	share, err := createShare(userID, createReq, hashedPassword)
+	if err != nil {
+		return models.Share{}, "", err
+	}
+	return share, plainPassword, nil
+}
+
+func applyShareTemplateDTO(template *models.ShareTemplate, req *dto.ShareTemplateDTO) error {
+	if err := validateShareWatermarkConfig(req.WatermarkEnabled, req.WatermarkConfig); err != nil {
+		return err
+	}
+
+	policy := req.PasswordPolicy
+	if policy == "" {
+		policy = models.SharePasswordPolicyNone
+	}
+
+	switch policy {
+	case models.SharePasswordPolicyFixed:
+		if req.Password != "" {
+			hashed, err := hashSharePassword(req.Password)
+			if err != nil {
+				return err
+			}
+			template.Password = hashed
+		} else if template.PasswordPolicy != models.SharePasswordPolicyFixed || template.Password == "" {
+			return errors.New(errors.CodeInvalidParameter, "固定密码策略需要填写密码")
+		}
+	default:
+		template.Password = ""
+	}
+
+	threshold := req.NotificationThreshold
+	if threshold <= 0 {
+		threshold = 100
+	}
+
+	template.Name = req.Name
+	template.IsDefault = req.IsDefault
+	template.ExpiredDays = req.ExpiredDays
+	template.MaxViews = req.MaxViews
+	template.MaxDownloads = req.MaxDownloads
+	template.MaxDownloadBytes = req.MaxDownloadMB * 1024 * 1024
+	template.PasswordPolicy = policy
+	template.CollectVisitorInfo = req.CollectVisitorInfo
+	template.NotificationOnAccess = req.NotificationOnAccess
+	template.NotificationThreshold = threshold
+	template.WatermarkEnabled = req.WatermarkEnabled
+	template.WatermarkConfig = req.WatermarkConfig
+	return nil
+}
+
+func getShareTemplate(userID uint, templateID string) (*models.ShareTemplate, error) {
+	var template models.ShareTemplate
+	if err := database.DB.Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "分享模板不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享模板失败")
+	}
+	return &template, nil
+}
+
+func getDefaultShareTemplate(userID uint) (*models.ShareTemplate, error) {
+	var template models.ShareTemplate
+	if err := database.DB.Where("user_id = ? AND is_default = ?", userID, true).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "尚未设置默认分享模板")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享模板失败")
+	}
+	return &template, nil
+}
+
+func clearDefaultShareTemplate(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.ShareTemplate{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,100 @@
+package share
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// ShareTokenInfo 分享者可见的访问令牌信息，令牌本身只展示末尾几位用于辨认
+type ShareTokenInfo struct {
+	ID          string           `json:"id"`
+	TokenSuffix string           `json:"token_suffix"`
+	ClientIP    string           `json:"client_ip"`
+	UserAgent   string           `json:"user_agent"`
+	CreatedAt   common.JSONTime  `json:"created_at"`
+	ExpiredAt   common.JSONTime  `json:"expired_at"`
+	RevokedAt   *common.JSONTime `json:"revoked_at"`
+	Active      bool             `json:"active"`
+}
+
+// GetShareAccessTokens 列出分享签发的访客令牌，默认只返回仍有效的令牌；管理员令牌不对分享者展示
+func GetShareAccessTokens(shareID string, userID uint, includeInactive bool) ([]ShareTokenInfo, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, err
+	}
+
+	db := database.DB.Where("share_id = ? AND is_admin = ?", shareID, false)
+	if !includeInactive {
+		db = db.Where("expired_at > ? AND revoked_at IS NULL", time.Now())
+	}
+
+	var tokens []models.ShareAccessToken
+	if err := db.Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询访问令牌失败")
+	}
+
+	result := make([]ShareTokenInfo, 0, len(tokens))
+	for _, t := range tokens {
+		suffix := t.Token
+		if len(suffix) > 4 {
+			suffix = suffix[len(suffix)-4:]
+		}
+		result = append(result, ShareTokenInfo{
+			ID:          t.ID,
+			TokenSuffix: suffix,
+			ClientIP:    t.ClientIP,
+			UserAgent:   t.UserAgent,
+			CreatedAt:   t.CreatedAt,
+			ExpiredAt:   t.ExpiredAt,
+			RevokedAt:   t.RevokedAt,
+			Active:      !t.IsRevoked() && !t.IsExpired(),
+		})
+	}
+	return result, nil
+}
+
+// RevokeShareAccessToken 吊销单个访问令牌
+func RevokeShareAccessToken(shareID, tokenID string, userID uint) error {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return err
+	}
+
+	count, err := revokeShareTokens(database.DB.Where("share_id = ? AND id = ?", shareID, tokenID))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New(errors.CodeNotFound, "令牌不存在或已失效")
+	}
+	return nil
+}
+
+// RevokeShareAccessTokensByIP 吊销某个IP获取的全部有效令牌，clientIP 为空时吊销该分享的全部令牌
+func RevokeShareAccessTokensByIP(shareID, clientIP string, userID uint) (int64, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return 0, err
+	}
+
+	db := database.DB.Where("share_id = ?", shareID)
+	if clientIP != "" {
+		db = db.Where("client_ip = ?", clientIP)
+	}
+	return revokeShareTokens(db)
+}
+
+func revokeShareTokens(db *gorm.DB) (int64, error) {
+	now := common.JSONTime(time.Now())
+	result := db.Model(&models.ShareAccessToken{}).
+		Where("is_admin = ? AND revoked_at IS NULL AND expired_at > ?", false, time.Now()).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "吊销访问令牌失败")
+	}
+	return result.RowsAffected, nil
+}
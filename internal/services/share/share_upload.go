@@ -0,0 +1,202 @@
+package share
+
+import (
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"pixelpunk/internal/controllers/share/dto"
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UploadToShare 访客向收集型分享上传文件，文件归属分享者并计入其存储空间
+func UploadToShare(c *gin.Context, shareInfo models.Share, file *multipart.FileHeader, uploaderName, message string) (*models.ShareUpload, error) {
+	if !shareInfo.IsCollect() {
+		return nil, errors.New(errors.CodeForbidden, "该分享不允许上传文件")
+	}
+	if !shareInfo.IsAccessible() {
+		return nil, errors.New(errors.CodeShareExpired, "分享已失效")
+	}
+
+	if shareInfo.UploadMaxFileSizeMB > 0 && file.Size > int64(shareInfo.UploadMaxFileSizeMB)*1024*1024 {
+		return nil, errors.New(errors.CodeFileTooLarge, fmt.Sprintf("单个文件不能超过 %dMB", shareInfo.UploadMaxFileSizeMB))
+	}
+	if !isUploadFormatAllowed(shareInfo.UploadAllowedFormats, file.Filename) {
+		return nil, errors.New(errors.CodeFileTypeNotSupported, "该分享仅接收以下格式: "+shareInfo.UploadAllowedFormats)
+	}
+
+	// 先占用名额再上传，避免并发上传超过上限
+	result := database.DB.Model(&models.Share{}).
+		Where("id = ? AND (upload_max_files = 0 OR upload_count < upload_max_files)", shareInfo.ID).
+		UpdateColumn("upload_count", gorm.Expr("upload_count + 1"))
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "更新分享上传计数失败")
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeUploadLimitExceeded, "该分享已达到接收文件数上限")
+	}
+
+	// 需要审核时文件创建即处于分享者审核状态，不进入管理员审核队列，也不触发AI处理
+	status, fileStatus := common.ShareUploadStatusApproved, ""
+	if shareInfo.UploadModeration {
+		status, fileStatus = common.ShareUploadStatusPending, common.FileStatusShareHold
+	}
+
+	fileInfo, err := filesvc.UploadFileWithStatus(c, shareInfo.UserID, file, shareInfo.UploadFolderID, "private", fileStatus)
+	if err != nil {
+		releaseShareUploadSlot(shareInfo.ID)
+		return nil, err
+	}
+
+	upload := &models.ShareUpload{
+		ID:           generateID(),
+		ShareID:      shareInfo.ID,
+		FileID:       fileInfo.ID,
+		UploaderName: strings.TrimSpace(uploaderName),
+		Message:      strings.TrimSpace(message),
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.GetHeader("User-Agent"),
+		FileName:     file.Filename,
+		FileSize:     file.Size,
+		Status:       status,
+	}
+	if err := database.DB.Create(upload).Error; err != nil {
+		logger.Error("记录分享上传失败: shareID=%s, fileID=%s, error=%v", shareInfo.ID, fileInfo.ID, err)
+	}
+
+	return upload, nil
+}
+
+// GetShareUploads 分享者查看收集到的文件
+func GetShareUploads(shareID string, userID uint, query *dto.ShareUploadQueryDTO) ([]models.ShareUpload, int64, error) {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	db := database.DB.Model(&models.ShareUpload{}).Where("share_id = ?", shareID)
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享上传记录失败")
+	}
+
+	page := query.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := query.Size
+	if size <= 0 {
+		size = common.DefaultPageSize
+	}
+
+	var uploads []models.ShareUpload
+	if err := db.Preload("File").Order("created_at DESC").
+		Offset((page - 1) * size).Limit(size).
+		Find(&uploads).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享上传记录失败")
+	}
+	return uploads, total, nil
+}
+
+// ReviewShareUpload 分享者审核访客上传，通过后文件在分享中可见，拒绝则删除文件并释放名额
+func ReviewShareUpload(shareID, uploadID string, userID uint, approve bool) error {
+	if _, err := getOwnedShare(shareID, userID); err != nil {
+		return err
+	}
+
+	var upload models.ShareUpload
+	if err := database.DB.Where("id = ? AND share_id = ?", uploadID, shareID).First(&upload).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New(errors.CodeNotFound, "上传记录不存在")
+		}
+		return errors.Wrap(err, errors.CodeDBQueryFailed, "查询上传记录失败")
+	}
+	if upload.Status != common.ShareUploadStatusPending {
+		return errors.New(errors.CodeConflict, "该上传已审核")
+	}
+
+	if approve {
+		if err := filesvc.ReleaseShareHold(userID, upload.FileID); err != nil {
+			return err
+		}
+	} else {
+		if err := filesvc.DeleteFile(userID, upload.FileID); err != nil && !errors.Is(err, errors.CodeFileNotFound) {
+			return err
+		}
+		releaseShareUploadSlot(shareID)
+	}
+
+	status := common.ShareUploadStatusRejected
+	if approve {
+		status = common.ShareUploadStatusApproved
+	}
+	if err := database.DB.Model(&upload).Update("status", status).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新上传记录失败")
+	}
+	return nil
+}
+
+func getOwnedShare(shareID string, userID uint) (*models.Share, error) {
+	var shareObj models.Share
+	if err := database.DB.Where("id = ? AND user_id = ?", shareID, userID).First(&shareObj).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "分享不存在或您无权访问")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询分享失败")
+	}
+	return &shareObj, nil
+}
+
+func releaseShareUploadSlot(shareID string) {
+	if err := database.DB.Model(&models.Share{}).
+		Where("id = ? AND upload_count > 0", shareID).
+		UpdateColumn("upload_count", gorm.Expr("upload_count - 1")).Error; err != nil {
+		logger.Warn("释放分享上传名额失败: shareID=%s, error=%v", shareID, err)
+	}
+}
+
+// normalizeUploadFormats 统一为小写、去掉点号的逗号分隔列表
+func normalizeUploadFormats(formats string) string {
+	var result []string
+	seen := make(map[string]struct{})
+	for _, f := range strings.Split(formats, ",") {
+		f = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(f)), ".")
+		if f == "" {
+			continue
+		}
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		result = append(result, f)
+	}
+	return strings.Join(result, ",")
+}
+
+func isUploadFormatAllowed(allowed, fileName string) bool {
+	if allowed == "" {
+		return true
+	}
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	if ext == "" {
+		return false
+	}
+	for _, f := range strings.Split(allowed, ",") {
+		if f == ext {
+			return true
+		}
+	}
+	return false
+}
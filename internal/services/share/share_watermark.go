@@ -0,0 +1,78 @@
+package share
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/watermark"
+)
+
+// ShouldWatermarkShareDownload 开启强制水印的分享对访客下载加水印，分享者本人下载原图
+func ShouldWatermarkShareDownload(shareInfo models.Share, userID uint) bool {
+	if !shareInfo.WatermarkEnabled || shareInfo.WatermarkConfig == "" {
+		return false
+	}
+	return userID == 0 || userID != shareInfo.UserID
+}
+
+// RenderShareWatermark 读取文件并按分享的水印配置合成，非图片文件原样返回 nil；
+// 强制水印失败时不回退到原图，避免泄露未加水印的文件
+func RenderShareWatermark(ctx context.Context, shareInfo models.Share, file models.File) ([]byte, error) {
+	if !file.IsImage() {
+		return nil, nil
+	}
+	if !isWatermarkFormatSupported(file.Format) {
+		return nil, errors.New(errors.CodeFileTypeNotSupported, "该图片格式不支持添加水印，分享者已禁止下载原图")
+	}
+
+	reader, err := storage.NewGlobalStorage().ReadFile(ctx, file.StorageProviderID, file.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeFileNotFound, "读取文件失败")
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeFileNotFound, "读取文件失败")
+	}
+
+	result, err := watermark.ProcessBytesWithConfigJSON(data, shareInfo.WatermarkConfig)
+	if err != nil {
+		logger.Error("分享下载水印合成失败: shareID=%s, fileID=%s, error=%v", shareInfo.ID, file.ID, err)
+		return nil, errors.Wrap(err, errors.CodeInternal, "水印合成失败")
+	}
+	if !result.Success || len(result.ProcessedData) == 0 {
+		logger.Error("分享下载水印合成返回失败: shareID=%s, fileID=%s, error=%s", shareInfo.ID, file.ID, result.ErrorMessage)
+		return nil, errors.New(errors.CodeInternal, "水印合成失败")
+	}
+	return result.ProcessedData, nil
+}
+
+// validateShareWatermarkConfig 开启强制水印时校验水印配置
+func validateShareWatermarkConfig(enabled bool, config string) error {
+	if !enabled {
+		return nil
+	}
+	if config == "" {
+		return errors.New(errors.CodeInvalidParameter, "开启下载水印时需要提供水印配置")
+	}
+	if err := watermark.ValidateConfigJSON(config); err != nil {
+		return errors.New(errors.CodeInvalidParameter, "水印配置无效: "+err.Error())
+	}
+	return nil
+}
+
+func isWatermarkFormatSupported(format string) bool {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	for _, f := range watermark.GetSupportedFormats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -38,7 +38,7 @@ func GetDashboardUploadTrends(days int) (*models.DashboardUploadTrendsResponse,
 		result.Timeline[days-1-i] = date.Format("01-02")
 
 		var uploadCount int64
-		if err := database.DB.Model(&models.File{}).
+		if err := database.ReadDB().Model(&models.File{}).
 			Where("created_at >= ? AND created_at < ?", date, nextDate).
 			Count(&uploadCount).Error; err != nil {
 			return nil, err
@@ -46,7 +46,7 @@ func GetDashboardUploadTrends(days int) (*models.DashboardUploadTrendsResponse,
 		result.UploadCounts[days-1-i] = uploadCount
 
 		var storageGrowth *int64
-		if err := database.DB.Model(&models.File{}).
+		if err := database.ReadDB().Model(&models.File{}).
 			Where("created_at >= ? AND created_at < ?", date, nextDate).
 			Select("SUM(size)").Row().Scan(&storageGrowth); err != nil {
 			return nil, err
@@ -70,14 +70,14 @@ func GetDashboardAIServices() (*models.DashboardAIServicesResponse, error) {
 	var result models.DashboardAIServicesResponse
 
 	var taggingQueueSize int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status = ?", common.AITaggingStatusPending).
 		Count(&taggingQueueSize).Error; err != nil {
 		return nil, err
 	}
 
 	var taggingProcessing int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status = ?", common.AITaggingStatusPending).
 		Count(&taggingProcessing).Error; err != nil {
 		return nil, err
@@ -85,7 +85,7 @@ func GetDashboardAIServices() (*models.DashboardAIServicesResponse, error) {
 
 	today := time.Now().Truncate(24 * time.Hour)
 	var taggingCompletedToday int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status = ? AND updated_at >= ?", common.AITaggingStatusDone, today).
 		Count(&taggingCompletedToday).Error; err != nil {
 		return nil, err
@@ -101,15 +101,15 @@ func GetDashboardAIServices() (*models.DashboardAIServicesResponse, error) {
 	var vectorProcessing int64
 	var vectorCompletionRate float64
 
-	if database.DB.Migrator().HasTable(&models.VectorProcessingLog{}) {
+	if database.ReadDB().Migrator().HasTable(&models.VectorProcessingLog{}) {
 		var totalVectorTasks int64
 		var completedVectorTasks int64
 
-		if err := database.DB.Model(&models.VectorProcessingLog{}).
+		if err := database.ReadDB().Model(&models.VectorProcessingLog{}).
 			Where("created_at >= ?", today).
 			Count(&totalVectorTasks).Error; err == nil {
 
-			if err := database.DB.Model(&models.VectorProcessingLog{}).
+			if err := database.ReadDB().Model(&models.VectorProcessingLog{}).
 				Where("created_at >= ? AND action = ?", today, "success").
 				Count(&completedVectorTasks).Error; err == nil && totalVectorTasks > 0 {
 				vectorCompletionRate = float64(completedVectorTasks) / float64(totalVectorTasks) * 100
@@ -127,7 +127,7 @@ func GetDashboardAIServices() (*models.DashboardAIServicesResponse, error) {
 	var contentReviewApprovedToday int64
 	var contentReviewRejectedToday int64
 
-	if database.DB.Migrator().HasTable("review_log") {
+	if database.ReadDB().Migrator().HasTable("review_log") {
 	}
 
 	result.ContentReview = models.AIContentReviewStats{
@@ -166,26 +166,26 @@ func GetDashboardShareStats() (*models.DashboardShareStatsResponse, error) {
 	var result models.DashboardShareStatsResponse
 
 	var totalShares int64
-	if err := database.DB.Model(&models.Share{}).Count(&totalShares).Error; err != nil {
+	if err := database.ReadDB().Model(&models.Share{}).Count(&totalShares).Error; err != nil {
 		return nil, err
 	}
 
 	var activeShares int64
-	if err := database.DB.Model(&models.Share{}).
+	if err := database.ReadDB().Model(&models.Share{}).
 		Where("status = ?", 1).
 		Count(&activeShares).Error; err != nil {
 		return nil, err
 	}
 
 	var inactiveShares int64
-	if err := database.DB.Model(&models.Share{}).
+	if err := database.ReadDB().Model(&models.Share{}).
 		Where("status != ?", 1).
 		Count(&inactiveShares).Error; err != nil {
 		return nil, err
 	}
 
 	var totalVisits *int64
-	if err := database.DB.Model(&models.Share{}).
+	if err := database.ReadDB().Model(&models.Share{}).
 		Select("SUM(current_views)").Row().Scan(&totalVisits); err != nil {
 		return nil, err
 	}
@@ -195,8 +195,8 @@ func GetDashboardShareStats() (*models.DashboardShareStatsResponse, error) {
 	}
 
 	var totalDownloads int64 = 0
-	if database.DB.Migrator().HasTable(&models.FileDownloadLog{}) {
-		if err := database.DB.Model(&models.FileDownloadLog{}).
+	if database.ReadDB().Migrator().HasTable(&models.FileDownloadLog{}) {
+		if err := database.ReadDB().Model(&models.FileDownloadLog{}).
 			Where("share_key IS NOT NULL AND share_key != ''").
 			Count(&totalDownloads).Error; err != nil {
 			totalDownloads = 0
@@ -205,7 +205,7 @@ func GetDashboardShareStats() (*models.DashboardShareStatsResponse, error) {
 
 	lastMonth := time.Now().AddDate(0, -1, 0).Truncate(24 * time.Hour)
 	var lastMonthShares int64
-	if err := database.DB.Model(&models.Share{}).
+	if err := database.ReadDB().Model(&models.Share{}).
 		Where("created_at < ?", lastMonth).
 		Count(&lastMonthShares).Error; err != nil {
 		return nil, err
@@ -215,7 +215,7 @@ func GetDashboardShareStats() (*models.DashboardShareStatsResponse, error) {
 
 	today := time.Now().Truncate(24 * time.Hour)
 	var newSharesToday int64
-	if err := database.DB.Model(&models.Share{}).
+	if err := database.ReadDB().Model(&models.Share{}).
 		Where("created_at >= ?", today).
 		Count(&newSharesToday).Error; err != nil {
 		return nil, err
@@ -251,19 +251,19 @@ func GetDashboardTagStats() (*models.DashboardTagStatsResponse, error) {
 	var result models.DashboardTagStatsResponse
 
 	var totalTagsCount int64
-	if err := database.DB.Model(&models.GlobalTag{}).Count(&totalTagsCount).Error; err != nil {
+	if err := database.ReadDB().Model(&models.GlobalTag{}).Count(&totalTagsCount).Error; err != nil {
 		return nil, err
 	}
 
 	var taggedImagesCount int64
-	if err := database.DB.Model(&models.FileGlobalTagRelation{}).
+	if err := database.ReadDB().Model(&models.FileGlobalTagRelation{}).
 		Distinct("file_id").
 		Count(&taggedImagesCount).Error; err != nil {
 		return nil, err
 	}
 
 	var untaggedImages int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status IN (?)", []string{
 			common.AITaggingStatusNone,
 			common.AITaggingStatusFailed,
@@ -277,7 +277,7 @@ func GetDashboardTagStats() (*models.DashboardTagStatsResponse, error) {
 		Name  string `json:"name"`
 		Count int64  `json:"count"`
 	}
-	if err := database.DB.Table("global_tag as t").
+	if err := database.ReadDB().Table("global_tag as t").
 		Select("t.id, t.name, COUNT(r.id) as count").
 		Joins("LEFT JOIN file_global_tag_relation as r ON t.id = r.tag_id").
 		Group("t.id, t.name").
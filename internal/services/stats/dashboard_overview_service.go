@@ -0,0 +1,201 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/metrics"
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+)
+
+// 总览数据缓存时间，各分项均来自聚合表或分组统计，短时间内无需重复计算
+const dashboardOverviewTTL = time.Minute
+
+type statusCount struct {
+	Status string
+	Count  int64
+}
+
+/* GetDashboardOverview 获取管理后台总览，refresh 为 true 时跳过缓存重新计算 */
+func GetDashboardOverview(days int, refresh bool) (*models.DashboardOverviewResponse, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if days > 90 {
+		days = 90
+	}
+
+	cacheKey := fmt.Sprintf("dashboard:overview:%d", days)
+	if !refresh {
+		if cached, err := cache.Get(cacheKey); err == nil && cached != "" {
+			var result models.DashboardOverviewResponse
+			if err := json.Unmarshal([]byte(cached), &result); err == nil {
+				return &result, nil
+			}
+		}
+	}
+
+	result := models.DashboardOverviewResponse{
+		Days:        days,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+
+	var err error
+	if result.Uploads, err = overviewUploadSeries(days); err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计上传趋势失败")
+	}
+	if result.ActiveUsers, err = overviewActiveUsers(); err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计活跃用户失败")
+	}
+	if result.Queues, err = overviewQueueDepth(); err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计任务队列失败")
+	}
+	if result.Review, err = overviewReviewBacklog(); err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计审核积压失败")
+	}
+	if result.ErrorRates, err = overviewErrorRates(); err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "统计错误率失败")
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		cache.Set(cacheKey, string(data), dashboardOverviewTTL)
+	}
+
+	return &result, nil
+}
+
+// overviewUploadSeries 从每日全局统计表读取，缺失的日期补零
+func overviewUploadSeries(days int) (models.DashboardUploadSeries, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -(days - 1))
+
+	var rows []models.GlobalStats
+	if err := database.ReadDB().Where("date >= ?", start).Order("date ASC").Find(&rows).Error; err != nil {
+		return models.DashboardUploadSeries{}, err
+	}
+	byDate := make(map[string]models.GlobalStats, len(rows))
+	for _, row := range rows {
+		byDate[row.Date.Format("2006-01-02")] = row
+	}
+
+	series := models.DashboardUploadSeries{
+		Timeline:      make([]string, days),
+		UploadCounts:  make([]int64, days),
+		StorageGrowth: make([]int64, days),
+		TotalStorage:  make([]int64, days),
+	}
+	for i := 0; i < days; i++ {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		series.Timeline[i] = date
+		if row, ok := byDate[date]; ok {
+			series.UploadCounts[i] = row.NewImages
+			series.StorageGrowth[i] = row.NewStorage
+			series.TotalStorage[i] = row.TotalStorage
+		} else if i > 0 {
+			series.TotalStorage[i] = series.TotalStorage[i-1]
+		}
+	}
+	return series, nil
+}
+
+func overviewActiveUsers() (models.DashboardActiveUsers, error) {
+	var result models.DashboardActiveUsers
+	now := time.Now()
+
+	if err := database.ReadDB().Model(&models.User{}).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+	windows := []struct {
+		since time.Time
+		dest  *int64
+	}{
+		{now.Add(-24 * time.Hour), &result.Daily},
+		{now.AddDate(0, 0, -7), &result.Weekly},
+		{now.AddDate(0, 0, -30), &result.Monthly},
+	}
+	for _, w := range windows {
+		if err := database.ReadDB().Model(&models.User{}).Where("last_activity_at >= ?", w.since).Count(w.dest).Error; err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func overviewQueueDepth() (models.DashboardQueueDepth, error) {
+	aiCounts, err := countByStatus(&models.AIJob{}, time.Time{})
+	if err != nil {
+		return models.DashboardQueueDepth{}, err
+	}
+	vectorCounts, err := countByStatus(&models.VectorJob{}, time.Time{})
+	if err != nil {
+		return models.DashboardQueueDepth{}, err
+	}
+	return models.DashboardQueueDepth{AI: aiCounts, Vector: vectorCounts}, nil
+}
+
+func overviewReviewBacklog() (models.DashboardReviewBacklog, error) {
+	var result models.DashboardReviewBacklog
+	if err := database.ReadDB().Model(&models.File{}).Where("status = ?", "pending_review").Count(&result.PendingFiles).Error; err != nil {
+		return result, err
+	}
+	if err := database.ReadDB().Model(&models.ReviewAppeal{}).Where("status = ?", models.ReviewAppealStatusPending).Count(&result.PendingAppeals).Error; err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// overviewErrorRates HTTP错误率取最近一小时，队列错误率取最近24小时内结束的任务
+func overviewErrorRates() (models.DashboardErrorRates, error) {
+	httpStats := metrics.GetHTTPStats(60)
+	result := models.DashboardErrorRates{
+		HTTPRequests:     httpStats.Requests,
+		HTTPServerErrors: httpStats.ServerErrors,
+		HTTPErrorRate:    httpStats.ErrorRate,
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	aiCounts, err := countByStatus(&models.AIJob{}, since)
+	if err != nil {
+		return result, err
+	}
+	result.AIFailed = aiCounts["failed"] + aiCounts["dead"]
+	result.AIErrorRate = failureRate(result.AIFailed, aiCounts["done"])
+
+	vectorCounts, err := countByStatus(&models.VectorJob{}, since)
+	if err != nil {
+		return result, err
+	}
+	result.VectorFailed = vectorCounts["failed"]
+	result.VectorErrorRate = failureRate(result.VectorFailed, vectorCounts["done"])
+
+	return result, nil
+}
+
+// countByStatus 按状态分组计数，since 非零时只统计该时间之后更新的任务
+func countByStatus(model interface{}, since time.Time) (map[string]int64, error) {
+	query := database.ReadDB().Model(model).Select("status, COUNT(*) AS count").Group("status")
+	if !since.IsZero() {
+		query = query.Where("updated_at >= ?", since)
+	}
+	var rows []statusCount
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.Status] = row.Count
+	}
+	return result, nil
+}
+
+func failureRate(failed, succeeded int64) float64 {
+	if failed+succeeded == 0 {
+		return 0
+	}
+	return float64(failed) / float64(failed+succeeded) * 100
+}
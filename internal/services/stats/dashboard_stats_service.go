@@ -23,23 +23,23 @@ func GetDashboardUserStats() (*models.DashboardUserStatsResponse, error) {
 	var result models.DashboardUserStatsResponse
 
 	var totalUsers int64
-	if err := database.DB.Model(&models.User{}).Count(&totalUsers).Error; err != nil {
+	if err := database.ReadDB().Model(&models.User{}).Count(&totalUsers).Error; err != nil {
 		return nil, err
 	}
 
 	var bannedUsers int64
-	if err := database.DB.Model(&models.User{}).Where("status = ?", common.UserStatusDisabled).Count(&bannedUsers).Error; err != nil {
+	if err := database.ReadDB().Model(&models.User{}).Where("status = ?", common.UserStatusDisabled).Count(&bannedUsers).Error; err != nil {
 		return nil, err
 	}
 
 	today := time.Now().Truncate(24 * time.Hour)
 	var newUsersToday int64
-	if err := database.DB.Model(&models.User{}).Where("created_at >= ?", today).Count(&newUsersToday).Error; err != nil {
+	if err := database.ReadDB().Model(&models.User{}).Where("created_at >= ?", today).Count(&newUsersToday).Error; err != nil {
 		return nil, err
 	}
 
 	var activeUsersToday int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("created_at >= ?", today).
 		Distinct("user_id").
 		Count(&activeUsersToday).Error; err != nil {
@@ -48,7 +48,7 @@ func GetDashboardUserStats() (*models.DashboardUserStatsResponse, error) {
 
 	lastMonth := time.Now().AddDate(0, -1, 0).Truncate(24 * time.Hour)
 	var lastMonthUsers int64
-	if err := database.DB.Model(&models.User{}).Where("created_at < ?", lastMonth).Count(&lastMonthUsers).Error; err != nil {
+	if err := database.ReadDB().Model(&models.User{}).Where("created_at < ?", lastMonth).Count(&lastMonthUsers).Error; err != nil {
 		return nil, err
 	}
 
@@ -81,18 +81,18 @@ func GetDashboardFileStats() (*models.DashboardFileStatsResponse, error) {
 	var result models.DashboardFileStatsResponse
 
 	var totalImages int64
-	if err := database.DB.Model(&models.File{}).Count(&totalImages).Error; err != nil {
+	if err := database.ReadDB().Model(&models.File{}).Count(&totalImages).Error; err != nil {
 		return nil, err
 	}
 
 	today := time.Now().Truncate(24 * time.Hour)
 	var uploadedToday int64
-	if err := database.DB.Model(&models.File{}).Where("created_at >= ?", today).Count(&uploadedToday).Error; err != nil {
+	if err := database.ReadDB().Model(&models.File{}).Where("created_at >= ?", today).Count(&uploadedToday).Error; err != nil {
 		return nil, err
 	}
 
 	var aiTaggedCount int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status = ?", common.AITaggingStatusDone).
 		Count(&aiTaggedCount).Error; err != nil {
 		return nil, err
@@ -104,19 +104,19 @@ func GetDashboardFileStats() (*models.DashboardFileStatsResponse, error) {
 	}
 
 	var pendingReview int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("status = ?", "pending_review").
 		Count(&pendingReview).Error; err != nil {
 		return nil, err
 	}
 
 	var nsfwDetected int64
-	if err := database.DB.Model(&models.FileAIInfo{}).Where("is_nsfw = ?", true).Count(&nsfwDetected).Error; err != nil {
+	if err := database.ReadDB().Model(&models.FileAIInfo{}).Where("is_nsfw = ?", true).Count(&nsfwDetected).Error; err != nil {
 		return nil, err
 	}
 
 	var untaggedCount int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("ai_tagging_status IN (?)", []string{
 			common.AITaggingStatusNone,
 			common.AITaggingStatusFailed,
@@ -153,7 +153,7 @@ func GetDashboardStorageStats() (*models.DashboardStorageStatsResponse, error) {
 	var result models.DashboardStorageStatsResponse
 
 	var totalStorage *int64
-	if err := database.DB.Model(&models.File{}).Select("SUM(size)").Row().Scan(&totalStorage); err != nil {
+	if err := database.ReadDB().Model(&models.File{}).Select("SUM(size)").Row().Scan(&totalStorage); err != nil {
 		return nil, err
 	}
 	finalTotalStorage := int64(0)
@@ -162,7 +162,7 @@ func GetDashboardStorageStats() (*models.DashboardStorageStatsResponse, error) {
 	}
 
 	var imageCount int64
-	if err := database.DB.Model(&models.File{}).Count(&imageCount).Error; err != nil {
+	if err := database.ReadDB().Model(&models.File{}).Count(&imageCount).Error; err != nil {
 		return nil, err
 	}
 
@@ -176,14 +176,14 @@ func GetDashboardStorageStats() (*models.DashboardStorageStatsResponse, error) {
 	previousMonthStart := currentMonthStart.AddDate(0, -1, 0)
 
 	var addedCurrentMonth int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("created_at >= ?", currentMonthStart).
 		Select("COALESCE(SUM(size), 0)").Row().Scan(&addedCurrentMonth); err != nil {
 		return nil, err
 	}
 
 	var addedPreviousMonth int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("created_at >= ? AND created_at < ?", previousMonthStart, currentMonthStart).
 		Select("COALESCE(SUM(size), 0)").Row().Scan(&addedPreviousMonth); err != nil {
 		return nil, err
@@ -193,7 +193,7 @@ func GetDashboardStorageStats() (*models.DashboardStorageStatsResponse, error) {
 
 	today := time.Now().Truncate(24 * time.Hour)
 	var newStorageToday *int64
-	if err := database.DB.Model(&models.File{}).
+	if err := database.ReadDB().Model(&models.File{}).
 		Where("created_at >= ?", today).
 		Select("SUM(size)").Row().Scan(&newStorageToday); err != nil {
 		return nil, err
@@ -207,7 +207,7 @@ func GetDashboardStorageStats() (*models.DashboardStorageStatsResponse, error) {
 	usagePercentage := float64(0)
 
 	var totalBandwidth int64
-	if err := database.DB.Model(&models.GlobalStats{}).
+	if err := database.ReadDB().Model(&models.GlobalStats{}).
 		Select("COALESCE(MAX(total_bandwidth), 0)").Row().Scan(&totalBandwidth); err != nil {
 		totalBandwidth = 0
 	}
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"pixelpunk/internal/models"
+	quotaService "pixelpunk/internal/services/quota"
 	"pixelpunk/pkg/cache"
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
@@ -35,6 +36,7 @@ func GetUserStats(userID uint) (*models.UserStatsResponse, error) {
 			DefaultAccessLevel: "private",
 		}
 	}
+	quotaService.ApplyLimits(userID, &settings)
 
 	response := &models.UserStatsResponse{
 		Storage: models.StorageStats{
@@ -229,6 +231,8 @@ func CheckUserStorageAvailable(userID uint, fileSize int64) (bool, error) {
 			DefaultAccessLevel: "private",
 		}
 	}
+	// 用户组配置的存储配额优先于用户单独设置，并叠加管理员追加的配额
+	quotaService.ApplyLimits(userID, &settings)
 
 	totalSizeAfterUpload := stats.TotalSize + fileSize
 
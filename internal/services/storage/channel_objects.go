@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage/adapter"
+)
+
+const (
+	defaultObjectPageSize = 100
+	maxObjectPageSize     = 1000
+	maxOrphanDeleteBatch  = 200
+)
+
+// ChannelObject 存储对象及其关联的文件记录
+type ChannelObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Referenced   bool      `json:"referenced"`
+	FileID       string    `json:"file_id,omitempty"`
+	Role         string    `json:"role,omitempty"` // original / thumbnail
+}
+
+// ChannelObjectPage 存储对象分页结果
+type ChannelObjectPage struct {
+	Items      []ChannelObject `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// OrphanDeleteResult 未引用对象删除结果
+type OrphanDeleteResult struct {
+	Deleted []string          `json:"deleted"`
+	Skipped map[string]string `json:"skipped"`
+}
+
+/* ListChannelObjects 直接列举存储渠道中的对象，并标注是否被文件记录引用 */
+func ListChannelObjects(channelID, prefix, cursor string, limit int) (*ChannelObjectPage, error) {
+	if limit <= 0 {
+		limit = defaultObjectPageSize
+	}
+	if limit > maxObjectPageSize {
+		limit = maxObjectPageSize
+	}
+
+	_, lister, err := getChannelLister(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := lister.ListObjects(ctx, prefix, cursor, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "列举存储对象失败")
+	}
+
+	keys := make([]string, 0, len(result.Objects))
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	refs, err := findObjectReferences(channelID, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ChannelObjectPage{
+		Items:      make([]ChannelObject, 0, len(result.Objects)),
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}
+	for _, obj := range result.Objects {
+		item := ChannelObject{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}
+		if ref, ok := refs[obj.Key]; ok {
+			item.Referenced = true
+			item.FileID = ref.fileID
+			item.Role = ref.role
+		}
+		page.Items = append(page.Items, item)
+	}
+	return page, nil
+}
+
+/* DeleteOrphanObjects 删除未被任何文件记录引用的对象，删除前逐一复核引用关系 */
+func DeleteOrphanObjects(channelID string, keys []string) (*OrphanDeleteResult, error) {
+	if len(keys) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "请选择要删除的对象")
+	}
+	if len(keys) > maxOrphanDeleteBatch {
+		return nil, errors.New(errors.CodeInvalidParameter, "单次最多删除200个对象")
+	}
+
+	st, _, err := getChannelLister(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := findObjectReferences(channelID, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrphanDeleteResult{Deleted: []string{}, Skipped: map[string]string{}}
+	ctx := context.Background()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if ref, ok := refs[key]; ok {
+			result.Skipped[key] = "仍被文件 " + ref.fileID + " 引用"
+			continue
+		}
+		if err := st.Delete(ctx, key); err != nil {
+			result.Skipped[key] = err.Error()
+			continue
+		}
+		result.Deleted = append(result.Deleted, key)
+	}
+
+	logger.Info("清理存储渠道未引用对象: channelID=%s, 删除=%d, 跳过=%d", channelID, len(result.Deleted), len(result.Skipped))
+	return result, nil
+}
+
+/* GetChannelAdapter 获取渠道的存储适配器，用于备份等需要直接读写对象的场景 */
+func GetChannelAdapter(channelID string) (adapter.StorageAdapter, error) {
+	if _, err := GetChannelByID(channelID); err != nil {
+		return nil, errors.New(errors.CodeNotFound, "存储渠道不存在")
+	}
+
+	mgr, err := createStorageManager()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "创建存储管理器失败")
+	}
+	a, err := mgr.GetAdapter(channelID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "获取存储适配器失败")
+	}
+	return a, nil
+}
+
+// getChannelLister 获取渠道适配器，仅支持列举对象的存储类型可用
+func getChannelLister(channelID string) (adapter.StorageAdapter, adapter.ObjectLister, error) {
+	a, err := GetChannelAdapter(channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lister, ok := a.(adapter.ObjectLister)
+	if !ok {
+		return nil, nil, errors.New(errors.CodeInvalidParameter, "该存储类型不支持列举对象")
+	}
+	return a, lister, nil
+}
+
+type objectReference struct {
+	fileID string
+	role   string
+}
+
+// findObjectReferences 查询引用这些对象键的文件记录，包括回收站中的文件
+func findObjectReferences(channelID string, keys []string) (map[string]objectReference, error) {
+	refs := make(map[string]objectReference)
+	if len(keys) == 0 {
+		return refs, nil
+	}
+
+	var files []models.File
+	if err := database.GetDB().Unscoped().
+		Select("id", "local_file_path", "local_thumb_path", "remote_url", "remote_thumb_url").
+		Where("storage_provider_id = ?", channelID).
+		Where("local_file_path IN ? OR local_thumb_path IN ? OR remote_url IN ? OR remote_thumb_url IN ?", keys, keys, keys, keys).
+		Find(&files).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询对象引用失败")
+	}
+
+	for _, f := range files {
+		for _, key := range []string{f.LocalFilePath, f.RemoteURL} {
+			if key != "" {
+				refs[key] = objectReference{fileID: f.ID, role: "original"}
+			}
+		}
+		for _, key := range []string{f.LocalThumbPath, f.RemoteThumbURL} {
+			if key != "" {
+				refs[key] = objectReference{fileID: f.ID, role: "thumbnail"}
+			}
+		}
+	}
+	return refs, nil
+}
@@ -7,6 +7,7 @@ import (
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
 
 	"gorm.io/gorm"
 )
@@ -70,6 +71,7 @@ func (s *FileGlobalTagService) AddTagsToFile(fileID string, tagIDs []uint, sourc
 	if err := s.db.Create(&newRelations).Error; err != nil {
 		return fmt.Errorf("批量创建文件标签关联失败: %v", err)
 	}
+	go vector.SyncFileMeta(fileID)
 
 	// 手动批量更新受影响标签的usage_count
 	if len(actuallyAddedTagIDs) > 0 {
@@ -106,6 +108,7 @@ func (s *FileGlobalTagService) RemoveTagsFromFile(fileID string, tagIDs []uint)
 	if err != nil {
 		return fmt.Errorf("移除文件标签失败: %v", err)
 	}
+	go vector.SyncFileMeta(fileID)
 
 	// 手动批量更新受影响标签的usage_count
 	if len(tagIDs) > 0 {
@@ -172,9 +175,13 @@ func (s *FileGlobalTagService) ReplaceFileTags(fileID string, newTagIDs []uint,
 		return fmt.Errorf("数据库连接失败")
 	}
 
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
 		return s.ReplaceFileTagsTx(tx, fileID, newTagIDs, source, confidence)
-	})
+	}); err != nil {
+		return err
+	}
+	go vector.SyncFileMeta(fileID)
+	return nil
 }
 
 /* ReplaceFileTagsTx 使用外部事务替换文件的所有标签 */
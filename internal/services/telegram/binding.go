@@ -0,0 +1,163 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	tgbot "pixelpunk/pkg/telegram"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	bindCodeTTL      = 10 * time.Minute
+	bindCodeLength   = 8
+	bindCodeCacheKey = "telegram:bind:"
+)
+
+// BindCode 绑定码，用户在 Telegram 中发送给机器人完成绑定
+type BindCode struct {
+	Code        string    `json:"code"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	BotUsername string    `json:"bot_username"`
+	DeepLink    string    `json:"deep_link"` // 打开即自动发送绑定码的链接
+}
+
+// BindingStatus 当前用户的绑定状态
+type BindingStatus struct {
+	Enabled     bool                    `json:"enabled"`
+	BotUsername string                  `json:"bot_username"`
+	Binding     *models.TelegramBinding `json:"binding"`
+}
+
+/* GetBindingStatus 获取机器人开启状态与当前用户的绑定信息 */
+func GetBindingStatus(userID uint) (*BindingStatus, error) {
+	status := &BindingStatus{Enabled: IsEnabled(), BotUsername: BotUsername()}
+
+	var binding models.TelegramBinding
+	err := database.DB.Where("user_id = ?", userID).First(&binding).Error
+	if err == nil {
+		status.Binding = &binding
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询Telegram绑定失败")
+	}
+	return status, nil
+}
+
+/* CreateBindCode 生成一次性绑定码，新绑定会替换已有绑定 */
+func CreateBindCode(userID uint) (*BindCode, error) {
+	username := BotUsername()
+	if !IsEnabled() || username == "" {
+		return nil, errors.New(errors.CodeForbidden, "Telegram 机器人未开启")
+	}
+
+	code := strings.ToUpper(utils.GenerateRandomString(bindCodeLength))
+	if err := cache.Set(bindCodeCacheKey+code, strconv.FormatUint(uint64(userID), 10), bindCodeTTL); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "生成绑定码失败")
+	}
+
+	return &BindCode{
+		Code:        code,
+		ExpiresAt:   time.Now().Add(bindCodeTTL),
+		BotUsername: username,
+		DeepLink:    "https://t.me/" + username + "?start=" + code,
+	}, nil
+}
+
+/* UpdateUploadFolder 设置机器人上传的目标文件夹，为空时上传到根目录 */
+func UpdateUploadFolder(userID uint, folderID string) (*models.TelegramBinding, error) {
+	var binding models.TelegramBinding
+	if err := database.DB.Where("user_id = ?", userID).First(&binding).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "尚未绑定Telegram")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询Telegram绑定失败")
+	}
+
+	if folderID != "" {
+		var count int64
+		database.DB.Model(&models.Folder{}).Where("id = ? AND user_id = ?", folderID, userID).Count(&count)
+		if count == 0 {
+			return nil, errors.New(errors.CodeFolderNotFound, "文件夹不存在")
+		}
+	}
+
+	if err := database.DB.Model(&binding).Update("folder_id", folderID).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新上传文件夹失败")
+	}
+	return &binding, nil
+}
+
+/* Unbind 解除当前用户的绑定 */
+func Unbind(userID uint) error {
+	if err := database.DB.Where("user_id = ?", userID).Delete(&models.TelegramBinding{}).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBDeleteFailed, "解除Telegram绑定失败")
+	}
+	return nil
+}
+
+// bindChat 校验绑定码并将会话绑定到对应用户，返回用户名
+func bindChat(code string, msg *tgbot.Message) (string, error) {
+	key := bindCodeCacheKey + strings.ToUpper(strings.TrimSpace(code))
+	value, err := cache.Get(key)
+	if err != nil || value == "" {
+		return "", errors.New(errors.CodeInvalidParameter, "绑定码无效或已过期，请在网站重新获取")
+	}
+	cache.Del(key)
+
+	userID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return "", errors.New(errors.CodeInvalidParameter, "绑定码无效或已过期，请在网站重新获取")
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "username").First(&user, userID).Error; err != nil {
+		return "", errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+
+	telegramUsername := ""
+	if msg.From != nil {
+		telegramUsername = msg.From.Username
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 一个账号只绑定一个会话，一个会话也只属于一个账号
+		if err := tx.Where("user_id = ? OR chat_id = ?", user.ID, msg.Chat.ID).Delete(&models.TelegramBinding{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TelegramBinding{
+			UserID:           user.ID,
+			ChatID:           msg.Chat.ID,
+			TelegramUsername: telegramUsername,
+		}).Error
+	})
+	if err != nil {
+		return "", errors.Wrap(err, errors.CodeDBCreateFailed, "绑定失败")
+	}
+	return user.Username, nil
+}
+
+func unbindChat(chatID int64) (bool, error) {
+	result := database.DB.Where("chat_id = ?", chatID).Delete(&models.TelegramBinding{})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "解除Telegram绑定失败")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func findBindingByChat(chatID int64) (*models.TelegramBinding, error) {
+	var binding models.TelegramBinding
+	if err := database.DB.Where("chat_id = ?", chatID).First(&binding).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询Telegram绑定失败")
+	}
+	return &binding, nil
+}
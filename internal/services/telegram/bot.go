@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/logger"
+	tgbot "pixelpunk/pkg/telegram"
+)
+
+const (
+	pollTimeoutSeconds = 50
+	pollRetryDelay     = 5 * time.Second
+	maxConcurrentJobs  = 4
+)
+
+// bot 正在运行的机器人实例，通过长轮询接收消息
+type bot struct {
+	client   *tgbot.Client
+	username string
+	cancel   context.CancelFunc
+	done     chan struct{}
+	jobs     chan struct{}
+}
+
+var (
+	botMu      sync.Mutex
+	runningBot *bot
+	hooksOnce  sync.Once
+)
+
+/* IsEnabled 是否开启 Telegram 机器人 */
+func IsEnabled() bool {
+	return setting.GetBool("telegram", "enabled", false) && setting.GetString("telegram", "bot_token", "") != ""
+}
+
+/* BotUsername 正在运行的机器人用户名，未运行时返回空 */
+func BotUsername() string {
+	botMu.Lock()
+	defer botMu.Unlock()
+	if runningBot == nil {
+		return ""
+	}
+	return runningBot.username
+}
+
+/* Start 按当前设置启动机器人，未开启或未配置 Token 时不启动 */
+func Start() {
+	botMu.Lock()
+	defer botMu.Unlock()
+	startLocked()
+}
+
+/* Stop 停止机器人并等待正在进行的轮询退出 */
+func Stop() {
+	botMu.Lock()
+	defer botMu.Unlock()
+	stopLocked()
+}
+
+/* Restart 设置变更后重新启动机器人 */
+func Restart() {
+	botMu.Lock()
+	defer botMu.Unlock()
+	stopLocked()
+	startLocked()
+}
+
+/* RegisterTelegramSettingHooks 开关或 Token 变更时重启机器人 */
+func RegisterTelegramSettingHooks() {
+	hooksOnce.Do(func() {
+		for _, key := range []string{"enabled", "bot_token"} {
+			setting.RegisterSettingChangeHandler("telegram", key, func(string) {
+				go Restart()
+			})
+		}
+	})
+}
+
+func startLocked() {
+	if runningBot != nil {
+		return
+	}
+	// 设置变更回调触发时缓存可能尚未刷新，直接读取数据库
+	if !setting.GetBoolDirectFromDB("telegram", "enabled", false) {
+		return
+	}
+	token := strings.TrimSpace(setting.GetStringDirectFromDB("telegram", "bot_token", ""))
+	if token == "" {
+		logger.Warn("Telegram 机器人已开启但未配置 Bot Token")
+		return
+	}
+
+	client := tgbot.NewClient(token)
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 15*time.Second)
+	me, err := client.GetMe(checkCtx)
+	cancelCheck()
+	if err != nil {
+		logger.Error("Telegram 机器人启动失败，请检查 Bot Token: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &bot{
+		client:   client,
+		username: me.Username,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		jobs:     make(chan struct{}, maxConcurrentJobs),
+	}
+	runningBot = b
+	go b.run(ctx)
+	logger.Info("Telegram 机器人已启动: @%s", me.Username)
+}
+
+func stopLocked() {
+	if runningBot == nil {
+		return
+	}
+	runningBot.cancel()
+	<-runningBot.done
+	logger.Info("Telegram 机器人已停止: @%s", runningBot.username)
+	runningBot = nil
+}
+
+func (b *bot) run(ctx context.Context) {
+	defer close(b.done)
+
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.client.GetUpdates(ctx, offset, pollTimeoutSeconds)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Telegram 获取消息失败: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message != nil {
+				b.handleMessage(ctx, update.Message)
+			}
+		}
+	}
+}
+
+func (b *bot) reply(ctx context.Context, msg *tgbot.Message, text string) {
+	if err := b.client.SendMessage(ctx, msg.Chat.ID, text, msg.MessageID); err != nil && ctx.Err() == nil {
+		logger.Warn("Telegram 回复消息失败: chatID=%d, error=%v", msg.Chat.ID, err)
+	}
+}
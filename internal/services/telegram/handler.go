@@ -0,0 +1,208 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	tgbot "pixelpunk/pkg/telegram"
+
+	"gorm.io/gorm"
+)
+
+// Bot API 只允许机器人下载 20MB 以内的文件
+const maxDownloadSize = 20 << 20
+
+const helpText = `发送图片即可上传到你的账号，并返回图片链接。
+以文件形式发送可保留原图不被 Telegram 压缩。
+
+/bind 绑定码 - 绑定账号（绑定码在网站个人设置中获取）
+/unbind - 解除绑定
+/help - 查看帮助`
+
+func (b *bot) handleMessage(ctx context.Context, msg *tgbot.Message) {
+	// 只处理私聊，避免群内任何成员都能向绑定账号上传
+	if msg.Chat.Type != "private" {
+		return
+	}
+
+	if strings.HasPrefix(msg.Text, "/") {
+		b.handleCommand(ctx, msg)
+		return
+	}
+
+	if len(msg.Photo) > 0 || msg.Document != nil {
+		select {
+		case b.jobs <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		go func() {
+			defer func() { <-b.jobs }()
+			b.handleUpload(ctx, msg)
+		}()
+		return
+	}
+
+	b.reply(ctx, msg, helpText)
+}
+
+func (b *bot) handleCommand(ctx context.Context, msg *tgbot.Message) {
+	fields := strings.Fields(msg.Text)
+	// 群组中的命令形如 /bind@bot_name
+	command := strings.SplitN(fields[0], "@", 2)[0]
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch command {
+	case "/start", "/bind":
+		if arg == "" {
+			if binding, _ := findBindingByChat(msg.Chat.ID); binding != nil {
+				b.reply(ctx, msg, "已绑定账号，直接发送图片即可上传。\n\n"+helpText)
+				return
+			}
+			b.reply(ctx, msg, "请先在网站个人设置中获取绑定码，然后发送 /bind 绑定码 完成绑定。")
+			return
+		}
+		username, err := bindChat(arg, msg)
+		if err != nil {
+			b.reply(ctx, msg, errorMessage(err))
+			return
+		}
+		b.reply(ctx, msg, fmt.Sprintf("已绑定账号 %s，现在可以直接发送图片上传。", username))
+	case "/unbind":
+		deleted, err := unbindChat(msg.Chat.ID)
+		if err != nil {
+			b.reply(ctx, msg, errorMessage(err))
+			return
+		}
+		if !deleted {
+			b.reply(ctx, msg, "当前会话未绑定账号。")
+			return
+		}
+		b.reply(ctx, msg, "已解除绑定。")
+	default:
+		b.reply(ctx, msg, helpText)
+	}
+}
+
+func (b *bot) handleUpload(ctx context.Context, msg *tgbot.Message) {
+	binding, err := findBindingByChat(msg.Chat.ID)
+	if err != nil {
+		b.reply(ctx, msg, errorMessage(err))
+		return
+	}
+	if binding == nil {
+		b.reply(ctx, msg, "当前会话未绑定账号，请先发送 /bind 绑定码 完成绑定。")
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "status").First(&user, binding.UserID).Error; err != nil || user.Status != common.UserStatusNormal {
+		b.reply(ctx, msg, "账号状态异常，无法上传。")
+		return
+	}
+
+	fileID, name, contentType, size := pickAttachment(msg)
+	if fileID == "" {
+		b.reply(ctx, msg, "仅支持上传图片。")
+		return
+	}
+	if size > maxDownloadSize {
+		b.reply(ctx, msg, "文件超过 20MB，Telegram 机器人无法下载。")
+		return
+	}
+
+	tmp, err := b.download(ctx, fileID)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Telegram 下载文件失败: chatID=%d, error=%v", msg.Chat.ID, err)
+			b.reply(ctx, msg, "下载图片失败，请稍后重试。")
+		}
+		return
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if name == "" {
+		name = fmt.Sprintf("telegram_%s.jpg", time.Unix(msg.Date, 0).Format("20060102_150405"))
+	}
+	uploaded, err := filesvc.UploadFileContent(binding.UserID, binding.FolderID, name, tmp, contentType)
+	if err != nil {
+		b.reply(ctx, msg, "上传失败："+errorMessage(err))
+		return
+	}
+
+	now := common.JSONTime(time.Now())
+	database.DB.Model(&models.TelegramBinding{}).Where("id = ?", binding.ID).Updates(map[string]interface{}{
+		"upload_count":   gorm.Expr("upload_count + 1"),
+		"last_upload_at": &now,
+	})
+
+	b.reply(ctx, msg, "上传成功\n"+uploaded.URL)
+}
+
+// pickAttachment 选出要上传的附件：图片取最大尺寸，文件仅接受图片类型
+func pickAttachment(msg *tgbot.Message) (fileID, name, contentType string, size int64) {
+	if len(msg.Photo) > 0 {
+		largest := msg.Photo[len(msg.Photo)-1]
+		return largest.FileID, "", "image/jpeg", largest.FileSize
+	}
+	doc := msg.Document
+	if doc == nil || !strings.HasPrefix(doc.MimeType, "image/") {
+		return "", "", "", 0
+	}
+	return doc.FileID, path.Base(doc.FileName), doc.MimeType, doc.FileSize
+}
+
+// download 将 Telegram 文件下载到临时文件，调用方负责关闭和删除
+func (b *bot) download(ctx context.Context, fileID string) (*os.File, error) {
+	info, err := b.client.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := b.client.DownloadFile(ctx, info.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "pixelpunk-telegram-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tmp, io.LimitReader(body, maxDownloadSize+1)); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return tmp, nil
+}
+
+// errorMessage 业务错误直接展示给用户，其余错误只返回通用提示
+func errorMessage(err error) string {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Message
+	}
+	logger.Error("Telegram 机器人处理失败: %v", err)
+	return "服务器内部错误"
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/auth"
+	messageService "pixelpunk/internal/services/message"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/geoip"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 锁定账号链接的有效天数
+const loginAlertLockTokenDays = 7
+
+/* CheckLoginAnomaly 登录成功后比对历史设备与国家，发现新设备或新国家时发送站内信和邮件提醒 */
+func CheckLoginAnomaly(user *models.User, client auth.ClientInfo) {
+	if !setting.GetBool("security", "login_alert_enabled", true) {
+		return
+	}
+
+	fingerprint := common.DeviceFingerprint(client.UserAgent)
+	location := geoip.Lookup(setting.GetString("security", "geoip_database_path", ""), client.IP)
+	now := common.JSONTimeNow()
+	db := database.GetDB()
+
+	var footprint models.UserLoginFootprint
+	err := db.Where("user_id = ? AND device_fingerprint = ? AND country = ?", user.ID, fingerprint, location.CountryCode).
+		First(&footprint).Error
+	if err == nil {
+		db.Model(&footprint).Updates(map[string]interface{}{
+			"last_ip":       client.IP,
+			"last_login_at": now,
+			"login_count":   gorm.Expr("login_count + ?", 1),
+		})
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		logger.Warn("查询登录足迹失败: userID=%d, error=%v", user.ID, err)
+		return
+	}
+
+	// 首次记录足迹时作为基线，不提醒
+	var total int64
+	db.Model(&models.UserLoginFootprint{}).Where("user_id = ?", user.ID).Count(&total)
+
+	var reasons []string
+	if total > 0 {
+		var count int64
+		db.Model(&models.UserLoginFootprint{}).Where("user_id = ? AND device_fingerprint = ?", user.ID, fingerprint).Count(&count)
+		if count == 0 {
+			reasons = append(reasons, "新设备")
+		}
+		if location.CountryCode != "" {
+			db.Model(&models.UserLoginFootprint{}).Where("user_id = ? AND country = ?", user.ID, location.CountryCode).Count(&count)
+			if count == 0 {
+				reasons = append(reasons, "新的国家/地区")
+			}
+		}
+	}
+
+	footprint = models.UserLoginFootprint{
+		UserID:            user.ID,
+		DeviceFingerprint: fingerprint,
+		Country:           location.CountryCode,
+		DeviceName:        common.DescribeUserAgent(client.UserAgent),
+		LastIP:            client.IP,
+		LoginCount:        1,
+		LastLoginAt:       now,
+	}
+	if len(reasons) > 0 {
+		expiresAt := common.JSONTime(time.Now().AddDate(0, 0, loginAlertLockTokenDays))
+		footprint.LockToken = utils.GenerateRandomString(48)
+		footprint.LockTokenExpiresAt = &expiresAt
+	}
+	if err := db.Create(&footprint).Error; err != nil {
+		// 并发登录时可能已被另一请求写入
+		logger.Warn("记录登录足迹失败: userID=%d, error=%v", user.ID, err)
+		return
+	}
+
+	if len(reasons) > 0 {
+		sendLoginAlert(user, &footprint, location, strings.Join(reasons, "、"))
+	}
+}
+
+func sendLoginAlert(user *models.User, footprint *models.UserLoginFootprint, location geoip.Location, reason string) {
+	variables := map[string]interface{}{
+		"username":     user.Username,
+		"reason":       reason,
+		"device_name":  footprint.DeviceName,
+		"ip":           html.EscapeString(footprint.LastIP),
+		"location":     location.CountryName,
+		"login_time":   time.Time(footprint.LastLoginAt).Format("2006-01-02 15:04:05"),
+		"lock_url":     utils.GetBaseUrl() + "/security/lock-account?token=" + footprint.LockToken,
+		"expire_days":  loginAlertLockTokenDays,
+		"related_type": "security",
+	}
+
+	if err := messageService.GetMessageService().SendTemplateMessage(user.ID, common.MessageTypeSecurityLoginAlert, variables); err != nil {
+		logger.Warn("发送异常登录提醒失败: userID=%d, error=%v", user.ID, err)
+		return
+	}
+	logger.Info("检测到异常登录并已提醒用户: userID=%d, reason=%s, ip=%s, country=%s", user.ID, reason, footprint.LastIP, footprint.Country)
+}
+
+/* LockAccountByAlertToken 用户通过异常登录提醒确认"不是我本人"：禁用账号并注销所有设备 */
+func LockAccountByAlertToken(token string) error {
+	if token == "" {
+		return errors.New(errors.CodeInvalidToken, "锁定链接无效")
+	}
+
+	db := database.GetDB()
+	var footprint models.UserLoginFootprint
+	if err := db.Where("lock_token = ?", token).First(&footprint).Error; err != nil {
+		return errors.New(errors.CodeInvalidToken, "锁定链接无效或已使用")
+	}
+	if footprint.LockTokenExpiresAt == nil || time.Now().After(time.Time(*footprint.LockTokenExpiresAt)) {
+		return errors.New(errors.CodeTokenExpired, "锁定链接已过期")
+	}
+
+	var user models.User
+	if err := db.First(&user, footprint.UserID).Error; err != nil {
+		return errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 可疑设备不再视为已知设备，下次从该设备登录仍会提醒
+		if err := tx.Delete(&footprint).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除登录足迹失败")
+		}
+		// 超级管理员被禁用后无人可解锁，仅注销会话
+		if !user.IsSuperAdmin() {
+			if err := tx.Model(&user).Update("status", common.UserStatusDisabled).Error; err != nil {
+				return errors.Wrap(err, errors.CodeDBUpdateFailed, "锁定账号失败")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	revokeAllSessions(user.ID)
+	logger.Warn("用户通过异常登录提醒锁定了账号: userID=%d, ip=%s", user.ID, footprint.LastIP)
+	return nil
+}
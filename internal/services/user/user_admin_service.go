@@ -94,6 +94,7 @@ func GetUserList(query *dto.AdminUserListQueryDTO) (*dto.AdminUserListResponseDT
 			AvatarFullPath: avatarFullPath,
 			Status:         user.Status,
 			Role:           user.Role,
+			GroupID:        user.GroupID,
 			StorageLimit:   userSettings.StorageLimit,
 			BandwidthLimit: userSettings.BandwidthLimit,
 			UsedStorage:    userStats.TotalSize,
@@ -209,6 +210,7 @@ func AdminGetUserByID(id uint) (*dto.AdminUserResponseDTO, error) {
 		AvatarFullPath: avatarFullPath,
 		Status:         user.Status,
 		Role:           user.Role,
+		GroupID:        user.GroupID,
 		StorageLimit:   userSettings.StorageLimit,
 		BandwidthLimit: userSettings.BandwidthLimit,
 		UsedStorage:    userStats.TotalSize,
@@ -331,6 +333,7 @@ func AdminCreateUser(createDTO *dto.AdminCreateUserDTO) (*dto.AdminUserResponseD
 		AvatarFullPath: avatarFullPath,
 		Status:         user.Status,
 		Role:           user.Role,
+		GroupID:        user.GroupID,
 		StorageLimit:   userSettings.StorageLimit,
 		BandwidthLimit: userSettings.BandwidthLimit,
 		UsedStorage:    userStats.TotalSize,
@@ -412,6 +415,8 @@ func AdminResetUserPassword(id uint) (*dto.AdminResetUserPasswordResponseDTO, er
 		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "重置密码失败")
 	}
 
+	revokeAllSessions(user.ID)
+
 	return &dto.AdminResetUserPasswordResponseDTO{
 		NewPassword: newPassword,
 	}, nil
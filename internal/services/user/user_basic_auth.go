@@ -0,0 +1,75 @@
+package user
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	ldapService "pixelpunk/internal/services/ldap"
+	samlService "pixelpunk/internal/services/saml"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+)
+
+/* AuthenticateBasic 校验用户名/邮箱与密码（用于 WebDAV 等 Basic 认证），与网页登录共用失败计数与锁定，不创建会话 */
+func AuthenticateBasic(account, password string) (*models.User, error) {
+	if account == "" || password == "" {
+		return nil, errors.New(errors.CodeUnauthorized, "用户名或密码错误")
+	}
+
+	var user models.User
+	if err := database.GetDB().Where("username = ? OR email = ?", account, account).First(&user).Error; err != nil {
+		return nil, errors.New(errors.CodeUnauthorized, "用户名或密码错误")
+	}
+	if samlService.IsSSOEnforced() && !user.IsSuperAdmin() {
+		return nil, errors.New(errors.CodeForbidden, "系统已启用强制SSO登录，请使用API密钥访问")
+	}
+
+	lockKey := fmt.Sprintf("user:login:lock:%d", user.ID)
+	if cache.GetCache().Exists(lockKey) {
+		return nil, errors.New(errors.CodeForbidden, "账户已被锁定，请稍后再试")
+	}
+
+	passwordValid := false
+	ldapConfig := ldapService.GetConfig()
+	if user.LdapDN != nil && ldapConfig.Enabled && ldapConfig.URL != "" && ldapConfig.BaseDN != "" {
+		if err := verifyLDAPUser(ldapConfig, &user, account, password); err != nil {
+			if !stderrors.Is(err, ldapService.ErrInvalidCredentials) {
+				return nil, err
+			}
+		} else {
+			passwordValid = true
+		}
+	} else {
+		passwordValid = user.Password != "" && utils.ComparePasswords(user.Password, password)
+	}
+
+	attemptKey := fmt.Sprintf("user:login:attempts:%d", user.ID)
+	if !passwordValid {
+		attemptCount := 0
+		if val, err := cache.GetCache().Get(attemptKey); err == nil && val != "" {
+			attemptCount, _ = strconv.Atoi(val)
+		}
+		attemptCount++
+		_ = cache.GetCache().Set(attemptKey, strconv.Itoa(attemptCount), time.Minute)
+
+		maxAttempts := setting.GetInt("security", "max_login_attempts", 5)
+		if attemptCount >= maxAttempts {
+			lockoutMinutes := setting.GetInt("security", "account_lockout_minutes", 30)
+			_ = cache.GetCache().Set(lockKey, "1", time.Duration(lockoutMinutes)*time.Minute)
+			_ = cache.GetCache().Del(attemptKey)
+		}
+		return nil, errors.New(errors.CodeUnauthorized, "用户名或密码错误")
+	}
+	_ = cache.GetCache().Del(attemptKey)
+
+	if !user.IsNormal() {
+		return nil, errors.New(errors.CodeUserDisabled, "账号已被禁用")
+	}
+	return &user, nil
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	stderrors "errors"
+	"strings"
+
+	"pixelpunk/internal/models"
+	ldapService "pixelpunk/internal/services/ldap"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+)
+
+/* authenticateLDAPUser LDAP认证并返回对应的本地用户，首次登录时即时创建 */
+func authenticateLDAPUser(cfg *ldapService.Config, account, password string) (*models.User, error) {
+	entry, err := ldapService.Authenticate(cfg, account, password)
+	if err != nil {
+		if stderrors.Is(err, ldapService.ErrInvalidCredentials) {
+			return nil, err
+		}
+		logger.Error("LDAP认证失败: account=%s, error=%v", account, err)
+		return nil, errors.New(errors.CodeInternal, "LDAP认证服务不可用，请稍后再试")
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.Where("ldap_dn = ?", entry.DN).First(&user).Error; err == nil {
+		syncLDAPUserRole(cfg, &user, entry)
+		return &user, nil
+	}
+
+	if !cfg.AutoCreateUser {
+		return nil, errors.New(errors.CodeForbidden, "LDAP账号尚未开通，请联系管理员")
+	}
+
+	return createLDAPUser(entry)
+}
+
+/* verifyLDAPUser 校验已关联LDAP的本地用户密码，目录中的DN必须与本地记录一致 */
+func verifyLDAPUser(cfg *ldapService.Config, user *models.User, account, password string) error {
+	entry, err := ldapService.Authenticate(cfg, account, password)
+	if err != nil {
+		if stderrors.Is(err, ldapService.ErrInvalidCredentials) {
+			return err
+		}
+		logger.Error("LDAP认证失败: account=%s, error=%v", account, err)
+		return errors.New(errors.CodeInternal, "LDAP认证服务不可用，请稍后再试")
+	}
+	if !strings.EqualFold(entry.DN, *user.LdapDN) {
+		return ldapService.ErrInvalidCredentials
+	}
+
+	syncLDAPUserRole(cfg, user, entry)
+	return nil
+}
+
+// syncLDAPUserRole 配置了组映射时按目录组同步角色，超级管理员不会被自动降级
+func syncLDAPUserRole(cfg *ldapService.Config, user *models.User, entry *ldapService.Entry) {
+	syncSSOUserRole("LDAP", cfg.GroupRoleMapping != "", user, entry.Role)
+}
+
+func createLDAPUser(entry *ldapService.Entry) (*models.User, error) {
+	dn := entry.DN
+	user, err := provisionSSOUser(ssoAccount{
+		Source:   "LDAP",
+		Username: entry.Username,
+		Email:    entry.Email,
+		Role:     entry.Role,
+		Bind:     func(u *models.User) { u.LdapDN = &dn },
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("LDAP用户首次登录已创建本地账号: userID=%d, dn=%s", user.ID, entry.DN)
+	return user, nil
+}
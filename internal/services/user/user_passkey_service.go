@@ -0,0 +1,455 @@
+package user
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/auth"
+	samlService "pixelpunk/internal/services/saml"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	passkeySessionTTL    = 5 * time.Minute
+	passkeyMaxPerUser    = 20
+	passkeySessionPrefix = "passkey:session:"
+)
+
+// passkeySession 一次注册或登录仪式的服务端状态，保存在缓存中且只能使用一次
+type passkeySession struct {
+	UserID  uint                 `json:"user_id"`
+	Purpose string               `json:"purpose"`
+	Data    webauthn.SessionData `json:"data"`
+}
+
+// passkeyUser 适配 webauthn.User 接口
+type passkeyUser struct {
+	user     *models.User
+	passkeys []models.UserPasskey
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return passkeyUserHandle(u.user.ID)
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, pk := range u.passkeys {
+		id, err := base64.RawURLEncoding.DecodeString(pk.CredentialID)
+		if err != nil {
+			continue
+		}
+		var transports []protocol.AuthenticatorTransport
+		for _, t := range strings.Split(pk.Transports, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				transports = append(transports, protocol.AuthenticatorTransport(t))
+			}
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              id,
+			PublicKey:       pk.PublicKey,
+			AttestationType: pk.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: pk.BackupEligible,
+				BackupState:    pk.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				SignCount: pk.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+func passkeyUserHandle(userID uint) []byte {
+	return []byte(strconv.FormatUint(uint64(userID), 10))
+}
+
+/* newWebAuthn 按安全设置构造 WebAuthn 实例，RP ID 和来源默认取自站点地址 */
+func newWebAuthn() (*webauthn.WebAuthn, error) {
+	if !setting.GetBool("security", "passkey_enabled", true) {
+		return nil, errors.New(errors.CodeForbidden, "管理员已关闭通行密钥登录")
+	}
+
+	baseURL := strings.TrimRight(setting.GetString("website", "site_base_url", ""), "/")
+	rpID := setting.GetString("security", "passkey_rp_id", "")
+	var origins []string
+	for _, origin := range strings.Split(setting.GetString("security", "passkey_rp_origins", ""), ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	if baseURL != "" {
+		if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+			if rpID == "" {
+				rpID = parsed.Hostname()
+			}
+			if len(origins) == 0 {
+				origins = append(origins, parsed.Scheme+"://"+parsed.Host)
+			}
+		}
+	}
+	if rpID == "" || len(origins) == 0 {
+		return nil, errors.New(errors.CodeInternal, "通行密钥未配置：请先设置站点地址或RP ID")
+	}
+
+	siteName := setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk")
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: siteName,
+		RPOrigins:     origins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: passkeySessionTTL, TimeoutUVD: passkeySessionTTL},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: passkeySessionTTL, TimeoutUVD: passkeySessionTTL},
+		},
+	})
+	if err != nil {
+		logger.Error("通行密钥配置无效: rpID=%s, origins=%v, error=%v", rpID, origins, err)
+		return nil, errors.New(errors.CodeInternal, "通行密钥配置无效")
+	}
+	return w, nil
+}
+
+func savePasskeySession(session *passkeySession) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", errors.New(errors.CodeInternal, "保存通行密钥会话失败")
+	}
+	sessionID := utils.GenerateRandomString(32)
+	if err := cache.GetCache().Set(passkeySessionPrefix+sessionID, string(data), passkeySessionTTL); err != nil {
+		return "", errors.New(errors.CodeInternal, "保存通行密钥会话失败")
+	}
+	return sessionID, nil
+}
+
+// loadPasskeySession 取出会话后立即删除，防止挑战被重放
+func loadPasskeySession(sessionID, purpose string) (*passkeySession, error) {
+	key := passkeySessionPrefix + sessionID
+	raw, err := cache.GetCache().Get(key)
+	if err != nil || raw == "" {
+		return nil, errors.New(errors.CodeValidationFailed, "通行密钥会话已过期，请重试")
+	}
+	_ = cache.GetCache().Del(key)
+
+	var session passkeySession
+	if err := json.Unmarshal([]byte(raw), &session); err != nil || session.Purpose != purpose {
+		return nil, errors.New(errors.CodeValidationFailed, "通行密钥会话无效，请重试")
+	}
+	return &session, nil
+}
+
+func loadPasskeyUser(userID uint) (*passkeyUser, error) {
+	db := database.GetDB()
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	var passkeys []models.UserPasskey
+	if err := db.Where("user_id = ?", userID).Find(&passkeys).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询通行密钥失败")
+	}
+	return &passkeyUser{user: &user, passkeys: passkeys}, nil
+}
+
+/* BeginPasskeyRegistration 生成注册通行密钥的挑战，已有凭据会被排除以免同一设备重复注册 */
+func BeginPasskeyRegistration(userID uint) (*protocol.CredentialCreation, string, error) {
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, "", err
+	}
+	pu, err := loadPasskeyUser(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(pu.passkeys) >= passkeyMaxPerUser {
+		return nil, "", errors.New(errors.CodeValidationFailed, fmt.Sprintf("每个账号最多注册%d个通行密钥", passkeyMaxPerUser))
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(pu.passkeys))
+	for _, cred := range pu.WebAuthnCredentials() {
+		exclusions = append(exclusions, cred.Descriptor())
+	}
+
+	creation, sessionData, err := w.BeginRegistration(pu,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		logger.Error("生成通行密钥注册挑战失败: userID=%d, error=%v", userID, err)
+		return nil, "", errors.New(errors.CodeInternal, "生成通行密钥注册挑战失败")
+	}
+
+	sessionID, err := savePasskeySession(&passkeySession{UserID: userID, Purpose: "register", Data: *sessionData})
+	if err != nil {
+		return nil, "", err
+	}
+	return creation, sessionID, nil
+}
+
+/* FinishPasskeyRegistration 校验浏览器返回的证明并保存凭据 */
+func FinishPasskeyRegistration(userID uint, sessionID, name string, credential []byte) (*models.UserPasskey, error) {
+	session, err := loadPasskeySession(sessionID, "register")
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, errors.New(errors.CodeValidationFailed, "通行密钥会话无效，请重试")
+	}
+
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	pu, err := loadPasskeyUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(credential))
+	if err != nil {
+		return nil, errors.New(errors.CodeInvalidParameter, "通行密钥凭据格式错误")
+	}
+	cred, err := w.CreateCredential(pu, session.Data, parsed)
+	if err != nil {
+		logger.Warn("通行密钥注册校验失败: userID=%d, error=%v", userID, err)
+		return nil, errors.New(errors.CodeValidationFailed, "通行密钥校验失败")
+	}
+
+	transports := make([]string, 0, len(cred.Transport))
+	for _, t := range cred.Transport {
+		transports = append(transports, string(t))
+	}
+	aaguid := ""
+	if id, err := uuid.FromBytes(cred.Authenticator.AAGUID); err == nil {
+		aaguid = id.String()
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fmt.Sprintf("通行密钥 %d", len(pu.passkeys)+1)
+	}
+
+	passkey := models.UserPasskey{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    base64.RawURLEncoding.EncodeToString(cred.ID),
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		AAGUID:          aaguid,
+		Transports:      strings.Join(transports, ","),
+		SignCount:       cred.Authenticator.SignCount,
+		BackupEligible:  cred.Flags.BackupEligible,
+		BackupState:     cred.Flags.BackupState,
+	}
+
+	db := database.GetDB()
+	var count int64
+	db.Model(&models.UserPasskey{}).Where("credential_id = ?", passkey.CredentialID).Count(&count)
+	if count > 0 {
+		return nil, errors.New(errors.CodeValidationFailed, "该通行密钥已注册")
+	}
+	if err := db.Create(&passkey).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "保存通行密钥失败")
+	}
+
+	return &passkey, nil
+}
+
+/* BeginPasskeyLogin 生成登录挑战；未提供账号，或账号不存在、尚未注册通行密钥时使用可发现凭据（无用户名登录），
+ * 响应不区分这几种情况，避免通过该接口探测账号是否存在 */
+func BeginPasskeyLogin(account string) (*protocol.CredentialAssertion, string, error) {
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		assertion   *protocol.CredentialAssertion
+		sessionData *webauthn.SessionData
+		userID      uint
+	)
+
+	if pu := findPasskeyLoginUser(strings.TrimSpace(account)); pu != nil {
+		userID = pu.user.ID
+		assertion, sessionData, err = w.BeginLogin(pu)
+	} else {
+		assertion, sessionData, err = w.BeginDiscoverableLogin()
+	}
+	if err != nil {
+		logger.Error("生成通行密钥登录挑战失败: account=%s, error=%v", account, err)
+		return nil, "", errors.New(errors.CodeInternal, "生成通行密钥登录挑战失败")
+	}
+
+	sessionID, err := savePasskeySession(&passkeySession{UserID: userID, Purpose: "login", Data: *sessionData})
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, sessionID, nil
+}
+
+// findPasskeyLoginUser 按用户名或邮箱查找已注册通行密钥的用户，找不到时返回 nil
+func findPasskeyLoginUser(account string) *passkeyUser {
+	if account == "" {
+		return nil
+	}
+	var user models.User
+	if err := database.GetDB().Where("username = ? OR email = ?", account, account).First(&user).Error; err != nil {
+		return nil
+	}
+	pu, err := loadPasskeyUser(user.ID)
+	if err != nil || len(pu.passkeys) == 0 {
+		return nil
+	}
+	return pu
+}
+
+/* FinishPasskeyLogin 校验断言签名，成功后与密码登录一样签发token */
+func FinishPasskeyLogin(sessionID string, credential []byte, client auth.ClientInfo) (map[string]interface{}, string, error) {
+	session, err := loadPasskeySession(sessionID, "login")
+	if err != nil {
+		return nil, "", err
+	}
+
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, "", err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(credential))
+	if err != nil {
+		return nil, "", errors.New(errors.CodeInvalidParameter, "通行密钥凭据格式错误")
+	}
+
+	var pu *passkeyUser
+	var cred *webauthn.Credential
+	if session.UserID == 0 {
+		cred, err = w.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			id, parseErr := strconv.ParseUint(string(userHandle), 10, 64)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			loaded, loadErr := loadPasskeyUser(uint(id))
+			if loadErr != nil {
+				return nil, loadErr
+			}
+			pu = loaded
+			return loaded, nil
+		}, session.Data, parsed)
+	} else {
+		pu, err = loadPasskeyUser(session.UserID)
+		if err != nil {
+			return nil, "", err
+		}
+		cred, err = w.ValidateLogin(pu, session.Data, parsed)
+	}
+	if err != nil || pu == nil {
+		logger.Warn("通行密钥登录校验失败: error=%v", err)
+		return nil, "", errors.New(errors.CodeUnauthorized, "通行密钥校验失败")
+	}
+
+	user := pu.user
+	// 与密码登录一致，强制SSO时只保留超级管理员的本地登录方式
+	if samlService.IsSSOEnforced() && !user.IsSuperAdmin() {
+		return nil, "", errors.New(errors.CodeForbidden, "系统已启用强制SSO登录，请使用企业SSO登录")
+	}
+	if cache.GetCache().Exists(fmt.Sprintf("user:login:lock:%d", user.ID)) {
+		return nil, "", errors.New(errors.CodeForbidden, "账户已被锁定，请稍后再试")
+	}
+	if !user.IsNormal() {
+		return nil, "", errors.New(errors.CodeUserDisabled, "账号已被禁用")
+	}
+
+	updates := map[string]interface{}{
+		"sign_count":   cred.Authenticator.SignCount,
+		"backup_state": cred.Flags.BackupState,
+		"last_used_at": common.JSONTimeNow(),
+		"last_used_ip": client.IP,
+	}
+	if cred.Authenticator.CloneWarning {
+		logger.Warn("通行密钥签名计数器回退，可能存在克隆设备: userID=%d", user.ID)
+		updates["clone_warning"] = true
+	}
+	if err := database.GetDB().Model(&models.UserPasskey{}).
+		Where("user_id = ? AND credential_id = ?", user.ID, base64.RawURLEncoding.EncodeToString(cred.ID)).
+		Updates(updates).Error; err != nil {
+		logger.Warn("更新通行密钥使用记录失败: userID=%d, error=%v", user.ID, err)
+	}
+
+	jwtSecret := setting.GetString("security", "jwt_secret", "")
+	if jwtSecret == "" {
+		return nil, "", errors.New(errors.CodeInternal, "安全配置缺失：jwt_secret 未设置")
+	}
+	expiresHours := setting.GetInt("security", "login_expire_hours", 0)
+	if expiresHours <= 0 {
+		return nil, "", errors.New(errors.CodeInternal, "安全配置缺失：login_expire_hours 未设置或非法")
+	}
+
+	return buildLoginResult(user, "passkey", client, jwtSecret, expiresHours)
+}
+
+/* ListUserPasskeys 获取用户已注册的通行密钥 */
+func ListUserPasskeys(userID uint) ([]models.UserPasskey, error) {
+	var passkeys []models.UserPasskey
+	if err := database.GetDB().Where("user_id = ?", userID).Order("id ASC").Find(&passkeys).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询通行密钥失败")
+	}
+	return passkeys, nil
+}
+
+/* RenameUserPasskey 修改通行密钥的设备名称 */
+func RenameUserPasskey(userID, passkeyID uint, name string) error {
+	result := database.GetDB().Model(&models.UserPasskey{}).
+		Where("id = ? AND user_id = ?", passkeyID, userID).
+		Update("name", strings.TrimSpace(name))
+	if result.Error != nil {
+		return errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "修改通行密钥名称失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeNotFound, "通行密钥不存在")
+	}
+	return nil
+}
+
+/* DeleteUserPasskey 删除通行密钥，删除后对应设备无法再登录 */
+func DeleteUserPasskey(userID, passkeyID uint) error {
+	result := database.GetDB().Where("id = ? AND user_id = ?", passkeyID, userID).Delete(&models.UserPasskey{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "删除通行密钥失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeNotFound, "通行密钥不存在")
+	}
+	return nil
+}
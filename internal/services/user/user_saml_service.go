@@ -0,0 +1,107 @@
+package user
+
+import (
+	"strconv"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/auth"
+	samlService "pixelpunk/internal/services/saml"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+const (
+	samlTicketKeyPrefix = "saml:ticket:"
+	samlTicketTTL       = 2 * time.Minute
+)
+
+/* CreateSAMLLoginTicket 根据断言身份关联或创建本地用户，返回换取登录凭证的一次性票据 */
+func CreateSAMLLoginTicket(identity *samlService.Identity) (string, error) {
+	cfg := samlService.GetConfig()
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.Where("saml_id = ?", identity.NameID).First(&user).Error; err == nil {
+		syncSAMLUserRole(cfg, &user, identity)
+	} else {
+		if !cfg.AutoCreateUser {
+			return "", errors.New(errors.CodeForbidden, "SSO账号尚未开通，请联系管理员")
+		}
+		created, err := createSAMLUser(identity)
+		if err != nil {
+			return "", err
+		}
+		user = *created
+	}
+
+	if !user.IsNormal() {
+		return "", errors.New(errors.CodeUserDisabled, "账号已被禁用")
+	}
+
+	ticket := utils.GenerateRandomString(32)
+	if err := cache.GetCache().Set(samlTicketKeyPrefix+ticket, strconv.FormatUint(uint64(user.ID), 10), samlTicketTTL); err != nil {
+		return "", errors.Wrap(err, errors.CodeInternal, "保存登录票据失败")
+	}
+	return ticket, nil
+}
+
+/* ExchangeSAMLTicket 使用一次性票据换取登录凭证 */
+func ExchangeSAMLTicket(ticket string, client auth.ClientInfo) (map[string]interface{}, string, error) {
+	key := samlTicketKeyPrefix + ticket
+	val, err := cache.GetCache().Get(key)
+	if err != nil || val == "" {
+		return nil, "", errors.New(errors.CodeUnauthorized, "登录票据无效或已过期，请重新登录")
+	}
+	_ = cache.GetCache().Del(key)
+
+	userID, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return nil, "", errors.New(errors.CodeUnauthorized, "登录票据无效")
+	}
+
+	var user models.User
+	if err := database.GetDB().First(&user, uint(userID)).Error; err != nil {
+		return nil, "", errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	if !user.IsNormal() {
+		return nil, "", errors.New(errors.CodeUserDisabled, "账号已被禁用")
+	}
+
+	jwtSecret := setting.GetString("security", "jwt_secret", "")
+	if jwtSecret == "" {
+		return nil, "", errors.New(errors.CodeInternal, "安全配置缺失：jwt_secret 未设置")
+	}
+	expiresHours := setting.GetInt("security", "login_expire_hours", 0)
+	if expiresHours <= 0 {
+		return nil, "", errors.New(errors.CodeInternal, "安全配置缺失：login_expire_hours 未设置或非法")
+	}
+
+	return buildLoginResult(&user, "saml", client, jwtSecret, expiresHours)
+}
+
+// syncSAMLUserRole 配置了组映射时按断言中的组同步角色，超级管理员不会被自动降级
+func syncSAMLUserRole(cfg *samlService.Config, user *models.User, identity *samlService.Identity) {
+	syncSSOUserRole("SAML", cfg.GroupRoleMapping != "", user, identity.Role)
+}
+
+func createSAMLUser(identity *samlService.Identity) (*models.User, error) {
+	nameID := identity.NameID
+	user, err := provisionSSOUser(ssoAccount{
+		Source:   "SSO",
+		Username: identity.Username,
+		Email:    identity.Email,
+		Role:     identity.Role,
+		Bind:     func(u *models.User) { u.SamlID = &nameID },
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("SAML用户首次登录已创建本地账号: userID=%d, nameID=%s", user.ID, identity.NameID)
+	return user, nil
+}
@@ -1,13 +1,18 @@
 package user
 
 import (
+	stderrors "errors"
 	"fmt"
+	"html"
 	"math/rand"
 	"pixelpunk/internal/controllers/user/dto"
 	"pixelpunk/internal/models"
 	"pixelpunk/internal/services/auth"
 	folderService "pixelpunk/internal/services/folder"
+	inviteService "pixelpunk/internal/services/invite"
+	ldapService "pixelpunk/internal/services/ldap"
 	messageService "pixelpunk/internal/services/message"
+	samlService "pixelpunk/internal/services/saml"
 	"pixelpunk/internal/services/setting"
 	"pixelpunk/internal/services/stats"
 	"pixelpunk/pkg/cache"
@@ -38,12 +43,33 @@ func GetUserService() *UserService {
 	return userService
 }
 
-func Login(account, password string) (map[string]interface{}, string, error) {
+func Login(account, password string, client auth.ClientInfo) (map[string]interface{}, string, error) {
 	db := database.GetDB()
 	var user models.User
 	result := db.Where("username = ? OR email = ?", account, account).First(&user)
+
+	// 强制SSO时保留超级管理员的密码登录，避免IdP故障时无法进入后台
+	if samlService.IsSSOEnforced() && (result.Error != nil || !user.IsSuperAdmin()) {
+		return nil, "", errors.New(errors.CodeForbidden, "系统已启用强制SSO登录，请使用企业SSO登录")
+	}
+
+	ldapConfig := ldapService.GetConfig()
+	ldapEnabled := ldapConfig.Enabled && ldapConfig.URL != "" && ldapConfig.BaseDN != ""
+	ldapAuthenticated := false
 	if result.Error != nil {
-		return nil, "", errors.New(errors.CodeUserNotFound, "用户不存在")
+		if !ldapEnabled {
+			return nil, "", errors.New(errors.CodeUserNotFound, "用户不存在")
+		}
+		// 本地不存在时尝试LDAP认证，首次登录即时创建本地用户
+		ldapUser, err := authenticateLDAPUser(ldapConfig, account, password)
+		if err != nil {
+			if stderrors.Is(err, ldapService.ErrInvalidCredentials) {
+				return nil, "", errors.New(errors.CodeUserNotFound, "用户不存在")
+			}
+			return nil, "", err
+		}
+		user = *ldapUser
+		ldapAuthenticated = true
 	}
 
 	securitySettings, err := setting.GetSettingsByGroupAsMap("security")
@@ -117,7 +143,22 @@ func Login(account, password string) (map[string]interface{}, string, error) {
 		}
 	}
 
-	if !utils.ComparePasswords(user.Password, password) {
+	passwordValid := ldapAuthenticated
+	if !passwordValid {
+		if user.LdapDN != nil && ldapEnabled {
+			if err := verifyLDAPUser(ldapConfig, &user, account, password); err != nil {
+				if !stderrors.Is(err, ldapService.ErrInvalidCredentials) {
+					return nil, "", err
+				}
+			} else {
+				passwordValid = true
+			}
+		} else {
+			passwordValid = utils.ComparePasswords(user.Password, password)
+		}
+	}
+
+	if !passwordValid {
 		attemptCount++
 		_ = cache.GetCache().Set(attemptKey, fmt.Sprintf("%d", attemptCount), time.Minute)
 
@@ -139,11 +180,24 @@ func Login(account, password string) (map[string]interface{}, string, error) {
 		return nil, "", errors.New(errors.CodeUserDisabled, "账号已被禁用")
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username, int(user.Role), jwtSecret, expiresHours)
+	loginMethod := "password"
+	if user.LdapDN != nil {
+		loginMethod = "ldap"
+	}
+	return buildLoginResult(&user, loginMethod, client, jwtSecret, expiresHours)
+}
+
+/* buildLoginResult 为已通过认证的用户创建登录会话、签发token并组装登录返回的用户信息 */
+func buildLoginResult(user *models.User, loginMethod string, client auth.ClientInfo, jwtSecret string, expiresHours int) (map[string]interface{}, string, error) {
+	token, err := auth.IssueSessionToken(user, loginMethod, client, jwtSecret, expiresHours)
 	if err != nil {
+		logger.Error("签发登录凭证失败: userID=%d, error=%v", user.ID, err)
 		return nil, "", errors.New(errors.CodeInternal, "生成token失败")
 	}
 
+	loginUser := *user
+	go CheckLoginAnomaly(&loginUser, client)
+
 	avatarFullPath := ""
 	if user.Avatar != "" {
 		avatarFullPath = utils.GetSystemFileURL(user.Avatar)
@@ -193,13 +247,16 @@ func FindUserByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// 邮箱验证码有效期
+const verificationCodeTTL = 5 * time.Minute
+
 func generateVerificationCode(email string, codeType string) string {
 	rand.Seed(time.Now().UnixNano())
 
 	code := fmt.Sprintf("%06d", rand.Intn(1000000))
 
 	key := fmt.Sprintf("%s:%s:code", email, codeType)
-	err := cache.GetCache().Set(key, code, 5*time.Minute)
+	err := cache.GetCache().Set(key, code, verificationCodeTTL)
 	if err != nil {
 		logger.Warn("存储验证码到缓存失败: %v", err)
 		return ""
@@ -293,9 +350,13 @@ func ValidateCode(email, code, codeType string) bool {
 	return false
 }
 
-func RegisterUser(username, email, password, code string) (uint, error) {
+func RegisterUser(username, email, password, code, inviteCode string) (uint, error) {
 	db := database.GetDB()
 
+	if samlService.IsSSOEnforced() {
+		return 0, errors.New(errors.CodeForbidden, "系统已启用强制SSO登录，请通过企业SSO登录自动开通账号")
+	}
+
 	registrationSettings, err := setting.GetSettingsByGroupAsMap("registration")
 	if err != nil {
 		return 0, errors.New(errors.CodeInternal, "获取系统设置失败")
@@ -306,6 +367,13 @@ func RegisterUser(username, email, password, code string) (uint, error) {
 		return 0, errors.New(errors.CodeForbidden, "管理员已关闭注册功能")
 	}
 
+	inviteOnly := inviteService.GetRegistrationMode() == inviteService.RegistrationModeInvite
+	if inviteOnly {
+		if _, err := inviteService.CheckCode(inviteCode); err != nil {
+			return 0, err
+		}
+	}
+
 	emailVerification, ok := registrationSettings.Settings["email_verification"].(bool)
 	if ok && emailVerification {
 		if !ValidateCode(email, code, common.CodeTypeRegister) {
@@ -343,54 +411,83 @@ func RegisterUser(username, email, password, code string) (uint, error) {
 			Bio:      randomBio, // 设置随机签名
 		}
 
+		if inviteOnly {
+			invitation, err := inviteService.ConsumeCode(tx, inviteCode)
+			if err != nil {
+				return err
+			}
+			user.InvitationCodeID = &invitation.ID
+		}
+
 		if err := tx.Create(&user).Error; err != nil {
 			return errors.New(errors.CodeDBCreateFailed, "创建用户失败")
 		}
 
-		return nil
+		return initNewUserResources(tx, &user, registrationSettings.Settings)
 	})
 
 	if err != nil {
 		return 0, err
 	}
+	onNewUserCreated(&user, registrationSettings.Settings)
+
+	return user.ID, nil
+}
+
+/* initNewUserResources 在 tx 中创建新用户的设置与用量统计记录，初始配额取自注册设置 */
+func initNewUserResources(tx *gorm.DB, user *models.User, registrationSettings map[string]interface{}) error {
+	initialStorage, initialBandwidth := initialUserQuota(registrationSettings)
+	now := common.JSONTimeNow()
+
+	settings := models.UserSettings{
+		UserID:             user.ID,
+		StorageLimit:       initialStorage,
+		BandwidthLimit:     initialBandwidth,
+		DefaultAccessLevel: "private",
+		AIEnabled:          true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := tx.Create(&settings).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBCreateFailed, "创建用户设置记录失败")
+	}
+
+	usage := models.UserUsageStats{UserID: user.ID, UpdatedAt: now}
+	if err := tx.Create(&usage).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBCreateFailed, "创建用户统计记录失败")
+	}
+	return nil
+}
 
+/* onNewUserCreated 新用户事务提交后生成路径别名、记录统计并发送欢迎消息 */
+func onNewUserCreated(user *models.User, registrationSettings map[string]interface{}) {
 	if _, aliasErr := tenant.ResolveAlias(user.ID); aliasErr != nil {
 		logger.Warn("生成用户路径别名失败: userID=%d, err=%v", user.ID, aliasErr)
 	}
 
-	if err := InitUserSettings(user.ID); err != nil {
-		return 0, err
-	}
+	stats.GetStatsAdapter().RecordUserCreated()
+
+	initialStorage, initialBandwidth := initialUserQuota(registrationSettings)
+	go sendRegistrationWelcomeMessage(user.ID, user.Username, initialStorage, initialBandwidth)
+}
 
+// initialUserQuota 注册设置中的初始存储空间与带宽（MB），未配置时默认50MB与1GB，返回字节数
+func initialUserQuota(registrationSettings map[string]interface{}) (int64, int64) {
 	initialStorage := int64(50) * 1024 * 1024     // 默认50MB，转换为字节
 	initialBandwidth := int64(1024) * 1024 * 1024 // 默认1GB，转换为字节
 
-	if storageValue, exists := registrationSettings.Settings["user_initial_storage"]; exists {
+	if storageValue, exists := registrationSettings["user_initial_storage"]; exists {
 		if storageInt, ok := storageValue.(float64); ok {
 			initialStorage = int64(storageInt) * 1024 * 1024 // 转换为字节
 		}
 	}
 
-	if bandwidthValue, exists := registrationSettings.Settings["user_initial_bandwidth"]; exists {
+	if bandwidthValue, exists := registrationSettings["user_initial_bandwidth"]; exists {
 		if bandwidthInt, ok := bandwidthValue.(float64); ok {
 			initialBandwidth = int64(bandwidthInt) * 1024 * 1024 // 转换为字节
 		}
 	}
-
-	_, err = UpdateUserSettings(user.ID, initialStorage, initialBandwidth, "", false)
-	if err != nil {
-		return 0, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新用户存储空间和带宽设置失败")
-	}
-
-	if err := InitUserUsageStats(user.ID); err != nil {
-		return 0, err
-	}
-
-	stats.GetStatsAdapter().RecordUserCreated()
-
-	go sendRegistrationWelcomeMessage(user.ID, username, initialStorage, initialBandwidth)
-
-	return user.ID, nil
+	return initialStorage, initialBandwidth
 }
 
 func GetUserFolders(userID uint, query *dto.FolderQueryDTO) (interface{}, error) {
@@ -518,18 +615,26 @@ func ResetPassword(email, code, newPassword string) error {
 		return errors.New(errors.CodeInternal, "密码加密失败")
 	}
 
-	result := db.Model(&models.User{}).Where("email = ?", email).Update("password", hashedPassword)
-	if result.Error != nil {
-		return errors.New(errors.CodeDBUpdateFailed, "更新密码失败")
+	var user models.User
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+		return errors.New(errors.CodeUserNotFound, "未找到用户")
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New(errors.CodeUserNotFound, "未找到用户")
+	if err := db.Model(&user).Update("password", hashedPassword).Error; err != nil {
+		return errors.New(errors.CodeDBUpdateFailed, "更新密码失败")
 	}
 
+	revokeAllSessions(user.ID)
 	return nil
 }
 
+// revokeAllSessions 密码被重置后注销该用户所有设备上的登录会话
+func revokeAllSessions(userID uint) {
+	if _, err := auth.RevokeUserSessions(userID, ""); err != nil {
+		logger.Warn("注销用户登录会话失败: userID=%d, error=%v", userID, err)
+	}
+}
+
 /* UpdatePassword 修改密码（需验证旧密码） */
 func UpdatePassword(userID uint, oldPassword, newPassword string) error {
 	db := database.GetDB()
@@ -555,6 +660,7 @@ func UpdatePassword(userID uint, oldPassword, newPassword string) error {
 	return nil
 }
 
+/* SendChangeEmailCode 向新邮箱发送验证码并记录待验证邮箱，验证通过前当前邮箱保持不变 */
 func SendChangeEmailCode(userID uint, newEmail string) error {
 	currentUser, err := FindUserByID(fmt.Sprintf("%d", userID))
 	if err != nil {
@@ -575,13 +681,24 @@ func SendChangeEmailCode(userID uint, newEmail string) error {
 		return errors.New(errors.CodeEmailExists, "该邮箱已被注册")
 	}
 
+	if !email.IsMailEnabled() {
+		return errors.New(errors.CodeEmailServiceError, "邮件服务不可用，请联系管理员")
+	}
+
 	code := generateVerificationCode(newEmail, common.CodeTypeChangeEmail)
 	if code == "" {
 		return errors.New(errors.CodeValidationFailed, "生成验证码失败")
 	}
 
-	err = sendVerificationEmail(newEmail, code, common.CodeTypeChangeEmail)
-	if err != nil {
+	expiresAt := common.JSONTime(time.Now().Add(verificationCodeTTL))
+	if err := database.GetDB().Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"pending_email":            newEmail,
+		"pending_email_expires_at": &expiresAt,
+	}).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "记录待验证邮箱失败")
+	}
+
+	if err := sendEmailChangeCode(currentUser, newEmail, code); err != nil {
 		return err
 	}
 
@@ -590,20 +707,21 @@ func SendChangeEmailCode(userID uint, newEmail string) error {
 	return nil
 }
 
+/* ChangeEmail 校验新邮箱收到的验证码后更换邮箱，并通知原邮箱 */
 func ChangeEmail(userID uint, newEmail, code string) error {
 	db := database.GetDB()
 
-	if !ValidateCode(newEmail, code, common.CodeTypeChangeEmail) {
-		return errors.New(errors.CodeInvalidVerifyCode, "验证码无效或已过期")
+	var currentUser models.User
+	if err := db.First(&currentUser, userID).Error; err != nil {
+		return errors.New(errors.CodeUserNotFound, "用户不存在")
 	}
 
-	currentUser, err := FindUserByID(fmt.Sprintf("%d", userID))
-	if err != nil {
-		return errors.New(errors.CodeUserNotFound, "用户不存在")
+	if !currentUser.HasPendingEmail() || currentUser.PendingEmail != newEmail {
+		return errors.New(errors.CodeInvalidVerifyCode, "请先获取新邮箱的验证码")
 	}
 
-	if currentUser.Email == newEmail {
-		return errors.New(errors.CodeInvalidRequest, "新邮箱不能与当前邮箱相同")
+	if !ValidateCode(newEmail, code, common.CodeTypeChangeEmail) {
+		return errors.New(errors.CodeInvalidVerifyCode, "验证码无效或已过期")
 	}
 
 	existingUser, err := FindUserByEmail(newEmail)
@@ -611,7 +729,12 @@ func ChangeEmail(userID uint, newEmail, code string) error {
 		return errors.New(errors.CodeEmailExists, "该邮箱已被注册")
 	}
 
-	result := db.Model(&models.User{}).Where("id = ?", userID).Update("email", newEmail)
+	oldEmail := currentUser.Email
+	result := db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":                    newEmail,
+		"pending_email":            "",
+		"pending_email_expires_at": nil,
+	})
 	if result.Error != nil {
 		return errors.New(errors.CodeDBUpdateFailed, "更新邮箱失败")
 	}
@@ -620,9 +743,92 @@ func ChangeEmail(userID uint, newEmail, code string) error {
 		return errors.New(errors.CodeUserNotFound, "未找到用户")
 	}
 
+	if oldEmail != "" {
+		go notifyEmailChanged(currentUser.Username, oldEmail, newEmail)
+	}
+
 	return nil
 }
 
+/* CancelEmailChange 放弃尚未验证的邮箱更换 */
+func CancelEmailChange(userID uint) error {
+	var currentUser models.User
+	if err := database.GetDB().First(&currentUser, userID).Error; err != nil {
+		return errors.New(errors.CodeUserNotFound, "用户不存在")
+	}
+	if currentUser.PendingEmail == "" {
+		return errors.New(errors.CodeInvalidRequest, "没有待验证的邮箱")
+	}
+
+	_ = cache.GetCache().Del(fmt.Sprintf("%s:%s:code", currentUser.PendingEmail, common.CodeTypeChangeEmail))
+
+	if err := database.GetDB().Model(&currentUser).Updates(map[string]interface{}{
+		"pending_email":            "",
+		"pending_email_expires_at": nil,
+	}).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "取消更换邮箱失败")
+	}
+	return nil
+}
+
+// sendEmailChangeCode 按消息模板渲染验证码邮件，模板停用时退回纯文本
+func sendEmailChangeCode(user *models.User, newEmail, code string) error {
+	variables := map[string]interface{}{
+		"username":       html.EscapeString(user.Username),
+		"site_name":      html.EscapeString(setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk")),
+		"code":           code,
+		"expire_minutes": int(verificationCodeTTL.Minutes()),
+	}
+
+	subject, body, err := messageService.GetMessageService().RenderTemplate(common.MessageTypeSecurityEmailChangeCode, variables)
+	if err != nil {
+		subject = "更换邮箱验证码"
+		body = fmt.Sprintf("您的验证码是: %s，%d分钟内有效。", code, int(verificationCodeTTL.Minutes()))
+	}
+
+	if err := email.SendMail(newEmail, subject, body); err != nil {
+		return errors.Wrap(err, errors.CodeEmailSendFailed, "发送邮件失败")
+	}
+	return nil
+}
+
+// notifyEmailChanged 邮箱更换成功后通知原邮箱，便于账号被盗时及时发现
+func notifyEmailChanged(username, oldEmail, newEmail string) {
+	if !email.IsMailEnabled() {
+		return
+	}
+
+	variables := map[string]interface{}{
+		"username":   html.EscapeString(username),
+		"site_name":  html.EscapeString(setting.GetStringDirectFromDB("website_info", "site_name", "PixelPunk")),
+		"old_email":  html.EscapeString(oldEmail),
+		"new_email":  html.EscapeString(maskEmail(newEmail)),
+		"changed_at": time.Now().Format("2006-01-02 15:04"),
+	}
+
+	subject, body, err := messageService.GetMessageService().RenderTemplate(common.MessageTypeSecurityEmailChanged, variables)
+	if err != nil {
+		logger.Warn("渲染邮箱更换通知失败: %v", err)
+		return
+	}
+	if err := email.SendMail(oldEmail, subject, body); err != nil {
+		logger.Warn("发送邮箱更换通知失败: email=%s, error=%v", oldEmail, err)
+	}
+}
+
+// maskEmail 隐藏邮箱用户名中间部分，避免通知邮件泄露完整的新邮箱
+func maskEmail(addr string) string {
+	at := strings.LastIndex(addr, "@")
+	if at <= 1 {
+		return addr
+	}
+	name := addr[:at]
+	if len(name) <= 2 {
+		return name[:1] + "***" + addr[at:]
+	}
+	return name[:1] + "***" + name[len(name)-1:] + addr[at:]
+}
+
 func sendRegistrationWelcomeMessage(userID uint, username string, initialStorage, initialBandwidth int64) {
 	siteName := "PixelPunk" // 默认值
 	constructionSettings, err := setting.GetSettingsByGroupAsMap("construction")
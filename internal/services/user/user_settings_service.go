@@ -5,6 +5,7 @@ import (
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
 
 	"gorm.io/gorm"
 )
@@ -25,6 +26,7 @@ func InitUserSettings(userID uint) error {
 		BandwidthLimit:     models.DefaultBandwidthLimit,
 		DefaultAccessLevel: "private",
 		OptimizeImages:     true,
+		AIEnabled:          true,
 		CreatedAt:          common.JSONTimeNow(),
 		UpdatedAt:          common.JSONTimeNow(),
 	}
@@ -81,3 +83,63 @@ func UpdateUserSettings(userID uint, storageLimit, bandwidthLimit int64, default
 
 	return settings, nil
 }
+
+// GetAIWebhookConfig 获取用户的AI处理完成回调配置
+func GetAIWebhookConfig(userID uint) (map[string]interface{}, error) {
+	settings, err := GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"url":    settings.AIWebhookURL,
+		"secret": settings.AIWebhookSecret,
+	}, nil
+}
+
+// UpdateAIWebhookConfig 更新AI处理完成回调地址；url 为空表示关闭回调，只允许公网地址
+func UpdateAIWebhookConfig(userID uint, url string, regenerateSecret bool) (map[string]interface{}, error) {
+	if url != "" {
+		if err := utils.ValidatePublicURL(url); err != nil {
+			return nil, errors.New(errors.CodeInvalidParameter, "回调地址无效: "+err.Error())
+		}
+	}
+
+	settings, err := GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.AIWebhookURL = url
+	if url != "" && (settings.AIWebhookSecret == "" || regenerateSecret) {
+		settings.AIWebhookSecret = utils.GenerateRandomString(32)
+	}
+	settings.UpdatedAt = common.JSONTimeNow()
+
+	if err := database.DB.Model(&models.UserSettings{}).Where("id = ?", settings.ID).Updates(map[string]interface{}{
+		"ai_webhook_url":    settings.AIWebhookURL,
+		"ai_webhook_secret": settings.AIWebhookSecret,
+		"updated_at":        settings.UpdatedAt,
+	}).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新回调配置失败")
+	}
+
+	return map[string]interface{}{
+		"url":    settings.AIWebhookURL,
+		"secret": settings.AIWebhookSecret,
+	}, nil
+}
+
+// SetUserAIEnabled 设置用户上传的文件是否进行AI处理
+func SetUserAIEnabled(userID uint, enabled bool) error {
+	settings, err := GetUserSettings(userID)
+	if err != nil {
+		return err
+	}
+	if err := database.DB.Model(&models.UserSettings{}).Where("id = ?", settings.ID).Updates(map[string]interface{}{
+		"ai_enabled": enabled,
+		"updated_at": common.JSONTimeNow(),
+	}).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDBUpdateFailed, "更新AI处理设置失败")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"fmt"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// ssoAccount 外部身份源（LDAP、SAML）提供的账号信息
+type ssoAccount struct {
+	Source   string // 身份源名称，用于提示与日志
+	Username string
+	Email    string
+	Role     int
+	Bind     func(user *models.User) // 写入本地用户与外部账号的关联字段
+}
+
+/* provisionSSOUser 为外部身份源账号创建本地用户：不按用户名或邮箱关联已有账号，用户名冲突时追加序号，
+ * 没有邮箱时存 NULL，用户与初始设置、用量统计在同一事务中创建 */
+func provisionSSOUser(account ssoAccount) (*models.User, error) {
+	db := database.GetDB()
+
+	// 不按用户名或邮箱自动关联本地账号，避免外部账号接管同名的本地管理员
+	if account.Email != "" {
+		var count int64
+		db.Model(&models.User{}).Where("email = ?", account.Email).Count(&count)
+		if count > 0 {
+			return nil, errors.New(errors.CodeEmailExists, "该邮箱已被本地账号使用，请联系管理员处理")
+		}
+	}
+
+	username, err := allocateSSOUsername(db, account)
+	if err != nil {
+		return nil, err
+	}
+
+	registrationSettings, err := setting.GetSettingsByGroupAsMap("registration")
+	if err != nil {
+		return nil, errors.New(errors.CodeInternal, "获取系统设置失败")
+	}
+
+	user := models.User{
+		Username: username,
+		Email:    account.Email,
+		Status:   common.UserStatusNormal,
+		Role:     account.Role,
+		Bio:      common.GetRandomBio(),
+	}
+	account.Bind(&user)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		create := tx
+		if user.Email == "" {
+			// 邮箱唯一索引允许多个 NULL，但不允许多个空串
+			create = tx.Omit("Email")
+		}
+		if err := create.Create(&user).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBCreateFailed, fmt.Sprintf("创建%s用户失败", account.Source))
+		}
+		return initNewUserResources(tx, &user, registrationSettings.Settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+	onNewUserCreated(&user, registrationSettings.Settings)
+
+	return &user, nil
+}
+
+// allocateSSOUsername 截断过长的用户名，与已有用户重名时依次追加序号
+func allocateSSOUsername(db *gorm.DB, account ssoAccount) (string, error) {
+	username := account.Username
+	if len(username) > 50 {
+		username = username[:50]
+	}
+	var count int64
+	db.Model(&models.User{}).Where("username = ?", username).Count(&count)
+	if count == 0 {
+		return username, nil
+	}
+	for i := 1; i < 100; i++ {
+		candidate := fmt.Sprintf("%s%d", username, i)
+		db.Model(&models.User{}).Where("username = ?", candidate).Count(&count)
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+	return "", errors.New(errors.CodeUserExists, fmt.Sprintf("无法为%s账号分配用户名", account.Source))
+}
+
+// syncSSOUserRole 配置了组映射时按外部组同步角色，超级管理员不会被自动降级
+func syncSSOUserRole(source string, mapped bool, user *models.User, role int) {
+	if !mapped || user.Role == role || user.IsSuperAdmin() {
+		return
+	}
+	if err := database.GetDB().Model(user).Update("role", role).Error; err != nil {
+		logger.Warn("同步%s用户角色失败: userID=%d, error=%v", source, user.ID, err)
+		return
+	}
+	logger.Info("%s用户角色已同步: userID=%d, %d -> %d", source, user.ID, user.Role, role)
+	user.Role = role
+}
@@ -0,0 +1,286 @@
+package user_group
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	groupProfileCacheKey = "user_group_profile:%d"
+	groupProfileCacheTTL = 10 * time.Minute
+	noGroupMarker        = "none"
+)
+
+func init() {
+	cache.RegisterLocalPrefix(strings.TrimSuffix(groupProfileCacheKey, "%d"))
+}
+
+/* GroupInput 创建或更新用户组的参数 */
+type GroupInput struct {
+	Name             string
+	Description      string
+	StorageLimit     int64
+	BandwidthLimit   int64
+	DailyUploadLimit int
+	DailyAILimit     int
+}
+
+/* GroupWithCount 用户组及成员数量 */
+type GroupWithCount struct {
+	models.UserGroup
+	MemberCount int64 `json:"member_count"`
+}
+
+/* CreateGroup 创建用户组 */
+func CreateGroup(input GroupInput) (*models.UserGroup, error) {
+	db := database.GetDB()
+
+	name := strings.TrimSpace(input.Name)
+	var count int64
+	db.Model(&models.UserGroup{}).Where("name = ?", name).Count(&count)
+	if count > 0 {
+		return nil, errors.New(errors.CodeDBDuplicate, "用户组名称已存在")
+	}
+
+	group := &models.UserGroup{
+		Name:             name,
+		Description:      input.Description,
+		StorageLimit:     input.StorageLimit,
+		BandwidthLimit:   input.BandwidthLimit,
+		DailyUploadLimit: input.DailyUploadLimit,
+		DailyAILimit:     input.DailyAILimit,
+	}
+	if err := db.Create(group).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建用户组失败")
+	}
+	return group, nil
+}
+
+/* UpdateGroup 更新用户组配额，成员的有效限额随之生效 */
+func UpdateGroup(groupID uint, input GroupInput) (*models.UserGroup, error) {
+	db := database.GetDB()
+
+	group, err := GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	var count int64
+	db.Model(&models.UserGroup{}).Where("name = ? AND id <> ?", name, groupID).Count(&count)
+	if count > 0 {
+		return nil, errors.New(errors.CodeDBDuplicate, "用户组名称已存在")
+	}
+
+	if err := db.Model(group).Updates(map[string]interface{}{
+		"name":               name,
+		"description":        input.Description,
+		"storage_limit":      input.StorageLimit,
+		"bandwidth_limit":    input.BandwidthLimit,
+		"daily_upload_limit": input.DailyUploadLimit,
+		"daily_ai_limit":     input.DailyAILimit,
+	}).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新用户组失败")
+	}
+
+	clearMembersCache(groupID)
+	return GetGroup(groupID)
+}
+
+/* DeleteGroup 删除用户组，成员恢复为各自的单独限额 */
+func DeleteGroup(groupID uint) error {
+	if _, err := GetGroup(groupID); err != nil {
+		return err
+	}
+
+	memberIDs := memberIDs(groupID)
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("group_id = ?", groupID).Update("group_id", nil).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBUpdateFailed, "移除用户组成员失败")
+		}
+		if err := tx.Where("target_type = ? AND target_id = ?", models.AnnouncementTargetGroup, groupID).
+			Delete(&models.AnnouncementTarget{}).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除公告投放对象失败")
+		}
+		if err := tx.Delete(&models.UserGroup{}, groupID).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDBDeleteFailed, "删除用户组失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	clearUsersCache(memberIDs)
+	return nil
+}
+
+/* GetGroup 获取用户组 */
+func GetGroup(groupID uint) (*models.UserGroup, error) {
+	var group models.UserGroup
+	if err := database.GetDB().First(&group, groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "用户组不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询用户组失败")
+	}
+	return &group, nil
+}
+
+/* ListGroups 获取全部用户组及成员数 */
+func ListGroups() ([]GroupWithCount, error) {
+	db := database.GetDB()
+
+	var groups []models.UserGroup
+	if err := db.Order("id ASC").Find(&groups).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "获取用户组列表失败")
+	}
+
+	type countRow struct {
+		GroupID uint
+		Total   int64
+	}
+	var rows []countRow
+	db.Model(&models.User{}).Select("group_id, COUNT(*) AS total").
+		Where("group_id IS NOT NULL").Group("group_id").Scan(&rows)
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.GroupID] = row.Total
+	}
+
+	result := make([]GroupWithCount, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, GroupWithCount{UserGroup: g, MemberCount: counts[g.ID]})
+	}
+	return result, nil
+}
+
+/* AssignUsers 将用户加入用户组，groupID 为0时移出用户组 */
+func AssignUsers(groupID uint, userIDs []uint) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, errors.New(errors.CodeInvalidParameter, "请选择用户")
+	}
+
+	var value interface{}
+	if groupID > 0 {
+		if _, err := GetGroup(groupID); err != nil {
+			return 0, err
+		}
+		value = groupID
+	}
+
+	result := database.GetDB().Model(&models.User{}).Where("id IN ?", userIDs).Update("group_id", value)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, errors.CodeDBUpdateFailed, "设置用户组失败")
+	}
+
+	clearUsersCache(userIDs)
+	logger.Info("用户组成员已更新: groupID=%d, 用户数=%d", groupID, result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
+/* GetUserGroup 获取用户所属的用户组（带缓存），未加入用户组时返回nil */
+func GetUserGroup(userID uint) *models.UserGroup {
+	key := fmt.Sprintf(groupProfileCacheKey, userID)
+	if cached, err := cache.Get(key); err == nil && cached != "" {
+		if cached == noGroupMarker {
+			return nil
+		}
+		var group models.UserGroup
+		if err := json.Unmarshal([]byte(cached), &group); err == nil {
+			return &group
+		}
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.Select("id", "group_id").First(&user, userID).Error; err != nil {
+		return nil
+	}
+
+	var group *models.UserGroup
+	if user.GroupID != nil {
+		var g models.UserGroup
+		if err := db.First(&g, *user.GroupID).Error; err == nil {
+			group = &g
+		}
+	}
+
+	value := noGroupMarker
+	if group != nil {
+		if data, err := json.Marshal(group); err == nil {
+			value = string(data)
+		}
+	}
+	cache.Set(key, value, groupProfileCacheTTL)
+	return group
+}
+
+/* ApplyGroupLimits 用户组配置了存储或流量配额时覆盖用户单独设置的限额 */
+func ApplyGroupLimits(userID uint, settings *models.UserSettings) {
+	group := GetUserGroup(userID)
+	if group == nil {
+		return
+	}
+	if group.StorageLimit > 0 {
+		settings.StorageLimit = group.StorageLimit
+	}
+	if group.BandwidthLimit > 0 {
+		settings.BandwidthLimit = group.BandwidthLimit
+	}
+}
+
+/* GetDailyUploadLimit 返回用户的每日上传数限制，用户组未配置时使用全局值 */
+func GetDailyUploadLimit(userID uint, globalLimit int) int {
+	if group := GetUserGroup(userID); group != nil && group.DailyUploadLimit != 0 {
+		return group.DailyUploadLimit
+	}
+	return globalLimit
+}
+
+/* CheckAIQuota 检查用户今天是否还能提交文件进行AI处理 */
+func CheckAIQuota(userID uint) bool {
+	group := GetUserGroup(userID)
+	if group == nil || group.DailyAILimit == 0 {
+		return true
+	}
+	if group.DailyAILimit < 0 {
+		return false
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var used int64
+	database.GetDB().Model(&models.File{}).
+		Where("user_id = ? AND created_at >= ? AND ai_tagging_status IN ?", userID, startOfDay,
+			[]string{common.AITaggingStatusPending, common.AITaggingStatusDone, common.AITaggingStatusFailed}).
+		Count(&used)
+	return used < int64(group.DailyAILimit)
+}
+
+func memberIDs(groupID uint) []uint {
+	var ids []uint
+	database.GetDB().Model(&models.User{}).Where("group_id = ?", groupID).Pluck("id", &ids)
+	return ids
+}
+
+func clearMembersCache(groupID uint) {
+	clearUsersCache(memberIDs(groupID))
+}
+
+func clearUsersCache(userIDs []uint) {
+	for _, id := range userIDs {
+		cache.Del(fmt.Sprintf(groupProfileCacheKey, id))
+	}
+}
@@ -0,0 +1,87 @@
+package vector
+
+import (
+	"fmt"
+	"io"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+)
+
+/* ExportVectors 导出全部向量及payload，用于与SQL数据库一同备份 */
+func ExportVectors(w io.Writer) (int, error) {
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return 0, fmt.Errorf("向量引擎未启用")
+	}
+
+	count, err := engine.ExportVectors(w)
+	if err != nil {
+		return count, err
+	}
+	logger.Info("向量导出完成: %d 条", count)
+	return count, nil
+}
+
+/* ImportVectors 从导出文件恢复向量，并将对应的向量记录标记为已完成 */
+func ImportVectors(r io.Reader) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("数据库连接不可用")
+	}
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return 0, fmt.Errorf("向量引擎未启用")
+	}
+
+	count, err := engine.ImportVectors(r, func(items []vector.VectorItem) {
+		fileIDs := make([]string, 0, len(items))
+		for _, item := range items {
+			fileIDs = append(fileIDs, item.FileID)
+		}
+
+		// 仅同步仍存在的文件，其余向量留给孤儿清理处理
+		var existing []string
+		db.Model(&models.File{}).Where("id IN ?", fileIDs).Pluck("id", &existing)
+		exists := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			exists[id] = true
+		}
+
+		for _, item := range items {
+			if !exists[item.FileID] {
+				continue
+			}
+			updates := map[string]interface{}{
+				"status":        common.VectorStatusCompleted,
+				"description":   item.Description,
+				"model":         item.Model,
+				"dimension":     len(item.Vector),
+				"error_message": "",
+				"retry_count":   0,
+			}
+			res := db.Model(&models.FileVector{}).Where("file_id = ?", item.FileID).Updates(updates)
+			if res.Error == nil && res.RowsAffected == 0 {
+				_ = db.Create(&models.FileVector{
+					FileID:      item.FileID,
+					Description: item.Description,
+					Model:       item.Model,
+					Dimension:   len(item.Vector),
+					Status:      common.VectorStatusCompleted,
+				}).Error
+			}
+		}
+	})
+	if count > 0 {
+		notifyVectorStatsChange()
+	}
+	if err != nil {
+		return count, err
+	}
+
+	logger.Info("向量导入完成: %d 条", count)
+	return count, nil
+}
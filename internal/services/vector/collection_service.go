@@ -0,0 +1,156 @@
+package vector
+
+import (
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+// GetVectorCollections 获取向量集合版本列表（新版本在前）
+func GetVectorCollections() ([]models.VectorCollection, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+
+	var collections []models.VectorCollection
+	if err := db.Order("backend ASC, version DESC").Find(&collections).Error; err != nil {
+		return nil, fmt.Errorf("查询向量集合版本失败: %v", err)
+	}
+	return collections, nil
+}
+
+// CheckVectorCollectionMigration 向量模型配置变化时自动发起集合迁移
+func CheckVectorCollectionMigration() {
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.NeedsCollectionMigration() {
+		return
+	}
+	if _, err := StartVectorCollectionMigration(); err != nil {
+		logger.Warn("发起向量集合迁移失败: %v", err)
+	}
+}
+
+// StartVectorCollectionMigration 创建新版本集合并在后台用新模型重新向量化，完成后切换搜索
+func StartVectorCollectionMigration() (*models.VectorCollection, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return nil, fmt.Errorf("向量引擎未启用")
+	}
+	if GetVectorReindexProgress().Status == ReindexStatusRunning {
+		return nil, fmt.Errorf("向量重建任务正在执行，请稍后再试")
+	}
+
+	record, err := engine.BeginCollectionMigration()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	db.Model(&models.FileAIInfo{}).Where("description != '' AND description IS NOT NULL").Count(&total)
+	db.Model(&models.VectorCollection{}).Where("id = ?", record.ID).Update("total", total)
+	record.Total = total
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("向量集合迁移任务 panic: %v", r)
+				engine.AbortCollectionMigration(fmt.Errorf("panic: %v", r))
+			}
+		}()
+		runVectorCollectionMigration(db, engine, record.ID)
+	}()
+
+	return record, nil
+}
+
+// runVectorCollectionMigration 按文件ID游标分批写入新集合，期间搜索仍使用旧集合
+func runVectorCollectionMigration(db *gorm.DB, engine *vector.VectorEngine, collectionID uint) {
+	var processed, failed int64
+	var failedIDs []string
+	lastFileID := ""
+	interval := time.Duration(defaultReindexIntervalMs) * time.Millisecond
+
+	for {
+		if building := engine.BuildingCollection(); building == nil || building.ID != collectionID {
+			return // 已被取消
+		}
+
+		var infos []models.FileAIInfo
+		if err := db.Select("file_id, description").
+			Where("description != '' AND description IS NOT NULL").
+			Where("file_id > ?", lastFileID).
+			Order("file_id ASC").
+			Limit(defaultReindexBatchSize).
+			Find(&infos).Error; err != nil {
+			engine.AbortCollectionMigration(fmt.Errorf("查询AI信息失败: %v", err))
+			return
+		}
+		if len(infos) == 0 {
+			break
+		}
+		lastFileID = infos[len(infos)-1].FileID
+
+		items := make([]vector.VectorItem, 0, len(infos))
+		for _, info := range infos {
+			items = append(items, vector.VectorItem{FileID: info.FileID, Description: info.Description})
+		}
+
+		if err := engine.MigrateCollectionItems(items); err != nil {
+			// 批量失败时逐个处理，定位具体失败的文件
+			for _, item := range items {
+				if err := engine.MigrateCollectionItems([]vector.VectorItem{item}); err != nil {
+					failed++
+					failedIDs = append(failedIDs, item.FileID)
+				}
+			}
+		}
+		processed += int64(len(items))
+
+		db.Model(&models.VectorCollection{}).Where("id = ?", collectionID).
+			Updates(map[string]interface{}{"processed": processed, "failed": failed})
+		time.Sleep(interval)
+	}
+
+	if processed > 0 && failed == processed {
+		engine.AbortCollectionMigration(fmt.Errorf("全部 %d 个文件向量化失败，请检查新模型配置", failed))
+		return
+	}
+
+	active, err := engine.CompleteCollectionMigration()
+	if err != nil {
+		logger.Error("切换向量集合失败: %v", err)
+		return
+	}
+
+	// 向量记录同步为新模型，迁移失败的文件交给常规队列重新处理
+	db.Model(&models.FileVector{}).Where("status = ?", common.VectorStatusCompleted).
+		Updates(map[string]interface{}{"model": active.Model, "dimension": active.Dimension})
+	for start := 0; start < len(failedIDs); start += 500 {
+		end := start + 500
+		if end > len(failedIDs) {
+			end = len(failedIDs)
+		}
+		db.Model(&models.FileVector{}).Where("file_id IN ?", failedIDs[start:end]).
+			Updates(map[string]interface{}{"status": common.VectorStatusPending, "retry_count": 0})
+	}
+
+	if svc := GetGlobalVectorQueueService(); svc != nil && failed > 0 {
+		if _, err := svc.EnqueueAllPending(1000); err != nil {
+			logger.Warn("迁移失败文件重新入队失败: %v", err)
+		}
+	}
+	notifyVectorStatsChange()
+	logger.Info("向量集合迁移完成: v%d 处理 %d，失败 %d", active.Version, processed, failed)
+}
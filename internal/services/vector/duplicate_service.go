@@ -0,0 +1,290 @@
+package vector
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/storage"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	duplicateScanBatchSize = 200
+	duplicateScanNeighbors = 10
+)
+
+var (
+	duplicateScanRunning int32
+	// lastDuplicateScanAt 上次扫描开始时间，之后只需扫描新增或更新过的向量；进程重启后首次为全量扫描
+	lastDuplicateScanAt time.Time
+)
+
+// DuplicateScanResult 近似重复扫描结果
+type DuplicateScanResult struct {
+	Scanned   int     `json:"scanned"`
+	Found     int     `json:"found"`
+	Threshold float64 `json:"threshold"`
+	Duration  string  `json:"duration"`
+}
+
+// DuplicateFileInfo 近似重复文件的展示信息
+type DuplicateFileInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// DuplicateCandidateItem 近似重复候选列表项
+type DuplicateCandidateItem struct {
+	models.VectorDuplicateCandidate
+	File          *DuplicateFileInfo `json:"file"`
+	DuplicateFile *DuplicateFileInfo `json:"duplicate_file"`
+}
+
+type duplicateScanRow struct {
+	FileID string
+	UserID uint
+}
+
+// ScanVectorDuplicates 基于已有向量扫描同一用户下的近似重复文件，结果写入候选表
+func ScanVectorDuplicates(full bool) (*DuplicateScanResult, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return nil, fmt.Errorf("向量引擎未启用")
+	}
+	if !atomic.CompareAndSwapInt32(&duplicateScanRunning, 0, 1) {
+		return nil, fmt.Errorf("近似重复扫描正在执行")
+	}
+	defer atomic.StoreInt32(&duplicateScanRunning, 0)
+
+	threshold := setting.GetFloatDirectFromDB("vector", "duplicate_threshold", 0.95)
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.95
+	}
+
+	startedAt := time.Now()
+	since := lastDuplicateScanAt
+	if full {
+		since = time.Time{}
+	}
+
+	result := &DuplicateScanResult{Threshold: threshold}
+	lastFileID := ""
+	for {
+		var rows []duplicateScanRow
+		query := db.Table("file_vector").
+			Select("file_vector.file_id, file.user_id").
+			Joins("JOIN file ON file.id = file_vector.file_id").
+			Where("file_vector.status = ? AND file_vector.deleted_at IS NULL", common.VectorStatusCompleted).
+			Where("file.status = ? AND file.is_duplicate = ? AND file.user_id > 0", "active", false).
+			Where("file_vector.file_id > ?", lastFileID)
+		if !since.IsZero() {
+			query = query.Where("file_vector.updated_at > ?", since)
+		}
+		if err := query.Order("file_vector.file_id ASC").Limit(duplicateScanBatchSize).Scan(&rows).Error; err != nil {
+			return result, fmt.Errorf("查询向量记录失败: %v", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		lastFileID = rows[len(rows)-1].FileID
+
+		for _, row := range rows {
+			matches, err := engine.SearchSimilarByFileID(row.FileID, duplicateScanNeighbors+1, row.UserID, float32(threshold))
+			if err != nil {
+				logger.Debug("近似重复扫描跳过文件 %s: %v", row.FileID, err)
+				continue
+			}
+			result.Scanned++
+			result.Found += saveDuplicateCandidates(db, row, matches)
+		}
+	}
+
+	lastDuplicateScanAt = startedAt
+	result.Duration = time.Since(startedAt).String()
+	logger.Info("近似重复扫描完成: 扫描 %d，新发现/更新 %d，阈值 %.2f", result.Scanned, result.Found, threshold)
+	return result, nil
+}
+
+// saveDuplicateCandidates 写入候选对（文件ID小的在前，保证同一对只存一条），已忽略的记录保持原状态
+func saveDuplicateCandidates(db *gorm.DB, row duplicateScanRow, matches []vector.VectorSearchResult) int {
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if m.FileID != row.FileID {
+			ids = append(ids, m.FileID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0
+	}
+
+	// 只保留同一用户下仍有效的非秒传文件（秒传副本与原文件共享内容，无需提示）
+	var valid []string
+	db.Model(&models.File{}).
+		Where("id IN ? AND user_id = ? AND status = ? AND is_duplicate = ?", ids, row.UserID, "active", false).
+		Pluck("id", &valid)
+	validSet := make(map[string]bool, len(valid))
+	for _, id := range valid {
+		validSet[id] = true
+	}
+
+	now := common.JSONTime(time.Now())
+	saved := 0
+	for _, m := range matches {
+		if !validSet[m.FileID] {
+			continue
+		}
+		a, b := row.FileID, m.FileID
+		if a > b {
+			a, b = b, a
+		}
+		candidate := models.VectorDuplicateCandidate{
+			UserID:          row.UserID,
+			FileID:          a,
+			DuplicateFileID: b,
+			Similarity:      float64(m.Similarity),
+			Status:          models.DuplicateStatusPending,
+			ScannedAt:       now,
+		}
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "file_id"}, {Name: "duplicate_file_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"similarity", "scanned_at", "updated_at"}),
+		}).Create(&candidate).Error; err != nil {
+			logger.Warn("保存近似重复记录失败: %s-%s %v", a, b, err)
+			continue
+		}
+		saved++
+	}
+	return saved
+}
+
+// ListVectorDuplicates 分页获取近似重复候选，userID 为 0 时返回全部用户的记录（管理员）
+func ListVectorDuplicates(userID uint, status string, page, limit int) (map[string]interface{}, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.DuplicateStatusPending
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+
+	query := db.Model(&models.VectorDuplicateCandidate{}).Where("status = ?", status)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("查询近似重复总数失败: %v", err)
+	}
+
+	var candidates []models.VectorDuplicateCandidate
+	if err := query.Order("similarity DESC, id ASC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("查询近似重复列表失败: %v", err)
+	}
+
+	fileIDs := make([]string, 0, len(candidates)*2)
+	for _, c := range candidates {
+		fileIDs = append(fileIDs, c.FileID, c.DuplicateFileID)
+	}
+	var files []models.File
+	if len(fileIDs) > 0 {
+		db.Where("id IN ?", fileIDs).Find(&files)
+	}
+	fileMap := make(map[string]*DuplicateFileInfo, len(files))
+	for _, f := range files {
+		fullURL, fullThumbURL, _ := storage.GetFullURLs(f)
+		fileMap[f.ID] = &DuplicateFileInfo{
+			ID:           f.ID,
+			Name:         f.DisplayName,
+			URL:          fullURL,
+			ThumbnailURL: fullThumbURL,
+			Size:         f.Size,
+			Width:        f.Width,
+			Height:       f.Height,
+			CreatedAt:    time.Time(f.CreatedAt).Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	items := make([]DuplicateCandidateItem, 0, len(candidates))
+	for _, c := range candidates {
+		items = append(items, DuplicateCandidateItem{
+			VectorDuplicateCandidate: c,
+			File:                     fileMap[c.FileID],
+			DuplicateFile:            fileMap[c.DuplicateFileID],
+		})
+	}
+
+	return map[string]interface{}{
+		"items": items,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}, nil
+}
+
+// GetVectorDuplicate 获取单条近似重复候选，userID 为 0 时不校验归属
+func GetVectorDuplicate(userID, id uint) (*models.VectorDuplicateCandidate, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	query := db.Where("id = ?", id)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	var candidate models.VectorDuplicateCandidate
+	if err := query.Take(&candidate).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("近似重复记录不存在")
+		}
+		return nil, fmt.Errorf("查询近似重复记录失败: %v", err)
+	}
+	return &candidate, nil
+}
+
+// UpdateVectorDuplicateStatus 更新候选状态（忽略/已处理）
+func UpdateVectorDuplicateStatus(userID, id uint, status string) error {
+	if status != models.DuplicateStatusIgnored && status != models.DuplicateStatusResolved && status != models.DuplicateStatusPending {
+		return fmt.Errorf("无效的状态: %s", status)
+	}
+	candidate, err := GetVectorDuplicate(userID, id)
+	if err != nil {
+		return err
+	}
+	db := database.GetDB()
+	if err := db.Model(candidate).Update("status", status).Error; err != nil {
+		return fmt.Errorf("更新近似重复记录失败: %v", err)
+	}
+	return nil
+}
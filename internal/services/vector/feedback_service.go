@@ -0,0 +1,275 @@
+package vector
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+
+	"gorm.io/gorm/clause"
+)
+
+const (
+	feedbackBucketWidth      = 0.05
+	feedbackTargetPrecision  = 0.8 // 建议阈值：相似度不低于该值的结果中相关比例达到目标
+	feedbackMinSuggestSample = 20  // 样本不足时不给出建议
+)
+
+// SearchFeedbackInput 相关性评价参数
+type SearchFeedbackInput struct {
+	Query      string
+	FileID     string
+	Relevant   bool
+	Similarity float64
+	Rank       int
+	Mode       string
+	Scope      string
+}
+
+// FeedbackBucket 相似度区间的评价统计
+type FeedbackBucket struct {
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Total     int64   `json:"total"`
+	Relevant  int64   `json:"relevant"`
+	Precision float64 `json:"precision"`
+}
+
+// FeedbackModeStats 按搜索模式统计
+type FeedbackModeStats struct {
+	Mode            string  `json:"mode"`
+	Total           int64   `json:"total"`
+	Relevant        int64   `json:"relevant"`
+	Precision       float64 `json:"precision"`
+	AvgRelevantRank float64 `json:"avg_relevant_rank"` // 相关结果的平均排名，越小说明排序越好
+}
+
+// SearchFeedbackStats 相关性评价汇总，供调整阈值和融合权重参考
+type SearchFeedbackStats struct {
+	Days               int                    `json:"days"`
+	Total              int64                  `json:"total"`
+	Relevant           int64                  `json:"relevant"`
+	Irrelevant         int64                  `json:"irrelevant"`
+	Buckets            []FeedbackBucket       `json:"buckets"`
+	Modes              []FeedbackModeStats    `json:"modes"`
+	CurrentSettings    map[string]interface{} `json:"current_settings"`
+	SuggestedThreshold *float64               `json:"suggested_threshold"` // 样本不足或无法达到目标精度时为空
+}
+
+type feedbackRow struct {
+	Similarity float64
+	Relevant   bool
+	Rank       int
+	Mode       string
+}
+
+// normalizeFeedbackQuery 忽略大小写及多余空白，同一查询的多次评价合并
+func normalizeFeedbackQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// SaveSearchFeedback 保存用户对搜索结果的相关性评价，重复评价覆盖旧值
+func SaveSearchFeedback(userID uint, input SearchFeedbackInput) (*models.SearchFeedback, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+
+	query := normalizeFeedbackQuery(input.Query)
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	var count int64
+	if err := db.Model(&models.File{}).Where("id = ?", input.FileID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("查询文件失败: %v", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("文件不存在")
+	}
+
+	if input.Mode == "" {
+		input.Mode = "vector"
+	}
+	if input.Scope == "" {
+		input.Scope = "user"
+	}
+
+	hash := md5.Sum([]byte(query))
+	feedback := models.SearchFeedback{
+		UserID:     userID,
+		QueryHash:  hex.EncodeToString(hash[:]),
+		Query:      query,
+		FileID:     input.FileID,
+		Relevant:   input.Relevant,
+		Similarity: input.Similarity,
+		Rank:       input.Rank,
+		Mode:       input.Mode,
+		Scope:      input.Scope,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "query_hash"}, {Name: "file_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"relevant", "similarity", "rank", "mode", "scope", "updated_at"}),
+	}).Create(&feedback).Error; err != nil {
+		return nil, fmt.Errorf("保存搜索评价失败: %v", err)
+	}
+	return &feedback, nil
+}
+
+// ListSearchFeedback 分页获取评价记录，relevant 为空时不过滤
+func ListSearchFeedback(relevant *bool, mode string, page, limit int) (map[string]interface{}, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+
+	query := db.Model(&models.SearchFeedback{})
+	if relevant != nil {
+		query = query.Where("relevant = ?", *relevant)
+	}
+	if mode != "" {
+		query = query.Where("mode = ?", mode)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("查询搜索评价总数失败: %v", err)
+	}
+
+	var items []models.SearchFeedback
+	if err := query.Order("updated_at DESC, id DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("查询搜索评价失败: %v", err)
+	}
+
+	return map[string]interface{}{
+		"items": items,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}, nil
+}
+
+// GetSearchFeedbackStats 统计最近 days 天的评价，按相似度区间给出精度并推算建议阈值
+func GetSearchFeedbackStats(days int) (*SearchFeedbackStats, error) {
+	if days <= 0 {
+		days = 30
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+
+	var rows []feedbackRow
+	if err := db.Model(&models.SearchFeedback{}).
+		Select("similarity, relevant, rank, mode").
+		Where("updated_at >= ?", time.Now().AddDate(0, 0, -days)).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询搜索评价失败: %v", err)
+	}
+
+	bucketCount := int(math.Round(1 / feedbackBucketWidth))
+	buckets := make([]FeedbackBucket, bucketCount)
+	for i := range buckets {
+		buckets[i].Min = math.Round(float64(i)*feedbackBucketWidth*100) / 100
+		buckets[i].Max = math.Round(float64(i+1)*feedbackBucketWidth*100) / 100
+	}
+
+	stats := &SearchFeedbackStats{Days: days}
+	modeMap := make(map[string]*FeedbackModeStats)
+	rankSum := make(map[string]int64)
+	var modeOrder []string
+
+	for _, row := range rows {
+		stats.Total++
+		if row.Relevant {
+			stats.Relevant++
+		} else {
+			stats.Irrelevant++
+		}
+
+		m, ok := modeMap[row.Mode]
+		if !ok {
+			m = &FeedbackModeStats{Mode: row.Mode}
+			modeMap[row.Mode] = m
+			modeOrder = append(modeOrder, row.Mode)
+		}
+		m.Total++
+		if row.Relevant {
+			m.Relevant++
+			rankSum[row.Mode] += int64(row.Rank)
+		}
+
+		// 关键词命中的结果没有相似度，不参与阈值统计
+		if row.Similarity <= 0 {
+			continue
+		}
+		idx := int(row.Similarity / feedbackBucketWidth)
+		if idx >= bucketCount {
+			idx = bucketCount - 1
+		}
+		buckets[idx].Total++
+		if row.Relevant {
+			buckets[idx].Relevant++
+		}
+	}
+
+	for i := range buckets {
+		if buckets[i].Total > 0 {
+			buckets[i].Precision = float64(buckets[i].Relevant) / float64(buckets[i].Total)
+		}
+	}
+	stats.Buckets = buckets
+
+	for _, mode := range modeOrder {
+		m := modeMap[mode]
+		if m.Total > 0 {
+			m.Precision = float64(m.Relevant) / float64(m.Total)
+		}
+		if m.Relevant > 0 {
+			m.AvgRelevantRank = float64(rankSum[mode]) / float64(m.Relevant)
+		}
+		stats.Modes = append(stats.Modes, *m)
+	}
+
+	// 从高相似度向下累计，取累计精度仍达标的最低区间下限作为建议阈值
+	var cumTotal, cumRelevant int64
+	for i := bucketCount - 1; i >= 0; i-- {
+		cumTotal += buckets[i].Total
+		cumRelevant += buckets[i].Relevant
+		if cumTotal < feedbackMinSuggestSample || buckets[i].Total == 0 {
+			continue
+		}
+		if float64(cumRelevant)/float64(cumTotal) >= feedbackTargetPrecision {
+			threshold := buckets[i].Min
+			stats.SuggestedThreshold = &threshold
+		}
+	}
+
+	stats.CurrentSettings = map[string]interface{}{
+		"vector_search_threshold":     setting.GetFloatDirectFromDB("vector", "vector_search_threshold", 0.3),
+		"vector_similarity_threshold": setting.GetFloatDirectFromDB("vector", "vector_similarity_threshold", 0.7),
+		"hybrid_vector_weight":        setting.GetFloatDirectFromDB("vector", "hybrid_vector_weight", 1.0),
+		"hybrid_keyword_weight":       setting.GetFloatDirectFromDB("vector", "hybrid_keyword_weight", 1.0),
+		"hybrid_rrf_k":                setting.GetIntDirectFromDB("vector", "hybrid_rrf_k", 60),
+	}
+	return stats, nil
+}
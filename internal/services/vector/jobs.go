@@ -0,0 +1,72 @@
+package vector
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/folder"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+)
+
+func init() {
+	qqueue.Register(&qqueue.Descriptor{
+		Kind:  qqueue.KindVector,
+		Title: "向量生成",
+		Queue: func() qqueue.Queue {
+			if svc := GetGlobalVectorQueueService(); svc != nil {
+				return svc.queue
+			}
+			return nil
+		},
+		Policy: vectorRetryPolicy,
+		Paused: func() bool {
+			svc := GetGlobalVectorQueueService()
+			return svc != nil && svc.IsPaused()
+		},
+		Requeue: requeueDeadVectorJobs,
+	})
+}
+
+// vectorRetryPolicy 向量任务重试策略，在设置分组 jobs 中按 vector_ 前缀调整
+func vectorRetryPolicy() qqueue.RetryPolicy {
+	return qqueue.RetryPolicy{
+		MaxAttempts: setting.GetInt("jobs", "vector_max_attempts", 3),
+		BaseDelay:   time.Duration(setting.GetInt("jobs", "vector_retry_base_seconds", 3)) * time.Second,
+		MaxDelay:    time.Duration(setting.GetInt("jobs", "vector_retry_max_seconds", 60)) * time.Second,
+	}.Normalize()
+}
+
+// requeueDeadVectorJobs 重置向量记录的重试次数后以高优先级重新入队，已关闭AI处理的文件不再入队
+func requeueDeadVectorJobs(fileIDs []string) (int, error) {
+	svc := GetGlobalVectorQueueService()
+	if svc == nil || svc.queue == nil {
+		return 0, errors.New(errors.CodeServiceUnavailable, "向量队列未初始化")
+	}
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New(errors.CodeDBConnectionFailed, "无法获取数据库连接")
+	}
+
+	fileIDs = folder.FilterAIProcessingAllowed(fileIDs)
+	if len(fileIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := db.Model(&models.FileVector{}).Where("file_id IN ?", fileIDs).Updates(map[string]interface{}{
+		"status":        common.VectorStatusPending,
+		"retry_count":   0,
+		"error_message": "",
+	}).Error; err != nil {
+		return 0, errors.Wrap(err, errors.CodeDBUpdateFailed, "重置向量状态失败")
+	}
+
+	count := svc.RequeueVectors(fileIDs)
+	if count > 0 {
+		svc.pushWS()
+	}
+	return count, nil
+}
@@ -0,0 +1,98 @@
+package vector
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+)
+
+const payloadSyncBatchSize = 200
+
+var payloadSyncRunning int32
+
+// VectorPayloadSyncResult 元数据回填结果
+type VectorPayloadSyncResult struct {
+	Synced   int    `json:"synced"`
+	Failed   int    `json:"failed"`
+	Duration string `json:"duration"`
+}
+
+// CheckVectorPayloadSync 当前集合存在向量元数据低于当前版本时，在后台回填
+func CheckVectorPayloadSync() {
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return
+	}
+	if active := engine.ActiveCollection(); active == nil || active.PayloadVersion >= vector.VectorPayloadVersion {
+		return
+	}
+	if _, err := SyncVectorPayload(); err != nil {
+		logger.Warn("回填向量元数据失败: %v", err)
+	}
+}
+
+// SyncVectorPayload 为已完成的向量回填文件元数据，全部成功后搜索过滤改为在存储层执行
+func SyncVectorPayload() (*VectorPayloadSyncResult, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return nil, fmt.Errorf("向量引擎未启用")
+	}
+	if !atomic.CompareAndSwapInt32(&payloadSyncRunning, 0, 1) {
+		return nil, fmt.Errorf("向量元数据回填正在执行")
+	}
+	defer atomic.StoreInt32(&payloadSyncRunning, 0)
+
+	start := time.Now()
+	result := &VectorPayloadSyncResult{}
+	lastFileID := ""
+
+	for {
+		var fileIDs []string
+		if err := db.Model(&models.FileVector{}).
+			Where("status = ? AND file_id > ?", common.VectorStatusCompleted, lastFileID).
+			Order("file_id ASC").
+			Limit(payloadSyncBatchSize).
+			Pluck("file_id", &fileIDs).Error; err != nil {
+			return nil, fmt.Errorf("查询向量记录失败: %v", err)
+		}
+		if len(fileIDs) == 0 {
+			break
+		}
+		lastFileID = fileIDs[len(fileIDs)-1]
+
+		if err := engine.SyncFileMeta(fileIDs); err != nil {
+			// 批量失败时逐个重试，统计具体失败数量
+			for _, fileID := range fileIDs {
+				if err := engine.SyncFileMeta([]string{fileID}); err != nil {
+					result.Failed++
+					continue
+				}
+				result.Synced++
+			}
+			continue
+		}
+		result.Synced += len(fileIDs)
+	}
+
+	result.Duration = time.Since(start).String()
+	if result.Failed > 0 {
+		logger.Warn("向量元数据回填完成: 成功 %d，失败 %d，搜索继续使用结果过滤", result.Synced, result.Failed)
+		return result, nil
+	}
+
+	if err := engine.MarkPayloadSynced(); err != nil {
+		return result, fmt.Errorf("更新集合回填状态失败: %v", err)
+	}
+	logger.Info("向量元数据回填完成: %d 个向量，耗时 %s", result.Synced, result.Duration)
+	return result, nil
+}
@@ -0,0 +1,284 @@
+package vector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/vector"
+
+	"gorm.io/gorm"
+)
+
+// 重建索引任务状态
+const (
+	ReindexStatusIdle      = "idle"
+	ReindexStatusRunning   = "running"
+	ReindexStatusCompleted = "completed"
+	ReindexStatusFailed    = "failed"
+)
+
+const (
+	defaultReindexBatchSize  = 20
+	maxReindexBatchSize      = 100
+	defaultReindexIntervalMs = 500
+
+	reindexLockName = "vector:reindex"
+	reindexLockTTL  = 2 * time.Minute // 执行期间自动续期
+)
+
+// ReindexProgress 向量重建进度
+type ReindexProgress struct {
+	TaskID     string     `json:"task_id"`
+	Status     string     `json:"status"`
+	Model      string     `json:"model"`
+	Dimension  int        `json:"dimension"`
+	Total      int64      `json:"total"`
+	Processed  int64      `json:"processed"`
+	Succeeded  int64      `json:"succeeded"`
+	Failed     int64      `json:"failed"`
+	BatchSize  int        `json:"batch_size"`
+	IntervalMs int        `json:"interval_ms"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+var (
+	reindexMu       sync.Mutex
+	reindexProgress = ReindexProgress{Status: ReindexStatusIdle}
+)
+
+// GetVectorReindexProgress 获取当前（或最近一次）重建任务进度
+func GetVectorReindexProgress() ReindexProgress {
+	reindexMu.Lock()
+	defer reindexMu.Unlock()
+	return reindexProgress
+}
+
+func updateReindexProgress(fn func(p *ReindexProgress)) {
+	reindexMu.Lock()
+	fn(&reindexProgress)
+	reindexMu.Unlock()
+}
+
+// StartVectorReindex 删除并重建向量集合，后台按批次限速重新生成全部向量
+func StartVectorReindex(batchSize, intervalMs int) (ReindexProgress, error) {
+	done, err := prepareVectorReindex(batchSize, intervalMs)
+	if err != nil {
+		return ReindexProgress{}, err
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("向量重建任务 panic: %v", r)
+				finishVectorReindex(fmt.Errorf("panic: %v", r))
+			}
+		}()
+		done()
+	}()
+	return GetVectorReindexProgress(), nil
+}
+
+// RunVectorReindex 同步执行向量重建（命令行使用）
+func RunVectorReindex(batchSize, intervalMs int) (ReindexProgress, error) {
+	done, err := prepareVectorReindex(batchSize, intervalMs)
+	if err != nil {
+		return ReindexProgress{}, err
+	}
+	done()
+	return GetVectorReindexProgress(), nil
+}
+
+// prepareVectorReindex 校验状态并重建集合，返回实际执行重建的函数
+func prepareVectorReindex(batchSize, intervalMs int) (func(), error) {
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatchSize
+	}
+	if batchSize > maxReindexBatchSize {
+		batchSize = maxReindexBatchSize
+	}
+	if intervalMs <= 0 {
+		intervalMs = defaultReindexIntervalMs
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	engine := vector.GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return nil, fmt.Errorf("向量引擎未启用")
+	}
+
+	// 多实例部署时同一时间只允许一个实例重建
+	lock, ok := cache.TryLock(reindexLockName, reindexLockTTL)
+	if !ok {
+		return nil, fmt.Errorf("其他实例正在执行向量重建")
+	}
+
+	reindexMu.Lock()
+	if reindexProgress.Status == ReindexStatusRunning {
+		reindexMu.Unlock()
+		lock.Unlock()
+		return nil, fmt.Errorf("已有向量重建任务正在执行")
+	}
+	now := time.Now()
+	reindexProgress = ReindexProgress{
+		TaskID:     fmt.Sprintf("reindex-%d", now.Unix()),
+		Status:     ReindexStatusRunning,
+		BatchSize:  batchSize,
+		IntervalMs: intervalMs,
+		StartedAt:  &now,
+	}
+	reindexMu.Unlock()
+
+	// 重建期间暂停常规队列，避免与重建任务并发写入
+	queueSvc := GetGlobalVectorQueueService()
+	wasPaused := true
+	if queueSvc != nil {
+		wasPaused = queueSvc.IsPaused()
+		queueSvc.SetPaused(true)
+	}
+	restoreQueue := func() {
+		if queueSvc != nil && !wasPaused {
+			queueSvc.SetPaused(false)
+		}
+		lock.Unlock()
+	}
+
+	dimension, err := engine.RecreateCollection()
+	if err != nil {
+		restoreQueue()
+		finishVectorReindex(err)
+		return nil, err
+	}
+
+	var total int64
+	db.Model(&models.FileAIInfo{}).Where("description != '' AND description IS NOT NULL").Count(&total)
+	updateReindexProgress(func(p *ReindexProgress) {
+		p.Model = engine.GetCurrentModel()
+		p.Dimension = dimension
+		p.Total = total
+	})
+
+	// 集合已清空，所有向量记录回到待处理状态
+	if err := db.Model(&models.FileVector{}).Where("1 = 1").Updates(map[string]interface{}{
+		"status":        common.VectorStatusPending,
+		"error_message": "",
+		"retry_count":   0,
+	}).Error; err != nil {
+		logger.Warn("重置向量记录状态失败: %v", err)
+	}
+
+	logger.Info("开始重建向量索引: task=%s total=%d dimension=%d", GetVectorReindexProgress().TaskID, total, dimension)
+
+	return func() {
+		defer restoreQueue()
+		finishVectorReindex(runVectorReindexBatches(db, engine, batchSize, time.Duration(intervalMs)*time.Millisecond))
+	}, nil
+}
+
+// runVectorReindexBatches 按文件ID游标分批重新生成向量
+func runVectorReindexBatches(db *gorm.DB, engine *vector.VectorEngine, batchSize int, interval time.Duration) error {
+	lastFileID := ""
+	for {
+		var infos []models.FileAIInfo
+		if err := db.Select("file_id, description").
+			Where("description != '' AND description IS NOT NULL").
+			Where("file_id > ?", lastFileID).
+			Order("file_id ASC").
+			Limit(batchSize).
+			Find(&infos).Error; err != nil {
+			return fmt.Errorf("查询AI信息失败: %v", err)
+		}
+		if len(infos) == 0 {
+			return nil
+		}
+		lastFileID = infos[len(infos)-1].FileID
+
+		items := make([]vector.VectorItem, 0, len(infos))
+		for _, info := range infos {
+			items = append(items, vector.VectorItem{FileID: info.FileID, Description: info.Description})
+		}
+
+		model := engine.GetCurrentModel()
+		if err := engine.BatchProcessFiles(items); err == nil {
+			for _, item := range items {
+				markReindexResult(db, item, model, nil)
+			}
+		} else {
+			// 批量失败时逐个处理，定位具体失败的文件
+			for _, item := range items {
+				markReindexResult(db, item, model, engine.ProcessFile(item.FileID, item.Description))
+			}
+		}
+
+		notifyVectorStatsChange()
+		if interval > 0 {
+			time.Sleep(interval)
+		}
+	}
+}
+
+// markReindexResult 更新单个文件的向量记录并累计进度
+func markReindexResult(db *gorm.DB, item vector.VectorItem, model string, procErr error) {
+	status := common.VectorStatusCompleted
+	errMsg := ""
+	if procErr != nil {
+		status = common.VectorStatusFailed
+		errMsg = procErr.Error()
+	}
+
+	updates := map[string]interface{}{
+		"status":        status,
+		"description":   item.Description,
+		"model":         model,
+		"error_message": errMsg,
+		"retry_count":   0,
+	}
+	res := db.Model(&models.FileVector{}).Where("file_id = ?", item.FileID).Updates(updates)
+	if res.Error == nil && res.RowsAffected == 0 {
+		_ = db.Create(&models.FileVector{
+			FileID:       item.FileID,
+			Description:  item.Description,
+			Model:        model,
+			Status:       status,
+			ErrorMessage: errMsg,
+		}).Error
+	}
+
+	updateReindexProgress(func(p *ReindexProgress) {
+		p.Processed++
+		if procErr != nil {
+			p.Failed++
+		} else {
+			p.Succeeded++
+		}
+	})
+}
+
+func finishVectorReindex(err error) {
+	now := time.Now()
+	updateReindexProgress(func(p *ReindexProgress) {
+		p.FinishedAt = &now
+		if err != nil {
+			p.Status = ReindexStatusFailed
+			p.Error = err.Error()
+			return
+		}
+		p.Status = ReindexStatusCompleted
+	})
+
+	progress := GetVectorReindexProgress()
+	if err != nil {
+		logger.Error("向量重建失败: task=%s err=%v", progress.TaskID, err)
+		return
+	}
+	logger.Info("向量重建完成: task=%s 成功=%d 失败=%d", progress.TaskID, progress.Succeeded, progress.Failed)
+}
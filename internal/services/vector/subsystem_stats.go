@@ -0,0 +1,120 @@
+package vector
+
+import (
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/vector"
+)
+
+// 最早排队任务超过该时长视为处理跟不上
+const vectorBacklogLagThreshold = 10 * time.Minute
+
+// VectorJobBacklog VectorJob 队列积压情况
+type VectorJobBacklog struct {
+	Queued          int64      `json:"queued"`
+	Processing      int64      `json:"processing"`
+	Failed          int64      `json:"failed"`
+	OldestQueuedAt  *time.Time `json:"oldest_queued_at,omitempty"`
+	OldestQueuedAge int64      `json:"oldest_queued_age_seconds"`
+}
+
+// VectorLastError 最近一次向量化或队列错误
+type VectorLastError struct {
+	Source  string    `json:"source"` // embedding/job
+	FileID  string    `json:"file_id,omitempty"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// VectorSubsystemStats 向量子系统运行状况，用于判断向量化是否跟得上
+type VectorSubsystemStats struct {
+	Enabled           bool                        `json:"enabled"`
+	Backend           string                      `json:"backend"`
+	Collection        *models.VectorCollection    `json:"collection,omitempty"`
+	CollectionSize    int64                       `json:"collection_size"`
+	StorageSizeBytes  int64                       `json:"storage_size_bytes"`
+	StorageError      string                      `json:"storage_error,omitempty"`
+	Backlog           VectorJobBacklog            `json:"backlog"`
+	LastError         *VectorLastError            `json:"last_error,omitempty"`
+	AvgEmbedLatencyMs float64                     `json:"avg_embed_latency_ms"`
+	Embedding         vector.VectorEngineMetrics  `json:"embedding"`
+	BuiltinQdrant     *vector.BuiltinQdrantStatus `json:"builtin_qdrant,omitempty"`
+	KeepingUp         bool                        `json:"keeping_up"`
+}
+
+// GetVectorSubsystemStats 汇总集合大小、队列积压、最近错误、向量化耗时及存储占用
+func GetVectorSubsystemStats() *VectorSubsystemStats {
+	stats := &VectorSubsystemStats{
+		Backend: setting.GetStringDirectFromDB("vector", "vector_backend", vector.VectorBackendQdrant),
+	}
+
+	engine := vector.GetGlobalVectorEngine()
+	if engine != nil && engine.IsEnabled() {
+		stats.Enabled = true
+		stats.Collection = engine.ActiveCollection()
+		if storageStats, err := engine.GetStorageStats(); err != nil {
+			stats.StorageError = err.Error()
+		} else if storageStats != nil {
+			stats.CollectionSize = storageStats.TotalVectors
+			stats.StorageSizeBytes = storageStats.StorageSize
+		}
+	}
+
+	if builtin := vector.GetBuiltinQdrantStatus(); builtin.Enabled {
+		stats.BuiltinQdrant = &builtin
+	}
+
+	stats.Embedding = vector.GetVectorEngineMetrics()
+	stats.AvgEmbedLatencyMs = stats.Embedding.AvgEmbedLatencyMs
+	if stats.Embedding.LastError != "" {
+		stats.LastError = &VectorLastError{
+			Source:  "embedding",
+			Message: stats.Embedding.LastError,
+			At:      stats.Embedding.LastErrorAt,
+		}
+	}
+
+	if db := database.GetDB(); db != nil {
+		var counts []struct {
+			Status string
+			Count  int64
+		}
+		db.Model(&models.VectorJob{}).Select("status, COUNT(*) AS count").Group("status").Scan(&counts)
+		for _, c := range counts {
+			switch c.Status {
+			case "queued":
+				stats.Backlog.Queued = c.Count
+			case "processing":
+				stats.Backlog.Processing = c.Count
+			case "failed":
+				stats.Backlog.Failed = c.Count
+			}
+		}
+
+		var oldest models.VectorJob
+		if err := db.Where("status = ?", "queued").Order("created_at ASC").Limit(1).Find(&oldest).Error; err == nil && oldest.ID > 0 {
+			stats.Backlog.OldestQueuedAt = &oldest.CreatedAt
+			stats.Backlog.OldestQueuedAge = int64(time.Since(oldest.CreatedAt).Seconds())
+		}
+
+		// 队列记录的错误比进程内的更新时以队列为准
+		var lastFailed models.VectorJob
+		if err := db.Where("last_error <> ''").Order("updated_at DESC").Limit(1).Find(&lastFailed).Error; err == nil && lastFailed.ID > 0 {
+			if stats.LastError == nil || lastFailed.UpdatedAt.After(stats.LastError.At) {
+				stats.LastError = &VectorLastError{
+					Source:  "job",
+					FileID:  lastFailed.FileID,
+					Message: lastFailed.LastError,
+					At:      lastFailed.UpdatedAt,
+				}
+			}
+		}
+	}
+
+	stats.KeepingUp = stats.Backlog.OldestQueuedAt == nil ||
+		time.Duration(stats.Backlog.OldestQueuedAge)*time.Second < vectorBacklogLagThreshold
+	return stats
+}
@@ -10,6 +10,7 @@ import (
 	metrics "pixelpunk/internal/metrics"
 	"pixelpunk/internal/models"
 	qqueue "pixelpunk/internal/queue"
+	"pixelpunk/internal/services/folder"
 	"pixelpunk/internal/services/setting"
 	ws "pixelpunk/internal/websocket"
 	"pixelpunk/pkg/cache"
@@ -30,6 +31,8 @@ type VectorQueueService struct {
 	cancel        context.CancelFunc
 	activeWorkers int
 	reaperStop    chan struct{}
+	tracker       *qqueue.Tracker // 已取出未确认的任务，关闭超时时放回队列
+	wg            sync.WaitGroup
 }
 
 var globalVectorQueueService *VectorQueueService
@@ -57,11 +60,8 @@ func RegisterVectorConfigHooks() {
 
 		eng := vector.GetGlobalVectorEngine()
 		if eng == nil {
-			// 从数据库读取Qdrant配置并初始化引擎
-			qdrantURL := setting.GetStringDirectFromDB("vector", "qdrant_url", "http://localhost:6333")
-			qdrantTimeout := setting.GetIntDirectFromDB("vector", "qdrant_timeout", 30)
-
-			if err := vector.InitQdrantVectorEngine(qdrantURL, qdrantTimeout); err != nil {
+			// 按配置的向量后端（Qdrant / pgvector）初始化引擎
+			if err := vector.InitConfiguredVectorEngine(); err != nil {
 				logger.Error("[向量服务] 初始化向量引擎失败: %v", err)
 				return
 			}
@@ -78,6 +78,9 @@ func RegisterVectorConfigHooks() {
 			return
 		}
 
+		// 向量模型变化时在新版本集合中后台重建，避免新旧模型向量混用
+		go CheckVectorCollectionMigration()
+
 		if globalVectorQueueService == nil {
 			if err := InitGlobalVectorQueue(); err != nil {
 				logger.Error("[向量服务] 初始化队列失败: %v", err)
@@ -100,7 +103,7 @@ func RegisterVectorConfigHooks() {
 		}
 	}
 
-	criticalKeys := []string{"vector_enabled", "vector_api_key", "vector_base_url", "vector_model", "qdrant_url"}
+	criticalKeys := []string{"vector_enabled", "vector_provider", "ollama_base_url", "ollama_model", "vector_api_key", "vector_base_url", "vector_model", "qdrant_url", "qdrant_api_key", "qdrant_ca_cert", "qdrant_tls_skip_verify", "vector_backend", "pgvector_dsn", "weaviate_url", "weaviate_api_key"}
 	for _, key := range criticalKeys {
 		setting.RegisterSettingChangeHandler("vector", key, func(value string) {
 			handleVectorConfigChange()
@@ -136,7 +139,7 @@ func InitGlobalVectorQueue() error {
 	concurrency := setting.GetIntDirectFromDB("vector", "vector_concurrency", 3)
 	autoProcessingEnabled := setting.GetBoolDirectFromDB("vector", "vector_auto_processing_enabled", true)
 	paused := !autoProcessingEnabled
-	svc := &VectorQueueService{paused: paused, concurrent: concurrency, ctx: ctx, cancel: cancel, reaperStop: make(chan struct{})}
+	svc := &VectorQueueService{paused: paused, concurrent: concurrency, ctx: ctx, cancel: cancel, reaperStop: make(chan struct{}), tracker: qqueue.NewTracker(qqueue.KindVector)}
 
 	if cache.IsRedisEnabled() {
 		if rq := qqueue.NewRedisQueue(); rq != nil {
@@ -149,6 +152,7 @@ func InitGlobalVectorQueue() error {
 	}
 
 	for i := 0; i < svc.concurrent; i++ {
+		svc.wg.Add(1)
 		go svc.worker(i + 1)
 	}
 	globalVectorQueueService = svc
@@ -164,8 +168,9 @@ func InitGlobalVectorQueue() error {
 }
 
 func (s *VectorQueueService) worker(id int) {
+	defer s.wg.Done()
 	db := database.GetDB()
-	for {
+	for s.ctx.Err() == nil {
 		if s.queue == nil {
 			time.Sleep(1 * time.Second)
 			continue
@@ -181,6 +186,7 @@ func (s *VectorQueueService) worker(id int) {
 			time.Sleep(100 * time.Millisecond)
 			continue
 		}
+		ack, nack = s.tracker.Wrap(task.FileID, ack, nack)
 		if s.paused {
 			_ = nack(1*time.Second, false, "paused")
 			s.activeWorkers--
@@ -193,6 +199,44 @@ func (s *VectorQueueService) worker(id int) {
 	}
 }
 
+// Shutdown 停止取新任务并等待进行中的任务完成，ctx 到期时将未完成的任务放回队列，并把对应向量记录恢复为待处理
+func (s *VectorQueueService) Shutdown(ctx context.Context) {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("向量队列已停止")
+	case <-ctx.Done():
+		fileIDs := s.tracker.Checkpoint("service shutdown")
+		if len(fileIDs) > 0 {
+			if db := database.GetDB(); db != nil {
+				_ = db.Model(&models.FileVector{}).
+					Where("file_id IN ? AND status = ?", fileIDs, common.VectorStatusProcessing).
+					Update("status", common.VectorStatusPending).Error
+			}
+		}
+		logger.Warn("向量队列未在关闭时限内完成，已将 %d 个任务放回队列", len(fileIDs))
+	}
+
+	if s.reaperStop != nil {
+		close(s.reaperStop)
+		s.reaperStop = nil
+	}
+}
+
+/* ShutdownVectorQueue 关闭全局向量队列，应用退出时调用 */
+func ShutdownVectorQueue(ctx context.Context) {
+	if svc := globalVectorQueueService; svc != nil {
+		svc.Shutdown(ctx)
+	}
+}
+
 func (s *VectorQueueService) processVectorTask(task *qqueue.TaggingTask, ack qqueue.AckFunc, nack qqueue.NackFunc, db *gorm.DB) {
 	defer s.pushWS()
 
@@ -212,6 +256,15 @@ func (s *VectorQueueService) processVectorTask(task *qqueue.TaggingTask, ack qqu
 		return
 	}
 
+	// 入队后文件可能被移入关闭AI处理的文件夹，或用户关闭了AI处理
+	var file models.File
+	if err := db.Select("id, user_id, folder_id").Where("id = ?", ai.FileID).Take(&file).Error; err == nil &&
+		!folder.IsAIProcessingAllowed(file.UserID, file.FolderID) {
+		folder.MarkAIProcessingSkipped([]string{ai.FileID})
+		_ = ack()
+		return
+	}
+
 	_ = db.Model(&models.FileVector{}).Where("file_id = ?", ai.FileID).Updates(map[string]interface{}{
 		"status":        common.VectorStatusProcessing,
 		"error_message": "",
@@ -254,7 +307,7 @@ func (s *VectorQueueService) processVectorTask(task *qqueue.TaggingTask, ack qqu
 				"retry_count":   0,
 			}).Error
 			logger.Error("向量生成遇到致命错误（不可重试）: file_id=%s, err=%v", ai.FileID, errProc)
-			_ = ack()
+			_ = nack(0, true, errorMsg)
 			return
 		}
 
@@ -268,27 +321,21 @@ func (s *VectorQueueService) processVectorTask(task *qqueue.TaggingTask, ack qqu
 			"last_retry_at": &now,
 		}).Error
 
-		maxRetries := 3
-		if currentRetries >= maxRetries {
+		policy := vectorRetryPolicy()
+		if policy.Exhausted(currentRetries) {
 			_ = db.Model(&models.FileVector{}).Where("file_id = ?", ai.FileID).Updates(map[string]interface{}{
 				"status":        common.VectorStatusFailed,
 				"error_message": errorMsg,
-				"retry_count":   maxRetries,
+				"retry_count":   currentRetries,
 			}).Error
-			logger.Error("向量生成失败（重试次数已达上限%d次）: file_id=%s, err=%v", maxRetries, ai.FileID, errProc)
-			_ = ack()
+			logger.Error("向量生成失败（重试次数已达上限%d次）: file_id=%s, err=%v", policy.MaxAttempts, ai.FileID, errProc)
+			_ = nack(0, true, errorMsg)
 			return
 		}
 
-		var delay time.Duration = 1 * time.Second
-		if currentRetries >= 2 {
-			delay = 5 * time.Second
-		} else if currentRetries >= 1 {
-			delay = 3 * time.Second
-		}
-
+		delay := policy.Delay(currentRetries)
 		logger.Warn("向量生成重试: file_id=%s, 重试次数=%d/%d, 延迟=%v, err=%v",
-			ai.FileID, currentRetries, maxRetries, delay, errProc)
+			ai.FileID, currentRetries, policy.MaxAttempts, delay, errProc)
 		_ = nack(delay, false, errorMsg)
 		metrics.IncVectorNack()
 
@@ -353,6 +400,14 @@ func (s *VectorQueueService) EnqueueVector(fileID string) error {
 	return s.queue.EnqueueUnique(fileID, 0)
 }
 
+/* RequeueVectors 后台手动重试时使用，进入死信的任务移出死信后以高优先级重新入队，返回入队的数量 */
+func (s *VectorQueueService) RequeueVectors(fileIDs []string) int {
+	if s == nil || s.queue == nil {
+		return 0
+	}
+	return qqueue.RequeueDeadIDs(s.queue, fileIDs, qqueue.PriorityHigh)
+}
+
 func (s *VectorQueueService) EnqueueAllPending(batch int) (int, error) {
 	if s.paused {
 		return 0, nil
@@ -372,6 +427,7 @@ func (s *VectorQueueService) EnqueueAllPending(batch int) (int, error) {
 	// 清理vector_job表中的旧记录，以便重新入队
 	_ = db.Where("file_id IN ? AND status IN ?", ids, []string{"done", "failed"}).Delete(&models.VectorJob{}).Error
 
+	ids = folder.FilterAIProcessingAllowed(ids)
 	enq := 0
 	for _, id := range ids {
 		if s.queue.EnqueueUnique(id, 0) == nil {
@@ -404,6 +460,7 @@ func (s *VectorQueueService) UpdateConcurrency(n int) {
 	if n > s.concurrent {
 		add := n - s.concurrent
 		for i := 0; i < add; i++ {
+			s.wg.Add(1)
 			go s.worker(s.concurrent + i + 1)
 		}
 	}
@@ -521,8 +578,9 @@ func (s *VectorQueueService) ReconcileMissing(limit int, dryRun bool) (int, int,
 		_ = db.Where("file_id IN ? AND status IN ?", ids, []string{"done", "failed"}).Delete(&models.VectorJob{}).Error
 	}
 
+	allowed := folder.FilterAIProcessingAllowed(ids)
 	enq := 0
-	for _, id := range ids {
+	for _, id := range allowed {
 		if s.EnqueueVector(id) == nil {
 			enq++
 		}
@@ -608,6 +666,7 @@ func (s *VectorQueueService) RebuildStale(limit int) (int, error) {
 		_ = db.Where("file_id IN ? AND status IN ?", ids, []string{"done", "failed"}).Delete(&models.VectorJob{}).Error
 	}
 
+	ids = folder.FilterAIProcessingAllowed(ids)
 	enq := 0
 	for _, id := range ids {
 		if s.EnqueueVector(id) == nil {
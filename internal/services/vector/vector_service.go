@@ -248,7 +248,7 @@ func batchRetryVectors(db *gorm.DB, fileIDs []string) error {
 			continue
 		}
 
-		if svc := GetGlobalVectorQueueService(); svc != nil && svc.EnqueueVector(vectorRecord.FileID) == nil {
+		if svc := GetGlobalVectorQueueService(); svc != nil && svc.RequeueVectors([]string{vectorRecord.FileID}) > 0 {
 			pushedCount++
 		} else {
 			logger.Warn("重试任务推送失败: %s", vectorRecord.FileID)
@@ -318,6 +318,11 @@ func RetryVector(fileID string) error {
 	logVectorProcessing(fileID, models.VectorLogActionRetry, "vector.retry",
 		map[string]interface{}{}, vector.Model, 0, "", "")
 
+	// 进入死信的任务不会被补偿扫描重新入队，手动重试时直接入队
+	if svc := GetGlobalVectorQueueService(); svc != nil {
+		svc.RequeueVectors([]string{fileID})
+	}
+
 	notifyVectorStatsChange()
 
 	return nil
@@ -453,6 +458,7 @@ func RetryAllFailedVectors() error {
 
 	successCount := 0
 	taskID := fmt.Sprintf("retry-all-%d", time.Now().Unix())
+	retriedIDs := make([]string, 0, len(failedVectors))
 
 	for _, vector := range failedVectors {
 		updateData := map[string]interface{}{
@@ -482,10 +488,15 @@ func RetryAllFailedVectors() error {
 			logger.Error("记录重试日志失败: %s, 错误: %v", vector.FileID, err)
 		}
 
+		retriedIDs = append(retriedIDs, vector.FileID)
 		successCount++
 	}
 
 	if successCount > 0 {
+		// 进入死信的任务不会被补偿扫描重新入队，手动重试时直接入队
+		if svc := GetGlobalVectorQueueService(); svc != nil {
+			svc.RequeueVectors(retriedIDs)
+		}
 		notifyVectorStatsChange()
 	}
 
@@ -643,7 +654,10 @@ func GetQdrantRealStats() (*QdrantRealStatsResponse, error) {
 		qdrantTimeout = 30
 	}
 
-	client := vector.NewQdrantClient(qdrantURL, qdrantTimeout)
+	client, err := vector.NewQdrantClientWithOptions(qdrantURL, qdrantTimeout, vector.QdrantOptionsFromSettings())
+	if err != nil {
+		return nil, err
+	}
 
 	if err := client.HealthCheck(); err != nil {
 		return &QdrantRealStatsResponse{
@@ -0,0 +1,298 @@
+package webdav
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"time"
+
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/pkg/logger"
+
+	"golang.org/x/net/webdav"
+)
+
+// fileInfo 实现 os.FileInfo，同时提供 ETag 与 Content-Type，避免 webdav 读取内容来推断
+type fileInfo struct {
+	name        string
+	size        int64
+	modTime     time.Time
+	isDir       bool
+	parentID    string
+	etag        string
+	contentType string
+}
+
+func folderInfo(f *models.Folder) *fileInfo {
+	return &fileInfo{name: f.Name, modTime: time.Time(f.UpdatedAt), isDir: true, parentID: f.ParentID}
+}
+
+func fileInfoOf(f *models.File) *fileInfo {
+	etag := f.MD5Hash
+	if etag == "" {
+		etag = f.ID
+	}
+	contentType := f.Mime
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(f.OriginalName))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &fileInfo{
+		name:        f.OriginalName,
+		size:        f.Size,
+		modTime:     time.Time(f.CreatedAt),
+		parentID:    f.FolderID,
+		etag:        `"` + etag + `"`,
+		contentType: contentType,
+	}
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.isDir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+/* ETag 文件以 MD5 作为 ETag，目录交由 webdav 按修改时间生成 */
+func (fi *fileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.isDir || fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+/* ContentType 使用上传时识别的类型 */
+func (fi *fileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.isDir || fi.contentType == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.contentType, nil
+}
+
+// dirFile 只读目录句柄
+type dirFile struct {
+	fs       *LibraryFS
+	folderID string
+	info     *fileInfo
+	entries  []os.FileInfo
+	loaded   bool
+	offset   int
+}
+
+func (d *dirFile) Close() error                                 { return nil }
+func (d *dirFile) Read(p []byte) (int, error)                   { return 0, fs.ErrInvalid }
+func (d *dirFile) Write(p []byte) (int, error)                  { return 0, fs.ErrInvalid }
+func (d *dirFile) Seek(offset int64, whence int) (int64, error) { return 0, fs.ErrInvalid }
+func (d *dirFile) Stat() (os.FileInfo, error)                   { return d.info, nil }
+
+func (d *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !d.loaded {
+		entries, err := d.fs.readDir(d.folderID)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.loaded = true
+	}
+
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	d.offset += count
+	return remaining[:count], nil
+}
+
+// readFile 按需打开文件内容；远程存储不支持随机读取，向后跳转时丢弃中间数据，向前跳转时重新打开
+type readFile struct {
+	ctx    context.Context
+	file   models.File
+	info   *fileInfo
+	rc     io.ReadCloser
+	pos    int64
+	offset int64
+}
+
+func (f *readFile) Readdir(count int) ([]os.FileInfo, error) { return nil, fs.ErrInvalid }
+func (f *readFile) Write(p []byte) (int, error)              { return 0, fs.ErrPermission }
+func (f *readFile) Stat() (os.FileInfo, error)               { return f.info, nil }
+
+func (f *readFile) Read(p []byte) (int, error) {
+	if err := f.sync(); err != nil {
+		return 0, err
+	}
+	n, err := f.rc.Read(p)
+	f.pos += int64(n)
+	f.offset = f.pos
+	return n, err
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	default:
+		return 0, fs.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, fs.ErrInvalid
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *readFile) Close() error {
+	if f.rc == nil {
+		return nil
+	}
+	return f.rc.Close()
+}
+
+// sync 使底层读取位置与逻辑偏移一致
+func (f *readFile) sync() error {
+	if f.rc != nil && f.pos == f.offset {
+		return nil
+	}
+	if seeker, ok := f.rc.(io.Seeker); ok {
+		pos, err := seeker.Seek(f.offset, io.SeekStart)
+		f.pos = pos
+		return err
+	}
+	if f.rc == nil || f.offset < f.pos {
+		if f.rc != nil {
+			f.rc.Close()
+		}
+		rc, err := filesvc.OpenFileContent(f.ctx, f.file)
+		if err != nil {
+			return toOSError(err)
+		}
+		f.rc = rc
+		f.pos = 0
+		if seeker, ok := rc.(io.Seeker); ok && f.offset > 0 {
+			pos, err := seeker.Seek(f.offset, io.SeekStart)
+			f.pos = pos
+			return err
+		}
+	}
+	skipped, err := io.CopyN(io.Discard, f.rc, f.offset-f.pos)
+	f.pos += skipped
+	return err
+}
+
+// writeFile 写入临时文件，关闭时作为新文件上传；未写入任何内容或写入未完成时不创建文件
+type writeFile struct {
+	fs       *LibraryFS
+	folderID string
+	name     string
+	maxSize  int64
+	tmp      *os.File
+	size     int64
+	err      error // 写入临时文件或读取来源时的首个错误，存在时关闭只丢弃临时文件
+}
+
+func (w *writeFile) Read(p []byte) (int, error)                   { return 0, fs.ErrPermission }
+func (w *writeFile) Seek(offset int64, whence int) (int64, error) { return w.size, nil }
+func (w *writeFile) Readdir(count int) ([]os.FileInfo, error)     { return nil, fs.ErrInvalid }
+
+func (w *writeFile) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.size+int64(len(p)) > w.maxSize {
+		w.err = os.ErrPermission
+		return 0, w.err
+	}
+	if w.tmp == nil {
+		tmp, err := os.CreateTemp("", "pixelpunk-webdav-*")
+		if err != nil {
+			w.err = err
+			return 0, err
+		}
+		w.tmp = tmp
+	}
+	n, err := w.tmp.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// ReadFrom 由 io.Copy 调用，记录读取请求体（PUT）或源文件（COPY/MOVE）时的错误；
+// 客户端断开等情况下 webdav 仍会调用 Close，据此丢弃不完整的内容
+func (w *writeFile) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 32*1024)
+	var total int64
+	for {
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			written, werr := w.Write(buf[:n])
+			total += int64(written)
+			if werr != nil {
+				return total, werr
+			}
+		}
+		if rerr == io.EOF {
+			return total, nil
+		}
+		if rerr != nil {
+			if w.err == nil {
+				w.err = rerr
+			}
+			return total, rerr
+		}
+	}
+}
+
+func (w *writeFile) Stat() (os.FileInfo, error) {
+	return &fileInfo{name: w.name, size: w.size, modTime: time.Now(), parentID: w.folderID}, nil
+}
+
+func (w *writeFile) Close() error {
+	if w.tmp == nil {
+		return nil
+	}
+	defer func() {
+		w.tmp.Close()
+		os.Remove(w.tmp.Name())
+	}()
+
+	// 写入未完成（超过大小限制、客户端断开等）时不替换已有文件
+	if w.err != nil {
+		logger.Warn("WebDAV 上传未完成，已丢弃: userID=%d, name=%s, size=%d, error=%v", w.fs.userID, w.name, w.size, w.err)
+		return w.err
+	}
+
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(path.Ext(w.name))
+	if _, err := filesvc.ReplaceLibraryFile(w.fs.c, w.fs.userID, w.fs.key, w.folderID, w.name, w.tmp, contentType); err != nil {
+		logger.Warn("WebDAV 上传文件失败: userID=%d, name=%s, error=%v", w.fs.userID, w.name, err)
+		return toOSError(err)
+	}
+	return nil
+}
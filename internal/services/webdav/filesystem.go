@@ -0,0 +1,324 @@
+package webdav
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	filesvc "pixelpunk/internal/services/file"
+	"pixelpunk/internal/services/folder"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/webdav"
+	"gorm.io/gorm"
+)
+
+// LibraryFS 将用户的文件夹与文件映射为 WebDAV 文件系统，使用API密钥时以密钥限定目录为根
+type LibraryFS struct {
+	c      *gin.Context
+	userID uint
+	key    *models.APIKey
+	root   string
+}
+
+var _ webdav.FileSystem = (*LibraryFS)(nil)
+
+// entry 路径解析结果，目录与文件二选一
+type entry struct {
+	folderID string
+	isDir    bool
+	file     *models.File
+	info     *fileInfo
+}
+
+/* NewLibraryFS 创建请求级文件系统，key 为空时表示使用账号密码登录 */
+func NewLibraryFS(c *gin.Context, userID uint, key *models.APIKey) *LibraryFS {
+	fs := &LibraryFS{c: c, userID: userID, key: key}
+	if key != nil && key.IsFolderRestricted() {
+		fs.root = key.FolderID
+	}
+	return fs
+}
+
+/* Mkdir 创建目录，父目录必须存在 */
+func (fs *LibraryFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	dir, base := splitPath(name)
+	if base == "" {
+		return os.ErrExist
+	}
+	if _, err := fs.resolve(name); err == nil {
+		return os.ErrExist
+	}
+	parent, err := fs.resolve(dir)
+	if err != nil {
+		return err
+	}
+	if !parent.isDir {
+		return os.ErrNotExist
+	}
+
+	if _, err := folder.CreateFolder(fs.userID, base, parent.folderID, "private", "", nil); err != nil {
+		return toOSError(err)
+	}
+	return nil
+}
+
+/* OpenFile 打开文件或目录，写入模式下返回的文件在关闭时上传 */
+func (fs *LibraryFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		return fs.openForWrite(name, flag)
+	}
+
+	e, err := fs.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if e.isDir {
+		return &dirFile{fs: fs, folderID: e.folderID, info: e.info}, nil
+	}
+	return &readFile{ctx: ctx, file: *e.file, info: e.info}, nil
+}
+
+/* RemoveAll 删除文件或整个目录，目录内的文件进入删除流程后再删除目录本身 */
+func (fs *LibraryFS) RemoveAll(ctx context.Context, name string) error {
+	e, err := fs.resolve(name)
+	if err != nil {
+		return err
+	}
+	if e.isDir {
+		if e.folderID == fs.root {
+			return os.ErrPermission
+		}
+		return fs.removeFolderTree(e.folderID)
+	}
+
+	var ids []string
+	database.DB.Model(&models.File{}).
+		Where("user_id = ? AND folder_id = ? AND original_name = ? AND status NOT IN ?", fs.userID, e.file.FolderID, e.file.OriginalName, filesvc.LibraryHiddenStatuses()).
+		Pluck("id", &ids)
+	for _, id := range ids {
+		if err := filesvc.DeleteFile(fs.userID, id); err != nil {
+			return toOSError(err)
+		}
+	}
+	return nil
+}
+
+/* Rename 移动或重命名文件与目录，目标已存在时由调用方先行删除 */
+func (fs *LibraryFS) Rename(ctx context.Context, oldName, newName string) error {
+	e, err := fs.resolve(oldName)
+	if err != nil {
+		return err
+	}
+	if e.isDir && e.folderID == fs.root {
+		return os.ErrPermission
+	}
+	if _, err := fs.resolve(newName); err == nil {
+		return os.ErrExist
+	}
+
+	dir, base := splitPath(newName)
+	parent, err := fs.resolve(dir)
+	if err != nil {
+		return err
+	}
+	if !parent.isDir || base == "" {
+		return os.ErrNotExist
+	}
+
+	if e.isDir {
+		if parent.folderID != e.info.parentID {
+			if err := folder.MoveFolder(fs.userID, e.folderID, parent.folderID); err != nil {
+				return toOSError(err)
+			}
+		}
+		if base != e.info.name {
+			if _, err := folder.UpdateFolder(fs.userID, e.folderID, base, "", "", "", nil); err != nil {
+				return toOSError(err)
+			}
+		}
+		return nil
+	}
+
+	updates := map[string]interface{}{"folder_id": parent.folderID}
+	if base != e.file.OriginalName {
+		updates["original_name"] = base
+		updates["display_name"] = base
+	}
+	if err := database.DB.Model(&models.File{}).Where("id = ? AND user_id = ?", e.file.ID, fs.userID).Updates(updates).Error; err != nil {
+		return toOSError(errors.Wrap(err, errors.CodeDBUpdateFailed, "移动文件失败"))
+	}
+	return nil
+}
+
+/* Stat 获取文件或目录信息 */
+func (fs *LibraryFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	e, err := fs.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.info, nil
+}
+
+// resolve 按路径逐级查找目录，最后一段优先匹配目录，其次匹配最新上传的同名文件
+func (fs *LibraryFS) resolve(name string) (*entry, error) {
+	parts := splitParts(name)
+	current := &entry{folderID: fs.root, isDir: true, info: &fileInfo{name: "/", isDir: true}}
+	if fs.root != "" {
+		var root models.Folder
+		if err := database.DB.Where("id = ? AND user_id = ?", fs.root, fs.userID).First(&root).Error; err != nil {
+			return nil, os.ErrNotExist
+		}
+		current.info.modTime = time.Time(root.UpdatedAt)
+	}
+
+	for i, part := range parts {
+		var f models.Folder
+		err := database.DB.Where("user_id = ? AND parent_id = ? AND name = ?", fs.userID, current.folderID, part).First(&f).Error
+		if err == nil {
+			current = &entry{folderID: f.ID, isDir: true, info: folderInfo(&f)}
+			continue
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, toOSError(errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败"))
+		}
+		if i != len(parts)-1 {
+			return nil, os.ErrNotExist
+		}
+
+		var file models.File
+		err = database.DB.Where("user_id = ? AND folder_id = ? AND original_name = ? AND status NOT IN ?", fs.userID, current.folderID, part, filesvc.LibraryHiddenStatuses()).
+			Order("created_at DESC").First(&file).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, os.ErrNotExist
+			}
+			return nil, toOSError(errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败"))
+		}
+		return &entry{folderID: current.folderID, file: &file, info: fileInfoOf(&file)}, nil
+	}
+	return current, nil
+}
+
+// readDir 列出目录下的子目录与文件，同名文件只保留最新一个，与目录重名的文件不可见
+func (fs *LibraryFS) readDir(folderID string) ([]os.FileInfo, error) {
+	var folders []models.Folder
+	if err := database.DB.Where("user_id = ? AND parent_id = ?", fs.userID, folderID).Order("name ASC").Find(&folders).Error; err != nil {
+		return nil, toOSError(errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件夹失败"))
+	}
+	var files []models.File
+	if err := database.DB.Where("user_id = ? AND folder_id = ? AND status NOT IN ?", fs.userID, folderID, filesvc.LibraryHiddenStatuses()).
+		Order("created_at DESC").Find(&files).Error; err != nil {
+		return nil, toOSError(errors.Wrap(err, errors.CodeDBQueryFailed, "查询文件失败"))
+	}
+
+	seen := make(map[string]bool, len(folders)+len(files))
+	infos := make([]os.FileInfo, 0, len(folders)+len(files))
+	for i := range folders {
+		seen[folders[i].Name] = true
+		infos = append(infos, folderInfo(&folders[i]))
+	}
+	for i := range files {
+		if seen[files[i].OriginalName] {
+			continue
+		}
+		seen[files[i].OriginalName] = true
+		infos = append(infos, fileInfoOf(&files[i]))
+	}
+	return infos, nil
+}
+
+func (fs *LibraryFS) openForWrite(name string, flag int) (webdav.File, error) {
+	dir, base := splitPath(name)
+	if base == "" {
+		return nil, os.ErrPermission
+	}
+	e, err := fs.resolve(name)
+	switch {
+	case err == nil && e.isDir:
+		return nil, os.ErrExist
+	case err == nil && flag&os.O_EXCL != 0:
+		return nil, os.ErrExist
+	case err != nil && err != os.ErrNotExist:
+		return nil, err
+	case err != nil && flag&os.O_CREATE == 0:
+		return nil, os.ErrNotExist
+	}
+
+	parent, err := fs.resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.isDir {
+		return nil, os.ErrNotExist
+	}
+	return &writeFile{fs: fs, folderID: parent.folderID, name: base, maxSize: filesvc.MaxLibraryFileSize(fs.key)}, nil
+}
+
+// removeFolderTree 删除目录及其全部子目录；回收站中的文件移回根目录以便仍可恢复
+func (fs *LibraryFS) removeFolderTree(folderID string) error {
+	ids := []string{folderID}
+	for i := 0; i < len(ids); i++ {
+		var children []string
+		if err := database.DB.Model(&models.Folder{}).Where("user_id = ? AND parent_id = ?", fs.userID, ids[i]).Pluck("id", &children).Error; err != nil {
+			return toOSError(errors.Wrap(err, errors.CodeDBQueryFailed, "查询子文件夹失败"))
+		}
+		ids = append(ids, children...)
+	}
+
+	var fileIDs []string
+	database.DB.Model(&models.File{}).
+		Where("user_id = ? AND folder_id IN ? AND status NOT IN ?", fs.userID, ids, filesvc.LibraryHiddenStatuses()).
+		Pluck("id", &fileIDs)
+	for _, id := range fileIDs {
+		if err := filesvc.DeleteFile(fs.userID, id); err != nil {
+			return toOSError(err)
+		}
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.File{}).
+			Where("user_id = ? AND folder_id IN ? AND status = ?", fs.userID, ids, filesvc.StatusDeleted).
+			Update("folder_id", "").Error; err != nil {
+			return toOSError(errors.Wrap(err, errors.CodeDBUpdateFailed, "移动回收站文件失败"))
+		}
+		if err := tx.Where("user_id = ? AND id IN ?", fs.userID, ids).Delete(&models.Folder{}).Error; err != nil {
+			return toOSError(errors.Wrap(err, errors.CodeFolderDeleteFailed, "删除文件夹失败"))
+		}
+		return nil
+	})
+}
+
+func splitParts(name string) []string {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
+
+func splitPath(name string) (string, string) {
+	dir, base := path.Split(strings.TrimSuffix(path.Clean("/"+name), "/"))
+	return dir, base
+}
+
+// toOSError 将业务错误映射为 webdav 包识别的文件系统错误
+func toOSError(err error) error {
+	switch {
+	case errors.IsCode(err, errors.CodeFileNotFound), errors.IsCode(err, errors.CodeFolderNotFound), errors.IsCode(err, errors.CodeNotFound):
+		return os.ErrNotExist
+	case errors.IsCode(err, errors.CodeFolderNameDuplicate):
+		return os.ErrExist
+	case errors.IsCode(err, errors.CodeForbidden), errors.IsCode(err, errors.CodeInvalidParameter),
+		errors.IsCode(err, errors.CodeStorageLimitExceeded), errors.IsCode(err, errors.CodeUploadLimitExceeded), errors.IsCode(err, errors.CodeFileTooLarge):
+		return os.ErrPermission
+	}
+	logger.Error("WebDAV 操作失败: %v", err)
+	return err
+}
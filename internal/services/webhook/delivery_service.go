@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+/* DeliveryListParams 投递记录查询参数 */
+type DeliveryListParams struct {
+	WebhookID uint
+	Event     string
+	Status    string
+	Page      int
+	Size      int
+}
+
+/* ListDeliveries 分页查询投递记录，列表不返回请求与响应内容 */
+func ListDeliveries(params DeliveryListParams) ([]models.WebhookDelivery, int64, error) {
+	query := database.GetDB().Model(&models.WebhookDelivery{}).Where("webhook_id = ?", params.WebhookID)
+	if params.Event != "" {
+		query = query.Where("event = ?", params.Event)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询投递记录失败")
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Omit("payload", "response_body").
+		Order("id DESC").
+		Offset((params.Page - 1) * params.Size).
+		Limit(params.Size).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, errors.Wrap(err, errors.CodeDBQueryFailed, "查询投递记录失败")
+	}
+	return deliveries, total, nil
+}
+
+/* GetDelivery 获取投递详情，包括请求体与响应内容 */
+func GetDelivery(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := database.GetDB().First(&delivery, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "投递记录不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询投递记录失败")
+	}
+	return &delivery, nil
+}
+
+/* Redeliver 以相同的请求体重新投递，生成新的投递记录并同步返回结果 */
+func Redeliver(id uint) (*models.WebhookDelivery, error) {
+	original, err := GetDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getWebhook(original.WebhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := createDelivery(original.WebhookID, original.EventID, original.Event, original.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建投递记录失败")
+	}
+	return deliverNow(delivery.ID)
+}
+
+/* Ping 发送测试事件，用于验证回调地址与签名配置，停用的 Webhook 也可测试 */
+func Ping(webhookID uint) (*models.WebhookDelivery, error) {
+	hook, err := getWebhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := Payload{
+		ID:        utils.GenerateFileID(),
+		Event:     EventPing,
+		Timestamp: time.Now().Unix(),
+		Data: map[string]interface{}{
+			"webhook_id": hook.ID,
+			"events":     hook.EventList(),
+		},
+	}
+	body, _ := json.Marshal(payload)
+
+	delivery, err := createDelivery(hook.ID, payload.ID, EventPing, string(body))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建投递记录失败")
+	}
+	return deliverNow(delivery.ID)
+}
+
+func deliverNow(id uint) (*models.WebhookDelivery, error) {
+	delivery := attemptDelivery(id)
+	if delivery == nil {
+		return GetDelivery(id)
+	}
+	return delivery, nil
+}
+
+/* CleanupDeliveries 删除超过保留天数且已结束的投递记录 */
+func CleanupDeliveries() (int64, error) {
+	days := setting.GetInt("webhook", "webhook_delivery_retention_days", 30)
+	if days <= 0 {
+		return 0, nil
+	}
+
+	result := database.GetDB().
+		Where("status IN ? AND created_at < ?", []string{models.WebhookDeliverySuccess, models.WebhookDeliveryFailed}, time.Now().AddDate(0, 0, -days)).
+		Delete(&models.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, errors.CodeDBDeleteFailed, "清理Webhook投递记录失败")
+	}
+	return result.RowsAffected, nil
+}
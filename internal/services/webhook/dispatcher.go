@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/utils"
+)
+
+const (
+	deliveryWorkers     = 4
+	deliveryQueueSize   = 1000
+	maxResponseBodySize = 2048
+	retryBaseDelay      = 30 * time.Second
+	retryMaxDelay       = 6 * time.Hour
+	// 投递中的记录超过该时间仍未完成，视为进程中断，重新进入重试
+	staleDeliveryTimeout = 5 * time.Minute
+)
+
+var (
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+
+	deliveryQueue chan uint
+	workersOnce   sync.Once
+)
+
+// Payload 推送给回调地址的请求体
+type Payload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+/* Dispatch 为订阅了该事件的 Webhook 创建投递记录并异步推送，队列已满时由定时任务补发 */
+func Dispatch(event string, data interface{}) {
+	hooks, err := loadActiveHooks()
+	if err != nil {
+		logger.Warn("读取Webhook配置失败: event=%s err=%v", event, err)
+		return
+	}
+
+	var targets []models.Webhook
+	for _, hook := range hooks {
+		if hook.Subscribes(event) {
+			targets = append(targets, hook)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	payload := Payload{
+		ID:        utils.GenerateFileID(),
+		Event:     event,
+		Timestamp: time.Now().Unix(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Warn("序列化Webhook事件失败: event=%s err=%v", event, err)
+		return
+	}
+
+	for _, hook := range targets {
+		delivery, err := createDelivery(hook.ID, payload.ID, event, string(body))
+		if err != nil {
+			logger.Warn("创建Webhook投递记录失败: webhookID=%d event=%s err=%v", hook.ID, event, err)
+			continue
+		}
+		enqueue(delivery.ID)
+	}
+}
+
+func createDelivery(webhookID uint, eventID, event, payload string) (*models.WebhookDelivery, error) {
+	now := common.JSONTime(time.Now())
+	delivery := &models.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := database.GetDB().Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func enqueue(deliveryID uint) {
+	workersOnce.Do(startWorkers)
+	select {
+	case deliveryQueue <- deliveryID:
+	default:
+		// 队列已满，记录保持 pending，等待定时任务处理
+	}
+}
+
+func startWorkers() {
+	deliveryQueue = make(chan uint, deliveryQueueSize)
+	for i := 0; i < deliveryWorkers; i++ {
+		go func() {
+			for id := range deliveryQueue {
+				attemptDelivery(id)
+			}
+		}()
+	}
+}
+
+/* ProcessDueDeliveries 将到期的待投递与重试记录放入投递队列并回收中断的投递，已停用的 Webhook 暂停重试，返回入队条数 */
+func ProcessDueDeliveries(limit int) int {
+	db := database.GetDB()
+	now := time.Now()
+
+	db.Model(&models.WebhookDelivery{}).
+		Where("status = ? AND updated_at < ?", models.WebhookDeliveryDelivering, now.Add(-staleDeliveryTimeout)).
+		Updates(map[string]interface{}{"status": models.WebhookDeliveryRetrying, "next_attempt_at": now})
+
+	var ids []uint
+	db.Model(&models.WebhookDelivery{}).
+		Where("status IN ? AND next_attempt_at <= ?", []string{models.WebhookDeliveryPending, models.WebhookDeliveryRetrying}, now).
+		Where("webhook_id IN (?)", db.Model(&models.Webhook{}).Select("id").Where("enabled = ?", true)).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Pluck("id", &ids)
+
+	for _, id := range ids {
+		enqueue(id)
+	}
+	return len(ids)
+}
+
+// attemptDelivery 抢占一条投递记录并发送，多个实例或协程同时处理时只有一个会成功抢占
+func attemptDelivery(id uint) *models.WebhookDelivery {
+	db := database.GetDB()
+
+	claim := db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status IN ?", id, []string{models.WebhookDeliveryPending, models.WebhookDeliveryRetrying}).
+		Update("status", models.WebhookDeliveryDelivering)
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return nil
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.First(&delivery, id).Error; err != nil {
+		return nil
+	}
+	var hook models.Webhook
+	if err := db.First(&hook, delivery.WebhookID).Error; err != nil {
+		db.Model(&delivery).Updates(map[string]interface{}{
+			"status": models.WebhookDeliveryFailed,
+			"error":  "Webhook不存在",
+		})
+		return nil
+	}
+
+	status, body, duration, sendErr := send(&hook, &delivery)
+	delivery.Attempts++
+	now := common.JSONTime(time.Now())
+
+	updates := map[string]interface{}{
+		"attempts":        delivery.Attempts,
+		"response_status": status,
+		"response_body":   body,
+		"duration_ms":     duration.Milliseconds(),
+		"error":           "",
+	}
+	success := sendErr == nil && status >= 200 && status < 300
+	if success {
+		updates["status"] = models.WebhookDeliverySuccess
+		updates["delivered_at"] = &now
+		updates["next_attempt_at"] = nil
+	} else {
+		errMsg := fmt.Sprintf("HTTP %d", status)
+		if sendErr != nil {
+			errMsg = sendErr.Error()
+		}
+		if len(errMsg) > 500 {
+			errMsg = errMsg[:500]
+		}
+		updates["error"] = errMsg
+
+		if delivery.Attempts >= maxAttempts() {
+			updates["status"] = models.WebhookDeliveryFailed
+			updates["next_attempt_at"] = nil
+			logger.Warn("Webhook投递失败且不再重试: webhookID=%d event=%s deliveryID=%d err=%s", hook.ID, delivery.Event, delivery.ID, errMsg)
+		} else {
+			next := common.JSONTime(time.Now().Add(retryDelay(delivery.Attempts)))
+			updates["status"] = models.WebhookDeliveryRetrying
+			updates["next_attempt_at"] = &next
+		}
+	}
+	db.Model(&delivery).Updates(updates)
+
+	hookUpdates := map[string]interface{}{
+		"last_delivery_at": &now,
+		"last_status":      updates["status"],
+	}
+	if success {
+		hookUpdates["consecutive_failures"] = 0
+	} else {
+		hookUpdates["consecutive_failures"] = hook.ConsecutiveFailures + 1
+	}
+	db.Model(&hook).Updates(hookUpdates)
+
+	db.First(&delivery, id)
+	return &delivery
+}
+
+// send 发送请求，签名方式与其他 PixelPunk 回调一致：X-PixelPunk-Signature = sha256=HMAC(secret, body)
+func send(hook *models.Webhook, delivery *models.WebhookDelivery) (int, string, time.Duration, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PixelPunk-Webhook/1.0")
+	req.Header.Set("X-PixelPunk-Event", delivery.Event)
+	req.Header.Set("X-PixelPunk-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set("X-PixelPunk-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, "", duration, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	return resp.StatusCode, string(respBody), duration, nil
+}
+
+func maxAttempts() int {
+	attempts := setting.GetInt("webhook", "webhook_max_attempts", 6)
+	if attempts < 1 {
+		return 1
+	}
+	return attempts
+}
+
+// retryDelay 指数退避：30秒、1分钟、2分钟……最长6小时
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
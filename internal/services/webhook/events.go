@@ -0,0 +1,47 @@
+package webhook
+
+// 可订阅的事件
+const (
+	EventPing               = "webhook.ping"
+	EventFileUploaded       = "file.uploaded"
+	EventFileDeleted        = "file.deleted"
+	EventShareCreated       = "share.created"
+	EventShareDeleted       = "share.deleted"
+	EventUserRegistered     = "user.registered"
+	EventReviewApproved     = "content.review.approved"
+	EventReviewRejected     = "content.review.rejected"
+	EventFileHardDeleted    = "content.file.hard_deleted"
+	EventAITaggingCompleted = "ai.tagging.completed"
+)
+
+// EventInfo 事件说明，供管理端选择订阅
+type EventInfo struct {
+	Event       string `json:"event"`
+	Description string `json:"description"`
+}
+
+var supportedEvents = []EventInfo{
+	{EventFileUploaded, "文件上传完成"},
+	{EventFileDeleted, "用户删除文件"},
+	{EventShareCreated, "创建分享"},
+	{EventShareDeleted, "删除分享"},
+	{EventUserRegistered, "新用户注册"},
+	{EventReviewApproved, "内容审核通过"},
+	{EventReviewRejected, "内容审核拒绝"},
+	{EventFileHardDeleted, "文件被永久删除"},
+	{EventAITaggingCompleted, "AI 打标完成"},
+}
+
+/* SupportedEvents 返回可订阅的事件列表 */
+func SupportedEvents() []EventInfo {
+	return supportedEvents
+}
+
+func isSupportedEvent(event string) bool {
+	for _, e := range supportedEvents {
+		if e.Event == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package webhook
+
+import (
+	"strings"
+	"sync"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/database"
+	"pixelpunk/pkg/errors"
+	"pixelpunk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+/* WebhookInput 创建或更新 Webhook 的参数 */
+type WebhookInput struct {
+	Name        string
+	URL         string
+	Events      []string
+	Enabled     *bool
+	Description string
+}
+
+// WebhookResponse Webhook 详情，密钥只在创建和重置时返回
+type WebhookResponse struct {
+	models.Webhook
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// 启用的 Webhook 缓存，配置变更时清空
+var (
+	activeHooks     []models.Webhook
+	activeHooksOK   bool
+	activeHooksLock sync.RWMutex
+)
+
+/* Create 创建 Webhook 并生成签名密钥 */
+func Create(adminID uint, input WebhookInput) (*WebhookResponse, error) {
+	events, err := normalizeEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &models.Webhook{
+		Name:        strings.TrimSpace(input.Name),
+		URL:         strings.TrimSpace(input.URL),
+		Secret:      utils.GenerateRandomString(32),
+		Events:      strings.Join(events, ","),
+		Enabled:     input.Enabled == nil || *input.Enabled,
+		Description: input.Description,
+		CreatedBy:   adminID,
+	}
+	if err := database.GetDB().Create(hook).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBCreateFailed, "创建Webhook失败")
+	}
+	invalidateActiveHooks()
+
+	resp := toResponse(hook)
+	resp.Secret = hook.Secret
+	return resp, nil
+}
+
+/* Update 更新 Webhook 配置 */
+func Update(id uint, input WebhookInput) (*WebhookResponse, error) {
+	hook, err := getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"name":        strings.TrimSpace(input.Name),
+		"url":         strings.TrimSpace(input.URL),
+		"events":      strings.Join(events, ","),
+		"description": input.Description,
+	}
+	if input.Enabled != nil {
+		updates["enabled"] = *input.Enabled
+		if *input.Enabled {
+			updates["consecutive_failures"] = 0
+		}
+	}
+	if err := database.GetDB().Model(hook).Updates(updates).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新Webhook失败")
+	}
+	invalidateActiveHooks()
+
+	return Get(id)
+}
+
+/* SetEnabled 启用或停用 Webhook，重新启用时清零连续失败次数 */
+func SetEnabled(id uint, enabled bool) (*WebhookResponse, error) {
+	hook, err := getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"enabled": enabled}
+	if enabled {
+		updates["consecutive_failures"] = 0
+	}
+	if err := database.GetDB().Model(hook).Updates(updates).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "更新Webhook状态失败")
+	}
+	invalidateActiveHooks()
+
+	return Get(id)
+}
+
+/* RotateSecret 重新生成签名密钥 */
+func RotateSecret(id uint) (*WebhookResponse, error) {
+	hook, err := getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	hook.Secret = utils.GenerateRandomString(32)
+	if err := database.GetDB().Model(hook).Update("secret", hook.Secret).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBUpdateFailed, "重置Webhook密钥失败")
+	}
+	invalidateActiveHooks()
+
+	resp := toResponse(hook)
+	resp.Secret = hook.Secret
+	return resp, nil
+}
+
+/* Delete 删除 Webhook 及其投递记录 */
+func Delete(id uint) (*models.Webhook, error) {
+	hook, err := getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(hook).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBDeleteFailed, "删除Webhook失败")
+	}
+	invalidateActiveHooks()
+
+	return hook, nil
+}
+
+/* Get 获取 Webhook 详情 */
+func Get(id uint) (*WebhookResponse, error) {
+	hook, err := getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(hook), nil
+}
+
+/* List 获取全部 Webhook */
+func List() ([]WebhookResponse, error) {
+	var hooks []models.Webhook
+	if err := database.GetDB().Order("id ASC").Find(&hooks).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询Webhook失败")
+	}
+
+	items := make([]WebhookResponse, 0, len(hooks))
+	for i := range hooks {
+		items = append(items, *toResponse(&hooks[i]))
+	}
+	return items, nil
+}
+
+func getWebhook(id uint) (*models.Webhook, error) {
+	var hook models.Webhook
+	if err := database.GetDB().First(&hook, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeNotFound, "Webhook不存在")
+		}
+		return nil, errors.Wrap(err, errors.CodeDBQueryFailed, "查询Webhook失败")
+	}
+	return &hook, nil
+}
+
+func toResponse(hook *models.Webhook) *WebhookResponse {
+	return &WebhookResponse{Webhook: *hook, Events: hook.EventList()}
+}
+
+// normalizeEvents 去重并校验事件名，包含 * 时只保留 *
+func normalizeEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "" || seen[e] {
+			continue
+		}
+		if e == models.WebhookAllEvents {
+			return []string{models.WebhookAllEvents}, nil
+		}
+		if !isSupportedEvent(e) {
+			return nil, errors.New(errors.CodeInvalidParameter, "不支持的事件: "+e)
+		}
+		seen[e] = true
+		result = append(result, e)
+	}
+	if len(result) == 0 {
+		return nil, errors.New(errors.CodeInvalidParameter, "请至少订阅一个事件")
+	}
+	return result, nil
+}
+
+// loadActiveHooks 读取启用的 Webhook，结果缓存到配置变更为止
+func loadActiveHooks() ([]models.Webhook, error) {
+	activeHooksLock.RLock()
+	if activeHooksOK {
+		hooks := activeHooks
+		activeHooksLock.RUnlock()
+		return hooks, nil
+	}
+	activeHooksLock.RUnlock()
+
+	var hooks []models.Webhook
+	if err := database.GetDB().Where("enabled = ?", true).Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	activeHooksLock.Lock()
+	activeHooks = hooks
+	activeHooksOK = true
+	activeHooksLock.Unlock()
+	return hooks, nil
+}
+
+func invalidateActiveHooks() {
+	activeHooksLock.Lock()
+	activeHooks = nil
+	activeHooksOK = false
+	activeHooksLock.Unlock()
+}
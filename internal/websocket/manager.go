@@ -22,6 +22,8 @@ type Manager struct {
 	stats *Stats
 
 	config *Config
+
+	relay *relay
 }
 
 // Config 配置
@@ -76,6 +78,7 @@ func DefaultConfig() *Config {
 
 // Start 启动管理器
 func (m *Manager) Start() {
+	m.relay = newRelay(m)
 	go m.run()
 	go m.pingClients()
 	go m.cleanupClients()
@@ -84,6 +87,7 @@ func (m *Manager) Start() {
 // Stop 停止管理器
 func (m *Manager) Stop() {
 	close(m.stopChan)
+	m.relay.close()
 
 	m.clientsMux.RLock()
 	for _, client := range m.clients {
@@ -108,16 +112,30 @@ func (m *Manager) UnregisterClient(client *Client) {
 	}
 }
 
-// BroadcastMessage 广播消息
+// BroadcastMessage 广播消息，多实例部署时同时转发给其他实例
 func (m *Manager) BroadcastMessage(msg *Message) {
+	m.broadcastLocal(msg)
+	m.relay.publish(&relayEnvelope{Target: relayTargetAll, Message: msg})
+}
+
+func (m *Manager) broadcastLocal(msg *Message) {
 	select {
 	case m.broadcast <- msg:
 	case <-m.stopChan:
 	}
 }
 
-// SendToClient 发送消息给指定客户端
+// SendToClient 发送消息给指定客户端，客户端不在本机时转发给其他实例
 func (m *Manager) SendToClient(clientID string, msg *Message) error {
+	err := m.sendToClientLocal(clientID, msg)
+	if err == ErrClientNotFound && m.relay != nil {
+		m.relay.publish(&relayEnvelope{Target: relayTargetClient, ClientID: clientID, Message: msg})
+		return nil
+	}
+	return err
+}
+
+func (m *Manager) sendToClientLocal(clientID string, msg *Message) error {
 	m.clientsMux.RLock()
 	client, exists := m.clients[clientID]
 	m.clientsMux.RUnlock()
@@ -131,6 +149,11 @@ func (m *Manager) SendToClient(clientID string, msg *Message) error {
 
 // SendToAdmins 发送消息给所有管理员
 func (m *Manager) SendToAdmins(msg *Message) {
+	m.sendToAdminsLocal(msg)
+	m.relay.publish(&relayEnvelope{Target: relayTargetAdmins, Message: msg})
+}
+
+func (m *Manager) sendToAdminsLocal(msg *Message) {
 	m.clientsMux.RLock()
 	defer m.clientsMux.RUnlock()
 
@@ -145,6 +168,27 @@ func (m *Manager) SendToAdmins(msg *Message) {
 	}
 }
 
+// SendToUser 发送消息给指定用户的所有连接
+func (m *Manager) SendToUser(userID uint, msg *Message) {
+	m.sendToUserLocal(userID, msg)
+	m.relay.publish(&relayEnvelope{Target: relayTargetUser, UserID: userID, Message: msg})
+}
+
+func (m *Manager) sendToUserLocal(userID uint, msg *Message) {
+	m.clientsMux.RLock()
+	defer m.clientsMux.RUnlock()
+
+	for _, client := range m.clients {
+		if client.UserID == userID && client.IsConnected() {
+			go func(c *Client) {
+				if err := c.SendMessage(msg); err != nil {
+					logger.Warn("发送消息给用户失败: %v", err)
+				}
+			}(client)
+		}
+	}
+}
+
 func (m *Manager) GetStats() *Stats {
 	m.stats.mutex.RLock()
 	defer m.stats.mutex.RUnlock()
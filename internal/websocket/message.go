@@ -15,6 +15,7 @@ const (
 	MessageTypeLogs         MessageType = "logs"
 	MessageTypeAnnouncement MessageType = "announcement"
 	MessageTypeSystemStatus MessageType = "system_status"
+	MessageTypeAITagging    MessageType = "ai_tagging_completed"
 	MessageTypeError        MessageType = "error"
 	MessageTypePing         MessageType = "ping"
 	MessageTypePong         MessageType = "pong"
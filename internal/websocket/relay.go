@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"pixelpunk/pkg/cache"
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const relayChannel = "ws:relay"
+
+// 转发目标
+const (
+	relayTargetAll    = "all"
+	relayTargetAdmins = "admins"
+	relayTargetUser   = "user"
+	relayTargetClient = "client"
+)
+
+// relayEnvelope 通过 Redis 发布给其他实例的消息，各实例只投递给本机连接
+type relayEnvelope struct {
+	Origin   string   `json:"origin"`
+	Target   string   `json:"target"`
+	UserID   uint     `json:"user_id,omitempty"`
+	ClientID string   `json:"client_id,omitempty"`
+	Message  *Message `json:"message"`
+}
+
+// relay 多实例部署时的消息扇出，客户端可能连接在负载均衡后的任意实例上
+type relay struct {
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+}
+
+// newRelay 启用 Redis 时订阅转发频道，未启用时返回 nil，消息只在本机投递
+func newRelay(m *Manager) *relay {
+	client := cache.GetRedisClient()
+	if !cache.IsRedisEnabled() || client == nil {
+		return nil
+	}
+
+	r := &relay{
+		client:  client,
+		channel: cache.GetNamespace() + ":" + relayChannel,
+	}
+	r.pubsub = client.Subscribe(context.Background(), r.channel)
+	go r.listen(m)
+	return r
+}
+
+// publish 将已在本机投递的消息发布给其他实例
+func (r *relay) publish(env *relayEnvelope) {
+	if r == nil {
+		return
+	}
+	env.Origin = cache.InstanceID()
+	data, err := json.Marshal(env)
+	if err != nil {
+		logger.Warn("序列化WebSocket转发消息失败: %v", err)
+		return
+	}
+	if err := r.client.Publish(context.Background(), r.channel, data).Err(); err != nil {
+		logger.Warn("发布WebSocket转发消息失败: %v", err)
+	}
+}
+
+// listen 处理其他实例发布的消息，连接断开期间的消息会丢失，推送本身不保证送达
+func (r *relay) listen(m *Manager) {
+	for msg := range r.pubsub.Channel() {
+		var env relayEnvelope
+		if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil || env.Message == nil {
+			continue
+		}
+		if env.Origin == cache.InstanceID() {
+			continue
+		}
+
+		switch env.Target {
+		case relayTargetAll:
+			m.broadcastLocal(env.Message)
+		case relayTargetAdmins:
+			m.sendToAdminsLocal(env.Message)
+		case relayTargetUser:
+			m.sendToUserLocal(env.UserID, env.Message)
+		case relayTargetClient:
+			_ = m.sendToClientLocal(env.ClientID, env.Message)
+		}
+	}
+}
+
+func (r *relay) close() {
+	if r == nil {
+		return
+	}
+	if err := r.pubsub.Close(); err != nil {
+		logger.Warn("关闭WebSocket转发订阅失败: %v", err)
+	}
+}
@@ -15,6 +15,8 @@ type migrationTask struct {
 // 注册的迁移列表
 var registeredMigrations = []migrationTask{
 	{"add_system_settings", AddSystemSettings},
+	{"share_upload_hold_status", MigrateShareUploadHold},
+	{"seed_platform_settings", SeedPlatformSettings},
 }
 
 // RegisterAllMigrations 注册所有迁移函数
@@ -80,6 +80,30 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "网站基础地址",
 			IsSystem:    true,
 		},
+		{
+			Key:         "maintenance_mode",
+			Value:       DefaultSettings.Website.MaintenanceMode,
+			Type:        "boolean",
+			Group:       "website",
+			Description: "维护模式：开启后上传和修改操作返回503，文件访问不受影响，管理员不受限制",
+			IsSystem:    true,
+		},
+		{
+			Key:         "maintenance_message",
+			Value:       DefaultSettings.Website.MaintenanceMessage,
+			Type:        "string",
+			Group:       "website",
+			Description: "维护模式提示信息",
+			IsSystem:    true,
+		},
+		{
+			Key:         "maintenance_end_time",
+			Value:       DefaultSettings.Website.MaintenanceEndTime,
+			Type:        "string",
+			Group:       "website",
+			Description: "预计维护结束时间（格式 2006-01-02 15:04:05），用于提示及 Retry-After 响应头",
+			IsSystem:    true,
+		},
 	}
 	allSettings = append(allSettings, websiteSettings...)
 
@@ -226,6 +250,22 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "新用户默认带宽流量(MB)",
 			IsSystem:    true,
 		},
+		{
+			Key:         "registration_mode",
+			Value:       DefaultSettings.Registration.RegistrationMode,
+			Type:        "string",
+			Group:       "registration",
+			Description: "注册模式：open开放注册，invite仅限邀请码注册（开放注册关闭时不允许注册）",
+			IsSystem:    true,
+		},
+		{
+			Key:         "user_invite_quota",
+			Value:       DefaultSettings.Registration.UserInviteQuota,
+			Type:        "number",
+			Group:       "registration",
+			Description: "普通用户可生成的邀请码数量，0表示仅管理员可生成",
+			IsSystem:    true,
+		},
 	}
 	allSettings = append(allSettings, registrationSettings...)
 
@@ -319,6 +359,110 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "AI任务历史保留天数",
 			IsSystem:    true,
 		},
+		{
+			Key:         "nsfw_category_rules",
+			Value:       DefaultSettings.AI.NSFWCategoryRules,
+			Type:        "json",
+			Group:       "ai",
+			Description: "NSFW分类阈值与处理动作(如 {\"violence\":{\"threshold\":0.7,\"action\":\"pending_review\"}})",
+			IsSystem:    true,
+		},
+		{
+			Key:         "tag_min_confidence",
+			Value:       DefaultSettings.AI.TagMinConfidence,
+			Type:        "number",
+			Group:       "ai",
+			Description: "自动应用AI标签的最低置信度(0-1)，低于该值的标签需用户确认，0表示不限制",
+			IsSystem:    true,
+		},
+		{
+			Key:         "category_min_confidence",
+			Value:       DefaultSettings.AI.CategoryMinConfidence,
+			Type:        "number",
+			Group:       "ai",
+			Description: "自动应用AI分类的最低置信度(0-1)，低于该值的分类需用户确认，0表示不限制",
+			IsSystem:    true,
+		},
+		{
+			Key:         "recommend_min_confidence",
+			Value:       DefaultSettings.AI.RecommendMinConfidence,
+			Type:        "number",
+			Group:       "ai",
+			Description: "自动应用AI推荐的最低置信度(0-1)，低于该值的推荐需用户确认，0表示不限制",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_auto_approve_enabled",
+			Value:       DefaultSettings.AI.AutoApproveEnabled,
+			Type:        "boolean",
+			Group:       "ai",
+			Description: "待审核文件超时无人处理时按NSFW分数自动通过",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_auto_approve_max_nsfw_score",
+			Value:       DefaultSettings.AI.AutoApproveMaxNSFWScore,
+			Type:        "number",
+			Group:       "ai",
+			Description: "自动通过的NSFW分数上限(0-1)，分数低于该值的待审核文件才会自动通过",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_auto_approve_after_hours",
+			Value:       DefaultSettings.AI.AutoApproveAfterHours,
+			Type:        "number",
+			Group:       "ai",
+			Description: "待审核文件上传后超过多少小时无人处理则自动通过",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_webhook_url",
+			Value:       DefaultSettings.AI.ReviewWebhookURL,
+			Type:        "string",
+			Group:       "ai",
+			Description: "审核决定(通过/拒绝/硬删除)的Webhook推送地址，为空表示不推送",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_webhook_secret",
+			Value:       DefaultSettings.AI.ReviewWebhookSecret,
+			Type:        "string",
+			Group:       "ai",
+			Description: "审核Webhook签名密钥，设置后请求头 X-PixelPunk-Signature 携带 HMAC-SHA256 签名",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_webhook_format",
+			Value:       DefaultSettings.AI.ReviewWebhookFormat,
+			Type:        "string",
+			Group:       "ai",
+			Description: "审核Webhook消息格式：json(通用JSON) 或 discord(Discord频道消息)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_rereview_enabled",
+			Value:       DefaultSettings.AI.ReReviewEnabled,
+			Type:        "boolean",
+			Group:       "ai",
+			Description: "NSFW阈值调低后，将分数落在新阈值临界区间内的已通过文件重新放入审核队列",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_rereview_band",
+			Value:       DefaultSettings.AI.ReReviewBand,
+			Type:        "number",
+			Group:       "ai",
+			Description: "重新审核的临界区间宽度(0-1)，分数在[新阈值, 新阈值+宽度)且低于旧阈值的文件会被重新审核",
+			IsSystem:    true,
+		},
+		{
+			Key:         "review_rereview_applied_threshold",
+			Value:       "",
+			Type:        "string",
+			Group:       "ai",
+			Description: "重新审核任务上次应用的NSFW阈值(系统维护，请勿修改)",
+			IsSystem:    true,
+		},
 	}
 	allSettings = append(allSettings, aiSettings...)
 
@@ -716,9 +860,299 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "域名黑名单",
 			IsSystem:    true,
 		},
+		{
+			Key:         "passkey_enabled",
+			Value:       DefaultSettings.Security.PasskeyEnabled,
+			Type:        "boolean",
+			Group:       "security",
+			Description: "启用通行密钥(Passkey)登录",
+			IsSystem:    true,
+		},
+		{
+			Key:         "passkey_rp_id",
+			Value:       DefaultSettings.Security.PasskeyRPID,
+			Type:        "string",
+			Group:       "security",
+			Description: "通行密钥RP ID，留空时使用站点地址的域名",
+			IsSystem:    true,
+		},
+		{
+			Key:         "passkey_rp_origins",
+			Value:       DefaultSettings.Security.PasskeyRPOrigins,
+			Type:        "string",
+			Group:       "security",
+			Description: "允许的通行密钥来源，多个用逗号分隔，留空时使用站点地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "sso_enforced",
+			Value:       DefaultSettings.Security.SSOEnforced,
+			Type:        "boolean",
+			Group:       "security",
+			Description: "强制使用SSO登录，开启后禁用账号密码登录与注册（超级管理员除外）",
+			IsSystem:    true,
+		},
+		{
+			Key:         "account_deletion_grace_days",
+			Value:       DefaultSettings.Security.AccountDeletionGrace,
+			Type:        "number",
+			Group:       "security",
+			Description: "注销账号的冷静期天数，期间可撤销注销，到期后删除全部数据",
+			IsSystem:    true,
+		},
+		{
+			Key:         "data_export_ttl_hours",
+			Value:       DefaultSettings.Security.DataExportTTLHours,
+			Type:        "number",
+			Group:       "security",
+			Description: "个人数据导出包的保留时长（小时）",
+			IsSystem:    true,
+		},
+		{
+			Key:         "login_alert_enabled",
+			Value:       DefaultSettings.Security.LoginAlertEnabled,
+			Type:        "boolean",
+			Group:       "security",
+			Description: "检测到新设备或新国家登录时通过站内信和邮件提醒用户",
+			IsSystem:    true,
+		},
+		{
+			Key:         "geoip_database_path",
+			Value:       DefaultSettings.Security.GeoIPDatabasePath,
+			Type:        "string",
+			Group:       "security",
+			Description: "MaxMind GeoLite2-Country 数据库文件路径，留空时仅按设备识别异常登录",
+			IsSystem:    true,
+		},
 	}
 	allSettings = append(allSettings, securitySettings...)
 
+	// LDAP / Active Directory 认证设置
+	ldapSettings := []dto.SettingCreateDTO{
+		{
+			Key:         "ldap_enabled",
+			Value:       DefaultSettings.LDAP.LDAPEnabled,
+			Type:        "boolean",
+			Group:       "ldap",
+			Description: "启用LDAP/AD登录",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_url",
+			Value:       DefaultSettings.LDAP.LDAPURL,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "LDAP服务器地址，如 ldaps://ldap.example.com:636",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_start_tls",
+			Value:       DefaultSettings.LDAP.LDAPStartTLS,
+			Type:        "boolean",
+			Group:       "ldap",
+			Description: "使用StartTLS加密连接",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_insecure_skip_verify",
+			Value:       DefaultSettings.LDAP.LDAPInsecureSkipVerify,
+			Type:        "boolean",
+			Group:       "ldap",
+			Description: "跳过TLS证书校验",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_bind_dn",
+			Value:       DefaultSettings.LDAP.LDAPBindDN,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "用于搜索用户的绑定DN",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_bind_password",
+			Value:       DefaultSettings.LDAP.LDAPBindPassword,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "绑定DN的密码",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_base_dn",
+			Value:       DefaultSettings.LDAP.LDAPBaseDN,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "用户搜索的Base DN",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_user_filter",
+			Value:       DefaultSettings.LDAP.LDAPUserFilter,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "用户搜索过滤器，{username} 为登录账号占位符",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_username_attribute",
+			Value:       DefaultSettings.LDAP.LDAPUsernameAttribute,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "用户名属性",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_email_attribute",
+			Value:       DefaultSettings.LDAP.LDAPEmailAttribute,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "邮箱属性",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_group_attribute",
+			Value:       DefaultSettings.LDAP.LDAPGroupAttribute,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "用户所属组属性",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_group_role_mapping",
+			Value:       DefaultSettings.LDAP.LDAPGroupRoleMapping,
+			Type:        "string",
+			Group:       "ldap",
+			Description: "组到角色的映射，每行一条：组DN=角色(1超级管理员 2管理员 3普通用户 4审核员)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_default_role",
+			Value:       DefaultSettings.LDAP.LDAPDefaultRole,
+			Type:        "number",
+			Group:       "ldap",
+			Description: "未匹配任何组时的默认角色",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ldap_auto_create_user",
+			Value:       DefaultSettings.LDAP.LDAPAutoCreateUser,
+			Type:        "boolean",
+			Group:       "ldap",
+			Description: "首次登录时自动创建本地用户",
+			IsSystem:    true,
+		},
+	}
+	allSettings = append(allSettings, ldapSettings...)
+
+	// SAML 2.0 单点登录设置
+	samlSettings := []dto.SettingCreateDTO{
+		{
+			Key:         "saml_enabled",
+			Value:       DefaultSettings.SAML.SAMLEnabled,
+			Type:        "boolean",
+			Group:       "saml",
+			Description: "启用SAML 2.0单点登录",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_idp_metadata_url",
+			Value:       DefaultSettings.SAML.SAMLIDPMetadataURL,
+			Type:        "string",
+			Group:       "saml",
+			Description: "身份提供商(IdP)元数据地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_idp_metadata_xml",
+			Value:       DefaultSettings.SAML.SAMLIDPMetadataXML,
+			Type:        "string",
+			Group:       "saml",
+			Description: "身份提供商(IdP)元数据XML，填写后优先于元数据地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_sp_entity_id",
+			Value:       DefaultSettings.SAML.SAMLSPEntityID,
+			Type:        "string",
+			Group:       "saml",
+			Description: "服务提供商(SP)实体ID，留空时使用元数据地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_sp_certificate",
+			Value:       DefaultSettings.SAML.SAMLSPCertificate,
+			Type:        "string",
+			Group:       "saml",
+			Description: "SP签名证书(PEM)，留空时首次使用自动生成",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_sp_private_key",
+			Value:       DefaultSettings.SAML.SAMLSPPrivateKey,
+			Type:        "string",
+			Group:       "saml",
+			Description: "SP签名私钥(PEM)，留空时首次使用自动生成",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_username_attribute",
+			Value:       DefaultSettings.SAML.SAMLUsernameAttribute,
+			Type:        "string",
+			Group:       "saml",
+			Description: "用户名属性，缺失时使用NameID",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_email_attribute",
+			Value:       DefaultSettings.SAML.SAMLEmailAttribute,
+			Type:        "string",
+			Group:       "saml",
+			Description: "邮箱属性",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_group_attribute",
+			Value:       DefaultSettings.SAML.SAMLGroupAttribute,
+			Type:        "string",
+			Group:       "saml",
+			Description: "用户所属组属性",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_group_role_mapping",
+			Value:       DefaultSettings.SAML.SAMLGroupRoleMapping,
+			Type:        "string",
+			Group:       "saml",
+			Description: "组到角色的映射，每行一条：组名=角色(1超级管理员 2管理员 3普通用户 4审核员)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_default_role",
+			Value:       DefaultSettings.SAML.SAMLDefaultRole,
+			Type:        "number",
+			Group:       "saml",
+			Description: "未匹配任何组时的默认角色",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_auto_create_user",
+			Value:       DefaultSettings.SAML.SAMLAutoCreateUser,
+			Type:        "boolean",
+			Group:       "saml",
+			Description: "首次登录时自动创建本地用户",
+			IsSystem:    true,
+		},
+		{
+			Key:         "saml_button_text",
+			Value:       DefaultSettings.SAML.SAMLButtonText,
+			Type:        "string",
+			Group:       "saml",
+			Description: "登录页SSO按钮文字",
+			IsSystem:    true,
+		},
+	}
+	allSettings = append(allSettings, samlSettings...)
+
 	// 向量搜索设置
 	vectorSettings := []dto.SettingCreateDTO{
 		{
@@ -742,7 +1176,7 @@ func AddSystemSettings(db *gorm.DB) error {
 			Value:       DefaultSettings.Vector.VectorProvider,
 			Type:        "string",
 			Group:       "vector",
-			Description: "向量化提供者",
+			Description: "向量化提供者（openai/ollama）",
 			IsSystem:    true,
 		},
 		{
@@ -753,6 +1187,22 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "向量化模型",
 			IsSystem:    true,
 		},
+		{
+			Key:         "ollama_base_url",
+			Value:       DefaultSettings.Vector.OllamaBaseURL,
+			Type:        "string",
+			Group:       "vector",
+			Description: "本地Ollama服务地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ollama_model",
+			Value:       DefaultSettings.Vector.OllamaModel,
+			Type:        "string",
+			Group:       "vector",
+			Description: "本地Ollama向量化模型",
+			IsSystem:    true,
+		},
 		{
 			Key:         "vector_api_key",
 			Value:       DefaultSettings.Vector.VectorAPIKey,
@@ -809,6 +1259,126 @@ func AddSystemSettings(db *gorm.DB) error {
 			Description: "向量生成并发数量",
 			IsSystem:    true,
 		},
+		{
+			Key:         "vector_backend",
+			Value:       DefaultSettings.Vector.VectorBackend,
+			Type:        "string",
+			Group:       "vector",
+			Description: "向量存储后端(qdrant/pgvector/weaviate)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "pgvector_dsn",
+			Value:       DefaultSettings.Vector.PgVectorDSN,
+			Type:        "string",
+			Group:       "vector",
+			Description: "pgvector使用的Postgres连接串",
+			IsSystem:    true,
+		},
+		{
+			Key:         "weaviate_url",
+			Value:       DefaultSettings.Vector.WeaviateURL,
+			Type:        "string",
+			Group:       "vector",
+			Description: "Weaviate服务地址",
+			IsSystem:    true,
+		},
+		{
+			Key:         "weaviate_api_key",
+			Value:       DefaultSettings.Vector.WeaviateAPIKey,
+			Type:        "string",
+			Group:       "vector",
+			Description: "Weaviate API密钥",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_api_key",
+			Value:       DefaultSettings.Vector.QdrantAPIKey,
+			Type:        "string",
+			Group:       "vector",
+			Description: "Qdrant API密钥，开启鉴权的实例需要配置",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_ca_cert",
+			Value:       DefaultSettings.Vector.QdrantCACert,
+			Type:        "string",
+			Group:       "vector",
+			Description: "Qdrant TLS CA证书(PEM内容或文件路径)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_tls_skip_verify",
+			Value:       DefaultSettings.Vector.QdrantTLSSkipVerify,
+			Type:        "boolean",
+			Group:       "vector",
+			Description: "跳过Qdrant TLS证书校验(仅用于测试)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_builtin",
+			Value:       DefaultSettings.Vector.QdrantBuiltin,
+			Type:        "boolean",
+			Group:       "vector",
+			Description: "是否由应用托管内置Qdrant进程",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_builtin_http_port",
+			Value:       DefaultSettings.Vector.QdrantBuiltinHTTPPort,
+			Type:        "number",
+			Group:       "vector",
+			Description: "内置Qdrant HTTP端口",
+			IsSystem:    true,
+		},
+		{
+			Key:         "qdrant_builtin_grpc_port",
+			Value:       DefaultSettings.Vector.QdrantBuiltinGRPCPort,
+			Type:        "number",
+			Group:       "vector",
+			Description: "内置Qdrant gRPC端口",
+			IsSystem:    true,
+		},
+		{
+			Key:         "hybrid_vector_weight",
+			Value:       DefaultSettings.Vector.HybridVectorWeight,
+			Type:        "number",
+			Group:       "vector",
+			Description: "混合搜索中向量结果的融合权重",
+			IsSystem:    true,
+		},
+		{
+			Key:         "hybrid_keyword_weight",
+			Value:       DefaultSettings.Vector.HybridKeywordWeight,
+			Type:        "number",
+			Group:       "vector",
+			Description: "混合搜索中关键词结果的融合权重",
+			IsSystem:    true,
+		},
+		{
+			Key:         "hybrid_rrf_k",
+			Value:       DefaultSettings.Vector.HybridRRFK,
+			Type:        "number",
+			Group:       "vector",
+			Description: "混合搜索RRF平滑常数k",
+			IsSystem:    true,
+		},
+		{
+			Key:         "duplicate_scan_enabled",
+			Value:       DefaultSettings.Vector.DuplicateScanEnabled,
+			Type:        "boolean",
+			Group:       "vector",
+			Description: "是否每晚基于向量扫描近似重复文件",
+			IsSystem:    true,
+		},
+		{
+			Key:         "duplicate_threshold",
+			Value:       DefaultSettings.Vector.DuplicateThreshold,
+			Type:        "number",
+			Group:       "vector",
+			Description: "近似重复判定的余弦相似度阈值",
+			IsSystem:    true,
+		},
 	}
 	allSettings = append(allSettings, vectorSettings...)
 
@@ -1028,6 +1598,123 @@ func AddSystemSettings(db *gorm.DB) error {
 	}
 	allSettings = append(allSettings, announcementSettings...)
 
+	// 分享配置
+	shareSettings := []dto.SettingCreateDTO{
+		{
+			Key:         "share_archive_max_files",
+			Value:       DefaultSettings.Share.ArchiveMaxFiles,
+			Type:        "number",
+			Group:       "share",
+			Description: "分享打包下载单次最大文件数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_archive_max_size_mb",
+			Value:       DefaultSettings.Share.ArchiveMaxSizeMB,
+			Type:        "number",
+			Group:       "share",
+			Description: "分享打包下载单次最大总大小(MB)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_archive_ttl_minutes",
+			Value:       DefaultSettings.Share.ArchiveTTLMinutes,
+			Type:        "number",
+			Group:       "share",
+			Description: "分享打包文件保留时间(分钟)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_archive_max_active_per_share",
+			Value:       DefaultSettings.Share.ArchiveMaxActivePerShare,
+			Type:        "number",
+			Group:       "share",
+			Description: "同一分享同时进行的打包任务数上限(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_archive_max_active_per_ip",
+			Value:       DefaultSettings.Share.ArchiveMaxActivePerIP,
+			Type:        "number",
+			Group:       "share",
+			Description: "同一IP同时进行的打包任务数上限(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_archive_disk_budget_mb",
+			Value:       DefaultSettings.Share.ArchiveDiskBudgetMB,
+			Type:        "number",
+			Group:       "share",
+			Description: "单个实例打包临时文件占用磁盘上限(MB，0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_expiry_reminder_hours",
+			Value:       DefaultSettings.Share.ExpiryReminderHours,
+			Type:        "number",
+			Group:       "share",
+			Description: "分享过期前多少小时提醒分享者(0表示不提醒)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_expiry_reminder_email",
+			Value:       DefaultSettings.Share.ExpiryReminderEmail,
+			Type:        "boolean",
+			Group:       "share",
+			Description: "分享过期提醒是否同时发送邮件",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_extend_days",
+			Value:       DefaultSettings.Share.ExtendDays,
+			Type:        "number",
+			Group:       "share",
+			Description: "一键续期延长的天数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_password_max_attempts",
+			Value:       DefaultSettings.Share.PasswordMaxAttempts,
+			Type:        "number",
+			Group:       "share",
+			Description: "同一IP连续输错分享密码的次数上限(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_password_lockout_minutes",
+			Value:       DefaultSettings.Share.PasswordLockoutMinutes,
+			Type:        "number",
+			Group:       "share",
+			Description: "分享密码错误次数超限后的锁定时长(分钟)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_email_code_ip_hourly",
+			Value:       DefaultSettings.Share.EmailCodeIPHourly,
+			Type:        "number",
+			Group:       "share",
+			Description: "同一IP每小时可发送的分享访问验证码数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_email_code_share_hourly",
+			Value:       DefaultSettings.Share.EmailCodeShareHourly,
+			Type:        "number",
+			Group:       "share",
+			Description: "同一分享每小时可发送的访问验证码数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "share_email_code_global_hourly",
+			Value:       DefaultSettings.Share.EmailCodeGlobalHourly,
+			Type:        "number",
+			Group:       "share",
+			Description: "全站每小时可发送的分享访问验证码数(0表示不限制)",
+			IsSystem:    true,
+		},
+	}
+	allSettings = append(allSettings, shareSettings...)
+
 	// 法律文档设置 - 使用预定义模板
 	legalSettings := []dto.SettingCreateDTO{
 		{
@@ -1049,6 +1736,9 @@ func AddSystemSettings(db *gorm.DB) error {
 	}
 	allSettings = append(allSettings, legalSettings...)
 
+	// 跨域、限流、WebDAV、GraphQL、后台任务与 Webhook 设置
+	allSettings = append(allSettings, platformSettings()...)
+
 	// 批量插入或更新设置
 	settingsDTO := &dto.BatchUpsertSettingDTO{
 		Settings: allSettings,
@@ -11,14 +11,26 @@ var DefaultSettings = struct {
 	Theme        ThemeSettings
 	Guest        GuestSettings
 	Security     SecuritySettings
+	LDAP         LDAPSettings
+	SAML         SAMLSettings
 	Vector       VectorSettings
 	Version      VersionSettings
 	Appearance   AppearanceSettings
 	Announcement AnnouncementSettings
+	Share        ShareSettings
+	CORS         CORSSettings
+	RateLimit    RateLimitSettings
+	WebDAV       WebDAVSettings
+	GraphQL      GraphQLSettings
+	Jobs         JobsSettings
+	Webhook      WebhookSettings
 }{
 	Website: WebsiteSettings{
-		AdminEmail:  "",
-		SiteBaseURL: "",
+		AdminEmail:         "",
+		SiteBaseURL:        "",
+		MaintenanceMode:    false,
+		MaintenanceMessage: "系统维护中，暂时无法上传或修改数据，请稍后再试",
+		MaintenanceEndTime: "",
 	},
 
 	WebsiteInfo: WebsiteInfoSettings{
@@ -42,6 +54,8 @@ var DefaultSettings = struct {
 		EmailVerification:    true,
 		UserInitialStorage:   1024,  // MB
 		UserInitialBandwidth: 10240, // MB
+		RegistrationMode:     "open",
+		UserInviteQuota:      0,
 	},
 
 	AI: AISettings{
@@ -56,6 +70,18 @@ var DefaultSettings = struct {
 		NSFWThreshold:             0.6,
 		PendingStuckThresholdMins: 30,
 		AIJobRetentionDays:        14,
+		NSFWCategoryRules:         map[string]interface{}{},
+		TagMinConfidence:          0,
+		CategoryMinConfidence:     0,
+		RecommendMinConfidence:    0,
+		AutoApproveEnabled:        false,
+		AutoApproveMaxNSFWScore:   0.3,
+		AutoApproveAfterHours:     24,
+		ReviewWebhookURL:          "",
+		ReviewWebhookSecret:       "",
+		ReviewWebhookFormat:       "json",
+		ReReviewEnabled:           false,
+		ReReviewBand:              0.1,
 	},
 
 	Mail: MailSettings{
@@ -118,6 +144,47 @@ var DefaultSettings = struct {
 		IPBlacklist:           "",
 		DomainWhitelist:       "",
 		DomainBlacklist:       "",
+		PasskeyEnabled:        true,
+		PasskeyRPID:           "",
+		PasskeyRPOrigins:      "",
+		SSOEnforced:           false,
+		AccountDeletionGrace:  7,
+		DataExportTTLHours:    24,
+		LoginAlertEnabled:     true,
+		GeoIPDatabasePath:     "",
+	},
+
+	LDAP: LDAPSettings{
+		LDAPEnabled:            false,
+		LDAPURL:                "",
+		LDAPStartTLS:           false,
+		LDAPInsecureSkipVerify: false,
+		LDAPBindDN:             "",
+		LDAPBindPassword:       "",
+		LDAPBaseDN:             "",
+		LDAPUserFilter:         "(&(objectClass=person)(|(uid={username})(sAMAccountName={username})(mail={username})))",
+		LDAPUsernameAttribute:  "uid",
+		LDAPEmailAttribute:     "mail",
+		LDAPGroupAttribute:     "memberOf",
+		LDAPGroupRoleMapping:   "",
+		LDAPDefaultRole:        3,
+		LDAPAutoCreateUser:     true,
+	},
+
+	SAML: SAMLSettings{
+		SAMLEnabled:           false,
+		SAMLIDPMetadataURL:    "",
+		SAMLIDPMetadataXML:    "",
+		SAMLSPEntityID:        "",
+		SAMLSPCertificate:     "",
+		SAMLSPPrivateKey:      "",
+		SAMLUsernameAttribute: "uid",
+		SAMLEmailAttribute:    "email",
+		SAMLGroupAttribute:    "groups",
+		SAMLGroupRoleMapping:  "",
+		SAMLDefaultRole:       3,
+		SAMLAutoCreateUser:    true,
+		SAMLButtonText:        "企业SSO登录",
 	},
 
 	Vector: VectorSettings{
@@ -125,6 +192,8 @@ var DefaultSettings = struct {
 		VectorAutoProcessingEnabled: true,
 		VectorProvider:              "openai",
 		VectorModel:                 "text-embedding-3-small",
+		OllamaBaseURL:               "http://localhost:11434",
+		OllamaModel:                 "all-minilm",
 		VectorAPIKey:                "",
 		VectorBaseURL:               "https://api.openai.com/v1",
 		VectorTimeout:               30,
@@ -132,6 +201,21 @@ var DefaultSettings = struct {
 		VectorSearchThreshold:       0.36,
 		VectorMaxResults:            100,
 		VectorConcurrency:           3,
+		VectorBackend:               "qdrant",
+		PgVectorDSN:                 "",
+		WeaviateURL:                 "",
+		WeaviateAPIKey:              "",
+		QdrantAPIKey:                "",
+		QdrantCACert:                "",
+		QdrantTLSSkipVerify:         false,
+		QdrantBuiltin:               false,
+		QdrantBuiltinHTTPPort:       6333,
+		QdrantBuiltinGRPCPort:       6334,
+		HybridVectorWeight:          1.0,
+		HybridKeywordWeight:         1.0,
+		HybridRRFK:                  60,
+		DuplicateScanEnabled:        true,
+		DuplicateThreshold:          0.95,
 	},
 
 	Version: VersionSettings{
@@ -167,12 +251,91 @@ var DefaultSettings = struct {
 		AnnouncementDisplayLimit:  10,
 		AnnouncementAutoShowDelay: 2, // 秒
 	},
+
+	Share: ShareSettings{
+		ArchiveMaxFiles:   500,
+		ArchiveMaxSizeMB:  2048,
+		ArchiveTTLMinutes: 60,
+
+		ArchiveMaxActivePerShare: 3,
+		ArchiveMaxActivePerIP:    2,
+		ArchiveDiskBudgetMB:      10240,
+
+		ExpiryReminderHours: 24,
+		ExpiryReminderEmail: false,
+		ExtendDays:          7,
+
+		PasswordMaxAttempts:    5,
+		PasswordLockoutMinutes: 15,
+
+		EmailCodeIPHourly:     10,
+		EmailCodeShareHourly:  50,
+		EmailCodeGlobalHourly: 200,
+	},
+
+	CORS: CORSSettings{
+		Enabled:               true,
+		APIAllowedOrigins:     "*",
+		APIAllowCredentials:   true,
+		APIAllowedMethods:     "GET, POST, PUT, DELETE, OPTIONS, PATCH",
+		APIMaxAge:             86400,
+		FilesAllowedOrigins:   "*",
+		FilesAllowCredentials: true,
+		FilesAllowedMethods:   "GET, HEAD, OPTIONS",
+		FilesMaxAge:           86400,
+	},
+
+	RateLimit: RateLimitSettings{
+		Enabled:            true,
+		GlobalPerMinute:    0,
+		GlobalBurst:        0,
+		AuthPerMinute:      20,
+		AuthBurst:          10,
+		SearchPerMinute:    60,
+		SearchBurst:        20,
+		RandomAPIPerMinute: 120,
+		RandomAPIBurst:     60,
+	},
+
+	WebDAV: WebDAVSettings{
+		Enabled:           true,
+		AllowPasswordAuth: true,
+	},
+
+	GraphQL: GraphQLSettings{
+		Enabled:   false,
+		MaxDepth:  12,
+		MaxFields: 300,
+	},
+
+	Jobs: JobsSettings{
+		AITaggingMaxAttempts:      3,
+		AITaggingRetryBaseSeconds: 1,
+		AITaggingRetryMaxSeconds:  60,
+		VectorMaxAttempts:         3,
+		VectorRetryBaseSeconds:    3,
+		VectorRetryMaxSeconds:     60,
+		ThumbnailMaxAttempts:      5,
+		ThumbnailRetryBaseSeconds: 60,
+		ThumbnailRetryMaxSeconds:  3600,
+		CleanupMaxAttempts:        3,
+		CleanupRetryBaseSeconds:   60,
+		CleanupRetryMaxSeconds:    900,
+	},
+
+	Webhook: WebhookSettings{
+		MaxAttempts:           6,
+		DeliveryRetentionDays: 30,
+	},
 }
 
 // WebsiteSettings 网站后端功能设置
 type WebsiteSettings struct {
-	AdminEmail  string
-	SiteBaseURL string
+	AdminEmail         string
+	SiteBaseURL        string
+	MaintenanceMode    bool
+	MaintenanceMessage string
+	MaintenanceEndTime string
 }
 
 // WebsiteInfoSettings 网站信息配置（前端显示）
@@ -196,8 +359,10 @@ type WebsiteInfoSettings struct {
 type RegistrationSettings struct {
 	EnableRegistration   bool
 	EmailVerification    bool
-	UserInitialStorage   int // MB
-	UserInitialBandwidth int // MB
+	UserInitialStorage   int    // MB
+	UserInitialBandwidth int    // MB
+	RegistrationMode     string // open: 开放注册 invite: 仅邀请注册
+	UserInviteQuota      int    // 普通用户可生成的邀请码数量
 }
 
 // AISettings AI配置
@@ -213,6 +378,18 @@ type AISettings struct {
 	NSFWThreshold             float64
 	PendingStuckThresholdMins int
 	AIJobRetentionDays        int
+	NSFWCategoryRules         map[string]interface{} // 分类 -> {threshold, action}
+	TagMinConfidence          float64                // 低于该置信度的标签转为建议，0表示不限制
+	CategoryMinConfidence     float64
+	RecommendMinConfidence    float64
+	AutoApproveEnabled        bool    // 低风险待审核文件超时自动通过
+	AutoApproveMaxNSFWScore   float64 // NSFW分数低于该值才会自动通过
+	AutoApproveAfterHours     int     // 进入待审核多少小时后无人处理则自动通过
+	ReviewWebhookURL          string  // 审核决定回调地址，为空不推送
+	ReviewWebhookSecret       string  // 回调签名密钥
+	ReviewWebhookFormat       string  // json/discord
+	ReReviewEnabled           bool    // 阈值调严后重新审核处于临界区间的已通过文件
+	ReReviewBand              float64 // 临界区间宽度，新阈值之上该宽度内的文件会重新进入审核
 }
 
 // MailSettings 邮件设置
@@ -277,6 +454,49 @@ type SecuritySettings struct {
 	IPBlacklist           string
 	DomainWhitelist       string
 	DomainBlacklist       string
+	PasskeyEnabled        bool
+	PasskeyRPID           string
+	PasskeyRPOrigins      string
+	SSOEnforced           bool
+	AccountDeletionGrace  int // 注销账号的冷静期天数
+	DataExportTTLHours    int // 个人数据导出包的保留小时数
+	LoginAlertEnabled     bool
+	GeoIPDatabasePath     string
+}
+
+// LDAPSettings LDAP / Active Directory 认证设置
+type LDAPSettings struct {
+	LDAPEnabled            bool
+	LDAPURL                string
+	LDAPStartTLS           bool
+	LDAPInsecureSkipVerify bool
+	LDAPBindDN             string
+	LDAPBindPassword       string
+	LDAPBaseDN             string
+	LDAPUserFilter         string
+	LDAPUsernameAttribute  string
+	LDAPEmailAttribute     string
+	LDAPGroupAttribute     string
+	LDAPGroupRoleMapping   string
+	LDAPDefaultRole        int
+	LDAPAutoCreateUser     bool
+}
+
+// SAMLSettings SAML 2.0 单点登录设置
+type SAMLSettings struct {
+	SAMLEnabled           bool
+	SAMLIDPMetadataURL    string
+	SAMLIDPMetadataXML    string
+	SAMLSPEntityID        string
+	SAMLSPCertificate     string
+	SAMLSPPrivateKey      string
+	SAMLUsernameAttribute string
+	SAMLEmailAttribute    string
+	SAMLGroupAttribute    string
+	SAMLGroupRoleMapping  string
+	SAMLDefaultRole       int
+	SAMLAutoCreateUser    bool
+	SAMLButtonText        string
 }
 
 // VectorSettings 向量搜索设置
@@ -285,6 +505,8 @@ type VectorSettings struct {
 	VectorAutoProcessingEnabled bool
 	VectorProvider              string
 	VectorModel                 string
+	OllamaBaseURL               string
+	OllamaModel                 string
 	VectorAPIKey                string
 	VectorBaseURL               string
 	VectorTimeout               int
@@ -292,6 +514,21 @@ type VectorSettings struct {
 	VectorSearchThreshold       float64
 	VectorMaxResults            int
 	VectorConcurrency           int
+	VectorBackend               string
+	PgVectorDSN                 string
+	WeaviateURL                 string
+	WeaviateAPIKey              string
+	QdrantAPIKey                string
+	QdrantCACert                string
+	QdrantTLSSkipVerify         bool
+	QdrantBuiltin               bool
+	QdrantBuiltinHTTPPort       int
+	QdrantBuiltinGRPCPort       int
+	HybridVectorWeight          float64
+	HybridKeywordWeight         float64
+	HybridRRFK                  int
+	DuplicateScanEnabled        bool
+	DuplicateThreshold          float64
 }
 
 // VersionSettings 版本信息设置
@@ -331,6 +568,95 @@ type AnnouncementSettings struct {
 	AnnouncementAutoShowDelay int // 秒
 }
 
+// ShareSettings 分享功能配置
+type ShareSettings struct {
+	ArchiveMaxFiles   int // 单次打包下载的最大文件数
+	ArchiveMaxSizeMB  int // 单次打包下载的最大总大小
+	ArchiveTTLMinutes int // 打包文件保留时间
+
+	ArchiveMaxActivePerShare int // 同一分享同时排队或打包中的任务数上限(0表示不限制)
+	ArchiveMaxActivePerIP    int // 同一IP同时排队或打包中的任务数上限(0表示不限制)
+	ArchiveDiskBudgetMB      int // 单个实例打包临时文件占用的磁盘上限(0表示不限制)
+
+	ExpiryReminderHours int  // 过期前多少小时提醒分享者
+	ExpiryReminderEmail bool // 过期提醒是否同时发送邮件
+	ExtendDays          int  // 一键续期延长的天数
+
+	PasswordMaxAttempts    int // 同一IP连续输错分享密码的次数上限(0表示不限制)
+	PasswordLockoutMinutes int // 超过上限后的锁定时长
+
+	EmailCodeIPHourly     int // 同一IP每小时可发送的分享访问验证码数(0表示不限制)
+	EmailCodeShareHourly  int // 同一分享每小时可发送的访问验证码数(0表示不限制)
+	EmailCodeGlobalHourly int // 全站每小时可发送的分享访问验证码数(0表示不限制)
+}
+
+// CORSSettings 跨域配置，api 分组为普通接口，files 分组为公开文件访问路由
+type CORSSettings struct {
+	Enabled bool
+
+	APIAllowedOrigins   string // 逗号分隔，支持 * 与 https://*.example.com 形式的通配
+	APIAllowCredentials bool
+	APIAllowedMethods   string
+	APIMaxAge           int // 预检结果缓存秒数
+
+	FilesAllowedOrigins   string
+	FilesAllowCredentials bool
+	FilesAllowedMethods   string
+	FilesMaxAge           int
+}
+
+// RateLimitSettings 接口限流配置，每分钟补充的令牌数为 0 表示不限制
+type RateLimitSettings struct {
+	Enabled bool
+
+	GlobalPerMinute    int
+	GlobalBurst        int
+	AuthPerMinute      int
+	AuthBurst          int
+	SearchPerMinute    int
+	SearchBurst        int
+	RandomAPIPerMinute int
+	RandomAPIBurst     int
+}
+
+// WebDAVSettings WebDAV 访问配置
+type WebDAVSettings struct {
+	Enabled           bool
+	AllowPasswordAuth bool // 是否允许使用账号密码认证，关闭后只能使用访问令牌
+}
+
+// GraphQLSettings GraphQL 接口配置
+type GraphQLSettings struct {
+	Enabled   bool
+	MaxDepth  int // 查询最大嵌套深度
+	MaxFields int // 单次查询最多返回的字段数
+}
+
+// JobsSettings 后台任务重试策略
+type JobsSettings struct {
+	AITaggingMaxAttempts      int
+	AITaggingRetryBaseSeconds int
+	AITaggingRetryMaxSeconds  int
+
+	VectorMaxAttempts      int
+	VectorRetryBaseSeconds int
+	VectorRetryMaxSeconds  int
+
+	ThumbnailMaxAttempts      int
+	ThumbnailRetryBaseSeconds int
+	ThumbnailRetryMaxSeconds  int
+
+	CleanupMaxAttempts      int
+	CleanupRetryBaseSeconds int
+	CleanupRetryMaxSeconds  int
+}
+
+// WebhookSettings Webhook 投递配置
+type WebhookSettings struct {
+	MaxAttempts           int // 投递失败后的最大尝试次数
+	DeliveryRetentionDays int // 投递记录保留天数
+}
+
 // CategoryTemplateConfig 分类模板配置
 type CategoryTemplateConfig struct {
 	Name        string
@@ -0,0 +1,406 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"pixelpunk/internal/controllers/setting/dto"
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// platformSettings 跨域、限流、WebDAV、GraphQL、后台任务与 Webhook 设置，新安装与已有实例的补齐迁移共用
+func platformSettings() []dto.SettingCreateDTO {
+	d := DefaultSettings
+	return []dto.SettingCreateDTO{
+		// 跨域，api 分组为普通接口，files 分组为公开文件访问路由
+		{
+			Key:         "cors_enabled",
+			Value:       d.CORS.Enabled,
+			Type:        "boolean",
+			Group:       "cors",
+			Description: "是否返回跨域响应头",
+			IsSystem:    true,
+		},
+		{
+			Key:         "api_allowed_origins",
+			Value:       d.CORS.APIAllowedOrigins,
+			Type:        "string",
+			Group:       "cors",
+			Description: "接口允许的跨域来源，逗号分隔，支持 * 与 https://*.example.com",
+			IsSystem:    true,
+		},
+		{
+			Key:         "api_allow_credentials",
+			Value:       d.CORS.APIAllowCredentials,
+			Type:        "boolean",
+			Group:       "cors",
+			Description: "接口是否允许携带凭证(仅对本站与明确列出的来源生效)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "api_allowed_methods",
+			Value:       d.CORS.APIAllowedMethods,
+			Type:        "string",
+			Group:       "cors",
+			Description: "接口允许的跨域请求方法",
+			IsSystem:    true,
+		},
+		{
+			Key:         "api_max_age",
+			Value:       d.CORS.APIMaxAge,
+			Type:        "number",
+			Group:       "cors",
+			Description: "接口预检结果缓存秒数(0表示不返回)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "files_allowed_origins",
+			Value:       d.CORS.FilesAllowedOrigins,
+			Type:        "string",
+			Group:       "cors",
+			Description: "公开文件路由允许的跨域来源，逗号分隔",
+			IsSystem:    true,
+		},
+		{
+			Key:         "files_allow_credentials",
+			Value:       d.CORS.FilesAllowCredentials,
+			Type:        "boolean",
+			Group:       "cors",
+			Description: "公开文件路由是否允许携带凭证",
+			IsSystem:    true,
+		},
+		{
+			Key:         "files_allowed_methods",
+			Value:       d.CORS.FilesAllowedMethods,
+			Type:        "string",
+			Group:       "cors",
+			Description: "公开文件路由允许的跨域请求方法",
+			IsSystem:    true,
+		},
+		{
+			Key:         "files_max_age",
+			Value:       d.CORS.FilesMaxAge,
+			Type:        "number",
+			Group:       "cors",
+			Description: "公开文件路由预检结果缓存秒数(0表示不返回)",
+			IsSystem:    true,
+		},
+
+		// 接口限流，每分钟补充的令牌数为 0 表示不限制
+		{
+			Key:         "rate_limit_enabled",
+			Value:       d.RateLimit.Enabled,
+			Type:        "boolean",
+			Group:       "rate_limit",
+			Description: "是否启用接口限流",
+			IsSystem:    true,
+		},
+		{
+			Key:         "global_per_minute",
+			Value:       d.RateLimit.GlobalPerMinute,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "全局策略每分钟补充的令牌数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "global_burst",
+			Value:       d.RateLimit.GlobalBurst,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "全局策略桶容量",
+			IsSystem:    true,
+		},
+		{
+			Key:         "auth_per_minute",
+			Value:       d.RateLimit.AuthPerMinute,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "认证接口每分钟补充的令牌数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "auth_burst",
+			Value:       d.RateLimit.AuthBurst,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "认证接口桶容量",
+			IsSystem:    true,
+		},
+		{
+			Key:         "search_per_minute",
+			Value:       d.RateLimit.SearchPerMinute,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "搜索接口每分钟补充的令牌数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "search_burst",
+			Value:       d.RateLimit.SearchBurst,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "搜索接口桶容量",
+			IsSystem:    true,
+		},
+		{
+			Key:         "random_api_per_minute",
+			Value:       d.RateLimit.RandomAPIPerMinute,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "随机图片接口每分钟补充的令牌数(0表示不限制)",
+			IsSystem:    true,
+		},
+		{
+			Key:         "random_api_burst",
+			Value:       d.RateLimit.RandomAPIBurst,
+			Type:        "number",
+			Group:       "rate_limit",
+			Description: "随机图片接口桶容量",
+			IsSystem:    true,
+		},
+
+		// WebDAV
+		{
+			Key:         "webdav_enabled",
+			Value:       d.WebDAV.Enabled,
+			Type:        "boolean",
+			Group:       "webdav",
+			Description: "是否开放 WebDAV 访问",
+			IsSystem:    true,
+		},
+		{
+			Key:         "webdav_allow_password_auth",
+			Value:       d.WebDAV.AllowPasswordAuth,
+			Type:        "boolean",
+			Group:       "webdav",
+			Description: "WebDAV 是否允许账号密码认证(关闭后只能使用访问令牌)",
+			IsSystem:    true,
+		},
+
+		// GraphQL
+		{
+			Key:         "graphql_enabled",
+			Value:       d.GraphQL.Enabled,
+			Type:        "boolean",
+			Group:       "graphql",
+			Description: "是否开放 GraphQL 接口",
+			IsSystem:    true,
+		},
+		{
+			Key:         "graphql_max_depth",
+			Value:       d.GraphQL.MaxDepth,
+			Type:        "number",
+			Group:       "graphql",
+			Description: "GraphQL 查询最大嵌套深度",
+			IsSystem:    true,
+		},
+		{
+			Key:         "graphql_max_fields",
+			Value:       d.GraphQL.MaxFields,
+			Type:        "number",
+			Group:       "graphql",
+			Description: "GraphQL 单次查询最多返回的字段数",
+			IsSystem:    true,
+		},
+
+		// 后台任务重试策略
+		{
+			Key:         "ai_tagging_max_attempts",
+			Value:       d.Jobs.AITaggingMaxAttempts,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "AI打标任务最大尝试次数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ai_tagging_retry_base_seconds",
+			Value:       d.Jobs.AITaggingRetryBaseSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "AI打标任务首次重试延迟秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "ai_tagging_retry_max_seconds",
+			Value:       d.Jobs.AITaggingRetryMaxSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "AI打标任务重试延迟上限秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "vector_max_attempts",
+			Value:       d.Jobs.VectorMaxAttempts,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "向量生成任务最大尝试次数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "vector_retry_base_seconds",
+			Value:       d.Jobs.VectorRetryBaseSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "向量生成任务首次重试延迟秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "vector_retry_max_seconds",
+			Value:       d.Jobs.VectorRetryMaxSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "向量生成任务重试延迟上限秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "thumbnail_max_attempts",
+			Value:       d.Jobs.ThumbnailMaxAttempts,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "缩略图重新生成任务最大尝试次数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "thumbnail_retry_base_seconds",
+			Value:       d.Jobs.ThumbnailRetryBaseSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "缩略图重新生成任务首次重试延迟秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "thumbnail_retry_max_seconds",
+			Value:       d.Jobs.ThumbnailRetryMaxSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "缩略图重新生成任务重试延迟上限秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "cleanup_max_attempts",
+			Value:       d.Jobs.CleanupMaxAttempts,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "定时清理任务最大尝试次数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "cleanup_retry_base_seconds",
+			Value:       d.Jobs.CleanupRetryBaseSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "定时清理任务首次重试延迟秒数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "cleanup_retry_max_seconds",
+			Value:       d.Jobs.CleanupRetryMaxSeconds,
+			Type:        "number",
+			Group:       "jobs",
+			Description: "定时清理任务重试延迟上限秒数",
+			IsSystem:    true,
+		},
+
+		// Webhook
+		{
+			Key:         "webhook_max_attempts",
+			Value:       d.Webhook.MaxAttempts,
+			Type:        "number",
+			Group:       "webhook",
+			Description: "Webhook 投递最大尝试次数",
+			IsSystem:    true,
+		},
+		{
+			Key:         "webhook_delivery_retention_days",
+			Value:       d.Webhook.DeliveryRetentionDays,
+			Type:        "number",
+			Group:       "webhook",
+			Description: "Webhook 投递记录保留天数",
+			IsSystem:    true,
+		},
+	}
+}
+
+// legacyPlatformSettingKeys 早期版本在各分组中使用的无前缀键名，键名全局唯一导致不同分组互相冲突，现改为带分组前缀
+var legacyPlatformSettingKeys = []struct {
+	group  string
+	oldKey string
+	newKey string
+}{
+	{"cors", "enabled", "cors_enabled"},
+	{"rate_limit", "enabled", "rate_limit_enabled"},
+	{"webdav", "enabled", "webdav_enabled"},
+	{"webdav", "allow_password_auth", "webdav_allow_password_auth"},
+	{"graphql", "enabled", "graphql_enabled"},
+	{"graphql", "max_depth", "graphql_max_depth"},
+	{"graphql", "max_fields", "graphql_max_fields"},
+	{"webhook", "max_attempts", "webhook_max_attempts"},
+	{"webhook", "delivery_retention_days", "webhook_delivery_retention_days"},
+}
+
+/* SeedPlatformSettings 为已有实例补齐 platformSettings 中缺失的设置：旧键名中保存的值迁移到新键名，
+ * 其余缺失项按默认值创建，已存在的设置保持不变 */
+func SeedPlatformSettings(db *gorm.DB) error {
+	settings := platformSettings()
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.Key
+	}
+	var existing []string
+	if err := db.Model(&models.Setting{}).Where("`key` IN ?", keys).Pluck("key", &existing).Error; err != nil {
+		return fmt.Errorf("查询已有设置失败: %v", err)
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		exists[key] = true
+	}
+
+	// 旧键名的值沿用到新键名，删除旧记录后随新设置一起创建，由设置服务刷新缓存
+	legacyValues := make(map[string]interface{})
+	for _, legacy := range legacyPlatformSettingKeys {
+		if exists[legacy.newKey] {
+			continue
+		}
+		var old models.Setting
+		if err := db.Where("`group` = ? AND `key` = ?", legacy.group, legacy.oldKey).Take(&old).Error; err != nil {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(old.Value), &value); err != nil {
+			logger.Warn("设置 %s.%s 的值无法解析，按默认值创建 %s: %v", legacy.group, legacy.oldKey, legacy.newKey, err)
+		} else {
+			legacyValues[legacy.newKey] = value
+		}
+		if err := db.Delete(&old).Error; err != nil {
+			return fmt.Errorf("删除旧设置 %s.%s 失败: %v", legacy.group, legacy.oldKey, err)
+		}
+	}
+
+	var missing []dto.SettingCreateDTO
+	for _, s := range settings {
+		if exists[s.Key] {
+			continue
+		}
+		if value, ok := legacyValues[s.Key]; ok {
+			s.Value = value
+		}
+		missing = append(missing, s)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	result, err := setting.BatchCreateSettings(&dto.BatchSettingCreateDTO{Settings: missing})
+	if err != nil {
+		return fmt.Errorf("补齐系统设置失败: %v", err)
+	}
+	logger.Infof("已补齐 %d 项系统设置", len(result.Success))
+	for _, failedItem := range result.Failed {
+		logger.Errorf("设置 %s 创建失败: %s", failedItem.Key, failedItem.Message)
+	}
+	return nil
+}
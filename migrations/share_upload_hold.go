@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"pixelpunk/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// MigrateShareUploadHold 将收集型分享中尚未审核、此前复用 pending_review 状态的访客上传改为分享者审核状态，
+// 使其退出管理员审核队列与自动通过任务
+func MigrateShareUploadHold(db *gorm.DB) error {
+	if !db.Migrator().HasTable("share_upload") {
+		return nil
+	}
+	return db.Table("file").
+		Where("status = ? AND id IN (?)", "pending_review",
+			db.Table("share_upload").Select("file_id").Where("status = ?", common.ShareUploadStatusPending)).
+		Update("status", common.FileStatusShareHold).Error
+}
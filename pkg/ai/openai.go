@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"pixelpunk/pkg/ai/prompts"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 	"strings"
 	"time"
 )
@@ -53,7 +54,7 @@ func NewOpenAIProvider(config *Config) *OpenAIProvider {
 
 	client := &http.Client{
 		Timeout:   config.Timeout,
-		Transport: transport,
+		Transport: tracing.NewTransport("ai.openai", transport),
 	}
 
 	return &OpenAIProvider{
@@ -200,10 +201,11 @@ func (p *OpenAIProvider) CategorizeFile(ctx context.Context, req *FileCategoriza
 // parseCategorizationResponse 解析分类响应
 func (p *OpenAIProvider) parseCategorizationResponse(content string, usage *TokenUsage) (*FileCategorizationResponse, error) {
 	var result struct {
-		Success             bool   `json:"success"`
-		CategoryID          uint   `json:"category_id"`
-		CategoryName        string `json:"category_name"`
-		CategoryDescription string `json:"category_description"`
+		Success             bool     `json:"success"`
+		CategoryID          uint     `json:"category_id"`
+		CategoryName        string   `json:"category_name"`
+		CategoryDescription string   `json:"category_description"`
+		Confidence          *float64 `json:"confidence"`
 	}
 
 	cleanContent := CleanJSON(ExtractJSONFromText(content))
@@ -227,11 +229,18 @@ func (p *OpenAIProvider) parseCategorizationResponse(content string, usage *Toke
 
 	// CategoryID=0 表示AI建议创建新分类，这是正常情况
 
+	// 兼容未返回置信度的模型：视为完全确定
+	confidence := 1.0
+	if result.Confidence != nil && *result.Confidence >= 0 && *result.Confidence <= 1 {
+		confidence = *result.Confidence
+	}
+
 	return &FileCategorizationResponse{
 		Success:             true,
 		CategoryID:          result.CategoryID,
 		CategoryName:        result.CategoryName,
 		CategoryDescription: result.CategoryDescription,
+		Confidence:          confidence,
 		Usage:               usage,
 	}, nil
 }
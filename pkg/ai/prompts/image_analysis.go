@@ -81,6 +81,10 @@ func GetImageAnalysisSystemPrompt() string {
     "风格关键词"    // 艺术风格、情感氛围、视觉特点
   ],
   "isRecommended": false, // 是否值得推荐作为对外的展示，希望要求严格一点，好的图片才值得推荐，只有达到壁纸级别的图片才适合被推，如果质量尺寸等不符合壁纸要求不要推荐
+  "recommend_confidence": 0.0, // 推荐判断的置信度，0-1范围
+  "tag_confidence": {
+    "标签1": 0.95            // 每个标签的置信度，0-1范围，键与tags中的标签一一对应
+  },
   "basic_info": {
     "width": 1920,
     "height": 1080,
@@ -109,7 +109,8 @@ func GetImageCategorizationPrompt(categories []CategoryInfo) string {
   "success": true,
   "category_id": <选中的分类ID>,
   "category_name": "<选中的分类名称>",
-  "category_description": "<分类的简洁描述，50字以内，描述此分类的特征和用途>"
+  "category_description": "<分类的简洁描述，50字以内，描述此分类的特征和用途>",
+  "confidence": <分类置信度，0-1范围，越确定越接近1>
 }
 
 ⚠️ **关键要求**：
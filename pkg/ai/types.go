@@ -102,6 +102,7 @@ type FileCategorizationResponse struct {
 	CategoryID          uint        `json:"category_id,omitempty"`
 	CategoryName        string      `json:"category_name,omitempty"`
 	CategoryDescription string      `json:"category_description,omitempty"`
+	Confidence          float64     `json:"confidence"` // 分类置信度(0-1)，AI未返回时视为1
 	ErrMsg              string      `json:"errMsg,omitempty"`
 	Usage               *TokenUsage `json:"usage,omitempty"`
 }
@@ -1,11 +1,14 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"pixelpunk/pkg/config"
 	"pixelpunk/pkg/logger"
 	"strings"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // Cache 定义缓存接口
@@ -117,6 +120,26 @@ func ClearNamespaceCache() error {
 		pattern := fmt.Sprintf("%s:*", namespace)
 		ctx := redisCache.ctx
 
+		if redisCache.local != nil {
+			redisCache.local.invalidate(ctx, invalidateOpDel, invalidateAllKey)
+		}
+
+		// 集群模式下键分布在各主节点，需逐个节点查找，且跨槽位的键不能一次删除
+		if cluster, ok := redisCache.client.(*redis.ClusterClient); ok {
+			return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+				keys, err := node.Keys(ctx, pattern).Result()
+				if err != nil {
+					return err
+				}
+				for _, key := range keys {
+					if err := node.Del(ctx, key).Err(); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+
 		keys, err := redisCache.client.Keys(ctx, pattern).Result()
 		if err != nil {
 			return err
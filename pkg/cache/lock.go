@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 仅当锁仍归当前持有者时才续期或释放，避免误删其他实例在锁过期后获取的新锁
+var (
+	lockRenewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	lockReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	if tonumber(ARGV[2]) > 0 then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Lock Redis 分布式锁，多实例部署时保证同一任务同一时间只有一个实例执行。
+// 持有期间后台按租期的三分之一自动续期，实例崩溃后锁在租期到达后自动释放
+type Lock struct {
+	client redis.UniversalClient
+	key    string
+	token  string
+	ttl    time.Duration
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+// InstanceID 当前进程的实例标识，多实例部署时用于区分消息来源
+func InstanceID() string {
+	return instanceID
+}
+
+// TryLock 尝试获取指定名称的锁，未启用 Redis 时视为单实例部署，直接返回成功与空锁。
+// Redis 出错时返回失败，由调用方跳过本次执行，避免多个实例同时执行
+func TryLock(name string, ttl time.Duration) (*Lock, bool) {
+	client := GetRedisClient()
+	if !IsRedisEnabled() || client == nil {
+		return nil, true
+	}
+
+	l := &Lock{
+		client: client,
+		key:    buildKey("lock:" + name),
+		token:  instanceID + ":" + newInstanceID(),
+		ttl:    ttl,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	ok, err := client.SetNX(GetRedisContext(), l.key, l.token, ttl).Result()
+	if err != nil {
+		logger.Warn("获取分布式锁 %s 失败: %v", name, err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	go l.renew()
+	return l, true
+}
+
+func (l *Lock) renew() {
+	defer close(l.done)
+
+	interval := l.ttl / 3
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n, err := lockRenewScript.Run(GetRedisContext(), l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+			if err != nil {
+				logger.Warn("分布式锁 %s 续期失败: %v", l.key, err)
+				continue
+			}
+			if n == 0 {
+				logger.Warn("分布式锁 %s 已失效，可能已被其他实例获取", l.key)
+				return
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Unlock 立即释放锁，空锁与重复调用均为空操作
+func (l *Lock) Unlock() {
+	l.UnlockAfter(0)
+}
+
+// UnlockAfter 停止续期并在 hold 后释放锁。定时任务执行很快时保留一段时间，
+// 防止时钟略慢的实例在锁释放后才触发同一轮调度而重复执行
+func (l *Lock) UnlockAfter(hold time.Duration) {
+	if l == nil {
+		return
+	}
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		if err := lockReleaseScript.Run(GetRedisContext(), l.client, []string{l.key}, l.token, hold.Milliseconds()).Err(); err != nil {
+			logger.Warn("释放分布式锁 %s 失败: %v", l.key, err)
+		}
+	})
+}
@@ -5,17 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"pixelpunk/pkg/config"
+	"pixelpunk/pkg/logger"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// Redis 部署模式
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
 var redisCache *RedisCache
 
 // RedisCache Redis缓存实现
 type RedisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 	ctx    context.Context
+	mode   string
+	local  *tieredLayer // 热点键的进程内缓存，未启用时为 nil
 }
 
 // InitRedis 初始化Redis缓存
@@ -23,25 +34,21 @@ func InitRedis() error {
 	cfg := config.GetConfig().Redis
 
 	// 如果未配置Redis主机，则不使用Redis
-	if cfg.Host == "" {
+	if cfg.Host == "" && len(cfg.Addrs) == 0 {
 		return errors.New("Redis未配置")
 	}
 
-	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
-	options := &redis.Options{
-		Addr:     addr,
-		Password: cfg.Password,
-		DB:       cfg.DB,
+	client, mode, err := newRedisClient(cfg)
+	if err != nil {
+		return err
 	}
 
-	client := redis.NewClient(options)
-
 	ctx := context.Background()
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	_, err := client.Ping(ctxWithTimeout).Result()
+	_, err = client.Ping(ctxWithTimeout).Result()
 	if err != nil {
 		client.Close() // 关闭失败的连接
 		return err
@@ -50,6 +57,10 @@ func InitRedis() error {
 	redisCache = &RedisCache{
 		client: client,
 		ctx:    ctx,
+		mode:   mode,
+	}
+	if size := localCacheSize(cfg.LocalCacheSize); size > 0 {
+		redisCache.local = newTieredLayer(ctx, client, size)
 	}
 
 	defaultCache = redisCache
@@ -57,6 +68,49 @@ func InitRedis() error {
 	return nil
 }
 
+// newRedisClient 按部署模式创建客户端：单机、哨兵（主节点切换后自动重连）或集群
+func newRedisClient(cfg config.RedisConfig) (redis.UniversalClient, string, error) {
+	addrs := cfg.Addrs
+	if len(addrs) == 0 {
+		port := cfg.Port
+		if port <= 0 {
+			port = 6379
+		}
+		addrs = []string{fmt.Sprintf("%s:%d", cfg.Host, port)}
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(cfg.Mode))
+	switch mode {
+	case "", RedisModeStandalone:
+		return redis.NewClient(&redis.Options{
+			Addr:     addrs[0],
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}), RedisModeStandalone, nil
+	case RedisModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, mode, errors.New("Redis哨兵模式需要配置 master_name")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+		}), mode, nil
+	case RedisModeCluster:
+		if cfg.DB != 0 {
+			logger.Warn("Redis集群模式不支持选择数据库，已忽略 db=%d", cfg.DB)
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    addrs,
+			Password: cfg.Password,
+		}), mode, nil
+	default:
+		return nil, mode, fmt.Errorf("不支持的Redis模式: %s", cfg.Mode)
+	}
+}
+
 // TestRedisConnection 测试Redis连接（不初始化全局客户端）
 func TestRedisConnection(host string, port int, password string, db int) error {
 	if host == "" {
@@ -89,21 +143,55 @@ func TestRedisConnection(host string, port int, password string, db int) error {
 
 // Set 设置缓存
 func (c *RedisCache) Set(key string, value string, expiration time.Duration) error {
-	return c.client.Set(c.ctx, key, value, expiration).Err()
+	if err := c.client.Set(c.ctx, key, value, expiration).Err(); err != nil {
+		return err
+	}
+	if c.local != nil && c.local.isLocal(key) {
+		c.local.invalidate(c.ctx, invalidateOpSet, key)
+		c.local.store(key, value, expiration)
+	}
+	return nil
 }
 
-// Get 获取缓存
+// Get 获取缓存，热点键优先读取本地副本
 func (c *RedisCache) Get(key string) (string, error) {
-	return c.client.Get(c.ctx, key).Result()
+	if c.local == nil || !c.local.isLocal(key) {
+		return c.client.Get(c.ctx, key).Result()
+	}
+	if value, ok := c.local.get(key); ok {
+		return value, nil
+	}
+
+	// 同时取剩余有效期，本地副本不会比 Redis 中的键活得更久
+	pipe := c.client.Pipeline()
+	getCmd := pipe.Get(c.ctx, key)
+	ttlCmd := pipe.PTTL(c.ctx, key)
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return "", err
+	}
+	value := getCmd.Val()
+	c.local.store(key, value, ttlCmd.Val())
+	return value, nil
 }
 
 // Del 删除缓存
 func (c *RedisCache) Del(key string) error {
-	return c.client.Del(c.ctx, key).Err()
+	if err := c.client.Del(c.ctx, key).Err(); err != nil {
+		return err
+	}
+	if c.local != nil && c.local.isLocal(key) {
+		c.local.invalidate(c.ctx, invalidateOpDel, key)
+	}
+	return nil
 }
 
 // Exists 检查键是否存在
 func (c *RedisCache) Exists(key string) bool {
+	if c.local != nil && c.local.isLocal(key) {
+		if _, ok := c.local.get(key); ok {
+			return true
+		}
+	}
 	result, _ := c.client.Exists(c.ctx, key).Result()
 	return result > 0
 }
@@ -115,24 +203,62 @@ func (c *RedisCache) TTL(key string) (time.Duration, error) {
 
 // Expire 设置过期时间
 func (c *RedisCache) Expire(key string, expiration time.Duration) error {
-	return c.client.Expire(c.ctx, key, expiration).Err()
+	if err := c.client.Expire(c.ctx, key, expiration).Err(); err != nil {
+		return err
+	}
+	if c.local != nil && c.local.isLocal(key) {
+		c.local.invalidate(c.ctx, invalidateOpSet, key)
+	}
+	return nil
 }
 
 // Close 关闭Redis连接
 func (c *RedisCache) Close() error {
+	if c.local != nil {
+		_ = c.local.close()
+	}
 	if c.client != nil {
 		return c.client.Close()
 	}
 	return nil
 }
 
+// localCacheSize 本地缓存容量，0 使用默认值，负数关闭本地缓存
+func localCacheSize(size int) int {
+	if size == 0 {
+		return defaultLocalCacheSize
+	}
+	return size
+}
+
 // RedisAvailable 检查Redis是否可用
 func RedisAvailable() bool {
 	return redisCache != nil && redisCache.client != nil
 }
 
+// GetRedisMode 当前Redis部署模式，未启用时返回空字符串
+func GetRedisMode() string {
+	if !RedisAvailable() {
+		return ""
+	}
+	return redisCache.mode
+}
+
+// IsRedisCluster 是否为集群模式
+func IsRedisCluster() bool {
+	return GetRedisMode() == RedisModeCluster
+}
+
+// HashTag 集群模式下为键前缀加上哈希标签，使同一前缀的键落在同一槽位，以支持多键脚本与事务
+func HashTag(prefix string) string {
+	if IsRedisCluster() {
+		return "{" + prefix + "}"
+	}
+	return prefix
+}
+
 // 性能监控和指标收集使用
-func GetRedisClient() *redis.Client {
+func GetRedisClient() redis.UniversalClient {
 	if !RedisAvailable() {
 		return nil
 	}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pixelpunk/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLocalCacheSize = 10000
+	// localCacheMaxTTL 本地副本最长有效期，即使失效通知丢失，数据最多延迟这么久
+	localCacheMaxTTL = time.Minute
+
+	invalidateChannel = "cache:invalidate"
+	invalidateOpSet   = "set"
+	invalidateOpDel   = "del"
+	invalidateAllKey  = "*"
+)
+
+// InvalidateHandler 其他实例删除缓存键时的回调，key 不带命名空间
+type InvalidateHandler func(key string)
+
+// LocalCacheStats 本地缓存命中统计
+type LocalCacheStats struct {
+	Enabled bool  `json:"enabled"`
+	Size    int   `json:"size"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+var (
+	tieredMu           sync.RWMutex
+	localPrefixes      []string
+	invalidateHandlers = make(map[string][]InvalidateHandler)
+	instanceID         = newInstanceID()
+)
+
+// RegisterLocalPrefix 将指定前缀的键同时缓存在进程内 LRU，适用于读多写少的热点数据，仅在启用 Redis 时生效
+func RegisterLocalPrefix(prefixes ...string) {
+	tieredMu.Lock()
+	defer tieredMu.Unlock()
+	localPrefixes = append(localPrefixes, prefixes...)
+}
+
+// OnInvalidate 注册其他实例删除指定前缀缓存键时的回调，用于同步进程内状态
+func OnInvalidate(prefix string, handler InvalidateHandler) {
+	tieredMu.Lock()
+	defer tieredMu.Unlock()
+	invalidateHandlers[prefix] = append(invalidateHandlers[prefix], handler)
+}
+
+// GetLocalCacheStats 获取本地缓存命中统计
+func GetLocalCacheStats() LocalCacheStats {
+	if redisCache == nil || redisCache.local == nil {
+		return LocalCacheStats{}
+	}
+	t := redisCache.local
+	return LocalCacheStats{
+		Enabled: true,
+		Size:    t.lru.len(),
+		Hits:    t.hits.Load(),
+		Misses:  t.misses.Load(),
+	}
+}
+
+// tieredLayer Redis 之前的进程内缓存层，写入与删除通过发布订阅通知其他实例失效
+type tieredLayer struct {
+	lru     *lruCache
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+func newTieredLayer(ctx context.Context, client redis.UniversalClient, size int) *tieredLayer {
+	t := &tieredLayer{
+		lru:     newLRUCache(size),
+		client:  client,
+		channel: buildKey(invalidateChannel),
+	}
+	t.pubsub = client.Subscribe(ctx, t.channel)
+	go t.listen()
+	return t
+}
+
+// isLocal 判断带命名空间的键是否属于本地缓存的前缀
+func (t *tieredLayer) isLocal(key string) bool {
+	tieredMu.RLock()
+	defer tieredMu.RUnlock()
+	for _, prefix := range localPrefixes {
+		if strings.HasPrefix(key, buildKey(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tieredLayer) get(key string) (string, bool) {
+	value, ok := t.lru.get(key)
+	if ok {
+		t.hits.Add(1)
+	} else {
+		t.misses.Add(1)
+	}
+	return value, ok
+}
+
+// store 写入本地副本，有效期不超过 Redis 中的剩余时间
+func (t *tieredLayer) store(key, value string, expiration time.Duration) {
+	ttl := localCacheMaxTTL
+	if expiration > 0 && expiration < ttl {
+		ttl = expiration
+	}
+	t.lru.set(key, value, ttl)
+}
+
+// invalidate 删除本地副本并通知其他实例
+func (t *tieredLayer) invalidate(ctx context.Context, op, key string) {
+	if key == invalidateAllKey {
+		t.lru.clear()
+	} else {
+		t.lru.del(key)
+	}
+	if err := t.client.Publish(ctx, t.channel, instanceID+"|"+op+"|"+key).Err(); err != nil {
+		logger.Warn("发布缓存失效通知失败: %v", err)
+	}
+}
+
+// listen 处理其他实例的失效通知，连接断开期间可能丢失通知，由本地有效期兜底
+func (t *tieredLayer) listen() {
+	for msg := range t.pubsub.Channel() {
+		parts := strings.SplitN(msg.Payload, "|", 3)
+		if len(parts) != 3 || parts[0] == instanceID {
+			continue
+		}
+		op, key := parts[1], parts[2]
+
+		if key == invalidateAllKey {
+			t.lru.clear()
+		} else {
+			t.lru.del(key)
+		}
+		if op == invalidateOpDel && key != invalidateAllKey {
+			notifyInvalidate(strings.TrimPrefix(key, buildKey("")))
+		}
+	}
+}
+
+func (t *tieredLayer) close() error {
+	return t.pubsub.Close()
+}
+
+func notifyInvalidate(key string) {
+	tieredMu.RLock()
+	var handlers []InvalidateHandler
+	for prefix, hs := range invalidateHandlers {
+		if strings.HasPrefix(key, prefix) {
+			handlers = append(handlers, hs...)
+		}
+	}
+	tieredMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(key)
+	}
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
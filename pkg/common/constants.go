@@ -27,6 +27,7 @@ const (
 	UserRoleSuperAdmin = 1
 	UserRoleAdmin      = 2
 	UserRoleUser       = 3
+	UserRoleReviewer   = 4 // 内容审核员，只能访问审核队列和审核记录
 )
 
 const (
@@ -62,6 +63,7 @@ const (
 	VectorStatusFailed     = "failed"
 	VectorStatusReset      = "reset"
 	VectorStatusStale      = "stale"
+	VectorStatusIgnored    = "ignored" // 用户或文件夹关闭了AI处理
 )
 
 const (
@@ -85,6 +87,25 @@ const (
 	ShareItemTypeFile   = "file"
 )
 
+const (
+	ShareTypeView    = "view"    // 普通分享，仅供浏览下载
+	ShareTypeCollect = "collect" // 收集型分享，访客可上传到分享的文件夹
+)
+
+const (
+	ShareAccessModePublic = "public" // 持有链接（及密码）即可访问
+	ShareAccessModeUsers  = "users"  // 仅指定的注册用户登录后可访问
+)
+
+const (
+	ShareUploadStatusPending  = "pending"
+	ShareUploadStatusApproved = "approved"
+	ShareUploadStatusRejected = "rejected"
+)
+
+// FileStatusShareHold 收集型分享中等待分享者审核的访客上传的文件状态，不进入管理员审核队列与AI处理
+const FileStatusShareHold = "share_pending"
+
 const (
 	MessageStatusUnread  = 1
 	MessageStatusRead    = 2
@@ -100,14 +121,18 @@ const (
 const (
 	MessageTypeSystemMaintenance = "system.maintenance"
 	MessageTypeSystemUpdate      = "system.update"
+	MessageTypeSystemBroadcast   = "system.broadcast"
 
 	MessageTypeAccountRegister         = "account.register"
 	MessageTypeAccountStorageGranted   = "account.storage_granted"
 	MessageTypeAccountBandwidthGranted = "account.bandwidth_granted"
+	MessageTypeAccountQuotaGranted     = "account.quota_granted"
 
 	MessageTypeContentReviewPending  = "content.review_pending"
 	MessageTypeContentReviewApproved = "content.review_approved"
 	MessageTypeContentReviewRejected = "content.review_rejected"
+	MessageTypeContentAppealApproved = "content.appeal_approved"
+	MessageTypeContentAppealDenied   = "content.appeal_denied"
 
 	MessageTypeFileDeletedByAdmin      = "file.deleted_by_admin"
 	MessageTypeFileBatchDeletedByAdmin = "file.batch_deleted_by_admin"
@@ -122,6 +147,8 @@ const (
 
 	MessageTypeSecurityLoginAlert      = "security.login_alert"
 	MessageTypeSecurityPasswordChanged = "security.password_changed"
+	MessageTypeSecurityEmailChangeCode = "security.email_change_code"
+	MessageTypeSecurityEmailChanged    = "security.email_changed"
 
 	MessageTypeAPIKeyCreated     = "apikey.created"
 	MessageTypeAPIKeyDeleted     = "apikey.deleted"
@@ -135,6 +162,7 @@ const (
 	MessageTypeRandomAPIEnabled  = "random_api.enabled"
 
 	MessageTypeShareExpiryWarning = "share.expiry_warning"
+	MessageTypeShareInvitation    = "share.invitation"
 )
 
 const (
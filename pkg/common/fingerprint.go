@@ -143,6 +143,22 @@ func GetSimilarFingerprints(fingerprint string) []string {
 	return similar
 }
 
+// DeviceFingerprint 根据浏览器和操作系统生成稳定的设备指纹，不含IP和时间，用于识别用户登录过的设备
+func DeviceFingerprint(ua string) string {
+	if strings.TrimSpace(ua) == "" {
+		return generateHash("unknown|login-device")
+	}
+	return generateHash(extractBrowser(ua) + "|" + extractOS(ua) + "|login-device")
+}
+
+// DescribeUserAgent 生成"浏览器 / 操作系统"形式的设备描述，用于会话和登录记录展示
+func DescribeUserAgent(ua string) string {
+	if strings.TrimSpace(ua) == "" {
+		return "Unknown"
+	}
+	return extractBrowser(ua) + " / " + extractOS(ua)
+}
+
 // extractBrowser 从User-Agent中提取浏览器信息
 func extractBrowser(ua string) string {
 	browsers := []struct {
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+/* ResolveMappedRole 按 "组=角色" 映射计算角色，匹配多个组时取权限最高者，未匹配时使用默认角色 */
+func ResolveMappedRole(rawMapping string, groups []string, defaultRole int) int {
+	mapping := parseGroupRoleMapping(rawMapping)
+	role := 0
+	for _, group := range groups {
+		for mappedGroup, mappedRole := range mapping {
+			if strings.EqualFold(strings.TrimSpace(group), mappedGroup) && rolePriority(mappedRole) > rolePriority(role) {
+				role = mappedRole
+			}
+		}
+	}
+	if role == 0 {
+		role = defaultRole
+	}
+	if rolePriority(role) == 0 {
+		role = UserRoleUser
+	}
+	return role
+}
+
+// parseGroupRoleMapping 解析 "组=角色" 格式的映射，每行或分号分隔一条
+func parseGroupRoleMapping(raw string) map[string]int {
+	mapping := make(map[string]int)
+	lines := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == ';'
+	})
+	for _, line := range lines {
+		// 组DN本身包含等号，以最后一个等号分隔
+		idx := strings.LastIndex(line, "=")
+		if idx <= 0 {
+			continue
+		}
+		role, err := strconv.Atoi(strings.TrimSpace(line[idx+1:]))
+		if err != nil || rolePriority(role) == 0 {
+			continue
+		}
+		mapping[strings.TrimSpace(line[:idx])] = role
+	}
+	return mapping
+}
+
+// rolePriority 角色权限高低，0 表示无效角色
+func rolePriority(role int) int {
+	switch role {
+	case UserRoleSuperAdmin:
+		return 4
+	case UserRoleAdmin:
+		return 3
+	case UserRoleReviewer:
+		return 2
+	case UserRoleUser:
+		return 1
+	}
+	return 0
+}
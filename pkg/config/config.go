@@ -2,12 +2,14 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"pixelpunk/pkg/logger"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,21 +21,28 @@ const (
 
 // Config 应用配置结构
 type Config struct {
-	App      AppConfig      `yaml:"app" env:"APP"`
-	Database DatabaseConfig `yaml:"database" env:"DB"`
-	Redis    RedisConfig    `yaml:"redis" env:"REDIS"`
-	Upload   UploadConfig   `yaml:"upload" env:"UPLOAD"`
-	Vector   VectorConfig   `yaml:"vector" env:"VECTOR"`
+	App       AppConfig       `yaml:"app" env:"APP"`
+	Database  DatabaseConfig  `yaml:"database" env:"DB"`
+	Redis     RedisConfig     `yaml:"redis" env:"REDIS"`
+	Upload    UploadConfig    `yaml:"upload" env:"UPLOAD"`
+	Vector    VectorConfig    `yaml:"vector" env:"VECTOR"`
+	Tracing   TracingConfig   `yaml:"tracing" env:"TRACING"`
+	TLS       TLSConfig       `yaml:"tls" env:"TLS"`
+	Bootstrap BootstrapConfig `yaml:"bootstrap" env:"BOOTSTRAP"`
 }
 
 // 更新服务配置已移除
 
 // AppConfig 应用基础配置
 type AppConfig struct {
-	Port           int      `yaml:"port" env:"PORT"`
-	Mode           string   `yaml:"mode" env:"MODE"`
-	Namespace      string   `yaml:"ns" env:"NS"`                           // 命名空间，用于缓存隔离，默认: pixelpunk
-	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"` // 信任的代理 IP 列表，支持 CIDR 格式
+	Port            int      `yaml:"port" env:"PORT"`
+	Mode            string   `yaml:"mode" env:"MODE"`
+	Namespace       string   `yaml:"ns" env:"NS"`                             // 命名空间，用于缓存隔离，默认: pixelpunk
+	TrustedProxies  []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`   // 信任的代理 IP 列表，支持 CIDR 格式
+	LogLevel        string   `yaml:"log_level" env:"LOG_LEVEL"`               // 日志级别: silent/error/warn/info，默认: info
+	AccessLog       string   `yaml:"access_log" env:"ACCESS_LOG"`             // 访问日志格式: json/text/off，默认: json
+	ShutdownTimeout int      `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"` // 优雅关闭时限（秒），默认: 10
+	HealthToken     string   `yaml:"health_token" env:"HEALTH_TOKEN"`         // 完整健康检查的探测令牌，为空时仅管理员可查看
 }
 
 // DatabaseConfig 数据库配置
@@ -45,14 +54,24 @@ type DatabaseConfig struct {
 	Password string `yaml:"password" env:"PASSWORD"`
 	Name     string `yaml:"name" env:"NAME"`
 	Path     string `yaml:"path" env:"PATH"` // SQLite数据库文件路径
+
+	// 只读副本（仅 MySQL），列表、搜索与统计查询发往副本，写操作仍在主库
+	ReplicaHosts    []string `yaml:"replica_hosts" env:"REPLICA_HOSTS"`       // 副本地址 host 或 host:port，未指定端口时使用 port
+	ReplicaUsername string   `yaml:"replica_username" env:"REPLICA_USERNAME"` // 为空时使用 username
+	ReplicaPassword string   `yaml:"replica_password" env:"REPLICA_PASSWORD"` // 为空时使用 password
 }
 
 // RedisConfig Redis配置
 type RedisConfig struct {
-	Host     string `yaml:"host" env:"HOST"`
-	Port     int    `yaml:"port" env:"PORT"`
-	Password string `yaml:"password" env:"PASSWORD"`
-	DB       int    `yaml:"db" env:"DB"`
+	Host             string   `yaml:"host" env:"HOST"`
+	Port             int      `yaml:"port" env:"PORT"`
+	Password         string   `yaml:"password" env:"PASSWORD"`
+	DB               int      `yaml:"db" env:"DB"`
+	Mode             string   `yaml:"mode" env:"MODE"`                           // 部署模式: standalone（默认）/ sentinel / cluster
+	Addrs            []string `yaml:"addrs" env:"ADDRS"`                         // 哨兵或集群节点地址列表（host:port），为空时使用 host/port
+	MasterName       string   `yaml:"master_name" env:"MASTER_NAME"`             // 哨兵模式下的主节点名称
+	SentinelPassword string   `yaml:"sentinel_password" env:"SENTINEL_PASSWORD"` // 哨兵节点密码，与数据节点密码不同时设置
+	LocalCacheSize   int      `yaml:"local_cache_size" env:"LOCAL_CACHE_SIZE"`   // 热点键进程内缓存容量，0 使用默认值 10000，负数关闭
 }
 
 // UploadConfig 上传配置
@@ -71,9 +90,62 @@ type VectorConfig struct {
 	OpenAIModel   string `yaml:"openai_model" env:"OPENAI_MODEL"`       // 向量化模型
 }
 
+// TracingConfig 链路追踪配置，通过 OTLP/HTTP 导出
+type TracingConfig struct {
+	Enabled     bool     `yaml:"enabled" env:"ENABLED"`           // 是否启用链路追踪
+	Endpoint    string   `yaml:"endpoint" env:"ENDPOINT"`         // OTLP/HTTP 地址，如 http://otel-collector:4318
+	ServiceName string   `yaml:"service_name" env:"SERVICE_NAME"` // 服务名，默认: pixelpunk
+	SampleRatio float64  `yaml:"sample_ratio" env:"SAMPLE_RATIO"` // 采样比例 0-1，默认: 1
+	Headers     []string `yaml:"headers" env:"HEADERS"`           // 导出请求附加的请求头，格式 key=value
+}
+
+// TLSConfig 内置 HTTPS，通过 ACME（Let's Encrypt）自动申请与续期证书
+type TLSConfig struct {
+	Enabled  bool     `yaml:"enabled" env:"ENABLED"`     // 是否启用内置 HTTPS
+	Domains  []string `yaml:"domains" env:"DOMAINS"`     // 申请证书的域名，只为列出的域名签发
+	Email    string   `yaml:"email" env:"EMAIL"`         // ACME 账户邮箱，用于接收证书到期提醒
+	CacheDir string   `yaml:"cache_dir" env:"CACHE_DIR"` // 证书缓存目录，默认: data/autocert
+	Port     int      `yaml:"port" env:"PORT"`           // HTTPS 监听端口，默认: 443
+	HTTPPort int      `yaml:"http_port" env:"HTTP_PORT"` // HTTP 监听端口，用于 HTTP-01 验证并重定向到 HTTPS，默认: 80，-1 表示不监听
+	Staging  bool     `yaml:"staging" env:"STAGING"`     // 使用 Let's Encrypt 测试环境，调试时避免触发频率限制
+}
+
+// BootstrapConfig 免安装向导的引导配置，用于 Docker/K8s 部署时直接完成初始化
+// 管理员仅在系统中没有管理员时创建；其余非空项每次启动写入系统设置，以配置为准
+type BootstrapConfig struct {
+	AdminUsername string `yaml:"admin_username" env:"ADMIN_USERNAME"` // 超级管理员用户名
+	AdminPassword string `yaml:"admin_password" env:"ADMIN_PASSWORD"` // 超级管理员密码
+	AdminEmail    string `yaml:"admin_email" env:"ADMIN_EMAIL"`       // 超级管理员邮箱，默认: <用户名>@pixelpunk.local
+
+	SiteBaseURL string `yaml:"site_base_url" env:"SITE_BASE_URL"` // 站点访问地址
+	JWTSecret   string `yaml:"jwt_secret" env:"JWT_SECRET"`       // JWT 签名密钥，多实例部署时需保持一致
+
+	SMTPHost        string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort        int    `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPEncryption  string `yaml:"smtp_encryption" env:"SMTP_ENCRYPTION"` // ssl/tls/starttls
+	SMTPUsername    string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword    string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	SMTPFromAddress string `yaml:"smtp_from_address" env:"SMTP_FROM_ADDRESS"`
+	SMTPFromName    string `yaml:"smtp_from_name" env:"SMTP_FROM_NAME"`
+
+	AIEnabled *bool  `yaml:"ai_enabled" env:"AI_ENABLED"` // 未设置时保持系统设置不变
+	AIAPIKey  string `yaml:"ai_api_key" env:"AI_API_KEY"`
+	AIModel   string `yaml:"ai_model" env:"AI_MODEL"`
+	AIProxy   string `yaml:"ai_proxy" env:"AI_PROXY"` // OpenAI 兼容接口地址
+
+	VectorEnabled  *bool  `yaml:"vector_enabled" env:"VECTOR_ENABLED"`   // 未设置时保持系统设置不变
+	VectorProvider string `yaml:"vector_provider" env:"VECTOR_PROVIDER"` // openai/ollama
+	VectorAPIKey   string `yaml:"vector_api_key" env:"VECTOR_API_KEY"`
+	VectorBaseURL  string `yaml:"vector_base_url" env:"VECTOR_BASE_URL"`
+	VectorModel    string `yaml:"vector_model" env:"VECTOR_MODEL"`
+	QdrantURL      string `yaml:"qdrant_url" env:"QDRANT_URL"`
+	QdrantAPIKey   string `yaml:"qdrant_api_key" env:"QDRANT_API_KEY"`
+}
+
 var (
-	config Config
-	once   sync.Once
+	// current 当前生效的配置；每次加载生成新的结构体整体替换，已发布的配置不再修改
+	current atomic.Pointer[Config]
+	once    sync.Once
 )
 
 // setDefaultConfig 设置默认配置值
@@ -88,38 +160,59 @@ func setDefaultConfig(cfg *Config) {
 	cfg.Redis.Host = "localhost"
 	cfg.Redis.Port = 6379
 	cfg.Redis.DB = 0
+
+	cfg.Tracing.ServiceName = "pixelpunk"
+	cfg.Tracing.SampleRatio = 1
+
+	cfg.TLS.CacheDir = "data/autocert"
+	cfg.TLS.Port = 443
+	cfg.TLS.HTTPPort = 80
 }
 
 // InitConfig 初始化配置
 func InitConfig() {
 	once.Do(func() {
-		setDefaultConfig(&config)
+		cfg := &Config{}
+		setDefaultConfig(cfg)
 
 		// 先从配置文件读取默认配置
-		loadConfigFromFile(&config)
+		if err := loadConfigFromFile(cfg); err != nil {
+			logger.Warn("%v，将使用默认配置和环境变量", err)
+		}
 
 		// 然后从环境变量中覆盖配置
-		loadConfigFromEnv(&config)
+		loadConfigFromEnv(cfg)
+		applyUploadDefaults(cfg)
 
 		// 打印加载配置信息（带颜色）
+		current.Store(cfg)
 	})
 }
 
-// ReloadConfig 强制重新加载配置（用于安装完成后）
-func ReloadConfig() {
-	setDefaultConfig(&config)
+// ReloadConfig 强制重新加载配置（用于安装完成后及 SIGHUP 热重载）
+// 加载到新的结构体后整体替换，避免配置文件中删除的字段保留旧值；
+// 配置文件无法读取或解析时保留当前配置并返回错误，不回退为默认值
+func ReloadConfig() error {
+	InitConfig()
+
+	fresh := &Config{}
+	setDefaultConfig(fresh)
 
 	// 先从配置文件读取默认配置
-	loadConfigFromFile(&config)
+	if err := loadConfigFromFile(fresh); err != nil {
+		return err
+	}
 
 	// 然后从环境变量中覆盖配置
-	loadConfigFromEnv(&config)
+	loadConfigFromEnv(fresh)
+	applyUploadDefaults(fresh)
 
-	// 打印加载配置信息（带颜色）
+	current.Store(fresh)
+	return nil
 }
 
 // loadConfigFromFile 从配置文件加载配置
-func loadConfigFromFile(cfg *Config) {
+func loadConfigFromFile(cfg *Config) error {
 	// 按优先级尝试读取配置文件
 	configPaths := []string{
 		"configs/config.yaml", // 新路径
@@ -137,12 +230,25 @@ func loadConfigFromFile(cfg *Config) {
 	}
 
 	if err != nil {
-		logger.Warn("无法读取配置文件: %v，将使用默认配置和环境变量", err)
-		return
+		return fmt.Errorf("无法读取配置文件: %v", err)
 	}
 
 	if err := yaml.Unmarshal(data, cfg); err != nil {
-		logger.Warn("无法解析配置文件: %v，将使用默认配置和环境变量", err)
+		return fmt.Errorf("无法解析配置文件: %v", err)
+	}
+	return nil
+}
+
+// applyUploadDefaults 补全上传限制的默认值，在发布配置前完成，读取时无需再修改
+func applyUploadDefaults(cfg *Config) {
+	if cfg.Upload.MaxFileSize == 0 {
+		cfg.Upload.MaxFileSize = 100 * 1024 * 1024 // 100MB
+	}
+
+	if len(cfg.Upload.AllowedTypes) == 0 {
+		cfg.Upload.AllowedTypes = []string{
+			"image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp", "image/bmp",
+		}
 	}
 }
 
@@ -163,6 +269,12 @@ func loadConfigFromEnv(cfg *Config) {
 	// 处理Vector配置的环境变量
 	loadEnvToStruct(envPrefix+"VECTOR_", &cfg.Vector)
 
+	// 处理Tracing配置的环境变量
+	loadEnvToStruct(envPrefix+"TRACING_", &cfg.Tracing)
+
+	// 处理Bootstrap配置的环境变量
+	loadEnvToStruct(envPrefix+"BOOTSTRAP_", &cfg.Bootstrap)
+
 }
 
 // loadEnvToStruct 加载环境变量到结构体
@@ -209,6 +321,11 @@ func setFieldValue(field reflect.Value, value string) {
 	}
 
 	switch field.Kind() {
+	case reflect.Ptr:
+		// 指针字段用于区分"未设置"与零值，例如 *bool
+		elem := reflect.New(field.Type().Elem())
+		setFieldValue(elem.Elem(), value)
+		field.Set(elem)
 	case reflect.String:
 		field.SetString(value)
 	case reflect.Slice:
@@ -275,11 +392,18 @@ func parseEnvStringSlice(value string) []string {
 	return out
 }
 
+// GetConfig 返回当前配置，调用方只读，不得修改返回的结构体
 func GetConfig() *Config {
-	if reflect.DeepEqual(config, Config{}) {
-		InitConfig()
+	if cfg := current.Load(); cfg != nil {
+		return cfg
 	}
-	return &config
+	InitConfig()
+	return current.Load()
+}
+
+// HasDatabaseEnv 是否通过环境变量指定了数据库类型，此时无需配置文件即可启动
+func HasDatabaseEnv() bool {
+	return os.Getenv(envPrefix+"DB_TYPE") != ""
 }
 
 // GetEnvString 从环境变量获取字符串值
@@ -311,17 +435,6 @@ func GetEnvBool(key string, defaultValue bool) bool {
 }
 
 func GetUploadConfig() *UploadConfig {
-	config := GetConfig()
-
-	if config.Upload.MaxFileSize == 0 {
-		config.Upload.MaxFileSize = 100 * 1024 * 1024 // 100MB
-	}
-
-	if len(config.Upload.AllowedTypes) == 0 {
-		config.Upload.AllowedTypes = []string{
-			"image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp", "image/bmp",
-		}
-	}
-
-	return &config.Upload
+	upload := GetConfig().Upload
+	return &upload
 }
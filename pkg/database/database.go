@@ -8,6 +8,7 @@ import (
 	"pixelpunk/pkg/common"
 	"pixelpunk/pkg/config"
 	log "pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 	"strings"
 	"time"
 
@@ -89,8 +90,8 @@ func InitDB() {
 	cfg := config.GetConfig().Database
 	installManager := common.GetInstallManager()
 
-	// 首先检查配置文件是否存在（支持多路径）
-	configExists := checkConfigFileExists()
+	// 首先检查配置文件是否存在（支持多路径），通过环境变量配置数据库时视为已配置
+	configExists := checkConfigFileExists() || config.HasDatabaseEnv()
 	if !configExists {
 		log.Info("配置文件不存在，进入安装模式")
 		installManager.SetInstallMode(true)
@@ -208,6 +209,9 @@ func InitDB() {
 		}
 	}
 
+	registerPlugins()
+	registerReplicas(cfg)
+
 	// 为SQLite配置连接池参数，避免并发锁定
 	if cfg.Type == "sqlite" {
 		sqlDB, err := DB.DB()
@@ -295,6 +299,13 @@ func TestDatabaseConnection(dbType, host string, port int, username, password, n
 	return nil
 }
 
+// registerPlugins 注册 GORM 插件，链路追踪插件在未开启追踪时不做任何处理
+func registerPlugins() {
+	if err := DB.Use(tracing.GormPlugin{}); err != nil {
+		log.Warn("注册数据库链路追踪插件失败: %v", err)
+	}
+}
+
 // ReconnectDatabase 重新连接数据库（用于安装完成后）
 func ReconnectDatabase() error {
 	cfg := config.GetConfig().Database
@@ -311,6 +322,9 @@ func ReconnectDatabase() error {
 		return fmt.Errorf("重新连接数据库失败: %v", err)
 	}
 
+	registerPlugins()
+	registerReplicas(cfg)
+
 	// 为SQLite配置连接池参数，避免并发锁定
 	if cfg.Type == "sqlite" {
 		sqlDB, err := DB.DB()
@@ -370,19 +384,37 @@ func autoMigrate() error {
 		&models.FileAIInfo{},
 		&models.FileTaggingLog{},
 		&models.UserAccessControl{},
+		&models.UserPasskey{},
+		&models.UserSession{},
+		&models.UserLoginFootprint{},
+		&models.InvitationCode{},
+		&models.UserGroup{},
+		&models.AdminAuditLog{},
 		&models.Share{},
 		&models.ShareItem{},
 		&models.ShareAccessLog{},
 		&models.ShareVisitorInfo{},
 		&models.ShareAccessToken{},
+		&models.ShareUpload{},
+		&models.ShareTemplate{},
+		&models.ShareRecipient{},
+		&models.ShareExclusion{},
+		&models.ShareInvitation{},
 		&models.UploadSession{},
 		&models.UploadChunk{},
 		&models.FileVector{},
 		&models.VectorProcessingLog{},
 		&models.VectorVerificationTask{},
+		&models.VectorDuplicateCandidate{},
+		&models.VectorCollection{},
+		&models.SearchFeedback{},
 		&models.ReviewLog{},
+		&models.ReviewClaim{},
+		&models.ReviewAppeal{},
+		&models.ReviewBulkJob{},
 		&models.Message{},
 		&models.MessageTemplate{},
+		&models.MessageBroadcast{},
 		&models.ActivityLog{},
 		&models.GuestUploadLimit{},
 		&models.GuestUploadLog{},
@@ -399,7 +431,16 @@ func autoMigrate() error {
 		// 队列表模型（改为自动迁移）
 		&models.AIJob{},
 		&models.VectorJob{},
+		&models.BackgroundJob{},
+		&models.AISuggestion{},
 		&models.Announcement{},
+		&models.AnnouncementTarget{},
+		&models.AnnouncementRead{},
+		&models.UserQuotaGrant{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.TelegramBinding{},
+		&models.CronRun{},
 	}
 
 	silentDB := DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
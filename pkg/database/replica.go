@@ -0,0 +1,79 @@
+package database
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/pkg/config"
+	log "pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// readReplicaResolver 只读副本解析器名称，只有通过 ReadDB 发起的查询才会使用副本
+const readReplicaResolver = "read_replica"
+
+var replicaEnabled bool
+
+/* ReadDB 返回用于列表、搜索与统计等重查询的连接，配置了只读副本时查询发往副本，写操作与事务仍在主库；未配置副本时即主库 */
+func ReadDB() *gorm.DB {
+	if DB == nil || !replicaEnabled {
+		return DB
+	}
+	return DB.Clauses(dbresolver.Use(readReplicaResolver)).Session(&gorm.Session{})
+}
+
+// registerReplicas 注册只读副本，副本连接失败时记录警告，查询继续使用主库
+func registerReplicas(cfg config.DatabaseConfig) {
+	replicaEnabled = false
+	if cfg.Type != "mysql" || len(cfg.ReplicaHosts) == 0 {
+		return
+	}
+
+	username, password := cfg.ReplicaUsername, cfg.ReplicaPassword
+	if username == "" {
+		username, password = cfg.Username, cfg.Password
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(cfg.ReplicaHosts))
+	for _, addr := range cfg.ReplicaHosts {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		host, port := addr, cfg.Port
+		if h, p, err := net.SplitHostPort(addr); err == nil {
+			if n, err := strconv.Atoi(p); err == nil {
+				host, port = h, n
+			}
+		}
+		dialector, err := getDialector(cfg.Type, host, username, password, cfg.Name, "", port)
+		if err != nil {
+			log.Warn("只读副本配置错误，查询继续使用主库: %v", err)
+			return
+		}
+		replicas = append(replicas, dialector)
+	}
+	if len(replicas) == 0 {
+		return
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, readReplicaResolver).
+		SetMaxOpenConns(150).
+		SetMaxIdleConns(50).
+		SetConnMaxLifetime(time.Hour).
+		SetConnMaxIdleTime(10 * time.Minute)
+	if err := DB.Use(resolver); err != nil {
+		log.Warn("连接只读副本失败，查询继续使用主库: %v", err)
+		return
+	}
+
+	replicaEnabled = true
+	log.Info("已启用 %d 个只读副本", len(replicas))
+}
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// ProbeSMTP 连接当前配置的 SMTP 服务器并完成 EHLO（及 STARTTLS）握手，不进行认证与发信
+func ProbeSMTP(timeout time.Duration) (string, error) {
+	mutex.RLock()
+	d := mailer
+	mutex.RUnlock()
+	if d == nil {
+		return "", errors.New("邮件服务未启用")
+	}
+
+	addr := net.JoinHostPort(d.Host, strconv.Itoa(d.Port))
+	tlsConfig := d.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: d.Host}
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if d.SSL {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return addr, translateMailError(err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, d.Host)
+	if err != nil {
+		conn.Close()
+		return addr, translateMailError(err)
+	}
+	defer client.Close()
+
+	localName := d.LocalName
+	if localName == "" {
+		localName = "localhost"
+	}
+	if err := client.Hello(localName); err != nil {
+		return addr, translateMailError(err)
+	}
+	if !d.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return addr, translateMailError(err)
+			}
+		}
+	}
+	return addr, client.Quit()
+}
@@ -96,6 +96,7 @@ const (
 	CodeShareAccessExceeded ErrorCode = 8002
 	CodeShareNotFound       ErrorCode = 8003
 	CodeSharePasswordWrong  ErrorCode = 8004
+	CodeShareNotStarted     ErrorCode = 8005
 )
 
 var errorCodeToHTTPStatus = map[ErrorCode]int{
@@ -181,6 +182,7 @@ var errorCodeToHTTPStatus = map[ErrorCode]int{
 	CodeShareAccessExceeded: 403,
 	CodeShareNotFound:       404,
 	CodeSharePasswordWrong:  401,
+	CodeShareNotStarted:     403,
 }
 
 var errorCodeToMessage = map[ErrorCode]string{
@@ -266,6 +268,7 @@ var errorCodeToMessage = map[ErrorCode]string{
 	CodeShareAccessExceeded: "分享访问次数已超限",
 	CodeShareNotFound:       "分享不存在",
 	CodeSharePasswordWrong:  "分享密码错误",
+	CodeShareNotStarted:     "分享尚未开放",
 }
 
 type Error struct {
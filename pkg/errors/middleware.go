@@ -21,13 +21,18 @@ type Response struct {
 
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := uuid.New().String()
+		// 沿用上游（网关、调用方）传入的请求ID，便于跨服务串联日志
+		requestID := c.GetHeader(logger.RequestIDHeader)
+		if !logger.ValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
 		c.Set("RequestID", requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Header(logger.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
 		defer func() {
 			if r := recover(); r != nil {
 				stackTrace := string(debug.Stack())
-				logger.Error("[PANIC] %v\nTrace: %s", r, stackTrace)
+				logger.ErrorContext(c.Request.Context(), "[PANIC] %v\nTrace: %s", r, stackTrace)
 				err := &Error{
 					Code:      CodeInternal,
 					Message:   "服务器内部错误",
@@ -36,6 +41,7 @@ func ErrorHandler() gin.HandlerFunc {
 					Time:      time.Now(),
 					RequestID: requestID,
 				}
+				report(c, err, http.StatusInternalServerError, true)
 				responseError(c, err)
 				c.Abort()
 			}
@@ -63,6 +69,7 @@ func responseError(c *gin.Context, err error) {
 	if exists {
 		apiErr.RequestID = requestID.(string)
 	}
+	report(c, apiErr, statusCode, false)
 	response := Response{
 		Code:      int(apiErr.Code),
 		Message:   apiErr.Message,
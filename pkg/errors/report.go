@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reporter 错误上报回调，在错误响应写出时调用，由上层接入 Sentry 等外部服务
+type Reporter func(c *gin.Context, err *Error, status int, panicked bool)
+
+const reportedKey = "errors.reported"
+
+var reporter atomic.Pointer[Reporter]
+
+/* SetReporter 设置错误上报回调，传入 nil 时关闭上报 */
+func SetReporter(r Reporter) {
+	if r == nil {
+		reporter.Store(nil)
+		return
+	}
+	reporter.Store(&r)
+}
+
+// report 每个请求只上报一次，避免 panic 恢复与错误响应重复上报
+func report(c *gin.Context, err *Error, status int, panicked bool) {
+	r := reporter.Load()
+	if r == nil || c == nil || err == nil {
+		return
+	}
+	if _, done := c.Get(reportedKey); done {
+		return
+	}
+	c.Set(reportedKey, true)
+	(*r)(c, err, status, panicked)
+}
@@ -58,6 +58,7 @@ func ResponseError(c *gin.Context, err *Error, detail string) {
 		Timestamp: time.Now().Unix(),
 	}
 	statusCode := HTTPStatus(err)
+	report(c, err, statusCode, false)
 	c.JSON(statusCode, response)
 }
 
@@ -77,5 +78,6 @@ func ResponseErrorWithMessage(c *gin.Context, err *Error, message string) {
 		Timestamp: time.Now().Unix(),
 	}
 	statusCode := HTTPStatus(err)
+	report(c, &customErr, statusCode, false)
 	c.JSON(statusCode, response)
 }
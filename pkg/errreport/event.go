@@ -0,0 +1,130 @@
+// Package errreport 将服务端错误上报到 Sentry 或通用 HTTP 回调地址，不依赖第三方 SDK。
+package errreport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Level 错误级别，取值与 Sentry 一致
+type Level string
+
+const (
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+var levelRank = map[Level]int{
+	LevelWarning: 1,
+	LevelError:   2,
+	LevelFatal:   3,
+}
+
+/* AtLeast 级别是否不低于 min，未知级别视为 error */
+func (l Level) AtLeast(min Level) bool {
+	rank, ok := levelRank[l]
+	if !ok {
+		rank = levelRank[LevelError]
+	}
+	minRank, ok := levelRank[min]
+	if !ok {
+		minRank = levelRank[LevelError]
+	}
+	return rank >= minRank
+}
+
+// Event 一次错误事件
+type Event struct {
+	ID          string            `json:"id"`
+	Time        time.Time         `json:"time"`
+	Level       Level             `json:"level"`
+	Code        int               `json:"code"`             // 业务错误码
+	Message     string            `json:"message"`          // 对外的错误信息
+	Detail      string            `json:"detail,omitempty"` // 内部错误详情
+	Stack       string            `json:"stack,omitempty"`  // 调用栈
+	Status      int               `json:"status"`           // HTTP 状态码
+	Panic       bool              `json:"panic"`            // 是否由 panic 触发
+	RequestID   string            `json:"request_id"`       // 请求ID
+	TraceID     string            `json:"trace_id,omitempty"`
+	UserID      uint              `json:"user_id,omitempty"` // 当前用户，0 表示未登录
+	Method      string            `json:"method"`
+	Route       string            `json:"route"` // 路由模板，如 /api/v1/files/:id
+	Path        string            `json:"path"`
+	ClientIP    string            `json:"client_ip"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// Sender 上报通道
+type Sender interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+/* NewEventID 生成 32 位十六进制事件ID，Sentry 要求该格式 */
+func NewEventID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Frame 解析后的调用栈帧
+type Frame struct {
+	File     string
+	Line     int
+	Function string
+}
+
+/* ParseStack 解析调用栈，支持 errors 包的 file:line - function 格式与 debug.Stack 格式，最近的调用在前 */
+func ParseStack(stack string) []Frame {
+	var frames []Frame
+	function := ""
+	for _, line := range strings.Split(stack, "\n") {
+		// debug.Stack 格式：函数名一行，随后以制表符开头的 file:line +0x.. 一行
+		if strings.HasPrefix(line, "\t") {
+			location, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			if frame, ok := parseLocation(location, function); ok {
+				frames = append(frames, frame)
+			}
+			function = ""
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		location, fn, found := strings.Cut(line, " - ")
+		if !found {
+			if idx := strings.LastIndex(line, "("); idx > 0 {
+				line = line[:idx]
+			}
+			function = line
+			continue
+		}
+		if frame, ok := parseLocation(location, fn); ok {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+func parseLocation(location, function string) (Frame, bool) {
+	idx := strings.LastIndex(location, ":")
+	if idx <= 0 {
+		return Frame{}, false
+	}
+	lineNo, err := strconv.Atoi(location[idx+1:])
+	if err != nil {
+		return Frame{}, false
+	}
+	return Frame{File: location[:idx], Line: lineNo, Function: function}, true
+}
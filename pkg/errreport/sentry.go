@@ -0,0 +1,245 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const clientName = "pixelpunk-errreport/1.0"
+
+// SentryClient 通过 Envelope 接口上报事件到 Sentry（兼容 GlitchTip 等实现）
+type SentryClient struct {
+	dsn       string
+	endpoint  string
+	publicKey string
+	client    *http.Client
+}
+
+/* NewSentryClient 解析 DSN，格式为 https://<public_key>@<host>/<project_id> */
+func NewSentryClient(dsn string) (*SentryClient, error) {
+	u, err := url.Parse(strings.TrimSpace(dsn))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid sentry dsn")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("sentry dsn missing public key")
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	projectID := path[idx+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("sentry dsn missing project id")
+	}
+	prefix := path[:idx]
+
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, projectID)
+	return &SentryClient{
+		dsn:       u.String(),
+		endpoint:  endpoint,
+		publicKey: u.User.Username(),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+/* Send 实现 Sender */
+func (s *SentryClient) Send(ctx context.Context, event *Event) error {
+	payload, err := json.Marshal(s.buildEvent(event))
+	if err != nil {
+		return err
+	}
+
+	header, _ := json.Marshal(map[string]string{
+		"event_id": event.ID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+		"dsn":      s.dsn,
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{
+		"type":   "event",
+		"length": len(payload),
+	})
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(itemHeader)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", clientName, s.publicKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sentry returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return nil
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   float64           `json:"timestamp"`
+	Level       Level             `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Message     string            `json:"message,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Request     *sentryRequest    `json:"request,omitempty"`
+	Exception   *sentryExceptions `json:"exception,omitempty"`
+}
+
+type sentryUser struct {
+	ID        string `json:"id,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+type sentryRequest struct {
+	URL    string `json:"url,omitempty"`
+	Method string `json:"method,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Mechanism  *sentryMechanism  `json:"mechanism,omitempty"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryMechanism struct {
+	Type    string `json:"type"`
+	Handled bool   `json:"handled"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+func (s *SentryClient) buildEvent(event *Event) *sentryEvent {
+	tags := map[string]string{
+		"request_id":  event.RequestID,
+		"route":       event.Route,
+		"method":      event.Method,
+		"status_code": strconv.Itoa(event.Status),
+		"error_code":  strconv.Itoa(event.Code),
+	}
+	if event.TraceID != "" {
+		tags["trace_id"] = event.TraceID
+	}
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+
+	out := &sentryEvent{
+		EventID:     event.ID,
+		Timestamp:   float64(event.Time.UnixNano()) / 1e9,
+		Level:       event.Level,
+		Platform:    "go",
+		Logger:      "pixelpunk",
+		Transaction: strings.TrimSpace(event.Method + " " + event.Route),
+		ServerName:  event.ServerName,
+		Release:     event.Release,
+		Environment: event.Environment,
+		Tags:        tags,
+		Request:     &sentryRequest{URL: event.Path, Method: event.Method},
+	}
+	if event.Detail != "" && event.Detail != event.Message {
+		out.Extra = map[string]any{"detail": event.Detail}
+	}
+	if event.UserID > 0 || event.ClientIP != "" {
+		out.User = &sentryUser{IPAddress: event.ClientIP}
+		if event.UserID > 0 {
+			out.User.ID = strconv.FormatUint(uint64(event.UserID), 10)
+		}
+	}
+
+	exceptionType := "Error"
+	if event.Code != 0 {
+		exceptionType = fmt.Sprintf("Error%d", event.Code)
+	}
+	exception := sentryException{
+		Type:      exceptionType,
+		Value:     event.Message,
+		Mechanism: &sentryMechanism{Type: "generic", Handled: !event.Panic},
+	}
+	if event.Panic {
+		exception.Type = "panic"
+		exception.Value = event.Detail
+		exception.Mechanism.Type = "panic"
+	}
+	if frames := ParseStack(event.Stack); len(frames) > 0 {
+		// Sentry 要求最早的调用在前
+		st := &sentryStacktrace{Frames: make([]sentryFrame, 0, len(frames))}
+		for i := len(frames) - 1; i >= 0; i-- {
+			f := frames[i]
+			module, function := splitFunction(f.Function)
+			st.Frames = append(st.Frames, sentryFrame{
+				Function: function,
+				Module:   module,
+				AbsPath:  f.File,
+				Filename: shortFile(f.File),
+				Lineno:   f.Line,
+				InApp:    strings.HasPrefix(module, "pixelpunk"),
+			})
+		}
+		exception.Stacktrace = st
+	}
+	out.Exception = &sentryExceptions{Values: []sentryException{exception}}
+	return out
+}
+
+// splitFunction 将 pixelpunk/pkg/errors.New 拆分为包路径与函数名
+func splitFunction(fn string) (string, string) {
+	slash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[slash+1:], ".")
+	if dot < 0 {
+		return "", fn
+	}
+	dot += slash + 1
+	return fn[:dot], fn[dot+1:]
+}
+
+func shortFile(file string) string {
+	parts := strings.Split(file, "/")
+	if len(parts) > 3 {
+		parts = parts[len(parts)-3:]
+	}
+	return strings.Join(parts, "/")
+}
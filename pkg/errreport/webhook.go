@@ -0,0 +1,60 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSender 以 JSON 形式将事件 POST 到任意地址，便于接入自建告警系统
+type WebhookSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+/* NewWebhookSender 创建回调上报，token 非空时作为 Bearer 令牌发送 */
+func NewWebhookSender(rawURL, token string) (*WebhookSender, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return nil, fmt.Errorf("invalid webhook url")
+	}
+	return &WebhookSender{
+		url:    rawURL,
+		token:  strings.TrimSpace(token),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+/* Send 实现 Sender */
+func (w *WebhookSender) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", clientName)
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
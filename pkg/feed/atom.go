@@ -0,0 +1,124 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+const generator = "PixelPunk"
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	MediaNS   string      `xml:"xmlns:media,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Icon      string      `xml:"icon,omitempty"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string          `xml:"id"`
+	Title      string          `xml:"title"`
+	Updated    string          `xml:"updated"`
+	Published  string          `xml:"published,omitempty"`
+	Links      []atomLink      `xml:"link"`
+	Author     *atomPerson     `xml:"author,omitempty"`
+	Categories []atomCategory  `xml:"category"`
+	Summary    *atomText       `xml:"summary,omitempty"`
+	Thumbnail  *mediaThumbnail `xml:"media:thumbnail,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+/* ToAtom 编码为 Atom 1.0，文件以 rel="enclosure" 链接给出 */
+func (f *Feed) ToAtom() ([]byte, error) {
+	doc := atomFeed{
+		MediaNS:   "http://search.yahoo.com/mrss/",
+		ID:        f.ID,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Updated:   atomTime(f.Updated),
+		Icon:      f.Icon,
+		Generator: generator,
+	}
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.Link, Rel: "alternate", Type: "text/html"})
+	}
+	if f.FeedURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.FeedURL, Rel: "self", Type: FormatAtom.mediaType()})
+	}
+	if f.Author != "" {
+		doc.Author = &atomPerson{Name: f.Author}
+	}
+
+	for _, it := range f.Items {
+		updated := it.Updated
+		if updated.IsZero() {
+			updated = it.Published
+		}
+		entry := atomEntry{
+			ID:      it.ID,
+			Title:   it.Title,
+			Updated: atomTime(updated),
+		}
+		if !it.Published.IsZero() {
+			entry.Published = atomTime(it.Published)
+		}
+		if it.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: it.Link, Rel: "alternate", Type: "text/html"})
+		}
+		if it.Enclosure != nil {
+			entry.Links = append(entry.Links, atomLink{
+				Href:   it.Enclosure.URL,
+				Rel:    "enclosure",
+				Type:   it.Enclosure.Type,
+				Length: strconv.FormatInt(it.Enclosure.Length, 10),
+			})
+		}
+		if it.Author != "" {
+			entry.Author = &atomPerson{Name: it.Author}
+		}
+		for _, tag := range it.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		if it.Description != "" {
+			entry.Summary = &atomText{Type: "text", Value: it.Description}
+		}
+		if it.Thumbnail != "" {
+			entry.Thumbnail = &mediaThumbnail{URL: it.Thumbnail}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,81 @@
+package feed
+
+import "time"
+
+// Feed 与输出格式无关的订阅源
+type Feed struct {
+	ID          string // 全局唯一标识，Atom 与 JSON Feed 使用
+	Title       string
+	Link        string // 对应的网页地址
+	FeedURL     string // 订阅源自身的地址
+	Description string
+	Author      string
+	Icon        string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item 订阅条目
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Author      string
+	Published   time.Time
+	Updated     time.Time
+	Tags        []string
+	Thumbnail   string
+	Enclosure   *Enclosure
+}
+
+// Enclosure 条目附带的文件
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// Format 输出格式
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+/* ParseFormat 解析格式名称，无法识别时返回 false */
+func ParseFormat(name string) (Format, bool) {
+	switch Format(name) {
+	case FormatRSS, FormatAtom, FormatJSON:
+		return Format(name), true
+	}
+	return "", false
+}
+
+// ContentType 对应格式的响应类型
+func (f Format) ContentType() string {
+	return f.mediaType() + "; charset=utf-8"
+}
+
+func (f Format) mediaType() string {
+	switch f {
+	case FormatAtom:
+		return "application/atom+xml"
+	case FormatJSON:
+		return "application/feed+json"
+	}
+	return "application/rss+xml"
+}
+
+/* Encode 按格式编码订阅源 */
+func (f *Feed) Encode(format Format) ([]byte, error) {
+	switch format {
+	case FormatAtom:
+		return f.ToAtom()
+	case FormatJSON:
+		return f.ToJSON()
+	}
+	return f.ToRSS()
+}
@@ -0,0 +1,93 @@
+package geoip
+
+import (
+	"net"
+	"sync"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+var (
+	mu         sync.Mutex
+	current    *dbHandle
+	loadedPath string
+)
+
+// dbHandle 带引用计数的数据库读取器；路径变更后旧读取器被标记为退役，最后一个查询释放时才关闭，避免关闭仍在使用的内存映射
+type dbHandle struct {
+	reader  *geoip2.Reader
+	refs    int
+	retired bool
+}
+
+/* Location IP所属地区 */
+type Location struct {
+	CountryCode string // ISO 3166-1 国家代码，如 CN、US
+	CountryName string // 国家名称，优先使用中文
+}
+
+/* Lookup 使用 MaxMind GeoIP2/GeoLite2 数据库查询IP所属国家；数据库未配置、内网IP或查询失败时返回空结果 */
+func Lookup(dbPath, ip string) Location {
+	if dbPath == "" {
+		return Location{}
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() {
+		return Location{}
+	}
+
+	h := acquire(dbPath)
+	if h == nil {
+		return Location{}
+	}
+	record, err := h.reader.Country(addr)
+	release(h)
+	if err != nil {
+		return Location{}
+	}
+
+	name := record.Country.Names["zh-CN"]
+	if name == "" {
+		name = record.Country.Names["en"]
+	}
+	return Location{
+		CountryCode: record.Country.IsoCode,
+		CountryName: name,
+	}
+}
+
+// acquire 按路径懒加载数据库并增加引用计数，路径变更时重新打开；调用方用完后必须调用 release
+func acquire(dbPath string) *dbHandle {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if loadedPath != dbPath {
+		if current != nil {
+			current.retired = true
+			if current.refs == 0 {
+				current.reader.Close()
+			}
+			current = nil
+		}
+		loadedPath = dbPath
+		if r, err := geoip2.Open(dbPath); err == nil {
+			current = &dbHandle{reader: r}
+		}
+	}
+	if current == nil {
+		return nil
+	}
+	current.refs++
+	return current
+}
+
+// release 减少引用计数，已退役的读取器在最后一个引用释放后关闭
+func release(h *dbHandle) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	h.refs--
+	if h.retired && h.refs == 0 {
+		h.reader.Close()
+	}
+}
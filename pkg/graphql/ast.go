@@ -0,0 +1,150 @@
+package graphql
+
+// Location 源码位置，行列均从 1 开始
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Document 解析后的查询文档
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation 操作定义，目前只执行 query
+type Operation struct {
+	Type         string
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*Directive
+	SelectionSet []Selection
+	Loc          Location
+}
+
+// VariableDefinition 变量声明
+type VariableDefinition struct {
+	Name    string
+	Type    *TypeRef
+	Default Value
+	Loc     Location
+}
+
+// TypeRef 查询中引用的类型，如 [ID!]!
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+func (t *TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Fragment 具名片段
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Loc           Location
+}
+
+// Selection 字段、片段展开或内联片段
+type Selection interface {
+	location() Location
+}
+
+// Field 字段选择
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Loc          Location
+}
+
+// ResponseKey 结果中使用的键名，有别名时为别名
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread ...Name
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Loc        Location
+}
+
+// InlineFragment ... on Type { }
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Loc           Location
+}
+
+func (f *Field) location() Location          { return f.Loc }
+func (f *FragmentSpread) location() Location { return f.Loc }
+func (f *InlineFragment) location() Location { return f.Loc }
+
+// Argument 字段或指令参数
+type Argument struct {
+	Name  string
+	Value Value
+	Loc   Location
+}
+
+// Directive 指令，如 @include(if: $x)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Loc       Location
+}
+
+// Value 字面量或变量
+type Value interface{}
+
+// Variable $name
+type Variable struct{ Name string }
+
+// IntValue 整数字面量，保留原文以便按目标类型转换
+type IntValue struct{ Raw string }
+
+// FloatValue 浮点字面量
+type FloatValue struct{ Raw string }
+
+// StringValue 字符串字面量（已处理转义）
+type StringValue struct{ Value string }
+
+// BooleanValue 布尔字面量
+type BooleanValue struct{ Value bool }
+
+// NullValue null
+type NullValue struct{}
+
+// EnumValue 枚举字面量
+type EnumValue struct{ Name string }
+
+// ListValue 列表字面量
+type ListValue struct{ Values []Value }
+
+// ObjectValue 输入对象字面量
+type ObjectValue struct{ Fields []*ObjectField }
+
+// ObjectField 输入对象字段
+type ObjectField struct {
+	Name  string
+	Value Value
+}
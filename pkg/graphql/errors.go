@@ -0,0 +1,25 @@
+package graphql
+
+import "fmt"
+
+// Error 按规范返回的错误，Path 指向出错的字段
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Locations) > 0 {
+		return fmt.Sprintf("%s (line %d, column %d)", e.Message, e.Locations[0].Line, e.Locations[0].Column)
+	}
+	return e.Message
+}
+
+func syntaxError(loc Location, message string) *Error {
+	return &Error{Message: "Syntax Error: " + message, Locations: []Location{loc}}
+}
+
+func newError(loc Location, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}}
+}
@@ -0,0 +1,633 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxDepth 默认的最大查询深度，内省字段不计入
+const DefaultMaxDepth = 12
+
+// DefaultMaxFields 默认单次查询最多选择的字段数，片段按展开后计数，别名各自计入，内省字段不计入
+const DefaultMaxFields = 300
+
+// Params 执行参数
+type Params struct {
+	Schema        *Schema
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	Context       context.Context
+	// MaxDepth 字段嵌套的最大层数，0 表示使用 DefaultMaxDepth
+	MaxDepth int
+	// MaxFields 展开片段后允许选择的字段总数，0 表示使用 DefaultMaxFields
+	MaxFields int
+	// FormatError 将解析函数返回的错误转换为对外的错误信息，为空时使用 err.Error()
+	FormatError func(err error) string
+}
+
+// Result 执行结果，Data 按查询中字段的顺序序列化
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// HasErrors 是否包含错误
+func (r *Result) HasErrors() bool { return len(r.Errors) > 0 }
+
+/* Do 解析、校验并执行查询；仅支持 query 操作 */
+func Do(p Params) *Result {
+	doc, err := Parse(p.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+
+	op, err := selectOperation(doc, p.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+
+	e := &executor{
+		schema:      p.Schema,
+		doc:         doc,
+		ctx:         p.Context,
+		maxDepth:    p.MaxDepth,
+		maxFields:   p.MaxFields,
+		formatError: p.FormatError,
+	}
+	if e.ctx == nil {
+		e.ctx = context.Background()
+	}
+	if e.maxDepth <= 0 {
+		e.maxDepth = DefaultMaxDepth
+	}
+	if e.maxFields <= 0 {
+		e.maxFields = DefaultMaxFields
+	}
+
+	if e.vars, err = e.coerceVariables(op, p.Variables); err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+	if errs := e.validate(op); len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	data, _ := e.executeSelectionSet(p.Schema.Query, nil, op.SelectionSet, nil)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+func toError(err error) *Error {
+	if ge, ok := err.(*Error); ok {
+		return ge
+	}
+	return &Error{Message: err.Error()}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	var op *Operation
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		op = doc.Operations[0]
+	} else {
+		for _, o := range doc.Operations {
+			if o.Name == name {
+				op = o
+				break
+			}
+		}
+		if op == nil {
+			return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+		}
+	}
+	if op.Type != "query" {
+		return nil, newError(op.Loc, "Only query operations are supported, got %q.", op.Type)
+	}
+	return op, nil
+}
+
+type executor struct {
+	schema      *Schema
+	doc         *Document
+	ctx         context.Context
+	vars        map[string]interface{}
+	declared    map[string]bool
+	maxDepth    int
+	maxFields   int
+	fieldCount  int
+	formatError func(error) string
+	errors      []*Error
+}
+
+// fieldGroup 响应键相同的字段合并执行
+type fieldGroup struct {
+	key    string
+	fields []*Field
+}
+
+// resultMap 保持字段顺序的结果对象
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *resultMap) set(key string, v interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+// MarshalJSON 按插入顺序输出
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, _ := json.Marshal(k)
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// collectFields 展开片段并处理 @skip/@include，按响应键分组
+func (e *executor) collectFields(obj *Object, sels []Selection, groups []*fieldGroup, visited map[string]bool) ([]*fieldGroup, error) {
+	for _, sel := range sels {
+		switch s := sel.(type) {
+		case *Field:
+			ok, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			key := s.ResponseKey()
+			found := false
+			for _, g := range groups {
+				if g.key == key {
+					if g.fields[0].Name != s.Name {
+						return nil, newError(s.Loc, "Fields %q conflict because %q and %q are different fields.", key, g.fields[0].Name, s.Name)
+					}
+					g.fields = append(g.fields, s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, &fieldGroup{key: key, fields: []*Field{s}})
+			}
+		case *FragmentSpread:
+			ok, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !ok || visited[s.Name] {
+				continue
+			}
+			visited[s.Name] = true
+			frag := e.doc.Fragments[s.Name]
+			if frag == nil {
+				return nil, newError(s.Loc, "Unknown fragment %q.", s.Name)
+			}
+			if err := e.checkTypeCondition(obj, frag.TypeCondition, s.Loc); err != nil {
+				return nil, err
+			}
+			if groups, err = e.collectFields(obj, frag.SelectionSet, groups, visited); err != nil {
+				return nil, err
+			}
+		case *InlineFragment:
+			ok, err := e.shouldInclude(s.Directives)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			if s.TypeCondition != "" {
+				if err := e.checkTypeCondition(obj, s.TypeCondition, s.Loc); err != nil {
+					return nil, err
+				}
+			}
+			if groups, err = e.collectFields(obj, s.SelectionSet, groups, visited); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return groups, nil
+}
+
+// checkTypeCondition 没有接口与联合类型，片段的类型条件必须与当前对象一致
+func (e *executor) checkTypeCondition(obj *Object, cond string, loc Location) error {
+	if e.schema.Type(cond) == nil {
+		return newError(loc, "Unknown type %q.", cond)
+	}
+	if cond != obj.Name {
+		return newError(loc, "Fragment cannot be spread here as objects of type %q can never be of type %q.", obj.Name, cond)
+	}
+	return nil
+}
+
+func (e *executor) shouldInclude(directives []*Directive) (bool, error) {
+	for _, d := range directives {
+		var def *DirectiveDefinition
+		switch d.Name {
+		case "skip":
+			def = skipDirective
+		case "include":
+			def = includeDirective
+		default:
+			return false, newError(d.Loc, "Unknown directive \"@%s\".", d.Name)
+		}
+		args, err := e.coerceArguments(def.Args, d.Arguments, d.Loc)
+		if err != nil {
+			return false, err
+		}
+		if args["if"].(bool) == (d.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// fieldDefinition 查找字段定义，包括 __typename 与根类型上的内省字段
+func (e *executor) fieldDefinition(obj *Object, name string) *FieldDefinition {
+	switch name {
+	case "__typename":
+		return typenameMetaField
+	case "__schema":
+		if obj == e.schema.Query {
+			return schemaMetaField
+		}
+	case "__type":
+		if obj == e.schema.Query {
+			return typeMetaField
+		}
+	}
+	return obj.Field(name)
+}
+
+// validate 执行前校验字段、参数、片段、查询深度与字段总数
+func (e *executor) validate(op *Operation) []*Error {
+	var errs []*Error
+	checked := map[string]bool{}
+	for name, frag := range e.doc.Fragments {
+		if err := e.checkFragmentCycle(name, frag.SelectionSet, map[string]bool{name: true}, checked); err != nil {
+			errs = append(errs, err)
+		}
+		checked[name] = true
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return e.validateSelectionSet(e.schema.Query, op.SelectionSet, 1, false, errs)
+}
+
+// checkFragmentCycle 深度优先查找片段循环，checked 记录已确认无循环的片段，避免重复展开导致指数级遍历
+func (e *executor) checkFragmentCycle(origin string, sels []Selection, path, checked map[string]bool) *Error {
+	for _, sel := range sels {
+		switch s := sel.(type) {
+		case *Field:
+			if err := e.checkFragmentCycle(origin, s.SelectionSet, path, checked); err != nil {
+				return err
+			}
+		case *InlineFragment:
+			if err := e.checkFragmentCycle(origin, s.SelectionSet, path, checked); err != nil {
+				return err
+			}
+		case *FragmentSpread:
+			if path[s.Name] {
+				return newError(s.Loc, "Cannot spread fragment %q within itself.", origin)
+			}
+			if checked[s.Name] {
+				continue
+			}
+			frag := e.doc.Fragments[s.Name]
+			if frag == nil {
+				return newError(s.Loc, "Unknown fragment %q.", s.Name)
+			}
+			path[s.Name] = true
+			err := e.checkFragmentCycle(origin, frag.SelectionSet, path, checked)
+			delete(path, s.Name)
+			if err != nil {
+				return err
+			}
+			checked[s.Name] = true
+		}
+	}
+	return nil
+}
+
+func (e *executor) validateSelectionSet(obj *Object, sels []Selection, depth int, introspection bool, errs []*Error) []*Error {
+	groups, err := e.collectFields(obj, sels, nil, map[string]bool{})
+	if err != nil {
+		return append(errs, toError(err))
+	}
+	for _, g := range groups {
+		field := g.fields[0]
+		// 超出字段总数后立即停止，避免别名或重复展开片段放大校验与执行开销
+		if !introspection && !strings.HasPrefix(field.Name, "__") {
+			e.fieldCount++
+			if e.fieldCount > e.maxFields {
+				return append(errs, newError(field.Loc, "Query exceeds the maximum of %d selected fields.", e.maxFields))
+			}
+		}
+		def := e.fieldDefinition(obj, field.Name)
+		if def == nil {
+			errs = append(errs, newError(field.Loc, "Cannot query field %q on type %q.", field.Name, obj.Name))
+			continue
+		}
+		for _, f := range g.fields {
+			for _, arg := range f.Arguments {
+				if def.arg(arg.Name) == nil {
+					errs = append(errs, newError(arg.Loc, "Unknown argument %q on field \"%s.%s\".", arg.Name, obj.Name, def.Name))
+				}
+			}
+			if _, err := e.coerceArguments(def.Args, f.Arguments, f.Loc); err != nil {
+				errs = append(errs, toError(err))
+			}
+		}
+
+		var subs []Selection
+		for _, f := range g.fields {
+			subs = append(subs, f.SelectionSet...)
+		}
+		child, ok := namedType(def.Type).(*Object)
+		if !ok {
+			if len(subs) > 0 {
+				errs = append(errs, newError(field.Loc, "Field %q must not have a selection since type %q has no subfields.", field.Name, def.Type))
+			}
+			continue
+		}
+		if len(subs) == 0 {
+			errs = append(errs, newError(field.Loc, "Field %q of type %q must have a selection of subfields.", field.Name, def.Type))
+			continue
+		}
+
+		nextIntrospection := introspection || strings.HasPrefix(field.Name, "__")
+		nextDepth := depth
+		if !nextIntrospection {
+			nextDepth++
+			if nextDepth > e.maxDepth {
+				errs = append(errs, newError(field.Loc, "Query exceeds the maximum depth of %d.", e.maxDepth))
+				continue
+			}
+		}
+		errs = e.validateSelectionSet(child, subs, nextDepth, nextIntrospection, errs)
+		if e.fieldCount > e.maxFields {
+			return errs
+		}
+	}
+	return errs
+}
+
+// executeSelectionSet 依次执行字段；非空字段出错时返回 nil 并向上传递
+func (e *executor) executeSelectionSet(obj *Object, source interface{}, sels []Selection, path []interface{}) (interface{}, bool) {
+	groups, err := e.collectFields(obj, sels, nil, map[string]bool{})
+	if err != nil {
+		e.addError(toError(err), path)
+		return nil, true
+	}
+	return e.executeGroups(obj, source, groups, path)
+}
+
+func (e *executor) executeGroups(obj *Object, source interface{}, groups []*fieldGroup, path []interface{}) (interface{}, bool) {
+	result := &resultMap{values: make(map[string]interface{}, len(groups))}
+	for _, g := range groups {
+		def := e.fieldDefinition(obj, g.fields[0].Name)
+		fieldPath := appendPath(path, g.key)
+		v, errored := e.executeField(obj, source, g, def, fieldPath)
+		if errored {
+			if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, true
+			}
+		}
+		result.set(g.key, v)
+	}
+	return result, false
+}
+
+func (e *executor) executeField(obj *Object, source interface{}, g *fieldGroup, def *FieldDefinition, path []interface{}) (interface{}, bool) {
+	field := g.fields[0]
+	args, err := e.coerceArguments(def.Args, field.Arguments, field.Loc)
+	if err != nil {
+		e.addError(toError(err), path)
+		return nil, true
+	}
+
+	// 提前收集子字段，解析函数可据此决定需要加载的数据
+	var subGroups []*fieldGroup
+	var selections []*Field
+	if child, ok := namedType(def.Type).(*Object); ok {
+		var subs []Selection
+		for _, f := range g.fields {
+			subs = append(subs, f.SelectionSet...)
+		}
+		if subGroups, err = e.collectFields(child, subs, nil, map[string]bool{}); err != nil {
+			e.addError(toError(err), path)
+			return nil, true
+		}
+		for _, sg := range subGroups {
+			selections = append(selections, sg.fields...)
+		}
+	}
+
+	params := ResolveParams{
+		Context: e.ctx,
+		Source:  source,
+		Args:    args,
+		Info: ResolveInfo{
+			FieldName:  def.Name,
+			Path:       path,
+			ParentType: obj,
+			Schema:     e.schema,
+			Selections: selections,
+		},
+	}
+	value, err := e.resolve(def, params)
+	if err != nil {
+		e.addError(&Error{Message: e.message(err), Locations: []Location{field.Loc}}, path)
+		return nil, true
+	}
+	return e.completeValue(def.Type, field, subGroups, value, path)
+}
+
+func (e *executor) resolve(def *FieldDefinition, p ResolveParams) (v interface{}, err error) {
+	if ctxErr := p.Context.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("internal error resolving field %q: %v", def.Name, r)
+		}
+	}()
+	if def.Resolve != nil {
+		return def.Resolve(p)
+	}
+	return defaultResolve(p.Source, def.Name), nil
+}
+
+func (e *executor) message(err error) string {
+	if e.formatError != nil {
+		return e.formatError(err)
+	}
+	return err.Error()
+}
+
+// completeValue 按声明类型转换解析结果，返回值为 nil 且 errored 为真表示因错误置空
+func (e *executor) completeValue(t Type, field *Field, subGroups []*fieldGroup, value interface{}, path []interface{}) (interface{}, bool) {
+	if nn, ok := t.(*NonNull); ok {
+		v, errored := e.completeValue(nn.OfType, field, subGroups, value, path)
+		if v == nil {
+			if !errored {
+				e.addError(newError(field.Loc, "Cannot return null for non-nullable field %q.", field.Name), path)
+			}
+			return nil, true
+		}
+		return v, errored
+	}
+
+	if isNil(value) {
+		return nil, false
+	}
+
+	switch tt := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(value)
+		for rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(newError(field.Loc, "Expected a list for field %q.", field.Name), path)
+			return nil, true
+		}
+		_, itemNonNull := tt.OfType.(*NonNull)
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			v, errored := e.completeValue(tt.OfType, field, subGroups, rv.Index(i).Interface(), appendPath(path, i))
+			if errored && itemNonNull {
+				return nil, true
+			}
+			items[i] = v
+		}
+		return items, false
+	case *Scalar:
+		v, err := tt.Serialize(value)
+		if err != nil {
+			e.addError(newError(field.Loc, "%s", err.Error()), path)
+			return nil, true
+		}
+		return v, false
+	case *Enum:
+		for _, ev := range tt.Values {
+			if reflect.DeepEqual(tt.internalValue(ev), value) {
+				return ev.Name, false
+			}
+		}
+		e.addError(newError(field.Loc, "Enum %q cannot represent value: %v", tt.Name, value), path)
+		return nil, true
+	case *Object:
+		v, errored := e.executeGroups(tt, value, subGroups, path)
+		if errored {
+			return nil, true
+		}
+		return v, false
+	}
+	return nil, false
+}
+
+func (e *executor) addError(err *Error, path []interface{}) {
+	if err.Path == nil && path != nil {
+		err.Path = append([]interface{}{}, path...)
+	}
+	e.errors = append(e.errors, err)
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	next := make([]interface{}, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, key)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// defaultResolve 从 map 或结构体中读取同名字段，结构体按 json 标签或字段名（忽略大小写）匹配
+func defaultResolve(source interface{}, name string) interface{} {
+	if source == nil {
+		return nil
+	}
+	if m, ok := source.(map[string]interface{}); ok {
+		return m[name]
+	}
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	if v, ok := structField(rv, name); ok {
+		return v.Interface()
+	}
+	return nil
+}
+
+func structField(rv reflect.Value, name string) (reflect.Value, bool) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if tag == name || strings.EqualFold(sf.Name, name) {
+			return rv.Field(i), true
+		}
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.Anonymous {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			if v, ok := structField(fv, name); ok {
+				return v, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
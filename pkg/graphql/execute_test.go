@@ -0,0 +1,265 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testFile struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Size int64
+}
+
+var testFiles = []*testFile{
+	{ID: 1, Name: "a.png", Size: 100},
+	{ID: 2, Name: "b.jpg", Size: 200},
+	{ID: 3, Name: "c.gif", Size: 300},
+}
+
+// newTestSchema 构造 Query { file files fail failRequired } 与 File { id name size next } 的测试用 Schema
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	file := NewObject("File", "",
+		&FieldDefinition{Name: "id", Type: NewNonNull(ID)},
+		&FieldDefinition{Name: "name", Type: String},
+		&FieldDefinition{Name: "size", Type: Int},
+	)
+	file.AddFields(&FieldDefinition{Name: "next", Type: file, Resolve: func(p ResolveParams) (interface{}, error) {
+		f := p.Source.(*testFile)
+		return testFiles[int(f.ID)%len(testFiles)], nil
+	}})
+
+	query := NewObject("Query", "",
+		&FieldDefinition{
+			Name: "file",
+			Type: file,
+			Args: []*ArgumentDefinition{{Name: "id", Type: NewNonNull(ID)}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				for _, f := range testFiles {
+					if fmt.Sprint(f.ID) == p.Args["id"] {
+						return f, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		&FieldDefinition{
+			Name: "files",
+			Type: NewNonNull(NewList(NewNonNull(file))),
+			Args: []*ArgumentDefinition{{Name: "first", Type: Int, DefaultValue: 2}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				n := p.Args["first"].(int)
+				if n > len(testFiles) {
+					n = len(testFiles)
+				}
+				return testFiles[:n], nil
+			},
+		},
+		&FieldDefinition{
+			Name: "fail",
+			Type: String,
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return nil, errors.New("boom")
+			},
+		},
+		&FieldDefinition{
+			Name: "failRequired",
+			Type: NewNonNull(String),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return nil, errors.New("boom")
+			},
+		},
+	)
+	schema, err := NewSchema(query, "")
+	if err != nil {
+		t.Fatalf("NewSchema 返回错误: %v", err)
+	}
+	return schema
+}
+
+func marshalResult(t *testing.T, r *Result) string {
+	t.Helper()
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("序列化结果失败: %v", err)
+	}
+	return string(b)
+}
+
+// TestDoExecute 验证别名、片段、变量、指令与字段顺序
+func TestDoExecute(t *testing.T) {
+	schema := newTestSchema(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "默认参数与字段顺序",
+			query: `{ files { name id } }`,
+			want:  `{"data":{"files":[{"name":"a.png","id":"1"},{"name":"b.jpg","id":"2"}]}}`,
+		},
+		{
+			name:  "别名与结构体字段名匹配",
+			query: `{ a: file(id: 1) { size } b: file(id: "3") { size } missing: file(id: 9) { id } }`,
+			want:  `{"data":{"a":{"size":100},"b":{"size":300},"missing":null}}`,
+		},
+		{
+			name:      "变量与片段",
+			query:     `query Q($n: Int) { files(first: $n) { ...F } } fragment F on File { id ... on File { name } }`,
+			variables: map[string]interface{}{"n": 3},
+			want:      `{"data":{"files":[{"id":"1","name":"a.png"},{"id":"2","name":"b.jpg"},{"id":"3","name":"c.gif"}]}}`,
+		},
+		{
+			name:      "skip 与 include",
+			query:     `query Q($yes: Boolean!) { file(id: 2) { id @skip(if: $yes) name @include(if: $yes) size @include(if: false) } }`,
+			variables: map[string]interface{}{"yes": true},
+			want:      `{"data":{"file":{"name":"b.jpg"}}}`,
+		},
+		{
+			name:  "相同响应键的字段合并",
+			query: `{ file(id: 1) { id } file(id: 1) { name } }`,
+			want:  `{"data":{"file":{"id":"1","name":"a.png"}}}`,
+		},
+		{
+			name:  "__typename",
+			query: `{ __typename file(id: 1) { __typename next { id } } }`,
+			want:  `{"data":{"__typename":"Query","file":{"__typename":"File","next":{"id":"2"}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Do(Params{Schema: schema, Query: tt.query, Variables: tt.variables})
+			if got := marshalResult(t, r); got != tt.want {
+				t.Errorf("结果为 %s，期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDoResolveErrors 验证解析错误的路径、格式化与非空字段的置空传递
+func TestDoResolveErrors(t *testing.T) {
+	schema := newTestSchema(t)
+
+	r := Do(Params{Schema: schema, Query: `{ fail file(id: 1) { id } }`})
+	if got, want := marshalResult(t, r), `{"data":{"fail":null,"file":{"id":"1"}},"errors":[{"message":"boom","locations":[{"line":1,"column":3}],"path":["fail"]}]}`; got != want {
+		t.Errorf("可空字段出错的结果为 %s，期望 %s", got, want)
+	}
+
+	r = Do(Params{
+		Schema:      schema,
+		Query:       `{ file(id: 1) { id } failRequired }`,
+		FormatError: func(err error) string { return "internal" },
+	})
+	if r.Data != nil {
+		t.Errorf("非空根字段出错时 data 应为 null，实际为 %s", marshalResult(t, r))
+	}
+	if len(r.Errors) != 1 || r.Errors[0].Message != "internal" {
+		t.Errorf("错误应经过 FormatError 处理，实际为 %s", marshalResult(t, r))
+	}
+}
+
+// TestDoValidationErrors 验证执行前的校验错误，校验失败时不调用任何解析函数
+func TestDoValidationErrors(t *testing.T) {
+	schema := newTestSchema(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		message   string
+	}{
+		{"未知字段", `{ nope }`, "", nil, `Cannot query field "nope" on type "Query".`},
+		{"未知参数", `{ file(id: 1, x: 2) { id } }`, "", nil, `Unknown argument "x"`},
+		{"缺少必填参数", `{ file { id } }`, "", nil, `"id"`},
+		{"对象字段缺少子选择", `{ file(id: 1) }`, "", nil, "must have a selection of subfields"},
+		{"标量字段带子选择", `{ file(id: 1) { id { x } } }`, "", nil, "must not have a selection"},
+		{"别名冲突", `{ file(id: 1) { x: id x: name } }`, "", nil, "conflict"},
+		{"片段循环", `{ file(id: 1) { ...A } } fragment A on File { ...B } fragment B on File { ...A }`, "", nil, "within itself"},
+		{"未知片段", `{ file(id: 1) { ...A } }`, "", nil, `Unknown fragment "A".`},
+		{"片段类型不匹配", `{ file(id: 1) { ... on Query { __typename } } }`, "", nil, "can never be of type"},
+		{"未知指令", `{ file(id: 1) { id @deprecated } }`, "", nil, `Unknown directive "@deprecated".`},
+		{"变量类型错误", `query Q($n: Int) { files(first: $n) { id } }`, "", map[string]interface{}{"n": "x"}, "$n"},
+		{"不支持 mutation", `mutation { file(id: 1) { id } }`, "", nil, "Only query operations are supported"},
+		{"多个操作未指定名称", `query A { files { id } } query B { files { id } }`, "", nil, "Must provide operation name"},
+		{"未知操作名", `query A { files { id } }`, "B", nil, `Unknown operation named "B".`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Do(Params{Schema: schema, Query: tt.query, OperationName: tt.operation, Variables: tt.variables})
+			if r.Data != nil {
+				t.Errorf("校验失败时不应返回 data，实际为 %s", marshalResult(t, r))
+			}
+			if len(r.Errors) == 0 {
+				t.Fatalf("期望返回错误")
+			}
+			if !strings.Contains(r.Errors[0].Message, tt.message) {
+				t.Errorf("错误为 %q，期望包含 %q", r.Errors[0].Message, tt.message)
+			}
+		})
+	}
+}
+
+// TestDoMaxDepth 验证查询深度限制，内省字段不计入深度
+func TestDoMaxDepth(t *testing.T) {
+	schema := newTestSchema(t)
+
+	r := Do(Params{Schema: schema, Query: `{ file(id: 1) { next { id } } }`, MaxDepth: 3})
+	if r.HasErrors() {
+		t.Errorf("深度为 3 的查询应通过，实际返回 %s", marshalResult(t, r))
+	}
+
+	r = Do(Params{Schema: schema, Query: `{ file(id: 1) { next { next { id } } } }`, MaxDepth: 3})
+	if !r.HasErrors() || !strings.Contains(r.Errors[0].Message, "maximum depth of 3") {
+		t.Errorf("深度为 4 的查询应被拒绝，实际返回 %s", marshalResult(t, r))
+	}
+
+	r = Do(Params{Schema: schema, Query: `{ __schema { types { fields { type { ofType { ofType { name } } } } } } }`, MaxDepth: 1})
+	if r.HasErrors() {
+		t.Errorf("内省查询不应受深度限制，实际返回 %s", marshalResult(t, r))
+	}
+}
+
+// TestDoMaxFields 验证字段总数限制：别名逐个计入，片段按展开次数计入
+func TestDoMaxFields(t *testing.T) {
+	schema := newTestSchema(t)
+
+	var aliases strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&aliases, " f%d: file(id: 1) { id }", i)
+	}
+	r := Do(Params{Schema: schema, Query: "{" + aliases.String() + " }", MaxFields: 20})
+	if r.HasErrors() {
+		t.Errorf("20 个字段的查询应通过，实际返回 %s", marshalResult(t, r))
+	}
+	r = Do(Params{Schema: schema, Query: "{" + aliases.String() + " }", MaxFields: 19})
+	if !r.HasErrors() || !strings.Contains(r.Errors[0].Message, "maximum of 19 selected fields") {
+		t.Errorf("超过 19 个字段的查询应被拒绝，实际返回 %s", marshalResult(t, r))
+	}
+
+	// 每层片段展开两次，字段数随层数指数增长
+	var bomb strings.Builder
+	bomb.WriteString("{ file(id: 1) { ...F0 } }")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&bomb, " fragment F%d on File { next { ...F%d } n: next { ...F%d } }", i, i+1, i+1)
+	}
+	bomb.WriteString(" fragment F30 on File { id }")
+	r = Do(Params{Schema: schema, Query: bomb.String(), MaxDepth: 100})
+	if !r.HasErrors() || !strings.Contains(r.Errors[0].Message, fmt.Sprintf("maximum of %d selected fields", DefaultMaxFields)) {
+		t.Errorf("重复展开片段的查询应被字段总数限制拒绝，实际返回 %s", marshalResult(t, r))
+	}
+
+	r = Do(Params{Schema: schema, Query: `{ __schema { types { name fields { name args { name } type { name } } } } }`, MaxFields: 1})
+	if r.HasErrors() {
+		t.Errorf("内省字段不应计入字段总数，实际返回 %s", marshalResult(t, r))
+	}
+}
@@ -0,0 +1,281 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 内省类型，字段之间相互引用，在 init 中补全
+var (
+	schemaType       = NewObject("__Schema", "A GraphQL Schema defines the capabilities of a GraphQL server.")
+	typeType         = NewObject("__Type", "The fundamental unit of any GraphQL Schema is the type.")
+	fieldType        = NewObject("__Field", "Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type.")
+	inputValueType   = NewObject("__InputValue", "Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values.")
+	enumValueType    = NewObject("__EnumValue", "One possible value for a given Enum.")
+	directiveType    = NewObject("__Directive", "A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.")
+	typeKindEnum     = newNameEnum("__TypeKind", "An enum describing what kind of type a given `__Type` is.", "SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL")
+	directiveLocEnum = newNameEnum("__DirectiveLocation", "A Directive can be adjacent to many parts of the GraphQL language.",
+		"QUERY", "MUTATION", "SUBSCRIPTION", "FIELD", "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT", "VARIABLE_DEFINITION",
+		"SCHEMA", "SCALAR", "OBJECT", "FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INTERFACE", "UNION", "ENUM", "ENUM_VALUE",
+		"INPUT_OBJECT", "INPUT_FIELD_DEFINITION")
+)
+
+// 根类型上的元字段
+var (
+	schemaMetaField = &FieldDefinition{
+		Name:        "__schema",
+		Description: "Access the current type schema of this server.",
+		Type:        NewNonNull(schemaType),
+		Resolve:     func(p ResolveParams) (interface{}, error) { return p.Info.Schema, nil },
+	}
+	typeMetaField = &FieldDefinition{
+		Name:        "__type",
+		Description: "Request the type information of a single type.",
+		Type:        typeType,
+		Args:        []*ArgumentDefinition{{Name: "name", Type: NewNonNull(String)}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			if t := p.Info.Schema.Type(p.Args["name"].(string)); t != nil {
+				return t, nil
+			}
+			return nil, nil
+		},
+	}
+	typenameMetaField = &FieldDefinition{
+		Name:        "__typename",
+		Description: "The name of the current Object type at runtime.",
+		Type:        NewNonNull(String),
+		Resolve:     func(p ResolveParams) (interface{}, error) { return p.Info.ParentType.Name, nil },
+	}
+)
+
+func newNameEnum(name, description string, values ...string) *Enum {
+	e := &Enum{Name: name, Description: description}
+	for _, v := range values {
+		e.Values = append(e.Values, &EnumValueDefinition{Name: v})
+	}
+	return e
+}
+
+var includeDeprecatedArg = []*ArgumentDefinition{{Name: "includeDeprecated", Type: Boolean, DefaultValue: false}}
+
+func init() {
+	nonNullString := NewNonNull(String)
+	nonNullBoolean := NewNonNull(Boolean)
+	nonNullType := NewNonNull(typeType)
+
+	schemaType.AddFields(
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*Schema).Description), nil
+		}},
+		&FieldDefinition{Name: "types", Description: "A list of all types supported by this server.", Type: NewNonNull(NewList(nonNullType)),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				s := p.Source.(*Schema)
+				types := make([]Type, 0, len(s.types))
+				for _, name := range s.TypeNames() {
+					types = append(types, s.types[name])
+				}
+				return types, nil
+			}},
+		&FieldDefinition{Name: "queryType", Description: "The type that query operations will be rooted at.", Type: nonNullType,
+			Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*Schema).Query, nil }},
+		&FieldDefinition{Name: "mutationType", Type: typeType, Resolve: nullResolve},
+		&FieldDefinition{Name: "subscriptionType", Type: typeType, Resolve: nullResolve},
+		&FieldDefinition{Name: "directives", Description: "A list of all directives supported by this server.", Type: NewNonNull(NewList(NewNonNull(directiveType))),
+			Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*Schema).directives, nil }},
+	)
+
+	typeType.AddFields(
+		&FieldDefinition{Name: "kind", Type: NewNonNull(typeKindEnum), Resolve: func(p ResolveParams) (interface{}, error) {
+			switch p.Source.(type) {
+			case *Scalar:
+				return "SCALAR", nil
+			case *Object:
+				return "OBJECT", nil
+			case *Enum:
+				return "ENUM", nil
+			case *List:
+				return "LIST", nil
+			case *NonNull:
+				return "NON_NULL", nil
+			}
+			return nil, fmt.Errorf("unknown kind of type: %v", p.Source)
+		}},
+		&FieldDefinition{Name: "name", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			if n, ok := p.Source.(Named); ok {
+				return n.TypeName(), nil
+			}
+			return nil, nil
+		}},
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			switch t := p.Source.(type) {
+			case *Scalar:
+				return optionalString(t.Description), nil
+			case *Object:
+				return optionalString(t.Description), nil
+			case *Enum:
+				return optionalString(t.Description), nil
+			}
+			return nil, nil
+		}},
+		&FieldDefinition{Name: "specifiedByURL", Type: String, Resolve: nullResolve},
+		&FieldDefinition{Name: "fields", Type: NewList(NewNonNull(fieldType)), Args: includeDeprecatedArg,
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(*Object)
+				if !ok {
+					return nil, nil
+				}
+				all, _ := p.Args["includeDeprecated"].(bool)
+				fields := make([]*FieldDefinition, 0, len(obj.fields))
+				for _, f := range obj.fields {
+					if all || f.DeprecationReason == "" {
+						fields = append(fields, f)
+					}
+				}
+				return fields, nil
+			}},
+		&FieldDefinition{Name: "interfaces", Type: NewList(nonNullType), Resolve: func(p ResolveParams) (interface{}, error) {
+			if _, ok := p.Source.(*Object); ok {
+				return []Type{}, nil
+			}
+			return nil, nil
+		}},
+		&FieldDefinition{Name: "possibleTypes", Type: NewList(nonNullType), Resolve: nullResolve},
+		&FieldDefinition{Name: "enumValues", Type: NewList(NewNonNull(enumValueType)), Args: includeDeprecatedArg,
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				e, ok := p.Source.(*Enum)
+				if !ok {
+					return nil, nil
+				}
+				all, _ := p.Args["includeDeprecated"].(bool)
+				values := make([]*EnumValueDefinition, 0, len(e.Values))
+				for _, v := range e.Values {
+					if all || v.DeprecationReason == "" {
+						values = append(values, v)
+					}
+				}
+				return values, nil
+			}},
+		&FieldDefinition{Name: "inputFields", Type: NewList(NewNonNull(inputValueType)), Resolve: nullResolve},
+		&FieldDefinition{Name: "ofType", Type: typeType, Resolve: func(p ResolveParams) (interface{}, error) {
+			switch t := p.Source.(type) {
+			case *List:
+				return t.OfType, nil
+			case *NonNull:
+				return t.OfType, nil
+			}
+			return nil, nil
+		}},
+	)
+
+	fieldType.AddFields(
+		&FieldDefinition{Name: "name", Type: nonNullString},
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*FieldDefinition).Description), nil
+		}},
+		&FieldDefinition{Name: "args", Type: NewNonNull(NewList(NewNonNull(inputValueType))), Resolve: func(p ResolveParams) (interface{}, error) {
+			if args := p.Source.(*FieldDefinition).Args; args != nil {
+				return args, nil
+			}
+			return []*ArgumentDefinition{}, nil
+		}},
+		&FieldDefinition{Name: "type", Type: nonNullType},
+		&FieldDefinition{Name: "isDeprecated", Type: nonNullBoolean, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*FieldDefinition).DeprecationReason != "", nil
+		}},
+		&FieldDefinition{Name: "deprecationReason", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*FieldDefinition).DeprecationReason), nil
+		}},
+	)
+
+	inputValueType.AddFields(
+		&FieldDefinition{Name: "name", Type: nonNullString},
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*ArgumentDefinition).Description), nil
+		}},
+		&FieldDefinition{Name: "type", Type: nonNullType},
+		&FieldDefinition{Name: "defaultValue", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			a := p.Source.(*ArgumentDefinition)
+			if a.DefaultValue == nil {
+				return nil, nil
+			}
+			return printValue(a.Type, a.DefaultValue), nil
+		}},
+		&FieldDefinition{Name: "isDeprecated", Type: nonNullBoolean, Resolve: func(p ResolveParams) (interface{}, error) { return false, nil }},
+		&FieldDefinition{Name: "deprecationReason", Type: String, Resolve: nullResolve},
+	)
+
+	enumValueType.AddFields(
+		&FieldDefinition{Name: "name", Type: nonNullString},
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*EnumValueDefinition).Description), nil
+		}},
+		&FieldDefinition{Name: "isDeprecated", Type: nonNullBoolean, Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Source.(*EnumValueDefinition).DeprecationReason != "", nil
+		}},
+		&FieldDefinition{Name: "deprecationReason", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*EnumValueDefinition).DeprecationReason), nil
+		}},
+	)
+
+	directiveType.AddFields(
+		&FieldDefinition{Name: "name", Type: nonNullString},
+		&FieldDefinition{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+			return optionalString(p.Source.(*DirectiveDefinition).Description), nil
+		}},
+		&FieldDefinition{Name: "isRepeatable", Type: nonNullBoolean, Resolve: func(p ResolveParams) (interface{}, error) { return false, nil }},
+		&FieldDefinition{Name: "locations", Type: NewNonNull(NewList(NewNonNull(directiveLocEnum)))},
+		&FieldDefinition{Name: "args", Type: NewNonNull(NewList(NewNonNull(inputValueType))), Resolve: func(p ResolveParams) (interface{}, error) {
+			if args := p.Source.(*DirectiveDefinition).Args; args != nil {
+				return args, nil
+			}
+			return []*ArgumentDefinition{}, nil
+		}},
+	)
+}
+
+// addIntrospection 注册内省类型，使其可通过 __type 查询
+func addIntrospection(s *Schema) {
+	for _, t := range []Named{schemaType, typeType, fieldType, inputValueType, enumValueType, directiveType, typeKindEnum, directiveLocEnum} {
+		s.types[t.TypeName()] = t
+	}
+}
+
+func nullResolve(ResolveParams) (interface{}, error) { return nil, nil }
+
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// printValue 以 GraphQL 字面量形式输出参数默认值
+func printValue(t Type, v interface{}) string {
+	switch tt := t.(type) {
+	case *NonNull:
+		return printValue(tt.OfType, v)
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return printValue(tt.OfType, v)
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = printValue(tt.OfType, rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *Enum:
+		for _, ev := range tt.Values {
+			if reflect.DeepEqual(tt.internalValue(ev), v) {
+				return ev.Name
+			}
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,422 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// maxTokens 单个查询允许的词法单元数量，防止超大查询消耗解析资源
+const maxTokens = 20000
+
+type parser struct {
+	lex   *lexer
+	tok   token
+	count int
+}
+
+/* Parse 解析查询文档 */
+func Parse(source string) (*Document, error) {
+	p := &parser{lex: newLexer(source)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peek(tokenName, "query"), p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peek(tokenName, "fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[frag.Name]; exists {
+				return nil, newError(frag.Loc, "There can be only one fragment named %q.", frag.Name)
+			}
+			doc.Fragments[frag.Name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, &Error{Message: "Document does not contain any operation."}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.count++
+	if p.count > maxTokens {
+		return syntaxError(tok.loc, "Document contains too many tokens.")
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return syntaxError(p.tok.loc, "Unexpected <EOF>.")
+	}
+	return syntaxError(p.tok.loc, fmt.Sprintf("Unexpected %q.", p.tok.value))
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: "query", Loc: p.tok.loc}
+	if p.peek(tokenPunct, "{") {
+		set, err := p.parseSelectionSet()
+		op.SelectionSet = set
+		return op, err
+	}
+
+	op.Type = p.tok.value
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip(tokenPunct, "("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokenPunct, ")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	directives, err := p.parseDirectives(false)
+	if err != nil {
+		return nil, err
+	}
+	op.Directives = directives
+
+	op.SelectionSet, err = p.parseSelectionSet()
+	return op, err
+}
+
+func (p *parser) parseVariableDefinition() (*VariableDefinition, error) {
+	def := &VariableDefinition{Loc: p.tok.loc}
+	if err := p.expect(tokenPunct, "$"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	def.Name = name
+	if err := p.expect(tokenPunct, ":"); err != nil {
+		return nil, err
+	}
+	if def.Type, err = p.parseTypeRef(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(tokenPunct, "="); err != nil {
+		return nil, err
+	} else if ok {
+		if def.Default, err = p.parseValue(true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(true); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func (p *parser) parseTypeRef() (*TypeRef, error) {
+	var t *TypeRef
+	if ok, err := p.skip(tokenPunct, "["); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Elem: elem}
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		t = &TypeRef{Name: name}
+	}
+
+	if ok, err := p.skip(tokenPunct, "!"); err != nil {
+		return nil, err
+	} else if ok {
+		t.NonNull = true
+	}
+	return t, nil
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	frag := &Fragment{Loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, syntaxError(frag.Loc, "Unexpected \"on\".")
+	}
+	frag.Name = name
+	if err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	if frag.TypeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if frag.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	frag.SelectionSet, err = p.parseSelectionSet()
+	return frag, err
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek(tokenPunct, "}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	loc := p.tok.loc
+	if ok, err := p.skip(tokenPunct, "..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			spread := &FragmentSpread{Name: p.tok.value, Loc: loc}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			spread.Directives, err = p.parseDirectives(false)
+			return spread, err
+		}
+
+		inline := &InlineFragment{Loc: loc}
+		if ok, err := p.skip(tokenName, "on"); err != nil {
+			return nil, err
+		} else if ok {
+			if inline.TypeCondition, err = p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+		if inline.Directives, err = p.parseDirectives(false); err != nil {
+			return nil, err
+		}
+		inline.SelectionSet, err = p.parseSelectionSet()
+		return inline, err
+	}
+
+	field := &Field{Loc: loc}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(tokenPunct, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	if field.Arguments, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+	if field.Directives, err = p.parseDirectives(false); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "{") {
+		if field.SelectionSet, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments(constant bool) ([]*Argument, error) {
+	if ok, err := p.skip(tokenPunct, "("); err != nil || !ok {
+		return nil, err
+	}
+	var args []*Argument
+	for !p.peek(tokenPunct, ")") {
+		arg := &Argument{Loc: p.tok.loc}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = name
+		if err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if arg.Value, err = p.parseValue(constant); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseDirectives(constant bool) ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(tokenPunct, "@") {
+		d := &Directive{Loc: p.tok.loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		d.Name = name
+		if d.Arguments, err = p.parseArguments(constant); err != nil {
+			return nil, err
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+func (p *parser) parseValue(constant bool) (Value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return &Variable{Name: name}, err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := &ListValue{}
+			for !p.peek(tokenPunct, "]") {
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list.Values = append(list.Values, v)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			obj := &ObjectValue{}
+			for !p.peek(tokenPunct, "}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(tokenPunct, ":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				obj.Fields = append(obj.Fields, &ObjectField{Name: name, Value: v})
+			}
+			return obj, p.advance()
+		}
+	case tokenInt:
+		return &IntValue{Raw: tok.value}, p.advance()
+	case tokenFloat:
+		return &FloatValue{Raw: tok.value}, p.advance()
+	case tokenString:
+		return &StringValue{Value: tok.value}, p.advance()
+	case tokenName:
+		var v Value
+		switch tok.value {
+		case "true":
+			v = &BooleanValue{Value: true}
+		case "false":
+			v = &BooleanValue{Value: false}
+		case "null":
+			v = &NullValue{}
+		default:
+			v = &EnumValue{Name: tok.value}
+		}
+		return v, p.advance()
+	}
+	return nil, p.unexpected()
+}
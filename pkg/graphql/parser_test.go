@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseDocument 验证操作、变量、别名、参数与片段的解析结果
+func TestParseDocument(t *testing.T) {
+	doc, err := Parse(`
+		# 注释会被忽略
+		query Files($first: Int = 10, $tags: [String!]!) {
+			recent: files(first: $first, tags: $tags, sort: NEWEST, filter: {name: "a\"b", size: 1.5, deleted: false, owner: null}) {
+				...FileFields
+				... on File @include(if: true) { id }
+			}
+		}
+		fragment FileFields on File { id name }
+	`)
+	if err != nil {
+		t.Fatalf("Parse 返回错误: %v", err)
+	}
+
+	if len(doc.Operations) != 1 {
+		t.Fatalf("操作数量为 %d，期望 1", len(doc.Operations))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Files" {
+		t.Errorf("操作为 %s %s，期望 query Files", op.Type, op.Name)
+	}
+	if len(op.Variables) != 2 {
+		t.Fatalf("变量数量为 %d，期望 2", len(op.Variables))
+	}
+	if got := op.Variables[0].Type.String(); got != "Int" {
+		t.Errorf("$first 类型为 %s，期望 Int", got)
+	}
+	if def, ok := op.Variables[0].Default.(*IntValue); !ok || def.Raw != "10" {
+		t.Errorf("$first 默认值为 %#v，期望 10", op.Variables[0].Default)
+	}
+	if got := op.Variables[1].Type.String(); got != "[String!]!" {
+		t.Errorf("$tags 类型为 %s，期望 [String!]!", got)
+	}
+
+	field, ok := op.SelectionSet[0].(*Field)
+	if !ok {
+		t.Fatalf("第一个选择为 %T，期望 *Field", op.SelectionSet[0])
+	}
+	if field.Alias != "recent" || field.Name != "files" || field.ResponseKey() != "recent" {
+		t.Errorf("字段为 %s: %s，期望 recent: files", field.Alias, field.Name)
+	}
+	if len(field.Arguments) != 4 {
+		t.Fatalf("参数数量为 %d，期望 4", len(field.Arguments))
+	}
+	if v, ok := field.Arguments[0].Value.(*Variable); !ok || v.Name != "first" {
+		t.Errorf("first 参数为 %#v，期望变量 $first", field.Arguments[0].Value)
+	}
+	if v, ok := field.Arguments[2].Value.(*EnumValue); !ok || v.Name != "NEWEST" {
+		t.Errorf("sort 参数为 %#v，期望枚举 NEWEST", field.Arguments[2].Value)
+	}
+	obj, ok := field.Arguments[3].Value.(*ObjectValue)
+	if !ok || len(obj.Fields) != 4 {
+		t.Fatalf("filter 参数为 %#v，期望包含 4 个字段的对象", field.Arguments[3].Value)
+	}
+	if v, ok := obj.Fields[0].Value.(*StringValue); !ok || v.Value != `a"b` {
+		t.Errorf("filter.name 为 %#v，期望转义后的 a\"b", obj.Fields[0].Value)
+	}
+	if _, ok := obj.Fields[1].Value.(*FloatValue); !ok {
+		t.Errorf("filter.size 为 %#v，期望浮点数", obj.Fields[1].Value)
+	}
+	if v, ok := obj.Fields[2].Value.(*BooleanValue); !ok || v.Value {
+		t.Errorf("filter.deleted 为 %#v，期望 false", obj.Fields[2].Value)
+	}
+	if _, ok := obj.Fields[3].Value.(*NullValue); !ok {
+		t.Errorf("filter.owner 为 %#v，期望 null", obj.Fields[3].Value)
+	}
+
+	if len(field.SelectionSet) != 2 {
+		t.Fatalf("子选择数量为 %d，期望 2", len(field.SelectionSet))
+	}
+	if s, ok := field.SelectionSet[0].(*FragmentSpread); !ok || s.Name != "FileFields" {
+		t.Errorf("第一个子选择为 %#v，期望 ...FileFields", field.SelectionSet[0])
+	}
+	inline, ok := field.SelectionSet[1].(*InlineFragment)
+	if !ok || inline.TypeCondition != "File" || len(inline.Directives) != 1 || inline.Directives[0].Name != "include" {
+		t.Errorf("第二个子选择为 %#v，期望 ... on File @include", field.SelectionSet[1])
+	}
+
+	frag := doc.Fragments["FileFields"]
+	if frag == nil || frag.TypeCondition != "File" || len(frag.SelectionSet) != 2 {
+		t.Errorf("片段 FileFields 解析结果为 %#v", frag)
+	}
+}
+
+// TestParseErrors 验证语法错误与文档级错误
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"空文档", ``, "Document does not contain any operation."},
+		{"只有片段", `fragment F on File { id }`, "Document does not contain any operation."},
+		{"未闭合的选择集", `{ files { id }`, "Syntax Error"},
+		{"未闭合的字符串", `{ file(id: "abc) { id } }`, "Syntax Error"},
+		{"非法字符", `{ file(id: 1) { id } } %`, "Syntax Error"},
+		{"重复片段", `{ id } fragment F on File { id } fragment F on File { id }`, `There can be only one fragment named "F".`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("期望返回错误")
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("错误为 %q，期望包含 %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+// TestParseTokenLimit 验证超出词法单元上限的查询会被拒绝
+func TestParseTokenLimit(t *testing.T) {
+	query := "{" + strings.Repeat(" id", maxTokens) + " }"
+	if _, err := Parse(query); err == nil {
+		t.Fatalf("超过 %d 个词法单元的查询应返回错误", maxTokens)
+	}
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Int 32 位有符号整数
+var Int = &Scalar{
+	Name:        "Int",
+	Description: "The `Int` scalar type represents non-fractional signed whole numeric values between -(2^31) and 2^31 - 1.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		n, ok := toInt64(v)
+		if !ok || n > math.MaxInt32 || n < math.MinInt32 {
+			return nil, fmt.Errorf("Int cannot represent value: %v", v)
+		}
+		return n, nil
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		switch n := v.(type) {
+		case float64:
+			if n == math.Trunc(n) && n <= math.MaxInt32 && n >= math.MinInt32 {
+				return int(n), nil
+			}
+		case json.Number:
+			if i, err := strconv.ParseInt(string(n), 10, 32); err == nil {
+				return int(i), nil
+			}
+		case int:
+			if n <= math.MaxInt32 && n >= math.MinInt32 {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("Int cannot represent non-integer value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		if iv, ok := v.(*IntValue); ok {
+			if i, err := strconv.ParseInt(iv.Raw, 10, 32); err == nil {
+				return int(i), nil
+			}
+		}
+		return nil, fmt.Errorf("Int cannot represent non-integer value")
+	},
+}
+
+// Long 64 位有符号整数，用于文件大小、流量等可能超出 Int 范围的数值
+var Long = &Scalar{
+	Name:        "Long",
+	Description: "The `Long` scalar type represents non-fractional signed whole numeric values up to 64 bits.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if n, ok := toInt64(v); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("Long cannot represent value: %v", v)
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		switch n := v.(type) {
+		case float64:
+			if n == math.Trunc(n) {
+				return int64(n), nil
+			}
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		case int:
+			return int64(n), nil
+		case int64:
+			return n, nil
+		}
+		return nil, fmt.Errorf("Long cannot represent non-integer value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		if iv, ok := v.(*IntValue); ok {
+			return strconv.ParseInt(iv.Raw, 10, 64)
+		}
+		return nil, fmt.Errorf("Long cannot represent non-integer value")
+	},
+}
+
+// Float 双精度浮点数
+var Float = &Scalar{
+	Name:        "Float",
+	Description: "The `Float` scalar type represents signed double-precision fractional values.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if f, ok := toFloat64(v); ok {
+			return f, nil
+		}
+		return nil, fmt.Errorf("Float cannot represent value: %v", v)
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case json.Number:
+			return n.Float64()
+		}
+		return nil, fmt.Errorf("Float cannot represent non numeric value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		switch n := v.(type) {
+		case *IntValue:
+			return strconv.ParseFloat(n.Raw, 64)
+		case *FloatValue:
+			return strconv.ParseFloat(n.Raw, 64)
+		}
+		return nil, fmt.Errorf("Float cannot represent non numeric value")
+	},
+}
+
+// String UTF-8 字符串
+var String = &Scalar{
+	Name:        "String",
+	Description: "The `String` scalar type represents textual data, represented as UTF-8 character sequences.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		switch s := v.(type) {
+		case string:
+			return s, nil
+		case fmt.Stringer:
+			return s.String(), nil
+		}
+		return fmt.Sprint(v), nil
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("String cannot represent a non string value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		if s, ok := v.(*StringValue); ok {
+			return s.Value, nil
+		}
+		return nil, fmt.Errorf("String cannot represent a non string value")
+	},
+}
+
+// Boolean 布尔值
+var Boolean = &Scalar{
+	Name:        "Boolean",
+	Description: "The `Boolean` scalar type represents `true` or `false`.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", v)
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		if b, ok := v.(*BooleanValue); ok {
+			return b.Value, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value")
+	},
+}
+
+// ID 唯一标识，输出为字符串，输入接受字符串或整数
+var ID = &Scalar{
+	Name:        "ID",
+	Description: "The `ID` scalar type represents a unique identifier, serialized as a String.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		if n, ok := toInt64(v); ok {
+			return strconv.FormatInt(n, 10), nil
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %v", v)
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		switch s := v.(type) {
+		case string:
+			return s, nil
+		case float64:
+			if s == math.Trunc(s) {
+				return strconv.FormatInt(int64(s), 10), nil
+			}
+		case json.Number:
+			return string(s), nil
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		switch s := v.(type) {
+		case *StringValue:
+			return s.Value, nil
+		case *IntValue:
+			return s.Raw, nil
+		}
+		return nil, fmt.Errorf("ID cannot represent a non-string and non-integer value")
+	},
+}
+
+// DateTime RFC 3339 时间，接受 time.Time 及可转换为 time.Time 的类型
+var DateTime = &Scalar{
+	Name:        "DateTime",
+	Description: "A date-time string in RFC 3339 format.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.IsValid() && rv.Type().ConvertibleTo(timeType) {
+			t := rv.Convert(timeType).Interface().(time.Time)
+			if t.IsZero() {
+				return nil, nil
+			}
+			return t.Format(time.RFC3339), nil
+		}
+		return nil, fmt.Errorf("DateTime cannot represent value: %v", v)
+	},
+	ParseValue: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return time.Parse(time.RFC3339, s)
+		}
+		return nil, fmt.Errorf("DateTime cannot represent value: %v", v)
+	},
+	ParseLiteral: func(v Value) (interface{}, error) {
+		if s, ok := v.(*StringValue); ok {
+			return time.Parse(time.RFC3339, s.Value)
+		}
+		return nil, fmt.Errorf("DateTime cannot represent a non string value")
+	},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func toInt64(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) {
+			return int64(f), true
+		}
+	}
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	if n, ok := toInt64(v); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
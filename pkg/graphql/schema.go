@@ -0,0 +1,275 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Type 输出与输入类型
+type Type interface {
+	String() string
+}
+
+// Named 具名类型（标量、枚举、对象）
+type Named interface {
+	Type
+	TypeName() string
+}
+
+// ResolveParams 字段解析参数
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+	Info    ResolveInfo
+}
+
+// ResolveInfo 当前字段的执行信息，Selections 可用于按需预加载
+type ResolveInfo struct {
+	FieldName  string
+	Path       []interface{}
+	ParentType *Object
+	Schema     *Schema
+	Selections []*Field
+}
+
+// ResolveFunc 字段解析函数
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// Scalar 标量类型
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize 将解析结果转换为 JSON 输出值
+	Serialize func(value interface{}) (interface{}, error)
+	// ParseValue 转换变量中的 JSON 值
+	ParseValue func(value interface{}) (interface{}, error)
+	// ParseLiteral 转换查询中的字面量
+	ParseLiteral func(value Value) (interface{}, error)
+}
+
+func (s *Scalar) String() string   { return s.Name }
+func (s *Scalar) TypeName() string { return s.Name }
+
+// EnumValueDefinition 枚举取值
+type EnumValueDefinition struct {
+	Name              string
+	Description       string
+	Value             interface{}
+	DeprecationReason string
+}
+
+// Enum 枚举类型，Value 为空时以名称作为内部值
+type Enum struct {
+	Name        string
+	Description string
+	Values      []*EnumValueDefinition
+}
+
+func (e *Enum) String() string   { return e.Name }
+func (e *Enum) TypeName() string { return e.Name }
+
+func (e *Enum) internalValue(v *EnumValueDefinition) interface{} {
+	if v.Value != nil {
+		return v.Value
+	}
+	return v.Name
+}
+
+// ArgumentDefinition 字段参数
+type ArgumentDefinition struct {
+	Name         string
+	Description  string
+	Type         Type
+	DefaultValue interface{}
+}
+
+// FieldDefinition 对象字段
+type FieldDefinition struct {
+	Name              string
+	Description       string
+	Type              Type
+	Args              []*ArgumentDefinition
+	Resolve           ResolveFunc
+	DeprecationReason string
+}
+
+func (f *FieldDefinition) arg(name string) *ArgumentDefinition {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Object 对象类型，字段可在创建后追加以支持相互引用
+type Object struct {
+	Name        string
+	Description string
+	fields      []*FieldDefinition
+	fieldMap    map[string]*FieldDefinition
+}
+
+/* NewObject 创建对象类型 */
+func NewObject(name, description string, fields ...*FieldDefinition) *Object {
+	o := &Object{Name: name, Description: description, fieldMap: map[string]*FieldDefinition{}}
+	o.AddFields(fields...)
+	return o
+}
+
+/* AddFields 追加字段 */
+func (o *Object) AddFields(fields ...*FieldDefinition) {
+	for _, f := range fields {
+		o.fields = append(o.fields, f)
+		o.fieldMap[f.Name] = f
+	}
+}
+
+// Fields 按定义顺序返回字段
+func (o *Object) Fields() []*FieldDefinition { return o.fields }
+
+// Field 按名称查找字段
+func (o *Object) Field(name string) *FieldDefinition { return o.fieldMap[name] }
+
+func (o *Object) String() string   { return o.Name }
+func (o *Object) TypeName() string { return o.Name }
+
+// List 列表类型
+type List struct{ OfType Type }
+
+func (l *List) String() string { return "[" + l.OfType.String() + "]" }
+
+// NonNull 非空类型
+type NonNull struct{ OfType Type }
+
+func (n *NonNull) String() string { return n.OfType.String() + "!" }
+
+/* NewList 创建列表类型 */
+func NewList(t Type) *List { return &List{OfType: t} }
+
+/* NewNonNull 创建非空类型 */
+func NewNonNull(t Type) *NonNull { return &NonNull{OfType: t} }
+
+// DirectiveDefinition 指令定义，仅用于内省展示，执行时只识别 @skip 与 @include
+type DirectiveDefinition struct {
+	Name        string
+	Description string
+	Locations   []string
+	Args        []*ArgumentDefinition
+}
+
+// Schema 只读的查询 Schema
+type Schema struct {
+	Description string
+	Query       *Object
+	types       map[string]Named
+	directives  []*DirectiveDefinition
+}
+
+/* NewSchema 从查询根类型收集全部类型，同名类型必须是同一个定义 */
+func NewSchema(query *Object, description string) (*Schema, error) {
+	s := &Schema{
+		Description: description,
+		Query:       query,
+		types:       map[string]Named{},
+		directives:  []*DirectiveDefinition{skipDirective, includeDirective, deprecatedDirective},
+	}
+	for _, t := range []Named{Int, Float, String, Boolean, ID} {
+		s.types[t.TypeName()] = t
+	}
+	addIntrospection(s)
+	if err := s.collect(query); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+/* Type 按名称查找类型 */
+func (s *Schema) Type(name string) Named { return s.types[name] }
+
+// TypeNames 按名称排序的全部类型名
+func (s *Schema) TypeNames() []string {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Schema) collect(t Type) error {
+	switch v := t.(type) {
+	case *List:
+		return s.collect(v.OfType)
+	case *NonNull:
+		return s.collect(v.OfType)
+	case Named:
+		if existing, ok := s.types[v.TypeName()]; ok {
+			if existing != v {
+				return fmt.Errorf("graphql: duplicate type %q", v.TypeName())
+			}
+			return nil
+		}
+		s.types[v.TypeName()] = v
+		if obj, ok := v.(*Object); ok {
+			for _, f := range obj.fields {
+				if err := s.collect(f.Type); err != nil {
+					return err
+				}
+				for _, a := range f.Args {
+					if err := s.collect(a.Type); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// namedType 去掉列表与非空包装
+func namedType(t Type) Named {
+	for {
+		switch v := t.(type) {
+		case *List:
+			t = v.OfType
+		case *NonNull:
+			t = v.OfType
+		case Named:
+			return v
+		default:
+			return nil
+		}
+	}
+}
+
+func isLeafType(t Type) bool {
+	switch namedType(t).(type) {
+	case *Scalar, *Enum:
+		return true
+	}
+	return false
+}
+
+var (
+	skipDirective = &DirectiveDefinition{
+		Name:        "skip",
+		Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*ArgumentDefinition{{Name: "if", Description: "Skipped when true.", Type: NewNonNull(Boolean)}},
+	}
+	includeDirective = &DirectiveDefinition{
+		Name:        "include",
+		Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*ArgumentDefinition{{Name: "if", Description: "Included when true.", Type: NewNonNull(Boolean)}},
+	}
+	deprecatedDirective = &DirectiveDefinition{
+		Name:        "deprecated",
+		Description: "Marks an element of a GraphQL schema as no longer supported.",
+		Locations:   []string{"FIELD_DEFINITION", "ENUM_VALUE", "ARGUMENT_DEFINITION"},
+		Args:        []*ArgumentDefinition{{Name: "reason", Type: String, DefaultValue: "No longer supported"}},
+	}
+)
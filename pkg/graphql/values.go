@@ -0,0 +1,206 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// coerceVariables 按变量声明转换请求中的变量值
+func (e *executor) coerceVariables(op *Operation, input map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(op.Variables))
+	e.declared = make(map[string]bool, len(op.Variables))
+	for _, def := range op.Variables {
+		e.declared[def.Name] = true
+		t, err := e.inputType(def.Type)
+		if err != nil {
+			return nil, newError(def.Loc, "Variable \"$%s\": %s", def.Name, err.Error())
+		}
+		raw, provided := input[def.Name]
+		if !provided {
+			if def.Default != nil {
+				v, err := coerceLiteral(t, def.Default, nil)
+				if err != nil {
+					return nil, newError(def.Loc, "Variable \"$%s\" has invalid default value: %s", def.Name, err.Error())
+				}
+				vars[def.Name] = v
+				continue
+			}
+			if _, nonNull := t.(*NonNull); nonNull {
+				return nil, newError(def.Loc, "Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type.String())
+			}
+			continue
+		}
+		v, err := coerceValue(t, raw)
+		if err != nil {
+			return nil, newError(def.Loc, "Variable \"$%s\" got invalid value: %s", def.Name, err.Error())
+		}
+		vars[def.Name] = v
+	}
+	return vars, nil
+}
+
+// inputType 将变量声明中的类型引用解析为 Schema 中的输入类型
+func (e *executor) inputType(ref *TypeRef) (Type, error) {
+	var t Type
+	if ref.Elem != nil {
+		elem, err := e.inputType(ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(elem)
+	} else {
+		named := e.schema.Type(ref.Name)
+		switch named.(type) {
+		case *Scalar, *Enum:
+			t = named
+		case nil:
+			return nil, fmt.Errorf("unknown type %q", ref.Name)
+		default:
+			return nil, fmt.Errorf("type %q is not an input type", ref.Name)
+		}
+	}
+	if ref.NonNull {
+		t = NewNonNull(t)
+	}
+	return t, nil
+}
+
+// coerceArguments 转换字段或指令的参数，缺省时使用默认值
+func (e *executor) coerceArguments(defs []*ArgumentDefinition, args []*Argument, loc Location) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(defs))
+	for _, def := range defs {
+		var arg *Argument
+		for _, a := range args {
+			if a.Name == def.Name {
+				arg = a
+				break
+			}
+		}
+
+		provided := arg != nil
+		if provided {
+			if v, ok := arg.Value.(*Variable); ok {
+				if !e.declared[v.Name] {
+					return nil, newError(arg.Loc, "Variable \"$%s\" is not defined.", v.Name)
+				}
+				_, provided = e.vars[v.Name]
+			}
+		}
+		if !provided {
+			if def.DefaultValue != nil {
+				result[def.Name] = def.DefaultValue
+			} else if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, newError(loc, "Argument %q of required type %q was not provided.", def.Name, def.Type)
+			}
+			continue
+		}
+
+		v, err := coerceLiteral(def.Type, arg.Value, e.vars)
+		if err != nil {
+			return nil, newError(arg.Loc, "Argument %q has invalid value: %s", def.Name, err.Error())
+		}
+		result[def.Name] = v
+	}
+	return result, nil
+}
+
+// coerceLiteral 转换查询中的字面量，变量取已转换的值
+func coerceLiteral(t Type, value Value, vars map[string]interface{}) (interface{}, error) {
+	if v, ok := value.(*Variable); ok {
+		val, provided := vars[v.Name]
+		if _, nonNull := t.(*NonNull); nonNull && (!provided || val == nil) {
+			return nil, fmt.Errorf("expected non-null value for variable \"$%s\"", v.Name)
+		}
+		return val, nil
+	}
+
+	switch tt := t.(type) {
+	case *NonNull:
+		if _, isNull := value.(*NullValue); isNull {
+			return nil, fmt.Errorf("expected value of type %q, found null", tt)
+		}
+		return coerceLiteral(tt.OfType, value, vars)
+	}
+	if _, isNull := value.(*NullValue); isNull {
+		return nil, nil
+	}
+
+	switch tt := t.(type) {
+	case *List:
+		list, ok := value.(*ListValue)
+		if !ok {
+			item, err := coerceLiteral(tt.OfType, value, vars)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		items := make([]interface{}, len(list.Values))
+		for i, item := range list.Values {
+			v, err := coerceLiteral(tt.OfType, item, vars)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+	case *Scalar:
+		return tt.ParseLiteral(value)
+	case *Enum:
+		if ev, ok := value.(*EnumValue); ok {
+			for _, def := range tt.Values {
+				if def.Name == ev.Name {
+					return tt.internalValue(def), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("value is not a valid %s", tt.Name)
+	}
+	return nil, fmt.Errorf("type %q is not an input type", t)
+}
+
+// coerceValue 转换变量中的 JSON 值
+func coerceValue(t Type, value interface{}) (interface{}, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected non-nullable type %q not to be null", t)
+		}
+		return coerceValue(nn.OfType, value)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch tt := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice {
+			item, err := coerceValue(tt.OfType, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			v, err := coerceValue(tt.OfType, rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+	case *Scalar:
+		return tt.ParseValue(value)
+	case *Enum:
+		if name, ok := value.(string); ok {
+			for _, def := range tt.Values {
+				if def.Name == name {
+					return tt.internalValue(def), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("value is not a valid %s", tt.Name)
+	}
+	return nil, fmt.Errorf("type %q is not an input type", t)
+}
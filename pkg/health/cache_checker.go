@@ -59,6 +59,7 @@ func (c *CacheChecker) Check() (Status, map[string]interface{}) {
 
 	if cache.IsRedisEnabled() {
 		details["type"] = "redis"
+		details["local_cache"] = cache.GetLocalCacheStats()
 	}
 
 	// 如果响应时间超过50ms, 视为性能下降
@@ -2,6 +2,7 @@ package health
 
 import (
 	"pixelpunk/pkg/email"
+	"time"
 )
 
 // EmailChecker 邮件服务健康检查器
@@ -23,10 +24,17 @@ func (c *EmailChecker) Check() (Status, map[string]interface{}) {
 
 	details := map[string]interface{}{
 		"status":        "enabled",
-		"message":       "邮件服务已启用，配置来源：数据库",
 		"config_source": "database",
 	}
 
+	// 实际连接 SMTP 服务器完成握手，邮件不可用不影响核心功能，失败时视为性能下降
+	addr, err := email.ProbeSMTP(5 * time.Second)
+	details["addr"] = addr
+	if err != nil {
+		details["error"] = err.Error()
+		return StatusDegraded, details
+	}
+
 	return StatusUp, details
 }
 
@@ -1,6 +1,7 @@
 package health
 
 import (
+	"fmt"
 	"runtime"
 	"sync"
 	"time"
@@ -38,12 +39,18 @@ type Checker interface {
 	Type() CheckType
 }
 
+// CheckerProvider 动态提供检查项，每次检查时调用，用于按存储渠道等可变数量的依赖生成检查项
+type CheckerProvider func() []Checker
+
 // Result 表示单个健康检查结果
 type Result struct {
-	Name      string                 `json:"name"`
-	Status    Status                 `json:"status"`
-	CheckTime time.Time              `json:"check_time"`
-	Details   map[string]interface{} `json:"details,omitempty"`
+	Name        string                 `json:"name"`
+	Status      Status                 `json:"status"`
+	CheckTime   time.Time              `json:"check_time"`
+	LatencyMs   int64                  `json:"latency_ms"`
+	LastError   string                 `json:"last_error,omitempty"`    // 最近一次失败的错误信息，恢复后仍保留
+	LastErrorAt *time.Time             `json:"last_error_at,omitempty"` // 最近一次失败的时间
+	Details     map[string]interface{} `json:"details,omitempty"`
 }
 
 // SystemHealth 表示系统健康状态
@@ -57,15 +64,29 @@ type SystemHealth struct {
 	StartTime  time.Time      `json:"start_time"`
 }
 
+// checkTimeout 单个检查项的最长执行时间，超时视为不可用
+const checkTimeout = 10 * time.Second
+
+// lastError 检查项最近一次失败记录
+type lastError struct {
+	message string
+	at      time.Time
+}
+
 var (
 	// 健康检查项列表
 	checkers []Checker
+	// 动态检查项提供者
+	providers []CheckerProvider
 	// 防止并发注册检查项
 	registerMutex sync.Mutex
 	// 应用启动时间
 	startTime = time.Now()
 	// 应用版本
 	appVersion = "1.0.0"
+	// 各检查项最近一次失败记录
+	lastErrors   = make(map[string]lastError)
+	lastErrorsMu sync.Mutex
 )
 
 // RegisterChecker 注册健康检查项
@@ -82,35 +103,52 @@ func RegisterChecker(checker Checker) {
 	checkers = append(checkers, checker)
 }
 
+// RegisterProvider 注册动态检查项提供者
+func RegisterProvider(provider CheckerProvider) {
+	registerMutex.Lock()
+	defer registerMutex.Unlock()
+
+	providers = append(providers, provider)
+}
+
 func SetVersion(version string) {
 	appVersion = version
 }
 
-// Check 执行健康检查
+func GetVersion() string {
+	return appVersion
+}
+
+// Check 执行健康检查，各检查项并发执行
 func Check(checkType CheckType) SystemHealth {
 	now := time.Now()
-	results := make([]Result, 0)
 
-	// 系统总体状态默认为正常
-	overallStatus := StatusUp
-
-	for _, checker := range checkers {
+	selected := make([]Checker, 0)
+	for _, checker := range currentCheckers(checkType) {
 		if checkType != CheckTypeComplete && checker.Type() != CheckTypeBasic {
 			continue
 		}
+		selected = append(selected, checker)
+	}
 
-		status, details := checker.Check()
-		results = append(results, Result{
-			Name:      checker.Name(),
-			Status:    status,
-			CheckTime: now,
-			Details:   details,
-		})
+	results := make([]Result, len(selected))
+	var wg sync.WaitGroup
+	for i, checker := range selected {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = runChecker(checker, now)
+		}(i, checker)
+	}
+	wg.Wait()
 
+	// 系统总体状态默认为正常
+	overallStatus := StatusUp
+	for _, result := range results {
 		// 如果任一组件状态为 Down，则系统状态为 Down
-		if status == StatusDown {
+		if result.Status == StatusDown {
 			overallStatus = StatusDown
-		} else if status == StatusDegraded && overallStatus != StatusDown {
+		} else if result.Status == StatusDegraded && overallStatus != StatusDown {
 			// 如果任一组件状态为 Degraded 且当前系统状态不是 Down，则系统状态为 Degraded
 			overallStatus = StatusDegraded
 		}
@@ -129,6 +167,109 @@ func Check(checkType CheckType) SystemHealth {
 	}
 }
 
+// checkCacheTTL 完整检查结果的缓存时长，避免频繁请求反复探测外部依赖
+const checkCacheTTL = 30 * time.Second
+
+var (
+	checkCacheMu sync.Mutex
+	checkCache   = make(map[CheckType]SystemHealth)
+)
+
+// CachedCheck 返回缓存时长内的检查结果，过期后重新检查；并发请求等待同一次检查完成
+func CachedCheck(checkType CheckType) SystemHealth {
+	checkCacheMu.Lock()
+	defer checkCacheMu.Unlock()
+
+	if cached, ok := checkCache[checkType]; ok && time.Since(cached.CheckTime) < checkCacheTTL {
+		return cached
+	}
+	result := Check(checkType)
+	checkCache[checkType] = result
+	return result
+}
+
+// publicView 去掉错误信息、依赖详情与系统信息，只保留各检查项的状态与耗时
+func publicView(h SystemHealth) SystemHealth {
+	components := make([]Result, len(h.Components))
+	for i, r := range h.Components {
+		components[i] = Result{
+			Name:      r.Name,
+			Status:    r.Status,
+			CheckTime: r.CheckTime,
+			LatencyMs: r.LatencyMs,
+		}
+	}
+	h.Components = components
+	h.SystemInfo = nil
+	return h
+}
+
+// currentCheckers 静态注册的检查项，完整检查时附加动态提供的检查项
+func currentCheckers(checkType CheckType) []Checker {
+	registerMutex.Lock()
+	list := append([]Checker(nil), checkers...)
+	providerList := append([]CheckerProvider(nil), providers...)
+	registerMutex.Unlock()
+
+	if checkType == CheckTypeComplete {
+		for _, provider := range providerList {
+			list = append(list, provider()...)
+		}
+	}
+	return list
+}
+
+// runChecker 执行单个检查项并记录耗时，超时或失败时更新最近错误
+func runChecker(checker Checker, checkTime time.Time) Result {
+	type outcome struct {
+		status  Status
+		details map[string]interface{}
+	}
+
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{StatusDown, map[string]interface{}{"error": fmt.Sprintf("检查异常: %v", r)}}
+			}
+		}()
+		status, details := checker.Check()
+		done <- outcome{status, details}
+	}()
+
+	var o outcome
+	select {
+	case o = <-done:
+	case <-time.After(checkTimeout):
+		o = outcome{StatusDown, map[string]interface{}{"error": fmt.Sprintf("检查超时（%s）", checkTimeout)}}
+	}
+
+	result := Result{
+		Name:      checker.Name(),
+		Status:    o.status,
+		CheckTime: checkTime,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   o.details,
+	}
+
+	lastErrorsMu.Lock()
+	defer lastErrorsMu.Unlock()
+	if result.Status != StatusUp {
+		message, _ := o.details["error"].(string)
+		if message == "" {
+			message = string(result.Status)
+		}
+		lastErrors[result.Name] = lastError{message: message, at: time.Now()}
+	}
+	if le, ok := lastErrors[result.Name]; ok {
+		at := le.at
+		result.LastError = le.message
+		result.LastErrorAt = &at
+	}
+	return result
+}
+
 // getSystemInfo 获取系统信息
 func getSystemInfo() map[string]interface{} {
 	var memStats runtime.MemStats
@@ -21,15 +21,22 @@ func BasicHealthHandler(c *gin.Context) {
 	c.JSON(statusCode, result)
 }
 
-// CompleteHealthHandler 完整健康检查处理器
+// FullAccessKey 上下文标记，为 true 时调用方为管理员或持有探测令牌，可执行主动探测并查看完整详情
+const FullAccessKey = "health_full_access"
+
+/* CompleteHealthHandler 完整健康检查处理器；主动探测 SMTP、存储渠道等依赖仅对管理员或持有探测令牌的调用方开放，
+ * 其他调用方只得到基础检查项的状态与耗时，不包含错误信息与地址等详情 */
 func CompleteHealthHandler(c *gin.Context) {
-	result := Check(CheckTypeComplete)
+	var result SystemHealth
+	if c.GetBool(FullAccessKey) {
+		result = CachedCheck(CheckTypeComplete)
+	} else {
+		result = publicView(Check(CheckTypeBasic))
+	}
 
 	statusCode := http.StatusOK
 	if result.Status == StatusDown {
 		statusCode = http.StatusServiceUnavailable
-	} else if result.Status == StatusDegraded {
-		statusCode = http.StatusOK // 降级状态仍返回200，但在响应中标记
 	}
 
 	c.JSON(statusCode, result)
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"pixelpunk/pkg/cache"
+	"time"
+)
+
+// RedisChecker Redis 连接健康检查器，未启用 Redis 时视为正常
+type RedisChecker struct{}
+
+// Name 返回检查项名称
+func (c *RedisChecker) Name() string {
+	return "redis"
+}
+
+// Check 执行健康检查
+func (c *RedisChecker) Check() (Status, map[string]interface{}) {
+	if !cache.IsRedisEnabled() {
+		return StatusUp, map[string]interface{}{
+			"status":  "disabled",
+			"message": "未启用Redis，使用内存缓存",
+		}
+	}
+
+	client := cache.GetRedisClient()
+	if client == nil {
+		return StatusDown, map[string]interface{}{
+			"error": "Redis客户端未初始化",
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return StatusDown, map[string]interface{}{
+			"mode":  cache.GetRedisMode(),
+			"error": err.Error(),
+		}
+	}
+	pingTime := time.Since(start)
+
+	stats := client.PoolStats()
+	details := map[string]interface{}{
+		"status":       "enabled",
+		"mode":         cache.GetRedisMode(),
+		"ping_time_ms": pingTime.Milliseconds(),
+		"total_conns":  stats.TotalConns,
+		"idle_conns":   stats.IdleConns,
+		"timeouts":     stats.Timeouts,
+	}
+
+	// 如果响应时间超过50ms, 视为性能下降
+	if pingTime > 50*time.Millisecond {
+		return StatusDegraded, details
+	}
+
+	return StatusUp, details
+}
+
+// Type 返回检查类型
+func (c *RedisChecker) Type() CheckType {
+	return CheckTypeComplete
+}
+
+// init 注册Redis健康检查器
+func init() {
+	RegisterChecker(&RedisChecker{})
+}
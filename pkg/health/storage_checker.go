@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/storage"
+	"pixelpunk/pkg/database"
+)
+
+const channelHealthTimeout = 8 * time.Second
+
+// channelChecker 单个存储渠道的健康检查，默认渠道不可用时系统视为不可用，其他渠道视为性能下降
+type channelChecker struct {
+	channel models.StorageChannel
+}
+
+func (c *channelChecker) Name() string {
+	return "storage:" + c.channel.ID
+}
+
+func (c *channelChecker) Check() (Status, map[string]interface{}) {
+	details := map[string]interface{}{
+		"channel":    c.channel.Name,
+		"type":       c.channel.Type,
+		"is_default": c.channel.IsDefault,
+	}
+
+	failed := StatusDegraded
+	if c.channel.IsDefault {
+		failed = StatusDown
+	}
+
+	st, err := storage.GetChannelAdapter(c.channel.ID)
+	if err != nil {
+		details["error"] = err.Error()
+		return failed, details
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), channelHealthTimeout)
+	defer cancel()
+	if err := st.HealthCheck(ctx); err != nil {
+		details["error"] = err.Error()
+		return failed, details
+	}
+	return StatusUp, details
+}
+
+func (c *channelChecker) Type() CheckType {
+	return CheckTypeComplete
+}
+
+// channelCheckers 为每个启用的存储渠道生成检查项
+func channelCheckers() []Checker {
+	db := database.GetDB()
+	if db == nil {
+		return nil
+	}
+	var channels []models.StorageChannel
+	if err := db.Where("status = ?", 1).Order("created_at").Find(&channels).Error; err != nil {
+		return nil
+	}
+	checkers := make([]Checker, 0, len(channels))
+	for _, ch := range channels {
+		checkers = append(checkers, &channelChecker{channel: ch})
+	}
+	return checkers
+}
+
+func init() {
+	RegisterProvider(channelCheckers)
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 访问日志格式
+const (
+	AccessLogJSON = "json" // 每个请求输出一行 JSON，便于日志系统采集
+	AccessLogText = "text" // 以普通信息日志输出
+	AccessLogOff  = "off"  // 关闭访问日志
+)
+
+// AccessEntry 单个请求的访问日志
+type AccessEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Route     string    `json:"route,omitempty"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	BytesIn   int64     `json:"bytes_in"`
+	BytesOut  int       `json:"bytes_out"`
+	ClientIP  string    `json:"client_ip"`
+	UserID    uint      `json:"user_id,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+var (
+	accessFormat atomic.Value
+	accessLogger = log.New(os.Stdout, "", 0)
+)
+
+/* SetAccessLogFormat 设置访问日志格式：json、text、off，为空时使用 json */
+func SetAccessLogFormat(format string) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case AccessLogText, AccessLogOff:
+	default:
+		format = AccessLogJSON
+	}
+	accessFormat.Store(format)
+}
+
+/* AccessLogEnabled 是否输出访问日志 */
+func AccessLogEnabled() bool {
+	return accessLogFormat() != AccessLogOff
+}
+
+func accessLogFormat() string {
+	if f, ok := accessFormat.Load().(string); ok {
+		return f
+	}
+	return AccessLogJSON
+}
+
+/* Access 输出一条访问日志 */
+func Access(e *AccessEntry) {
+	switch accessLogFormat() {
+	case AccessLogOff:
+		return
+	case AccessLogText:
+		GetLogger().Info(WithRequestID(context.Background(), e.RequestID), "%s %s %d %.1fms %dB ip=%s user=%d",
+			e.Method, sanitizeLogContent(e.Path), e.Status, e.LatencyMs, e.BytesOut, e.ClientIP, e.UserID)
+		return
+	}
+
+	// JSON 编码会转义控制字符，无需额外清理
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	accessLogger.Println(string(data))
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+)
+
+// RequestIDHeader 请求ID的请求头与响应头名称
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+/* WithRequestID 将请求ID写入上下文，使用该上下文输出的日志会带上请求ID */
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+/* RequestIDFromContext 读取上下文中的请求ID，不存在时返回空字符串 */
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+/* ValidRequestID 校验上游传入的请求ID，只接受有限长度的字母、数字与 -_.: 字符，避免日志注入 */
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '-' || ch == '_' || ch == '.' || ch == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// withRequestID 为日志格式串加上请求ID前缀，请求ID已通过校验，不含格式化占位符
+func withRequestID(ctx context.Context, format string) string {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return "[req:" + id + "] " + format
+	}
+	return format
+}
+
+// InfoContext 打印信息日志，带上上下文中的请求ID
+func InfoContext(ctx context.Context, format string, args ...interface{}) {
+	safeFormat := sanitizeLogContent(format)
+	safeArgs := sanitizeArgs(args)
+	GetLogger().Info(ctx, safeFormat, safeArgs...)
+}
+
+// WarnContext 打印警告日志，带上上下文中的请求ID
+func WarnContext(ctx context.Context, format string, args ...interface{}) {
+	safeFormat := sanitizeLogContent(format)
+	safeArgs := sanitizeArgs(args)
+	GetLogger().Warn(ctx, safeFormat, safeArgs...)
+}
+
+// ErrorContext 打印错误日志，带上上下文中的请求ID
+func ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	safeFormat := sanitizeLogContent(format)
+	safeArgs := sanitizeArgs(args)
+	GetLogger().Error(ctx, safeFormat, safeArgs...)
+}
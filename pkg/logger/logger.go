@@ -92,7 +92,7 @@ func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
 // Info 打印信息日志
 func (l *Logger) Info(ctx context.Context, format string, args ...interface{}) {
 	if l.LogLevel >= logger.Info {
-		safeFormat := sanitizeLogContent(format)
+		safeFormat := withRequestID(ctx, sanitizeLogContent(format))
 		safeArgs := sanitizeArgs(args)
 		if l.config.Colorful {
 			l.Printf(Green+"[INFO] "+safeFormat+Reset, safeArgs...)
@@ -105,7 +105,7 @@ func (l *Logger) Info(ctx context.Context, format string, args ...interface{}) {
 // Warn 打印警告日志
 func (l *Logger) Warn(ctx context.Context, format string, args ...interface{}) {
 	if l.LogLevel >= logger.Warn {
-		safeFormat := sanitizeLogContent(format)
+		safeFormat := withRequestID(ctx, sanitizeLogContent(format))
 		safeArgs := sanitizeArgs(args)
 		if l.config.Colorful {
 			l.Printf(Yellow+"[WARN] "+safeFormat+Reset, safeArgs...)
@@ -118,7 +118,7 @@ func (l *Logger) Warn(ctx context.Context, format string, args ...interface{}) {
 // Error 打印错误日志
 func (l *Logger) Error(ctx context.Context, format string, args ...interface{}) {
 	if l.LogLevel >= logger.Error {
-		safeFormat := sanitizeLogContent(format)
+		safeFormat := withRequestID(ctx, sanitizeLogContent(format))
 		safeArgs := sanitizeArgs(args)
 		if l.config.Colorful {
 			l.Printf(Red+"[ERROR] "+safeFormat+Reset, safeArgs...)
@@ -308,3 +308,22 @@ func PrintSeparator() {
 func DefaultLogger(msg string) {
 	log.Println(msg)
 }
+
+// SetLevel 按名称设置日志级别：silent、error、warn、info，为空时使用 info
+func SetLevel(name string) error {
+	var level logger.LogLevel
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "info", "debug":
+		level = logger.Info
+	case "warn", "warning":
+		level = logger.Warn
+	case "error":
+		level = logger.Error
+	case "silent":
+		level = logger.Silent
+	default:
+		return fmt.Errorf("unknown log level: %s", name)
+	}
+	GetLogger().LogLevel = level
+	return nil
+}
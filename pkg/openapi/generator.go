@@ -0,0 +1,286 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoint 对单个接口的补充描述，未描述的路由只根据路由信息生成
+type Endpoint struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Query       interface{} // 查询参数结构体，按 form 标签生成
+	Body        interface{} // JSON 请求体，结构体或 *Schema
+	Form        *Schema     // multipart/form-data 请求体
+	Response    interface{} // 成功响应中 data 字段的结构，结构体或 *Schema
+}
+
+type securityRule struct {
+	prefix string
+	scheme string
+}
+
+// Generator 根据 gin 路由表与接口描述生成 OpenAPI 文档
+type Generator struct {
+	info            Info
+	prefix          string
+	schemes         map[string]*SecurityScheme
+	defaultSecurity string
+	securityRules   []securityRule
+	public          map[string]bool
+	publicPrefixes  []string
+	endpoints       map[string]Endpoint
+}
+
+/* NewGenerator 创建文档生成器，只有以 prefix 开头的路由会出现在文档中 */
+func NewGenerator(info Info, prefix string) *Generator {
+	return &Generator{
+		info:      info,
+		prefix:    prefix,
+		schemes:   make(map[string]*SecurityScheme),
+		public:    make(map[string]bool),
+		endpoints: make(map[string]Endpoint),
+	}
+}
+
+/* AddSecurityScheme 登记鉴权方式，第一个登记的作为默认鉴权 */
+func (g *Generator) AddSecurityScheme(name string, scheme *SecurityScheme) {
+	g.schemes[name] = scheme
+	if g.defaultSecurity == "" {
+		g.defaultSecurity = name
+	}
+}
+
+/* UseSecurity 指定某个路径前缀下的接口使用的鉴权方式 */
+func (g *Generator) UseSecurity(pathPrefix, scheme string) {
+	g.securityRules = append(g.securityRules, securityRule{prefix: pathPrefix, scheme: scheme})
+}
+
+/* MarkPublic 将给定路由标记为无需鉴权 */
+func (g *Generator) MarkPublic(routes gin.RoutesInfo) {
+	for _, route := range routes {
+		g.public[routeKey(route.Method, route.Path)] = true
+	}
+}
+
+/* MarkPublicPrefix 将某个路径前缀下的所有接口标记为无需鉴权 */
+func (g *Generator) MarkPublicPrefix(pathPrefix string) {
+	g.publicPrefixes = append(g.publicPrefixes, pathPrefix)
+}
+
+/* Describe 补充接口说明与请求、响应结构，path 使用 gin 路由写法 */
+func (g *Generator) Describe(method, path string, ep Endpoint) {
+	g.endpoints[routeKey(method, path)] = ep
+}
+
+/* Build 生成文档，serverURL 为空时不输出 servers */
+func (g *Generator) Build(routes gin.RoutesInfo, serverURL string) *Document {
+	registry := newSchemaRegistry()
+	registry.schemas["ErrorResponse"] = envelopeSchema(nil)
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    g.info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas:         registry.schemas,
+			SecuritySchemes: g.schemes,
+		},
+	}
+	if serverURL != "" {
+		doc.Servers = []Server{{URL: strings.TrimRight(serverURL, "/")}}
+	}
+
+	sorted := make(gin.RoutesInfo, 0, len(routes))
+	for _, route := range routes {
+		if strings.HasPrefix(route.Path, g.prefix) {
+			sorted = append(sorted, route)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	usedIDs := make(map[string]bool)
+	tagSet := make(map[string]bool)
+	for _, route := range sorted {
+		path, pathParams := convertPath(route.Path)
+		ep := g.endpoints[routeKey(route.Method, route.Path)]
+		data := registry.resolve(ep.Response)
+		if data == nil {
+			data = &Schema{}
+		}
+
+		op := &Operation{
+			OperationID: g.operationID(route, usedIDs),
+			Summary:     ep.Summary,
+			Description: ep.Description,
+			Tags:        ep.Tags,
+			Responses: map[string]Response{
+				"200": {
+					Description: "成功",
+					Content:     jsonContent(envelopeSchema(data)),
+				},
+				"default": {
+					Description: "错误",
+					Content:     jsonContent(&Schema{Ref: "#/components/schemas/ErrorResponse"}),
+				},
+			},
+		}
+		if len(op.Tags) == 0 {
+			op.Tags = []string{g.tagFor(route.Path)}
+		}
+		for _, tag := range op.Tags {
+			tagSet[tag] = true
+		}
+
+		for _, name := range pathParams {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		if ep.Query != nil {
+			op.Parameters = append(op.Parameters, registry.queryParameters(ep.Query)...)
+		}
+
+		if ep.Body != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonContent(registry.resolve(ep.Body))}
+		} else if ep.Form != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"multipart/form-data": {Schema: registry.expand(ep.Form)}},
+			}
+		}
+
+		if scheme := g.securityFor(route); scheme != "" {
+			op.Security = []SecurityRequirement{{scheme: []string{}}}
+		}
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = op
+	}
+
+	for tag := range tagSet {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	return doc
+}
+
+// securityFor 公开接口返回空字符串
+func (g *Generator) securityFor(route gin.RouteInfo) string {
+	if g.public[routeKey(route.Method, route.Path)] {
+		return ""
+	}
+	for _, p := range g.publicPrefixes {
+		if strings.HasPrefix(route.Path, p) {
+			return ""
+		}
+	}
+	for _, rule := range g.securityRules {
+		if strings.HasPrefix(route.Path, rule.prefix) {
+			return rule.scheme
+		}
+	}
+	return g.defaultSecurity
+}
+
+// tagFor 以前缀后的第一段路径作为分组，管理接口再细分一级
+func (g *Generator) tagFor(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, g.prefix), "/"), "/")
+	if segments[0] == "" {
+		return "default"
+	}
+	if segments[0] == "admin" && len(segments) > 1 && !isParamSegment(segments[1]) {
+		return "admin/" + segments[1]
+	}
+	return segments[0]
+}
+
+// operationID 优先使用处理函数名，重复或为匿名函数时改用方法与路径生成
+func (g *Generator) operationID(route gin.RouteInfo, used map[string]bool) string {
+	id := route.Handler
+	if i := strings.LastIndex(id, "."); i >= 0 {
+		id = id[i+1:]
+	}
+	id = strings.TrimSuffix(id, "-fm")
+	if id != "" {
+		id = strings.ToLower(id[:1]) + id[1:]
+	}
+
+	if id == "" || strings.HasPrefix(id, "func") || used[id] {
+		id = strings.ToLower(route.Method)
+		for _, seg := range strings.Split(strings.TrimPrefix(route.Path, g.prefix), "/") {
+			seg = strings.TrimLeft(seg, ":*")
+			for _, part := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+				id += strings.ToUpper(part[:1]) + part[1:]
+			}
+		}
+	}
+
+	base := id
+	for n := 2; used[id]; n++ {
+		id = fmt.Sprintf("%s%d", base, n)
+	}
+	used[id] = true
+	return id
+}
+
+// convertPath 将 gin 的 :param 与 *param 转换为 {param}
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, seg := range segments {
+		if isParamSegment(seg) {
+			name := seg[1:]
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func isParamSegment(seg string) bool {
+	return strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*")
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// envelopeSchema 统一响应结构，对应 errors.Response
+func envelopeSchema(data *Schema) *Schema {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer", Format: "int32"},
+			"message":    {Type: "string"},
+			"request_id": {Type: "string"},
+			"timestamp":  {Type: "integer", Format: "int64"},
+		},
+		Required: []string{"code", "message", "timestamp"},
+	}
+	if data != nil {
+		s.Properties["data"] = data
+	}
+	return s
+}
@@ -0,0 +1,273 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry 将具名结构体登记到 components.schemas，同一类型只生成一次，也避免自引用结构体无限递归
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// resolve 已是 *Schema 时原样返回，否则按 Go 类型反射生成
+func (r *schemaRegistry) resolve(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if s, ok := v.(*Schema); ok {
+		return r.expand(s)
+	}
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+// expand 复制手写的 Schema，并展开其中由 TypeOf 引用的 Go 类型
+func (r *schemaRegistry) expand(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.goType != nil {
+		return r.schemaOf(s.goType)
+	}
+
+	out := *s
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, p := range s.Properties {
+			out.Properties[name] = r.expand(p)
+		}
+	}
+	out.Items = r.expand(s.Items)
+	out.AdditionalProperties = r.expand(s.AdditionalProperties)
+	if s.OneOf != nil {
+		out.OneOf = make([]*Schema, 0, len(s.OneOf))
+		for _, o := range s.OneOf {
+			out.OneOf = append(out.OneOf, r.expand(o))
+		}
+	}
+	return &out
+}
+
+func (r *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct && t.ConvertibleTo(timeType) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.register(t)}
+	default:
+		// interface{} 等无法确定结构的类型
+		return &Schema{}
+	}
+}
+
+// register 登记具名结构体，重名时加上包名区分
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = pkg + "." + name
+	}
+
+	// 先占位再展开字段，自引用字段会直接得到 $ref
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.collectFields(t, "json", func(name string, fs *Schema, required bool) {
+		s.Properties[name] = fs
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	})
+	return s
+}
+
+// queryParameters 按 form 标签把查询结构体展开为查询参数
+func (r *schemaRegistry) queryParameters(v interface{}) []Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []Parameter
+	r.collectFields(t, "form", func(name string, fs *Schema, required bool) {
+		params = append(params, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: fs.Description,
+			Required:    required,
+			Schema:      fs,
+		})
+	})
+	return params
+}
+
+// collectFields 遍历导出字段，匿名嵌入的结构体字段会被展开到外层
+func (r *schemaRegistry) collectFields(t reflect.Type, tagKey string, fn func(name string, s *Schema, required bool)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagKey)
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !ft.ConvertibleTo(timeType) {
+				r.collectFields(ft, tagKey, fn)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fs := r.schemaOf(field.Type)
+		required := false
+		if binding := field.Tag.Get("binding"); binding != "" {
+			// $ref 不能附带约束，只判断是否必填
+			if fs.Ref == "" {
+				required = applyBinding(fs, field.Type, binding)
+			} else {
+				required = hasRule(binding, "required")
+			}
+		}
+		fn(name, fs, required)
+	}
+}
+
+// applyBinding 将 validator 规则转换为 schema 约束，返回字段是否必填
+func applyBinding(s *Schema, t reflect.Type, binding string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		// dive 之后的规则作用于切片元素
+		if rule == "dive" {
+			break
+		}
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				if s.Type == "integer" {
+					if n, err := strconv.Atoi(option); err == nil {
+						s.Enum = append(s.Enum, n)
+						continue
+					}
+				}
+				s.Enum = append(s.Enum, option)
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			isMin := key == "min" || key == "gte"
+			switch t.Kind() {
+			case reflect.String:
+				l := int(n)
+				if isMin {
+					s.MinLength = &l
+				} else {
+					s.MaxLength = &l
+				}
+			case reflect.Slice, reflect.Array, reflect.Map:
+				l := int(n)
+				if isMin {
+					s.MinItems = &l
+				} else {
+					s.MaxItems = &l
+				}
+			default:
+				if isMin {
+					s.Minimum = &n
+				} else {
+					s.Maximum = &n
+				}
+			}
+		}
+	}
+	return required
+}
+
+func hasRule(binding, name string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		if rule == "dive" {
+			return false
+		}
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
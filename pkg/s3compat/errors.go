@@ -0,0 +1,50 @@
+package s3compat
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// Error S3 标准错误，Code 与 AWS 文档保持一致以便客户端识别
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+var (
+	ErrAccessDenied          = &Error{http.StatusForbidden, "AccessDenied", "Access Denied"}
+	ErrInvalidAccessKeyID    = &Error{http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records."}
+	ErrSignatureDoesNotMatch = &Error{http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided."}
+	ErrMalformedAuth         = &Error{http.StatusBadRequest, "AuthorizationHeaderMalformed", "The authorization header is malformed."}
+	ErrMissingAuth           = &Error{http.StatusForbidden, "AccessDenied", "Only AWS Signature Version 4 requests are supported."}
+	ErrRequestTimeTooSkewed  = &Error{http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the server's time is too large."}
+	ErrExpiredPresignRequest = &Error{http.StatusForbidden, "AccessDenied", "Request has expired."}
+	ErrContentSHA256Mismatch = &Error{http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed."}
+	ErrIncompleteBody        = &Error{http.StatusBadRequest, "IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header."}
+	ErrNoSuchBucket          = &Error{http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist."}
+	ErrNoSuchKey             = &Error{http.StatusNotFound, "NoSuchKey", "The specified key does not exist."}
+	ErrInvalidArgument       = &Error{http.StatusBadRequest, "InvalidArgument", "Invalid Argument"}
+	ErrEntityTooLarge        = &Error{http.StatusBadRequest, "EntityTooLarge", "Your proposed upload exceeds the maximum allowed object size."}
+	ErrNotImplemented        = &Error{http.StatusNotImplemented, "NotImplemented", "A header or operation you provided implies functionality that is not implemented."}
+	ErrSlowDown              = &Error{http.StatusServiceUnavailable, "SlowDown", "Please reduce your request rate."}
+	ErrInternal              = &Error{http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again."}
+)
+
+/* WithMessage 复制错误并替换说明 */
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{Status: e.Status, Code: e.Code, Message: message}
+}
+
+// ErrorResponse S3 错误响应体
+type ErrorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource,omitempty"`
+	RequestID string   `xml:"RequestId,omitempty"`
+}
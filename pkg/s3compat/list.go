@@ -0,0 +1,80 @@
+package s3compat
+
+import (
+	"strings"
+	"time"
+)
+
+// ListEntry 参与列举的对象，调用方需按 Key 升序传入
+type ListEntry struct {
+	Key          string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListPage 一页列举结果，NextMarker 为本页最后一个对象键或公共前缀
+type ListPage struct {
+	Contents       []Object
+	CommonPrefixes []CommonPrefix
+	IsTruncated    bool
+	NextMarker     string
+}
+
+/* Paginate 按前缀、分隔符与起始位置切出一页，公共前缀与对象一起计入 maxKeys */
+func Paginate(entries []ListEntry, prefix, delimiter, after string, maxKeys int) ListPage {
+	page := ListPage{Contents: []Object{}, CommonPrefixes: []CommonPrefix{}}
+	if maxKeys <= 0 {
+		return page
+	}
+
+	count := 0
+	lastPrefix := ""
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Key, prefix) {
+			continue
+		}
+
+		item := e.Key
+		isPrefix := false
+		if delimiter != "" {
+			if idx := strings.Index(e.Key[len(prefix):], delimiter); idx >= 0 {
+				item = e.Key[:len(prefix)+idx+len(delimiter)]
+				isPrefix = true
+			}
+		}
+		if item <= after || (isPrefix && item == lastPrefix) {
+			continue
+		}
+
+		if count == maxKeys {
+			page.IsTruncated = true
+			break
+		}
+		count++
+		page.NextMarker = item
+
+		if isPrefix {
+			lastPrefix = item
+			page.CommonPrefixes = append(page.CommonPrefixes, CommonPrefix{Prefix: item})
+			continue
+		}
+		page.Contents = append(page.Contents, Object{
+			Key:          e.Key,
+			LastModified: FormatTime(e.LastModified),
+			ETag:         e.ETag,
+			Size:         e.Size,
+			StorageClass: "STANDARD",
+		})
+	}
+	return page
+}
+
+/* EncodeKey encoding-type=url 时编码对象键，保留路径分隔符 */
+func EncodeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = uriEncode(p)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,160 @@
+package s3compat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// 分块上传官方示例：66560 字节的 'a'，按 64KB 与 1KB 两块加结束块发送
+const (
+	chunkSeedSignature = "4f232c4386841ef735655705268965c44a0e4690baa4adea153f7db9fa80a0a9"
+	chunk1Signature    = "ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648"
+	chunk2Signature    = "0055627c9e194cb4542bae2aa5492e3c1575bbb81b612b7d234b86a503ef5497"
+	chunkFinalSig      = "b6c6ea8a5354eaf15b3cb7646744f4275b71ea724fed81ceb9323e279d449df9"
+)
+
+func chunkedExampleBody(sig1, sig2, sigFinal string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "10000;chunk-signature=%s\r\n%s\r\n", sig1, strings.Repeat("a", 65536))
+	fmt.Fprintf(&b, "400;chunk-signature=%s\r\n%s\r\n", sig2, strings.Repeat("a", 1024))
+	fmt.Fprintf(&b, "0;chunk-signature=%s\r\n\r\n", sigFinal)
+	return b.String()
+}
+
+func newChunkedExampleRequest(body string) *http.Request {
+	return newSignedRequest(http.MethodPut, "/examplebucket/chunkObject.txt", "s3.amazonaws.com", map[string]string{
+		"X-Amz-Date":                   testAmzDate,
+		"X-Amz-Storage-Class":          "REDUCED_REDUNDANCY",
+		"X-Amz-Content-Sha256":         StreamingSignedPayload,
+		"Content-Encoding":             "aws-chunked",
+		"X-Amz-Decoded-Content-Length": "66560",
+		"Content-Length":               "66824",
+		"Authorization": "AWS4-HMAC-SHA256 Credential=" + testCredential +
+			",SignedHeaders=content-encoding;content-length;host;x-amz-content-sha256;x-amz-date;x-amz-decoded-content-length;x-amz-storage-class" +
+			",Signature=" + chunkSeedSignature,
+	}, body)
+}
+
+// TestStreamingSignedPayload 验证分块签名官方示例的种子签名与逐块链式签名
+func TestStreamingSignedPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"官方示例", chunkedExampleBody(chunk1Signature, chunk2Signature, chunkFinalSig), nil},
+		{"中间块签名错误", chunkedExampleBody(chunk1Signature, chunk1Signature, chunkFinalSig), ErrSignatureDoesNotMatch},
+		{"结束块签名错误", chunkedExampleBody(chunk1Signature, chunk2Signature, chunk2Signature), ErrSignatureDoesNotMatch},
+		{"数据被篡改", strings.Replace(chunkedExampleBody(chunk1Signature, chunk2Signature, chunkFinalSig), "\r\naaaa", "\r\nbaaa", 1), ErrSignatureDoesNotMatch},
+		{"缺少结束块", strings.TrimSuffix(chunkedExampleBody(chunk1Signature, chunk2Signature, chunkFinalSig), "0;chunk-signature="+chunkFinalSig+"\r\n\r\n"), ErrIncompleteBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newChunkedExampleRequest(tt.body)
+			sr, err := ParseRequest(r)
+			if err != nil {
+				t.Fatalf("ParseRequest 返回错误: %v", err)
+			}
+			if err := sr.Verify(r, testSecret, testSignedAt); err != nil {
+				t.Fatalf("种子签名校验失败: %v", err)
+			}
+
+			body, size, err := sr.Body(r)
+			if err != nil {
+				t.Fatalf("Body 返回错误: %v", err)
+			}
+			if size != 66560 {
+				t.Errorf("解码后长度为 %d，期望 66560", size)
+			}
+			data, err := io.ReadAll(body)
+			if err != tt.wantErr {
+				t.Fatalf("读取错误为 %v，期望 %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && string(data) != strings.Repeat("a", 66560) {
+				t.Errorf("解码后的数据不正确，长度为 %d", len(data))
+			}
+		})
+	}
+}
+
+// TestStreamingUnsignedTrailer 验证不签名分块与 trailer 头的解码
+func TestStreamingUnsignedTrailer(t *testing.T) {
+	body := "5\r\nhello\r\n6\r\n world\r\n0\r\nx-amz-checksum-crc32:DUoRhQ==\r\n\r\n"
+	r := newSignedRequest(http.MethodPut, "/bucket/key", "s3.amazonaws.com", map[string]string{
+		"X-Amz-Decoded-Content-Length": "11",
+	}, body)
+	sr := &SignedRequest{PayloadHash: StreamingUnsignedTrailer}
+
+	reader, size, err := sr.Body(r)
+	if err != nil {
+		t.Fatalf("Body 返回错误: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("读取错误: %v", err)
+	}
+	if size != 11 || string(data) != "hello world" {
+		t.Errorf("解码结果为 %q（长度 %d），期望 \"hello world\"（长度 11）", data, size)
+	}
+
+	r = newSignedRequest(http.MethodPut, "/bucket/key", "s3.amazonaws.com", nil, body)
+	if _, _, err := sr.Body(r); err == nil {
+		t.Errorf("缺少 x-amz-decoded-content-length 时应返回错误")
+	}
+
+	r = newSignedRequest(http.MethodPut, "/bucket/key", "s3.amazonaws.com", map[string]string{
+		"X-Amz-Decoded-Content-Length": "1",
+	}, fmt.Sprintf("%x\r\n", maxChunkSize+1))
+	reader, _, _ = sr.Body(r)
+	if _, err := io.ReadAll(reader); err != ErrIncompleteBody {
+		t.Errorf("超出单块上限时应返回 IncompleteBody，实际为 %v", err)
+	}
+}
+
+// TestSingleChunkPayload 验证单块请求体在读完时比对摘要
+func TestSingleChunkPayload(t *testing.T) {
+	const payload = "Welcome to Amazon S3."
+	const payloadHash = "44ce7dd67c959e0d3524ffac1771dfbba87d2b6b4b4e99e42034a8b803f8b072"
+
+	tests := []struct {
+		name    string
+		hash    string
+		body    string
+		wantErr error
+	}{
+		{"摘要一致", payloadHash, payload, nil},
+		{"摘要不一致", payloadHash, payload + "!", ErrContentSHA256Mismatch},
+		{"不签名请求体", UnsignedPayload, payload, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSignedRequest(http.MethodPut, "/test%24file.text", "examplebucket.s3.amazonaws.com", nil, tt.body)
+			sr := &SignedRequest{PayloadHash: tt.hash}
+			reader, size, err := sr.Body(r)
+			if err != nil {
+				t.Fatalf("Body 返回错误: %v", err)
+			}
+			if size != int64(len(tt.body)) {
+				t.Errorf("长度为 %d，期望 %d", size, len(tt.body))
+			}
+			data, err := io.ReadAll(reader)
+			if err != tt.wantErr {
+				t.Fatalf("读取错误为 %v，期望 %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !bytes.Equal(data, []byte(tt.body)) {
+				t.Errorf("读取结果为 %q，期望 %q", data, tt.body)
+			}
+		})
+	}
+
+	sr := &SignedRequest{PayloadHash: "not-a-hash"}
+	if _, _, err := sr.Body(newSignedRequest(http.MethodPut, "/a", "examplebucket.s3.amazonaws.com", nil, payload)); err == nil {
+		t.Errorf("无法识别的 x-amz-content-sha256 应返回错误")
+	}
+}
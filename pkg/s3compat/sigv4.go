@@ -0,0 +1,276 @@
+package s3compat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat = "20060102T150405Z"
+	maxClockSkew  = 15 * time.Minute
+	maxPresignTTL = 7 * 24 * time.Hour
+
+	// x-amz-content-sha256 的特殊取值
+	UnsignedPayload          = "UNSIGNED-PAYLOAD"
+	StreamingSignedPayload   = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	StreamingUnsignedTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+)
+
+// emptySHA256 空字符串的 SHA256，用于分块签名
+var emptySHA256 = hashHex(nil)
+
+// SignedRequest 从请求中解析出的 V4 签名信息
+type SignedRequest struct {
+	AccessKeyID   string
+	Date          string // 凭证范围中的日期 yyyymmdd
+	Region        string
+	Service       string
+	SignedHeaders []string
+	Signature     string
+	AmzDate       string
+	PayloadHash   string
+	Presigned     bool
+
+	signingKey []byte
+}
+
+/* ParseRequest 解析 Authorization 头或预签名 URL 中的 V4 签名信息，不校验签名 */
+func ParseRequest(r *http.Request) (*SignedRequest, error) {
+	if r.URL.Query().Get("X-Amz-Algorithm") != "" {
+		return parsePresigned(r)
+	}
+
+	authz := r.Header.Get("Authorization")
+	if authz == "" {
+		return nil, ErrMissingAuth
+	}
+	if !strings.HasPrefix(authz, signAlgorithm+" ") {
+		return nil, ErrMissingAuth
+	}
+
+	sr := &SignedRequest{}
+	for _, part := range strings.Split(strings.TrimPrefix(authz, signAlgorithm+" "), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, ErrMalformedAuth
+		}
+		switch k {
+		case "Credential":
+			if err := sr.parseCredential(v); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sr.SignedHeaders = strings.Split(v, ";")
+		case "Signature":
+			sr.Signature = v
+		}
+	}
+	if sr.AccessKeyID == "" || len(sr.SignedHeaders) == 0 || sr.Signature == "" {
+		return nil, ErrMalformedAuth
+	}
+
+	sr.AmzDate = r.Header.Get("X-Amz-Date")
+	if sr.AmzDate == "" {
+		if d, err := http.ParseTime(r.Header.Get("Date")); err == nil {
+			sr.AmzDate = d.UTC().Format(amzDateFormat)
+		}
+	}
+	sr.PayloadHash = r.Header.Get("X-Amz-Content-Sha256")
+	if sr.PayloadHash == "" {
+		return nil, ErrMalformedAuth.WithMessage("Missing required header x-amz-content-sha256.")
+	}
+	return sr, nil
+}
+
+func parsePresigned(r *http.Request) (*SignedRequest, error) {
+	q := r.URL.Query()
+	if q.Get("X-Amz-Algorithm") != signAlgorithm {
+		return nil, ErrMissingAuth
+	}
+
+	sr := &SignedRequest{
+		SignedHeaders: strings.Split(q.Get("X-Amz-SignedHeaders"), ";"),
+		Signature:     q.Get("X-Amz-Signature"),
+		AmzDate:       q.Get("X-Amz-Date"),
+		PayloadHash:   UnsignedPayload,
+		Presigned:     true,
+	}
+	if err := sr.parseCredential(q.Get("X-Amz-Credential")); err != nil {
+		return nil, err
+	}
+	if sr.Signature == "" || sr.AmzDate == "" {
+		return nil, ErrMalformedAuth
+	}
+	return sr, nil
+}
+
+// parseCredential 格式：AKID/yyyymmdd/region/s3/aws4_request
+func (sr *SignedRequest) parseCredential(v string) error {
+	parts := strings.Split(v, "/")
+	if len(parts) != 5 || parts[4] != "aws4_request" {
+		return ErrMalformedAuth
+	}
+	sr.AccessKeyID, sr.Date, sr.Region, sr.Service = parts[0], parts[1], parts[2], parts[3]
+	return nil
+}
+
+/* Verify 使用密钥校验签名与请求时间，预签名请求同时检查有效期 */
+func (sr *SignedRequest) Verify(r *http.Request, secret string, now time.Time) error {
+	signedAt, err := time.Parse(amzDateFormat, sr.AmzDate)
+	if err != nil || !strings.HasPrefix(sr.AmzDate, sr.Date) {
+		return ErrMalformedAuth
+	}
+
+	if sr.Presigned {
+		expires, err := strconv.Atoi(r.URL.Query().Get("X-Amz-Expires"))
+		if err != nil || expires <= 0 || time.Duration(expires)*time.Second > maxPresignTTL {
+			return ErrMalformedAuth
+		}
+		if now.After(signedAt.Add(time.Duration(expires) * time.Second)) {
+			return ErrExpiredPresignRequest
+		}
+		if signedAt.Sub(now) > maxClockSkew {
+			return ErrRequestTimeTooSkewed
+		}
+	} else if d := now.Sub(signedAt); d > maxClockSkew || d < -maxClockSkew {
+		return ErrRequestTimeTooSkewed
+	}
+
+	sr.signingKey = deriveSigningKey(secret, sr.Date, sr.Region, sr.Service)
+	expected := hex.EncodeToString(hmacSHA256(sr.signingKey, sr.stringToSign(r)))
+	if !hmac.Equal([]byte(expected), []byte(sr.Signature)) {
+		return ErrSignatureDoesNotMatch
+	}
+	return nil
+}
+
+func (sr *SignedRequest) scope() string {
+	return sr.Date + "/" + sr.Region + "/" + sr.Service + "/aws4_request"
+}
+
+func (sr *SignedRequest) stringToSign(r *http.Request) string {
+	return signAlgorithm + "\n" + sr.AmzDate + "\n" + sr.scope() + "\n" + hashHex([]byte(sr.canonicalRequest(r)))
+}
+
+func (sr *SignedRequest) canonicalRequest(r *http.Request) string {
+	// 使用客户端发送的原始路径，S3 签名不对路径做二次规范化
+	path := r.RequestURI
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	var headers strings.Builder
+	for _, name := range sr.SignedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(canonicalHeaderValue(r, name))
+		headers.WriteByte('\n')
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		path,
+		canonicalQuery(r.URL.RawQuery, sr.Presigned),
+		headers.String(),
+		strings.Join(sr.SignedHeaders, ";"),
+		sr.PayloadHash,
+	}, "\n")
+}
+
+func canonicalHeaderValue(r *http.Request, name string) string {
+	switch name {
+	case "host":
+		return r.Host
+	case "content-length":
+		if v := r.Header.Get("Content-Length"); v != "" {
+			return v
+		}
+		return strconv.FormatInt(r.ContentLength, 10)
+	}
+
+	values := r.Header.Values(name)
+	for i, v := range values {
+		values[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(values, ",")
+}
+
+// canonicalQuery 按键名排序并重新编码，预签名请求去掉签名本身
+func canonicalQuery(rawQuery string, presigned bool) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	type pair struct{ k, v string }
+	var pairs []pair
+	for _, item := range strings.Split(rawQuery, "&") {
+		if item == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(item, "=")
+		k, _ = url.PathUnescape(k)
+		v, _ = url.PathUnescape(v)
+		if presigned && k == "X-Amz-Signature" {
+			continue
+		}
+		pairs = append(pairs, pair{uriEncode(k), uriEncode(v)})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].k != pairs[j].k {
+			return pairs[i].k < pairs[j].k
+		}
+		return pairs[i].v < pairs[j].v
+	})
+
+	parts := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		parts = append(parts, p.k+"="+p.v)
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode 按 AWS 规则编码，除 A-Z a-z 0-9 - _ . ~ 外全部百分号编码
+func uriEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
+
+func deriveSigningKey(secret, date, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secret), date)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
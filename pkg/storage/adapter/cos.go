@@ -374,7 +374,11 @@ func (a *COSAdapter) generateThumbnail(src io.Reader, req *UploadRequest, origin
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to read source data: %w", err)
 	}
+	return a.generateThumbnailFromData(data, req)
+}
 
+// generateThumbnailFromData 基于原图数据生成并上传缩略图
+func (a *COSAdapter) generateThumbnailFromData(data []byte, req *UploadRequest) (string, string, string, error) {
 	// SVG 特判：直接拷贝为缩略图
 	if strings.EqualFold(strings.TrimPrefix(strings.ToLower(filepath.Ext(req.FileName)), "."), "svg") {
 		thumbFileName := utils.MakeThumbName(req.FileName, "svg")
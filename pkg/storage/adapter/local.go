@@ -164,7 +164,7 @@ func (a *LocalAdapter) Upload(ctx context.Context, req *UploadRequest) (*UploadR
 	return result, nil
 }
 
-// Delete 删除文件
+// Delete 删除文件，路径中含有 .. 或解析后不在 basePath/thumbnailPath 之内时拒绝删除
 func (a *LocalAdapter) Delete(ctx context.Context, path string) error {
 	if !a.initialized {
 		return NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
@@ -174,18 +174,22 @@ func (a *LocalAdapter) Delete(ctx context.Context, path string) error {
 	if p == "" {
 		return nil
 	}
+	if hasParentSegment(p) {
+		return NewStorageError(ErrorTypePermission, "path contains parent directory reference", nil)
+	}
 
 	if strings.HasPrefix(p, "thumbnails/") || strings.HasPrefix(p, "/thumbnails/") {
 		tclean := strings.TrimPrefix(p, "/")
 		tclean = strings.TrimPrefix(tclean, "thumbnails/")
-		_ = os.Remove(filepath.Join(a.thumbnailPath, tclean))
-		return nil
+		return removeWithin(a.thumbnailPath, filepath.Join(a.thumbnailPath, tclean))
 	}
 
 	if filepath.IsAbs(p) {
-		if strings.HasPrefix(p, a.basePath) || strings.HasPrefix(p, a.thumbnailPath) {
-			_ = os.Remove(p)
-			return nil
+		if isWithin(a.basePath, p) {
+			return removeWithin(a.basePath, p)
+		}
+		if isWithin(a.thumbnailPath, p) {
+			return removeWithin(a.thumbnailPath, p)
 		}
 		// 非本适配器目录，忽略
 		return nil
@@ -195,25 +199,57 @@ func (a *LocalAdapter) Delete(ctx context.Context, path string) error {
 	// 如果是对象键 files/
 	if strings.HasPrefix(clean, "files/") {
 		clean = strings.TrimPrefix(clean, "files/")
-		_ = os.Remove(filepath.Join(a.basePath, clean))
-		return nil
+		return removeWithin(a.basePath, filepath.Join(a.basePath, clean))
 	}
 	// 如果是缩略图物理相对路径（带有 thumbnailPath 前缀）
 	thumbRelBase := strings.TrimPrefix(filepath.Clean(a.thumbnailPath), "/")
 	if strings.HasPrefix(clean, thumbRelBase+"/") {
 		rel := strings.TrimPrefix(clean, thumbRelBase+"/")
-		_ = os.Remove(filepath.Join(a.thumbnailPath, rel))
-		return nil
+		return removeWithin(a.thumbnailPath, filepath.Join(a.thumbnailPath, rel))
 	}
 	// 如果是原图物理相对路径（带有 basePath 前缀）
 	baseRel := strings.TrimPrefix(filepath.Clean(a.basePath), "/")
 	if strings.HasPrefix(clean, baseRel+"/") {
 		rel := strings.TrimPrefix(clean, baseRel+"/")
-		_ = os.Remove(filepath.Join(a.basePath, rel))
-		return nil
+		return removeWithin(a.basePath, filepath.Join(a.basePath, rel))
 	}
 	// 否则作为相对路径直接拼到 basePath
-	_ = os.Remove(filepath.Join(a.basePath, clean))
+	return removeWithin(a.basePath, filepath.Join(a.basePath, clean))
+}
+
+// hasParentSegment 路径中是否含有 .. 段，同时按 / 与 \ 分隔
+func hasParentSegment(p string) bool {
+	for _, seg := range strings.FieldsFunc(p, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin target 解析为绝对路径后是否位于 root 之下（不含 root 本身）
+func isWithin(root, target string) bool {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return false
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absRoot, absTarget)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return true
+}
+
+// removeWithin 确认目标位于 root 之下后删除，文件不存在时视为成功
+func removeWithin(root, target string) error {
+	if !isWithin(root, target) {
+		return NewStorageError(ErrorTypePermission, "path resolves outside storage directory", nil)
+	}
+	_ = os.Remove(target)
 	return nil
 }
 
@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectLister 可选接口：支持直接列举存储中的对象
+type ObjectLister interface {
+	// ListObjects 按前缀分页列举对象，cursor 为上一页返回的 NextCursor
+	ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error)
+}
+
+// ObjectInfo 存储对象信息，Key 与上传结果中的 OriginalPath/ThumbnailPath 一致
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ListObjectsResult 对象列举结果
+type ListObjectsResult struct {
+	Objects    []ObjectInfo `json:"objects"`
+	NextCursor string       `json:"next_cursor"`
+	HasMore    bool         `json:"has_more"`
+}
+
+// ListObjects 遍历原图与缩略图目录，按路径排序后以最后一个键作为游标
+func (a *LocalAdapter) ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+
+	var keys []ObjectInfo
+	for _, root := range []string{a.basePath, a.thumbnailPath} {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if d.IsDir() {
+				return nil
+			}
+			key := filepath.ToSlash(p)
+			if !strings.HasPrefix(key, prefix) || key <= cursor {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			keys = append(keys, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return nil, NewStorageError(ErrorTypeInternal, "failed to list local objects", err)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+
+	result := &ListObjectsResult{Objects: keys}
+	if len(keys) > limit {
+		result.Objects = keys[:limit]
+		result.HasMore = true
+		result.NextCursor = keys[limit-1].Key
+	}
+	return result, nil
+}
+
+// ListObjects 使用 ListObjectsV2 的 ContinuationToken 作为游标
+func (a *S3Adapter) ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return listS3Objects(ctx, a.client, a.bucket, prefix, cursor, limit)
+}
+
+// ListObjects R2 与 S3 列举方式一致
+func (a *R2Adapter) ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return listS3Objects(ctx, a.client, a.bucket, prefix, cursor, limit)
+}
+
+// ListObjects 雨云 ROS 与 S3 列举方式一致
+func (a *RainyunAdapter) ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return listS3Objects(ctx, a.client, a.bucket, prefix, cursor, limit)
+}
+
+func listS3Objects(ctx context.Context, client *s3.Client, bucket, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int32(int32(limit)),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if cursor != "" {
+		input.ContinuationToken = aws.String(cursor)
+	}
+
+	out, err := client.ListObjectsV2(ctx, input)
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to list objects", err)
+	}
+
+	result := &ListObjectsResult{Objects: make([]ObjectInfo, 0, len(out.Contents))}
+	for _, obj := range out.Contents {
+		info := ObjectInfo{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+		if obj.LastModified != nil {
+			info.LastModified = *obj.LastModified
+		}
+		result.Objects = append(result.Objects, info)
+	}
+	if aws.ToBool(out.IsTruncated) {
+		result.HasMore = true
+		result.NextCursor = aws.ToString(out.NextContinuationToken)
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package adapter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectWriter 可选接口：按指定键原样写入对象，不经过图片处理和用户目录规则，用于备份等非图片数据
+type ObjectWriter interface {
+	PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error
+}
+
+// PutObject 直接写入 S3 对象
+func (a *S3Adapter) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	if !a.initialized {
+		return NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return putS3Object(ctx, a.client, a.bucket, key, body, size, contentType)
+}
+
+// PutObject R2 与 S3 写入方式一致
+func (a *R2Adapter) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	if !a.initialized {
+		return NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return putS3Object(ctx, a.client, a.bucket, key, body, size, contentType)
+}
+
+// PutObject 雨云 ROS 与 S3 写入方式一致
+func (a *RainyunAdapter) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	if !a.initialized {
+		return NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	return putS3Object(ctx, a.client, a.bucket, key, body, size, contentType)
+}
+
+func putS3Object(ctx context.Context, client *s3.Client, bucket, key string, body io.ReadSeeker, size int64, contentType string) error {
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return NewStorageError(ErrorTypeNetwork, "failed to put object", err)
+	}
+	return nil
+}
+
+// PutObject 通过 WebDAV PUT 写入对象，大文件上传不受默认请求超时限制，由 ctx 控制
+func (a *WebDAVAdapter) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	if !a.initialized {
+		return NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	full := a.fullKey(key)
+	if a.autoMkdir {
+		_ = a.mkcolParents(ctx, full)
+	}
+
+	u := a.resourceURL(full)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), io.NopCloser(body))
+	if err != nil {
+		return NewStorageError(ErrorTypeInternal, "failed to build request", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	a.basicAuth(req)
+
+	client := *a.httpClient
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return NewStorageError(ErrorTypeNetwork, "webdav put failed", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return NewStorageError(ErrorTypeInternal, "webdav put failed", fmt.Errorf("%s: %s", resp.Status, string(b)))
+	}
+	return nil
+}
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ContentLength int64  `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+				ResourceType  struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// ListObjects 使用 PROPFIND 列举前缀所在目录的文件（不递归子目录），按键排序后以最后一个键作为游标
+func (a *WebDAVAdapter) ListObjects(ctx context.Context, prefix, cursor string, limit int) (*ListObjectsResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+
+	dir := ""
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = prefix[:i]
+	}
+	u := a.resourceURL(a.fullKey(dir))
+	u.Path = strings.TrimRight(u.Path, "/") + "/"
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", u.String(), strings.NewReader(
+		`<?xml version="1.0" encoding="utf-8"?><propfind xmlns="DAV:"><prop><getcontentlength/><getlastmodified/><resourcetype/></prop></propfind>`))
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeInternal, "failed to build request", err)
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml")
+	a.basicAuth(req)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to list objects", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return &ListObjectsResult{Objects: []ObjectInfo{}}, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to list objects", fmt.Errorf("%s: %s", resp.Status, string(b)))
+	}
+
+	var ms webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, NewStorageError(ErrorTypeInternal, "failed to parse PROPFIND response", err)
+	}
+
+	result := &ListObjectsResult{Objects: []ObjectInfo{}}
+	for _, r := range ms.Responses {
+		if len(r.Propstat) == 0 || r.Propstat[0].Prop.ResourceType.Collection != nil {
+			continue
+		}
+		hrefPath := r.Href
+		if parsed, err := url.Parse(r.Href); err == nil {
+			hrefPath = parsed.Path
+		}
+		key := path.Base(hrefPath)
+		if dir != "" {
+			key = dir + "/" + key
+		}
+		if !strings.HasPrefix(key, prefix) || (cursor != "" && key <= cursor) {
+			continue
+		}
+		prop := r.Propstat[0].Prop
+		info := ObjectInfo{Key: key, Size: prop.ContentLength}
+		if t, err := time.Parse(time.RFC1123, prop.LastModified); err == nil {
+			info.LastModified = t
+		}
+		result.Objects = append(result.Objects, info)
+	}
+	sort.Slice(result.Objects, func(i, j int) bool { return result.Objects[i].Key < result.Objects[j].Key })
+	if limit > 0 && len(result.Objects) > limit {
+		result.Objects = result.Objects[:limit]
+		result.HasMore = true
+		result.NextCursor = result.Objects[limit-1].Key
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package adapter
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"pixelpunk/pkg/imagex/iox"
+	"pixelpunk/pkg/storage/utils"
+)
+
+// ThumbnailRegenerator 可选接口：基于已保存的原图重新生成缩略图，用于上传时缩略图生成失败后的重试。
+// req 提供用户、文件夹、文件名与缩略图尺寸，结果只填写缩略图相关字段
+type ThumbnailRegenerator interface {
+	RegenerateThumbnail(ctx context.Context, originalPath string, req *UploadRequest) (*UploadResult, error)
+}
+
+// RegenerateThumbnail 读取本地原图重新生成缩略图，originalPath 须位于存储目录内
+func (a *LocalAdapter) RegenerateThumbnail(ctx context.Context, originalPath string, req *UploadRequest) (*UploadResult, error) {
+	if !a.initialized {
+		return nil, NewStorageError(ErrorTypeInternal, "adapter not initialized", nil)
+	}
+	fullPath := originalPath
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(a.basePath, strings.TrimPrefix(strings.TrimPrefix(originalPath, "/"), "files/"))
+	}
+	if hasParentSegment(originalPath) || !isWithin(a.basePath, fullPath) {
+		return nil, NewStorageError(ErrorTypePermission, "path is outside the storage directory", nil)
+	}
+
+	_, thumbPath, thumbURL, err := a.generateThumbnailBeforeWebP(fullPath, req, "", "")
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeInternal, "failed to regenerate thumbnail", err)
+	}
+	return &UploadResult{
+		ThumbnailPath: thumbPath,
+		ThumbnailURL:  thumbURL,
+		FullThumbURL:  a.buildFullURL(thumbURL, true),
+	}, nil
+}
+
+// RegenerateThumbnail 下载 COS 原图重新生成并上传缩略图
+func (a *COSAdapter) RegenerateThumbnail(ctx context.Context, originalPath string, req *UploadRequest) (*UploadResult, error) {
+	rc, err := a.ReadFile(ctx, originalPath)
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to read original object", err)
+	}
+	defer rc.Close()
+	data, err := iox.ReadAllWithLimit(rc, iox.DefaultMaxReadBytes)
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to read original object", err)
+	}
+
+	thumbPath, _, thumbRemoteURL, err := a.generateThumbnailFromData(data, req)
+	if err != nil {
+		return nil, NewStorageError(ErrorTypeNetwork, "failed to regenerate thumbnail", err)
+	}
+	thumbFormat := strings.TrimPrefix(strings.ToLower(filepath.Ext(thumbPath)), ".")
+	if thumbFormat == "" {
+		thumbFormat = "jpg"
+	}
+	return &UploadResult{
+		ThumbnailPath:  thumbPath,
+		ThumbnailURL:   utils.BuildLogicalPath(req.FolderPath, utils.MakeThumbName(req.FileName, thumbFormat)),
+		FullThumbURL:   thumbRemoteURL,
+		RemoteThumbURL: thumbPath,
+	}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"pixelpunk/internal/models"
 	"pixelpunk/pkg/storage/adapter"
 	"pixelpunk/pkg/storage/factory"
+	"pixelpunk/pkg/tracing"
 )
 
 // ChannelRepository 渠道仓库接口
@@ -123,7 +124,7 @@ func (m *StorageManager) Upload(ctx context.Context, channelID string, req *adap
 		return nil, fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
 	}
 
-	return adapterInstance.Upload(ctx, req)
+	return uploadWithSpan(ctx, channelID, adapterInstance, req)
 }
 
 // UploadWithDefault 使用默认适配器上传
@@ -133,7 +134,7 @@ func (m *StorageManager) UploadWithDefault(ctx context.Context, req *adapter.Upl
 		return nil, fmt.Errorf("failed to get default adapter: %w", err)
 	}
 
-	return adapterInstance.Upload(ctx, req)
+	return uploadWithSpan(ctx, "default", adapterInstance, req)
 }
 
 // UploadWithBest 使用最佳适配器上传
@@ -143,7 +144,7 @@ func (m *StorageManager) UploadWithBest(ctx context.Context, req *adapter.Upload
 		return nil, fmt.Errorf("failed to get best adapter: %w", err)
 	}
 
-	return adapterInstance.Upload(ctx, req)
+	return uploadWithSpan(ctx, "best", adapterInstance, req)
 }
 
 // Delete 删除文件
@@ -153,7 +154,12 @@ func (m *StorageManager) Delete(ctx context.Context, channelID, path string) err
 		return fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
 	}
 
-	return adapterInstance.Delete(ctx, path)
+	ctx, span := startSpan(ctx, "delete", channelID, adapterInstance, tracing.Attr("storage.path", path))
+	defer span.End()
+
+	err = adapterInstance.Delete(ctx, path)
+	span.RecordError(err)
+	return err
 }
 
 func (m *StorageManager) GetURL(channelID, path string, options *adapter.URLOptions) (string, error) {
@@ -297,8 +303,11 @@ func (m *StorageManager) GetBase64(ctx context.Context, channelID, path string)
 	if err != nil {
 		return "", fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
 	}
+	ctx, span := startSpan(ctx, "read", channelID, adapterInstance, tracing.Attr("storage.path", path))
+	defer span.End()
 	rc, err := adapterInstance.ReadFile(ctx, path)
 	if err != nil {
+		span.RecordError(err)
 		return "", err
 	}
 	defer rc.Close()
@@ -316,8 +325,11 @@ func (m *StorageManager) GetThumbnailBase64(ctx context.Context, channelID, path
 	if err != nil {
 		return "", fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
 	}
+	ctx, span := startSpan(ctx, "read", channelID, adapterInstance, tracing.Attr("storage.path", path))
+	defer span.End()
 	rc, err := adapterInstance.ReadFile(ctx, path)
 	if err != nil {
+		span.RecordError(err)
 		return "", err
 	}
 	defer rc.Close()
@@ -335,5 +347,29 @@ func (m *StorageManager) ReadFile(ctx context.Context, channelID, path string) (
 		return nil, fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
 	}
 
-	return adapterInstance.ReadFile(ctx, path)
+	ctx, span := startSpan(ctx, "read", channelID, adapterInstance, tracing.Attr("storage.path", path))
+	defer span.End()
+
+	rc, err := adapterInstance.ReadFile(ctx, path)
+	span.RecordError(err)
+	return rc, err
+}
+
+// RegenerateThumbnail 基于已保存的原图重新生成缩略图，渠道适配器不支持时返回错误
+func (m *StorageManager) RegenerateThumbnail(ctx context.Context, channelID, originalPath string, req *adapter.UploadRequest) (*adapter.UploadResult, error) {
+	adapterInstance, err := m.GetAdapter(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adapter for channel %s: %w", channelID, err)
+	}
+	regenerator, ok := adapterInstance.(adapter.ThumbnailRegenerator)
+	if !ok {
+		return nil, fmt.Errorf("storage type %s does not support thumbnail regeneration", adapterInstance.GetType())
+	}
+
+	ctx, span := startSpan(ctx, "regenerate_thumbnail", channelID, adapterInstance, tracing.Attr("storage.path", originalPath))
+	defer span.End()
+
+	result, err := regenerator.RegenerateThumbnail(ctx, originalPath, req)
+	span.RecordError(err)
+	return result, err
 }
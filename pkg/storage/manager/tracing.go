@@ -0,0 +1,38 @@
+package manager
+
+import (
+	"context"
+
+	"pixelpunk/pkg/storage/adapter"
+	"pixelpunk/pkg/tracing"
+)
+
+// startSpan 为一次存储操作创建客户端 Span，记录渠道与适配器类型
+func startSpan(ctx context.Context, op, channelID string, a adapter.StorageAdapter, attrs ...tracing.Attribute) (context.Context, *tracing.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attrs = append(attrs,
+		tracing.Attr("storage.operation", op),
+		tracing.Attr("storage.channel_id", channelID),
+		tracing.Attr("storage.type", a.GetType()),
+	)
+	return tracing.Start(ctx, "storage."+op, tracing.WithKind(tracing.SpanKindClient), tracing.WithAttributes(attrs...))
+}
+
+// uploadWithSpan 在 Span 中执行上传
+func uploadWithSpan(ctx context.Context, channelID string, a adapter.StorageAdapter, req *adapter.UploadRequest) (*adapter.UploadResult, error) {
+	size := int64(len(req.ProcessedData))
+	if size == 0 && req.File != nil {
+		size = req.File.Size
+	}
+	ctx, span := startSpan(ctx, "upload", channelID, a,
+		tracing.Attr("storage.object_size", size),
+		tracing.Attr("storage.content_type", req.ContentType),
+	)
+	defer span.End()
+
+	result, err := a.Upload(ctx, req)
+	span.RecordError(err)
+	return result, err
+}
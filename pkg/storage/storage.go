@@ -160,6 +160,35 @@ func (s *Storage) Upload(ctx context.Context, req *UploadRequest) (*UploadResult
 	}, nil
 }
 
+// RegenerateThumbnail 基于已保存的原图重新生成缩略图，req 中的 File 与 ProcessedData 不使用
+func (s *Storage) RegenerateThumbnail(ctx context.Context, channelID, originalPath string, req *UploadRequest) (*UploadResult, error) {
+	result, err := s.manager.RegenerateThumbnail(ctx, channelID, originalPath, &adapter.UploadRequest{
+		UserID:     req.UserID,
+		FolderPath: req.FolderPath,
+		FileName:   req.FileName,
+		Options: &adapter.UploadOptions{
+			Quality:       req.Quality,
+			MaxWidth:      req.MaxWidth,
+			MaxHeight:     req.MaxHeight,
+			GenerateThumb: true,
+			ThumbWidth:    req.ThumbWidth,
+			ThumbHeight:   req.ThumbHeight,
+			ThumbQuality:  req.ThumbQuality,
+			Compress:      req.Compress,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UploadResult{
+		ThumbnailPath:  result.ThumbnailPath,
+		ThumbnailURL:   result.ThumbnailURL,
+		FullThumbURL:   result.FullThumbURL,
+		RemoteThumbURL: result.RemoteThumbURL,
+		ChannelID:      channelID,
+	}, nil
+}
+
 // UploadWithDefault 使用默认渠道上传
 func (s *Storage) UploadWithDefault(ctx context.Context, req *UploadRequest) (*UploadResult, error) {
 	req.ChannelID = "" // 确保使用默认渠道
@@ -0,0 +1,209 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const apiBase = "https://api.telegram.org"
+
+// Client Telegram Bot API 客户端，只实现上传机器人需要的方法
+type Client struct {
+	token string
+	http  *http.Client
+}
+
+// User Telegram 用户或机器人
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+// Chat 会话
+type Chat struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Username string `json:"username"`
+}
+
+// PhotoSize 图片的一种尺寸，Telegram 按尺寸从小到大返回
+type PhotoSize struct {
+	FileID   string `json:"file_id"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	FileSize int64  `json:"file_size"`
+}
+
+// Document 以文件形式发送的附件，保留原图不压缩
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	FileSize int64  `json:"file_size"`
+}
+
+// Message 消息
+type Message struct {
+	MessageID int64       `json:"message_id"`
+	From      *User       `json:"from"`
+	Chat      Chat        `json:"chat"`
+	Date      int64       `json:"date"`
+	Text      string      `json:"text"`
+	Caption   string      `json:"caption"`
+	Photo     []PhotoSize `json:"photo"`
+	Document  *Document   `json:"document"`
+}
+
+// Update 机器人收到的更新
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+// File getFile 返回的文件信息
+type File struct {
+	FileID   string `json:"file_id"`
+	FileSize int64  `json:"file_size"`
+	FilePath string `json:"file_path"`
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+	ErrorCode   int             `json:"error_code"`
+}
+
+// APIError Bot API 返回的错误
+type APIError struct {
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+/* NewClient 创建客户端，长轮询请求的超时由调用方的 context 控制 */
+func NewClient(token string) *Client {
+	return &Client{
+		token: token,
+		http:  &http.Client{Timeout: 90 * time.Second},
+	}
+}
+
+/* GetMe 获取机器人自身信息，可用于校验 Token */
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.call(ctx, "getMe", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+/* GetUpdates 长轮询获取更新，offset 为已处理的最大 update_id + 1 */
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeoutSeconds int) ([]Update, error) {
+	params := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeoutSeconds,
+		"allowed_updates": []string{"message"},
+	}
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+/* SendMessage 发送文本消息，replyTo 为 0 时不引用原消息 */
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string, replyTo int64) error {
+	params := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if replyTo > 0 {
+		params["reply_to_message_id"] = replyTo
+	}
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+/* GetFile 获取文件下载路径 */
+func (c *Client) GetFile(ctx context.Context, fileID string) (*File, error) {
+	var file File
+	if err := c.call(ctx, "getFile", map[string]interface{}{"file_id": fileID}, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+/* DownloadFile 下载 GetFile 返回的文件，调用方负责关闭 */
+func (c *Client) DownloadFile(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBase+"/file/bot"+c.token+"/"+strings.TrimPrefix(filePath, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, stripURL(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &APIError{Code: resp.StatusCode, Description: "download failed"}
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	var body io.Reader
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+"/bot"+c.token+"/"+method, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return stripURL(err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("decode telegram response: %w", err)
+	}
+	if !apiResp.OK {
+		return &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+	}
+	if result != nil && len(apiResp.Result) > 0 {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
+
+// stripURL 去掉错误中的请求地址，避免 Bot Token 出现在日志中
+func stripURL(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return fmt.Errorf("%s %s: %w", urlErr.Op, apiBase, urlErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,338 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 4096
+	defaultBatchSize     = 512
+	defaultFlushInterval = 5 * time.Second
+	exportTimeout        = 10 * time.Second
+)
+
+// Config 链路追踪配置
+type Config struct {
+	Endpoint    string            // OTLP/HTTP 地址，如 http://otel-collector:4318，未包含路径时追加 /v1/traces
+	ServiceName string            // 服务名
+	Version     string            // 服务版本
+	SampleRatio float64           // 采样比例 0-1
+	Headers     map[string]string // 导出请求附加的请求头，如鉴权信息
+}
+
+/* Init 按配置开启链路追踪并启动后台导出，重复调用会替换之前的配置 */
+func Init(cfg Config) error {
+	endpoint := strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if endpoint == "" {
+		return fmt.Errorf("tracing endpoint is empty")
+	}
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	cfg.Endpoint = endpoint
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "pixelpunk"
+	}
+
+	t := &tracer{cfg: cfg, exporter: newExporter(cfg)}
+	if old := current.Swap(t); old != nil {
+		old.exporter.shutdown(context.Background())
+	}
+	return nil
+}
+
+/* Shutdown 停止追踪并导出缓冲中的 Span */
+func Shutdown(ctx context.Context) {
+	if old := current.Swap(nil); old != nil {
+		old.exporter.shutdown(ctx)
+	}
+}
+
+// exporter 批量导出器，缓冲满或定时将 Span 以 OTLP JSON 发送
+type exporter struct {
+	cfg    Config
+	client *http.Client
+	queue  chan *Span
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	dropped atomic.Int64
+}
+
+func newExporter(cfg Config) *exporter {
+	e := &exporter{
+		cfg:    cfg,
+		client: &http.Client{Timeout: exportTimeout},
+		queue:  make(chan *Span, defaultQueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go e.loop()
+	return e
+}
+
+// enqueue 队列已满时丢弃，不阻塞业务请求
+func (e *exporter) enqueue(s *Span) {
+	select {
+	case e.queue <- s:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+func (e *exporter) loop() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, defaultBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		e.export(batch)
+		batch = make([]*Span, 0, defaultBatchSize)
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= defaultBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if n := e.dropped.Swap(0); n > 0 {
+				reportError(fmt.Errorf("span queue full, dropped %d spans", n))
+			}
+		case <-e.stop:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *exporter) shutdown(ctx context.Context) {
+	e.once.Do(func() { close(e.stop) })
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+}
+
+func (e *exporter) export(spans []*Span) {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		reportError(fmt.Errorf("export %d spans: %w", len(spans), err))
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		reportError(fmt.Errorf("export %d spans: collector returned %s", len(spans), resp.Status))
+	}
+}
+
+// ErrorHandler 导出失败时的回调，默认忽略，由调用方接入日志
+var ErrorHandler func(err error)
+
+func reportError(err error) {
+	if ErrorHandler != nil {
+		ErrorHandler(err)
+	}
+}
+
+// 以下结构对应 OTLP ExportTraceServiceRequest 的 JSON 编码，ID 使用十六进制，64 位整数使用字符串
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *exporter) buildRequest(spans []*Span) otlpRequest {
+	resource := []otlpKeyValue{
+		toKeyValue(Attr("service.name", e.cfg.ServiceName)),
+		toKeyValue(Attr("telemetry.sdk.name", "pixelpunk-tracing")),
+		toKeyValue(Attr("telemetry.sdk.language", "go")),
+	}
+	if e.cfg.Version != "" {
+		resource = append(resource, toKeyValue(Attr("service.version", e.cfg.Version)))
+	}
+
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, s.toOTLP())
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: resource},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "pixelpunk", Version: e.cfg.Version},
+			Spans: out,
+		}},
+	}}}
+}
+
+func (s *Span) toOTLP() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := otlpSpan{
+		TraceID:           s.ctx.TraceID.String(),
+		SpanID:            s.ctx.SpanID.String(),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        toKeyValues(s.attributes),
+		Status:            otlpStatus{Code: s.status, Message: s.statusMsg},
+	}
+	if s.parent.IsValid() {
+		span.ParentSpanID = s.parent.String()
+	}
+	for _, ev := range s.events {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(ev.time.UnixNano(), 10),
+			Name:         ev.name,
+			Attributes:   toKeyValues(ev.attributes),
+		})
+	}
+	return span
+}
+
+func toKeyValues(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		out = append(out, toKeyValue(a))
+	}
+	return out
+}
+
+func toKeyValue(a Attribute) otlpKeyValue {
+	var v otlpValue
+	switch val := a.Value.(type) {
+	case string:
+		v.StringValue = &val
+	case bool:
+		v.BoolValue = &val
+	case int:
+		s := strconv.FormatInt(int64(val), 10)
+		v.IntValue = &s
+	case int32:
+		s := strconv.FormatInt(int64(val), 10)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(val, 10)
+		v.IntValue = &s
+	case uint:
+		s := strconv.FormatUint(uint64(val), 10)
+		v.IntValue = &s
+	case uint32:
+		s := strconv.FormatUint(uint64(val), 10)
+		v.IntValue = &s
+	case uint64:
+		s := strconv.FormatUint(val, 10)
+		v.IntValue = &s
+	case float32:
+		f := float64(val)
+		v.DoubleValue = &f
+	case float64:
+		v.DoubleValue = &val
+	default:
+		s := fmt.Sprint(val)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{Key: a.Key, Value: v}
+}
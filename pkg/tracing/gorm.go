@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// 单条 SQL 记录在 Span 中的最大长度
+const maxStatementLength = 2000
+
+// GormPlugin 为携带链路上下文的数据库操作创建子 Span，
+// 仅在 db.WithContext 传入的 ctx 已有 Span 时记录，避免后台查询产生大量孤立链路
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	register := []struct {
+		name   string
+		before func(name string, fn func(*gorm.DB)) error
+		after  func(name string, fn func(*gorm.DB)) error
+		op     string
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register, "INSERT"},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register, "SELECT"},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register, "UPDATE"},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register, "DELETE"},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register, "ROW"},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register, "RAW"},
+	}
+	for _, r := range register {
+		op := r.op
+		if err := r.before("tracing:before_"+r.name, func(tx *gorm.DB) { beforeStatement(tx, op) }); err != nil {
+			return err
+		}
+		if err := r.after("tracing:after_"+r.name, afterStatement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func beforeStatement(tx *gorm.DB, op string) {
+	if !Enabled() || tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	if SpanFromContext(tx.Statement.Context) == nil {
+		return
+	}
+
+	name := "db " + op
+	if tx.Statement.Table != "" {
+		name += " " + tx.Statement.Table
+	}
+	_, span := Start(tx.Statement.Context, name,
+		WithKind(SpanKindClient),
+		WithAttributes(
+			Attr("db.system", tx.Dialector.Name()),
+			Attr("db.operation.name", op),
+			Attr("db.collection.name", tx.Statement.Table),
+		))
+	if span != nil {
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func afterStatement(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(*Span)
+	if !ok || span == nil {
+		return
+	}
+
+	statement := tx.Statement.SQL.String()
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+	span.SetAttributes(
+		Attr("db.query.text", statement),
+		Attr("db.response.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+		span.RecordError(tx.Error)
+	}
+	span.End()
+}
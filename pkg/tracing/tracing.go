@@ -0,0 +1,306 @@
+// Package tracing 轻量的分布式链路追踪实现，数据模型与 OpenTelemetry 一致，
+// 通过 OTLP/HTTP (JSON) 导出到 Jaeger、Tempo、OTel Collector 等后端。
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind 与 OTLP 的 Span.SpanKind 取值一致
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode 与 OTLP 的 Status.StatusCode 取值一致
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// TraceID 16 字节链路ID
+type TraceID [16]byte
+
+// SpanID 8 字节 Span ID
+type SpanID [8]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+func (s SpanID) IsValid() bool  { return s != SpanID{} }
+
+// SpanContext 跨进程传播的链路标识
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Attribute 键值属性，Value 支持 string、bool、整数与浮点数
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr 构造属性
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span 一次操作的计时记录，未采样时为 nil，所有方法都可安全地在 nil 上调用
+type Span struct {
+	mu         sync.Mutex
+	ctx        SpanContext
+	parent     SpanID
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	status     StatusCode
+	statusMsg  string
+	events     []spanEvent
+	ended      bool
+}
+
+type spanEvent struct {
+	name       string
+	time       time.Time
+	attributes []Attribute
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+type tracer struct {
+	cfg      Config
+	exporter *exporter
+}
+
+var current atomic.Pointer[tracer]
+
+/* Enabled 是否开启链路追踪 */
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// StartOption Span 启动选项
+type StartOption func(*Span)
+
+// WithKind 指定 Span 类型
+func WithKind(kind SpanKind) StartOption {
+	return func(s *Span) { s.kind = kind }
+}
+
+// WithAttributes 启动时附带属性
+func WithAttributes(attrs ...Attribute) StartOption {
+	return func(s *Span) { s.attributes = append(s.attributes, attrs...) }
+}
+
+/* Start 在 ctx 当前 Span 下创建子 Span，没有父 Span 时开启新链路并按比例采样 */
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	t := current.Load()
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent := parentContext(ctx)
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = t.shouldSample(sc.TraceID)
+	}
+	if !sc.Sampled {
+		// 未采样的链路仍需传播 traceparent，保证下游采样决策一致
+		return context.WithValue(ctx, remoteKey{}, sc), nil
+	}
+
+	span := &Span{ctx: sc, parent: parent.SpanID, name: name, kind: SpanKindInternal, start: time.Now()}
+	for _, opt := range opts {
+		opt(span)
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+/* StartRoot 忽略 ctx 中已有的链路，开启独立的新链路，用于后台任务 */
+func StartRoot(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	ctx = context.WithValue(ctx, spanKey{}, (*Span)(nil))
+	ctx = context.WithValue(ctx, remoteKey{}, SpanContext{})
+	return Start(ctx, name, opts...)
+}
+
+/* SpanFromContext 获取 ctx 中的 Span，没有时返回 nil */
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+/* ContextWithSpan 将 Span 放入 ctx */
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+/* Detach 返回只携带链路信息的新 ctx，不继承原 ctx 的取消与超时，用于请求结束后仍需继续的操作 */
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if ctx == nil {
+		return detached
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return ContextWithSpan(detached, span)
+	}
+	if sc, ok := ctx.Value(remoteKey{}).(SpanContext); ok && sc.IsValid() {
+		return context.WithValue(detached, remoteKey{}, sc)
+	}
+	return detached
+}
+
+/* TraceIDFromContext 当前链路ID，便于写入日志与响应头 */
+func TraceIDFromContext(ctx context.Context) string {
+	sc := parentContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+func parentContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.ctx
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+/* SetAttributes 设置属性 */
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attrs...)
+	s.mu.Unlock()
+}
+
+/* SetName 修改 Span 名称，例如路由匹配后使用路由模板命名 */
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+/* SetStatus 设置状态 */
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.status = code
+	s.statusMsg = msg
+	s.mu.Unlock()
+}
+
+/* RecordError 记录错误事件并将状态置为错误，err 为 nil 时忽略 */
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, spanEvent{
+		name: "exception",
+		time: time.Now(),
+		attributes: []Attribute{
+			Attr("exception.type", fmt.Sprintf("%T", err)),
+			Attr("exception.message", err.Error()),
+		},
+	})
+	s.status = StatusError
+	s.statusMsg = err.Error()
+	s.mu.Unlock()
+}
+
+/* End 结束计时并提交导出，重复调用只生效一次 */
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if t := current.Load(); t != nil {
+		t.exporter.enqueue(s)
+	}
+}
+
+/* SpanContext 返回用于传播的链路标识 */
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+func (t *tracer) shouldSample(id TraceID) bool {
+	ratio := t.cfg.SampleRatio
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	// 与 OTel TraceIDRatioBased 相同，按 TraceID 低 8 字节决定，同一链路在各服务的决策一致
+	bound := uint64(ratio * (1 << 63))
+	return binary.BigEndian.Uint64(id[8:])>>1 < bound
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+	binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
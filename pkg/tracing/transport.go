@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+
+	"pixelpunk/pkg/logger"
+)
+
+// Transport 为出站 HTTP 请求创建客户端 Span，注入 traceparent 与请求ID
+type Transport struct {
+	name string
+	base http.RoundTripper
+}
+
+/* NewTransport 包装 base，name 作为 Span 名称前缀，如 ai.openai、vector.qdrant */
+func NewTransport(name string, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{name: name, base: base}
+}
+
+/* RoundTrip 实现 http.RoundTripper */
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// 请求ID与链路是否开启无关，总是透传给下游
+	if requestID := logger.RequestIDFromContext(req.Context()); requestID != "" && req.Header.Get(logger.RequestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(logger.RequestIDHeader, requestID)
+	}
+	if !Enabled() {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, span := Start(req.Context(), t.name+" "+req.Method,
+		WithKind(SpanKindClient),
+		WithAttributes(
+			Attr("http.request.method", req.Method),
+			Attr("server.address", req.URL.Hostname()),
+			Attr("url.path", req.URL.Path),
+		))
+	defer span.End()
+
+	// RoundTripper 不应修改原请求，克隆后再写入请求头
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(StatusError, "HTTP "+strconv.Itoa(resp.StatusCode))
+	}
+	return resp, nil
+}
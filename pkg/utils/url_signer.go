@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"pixelpunk/internal/models"
@@ -35,10 +36,10 @@ const (
 	CACHE_CLEANUP_INTERVAL = 5 * time.Minute  // 缓存清理间隔
 )
 
-// 全局签名器实例
+// 全局签名器实例，安全设置更新后重置以重新读取密钥
 var (
-	globalSigner *URLSigner
-	signerOnce   sync.Once
+	globalSigner atomic.Pointer[URLSigner]
+	signerMu     sync.Mutex
 )
 
 func NewURLSigner(secret string) *URLSigner {
@@ -140,6 +141,28 @@ func (s *URLSigner) VerifyFileURL(path, timeParam, signatureParam string) bool {
 	return hmac.Equal([]byte(expectedSignature), []byte(signatureParam))
 }
 
+// SignShareFile 为分享中的单个文件签发短期令牌，原图和缩略图共用同一令牌；grant 为访客打开分享时出示的凭证，令牌与之绑定
+func (s *URLSigner) SignShareFile(shareKey, fileID, grant string, duration time.Duration) (int64, string) {
+	expiry := time.Now().Truncate(TIME_WINDOW_ALIGN).Add(duration).Unix()
+	message := fmt.Sprintf("share:%s:%s:%s:%d", shareKey, fileID, grant, expiry)
+	return expiry, s.generateSignature(message)
+}
+
+// VerifyShareFile 验证分享文件令牌的签名与有效期，只能证明文件签发时属于该分享，分享与凭证是否仍有效需另行校验
+func (s *URLSigner) VerifyShareFile(shareKey, fileID, grant, timeParam, signatureParam string) bool {
+	if shareKey == "" || fileID == "" || timeParam == "" || signatureParam == "" {
+		return false
+	}
+
+	expiry, err := strconv.ParseInt(timeParam, 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return false
+	}
+
+	expectedSignature := s.generateSignature(fmt.Sprintf("share:%s:%s:%s:%d", shareKey, fileID, grant, expiry))
+	return hmac.Equal([]byte(expectedSignature), []byte(signatureParam))
+}
+
 // generateSignature 生成HMAC签名
 func (s *URLSigner) generateSignature(message string) string {
 	mac := hmac.New(sha256.New, []byte(s.secret))
@@ -214,11 +237,18 @@ func (s *URLSigner) cleanupExpiredCache() {
 }
 
 func GetURLSigner() *URLSigner {
-	signerOnce.Do(func() {
-		secret := GetSigningSecret()
-		globalSigner = NewURLSigner(secret)
-	})
-	return globalSigner
+	if signer := globalSigner.Load(); signer != nil {
+		return signer
+	}
+
+	signerMu.Lock()
+	defer signerMu.Unlock()
+	if signer := globalSigner.Load(); signer != nil {
+		return signer
+	}
+	signer := NewURLSigner(GetSigningSecret())
+	globalSigner.Store(signer)
+	return signer
 }
 
 func GetSigningSecret() string {
@@ -256,6 +286,5 @@ func getSecretFromDatabase() string {
 
 // ResetURLSigner 重置全局签名器（用于测试或密钥更新）
 func ResetURLSigner() {
-	globalSigner = nil
-	signerOnce = sync.Once{}
+	globalSigner.Store(nil)
 }
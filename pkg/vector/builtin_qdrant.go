@@ -0,0 +1,562 @@
+package vector
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"pixelpunk/pkg/health"
+	"pixelpunk/pkg/logger"
+)
+
+const (
+	builtinQdrantPidFile     = "qdrant/qdrant.pid"
+	builtinQdrantVersionFile = "qdrant/qdrant.version"
+	builtinQdrantLogFile     = "logs/qdrant.log"
+
+	builtinQdrantMinBackoff   = 2 * time.Second
+	builtinQdrantMaxBackoff   = time.Minute
+	builtinQdrantStableUptime = 5 * time.Minute // 运行超过该时长后重置重启退避
+	builtinQdrantWatchPeriod  = time.Minute     // 检查内置二进制是否被替换
+	builtinQdrantStopTimeout  = 10 * time.Second
+)
+
+// BuiltinQdrantStatus 内置Qdrant进程状态
+type BuiltinQdrantStatus struct {
+	Enabled      bool      `json:"enabled"`
+	Running      bool      `json:"running"`
+	PID          int       `json:"pid"`
+	HTTPPort     int       `json:"http_port"`
+	GRPCPort     int       `json:"grpc_port"`
+	Version      string    `json:"version"`
+	StartedAt    time.Time `json:"started_at"`
+	Restarts     int       `json:"restarts"`
+	LastExit     string    `json:"last_exit,omitempty"`
+	LastExitTime time.Time `json:"last_exit_time,omitempty"`
+}
+
+// builtinQdrantSupervisor 托管内置Qdrant进程：崩溃后按退避重启，二进制升级后平滑切换
+type builtinQdrantSupervisor struct {
+	mu         sync.Mutex
+	binPath    string
+	configPath string
+	httpPort   int
+	grpcPort   int
+	cmd        *exec.Cmd
+	exited     chan struct{}
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+	restartCh  chan struct{}
+	binModTime time.Time
+	status     BuiltinQdrantStatus
+}
+
+var (
+	builtinQdrant   *builtinQdrantSupervisor
+	builtinQdrantMu sync.Mutex
+)
+
+// StartBuiltinQdrant 启动并托管内置Qdrant，重复调用时直接返回
+func StartBuiltinQdrant(httpPort, grpcPort int) error {
+	builtinQdrantMu.Lock()
+	defer builtinQdrantMu.Unlock()
+
+	if builtinQdrant != nil {
+		return nil
+	}
+	if httpPort <= 0 {
+		httpPort = 6333
+	}
+	if grpcPort <= 0 {
+		grpcPort = 6334
+	}
+
+	binPath := findQdrantBinary()
+	if binPath == "" {
+		return fmt.Errorf("未找到Qdrant二进制文件")
+	}
+	absBinPath, err := filepath.Abs(binPath)
+	if err != nil {
+		return fmt.Errorf("无法获取二进制文件绝对路径: %v", err)
+	}
+	if err := os.Chmod(absBinPath, 0755); err != nil {
+		return fmt.Errorf("无法设置执行权限: %v", err)
+	}
+
+	configPath, err := prepareQdrantConfig(httpPort, grpcPort)
+	if err != nil {
+		return fmt.Errorf("准备配置文件失败: %v", err)
+	}
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return fmt.Errorf("无法获取配置文件绝对路径: %v", err)
+	}
+
+	// 上次运行遗留的进程无法被托管，先结束再由本进程接管
+	stopOrphanQdrant()
+	if isPortInUse(httpPort) {
+		return fmt.Errorf("HTTP端口 %d 已被占用", httpPort)
+	}
+
+	s := &builtinQdrantSupervisor{
+		binPath:    absBinPath,
+		configPath: absConfigPath,
+		httpPort:   httpPort,
+		grpcPort:   grpcPort,
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+		restartCh:  make(chan struct{}, 1),
+	}
+	s.status = BuiltinQdrantStatus{Enabled: true, HTTPPort: httpPort, GRPCPort: grpcPort}
+
+	if err := s.launch(); err != nil {
+		return err
+	}
+
+	builtinQdrant = s
+	health.RegisterChecker(&builtinQdrantChecker{})
+	go s.supervise()
+	return nil
+}
+
+// StopBuiltinQdrant 停止内置Qdrant进程，未启用时无操作
+func StopBuiltinQdrant() {
+	builtinQdrantMu.Lock()
+	s := builtinQdrant
+	builtinQdrant = nil
+	builtinQdrantMu.Unlock()
+
+	if s == nil {
+		return
+	}
+	close(s.stopCh)
+	<-s.doneCh
+	logger.Info("内置Qdrant已停止")
+}
+
+// RestartBuiltinQdrant 主动重启内置Qdrant（如替换二进制后）
+func RestartBuiltinQdrant() error {
+	builtinQdrantMu.Lock()
+	s := builtinQdrant
+	builtinQdrantMu.Unlock()
+
+	if s == nil {
+		return fmt.Errorf("内置Qdrant未启用")
+	}
+	select {
+	case s.restartCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// GetBuiltinQdrantStatus 获取内置Qdrant进程状态
+func GetBuiltinQdrantStatus() BuiltinQdrantStatus {
+	builtinQdrantMu.Lock()
+	s := builtinQdrant
+	builtinQdrantMu.Unlock()
+
+	if s == nil {
+		return BuiltinQdrantStatus{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// launch 校验二进制版本并启动进程
+func (s *builtinQdrantSupervisor) launch() error {
+	version, err := qdrantBinaryVersion(s.binPath)
+	if err != nil {
+		return err
+	}
+	recordQdrantVersion(version)
+
+	if err := os.MkdirAll("logs", 0755); err != nil {
+		return fmt.Errorf("无法创建日志目录: %v", err)
+	}
+	logFile, err := os.OpenFile(builtinQdrantLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("无法打开日志文件: %v", err)
+	}
+
+	workDir, _ := os.Getwd()
+	cmd := exec.Command(s.binPath, "--config-path", s.configPath)
+	cmd.Dir = workDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("启动进程失败: %v", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		logFile.Close()
+
+		s.mu.Lock()
+		s.status.Running = false
+		s.status.LastExitTime = time.Now()
+		if err != nil {
+			s.status.LastExit = err.Error()
+		} else {
+			s.status.LastExit = "exit status 0"
+		}
+		s.mu.Unlock()
+		close(exited)
+	}()
+
+	os.MkdirAll("qdrant", 0755)
+	os.WriteFile(builtinQdrantPidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
+
+	var modTime time.Time
+	if info, err := os.Stat(s.binPath); err == nil {
+		modTime = info.ModTime()
+	}
+
+	s.mu.Lock()
+	s.cmd = cmd
+	s.exited = exited
+	s.binModTime = modTime
+	s.status.Running = true
+	s.status.PID = cmd.Process.Pid
+	s.status.Version = version
+	s.status.StartedAt = time.Now()
+	s.mu.Unlock()
+
+	select {
+	case <-exited:
+		return fmt.Errorf("进程启动后立即退出")
+	case <-time.After(time.Second):
+	}
+
+	logger.Info("内置Qdrant已启动，PID: %d，版本: %s", cmd.Process.Pid, version)
+	return nil
+}
+
+// supervise 监控进程退出与二进制变更，直到收到停止信号
+func (s *builtinQdrantSupervisor) supervise() {
+	defer close(s.doneCh)
+
+	backoff := builtinQdrantMinBackoff
+	ticker := time.NewTicker(builtinQdrantWatchPeriod)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		exited := s.exited
+		startedAt := s.status.StartedAt
+		s.mu.Unlock()
+
+		select {
+		case <-s.stopCh:
+			s.terminate()
+			os.Remove(builtinQdrantPidFile)
+			return
+
+		case <-s.restartCh:
+			logger.Info("重启内置Qdrant")
+			s.terminate()
+			s.relaunch(&backoff)
+
+		case <-ticker.C:
+			if s.binaryChanged() {
+				logger.Info("检测到内置Qdrant二进制已更新，重启以加载新版本")
+				s.terminate()
+				s.relaunch(&backoff)
+			}
+
+		case <-exited:
+			s.mu.Lock()
+			lastExit := s.status.LastExit
+			s.mu.Unlock()
+
+			if time.Since(startedAt) > builtinQdrantStableUptime {
+				backoff = builtinQdrantMinBackoff
+			}
+			wait := backoff
+			logger.Error("内置Qdrant意外退出(%s)，%v 后重启", lastExit, wait)
+
+			select {
+			case <-s.stopCh:
+				os.Remove(builtinQdrantPidFile)
+				return
+			case <-time.After(wait):
+			}
+			backoff = nextQdrantBackoff(backoff)
+			s.relaunch(&backoff)
+		}
+	}
+}
+
+// relaunch 重新启动进程，失败时加大退避并等待下一次进程退出事件
+func (s *builtinQdrantSupervisor) relaunch(backoff *time.Duration) {
+	s.mu.Lock()
+	s.status.Restarts++
+	s.mu.Unlock()
+
+	if err := s.launch(); err != nil {
+		logger.Error("重启内置Qdrant失败: %v", err)
+		*backoff = nextQdrantBackoff(*backoff)
+		// 启动失败时构造一个已关闭的退出通道，使监控循环在退避后再次尝试
+		failed := make(chan struct{})
+		close(failed)
+		s.mu.Lock()
+		s.exited = failed
+		s.status.Running = false
+		s.status.LastExit = err.Error()
+		s.status.LastExitTime = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+func nextQdrantBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > builtinQdrantMaxBackoff {
+		return builtinQdrantMaxBackoff
+	}
+	return backoff
+}
+
+// terminate 先发送 SIGTERM 等待 Qdrant 刷盘退出，超时后强制结束
+func (s *builtinQdrantSupervisor) terminate() {
+	s.mu.Lock()
+	cmd := s.cmd
+	exited := s.exited
+	running := s.status.Running
+	s.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil || !running {
+		return
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		cmd.Process.Kill()
+	}
+	select {
+	case <-exited:
+	case <-time.After(builtinQdrantStopTimeout):
+		logger.Warn("内置Qdrant未在 %v 内退出，强制结束", builtinQdrantStopTimeout)
+		cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// binaryChanged 二进制文件被替换且新版本可运行时返回 true
+func (s *builtinQdrantSupervisor) binaryChanged() bool {
+	info, err := os.Stat(s.binPath)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	changed := !info.ModTime().Equal(s.binModTime)
+	current := s.status.Version
+	s.mu.Unlock()
+	if !changed {
+		return false
+	}
+
+	version, err := qdrantBinaryVersion(s.binPath)
+	if err != nil {
+		logger.Warn("新的Qdrant二进制无法运行，继续使用当前进程: %v", err)
+		s.mu.Lock()
+		s.binModTime = info.ModTime()
+		s.mu.Unlock()
+		return false
+	}
+	if version != current {
+		logger.Info("内置Qdrant版本变更: %s -> %s", current, version)
+	}
+	return true
+}
+
+// qdrantBinaryVersion 执行 --version 校验二进制可用并返回版本号
+func qdrantBinaryVersion(binPath string) (string, error) {
+	output, err := exec.Command(binPath, "--version").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "GLIBC") || strings.Contains(err.Error(), "GLIBC") {
+			return "", fmt.Errorf("系统GLIBC版本不兼容，需要GLIBC 2.31+")
+		}
+		return "", fmt.Errorf("Qdrant二进制文件无法运行: %v", err)
+	}
+	version := strings.TrimSpace(string(output))
+	version = strings.TrimSpace(strings.TrimPrefix(version, "qdrant"))
+	return version, nil
+}
+
+// recordQdrantVersion 记录当前运行的版本，版本变化时提示存储格式升级
+func recordQdrantVersion(version string) {
+	previous, _ := os.ReadFile(builtinQdrantVersionFile)
+	if prev := strings.TrimSpace(string(previous)); prev != "" && prev != version {
+		logger.Warn("内置Qdrant由 %s 升级至 %s，首次启动将迁移存储数据，跨多个大版本升级可能不被支持", prev, version)
+	}
+	os.MkdirAll("qdrant", 0755)
+	os.WriteFile(builtinQdrantVersionFile, []byte(version), 0644)
+}
+
+// stopOrphanQdrant 结束PID文件记录的遗留进程
+func stopOrphanQdrant() {
+	data, err := os.ReadFile(builtinQdrantPidFile)
+	if err != nil {
+		return
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		os.Remove(builtinQdrantPidFile)
+		return
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		os.Remove(builtinQdrantPidFile)
+		return
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		os.Remove(builtinQdrantPidFile)
+		return
+	}
+
+	logger.Info("结束遗留的内置Qdrant进程，PID: %d", pid)
+	deadline := time.Now().Add(builtinQdrantStopTimeout)
+	for time.Now().Before(deadline) {
+		if err := process.Signal(syscall.Signal(0)); err != nil {
+			break
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	process.Kill()
+	os.Remove(builtinQdrantPidFile)
+}
+
+func isPortInUse(port int) bool {
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	conn, err := net.DialTimeout("tcp", addr, 1*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func updateQdrantConfig(configPath string, httpPort, grpcPort int) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("读取Qdrant配置文件失败: %v", err)
+	}
+
+	content := string(data)
+
+	httpPortPattern := regexp.MustCompile(`(?m)^(\s*http_port:\s*)(\d+)`)
+	if httpPortPattern.MatchString(content) {
+		content = httpPortPattern.ReplaceAllString(content, fmt.Sprintf("${1}%d", httpPort))
+	} else {
+		servicePattern := regexp.MustCompile(`(?m)^(service:)`)
+		if servicePattern.MatchString(content) {
+			content = servicePattern.ReplaceAllString(content, fmt.Sprintf("${1}\n  http_port: %d", httpPort))
+		}
+	}
+
+	grpcPortPattern := regexp.MustCompile(`(?m)^(\s*grpc_port:\s*)(\d+)`)
+	if grpcPortPattern.MatchString(content) {
+		content = grpcPortPattern.ReplaceAllString(content, fmt.Sprintf("${1}%d", grpcPort))
+	} else {
+		servicePattern := regexp.MustCompile(`(?m)^(service:)`)
+		if servicePattern.MatchString(content) {
+			content = servicePattern.ReplaceAllString(content, fmt.Sprintf("${1}\n  grpc_port: %d", grpcPort))
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入Qdrant配置文件失败: %v", err)
+	}
+
+	return nil
+}
+
+func findQdrantBinary() string {
+	paths := []string{
+		"qdrant/bin/qdrant",
+		"qdrant/qdrant",
+		"./qdrant/bin/qdrant",
+		"qdrant/bin/qdrant.exe",
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func prepareQdrantConfig(httpPort, grpcPort int) (string, error) {
+	configPath := "qdrant/config/config.yaml"
+
+	if _, err := os.Stat(configPath); err == nil {
+		return configPath, updateQdrantConfig(configPath, httpPort, grpcPort)
+	}
+
+	if err := os.MkdirAll("qdrant/config", 0755); err != nil {
+		return "", err
+	}
+
+	config := fmt.Sprintf(`service:
+  http_port: %d
+  grpc_port: %d
+storage:
+  storage_path: ./storage
+`, httpPort, grpcPort)
+
+	return configPath, os.WriteFile(configPath, []byte(config), 0644)
+}
+
+// builtinQdrantChecker 内置Qdrant健康检查，仅在托管模式下注册
+type builtinQdrantChecker struct{}
+
+func (c *builtinQdrantChecker) Name() string {
+	return "qdrant_builtin"
+}
+
+func (c *builtinQdrantChecker) Check() (health.Status, map[string]interface{}) {
+	status := GetBuiltinQdrantStatus()
+	details := map[string]interface{}{
+		"pid":      status.PID,
+		"version":  status.Version,
+		"restarts": status.Restarts,
+	}
+	if status.LastExit != "" {
+		details["last_exit"] = status.LastExit
+	}
+	if !status.Enabled || !status.Running {
+		return health.StatusDown, details
+	}
+	details["uptime_seconds"] = int64(time.Since(status.StartedAt).Seconds())
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", status.HTTPPort))
+	if err != nil {
+		details["error"] = err.Error()
+		return health.StatusDegraded, details
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		details["error"] = fmt.Sprintf("healthz 返回状态码 %d", resp.StatusCode)
+		return health.StatusDegraded, details
+	}
+	return health.StatusUp, details
+}
+
+func (c *builtinQdrantChecker) Type() health.CheckType {
+	return health.CheckTypeComplete
+}
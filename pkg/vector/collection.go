@@ -0,0 +1,279 @@
+package vector
+
+import (
+	"fmt"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// collectionTarget 正在构建的新版本集合
+type collectionTarget struct {
+	record    models.VectorCollection
+	storage   VectorStore
+	embedding EmbeddingProvider
+}
+
+func currentVectorBackend() string {
+	backend := setting.GetStringDirectFromDB("vector", "vector_backend", VectorBackendQdrant)
+	if backend == "" {
+		backend = VectorBackendQdrant
+	}
+	return backend
+}
+
+// loadActiveCollection 读取当前后端生效的集合版本，不存在时以现有集合登记为版本1
+func loadActiveCollection(db *gorm.DB) *models.VectorCollection {
+	backend := currentVectorBackend()
+
+	// 上次进程退出时未完成的构建无法续传，标记失败后由迁移检查重新发起
+	db.Model(&models.VectorCollection{}).
+		Where("backend = ? AND status = ?", backend, models.VectorCollectionBuilding).
+		Updates(map[string]interface{}{"status": models.VectorCollectionFailed, "error": "服务重启，构建中断"})
+
+	var active models.VectorCollection
+	err := db.Where("backend = ? AND status = ?", backend, models.VectorCollectionActive).
+		Order("version DESC").First(&active).Error
+	if err == nil {
+		return &active
+	}
+
+	provider, model := currentEmbeddingConfig()
+	var dimension int
+	db.Model(&models.FileVector{}).Where("status = ?", common.VectorStatusCompleted).
+		Limit(1).Pluck("dimension", &dimension)
+
+	now := common.JSONTime(time.Now())
+	active = models.VectorCollection{
+		Backend:     backend,
+		Version:     1,
+		Provider:    provider,
+		Model:       model,
+		Dimension:   dimension,
+		Status:      models.VectorCollectionActive,
+		ActivatedAt: &now,
+	}
+	if err := db.Create(&active).Error; err != nil {
+		logger.Warn("登记向量集合版本失败: %v", err)
+	}
+	return &active
+}
+
+// ActiveCollection 当前生效的集合版本
+func (ve *VectorEngine) ActiveCollection() *models.VectorCollection {
+	if ve == nil {
+		return nil
+	}
+	ve.mutex.RLock()
+	defer ve.mutex.RUnlock()
+	if ve.collection == nil {
+		return nil
+	}
+	c := *ve.collection
+	return &c
+}
+
+// BuildingCollection 正在构建的集合版本，没有时返回 nil
+func (ve *VectorEngine) BuildingCollection() *models.VectorCollection {
+	target := ve.buildingTarget()
+	if target == nil {
+		return nil
+	}
+	c := target.record
+	return &c
+}
+
+func (ve *VectorEngine) buildingTarget() *collectionTarget {
+	if ve == nil {
+		return nil
+	}
+	ve.mutex.RLock()
+	defer ve.mutex.RUnlock()
+	return ve.building
+}
+
+// NeedsCollectionMigration 当前配置的提供者或模型与生效集合不一致时需要迁移
+func (ve *VectorEngine) NeedsCollectionMigration() bool {
+	active := ve.ActiveCollection()
+	if active == nil || ve.BuildingCollection() != nil {
+		return false
+	}
+	provider, model := currentEmbeddingConfig()
+	return active.Provider != provider || active.Model != model
+}
+
+// BeginCollectionMigration 按当前配置的模型创建新版本集合，之后的写入同时写入新旧集合
+func (ve *VectorEngine) BeginCollectionMigration() (*models.VectorCollection, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		return nil, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+	if ve.BuildingCollection() != nil {
+		return nil, fmt.Errorf("已有新版本向量集合正在构建")
+	}
+
+	provider, model := currentEmbeddingConfig()
+	embedding := NewPinnedEmbeddingProvider(provider, model)
+	dimension := embedding.GetDimension()
+	if dimension <= 0 {
+		return nil, fmt.Errorf("无法获取模型 %s 的向量维度", model)
+	}
+
+	active := ve.ActiveCollection()
+	if active == nil {
+		return nil, fmt.Errorf("当前向量集合版本未登记")
+	}
+	var maxVersion int
+	ve.db.Model(&models.VectorCollection{}).Where("backend = ?", active.Backend).
+		Select("COALESCE(MAX(version), 0)").Scan(&maxVersion)
+
+	// 新集合的向量写入时即带有完整元数据，无需回填
+	record := models.VectorCollection{
+		Backend:        active.Backend,
+		Version:        maxVersion + 1,
+		Provider:       provider,
+		Model:          model,
+		Dimension:      dimension,
+		Status:         models.VectorCollectionBuilding,
+		PayloadVersion: VectorPayloadVersion,
+	}
+
+	store := ve.storage.WithVersion(record.Version)
+	if err := store.RecreateCollection(dimension); err != nil {
+		return nil, fmt.Errorf("创建新版本向量集合失败: %v", err)
+	}
+	if err := ve.db.Create(&record).Error; err != nil {
+		_ = store.DeleteCollection()
+		return nil, fmt.Errorf("登记向量集合版本失败: %v", err)
+	}
+
+	ve.mutex.Lock()
+	ve.building = &collectionTarget{record: record, storage: store, embedding: embedding}
+	ve.mutex.Unlock()
+
+	logger.Info("开始构建向量集合 v%d: %s/%s 维度=%d", record.Version, provider, model, dimension)
+	return &record, nil
+}
+
+// MigrateCollectionItems 使用新模型向量化并写入正在构建的集合
+func (ve *VectorEngine) MigrateCollectionItems(items []VectorItem) error {
+	target := ve.buildingTarget()
+	if target == nil {
+		return fmt.Errorf("没有正在构建的向量集合")
+	}
+	return storeToTarget(target, items)
+}
+
+// writeToBuilding 构建期间的新增向量同步写入新集合，失败仅记录日志
+func (ve *VectorEngine) writeToBuilding(items []VectorItem) {
+	target := ve.buildingTarget()
+	if target == nil || len(items) == 0 {
+		return
+	}
+	if err := storeToTarget(target, items); err != nil {
+		logger.Warn("同步写入新版本向量集合失败: %v", err)
+	}
+}
+
+func storeToTarget(target *collectionTarget, items []VectorItem) error {
+	descriptions := make([]string, len(items))
+	for i, item := range items {
+		descriptions[i] = item.Description
+	}
+
+	vectors, err := target.embedding.BatchGenerateEmbeddings(descriptions)
+	if err != nil {
+		return fmt.Errorf("向量化失败: %v", err)
+	}
+	if len(vectors) != len(items) {
+		return fmt.Errorf("返回向量数量不匹配，期望: %d, 实际: %d", len(items), len(vectors))
+	}
+
+	vectorItems := make([]VectorItem, len(items))
+	for i, item := range items {
+		vectorItems[i] = VectorItem{
+			FileID:      item.FileID,
+			Vector:      vectors[i],
+			Description: item.Description,
+			Model:       target.record.Model,
+		}
+	}
+	return target.storage.BatchStoreVectors(vectorItems)
+}
+
+// CompleteCollectionMigration 原子切换到新版本集合，并删除旧集合
+func (ve *VectorEngine) CompleteCollectionMigration() (*models.VectorCollection, error) {
+	ve.mutex.Lock()
+	target := ve.building
+	if target == nil {
+		ve.mutex.Unlock()
+		return nil, fmt.Errorf("没有正在构建的向量集合")
+	}
+	oldStorage := ve.storage
+	oldCollection := ve.collection
+
+	now := common.JSONTime(time.Now())
+	target.record.Status = models.VectorCollectionActive
+	target.record.ActivatedAt = &now
+
+	ve.storage = target.storage
+	ve.embedding = target.embedding
+	ve.collection = &target.record
+	ve.building = nil
+	ve.mutex.Unlock()
+
+	err := ve.db.Transaction(func(tx *gorm.DB) error {
+		if oldCollection != nil {
+			if err := tx.Model(&models.VectorCollection{}).Where("id = ?", oldCollection.ID).
+				Update("status", models.VectorCollectionRetired).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.VectorCollection{}).Where("id = ?", target.record.ID).
+			Updates(map[string]interface{}{
+				"status":       models.VectorCollectionActive,
+				"activated_at": now,
+			}).Error
+	})
+	if err != nil {
+		logger.Error("更新向量集合版本状态失败: %v", err)
+	}
+
+	// 旧集合已不再被搜索使用，直接删除释放空间（共享连接，不调用 Close）
+	if oldStorage != nil {
+		if err := oldStorage.DeleteCollection(); err != nil {
+			logger.Warn("删除旧版本向量集合失败: %v", err)
+		}
+	}
+
+	logger.Info("向量集合已切换到 v%d (%s/%s)", target.record.Version, target.record.Provider, target.record.Model)
+	record := target.record
+	return &record, nil
+}
+
+// AbortCollectionMigration 放弃正在构建的集合，搜索继续使用当前集合
+func (ve *VectorEngine) AbortCollectionMigration(reason error) {
+	ve.mutex.Lock()
+	target := ve.building
+	ve.building = nil
+	ve.mutex.Unlock()
+	if target == nil {
+		return
+	}
+
+	if err := target.storage.DeleteCollection(); err != nil {
+		logger.Warn("删除未完成的向量集合失败: %v", err)
+	}
+
+	errMsg := ""
+	if reason != nil {
+		errMsg = reason.Error()
+	}
+	ve.db.Model(&models.VectorCollection{}).Where("id = ?", target.record.ID).
+		Updates(map[string]interface{}{"status": models.VectorCollectionFailed, "error": errMsg})
+	logger.Warn("向量集合 v%d 构建失败: %s", target.record.Version, errMsg)
+}
@@ -3,7 +3,9 @@ package vector
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 	"pixelpunk/pkg/utils"
 	"strings"
 	"time"
@@ -61,6 +63,7 @@ func NewOpenAIEmbeddingClientWithConfig(apiKey, baseURL, model string, timeout i
 	if baseURL != "" {
 		clientConfig.BaseURL = utils.NormalizeOpenAIBaseURL(baseURL)
 	}
+	clientConfig.HTTPClient = &http.Client{Transport: tracing.NewTransport("vector.embedding", nil)}
 
 	client := openai.NewClientWithConfig(clientConfig)
 
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"pixelpunk/internal/services/setting"
 	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 	"pixelpunk/pkg/utils"
 	"strings"
 	"time"
@@ -13,8 +16,81 @@ import (
 	"github.com/sashabaranov/go-openai"
 )
 
+// 向量化提供者
+const (
+	EmbeddingProviderOpenAI = "openai"
+	EmbeddingProviderOllama = "ollama"
+)
+
+// DynamicEmbeddingProvider 按 vector_provider 配置在每次调用时选择向量化提供者
+type DynamicEmbeddingProvider struct {
+	openai *DynamicOpenAIClient
+	ollama *OllamaEmbeddingClient
+}
+
+func NewDynamicEmbeddingProvider() *DynamicEmbeddingProvider {
+	return &DynamicEmbeddingProvider{
+		openai: NewDynamicOpenAIClient(),
+		ollama: NewOllamaEmbeddingClient(),
+	}
+}
+
+func (p *DynamicEmbeddingProvider) current() EmbeddingProvider {
+	switch setting.GetStringDirectFromDB("vector", "vector_provider", EmbeddingProviderOpenAI) {
+	case EmbeddingProviderOllama:
+		return p.ollama
+	default:
+		return p.openai
+	}
+}
+
+func (p *DynamicEmbeddingProvider) GenerateEmbedding(text string) ([]float32, error) {
+	return p.current().GenerateEmbedding(text)
+}
+
+func (p *DynamicEmbeddingProvider) BatchGenerateEmbeddings(texts []string) ([][]float32, error) {
+	return p.current().BatchGenerateEmbeddings(texts)
+}
+
+func (p *DynamicEmbeddingProvider) GetDimension() int {
+	return p.current().GetDimension()
+}
+
+func (p *DynamicEmbeddingProvider) GetModel() string {
+	return p.current().GetModel()
+}
+
+// NewPinnedEmbeddingProvider 创建绑定指定提供者和模型的客户端，其余配置（密钥、地址等）仍动态读取
+func NewPinnedEmbeddingProvider(provider, model string) EmbeddingProvider {
+	if provider == EmbeddingProviderOllama {
+		client := NewOllamaEmbeddingClient()
+		client.model = model
+		return &meteredEmbeddingProvider{EmbeddingProvider: client}
+	}
+	return &meteredEmbeddingProvider{EmbeddingProvider: &DynamicOpenAIClient{model: model}}
+}
+
+// currentEmbeddingConfig 读取当前配置的向量化提供者及模型
+func currentEmbeddingConfig() (provider, model string) {
+	provider = setting.GetStringDirectFromDB("vector", "vector_provider", EmbeddingProviderOpenAI)
+	if provider == EmbeddingProviderOllama {
+		model = setting.GetStringDirectFromDB("vector", "ollama_model", defaultOllamaModel)
+		if model == "" {
+			model = defaultOllamaModel
+		}
+		return provider, model
+	}
+	model = setting.GetStringDirectFromDB("vector", "vector_model", "text-embedding-3-small")
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	return EmbeddingProviderOpenAI, model
+}
+
 // DynamicOpenAIClient 动态OpenAI客户端（无需初始化，每次调用时读取最新配置）
-type DynamicOpenAIClient struct{}
+type DynamicOpenAIClient struct {
+	model string // 非空时固定使用该模型，不跟随 vector_model 变化
+}
 
 func NewDynamicOpenAIClient() *DynamicOpenAIClient {
 	return &DynamicOpenAIClient{}
@@ -79,6 +155,10 @@ func (c *DynamicOpenAIClient) getConfigFromDB() (apiKey, baseURL, model string,
 		}
 	}
 
+	if c.model != "" {
+		model = c.model
+	}
+
 	switch model {
 	case "text-embedding-3-large":
 		dimension = 3072
@@ -111,6 +191,7 @@ func (c *DynamicOpenAIClient) GenerateEmbedding(text string) ([]float32, error)
 	if baseURL != "" {
 		clientConfig.BaseURL = baseURL
 	}
+	clientConfig.HTTPClient = &http.Client{Transport: tracing.NewTransport("vector.embedding", nil)}
 	client := openai.NewClientWithConfig(clientConfig)
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -168,6 +249,7 @@ func (c *DynamicOpenAIClient) BatchGenerateEmbeddings(texts []string) ([][]float
 	if baseURL != "" {
 		clientConfig.BaseURL = baseURL
 	}
+	clientConfig.HTTPClient = &http.Client{Transport: tracing.NewTransport("vector.embedding", nil)}
 	client := openai.NewClientWithConfig(clientConfig)
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
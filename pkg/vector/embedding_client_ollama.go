@@ -0,0 +1,217 @@
+package vector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultOllamaModel   = "all-minilm"
+	ollamaMaxTextRunes   = 2000 // 本地小模型上下文较短，过长文本仅保留前半部分
+)
+
+// 常见本地嵌入模型的维度，未列出的模型首次使用时探测
+var ollamaModelDimensions = map[string]int{
+	"all-minilm":             384,
+	"all-minilm:l6-v2":       384,
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"bge-m3":                 1024,
+	"snowflake-arctic-embed": 1024,
+}
+
+// OllamaEmbeddingClient 本地 Ollama 向量化客户端，适合离线部署且无API费用
+type OllamaEmbeddingClient struct {
+	httpClient *http.Client
+	dimMu      sync.Mutex
+	dimensions map[string]int
+
+	// 固定配置（测试连接时使用），为空时每次调用读取最新配置
+	baseURL string
+	model   string // 非空时固定使用该模型（版本化集合绑定的模型）
+	timeout time.Duration
+}
+
+func NewOllamaEmbeddingClient() *OllamaEmbeddingClient {
+	return &OllamaEmbeddingClient{
+		httpClient: &http.Client{Transport: tracing.NewTransport("vector.ollama", nil)},
+		dimensions: make(map[string]int),
+	}
+}
+
+// NewOllamaEmbeddingClientWithConfig 使用指定配置创建Ollama向量化客户端
+func NewOllamaEmbeddingClientWithConfig(baseURL, model string, timeout int) *OllamaEmbeddingClient {
+	c := NewOllamaEmbeddingClient()
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	if c.baseURL == "" {
+		c.baseURL = defaultOllamaBaseURL
+	}
+	c.model = model
+	if c.model == "" {
+		c.model = defaultOllamaModel
+	}
+	c.timeout = time.Duration(timeout) * time.Second
+	if c.timeout <= 0 {
+		c.timeout = 30 * time.Second
+	}
+	return c
+}
+
+func (c *OllamaEmbeddingClient) getConfig() (baseURL, model string, timeout time.Duration) {
+	if c.baseURL != "" {
+		return c.baseURL, c.model, c.timeout
+	}
+
+	baseURL = strings.TrimRight(setting.GetStringDirectFromDB("vector", "ollama_base_url", defaultOllamaBaseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	model = c.model
+	if model == "" {
+		model = setting.GetStringDirectFromDB("vector", "ollama_model", defaultOllamaModel)
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+	timeout = time.Duration(setting.GetIntDirectFromDB("vector", "vector_timeout", 30)) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return baseURL, model, timeout
+}
+
+// embed 调用 Ollama /api/embed 接口，支持批量输入
+func (c *OllamaEmbeddingClient) embed(inputs []string) ([][]float32, error) {
+	baseURL, model, timeout := c.getConfig()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model": model,
+		"input": inputs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建Ollama请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama返回错误 (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("解析Ollama响应失败: %v", err)
+	}
+	if len(result.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("返回向量数量不匹配，期望: %d, 实际: %d", len(inputs), len(result.Embeddings))
+	}
+
+	if len(result.Embeddings[0]) > 0 {
+		c.dimMu.Lock()
+		c.dimensions[model] = len(result.Embeddings[0])
+		c.dimMu.Unlock()
+	}
+	return result.Embeddings, nil
+}
+
+// GenerateEmbedding 生成单个文本的向量
+func (c *OllamaEmbeddingClient) GenerateEmbedding(text string) ([]float32, error) {
+	text = c.preprocessText(text)
+	if text == "" {
+		return nil, fmt.Errorf("文本内容为空")
+	}
+
+	vectors, err := c.embed([]string{text})
+	if err != nil {
+		logger.Error("Ollama向量化失败: %v", err)
+		return nil, fmt.Errorf("Ollama向量化失败: %v", err)
+	}
+	return vectors[0], nil
+}
+
+// BatchGenerateEmbeddings 批量生成向量
+func (c *OllamaEmbeddingClient) BatchGenerateEmbeddings(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("文本列表为空")
+	}
+
+	processedTexts := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if processed := c.preprocessText(text); processed != "" {
+			processedTexts = append(processedTexts, processed)
+		}
+	}
+	if len(processedTexts) == 0 {
+		return nil, fmt.Errorf("没有有效的文本内容")
+	}
+
+	vectors, err := c.embed(processedTexts)
+	if err != nil {
+		logger.Error("Ollama批量向量化失败: %v", err)
+		return nil, fmt.Errorf("Ollama批量向量化失败: %v", err)
+	}
+	return vectors, nil
+}
+
+// GetDimension 返回当前模型维度，未知模型通过一次探测请求获取
+func (c *OllamaEmbeddingClient) GetDimension() int {
+	_, model, _ := c.getConfig()
+
+	c.dimMu.Lock()
+	dim, ok := c.dimensions[model]
+	c.dimMu.Unlock()
+	if ok {
+		return dim
+	}
+	if dim, ok := ollamaModelDimensions[model]; ok {
+		return dim
+	}
+
+	vectors, err := c.embed([]string{"dimension probe"})
+	if err != nil {
+		logger.Warn("获取Ollama模型 %s 的向量维度失败: %v", model, err)
+		return 0
+	}
+	return len(vectors[0])
+}
+
+func (c *OllamaEmbeddingClient) GetModel() string {
+	_, model, _ := c.getConfig()
+	return model
+}
+
+// preprocessText 合并空白并限制长度
+func (c *OllamaEmbeddingClient) preprocessText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > ollamaMaxTextRunes {
+		text = string(runes[:ollamaMaxTextRunes])
+	}
+	return text
+}
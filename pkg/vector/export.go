@@ -0,0 +1,185 @@
+package vector
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	vectorExportFormat  = "pixelpunk-vectors"
+	vectorExportVersion = 1
+	vectorImportBatch   = 100
+	vectorExportBatch   = 500
+)
+
+// vectorExportHeader 导出文件首行，描述导出来源
+type vectorExportHeader struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Model      string `json:"model"`
+	Dimension  int    `json:"dimension"`
+	ExportedAt string `json:"exported_at"`
+}
+
+// vectorExportRecord 导出文件中的单条向量
+type vectorExportRecord struct {
+	FileID      string    `json:"file_id"`
+	Vector      []float32 `json:"vector"`
+	Description string    `json:"description"`
+	Model       string    `json:"model"`
+}
+
+/* ExportVectors 将全部向量及 payload 以 gzip 压缩的 JSON Lines 写出，返回导出数量。
+ * 按批次遍历存储，导出数量与存储中的数量不一致时返回错误且不写出 gzip 结尾，使不完整的导出文件无法被导入 */
+func (ve *VectorEngine) ExportVectors(w io.Writer) (int, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		return 0, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+
+	expected, err := ve.storage.GetVectorCount(0)
+	if err != nil {
+		return 0, fmt.Errorf("统计向量数量失败: %v", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	header := vectorExportHeader{
+		Format:     vectorExportFormat,
+		Version:    vectorExportVersion,
+		Model:      ve.getCurrentModel(),
+		Dimension:  ve.embedding.GetDimension(),
+		ExportedAt: time.Now().Format(time.RFC3339),
+	}
+	if err := enc.Encode(header); err != nil {
+		return 0, fmt.Errorf("写入导出文件失败: %v", err)
+	}
+
+	count := 0
+	cursor := ""
+	for {
+		items, next, err := ve.storage.ScrollVectors(cursor, vectorExportBatch)
+		if err != nil {
+			return count, fmt.Errorf("读取向量失败: %v", err)
+		}
+		for _, item := range items {
+			if item.FileID == "" || len(item.Vector) == 0 {
+				continue
+			}
+			record := vectorExportRecord{
+				FileID:      item.FileID,
+				Vector:      item.Vector,
+				Description: item.Description,
+				Model:       item.Model,
+			}
+			if err := enc.Encode(record); err != nil {
+				return count, fmt.Errorf("写入导出文件失败: %v", err)
+			}
+			count++
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	// 导出期间有增删时以结束时的数量为准，两者都不一致说明遍历不完整
+	if int64(count) != expected {
+		current, err := ve.storage.GetVectorCount(0)
+		if err != nil || int64(count) != current {
+			return count, fmt.Errorf("导出数量(%d)与向量库中的数量(%d)不一致，导出文件不完整", count, expected)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return count, fmt.Errorf("写入导出文件失败: %v", err)
+	}
+	return count, nil
+}
+
+// ImportVectors 从导出文件恢复向量（无需重新向量化），onBatch 在每批写入成功后回调
+func (ve *VectorEngine) ImportVectors(r io.Reader, onBatch func(items []VectorItem)) (int, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		return 0, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+
+	br := bufio.NewReader(r)
+	var reader io.Reader = br
+	// 兼容未压缩的 JSON Lines 文件
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, fmt.Errorf("解压导出文件失败: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	dec := json.NewDecoder(reader)
+	var header vectorExportHeader
+	if err := dec.Decode(&header); err != nil {
+		return 0, fmt.Errorf("读取导出文件头失败: %v", err)
+	}
+	if header.Format != vectorExportFormat {
+		return 0, fmt.Errorf("不是有效的向量导出文件")
+	}
+	if header.Version > vectorExportVersion {
+		return 0, fmt.Errorf("不支持的导出文件版本: %d", header.Version)
+	}
+	if dim := ve.embedding.GetDimension(); header.Dimension > 0 && dim > 0 && header.Dimension != dim {
+		return 0, fmt.Errorf("导出文件向量维度(%d)与当前模型维度(%d)不一致，请先切换到模型 %s", header.Dimension, dim, header.Model)
+	}
+
+	total := 0
+	batch := make([]VectorItem, 0, vectorImportBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := ve.storage.BatchStoreVectors(batch); err != nil {
+			return fmt.Errorf("写入向量失败: %v", err)
+		}
+		total += len(batch)
+		if onBatch != nil {
+			onBatch(batch)
+		}
+		batch = make([]VectorItem, 0, vectorImportBatch)
+		return nil
+	}
+
+	for {
+		var record vectorExportRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return total, fmt.Errorf("解析导出文件失败: %v", err)
+		}
+		if record.FileID == "" || len(record.Vector) == 0 {
+			continue
+		}
+		model := record.Model
+		if model == "" {
+			model = header.Model
+		}
+		batch = append(batch, VectorItem{
+			FileID:      record.FileID,
+			Vector:      record.Vector,
+			Description: record.Description,
+			Model:       model,
+		})
+		if len(batch) >= vectorImportBatch {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return total, err
+	}
+	return total, nil
+}
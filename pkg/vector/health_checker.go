@@ -0,0 +1,52 @@
+package vector
+
+import (
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/health"
+)
+
+// vectorStoreChecker 向量存储后端（Qdrant / pgvector / Weaviate）连接健康检查
+type vectorStoreChecker struct{}
+
+func (c *vectorStoreChecker) Name() string {
+	return "vector_store"
+}
+
+func (c *vectorStoreChecker) Check() (health.Status, map[string]interface{}) {
+	details := map[string]interface{}{
+		"backend": setting.GetString("vector", "vector_backend", VectorBackendQdrant),
+	}
+	if !setting.GetBool("vector", "vector_enabled", false) {
+		details["status"] = "disabled"
+		return health.StatusUp, details
+	}
+
+	ve := GetGlobalVectorEngine()
+	if ve == nil {
+		details["error"] = "向量引擎未初始化"
+		return health.StatusDegraded, details
+	}
+	ve.mutex.RLock()
+	store := ve.storage
+	ve.mutex.RUnlock()
+	if store == nil {
+		details["error"] = "向量存储未初始化"
+		return health.StatusDegraded, details
+	}
+
+	// 向量检索不可用时上传与浏览不受影响，失败时视为性能下降
+	details["status"] = "enabled"
+	if err := store.HealthCheck(); err != nil {
+		details["error"] = err.Error()
+		return health.StatusDegraded, details
+	}
+	return health.StatusUp, details
+}
+
+func (c *vectorStoreChecker) Type() health.CheckType {
+	return health.CheckTypeComplete
+}
+
+func init() {
+	health.RegisterChecker(&vectorStoreChecker{})
+}
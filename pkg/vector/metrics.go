@@ -0,0 +1,88 @@
+package vector
+
+import (
+	"sync"
+	"time"
+)
+
+const embedLatencyWindow = 200 // 平均耗时按最近N次调用计算
+
+// VectorEngineMetrics 向量化调用的运行指标
+type VectorEngineMetrics struct {
+	EmbedCalls        int64     `json:"embed_calls"`
+	EmbedErrors       int64     `json:"embed_errors"`
+	AvgEmbedLatencyMs float64   `json:"avg_embed_latency_ms"` // 单条文本的平均耗时
+	LastEmbedAt       time.Time `json:"last_embed_at,omitempty"`
+	LastError         string    `json:"last_error,omitempty"`
+	LastErrorAt       time.Time `json:"last_error_at,omitempty"`
+}
+
+type engineMetrics struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+	stats     VectorEngineMetrics
+}
+
+var vectorMetrics = &engineMetrics{latencies: make([]time.Duration, 0, embedLatencyWindow)}
+
+func (m *engineMetrics) observe(elapsed time.Duration, texts int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.EmbedCalls++
+	if err != nil {
+		m.stats.EmbedErrors++
+		m.stats.LastError = err.Error()
+		m.stats.LastErrorAt = time.Now()
+		return
+	}
+	if texts <= 0 {
+		texts = 1
+	}
+
+	perText := elapsed / time.Duration(texts)
+	if len(m.latencies) < embedLatencyWindow {
+		m.latencies = append(m.latencies, perText)
+	} else {
+		m.latencies[m.next] = perText
+		m.next = (m.next + 1) % embedLatencyWindow
+	}
+	m.stats.LastEmbedAt = time.Now()
+}
+
+// GetVectorEngineMetrics 获取进程内累计的向量化指标，重启后清零
+func GetVectorEngineMetrics() VectorEngineMetrics {
+	vectorMetrics.mu.Lock()
+	defer vectorMetrics.mu.Unlock()
+
+	result := vectorMetrics.stats
+	if len(vectorMetrics.latencies) > 0 {
+		var total time.Duration
+		for _, d := range vectorMetrics.latencies {
+			total += d
+		}
+		avg := total / time.Duration(len(vectorMetrics.latencies))
+		result.AvgEmbedLatencyMs = float64(avg.Microseconds()) / 1000
+	}
+	return result
+}
+
+// meteredEmbeddingProvider 记录向量化耗时与错误
+type meteredEmbeddingProvider struct {
+	EmbeddingProvider
+}
+
+func (p *meteredEmbeddingProvider) GenerateEmbedding(text string) ([]float32, error) {
+	start := time.Now()
+	vector, err := p.EmbeddingProvider.GenerateEmbedding(text)
+	vectorMetrics.observe(time.Since(start), 1, err)
+	return vector, err
+}
+
+func (p *meteredEmbeddingProvider) BatchGenerateEmbeddings(texts []string) ([][]float32, error) {
+	start := time.Now()
+	vectors, err := p.EmbeddingProvider.BatchGenerateEmbeddings(texts)
+	vectorMetrics.observe(time.Since(start), len(texts), err)
+	return vectors, err
+}
@@ -0,0 +1,143 @@
+package vector
+
+import (
+	"fmt"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/common"
+	"pixelpunk/pkg/logger"
+)
+
+// payloadVersion 当前集合向量附带的元数据版本
+func (ve *VectorEngine) payloadVersion() int {
+	active := ve.ActiveCollection()
+	if active == nil {
+		return 0
+	}
+	return active.PayloadVersion
+}
+
+// effectiveFilter 元数据回填完成前去掉存储层尚不支持的条件，由调用方在结果中二次过滤
+func (ve *VectorEngine) effectiveFilter(filter VectorFilter) VectorFilter {
+	version := ve.payloadVersion()
+	if version < 1 {
+		filter.PublicOnly = false
+	}
+	if version < 2 {
+		filter.TagIDs = nil
+		filter.FolderID = ""
+		filter.Formats = nil
+		filter.CreatedFrom = 0
+		filter.CreatedTo = 0
+		filter.NSFW = nil
+	}
+	return filter
+}
+
+// StoreFilterReady 存储层是否能完整执行该过滤条件（否则调用方需要扩大召回并自行过滤）
+func (ve *VectorEngine) StoreFilterReady(filter VectorFilter) bool {
+	version := ve.payloadVersion()
+	if filter.HasFacets() {
+		return version >= 2
+	}
+	return !filter.PublicOnly || version >= 1
+}
+
+// SearchFilesWithFilter 按文本搜索，归属、访问级别及分面条件在向量存储中过滤，保证 topK 准确
+func (ve *VectorEngine) SearchFilesWithFilter(query string, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		logger.Error("向量引擎未初始化: %v", err)
+		return nil, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询为空")
+	}
+
+	queryVector, err := ve.embedding.GenerateEmbedding(query)
+	if err != nil {
+		logger.Error("查询向量化失败: %v", err)
+		return nil, fmt.Errorf("查询向量化失败: %v", err)
+	}
+
+	results, err := ve.storage.SearchVectorsWithFilter(queryVector, limit, ve.effectiveFilter(filter), threshold)
+	if err != nil {
+		logger.Error("向量搜索失败: %v", err)
+		return nil, fmt.Errorf("搜索失败: %v", err)
+	}
+	return results, nil
+}
+
+// SearchSimilarByFileIDWithFilter 以文件已存储的向量搜索相似文件，并在存储层过滤
+func (ve *VectorEngine) SearchSimilarByFileIDWithFilter(fileID string, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		return nil, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+
+	var baseVector models.FileVector
+	if err := ve.db.Where("file_id = ? AND status = ?", fileID, common.VectorStatusCompleted).First(&baseVector).Error; err != nil {
+		return nil, fmt.Errorf("文件向量信息不存在或未完成处理")
+	}
+
+	vec, _, err := ve.storage.FetchVectorWithPayload(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取基准向量失败: %v", err)
+	}
+	return ve.storage.SearchVectorsWithFilter(vec, limit, ve.effectiveFilter(filter), threshold)
+}
+
+// SyncFileMeta 将文件当前的元数据同步到向量存储（含正在构建的新集合）
+func (ve *VectorEngine) SyncFileMeta(fileIDs []string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	if err := ve.ensureInitialized(); err != nil {
+		return nil
+	}
+
+	metaMap := loadFileMeta(fileIDs)
+	target := ve.buildingTarget()
+
+	var lastErr error
+	failed := 0
+	for fileID, meta := range metaMap {
+		if err := ve.storage.UpdateFileMeta(fileID, meta); err != nil {
+			lastErr = err
+			failed++
+			continue
+		}
+		if target != nil {
+			_ = target.storage.UpdateFileMeta(fileID, meta)
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%d 个文件同步元数据失败: %v", failed, lastErr)
+	}
+	return nil
+}
+
+// MarkPayloadSynced 标记当前集合的元数据已回填到最新版本，之后直接在存储层过滤
+func (ve *VectorEngine) MarkPayloadSynced() error {
+	ve.mutex.Lock()
+	if ve.collection == nil {
+		ve.mutex.Unlock()
+		return fmt.Errorf("当前向量集合版本未登记")
+	}
+	ve.collection.PayloadVersion = VectorPayloadVersion
+	collectionID := ve.collection.ID
+	ve.mutex.Unlock()
+
+	return ve.db.Model(&models.VectorCollection{}).Where("id = ?", collectionID).
+		Update("payload_version", VectorPayloadVersion).Error
+}
+
+// SyncFileMeta 文件访问级别、推荐状态、标签或文件夹变化后同步到全局向量引擎，失败仅记录日志
+func SyncFileMeta(fileIDs ...string) {
+	engine := GetGlobalVectorEngine()
+	if engine == nil || !engine.IsEnabled() {
+		return
+	}
+	if err := engine.SyncFileMeta(fileIDs); err != nil {
+		logger.Warn("同步向量元数据失败: %v", err)
+	}
+}
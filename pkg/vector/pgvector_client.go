@@ -0,0 +1,463 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	pgVectorTable     = "file_vector_embedding"
+	pgVectorDimension = 1536 // 与 Qdrant 集合保持一致（text-embedding-3-small）
+)
+
+// PgVectorClient 基于 Postgres pgvector 扩展的向量存储
+type PgVectorClient struct {
+	db        *gorm.DB
+	table     string
+	dimension int
+	timeout   time.Duration
+}
+
+// pgVectorRow 向量表记录
+type pgVectorRow struct {
+	FileID      string
+	UserID      uint
+	Description string
+	Model       string
+	Embedding   string
+	Score       float64
+}
+
+// NewPgVectorClient 连接 Postgres 并返回 pgvector 存储客户端
+func NewPgVectorClient(dsn string, timeout int) (*PgVectorClient, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("未配置 pgvector 连接串")
+	}
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接Postgres失败: %w", err)
+	}
+
+	return &PgVectorClient{
+		db:        db,
+		table:     pgVectorTable,
+		dimension: pgVectorDimension,
+		timeout:   time.Duration(timeout) * time.Second,
+	}, nil
+}
+
+// InitCollection 初始化 pgvector 扩展、向量表及索引
+func (p *PgVectorClient) InitCollection() error {
+	stmts := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			file_id VARCHAR(64) PRIMARY KEY,
+			user_id BIGINT NOT NULL DEFAULT 0,
+			access_level VARCHAR(20) NOT NULL DEFAULT '',
+			is_recommended BOOLEAN NOT NULL DEFAULT FALSE,
+			folder_id VARCHAR(32) NOT NULL DEFAULT '',
+			format VARCHAR(10) NOT NULL DEFAULT '',
+			nsfw BOOLEAN NOT NULL DEFAULT FALSE,
+			file_created_at BIGINT NOT NULL DEFAULT 0,
+			tag_ids BIGINT[] NOT NULL DEFAULT '{}',
+			description TEXT NOT NULL DEFAULT '',
+			model VARCHAR(100) NOT NULL DEFAULT '',
+			embedding vector(%d) NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`, p.table, p.dimension),
+		// 早期版本创建的表没有访问控制及分面列
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS access_level VARCHAR(20) NOT NULL DEFAULT ''", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS is_recommended BOOLEAN NOT NULL DEFAULT FALSE", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS folder_id VARCHAR(32) NOT NULL DEFAULT ''", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS format VARCHAR(10) NOT NULL DEFAULT ''", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS nsfw BOOLEAN NOT NULL DEFAULT FALSE", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS file_created_at BIGINT NOT NULL DEFAULT 0", p.table),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS tag_ids BIGINT[] NOT NULL DEFAULT '{}'", p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_user_id ON %s (user_id)", p.table, p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_public ON %s (access_level, is_recommended)", p.table, p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_folder ON %s (folder_id)", p.table, p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_created ON %s (file_created_at)", p.table, p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_tags ON %s USING gin (tag_ids)", p.table, p.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_embedding ON %s USING hnsw (embedding vector_cosine_ops)", p.table, p.table),
+	}
+	for _, stmt := range stmts {
+		if err := p.db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("初始化pgvector表失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// WithVersion 返回指向指定版本向量表的客户端（共享连接池）
+func (p *PgVectorClient) WithVersion(version int) VectorStore {
+	clone := *p
+	clone.table = versionedCollectionName(pgVectorTable, version)
+	return &clone
+}
+
+// DeleteCollection 删除向量表
+func (p *PgVectorClient) DeleteCollection() error {
+	if err := p.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", p.table)).Error; err != nil {
+		return fmt.Errorf("删除pgvector表失败: %w", err)
+	}
+	return nil
+}
+
+// RecreateCollection 删除并按指定维度重建向量表
+func (p *PgVectorClient) RecreateCollection(dimension int) error {
+	if err := p.DeleteCollection(); err != nil {
+		return err
+	}
+	if dimension > 0 {
+		p.dimension = dimension
+	}
+	return p.InitCollection()
+}
+
+// HealthCheck 健康检查
+func (p *PgVectorClient) HealthCheck() error {
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return fmt.Errorf("pgvector连接不可用: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("pgvector健康检查失败: %w", err)
+	}
+	return nil
+}
+
+// IsEnabled 检查是否启用
+func (p *PgVectorClient) IsEnabled() bool {
+	return p.HealthCheck() == nil
+}
+
+// StoreVector 存储向量（存在则覆盖）
+func (p *PgVectorClient) StoreVector(fileID string, vector []float32, description string, model string) error {
+	meta := loadFileMeta([]string{fileID})[fileID]
+
+	sql := fmt.Sprintf(`INSERT INTO %s (file_id, user_id, access_level, is_recommended, folder_id, format, nsfw, file_created_at, tag_ids,
+			description, model, embedding, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?::bigint[], ?, ?, ?::vector, NOW())
+		ON CONFLICT (file_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			access_level = EXCLUDED.access_level,
+			is_recommended = EXCLUDED.is_recommended,
+			folder_id = EXCLUDED.folder_id,
+			format = EXCLUDED.format,
+			nsfw = EXCLUDED.nsfw,
+			file_created_at = EXCLUDED.file_created_at,
+			tag_ids = EXCLUDED.tag_ids,
+			description = EXCLUDED.description,
+			model = EXCLUDED.model,
+			embedding = EXCLUDED.embedding,
+			updated_at = NOW()`, p.table)
+	if err := p.db.Exec(sql, fileID, meta.UserID, meta.AccessLevel, meta.IsRecommended,
+		meta.FolderID, meta.Format, meta.NSFW, meta.CreatedAt, formatPgIntArray(meta.TagIDs),
+		description, model, formatPgVector(vector)).Error; err != nil {
+		return fmt.Errorf("存储向量失败: %w", err)
+	}
+	return nil
+}
+
+// BatchStoreVectors 批量存储向量
+func (p *PgVectorClient) BatchStoreVectors(items []VectorItem) error {
+	for _, item := range items {
+		if err := p.StoreVector(item.FileID, item.Vector, item.Description, item.Model); err != nil {
+			return fmt.Errorf("批量存储失败，文件ID: %s, 错误: %w", item.FileID, err)
+		}
+	}
+	return nil
+}
+
+// SearchVectors 按余弦相似度搜索向量
+func (p *PgVectorClient) SearchVectors(queryVector []float32, limit int, userID uint, threshold float32) ([]VectorSearchResult, error) {
+	return p.SearchVectorsWithFilter(queryVector, limit, VectorFilter{UserID: userID}, threshold)
+}
+
+// SearchVectorsWithFilter 在SQL中按归属/访问级别过滤后按余弦相似度排序
+func (p *PgVectorClient) SearchVectorsWithFilter(queryVector []float32, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	vec := formatPgVector(queryVector)
+
+	var rows []pgVectorRow
+	query := p.db.Table(p.table).
+		Select("file_id, description, 1 - (embedding <=> ?::vector) AS score", vec).
+		Where("1 - (embedding <=> ?::vector) >= ?", vec, threshold)
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.PublicOnly {
+		query = query.Where("access_level = ? AND is_recommended = ?", vectorAccessPublic, true)
+	}
+	if len(filter.TagIDs) > 0 {
+		query = query.Where("tag_ids @> ?::bigint[]", formatPgIntArray(filter.TagIDs))
+	}
+	if filter.FolderID != "" {
+		query = query.Where("folder_id = ?", filter.FolderID)
+	}
+	if len(filter.Formats) > 0 {
+		query = query.Where("format IN ?", filter.Formats)
+	}
+	if filter.CreatedFrom > 0 {
+		query = query.Where("file_created_at >= ?", filter.CreatedFrom)
+	}
+	if filter.CreatedTo > 0 {
+		query = query.Where("file_created_at <= ?", filter.CreatedTo)
+	}
+	if filter.NSFW != nil {
+		query = query.Where("nsfw = ?", *filter.NSFW)
+	}
+	if err := query.Order(gorm.Expr("embedding <=> ?::vector", vec)).Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("搜索失败: %w", err)
+	}
+
+	results := make([]VectorSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, VectorSearchResult{
+			FileID:      row.FileID,
+			Description: row.Description,
+			Similarity:  float32(row.Score),
+			Score:       float32(row.Score),
+		})
+	}
+	return results, nil
+}
+
+// SearchSimilar 搜索相似向量
+func (p *PgVectorClient) SearchSimilar(queryVector []float32, limit int, userID uint, threshold float32, model string) ([]VectorSearchResult, error) {
+	return p.SearchVectors(queryVector, limit, userID, threshold)
+}
+
+// SearchSimilarWithQuery 带查询的搜索相似向量
+func (p *PgVectorClient) SearchSimilarWithQuery(queryVector []float32, limit int, userID uint, threshold float32, query string, model string) ([]VectorSearchResult, error) {
+	return p.SearchVectors(queryVector, limit, userID, threshold)
+}
+
+// SearchSimilarByID 通过文件ID搜索相似向量
+func (p *PgVectorClient) SearchSimilarByID(fileID string, limit int, userID uint, threshold float32, model string) ([]VectorSearchResult, error) {
+	vec, _, err := p.FetchVectorWithPayload(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取基准向量失败: %w", err)
+	}
+	return p.SearchVectors(vec, limit, userID, threshold)
+}
+
+// FetchVectorWithPayload 获取指定 fileID 的向量及附加信息（description/model等）
+func (p *PgVectorClient) FetchVectorWithPayload(fileID string) ([]float32, map[string]interface{}, error) {
+	var rows []pgVectorRow
+	if err := p.db.Table(p.table).
+		Select("file_id, user_id, description, model, embedding::text AS embedding").
+		Where("file_id = ?", fileID).
+		Limit(1).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, fmt.Errorf("获取向量失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("向量不存在或为空")
+	}
+
+	vec, err := parsePgVector(rows[0].Embedding)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vec) == 0 {
+		return nil, nil, fmt.Errorf("向量不存在或为空")
+	}
+
+	payload := map[string]interface{}{
+		"file_id":     rows[0].FileID,
+		"description": rows[0].Description,
+		"model":       rows[0].Model,
+		"user_id":     rows[0].UserID,
+	}
+	return vec, payload, nil
+}
+
+// GetVector 获取向量记录
+func (p *PgVectorClient) GetVector(fileID string) (*models.FileVector, error) {
+	vec, payload, err := p.FetchVectorWithPayload(fileID)
+	if err != nil {
+		return nil, err
+	}
+	fv := &models.FileVector{FileID: fileID, Dimension: len(vec)}
+	if v, ok := payload["description"].(string); ok {
+		fv.Description = v
+	}
+	if v, ok := payload["model"].(string); ok {
+		fv.Model = v
+	}
+	return fv, nil
+}
+
+// UpdateFileMeta 更新向量附带的文件元数据
+func (p *PgVectorClient) UpdateFileMeta(fileID string, meta VectorFileMeta) error {
+	sql := fmt.Sprintf(`UPDATE %s SET user_id = ?, access_level = ?, is_recommended = ?,
+		folder_id = ?, format = ?, nsfw = ?, file_created_at = ?, tag_ids = ?::bigint[] WHERE file_id = ?`, p.table)
+	if err := p.db.Exec(sql, meta.UserID, meta.AccessLevel, meta.IsRecommended,
+		meta.FolderID, meta.Format, meta.NSFW, meta.CreatedAt, formatPgIntArray(meta.TagIDs), fileID).Error; err != nil {
+		return fmt.Errorf("更新向量元数据失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteVector 删除向量
+func (p *PgVectorClient) DeleteVector(fileID string) error {
+	if err := p.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE file_id = ?", p.table), fileID).Error; err != nil {
+		return fmt.Errorf("删除向量失败: %w", err)
+	}
+	return nil
+}
+
+// VectorExists 检查向量是否存在
+func (p *PgVectorClient) VectorExists(fileID string) (bool, error) {
+	var count int64
+	if err := p.db.Table(p.table).Where("file_id = ?", fileID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查询向量失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetVectorCount 获取用户向量数量，userID 为 0 时统计全部
+func (p *PgVectorClient) GetVectorCount(userID uint) (int64, error) {
+	var count int64
+	query := p.db.Table(p.table)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计向量数量失败: %w", err)
+	}
+	return count, nil
+}
+
+// GetAllFileIDs 遍历获取 file_id，用于对账/清理孤儿；limit<=0 时最多返回 100k
+func (p *PgVectorClient) GetAllFileIDs(limit int) ([]string, error) {
+	if limit <= 0 || limit > 100000 {
+		limit = 100000
+	}
+	var ids []string
+	if err := p.db.Table(p.table).Order("file_id").Limit(limit).Pluck("file_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取向量文件ID失败: %w", err)
+	}
+	return ids, nil
+}
+
+// ScrollVectors 按 file_id 顺序分页读取向量，游标为上一批最后一个 file_id
+func (p *PgVectorClient) ScrollVectors(cursor string, limit int) ([]VectorItem, string, error) {
+	var rows []pgVectorRow
+	query := p.db.Table(p.table).
+		Select("file_id, user_id, description, model, embedding::text AS embedding").
+		Order("file_id").
+		Limit(limit)
+	if cursor != "" {
+		query = query.Where("file_id > ?", cursor)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, "", fmt.Errorf("读取向量失败: %w", err)
+	}
+
+	items := make([]VectorItem, 0, len(rows))
+	for _, row := range rows {
+		vec, err := parsePgVector(row.Embedding)
+		if err != nil {
+			return nil, "", err
+		}
+		items = append(items, VectorItem{FileID: row.FileID, Vector: vec, Description: row.Description, Model: row.Model})
+	}
+
+	next := ""
+	if len(rows) == limit {
+		next = rows[len(rows)-1].FileID
+	}
+	return items, next, nil
+}
+
+// GetStorageStats 获取存储统计
+func (p *PgVectorClient) GetStorageStats() (*VectorStorageStats, error) {
+	count, err := p.GetVectorCount(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	_ = p.db.Raw("SELECT pg_total_relation_size(?::regclass)", p.table).Scan(&size).Error
+
+	return &VectorStorageStats{
+		TotalVectors:   count,
+		CompletedCount: count,
+		LastUpdateTime: time.Now(),
+		StorageSize:    size,
+	}, nil
+}
+
+// Close 关闭数据库连接
+func (p *PgVectorClient) Close() error {
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// formatPgVector 转为 pgvector 文本格式，如 [0.1,0.2]
+func formatPgVector(vec []float32) string {
+	var sb strings.Builder
+	sb.Grow(len(vec) * 10)
+	sb.WriteByte('[')
+	for i, v := range vec {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// formatPgIntArray 转为 Postgres 数组文本格式，如 {1,2}
+func formatPgIntArray(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// parsePgVector 解析 pgvector 文本格式
+func parsePgVector(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "[")
+	s = strings.TrimSuffix(s, "]")
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	vec := make([]float32, 0, len(parts))
+	for _, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("解析向量失败: %w", err)
+		}
+		vec = append(vec, float32(f))
+	}
+	return vec, nil
+}
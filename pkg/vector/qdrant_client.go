@@ -2,17 +2,19 @@ package vector
 
 import (
 	"bytes"
-	"crypto/md5"
-	"encoding/hex"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"pixelpunk/internal/models"
-	"pixelpunk/pkg/database"
 	"pixelpunk/pkg/logger"
+	"pixelpunk/pkg/tracing"
 )
 
 // QdrantClient 直接连接Qdrant的简单客户端
@@ -20,6 +22,7 @@ type QdrantClient struct {
 	baseURL    string
 	httpClient *http.Client
 	collection string
+	dimension  int
 }
 
 // QdrantPoint Qdrant点结构
@@ -55,29 +58,85 @@ type QdrantSearchResult struct {
 	Vector  []float32              `json:"vector,omitempty"`
 }
 
+const qdrantCollectionName = "file_vectors"
+
 func NewQdrantClient(qdrantURL string, timeout int) *QdrantClient {
 	return &QdrantClient{
 		baseURL:    qdrantURL,
-		httpClient: &http.Client{Timeout: time.Duration(timeout) * time.Second},
-		collection: "file_vectors",
+		httpClient: &http.Client{Timeout: time.Duration(timeout) * time.Second, Transport: tracing.NewTransport("vector.qdrant", nil)},
+		collection: qdrantCollectionName,
+		dimension:  1536, // text-embedding-3-small 向量维度
 	}
 }
 
-// generateQdrantID 基于文件ID生成确定性UUID
-func (q *QdrantClient) generateQdrantID(fileID string) string {
-	// 使用MD5哈希生成确定性的UUID
-	hash := md5.Sum([]byte(fileID))
-	hashHex := hex.EncodeToString(hash[:])
+// QdrantOptions Qdrant认证与TLS选项，用于连接托管或开启鉴权的实例
+type QdrantOptions struct {
+	APIKey             string // 通过 api-key 请求头发送
+	CACert             string // 自签名证书的CA，PEM内容或证书文件路径
+	InsecureSkipVerify bool   // 跳过证书校验，仅用于测试环境
+}
+
+// qdrantTransport 为每个请求附加 api-key 请求头
+type qdrantTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+func (t *qdrantTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.apiKey != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("api-key", t.apiKey)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// NewQdrantClientWithOptions 创建带认证/TLS配置的客户端，CA证书无效时返回错误
+func NewQdrantClientWithOptions(qdrantURL string, timeout int, opts QdrantOptions) (*QdrantClient, error) {
+	client := NewQdrantClient(qdrantURL, timeout)
+	if opts.APIKey == "" && opts.CACert == "" && !opts.InsecureSkipVerify {
+		return client, nil
+	}
 
-	// 将32位十六进制字符串格式化为UUID格式
-	uuidStr := fmt.Sprintf("%s-%s-%s-%s-%s",
-		hashHex[0:8],
-		hashHex[8:12],
-		hashHex[12:16],
-		hashHex[16:20],
-		hashHex[20:32])
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.CACert != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+		if opts.CACert != "" {
+			pool, err := loadQdrantCACert(opts.CACert)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	client.httpClient.Transport = tracing.NewTransport("vector.qdrant", &qdrantTransport{apiKey: opts.APIKey, base: transport})
+	return client, nil
+}
+
+// loadQdrantCACert 解析CA证书，非PEM内容时按文件路径读取
+func loadQdrantCACert(caCert string) (*x509.CertPool, error) {
+	pemData := []byte(caCert)
+	if !strings.Contains(caCert, "-----BEGIN") {
+		data, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("读取Qdrant CA证书失败: %v", err)
+		}
+		pemData = data
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("Qdrant CA证书格式无效")
+	}
+	return pool, nil
+}
 
-	return uuidStr
+// generateQdrantID 基于文件ID生成确定性UUID
+func (q *QdrantClient) generateQdrantID(fileID string) string {
+	return fileIDToUUID(fileID)
 }
 
 func (q *QdrantClient) collectionExists() bool {
@@ -94,6 +153,7 @@ func (q *QdrantClient) InitCollection() error {
 	resp, err := q.httpClient.Get(fmt.Sprintf("%s/collections/%s", q.baseURL, q.collection))
 	if err == nil && resp != nil && resp.StatusCode == 200 {
 		resp.Body.Close()
+		q.ensurePayloadIndexes()
 		return nil
 	}
 	if resp != nil {
@@ -102,7 +162,7 @@ func (q *QdrantClient) InitCollection() error {
 
 	createReq := map[string]interface{}{
 		"vectors": map[string]interface{}{
-			"size":     1536, // text-embedding-3-small 向量维度
+			"size":     q.dimension,
 			"distance": "Cosine",
 		},
 	}
@@ -131,6 +191,78 @@ func (q *QdrantClient) InitCollection() error {
 		return fmt.Errorf("创建集合失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
 	}
 
+	q.ensurePayloadIndexes()
+	return nil
+}
+
+// ensurePayloadIndexes 为过滤字段建立payload索引（已存在时Qdrant直接返回成功）
+func (q *QdrantClient) ensurePayloadIndexes() {
+	fields := map[string]string{
+		"user_id":        "integer",
+		"access_level":   "keyword",
+		"is_recommended": "bool",
+		"folder_id":      "keyword",
+		"format":         "keyword",
+		"nsfw":           "bool",
+		"created_at":     "integer",
+		"tag_ids":        "integer",
+	}
+	for field, schema := range fields {
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"field_name":   field,
+			"field_schema": schema,
+		})
+		req, err := http.NewRequest("PUT",
+			fmt.Sprintf("%s/collections/%s/index", q.baseURL, q.collection),
+			bytes.NewBuffer(reqBody))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := q.httpClient.Do(req)
+		if err != nil {
+			logger.Warn("创建payload索引 %s 失败: %v", field, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+// WithVersion 返回指向指定版本集合的客户端
+func (q *QdrantClient) WithVersion(version int) VectorStore {
+	clone := *q
+	clone.collection = versionedCollectionName(qdrantCollectionName, version)
+	return &clone
+}
+
+// RecreateCollection 删除并按指定维度重建集合
+func (q *QdrantClient) RecreateCollection(dimension int) error {
+	if err := q.DeleteCollection(); err != nil {
+		return err
+	}
+
+	if dimension > 0 {
+		q.dimension = dimension
+	}
+	return q.InitCollection()
+}
+
+// DeleteCollection 删除集合
+func (q *QdrantClient) DeleteCollection() error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/collections/%s", q.baseURL, q.collection), nil)
+	if err != nil {
+		return fmt.Errorf("创建DELETE请求失败: %w", err)
+	}
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("删除集合请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("删除集合失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
 	return nil
 }
 
@@ -147,23 +279,15 @@ func (q *QdrantClient) StoreVector(fileID string, vector []float32, description
 	}
 
 	qdrantID := q.generateQdrantID(fileID)
-	var userID uint
-	if db := database.GetDB(); db != nil {
-		var file models.File
-		if err := db.Where("id = ?", fileID).First(&file).Error; err == nil {
-			userID = file.UserID
-		}
-	}
+	payload := qdrantMetaPayload(loadFileMeta([]string{fileID})[fileID])
+	payload["file_id"] = fileID // 保存原始文件ID
+	payload["description"] = description
+	payload["model"] = model
 
 	point := QdrantPoint{
-		Id:     qdrantID,
-		Vector: vector,
-		Payload: map[string]interface{}{
-			"file_id":     fileID, // 保存原始文件ID
-			"description": description,
-			"model":       model,
-			"user_id":     userID,
-		},
+		Id:      qdrantID,
+		Vector:  vector,
+		Payload: payload,
 	}
 
 	reqBody := map[string]interface{}{
@@ -199,6 +323,11 @@ func (q *QdrantClient) StoreVector(fileID string, vector []float32, description
 
 // SearchVectors 搜索相似向量
 func (q *QdrantClient) SearchVectors(queryVector []float32, limit int, userID uint, threshold float32) ([]VectorSearchResult, error) {
+	return q.SearchVectorsWithFilter(queryVector, limit, VectorFilter{UserID: userID}, threshold)
+}
+
+// SearchVectorsWithFilter 使用payload过滤搜索相似向量
+func (q *QdrantClient) SearchVectorsWithFilter(queryVector []float32, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error) {
 	searchReq := QdrantSearchRequest{
 		Vector:         queryVector,
 		Limit:          limit,
@@ -207,15 +336,8 @@ func (q *QdrantClient) SearchVectors(queryVector []float32, limit int, userID ui
 		ScoreThreshold: &threshold,
 	}
 
-	if userID > 0 {
-		searchReq.Filter = map[string]interface{}{
-			"must": []map[string]interface{}{
-				{
-					"key":   "user_id",
-					"match": map[string]interface{}{"value": userID},
-				},
-			},
-		}
+	if must := qdrantFilterConditions(filter); len(must) > 0 {
+		searchReq.Filter = map[string]interface{}{"must": must}
 	}
 
 	reqData, err := json.Marshal(searchReq)
@@ -285,19 +407,55 @@ func (q *QdrantClient) HealthCheck() error {
 	return nil
 }
 
-// 当前未实现，返回0（该功能暂不需要）
+// GetUserVectorCount 精确统计向量数量，userID 为 0 时统计全部
 func (q *QdrantClient) GetUserVectorCount(userID uint) (int64, error) {
-	return 0, nil
+	reqBody := map[string]interface{}{"exact": true}
+	if userID > 0 {
+		reqBody["filter"] = map[string]interface{}{
+			"must": []map[string]interface{}{
+				{"key": "user_id", "match": map[string]interface{}{"value": userID}},
+			},
+		}
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("序列化count请求失败: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/count", q.baseURL, q.collection)
+	resp, err := q.httpClient.Post(url, "application/json", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return 0, fmt.Errorf("count 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("count 失败，状态码: %d, 响应: %s", resp.StatusCode, string(b))
+	}
+
+	var cr struct {
+		Result struct {
+			Count int64 `json:"count"`
+		} `json:"result"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return 0, fmt.Errorf("解析count响应失败: %w", err)
+	}
+	if cr.Status != "ok" {
+		return 0, fmt.Errorf("count 响应状态异常: %s", cr.Status)
+	}
+	return cr.Result.Count, nil
 }
 
 // GetAllFileIDs 从 Qdrant 遍历获取 file_id（通过 payload.file_id），用于对账/清理孤儿
 // limit: 最多返回的数量；<=0 表示不限制（但为安全起见这里按批次滚动，最多返回 100k）
 func (q *QdrantClient) GetAllFileIDs(limit int) ([]string, error) {
 	type scrollReq struct {
-		WithPayload bool                    `json:"with_payload"`
-		WithVector  bool                    `json:"with_vector"`
-		Limit       int                     `json:"limit"`
-		Offset      *map[string]interface{} `json:"offset,omitempty"`
+		WithPayload bool            `json:"with_payload"`
+		WithVector  bool            `json:"with_vector"`
+		Limit       int             `json:"limit"`
+		Offset      json.RawMessage `json:"offset,omitempty"`
 	}
 	type point struct {
 		ID      interface{}            `json:"id"`
@@ -305,8 +463,8 @@ func (q *QdrantClient) GetAllFileIDs(limit int) ([]string, error) {
 	}
 	type scrollResp struct {
 		Result struct {
-			Points []point                 `json:"points"`
-			Offset *map[string]interface{} `json:"offset"`
+			Points []point         `json:"points"`
+			Offset json.RawMessage `json:"next_page_offset"`
 		} `json:"result"`
 		Status string  `json:"status"`
 		Time   float64 `json:"time"`
@@ -323,7 +481,7 @@ func (q *QdrantClient) GetAllFileIDs(limit int) ([]string, error) {
 	}
 
 	var all []string
-	var offset *map[string]interface{}
+	var offset json.RawMessage
 	for {
 		reqBody := scrollReq{WithPayload: true, WithVector: false, Limit: batch, Offset: offset}
 		bodyBytes, err := json.Marshal(reqBody)
@@ -361,7 +519,7 @@ func (q *QdrantClient) GetAllFileIDs(limit int) ([]string, error) {
 		if len(all) >= maxTotal {
 			break
 		}
-		if sr.Result.Offset == nil {
+		if len(sr.Result.Offset) == 0 || string(sr.Result.Offset) == "null" {
 			break
 		}
 		offset = sr.Result.Offset
@@ -374,6 +532,113 @@ func (q *QdrantClient) GetAllFileIDs(limit int) ([]string, error) {
 	return all, nil
 }
 
+// ScrollVectors 通过 scroll 接口按批次读取向量及 payload，游标为 Qdrant 返回的 next_page_offset
+func (q *QdrantClient) ScrollVectors(cursor string, limit int) ([]VectorItem, string, error) {
+	reqBody := map[string]interface{}{
+		"with_payload": true,
+		"with_vector":  true,
+		"limit":        limit,
+	}
+	if cursor != "" {
+		reqBody["offset"] = json.RawMessage(cursor)
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("序列化scroll请求失败: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/scroll", q.baseURL, q.collection)
+	resp, err := q.httpClient.Post(url, "application/json", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, "", fmt.Errorf("scroll 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("scroll 失败，状态码: %d, 响应: %s", resp.StatusCode, string(b))
+	}
+
+	var sr struct {
+		Result struct {
+			Points []struct {
+				Vector  []float32              `json:"vector"`
+				Payload map[string]interface{} `json:"payload"`
+			} `json:"points"`
+			Offset json.RawMessage `json:"next_page_offset"`
+		} `json:"result"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		return nil, "", fmt.Errorf("解析scroll响应失败: %w", err)
+	}
+	if sr.Status != "ok" {
+		return nil, "", fmt.Errorf("scroll 响应状态异常: %s", sr.Status)
+	}
+
+	items := make([]VectorItem, 0, len(sr.Result.Points))
+	for _, p := range sr.Result.Points {
+		item := VectorItem{Vector: p.Vector}
+		item.FileID, _ = p.Payload["file_id"].(string)
+		item.Description, _ = p.Payload["description"].(string)
+		item.Model, _ = p.Payload["model"].(string)
+		items = append(items, item)
+	}
+
+	next := ""
+	if len(sr.Result.Offset) > 0 && string(sr.Result.Offset) != "null" {
+		next = string(sr.Result.Offset)
+	}
+	return items, next, nil
+}
+
+// diskUsage 从 telemetry 汇总当前集合各分段的磁盘占用，不可用时返回0
+func (q *QdrantClient) diskUsage() int64 {
+	resp, err := q.httpClient.Get(q.baseURL + "/telemetry?details_level=3")
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return 0
+	}
+
+	var telemetry struct {
+		Result struct {
+			Collections struct {
+				Collections []map[string]interface{} `json:"collections"`
+			} `json:"collections"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&telemetry); err != nil {
+		return 0
+	}
+	for _, collection := range telemetry.Result.Collections.Collections {
+		if id, _ := collection["id"].(string); id == q.collection {
+			return sumDiskUsage(collection)
+		}
+	}
+	return 0
+}
+
+func sumDiskUsage(node interface{}) int64 {
+	var total int64
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if n, ok := child.(float64); ok && key == "disk_usage_bytes" {
+				total += int64(n)
+				continue
+			}
+			total += sumDiskUsage(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			total += sumDiskUsage(child)
+		}
+	}
+	return total
+}
+
 func (q *QdrantClient) GetStorageStats() (*VectorStorageStats, error) {
 	// 优先使用 /points/count 以提升跨版本兼容性
 	type countResp struct {
@@ -397,7 +662,7 @@ func (q *QdrantClient) GetStorageStats() (*VectorStorageStats, error) {
 				FailedCount:    0,
 				AverageNorm:    0,
 				LastUpdateTime: time.Now(),
-				StorageSize:    0,
+				StorageSize:    q.diskUsage(),
 			}, nil
 		}
 	}
@@ -626,6 +891,99 @@ func (q *QdrantClient) SearchSimilarByID(fileID string, limit int, userID uint,
 	return q.SearchVectors(pointResp.Result.Vector, limit, userID, threshold)
 }
 
+// qdrantMetaPayload 文件元数据对应的payload字段
+func qdrantMetaPayload(meta VectorFileMeta) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":        meta.UserID,
+		"access_level":   meta.AccessLevel,
+		"is_recommended": meta.IsRecommended,
+		"folder_id":      meta.FolderID,
+		"format":         meta.Format,
+		"nsfw":           meta.NSFW,
+		"created_at":     meta.CreatedAt,
+		"tag_ids":        meta.TagIDs,
+	}
+}
+
+// qdrantFilterConditions 将过滤条件转换为 must 子句
+func qdrantFilterConditions(filter VectorFilter) []map[string]interface{} {
+	var must []map[string]interface{}
+	match := func(key string, value interface{}) {
+		must = append(must, map[string]interface{}{
+			"key":   key,
+			"match": map[string]interface{}{"value": value},
+		})
+	}
+
+	if filter.UserID > 0 {
+		match("user_id", filter.UserID)
+	}
+	if filter.PublicOnly {
+		match("access_level", vectorAccessPublic)
+		match("is_recommended", true)
+	}
+	// 数组字段的 match 只要求包含该值，逐个标签添加条件即为“同时包含”
+	for _, tagID := range filter.TagIDs {
+		match("tag_ids", tagID)
+	}
+	if filter.FolderID != "" {
+		match("folder_id", filter.FolderID)
+	}
+	if len(filter.Formats) > 0 {
+		must = append(must, map[string]interface{}{
+			"key":   "format",
+			"match": map[string]interface{}{"any": filter.Formats},
+		})
+	}
+	if filter.CreatedFrom > 0 || filter.CreatedTo > 0 {
+		rng := map[string]interface{}{}
+		if filter.CreatedFrom > 0 {
+			rng["gte"] = filter.CreatedFrom
+		}
+		if filter.CreatedTo > 0 {
+			rng["lte"] = filter.CreatedTo
+		}
+		must = append(must, map[string]interface{}{"key": "created_at", "range": rng})
+	}
+	if filter.NSFW != nil {
+		match("nsfw", *filter.NSFW)
+	}
+	return must
+}
+
+// UpdateFileMeta 更新点的文件元数据payload（点不存在时忽略）
+func (q *QdrantClient) UpdateFileMeta(fileID string, meta VectorFileMeta) error {
+	reqBody := map[string]interface{}{
+		"payload": qdrantMetaPayload(meta),
+		"points":  []string{q.generateQdrantID(fileID)},
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	resp, err := q.httpClient.Post(
+		fmt.Sprintf("%s/collections/%s/points/payload", q.baseURL, q.collection),
+		"application/json",
+		bytes.NewBuffer(data),
+	)
+	if err != nil {
+		return fmt.Errorf("更新payload请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("更新payload失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Close Qdrant HTTP客户端无需显式关闭
+func (q *QdrantClient) Close() error {
+	return nil
+}
+
 // IsEnabled 检查是否启用
 func (q *QdrantClient) IsEnabled() bool {
 	return q.HealthCheck() == nil
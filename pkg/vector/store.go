@@ -0,0 +1,155 @@
+package vector
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/internal/services/setting"
+	"pixelpunk/pkg/database"
+)
+
+// 向量存储后端
+const (
+	VectorBackendQdrant   = "qdrant"
+	VectorBackendPgVector = "pgvector"
+	VectorBackendWeaviate = "weaviate"
+)
+
+// NewVectorStoreFromSettings 根据 vector 组配置创建向量存储后端
+func NewVectorStoreFromSettings() (VectorStore, error) {
+	backend := setting.GetStringDirectFromDB("vector", "vector_backend", VectorBackendQdrant)
+	timeout := setting.GetIntDirectFromDB("vector", "qdrant_timeout", 30)
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	switch backend {
+	case VectorBackendQdrant, "":
+		qdrantURL := setting.GetStringDirectFromDB("vector", "qdrant_url", "")
+		if qdrantURL == "" {
+			return nil, fmt.Errorf("向量后端为qdrant，但未配置 qdrant_url")
+		}
+		qdrantClient, err := NewQdrantClientWithOptions(qdrantURL, timeout, QdrantOptionsFromSettings())
+		if err != nil {
+			return nil, err
+		}
+		return qdrantClient, nil
+	case VectorBackendPgVector:
+		dsn := setting.GetStringDirectFromDB("vector", "pgvector_dsn", "")
+		if dsn == "" {
+			return nil, fmt.Errorf("向量后端为pgvector，但未配置 pgvector_dsn")
+		}
+		pgClient, err := NewPgVectorClient(dsn, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return pgClient, nil
+	case VectorBackendWeaviate:
+		weaviateURL := setting.GetStringDirectFromDB("vector", "weaviate_url", "")
+		if weaviateURL == "" {
+			return nil, fmt.Errorf("向量后端为weaviate，但未配置 weaviate_url")
+		}
+		apiKey := setting.GetStringDirectFromDB("vector", "weaviate_api_key", "")
+		return NewWeaviateClient(weaviateURL, apiKey, timeout), nil
+	default:
+		return nil, fmt.Errorf("不支持的向量后端: %s", backend)
+	}
+}
+
+// vectorStoreSignature 创建存储客户端所用的连接配置，用于判断是否需要重建客户端
+func vectorStoreSignature() string {
+	return strings.Join([]string{
+		currentVectorBackend(),
+		strconv.Itoa(setting.GetIntDirectFromDB("vector", "qdrant_timeout", 30)),
+		setting.GetStringDirectFromDB("vector", "qdrant_url", ""),
+		setting.GetStringDirectFromDB("vector", "qdrant_api_key", ""),
+		setting.GetStringDirectFromDB("vector", "qdrant_ca_cert", ""),
+		strconv.FormatBool(setting.GetBoolDirectFromDB("vector", "qdrant_tls_skip_verify", false)),
+		setting.GetStringDirectFromDB("vector", "pgvector_dsn", ""),
+		setting.GetStringDirectFromDB("vector", "weaviate_url", ""),
+		setting.GetStringDirectFromDB("vector", "weaviate_api_key", ""),
+	}, "\x00")
+}
+
+// QdrantOptionsFromSettings 读取 vector 组中的Qdrant认证与TLS配置
+func QdrantOptionsFromSettings() QdrantOptions {
+	return QdrantOptions{
+		APIKey:             setting.GetStringDirectFromDB("vector", "qdrant_api_key", ""),
+		CACert:             setting.GetStringDirectFromDB("vector", "qdrant_ca_cert", ""),
+		InsecureSkipVerify: setting.GetBoolDirectFromDB("vector", "qdrant_tls_skip_verify", false),
+	}
+}
+
+// versionedCollectionName 版本1沿用原始名称，兼容引入版本化之前的数据
+func versionedCollectionName(base string, version int) string {
+	if version <= 1 {
+		return base
+	}
+	return fmt.Sprintf("%s_v%d", base, version)
+}
+
+// 画廊可见的访问级别（与 file 服务的 AccessPublic 保持一致）
+const vectorAccessPublic = "public"
+
+// VectorPayloadVersion 向量附带元数据的版本：1 访问级别，2 标签/文件夹/格式/时间/NSFW 分面
+const VectorPayloadVersion = 2
+
+// loadFileMeta 批量读取文件的归属、访问级别及分面字段，写入向量时一并存储用于存储层过滤
+func loadFileMeta(fileIDs []string) map[string]VectorFileMeta {
+	result := make(map[string]VectorFileMeta, len(fileIDs))
+	db := database.GetDB()
+	if db == nil || len(fileIDs) == 0 {
+		return result
+	}
+
+	var files []models.File
+	if err := db.Select("id, user_id, access_level, is_recommended, folder_id, format, nsfw, created_at").
+		Where("id IN ?", fileIDs).Find(&files).Error; err != nil {
+		return result
+	}
+
+	var relations []models.FileGlobalTagRelation
+	db.Select("file_id, tag_id").Where("file_id IN ?", fileIDs).Find(&relations)
+	tagMap := make(map[string][]uint, len(files))
+	for _, r := range relations {
+		tagMap[r.FileID] = append(tagMap[r.FileID], r.TagID)
+	}
+
+	for _, f := range files {
+		tagIDs := tagMap[f.ID]
+		if tagIDs == nil {
+			tagIDs = []uint{}
+		}
+		result[f.ID] = VectorFileMeta{
+			UserID:        f.UserID,
+			AccessLevel:   f.AccessLevel,
+			IsRecommended: f.IsRecommended,
+			FolderID:      f.FolderID,
+			Format:        strings.ToLower(f.Format),
+			NSFW:          f.NSFW,
+			CreatedAt:     time.Time(f.CreatedAt).Unix(),
+			TagIDs:        tagIDs,
+		}
+	}
+	return result
+}
+
+// fileIDToUUID 基于文件ID生成确定性UUID（Qdrant点ID / Weaviate对象ID）
+func fileIDToUUID(fileID string) string {
+	// 使用MD5哈希生成确定性的UUID
+	hash := md5.Sum([]byte(fileID))
+	hashHex := hex.EncodeToString(hash[:])
+
+	// 将32位十六进制字符串格式化为UUID格式
+	return fmt.Sprintf("%s-%s-%s-%s-%s",
+		hashHex[0:8],
+		hashHex[8:12],
+		hashHex[12:16],
+		hashHex[16:20],
+		hashHex[20:32])
+}
@@ -18,6 +18,63 @@ type VectorStorage interface {
 	VectorExists(fileID string) (bool, error) // 新增：检查向量是否存在
 }
 
+// VectorStore 向量存储后端接口，引擎只依赖该接口，具体后端由 vector_backend 配置选择
+type VectorStore interface {
+	VectorStorage
+
+	// InitCollection 创建集合/表（已存在时直接返回）
+	InitCollection() error
+	// RecreateCollection 删除并按指定维度重建集合（切换向量模型后重建索引使用）
+	RecreateCollection(dimension int) error
+	// DeleteCollection 删除集合/表（不存在时忽略）
+	DeleteCollection() error
+	// WithVersion 返回指向指定版本集合的存储客户端（共享连接），版本1为原始集合
+	WithVersion(version int) VectorStore
+	HealthCheck() error
+	Close() error
+
+	FetchVectorWithPayload(fileID string) ([]float32, map[string]interface{}, error)
+	SearchSimilarByID(fileID string, limit int, userID uint, threshold float32, model string) ([]VectorSearchResult, error)
+	GetAllFileIDs(limit int) ([]string, error)
+	// ScrollVectors 按批次遍历全部向量及附加信息，cursor 为空时从头开始，返回的游标为空表示遍历结束
+	ScrollVectors(cursor string, limit int) ([]VectorItem, string, error)
+
+	// SearchVectorsWithFilter 在存储层按归属/访问级别及分面条件过滤后取 topK，避免 Go 侧后过滤导致结果不足或越权
+	SearchVectorsWithFilter(queryVector []float32, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error)
+	// UpdateFileMeta 更新向量附带的文件元数据（访问级别、标签、文件夹等变化时调用）
+	UpdateFileMeta(fileID string, meta VectorFileMeta) error
+}
+
+// VectorFilter 存储层搜索过滤条件，零值字段不参与过滤
+type VectorFilter struct {
+	UserID      uint     // 大于0时只返回该用户的文件
+	PublicOnly  bool     // 只返回公开且被推荐的文件（画廊搜索）
+	TagIDs      []uint   // 必须同时包含这些标签
+	FolderID    string   // 所在文件夹
+	Formats     []string // 文件格式，任一匹配即可
+	CreatedFrom int64    // 上传时间下限（Unix秒）
+	CreatedTo   int64    // 上传时间上限（Unix秒）
+	NSFW        *bool    // 是否为敏感内容
+}
+
+// HasFacets 是否包含访问控制以外的分面条件
+func (f VectorFilter) HasFacets() bool {
+	return len(f.TagIDs) > 0 || f.FolderID != "" || len(f.Formats) > 0 ||
+		f.CreatedFrom > 0 || f.CreatedTo > 0 || f.NSFW != nil
+}
+
+// VectorFileMeta 随向量一起存储的文件元数据，用于存储层过滤
+type VectorFileMeta struct {
+	UserID        uint
+	AccessLevel   string
+	IsRecommended bool
+	FolderID      string
+	Format        string
+	NSFW          bool
+	CreatedAt     int64 // Unix秒
+	TagIDs        []uint
+}
+
 // VectorItem 批量向量处理项
 type VectorItem struct {
 	FileID      string
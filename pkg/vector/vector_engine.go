@@ -121,11 +121,14 @@ func getVectorConfigFromDB() (*VectorConfig, error) {
 
 // VectorEngine 向量引擎
 type VectorEngine struct {
-	db        *gorm.DB
-	storage   VectorStorage
-	embedding EmbeddingProvider
-	enabled   bool
-	mutex     sync.RWMutex
+	db         *gorm.DB
+	storage    VectorStore
+	embedding  EmbeddingProvider
+	collection *models.VectorCollection // 当前生效的集合版本
+	building   *collectionTarget        // 正在后台构建的新版本集合
+	storeSig   string                   // 创建存储客户端时的连接配置，变化时 ReloadConfig 重建客户端
+	enabled    bool
+	mutex      sync.RWMutex
 }
 
 // VectorService 向量服务接口
@@ -148,7 +151,7 @@ type VectorService interface {
 
 var (
 	globalVectorEngine *VectorEngine
-	engineOnce         sync.Once
+	engineMu           sync.Mutex
 )
 
 func NewVectorEngine(db *gorm.DB) *VectorEngine {
@@ -171,9 +174,11 @@ func GetEngine() *VectorEngine {
 
 // InitGlobalVectorEngine 初始化全局向量引擎（嵌入式模式，已弃用）
 func InitGlobalVectorEngine(db *gorm.DB) error {
-	engineOnce.Do(func() {
+	engineMu.Lock()
+	if globalVectorEngine == nil {
 		globalVectorEngine = NewVectorEngine(db)
-	})
+	}
+	engineMu.Unlock()
 
 	if globalVectorEngine != nil && globalVectorEngine.enabled {
 		return globalVectorEngine.Initialize()
@@ -184,30 +189,51 @@ func InitGlobalVectorEngine(db *gorm.DB) error {
 
 // InitQdrantVectorEngine 初始化Qdrant向量引擎（直连模式，使用动态配置）
 func InitQdrantVectorEngine(qdrantURL string, timeout int) error {
-	engineOnce.Do(func() {
-		qdrantClient := NewQdrantClient(qdrantURL, timeout)
+	return initVectorEngineWithStore(NewQdrantClient(qdrantURL, timeout))
+}
 
-		if err := qdrantClient.InitCollection(); err != nil {
-			logger.Error("初始化Qdrant集合失败: %v", err)
-		}
+// InitConfiguredVectorEngine 按 vector_backend 配置初始化对应的向量引擎
+func InitConfiguredVectorEngine() error {
+	store, err := NewVectorStoreFromSettings()
+	if err != nil {
+		return err
+	}
+	return initVectorEngineWithStore(store)
+}
 
-		// 使用动态OpenAI客户端（无需初始化配置，每次调用时动态读取）
-		dynamicClient := NewDynamicOpenAIClient()
+// initVectorEngineWithStore 使用指定存储后端初始化全局向量引擎，已初始化时不重复创建；
+// 失败时关闭存储并返回错误，引擎保持未初始化，下次初始化时重试
+func initVectorEngineWithStore(store VectorStore) error {
+	engineMu.Lock()
+	defer engineMu.Unlock()
 
-		db := database.GetDB()
-		if db == nil {
-			logger.Error("数据库连接不可用，向量引擎初始化失败")
-			return
-		}
+	if globalVectorEngine != nil {
+		_ = store.Close()
+		return nil
+	}
 
-		globalVectorEngine = &VectorEngine{
-			db:        db,
-			storage:   qdrantClient,
-			embedding: dynamicClient, // 动态客户端，自动读取最新配置
-			enabled:   true,
-		}
-	})
+	db := database.GetDB()
+	if db == nil {
+		_ = store.Close()
+		return fmt.Errorf("数据库连接不可用，向量引擎初始化失败")
+	}
+
+	// 按当前生效的集合版本选择集合及其绑定的向量模型
+	collection := loadActiveCollection(db)
+	versioned := store.WithVersion(collection.Version)
+	if err := versioned.InitCollection(); err != nil {
+		_ = store.Close()
+		return fmt.Errorf("初始化向量集合失败: %v", err)
+	}
 
+	globalVectorEngine = &VectorEngine{
+		db:         db,
+		storage:    versioned,
+		embedding:  NewPinnedEmbeddingProvider(collection.Provider, collection.Model),
+		collection: collection,
+		storeSig:   vectorStoreSignature(),
+		enabled:    true,
+	}
 	return nil
 }
 
@@ -232,12 +258,10 @@ func (ve *VectorEngine) ensureInitialized() error {
 		return fmt.Errorf("embedding客户端未初始化")
 	}
 
-	// 对于QdrantClient，检查连接是否可用
-	if qdrantClient, ok := ve.storage.(*QdrantClient); ok {
-		if err := qdrantClient.HealthCheck(); err != nil {
-			logger.Warn("Qdrant连接检查失败: %v", err)
-			return fmt.Errorf("qdrant不可用: %v", err)
-		}
+	// 检查存储后端连接是否可用
+	if err := ve.storage.HealthCheck(); err != nil {
+		logger.Warn("向量存储连接检查失败: %v", err)
+		return fmt.Errorf("向量存储不可用: %v", err)
 	}
 
 	return nil
@@ -252,7 +276,10 @@ func (ve *VectorEngine) Close() error {
 	ve.mutex.Lock()
 	defer ve.mutex.Unlock()
 
-	// Qdrant HTTP客户端不需要显式关闭
+	// HTTP类客户端不需要显式关闭，pgvector 需要释放连接池
+	if ve.storage != nil {
+		_ = ve.storage.Close()
+	}
 
 	ve.storage = nil
 	ve.embedding = nil
@@ -298,7 +325,7 @@ func (ve *VectorEngine) Initialize() error {
 }
 
 // CloneVectorFrom 从已有文件复制向量到新文件（用于重复文件零成本复用）
-// description 为空时将尝试从Qdrant payload 或 AIInfo 获取
+// description 为空时将尝试从存储的 payload 或 AIInfo 获取
 func (ve *VectorEngine) CloneVectorFrom(originalID, newID, description string) error {
 	if ve == nil {
 		return nil
@@ -307,13 +334,7 @@ func (ve *VectorEngine) CloneVectorFrom(originalID, newID, description string) e
 		return fmt.Errorf("向量引擎未就绪: %v", err)
 	}
 
-	// 仅在存储为 Qdrant 时支持直接拷贝
-	qc, ok := ve.storage.(*QdrantClient)
-	if !ok {
-		return nil // 非Qdrant存储暂不处理，后续由队列兜底
-	}
-
-	vec, payload, err := qc.FetchVectorWithPayload(originalID)
+	vec, payload, err := ve.storage.FetchVectorWithPayload(originalID)
 	if err != nil {
 		return err
 	}
@@ -343,6 +364,11 @@ func (ve *VectorEngine) CloneVectorFrom(originalID, newID, description string) e
 	if err := ve.storage.StoreVector(newID, vec, desc, model); err != nil {
 		return err
 	}
+	if target := ve.buildingTarget(); target != nil {
+		if buildVec, _, err := target.storage.FetchVectorWithPayload(originalID); err == nil {
+			_ = target.storage.StoreVector(newID, buildVec, desc, target.record.Model)
+		}
+	}
 
 	var existing models.FileVector
 	if err := ve.db.Where("file_id = ?", newID).First(&existing).Error; err == nil {
@@ -366,6 +392,43 @@ func (ve *VectorEngine) CloneVectorFrom(originalID, newID, description string) e
 	return nil
 }
 
+// RecreateCollection 按当前向量模型的维度重建集合，返回使用的维度
+func (ve *VectorEngine) RecreateCollection() (int, error) {
+	if err := ve.ensureInitialized(); err != nil {
+		return 0, fmt.Errorf("向量搜索功能不可用: %v", err)
+	}
+
+	if ve.BuildingCollection() != nil {
+		return 0, fmt.Errorf("新版本向量集合正在构建，请等待完成后再重建")
+	}
+
+	// 原地重建时直接采用当前配置的模型，并更新当前集合版本记录
+	provider, model := currentEmbeddingConfig()
+	embedding := NewPinnedEmbeddingProvider(provider, model)
+	dimension := embedding.GetDimension()
+	if err := ve.storage.RecreateCollection(dimension); err != nil {
+		return 0, fmt.Errorf("重建向量集合失败: %v", err)
+	}
+
+	ve.mutex.Lock()
+	ve.embedding = embedding
+	if ve.collection != nil {
+		ve.collection.Provider = provider
+		ve.collection.Model = model
+		ve.collection.Dimension = dimension
+		ve.collection.PayloadVersion = VectorPayloadVersion // 重建后写入的向量均带有完整元数据
+		_ = ve.db.Model(ve.collection).Updates(map[string]interface{}{
+			"provider":        provider,
+			"model":           model,
+			"dimension":       dimension,
+			"payload_version": VectorPayloadVersion,
+		}).Error
+	}
+	ve.mutex.Unlock()
+
+	return dimension, nil
+}
+
 // ProcessFile 处理单个文件的向量化（命名保留，内部统一用 fileID）
 func (ve *VectorEngine) ProcessFile(fileID, description string) error {
 	if err := ve.ensureInitialized(); err != nil {
@@ -391,6 +454,7 @@ func (ve *VectorEngine) ProcessFile(fileID, description string) error {
 		return fmt.Errorf("存储失败: %v", err)
 	}
 
+	ve.writeToBuilding([]VectorItem{{FileID: fileID, Description: description}})
 	return nil
 }
 
@@ -432,6 +496,7 @@ func (ve *VectorEngine) BatchProcessFiles(items []VectorItem) error {
 		return fmt.Errorf("批量存储失败: %v", err)
 	}
 
+	ve.writeToBuilding(items)
 	return nil
 }
 
@@ -446,15 +511,8 @@ func (ve *VectorEngine) SearchSimilarByFileID(fileID string, limit int, userID u
 		return nil, fmt.Errorf("文件向量信息不存在或未完成处理")
 	}
 
-	// 使用Qdrant进行向量相似度搜索
-	if qdrantClient, ok := ve.storage.(*QdrantClient); ok {
-		// 直接使用 fileID 从 Qdrant 搜索相似向量
-		return qdrantClient.SearchSimilarByID(fileID, limit, userID, threshold, baseVector.Model)
-	}
-
-	// 如果不是Qdrant存储，降级使用描述文本搜索
-	logger.Warn("非Qdrant存储，降级使用文本搜索")
-	return ve.SearchFiles(baseVector.Description, limit, userID, threshold)
+	// 直接使用 fileID 对应的已存储向量搜索
+	return ve.storage.SearchSimilarByID(fileID, limit, userID, threshold, baseVector.Model)
 }
 
 // SearchFiles 搜索相似文件
@@ -491,6 +549,9 @@ func (ve *VectorEngine) DeleteVector(fileID string) error {
 		return nil
 	}
 
+	if target := ve.buildingTarget(); target != nil {
+		_ = target.storage.DeleteVector(fileID)
+	}
 	return ve.storage.DeleteVector(fileID)
 }
 
@@ -523,11 +584,7 @@ func (ve *VectorEngine) GetAllFileIDs(limit int) ([]string, error) {
 	if err := ve.ensureInitialized(); err != nil {
 		return nil, fmt.Errorf("向量搜索功能不可用: %v", err)
 	}
-	// 仅在 Qdrant 存储下支持
-	if qdrantClient, ok := ve.storage.(*QdrantClient); ok {
-		return qdrantClient.GetAllFileIDs(limit)
-	}
-	return nil, fmt.Errorf("当前存储不支持遍历所有向量id")
+	return ve.storage.GetAllFileIDs(limit)
 }
 
 // HealthCheck 健康检查
@@ -540,11 +597,8 @@ func (ve *VectorEngine) HealthCheck() error {
 		return fmt.Errorf("向量存储未初始化")
 	}
 
-	// 对于 QdrantClient，检查连接健康状态
-	if qdrantClient, ok := ve.storage.(*QdrantClient); ok {
-		if err := qdrantClient.HealthCheck(); err != nil {
-			return fmt.Errorf("qdrant连接不健康: %v", err)
-		}
+	if err := ve.storage.HealthCheck(); err != nil {
+		return fmt.Errorf("向量存储连接不健康: %v", err)
 	}
 
 	// 注意：在直连模式下，ve.db 可以为 nil，ve.embedding 也可能不需要
@@ -552,17 +606,66 @@ func (ve *VectorEngine) HealthCheck() error {
 	return nil
 }
 
-// ReloadConfig 重新加载向量配置（动态客户端无需操作，配置会自动读取）
+// ReloadConfig 存储后端或其连接配置变化时重建存储客户端并切换，旧客户端随后关闭；
+// 其余配置由动态客户端在每次调用时读取
 func (ve *VectorEngine) ReloadConfig() error {
 	if ve == nil {
 		return fmt.Errorf("向量引擎未初始化")
 	}
 
-	// 动态客户端会在每次调用时自动读取最新配置，无需手动重新加载
+	sig := vectorStoreSignature()
+	ve.mutex.RLock()
+	unchanged := ve.storage != nil && ve.storeSig == sig
+	ve.mutex.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	db := ve.db
+	if db == nil {
+		db = database.GetDB()
+	}
+	if db == nil {
+		return fmt.Errorf("数据库连接不可用")
+	}
+
+	store, err := NewVectorStoreFromSettings()
+	if err != nil {
+		return err
+	}
+	collection := loadActiveCollection(db)
+	versioned := store.WithVersion(collection.Version)
+	if err := versioned.InitCollection(); err != nil {
+		_ = store.Close()
+		return fmt.Errorf("初始化向量集合失败: %v", err)
+	}
+
+	// 正在构建的新版本集合属于旧后端，切换后无法继续
+	if ve.buildingTarget() != nil {
+		ve.AbortCollectionMigration(fmt.Errorf("向量存储配置已变更"))
+	}
+
+	ve.mutex.Lock()
+	old := ve.storage
+	ve.db = db
+	ve.storage = versioned
+	ve.embedding = NewPinnedEmbeddingProvider(collection.Provider, collection.Model)
+	ve.collection = collection
+	ve.storeSig = sig
+	if old == nil {
+		ve.enabled = true
+	}
+	ve.mutex.Unlock()
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			logger.Warn("关闭旧向量存储客户端失败: %v", err)
+		}
+	}
+	logger.Info("向量存储已切换到 %s (集合 v%d)", collection.Backend, collection.Version)
 	return nil
 }
 
-
 // Disable 禁用向量搜索功能
 func (ve *VectorEngine) Disable() {
 	if ve == nil {
@@ -575,8 +678,20 @@ func (ve *VectorEngine) Disable() {
 	ve.enabled = false
 }
 
-// getCurrentModel 获取当前向量模型
+// GetCurrentModel 获取当前配置的向量模型
+func (ve *VectorEngine) GetCurrentModel() string {
+	return ve.getCurrentModel()
+}
+
+// getCurrentModel 获取当前向量模型（即当前集合版本绑定的模型）
 func (ve *VectorEngine) getCurrentModel() string {
+	ve.mutex.RLock()
+	embedding := ve.embedding
+	ve.mutex.RUnlock()
+	if embedding != nil {
+		return embedding.GetModel()
+	}
+
 	vectorConfig, err := getVectorConfigFromDB()
 	if err != nil {
 		logger.Warn("获取向量模型配置失败，使用默认值: %v", err)
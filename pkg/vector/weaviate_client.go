@@ -0,0 +1,640 @@
+package vector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pixelpunk/internal/models"
+	"pixelpunk/pkg/tracing"
+)
+
+// WeaviateClient 通过 REST/GraphQL 接口连接 Weaviate 的向量存储
+type WeaviateClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	class      string
+}
+
+// weaviateObject Weaviate对象
+type weaviateObject struct {
+	Class      string                 `json:"class"`
+	ID         string                 `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	Vector     []float32              `json:"vector,omitempty"`
+}
+
+const weaviateClassName = "PixelpunkFileVector"
+
+// weaviateMetaProperties 文件元数据属性，用于 where 过滤
+var weaviateMetaProperties = []map[string]interface{}{
+	{"name": "accessLevel", "dataType": []string{"text"}, "tokenization": "field"},
+	{"name": "isRecommended", "dataType": []string{"boolean"}},
+	{"name": "folderId", "dataType": []string{"text"}, "tokenization": "field"},
+	{"name": "format", "dataType": []string{"text"}, "tokenization": "field"},
+	{"name": "nsfw", "dataType": []string{"boolean"}},
+	{"name": "fileCreatedAt", "dataType": []string{"int"}},
+	{"name": "tagIds", "dataType": []string{"int[]"}},
+}
+
+func NewWeaviateClient(weaviateURL, apiKey string, timeout int) *WeaviateClient {
+	return &WeaviateClient{
+		baseURL:    strings.TrimRight(weaviateURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: time.Duration(timeout) * time.Second, Transport: tracing.NewTransport("vector.weaviate", nil)},
+		class:      weaviateClassName,
+	}
+}
+
+// doRequest 发送请求，body 为 nil 时不带请求体
+func (w *WeaviateClient) doRequest(method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("序列化请求失败: %w", err)
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, w.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+	return w.httpClient.Do(req)
+}
+
+// graphQL 执行 GraphQL 查询，返回 data 字段
+func (w *WeaviateClient) graphQL(query string, out interface{}) error {
+	resp, err := w.doRequest(http.MethodPost, "/v1/graphql", map[string]string{"query": query})
+	if err != nil {
+		return fmt.Errorf("graphql请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("graphql请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var gqlResp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
+		return fmt.Errorf("解析graphql响应失败: %w", err)
+	}
+	if len(gqlResp.Errors) > 0 {
+		return fmt.Errorf("graphql查询失败: %s", gqlResp.Errors[0].Message)
+	}
+	return json.Unmarshal(gqlResp.Data, out)
+}
+
+// InitCollection 初始化类（集合）定义，使用外部向量并以余弦距离建索引
+func (w *WeaviateClient) InitCollection() error {
+	resp, err := w.doRequest(http.MethodGet, "/v1/schema/"+w.class, nil)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		resp.Body.Close()
+		w.ensureMetaProperties()
+		return nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	schema := map[string]interface{}{
+		"class":      w.class,
+		"vectorizer": "none",
+		"vectorIndexConfig": map[string]interface{}{
+			"distance": "cosine",
+		},
+		"properties": append([]map[string]interface{}{
+			{"name": "fileId", "dataType": []string{"text"}},
+			{"name": "userId", "dataType": []string{"int"}},
+			{"name": "description", "dataType": []string{"text"}},
+			{"name": "model", "dataType": []string{"text"}},
+		}, weaviateMetaProperties...),
+	}
+
+	resp, err = w.doRequest(http.MethodPost, "/v1/schema", schema)
+	if err != nil {
+		return fmt.Errorf("创建集合请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("创建集合失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// ensureMetaProperties 为早期创建的类补充元数据属性（已存在时Weaviate返回422，忽略）
+func (w *WeaviateClient) ensureMetaProperties() {
+	for _, prop := range weaviateMetaProperties {
+		resp, err := w.doRequest(http.MethodPost, "/v1/schema/"+w.class+"/properties", prop)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+// RecreateCollection 删除并重建类定义（Weaviate 按写入的向量自动确定维度）
+func (w *WeaviateClient) RecreateCollection(dimension int) error {
+	if err := w.DeleteCollection(); err != nil {
+		return err
+	}
+	return w.InitCollection()
+}
+
+// WithVersion 返回指向指定版本类的客户端
+func (w *WeaviateClient) WithVersion(version int) VectorStore {
+	clone := *w
+	clone.class = versionedCollectionName(weaviateClassName, version)
+	return &clone
+}
+
+// DeleteCollection 删除类及其全部对象
+func (w *WeaviateClient) DeleteCollection() error {
+	resp, err := w.doRequest(http.MethodDelete, "/v1/schema/"+w.class, nil)
+	if err != nil {
+		return fmt.Errorf("删除集合请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("删除集合失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// HealthCheck 健康检查
+func (w *WeaviateClient) HealthCheck() error {
+	resp, err := w.doRequest(http.MethodGet, "/v1/.well-known/ready", nil)
+	if err != nil {
+		return fmt.Errorf("weaviate健康检查失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weaviate服务不健康，状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close Weaviate HTTP客户端无需显式关闭
+func (w *WeaviateClient) Close() error {
+	return nil
+}
+
+// StoreVector 存储向量
+func (w *WeaviateClient) StoreVector(fileID string, vector []float32, description string, model string) error {
+	return w.BatchStoreVectors([]VectorItem{{
+		FileID:      fileID,
+		Vector:      vector,
+		Description: description,
+		Model:       model,
+	}})
+}
+
+// BatchStoreVectors 批量存储向量（batch 接口按ID覆盖已有对象）
+func (w *WeaviateClient) BatchStoreVectors(items []VectorItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	fileIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		fileIDs = append(fileIDs, item.FileID)
+	}
+	metaMap := loadFileMeta(fileIDs)
+
+	objects := make([]weaviateObject, 0, len(items))
+	for _, item := range items {
+		properties := weaviateMetaValues(metaMap[item.FileID])
+		properties["fileId"] = item.FileID
+		properties["description"] = item.Description
+		properties["model"] = item.Model
+		objects = append(objects, weaviateObject{
+			Class:      w.class,
+			ID:         fileIDToUUID(item.FileID),
+			Properties: properties,
+			Vector:     item.Vector,
+		})
+	}
+
+	resp, err := w.doRequest(http.MethodPost, "/v1/batch/objects", map[string]interface{}{"objects": objects})
+	if err != nil {
+		return fmt.Errorf("存储向量请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("存储向量失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var results []struct {
+		ID     string `json:"id"`
+		Result struct {
+			Errors *struct {
+				Error []struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return fmt.Errorf("解析存储响应失败: %w", err)
+	}
+	for i, r := range results {
+		if r.Result.Errors != nil && len(r.Result.Errors.Error) > 0 {
+			fileID := r.ID
+			if i < len(items) {
+				fileID = items[i].FileID
+			}
+			return fmt.Errorf("存储向量失败，文件ID: %s, 错误: %s", fileID, r.Result.Errors.Error[0].Message)
+		}
+	}
+	return nil
+}
+
+// SearchVectors 按余弦相似度搜索向量
+func (w *WeaviateClient) SearchVectors(queryVector []float32, limit int, userID uint, threshold float32) ([]VectorSearchResult, error) {
+	return w.SearchVectorsWithFilter(queryVector, limit, VectorFilter{UserID: userID}, threshold)
+}
+
+// SearchVectorsWithFilter 使用 where 条件在 Weaviate 内过滤后搜索
+func (w *WeaviateClient) SearchVectorsWithFilter(queryVector []float32, limit int, filter VectorFilter, threshold float32) ([]VectorSearchResult, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	vecJSON, err := json.Marshal(queryVector)
+	if err != nil {
+		return nil, fmt.Errorf("序列化查询向量失败: %w", err)
+	}
+
+	// Weaviate 返回余弦距离，相似度 = 1 - 距离
+	args := fmt.Sprintf("nearVector: {vector: %s, distance: %f}, limit: %d", vecJSON, 1-threshold, limit)
+	operands := weaviateWhereOperands(filter)
+	switch len(operands) {
+	case 0:
+	case 1:
+		args += ", where: " + operands[0]
+	default:
+		args += fmt.Sprintf(", where: {operator: And, operands: [%s]}", strings.Join(operands, ", "))
+	}
+	query := fmt.Sprintf("{ Get { %s(%s) { fileId description _additional { distance } } } }", w.class, args)
+
+	var data struct {
+		Get map[string][]struct {
+			FileID      string `json:"fileId"`
+			Description string `json:"description"`
+			Additional  struct {
+				Distance float32 `json:"distance"`
+			} `json:"_additional"`
+		} `json:"Get"`
+	}
+	if err := w.graphQL(query, &data); err != nil {
+		return nil, fmt.Errorf("搜索失败: %w", err)
+	}
+
+	items := data.Get[w.class]
+	results := make([]VectorSearchResult, 0, len(items))
+	for _, item := range items {
+		if item.FileID == "" {
+			continue
+		}
+		score := 1 - item.Additional.Distance
+		results = append(results, VectorSearchResult{
+			FileID:      item.FileID,
+			Description: item.Description,
+			Similarity:  score,
+			Score:       score,
+		})
+	}
+	return results, nil
+}
+
+// SearchSimilar 搜索相似向量
+func (w *WeaviateClient) SearchSimilar(queryVector []float32, limit int, userID uint, threshold float32, model string) ([]VectorSearchResult, error) {
+	return w.SearchVectors(queryVector, limit, userID, threshold)
+}
+
+// SearchSimilarWithQuery 带查询的搜索相似向量
+func (w *WeaviateClient) SearchSimilarWithQuery(queryVector []float32, limit int, userID uint, threshold float32, query string, model string) ([]VectorSearchResult, error) {
+	return w.SearchVectors(queryVector, limit, userID, threshold)
+}
+
+// SearchSimilarByID 通过文件ID搜索相似向量
+func (w *WeaviateClient) SearchSimilarByID(fileID string, limit int, userID uint, threshold float32, model string) ([]VectorSearchResult, error) {
+	vec, _, err := w.FetchVectorWithPayload(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取基准向量失败: %w", err)
+	}
+	return w.SearchVectors(vec, limit, userID, threshold)
+}
+
+// weaviateMetaValues 文件元数据对应的属性值
+func weaviateMetaValues(meta VectorFileMeta) map[string]interface{} {
+	return map[string]interface{}{
+		"userId":        meta.UserID,
+		"accessLevel":   meta.AccessLevel,
+		"isRecommended": meta.IsRecommended,
+		"folderId":      meta.FolderID,
+		"format":        meta.Format,
+		"nsfw":          meta.NSFW,
+		"fileCreatedAt": meta.CreatedAt,
+		"tagIds":        meta.TagIDs,
+	}
+}
+
+// weaviateWhereOperands 将过滤条件转换为 where 子句（各条件之间为 And）
+func weaviateWhereOperands(filter VectorFilter) []string {
+	var operands []string
+	if filter.UserID > 0 {
+		operands = append(operands, fmt.Sprintf(`{path: ["userId"], operator: Equal, valueInt: %d}`, filter.UserID))
+	}
+	if filter.PublicOnly {
+		operands = append(operands,
+			fmt.Sprintf(`{path: ["accessLevel"], operator: Equal, valueText: %q}`, vectorAccessPublic),
+			`{path: ["isRecommended"], operator: Equal, valueBoolean: true}`)
+	}
+	if len(filter.TagIDs) > 0 {
+		tagsJSON, _ := json.Marshal(filter.TagIDs)
+		operands = append(operands, fmt.Sprintf(`{path: ["tagIds"], operator: ContainsAll, valueInt: %s}`, tagsJSON))
+	}
+	if filter.FolderID != "" {
+		operands = append(operands, fmt.Sprintf(`{path: ["folderId"], operator: Equal, valueText: %q}`, filter.FolderID))
+	}
+	if len(filter.Formats) > 0 {
+		formatsJSON, _ := json.Marshal(filter.Formats)
+		operands = append(operands, fmt.Sprintf(`{path: ["format"], operator: ContainsAny, valueText: %s}`, formatsJSON))
+	}
+	if filter.CreatedFrom > 0 {
+		operands = append(operands, fmt.Sprintf(`{path: ["fileCreatedAt"], operator: GreaterThanEqual, valueInt: %d}`, filter.CreatedFrom))
+	}
+	if filter.CreatedTo > 0 {
+		operands = append(operands, fmt.Sprintf(`{path: ["fileCreatedAt"], operator: LessThanEqual, valueInt: %d}`, filter.CreatedTo))
+	}
+	if filter.NSFW != nil {
+		operands = append(operands, fmt.Sprintf(`{path: ["nsfw"], operator: Equal, valueBoolean: %t}`, *filter.NSFW))
+	}
+	return operands
+}
+
+// UpdateFileMeta 局部更新对象的文件元数据属性（对象不存在时忽略）
+func (w *WeaviateClient) UpdateFileMeta(fileID string, meta VectorFileMeta) error {
+	body := map[string]interface{}{
+		"class":      w.class,
+		"properties": weaviateMetaValues(meta),
+	}
+	resp, err := w.doRequest(http.MethodPatch, fmt.Sprintf("/v1/objects/%s/%s", w.class, fileIDToUUID(fileID)), body)
+	if err != nil {
+		return fmt.Errorf("更新对象请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("更新对象失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// FetchVectorWithPayload 获取指定 fileID 的向量及属性（description/model等）
+func (w *WeaviateClient) FetchVectorWithPayload(fileID string) ([]float32, map[string]interface{}, error) {
+	path := fmt.Sprintf("/v1/objects/%s/%s?include=vector", w.class, fileIDToUUID(fileID))
+	resp, err := w.doRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取向量失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, nil, fmt.Errorf("获取向量失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var obj weaviateObject
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, nil, fmt.Errorf("解析向量响应失败: %w", err)
+	}
+	if len(obj.Vector) == 0 {
+		return nil, nil, fmt.Errorf("向量不存在或为空")
+	}
+
+	payload := map[string]interface{}{
+		"file_id":     obj.Properties["fileId"],
+		"description": obj.Properties["description"],
+		"model":       obj.Properties["model"],
+		"user_id":     obj.Properties["userId"],
+	}
+	return obj.Vector, payload, nil
+}
+
+// GetVector 获取向量记录
+func (w *WeaviateClient) GetVector(fileID string) (*models.FileVector, error) {
+	vec, payload, err := w.FetchVectorWithPayload(fileID)
+	if err != nil {
+		return nil, err
+	}
+	fv := &models.FileVector{FileID: fileID, Dimension: len(vec)}
+	if v, ok := payload["description"].(string); ok {
+		fv.Description = v
+	}
+	if v, ok := payload["model"].(string); ok {
+		fv.Model = v
+	}
+	return fv, nil
+}
+
+// DeleteVector 删除向量（不存在视为成功）
+func (w *WeaviateClient) DeleteVector(fileID string) error {
+	resp, err := w.doRequest(http.MethodDelete, fmt.Sprintf("/v1/objects/%s/%s", w.class, fileIDToUUID(fileID)), nil)
+	if err != nil {
+		return fmt.Errorf("删除向量请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("删除向量失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// VectorExists 检查向量是否存在
+func (w *WeaviateClient) VectorExists(fileID string) (bool, error) {
+	resp, err := w.doRequest(http.MethodHead, fmt.Sprintf("/v1/objects/%s/%s", w.class, fileIDToUUID(fileID)), nil)
+	if err != nil {
+		return false, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("weaviate 响应错误 %d", resp.StatusCode)
+	}
+}
+
+// GetVectorCount 获取用户向量数量，userID 为 0 时统计全部
+func (w *WeaviateClient) GetVectorCount(userID uint) (int64, error) {
+	args := ""
+	if userID > 0 {
+		args = fmt.Sprintf(`(where: {path: ["userId"], operator: Equal, valueInt: %d})`, userID)
+	}
+	query := fmt.Sprintf("{ Aggregate { %s%s { meta { count } } } }", w.class, args)
+
+	var data struct {
+		Aggregate map[string][]struct {
+			Meta struct {
+				Count int64 `json:"count"`
+			} `json:"meta"`
+		} `json:"Aggregate"`
+	}
+	if err := w.graphQL(query, &data); err != nil {
+		return 0, fmt.Errorf("统计向量数量失败: %w", err)
+	}
+	if items := data.Aggregate[w.class]; len(items) > 0 {
+		return items[0].Meta.Count, nil
+	}
+	return 0, nil
+}
+
+// GetAllFileIDs 通过游标遍历获取 file_id，用于对账/清理孤儿；limit<=0 时最多返回 100k
+func (w *WeaviateClient) GetAllFileIDs(limit int) ([]string, error) {
+	maxTotal := 100000
+	if limit > 0 && limit < maxTotal {
+		maxTotal = limit
+	}
+	batch := 1000
+	if maxTotal < batch {
+		batch = maxTotal
+	}
+
+	var all []string
+	after := ""
+	for len(all) < maxTotal {
+		params := url.Values{}
+		params.Set("class", w.class)
+		params.Set("limit", fmt.Sprintf("%d", batch))
+		if after != "" {
+			params.Set("after", after)
+		}
+
+		resp, err := w.doRequest(http.MethodGet, "/v1/objects?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("遍历对象请求失败: %w", err)
+		}
+		var listResp struct {
+			Objects []weaviateObject `json:"objects"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("遍历对象失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+		}
+		err = json.NewDecoder(resp.Body).Decode(&listResp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("解析对象列表失败: %w", err)
+		}
+
+		for _, obj := range listResp.Objects {
+			if v, ok := obj.Properties["fileId"].(string); ok && v != "" {
+				all = append(all, v)
+			}
+		}
+		if len(listResp.Objects) < batch {
+			break
+		}
+		after = listResp.Objects[len(listResp.Objects)-1].ID
+	}
+
+	if len(all) > maxTotal {
+		all = all[:maxTotal]
+	}
+	return all, nil
+}
+
+// ScrollVectors 通过 after 游标分页读取对象及向量，游标为上一批最后一个对象的ID
+func (w *WeaviateClient) ScrollVectors(cursor string, limit int) ([]VectorItem, string, error) {
+	params := url.Values{}
+	params.Set("class", w.class)
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("include", "vector")
+	if cursor != "" {
+		params.Set("after", cursor)
+	}
+
+	resp, err := w.doRequest(http.MethodGet, "/v1/objects?"+params.Encode(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("遍历对象请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("遍历对象失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var listResp struct {
+		Objects []weaviateObject `json:"objects"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, "", fmt.Errorf("解析对象列表失败: %w", err)
+	}
+
+	items := make([]VectorItem, 0, len(listResp.Objects))
+	for _, obj := range listResp.Objects {
+		item := VectorItem{Vector: obj.Vector}
+		item.FileID, _ = obj.Properties["fileId"].(string)
+		item.Description, _ = obj.Properties["description"].(string)
+		item.Model, _ = obj.Properties["model"].(string)
+		items = append(items, item)
+	}
+
+	next := ""
+	if len(listResp.Objects) == limit {
+		next = listResp.Objects[len(listResp.Objects)-1].ID
+	}
+	return items, next, nil
+}
+
+// GetStorageStats 获取存储统计
+func (w *WeaviateClient) GetStorageStats() (*VectorStorageStats, error) {
+	count, err := w.GetVectorCount(0)
+	if err != nil {
+		return nil, err
+	}
+	return &VectorStorageStats{
+		TotalVectors:   count,
+		CompletedCount: count,
+		LastUpdateTime: time.Now(),
+	}, nil
+}
+
+// IsEnabled 检查是否启用
+func (w *WeaviateClient) IsEnabled() bool {
+	return w.HealthCheck() == nil
+}